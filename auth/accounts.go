@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// ServiceAccount represents the non-interactive identity owned by the group
+// (organization), e.g. used by the CI/CD pipelines. Service accounts don't
+// have email and password, and authenticate using the issued keys only.
+// Scopes contain the actions the service account is allowed to perform,
+// regardless of the policies it has. Disabled service account keeps its
+// keys, but can't authenticate until it's enabled.
+type ServiceAccount struct {
+	ID        string
+	Name      string
+	OwnerID   string
+	Scopes    []string
+	Disabled  bool
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// ServiceAccounts specifies an API for the management of the service
+// accounts and their keys. The user identified by the token has to be the
+// member of the group owning the service account.
+type ServiceAccounts interface {
+	// CreateServiceAccount creates the service account owned by the group.
+	CreateServiceAccount(ctx context.Context, token string, sa ServiceAccount) (ServiceAccount, error)
+
+	// ListServiceAccounts lists the service accounts owned by the group.
+	ListServiceAccounts(ctx context.Context, token, ownerID string) ([]ServiceAccount, error)
+
+	// ViewServiceAccount retrieves the service account. The service account
+	// is allowed to view itself.
+	ViewServiceAccount(ctx context.Context, token, id string) (ServiceAccount, error)
+
+	// UpdateServiceAccountScopes replaces the scopes of the service account.
+	UpdateServiceAccountScopes(ctx context.Context, token, id string, scopes []string) (ServiceAccount, error)
+
+	// DisableServiceAccount disables the service account, so its keys are
+	// rejected until it's enabled again.
+	DisableServiceAccount(ctx context.Context, token, id string) error
+
+	// EnableServiceAccount enables the disabled service account.
+	EnableServiceAccount(ctx context.Context, token, id string) error
+
+	// RemoveServiceAccount removes the service account, revoking its keys.
+	RemoveServiceAccount(ctx context.Context, token, id string) error
+
+	// IssueServiceAccountKey issues the key of the service account, valid
+	// for the given duration, or until revoked if the duration is zero.
+	IssueServiceAccountKey(ctx context.Context, token, id string, duration time.Duration) (Key, string, error)
+
+	// RotateServiceAccountKey issues the key replacing the given key of the
+	// service account, which is revoked. The new key is valid for the same
+	// duration the replaced key was issued for.
+	RotateServiceAccountKey(ctx context.Context, token, id, keyID string) (Key, string, error)
+
+	// RevokeServiceAccountKey revokes the key of the service account.
+	RevokeServiceAccountKey(ctx context.Context, token, id, keyID string) error
+}
+
+// ServiceAccountRepository specifies ServiceAccount persistence API.
+type ServiceAccountRepository interface {
+	// Save persists the service account.
+	Save(ctx context.Context, sa ServiceAccount) error
+
+	// RetrieveByID retrieves the service account by its ID.
+	RetrieveByID(ctx context.Context, id string) (ServiceAccount, error)
+
+	// RetrieveByOwner retrieves the service accounts owned by the group.
+	RetrieveByOwner(ctx context.Context, ownerID string) ([]ServiceAccount, error)
+
+	// UpdateScopes replaces the scopes of the service account.
+	UpdateScopes(ctx context.Context, id string, scopes []string) error
+
+	// UpdateStatus disables or enables the service account.
+	UpdateStatus(ctx context.Context, id string, disabled bool) error
+
+	// Remove removes the service account and its keys.
+	Remove(ctx context.Context, id string) error
+}
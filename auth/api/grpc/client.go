@@ -12,26 +12,45 @@ import (
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
 	opentracing "github.com/opentracing/opentracing-go"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 const (
 	svcName = "mainflux.AuthService"
+
+	// clientIPKey is the metadata key of the IP address of the client the
+	// token is used from.
+	clientIPKey = "x-client-ip"
+
+	// scopeKey is the metadata key of the scope of the action the token is
+	// used to perform.
+	scopeKey = "x-scope"
+
+	// userAgentKey is the metadata key of the user agent of the client
+	// logging in.
+	userAgentKey = "x-user-agent"
 )
 
 var _ mainflux.AuthServiceClient = (*grpcClient)(nil)
 
 type grpcClient struct {
-	issue        endpoint.Endpoint
-	identify     endpoint.Endpoint
-	authorize    endpoint.Endpoint
-	addPolicy    endpoint.Endpoint
-	deletePolicy endpoint.Endpoint
-	listPolicies endpoint.Endpoint
-	assign       endpoint.Endpoint
-	members      endpoint.Endpoint
-	timeout      time.Duration
+	issue               endpoint.Endpoint
+	identify            endpoint.Endpoint
+	authorize           endpoint.Endpoint
+	addPolicy           endpoint.Endpoint
+	deletePolicy        endpoint.Endpoint
+	listPolicies        endpoint.Endpoint
+	assign              endpoint.Endpoint
+	members             endpoint.Endpoint
+	reserveQuota        endpoint.Endpoint
+	releaseQuota        endpoint.Endpoint
+	revokeKeys          endpoint.Endpoint
+	applyPolicyTemplate endpoint.Endpoint
+	removeLinks         endpoint.Endpoint
+	timeout             time.Duration
 }
 
 // NewClient returns new gRPC client instance.
@@ -44,6 +63,9 @@ func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Du
 			encodeIssueRequest,
 			decodeIssueResponse,
 			mainflux.UserIdentity{},
+			kitgrpc.ClientBefore(injectClientIP),
+			kitgrpc.ClientBefore(injectScope),
+			kitgrpc.ClientBefore(injectUserAgent),
 		).Endpoint()),
 		identify: kitot.TraceClient(tracer, "identify")(kitgrpc.NewClient(
 			conn,
@@ -52,6 +74,8 @@ func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Du
 			encodeIdentifyRequest,
 			decodeIdentifyResponse,
 			mainflux.UserIdentity{},
+			kitgrpc.ClientBefore(injectClientIP),
+			kitgrpc.ClientBefore(injectScope),
 		).Endpoint()),
 		authorize: kitot.TraceClient(tracer, "authorize")(kitgrpc.NewClient(
 			conn,
@@ -60,6 +84,8 @@ func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Du
 			encodeAuthorizeRequest,
 			decodeAuthorizeResponse,
 			mainflux.AuthorizeRes{},
+			kitgrpc.ClientBefore(injectClientIP),
+			kitgrpc.ClientBefore(injectScope),
 		).Endpoint()),
 		addPolicy: kitot.TraceClient(tracer, "add_policy")(kitgrpc.NewClient(
 			conn,
@@ -92,6 +118,8 @@ func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Du
 			encodeAssignRequest,
 			decodeAssignResponse,
 			mainflux.AuthorizeRes{},
+			kitgrpc.ClientBefore(injectClientIP),
+			kitgrpc.ClientBefore(injectScope),
 		).Endpoint()),
 		members: kitot.TraceClient(tracer, "members")(kitgrpc.NewClient(
 			conn,
@@ -100,6 +128,50 @@ func NewClient(tracer opentracing.Tracer, conn *grpc.ClientConn, timeout time.Du
 			encodeMembersRequest,
 			decodeMembersResponse,
 			mainflux.MembersRes{},
+			kitgrpc.ClientBefore(injectClientIP),
+			kitgrpc.ClientBefore(injectScope),
+		).Endpoint()),
+		reserveQuota: kitot.TraceClient(tracer, "reserve_quota")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ReserveQuota",
+			encodeQuotaRequest,
+			decodeEmptyResponse,
+			empty.Empty{},
+			kitgrpc.ClientBefore(injectClientIP),
+			kitgrpc.ClientBefore(injectScope),
+		).Endpoint()),
+		releaseQuota: kitot.TraceClient(tracer, "release_quota")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ReleaseQuota",
+			encodeQuotaRequest,
+			decodeEmptyResponse,
+			empty.Empty{},
+		).Endpoint()),
+		revokeKeys: kitot.TraceClient(tracer, "revoke_keys")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"RevokeKeys",
+			encodeRevokeKeysRequest,
+			decodeRevokeKeysResponse,
+			mainflux.RevokeKeysRes{},
+		).Endpoint()),
+		applyPolicyTemplate: kitot.TraceClient(tracer, "apply_policy_template")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"ApplyPolicyTemplate",
+			encodePolicyTemplateRequest,
+			decodeEmptyResponse,
+			empty.Empty{},
+		).Endpoint()),
+		removeLinks: kitot.TraceClient(tracer, "remove_links")(kitgrpc.NewClient(
+			conn,
+			svcName,
+			"RemoveLinks",
+			encodeRemoveLinksRequest,
+			decodeEmptyResponse,
+			empty.Empty{},
 		).Endpoint()),
 
 		timeout: timeout,
@@ -110,7 +182,7 @@ func (client grpcClient) Issue(ctx context.Context, req *mainflux.IssueReq, _ ..
 	ctx, close := context.WithTimeout(ctx, client.timeout)
 	defer close()
 
-	res, err := client.issue(ctx, issueReq{id: req.GetId(), email: req.GetEmail(), keyType: req.Type})
+	res, err := client.issue(ctx, issueReq{id: req.GetId(), email: req.GetEmail(), keyType: req.Type, claims: req.GetClaims()})
 	if err != nil {
 		return nil, err
 	}
@@ -121,7 +193,7 @@ func (client grpcClient) Issue(ctx context.Context, req *mainflux.IssueReq, _ ..
 
 func encodeIssueRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(issueReq)
-	return &mainflux.IssueReq{Id: req.id, Email: req.email, Type: req.keyType}, nil
+	return &mainflux.IssueReq{Id: req.id, Email: req.email, Type: req.keyType, Claims: req.claims}, nil
 }
 
 func decodeIssueResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
@@ -139,7 +211,7 @@ func (client grpcClient) Identify(ctx context.Context, token *mainflux.Token, _
 	}
 
 	ir := res.(identityRes)
-	return &mainflux.UserIdentity{Id: ir.id, Email: ir.email}, nil
+	return &mainflux.UserIdentity{Id: ir.id, Email: ir.email, Claims: ir.claims}, nil
 }
 
 func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -149,14 +221,14 @@ func encodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 
 func decodeIdentifyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*mainflux.UserIdentity)
-	return identityRes{id: res.GetId(), email: res.GetEmail()}, nil
+	return identityRes{id: res.GetId(), email: res.GetEmail(), claims: res.GetClaims()}, nil
 }
 
 func (client grpcClient) Authorize(ctx context.Context, req *mainflux.AuthorizeReq, _ ...grpc.CallOption) (r *mainflux.AuthorizeRes, err error) {
 	ctx, close := context.WithTimeout(ctx, client.timeout)
 	defer close()
 
-	res, err := client.authorize(ctx, authReq{Act: req.GetAct(), Obj: req.GetObj(), Sub: req.GetSub()})
+	res, err := client.authorize(ctx, authReq{Act: req.GetAct(), Obj: req.GetObj(), Sub: req.GetSub(), Token: req.GetToken(), Scope: req.GetScope()})
 	if err != nil {
 		return &mainflux.AuthorizeRes{}, err
 	}
@@ -173,9 +245,11 @@ func decodeAuthorizeResponse(_ context.Context, grpcRes interface{}) (interface{
 func encodeAuthorizeRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(authReq)
 	return &mainflux.AuthorizeReq{
-		Sub: req.Sub,
-		Obj: req.Obj,
-		Act: req.Act,
+		Sub:   req.Sub,
+		Obj:   req.Obj,
+		Act:   req.Act,
+		Token: req.Token,
+		Scope: req.Scope,
 	}, nil
 }
 
@@ -183,7 +257,7 @@ func (client grpcClient) AddPolicy(ctx context.Context, in *mainflux.AddPolicyRe
 	ctx, close := context.WithTimeout(ctx, client.timeout)
 	defer close()
 
-	res, err := client.addPolicy(ctx, addPolicyReq{Act: in.GetAct(), Obj: in.GetObj(), Sub: in.GetSub()})
+	res, err := client.addPolicy(ctx, addPolicyReq{Act: in.GetAct(), Obj: in.GetObj(), Sub: in.GetSub(), TTL: in.GetTtl()})
 	if err != nil {
 		return &mainflux.AddPolicyRes{}, err
 	}
@@ -203,6 +277,7 @@ func encodeAddPolicyRequest(_ context.Context, grpcReq interface{}) (interface{}
 		Sub: req.Sub,
 		Obj: req.Obj,
 		Act: req.Act,
+		Ttl: req.TTL,
 	}, nil
 }
 
@@ -268,6 +343,7 @@ func (client grpcClient) Members(ctx context.Context, req *mainflux.MembersReq,
 		token:      req.GetToken(),
 		groupID:    req.GetGroupID(),
 		memberType: req.GetType(),
+		relation:   req.GetRelation(),
 		offset:     req.GetOffset(),
 		limit:      req.GetLimit(),
 	})
@@ -278,32 +354,35 @@ func (client grpcClient) Members(ctx context.Context, req *mainflux.MembersReq,
 	mr := res.(membersRes)
 
 	return &mainflux.MembersRes{
-		Offset:  mr.offset,
-		Limit:   mr.limit,
-		Total:   mr.total,
-		Type:    mr.groupType,
-		Members: mr.members,
+		Offset:      mr.offset,
+		Limit:       mr.limit,
+		Total:       mr.total,
+		Type:        mr.groupType,
+		Members:     mr.members,
+		Memberships: mr.memberships,
 	}, err
 }
 
 func encodeMembersRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(membersReq)
 	return &mainflux.MembersReq{
-		Token:   req.token,
-		Offset:  req.offset,
-		Limit:   req.limit,
-		GroupID: req.groupID,
-		Type:    req.memberType,
+		Token:    req.token,
+		Offset:   req.offset,
+		Limit:    req.limit,
+		GroupID:  req.groupID,
+		Type:     req.memberType,
+		Relation: req.relation,
 	}, nil
 }
 
 func decodeMembersResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(*mainflux.MembersRes)
 	return membersRes{
-		offset:  res.Offset,
-		limit:   res.Limit,
-		total:   res.Total,
-		members: res.Members,
+		offset:      res.Offset,
+		limit:       res.Limit,
+		total:       res.Total,
+		members:     res.Members,
+		memberships: res.Memberships,
 	}, nil
 }
 
@@ -332,3 +411,125 @@ func decodeAssignResponse(_ context.Context, grpcReq interface{}) (interface{},
 		Act: req.Act,
 	}, nil
 }
+
+func (client grpcClient) ReserveQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	ctx, close := context.WithTimeout(ctx, client.timeout)
+	defer close()
+
+	if _, err := client.reserveQuota(ctx, quotaReq{token: req.GetToken(), resource: req.GetResource(), ids: req.GetIds()}); err != nil {
+		return &empty.Empty{}, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (client grpcClient) ReleaseQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	ctx, close := context.WithTimeout(ctx, client.timeout)
+	defer close()
+
+	if _, err := client.releaseQuota(ctx, quotaReq{resource: req.GetResource(), ids: req.GetIds()}); err != nil {
+		return &empty.Empty{}, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func encodeQuotaRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(quotaReq)
+	return &mainflux.QuotaReq{
+		Token:    req.token,
+		Resource: req.resource,
+		Ids:      req.ids,
+	}, nil
+}
+
+func decodeEmptyResponse(_ context.Context, _ interface{}) (interface{}, error) {
+	return emptyRes{}, nil
+}
+
+func (client grpcClient) RevokeKeys(ctx context.Context, req *mainflux.RevokeKeysReq, _ ...grpc.CallOption) (*mainflux.RevokeKeysRes, error) {
+	ctx, close := context.WithTimeout(ctx, client.timeout)
+	defer close()
+
+	res, err := client.revokeKeys(ctx, revokeKeysReq{token: req.GetToken(), subject: req.GetSubject()})
+	if err != nil {
+		return nil, err
+	}
+
+	rkr := res.(revokeKeysRes)
+	return &mainflux.RevokeKeysRes{Count: rkr.count}, nil
+}
+
+func encodeRevokeKeysRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(revokeKeysReq)
+	return &mainflux.RevokeKeysReq{Token: req.token, Subject: req.subject}, nil
+}
+
+func decodeRevokeKeysResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(*mainflux.RevokeKeysRes)
+	return revokeKeysRes{count: res.GetCount()}, nil
+}
+
+func (client grpcClient) ApplyPolicyTemplate(ctx context.Context, req *mainflux.PolicyTemplateReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	ctx, close := context.WithTimeout(ctx, client.timeout)
+	defer close()
+
+	ptr := policyTemplateReq{entityType: req.GetEntityType(), entityID: req.GetEntityID(), ownerID: req.GetOwnerID()}
+	if _, err := client.applyPolicyTemplate(ctx, ptr); err != nil {
+		return &empty.Empty{}, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+func (client grpcClient) RemoveLinks(ctx context.Context, req *mainflux.RemoveLinksReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	ctx, close := context.WithTimeout(ctx, client.timeout)
+	defer close()
+
+	if _, err := client.removeLinks(ctx, removeLinksReq{userID: req.GetUserID()}); err != nil {
+		return &empty.Empty{}, err
+	}
+
+	return &empty.Empty{}, nil
+}
+
+// injectUserAgent propagates the user agent of the client stored in the
+// context, so the sessions can be told apart by the device.
+func injectUserAgent(ctx context.Context, md *metadata.MD) context.Context {
+	if ua := auth.UserAgent(ctx); ua != "" {
+		md.Set(userAgentKey, ua)
+	}
+	return ctx
+}
+
+// injectClientIP propagates the IP address of the client stored in the
+// context, so the keys bound to the networks can be checked against it.
+func injectClientIP(ctx context.Context, md *metadata.MD) context.Context {
+	if ip := auth.ClientIP(ctx); ip != "" {
+		md.Set(clientIPKey, ip)
+	}
+	return ctx
+}
+
+// injectScope propagates the scope of the action stored in the context, so
+// the scoped keys can be checked against it.
+func injectScope(ctx context.Context, md *metadata.MD) context.Context {
+	if scope := auth.Scope(ctx); scope != "" {
+		md.Set(scopeKey, scope)
+	}
+	return ctx
+}
+
+func encodeRemoveLinksRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(removeLinksReq)
+	return &mainflux.RemoveLinksReq{UserID: req.userID}, nil
+}
+
+func encodePolicyTemplateRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(policyTemplateReq)
+	return &mainflux.PolicyTemplateReq{
+		EntityType: req.entityType,
+		EntityID:   req.entityID,
+		OwnerID:    req.ownerID,
+	}, nil
+}
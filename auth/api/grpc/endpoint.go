@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/auth/oidc"
 )
 
 func issueEndpoint(svc auth.Service) endpoint.Endpoint {
@@ -23,6 +25,7 @@ func issueEndpoint(svc auth.Service) endpoint.Endpoint {
 			Subject:  req.email,
 			IssuerID: req.id,
 			IssuedAt: time.Now().UTC(),
+			Claims:   req.claims,
 		}
 
 		_, secret, err := svc.Issue(ctx, "", key)
@@ -47,8 +50,9 @@ func identifyEndpoint(svc auth.Service) endpoint.Endpoint {
 		}
 
 		ret := identityRes{
-			id:    id.ID,
-			email: id.Email,
+			id:     id.ID,
+			email:  id.Email,
+			claims: id.Claims,
 		}
 		return ret, nil
 	}
@@ -62,7 +66,14 @@ func authorizeEndpoint(svc auth.Service) endpoint.Endpoint {
 			return authorizeRes{}, err
 		}
 
-		err := svc.Authorize(ctx, auth.PolicyReq{Subject: req.Sub, Object: req.Obj, Relation: req.Act})
+		pr := auth.PolicyReq{
+			Subject:  req.Sub,
+			Object:   req.Obj,
+			Relation: req.Act,
+			Token:    req.Token,
+			Scope:    req.Scope,
+		}
+		err := svc.Authorize(ctx, pr)
 		if err != nil {
 			return authorizeRes{}, err
 		}
@@ -77,11 +88,16 @@ func addPolicyEndpoint(svc auth.Service) endpoint.Endpoint {
 			return addPolicyRes{}, err
 		}
 
-		err := svc.AddPolicy(ctx, auth.PolicyReq{Subject: req.Sub, Object: req.Obj, Relation: req.Act})
-		if err != nil {
+		pr := auth.PolicyReq{
+			Subject:  req.Sub,
+			Object:   req.Obj,
+			Relation: req.Act,
+			TTL:      time.Duration(req.TTL) * time.Second,
+		}
+		if err := svc.AddPolicy(ctx, pr); err != nil {
 			return addPolicyRes{}, err
 		}
-		return addPolicyRes{authorized: true}, err
+		return addPolicyRes{authorized: true}, nil
 	}
 }
 
@@ -142,22 +158,108 @@ func membersEndpoint(svc auth.Service) endpoint.Endpoint {
 		}
 
 		pm := auth.PageMetadata{
-			Offset: req.offset,
-			Limit:  req.limit,
+			Offset:   req.offset,
+			Limit:    req.limit,
+			Relation: req.relation,
 		}
 		mp, err := svc.ListMembers(ctx, req.token, req.groupID, req.memberType, pm)
 		if err != nil {
 			return membersRes{}, err
 		}
 		var members []string
+		var memberships []*mainflux.Membership
 		for _, m := range mp.Members {
 			members = append(members, m.ID)
+			memberships = append(memberships, &mainflux.Membership{
+				Id:        m.ID,
+				Type:      m.Type,
+				Relation:  m.Relation,
+				CreatedBy: m.CreatedBy,
+				CreatedAt: m.CreatedAt.Unix(),
+			})
 		}
 		return membersRes{
-			offset:  req.offset,
-			limit:   req.limit,
-			total:   mp.PageMetadata.Total,
-			members: members,
+			offset:      req.offset,
+			limit:       req.limit,
+			total:       mp.PageMetadata.Total,
+			members:     members,
+			memberships: memberships,
 		}, nil
 	}
 }
+
+func reserveQuotaEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(quotaReq)
+		if err := req.validate(); err != nil {
+			return emptyRes{}, err
+		}
+
+		if err := svc.ReserveQuota(ctx, req.token, req.resource, req.ids...); err != nil {
+			return emptyRes{}, err
+		}
+		return emptyRes{}, nil
+	}
+}
+
+func revokeKeysEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(revokeKeysReq)
+		if err := req.validate(); err != nil {
+			return revokeKeysRes{}, err
+		}
+
+		count, err := svc.RevokeKeys(ctx, req.token, req.subject)
+		if err != nil {
+			return revokeKeysRes{}, err
+		}
+		return revokeKeysRes{count: count}, nil
+	}
+}
+
+func applyPolicyTemplateEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(policyTemplateReq)
+		if err := req.validate(); err != nil {
+			return emptyRes{}, err
+		}
+
+		e := auth.Entity{Type: req.entityType, ID: req.entityID, Owner: req.ownerID}
+		if err := svc.ApplyPolicyTemplate(ctx, e); err != nil {
+			return emptyRes{}, err
+		}
+		return emptyRes{}, nil
+	}
+}
+
+func removeLinksEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(removeLinksReq)
+		if err := req.validate(); err != nil {
+			return emptyRes{}, err
+		}
+
+		// There are no identity links without the OpenID Provider.
+		if svc == nil {
+			return emptyRes{}, nil
+		}
+		if err := svc.RemoveLinks(ctx, req.userID); err != nil {
+			return emptyRes{}, err
+		}
+		return emptyRes{}, nil
+	}
+}
+
+func releaseQuotaEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(quotaReq)
+		if err := req.validate(); err != nil {
+			return emptyRes{}, err
+		}
+
+		if err := svc.ReleaseQuota(ctx, req.resource, req.ids...); err != nil {
+			return emptyRes{}, err
+		}
+		return emptyRes{}, nil
+	}
+}
@@ -15,9 +15,12 @@ import (
 	grpcapi "github.com/mainflux/mainflux/auth/api/grpc"
 	"github.com/mainflux/mainflux/auth/jwt"
 	"github.com/mainflux/mainflux/auth/mocks"
+	"github.com/mainflux/mainflux/auth/oidc"
+	oidcmocks "github.com/mainflux/mainflux/auth/oidc/mocks"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -39,7 +42,10 @@ const (
 	memberRelation = "member"
 )
 
-var svc auth.Service
+var (
+	svc   auth.Service
+	links oidc.LinkRepository
+)
 
 func newService() auth.Service {
 	repo := mocks.NewKeyRepository()
@@ -52,13 +58,16 @@ func newService() auth.Service {
 
 	t := jwt.New(secret)
 
-	return auth.New(repo, groupRepo, idProvider, t, ketoMock)
+	return auth.New(repo, groupRepo, mocks.NewOrgRepository(), mocks.NewShareRepository(), mocks.NewRoleRepository(), mocks.NewServiceAccountRepository(), mocks.NewQuotaRepository(), mocks.NewPolicyExpirationRepository(), mocks.NewRevocationRepository(), mocks.NewSessionRepository(), mocks.NewAuditRepository(), idProvider, t, ketoMock, nil)
 }
 
 func startGRPCServer(svc auth.Service, port int) {
 	listener, _ := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	links = oidcmocks.NewLinkRepository()
+	oidcSvc := oidc.New(oidc.Config{}, svc, oidcmocks.NewCodeRepository(), nil, links, mocks.NewRevocationRepository(), nil)
+
 	server := grpc.NewServer()
-	mainflux.RegisterAuthServiceServer(server, grpcapi.NewServer(mocktracer.New(), svc))
+	mainflux.RegisterAuthServiceServer(server, grpcapi.NewServer(mocktracer.New(), svc, oidcSvc))
 	go server.Serve(listener)
 }
 
@@ -138,6 +147,10 @@ func TestIdentify(t *testing.T) {
 	conn, _ := grpc.Dial(authAddr, grpc.WithInsecure())
 	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
 
+	claims := map[string]string{"device_id": "dev-1", "tenant": "acme"}
+	claimsToken, err := client.Issue(context.Background(), &mainflux.IssueReq{Id: id, Email: email, Type: auth.UserKey, Claims: claims})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key with claims expected to succeed: %s", err))
+
 	cases := []struct {
 		desc  string
 		token string
@@ -166,6 +179,13 @@ func TestIdentify(t *testing.T) {
 			err:   nil,
 			code:  codes.OK,
 		},
+		{
+			desc:  "identify user with user token carrying claims",
+			token: claimsToken.GetValue(),
+			idt:   mainflux.UserIdentity{Email: email, Id: id, Claims: claims},
+			err:   nil,
+			code:  codes.OK,
+		},
 		{
 			desc:  "identify user with invalid user token",
 			token: "invalid",
@@ -193,6 +213,83 @@ func TestIdentify(t *testing.T) {
 	}
 }
 
+func TestIdentifyClientIP(t *testing.T) {
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	_, apiSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), AllowedIPs: []string{"10.0.0.0/8"}})
+	assert.Nil(t, err, fmt.Sprintf("Issuing API key expected to succeed: %s", err))
+
+	authAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(authAddr, grpc.WithInsecure())
+	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
+
+	cases := []struct {
+		desc string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{
+			desc: "identify API key with allowed address propagated by unauthenticated caller",
+			ctx:  auth.WithClientIP(context.Background(), "10.0.0.1"),
+			code: codes.Unauthenticated,
+		},
+		{
+			desc: "identify API key without client address",
+			ctx:  context.Background(),
+			code: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := client.Identify(tc.ctx, &mainflux.Token{Value: apiSecret})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "gRPC status can't be extracted from the error")
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.code, e.Code()))
+	}
+}
+
+func TestIdentifyScope(t *testing.T) {
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	_, apiSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), Scopes: []string{"messages:read"}})
+	assert.Nil(t, err, fmt.Sprintf("Issuing API key expected to succeed: %s", err))
+
+	authAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(authAddr, grpc.WithInsecure())
+	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
+
+	cases := []struct {
+		desc string
+		ctx  context.Context
+		code codes.Code
+	}{
+		{
+			desc: "identify scoped API key for scoped action",
+			ctx:  auth.WithScope(context.Background(), "messages:read"),
+			code: codes.OK,
+		},
+		{
+			desc: "identify scoped API key for action out of scope",
+			ctx:  auth.WithScope(context.Background(), "users:read"),
+			code: codes.Unauthenticated,
+		},
+		{
+			desc: "identify scoped API key without scope",
+			ctx:  context.Background(),
+			code: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := client.Identify(tc.ctx, &mainflux.Token{Value: apiSecret})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "gRPC status can't be extracted from the error")
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.code, e.Code()))
+	}
+}
+
 func TestAuthorize(t *testing.T) {
 	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
@@ -327,6 +424,79 @@ func TestAddPolicy(t *testing.T) {
 	}
 }
 
+func TestApplyPolicyTemplate(t *testing.T) {
+	authAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(authAddr, grpc.WithInsecure())
+	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
+
+	cases := []struct {
+		desc string
+		req  mainflux.PolicyTemplateReq
+		code codes.Code
+	}{
+		{
+			desc: "apply thing template",
+			req:  mainflux.PolicyTemplateReq{EntityType: auth.ThingEntity, EntityID: "thing", OwnerID: id},
+			code: codes.OK,
+		},
+		{
+			desc: "apply thing template without owner",
+			req:  mainflux.PolicyTemplateReq{EntityType: auth.ThingEntity, EntityID: "thing"},
+			code: codes.InvalidArgument,
+		},
+		{
+			desc: "apply template without entity ID",
+			req:  mainflux.PolicyTemplateReq{EntityType: auth.UserEntity},
+			code: codes.InvalidArgument,
+		},
+	}
+	for _, tc := range cases {
+		_, err := client.ApplyPolicyTemplate(context.Background(), &tc.req)
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "gRPC status can't be extracted from the error")
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.code, e.Code()))
+	}
+
+	err := svc.Authorize(context.Background(), auth.PolicyReq{Subject: id, Object: "thing", Relation: "write"})
+	assert.Nil(t, err, fmt.Sprintf("expected template policy to be added: %s", err))
+}
+
+func TestRemoveLinks(t *testing.T) {
+	err := links.Save(context.Background(), oidc.Link{Issuer: "https://accounts.example.com", Subject: "external", UserID: id, Email: email, CreatedAt: time.Now()})
+	require.Nil(t, err, fmt.Sprintf("saving link expected to succeed: %s", err))
+
+	authAddr := fmt.Sprintf("localhost:%d", port)
+	conn, _ := grpc.Dial(authAddr, grpc.WithInsecure())
+	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
+
+	cases := []struct {
+		desc   string
+		userID string
+		code   codes.Code
+	}{
+		{
+			desc:   "remove links of user",
+			userID: id,
+			code:   codes.OK,
+		},
+		{
+			desc:   "remove links without user",
+			userID: "",
+			code:   codes.InvalidArgument,
+		},
+	}
+	for _, tc := range cases {
+		_, err := client.RemoveLinks(context.Background(), &mainflux.RemoveLinksReq{UserID: tc.userID})
+		e, ok := status.FromError(err)
+		assert.True(t, ok, "gRPC status can't be extracted from the error")
+		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.code, e.Code()))
+	}
+
+	ls, err := links.RetrieveByUser(context.Background(), id)
+	require.Nil(t, err, fmt.Sprintf("retrieving links expected to succeed: %s", err))
+	assert.Empty(t, ls, fmt.Sprintf("expected links to be removed got %v", ls))
+}
+
 func TestDeletePolicy(t *testing.T) {
 	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
@@ -418,11 +588,15 @@ func TestMembers(t *testing.T) {
 	err = svc.Assign(context.Background(), token, group.ID, usersType, users...)
 	assert.Nil(t, err, fmt.Sprintf("Assign members to group expected to succeed: %s", err))
 
+	err = svc.SetRelation(context.Background(), token, group.ID, auth.EditorRelation, users[0])
+	assert.Nil(t, err, fmt.Sprintf("Setting member relation expected to succeed: %s", err))
+
 	cases := []struct {
 		desc      string
 		token     string
 		groupID   string
 		groupType string
+		relation  string
 		size      int
 		err       error
 		code      codes.Code
@@ -445,6 +619,36 @@ func TestMembers(t *testing.T) {
 			err:       nil,
 			code:      codes.OK,
 		},
+		{
+			desc:      "get users with editor relation",
+			groupID:   group.ID,
+			token:     token,
+			groupType: usersType,
+			relation:  auth.EditorRelation,
+			size:      1,
+			err:       nil,
+			code:      codes.OK,
+		},
+		{
+			desc:      "get users with viewer relation",
+			groupID:   group.ID,
+			token:     token,
+			groupType: usersType,
+			relation:  auth.ViewerRelation,
+			size:      numOfUsers - 1,
+			err:       nil,
+			code:      codes.OK,
+		},
+		{
+			desc:      "get users with invalid relation",
+			groupID:   group.ID,
+			token:     token,
+			groupType: usersType,
+			relation:  "invalid",
+			size:      0,
+			err:       auth.ErrMalformedEntity,
+			code:      codes.InvalidArgument,
+		},
 	}
 
 	authAddr := fmt.Sprintf("localhost:%d", port)
@@ -452,9 +656,16 @@ func TestMembers(t *testing.T) {
 	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
 
 	for _, tc := range cases {
-		m, err := client.Members(context.Background(), &mainflux.MembersReq{Token: tc.token, GroupID: tc.groupID, Type: tc.groupType, Offset: 0, Limit: 10})
+		m, err := client.Members(context.Background(), &mainflux.MembersReq{Token: tc.token, GroupID: tc.groupID, Type: tc.groupType, Relation: tc.relation, Offset: 0, Limit: 10})
 		e, ok := status.FromError(err)
 		assert.Equal(t, tc.size, len(m.Members), fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.size, len(m.Members)))
+		assert.Equal(t, tc.size, len(m.Memberships), fmt.Sprintf("%s: expected %d memberships got %d", tc.desc, tc.size, len(m.Memberships)))
+		for _, ms := range m.Memberships {
+			if tc.relation != "" {
+				assert.Equal(t, tc.relation, ms.Relation, fmt.Sprintf("%s: expected relation %s got %s", tc.desc, tc.relation, ms.Relation))
+			}
+			assert.Equal(t, id, ms.CreatedBy, fmt.Sprintf("%s: expected creator %s got %s", tc.desc, id, ms.CreatedBy))
+		}
 		assert.Equal(t, tc.code, e.Code(), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.code, e.Code()))
 		assert.True(t, ok, "OK expected to be true")
 	}
@@ -29,6 +29,7 @@ type issueReq struct {
 	id      string
 	email   string
 	keyType uint32
+	claims  map[string]string
 }
 
 func (req issueReq) validate() error {
@@ -37,7 +38,8 @@ func (req issueReq) validate() error {
 	}
 	if req.keyType != auth.UserKey &&
 		req.keyType != auth.APIKey &&
-		req.keyType != auth.RecoveryKey {
+		req.keyType != auth.RecoveryKey &&
+		req.keyType != auth.RefreshKey {
 		return auth.ErrMalformedEntity
 	}
 
@@ -61,12 +63,65 @@ func (req assignReq) validate() error {
 	return nil
 }
 
+type quotaReq struct {
+	token    string
+	resource string
+	ids      []string
+}
+
+func (req quotaReq) validate() error {
+	if req.resource == "" || len(req.ids) == 0 {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
+
+type revokeKeysReq struct {
+	token   string
+	subject string
+}
+
+func (req revokeKeysReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+	if req.subject == "" {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
+
+type policyTemplateReq struct {
+	entityType string
+	entityID   string
+	ownerID    string
+}
+
+func (req policyTemplateReq) validate() error {
+	if req.entityType == "" || req.entityID == "" {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
+
+type removeLinksReq struct {
+	userID string
+}
+
+func (req removeLinksReq) validate() error {
+	if req.userID == "" {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
+
 type membersReq struct {
 	token      string
 	groupID    string
 	offset     uint64
 	limit      uint64
 	memberType string
+	relation   string
 }
 
 func (req membersReq) validate() error {
@@ -79,6 +134,9 @@ func (req membersReq) validate() error {
 	if req.memberType == "" {
 		return auth.ErrMalformedEntity
 	}
+	if req.relation != "" && !auth.ValidRelation(req.relation) {
+		return auth.ErrMalformedEntity
+	}
 	return nil
 }
 
@@ -86,10 +144,13 @@ func (req membersReq) validate() error {
 // 1. subject - an action invoker
 // 2. object - an entity over which action will be executed
 // 3. action - type of action that will be executed (read/write)
+// 4. token - an optional token of the subject, limited to the scope
 type authReq struct {
-	Sub string
-	Obj string
-	Act string
+	Sub   string
+	Obj   string
+	Act   string
+	Token string
+	Scope string
 }
 
 func (req authReq) validate() error {
@@ -112,10 +173,11 @@ type addPolicyReq struct {
 	Sub string
 	Obj string
 	Act string
+	TTL int64
 }
 
 func (req addPolicyReq) validate() error {
-	if req.Sub == "" || req.Obj == "" || req.Act == "" {
+	if req.Sub == "" || req.Obj == "" || req.Act == "" || req.TTL < 0 {
 		return auth.ErrMalformedEntity
 	}
 	return nil
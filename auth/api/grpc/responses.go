@@ -3,9 +3,12 @@
 
 package grpc
 
+import "github.com/mainflux/mainflux"
+
 type identityRes struct {
-	id    string
-	email string
+	id     string
+	email  string
+	claims map[string]string
 }
 
 type issueRes struct {
@@ -29,12 +32,17 @@ type listPoliciesRes struct {
 }
 
 type membersRes struct {
-	total     uint64
-	offset    uint64
-	limit     uint64
-	groupType string
-	members   []string
+	total       uint64
+	offset      uint64
+	limit       uint64
+	groupType   string
+	members     []string
+	memberships []*mainflux.Membership
+}
+type revokeKeysRes struct {
+	count uint64
 }
+
 type emptyRes struct {
 	err error
 }
@@ -11,42 +11,59 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	mainflux "github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/auth/oidc"
 	"github.com/mainflux/mainflux/pkg/errors"
 	opentracing "github.com/opentracing/opentracing-go"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 var _ mainflux.AuthServiceServer = (*grpcServer)(nil)
 
 type grpcServer struct {
-	issue        kitgrpc.Handler
-	identify     kitgrpc.Handler
-	authorize    kitgrpc.Handler
-	addPolicy    kitgrpc.Handler
-	deletePolicy kitgrpc.Handler
-	listPolicies kitgrpc.Handler
-	assign       kitgrpc.Handler
-	members      kitgrpc.Handler
-}
-
-// NewServer returns new AuthServiceServer instance.
-func NewServer(tracer opentracing.Tracer, svc auth.Service) mainflux.AuthServiceServer {
+	issue               kitgrpc.Handler
+	identify            kitgrpc.Handler
+	authorize           kitgrpc.Handler
+	addPolicy           kitgrpc.Handler
+	deletePolicy        kitgrpc.Handler
+	listPolicies        kitgrpc.Handler
+	assign              kitgrpc.Handler
+	members             kitgrpc.Handler
+	reserveQuota        kitgrpc.Handler
+	releaseQuota        kitgrpc.Handler
+	revokeKeys          kitgrpc.Handler
+	applyPolicyTemplate kitgrpc.Handler
+	removeLinks         kitgrpc.Handler
+}
+
+// NewServer returns new AuthServiceServer instance. The OpenID Provider
+// service is nil if the provider is disabled.
+func NewServer(tracer opentracing.Tracer, svc auth.Service, oidcSvc oidc.Service) mainflux.AuthServiceServer {
 	return &grpcServer{
 		issue: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "issue")(issueEndpoint(svc)),
 			decodeIssueRequest,
 			encodeIssueResponse,
+			kitgrpc.ServerBefore(extractClientIP),
+			kitgrpc.ServerBefore(extractScope),
+			kitgrpc.ServerBefore(extractUserAgent),
 		),
 		identify: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "identify")(identifyEndpoint(svc)),
 			decodeIdentifyRequest,
 			encodeIdentifyResponse,
+			kitgrpc.ServerBefore(extractClientIP),
+			kitgrpc.ServerBefore(extractScope),
 		),
 		authorize: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "authorize")(authorizeEndpoint(svc)),
 			decodeAuthorizeRequest,
 			encodeAuthorizeResponse,
+			kitgrpc.ServerBefore(extractClientIP),
+			kitgrpc.ServerBefore(extractScope),
 		),
 		addPolicy: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "add_policy")(addPolicyEndpoint(svc)),
@@ -67,11 +84,42 @@ func NewServer(tracer opentracing.Tracer, svc auth.Service) mainflux.AuthService
 			kitot.TraceServer(tracer, "assign")(assignEndpoint(svc)),
 			decodeAssignRequest,
 			encodeEmptyResponse,
+			kitgrpc.ServerBefore(extractClientIP),
+			kitgrpc.ServerBefore(extractScope),
 		),
 		members: kitgrpc.NewServer(
 			kitot.TraceServer(tracer, "members")(membersEndpoint(svc)),
 			decodeMembersRequest,
 			encodeMembersResponse,
+			kitgrpc.ServerBefore(extractClientIP),
+			kitgrpc.ServerBefore(extractScope),
+		),
+		reserveQuota: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "reserve_quota")(reserveQuotaEndpoint(svc)),
+			decodeQuotaRequest,
+			encodeEmptyResponse,
+			kitgrpc.ServerBefore(extractClientIP),
+			kitgrpc.ServerBefore(extractScope),
+		),
+		releaseQuota: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "release_quota")(releaseQuotaEndpoint(svc)),
+			decodeQuotaRequest,
+			encodeEmptyResponse,
+		),
+		revokeKeys: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "revoke_keys")(revokeKeysEndpoint(svc)),
+			decodeRevokeKeysRequest,
+			encodeRevokeKeysResponse,
+		),
+		applyPolicyTemplate: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "apply_policy_template")(applyPolicyTemplateEndpoint(svc)),
+			decodePolicyTemplateRequest,
+			encodeEmptyResponse,
+		),
+		removeLinks: kitgrpc.NewServer(
+			kitot.TraceServer(tracer, "remove_links")(removeLinksEndpoint(oidcSvc)),
+			decodeRemoveLinksRequest,
+			encodeEmptyResponse,
 		),
 	}
 }
@@ -140,9 +188,49 @@ func (s *grpcServer) Members(ctx context.Context, req *mainflux.MembersReq) (*ma
 	return res.(*mainflux.MembersRes), nil
 }
 
+func (s *grpcServer) ReserveQuota(ctx context.Context, req *mainflux.QuotaReq) (*empty.Empty, error) {
+	_, res, err := s.reserveQuota.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*empty.Empty), nil
+}
+
+func (s *grpcServer) ReleaseQuota(ctx context.Context, req *mainflux.QuotaReq) (*empty.Empty, error) {
+	_, res, err := s.releaseQuota.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*empty.Empty), nil
+}
+
+func (s *grpcServer) RevokeKeys(ctx context.Context, req *mainflux.RevokeKeysReq) (*mainflux.RevokeKeysRes, error) {
+	_, res, err := s.revokeKeys.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*mainflux.RevokeKeysRes), nil
+}
+
+func (s *grpcServer) ApplyPolicyTemplate(ctx context.Context, req *mainflux.PolicyTemplateReq) (*empty.Empty, error) {
+	_, res, err := s.applyPolicyTemplate.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*empty.Empty), nil
+}
+
+func (s *grpcServer) RemoveLinks(ctx context.Context, req *mainflux.RemoveLinksReq) (*empty.Empty, error) {
+	_, res, err := s.removeLinks.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, encodeError(err)
+	}
+	return res.(*empty.Empty), nil
+}
+
 func decodeIssueRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.IssueReq)
-	return issueReq{id: req.GetId(), email: req.GetEmail(), keyType: req.GetType()}, nil
+	return issueReq{id: req.GetId(), email: req.GetEmail(), keyType: req.GetType(), claims: req.GetClaims()}, nil
 }
 
 func encodeIssueResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
@@ -157,12 +245,12 @@ func decodeIdentifyRequest(_ context.Context, grpcReq interface{}) (interface{},
 
 func encodeIdentifyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(identityRes)
-	return &mainflux.UserIdentity{Id: res.id, Email: res.email}, nil
+	return &mainflux.UserIdentity{Id: res.id, Email: res.email, Claims: res.claims}, nil
 }
 
 func decodeAuthorizeRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AuthorizeReq)
-	return authReq{Act: req.GetAct(), Obj: req.GetObj(), Sub: req.GetSub()}, nil
+	return authReq{Act: req.GetAct(), Obj: req.GetObj(), Sub: req.GetSub(), Token: req.GetToken(), Scope: req.GetScope()}, nil
 }
 
 func encodeAuthorizeResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
@@ -172,7 +260,7 @@ func encodeAuthorizeResponse(_ context.Context, grpcRes interface{}) (interface{
 
 func decodeAddPolicyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AddPolicyReq)
-	return addPolicyReq{Sub: req.GetSub(), Obj: req.GetObj(), Act: req.GetAct()}, nil
+	return addPolicyReq{Sub: req.GetSub(), Obj: req.GetObj(), Act: req.GetAct(), TTL: req.GetTtl()}, nil
 }
 
 func encodeAddPolicyResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
@@ -211,6 +299,7 @@ func decodeMembersRequest(_ context.Context, grpcReq interface{}) (interface{},
 		token:      req.GetToken(),
 		groupID:    req.GetGroupID(),
 		memberType: req.GetType(),
+		relation:   req.GetRelation(),
 		offset:     req.Offset,
 		limit:      req.Limit,
 	}, nil
@@ -219,11 +308,45 @@ func decodeMembersRequest(_ context.Context, grpcReq interface{}) (interface{},
 func encodeMembersResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
 	res := grpcRes.(membersRes)
 	return &mainflux.MembersRes{
-		Total:   res.total,
-		Offset:  res.offset,
-		Limit:   res.limit,
-		Type:    res.groupType,
-		Members: res.members,
+		Total:       res.total,
+		Offset:      res.offset,
+		Limit:       res.limit,
+		Type:        res.groupType,
+		Members:     res.members,
+		Memberships: res.memberships,
+	}, nil
+}
+
+func decodeQuotaRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.QuotaReq)
+	return quotaReq{
+		token:    req.GetToken(),
+		resource: req.GetResource(),
+		ids:      req.GetIds(),
+	}, nil
+}
+
+func decodeRevokeKeysRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.RevokeKeysReq)
+	return revokeKeysReq{token: req.GetToken(), subject: req.GetSubject()}, nil
+}
+
+func encodeRevokeKeysResponse(_ context.Context, grpcRes interface{}) (interface{}, error) {
+	res := grpcRes.(revokeKeysRes)
+	return &mainflux.RevokeKeysRes{Count: res.count}, nil
+}
+
+func decodeRemoveLinksRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.RemoveLinksReq)
+	return removeLinksReq{userID: req.GetUserID()}, nil
+}
+
+func decodePolicyTemplateRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*mainflux.PolicyTemplateReq)
+	return policyTemplateReq{
+		entityType: req.GetEntityType(),
+		entityID:   req.GetEntityID(),
+		ownerID:    req.GetOwnerID(),
 	}, nil
 }
 
@@ -232,6 +355,46 @@ func encodeEmptyResponse(_ context.Context, grpcRes interface{}) (interface{}, e
 	return &empty.Empty{}, encodeError(res.err)
 }
 
+// extractClientIP stores the IP address of the client propagated by the
+// caller in the context. The address is accepted only from the caller
+// authenticated by the client certificate, i.e. the internal service, since
+// any other caller reaching the port can propagate any address.
+func extractClientIP(ctx context.Context, md metadata.MD) context.Context {
+	if vals := md.Get(clientIPKey); len(vals) > 0 && verifiedPeer(ctx) {
+		return auth.WithClientIP(ctx, vals[0])
+	}
+	return ctx
+}
+
+// verifiedPeer tells whether the caller presented the client certificate
+// verified by the server requiring mutual TLS.
+func verifiedPeer(ctx context.Context) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	return ok && len(info.State.VerifiedChains) > 0
+}
+
+// extractScope stores the scope of the action propagated by the caller in the
+// context.
+func extractScope(ctx context.Context, md metadata.MD) context.Context {
+	if vals := md.Get(scopeKey); len(vals) > 0 {
+		return auth.WithScope(ctx, vals[0])
+	}
+	return ctx
+}
+
+// extractUserAgent stores the user agent of the client propagated by the
+// caller in the context.
+func extractUserAgent(ctx context.Context, md metadata.MD) context.Context {
+	if vals := md.Get(userAgentKey); len(vals) > 0 {
+		return auth.WithUserAgent(ctx, vals[0])
+	}
+	return ctx
+}
+
 func encodeError(err error) error {
 	switch {
 	case errors.Contains(err, nil):
@@ -243,6 +406,8 @@ func encodeError(err error) error {
 		return status.Error(codes.Unauthenticated, err.Error())
 	case errors.Contains(err, auth.ErrKeyExpired):
 		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Contains(err, auth.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
 	default:
 		return status.Error(codes.Internal, "internal server error")
 	}
@@ -0,0 +1,81 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"google.golang.org/grpc/credentials"
+)
+
+var (
+	errLoadCerts   = errors.New("failed to load certificates")
+	errLoadCACerts = errors.New("failed to load CA certificates")
+)
+
+// ClientCredentials returns the TLS credentials of the auth gRPC client. The
+// server certificate is verified against the CA certificates, or against the
+// system roots if the CA certificates aren't given. The client certificate,
+// if given, authenticates the client to the server requiring mutual TLS.
+func ClientCredentials(caCerts, certFile, keyFile string) (credentials.TransportCredentials, error) {
+	cfg := &tls.Config{}
+	if caCerts != "" {
+		pool, err := loadCertPool(caCerts)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.Wrap(errLoadCerts, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+// ServerCredentials returns the TLS credentials of the auth gRPC server. If
+// the client CA certificates are given, the server requires the clients to
+// present the certificate signed by one of them.
+func ServerCredentials(certFile, keyFile, clientCACerts string) (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.Wrap(errLoadCerts, err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if clientCACerts != "" {
+		pool, err := loadCertPool(clientCACerts)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	return credentials.NewTLS(cfg), nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(errLoadCACerts, err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errLoadCACerts
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,240 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
+	grpcapi "github.com/mainflux/mainflux/auth/api/grpc"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+const (
+	tlsPort   = 8082
+	tlsIPPort = 8083
+)
+
+type certFiles struct {
+	cert string
+	key  string
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+
+	caCert, caKey, caFiles := newCert(t, dir, "ca", nil, nil)
+	serverFiles := certFilesOf(newCert(t, dir, "server", caCert, caKey))
+	clientFiles := certFilesOf(newCert(t, dir, "client", caCert, caKey))
+	_, _, untrustedCA := newCert(t, dir, "untrusted-ca", nil, nil)
+
+	creds, err := grpcapi.ServerCredentials(serverFiles.cert, serverFiles.key, caFiles.cert)
+	require.Nil(t, err, fmt.Sprintf("unexpected error loading server credentials: %s", err))
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", tlsPort))
+	require.Nil(t, err, fmt.Sprintf("unexpected error listening: %s", err))
+	server := grpc.NewServer(grpc.Creds(creds))
+	mainflux.RegisterAuthServiceServer(server, grpcapi.NewServer(mocktracer.New(), svc, nil))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	_, token, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc    string
+		caCerts string
+		cert    string
+		key     string
+		success bool
+	}{
+		{
+			desc:    "identify with trusted client certificate",
+			caCerts: caFiles.cert,
+			cert:    clientFiles.cert,
+			key:     clientFiles.key,
+			success: true,
+		},
+		{
+			desc:    "identify without client certificate",
+			caCerts: caFiles.cert,
+			success: false,
+		},
+		{
+			desc:    "identify with untrusted client certificate",
+			caCerts: caFiles.cert,
+			cert:    untrustedCA.cert,
+			key:     untrustedCA.key,
+			success: false,
+		},
+		{
+			desc:    "identify with untrusted server certificate",
+			caCerts: untrustedCA.cert,
+			cert:    clientFiles.cert,
+			key:     clientFiles.key,
+			success: false,
+		},
+	}
+
+	for _, tc := range cases {
+		creds, err := grpcapi.ClientCredentials(tc.caCerts, tc.cert, tc.key)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error loading client credentials: %s", tc.desc, err))
+
+		conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", tlsPort), grpc.WithTransportCredentials(creds))
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error dialing: %s", tc.desc, err))
+		client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
+
+		idt, err := client.Identify(context.Background(), &mainflux.Token{Value: token})
+		if tc.success {
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			assert.Equal(t, email, idt.GetEmail(), fmt.Sprintf("%s: expected %s got %s", tc.desc, email, idt.GetEmail()))
+		} else {
+			assert.NotNil(t, err, fmt.Sprintf("%s: expected error", tc.desc))
+		}
+		conn.Close()
+	}
+}
+
+func TestMutualTLSClientIP(t *testing.T) {
+	dir := t.TempDir()
+
+	caCert, caKey, caFiles := newCert(t, dir, "ca", nil, nil)
+	serverFiles := certFilesOf(newCert(t, dir, "server", caCert, caKey))
+	clientFiles := certFilesOf(newCert(t, dir, "client", caCert, caKey))
+
+	creds, err := grpcapi.ServerCredentials(serverFiles.cert, serverFiles.key, caFiles.cert)
+	require.Nil(t, err, fmt.Sprintf("unexpected error loading server credentials: %s", err))
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", tlsIPPort))
+	require.Nil(t, err, fmt.Sprintf("unexpected error listening: %s", err))
+	server := grpc.NewServer(grpc.Creds(creds))
+	mainflux.RegisterAuthServiceServer(server, grpcapi.NewServer(mocktracer.New(), svc, nil))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+	_, apiSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), AllowedIPs: []string{"10.0.0.0/8"}})
+	require.Nil(t, err, fmt.Sprintf("Issuing API key expected to succeed: %s", err))
+
+	clientCreds, err := grpcapi.ClientCredentials(caFiles.cert, clientFiles.cert, clientFiles.key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error loading client credentials: %s", err))
+	conn, err := grpc.Dial(fmt.Sprintf("localhost:%d", tlsIPPort), grpc.WithTransportCredentials(clientCreds))
+	require.Nil(t, err, fmt.Sprintf("unexpected error dialing: %s", err))
+	defer conn.Close()
+	client := grpcapi.NewClient(mocktracer.New(), conn, time.Second)
+
+	cases := []struct {
+		desc    string
+		ctx     context.Context
+		success bool
+	}{
+		{
+			desc:    "identify API key from allowed network",
+			ctx:     auth.WithClientIP(context.Background(), "10.0.0.1"),
+			success: true,
+		},
+		{
+			desc:    "identify API key from other network",
+			ctx:     auth.WithClientIP(context.Background(), "203.0.113.7"),
+			success: false,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := client.Identify(tc.ctx, &mainflux.Token{Value: apiSecret})
+		if tc.success {
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		} else {
+			assert.NotNil(t, err, fmt.Sprintf("%s: expected error", tc.desc))
+		}
+	}
+}
+
+func TestCredentialsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	_, _, files := newCert(t, dir, "ca", nil, nil)
+	missing := filepath.Join(dir, "missing.pem")
+
+	_, err := grpcapi.ServerCredentials(missing, files.key, "")
+	assert.NotNil(t, err, "expected error loading missing server certificate")
+
+	_, err = grpcapi.ServerCredentials(files.cert, files.key, missing)
+	assert.NotNil(t, err, "expected error loading missing client CA certificates")
+
+	_, err = grpcapi.ServerCredentials(files.cert, files.key, files.key)
+	assert.NotNil(t, err, "expected error loading client CA certificates without certificate")
+
+	_, err = grpcapi.ClientCredentials(missing, "", "")
+	assert.NotNil(t, err, "expected error loading missing CA certificates")
+
+	_, err = grpcapi.ClientCredentials("", files.cert, missing)
+	assert.NotNil(t, err, "expected error loading missing client key")
+
+	_, err = grpcapi.ClientCredentials("", "", "")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error loading credentials using system roots: %s", err))
+}
+
+// newCert creates the certificate signed by the parent, or the self-signed
+// CA certificate if the parent isn't given.
+func newCert(t *testing.T, dir, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, certFiles) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("unexpected error generating key: %s", err))
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+		parent, parentKey = tmpl, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	require.Nil(t, err, fmt.Sprintf("unexpected error creating certificate: %s", err))
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err, fmt.Sprintf("unexpected error parsing certificate: %s", err))
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error marshaling key: %s", err))
+
+	files := certFiles{
+		cert: filepath.Join(dir, name+".crt"),
+		key:  filepath.Join(dir, name+".key"),
+	}
+	err = ioutil.WriteFile(files.cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)
+	require.Nil(t, err, fmt.Sprintf("unexpected error writing certificate: %s", err))
+	err = ioutil.WriteFile(files.key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	require.Nil(t, err, fmt.Sprintf("unexpected error writing key: %s", err))
+
+	return cert, key, files
+}
+
+func certFilesOf(_ *x509.Certificate, _ *ecdsa.PrivateKey, files certFiles) certFiles {
+	return files
+}
@@ -0,0 +1,203 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package accounts
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/auth"
+)
+
+func createAccountEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createAccountReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sa := auth.ServiceAccount{
+			Name:    req.Name,
+			OwnerID: req.OwnerID,
+			Scopes:  req.Scopes,
+		}
+		saved, err := svc.CreateServiceAccount(ctx, req.token, sa)
+		if err != nil {
+			return nil, err
+		}
+
+		return accountRes{toViewAccountRes(saved), true}, nil
+	}
+}
+
+func listAccountsEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listAccountsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		accounts, err := svc.ListServiceAccounts(ctx, req.token, req.ownerID)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listAccountsRes{Accounts: []viewAccountRes{}}
+		for _, sa := range accounts {
+			res.Accounts = append(res.Accounts, toViewAccountRes(sa))
+		}
+
+		return res, nil
+	}
+}
+
+func viewAccountEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(accountIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sa, err := svc.ViewServiceAccount(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return accountRes{toViewAccountRes(sa), false}, nil
+	}
+}
+
+func updateScopesEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateScopesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sa, err := svc.UpdateServiceAccountScopes(ctx, req.token, req.id, req.Scopes)
+		if err != nil {
+			return nil, err
+		}
+
+		return accountRes{toViewAccountRes(sa), false}, nil
+	}
+}
+
+func disableAccountEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(accountIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DisableServiceAccount(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return changeStatusRes{}, nil
+	}
+}
+
+func enableAccountEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(accountIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.EnableServiceAccount(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return changeStatusRes{}, nil
+	}
+}
+
+func removeAccountEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(accountIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveServiceAccount(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func issueKeyEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(issueKeyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		key, secret, err := svc.IssueServiceAccountKey(ctx, req.token, req.id, req.Duration*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		return toIssueKeyRes(key, secret), nil
+	}
+}
+
+func rotateKeyEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(keyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		key, secret, err := svc.RotateServiceAccountKey(ctx, req.token, req.id, req.keyID)
+		if err != nil {
+			return nil, err
+		}
+
+		return toIssueKeyRes(key, secret), nil
+	}
+}
+
+func revokeKeyEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(keyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeServiceAccountKey(ctx, req.token, req.id, req.keyID); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toViewAccountRes(sa auth.ServiceAccount) viewAccountRes {
+	return viewAccountRes{
+		ID:        sa.ID,
+		Name:      sa.Name,
+		OwnerID:   sa.OwnerID,
+		Scopes:    sa.Scopes,
+		Disabled:  sa.Disabled,
+		CreatedBy: sa.CreatedBy,
+		CreatedAt: sa.CreatedAt,
+	}
+}
+
+func toIssueKeyRes(key auth.Key, secret string) issueKeyRes {
+	res := issueKeyRes{
+		ID:       key.ID,
+		Value:    secret,
+		IssuedAt: key.IssuedAt,
+	}
+	if !key.ExpiresAt.IsZero() {
+		res.ExpiresAt = &key.ExpiresAt
+	}
+
+	return res
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package accounts
+
+import (
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+type createAccountReq struct {
+	token   string
+	Name    string   `json:"name"`
+	OwnerID string   `json:"owner_id"`
+	Scopes  []string `json:"scopes"`
+}
+
+func (req createAccountReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.Name == "" || req.OwnerID == "" || len(req.Scopes) == 0 {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listAccountsReq struct {
+	token   string
+	ownerID string
+}
+
+func (req listAccountsReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.ownerID == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type updateScopesReq struct {
+	token  string
+	id     string
+	Scopes []string `json:"scopes"`
+}
+
+func (req updateScopesReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || len(req.Scopes) == 0 {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type accountIDReq struct {
+	token string
+	id    string
+}
+
+func (req accountIDReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type issueKeyReq struct {
+	token    string
+	id       string
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+func (req issueKeyReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.Duration < 0 {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type keyReq struct {
+	token string
+	id    string
+	keyID string
+}
+
+func (req keyReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || req.keyID == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package accounts
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*accountRes)(nil)
+	_ mainflux.Response = (*listAccountsRes)(nil)
+	_ mainflux.Response = (*issueKeyRes)(nil)
+	_ mainflux.Response = (*changeStatusRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type viewAccountRes struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	OwnerID   string    `json:"owner_id"`
+	Scopes    []string  `json:"scopes"`
+	Disabled  bool      `json:"disabled"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type accountRes struct {
+	viewAccountRes
+	created bool
+}
+
+func (res accountRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res accountRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/service-accounts/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res accountRes) Empty() bool {
+	return false
+}
+
+type listAccountsRes struct {
+	Accounts []viewAccountRes `json:"service_accounts"`
+}
+
+func (res listAccountsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listAccountsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listAccountsRes) Empty() bool {
+	return false
+}
+
+type issueKeyRes struct {
+	ID        string     `json:"id,omitempty"`
+	Value     string     `json:"value,omitempty"`
+	IssuedAt  time.Time  `json:"issued_at,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func (res issueKeyRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res issueKeyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res issueKeyRes) Empty() bool {
+	return res.Value == ""
+}
+
+type changeStatusRes struct{}
+
+func (res changeStatusRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res changeStatusRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res changeStatusRes) Empty() bool {
+	return true
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
@@ -0,0 +1,235 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package accounts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	contentType = "application/json"
+	ownerKey    = "owner"
+)
+
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
+	}
+
+	mux.Post("/service-accounts", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_service_account")(createAccountEndpoint(svc)),
+		decodeCreateAccountRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/service-accounts", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_service_accounts")(listAccountsEndpoint(svc)),
+		decodeListAccountsRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/service-accounts/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_service_account")(viewAccountEndpoint(svc)),
+		decodeAccountIDRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/service-accounts/:id/scopes", kithttp.NewServer(
+		kitot.TraceServer(tracer, "update_service_account_scopes")(updateScopesEndpoint(svc)),
+		decodeUpdateScopesRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/service-accounts/:id/disable", kithttp.NewServer(
+		kitot.TraceServer(tracer, "disable_service_account")(disableAccountEndpoint(svc)),
+		decodeAccountIDRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/service-accounts/:id/enable", kithttp.NewServer(
+		kitot.TraceServer(tracer, "enable_service_account")(enableAccountEndpoint(svc)),
+		decodeAccountIDRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/service-accounts/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_service_account")(removeAccountEndpoint(svc)),
+		decodeAccountIDRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/service-accounts/:id/keys", kithttp.NewServer(
+		kitot.TraceServer(tracer, "issue_service_account_key")(issueKeyEndpoint(svc)),
+		decodeIssueKeyRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/service-accounts/:id/keys/:keyID/rotate", kithttp.NewServer(
+		kitot.TraceServer(tracer, "rotate_service_account_key")(rotateKeyEndpoint(svc)),
+		decodeKeyRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/service-accounts/:id/keys/:keyID", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_service_account_key")(revokeKeyEndpoint(svc)),
+		decodeKeyRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	return mux
+}
+
+func decodeCreateAccountRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := createAccountReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeListAccountsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	ownerID, err := httputil.ReadStringQuery(r, ownerKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := listAccountsReq{
+		token:   r.Header.Get("Authorization"),
+		ownerID: ownerID,
+	}
+
+	return req, nil
+}
+
+func decodeUpdateScopesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := updateScopesReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeAccountIDRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := accountIDReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeIssueKeyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := issueKeyReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeKeyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := keyReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+		keyID: bone.GetValue(r, "keyID"),
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	switch {
+	case errors.Contains(err, auth.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, io.EOF),
+		errors.Contains(err, io.ErrUnexpectedEOF):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, auth.ErrUnauthorizedAccess),
+		errors.Contains(err, auth.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, auth.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, auth.ErrConflict):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	errorVal, ok := err.(errors.Error)
+	if ok {
+		w.Header().Set("Content-Type", contentType)
+		if err := json.NewEncoder(w).Encode(errorRes{Err: i18n.Localize(ctx, errorVal.Msg())}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/auth"
+)
+
+func listAuditEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listAuditReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListAuditRecords(ctx, req.token, req.filter, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := auditPageRes{
+			Total:   page.Total,
+			Offset:  page.Offset,
+			Limit:   page.Limit,
+			Records: []auditRecordRes{},
+		}
+		for _, r := range page.Records {
+			res.Records = append(res.Records, auditRecordRes{
+				ID:        r.ID,
+				Operation: r.Operation,
+				Subject:   r.Subject,
+				Object:    r.Object,
+				Relation:  r.Relation,
+				Decision:  r.Decision,
+				CreatedAt: r.CreatedAt,
+			})
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import "github.com/mainflux/mainflux/auth"
+
+const maxLimit = 100
+
+type listAuditReq struct {
+	token  string
+	filter auth.AuditFilter
+	offset uint64
+	limit  uint64
+}
+
+func (req listAuditReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimit {
+		return auth.ErrMalformedEntity
+	}
+
+	if !req.filter.From.IsZero() && !req.filter.To.IsZero() && !req.filter.From.Before(req.filter.To) {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.Response = (*auditPageRes)(nil)
+
+type auditRecordRes struct {
+	ID        uint64    `json:"id"`
+	Operation string    `json:"operation"`
+	Subject   string    `json:"subject"`
+	Object    string    `json:"object"`
+	Relation  string    `json:"relation"`
+	Decision  string    `json:"decision"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type auditPageRes struct {
+	Total   uint64           `json:"total"`
+	Offset  uint64           `json:"offset"`
+	Limit   uint64           `json:"limit"`
+	Records []auditRecordRes `json:"records"`
+}
+
+func (res auditPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res auditPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res auditPageRes) Empty() bool {
+	return false
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	contentType  = "application/json"
+	operationKey = "operation"
+	subjectKey   = "subject"
+	objectKey    = "object"
+	relationKey  = "relation"
+	decisionKey  = "decision"
+	fromKey      = "from"
+	toKey        = "to"
+	offsetKey    = "offset"
+	limitKey     = "limit"
+	defLimit     = 10
+)
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
+	}
+
+	mux.Get("/audit", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_audit_records")(listAuditEndpoint(svc)),
+		decodeListAuditRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	return mux
+}
+
+func decodeListAuditRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	operation, err := httputil.ReadEnumQuery(r, operationKey, "", auth.CheckOperation, auth.AddOperation, auth.DeleteOperation)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := httputil.ReadEnumQuery(r, decisionKey, "", auth.AllowedDecision, auth.DeniedDecision, auth.SuccessDecision, auth.FailureDecision)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := httputil.ReadStringQuery(r, subjectKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := httputil.ReadStringQuery(r, objectKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	relation, err := httputil.ReadStringQuery(r, relationKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := httputil.ReadTimeQuery(r, fromKey, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := httputil.ReadTimeQuery(r, toKey, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := httputil.ReadUintQuery(r, offsetKey, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listAuditReq{
+		token: r.Header.Get("Authorization"),
+		filter: auth.AuditFilter{
+			Operation: operation,
+			Subject:   subject,
+			Object:    object,
+			Relation:  relation,
+			Decision:  decision,
+			From:      from,
+			To:        to,
+		},
+		offset: offset,
+		limit:  limit,
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	switch {
+	case errors.Contains(err, auth.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, auth.ErrUnauthorizedAccess),
+		errors.Contains(err, auth.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	errorVal, ok := err.(errors.Error)
+	if ok {
+		w.Header().Set("Content-Type", contentType)
+		if err := json.NewEncoder(w).Encode(errorRes{Err: i18n.Localize(ctx, errorVal.Msg())}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
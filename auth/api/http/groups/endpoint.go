@@ -19,6 +19,7 @@ func createGroupEndpoint(svc auth.Service) endpoint.Endpoint {
 			Description: req.Description,
 			ParentID:    req.ParentID,
 			Metadata:    req.Metadata,
+			Labels:      req.Labels,
 		}
 
 		group, err := svc.CreateGroup(ctx, req.token, group)
@@ -47,8 +48,10 @@ func viewGroupEndpoint(svc auth.Service) endpoint.Endpoint {
 			Name:        group.Name,
 			Description: group.Description,
 			Metadata:    group.Metadata,
+			Labels:      group.Labels,
 			ParentID:    group.ParentID,
 			OwnerID:     group.OwnerID,
+			OrgID:       group.OrgID,
 			CreatedAt:   group.CreatedAt,
 			UpdatedAt:   group.UpdatedAt,
 		}
@@ -69,6 +72,7 @@ func updateGroupEndpoint(svc auth.Service) endpoint.Endpoint {
 			Name:        req.Name,
 			Description: req.Description,
 			Metadata:    req.Metadata,
+			Labels:      req.Labels,
 		}
 
 		_, err := svc.UpdateGroup(ctx, req.token, group)
@@ -105,6 +109,7 @@ func listGroupsEndpoint(svc auth.Service) endpoint.Endpoint {
 		pm := auth.PageMetadata{
 			Level:    req.level,
 			Metadata: req.metadata,
+			Selector: req.selector,
 		}
 		page, err := svc.ListGroups(ctx, req.token, pm)
 		if err != nil {
@@ -130,6 +135,7 @@ func listMemberships(svc auth.Service) endpoint.Endpoint {
 			Offset:   req.offset,
 			Limit:    req.limit,
 			Metadata: req.metadata,
+			Selector: req.selector,
 		}
 
 		page, err := svc.ListMemberships(ctx, req.token, req.id, pm)
@@ -165,6 +171,7 @@ func listChildrenEndpoint(svc auth.Service) endpoint.Endpoint {
 		pm := auth.PageMetadata{
 			Level:    req.level,
 			Metadata: req.metadata,
+			Selector: req.selector,
 		}
 		page, err := svc.ListChildren(ctx, req.token, req.id, pm)
 		if err != nil {
@@ -188,6 +195,7 @@ func listParentsEndpoint(svc auth.Service) endpoint.Endpoint {
 		pm := auth.PageMetadata{
 			Level:    req.level,
 			Metadata: req.metadata,
+			Selector: req.selector,
 		}
 
 		page, err := svc.ListParents(ctx, req.token, req.id, pm)
@@ -210,7 +218,35 @@ func assignEndpoint(svc auth.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if err := svc.Assign(ctx, req.token, req.groupID, req.Type, req.Members...); err != nil {
+		if req.ExpiresAt != nil {
+			if err := svc.AssignTemporary(ctx, req.token, req.groupID, req.Type, *req.ExpiresAt, req.Members...); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := svc.Assign(ctx, req.token, req.groupID, req.Type, req.Members...); err != nil {
+				return nil, err
+			}
+		}
+
+		// Members are assigned as viewers, unless requested otherwise.
+		if req.Relation != "" && req.Relation != auth.ViewerRelation {
+			if err := svc.SetRelation(ctx, req.token, req.groupID, req.Relation, req.Members...); err != nil {
+				return nil, err
+			}
+		}
+
+		return assignRes{}, nil
+	}
+}
+
+func setRelationEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(setRelationReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.SetRelation(ctx, req.token, req.groupID, req.Relation, req.Members...); err != nil {
 			return nil, err
 		}
 
@@ -218,6 +254,56 @@ func assignEndpoint(svc auth.Service) endpoint.Endpoint {
 	}
 }
 
+func delegateGroupAdminEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(groupAdminReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.DelegateGroupAdmin(ctx, req.token, req.groupID, req.UserID, req.Relations...); err != nil {
+			return nil, err
+		}
+
+		return assignRes{}, nil
+	}
+}
+
+func revokeGroupAdminEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(groupAdminReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeGroupAdmin(ctx, req.token, req.groupID, req.UserID, req.Relations...); err != nil {
+			return nil, err
+		}
+
+		return unassignRes{}, nil
+	}
+}
+
+func listGroupAdminsEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(groupReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		admins, err := svc.ListGroupAdmins(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := groupAdminsRes{Admins: []groupAdminRes{}}
+		for _, a := range admins {
+			res.Admins = append(res.Admins, groupAdminRes{UserID: a.Subject, Relation: a.Relation})
+		}
+		return res, nil
+	}
+}
+
 func unassignEndpoint(svc auth.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(unassignReq)
@@ -244,6 +330,7 @@ func listMembersEndpoint(svc auth.Service) endpoint.Endpoint {
 			Offset:   req.offset,
 			Limit:    req.limit,
 			Metadata: req.metadata,
+			Relation: req.relation,
 		}
 		page, err := svc.ListMembers(ctx, req.token, req.id, req.groupType, pm)
 		if err != nil {
@@ -304,9 +391,11 @@ func toViewGroupRes(group auth.Group) viewGroupRes {
 		ID:          group.ID,
 		ParentID:    group.ParentID,
 		OwnerID:     group.OwnerID,
+		OrgID:       group.OrgID,
 		Name:        group.Name,
 		Description: group.Description,
 		Metadata:    group.Metadata,
+		Labels:      group.Labels,
 		Level:       group.Level,
 		Path:        group.Path,
 		Children:    make([]*viewGroupRes, 0),
@@ -336,9 +425,11 @@ func buildGroupsResponse(gp auth.GroupPage) groupPageRes {
 			ID:          group.ID,
 			ParentID:    group.ParentID,
 			OwnerID:     group.OwnerID,
+			OrgID:       group.OrgID,
 			Name:        group.Name,
 			Description: group.Description,
 			Metadata:    group.Metadata,
+			Labels:      group.Labels,
 			Level:       group.Level,
 			Path:        group.Path,
 			CreatedAt:   group.CreatedAt,
@@ -358,11 +449,17 @@ func buildUsersResponse(mp auth.MemberPage) memberPageRes {
 			Limit:  mp.Limit,
 			Name:   mp.Name,
 		},
-		Members: []interface{}{},
+		Members: []memberRes{},
 	}
 
 	for _, m := range mp.Members {
-		res.Members = append(res.Members, m)
+		res.Members = append(res.Members, memberRes{
+			ID:        m.ID,
+			Type:      m.Type,
+			Relation:  m.Relation,
+			CreatedBy: m.CreatedBy,
+			CreatedAt: m.CreatedAt,
+		})
 	}
 
 	return res
@@ -17,6 +17,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -58,7 +59,7 @@ func newService() auth.Service {
 	idProvider := uuid.NewMock()
 	t := jwt.New(secret)
 	policies := mocks.NewKetoMock(map[string][]mocks.MockSubjectSet{})
-	return auth.New(keys, groups, idProvider, t, policies)
+	return auth.New(keys, groups, mocks.NewOrgRepository(), mocks.NewShareRepository(), mocks.NewRoleRepository(), mocks.NewServiceAccountRepository(), mocks.NewQuotaRepository(), mocks.NewPolicyExpirationRepository(), mocks.NewRevocationRepository(), mocks.NewSessionRepository(), mocks.NewAuditRepository(), idProvider, t, policies, nil)
 }
 
 func newServer(svc auth.Service) *httptest.Server {
@@ -170,3 +171,365 @@ func TestShareGroupAccess(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 	}
 }
+
+func TestListMembersByRelation(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	group, err := svc.CreateGroup(context.Background(), secret, auth.Group{Name: "group"})
+	assert.Nil(t, err, fmt.Sprintf("Creating group expected to succeed: %s", err))
+
+	type assignReq struct {
+		Type     string   `json:"type,omitempty"`
+		Members  []string `json:"members"`
+		Relation string   `json:"relation,omitempty"`
+	}
+
+	setup := []struct {
+		desc   string
+		method string
+		req    assignReq
+		status int
+	}{
+		{
+			desc:   "assign viewers",
+			method: http.MethodPost,
+			req:    assignReq{Type: "users", Members: []string{"viewer", "editor"}},
+			status: http.StatusOK,
+		},
+		{
+			desc:   "assign owner",
+			method: http.MethodPost,
+			req:    assignReq{Type: "users", Members: []string{"owner"}, Relation: auth.OwnerRelation},
+			status: http.StatusOK,
+		},
+		{
+			desc:   "assign member with invalid relation",
+			method: http.MethodPost,
+			req:    assignReq{Type: "users", Members: []string{"invalid"}, Relation: "invalid"},
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "set editor relation",
+			method: http.MethodPut,
+			req:    assignReq{Members: []string{"editor"}, Relation: auth.EditorRelation},
+			status: http.StatusOK,
+		},
+		{
+			desc:   "set relation of non-member",
+			method: http.MethodPut,
+			req:    assignReq{Members: []string{"unknown"}, Relation: auth.EditorRelation},
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "set empty relation",
+			method: http.MethodPut,
+			req:    assignReq{Members: []string{"editor"}},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range setup {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      tc.method,
+			url:         fmt.Sprintf("%s/groups/%s/members", ts.URL, group.ID),
+			contentType: contentType,
+			token:       secret,
+			body:        strings.NewReader(toJSON(tc.req)),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	type memberRes struct {
+		ID        string `json:"id"`
+		Relation  string `json:"relation"`
+		CreatedBy string `json:"created_by"`
+	}
+	type memberPageRes struct {
+		Total   uint64      `json:"total"`
+		Members []memberRes `json:"members"`
+	}
+
+	cases := []struct {
+		desc     string
+		relation string
+		status   int
+		members  []string
+	}{
+		{
+			desc:     "list owners",
+			relation: auth.OwnerRelation,
+			status:   http.StatusOK,
+			members:  []string{"owner"},
+		},
+		{
+			desc:     "list editors",
+			relation: auth.EditorRelation,
+			status:   http.StatusOK,
+			members:  []string{"editor"},
+		},
+		{
+			desc:     "list viewers",
+			relation: auth.ViewerRelation,
+			status:   http.StatusOK,
+			members:  []string{"viewer"},
+		},
+		{
+			desc:     "list members with invalid relation",
+			relation: "invalid",
+			status:   http.StatusBadRequest,
+			members:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/groups/%s/members?type=users&relation=%s", ts.URL, group.ID, tc.relation),
+			token:  secret,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var page memberPageRes
+		json.NewDecoder(res.Body).Decode(&page)
+		var members []string
+		for _, m := range page.Members {
+			members = append(members, m.ID)
+			assert.Equal(t, tc.relation, m.Relation, fmt.Sprintf("%s: expected relation %s got %s", tc.desc, tc.relation, m.Relation))
+			assert.Equal(t, id, m.CreatedBy, fmt.Sprintf("%s: expected creator %s got %s", tc.desc, id, m.CreatedBy))
+		}
+		assert.ElementsMatch(t, tc.members, members, fmt.Sprintf("%s: expected members %v got %v", tc.desc, tc.members, members))
+	}
+}
+
+func TestListGroupsBySelector(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	type createGroupReq struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels,omitempty"`
+	}
+
+	setup := []struct {
+		desc   string
+		req    createGroupReq
+		status int
+	}{
+		{
+			desc:   "create production group",
+			req:    createGroupReq{Name: "prod", Labels: map[string]string{"env": "prod", "region": "eu"}},
+			status: http.StatusCreated,
+		},
+		{
+			desc:   "create development group",
+			req:    createGroupReq{Name: "dev", Labels: map[string]string{"env": "dev"}},
+			status: http.StatusCreated,
+		},
+		{
+			desc:   "create group with invalid labels",
+			req:    createGroupReq{Name: "invalid", Labels: map[string]string{"env=": "prod"}},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range setup {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/groups", ts.URL),
+			contentType: contentType,
+			token:       secret,
+			body:        strings.NewReader(toJSON(tc.req)),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	type groupRes struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	}
+	type groupPageRes struct {
+		Groups []groupRes `json:"groups"`
+	}
+
+	cases := []struct {
+		desc     string
+		selector string
+		status   int
+		groups   []string
+	}{
+		{
+			desc:     "list groups by equality selector",
+			selector: "env=prod",
+			status:   http.StatusOK,
+			groups:   []string{"prod"},
+		},
+		{
+			desc:     "list groups by inequality selector",
+			selector: "env!=prod",
+			status:   http.StatusOK,
+			groups:   []string{"dev"},
+		},
+		{
+			desc:     "list groups by existence selector",
+			selector: "!region",
+			status:   http.StatusOK,
+			groups:   []string{"dev"},
+		},
+		{
+			desc:     "list groups by multiple requirements",
+			selector: "env=prod,region=eu",
+			status:   http.StatusOK,
+			groups:   []string{"prod"},
+		},
+		{
+			desc:     "list groups by empty selector",
+			selector: "",
+			status:   http.StatusOK,
+			groups:   []string{"prod", "dev"},
+		},
+		{
+			desc:     "list groups by invalid selector",
+			selector: "env>prod",
+			status:   http.StatusBadRequest,
+			groups:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/groups?selector=%s", ts.URL, url.QueryEscape(tc.selector)),
+			token:  secret,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var page groupPageRes
+		json.NewDecoder(res.Body).Decode(&page)
+		var groups []string
+		for _, g := range page.Groups {
+			groups = append(groups, g.Name)
+		}
+		assert.ElementsMatch(t, tc.groups, groups, fmt.Sprintf("%s: expected groups %v got %v", tc.desc, tc.groups, groups))
+	}
+}
+
+func TestListGroupsByMetadata(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	type createGroupReq struct {
+		Name     string                 `json:"name"`
+		Metadata map[string]interface{} `json:"metadata,omitempty"`
+	}
+
+	setup := []createGroupReq{
+		{Name: "eu-acme", Metadata: map[string]interface{}{"region": "eu", "customer": map[string]interface{}{"id": "acme", "tier": "gold"}}},
+		{Name: "eu-other", Metadata: map[string]interface{}{"region": "eu", "customer": map[string]interface{}{"id": "other"}}},
+		{Name: "us-acme", Metadata: map[string]interface{}{"region": "us", "customer": map[string]interface{}{"id": "acme"}}},
+		{Name: "plain"},
+	}
+
+	for _, g := range setup {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/groups", ts.URL),
+			contentType: contentType,
+			token:       secret,
+			body:        strings.NewReader(toJSON(g)),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", g.Name, err))
+		assert.Equal(t, http.StatusCreated, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", g.Name, http.StatusCreated, res.StatusCode))
+	}
+
+	type groupRes struct {
+		Name string `json:"name"`
+	}
+	type groupPageRes struct {
+		Groups []groupRes `json:"groups"`
+	}
+
+	cases := []struct {
+		desc     string
+		metadata string
+		status   int
+		groups   []string
+	}{
+		{
+			desc:     "list groups by top level metadata",
+			metadata: `{"region": "eu"}`,
+			status:   http.StatusOK,
+			groups:   []string{"eu-acme", "eu-other"},
+		},
+		{
+			desc:     "list groups by nested metadata",
+			metadata: `{"customer": {"id": "acme"}}`,
+			status:   http.StatusOK,
+			groups:   []string{"eu-acme", "us-acme"},
+		},
+		{
+			desc:     "list groups by multiple metadata attributes",
+			metadata: `{"region": "eu", "customer": {"id": "acme"}}`,
+			status:   http.StatusOK,
+			groups:   []string{"eu-acme"},
+		},
+		{
+			desc:     "list groups by non-existing metadata",
+			metadata: `{"region": "asia"}`,
+			status:   http.StatusOK,
+			groups:   nil,
+		},
+		{
+			desc:     "list groups by invalid metadata",
+			metadata: `{"region":`,
+			status:   http.StatusBadRequest,
+			groups:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/groups?metadata=%s", ts.URL, url.QueryEscape(tc.metadata)),
+			token:  secret,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var page groupPageRes
+		json.NewDecoder(res.Body).Decode(&page)
+		var groups []string
+		for _, g := range page.Groups {
+			groups = append(groups, g.Name)
+		}
+		assert.ElementsMatch(t, tc.groups, groups, fmt.Sprintf("%s: expected groups %v got %v", tc.desc, tc.groups, groups))
+	}
+}
@@ -1,8 +1,11 @@
 package groups
 
 import (
+	"time"
+
 	"github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 )
 
 type createGroupReq struct {
@@ -11,6 +14,7 @@ type createGroupReq struct {
 	ParentID    string                 `json:"parent_id,omitempty"`
 	Description string                 `json:"description,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Labels      labels.Labels          `json:"labels,omitempty"`
 }
 
 func (req createGroupReq) validate() error {
@@ -20,6 +24,9 @@ func (req createGroupReq) validate() error {
 	if len(req.Name) > maxNameSize || req.Name == "" {
 		return errors.Wrap(auth.ErrMalformedEntity, auth.ErrBadGroupName)
 	}
+	if err := req.Labels.Validate(); err != nil {
+		return errors.Wrap(auth.ErrMalformedEntity, err)
+	}
 
 	return nil
 }
@@ -30,6 +37,7 @@ type updateGroupReq struct {
 	Name        string                 `json:"name,omitempty"`
 	Description string                 `json:"description,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Labels      labels.Labels          `json:"labels,omitempty"`
 }
 
 func (req updateGroupReq) validate() error {
@@ -41,6 +49,10 @@ func (req updateGroupReq) validate() error {
 		return auth.ErrMalformedEntity
 	}
 
+	if err := req.Labels.Validate(); err != nil {
+		return errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
 	return nil
 }
 
@@ -52,6 +64,7 @@ type listGroupsReq struct {
 	// - `false` - result is JSON array of groups.
 	tree     bool
 	metadata auth.GroupMetadata
+	selector labels.Selector
 }
 
 func (req listGroupsReq) validate() error {
@@ -70,6 +83,7 @@ type listMembersReq struct {
 	token     string
 	id        string
 	groupType string
+	relation  string
 	offset    uint64
 	limit     uint64
 	tree      bool
@@ -85,6 +99,10 @@ func (req listMembersReq) validate() error {
 		return auth.ErrMalformedEntity
 	}
 
+	if req.relation != "" && !auth.ValidRelation(req.relation) {
+		return auth.ErrMalformedEntity
+	}
+
 	return nil
 }
 
@@ -94,6 +112,7 @@ type listMembershipsReq struct {
 	offset   uint64
 	limit    uint64
 	metadata auth.GroupMetadata
+	selector labels.Selector
 }
 
 func (req listMembershipsReq) validate() error {
@@ -109,10 +128,12 @@ func (req listMembershipsReq) validate() error {
 }
 
 type assignReq struct {
-	token   string
-	groupID string
-	Type    string   `json:"type,omitempty"`
-	Members []string `json:"members"`
+	token     string
+	groupID   string
+	Type      string     `json:"type,omitempty"`
+	Members   []string   `json:"members"`
+	Relation  string     `json:"relation,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
 func (req assignReq) validate() error {
@@ -124,6 +145,10 @@ func (req assignReq) validate() error {
 		return auth.ErrMalformedEntity
 	}
 
+	if req.Relation != "" && !auth.ValidRelation(req.Relation) {
+		return auth.ErrMalformedEntity
+	}
+
 	return nil
 }
 
@@ -161,6 +186,47 @@ func (req unassignReq) validate() error {
 	return nil
 }
 
+type groupAdminReq struct {
+	token     string
+	groupID   string
+	UserID    string   `json:"user_id"`
+	Relations []string `json:"relations"`
+}
+
+func (req groupAdminReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.groupID == "" || req.UserID == "" || len(req.Relations) == 0 {
+		return auth.ErrMalformedEntity
+	}
+
+	for _, rel := range req.Relations {
+		if !auth.ValidAdminRelation(rel) {
+			return auth.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type setRelationReq struct {
+	assignReq
+}
+
+func (req setRelationReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.groupID == "" || len(req.Members) == 0 || !auth.ValidRelation(req.Relation) {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type groupReq struct {
 	token string
 	id    string
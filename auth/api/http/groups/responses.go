@@ -14,11 +14,20 @@ var (
 	_ mainflux.Response = (*deleteRes)(nil)
 	_ mainflux.Response = (*assignRes)(nil)
 	_ mainflux.Response = (*unassignRes)(nil)
+	_ mainflux.Response = (*groupAdminsRes)(nil)
 )
 
+type memberRes struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Relation  string    `json:"relation,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type memberPageRes struct {
 	pageRes
-	Members []interface{}
+	Members []memberRes `json:"members"`
 }
 
 func (res memberPageRes) Code() int {
@@ -52,9 +61,11 @@ type viewGroupRes struct {
 	ID          string                 `json:"id"`
 	Name        string                 `json:"name"`
 	OwnerID     string                 `json:"owner_id"`
+	OrgID       string                 `json:"org_id,omitempty"`
 	ParentID    string                 `json:"parent_id,omitempty"`
 	Description string                 `json:"description,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Labels      map[string]string      `json:"labels,omitempty"`
 	// Indicates a level in tree hierarchy from first group node - root.
 	Level int `json:"level"`
 	// Path in a tree consisting of group ids
@@ -172,6 +183,27 @@ func (res unassignRes) Empty() bool {
 	return true
 }
 
+type groupAdminRes struct {
+	UserID   string `json:"user_id"`
+	Relation string `json:"relation"`
+}
+
+type groupAdminsRes struct {
+	Admins []groupAdminRes `json:"admins"`
+}
+
+func (res groupAdminsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res groupAdminsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res groupAdminsRes) Empty() bool {
+	return false
+}
+
 type errorRes struct {
 	Err string `json:"error"`
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/opentracing/opentracing-go"
 )
@@ -29,8 +30,10 @@ const (
 	limitKey    = "limit"
 	levelKey    = "level"
 	metadataKey = "metadata"
+	selectorKey = "selector"
 	treeKey     = "tree"
 	groupType   = "type"
+	relationKey = "relation"
 	defOffset   = 0
 	defLimit    = 10
 	defLevel    = 1
@@ -40,6 +43,8 @@ const (
 func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
 	}
 	mux.Post("/groups", kithttp.NewServer(
 		kitot.TraceServer(tracer, "create_group")(createGroupEndpoint(svc)),
@@ -111,6 +116,13 @@ func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bo
 		opts...,
 	))
 
+	mux.Put("/groups/:groupID/members", kithttp.NewServer(
+		kitot.TraceServer(tracer, "set_relation")(setRelationEndpoint(svc)),
+		decodeSetRelationRequest,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Get("/groups/:groupID/members", kithttp.NewServer(
 		kitot.TraceServer(tracer, "list_members")(listMembersEndpoint(svc)),
 		decodeListMembersRequest,
@@ -118,6 +130,27 @@ func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bo
 		opts...,
 	))
 
+	mux.Post("/groups/:groupID/admins", kithttp.NewServer(
+		kitot.TraceServer(tracer, "delegate_group_admin")(delegateGroupAdminEndpoint(svc)),
+		decodeGroupAdminRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/groups/:groupID/admins", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_group_admin")(revokeGroupAdminEndpoint(svc)),
+		decodeGroupAdminRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/groups/:groupID/admins", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_group_admins")(listGroupAdminsEndpoint(svc)),
+		decodeGroupRequest,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Get("/members/:memberID/groups", kithttp.NewServer(
 		kitot.TraceServer(tracer, "list_memberships")(listMemberships(svc)),
 		decodeListMembershipsRequest,
@@ -154,6 +187,11 @@ func decodeListGroupsRequest(_ context.Context, r *http.Request) (interface{}, e
 		return nil, err
 	}
 
+	s, err := httputil.ReadSelectorQuery(r, selectorKey)
+	if err != nil {
+		return nil, err
+	}
+
 	t, err := httputil.ReadBoolQuery(r, treeKey, false)
 	if err != nil {
 		return nil, err
@@ -163,6 +201,7 @@ func decodeListGroupsRequest(_ context.Context, r *http.Request) (interface{}, e
 		token:    r.Header.Get("Authorization"),
 		level:    l,
 		metadata: m,
+		selector: s,
 		tree:     t,
 		id:       bone.GetValue(r, "groupID"),
 	}
@@ -195,10 +234,16 @@ func decodeListMembersRequest(_ context.Context, r *http.Request) (interface{},
 		return nil, err
 	}
 
+	rel, err := httputil.ReadStringQuery(r, relationKey, "")
+	if err != nil {
+		return nil, err
+	}
+
 	req := listMembersReq{
 		token:     r.Header.Get("Authorization"),
 		id:        bone.GetValue(r, "groupID"),
 		groupType: t,
+		relation:  rel,
 		offset:    o,
 		limit:     l,
 		metadata:  m,
@@ -223,12 +268,18 @@ func decodeListMembershipsRequest(_ context.Context, r *http.Request) (interface
 		return nil, err
 	}
 
+	s, err := httputil.ReadSelectorQuery(r, selectorKey)
+	if err != nil {
+		return nil, err
+	}
+
 	req := listMembershipsReq{
 		token:    r.Header.Get("Authorization"),
 		id:       bone.GetValue(r, "memberID"),
 		offset:   o,
 		limit:    l,
 		metadata: m,
+		selector: s,
 	}
 
 	return req, nil
@@ -285,6 +336,38 @@ func decodeAssignRequest(_ context.Context, r *http.Request) (interface{}, error
 	return req, nil
 }
 
+func decodeSetRelationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := setRelationReq{
+		assignReq{
+			token:   r.Header.Get("Authorization"),
+			groupID: bone.GetValue(r, "groupID"),
+		},
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeGroupAdminRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, auth.ErrUnsupportedContentType
+	}
+
+	req := groupAdminReq{
+		token:   r.Header.Get("Authorization"),
+		groupID: bone.GetValue(r, "groupID"),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeUnassignRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	req := unassignReq{
 		assignReq{
@@ -318,17 +401,20 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	switch {
-	case errors.Contains(err, auth.ErrMalformedEntity):
+	case errors.Contains(err, auth.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, auth.ErrUnauthorizedAccess):
 		w.WriteHeader(http.StatusForbidden)
-	case errors.Contains(err, auth.ErrNotFound):
+	case errors.Contains(err, auth.ErrNotFound),
+		errors.Contains(err, auth.ErrGroupNotFound):
 		w.WriteHeader(http.StatusNotFound)
 	case errors.Contains(err, auth.ErrConflict):
 		w.WriteHeader(http.StatusConflict)
-	case errors.Contains(err, auth.ErrAuthorization):
+	case errors.Contains(err, auth.ErrAuthorization),
+		errors.Contains(err, auth.ErrOrgMember):
 		w.WriteHeader(http.StatusForbidden)
 	case errors.Contains(err, auth.ErrMemberAlreadyAssigned):
 		w.WriteHeader(http.StatusConflict)
@@ -343,7 +429,7 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	}
 	errorVal, ok := err.(errors.Error)
 	if ok {
-		if err := json.NewEncoder(w).Encode(errorRes{Err: errorVal.Msg()}); err != nil {
+		if err := json.NewEncoder(w).Encode(errorRes{Err: i18n.Localize(ctx, errorVal.Msg())}); err != nil {
 			w.Header().Set("Content-Type", contentType)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
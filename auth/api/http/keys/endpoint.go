@@ -5,6 +5,10 @@ package keys
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
 	"time"
 
 	"github.com/go-kit/kit/endpoint"
@@ -20,8 +24,12 @@ func issueEndpoint(svc auth.Service) endpoint.Endpoint {
 
 		now := time.Now().UTC()
 		newKey := auth.Key{
-			IssuedAt: now,
-			Type:     req.Type,
+			IssuedAt:   now,
+			Type:       req.Type,
+			Scopes:     req.Scopes,
+			AllowedIPs: req.AllowedIPs,
+			Label:      req.Label,
+			Claims:     req.Claims,
 		}
 
 		duration := time.Duration(req.Duration * time.Second)
@@ -36,9 +44,13 @@ func issueEndpoint(svc auth.Service) endpoint.Endpoint {
 		}
 
 		res := issueKeyRes{
-			ID:       key.ID,
-			Value:    secret,
-			IssuedAt: key.IssuedAt,
+			ID:         key.ID,
+			Value:      secret,
+			IssuedAt:   key.IssuedAt,
+			Scopes:     key.Scopes,
+			AllowedIPs: key.AllowedIPs,
+			Label:      key.Label,
+			Claims:     key.Claims,
 		}
 		if !key.ExpiresAt.IsZero() {
 			res.ExpiresAt = &key.ExpiresAt
@@ -60,18 +72,51 @@ func retrieveEndpoint(svc auth.Service) endpoint.Endpoint {
 		if err != nil {
 			return nil, err
 		}
-		ret := retrieveKeyRes{
-			ID:       key.ID,
-			IssuerID: key.IssuerID,
-			Subject:  key.Subject,
-			Type:     key.Type,
-			IssuedAt: key.IssuedAt,
+
+		return toRetrieveKeyRes(key), nil
+	}
+}
+
+func listKeysEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listKeysReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
 		}
-		if !key.ExpiresAt.IsZero() {
-			ret.ExpiresAt = &key.ExpiresAt
+
+		page, err := svc.ListKeys(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := keysPageRes{
+			Total:  page.Total,
+			Offset: page.Offset,
+			Limit:  page.Limit,
+			Keys:   []retrieveKeyRes{},
+		}
+		for _, key := range page.Keys {
+			res.Keys = append(res.Keys, toRetrieveKeyRes(key))
+		}
+
+		return res, nil
+	}
+}
+
+func labelKeyEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(labelKeyReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.LabelKey(ctx, req.token, req.id, req.Label); err != nil {
+			return nil, err
 		}
 
-		return ret, nil
+		return labelKeyRes{}, nil
 	}
 }
 
@@ -90,3 +135,157 @@ func revokeEndpoint(svc auth.Service) endpoint.Endpoint {
 		return revokeKeyRes{}, nil
 	}
 }
+
+func revokeTokenEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(tokenReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeToken(ctx, req.token, req.Token); err != nil {
+			return nil, err
+		}
+
+		return revokeKeyRes{}, nil
+	}
+}
+
+func revokeKeysEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(revokeKeysReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		count, err := svc.RevokeKeys(ctx, req.token, req.subject)
+		if err != nil {
+			return nil, err
+		}
+
+		return revokeKeysRes{Revoked: count}, nil
+	}
+}
+
+func introspectEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(tokenReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		in, err := svc.Introspect(ctx, req.token, req.Token)
+		if err != nil {
+			return nil, err
+		}
+		if !in.Active {
+			return introspectRes{}, nil
+		}
+
+		key := in.Key
+		res := introspectRes{
+			Active:     true,
+			ID:         key.ID,
+			IssuerID:   key.IssuerID,
+			Subject:    key.Subject,
+			OrgID:      key.OrgID,
+			Type:       &key.Type,
+			IssuedAt:   &key.IssuedAt,
+			Scopes:     key.Scopes,
+			AllowedIPs: key.AllowedIPs,
+			Claims:     key.Claims,
+		}
+		if !key.ExpiresAt.IsZero() {
+			res.ExpiresAt = &key.ExpiresAt
+		}
+
+		return res, nil
+	}
+}
+
+func refreshEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(refreshReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		token, refresh, err := svc.Refresh(ctx, req.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+
+		return refreshRes{Token: token, RefreshToken: refresh}, nil
+	}
+}
+
+func jwksEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		keys, err := svc.PublicKeys(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		res := jwksRes{Keys: []jwk{}}
+		for _, key := range keys {
+			if k, ok := toJWK(key); ok {
+				res.Keys = append(res.Keys, k)
+			}
+		}
+
+		return res, nil
+	}
+}
+
+func toRetrieveKeyRes(key auth.Key) retrieveKeyRes {
+	ret := retrieveKeyRes{
+		ID:         key.ID,
+		IssuerID:   key.IssuerID,
+		Subject:    key.Subject,
+		OrgID:      key.OrgID,
+		Type:       key.Type,
+		IssuedAt:   key.IssuedAt,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		Label:      key.Label,
+		Claims:     key.Claims,
+	}
+	if !key.ExpiresAt.IsZero() {
+		ret.ExpiresAt = &key.ExpiresAt
+	}
+
+	return ret
+}
+
+// toJWK converts the public key to the JSON Web Key, as specified by
+// RFC 7517 and RFC 7518.
+func toJWK(key auth.PublicKey) (jwk, bool) {
+	k := jwk{
+		Kid: key.ID,
+		Alg: key.Algorithm,
+		Use: "sig",
+	}
+	switch pub := key.Key.(type) {
+	case *rsa.PublicKey:
+		k.Kty = "RSA"
+		k.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		k.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		// The coordinates are padded to the size of the curve.
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		x, y := make([]byte, size), make([]byte, size)
+		pub.X.FillBytes(x)
+		pub.Y.FillBytes(y)
+		k.Kty = "EC"
+		k.Crv = pub.Curve.Params().Name
+		k.X = base64.RawURLEncoding.EncodeToString(x)
+		k.Y = base64.RawURLEncoding.EncodeToString(y)
+	default:
+		return jwk{}, false
+	}
+
+	return k, true
+}
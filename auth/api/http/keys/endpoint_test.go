@@ -5,7 +5,14 @@ package keys_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net/http"
@@ -61,16 +68,19 @@ func (tr testRequest) make() (*http.Response, error) {
 }
 
 func newService() auth.Service {
+	return newServiceWithTokenizer(jwt.New(secret))
+}
+
+func newServiceWithTokenizer(t auth.Tokenizer) auth.Service {
 	repo := mocks.NewKeyRepository()
 	groupRepo := mocks.NewGroupRepository()
 	idProvider := uuid.NewMock()
-	t := jwt.New(secret)
 
 	mockAuthzDB := map[string][]mocks.MockSubjectSet{}
 	mockAuthzDB[id] = append(mockAuthzDB[id], mocks.MockSubjectSet{Object: "authorities", Relation: "member"})
 	ketoMock := mocks.NewKetoMock(mockAuthzDB)
 
-	return auth.New(repo, groupRepo, idProvider, t, ketoMock)
+	return auth.New(repo, groupRepo, mocks.NewOrgRepository(), mocks.NewShareRepository(), mocks.NewRoleRepository(), mocks.NewServiceAccountRepository(), mocks.NewQuotaRepository(), mocks.NewPolicyExpirationRepository(), mocks.NewRevocationRepository(), mocks.NewSessionRepository(), mocks.NewAuditRepository(), idProvider, t, ketoMock, nil)
 }
 
 func newServer(svc auth.Service) *httptest.Server {
@@ -242,6 +252,190 @@ func TestRetrieve(t *testing.T) {
 	}
 }
 
+type keysPageResponse struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+	Keys   []struct {
+		ID    string `json:"id"`
+		Label string `json:"label"`
+	} `json:"keys"`
+}
+
+func TestListKeys(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	n := 3
+	issued := time.Now()
+	for i := 0; i < n; i++ {
+		key := auth.Key{Type: auth.APIKey, IssuedAt: issued.Add(time.Duration(i) * time.Second), IssuerID: id, Subject: email, Label: fmt.Sprintf("key-%d", i)}
+		_, _, err := svc.Issue(context.Background(), loginSecret, key)
+		assert.Nil(t, err, fmt.Sprintf("Issuing API key expected to succeed: %s", err))
+	}
+
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	cases := []struct {
+		desc   string
+		query  string
+		token  string
+		status int
+		labels []string
+	}{
+		{
+			desc:   "list keys",
+			query:  "",
+			token:  loginSecret,
+			status: http.StatusOK,
+			labels: []string{"key-0", "key-1", "key-2"},
+		},
+		{
+			desc:   "list keys with offset and limit",
+			query:  "?offset=1&limit=1",
+			token:  loginSecret,
+			status: http.StatusOK,
+			labels: []string{"key-1"},
+		},
+		{
+			desc:   "list keys with too large limit",
+			query:  "?limit=1000",
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list keys with invalid offset",
+			query:  "?offset=invalid",
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list keys unauthorized",
+			query:  "",
+			token:  "wrong",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/keys%s", ts.URL, tc.query),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var page keysPageResponse
+		err = json.NewDecoder(res.Body).Decode(&page)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var labels []string
+		for _, k := range page.Keys {
+			labels = append(labels, k.Label)
+		}
+		assert.Equal(t, uint64(n), page.Total, fmt.Sprintf("%s: expected total %d got %d", tc.desc, n, page.Total))
+		assert.Equal(t, tc.labels, labels, fmt.Sprintf("%s: expected labels %v got %v", tc.desc, tc.labels, labels))
+	}
+}
+
+func TestLabelKey(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+	key := auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), IssuerID: id, Subject: email}
+
+	k, _, err := svc.Issue(context.Background(), loginSecret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing API key expected to succeed: %s", err))
+
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	cases := []struct {
+		desc   string
+		id     string
+		req    string
+		ct     string
+		token  string
+		status int
+	}{
+		{
+			desc:   "label an existing key",
+			id:     k.ID,
+			req:    `{"label": "ci"}`,
+			ct:     contentType,
+			token:  loginSecret,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "label a key with too long label",
+			id:     k.ID,
+			req:    toJSON(map[string]string{"label": strings.Repeat("a", 255)}),
+			ct:     contentType,
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "label a non-existing key",
+			id:     "non-existing",
+			req:    `{"label": "ci"}`,
+			ct:     contentType,
+			token:  loginSecret,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "label a key with invalid request format",
+			id:     k.ID,
+			req:    "{",
+			ct:     contentType,
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "label a key without content type",
+			id:     k.ID,
+			req:    `{"label": "ci"}`,
+			ct:     "",
+			token:  loginSecret,
+			status: http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:   "label a key unauthorized",
+			id:     k.ID,
+			req:    `{"label": "ci"}`,
+			ct:     contentType,
+			token:  "wrong",
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/keys/%s", ts.URL, tc.id),
+			contentType: tc.ct,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	saved, err := svc.RetrieveKey(context.Background(), loginSecret, k.ID)
+	assert.Nil(t, err, fmt.Sprintf("Retrieving API key expected to succeed: %s", err))
+	assert.Equal(t, "ci", saved.Label, fmt.Sprintf("expected label %s got %s", "ci", saved.Label))
+}
+
 func TestRevoke(t *testing.T) {
 	svc := newService()
 	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
@@ -292,3 +486,361 @@ func TestRevoke(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 	}
 }
+
+type revokeKeysResponse struct {
+	Revoked uint64 `json:"revoked"`
+}
+
+func TestRevokeKeys(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	otherID := "other"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user key expected to succeed: %s", err))
+
+	for i := 0; i < 2; i++ {
+		_, _, err := svc.Issue(context.Background(), otherSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+		assert.Nil(t, err, fmt.Sprintf("Issuing other user API key expected to succeed: %s", err))
+	}
+
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	cases := []struct {
+		desc    string
+		subject string
+		token   string
+		status  int
+		revoked uint64
+	}{
+		{
+			desc:    "revoke other user's keys as non-admin",
+			subject: id,
+			token:   otherSecret,
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "revoke keys without subject",
+			subject: "",
+			token:   loginSecret,
+			status:  http.StatusBadRequest,
+		},
+		{
+			desc:    "revoke keys unauthorized",
+			subject: otherID,
+			token:   "wrong",
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:    "revoke other user's keys as admin",
+			subject: otherID,
+			token:   loginSecret,
+			status:  http.StatusOK,
+			revoked: 2,
+		},
+		{
+			desc:    "revoke already revoked keys",
+			subject: otherID,
+			token:   loginSecret,
+			status:  http.StatusOK,
+			revoked: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/keys?subject=%s", ts.URL, tc.subject),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+		var body revokeKeysResponse
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.revoked, body.Revoked, fmt.Sprintf("%s: expected %d revoked keys got %d", tc.desc, tc.revoked, body.Revoked))
+	}
+}
+
+type tokenRequest struct {
+	Token string `json:"token"`
+}
+
+type introspectResponse struct {
+	Active   bool   `json:"active"`
+	ID       string `json:"id"`
+	IssuerID string `json:"issuer_id"`
+}
+
+func TestIntrospect(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	k, apiToken, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing API key expected to succeed: %s", err))
+
+	_, revokedToken, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing API key expected to succeed: %s", err))
+
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	revoke := testRequest{
+		client:      client,
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/keys/revoke", ts.URL),
+		contentType: contentType,
+		token:       loginSecret,
+		body:        strings.NewReader(toJSON(tokenRequest{Token: revokedToken})),
+	}
+	res, err := revoke.make()
+	assert.Nil(t, err, fmt.Sprintf("revoking token: unexpected error %s", err))
+	assert.Equal(t, http.StatusNoContent, res.StatusCode, fmt.Sprintf("revoking token: expected status code %d got %d", http.StatusNoContent, res.StatusCode))
+
+	cases := []struct {
+		desc   string
+		req    string
+		ct     string
+		token  string
+		status int
+		res    introspectResponse
+	}{
+		{
+			desc:   "introspect active token",
+			req:    toJSON(tokenRequest{Token: apiToken}),
+			ct:     contentType,
+			token:  loginSecret,
+			status: http.StatusOK,
+			res:    introspectResponse{Active: true, ID: k.ID, IssuerID: id},
+		},
+		{
+			desc:   "introspect revoked token",
+			req:    toJSON(tokenRequest{Token: revokedToken}),
+			ct:     contentType,
+			token:  loginSecret,
+			status: http.StatusOK,
+			res:    introspectResponse{Active: false},
+		},
+		{
+			desc:   "introspect token with invalid token",
+			req:    toJSON(tokenRequest{Token: apiToken}),
+			ct:     contentType,
+			token:  "wrong",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "introspect empty token",
+			req:    toJSON(tokenRequest{}),
+			ct:     contentType,
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "introspect token with invalid content type",
+			req:    toJSON(tokenRequest{Token: apiToken}),
+			ct:     "",
+			token:  loginSecret,
+			status: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/keys/introspect", ts.URL),
+			contentType: tc.ct,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+		var body introspectResponse
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error decoding response %s", tc.desc, err))
+		assert.Equal(t, tc.res, body, fmt.Sprintf("%s: expected response %v got %v", tc.desc, tc.res, body))
+	}
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type refreshResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func TestRefresh(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	_, refresh, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.RefreshKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing refresh key expected to succeed: %s", err))
+
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	cases := []struct {
+		desc   string
+		req    string
+		ct     string
+		status int
+	}{
+		{
+			desc:   "refresh with refresh token",
+			req:    toJSON(refreshRequest{RefreshToken: refresh}),
+			ct:     contentType,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "refresh with used refresh token",
+			req:    toJSON(refreshRequest{RefreshToken: refresh}),
+			ct:     contentType,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "refresh with login token",
+			req:    toJSON(refreshRequest{RefreshToken: loginSecret}),
+			ct:     contentType,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "refresh with empty refresh token",
+			req:    toJSON(refreshRequest{}),
+			ct:     contentType,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "refresh with malformed request",
+			req:    "{",
+			ct:     contentType,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "refresh with invalid content type",
+			req:    toJSON(refreshRequest{RefreshToken: refresh}),
+			ct:     "",
+			status: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/tokens/refresh", ts.URL),
+			contentType: tc.ct,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+		var body refreshResponse
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		_, err = svc.Identify(context.Background(), body.Token)
+		assert.Nil(t, err, fmt.Sprintf("%s: identifying refreshed token expected to succeed: %s", tc.desc, err))
+		assert.NotEmpty(t, body.RefreshToken, fmt.Sprintf("%s: expected refresh token", tc.desc))
+	}
+}
+
+type jwksResponse struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Alg string `json:"alg"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	} `json:"keys"`
+}
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err, fmt.Sprintf("generating RSA key expected to succeed: %s", err))
+	rsaPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(rsaKey)})
+	signing, err := jwt.ParseKey("rsa", jwt.RS256, rsaPEM)
+	assert.Nil(t, err, fmt.Sprintf("parsing RSA key expected to succeed: %s", err))
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err, fmt.Sprintf("generating ECDSA key expected to succeed: %s", err))
+	verification := jwt.Key{ID: "ec", Algorithm: jwt.ES256, Verify: &ecKey.PublicKey}
+	hmac, err := jwt.ParseKey("", jwt.HS256, []byte(secret))
+	assert.Nil(t, err, fmt.Sprintf("parsing secret expected to succeed: %s", err))
+
+	tokenizer, err := jwt.NewWithKeys(signing, verification, hmac)
+	assert.Nil(t, err, fmt.Sprintf("creating tokenizer expected to succeed: %s", err))
+
+	cases := []struct {
+		desc string
+		svc  auth.Service
+		kids []string
+	}{
+		{
+			desc: "retrieve public keys",
+			svc:  newServiceWithTokenizer(tokenizer),
+			kids: []string{"ec", "rsa"},
+		},
+		{
+			desc: "retrieve public keys using HMAC secret",
+			svc:  newService(),
+			kids: []string{},
+		},
+	}
+
+	for _, tc := range cases {
+		ts := newServer(tc.svc)
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/.well-known/jwks.json", ts.URL),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, http.StatusOK, res.StatusCode))
+
+		var body jwksResponse
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		kids := []string{}
+		for _, k := range body.Keys {
+			kids = append(kids, k.Kid)
+			switch k.Kty {
+			case "RSA":
+				n, err := base64.RawURLEncoding.DecodeString(k.N)
+				assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+				assert.Equal(t, rsaKey.N.Bytes(), n, fmt.Sprintf("%s: expected RSA modulus to match", tc.desc))
+				assert.Equal(t, jwt.RS256, k.Alg, fmt.Sprintf("%s: expected algorithm %s got %s", tc.desc, jwt.RS256, k.Alg))
+			case "EC":
+				x, err := base64.RawURLEncoding.DecodeString(k.X)
+				assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+				assert.Equal(t, ecKey.X.FillBytes(make([]byte, 32)), x, fmt.Sprintf("%s: expected EC coordinate to match", tc.desc))
+				assert.Equal(t, "P-256", k.Crv, fmt.Sprintf("%s: expected curve P-256 got %s", tc.desc, k.Crv))
+			}
+		}
+		assert.Equal(t, tc.kids, kids, fmt.Sprintf("%s: expected keys %v got %v", tc.desc, tc.kids, kids))
+		ts.Close()
+	}
+}
@@ -9,10 +9,16 @@ import (
 	"github.com/mainflux/mainflux/auth"
 )
 
+const maxLimitSize = 100
+
 type issueKeyReq struct {
-	token    string
-	Type     uint32        `json:"type,omitempty"`
-	Duration time.Duration `json:"duration,omitempty"`
+	token      string
+	Type       uint32            `json:"type,omitempty"`
+	Duration   time.Duration     `json:"duration,omitempty"`
+	Scopes     []string          `json:"scopes,omitempty"`
+	AllowedIPs []string          `json:"allowed_ips,omitempty"`
+	Label      string            `json:"label,omitempty"`
+	Claims     map[string]string `json:"claims,omitempty"`
 }
 
 // It is not possible to issue Reset key using HTTP API.
@@ -37,3 +43,76 @@ func (req keyReq) validate() error {
 	}
 	return nil
 }
+
+type listKeysReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listKeysReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
+
+type labelKeyReq struct {
+	token string
+	id    string
+	Label string `json:"label"`
+}
+
+func (req labelKeyReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+	if req.id == "" {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
+
+type revokeKeysReq struct {
+	token   string
+	subject string
+}
+
+func (req revokeKeysReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+	if req.subject == "" {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
+
+type refreshReq struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (req refreshReq) validate() error {
+	if req.RefreshToken == "" {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
+
+type tokenReq struct {
+	token string
+	Token string `json:"token"`
+}
+
+func (req tokenReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+	if req.Token == "" {
+		return auth.ErrMalformedEntity
+	}
+	return nil
+}
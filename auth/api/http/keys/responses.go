@@ -13,13 +13,21 @@ import (
 var (
 	_ mainflux.Response = (*issueKeyRes)(nil)
 	_ mainflux.Response = (*revokeKeyRes)(nil)
+	_ mainflux.Response = (*keysPageRes)(nil)
+	_ mainflux.Response = (*labelKeyRes)(nil)
+	_ mainflux.Response = (*introspectRes)(nil)
+	_ mainflux.Response = (*refreshRes)(nil)
 )
 
 type issueKeyRes struct {
-	ID        string     `json:"id,omitempty"`
-	Value     string     `json:"value,omitempty"`
-	IssuedAt  time.Time  `json:"issued_at,omitempty"`
-	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	Value      string            `json:"value,omitempty"`
+	IssuedAt   time.Time         `json:"issued_at,omitempty"`
+	ExpiresAt  *time.Time        `json:"expires_at,omitempty"`
+	Scopes     []string          `json:"scopes,omitempty"`
+	AllowedIPs []string          `json:"allowed_ips,omitempty"`
+	Label      string            `json:"label,omitempty"`
+	Claims     map[string]string `json:"claims,omitempty"`
 }
 
 func (res issueKeyRes) Code() int {
@@ -35,12 +43,17 @@ func (res issueKeyRes) Empty() bool {
 }
 
 type retrieveKeyRes struct {
-	ID        string     `json:"id,omitempty"`
-	IssuerID  string     `json:"issuer_id,omitempty"`
-	Subject   string     `json:"subject,omitempty"`
-	Type      uint32     `json:"type,omitempty"`
-	IssuedAt  time.Time  `json:"issued_at,omitempty"`
-	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	ID         string            `json:"id,omitempty"`
+	IssuerID   string            `json:"issuer_id,omitempty"`
+	Subject    string            `json:"subject,omitempty"`
+	OrgID      string            `json:"org_id,omitempty"`
+	Type       uint32            `json:"type,omitempty"`
+	IssuedAt   time.Time         `json:"issued_at,omitempty"`
+	ExpiresAt  *time.Time        `json:"expires_at,omitempty"`
+	Scopes     []string          `json:"scopes,omitempty"`
+	AllowedIPs []string          `json:"allowed_ips,omitempty"`
+	Label      string            `json:"label,omitempty"`
+	Claims     map[string]string `json:"claims,omitempty"`
 }
 
 func (res retrieveKeyRes) Code() int {
@@ -55,6 +68,25 @@ func (res retrieveKeyRes) Empty() bool {
 	return false
 }
 
+type keysPageRes struct {
+	Total  uint64           `json:"total"`
+	Offset uint64           `json:"offset"`
+	Limit  uint64           `json:"limit"`
+	Keys   []retrieveKeyRes `json:"keys"`
+}
+
+func (res keysPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res keysPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res keysPageRes) Empty() bool {
+	return false
+}
+
 type revokeKeyRes struct {
 }
 
@@ -70,6 +102,107 @@ func (res revokeKeyRes) Empty() bool {
 	return true
 }
 
+type labelKeyRes struct{}
+
+func (res labelKeyRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res labelKeyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res labelKeyRes) Empty() bool {
+	return true
+}
+
+type revokeKeysRes struct {
+	Revoked uint64 `json:"revoked"`
+}
+
+func (res revokeKeysRes) Code() int {
+	return http.StatusOK
+}
+
+func (res revokeKeysRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res revokeKeysRes) Empty() bool {
+	return false
+}
+
+type introspectRes struct {
+	Active     bool              `json:"active"`
+	ID         string            `json:"id,omitempty"`
+	IssuerID   string            `json:"issuer_id,omitempty"`
+	Subject    string            `json:"subject,omitempty"`
+	OrgID      string            `json:"org_id,omitempty"`
+	Type       *uint32           `json:"type,omitempty"`
+	IssuedAt   *time.Time        `json:"issued_at,omitempty"`
+	ExpiresAt  *time.Time        `json:"expires_at,omitempty"`
+	Scopes     []string          `json:"scopes,omitempty"`
+	AllowedIPs []string          `json:"allowed_ips,omitempty"`
+	Claims     map[string]string `json:"claims,omitempty"`
+}
+
+func (res introspectRes) Code() int {
+	return http.StatusOK
+}
+
+func (res introspectRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res introspectRes) Empty() bool {
+	return false
+}
+
 type errorRes struct {
 	Err string `json:"error"`
 }
+
+type refreshRes struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (res refreshRes) Code() int {
+	return http.StatusOK
+}
+
+func (res refreshRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res refreshRes) Empty() bool {
+	return false
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type jwksRes struct {
+	Keys []jwk `json:"keys"`
+}
+
+func (res jwksRes) Code() int {
+	return http.StatusOK
+}
+
+func (res jwksRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res jwksRes) Empty() bool {
+	return false
+}
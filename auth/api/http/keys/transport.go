@@ -15,17 +15,28 @@ import (
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/internal/ratelimit"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/opentracing/opentracing-go"
 )
 
-const contentType = "application/json"
+const (
+	contentType = "application/json"
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	defOffset   = 0
+	defLimit    = 10
+)
 
 var errUnsupportedContentType = errors.New("unsupported content type")
 
 func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
 	}
 	mux.Post("/keys", kithttp.NewServer(
 		kitot.TraceServer(tracer, "issue")(issueEndpoint(svc)),
@@ -34,6 +45,55 @@ func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bo
 		opts...,
 	))
 
+	mux.Post("/keys/revoke", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_token")(revokeTokenEndpoint(svc)),
+		decodeTokenReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/keys/introspect", kithttp.NewServer(
+		kitot.TraceServer(tracer, "introspect")(introspectEndpoint(svc)),
+		decodeTokenReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/tokens/refresh", kithttp.NewServer(
+		kitot.TraceServer(tracer, "refresh")(refreshEndpoint(svc)),
+		decodeRefreshReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/keys", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_keys")(revokeKeysEndpoint(svc)),
+		decodeRevokeKeysReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/.well-known/jwks.json", kithttp.NewServer(
+		kitot.TraceServer(tracer, "jwks")(jwksEndpoint(svc)),
+		decodeJWKSReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/keys", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_keys")(listKeysEndpoint(svc)),
+		decodeListKeysReq,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/keys/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "label_key")(labelKeyEndpoint(svc)),
+		decodeLabelKeyReq,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Get("/keys/:id", kithttp.NewServer(
 		kitot.TraceServer(tracer, "retrieve")(retrieveEndpoint(svc)),
 		decodeKeyReq,
@@ -73,6 +133,78 @@ func decodeKeyReq(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeListKeysReq(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listKeysReq{
+		token:  r.Header.Get("Authorization"),
+		offset: o,
+		limit:  l,
+	}
+	return req, nil
+}
+
+func decodeLabelKeyReq(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	req := labelKeyReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeRevokeKeysReq(_ context.Context, r *http.Request) (interface{}, error) {
+	req := revokeKeysReq{
+		token:   r.Header.Get("Authorization"),
+		subject: r.URL.Query().Get("subject"),
+	}
+	return req, nil
+}
+
+func decodeJWKSReq(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func decodeRefreshReq(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	var req refreshReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeTokenReq(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+	req := tokenReq{
+		token: r.Header.Get("Authorization"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -91,11 +223,13 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	switch {
-	case errors.Contains(err, auth.ErrMalformedEntity):
+	case errors.Contains(err, auth.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
 		w.WriteHeader(http.StatusBadRequest)
-	case errors.Contains(err, auth.ErrUnauthorizedAccess):
+	case errors.Contains(err, auth.ErrUnauthorizedAccess),
+		errors.Contains(err, auth.ErrAuthorization):
 		w.WriteHeader(http.StatusForbidden)
 	case errors.Contains(err, auth.ErrNotFound):
 		w.WriteHeader(http.StatusNotFound)
@@ -107,12 +241,14 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, errUnsupportedContentType):
 		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, ratelimit.ErrLimitExceeded):
+		w.WriteHeader(http.StatusTooManyRequests)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
 	errorVal, ok := err.(errors.Error)
 	if ok {
-		if err := json.NewEncoder(w).Encode(errorRes{Err: errorVal.Msg()}); err != nil {
+		if err := json.NewEncoder(w).Encode(errorRes{Err: i18n.Localize(ctx, errorVal.Msg())}); err != nil {
 			w.Header().Set("Content-Type", contentType)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
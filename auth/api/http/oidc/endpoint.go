@@ -0,0 +1,156 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/auth/oidc"
+)
+
+func discoveryEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		d := svc.Discovery(ctx)
+		return discoveryRes{
+			Issuer:                            d.Issuer,
+			AuthorizationEndpoint:             d.AuthorizationEndpoint,
+			TokenEndpoint:                     d.TokenEndpoint,
+			UserInfoEndpoint:                  d.UserInfoEndpoint,
+			JWKSURI:                           d.JWKSURI,
+			ScopesSupported:                   d.ScopesSupported,
+			ResponseTypesSupported:            d.ResponseTypesSupported,
+			GrantTypesSupported:               d.GrantTypesSupported,
+			SubjectTypesSupported:             d.SubjectTypesSupported,
+			SigningAlgValuesSupported:         d.SigningAlgValuesSupported,
+			TokenEndpointAuthMethodsSupported: d.TokenEndpointAuthMethodsSupported,
+			ClaimsSupported:                   d.ClaimsSupported,
+			CodeChallengeMethodsSupported:     d.CodeChallengeMethodsSupported,
+		}, nil
+	}
+}
+
+func loginEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(loginReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		location, err := svc.Login(ctx, req.req)
+		if err != nil {
+			return nil, err
+		}
+
+		return loginRes{location: location}, nil
+	}
+}
+
+func authorizeEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(authorizeReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		uri, err := svc.Authorize(ctx, req.token, req.authRequest())
+		if err != nil {
+			return nil, err
+		}
+
+		return authorizeRes{RedirectURI: uri}, nil
+	}
+}
+
+func tokenEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(tokenReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		tokens, err := svc.Exchange(ctx, req.req)
+		if err != nil {
+			return nil, err
+		}
+
+		return tokenRes{
+			AccessToken:     tokens.AccessToken,
+			IssuedTokenType: tokens.IssuedTokenType,
+			TokenType:       "Bearer",
+			ExpiresIn:       int64(time.Until(tokens.ExpiresAt).Seconds()),
+			IDToken:         tokens.IDToken,
+			Scope:           tokens.Scope,
+		}, nil
+	}
+}
+
+func userInfoEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userInfoReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		info, err := svc.UserInfo(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		return userInfoRes{Subject: info.Subject, Email: info.Email}, nil
+	}
+}
+
+func linkIdentityEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(linkIdentityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		l, err := svc.LinkIdentity(ctx, req.token, req.SubjectToken)
+		if err != nil {
+			return nil, err
+		}
+
+		return linkRes{Issuer: l.Issuer, Subject: l.Subject, CreatedAt: l.CreatedAt, created: true}, nil
+	}
+}
+
+func listLinksEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listLinksReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		links, err := svc.ListLinks(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := linksRes{Links: []linkRes{}}
+		for _, l := range links {
+			res.Links = append(res.Links, linkRes{Issuer: l.Issuer, Subject: l.Subject, CreatedAt: l.CreatedAt})
+		}
+
+		return res, nil
+	}
+}
+
+func unlinkIdentityEndpoint(svc oidc.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(unlinkIdentityReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnlinkIdentity(ctx, req.token, req.issuer, req.subject); err != nil {
+			return nil, err
+		}
+
+		return unlinkRes{}, nil
+	}
+}
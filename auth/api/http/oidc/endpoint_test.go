@@ -0,0 +1,508 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux/auth"
+	httpapi "github.com/mainflux/mainflux/auth/api/http/oidc"
+	"github.com/mainflux/mainflux/auth/jwt"
+	"github.com/mainflux/mainflux/auth/mocks"
+	"github.com/mainflux/mainflux/auth/oidc"
+	oidcmocks "github.com/mainflux/mainflux/auth/oidc/mocks"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	contentType  = "application/json"
+	formType     = "application/x-www-form-urlencoded"
+	secret       = "secret"
+	email        = "user@example.com"
+	userID       = "user-id"
+	issuer       = "https://mainflux.example.com"
+	loginURL     = "https://mainflux.example.com/login"
+	clientID     = "grafana"
+	clientSecret = "grafana-secret"
+	redirectURI  = "https://grafana.example.com/login/generic_oauth"
+	extIssuer    = "https://idp.example.com"
+	extToken     = "external-token"
+)
+
+type testRequest struct {
+	client      *http.Client
+	method      string
+	url         string
+	contentType string
+	token       string
+	basic       bool
+	body        io.Reader
+}
+
+func (tr testRequest) make() (*http.Response, error) {
+	req, err := http.NewRequest(tr.method, tr.url, tr.body)
+	if err != nil {
+		return nil, err
+	}
+	if tr.token != "" {
+		req.Header.Set("Authorization", tr.token)
+	}
+	if tr.basic {
+		req.SetBasicAuth(clientID, clientSecret)
+	}
+	if tr.contentType != "" {
+		req.Header.Set("Content-Type", tr.contentType)
+	}
+	return tr.client.Do(req)
+}
+
+func newServer(t *testing.T) (*httptest.Server, string) {
+	revocations := mocks.NewRevocationRepository()
+	authSvc := auth.New(mocks.NewKeyRepository(), mocks.NewGroupRepository(), mocks.NewOrgRepository(), mocks.NewShareRepository(), mocks.NewRoleRepository(), mocks.NewServiceAccountRepository(), mocks.NewQuotaRepository(), mocks.NewPolicyExpirationRepository(), revocations, mocks.NewSessionRepository(), mocks.NewAuditRepository(), uuid.NewMock(), jwt.New(secret), mocks.NewKetoMock(map[string][]mocks.MockSubjectSet{}), nil)
+	_, token, err := authSvc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: userID, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("issuing login key expected to succeed: %s", err))
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("generating RSA key expected to succeed: %s", err))
+	signer, err := jwt.NewIDTokenSigner(jwt.Key{ID: "kid", Algorithm: jwt.RS256, Sign: priv, Verify: &priv.PublicKey})
+	require.Nil(t, err, fmt.Sprintf("creating ID token signer expected to succeed: %s", err))
+
+	cfg := oidc.Config{
+		Issuer:   issuer,
+		LoginURL: loginURL,
+		Clients:  []oidc.Client{{ID: clientID, Secret: clientSecret, RedirectURIs: []string{redirectURI}}},
+	}
+	tv := oidcmocks.NewVerifier(map[string]oidc.ExternalIdentity{extToken: {Issuer: extIssuer, Subject: "external-user"}})
+	svc := oidc.New(cfg, authSvc, oidcmocks.NewCodeRepository(), signer, oidcmocks.NewLinkRepository(), revocations, tv)
+	mux := httpapi.MakeHandler(svc, bone.New(), mocktracer.New())
+
+	return httptest.NewServer(mux), token
+}
+
+func toJSON(data interface{}) string {
+	jsonData, _ := json.Marshal(data)
+	return string(jsonData)
+}
+
+func TestDiscovery(t *testing.T) {
+	ts, _ := newServer(t)
+	defer ts.Close()
+
+	req := testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/.well-known/openid-configuration", ts.URL),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusOK, res.StatusCode))
+
+	var body map[string]interface{}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, issuer, body["issuer"], "unexpected issuer")
+	assert.Equal(t, issuer+oidc.TokenPath, body["token_endpoint"], "unexpected token endpoint")
+	assert.Equal(t, issuer+oidc.JWKSPath, body["jwks_uri"], "unexpected JWKS URI")
+}
+
+func TestLogin(t *testing.T) {
+	ts, _ := newServer(t)
+	defer ts.Close()
+
+	client := ts.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	cases := []struct {
+		desc     string
+		query    url.Values
+		status   int
+		location string
+	}{
+		{
+			desc:     "redirect to login page",
+			query:    url.Values{"response_type": {"code"}, "client_id": {clientID}, "redirect_uri": {redirectURI}, "scope": {"openid"}},
+			status:   http.StatusFound,
+			location: loginURL,
+		},
+		{
+			desc:   "redirect with unregistered redirect URI",
+			query:  url.Values{"response_type": {"code"}, "client_id": {clientID}, "redirect_uri": {"https://attacker.example.com"}, "scope": {"openid"}},
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "redirect with unknown client",
+			query:  url.Values{"response_type": {"code"}, "client_id": {"unknown"}, "redirect_uri": {redirectURI}, "scope": {"openid"}},
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "redirect without client",
+			query:  url.Values{"response_type": {"code"}, "redirect_uri": {redirectURI}, "scope": {"openid"}},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s%s?%s", ts.URL, oidc.AuthorizationPath, tc.query.Encode()),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.location != "" {
+			location := res.Header.Get("Location")
+			assert.True(t, strings.HasPrefix(location, tc.location), fmt.Sprintf("%s: expected location %s got %s", tc.desc, tc.location, location))
+		}
+	}
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	ts, token := newServer(t)
+	defer ts.Close()
+
+	authReq := map[string]string{
+		"response_type": "code",
+		"client_id":     clientID,
+		"redirect_uri":  redirectURI,
+		"scope":         "openid email",
+		"state":         "state",
+		"nonce":         "nonce",
+	}
+
+	authCases := []struct {
+		desc        string
+		token       string
+		contentType string
+		body        string
+		status      int
+	}{
+		{
+			desc:        "authorize with invalid token",
+			token:       "invalid",
+			contentType: contentType,
+			body:        toJSON(authReq),
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "authorize with invalid content type",
+			token:       token,
+			contentType: formType,
+			body:        toJSON(authReq),
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "authorize with malformed body",
+			token:       token,
+			contentType: contentType,
+			body:        "{",
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "authorize valid request",
+			token:       token,
+			contentType: contentType,
+			body:        toJSON(authReq),
+			status:      http.StatusOK,
+		},
+	}
+
+	var code string
+	for _, tc := range authCases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s%s", ts.URL, oidc.AuthorizationPath),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if res.StatusCode != http.StatusOK {
+			continue
+		}
+		var body struct {
+			RedirectURI string `json:"redirect_uri"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		u, err := url.Parse(body.RedirectURI)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, "state", u.Query().Get("state"), fmt.Sprintf("%s: expected state in redirect URI", tc.desc))
+		code = u.Query().Get("code")
+	}
+
+	tokenForm := url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {redirectURI}}
+	postForm := url.Values{"grant_type": {"authorization_code"}, "code": {code}, "redirect_uri": {redirectURI}, "client_id": {clientID}, "client_secret": {"wrong"}}
+
+	tokenCases := []struct {
+		desc   string
+		basic  bool
+		form   url.Values
+		status int
+		err    string
+	}{
+		{
+			desc:   "exchange code with wrong client secret",
+			basic:  false,
+			form:   postForm,
+			status: http.StatusUnauthorized,
+			err:    "invalid_client",
+		},
+		{
+			desc:   "exchange code",
+			basic:  true,
+			form:   tokenForm,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "exchange used code",
+			basic:  true,
+			form:   tokenForm,
+			status: http.StatusBadRequest,
+			err:    "invalid_grant",
+		},
+	}
+
+	var accessToken string
+	for _, tc := range tokenCases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s%s", ts.URL, oidc.TokenPath),
+			contentType: formType,
+			basic:       tc.basic,
+			body:        strings.NewReader(tc.form.Encode()),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body map[string]interface{}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		if tc.err != "" {
+			assert.Equal(t, tc.err, body["error"], fmt.Sprintf("%s: expected error %s got %v", tc.desc, tc.err, body["error"]))
+			continue
+		}
+		assert.Equal(t, "no-store", res.Header.Get("Cache-Control"), fmt.Sprintf("%s: expected the tokens not to be cached", tc.desc))
+		assert.NotEmpty(t, body["id_token"], fmt.Sprintf("%s: expected ID token", tc.desc))
+		accessToken, _ = body["access_token"].(string)
+	}
+
+	infoCases := []struct {
+		desc   string
+		token  string
+		status int
+	}{
+		{
+			desc:   "retrieve user info with bearer token",
+			token:  "Bearer " + accessToken,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "retrieve user info with invalid token",
+			token:  "Bearer invalid",
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "retrieve user info without token",
+			token:  "",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range infoCases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s%s", ts.URL, oidc.UserInfoPath),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if res.StatusCode != http.StatusOK {
+			continue
+		}
+		var body map[string]string
+		err = json.NewDecoder(res.Body).Decode(&body)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, userID, body["sub"], fmt.Sprintf("%s: expected subject %s got %s", tc.desc, userID, body["sub"]))
+		assert.Equal(t, email, body["email"], fmt.Sprintf("%s: expected email %s got %s", tc.desc, email, body["email"]))
+	}
+}
+
+func TestTokenExchange(t *testing.T) {
+	ts, token := newServer(t)
+	defer ts.Close()
+
+	linkCases := []struct {
+		desc   string
+		token  string
+		body   string
+		status int
+	}{
+		{
+			desc:   "link identity with invalid subject token",
+			token:  token,
+			body:   toJSON(map[string]string{"subject_token": "invalid"}),
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "link identity without subject token",
+			token:  token,
+			body:   "{}",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "link identity with invalid token",
+			token:  "invalid",
+			body:   toJSON(map[string]string{"subject_token": extToken}),
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "link identity",
+			token:  token,
+			body:   toJSON(map[string]string{"subject_token": extToken}),
+			status: http.StatusCreated,
+		},
+		{
+			desc:   "link already linked identity",
+			token:  token,
+			body:   toJSON(map[string]string{"subject_token": extToken}),
+			status: http.StatusConflict,
+		},
+	}
+
+	for _, tc := range linkCases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s%s", ts.URL, oidc.LinksPath),
+			contentType: contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.body),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	req := testRequest{
+		client: ts.Client(),
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s%s", ts.URL, oidc.LinksPath),
+		token:  token,
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("expected status code %d got %d", http.StatusOK, res.StatusCode))
+	var links struct {
+		Links []map[string]interface{} `json:"links"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&links)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Len(t, links.Links, 1, "expected a single link")
+	assert.Equal(t, extIssuer, links.Links[0]["issuer"], "unexpected link issuer")
+
+	exchangeForm := url.Values{"grant_type": {oidc.TokenExchangeType}, "subject_token": {extToken}, "subject_token_type": {oidc.IDTokenType}}
+	invalidForm := url.Values{"grant_type": {oidc.TokenExchangeType}, "subject_token": {"invalid"}, "subject_token_type": {oidc.IDTokenType}}
+
+	exchangeCases := []struct {
+		desc   string
+		form   url.Values
+		status int
+		err    string
+	}{
+		{
+			desc:   "exchange invalid subject token",
+			form:   invalidForm,
+			status: http.StatusBadRequest,
+			err:    "invalid_grant",
+		},
+		{
+			desc:   "exchange subject token without client authentication",
+			form:   exchangeForm,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range exchangeCases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s%s", ts.URL, oidc.TokenPath),
+			contentType: formType,
+			body:        strings.NewReader(tc.form.Encode()),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body map[string]interface{}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		if tc.err != "" {
+			assert.Equal(t, tc.err, body["error"], fmt.Sprintf("%s: expected error %s got %v", tc.desc, tc.err, body["error"]))
+			continue
+		}
+		assert.Equal(t, oidc.AccessTokenType, body["issued_token_type"], fmt.Sprintf("%s: unexpected issued token type", tc.desc))
+		assert.NotEmpty(t, body["access_token"], fmt.Sprintf("%s: expected access token", tc.desc))
+		assert.Nil(t, body["id_token"], fmt.Sprintf("%s: expected no ID token", tc.desc))
+	}
+
+	unlinkCases := []struct {
+		desc   string
+		query  url.Values
+		status int
+	}{
+		{
+			desc:   "unlink identity without subject",
+			query:  url.Values{"issuer": {extIssuer}},
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "unlink identity",
+			query:  url.Values{"issuer": {extIssuer}, "subject": {"external-user"}},
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "unlink unlinked identity",
+			query:  url.Values{"issuer": {extIssuer}, "subject": {"external-user"}},
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range unlinkCases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s%s?%s", ts.URL, oidc.LinksPath, tc.query.Encode()),
+			token:  token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
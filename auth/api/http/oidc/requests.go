@@ -0,0 +1,120 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"github.com/mainflux/mainflux/auth/oidc"
+)
+
+type loginReq struct {
+	req oidc.AuthRequest
+}
+
+func (req loginReq) validate() error {
+	if req.req.ClientID == "" || req.req.RedirectURI == "" {
+		return oidc.ErrInvalidRequest
+	}
+	return nil
+}
+
+type authorizeReq struct {
+	token               string
+	ResponseType        string `json:"response_type"`
+	ClientID            string `json:"client_id"`
+	RedirectURI         string `json:"redirect_uri"`
+	Scope               string `json:"scope"`
+	State               string `json:"state,omitempty"`
+	Nonce               string `json:"nonce,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"`
+}
+
+func (req authorizeReq) validate() error {
+	if req.token == "" {
+		return oidc.ErrUnauthorizedAccess
+	}
+	if req.ClientID == "" || req.RedirectURI == "" {
+		return oidc.ErrInvalidRequest
+	}
+	return nil
+}
+
+func (req authorizeReq) authRequest() oidc.AuthRequest {
+	return oidc.AuthRequest{
+		ResponseType:        req.ResponseType,
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		State:               req.State,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+	}
+}
+
+type tokenReq struct {
+	req oidc.TokenRequest
+}
+
+// validate requires the client to identify itself, apart from the token
+// exchange which the public clients use as well.
+func (req tokenReq) validate() error {
+	if req.req.ClientID == "" && req.req.GrantType != oidc.TokenExchangeType {
+		return oidc.ErrInvalidClient
+	}
+	return nil
+}
+
+type userInfoReq struct {
+	token string
+}
+
+func (req userInfoReq) validate() error {
+	if req.token == "" {
+		return oidc.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+type linkIdentityReq struct {
+	token        string
+	SubjectToken string `json:"subject_token"`
+}
+
+func (req linkIdentityReq) validate() error {
+	if req.token == "" {
+		return oidc.ErrUnauthorizedAccess
+	}
+	if req.SubjectToken == "" {
+		return oidc.ErrInvalidRequest
+	}
+	return nil
+}
+
+type listLinksReq struct {
+	token string
+}
+
+func (req listLinksReq) validate() error {
+	if req.token == "" {
+		return oidc.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+type unlinkIdentityReq struct {
+	token   string
+	issuer  string
+	subject string
+}
+
+func (req unlinkIdentityReq) validate() error {
+	if req.token == "" {
+		return oidc.ErrUnauthorizedAccess
+	}
+	if req.issuer == "" || req.subject == "" {
+		return oidc.ErrInvalidRequest
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*discoveryRes)(nil)
+	_ mainflux.Response = (*loginRes)(nil)
+	_ mainflux.Response = (*authorizeRes)(nil)
+	_ mainflux.Response = (*tokenRes)(nil)
+	_ mainflux.Response = (*userInfoRes)(nil)
+	_ mainflux.Response = (*linkRes)(nil)
+	_ mainflux.Response = (*linksRes)(nil)
+	_ mainflux.Response = (*unlinkRes)(nil)
+)
+
+type discoveryRes struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	SigningAlgValuesSupported         []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+}
+
+func (res discoveryRes) Code() int {
+	return http.StatusOK
+}
+
+func (res discoveryRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res discoveryRes) Empty() bool {
+	return false
+}
+
+// loginRes redirects the user agent to the login page.
+type loginRes struct {
+	location string
+}
+
+func (res loginRes) Code() int {
+	return http.StatusFound
+}
+
+func (res loginRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": res.location,
+	}
+}
+
+func (res loginRes) Empty() bool {
+	return true
+}
+
+type authorizeRes struct {
+	RedirectURI string `json:"redirect_uri"`
+}
+
+func (res authorizeRes) Code() int {
+	return http.StatusOK
+}
+
+func (res authorizeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res authorizeRes) Empty() bool {
+	return false
+}
+
+type tokenRes struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+	IDToken         string `json:"id_token,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+}
+
+func (res tokenRes) Code() int {
+	return http.StatusOK
+}
+
+// The responses carrying the tokens must not be cached.
+func (res tokenRes) Headers() map[string]string {
+	return map[string]string{
+		"Cache-Control": "no-store",
+		"Pragma":        "no-cache",
+	}
+}
+
+func (res tokenRes) Empty() bool {
+	return false
+}
+
+type userInfoRes struct {
+	Subject string `json:"sub"`
+	Email   string `json:"email,omitempty"`
+}
+
+func (res userInfoRes) Code() int {
+	return http.StatusOK
+}
+
+func (res userInfoRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res userInfoRes) Empty() bool {
+	return false
+}
+
+type linkRes struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+	created   bool
+}
+
+func (res linkRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func (res linkRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res linkRes) Empty() bool {
+	return false
+}
+
+type linksRes struct {
+	Links []linkRes `json:"links"`
+}
+
+func (res linksRes) Code() int {
+	return http.StatusOK
+}
+
+func (res linksRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res linksRes) Empty() bool {
+	return false
+}
+
+type unlinkRes struct{}
+
+func (res unlinkRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res unlinkRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res unlinkRes) Empty() bool {
+	return true
+}
+
+// errorRes is the error response, as specified by RFC 6749.
+type errorRes struct {
+	Err         string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
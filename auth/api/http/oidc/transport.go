@@ -0,0 +1,271 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth/oidc"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	contentType     = "application/json"
+	formContentType = "application/x-www-form-urlencoded"
+	bearerPrefix    = "Bearer "
+)
+
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// MakeHandler returns a HTTP handler for the OpenID Provider endpoints.
+func MakeHandler(svc oidc.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
+	}
+
+	mux.Get("/.well-known/openid-configuration", kithttp.NewServer(
+		kitot.TraceServer(tracer, "oidc_discovery")(discoveryEndpoint(svc)),
+		decodeDiscovery,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get(oidc.AuthorizationPath, kithttp.NewServer(
+		kitot.TraceServer(tracer, "oidc_login")(loginEndpoint(svc)),
+		decodeLogin,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post(oidc.AuthorizationPath, kithttp.NewServer(
+		kitot.TraceServer(tracer, "oidc_authorize")(authorizeEndpoint(svc)),
+		decodeAuthorize,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post(oidc.TokenPath, kithttp.NewServer(
+		kitot.TraceServer(tracer, "oidc_token")(tokenEndpoint(svc)),
+		decodeToken,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get(oidc.UserInfoPath, kithttp.NewServer(
+		kitot.TraceServer(tracer, "oidc_userinfo")(userInfoEndpoint(svc)),
+		decodeUserInfo,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post(oidc.LinksPath, kithttp.NewServer(
+		kitot.TraceServer(tracer, "oidc_link_identity")(linkIdentityEndpoint(svc)),
+		decodeLinkIdentity,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get(oidc.LinksPath, kithttp.NewServer(
+		kitot.TraceServer(tracer, "oidc_list_links")(listLinksEndpoint(svc)),
+		decodeListLinks,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete(oidc.LinksPath, kithttp.NewServer(
+		kitot.TraceServer(tracer, "oidc_unlink_identity")(unlinkIdentityEndpoint(svc)),
+		decodeUnlinkIdentity,
+		encodeResponse,
+		opts...,
+	))
+
+	return mux
+}
+
+func decodeDiscovery(_ context.Context, _ *http.Request) (interface{}, error) {
+	return nil, nil
+}
+
+func decodeLogin(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	req := loginReq{
+		req: oidc.AuthRequest{
+			ResponseType:        q.Get("response_type"),
+			ClientID:            q.Get("client_id"),
+			RedirectURI:         q.Get("redirect_uri"),
+			Scope:               q.Get("scope"),
+			State:               q.Get("state"),
+			Nonce:               q.Get("nonce"),
+			CodeChallenge:       q.Get("code_challenge"),
+			CodeChallengeMethod: q.Get("code_challenge_method"),
+		},
+	}
+
+	return req, nil
+}
+
+func decodeAuthorize(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := authorizeReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(oidc.ErrInvalidRequest, err)
+	}
+
+	return req, nil
+}
+
+func decodeToken(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), formContentType) {
+		return nil, errUnsupportedContentType
+	}
+	if err := r.ParseForm(); err != nil {
+		return nil, errors.Wrap(oidc.ErrInvalidRequest, err)
+	}
+
+	req := tokenReq{
+		req: oidc.TokenRequest{
+			GrantType:    r.PostForm.Get("grant_type"),
+			Code:         r.PostForm.Get("code"),
+			RedirectURI:  r.PostForm.Get("redirect_uri"),
+			ClientID:     r.PostForm.Get("client_id"),
+			ClientSecret: r.PostForm.Get("client_secret"),
+			CodeVerifier: r.PostForm.Get("code_verifier"),
+
+			SubjectToken:     r.PostForm.Get("subject_token"),
+			SubjectTokenType: r.PostForm.Get("subject_token_type"),
+		},
+	}
+
+	// The client credentials sent using the Basic authentication scheme
+	// are form encoded, as specified by RFC 6749.
+	if id, secret, ok := r.BasicAuth(); ok {
+		var err error
+		if req.req.ClientID, err = url.QueryUnescape(id); err != nil {
+			return nil, errors.Wrap(oidc.ErrInvalidClient, err)
+		}
+		if req.req.ClientSecret, err = url.QueryUnescape(secret); err != nil {
+			return nil, errors.Wrap(oidc.ErrInvalidClient, err)
+		}
+	}
+
+	return req, nil
+}
+
+func decodeUserInfo(_ context.Context, r *http.Request) (interface{}, error) {
+	// The clients send the access token using the Bearer scheme, while the
+	// Mainflux clients send the bare token.
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), bearerPrefix)
+	return userInfoReq{token: token}, nil
+}
+
+func decodeLinkIdentity(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := linkIdentityReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(oidc.ErrInvalidRequest, err)
+	}
+
+	return req, nil
+}
+
+func decodeListLinks(_ context.Context, r *http.Request) (interface{}, error) {
+	return listLinksReq{token: r.Header.Get("Authorization")}, nil
+}
+
+func decodeUnlinkIdentity(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+	req := unlinkIdentityReq{
+		token:   r.Header.Get("Authorization"),
+		issuer:  q.Get("issuer"),
+		subject: q.Get("subject"),
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeError encodes the error as specified by RFC 6749 and RFC 6750, so the
+// clients handle it as any other OAuth 2.0 error.
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	res := errorRes{}
+	switch {
+	case errors.Contains(err, errUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		res.Err = "invalid_request"
+	case errors.Contains(err, oidc.ErrInvalidRequest):
+		w.WriteHeader(http.StatusBadRequest)
+		res.Err = "invalid_request"
+	case errors.Contains(err, oidc.ErrInvalidScope):
+		w.WriteHeader(http.StatusBadRequest)
+		res.Err = "invalid_scope"
+	case errors.Contains(err, oidc.ErrInvalidGrant):
+		w.WriteHeader(http.StatusBadRequest)
+		res.Err = "invalid_grant"
+	case errors.Contains(err, oidc.ErrUnsupportedGrantType):
+		w.WriteHeader(http.StatusBadRequest)
+		res.Err = "unsupported_grant_type"
+	case errors.Contains(err, oidc.ErrInvalidClient):
+		w.Header().Set("WWW-Authenticate", `Basic realm="mainflux"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		res.Err = "invalid_client"
+	case errors.Contains(err, oidc.ErrUnauthorizedAccess):
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		res.Err = "invalid_token"
+	case errors.Contains(err, oidc.ErrConflict):
+		w.WriteHeader(http.StatusConflict)
+		res.Err = "conflict"
+	case errors.Contains(err, oidc.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+		res.Err = "not_found"
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		res.Err = "server_error"
+	}
+
+	if errorVal, ok := err.(errors.Error); ok {
+		res.Description = i18n.Localize(ctx, errorVal.Msg())
+	}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
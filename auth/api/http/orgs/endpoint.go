@@ -0,0 +1,198 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package orgs
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/auth"
+)
+
+func createOrgEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(orgReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		org, err := svc.CreateOrg(ctx, req.token, toOrg(req))
+		if err != nil {
+			return nil, err
+		}
+
+		return orgRes{viewOrgRes: toViewOrgRes(org), created: true}, nil
+	}
+}
+
+func viewOrgEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewOrgReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		org, err := svc.ViewOrg(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return orgRes{viewOrgRes: toViewOrgRes(org)}, nil
+	}
+}
+
+func updateOrgEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(orgReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		org, err := svc.UpdateOrg(ctx, req.token, toOrg(req))
+		if err != nil {
+			return nil, err
+		}
+
+		return orgRes{viewOrgRes: toViewOrgRes(org)}, nil
+	}
+}
+
+func removeOrgEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewOrgReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveOrg(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return emptyRes{code: http.StatusNoContent}, nil
+	}
+}
+
+func listOrgsEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListOrgs(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := orgsPageRes{
+			pageRes: pageRes{Total: page.Total, Offset: page.Offset, Limit: page.Limit},
+			Orgs:    []viewOrgRes{},
+		}
+		for _, org := range page.Orgs {
+			res.Orgs = append(res.Orgs, toViewOrgRes(org))
+		}
+
+		return res, nil
+	}
+}
+
+func assignMembersEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(membersReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.AssignOrgMembers(ctx, req.token, req.id, req.Members...); err != nil {
+			return nil, err
+		}
+
+		return emptyRes{code: http.StatusOK}, nil
+	}
+}
+
+func unassignMembersEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(membersReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UnassignOrgMembers(ctx, req.token, req.id, req.Members...); err != nil {
+			return nil, err
+		}
+
+		return emptyRes{code: http.StatusNoContent}, nil
+	}
+}
+
+func listMembersEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListOrgMembers(ctx, req.token, req.id, req.offset, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := membersPageRes{
+			pageRes: pageRes{Total: page.Total, Offset: page.Offset, Limit: page.Limit},
+			Members: []viewMemberRes{},
+		}
+		for _, m := range page.Members {
+			res.Members = append(res.Members, viewMemberRes{ID: m.ID, CreatedBy: m.CreatedBy, CreatedAt: m.CreatedAt})
+		}
+
+		return res, nil
+	}
+}
+
+func switchOrgEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(switchOrgReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		key, secret, err := svc.SwitchOrg(ctx, req.token, req.OrgID)
+		if err != nil {
+			return nil, err
+		}
+
+		res := switchOrgRes{
+			ID:        key.ID,
+			Value:     secret,
+			OrgID:     key.OrgID,
+			IssuedAt:  key.IssuedAt,
+			ExpiresAt: key.ExpiresAt,
+		}
+
+		return res, nil
+	}
+}
+
+func toOrg(req orgReq) auth.Org {
+	return auth.Org{
+		ID:          req.id,
+		Name:        req.Name,
+		Description: req.Description,
+		Metadata:    req.Metadata,
+	}
+}
+
+func toViewOrgRes(org auth.Org) viewOrgRes {
+	return viewOrgRes{
+		ID:          org.ID,
+		OwnerID:     org.OwnerID,
+		Name:        org.Name,
+		Description: org.Description,
+		Metadata:    org.Metadata,
+		CreatedAt:   org.CreatedAt,
+		UpdatedAt:   org.UpdatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package orgs
+
+import "github.com/mainflux/mainflux/auth"
+
+const maxLimitSize = 100
+
+type orgReq struct {
+	token       string
+	id          string
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+func (req orgReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.Name == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewOrgReq struct {
+	token string
+	id    string
+}
+
+func (req viewOrgReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listReq struct {
+	token  string
+	id     string
+	offset uint64
+	limit  uint64
+}
+
+func (req listReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type membersReq struct {
+	token   string
+	id      string
+	Members []string `json:"members"`
+}
+
+func (req membersReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" || len(req.Members) == 0 {
+		return auth.ErrMalformedEntity
+	}
+
+	for _, m := range req.Members {
+		if m == "" {
+			return auth.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type switchOrgReq struct {
+	token string
+	// OrgID is the organization the new key is scoped to. The empty OrgID
+	// stands for the entities outside of any organization.
+	OrgID string `json:"org_id"`
+}
+
+func (req switchOrgReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
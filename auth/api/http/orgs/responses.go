@@ -0,0 +1,141 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package orgs
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*orgRes)(nil)
+	_ mainflux.Response = (*orgsPageRes)(nil)
+	_ mainflux.Response = (*membersPageRes)(nil)
+	_ mainflux.Response = (*switchOrgRes)(nil)
+	_ mainflux.Response = (*emptyRes)(nil)
+)
+
+type viewOrgRes struct {
+	ID          string                 `json:"id"`
+	OwnerID     string                 `json:"owner_id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt   time.Time              `json:"created_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+type orgRes struct {
+	viewOrgRes
+	created bool
+}
+
+func (res orgRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+func (res orgRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/orgs/%s", res.ID),
+		}
+	}
+	return map[string]string{}
+}
+
+func (res orgRes) Empty() bool {
+	return false
+}
+
+type pageRes struct {
+	Total  uint64 `json:"total"`
+	Offset uint64 `json:"offset"`
+	Limit  uint64 `json:"limit"`
+}
+
+type orgsPageRes struct {
+	pageRes
+	Orgs []viewOrgRes `json:"orgs"`
+}
+
+func (res orgsPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res orgsPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res orgsPageRes) Empty() bool {
+	return false
+}
+
+type viewMemberRes struct {
+	ID        string    `json:"id"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type membersPageRes struct {
+	pageRes
+	Members []viewMemberRes `json:"members"`
+}
+
+func (res membersPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res membersPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res membersPageRes) Empty() bool {
+	return false
+}
+
+type switchOrgRes struct {
+	ID        string    `json:"id"`
+	Value     string    `json:"value"`
+	OrgID     string    `json:"org_id,omitempty"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (res switchOrgRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res switchOrgRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res switchOrgRes) Empty() bool {
+	return false
+}
+
+type emptyRes struct {
+	code int
+}
+
+func (res emptyRes) Code() int {
+	return res.code
+}
+
+func (res emptyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res emptyRes) Empty() bool {
+	return true
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
@@ -0,0 +1,239 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package orgs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	contentType = "application/json"
+	offsetKey   = "offset"
+	limitKey    = "limit"
+	defOffset   = 0
+	defLimit    = 10
+)
+
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
+	}
+
+	mux.Post("/orgs", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_org")(createOrgEndpoint(svc)),
+		decodeOrgRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/orgs", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_orgs")(listOrgsEndpoint(svc)),
+		decodeListRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/orgs/switch", kithttp.NewServer(
+		kitot.TraceServer(tracer, "switch_org")(switchOrgEndpoint(svc)),
+		decodeSwitchOrgRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/orgs/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_org")(viewOrgEndpoint(svc)),
+		decodeViewOrgRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/orgs/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "update_org")(updateOrgEndpoint(svc)),
+		decodeOrgRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/orgs/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_org")(removeOrgEndpoint(svc)),
+		decodeViewOrgRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/orgs/:id/members", kithttp.NewServer(
+		kitot.TraceServer(tracer, "assign_org_members")(assignMembersEndpoint(svc)),
+		decodeMembersRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/orgs/:id/members", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unassign_org_members")(unassignMembersEndpoint(svc)),
+		decodeMembersRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/orgs/:id/members", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_org_members")(listMembersEndpoint(svc)),
+		decodeListRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	return mux
+}
+
+func decodeOrgRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := orgReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeViewOrgRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewOrgReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func decodeListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listReq{
+		token:  r.Header.Get("Authorization"),
+		id:     bone.GetValue(r, "id"),
+		offset: o,
+		limit:  l,
+	}
+
+	return req, nil
+}
+
+func decodeMembersRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := membersReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeSwitchOrgRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := switchOrgReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	switch {
+	case errors.Contains(err, auth.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, io.EOF),
+		errors.Contains(err, io.ErrUnexpectedEOF):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, auth.ErrUnauthorizedAccess),
+		errors.Contains(err, auth.ErrAuthorization),
+		errors.Contains(err, auth.ErrOrgMember):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, auth.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, auth.ErrConflict),
+		errors.Contains(err, auth.ErrMemberAlreadyAssigned),
+		errors.Contains(err, auth.ErrOrgNotEmpty):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, auth.ErrQuotaExceeded):
+		w.WriteHeader(http.StatusTooManyRequests)
+	case errors.Contains(err, errUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	errorVal, ok := err.(errors.Error)
+	if ok {
+		w.Header().Set("Content-Type", contentType)
+		msg := i18n.Localize(ctx, errorVal.Msg())
+		// Quota errors describe the exceeded limit.
+		if errors.Contains(err, auth.ErrQuotaExceeded) && errorVal.Err() != nil {
+			msg = fmt.Sprintf("%s: %s", msg, errorVal.Err())
+		}
+		if err := json.NewEncoder(w).Encode(errorRes{Err: msg}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
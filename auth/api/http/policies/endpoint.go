@@ -9,11 +9,15 @@ import (
 
 func createPolicyEndpoint(svc auth.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(policiesReq)
+		req := request.(createPoliciesReq)
 		if err := req.validate(); err != nil {
 			return createPolicyRes{}, err
 		}
 
+		if len(req.Triples) > 0 {
+			return createPolicyTriples(ctx, svc, req)
+		}
+
 		if err := svc.AddPolicies(ctx, req.token, req.Object, req.SubjectIDs, req.Policies); err != nil {
 			return createPolicyRes{}, err
 		}
@@ -22,6 +26,30 @@ func createPolicyEndpoint(svc auth.Service) endpoint.Endpoint {
 	}
 }
 
+func createPolicyTriples(ctx context.Context, svc auth.Service, req createPoliciesReq) (interface{}, error) {
+	prs := make([]auth.PolicyReq, len(req.Triples))
+	for i, t := range req.Triples {
+		// The TTL is validated along with the request.
+		ttl, _ := t.ttl()
+		prs[i] = auth.PolicyReq{Subject: t.Subject, Object: t.Object, Relation: t.Relation, Namespace: t.Namespace, TTL: ttl}
+	}
+
+	results, err := svc.AddPolicyTriples(ctx, req.token, prs)
+	if err != nil {
+		return createPolicyTriplesRes{}, err
+	}
+
+	res := createPolicyTriplesRes{Policies: []policyResultRes{}}
+	for _, r := range results {
+		pr := policyResultRes{Subject: r.Subject, Object: r.Object, Relation: r.Relation}
+		if r.Err != nil {
+			pr.Error = r.Err.Error()
+		}
+		res.Policies = append(res.Policies, pr)
+	}
+	return res, nil
+}
+
 func deletePoliciesEndpoint(svc auth.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(policiesReq)
@@ -36,3 +64,51 @@ func deletePoliciesEndpoint(svc auth.Service) endpoint.Endpoint {
 		return deletePoliciesRes{deleted: true}, nil
 	}
 }
+
+func inspectPoliciesEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(inspectPoliciesReq)
+		if err := req.validate(); err != nil {
+			return inspectPoliciesRes{}, err
+		}
+
+		pr := auth.PolicyReq{Subject: req.subject, Object: req.object, Relation: req.relation, Namespace: req.namespace}
+		policies, err := svc.InspectPolicies(ctx, req.token, pr)
+		if err != nil {
+			return inspectPoliciesRes{}, err
+		}
+
+		res := inspectPoliciesRes{Policies: []policyRes{}}
+		for _, p := range policies {
+			res.Policies = append(res.Policies, policyRes{Subject: p.Subject, Object: p.Object, Relation: p.Relation, Namespace: p.Namespace})
+		}
+		return res, nil
+	}
+}
+
+func explainPolicyEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(explainPolicyReq)
+		if err := req.validate(); err != nil {
+			return explainPolicyRes{}, err
+		}
+
+		pr := auth.PolicyReq{Subject: req.Subject, Object: req.Object, Relation: req.Relation, Namespace: req.Namespace}
+		expl, err := svc.ExplainPolicy(ctx, req.token, pr)
+		if err != nil {
+			return explainPolicyRes{}, err
+		}
+
+		res := explainPolicyRes{
+			Allowed:  expl.Allowed,
+			Reason:   expl.Reason,
+			Path:     []policyRes{},
+			Expanded: []string{},
+		}
+		for _, p := range expl.Path {
+			res.Path = append(res.Path, policyRes{Subject: p.Subject, Object: p.Object, Relation: p.Relation, Namespace: p.Namespace})
+		}
+		res.Expanded = append(res.Expanded, expl.Expanded...)
+		return res, nil
+	}
+}
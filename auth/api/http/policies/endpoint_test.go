@@ -68,7 +68,7 @@ func newService() auth.Service {
 	mockAuthzDB[unauthzID] = append(mockAuthzDB[unauthzID], mocks.MockSubjectSet{Object: "users", Relation: "member"})
 	ketoMock := mocks.NewKetoMock(mockAuthzDB)
 
-	return auth.New(repo, groupRepo, idProvider, t, ketoMock)
+	return auth.New(repo, groupRepo, mocks.NewOrgRepository(), mocks.NewShareRepository(), mocks.NewRoleRepository(), mocks.NewServiceAccountRepository(), mocks.NewQuotaRepository(), mocks.NewPolicyExpirationRepository(), mocks.NewRevocationRepository(), mocks.NewSessionRepository(), mocks.NewAuditRepository(), idProvider, t, ketoMock, nil)
 }
 
 func newServer(svc auth.Service) *httptest.Server {
@@ -207,6 +207,139 @@ func TestAddPolicies(t *testing.T) {
 	}
 }
 
+type policyTriple struct {
+	Subject   string `json:"subject"`
+	Object    string `json:"object"`
+	Relation  string `json:"relation"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type addPolicyTriplesRequest struct {
+	Triples    []policyTriple `json:"triples"`
+	SubjectIDs []string       `json:"subjects,omitempty"`
+	Policies   []string       `json:"policies,omitempty"`
+	Object     string         `json:"object,omitempty"`
+}
+
+type policyResultRes struct {
+	Subject  string `json:"subject"`
+	Object   string `json:"object"`
+	Relation string `json:"relation"`
+	Error    string `json:"error,omitempty"`
+}
+
+type addPolicyTriplesRes struct {
+	Policies []policyResultRes `json:"policies"`
+	Error    string            `json:"error"`
+}
+
+func TestAddPolicyTriples(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	_, userLoginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: unauthzID, Subject: unauthzEmail})
+	assert.Nil(t, err, fmt.Sprintf("Issuing unauthorized user's key expected to succeed: %s", err))
+
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	triples := []policyTriple{
+		{Subject: "user1", Object: "obj1", Relation: "read"},
+		{Subject: "user2", Object: "obj2", Relation: "write"},
+	}
+	valid := addPolicyTriplesRequest{Triples: triples}
+	invalidRelation := addPolicyTriplesRequest{Triples: append(triples, policyTriple{Subject: "user3", Object: "obj3", Relation: "invalid"})}
+	invalidNamespace := addPolicyTriplesRequest{Triples: []policyTriple{{Subject: "user1", Object: "obj1", Relation: "read", Namespace: "invalid"}}}
+	missingSubject := addPolicyTriplesRequest{Triples: []policyTriple{{Object: "obj1", Relation: "read"}}}
+	missingObject := addPolicyTriplesRequest{Triples: []policyTriple{{Subject: "user1", Relation: "read"}}}
+	combined := addPolicyTriplesRequest{Triples: triples, Object: "obj"}
+
+	cases := []struct {
+		desc     string
+		token    string
+		status   int
+		req      string
+		policies []policyResultRes
+		err      string
+	}{
+		{
+			desc:   "add policy triples with authorized access",
+			token:  loginSecret,
+			status: http.StatusCreated,
+			req:    toJSON(valid),
+			policies: []policyResultRes{
+				{Subject: "user1", Object: "obj1", Relation: "read"},
+				{Subject: "user2", Object: "obj2", Relation: "write"},
+			},
+		},
+		{
+			desc:   "add policy triples with unauthorized access",
+			token:  userLoginSecret,
+			status: http.StatusForbidden,
+			req:    toJSON(valid),
+		},
+		{
+			desc:   "add policy triples with invalid relation",
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+			req:    toJSON(invalidRelation),
+			err:    "malformed entity specification: policy 2: invalid relation 'invalid'",
+		},
+		{
+			desc:   "add policy triples with invalid namespace",
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+			req:    toJSON(invalidNamespace),
+			err:    "malformed entity specification: policy 0: invalid namespace 'invalid'",
+		},
+		{
+			desc:   "add policy triples with missing subject",
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+			req:    toJSON(missingSubject),
+			err:    "malformed entity specification: policy 0: missing subject",
+		},
+		{
+			desc:   "add policy triples with missing object",
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+			req:    toJSON(missingObject),
+			err:    "malformed entity specification: policy 0: missing object",
+		},
+		{
+			desc:   "add policy triples combined with object",
+			token:  loginSecret,
+			status: http.StatusBadRequest,
+			req:    toJSON(combined),
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/policies", ts.URL),
+			contentType: contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body addPolicyTriplesRes
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error while decoding response body: %s", tc.desc, err))
+		assert.ElementsMatch(t, tc.policies, body.Policies, fmt.Sprintf("%s: expected policies %v got %v", tc.desc, tc.policies, body.Policies))
+		if tc.err != "" {
+			assert.Equal(t, tc.err, body.Error, fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, body.Error))
+		}
+	}
+}
+
 func TestDeletePolicies(t *testing.T) {
 	svc := newService()
 	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
@@ -330,3 +463,124 @@ func TestDeletePolicies(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 	}
 }
+
+type policyRes struct {
+	Subject  string `json:"subject"`
+	Object   string `json:"object"`
+	Relation string `json:"relation"`
+}
+
+type inspectPoliciesRes struct {
+	Policies []policyRes `json:"policies"`
+}
+
+func TestInspectPolicies(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	_, userLoginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: unauthzID, Subject: unauthzEmail})
+	assert.Nil(t, err, fmt.Sprintf("Issuing unauthorized user's key expected to succeed: %s", err))
+
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	policies := addPolicyRequest{Object: "obj", Policies: []string{"read", "write"}, SubjectIDs: []string{"user1", "user2"}}
+	err = svc.AddPolicies(context.Background(), loginSecret, policies.Object, policies.SubjectIDs, policies.Policies)
+	assert.Nil(t, err, fmt.Sprintf("Adding policies expected to succeed: %s", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		query    string
+		status   int
+		policies []policyRes
+	}{
+		{
+			desc:   "inspect policies of subject",
+			token:  loginSecret,
+			query:  "subject=user1",
+			status: http.StatusOK,
+			policies: []policyRes{
+				{Subject: "user1", Object: "obj", Relation: "read"},
+				{Subject: "user1", Object: "obj", Relation: "write"},
+			},
+		},
+		{
+			desc:   "inspect policies of subject, object and relation",
+			token:  loginSecret,
+			query:  "subject=user2&object=obj&relation=write",
+			status: http.StatusOK,
+			policies: []policyRes{
+				{Subject: "user2", Object: "obj", Relation: "write"},
+			},
+		},
+		{
+			desc:     "inspect policies of non-existing object",
+			token:    loginSecret,
+			query:    "object=non-existing",
+			status:   http.StatusOK,
+			policies: []policyRes{},
+		},
+		{
+			desc:   "inspect own policies as non-admin",
+			token:  userLoginSecret,
+			query:  fmt.Sprintf("subject=%s", unauthzID),
+			status: http.StatusOK,
+			policies: []policyRes{
+				{Subject: unauthzID, Object: "users", Relation: "member"},
+			},
+		},
+		{
+			desc:   "inspect other user policies as non-admin",
+			token:  userLoginSecret,
+			query:  "subject=user1",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "inspect policies with invalid token",
+			token:  "invalid",
+			query:  "subject=user1",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "inspect policies with empty token",
+			token:  "",
+			query:  "subject=user1",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "inspect policies with invalid namespace",
+			token:  loginSecret,
+			query:  "subject=user1&namespace=invalid",
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "inspect policies with duplicate query parameter",
+			token:  loginSecret,
+			query:  "subject=user1&subject=user2",
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/policies?%s", ts.URL, tc.query),
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body inspectPoliciesRes
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.ElementsMatch(t, tc.policies, body.Policies, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.policies, body.Policies))
+	}
+}
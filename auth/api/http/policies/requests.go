@@ -1,7 +1,11 @@
 package policies
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/things"
 )
 
@@ -57,3 +61,106 @@ func (req policiesReq) validate() error {
 
 	return nil
 }
+
+type policyTripleReq struct {
+	Subject   string `json:"subject"`
+	Object    string `json:"object"`
+	Relation  string `json:"relation"`
+	Namespace string `json:"namespace,omitempty"`
+	TTL       string `json:"ttl,omitempty"`
+}
+
+// ttl returns the policy lifetime, which is zero if the TTL is not set.
+func (req policyTripleReq) ttl() (time.Duration, error) {
+	if req.TTL == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(req.TTL)
+}
+
+// createPoliciesReq accepts either the policies applied to every subject on
+// the object, or the explicit (subject, relation, object) triples.
+type createPoliciesReq struct {
+	policiesReq
+	Triples []policyTripleReq `json:"triples"`
+}
+
+func (req createPoliciesReq) validate() error {
+	if len(req.Triples) == 0 {
+		return req.policiesReq.validate()
+	}
+
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if len(req.SubjectIDs) > 0 || len(req.Policies) > 0 || req.Object != "" {
+		return errors.Wrap(auth.ErrMalformedEntity, fmt.Errorf("triples can't be combined with subjects, policies and object"))
+	}
+
+	for i, t := range req.Triples {
+		switch {
+		case t.Subject == "":
+			return invalidTriple(i, "missing subject")
+		case t.Object == "":
+			return invalidTriple(i, "missing object")
+		}
+		if _, ok := actions[t.Relation]; !ok {
+			return invalidTriple(i, fmt.Sprintf("invalid relation '%s'", t.Relation))
+		}
+		if !auth.ValidNamespace(t.Namespace) {
+			return invalidTriple(i, fmt.Sprintf("invalid namespace '%s'", t.Namespace))
+		}
+		if ttl, err := t.ttl(); err != nil || ttl < 0 {
+			return invalidTriple(i, fmt.Sprintf("invalid ttl '%s'", t.TTL))
+		}
+	}
+
+	return nil
+}
+
+// invalidTriple identifies the triple failing the validation by its index
+// in the request.
+func invalidTriple(i int, reason string) error {
+	return errors.Wrap(auth.ErrMalformedEntity, fmt.Errorf("policy %d: %s", i, reason))
+}
+
+type inspectPoliciesReq struct {
+	token     string
+	subject   string
+	object    string
+	relation  string
+	namespace string
+}
+
+func (req inspectPoliciesReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if !auth.ValidNamespace(req.namespace) {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type explainPolicyReq struct {
+	token     string
+	Subject   string `json:"subject"`
+	Object    string `json:"object"`
+	Relation  string `json:"relation"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (req explainPolicyReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.Subject == "" || req.Object == "" || req.Relation == "" || !auth.ValidNamespace(req.Namespace) {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
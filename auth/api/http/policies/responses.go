@@ -22,6 +22,37 @@ func (res createPolicyRes) Empty() bool {
 	return false
 }
 
+type policyResultRes struct {
+	Subject  string `json:"subject"`
+	Object   string `json:"object"`
+	Relation string `json:"relation"`
+	Error    string `json:"error,omitempty"`
+}
+
+type createPolicyTriplesRes struct {
+	Policies []policyResultRes `json:"policies"`
+}
+
+// Code returns 207 Multi-Status if some of the policies failed, so that
+// the client inspects the per-policy results.
+func (res createPolicyTriplesRes) Code() int {
+	for _, p := range res.Policies {
+		if p.Error != "" {
+			return http.StatusMultiStatus
+		}
+	}
+
+	return http.StatusCreated
+}
+
+func (res createPolicyTriplesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res createPolicyTriplesRes) Empty() bool {
+	return false
+}
+
 type deletePoliciesRes struct {
 	deleted bool
 }
@@ -42,6 +73,48 @@ func (res deletePoliciesRes) Empty() bool {
 	return false
 }
 
+type policyRes struct {
+	Subject   string `json:"subject"`
+	Object    string `json:"object"`
+	Relation  string `json:"relation"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type inspectPoliciesRes struct {
+	Policies []policyRes `json:"policies"`
+}
+
+func (res inspectPoliciesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res inspectPoliciesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res inspectPoliciesRes) Empty() bool {
+	return false
+}
+
+type explainPolicyRes struct {
+	Allowed  bool        `json:"allowed"`
+	Reason   string      `json:"reason"`
+	Path     []policyRes `json:"path"`
+	Expanded []string    `json:"expanded"`
+}
+
+func (res explainPolicyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res explainPolicyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res explainPolicyRes) Empty() bool {
+	return false
+}
+
 type errorRes struct {
 	Err string `json:"error"`
 }
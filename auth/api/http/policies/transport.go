@@ -3,6 +3,7 @@ package policies
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -12,23 +13,41 @@ import (
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/opentracing/opentracing-go"
 )
 
 var errUnsupportedContentType = errors.New("unsupported content type")
 
-const contentType = "application/json"
+const (
+	contentType = "application/json"
+
+	subjectKey   = "subject"
+	objectKey    = "object"
+	relationKey  = "relation"
+	namespaceKey = "namespace"
+)
 
 // MakeHandler returns a HTTP handler for API endpoints.
 func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
 	}
 
 	mux.Post("/policies", kithttp.NewServer(
 		kitot.TraceServer(tracer, "create_policy_bulk")(createPolicyEndpoint(svc)),
-		decodePoliciesRequest,
+		decodeCreatePoliciesRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/policies", kithttp.NewServer(
+		kitot.TraceServer(tracer, "inspect_policies")(inspectPoliciesEndpoint(svc)),
+		decodeInspectPoliciesRequest,
 		encodeResponse,
 		opts...,
 	))
@@ -40,9 +59,30 @@ func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bo
 		opts...,
 	))
 
+	mux.Post("/authz/explain", kithttp.NewServer(
+		kitot.TraceServer(tracer, "explain_policy")(explainPolicyEndpoint(svc)),
+		decodeExplainPolicyRequest,
+		encodeResponse,
+		opts...,
+	))
+
 	return mux
 }
 
+func decodeCreatePoliciesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, auth.ErrUnsupportedContentType
+	}
+
+	var req createPoliciesReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrFailedDecode, err)
+	}
+
+	req.token = r.Header.Get("Authorization")
+	return req, nil
+}
+
 func decodePoliciesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, auth.ErrUnsupportedContentType
@@ -57,6 +97,51 @@ func decodePoliciesRequest(ctx context.Context, r *http.Request) (interface{}, e
 	return req, nil
 }
 
+func decodeExplainPolicyRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, auth.ErrUnsupportedContentType
+	}
+
+	var req explainPolicyReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrFailedDecode, err)
+	}
+
+	req.token = r.Header.Get("Authorization")
+	return req, nil
+}
+
+func decodeInspectPoliciesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	s, err := httputil.ReadStringQuery(r, subjectKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := httputil.ReadStringQuery(r, objectKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := httputil.ReadStringQuery(r, relationKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ns, err := httputil.ReadStringQuery(r, namespaceKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := inspectPoliciesReq{
+		token:     r.Header.Get("Authorization"),
+		subject:   s,
+		object:    o,
+		relation:  rel,
+		namespace: ns,
+	}
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	w.Header().Set("Content-Type", contentType)
 
@@ -75,7 +160,7 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	switch {
 	case errors.Contains(err, auth.ErrMalformedEntity):
 		w.WriteHeader(http.StatusBadRequest)
@@ -93,6 +178,8 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, io.ErrUnexpectedEOF):
 		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrInvalidQueryParams):
+		w.WriteHeader(http.StatusBadRequest)
 	case errors.Contains(err, errUnsupportedContentType):
 		w.WriteHeader(http.StatusUnsupportedMediaType)
 	default:
@@ -100,7 +187,12 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	}
 	errorVal, ok := err.(errors.Error)
 	if ok {
-		if err := json.NewEncoder(w).Encode(errorRes{Err: errorVal.Msg()}); err != nil {
+		msg := i18n.Localize(ctx, errorVal.Msg())
+		// Malformed policies identify the offending policy.
+		if errors.Contains(errorVal, auth.ErrMalformedEntity) && errorVal.Err() != nil {
+			msg = fmt.Sprintf("%s: %s", msg, errorVal.Err())
+		}
+		if err := json.NewEncoder(w).Encode(errorRes{Err: msg}); err != nil {
 			w.Header().Set("Content-Type", contentType)
 			w.WriteHeader(http.StatusInternalServerError)
 		}
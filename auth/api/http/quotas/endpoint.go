@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package quotas
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/auth"
+)
+
+func setQuotaEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(setQuotaReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		q := auth.Quota{
+			OrgID:       req.orgID,
+			MaxUsers:    req.MaxUsers,
+			MaxThings:   req.MaxThings,
+			MaxChannels: req.MaxChannels,
+			MessageRate: req.MessageRate,
+		}
+		if err := svc.SetQuota(ctx, req.token, q); err != nil {
+			return nil, err
+		}
+
+		return setQuotaRes{}, nil
+	}
+}
+
+func viewQuotaEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewQuotaReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		q, u, err := svc.ViewQuota(ctx, req.token, req.orgID)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewQuotaRes{
+			OrgID: q.OrgID,
+			Quota: quotaRes{
+				MaxUsers:    q.MaxUsers,
+				MaxThings:   q.MaxThings,
+				MaxChannels: q.MaxChannels,
+				MessageRate: q.MessageRate,
+				UpdatedAt:   q.UpdatedAt,
+			},
+			Usage: usageRes{
+				Users:    u.Users,
+				Things:   u.Things,
+				Channels: u.Channels,
+			},
+		}
+
+		return res, nil
+	}
+}
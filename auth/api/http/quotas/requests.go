@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package quotas
+
+import "github.com/mainflux/mainflux/auth"
+
+type setQuotaReq struct {
+	token       string
+	orgID       string
+	MaxUsers    uint64  `json:"max_users"`
+	MaxThings   uint64  `json:"max_things"`
+	MaxChannels uint64  `json:"max_channels"`
+	MessageRate float64 `json:"message_rate"`
+}
+
+func (req setQuotaReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.orgID == "" || req.MessageRate < 0 {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewQuotaReq struct {
+	token string
+	orgID string
+}
+
+func (req viewQuotaReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.orgID == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package quotas
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*setQuotaRes)(nil)
+	_ mainflux.Response = (*viewQuotaRes)(nil)
+)
+
+type setQuotaRes struct{}
+
+func (res setQuotaRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res setQuotaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res setQuotaRes) Empty() bool {
+	return true
+}
+
+type quotaRes struct {
+	MaxUsers    uint64    `json:"max_users"`
+	MaxThings   uint64    `json:"max_things"`
+	MaxChannels uint64    `json:"max_channels"`
+	MessageRate float64   `json:"message_rate"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type usageRes struct {
+	Users    uint64 `json:"users"`
+	Things   uint64 `json:"things"`
+	Channels uint64 `json:"channels"`
+}
+
+type viewQuotaRes struct {
+	OrgID string   `json:"org_id"`
+	Quota quotaRes `json:"quota"`
+	Usage usageRes `json:"usage"`
+}
+
+func (res viewQuotaRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewQuotaRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewQuotaRes) Empty() bool {
+	return false
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
@@ -0,0 +1,155 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package roles
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/auth"
+)
+
+func createRoleEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createRoleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		r, err := svc.CreateRole(ctx, req.token, auth.Role{Name: req.Name, Relations: req.Relations})
+		if err != nil {
+			return nil, err
+		}
+
+		return roleRes{toViewRoleRes(r)}, nil
+	}
+}
+
+func listRolesEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRolesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		roles, err := svc.ListRoles(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listRolesRes{Roles: []viewRoleRes{}}
+		for _, r := range roles {
+			res.Roles = append(res.Roles, toViewRoleRes(r))
+		}
+
+		return res, nil
+	}
+}
+
+func removeRoleEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(roleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveRole(ctx, req.token, req.name); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func assignRoleEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(assignmentReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ra := auth.RoleAssignment{
+			Role:    req.role,
+			Subject: req.Subject,
+			Object:  req.Object,
+		}
+		saved, err := svc.AssignRole(ctx, req.token, ra)
+		if err != nil {
+			return nil, err
+		}
+
+		return assignmentRes{toViewAssignmentRes(saved)}, nil
+	}
+}
+
+func listAssignmentsEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listAssignmentsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ra := auth.RoleAssignment{
+			Role:    req.role,
+			Subject: req.subject,
+			Object:  req.object,
+		}
+		assignments, err := svc.ListRoleAssignments(ctx, req.token, ra)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listAssignmentsRes{Assignments: []viewAssignmentRes{}}
+		for _, a := range assignments {
+			res.Assignments = append(res.Assignments, toViewAssignmentRes(a))
+		}
+
+		return res, nil
+	}
+}
+
+func unassignRoleEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(assignmentReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		ra := auth.RoleAssignment{
+			Role:    req.role,
+			Subject: req.Subject,
+			Object:  req.Object,
+		}
+		if err := svc.UnassignRole(ctx, req.token, ra); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func toViewRoleRes(r auth.Role) viewRoleRes {
+	return viewRoleRes{
+		Name:      r.Name,
+		Relations: r.Relations,
+		Builtin:   r.Builtin,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func toViewAssignmentRes(ra auth.RoleAssignment) viewAssignmentRes {
+	relations := ra.Relations
+	if relations == nil {
+		relations = []string{}
+	}
+
+	return viewAssignmentRes{
+		Role:       ra.Role,
+		Subject:    ra.Subject,
+		Object:     ra.Object,
+		Relations:  relations,
+		AssignedBy: ra.AssignedBy,
+		CreatedAt:  ra.CreatedAt,
+	}
+}
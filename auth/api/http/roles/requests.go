@@ -0,0 +1,102 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package roles
+
+import "github.com/mainflux/mainflux/auth"
+
+// relations contains the relations the custom roles consist of.
+var relations = map[string]bool{
+	"read":   true,
+	"write":  true,
+	"delete": true,
+	"access": true,
+	"member": true,
+}
+
+type createRoleReq struct {
+	token     string
+	Name      string   `json:"name"`
+	Relations []string `json:"relations"`
+}
+
+func (req createRoleReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.Name == "" || len(req.Relations) == 0 {
+		return auth.ErrMalformedEntity
+	}
+
+	for _, rel := range req.Relations {
+		if !relations[rel] {
+			return auth.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type listRolesReq struct {
+	token string
+}
+
+func (req listRolesReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type roleReq struct {
+	token string
+	name  string
+}
+
+func (req roleReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.name == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type assignmentReq struct {
+	token   string
+	role    string
+	Subject string `json:"subject"`
+	Object  string `json:"object"`
+}
+
+func (req assignmentReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.role == "" || req.Subject == "" || req.Object == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listAssignmentsReq struct {
+	token   string
+	role    string
+	subject string
+	object  string
+}
+
+func (req listAssignmentsReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package roles
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*roleRes)(nil)
+	_ mainflux.Response = (*listRolesRes)(nil)
+	_ mainflux.Response = (*assignmentRes)(nil)
+	_ mainflux.Response = (*listAssignmentsRes)(nil)
+	_ mainflux.Response = (*removeRes)(nil)
+)
+
+type viewRoleRes struct {
+	Name      string    `json:"name"`
+	Relations []string  `json:"relations"`
+	Builtin   bool      `json:"builtin"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+type roleRes struct {
+	viewRoleRes
+}
+
+func (res roleRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res roleRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/roles/%s", res.Name),
+	}
+}
+
+func (res roleRes) Empty() bool {
+	return false
+}
+
+type listRolesRes struct {
+	Roles []viewRoleRes `json:"roles"`
+}
+
+func (res listRolesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listRolesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listRolesRes) Empty() bool {
+	return false
+}
+
+type viewAssignmentRes struct {
+	Role       string    `json:"role"`
+	Subject    string    `json:"subject"`
+	Object     string    `json:"object"`
+	Relations  []string  `json:"relations"`
+	AssignedBy string    `json:"assigned_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type assignmentRes struct {
+	viewAssignmentRes
+}
+
+func (res assignmentRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res assignmentRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res assignmentRes) Empty() bool {
+	return false
+}
+
+type listAssignmentsRes struct {
+	Assignments []viewAssignmentRes `json:"assignments"`
+}
+
+func (res listAssignmentsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listAssignmentsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listAssignmentsRes) Empty() bool {
+	return false
+}
+
+type removeRes struct{}
+
+func (res removeRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removeRes) Empty() bool {
+	return true
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
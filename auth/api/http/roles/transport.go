@@ -0,0 +1,221 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package roles
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/opentracing/opentracing-go"
+)
+
+const (
+	contentType = "application/json"
+	roleKey     = "role"
+	subjectKey  = "subject"
+	objectKey   = "object"
+)
+
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
+	}
+
+	mux.Post("/roles", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_role")(createRoleEndpoint(svc)),
+		decodeCreateRoleRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/roles", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_roles")(listRolesEndpoint(svc)),
+		decodeListRolesRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/roles/assignments", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_role_assignments")(listAssignmentsEndpoint(svc)),
+		decodeListAssignmentsRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/roles/:name", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_role")(removeRoleEndpoint(svc)),
+		decodeRoleRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/roles/:name/assignments", kithttp.NewServer(
+		kitot.TraceServer(tracer, "assign_role")(assignRoleEndpoint(svc)),
+		decodeAssignRoleRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/roles/:name/assignments", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unassign_role")(unassignRoleEndpoint(svc)),
+		decodeUnassignRoleRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	return mux
+}
+
+func decodeCreateRoleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := createRoleReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeListRolesRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listRolesReq{token: r.Header.Get("Authorization")}
+	return req, nil
+}
+
+func decodeRoleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := roleReq{
+		token: r.Header.Get("Authorization"),
+		name:  bone.GetValue(r, "name"),
+	}
+
+	return req, nil
+}
+
+func decodeAssignRoleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errUnsupportedContentType
+	}
+
+	req := assignmentReq{
+		token: r.Header.Get("Authorization"),
+		role:  bone.GetValue(r, "name"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(auth.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeUnassignRoleRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	subject, err := httputil.ReadStringQuery(r, subjectKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := httputil.ReadStringQuery(r, objectKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := assignmentReq{
+		token:   r.Header.Get("Authorization"),
+		role:    bone.GetValue(r, "name"),
+		Subject: subject,
+		Object:  object,
+	}
+
+	return req, nil
+}
+
+func decodeListAssignmentsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	role, err := httputil.ReadStringQuery(r, roleKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := httputil.ReadStringQuery(r, subjectKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := httputil.ReadStringQuery(r, objectKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := listAssignmentsReq{
+		token:   r.Header.Get("Authorization"),
+		role:    role,
+		subject: subject,
+		object:  object,
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	switch {
+	case errors.Contains(err, auth.ErrMalformedEntity),
+		errors.Contains(err, errors.ErrInvalidQueryParams),
+		errors.Contains(err, io.EOF),
+		errors.Contains(err, io.ErrUnexpectedEOF):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, auth.ErrUnauthorizedAccess),
+		errors.Contains(err, auth.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, auth.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, auth.ErrConflict):
+		w.WriteHeader(http.StatusConflict)
+	case errors.Contains(err, errUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	errorVal, ok := err.(errors.Error)
+	if ok {
+		w.Header().Set("Content-Type", contentType)
+		if err := json.NewEncoder(w).Encode(errorRes{Err: i18n.Localize(ctx, errorVal.Msg())}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
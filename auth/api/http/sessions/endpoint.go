@@ -0,0 +1,71 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sessions
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/auth"
+)
+
+func listSessionsEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(sessionsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		sessions, err := svc.ListSessions(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listSessionsRes{Sessions: []viewSessionRes{}}
+		for _, s := range sessions {
+			res.Sessions = append(res.Sessions, viewSessionRes{
+				ID:         s.ID,
+				Device:     s.Device,
+				IP:         s.IP,
+				IssuedAt:   s.IssuedAt,
+				LastSeenAt: s.LastSeenAt,
+				ExpiresAt:  s.ExpiresAt,
+				Current:    s.Current,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func revokeSessionEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(sessionIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeSession(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return revokeSessionRes{}, nil
+	}
+}
+
+func revokeOtherSessionsEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(sessionsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		n, err := svc.RevokeOtherSessions(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		return revokeSessionsRes{Revoked: n}, nil
+	}
+}
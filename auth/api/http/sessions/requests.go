@@ -0,0 +1,35 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sessions
+
+import "github.com/mainflux/mainflux/auth"
+
+type sessionsReq struct {
+	token string
+}
+
+func (req sessionsReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type sessionIDReq struct {
+	token string
+	id    string
+}
+
+func (req sessionIDReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
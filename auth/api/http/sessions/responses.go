@@ -0,0 +1,77 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sessions
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*listSessionsRes)(nil)
+	_ mainflux.Response = (*revokeSessionRes)(nil)
+	_ mainflux.Response = (*revokeSessionsRes)(nil)
+)
+
+type viewSessionRes struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	IssuedAt   time.Time `json:"issued_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+type listSessionsRes struct {
+	Sessions []viewSessionRes `json:"sessions"`
+}
+
+func (res listSessionsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listSessionsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listSessionsRes) Empty() bool {
+	return false
+}
+
+type revokeSessionRes struct{}
+
+func (res revokeSessionRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res revokeSessionRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res revokeSessionRes) Empty() bool {
+	return true
+}
+
+type revokeSessionsRes struct {
+	Revoked uint64 `json:"revoked"`
+}
+
+func (res revokeSessionsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res revokeSessionsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res revokeSessionsRes) Empty() bool {
+	return false
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
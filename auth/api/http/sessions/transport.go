@@ -0,0 +1,109 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/opentracing/opentracing-go"
+)
+
+const contentType = "application/json"
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(svc auth.Service, mux *bone.Mux, tracer opentracing.Tracer) *bone.Mux {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
+	}
+
+	mux.Get("/sessions", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_sessions")(listSessionsEndpoint(svc)),
+		decodeSessionsRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/sessions", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_other_sessions")(revokeOtherSessionsEndpoint(svc)),
+		decodeSessionsRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/sessions/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_session")(revokeSessionEndpoint(svc)),
+		decodeSessionIDRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	return mux
+}
+
+func decodeSessionsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := sessionsReq{token: r.Header.Get("Authorization")}
+
+	return req, nil
+}
+
+func decodeSessionIDRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := sessionIDReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
+	switch {
+	case errors.Contains(err, auth.ErrMalformedEntity):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, auth.ErrUnauthorizedAccess),
+		errors.Contains(err, auth.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, auth.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	errorVal, ok := err.(errors.Error)
+	if ok {
+		w.Header().Set("Content-Type", contentType)
+		if err := json.NewEncoder(w).Encode(errorRes{Err: i18n.Localize(ctx, errorVal.Msg())}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shares
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/auth"
+)
+
+func shareEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(shareReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		s := auth.Share{
+			GranteeID: req.GranteeID,
+			Object:    req.Object,
+			Actions:   req.Actions,
+		}
+		saved, err := svc.Share(ctx, req.token, s)
+		if err != nil {
+			return nil, err
+		}
+
+		return shareRes{toViewShareRes(saved), true}, nil
+	}
+}
+
+func listSharesEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listSharesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		shares, err := svc.ListShares(ctx, req.token, req.received)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listSharesRes{Shares: []viewShareRes{}}
+		for _, s := range shares {
+			res.Shares = append(res.Shares, toViewShareRes(s))
+		}
+
+		return res, nil
+	}
+}
+
+func revokeShareEndpoint(svc auth.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(shareIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RevokeShare(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return revokeShareRes{}, nil
+	}
+}
+
+func toViewShareRes(s auth.Share) viewShareRes {
+	return viewShareRes{
+		ID:        s.ID,
+		GrantorID: s.GrantorID,
+		GranteeID: s.GranteeID,
+		Object:    s.Object,
+		Actions:   s.Actions,
+		CreatedAt: s.CreatedAt,
+	}
+}
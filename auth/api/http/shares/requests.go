@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shares
+
+import "github.com/mainflux/mainflux/auth"
+
+// actions contains the actions on the things and channels that can be
+// shared with other users.
+var actions = map[string]bool{
+	"read":   true,
+	"write":  true,
+	"delete": true,
+}
+
+type shareReq struct {
+	token     string
+	GranteeID string   `json:"grantee_id"`
+	Object    string   `json:"object"`
+	Actions   []string `json:"actions"`
+}
+
+func (req shareReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.GranteeID == "" || req.Object == "" || len(req.Actions) == 0 {
+		return auth.ErrMalformedEntity
+	}
+
+	for _, action := range req.Actions {
+		if !actions[action] {
+			return auth.ErrMalformedEntity
+		}
+	}
+
+	return nil
+}
+
+type listSharesReq struct {
+	token    string
+	received bool
+}
+
+func (req listSharesReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
+
+type shareIDReq struct {
+	token string
+	id    string
+}
+
+func (req shareIDReq) validate() error {
+	if req.token == "" {
+		return auth.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return auth.ErrMalformedEntity
+	}
+
+	return nil
+}
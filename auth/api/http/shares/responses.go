@@ -0,0 +1,88 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package shares
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var (
+	_ mainflux.Response = (*shareRes)(nil)
+	_ mainflux.Response = (*listSharesRes)(nil)
+	_ mainflux.Response = (*revokeShareRes)(nil)
+)
+
+type viewShareRes struct {
+	ID        string    `json:"id"`
+	GrantorID string    `json:"grantor_id"`
+	GranteeID string    `json:"grantee_id"`
+	Object    string    `json:"object"`
+	Actions   []string  `json:"actions"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type shareRes struct {
+	viewShareRes
+	created bool
+}
+
+func (res shareRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res shareRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/shares/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res shareRes) Empty() bool {
+	return false
+}
+
+type listSharesRes struct {
+	Shares []viewShareRes `json:"shares"`
+}
+
+func (res listSharesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listSharesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listSharesRes) Empty() bool {
+	return false
+}
+
+type revokeShareRes struct{}
+
+func (res revokeShareRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res revokeShareRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res revokeShareRes) Empty() bool {
+	return true
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
@@ -3,23 +3,36 @@
 package http
 
 import (
-	"net/http"
-
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/auth/api/http/accounts"
+	"github.com/mainflux/mainflux/auth/api/http/audit"
 	"github.com/mainflux/mainflux/auth/api/http/groups"
 	"github.com/mainflux/mainflux/auth/api/http/keys"
+	"github.com/mainflux/mainflux/auth/api/http/orgs"
 	"github.com/mainflux/mainflux/auth/api/http/policies"
+	"github.com/mainflux/mainflux/auth/api/http/quotas"
+	"github.com/mainflux/mainflux/auth/api/http/roles"
+	"github.com/mainflux/mainflux/auth/api/http/sessions"
+	"github.com/mainflux/mainflux/auth/api/http/shares"
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-func MakeHandler(svc auth.Service, tracer opentracing.Tracer) http.Handler {
+// MakeHandler returns a HTTP handler for the auth service API endpoints.
+func MakeHandler(svc auth.Service, tracer opentracing.Tracer) *bone.Mux {
 	mux := bone.New()
 	mux = keys.MakeHandler(svc, mux, tracer)
 	mux = groups.MakeHandler(svc, mux, tracer)
+	mux = orgs.MakeHandler(svc, mux, tracer)
 	mux = policies.MakeHandler(svc, mux, tracer)
+	mux = shares.MakeHandler(svc, mux, tracer)
+	mux = roles.MakeHandler(svc, mux, tracer)
+	mux = accounts.MakeHandler(svc, mux, tracer)
+	mux = quotas.MakeHandler(svc, mux, tracer)
+	mux = audit.MakeHandler(svc, mux, tracer)
+	mux = sessions.MakeHandler(svc, mux, tracer)
 	mux.GetFunc("/version", mainflux.Version("auth"))
 	mux.Handle("/metrics", promhttp.Handler())
 	return mux
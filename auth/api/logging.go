@@ -39,6 +39,32 @@ func (lm *loggingMiddleware) ListPolicies(ctx context.Context, pr auth.PolicyReq
 	return lm.svc.ListPolicies(ctx, pr)
 }
 
+func (lm *loggingMiddleware) InspectPolicies(ctx context.Context, token string, pr auth.PolicyReq) (p []auth.PolicyReq, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method inspect_policies took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.InspectPolicies(ctx, token, pr)
+}
+
+func (lm *loggingMiddleware) ExplainPolicy(ctx context.Context, token string, pr auth.PolicyReq) (e auth.Explanation, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method explain_policy for subject %s, object %s and relation %s took %s to complete", pr.Subject, pr.Object, pr.Relation, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExplainPolicy(ctx, token, pr)
+}
+
 func (lm *loggingMiddleware) Issue(ctx context.Context, token string, newKey auth.Key) (key auth.Key, secret string, err error) {
 	defer func(begin time.Time) {
 		d := "infinite duration"
@@ -82,6 +108,32 @@ func (lm *loggingMiddleware) RetrieveKey(ctx context.Context, token, id string)
 	return lm.svc.RetrieveKey(ctx, token, id)
 }
 
+func (lm *loggingMiddleware) ListKeys(ctx context.Context, token string, offset, limit uint64) (page auth.KeysPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_keys took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListKeys(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) LabelKey(ctx context.Context, token, id, label string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method label_key for key %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.LabelKey(ctx, token, id, label)
+}
+
 func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id auth.Identity, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method identify took %s to complete", time.Since(begin))
@@ -95,6 +147,71 @@ func (lm *loggingMiddleware) Identify(ctx context.Context, key string) (id auth.
 	return lm.svc.Identify(ctx, key)
 }
 
+func (lm *loggingMiddleware) RevokeToken(ctx context.Context, token, revoked string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_token took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeToken(ctx, token, revoked)
+}
+
+func (lm *loggingMiddleware) Introspect(ctx context.Context, token, introspected string) (in auth.Introspection, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method introspect took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Introspect(ctx, token, introspected)
+}
+
+func (lm *loggingMiddleware) Refresh(ctx context.Context, refreshToken string) (token, refresh string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method refresh took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Refresh(ctx, refreshToken)
+}
+
+func (lm *loggingMiddleware) RevokeKeys(ctx context.Context, token, subject string) (count uint64, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_keys for subject %s took %s to complete", subject, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeKeys(ctx, token, subject)
+}
+
+func (lm *loggingMiddleware) PublicKeys(ctx context.Context) (keys []auth.PublicKey, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method public_keys took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PublicKeys(ctx)
+}
+
 func (lm *loggingMiddleware) Authorize(ctx context.Context, pr auth.PolicyReq) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method authorize took %s to complete", time.Since(begin))
@@ -132,6 +249,32 @@ func (lm *loggingMiddleware) AddPolicies(ctx context.Context, token, object stri
 	return lm.svc.AddPolicies(ctx, token, object, subjectIDs, relations)
 }
 
+func (lm *loggingMiddleware) AddPolicyTriples(ctx context.Context, token string, prs []auth.PolicyReq) (res []auth.PolicyResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method add_policy_triples took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AddPolicyTriples(ctx, token, prs)
+}
+
+func (lm *loggingMiddleware) ApplyPolicyTemplate(ctx context.Context, e auth.Entity) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method apply_policy_template for %s %s took %s to complete", e.Type, e.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ApplyPolicyTemplate(ctx, e)
+}
+
 func (lm *loggingMiddleware) DeletePolicy(ctx context.Context, pr auth.PolicyReq) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method delete_policy took %s to complete", time.Since(begin))
@@ -299,6 +442,45 @@ func (lm *loggingMiddleware) Unassign(ctx context.Context, token string, groupID
 	return lm.svc.Unassign(ctx, token, groupID, memberIDs...)
 }
 
+func (lm *loggingMiddleware) AssignTemporary(ctx context.Context, token, groupID, groupType string, expiresAt time.Time, memberIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method assign_temporary for token %s and member %s group id %s until %s took %s to complete", token, memberIDs, groupID, expiresAt, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AssignTemporary(ctx, token, groupID, groupType, expiresAt, memberIDs...)
+}
+
+func (lm *loggingMiddleware) SetRelation(ctx context.Context, token, groupID, relation string, memberIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method set_relation for token %s and member %s group id %s relation %s took %s to complete", token, memberIDs, groupID, relation, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SetRelation(ctx, token, groupID, relation, memberIDs...)
+}
+
+func (lm *loggingMiddleware) RevokeExpiredMemberships(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_expired_memberships took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Debug(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeExpiredMemberships(ctx)
+}
+
 func (lm *loggingMiddleware) AssignGroupAccessRights(ctx context.Context, token, thingGroupID, userGroupID string) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method share_group_access took %s to complete", time.Since(begin))
@@ -311,3 +493,510 @@ func (lm *loggingMiddleware) AssignGroupAccessRights(ctx context.Context, token,
 
 	return lm.svc.AssignGroupAccessRights(ctx, token, thingGroupID, userGroupID)
 }
+
+func (lm *loggingMiddleware) DelegateGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method delegate_group_admin for group %s and user %s took %s to complete", groupID, userID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DelegateGroupAdmin(ctx, token, groupID, userID, relations...)
+}
+
+func (lm *loggingMiddleware) RevokeGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_group_admin for group %s and user %s took %s to complete", groupID, userID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeGroupAdmin(ctx, token, groupID, userID, relations...)
+}
+
+func (lm *loggingMiddleware) ListGroupAdmins(ctx context.Context, token, groupID string) (admins []auth.PolicyReq, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_group_admins for group %s took %s to complete", groupID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListGroupAdmins(ctx, token, groupID)
+}
+
+func (lm *loggingMiddleware) Share(ctx context.Context, token string, s auth.Share) (share auth.Share, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method share for object %s and grantee %s took %s to complete", s.Object, s.GranteeID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Share(ctx, token, s)
+}
+
+func (lm *loggingMiddleware) ListShares(ctx context.Context, token string, received bool) (shares []auth.Share, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_shares took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListShares(ctx, token, received)
+}
+
+func (lm *loggingMiddleware) RevokeShare(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_share for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeShare(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) CreateRole(ctx context.Context, token string, r auth.Role) (role auth.Role, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_role for role %s took %s to complete", r.Name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateRole(ctx, token, r)
+}
+
+func (lm *loggingMiddleware) ListRoles(ctx context.Context, token string) (roles []auth.Role, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_roles took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListRoles(ctx, token)
+}
+
+func (lm *loggingMiddleware) RemoveRole(ctx context.Context, token, name string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_role for role %s took %s to complete", name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveRole(ctx, token, name)
+}
+
+func (lm *loggingMiddleware) AssignRole(ctx context.Context, token string, ra auth.RoleAssignment) (assignment auth.RoleAssignment, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method assign_role for role %s, subject %s and object %s took %s to complete", ra.Role, ra.Subject, ra.Object, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AssignRole(ctx, token, ra)
+}
+
+func (lm *loggingMiddleware) ListRoleAssignments(ctx context.Context, token string, ra auth.RoleAssignment) (assignments []auth.RoleAssignment, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_role_assignments took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListRoleAssignments(ctx, token, ra)
+}
+
+func (lm *loggingMiddleware) UnassignRole(ctx context.Context, token string, ra auth.RoleAssignment) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unassign_role for role %s, subject %s and object %s took %s to complete", ra.Role, ra.Subject, ra.Object, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnassignRole(ctx, token, ra)
+}
+
+func (lm *loggingMiddleware) CreateServiceAccount(ctx context.Context, token string, sa auth.ServiceAccount) (account auth.ServiceAccount, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_service_account for owner %s took %s to complete", sa.OwnerID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateServiceAccount(ctx, token, sa)
+}
+
+func (lm *loggingMiddleware) ListServiceAccounts(ctx context.Context, token, ownerID string) (accounts []auth.ServiceAccount, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_service_accounts for owner %s took %s to complete", ownerID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListServiceAccounts(ctx, token, ownerID)
+}
+
+func (lm *loggingMiddleware) ViewServiceAccount(ctx context.Context, token, id string) (account auth.ServiceAccount, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_service_account for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewServiceAccount(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) UpdateServiceAccountScopes(ctx context.Context, token, id string, scopes []string) (account auth.ServiceAccount, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_service_account_scopes for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateServiceAccountScopes(ctx, token, id, scopes)
+}
+
+func (lm *loggingMiddleware) DisableServiceAccount(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method disable_service_account for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DisableServiceAccount(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) EnableServiceAccount(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method enable_service_account for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EnableServiceAccount(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) RemoveServiceAccount(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_service_account for id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveServiceAccount(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) IssueServiceAccountKey(ctx context.Context, token, id string, duration time.Duration) (key auth.Key, secret string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method issue_service_account_key for service account %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.IssueServiceAccountKey(ctx, token, id, duration)
+}
+
+func (lm *loggingMiddleware) RotateServiceAccountKey(ctx context.Context, token, id, keyID string) (key auth.Key, secret string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method rotate_service_account_key for service account %s and key %s took %s to complete", id, keyID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RotateServiceAccountKey(ctx, token, id, keyID)
+}
+
+func (lm *loggingMiddleware) RevokeServiceAccountKey(ctx context.Context, token, id, keyID string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_service_account_key for service account %s and key %s took %s to complete", id, keyID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeServiceAccountKey(ctx, token, id, keyID)
+}
+
+func (lm *loggingMiddleware) SetQuota(ctx context.Context, token string, q auth.Quota) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method set_quota for org %s took %s to complete", q.OrgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SetQuota(ctx, token, q)
+}
+
+func (lm *loggingMiddleware) ViewQuota(ctx context.Context, token, orgID string) (q auth.Quota, u auth.Usage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_quota for org %s took %s to complete", orgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewQuota(ctx, token, orgID)
+}
+
+func (lm *loggingMiddleware) ReserveQuota(ctx context.Context, token, resource string, ids ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method reserve_quota for %d %s took %s to complete", len(ids), resource, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ReserveQuota(ctx, token, resource, ids...)
+}
+
+func (lm *loggingMiddleware) ReleaseQuota(ctx context.Context, resource string, ids ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method release_quota for %d %s took %s to complete", len(ids), resource, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ReleaseQuota(ctx, resource, ids...)
+}
+
+func (lm *loggingMiddleware) ListAuditRecords(ctx context.Context, token string, filter auth.AuditFilter, offset, limit uint64) (page auth.AuditPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_audit_records took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListAuditRecords(ctx, token, filter, offset, limit)
+}
+
+func (lm *loggingMiddleware) ListSessions(ctx context.Context, token string) (sessions []auth.Session, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_sessions took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListSessions(ctx, token)
+}
+
+func (lm *loggingMiddleware) RevokeSession(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_session for session %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeSession(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) RevokeOtherSessions(ctx context.Context, token string) (n uint64, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_other_sessions took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeOtherSessions(ctx, token)
+}
+
+func (lm *loggingMiddleware) CreateOrg(ctx context.Context, token string, o auth.Org) (org auth.Org, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_org for org %s took %s to complete", o.Name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateOrg(ctx, token, o)
+}
+
+func (lm *loggingMiddleware) ViewOrg(ctx context.Context, token, id string) (org auth.Org, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_org for org %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewOrg(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) UpdateOrg(ctx context.Context, token string, o auth.Org) (org auth.Org, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_org for org %s took %s to complete", o.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateOrg(ctx, token, o)
+}
+
+func (lm *loggingMiddleware) RemoveOrg(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_org for org %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveOrg(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListOrgs(ctx context.Context, token string, offset, limit uint64) (page auth.OrgsPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_orgs took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListOrgs(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) AssignOrgMembers(ctx context.Context, token, orgID string, memberIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method assign_org_members for org %s took %s to complete", orgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AssignOrgMembers(ctx, token, orgID, memberIDs...)
+}
+
+func (lm *loggingMiddleware) UnassignOrgMembers(ctx context.Context, token, orgID string, memberIDs ...string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unassign_org_members for org %s took %s to complete", orgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnassignOrgMembers(ctx, token, orgID, memberIDs...)
+}
+
+func (lm *loggingMiddleware) ListOrgMembers(ctx context.Context, token, orgID string, offset, limit uint64) (page auth.OrgMembersPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_org_members for org %s took %s to complete", orgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListOrgMembers(ctx, token, orgID, offset, limit)
+}
+
+func (lm *loggingMiddleware) SwitchOrg(ctx context.Context, token, orgID string) (key auth.Key, secret string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method switch_org to org %s took %s to complete", orgID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SwitchOrg(ctx, token, orgID)
+}
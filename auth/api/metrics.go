@@ -37,6 +37,24 @@ func (ms *metricsMiddleware) ListPolicies(ctx context.Context, pr auth.PolicyReq
 	return ms.svc.ListPolicies(ctx, pr)
 }
 
+func (ms *metricsMiddleware) InspectPolicies(ctx context.Context, token string, pr auth.PolicyReq) ([]auth.PolicyReq, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "inspect_policies").Add(1)
+		ms.latency.With("method", "inspect_policies").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.InspectPolicies(ctx, token, pr)
+}
+
+func (ms *metricsMiddleware) ExplainPolicy(ctx context.Context, token string, pr auth.PolicyReq) (auth.Explanation, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "explain_policy").Add(1)
+		ms.latency.With("method", "explain_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExplainPolicy(ctx, token, pr)
+}
+
 func (ms *metricsMiddleware) Issue(ctx context.Context, token string, key auth.Key) (auth.Key, string, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "issue_key").Add(1)
@@ -64,6 +82,24 @@ func (ms *metricsMiddleware) RetrieveKey(ctx context.Context, token, id string)
 	return ms.svc.RetrieveKey(ctx, token, id)
 }
 
+func (ms *metricsMiddleware) ListKeys(ctx context.Context, token string, offset, limit uint64) (auth.KeysPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_keys").Add(1)
+		ms.latency.With("method", "list_keys").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListKeys(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) LabelKey(ctx context.Context, token, id, label string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "label_key").Add(1)
+		ms.latency.With("method", "label_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.LabelKey(ctx, token, id, label)
+}
+
 func (ms *metricsMiddleware) Identify(ctx context.Context, token string) (auth.Identity, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "identify").Add(1)
@@ -73,6 +109,51 @@ func (ms *metricsMiddleware) Identify(ctx context.Context, token string) (auth.I
 	return ms.svc.Identify(ctx, token)
 }
 
+func (ms *metricsMiddleware) RevokeToken(ctx context.Context, token, revoked string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_token").Add(1)
+		ms.latency.With("method", "revoke_token").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeToken(ctx, token, revoked)
+}
+
+func (ms *metricsMiddleware) Introspect(ctx context.Context, token, introspected string) (auth.Introspection, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "introspect").Add(1)
+		ms.latency.With("method", "introspect").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Introspect(ctx, token, introspected)
+}
+
+func (ms *metricsMiddleware) Refresh(ctx context.Context, refreshToken string) (string, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "refresh").Add(1)
+		ms.latency.With("method", "refresh").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Refresh(ctx, refreshToken)
+}
+
+func (ms *metricsMiddleware) RevokeKeys(ctx context.Context, token, subject string) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_keys").Add(1)
+		ms.latency.With("method", "revoke_keys").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeKeys(ctx, token, subject)
+}
+
+func (ms *metricsMiddleware) PublicKeys(ctx context.Context) ([]auth.PublicKey, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "public_keys").Add(1)
+		ms.latency.With("method", "public_keys").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.PublicKeys(ctx)
+}
+
 func (ms *metricsMiddleware) Authorize(ctx context.Context, pr auth.PolicyReq) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "authorize").Add(1)
@@ -98,6 +179,24 @@ func (ms *metricsMiddleware) AddPolicies(ctx context.Context, token, object stri
 	return ms.svc.AddPolicies(ctx, token, object, subjectIDs, relations)
 }
 
+func (ms *metricsMiddleware) AddPolicyTriples(ctx context.Context, token string, prs []auth.PolicyReq) ([]auth.PolicyResult, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "add_policy_triples").Add(1)
+		ms.latency.With("method", "add_policy_triples").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AddPolicyTriples(ctx, token, prs)
+}
+
+func (ms *metricsMiddleware) ApplyPolicyTemplate(ctx context.Context, e auth.Entity) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "apply_policy_template").Add(1)
+		ms.latency.With("method", "apply_policy_template").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ApplyPolicyTemplate(ctx, e)
+}
+
 func (ms *metricsMiddleware) DeletePolicy(ctx context.Context, pr auth.PolicyReq) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "delete_policy").Add(1)
@@ -210,6 +309,33 @@ func (ms *metricsMiddleware) Unassign(ctx context.Context, token, groupID string
 	return ms.svc.Unassign(ctx, token, groupID, memberIDs...)
 }
 
+func (ms *metricsMiddleware) AssignTemporary(ctx context.Context, token, groupID, groupType string, expiresAt time.Time, memberIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "assign_temporary").Add(1)
+		ms.latency.With("method", "assign_temporary").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AssignTemporary(ctx, token, groupID, groupType, expiresAt, memberIDs...)
+}
+
+func (ms *metricsMiddleware) SetRelation(ctx context.Context, token, groupID, relation string, memberIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "set_relation").Add(1)
+		ms.latency.With("method", "set_relation").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SetRelation(ctx, token, groupID, relation, memberIDs...)
+}
+
+func (ms *metricsMiddleware) RevokeExpiredMemberships(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_expired_memberships").Add(1)
+		ms.latency.With("method", "revoke_expired_memberships").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeExpiredMemberships(ctx)
+}
+
 func (ms *metricsMiddleware) AssignGroupAccessRights(ctx context.Context, token, thingGroupID, userGroupID string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "share_group_access").Add(1)
@@ -218,3 +344,354 @@ func (ms *metricsMiddleware) AssignGroupAccessRights(ctx context.Context, token,
 
 	return ms.svc.AssignGroupAccessRights(ctx, token, thingGroupID, userGroupID)
 }
+
+func (ms *metricsMiddleware) DelegateGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "delegate_group_admin").Add(1)
+		ms.latency.With("method", "delegate_group_admin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DelegateGroupAdmin(ctx, token, groupID, userID, relations...)
+}
+
+func (ms *metricsMiddleware) RevokeGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_group_admin").Add(1)
+		ms.latency.With("method", "revoke_group_admin").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeGroupAdmin(ctx, token, groupID, userID, relations...)
+}
+
+func (ms *metricsMiddleware) ListGroupAdmins(ctx context.Context, token, groupID string) ([]auth.PolicyReq, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_group_admins").Add(1)
+		ms.latency.With("method", "list_group_admins").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListGroupAdmins(ctx, token, groupID)
+}
+
+func (ms *metricsMiddleware) Share(ctx context.Context, token string, s auth.Share) (auth.Share, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "share").Add(1)
+		ms.latency.With("method", "share").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Share(ctx, token, s)
+}
+
+func (ms *metricsMiddleware) ListShares(ctx context.Context, token string, received bool) ([]auth.Share, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_shares").Add(1)
+		ms.latency.With("method", "list_shares").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListShares(ctx, token, received)
+}
+
+func (ms *metricsMiddleware) RevokeShare(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_share").Add(1)
+		ms.latency.With("method", "revoke_share").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeShare(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) CreateRole(ctx context.Context, token string, r auth.Role) (auth.Role, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_role").Add(1)
+		ms.latency.With("method", "create_role").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateRole(ctx, token, r)
+}
+
+func (ms *metricsMiddleware) ListRoles(ctx context.Context, token string) ([]auth.Role, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_roles").Add(1)
+		ms.latency.With("method", "list_roles").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListRoles(ctx, token)
+}
+
+func (ms *metricsMiddleware) RemoveRole(ctx context.Context, token, name string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_role").Add(1)
+		ms.latency.With("method", "remove_role").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveRole(ctx, token, name)
+}
+
+func (ms *metricsMiddleware) AssignRole(ctx context.Context, token string, ra auth.RoleAssignment) (auth.RoleAssignment, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "assign_role").Add(1)
+		ms.latency.With("method", "assign_role").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AssignRole(ctx, token, ra)
+}
+
+func (ms *metricsMiddleware) ListRoleAssignments(ctx context.Context, token string, ra auth.RoleAssignment) ([]auth.RoleAssignment, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_role_assignments").Add(1)
+		ms.latency.With("method", "list_role_assignments").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListRoleAssignments(ctx, token, ra)
+}
+
+func (ms *metricsMiddleware) UnassignRole(ctx context.Context, token string, ra auth.RoleAssignment) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unassign_role").Add(1)
+		ms.latency.With("method", "unassign_role").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnassignRole(ctx, token, ra)
+}
+
+func (ms *metricsMiddleware) CreateServiceAccount(ctx context.Context, token string, sa auth.ServiceAccount) (auth.ServiceAccount, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_service_account").Add(1)
+		ms.latency.With("method", "create_service_account").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateServiceAccount(ctx, token, sa)
+}
+
+func (ms *metricsMiddleware) ListServiceAccounts(ctx context.Context, token, ownerID string) ([]auth.ServiceAccount, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_service_accounts").Add(1)
+		ms.latency.With("method", "list_service_accounts").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListServiceAccounts(ctx, token, ownerID)
+}
+
+func (ms *metricsMiddleware) ViewServiceAccount(ctx context.Context, token, id string) (auth.ServiceAccount, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_service_account").Add(1)
+		ms.latency.With("method", "view_service_account").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewServiceAccount(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) UpdateServiceAccountScopes(ctx context.Context, token, id string, scopes []string) (auth.ServiceAccount, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_service_account_scopes").Add(1)
+		ms.latency.With("method", "update_service_account_scopes").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateServiceAccountScopes(ctx, token, id, scopes)
+}
+
+func (ms *metricsMiddleware) DisableServiceAccount(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "disable_service_account").Add(1)
+		ms.latency.With("method", "disable_service_account").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DisableServiceAccount(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) EnableServiceAccount(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "enable_service_account").Add(1)
+		ms.latency.With("method", "enable_service_account").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnableServiceAccount(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) RemoveServiceAccount(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_service_account").Add(1)
+		ms.latency.With("method", "remove_service_account").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveServiceAccount(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) IssueServiceAccountKey(ctx context.Context, token, id string, duration time.Duration) (auth.Key, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "issue_service_account_key").Add(1)
+		ms.latency.With("method", "issue_service_account_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.IssueServiceAccountKey(ctx, token, id, duration)
+}
+
+func (ms *metricsMiddleware) RotateServiceAccountKey(ctx context.Context, token, id, keyID string) (auth.Key, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "rotate_service_account_key").Add(1)
+		ms.latency.With("method", "rotate_service_account_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RotateServiceAccountKey(ctx, token, id, keyID)
+}
+
+func (ms *metricsMiddleware) RevokeServiceAccountKey(ctx context.Context, token, id, keyID string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_service_account_key").Add(1)
+		ms.latency.With("method", "revoke_service_account_key").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeServiceAccountKey(ctx, token, id, keyID)
+}
+
+func (ms *metricsMiddleware) SetQuota(ctx context.Context, token string, q auth.Quota) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "set_quota").Add(1)
+		ms.latency.With("method", "set_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SetQuota(ctx, token, q)
+}
+
+func (ms *metricsMiddleware) ViewQuota(ctx context.Context, token, orgID string) (auth.Quota, auth.Usage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_quota").Add(1)
+		ms.latency.With("method", "view_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewQuota(ctx, token, orgID)
+}
+
+func (ms *metricsMiddleware) ReserveQuota(ctx context.Context, token, resource string, ids ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "reserve_quota").Add(1)
+		ms.latency.With("method", "reserve_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ReserveQuota(ctx, token, resource, ids...)
+}
+
+func (ms *metricsMiddleware) ReleaseQuota(ctx context.Context, resource string, ids ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "release_quota").Add(1)
+		ms.latency.With("method", "release_quota").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ReleaseQuota(ctx, resource, ids...)
+}
+
+func (ms *metricsMiddleware) ListAuditRecords(ctx context.Context, token string, filter auth.AuditFilter, offset, limit uint64) (auth.AuditPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_audit_records").Add(1)
+		ms.latency.With("method", "list_audit_records").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListAuditRecords(ctx, token, filter, offset, limit)
+}
+
+func (ms *metricsMiddleware) ListSessions(ctx context.Context, token string) ([]auth.Session, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_sessions").Add(1)
+		ms.latency.With("method", "list_sessions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListSessions(ctx, token)
+}
+
+func (ms *metricsMiddleware) RevokeSession(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_session").Add(1)
+		ms.latency.With("method", "revoke_session").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeSession(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) RevokeOtherSessions(ctx context.Context, token string) (uint64, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "revoke_other_sessions").Add(1)
+		ms.latency.With("method", "revoke_other_sessions").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeOtherSessions(ctx, token)
+}
+
+func (ms *metricsMiddleware) CreateOrg(ctx context.Context, token string, o auth.Org) (auth.Org, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_org").Add(1)
+		ms.latency.With("method", "create_org").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateOrg(ctx, token, o)
+}
+
+func (ms *metricsMiddleware) ViewOrg(ctx context.Context, token, id string) (auth.Org, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_org").Add(1)
+		ms.latency.With("method", "view_org").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewOrg(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) UpdateOrg(ctx context.Context, token string, o auth.Org) (auth.Org, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "update_org").Add(1)
+		ms.latency.With("method", "update_org").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateOrg(ctx, token, o)
+}
+
+func (ms *metricsMiddleware) RemoveOrg(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_org").Add(1)
+		ms.latency.With("method", "remove_org").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveOrg(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListOrgs(ctx context.Context, token string, offset, limit uint64) (auth.OrgsPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_orgs").Add(1)
+		ms.latency.With("method", "list_orgs").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListOrgs(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) AssignOrgMembers(ctx context.Context, token, orgID string, memberIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "assign_org_members").Add(1)
+		ms.latency.With("method", "assign_org_members").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AssignOrgMembers(ctx, token, orgID, memberIDs...)
+}
+
+func (ms *metricsMiddleware) UnassignOrgMembers(ctx context.Context, token, orgID string, memberIDs ...string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "unassign_org_members").Add(1)
+		ms.latency.With("method", "unassign_org_members").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnassignOrgMembers(ctx, token, orgID, memberIDs...)
+}
+
+func (ms *metricsMiddleware) ListOrgMembers(ctx context.Context, token, orgID string, offset, limit uint64) (auth.OrgMembersPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_org_members").Add(1)
+		ms.latency.With("method", "list_org_members").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListOrgMembers(ctx, token, orgID, offset, limit)
+}
+
+func (ms *metricsMiddleware) SwitchOrg(ctx context.Context, token, orgID string) (auth.Key, string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "switch_org").Add(1)
+		ms.latency.With("method", "switch_org").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SwitchOrg(ctx, token, orgID)
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/ratelimit"
+)
+
+var _ auth.Service = (*rateLimitMiddleware)(nil)
+
+// rateLimitMiddleware limits the rate of the key issuance, passing the other
+// calls through to the embedded service.
+type rateLimitMiddleware struct {
+	auth.Service
+	limiter *ratelimit.Limiter
+}
+
+// RateLimitMiddleware limits the rate of the keys issued with the token,
+// per client IP address and per account. The client IP address is the one
+// the transport trusts, so it can't be forged to evade the limit. The keys issued without the token,
+// i.e. the login keys, are limited by the service handling the login.
+func RateLimitMiddleware(svc auth.Service, limiter *ratelimit.Limiter) auth.Service {
+	return &rateLimitMiddleware{svc, limiter}
+}
+
+func (rm *rateLimitMiddleware) Issue(ctx context.Context, token string, key auth.Key) (auth.Key, string, error) {
+	if token == "" {
+		return rm.Service.Issue(ctx, token, key)
+	}
+
+	// The invalid token fails the issuance anyway, so it's only limited
+	// per client IP address.
+	var account string
+	if id, err := rm.Service.Identify(ctx, token); err == nil {
+		account = id.ID
+	}
+	if err := rm.limiter.Allow(ctx, auth.ClientIP(ctx), account); err != nil {
+		return auth.Key{}, "", err
+	}
+
+	return rm.Service.Issue(ctx, token, key)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	acl "github.com/ory/keto/proto/ory/keto/acl/v1alpha1"
+)
+
+// Audited policy operations.
+const (
+	CheckOperation  = "check"
+	AddOperation    = "add"
+	DeleteOperation = "delete"
+)
+
+// Decisions of the audited policy operations. The checks are either allowed
+// or denied, while the adds and deletes either succeed or fail. The checks
+// that couldn't be decided, e.g. due to the policy agent being unreachable,
+// are recorded as failed.
+const (
+	AllowedDecision = "allowed"
+	DeniedDecision  = "denied"
+	SuccessDecision = "success"
+	FailureDecision = "failure"
+)
+
+// AuditRecord represents the single policy operation performed by the
+// service.
+type AuditRecord struct {
+	ID        uint64
+	Operation string
+	Subject   string
+	Object    string
+	Relation  string
+	Decision  string
+	CreatedAt time.Time
+}
+
+// AuditFilter represents the criteria of the audit records retrieval. The
+// empty fields match any record.
+type AuditFilter struct {
+	Operation string
+	Subject   string
+	Object    string
+	Relation  string
+	Decision  string
+	From      time.Time
+	To        time.Time
+}
+
+// AuditPage represents the page of the audit records, ordered from the
+// oldest to the newest.
+type AuditPage struct {
+	Total   uint64
+	Offset  uint64
+	Limit   uint64
+	Records []AuditRecord
+}
+
+// Audit specifies an API for querying the authorization audit log.
+type Audit interface {
+	// ListAuditRecords retrieves the audit records matching the filter.
+	// Only the admin is allowed to list the audit records.
+	ListAuditRecords(ctx context.Context, token string, filter AuditFilter, offset, limit uint64) (AuditPage, error)
+}
+
+// AuditRepository specifies the audit log persistence API.
+type AuditRepository interface {
+	// Save appends the record to the audit log.
+	Save(ctx context.Context, r AuditRecord) error
+
+	// RetrieveAll retrieves the records matching the filter.
+	RetrieveAll(ctx context.Context, filter AuditFilter, offset, limit uint64) (AuditPage, error)
+}
+
+var _ PolicyAgent = (*auditAgent)(nil)
+
+// auditAgent records the decisions of the wrapped policy agent. Since the
+// audit log must not make the authorization unavailable, failures to save
+// the records don't fail the policy operations.
+type auditAgent struct {
+	agent PolicyAgent
+	audit AuditRepository
+}
+
+func (aa auditAgent) CheckPolicy(ctx context.Context, pr PolicyReq) error {
+	err := aa.agent.CheckPolicy(ctx, pr)
+	saveAudit(ctx, aa.audit, CheckOperation, pr, checkDecision(err))
+	return err
+}
+
+func (aa auditAgent) AddPolicy(ctx context.Context, pr PolicyReq) error {
+	err := aa.agent.AddPolicy(ctx, pr)
+	saveAudit(ctx, aa.audit, AddOperation, pr, changeDecision(err))
+	return err
+}
+
+func (aa auditAgent) DeletePolicy(ctx context.Context, pr PolicyReq) error {
+	err := aa.agent.DeletePolicy(ctx, pr)
+	saveAudit(ctx, aa.audit, DeleteOperation, pr, changeDecision(err))
+	return err
+}
+
+func (aa auditAgent) RetrievePolicies(ctx context.Context, pr PolicyReq) ([]*acl.RelationTuple, error) {
+	return aa.agent.RetrievePolicies(ctx, pr)
+}
+
+func saveAudit(ctx context.Context, audit AuditRepository, op string, pr PolicyReq, decision string) {
+	r := AuditRecord{
+		Operation: op,
+		Subject:   pr.Subject,
+		Object:    pr.Object,
+		Relation:  pr.Relation,
+		Decision:  decision,
+		CreatedAt: getTimestmap(),
+	}
+	audit.Save(ctx, r)
+}
+
+func checkDecision(err error) string {
+	switch {
+	case err == nil:
+		return AllowedDecision
+	case errors.Contains(err, ErrAuthorization):
+		return DeniedDecision
+	default:
+		return FailureDecision
+	}
+}
+
+func changeDecision(err error) string {
+	if err != nil {
+		return FailureDecision
+	}
+	return SuccessDecision
+}
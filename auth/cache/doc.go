@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cache contains the PolicyAgent decorator caching the policy
+// decisions, and the in-memory and Redis decision stores.
+package cache
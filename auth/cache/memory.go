@@ -0,0 +1,103 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Store = (*memoryStore)(nil)
+
+type entry struct {
+	key     string
+	allowed bool
+	expires time.Time
+}
+
+type memoryStore struct {
+	mu      sync.Mutex
+	gen     uint64
+	size    int
+	ttl     time.Duration
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+// NewMemoryStore returns the in-memory store keeping up to the given number
+// of the least recently used decisions for the given time. The policies
+// changed through the other service instances don't invalidate the store,
+// so it's meant for the single instance deployments.
+func NewMemoryStore(size int, ttl time.Duration) Store {
+	return &memoryStore{
+		size:    size,
+		ttl:     ttl,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (ms *memoryStore) Generation(ctx context.Context) (uint64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return ms.gen, nil
+}
+
+func (ms *memoryStore) Invalidate(ctx context.Context) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.gen++
+	ms.lru.Init()
+	ms.entries = make(map[string]*list.Element)
+	return nil
+}
+
+func (ms *memoryStore) Get(ctx context.Context, gen uint64, key string) (bool, bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	el, ok := ms.entries[key]
+	if gen != ms.gen || !ok {
+		return false, false, nil
+	}
+
+	e := el.Value.(*entry)
+	if time.Now().After(e.expires) {
+		ms.lru.Remove(el)
+		delete(ms.entries, key)
+		return false, false, nil
+	}
+
+	ms.lru.MoveToFront(el)
+	return e.allowed, true, nil
+}
+
+func (ms *memoryStore) Set(ctx context.Context, gen uint64, key string, allowed bool) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	// The decision made before the policies changed is stale.
+	if gen != ms.gen {
+		return nil
+	}
+
+	e := &entry{key: key, allowed: allowed, expires: time.Now().Add(ms.ttl)}
+	if el, ok := ms.entries[key]; ok {
+		el.Value = e
+		ms.lru.MoveToFront(el)
+		return nil
+	}
+
+	ms.entries[key] = ms.lru.PushFront(e)
+	if ms.lru.Len() > ms.size {
+		el := ms.lru.Back()
+		ms.lru.Remove(el)
+		delete(ms.entries, el.Value.(*entry).key)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mainflux/mainflux/auth"
+	acl "github.com/ory/keto/proto/ory/keto/acl/v1alpha1"
+)
+
+// Store stores the policy decisions. The decisions are stored for the
+// generation of the policies. Since the single policy, e.g. the group
+// membership, affects the decisions on many objects, changing the policies
+// starts the new generation instead of invalidating the affected decisions,
+// so the decisions of the previous generations are no longer used.
+type Store interface {
+	// Generation returns the current generation of the policies.
+	Generation(ctx context.Context) (uint64, error)
+
+	// Invalidate starts the new generation of the policies.
+	Invalidate(ctx context.Context) error
+
+	// Get returns the decision stored for the given generation and key,
+	// and whether the decision is found.
+	Get(ctx context.Context, gen uint64, key string) (allowed, ok bool, err error)
+
+	// Set stores the decision for the given generation and key.
+	Set(ctx context.Context, gen uint64, key string, allowed bool) error
+}
+
+var _ auth.PolicyAgent = (*policyAgent)(nil)
+
+type policyAgent struct {
+	agent auth.PolicyAgent
+	store Store
+}
+
+// NewPolicyAgent returns the PolicyAgent caching the decisions of the given
+// agent in the given store. Store failures don't fail the checks, which fall
+// back to the agent instead.
+func NewPolicyAgent(agent auth.PolicyAgent, store Store) auth.PolicyAgent {
+	return policyAgent{agent: agent, store: store}
+}
+
+func (pa policyAgent) CheckPolicy(ctx context.Context, pr auth.PolicyReq) error {
+	gen, err := pa.store.Generation(ctx)
+	if err != nil {
+		return pa.agent.CheckPolicy(ctx, pr)
+	}
+
+	k := key(pr)
+	if allowed, ok, err := pa.store.Get(ctx, gen, k); err == nil && ok {
+		if !allowed {
+			return auth.ErrAuthorization
+		}
+		return nil
+	}
+
+	err = pa.agent.CheckPolicy(ctx, pr)
+	switch {
+	case err == nil:
+		pa.store.Set(ctx, gen, k, true)
+	// Only the denial is cached, not the failure to reach the agent.
+	case err == auth.ErrAuthorization:
+		pa.store.Set(ctx, gen, k, false)
+	}
+	return err
+}
+
+func (pa policyAgent) AddPolicy(ctx context.Context, pr auth.PolicyReq) error {
+	// The policy may be partially applied even if the agent fails.
+	defer pa.store.Invalidate(ctx)
+	return pa.agent.AddPolicy(ctx, pr)
+}
+
+func (pa policyAgent) DeletePolicy(ctx context.Context, pr auth.PolicyReq) error {
+	defer pa.store.Invalidate(ctx)
+	return pa.agent.DeletePolicy(ctx, pr)
+}
+
+func (pa policyAgent) RetrievePolicies(ctx context.Context, pr auth.PolicyReq) ([]*acl.RelationTuple, error) {
+	return pa.agent.RetrievePolicies(ctx, pr)
+}
+
+func key(pr auth.PolicyReq) string {
+	return strings.Join([]string{pr.Subject, pr.Object, pr.Relation}, "|")
+}
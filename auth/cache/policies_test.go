@@ -0,0 +1,157 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cache_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/auth/cache"
+	"github.com/mainflux/mainflux/auth/mocks"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errUnavailable = errors.New("policy agent unavailable")
+
+	readPolicy  = auth.PolicyReq{Subject: "user", Object: "thing", Relation: "read"}
+	writePolicy = auth.PolicyReq{Subject: "user", Object: "thing", Relation: "write"}
+)
+
+// countingAgent counts the checks reaching the agent, and fails them while
+// the agent is unavailable.
+type countingAgent struct {
+	auth.PolicyAgent
+	checks      int
+	unavailable bool
+}
+
+func (ca *countingAgent) CheckPolicy(ctx context.Context, pr auth.PolicyReq) error {
+	ca.checks++
+	if ca.unavailable {
+		return errors.Wrap(auth.ErrAuthorization, errUnavailable)
+	}
+	return ca.PolicyAgent.CheckPolicy(ctx, pr)
+}
+
+func testPolicyAgent(t *testing.T, store cache.Store) {
+	agent := &countingAgent{PolicyAgent: mocks.NewKetoMock(map[string][]mocks.MockSubjectSet{})}
+	pa := cache.NewPolicyAgent(agent, store)
+
+	err := pa.AddPolicy(context.Background(), readPolicy)
+	require.Nil(t, err, fmt.Sprintf("adding policy expected to succeed: %s", err))
+
+	cases := []struct {
+		desc    string
+		policy  auth.PolicyReq
+		prepare func()
+		err     error
+		checks  int
+	}{
+		{
+			desc:   "check allowed policy",
+			policy: readPolicy,
+			err:    nil,
+			checks: 1,
+		},
+		{
+			desc:   "check cached allowed policy",
+			policy: readPolicy,
+			err:    nil,
+			checks: 1,
+		},
+		{
+			desc:   "check denied policy",
+			policy: writePolicy,
+			err:    auth.ErrAuthorization,
+			checks: 2,
+		},
+		{
+			desc:   "check cached denied policy",
+			policy: writePolicy,
+			err:    auth.ErrAuthorization,
+			checks: 2,
+		},
+		{
+			desc:   "check denied policy after adding it",
+			policy: writePolicy,
+			prepare: func() {
+				err := pa.AddPolicy(context.Background(), writePolicy)
+				require.Nil(t, err, fmt.Sprintf("adding policy expected to succeed: %s", err))
+			},
+			err:    nil,
+			checks: 3,
+		},
+		{
+			desc:   "check allowed policy after deleting it",
+			policy: readPolicy,
+			prepare: func() {
+				err := pa.DeletePolicy(context.Background(), readPolicy)
+				require.Nil(t, err, fmt.Sprintf("deleting policy expected to succeed: %s", err))
+			},
+			err:    auth.ErrAuthorization,
+			checks: 4,
+		},
+		{
+			desc:   "check policy with unavailable agent",
+			policy: auth.PolicyReq{Subject: "user", Object: "thing", Relation: "delete"},
+			prepare: func() {
+				agent.unavailable = true
+			},
+			err:    errUnavailable,
+			checks: 5,
+		},
+		{
+			desc:   "check policy with unavailable agent again",
+			policy: auth.PolicyReq{Subject: "user", Object: "thing", Relation: "delete"},
+			err:    errUnavailable,
+			checks: 6,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.prepare != nil {
+			tc.prepare()
+		}
+		err := pa.CheckPolicy(context.Background(), tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.checks, agent.checks, fmt.Sprintf("%s: expected %d agent checks got %d\n", tc.desc, tc.checks, agent.checks))
+	}
+}
+
+func TestMemoryPolicyAgent(t *testing.T) {
+	testPolicyAgent(t, cache.NewMemoryStore(100, time.Minute))
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := cache.NewMemoryStore(2, 50*time.Millisecond)
+	ctx := context.Background()
+
+	gen, err := store.Generation(ctx)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for _, k := range []string{"a", "b", "c"} {
+		err := store.Set(ctx, gen, k, true)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	_, ok, _ := store.Get(ctx, gen, "a")
+	assert.False(t, ok, "expected least recently used decision to be evicted")
+	_, ok, _ = store.Get(ctx, gen, "c")
+	assert.True(t, ok, "expected decision to be stored")
+
+	err = store.Set(ctx, gen+1, "d", true)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, ok, _ = store.Get(ctx, gen+1, "d")
+	assert.False(t, ok, "expected decision of other generation not to be stored")
+
+	time.Sleep(100 * time.Millisecond)
+	_, ok, _ = store.Get(ctx, gen, "c")
+	assert.False(t, ok, "expected decision to expire")
+}
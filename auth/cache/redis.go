@@ -0,0 +1,70 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	keyPrefix     = "policies"
+	generationKey = keyPrefix + ":generation"
+
+	allowed = "1"
+	denied  = "0"
+)
+
+var _ Store = (*redisStore)(nil)
+
+type redisStore struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisStore returns the Redis store keeping the decisions for the
+// given time. Since the generation is kept in Redis, the policies changed
+// through any service instance invalidate the decisions of all of them.
+func NewRedisStore(client *redis.Client, ttl time.Duration) Store {
+	return redisStore{client: client, ttl: ttl}
+}
+
+func (rs redisStore) Generation(ctx context.Context) (uint64, error) {
+	gen, err := rs.client.Get(ctx, generationKey).Uint64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return gen, err
+}
+
+func (rs redisStore) Invalidate(ctx context.Context) error {
+	return rs.client.Incr(ctx, generationKey).Err()
+}
+
+func (rs redisStore) Get(ctx context.Context, gen uint64, key string) (bool, bool, error) {
+	val, err := rs.client.Get(ctx, decisionKey(gen, key)).Result()
+	switch {
+	case err == redis.Nil:
+		return false, false, nil
+	case err != nil:
+		return false, false, err
+	}
+
+	return val == allowed, true, nil
+}
+
+func (rs redisStore) Set(ctx context.Context, gen uint64, key string, allow bool) error {
+	val := denied
+	if allow {
+		val = allowed
+	}
+	return rs.client.Set(ctx, decisionKey(gen, key), val, rs.ttl).Err()
+}
+
+func decisionKey(gen uint64, key string) string {
+	return fmt.Sprintf("%s:%d:%s", keyPrefix, gen, key)
+}
@@ -4,11 +4,38 @@ import (
 	"context"
 	"errors"
 	"time"
+
+	"github.com/mainflux/mainflux/pkg/labels"
 )
 
 const MaxLevel = uint64(5)
 const MinLevel = uint64(1)
 
+// The relations describe the role of the member in the group, so the clients
+// can tell the members managing the group from the ones using it.
+const (
+	OwnerRelation  = "owner"
+	EditorRelation = "editor"
+	ViewerRelation = "viewer"
+)
+
+// The admin relations delegate the administration of the group to the user,
+// next to the group owner and the admin. They are stored in the policy
+// backend and apply to the group only, not to its sub-groups, except for
+// the AdminRelation.
+const (
+	// InviteRelation allows the user to manage the group members.
+	InviteRelation = "invite"
+
+	// CreateRelation allows the user to create the sub-groups of the group.
+	CreateRelation = "create"
+
+	// AdminRelation makes the user the admin of the group subtree: the user
+	// holds the other admin relations on the group and its sub-groups, and
+	// manages the users, things and channels assigned to any of them.
+	AdminRelation = "admin"
+)
+
 var (
 	// ErrMaxLevelExceeded malformed entity.
 	ErrMaxLevelExceeded = errors.New("level must be less than or equal 5")
@@ -64,15 +91,51 @@ type GroupMetadata map[string]interface{}
 type Member struct {
 	ID   string
 	Type string
+	// Relation is the role of the member in the group.
+	Relation string
+	// CreatedBy is the ID of the user who assigned the member.
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// Membership contains the metadata recorded when the members are assigned
+// to the group.
+type Membership struct {
+	Relation  string
+	CreatedBy string
+}
+
+// ValidRelation checks if the relation is one of the supported group member
+// relations.
+func ValidRelation(relation string) bool {
+	switch relation {
+	case OwnerRelation, EditorRelation, ViewerRelation:
+		return true
+	default:
+		return false
+	}
+}
+
+// ValidAdminRelation checks if the relation is one of the group admin
+// relations.
+func ValidAdminRelation(relation string) bool {
+	switch relation {
+	case InviteRelation, CreateRelation, AdminRelation:
+		return true
+	default:
+		return false
+	}
 }
 
 type Group struct {
 	ID          string
 	OwnerID     string
+	OrgID       string
 	ParentID    string
 	Name        string
 	Description string
 	Metadata    GroupMetadata
+	Labels      labels.Labels
 	// Indicates a level in tree hierarchy.
 	// Root node is level 1.
 	Level int
@@ -85,6 +148,15 @@ type Group struct {
 	UpdatedAt time.Time
 }
 
+// MembershipExpiration represents the deadline of the temporary group
+// membership.
+type MembershipExpiration struct {
+	GroupID   string
+	MemberID  string
+	Type      string
+	ExpiresAt time.Time
+}
+
 type PageMetadata struct {
 	Total    uint64
 	Offset   uint64
@@ -93,7 +165,13 @@ type PageMetadata struct {
 	Level    uint64
 	Name     string
 	Type     string
+	Relation string
 	Metadata GroupMetadata
+	// Selector filters the groups by their labels.
+	Selector labels.Selector
+	// OrgID limits the groups to the organization. The empty OrgID limits
+	// the groups to the ones outside of any organization.
+	OrgID string
 }
 
 type GroupPage struct {
@@ -126,6 +204,8 @@ type GroupService interface {
 	ListParents(ctx context.Context, token, childID string, pm PageMetadata) (GroupPage, error)
 
 	// ListMembers retrieves everything that is assigned to a group identified by groupID.
+	// The members can be filtered by their relation using the page metadata.
+	// The zero limit lists all the members, along with their total count.
 	ListMembers(ctx context.Context, token, groupID, groupType string, pm PageMetadata) (MemberPage, error)
 
 	// ListMemberships retrieves all groups for member that is identified with memberID belongs to.
@@ -140,8 +220,31 @@ type GroupService interface {
 	// Unassign removes member with memberID from group identified by groupID.
 	Unassign(ctx context.Context, token, groupID string, memberIDs ...string) error
 
+	// AssignTemporary adds the members into the group identified by groupID
+	// until the given deadline, after which the memberships are revoked.
+	AssignTemporary(ctx context.Context, token, groupID, groupType string, expiresAt time.Time, memberIDs ...string) error
+
+	// SetRelation changes the relation of the members of the group
+	// identified by groupID. The members are assigned as viewers.
+	SetRelation(ctx context.Context, token, groupID, relation string, memberIDs ...string) error
+
+	// RevokeExpiredMemberships removes the temporary memberships whose
+	// deadline has passed, along with the policies derived from them.
+	RevokeExpiredMemberships(ctx context.Context) error
+
 	// AssignGroupAccessRights adds access rights on thing groups to user group.
 	AssignGroupAccessRights(ctx context.Context, token, thingGroupID, userGroupID string) error
+
+	// DelegateGroupAdmin grants the user the admin relations on the group.
+	// This method is only allowed to use as the group owner or the admin.
+	DelegateGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) error
+
+	// RevokeGroupAdmin removes the admin relations of the user on the group.
+	// This method is only allowed to use as the group owner or the admin.
+	RevokeGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) error
+
+	// ListGroupAdmins lists the admin relations delegated on the group.
+	ListGroupAdmins(ctx context.Context, token, groupID string) ([]PolicyReq, error)
 }
 
 type GroupRepository interface {
@@ -170,11 +273,22 @@ type GroupRepository interface {
 	Memberships(ctx context.Context, memberID string, pm PageMetadata) (GroupPage, error)
 
 	// Members retrieves everything that is assigned to a group identified by groupID.
+	// The zero limit retrieves all the members.
 	Members(ctx context.Context, groupID, groupType string, pm PageMetadata) (MemberPage, error)
 
-	// Assign adds a member to group.
-	Assign(ctx context.Context, groupID, groupType string, memberIDs ...string) error
+	// Assign adds a member to group, recording the membership metadata.
+	Assign(ctx context.Context, groupID, groupType string, m Membership, memberIDs ...string) error
 
 	// Unassign removes a member from a group
 	Unassign(ctx context.Context, groupID string, memberIDs ...string) error
+
+	// SetExpiration sets the deadline of the members' group membership.
+	SetExpiration(ctx context.Context, groupID string, expiresAt time.Time, memberIDs ...string) error
+
+	// SetRelation sets the relation of the members of the group.
+	SetRelation(ctx context.Context, groupID, relation string, memberIDs ...string) error
+
+	// RetrieveExpired retrieves the memberships whose deadline is before
+	// the given time.
+	RetrieveExpired(ctx context.Context, before time.Time) ([]MembershipExpiration, error)
 }
@@ -0,0 +1,259 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/mainflux/mainflux/auth/oidc"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// refreshInterval limits the refreshes of the issuer keys, since the
+	// tokens signed using the unknown keys trigger the refresh.
+	refreshInterval = time.Minute
+
+	// maxDocumentSize limits the size of the fetched discovery and JWKS
+	// documents.
+	maxDocumentSize = 1 << 20
+)
+
+var (
+	errUntrustedIssuer = errors.New("token issuer is not trusted")
+	errInvalidAudience = errors.New("token audience is invalid")
+	errMissingClaims   = errors.New("token subject or expiration is missing")
+	errUnknownKey      = errors.New("token signing key is unknown")
+	errFetchKeys       = errors.New("failed to fetch issuer keys")
+)
+
+var _ oidc.TokenVerifier = (*externalVerifier)(nil)
+
+type issuerKeys struct {
+	keys      map[string]Key
+	fetchedAt time.Time
+}
+
+type externalVerifier struct {
+	client  *http.Client
+	issuers map[string]oidc.TrustedIssuer
+
+	mu   sync.Mutex
+	keys map[string]issuerKeys
+}
+
+// NewExternalVerifier returns the verifier of the tokens signed by the trusted
+// external OpenID Providers. The signing keys of the providers are fetched
+// from their JWKS, found using the OpenID Connect discovery, and cached. The
+// tokens have to be issued to the configured audience.
+func NewExternalVerifier(client *http.Client, issuers []oidc.TrustedIssuer) oidc.TokenVerifier {
+	trusted := map[string]oidc.TrustedIssuer{}
+	for _, iss := range issuers {
+		trusted[iss.URL] = iss
+	}
+
+	return &externalVerifier{
+		client:  client,
+		issuers: trusted,
+		keys:    map[string]issuerKeys{},
+	}
+}
+
+func (v *externalVerifier) Verify(ctx context.Context, token string) (oidc.ExternalIdentity, error) {
+	c := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, c, func(t *jwt.Token) (interface{}, error) {
+		iss, _ := c["iss"].(string)
+		if _, ok := v.issuers[iss]; !ok {
+			return nil, errUntrustedIssuer
+		}
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.key(ctx, iss, kid)
+		if err != nil {
+			return nil, err
+		}
+		// The algorithm must match the key to prevent the algorithm
+		// confusion.
+		if t.Method.Alg() != key.Algorithm {
+			return nil, ErrUnsupportedAlgorithm
+		}
+		return key.Verify, nil
+	})
+	if err != nil {
+		return oidc.ExternalIdentity{}, err
+	}
+
+	iss, _ := c["iss"].(string)
+	if !c.VerifyAudience(v.issuers[iss].Audience, true) {
+		return oidc.ExternalIdentity{}, errInvalidAudience
+	}
+	sub, _ := c["sub"].(string)
+	if sub == "" || !c.VerifyExpiresAt(time.Now().Unix(), true) {
+		return oidc.ExternalIdentity{}, errMissingClaims
+	}
+
+	email, _ := c["email"].(string)
+	return oidc.ExternalIdentity{Issuer: iss, Subject: sub, Email: email, EmailVerified: emailVerified(c)}, nil
+}
+
+// emailVerified returns the email_verified claim, which some identity
+// providers issue as the string.
+func emailVerified(c jwt.MapClaims) bool {
+	switch v := c["email_verified"].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+// key returns the issuer key with the given ID, refreshing the issuer keys
+// if the key is unknown.
+func (v *externalVerifier) key(ctx context.Context, issuer, kid string) (Key, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ik, ok := v.keys[issuer]
+	if key, found := ik.keys[kid]; found {
+		return key, nil
+	}
+	if ok && time.Since(ik.fetchedAt) < refreshInterval {
+		return Key{}, errUnknownKey
+	}
+
+	// The failed refreshes are limited as well, keeping the known keys.
+	keys, err := v.fetchKeys(ctx, issuer)
+	if err != nil {
+		v.keys[issuer] = issuerKeys{keys: ik.keys, fetchedAt: time.Now()}
+		return Key{}, err
+	}
+	v.keys[issuer] = issuerKeys{keys: keys, fetchedAt: time.Now()}
+
+	key, found := keys[kid]
+	if !found {
+		return Key{}, errUnknownKey
+	}
+	return key, nil
+}
+
+func (v *externalVerifier) fetchKeys(ctx context.Context, issuer string) (map[string]Key, error) {
+	var meta struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := v.fetch(ctx, strings.TrimSuffix(issuer, "/")+discoveryPath, &meta); err != nil {
+		return nil, err
+	}
+	// The issuer must match the URL the metadata is fetched from, as
+	// specified by OpenID Connect Discovery.
+	if meta.Issuer != issuer || meta.JWKSURI == "" {
+		return nil, errors.Wrap(errFetchKeys, fmt.Errorf("invalid metadata of issuer '%s'", issuer))
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := v.fetch(ctx, meta.JWKSURI, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]Key{}
+	for _, k := range set.Keys {
+		if key, ok := k.toKey(); ok {
+			keys[key.ID] = key
+		}
+	}
+
+	return keys, nil
+}
+
+func (v *externalVerifier) fetch(ctx context.Context, url string, doc interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(errFetchKeys, err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return errors.Wrap(errFetchKeys, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.Wrap(errFetchKeys, fmt.Errorf("unexpected status %d of '%s'", resp.StatusCode, url))
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(doc); err != nil {
+		return errors.Wrap(errFetchKeys, err)
+	}
+
+	return nil
+}
+
+// jwk is the JSON Web Key, as specified by RFC 7517.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// toKey converts the signing key to the verification key of the supported
+// algorithm. The other keys are skipped.
+func (k jwk) toKey() (Key, bool) {
+	if k.Use != "" && k.Use != "sig" {
+		return Key{}, false
+	}
+
+	key := Key{ID: k.Kid}
+	switch {
+	case k.Kty == "RSA":
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			return Key{}, false
+		}
+		key.Algorithm = RS256
+		key.Verify = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case k.Kty == "EC" && k.Crv == elliptic.P256().Params().Name:
+		x, errX := base64.RawURLEncoding.DecodeString(k.X)
+		y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+		if errX != nil || errY != nil {
+			return Key{}, false
+		}
+		pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return Key{}, false
+		}
+		key.Algorithm = ES256
+		key.Verify = pub
+	default:
+		return Key{}, false
+	}
+
+	if k.Alg != "" && k.Alg != key.Algorithm {
+		return Key{}, false
+	}
+
+	return key, true
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package jwt
+
+import (
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/mainflux/mainflux/auth/oidc"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var errSymmetricKey = errors.New("ID tokens can't be signed using HMAC secret")
+
+type idClaims struct {
+	jwt.StandardClaims
+	Nonce string `json:"nonce,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+var _ oidc.Signer = (*idTokenSigner)(nil)
+
+type idTokenSigner struct {
+	key Key
+}
+
+// NewIDTokenSigner returns the signer of the OpenID Connect ID tokens. The
+// clients verify the ID tokens using the published JWKS, so the signing key
+// has to be the RSA or ECDSA private key.
+func NewIDTokenSigner(key Key) (oidc.Signer, error) {
+	if _, ok := methods[key.Algorithm]; !ok {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if key.Algorithm == HS256 {
+		return nil, errors.Wrap(ErrUnsupportedAlgorithm, errSymmetricKey)
+	}
+	if key.Sign == nil {
+		return nil, errMissingSigningKey
+	}
+
+	return idTokenSigner{key: key}, nil
+}
+
+func (s idTokenSigner) Sign(t oidc.IDToken) (string, error) {
+	claims := idClaims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:    t.Issuer,
+			Subject:   t.Subject,
+			Audience:  t.Audience,
+			IssuedAt:  t.IssuedAt.UTC().Unix(),
+			ExpiresAt: t.ExpiresAt.UTC().Unix(),
+		},
+		Nonce: t.Nonce,
+		Email: t.Email,
+	}
+
+	token := jwt.NewWithClaims(methods[s.key.Algorithm], claims)
+	if s.key.ID != "" {
+		token.Header["kid"] = s.key.ID
+	}
+	return token.SignedString(s.key.Sign)
+}
+
+func (s idTokenSigner) Algorithm() string {
+	return s.key.Algorithm
+}
@@ -0,0 +1,108 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package jwt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"io/ioutil"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// Supported signing algorithms.
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	ES256 = "ES256"
+)
+
+var (
+	// ErrUnsupportedAlgorithm indicates that the signing algorithm is not
+	// supported.
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+
+	// ErrInvalidKey indicates that the key can't be used with its algorithm.
+	ErrInvalidKey = errors.New("invalid signing key")
+
+	errLoadKey = errors.New("failed to load signing key")
+)
+
+var methods = map[string]jwt.SigningMethod{
+	HS256: jwt.SigningMethodHS256,
+	RS256: jwt.SigningMethodRS256,
+	ES256: jwt.SigningMethodES256,
+}
+
+// Key represents the key used to sign and verify the tokens.
+type Key struct {
+	// ID is sent in the "kid" header of the signed tokens, so the tokens
+	// are verified using the key that signed them. The tokens without
+	// the header are verified using the key with empty ID.
+	ID        string
+	Algorithm string
+
+	// Sign is the HMAC secret, or the RSA or ECDSA private key. It's nil
+	// for the keys used only for verification.
+	Sign interface{}
+
+	// Verify is the HMAC secret, or the RSA or ECDSA public key.
+	Verify interface{}
+}
+
+// ParseKey parses the key for the given algorithm. HMAC secret is used as
+// is, while the RSA and ECDSA keys are PEM encoded. Public key can be used
+// only for verification.
+func ParseKey(id, alg string, data []byte) (Key, error) {
+	key := Key{ID: id, Algorithm: alg}
+	switch alg {
+	case HS256:
+		secret := bytes.TrimSpace(data)
+		if len(secret) == 0 {
+			return Key{}, ErrInvalidKey
+		}
+		key.Sign, key.Verify = secret, secret
+	case RS256:
+		if priv, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			key.Sign, key.Verify = priv, &priv.PublicKey
+			break
+		}
+		pub, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return Key{}, errors.Wrap(ErrInvalidKey, err)
+		}
+		key.Verify = pub
+	case ES256:
+		if priv, err := jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+			key.Sign, key.Verify = priv, &priv.PublicKey
+			break
+		}
+		pub, err := jwt.ParseECPublicKeyFromPEM(data)
+		if err != nil {
+			return Key{}, errors.Wrap(ErrInvalidKey, err)
+		}
+		key.Verify = pub
+	default:
+		return Key{}, ErrUnsupportedAlgorithm
+	}
+
+	// ES256 requires the P-256 curve.
+	if pub, ok := key.Verify.(*ecdsa.PublicKey); ok && pub.Curve != elliptic.P256() {
+		return Key{}, ErrInvalidKey
+	}
+
+	return key, nil
+}
+
+// LoadKey loads the key for the given algorithm from the file.
+func LoadKey(id, alg, path string) (Key, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Key{}, errors.Wrap(errLoadKey, err)
+	}
+
+	return ParseKey(id, alg, data)
+}
@@ -4,12 +4,26 @@
 package jwt_test
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	gojwt "github.com/golang-jwt/jwt/v4"
 	"github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/auth/jwt"
+	"github.com/mainflux/mainflux/auth/oidc"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -67,6 +81,11 @@ func TestParse(t *testing.T) {
 	expToken, err := tokenizer.Issue(expKey)
 	require.Nil(t, err, fmt.Sprintf("issuing expired key expected to succeed: %s", err))
 
+	claimsKey := key()
+	claimsKey.Claims = map[string]string{"device_id": "dev-1", "tenant": "acme"}
+	claimsToken, err := tokenizer.Issue(claimsKey)
+	require.Nil(t, err, fmt.Sprintf("issuing key with claims expected to succeed: %s", err))
+
 	cases := []struct {
 		desc  string
 		key   auth.Key
@@ -97,6 +116,12 @@ func TestParse(t *testing.T) {
 			token: apiToken,
 			err:   auth.ErrAPIKeyExpired,
 		},
+		{
+			desc:  "parse key with claims",
+			key:   claimsKey,
+			token: claimsToken,
+			err:   nil,
+		},
 	}
 
 	for _, tc := range cases {
@@ -105,3 +130,318 @@ func TestParse(t *testing.T) {
 		assert.Equal(t, tc.key, key, fmt.Sprintf("%s expected %v, got %v", tc.desc, tc.key, key))
 	}
 }
+
+func rsaPEM(t *testing.T) []byte {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("generating RSA key expected to succeed: %s", err))
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+}
+
+func ecPEM(t *testing.T, curve elliptic.Curve) ([]byte, []byte) {
+	priv, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.Nil(t, err, fmt.Sprintf("generating ECDSA key expected to succeed: %s", err))
+	der, err := x509.MarshalECPrivateKey(priv)
+	require.Nil(t, err, fmt.Sprintf("marshaling ECDSA key expected to succeed: %s", err))
+	pubDER, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.Nil(t, err, fmt.Sprintf("marshaling ECDSA public key expected to succeed: %s", err))
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+}
+
+func TestParseKey(t *testing.T) {
+	ecPriv, ecPub := ecPEM(t, elliptic.P256())
+	p384, _ := ecPEM(t, elliptic.P384())
+
+	cases := []struct {
+		desc   string
+		alg    string
+		data   []byte
+		signer bool
+		err    error
+	}{
+		{
+			desc:   "parse HMAC secret",
+			alg:    jwt.HS256,
+			data:   []byte(secret),
+			signer: true,
+			err:    nil,
+		},
+		{
+			desc: "parse empty HMAC secret",
+			alg:  jwt.HS256,
+			data: []byte(" \n"),
+			err:  jwt.ErrInvalidKey,
+		},
+		{
+			desc:   "parse RSA private key",
+			alg:    jwt.RS256,
+			data:   rsaPEM(t),
+			signer: true,
+			err:    nil,
+		},
+		{
+			desc:   "parse ECDSA private key",
+			alg:    jwt.ES256,
+			data:   ecPriv,
+			signer: true,
+			err:    nil,
+		},
+		{
+			desc:   "parse ECDSA public key",
+			alg:    jwt.ES256,
+			data:   ecPub,
+			signer: false,
+			err:    nil,
+		},
+		{
+			desc: "parse ECDSA key with wrong curve",
+			alg:  jwt.ES256,
+			data: p384,
+			err:  jwt.ErrInvalidKey,
+		},
+		{
+			desc: "parse ECDSA key as RSA key",
+			alg:  jwt.RS256,
+			data: ecPriv,
+			err:  jwt.ErrInvalidKey,
+		},
+		{
+			desc: "parse key with unsupported algorithm",
+			alg:  "none",
+			data: []byte(secret),
+			err:  jwt.ErrUnsupportedAlgorithm,
+		},
+	}
+
+	for _, tc := range cases {
+		key, err := jwt.ParseKey("kid", tc.alg, tc.data)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s expected %s, got %s", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.signer, key.Sign != nil, fmt.Sprintf("%s expected signing key %t", tc.desc, tc.signer))
+		}
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	legacy := jwt.New(secret)
+	legacyToken, err := legacy.Issue(key())
+	require.Nil(t, err, fmt.Sprintf("issuing legacy key expected to succeed: %s", err))
+
+	oldKey, err := jwt.ParseKey("old", jwt.RS256, rsaPEM(t))
+	require.Nil(t, err, fmt.Sprintf("parsing old key expected to succeed: %s", err))
+	old, err := jwt.NewWithKeys(oldKey)
+	require.Nil(t, err, fmt.Sprintf("creating old tokenizer expected to succeed: %s", err))
+	oldToken, err := old.Issue(key())
+	require.Nil(t, err, fmt.Sprintf("issuing old key expected to succeed: %s", err))
+
+	ecPriv, _ := ecPEM(t, elliptic.P256())
+	newKey, err := jwt.ParseKey("new", jwt.ES256, ecPriv)
+	require.Nil(t, err, fmt.Sprintf("parsing new key expected to succeed: %s", err))
+	secretKey, err := jwt.ParseKey("", jwt.HS256, []byte(secret))
+	require.Nil(t, err, fmt.Sprintf("parsing secret expected to succeed: %s", err))
+
+	// The old RSA public key can't be used to verify HMAC signature.
+	forgedKey, err := jwt.ParseKey("old", jwt.HS256, []byte(secret))
+	require.Nil(t, err, fmt.Sprintf("parsing forged key expected to succeed: %s", err))
+	forged, err := jwt.NewWithKeys(forgedKey)
+	require.Nil(t, err, fmt.Sprintf("creating forged tokenizer expected to succeed: %s", err))
+	forgedToken, err := forged.Issue(key())
+	require.Nil(t, err, fmt.Sprintf("issuing forged key expected to succeed: %s", err))
+
+	oldKey.Sign = nil
+	tokenizer, err := jwt.NewWithKeys(newKey, oldKey, secretKey)
+	require.Nil(t, err, fmt.Sprintf("creating rotated tokenizer expected to succeed: %s", err))
+	newToken, err := tokenizer.Issue(key())
+	require.Nil(t, err, fmt.Sprintf("issuing new key expected to succeed: %s", err))
+
+	_, err = jwt.NewWithKeys(oldKey)
+	assert.NotNil(t, err, "creating tokenizer without private signing key expected to fail")
+
+	cases := []struct {
+		desc  string
+		token string
+		err   error
+	}{
+		{
+			desc:  "parse token signed with new key",
+			token: newToken,
+			err:   nil,
+		},
+		{
+			desc:  "parse token signed with old key",
+			token: oldToken,
+			err:   nil,
+		},
+		{
+			desc:  "parse token signed with legacy secret",
+			token: legacyToken,
+			err:   nil,
+		},
+		{
+			desc:  "parse token signed with wrong algorithm",
+			token: forgedToken,
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		k, err := tokenizer.Parse(tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s expected %s, got %s", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, key(), k, fmt.Sprintf("%s expected %v, got %v", tc.desc, key(), k))
+		}
+	}
+
+	_, err = old.Parse(newToken)
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("parsing token with unknown key ID expected %s, got %s", auth.ErrUnauthorizedAccess, err))
+}
+
+func TestIDTokenSigner(t *testing.T) {
+	rsaKey, err := jwt.ParseKey("rsa", jwt.RS256, rsaPEM(t))
+	require.Nil(t, err, fmt.Sprintf("parsing RSA key expected to succeed: %s", err))
+	_, ecPub := ecPEM(t, elliptic.P256())
+	pubKey, err := jwt.ParseKey("pub", jwt.ES256, ecPub)
+	require.Nil(t, err, fmt.Sprintf("parsing ECDSA public key expected to succeed: %s", err))
+	secretKey, err := jwt.ParseKey("secret", jwt.HS256, []byte(secret))
+	require.Nil(t, err, fmt.Sprintf("parsing secret expected to succeed: %s", err))
+
+	cases := []struct {
+		desc string
+		key  jwt.Key
+		err  error
+	}{
+		{
+			desc: "create signer with RSA private key",
+			key:  rsaKey,
+			err:  nil,
+		},
+		{
+			desc: "create signer with ECDSA public key",
+			key:  pubKey,
+			err:  errors.New("missing private signing key"),
+		},
+		{
+			desc: "create signer with HMAC secret",
+			key:  secretKey,
+			err:  jwt.ErrUnsupportedAlgorithm,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := jwt.NewIDTokenSigner(tc.key)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s expected %s, got %s", tc.desc, tc.err, err))
+	}
+
+	signer, err := jwt.NewIDTokenSigner(rsaKey)
+	require.Nil(t, err, fmt.Sprintf("creating signer expected to succeed: %s", err))
+	now := time.Now().UTC()
+	token, err := signer.Sign(oidc.IDToken{
+		Issuer:    "https://mainflux.example.com",
+		Subject:   "user",
+		Audience:  "grafana",
+		Email:     "user@example.com",
+		Nonce:     "nonce",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Minute),
+	})
+	require.Nil(t, err, fmt.Sprintf("signing ID token expected to succeed: %s", err))
+
+	claims := gojwt.MapClaims{}
+	_, err = gojwt.ParseWithClaims(token, claims, func(token *gojwt.Token) (interface{}, error) {
+		assert.Equal(t, "rsa", token.Header["kid"], "ID token expected to carry the signing key ID")
+		return rsaKey.Verify, nil
+	})
+	require.Nil(t, err, fmt.Sprintf("verifying ID token expected to succeed: %s", err))
+	assert.Equal(t, "grafana", claims["aud"], "ID token expected to be issued to the client")
+	assert.Equal(t, "nonce", claims["nonce"], "ID token expected to carry the nonce")
+	assert.Equal(t, "user@example.com", claims["email"], "ID token expected to carry the email")
+}
+
+func TestExternalVerifier(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("generating RSA key expected to succeed: %s", err))
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("generating RSA key expected to succeed: %s", err))
+
+	// The identity provider publishes the discovery document and the JWKS.
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": ts.URL, "jwks_uri": ts.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		jwk := map[string]string{
+			"kty": "RSA",
+			"kid": "idp",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(priv.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(priv.E)).Bytes()),
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{jwk}})
+	})
+
+	verifier := jwt.NewExternalVerifier(ts.Client(), []oidc.TrustedIssuer{{URL: ts.URL, Audience: "mainflux"}})
+
+	sign := func(claims gojwt.MapClaims, kid string, key *rsa.PrivateKey) string {
+		token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, claims)
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		require.Nil(t, err, fmt.Sprintf("signing token expected to succeed: %s", err))
+		return s
+	}
+	claims := func(iss, aud string, exp time.Duration) gojwt.MapClaims {
+		return gojwt.MapClaims{"iss": iss, "sub": "external-user", "aud": []string{"other", aud}, "exp": time.Now().Add(exp).Unix()}
+	}
+	hmacToken, err := gojwt.NewWithClaims(gojwt.SigningMethodHS256, claims(ts.URL, "mainflux", time.Minute)).SignedString([]byte(secret))
+	require.Nil(t, err, fmt.Sprintf("signing token expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		err   bool
+	}{
+		{
+			desc:  "verify valid token",
+			token: sign(claims(ts.URL, "mainflux", time.Minute), "idp", priv),
+			err:   false,
+		},
+		{
+			desc:  "verify token of untrusted issuer",
+			token: sign(claims("https://attacker.example.com", "mainflux", time.Minute), "idp", priv),
+			err:   true,
+		},
+		{
+			desc:  "verify token issued to another audience",
+			token: sign(claims(ts.URL, "grafana", time.Minute), "idp", priv),
+			err:   true,
+		},
+		{
+			desc:  "verify expired token",
+			token: sign(claims(ts.URL, "mainflux", -time.Minute), "idp", priv),
+			err:   true,
+		},
+		{
+			desc:  "verify token signed by unknown key",
+			token: sign(claims(ts.URL, "mainflux", time.Minute), "unknown", other),
+			err:   true,
+		},
+		{
+			desc:  "verify token signed by wrong key",
+			token: sign(claims(ts.URL, "mainflux", time.Minute), "idp", other),
+			err:   true,
+		},
+		{
+			desc:  "verify HMAC token",
+			token: hmacToken,
+			err:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := verifier.Verify(context.Background(), tc.token)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		if err == nil {
+			assert.Equal(t, oidc.ExternalIdentity{Issuer: ts.URL, Subject: "external-user"}, id, fmt.Sprintf("%s: unexpected identity", tc.desc))
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package jwt
 
 import (
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -15,25 +16,61 @@ const issuerName = "mainflux.auth"
 
 type claims struct {
 	jwt.StandardClaims
-	IssuerID string  `json:"issuer_id,omitempty"`
-	Type     *uint32 `json:"type,omitempty"`
+	IssuerID   string            `json:"issuer_id,omitempty"`
+	OrgID      string            `json:"org_id,omitempty"`
+	Type       *uint32           `json:"type,omitempty"`
+	Scopes     []string          `json:"scopes,omitempty"`
+	AllowedIPs []string          `json:"allowed_ips,omitempty"`
+	Claims     map[string]string `json:"claims,omitempty"`
 }
 
 func (c claims) Valid() error {
-	if c.Type == nil || *c.Type > auth.APIKey || c.Issuer != issuerName {
+	if c.Type == nil || *c.Type > auth.RefreshKey || c.Issuer != issuerName {
 		return auth.ErrMalformedEntity
 	}
 
 	return c.StandardClaims.Valid()
 }
 
+var errMissingSigningKey = errors.New("missing private signing key")
+
 type tokenizer struct {
-	secret string
+	signing Key
+	keys    map[string]Key
 }
 
-// New returns new JWT Tokenizer.
+// New returns new JWT Tokenizer that uses the HMAC secret.
 func New(secret string) auth.Tokenizer {
-	return tokenizer{secret: secret}
+	key := Key{Algorithm: HS256, Sign: []byte(secret), Verify: []byte(secret)}
+	return tokenizer{
+		signing: key,
+		keys:    map[string]Key{key.ID: key},
+	}
+}
+
+// NewWithKeys returns new JWT Tokenizer that signs the tokens using the
+// signing key, and verifies them using the key that signed them, looked up
+// by the "kid" header among the signing and the verification keys. The keys
+// are rotated with no downtime by signing with the new key, while keeping
+// the old one for verification until the tokens it signed expire.
+func NewWithKeys(signing Key, verification ...Key) (auth.Tokenizer, error) {
+	if _, ok := methods[signing.Algorithm]; !ok {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if signing.Sign == nil {
+		return nil, errMissingSigningKey
+	}
+
+	keys := map[string]Key{}
+	for _, key := range verification {
+		if _, ok := methods[key.Algorithm]; !ok {
+			return nil, ErrUnsupportedAlgorithm
+		}
+		keys[key.ID] = key
+	}
+	keys[signing.ID] = signing
+
+	return tokenizer{signing: signing, keys: keys}, nil
 }
 
 func (svc tokenizer) Issue(key auth.Key) (string, error) {
@@ -43,8 +80,12 @@ func (svc tokenizer) Issue(key auth.Key) (string, error) {
 			Subject:  key.Subject,
 			IssuedAt: key.IssuedAt.UTC().Unix(),
 		},
-		IssuerID: key.IssuerID,
-		Type:     &key.Type,
+		IssuerID:   key.IssuerID,
+		OrgID:      key.OrgID,
+		Type:       &key.Type,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		Claims:     key.Claims,
 	}
 
 	if !key.ExpiresAt.IsZero() {
@@ -54,23 +95,30 @@ func (svc tokenizer) Issue(key auth.Key) (string, error) {
 		claims.Id = key.ID
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(svc.secret))
+	token := jwt.NewWithClaims(methods[svc.signing.Algorithm], claims)
+	if svc.signing.ID != "" {
+		token.Header["kid"] = svc.signing.ID
+	}
+	return token.SignedString(svc.signing.Sign)
 }
 
 func (svc tokenizer) Parse(token string) (auth.Key, error) {
 	c := claims{}
 	_, err := jwt.ParseWithClaims(token, &c, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := svc.keys[kid]
+		// The algorithm must match the key to prevent the algorithm
+		// confusion, e.g. verifying HMAC signature using the public key.
+		if !ok || token.Method.Alg() != key.Algorithm {
 			return nil, auth.ErrUnauthorizedAccess
 		}
-		return []byte(svc.secret), nil
+		return key.Verify, nil
 	})
 
 	if err != nil {
 		if e, ok := err.(*jwt.ValidationError); ok && e.Errors == jwt.ValidationErrorExpired {
 			// Expired User key needs to be revoked.
-			if c.Type != nil && *c.Type == auth.APIKey {
+			if c.Type != nil && (*c.Type == auth.APIKey || *c.Type == auth.ServiceAccountKey) {
 				return c.toKey(), auth.ErrAPIKeyExpired
 			}
 			return auth.Key{}, errors.Wrap(auth.ErrKeyExpired, err)
@@ -81,12 +129,29 @@ func (svc tokenizer) Parse(token string) (auth.Key, error) {
 	return c.toKey(), nil
 }
 
+func (svc tokenizer) PublicKeys() []auth.PublicKey {
+	keys := []auth.PublicKey{}
+	for _, key := range svc.keys {
+		if key.Algorithm == HS256 {
+			continue
+		}
+		keys = append(keys, auth.PublicKey{ID: key.ID, Algorithm: key.Algorithm, Key: key.Verify})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+
+	return keys
+}
+
 func (c claims) toKey() auth.Key {
 	key := auth.Key{
-		ID:       c.Id,
-		IssuerID: c.IssuerID,
-		Subject:  c.Subject,
-		IssuedAt: time.Unix(c.IssuedAt, 0).UTC(),
+		ID:         c.Id,
+		IssuerID:   c.IssuerID,
+		OrgID:      c.OrgID,
+		Subject:    c.Subject,
+		IssuedAt:   time.Unix(c.IssuedAt, 0).UTC(),
+		Scopes:     c.Scopes,
+		AllowedIPs: c.AllowedIPs,
+		Claims:     c.Claims,
 	}
 	if c.ExpiresAt != 0 {
 		key.ExpiresAt = time.Unix(c.ExpiresAt, 0).UTC()
@@ -0,0 +1,37 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package jwt
+
+import (
+	"github.com/hashicorp/vault/api"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// vaultKeyField is the field of the Vault secret holding the key.
+const vaultKeyField = "key"
+
+// ReadVaultKey reads the key for the given algorithm from the "key" field
+// of the Vault secret at the given path. Both KV version 1 and version 2
+// secrets are supported.
+func ReadVaultKey(client *api.Client, id, alg, path string) (Key, error) {
+	secret, err := client.Logical().Read(path)
+	if err != nil {
+		return Key{}, errors.Wrap(errLoadKey, err)
+	}
+	if secret == nil {
+		return Key{}, errors.Wrap(errLoadKey, errors.New("secret not found"))
+	}
+
+	data := secret.Data
+	// KV version 2 nests the secret data.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	value, ok := data[vaultKeyField].(string)
+	if !ok {
+		return Key{}, errors.Wrap(errLoadKey, errors.New("missing key field"))
+	}
+
+	return ParseKey(id, alg, []byte(value))
+}
@@ -13,10 +13,7 @@ import (
 	acl "github.com/ory/keto/proto/ory/keto/acl/v1alpha1"
 )
 
-const (
-	subjectSetRegex = "^.{1,}:.{1,}#.{1,}$" // expected subject set structure is <namespace>:<object>#<relation>
-	ketoNamespace   = "members"
-)
+const subjectSetRegex = "^.{1,}:.{1,}#.{1,}$" // expected subject set structure is <namespace>:<object>#<relation>
 
 type policyAgent struct {
 	writer  acl.WriteServiceClient
@@ -32,7 +29,7 @@ func NewPolicyAgent(checker acl.CheckServiceClient, writer acl.WriteServiceClien
 
 func (pa policyAgent) CheckPolicy(ctx context.Context, pr auth.PolicyReq) error {
 	res, err := pa.checker.Check(context.Background(), &acl.CheckRequest{
-		Namespace: ketoNamespace,
+		Namespace: namespace(pr),
 		Object:    pr.Object,
 		Relation:  pr.Relation,
 		Subject:   getSubject(pr),
@@ -47,27 +44,16 @@ func (pa policyAgent) CheckPolicy(ctx context.Context, pr auth.PolicyReq) error
 }
 
 func (pa policyAgent) AddPolicy(ctx context.Context, pr auth.PolicyReq) error {
-	var ss *acl.Subject
-	switch isSubjectSet(pr.Subject) {
-	case true:
-		namespace, object, relation := parseSubjectSet(pr.Subject)
-		ss = &acl.Subject{
-			Ref: &acl.Subject_Set{Set: &acl.SubjectSet{Namespace: namespace, Object: object, Relation: relation}},
-		}
-	default:
-		ss = &acl.Subject{Ref: &acl.Subject_Id{Id: pr.Subject}}
-	}
-
 	trt := pa.writer.TransactRelationTuples
 	_, err := trt(context.Background(), &acl.TransactRelationTuplesRequest{
 		RelationTupleDeltas: []*acl.RelationTupleDelta{
 			{
 				Action: acl.RelationTupleDelta_INSERT,
 				RelationTuple: &acl.RelationTuple{
-					Namespace: ketoNamespace,
+					Namespace: namespace(pr),
 					Object:    pr.Object,
 					Relation:  pr.Relation,
-					Subject:   ss,
+					Subject:   toSubject(pr.Subject),
 				},
 			},
 		},
@@ -82,12 +68,10 @@ func (pa policyAgent) DeletePolicy(ctx context.Context, pr auth.PolicyReq) error
 			{
 				Action: acl.RelationTupleDelta_DELETE,
 				RelationTuple: &acl.RelationTuple{
-					Namespace: ketoNamespace,
+					Namespace: namespace(pr),
 					Object:    pr.Object,
 					Relation:  pr.Relation,
-					Subject: &acl.Subject{Ref: &acl.Subject_Id{
-						Id: pr.Subject,
-					}},
+					Subject:   toSubject(pr.Subject),
 				},
 			},
 		},
@@ -96,51 +80,58 @@ func (pa policyAgent) DeletePolicy(ctx context.Context, pr auth.PolicyReq) error
 }
 
 func (pa policyAgent) RetrievePolicies(ctx context.Context, pr auth.PolicyReq) ([]*acl.RelationTuple, error) {
-	var ss *acl.Subject
-	switch isSubjectSet(pr.Subject) {
-	case true:
-		namespace, object, relation := parseSubjectSet(pr.Subject)
-		ss = &acl.Subject{
-			Ref: &acl.Subject_Set{Set: &acl.SubjectSet{Namespace: namespace, Object: object, Relation: relation}},
-		}
-	default:
-		ss = &acl.Subject{Ref: &acl.Subject_Id{Id: pr.Subject}}
+	query := &acl.ListRelationTuplesRequest_Query{
+		Namespace: namespace(pr),
+		Object:    pr.Object,
+		Relation:  pr.Relation,
 	}
-
-	res, err := pa.reader.ListRelationTuples(ctx, &acl.ListRelationTuplesRequest{
-		Query: &acl.ListRelationTuplesRequest_Query{
-			Namespace: ketoNamespace,
-			Relation:  pr.Relation,
-			Subject:   ss,
-		},
-	})
-	if err != nil {
-		return []*acl.RelationTuple{}, err
+	if pr.Subject != "" {
+		query.Subject = toSubject(pr.Subject)
 	}
 
-	tuple := res.GetRelationTuples()
-	for res.NextPageToken != "" {
-		tuple = append(tuple, res.GetRelationTuples()...)
-	}
+	tuples := []*acl.RelationTuple{}
+	req := &acl.ListRelationTuplesRequest{Query: query}
+	for {
+		res, err := pa.reader.ListRelationTuples(ctx, req)
+		if err != nil {
+			return []*acl.RelationTuple{}, err
+		}
+		tuples = append(tuples, res.GetRelationTuples()...)
 
-	return tuple, nil
+		if res.GetNextPageToken() == "" {
+			return tuples, nil
+		}
+		req.PageToken = res.GetNextPageToken()
+	}
 }
 
 // getSubject returns a 'subject' field for ACL(access control lists).
 // If the given PolicyReq argument contains a subject as subject set,
 // it returns subject set; otherwise, it returns a subject.
 func getSubject(pr auth.PolicyReq) *acl.Subject {
-	if isSubjectSet(pr.Subject) {
+	return toSubject(pr.Subject)
+}
+
+// namespace returns the namespace of the policy object, falling back to the
+// members namespace for the policies without one.
+func namespace(pr auth.PolicyReq) string {
+	if pr.Namespace == "" {
+		return auth.MembersNamespace
+	}
+	return pr.Namespace
+}
+
+// toSubject returns the subject set reference if the given subject is the
+// subject set, or the subject ID reference otherwise.
+func toSubject(subject string) *acl.Subject {
+	if isSubjectSet(subject) {
+		namespace, object, relation := parseSubjectSet(subject)
 		return &acl.Subject{
-			Ref: &acl.Subject_Set{Set: &acl.SubjectSet{
-				Namespace: ketoNamespace,
-				Object:    pr.Object,
-				Relation:  pr.Relation,
-			}},
+			Ref: &acl.Subject_Set{Set: &acl.SubjectSet{Namespace: namespace, Object: object, Relation: relation}},
 		}
 	}
 
-	return &acl.Subject{Ref: &acl.Subject_Id{Id: pr.Subject}}
+	return &acl.Subject{Ref: &acl.Subject_Id{Id: subject}}
 }
 
 // isSubjectSet returns true when given subject is subject set.
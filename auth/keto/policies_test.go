@@ -71,9 +71,58 @@ func TestGetSubject(t *testing.T) {
 	_, ok := ref1.(*acl.Subject_Id)
 	assert.True(t, ok, fmt.Errorf("subject reference of %#v is expected to be (*acl.Subject_Id), got %T", p1, ref1))
 
-	p2 := auth.PolicyReq{Subject: "members:group#access", Object: "object", Relation: "relation"}
+	p2 := auth.PolicyReq{Subject: "members:group#access", Object: "object", Relation: "relation", Namespace: auth.ThingsNamespace}
 	s2 := getSubject(p2)
 	ref2 := s2.GetRef()
-	_, ok = ref2.(*acl.Subject_Set)
+	set, ok := ref2.(*acl.Subject_Set)
 	assert.True(t, ok, fmt.Errorf("subject reference of %#v is expected to be (*acl.Subject_Set), got %T", p2, ref2))
+	expected := &acl.SubjectSet{Namespace: "members", Object: "group", Relation: "access"}
+	assert.Equal(t, expected.String(), set.Set.String(), fmt.Sprintf("subject set expected to be %v, got %v", expected, set.Set))
+}
+
+func TestNamespace(t *testing.T) {
+	cases := []struct {
+		desc      string
+		namespace string
+		result    string
+	}{
+		{
+			desc:      "policy without namespace",
+			namespace: "",
+			result:    auth.MembersNamespace,
+		},
+		{
+			desc:      "policy with members namespace",
+			namespace: auth.MembersNamespace,
+			result:    auth.MembersNamespace,
+		},
+		{
+			desc:      "policy with things namespace",
+			namespace: auth.ThingsNamespace,
+			result:    auth.ThingsNamespace,
+		},
+		{
+			desc:      "policy with channels namespace",
+			namespace: auth.ChannelsNamespace,
+			result:    auth.ChannelsNamespace,
+		},
+	}
+
+	for _, tc := range cases {
+		ns := namespace(auth.PolicyReq{Subject: "subject", Object: "object", Relation: "relation", Namespace: tc.namespace})
+		assert.Equal(t, tc.result, ns, fmt.Sprintf("%s: expected namespace %s got %s\n", tc.desc, tc.result, ns))
+	}
+}
+
+func TestToSubject(t *testing.T) {
+	s1 := toSubject("subject")
+	id, ok := s1.GetRef().(*acl.Subject_Id)
+	assert.True(t, ok, fmt.Sprintf("subject reference of %s is expected to be (*acl.Subject_Id), got %T", "subject", s1.GetRef()))
+	assert.Equal(t, "subject", id.Id, fmt.Sprintf("subject ID expected to be %s, got %s", "subject", id.Id))
+
+	s2 := toSubject("members:group#access")
+	set, ok := s2.GetRef().(*acl.Subject_Set)
+	assert.True(t, ok, fmt.Sprintf("subject reference of %s is expected to be (*acl.Subject_Set), got %T", "members:group#access", s2.GetRef()))
+	expected := &acl.SubjectSet{Namespace: "members", Object: "group", Relation: "access"}
+	assert.Equal(t, expected.String(), set.Set.String(), fmt.Sprintf("subject set expected to be %v, got %v", expected, set.Set))
 }
@@ -6,6 +6,8 @@ package auth
 import (
 	"context"
 	"errors"
+	"net"
+	"strings"
 	"time"
 )
 
@@ -19,6 +21,17 @@ var (
 	// ErrAPIKeyExpired indicates that the Key is expired
 	// and that the key type is API key.
 	ErrAPIKeyExpired = errors.New("use of expired API key")
+
+	// ErrKeyRevoked indicates that the Key is revoked.
+	ErrKeyRevoked = errors.New("use of revoked key")
+
+	// ErrKeyIPNotAllowed indicates that the Key is used from the IP address
+	// outside of its allowlist.
+	ErrKeyIPNotAllowed = errors.New("use of key outside of allowed networks")
+
+	// ErrKeyScopeNotAllowed indicates that the scoped Key is used to perform
+	// the action outside of its scopes.
+	ErrKeyScopeNotAllowed = errors.New("use of key outside of allowed scopes")
 )
 
 const (
@@ -28,8 +41,24 @@ const (
 	RecoveryKey
 	// APIKey enables the one to act on behalf of the user.
 	APIKey
+	// ServiceAccountKey enables the one to act on behalf of the service
+	// account.
+	ServiceAccountKey
+	// RefreshKey is issued alongside the User key on login, and is used to
+	// obtain the new User key once the previous one expires.
+	RefreshKey
 )
 
+// wildcardScope matches any resource or action of the scope.
+const wildcardScope = "*"
+
+// maxKeyLabelLength is the maximal length of the Key label.
+const maxKeyLabelLength = 254
+
+// maxKeyClaims is the maximal number of the Key custom claims, keeping the
+// issued tokens reasonably small.
+const maxKeyClaims = 16
+
 // Key represents API key.
 type Key struct {
 	ID        string
@@ -38,22 +67,153 @@ type Key struct {
 	Subject   string
 	IssuedAt  time.Time
 	ExpiresAt time.Time
+
+	// Scopes restrict the API key to the listed actions, in the format of
+	// <resource>:<action>, e.g. "things:read" or "messages:write". Either
+	// part can be the "*" wildcard. The key without scopes is unrestricted.
+	Scopes []string
+
+	// OrgID is the organization the key is scoped to. The key without the
+	// organization reaches only the entities outside of any organization.
+	OrgID string
+
+	// AllowedIPs restrict the API key to the clients from the listed
+	// networks in the CIDR notation, e.g. "10.0.0.0/8". The plain IP
+	// address stands for the single address network. The key without the
+	// allowlist is usable from any address.
+	AllowedIPs []string
+
+	// Label is the free-form name helping the user to recognize the key.
+	Label string
+
+	// Claims are the custom claims carried by the issued token, such as
+	// the device ID or the tenant. They are surfaced on the identification,
+	// sparing the consumers the extra lookups.
+	Claims map[string]string
+}
+
+// KeysPage contains the page of the keys.
+type KeysPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Keys   []Key
 }
 
-// Identity contains ID and Email.
+// Identity contains ID and Email, the organization and the custom claims
+// of the key.
 type Identity struct {
-	ID    string
-	Email string
+	ID     string
+	Email  string
+	OrgID  string
+	Claims map[string]string
 }
 
 // Expired verifies if the key is expired.
 func (k Key) Expired() bool {
-	if k.Type == APIKey && k.ExpiresAt.IsZero() {
+	if (k.Type == APIKey || k.Type == ServiceAccountKey) && k.ExpiresAt.IsZero() {
 		return false
 	}
 	return k.ExpiresAt.UTC().Before(time.Now().UTC())
 }
 
+// Allows verifies if the key is allowed to perform the scoped action. The
+// scoped key isn't allowed to perform the action without the scope.
+func (k Key) Allows(scope string) bool {
+	if len(k.Scopes) == 0 {
+		return true
+	}
+
+	resource, action, ok := splitScope(scope)
+	if !ok {
+		return false
+	}
+	for _, s := range k.Scopes {
+		r, a, _ := splitScope(s)
+		if (r == wildcardScope || r == resource) && (a == wildcardScope || a == action) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsIP verifies if the key is allowed to be used from the IP address.
+// The key with the allowlist isn't allowed to be used from the unknown
+// address.
+func (k Key) AllowsIP(ip string) bool {
+	if len(k.AllowedIPs) == 0 {
+		return true
+	}
+
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, cidr := range k.AllowedIPs {
+		if network, err := ParseNetwork(cidr); err == nil && network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseNetwork parses the network of the key allowlist, which is either in
+// the CIDR notation or the plain IP address.
+func ParseNetwork(s string) (*net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, network, err := net.ParseCIDR(s)
+		return network, err
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, &net.ParseError{Type: "IP address", Text: s}
+	}
+	bits := 8 * net.IPv6len
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits = ip4, 8*net.IPv4len
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+}
+
+type clientIPKey struct{}
+
+// WithClientIP returns the context carrying the IP address of the client
+// using the key, as propagated by the transports.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP returns the IP address of the client stored in the context, and
+// the empty string if it's unknown.
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+type scopeKey struct{}
+
+// WithScope returns the context carrying the scope of the action the key is
+// used to perform, in the format of <resource>:<action>.
+func WithScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// Scope returns the scope of the action stored in the context, and the empty
+// string if the action doesn't declare it.
+func Scope(ctx context.Context) string {
+	scope, _ := ctx.Value(scopeKey{}).(string)
+	return scope
+}
+
+func splitScope(scope string) (string, string, bool) {
+	parts := strings.Split(scope, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
 // KeyRepository specifies Key persistence API.
 type KeyRepository interface {
 	// Save persists the Key. A non-nil error is returned to indicate
@@ -65,4 +225,15 @@ type KeyRepository interface {
 
 	// Remove removes Key with provided ID.
 	Remove(context.Context, string, string) error
+
+	// RetrieveByIssuer retrieves the Keys of the provided issuer scoped to
+	// the provided organization, ordered by the issue time.
+	RetrieveByIssuer(ctx context.Context, issuerID, orgID string, offset, limit uint64) (KeysPage, error)
+
+	// UpdateLabel updates the label of the Key with provided ID.
+	UpdateLabel(ctx context.Context, issuerID, id, label string) error
+
+	// RemoveByIssuer removes all the Keys of the provided issuer, returning
+	// the removed Keys.
+	RemoveByIssuer(context.Context, string) ([]Key, error)
 }
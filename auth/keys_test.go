@@ -58,3 +58,105 @@ func TestExpired(t *testing.T) {
 		assert.Equal(t, tc.expired, res, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.expired, res))
 	}
 }
+
+func TestAllows(t *testing.T) {
+	cases := []struct {
+		desc   string
+		scopes []string
+		scope  string
+		allows bool
+	}{
+		{
+			desc:   "unscoped key",
+			scope:  "things:write",
+			allows: true,
+		},
+		{
+			desc:   "scoped action",
+			scopes: []string{"things:read", "messages:write"},
+			scope:  "messages:write",
+			allows: true,
+		},
+		{
+			desc:   "action out of scope",
+			scopes: []string{"things:read"},
+			scope:  "things:write",
+			allows: false,
+		},
+		{
+			desc:   "wildcard action",
+			scopes: []string{"things:*"},
+			scope:  "things:delete",
+			allows: true,
+		},
+		{
+			desc:   "wildcard resource",
+			scopes: []string{"*:read"},
+			scope:  "channels:read",
+			allows: true,
+		},
+		{
+			desc:   "malformed scope",
+			scopes: []string{"*:*"},
+			scope:  "things",
+			allows: false,
+		},
+	}
+
+	for _, tc := range cases {
+		key := auth.Key{Type: auth.APIKey, Scopes: tc.scopes}
+		res := key.Allows(tc.scope)
+		assert.Equal(t, tc.allows, res, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.allows, res))
+	}
+}
+
+func TestAllowsIP(t *testing.T) {
+	cases := []struct {
+		desc    string
+		allowed []string
+		ip      string
+		allows  bool
+	}{
+		{
+			desc:   "key without allowlist",
+			ip:     "203.0.113.7",
+			allows: true,
+		},
+		{
+			desc:    "address in allowed network",
+			allowed: []string{"10.0.0.0/8", "192.168.1.0/24"},
+			ip:      "192.168.1.42",
+			allows:  true,
+		},
+		{
+			desc:    "address outside of allowed networks",
+			allowed: []string{"10.0.0.0/8"},
+			ip:      "203.0.113.7",
+			allows:  false,
+		},
+		{
+			desc:    "allowed single address",
+			allowed: []string{"203.0.113.7"},
+			ip:      "203.0.113.7",
+			allows:  true,
+		},
+		{
+			desc:    "address in allowed IPv6 network",
+			allowed: []string{"2001:db8::/32"},
+			ip:      "2001:db8::1",
+			allows:  true,
+		},
+		{
+			desc:    "unknown address",
+			allowed: []string{"10.0.0.0/8"},
+			ip:      "",
+			allows:  false,
+		},
+	}
+
+	for _, tc := range cases {
+		key := auth.Key{Type: auth.APIKey, AllowedIPs: tc.allowed}
+		res := key.AllowsIP(tc.ip)
+		assert.Equal(t, tc.allows, res, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.allows, res))
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+var _ auth.ServiceAccountRepository = (*accountRepositoryMock)(nil)
+
+type accountRepositoryMock struct {
+	mu       sync.Mutex
+	accounts map[string]auth.ServiceAccount
+}
+
+// NewServiceAccountRepository creates in-memory service account repository.
+func NewServiceAccountRepository() auth.ServiceAccountRepository {
+	return &accountRepositoryMock{
+		accounts: make(map[string]auth.ServiceAccount),
+	}
+}
+
+func (arm *accountRepositoryMock) Save(ctx context.Context, sa auth.ServiceAccount) error {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	for _, a := range arm.accounts {
+		if a.ID == sa.ID || (a.OwnerID == sa.OwnerID && a.Name == sa.Name) {
+			return auth.ErrConflict
+		}
+	}
+
+	arm.accounts[sa.ID] = sa
+	return nil
+}
+
+func (arm *accountRepositoryMock) RetrieveByID(ctx context.Context, id string) (auth.ServiceAccount, error) {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	if sa, ok := arm.accounts[id]; ok {
+		return sa, nil
+	}
+
+	return auth.ServiceAccount{}, auth.ErrNotFound
+}
+
+func (arm *accountRepositoryMock) RetrieveByOwner(ctx context.Context, ownerID string) ([]auth.ServiceAccount, error) {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	var accounts []auth.ServiceAccount
+	for _, sa := range arm.accounts {
+		if sa.OwnerID == ownerID {
+			accounts = append(accounts, sa)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].CreatedAt.Before(accounts[j].CreatedAt)
+	})
+
+	return accounts, nil
+}
+
+func (arm *accountRepositoryMock) UpdateScopes(ctx context.Context, id string, scopes []string) error {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	sa, ok := arm.accounts[id]
+	if !ok {
+		return auth.ErrNotFound
+	}
+
+	sa.Scopes = scopes
+	arm.accounts[id] = sa
+	return nil
+}
+
+func (arm *accountRepositoryMock) UpdateStatus(ctx context.Context, id string, disabled bool) error {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	sa, ok := arm.accounts[id]
+	if !ok {
+		return auth.ErrNotFound
+	}
+
+	sa.Disabled = disabled
+	arm.accounts[id] = sa
+	return nil
+}
+
+func (arm *accountRepositoryMock) Remove(ctx context.Context, id string) error {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	delete(arm.accounts, id)
+	return nil
+}
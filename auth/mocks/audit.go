@@ -0,0 +1,69 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+var _ auth.AuditRepository = (*auditRepositoryMock)(nil)
+
+type auditRepositoryMock struct {
+	mu      sync.Mutex
+	records []auth.AuditRecord
+}
+
+// NewAuditRepository creates in-memory audit repository.
+func NewAuditRepository() auth.AuditRepository {
+	return &auditRepositoryMock{}
+}
+
+func (arm *auditRepositoryMock) Save(ctx context.Context, r auth.AuditRecord) error {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	r.ID = uint64(len(arm.records) + 1)
+	arm.records = append(arm.records, r)
+	return nil
+}
+
+func (arm *auditRepositoryMock) RetrieveAll(ctx context.Context, filter auth.AuditFilter, offset, limit uint64) (auth.AuditPage, error) {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	page := auth.AuditPage{
+		Offset:  offset,
+		Limit:   limit,
+		Records: []auth.AuditRecord{},
+	}
+	for _, r := range arm.records {
+		if !matchAudit(r, filter) {
+			continue
+		}
+		if page.Total >= offset && uint64(len(page.Records)) < limit {
+			page.Records = append(page.Records, r)
+		}
+		page.Total++
+	}
+
+	return page, nil
+}
+
+func matchAudit(r auth.AuditRecord, f auth.AuditFilter) bool {
+	switch {
+	case f.Operation != "" && f.Operation != r.Operation,
+		f.Subject != "" && f.Subject != r.Subject,
+		f.Object != "" && f.Object != r.Object,
+		f.Relation != "" && f.Relation != r.Relation,
+		f.Decision != "" && f.Decision != r.Decision,
+		!f.From.IsZero() && r.CreatedAt.Before(f.From),
+		!f.To.IsZero() && !r.CreatedAt.Before(f.To):
+		return false
+	default:
+		return true
+	}
+}
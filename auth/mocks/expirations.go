@@ -0,0 +1,60 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+var _ auth.PolicyExpirationRepository = (*expirationRepositoryMock)(nil)
+
+type expirationRepositoryMock struct {
+	mu          sync.Mutex
+	expirations map[string]auth.PolicyExpiration
+}
+
+// NewPolicyExpirationRepository creates in-memory policy expiration repository.
+func NewPolicyExpirationRepository() auth.PolicyExpirationRepository {
+	return &expirationRepositoryMock{
+		expirations: make(map[string]auth.PolicyExpiration),
+	}
+}
+
+func (erm *expirationRepositoryMock) Save(ctx context.Context, pe auth.PolicyExpiration) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	erm.expirations[policyKey(pe.Subject, pe.Object, pe.Relation, pe.Namespace)] = pe
+	return nil
+}
+
+func (erm *expirationRepositoryMock) Retrieve(ctx context.Context, pr auth.PolicyReq) (auth.PolicyExpiration, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	pe, ok := erm.expirations[policyKey(pr.Subject, pr.Object, pr.Relation, pr.Namespace)]
+	if !ok {
+		return auth.PolicyExpiration{}, auth.ErrNotFound
+	}
+	return pe, nil
+}
+
+func (erm *expirationRepositoryMock) Remove(ctx context.Context, pr auth.PolicyReq) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	delete(erm.expirations, policyKey(pr.Subject, pr.Object, pr.Relation, pr.Namespace))
+	return nil
+}
+
+func policyKey(subject, object, relation, namespace string) string {
+	if namespace == "" {
+		namespace = auth.MembersNamespace
+	}
+	return fmt.Sprintf("%s|%s|%s|%s", namespace, subject, object, relation)
+}
@@ -6,6 +6,7 @@ package mocks
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -38,6 +39,14 @@ type groupRepositoryMock struct {
 	// is an element in the map members where group id is a key.
 	// members     map[type][GroupID]map[MemberID]MemberID
 	members map[string]map[string]map[string]string
+	// Map of membership deadlines where member id is a key
+	// is an element in the map expirations where group id is a key.
+	// expirations map[GroupID]map[MemberID]time.Time
+	expirations map[string]map[string]time.Time
+	// Map of membership metadata where member id is a key
+	// is an element in the map relations where group id is a key.
+	// relations map[GroupID]map[MemberID]auth.Member
+	relations map[string]map[string]auth.Member
 }
 
 // NewGroupRepository creates in-memory user repository
@@ -48,6 +57,8 @@ func NewGroupRepository() auth.GroupRepository {
 		parents:     make(map[string]string),
 		memberships: make(map[string]map[string]auth.Group),
 		members:     make(map[string]map[string]map[string]string),
+		expirations: make(map[string]map[string]time.Time),
+		relations:   make(map[string]map[string]auth.Member),
 	}
 }
 
@@ -89,6 +100,7 @@ func (grm *groupRepositoryMock) Update(ctx context.Context, group auth.Group) (a
 	up.Name = group.Name
 	up.Description = group.Description
 	up.Metadata = group.Metadata
+	up.Labels = group.Labels
 	up.UpdatedAt = time.Now()
 
 	grm.groups[group.ID] = up
@@ -141,6 +153,9 @@ func (grm *groupRepositoryMock) RetrieveAll(ctx context.Context, pm auth.PageMet
 	defer grm.mu.Unlock()
 	var items []auth.Group
 	for _, g := range grm.groups {
+		if g.OrgID != pm.OrgID || !pm.Selector.Matches(g.Labels) || !containsMetadata(g.Metadata, pm.Metadata) {
+			continue
+		}
 		items = append(items, g)
 	}
 	return auth.GroupPage{
@@ -166,12 +181,14 @@ func (grm *groupRepositoryMock) Unassign(ctx context.Context, groupID string, me
 			delete(grm.members[groupID][typ], memberID)
 			delete(grm.memberships[memberID], groupID)
 		}
+		delete(grm.expirations[groupID], memberID)
+		delete(grm.relations[groupID], memberID)
 
 	}
 	return nil
 }
 
-func (grm *groupRepositoryMock) Assign(ctx context.Context, groupID, groupType string, memberIDs ...string) error {
+func (grm *groupRepositoryMock) Assign(ctx context.Context, groupID, groupType string, m auth.Membership, memberIDs ...string) error {
 	grm.mu.Lock()
 	defer grm.mu.Unlock()
 	if _, ok := grm.groups[groupID]; !ok {
@@ -181,6 +198,9 @@ func (grm *groupRepositoryMock) Assign(ctx context.Context, groupID, groupType s
 	if _, ok := grm.members[groupID]; !ok {
 		grm.members[groupID] = make(map[string]map[string]string)
 	}
+	if _, ok := grm.relations[groupID]; !ok {
+		grm.relations[groupID] = make(map[string]auth.Member)
+	}
 
 	for _, memberID := range memberIDs {
 		if _, ok := grm.members[groupID][groupType]; !ok {
@@ -192,9 +212,72 @@ func (grm *groupRepositoryMock) Assign(ctx context.Context, groupID, groupType s
 
 		grm.members[groupID][groupType][memberID] = memberID
 		grm.memberships[memberID][groupID] = grm.groups[groupID]
+		grm.relations[groupID][memberID] = auth.Member{
+			ID:        memberID,
+			Type:      groupType,
+			Relation:  m.Relation,
+			CreatedBy: m.CreatedBy,
+			CreatedAt: time.Now(),
+		}
+	}
+	return nil
+
+}
+
+func (grm *groupRepositoryMock) SetExpiration(ctx context.Context, groupID string, expiresAt time.Time, memberIDs ...string) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+	for _, memberID := range memberIDs {
+		if _, ok := grm.memberships[memberID][groupID]; !ok {
+			return auth.ErrNotFound
+		}
+	}
+
+	if _, ok := grm.expirations[groupID]; !ok {
+		grm.expirations[groupID] = make(map[string]time.Time)
+	}
+	for _, memberID := range memberIDs {
+		grm.expirations[groupID][memberID] = expiresAt
 	}
 	return nil
+}
+
+func (grm *groupRepositoryMock) SetRelation(ctx context.Context, groupID, relation string, memberIDs ...string) error {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+	for _, memberID := range memberIDs {
+		if _, ok := grm.relations[groupID][memberID]; !ok {
+			return auth.ErrNotFound
+		}
+	}
 
+	for _, memberID := range memberIDs {
+		m := grm.relations[groupID][memberID]
+		m.Relation = relation
+		grm.relations[groupID][memberID] = m
+	}
+	return nil
+}
+
+func (grm *groupRepositoryMock) RetrieveExpired(ctx context.Context, before time.Time) ([]auth.MembershipExpiration, error) {
+	grm.mu.Lock()
+	defer grm.mu.Unlock()
+	var items []auth.MembershipExpiration
+	for groupID, exps := range grm.expirations {
+		for memberID, expiresAt := range exps {
+			if !expiresAt.Before(before) {
+				continue
+			}
+			me := auth.MembershipExpiration{GroupID: groupID, MemberID: memberID, ExpiresAt: expiresAt}
+			for typ, m := range grm.members[groupID] {
+				if _, ok := m[memberID]; ok {
+					me.Type = typ
+				}
+			}
+			items = append(items, me)
+		}
+	}
+	return items, nil
 }
 
 func (grm *groupRepositoryMock) Memberships(ctx context.Context, memberID string, pm auth.PageMetadata) (auth.GroupPage, error) {
@@ -207,6 +290,9 @@ func (grm *groupRepositoryMock) Memberships(ctx context.Context, memberID string
 
 	i := uint64(0)
 	for _, g := range grm.memberships[memberID] {
+		if g.OrgID != pm.OrgID || !pm.Selector.Matches(g.Labels) || !containsMetadata(g.Metadata, pm.Metadata) {
+			continue
+		}
 		if i >= first && i < last {
 			items = append(items, g)
 		}
@@ -227,25 +313,47 @@ func (grm *groupRepositoryMock) Members(ctx context.Context, groupID, groupType
 	grm.mu.Lock()
 	defer grm.mu.Unlock()
 	var items []auth.Member
-	members, ok := grm.members[groupID][groupType]
-	if !ok {
-		return auth.MemberPage{}, auth.ErrGroupNotFound
+	// The empty group type stands for the members of all types.
+	members := make(map[string]string)
+	if groupType == "" {
+		for typ, ms := range grm.members[groupID] {
+			for id := range ms {
+				members[id] = typ
+			}
+		}
+	} else {
+		ms, ok := grm.members[groupID][groupType]
+		if !ok {
+			return auth.MemberPage{}, auth.ErrGroupNotFound
+		}
+		for id := range ms {
+			members[id] = groupType
+		}
 	}
 
 	first := uint64(pm.Offset)
 	last := first + uint64(pm.Limit)
 
 	i := uint64(0)
-	for _, g := range members {
-		if i >= first && i < last {
-			items = append(items, auth.Member{ID: g, Type: groupType})
+	for g, typ := range members {
+		m, ok := grm.relations[groupID][g]
+		if !ok {
+			m = auth.Member{ID: g, Type: typ}
+		}
+		if pm.Relation != "" && m.Relation != pm.Relation {
+			continue
+		}
+		if i >= first && (pm.Limit == 0 || i < last) {
+			items = append(items, m)
 		}
 		i++
 	}
 	return auth.MemberPage{
 		Members: items,
 		PageMetadata: auth.PageMetadata{
-			Total: uint64(len(items)),
+			Total:  i,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
 		},
 	}, nil
 }
@@ -297,14 +405,12 @@ func (grm *groupRepositoryMock) RetrieveAllChildren(ctx context.Context, groupID
 		return auth.GroupPage{}, nil
 	}
 
+	// The descendants share the path prefix of the group.
 	groups := make([]auth.Group, 0)
-	groups = append(groups, group)
-	for ch := range grm.parents {
-		g, ok := grm.groups[ch]
-		if !ok {
-			panic(fmt.Sprintf("child with id %s not found", ch))
+	for _, g := range grm.groups {
+		if g.Path == group.Path || strings.HasPrefix(g.Path, group.Path+".") {
+			groups = append(groups, g)
 		}
-		groups = append(groups, g)
 	}
 
 	return auth.GroupPage{
@@ -316,3 +422,27 @@ func (grm *groupRepositoryMock) RetrieveAllChildren(ctx context.Context, groupID
 		},
 	}, nil
 }
+
+// containsMetadata mimics the JSONB containment of the metadata filter: the
+// nested objects are matched recursively, and the other values are matched
+// as a whole.
+func containsMetadata(m, filter map[string]interface{}) bool {
+	for k, fv := range filter {
+		v, ok := m[k]
+		if !ok {
+			return false
+		}
+		fm, fok := fv.(map[string]interface{})
+		vm, vok := v.(map[string]interface{})
+		if fok && vok {
+			if !containsMetadata(vm, fm) {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(v, fv) {
+			return false
+		}
+	}
+	return true
+}
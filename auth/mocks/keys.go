@@ -5,6 +5,7 @@ package mocks
 
 import (
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/mainflux/mainflux/auth"
@@ -53,3 +54,56 @@ func (krm *keyRepositoryMock) Remove(ctx context.Context, issuerID, id string) e
 	}
 	return nil
 }
+
+func (krm *keyRepositoryMock) RetrieveByIssuer(ctx context.Context, issuerID, orgID string, offset, limit uint64) (auth.KeysPage, error) {
+	krm.mu.Lock()
+	defer krm.mu.Unlock()
+
+	var keys []auth.Key
+	for _, key := range krm.keys {
+		if key.IssuerID == issuerID && key.OrgID == orgID {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].IssuedAt.Equal(keys[j].IssuedAt) {
+			return keys[i].ID < keys[j].ID
+		}
+		return keys[i].IssuedAt.Before(keys[j].IssuedAt)
+	})
+	start, end := bounds(len(keys), offset, limit)
+
+	return auth.KeysPage{
+		Total:  uint64(len(keys)),
+		Offset: offset,
+		Limit:  limit,
+		Keys:   keys[start:end],
+	}, nil
+}
+
+func (krm *keyRepositoryMock) UpdateLabel(ctx context.Context, issuerID, id, label string) error {
+	krm.mu.Lock()
+	defer krm.mu.Unlock()
+
+	key, ok := krm.keys[id]
+	if !ok || key.IssuerID != issuerID {
+		return auth.ErrNotFound
+	}
+	key.Label = label
+	krm.keys[id] = key
+	return nil
+}
+
+func (krm *keyRepositoryMock) RemoveByIssuer(ctx context.Context, issuerID string) ([]auth.Key, error) {
+	krm.mu.Lock()
+	defer krm.mu.Unlock()
+
+	var keys []auth.Key
+	for id, key := range krm.keys {
+		if key.IssuerID == issuerID {
+			keys = append(keys, key)
+			delete(krm.keys, id)
+		}
+	}
+	return keys, nil
+}
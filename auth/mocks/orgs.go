@@ -0,0 +1,174 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+var _ auth.OrgRepository = (*orgRepositoryMock)(nil)
+
+type orgRepositoryMock struct {
+	mu   sync.Mutex
+	orgs map[string]auth.Org
+	// members map[OrgID]map[MemberID]auth.OrgMember
+	members map[string]map[string]auth.OrgMember
+}
+
+// NewOrgRepository creates in-memory organization repository.
+func NewOrgRepository() auth.OrgRepository {
+	return &orgRepositoryMock{
+		orgs:    make(map[string]auth.Org),
+		members: make(map[string]map[string]auth.OrgMember),
+	}
+}
+
+func (orm *orgRepositoryMock) Save(ctx context.Context, o auth.Org) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	if _, ok := orm.orgs[o.ID]; ok {
+		return auth.ErrConflict
+	}
+
+	orm.orgs[o.ID] = o
+	orm.members[o.ID] = map[string]auth.OrgMember{
+		o.OwnerID: {ID: o.OwnerID, CreatedBy: o.OwnerID, CreatedAt: o.CreatedAt},
+	}
+	return nil
+}
+
+func (orm *orgRepositoryMock) Update(ctx context.Context, o auth.Org) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	if _, ok := orm.orgs[o.ID]; !ok {
+		return auth.ErrNotFound
+	}
+
+	orm.orgs[o.ID] = o
+	return nil
+}
+
+func (orm *orgRepositoryMock) Remove(ctx context.Context, id string) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	delete(orm.orgs, id)
+	delete(orm.members, id)
+	return nil
+}
+
+func (orm *orgRepositoryMock) RetrieveByID(ctx context.Context, id string) (auth.Org, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	if o, ok := orm.orgs[id]; ok {
+		return o, nil
+	}
+
+	return auth.Org{}, auth.ErrNotFound
+}
+
+func (orm *orgRepositoryMock) RetrieveByMember(ctx context.Context, memberID string, offset, limit uint64) (auth.OrgsPage, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	var orgs []auth.Org
+	for id, members := range orm.members {
+		if _, ok := members[memberID]; ok {
+			orgs = append(orgs, orm.orgs[id])
+		}
+	}
+	sort.Slice(orgs, func(i, j int) bool {
+		return orgs[i].ID < orgs[j].ID
+	})
+	start, end := bounds(len(orgs), offset, limit)
+
+	return auth.OrgsPage{
+		Total:  uint64(len(orgs)),
+		Offset: offset,
+		Limit:  limit,
+		Orgs:   orgs[start:end],
+	}, nil
+}
+
+func (orm *orgRepositoryMock) AssignMembers(ctx context.Context, orgID, createdBy string, memberIDs ...string) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	members, ok := orm.members[orgID]
+	if !ok {
+		return auth.ErrNotFound
+	}
+	for _, id := range memberIDs {
+		if _, ok := members[id]; ok {
+			return auth.ErrMemberAlreadyAssigned
+		}
+	}
+
+	for _, id := range memberIDs {
+		members[id] = auth.OrgMember{ID: id, CreatedBy: createdBy, CreatedAt: time.Now()}
+	}
+	return nil
+}
+
+func (orm *orgRepositoryMock) UnassignMembers(ctx context.Context, orgID string, memberIDs ...string) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	for _, id := range memberIDs {
+		delete(orm.members[orgID], id)
+	}
+	return nil
+}
+
+func (orm *orgRepositoryMock) RetrieveMembers(ctx context.Context, orgID string, offset, limit uint64) (auth.OrgMembersPage, error) {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	var members []auth.OrgMember
+	for _, m := range orm.members[orgID] {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+	start, end := bounds(len(members), offset, limit)
+
+	return auth.OrgMembersPage{
+		Total:   uint64(len(members)),
+		Offset:  offset,
+		Limit:   limit,
+		Members: members[start:end],
+	}, nil
+}
+
+func (orm *orgRepositoryMock) IsMember(ctx context.Context, orgID, memberID string) error {
+	orm.mu.Lock()
+	defer orm.mu.Unlock()
+
+	if _, ok := orm.members[orgID][memberID]; ok {
+		return nil
+	}
+	return auth.ErrOrgMember
+}
+
+// bounds returns the bounds of the page of the n items.
+func bounds(n int, offset, limit uint64) (uint64, uint64) {
+	total := uint64(n)
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end
+}
@@ -66,11 +66,23 @@ func (pa *policyAgentMock) RetrievePolicies(ctx context.Context, pr auth.PolicyR
 	pa.mu.Lock()
 	defer pa.mu.Unlock()
 
-	ssList := pa.authzDB[pr.Subject]
 	tuple := []*acl.RelationTuple{}
-	for _, ss := range ssList {
-		if ss.Relation == pr.Relation {
-			tuple = append(tuple, &acl.RelationTuple{Object: ss.Object, Relation: ss.Relation})
+	for subject, ssList := range pa.authzDB {
+		if pr.Subject != "" && subject != pr.Subject {
+			continue
+		}
+		for _, ss := range ssList {
+			if ss.Object == "" {
+				// Deleted policy.
+				continue
+			}
+			if (pr.Object == "" || ss.Object == pr.Object) && (pr.Relation == "" || ss.Relation == pr.Relation) {
+				tuple = append(tuple, &acl.RelationTuple{
+					Object:   ss.Object,
+					Relation: ss.Relation,
+					Subject:  &acl.Subject{Ref: &acl.Subject_Id{Id: subject}},
+				})
+			}
 		}
 	}
 	return tuple, nil
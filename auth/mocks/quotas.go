@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var _ auth.QuotaRepository = (*quotaRepositoryMock)(nil)
+
+type quotaRepositoryMock struct {
+	mu     sync.Mutex
+	quotas map[string]auth.Quota
+	// usage maps organization and resource to the charged resource IDs.
+	usage map[string]map[string]map[string]bool
+}
+
+// NewQuotaRepository creates in-memory quota repository.
+func NewQuotaRepository() auth.QuotaRepository {
+	return &quotaRepositoryMock{
+		quotas: make(map[string]auth.Quota),
+		usage:  make(map[string]map[string]map[string]bool),
+	}
+}
+
+func (qrm *quotaRepositoryMock) Save(ctx context.Context, q auth.Quota) error {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	qrm.quotas[q.OrgID] = q
+	return nil
+}
+
+func (qrm *quotaRepositoryMock) RetrieveByOrg(ctx context.Context, orgID string) (auth.Quota, error) {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	q, ok := qrm.quotas[orgID]
+	if !ok {
+		return auth.Quota{}, auth.ErrNotFound
+	}
+	return q, nil
+}
+
+func (qrm *quotaRepositoryMock) Usage(ctx context.Context, orgID string) (auth.Usage, error) {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	return auth.Usage{
+		Things:   uint64(len(qrm.usage[orgID][auth.ThingsResource])),
+		Channels: uint64(len(qrm.usage[orgID][auth.ChannelsResource])),
+	}, nil
+}
+
+func (qrm *quotaRepositoryMock) Charge(ctx context.Context, orgID, resource string, limit uint64, ids ...string) error {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	if _, ok := qrm.usage[orgID]; !ok {
+		qrm.usage[orgID] = make(map[string]map[string]bool)
+	}
+	if _, ok := qrm.usage[orgID][resource]; !ok {
+		qrm.usage[orgID][resource] = make(map[string]bool)
+	}
+
+	charged := qrm.usage[orgID][resource]
+	if limit > 0 && uint64(len(charged)+len(ids)) > limit {
+		return errors.Wrap(auth.ErrQuotaExceeded, fmt.Errorf("organization %s allows at most %d %s, %d used", orgID, limit, resource, len(charged)))
+	}
+
+	for _, id := range ids {
+		charged[id] = true
+	}
+	return nil
+}
+
+func (qrm *quotaRepositoryMock) Release(ctx context.Context, resource string, ids ...string) error {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	for _, usage := range qrm.usage {
+		for _, id := range ids {
+			delete(usage[resource], id)
+		}
+	}
+	return nil
+}
+
+func (qrm *quotaRepositoryMock) RetrieveOrgs(ctx context.Context, resource, id string) ([]string, error) {
+	qrm.mu.Lock()
+	defer qrm.mu.Unlock()
+
+	var orgs []string
+	for orgID, usage := range qrm.usage {
+		if usage[resource][id] {
+			orgs = append(orgs, orgID)
+		}
+	}
+	return orgs, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+var _ auth.RevocationRepository = (*revocationRepositoryMock)(nil)
+
+type revocationRepositoryMock struct {
+	mu          sync.Mutex
+	revocations map[string]auth.Revocation
+	issuers     map[string]time.Time
+}
+
+// NewRevocationRepository creates in-memory key revocation repository.
+func NewRevocationRepository() auth.RevocationRepository {
+	return &revocationRepositoryMock{
+		revocations: make(map[string]auth.Revocation),
+		issuers:     make(map[string]time.Time),
+	}
+}
+
+func (rrm *revocationRepositoryMock) Save(ctx context.Context, r auth.Revocation) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	rrm.revocations[r.KeyID] = r
+	return nil
+}
+
+func (rrm *revocationRepositoryMock) Contains(ctx context.Context, keyID string) (bool, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	_, ok := rrm.revocations[keyID]
+	return ok, nil
+}
+
+func (rrm *revocationRepositoryMock) SaveIssuer(ctx context.Context, issuerID string, revokedAt time.Time) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if revokedAt.After(rrm.issuers[issuerID]) {
+		rrm.issuers[issuerID] = revokedAt
+	}
+	return nil
+}
+
+func (rrm *revocationRepositoryMock) RetrieveIssuer(ctx context.Context, issuerID string) (time.Time, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	return rrm.issuers[issuerID], nil
+}
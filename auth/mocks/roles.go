@@ -0,0 +1,119 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+var _ auth.RoleRepository = (*roleRepositoryMock)(nil)
+
+type roleRepositoryMock struct {
+	mu          sync.Mutex
+	roles       map[string]auth.Role
+	assignments map[string]auth.RoleAssignment
+}
+
+// NewRoleRepository creates in-memory role repository.
+func NewRoleRepository() auth.RoleRepository {
+	return &roleRepositoryMock{
+		roles:       make(map[string]auth.Role),
+		assignments: make(map[string]auth.RoleAssignment),
+	}
+}
+
+func (rrm *roleRepositoryMock) Save(ctx context.Context, r auth.Role) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if _, ok := rrm.roles[r.Name]; ok {
+		return auth.ErrConflict
+	}
+
+	rrm.roles[r.Name] = r
+	return nil
+}
+
+func (rrm *roleRepositoryMock) RetrieveByName(ctx context.Context, name string) (auth.Role, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	if r, ok := rrm.roles[name]; ok {
+		return r, nil
+	}
+
+	return auth.Role{}, auth.ErrNotFound
+}
+
+func (rrm *roleRepositoryMock) RetrieveAll(ctx context.Context) ([]auth.Role, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	var roles []auth.Role
+	for _, r := range rrm.roles {
+		roles = append(roles, r)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Name < roles[j].Name
+	})
+
+	return roles, nil
+}
+
+func (rrm *roleRepositoryMock) Remove(ctx context.Context, name string) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	delete(rrm.roles, name)
+	return nil
+}
+
+func (rrm *roleRepositoryMock) SaveAssignment(ctx context.Context, ra auth.RoleAssignment) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	key := assignmentKey(ra)
+	if _, ok := rrm.assignments[key]; ok {
+		return auth.ErrConflict
+	}
+
+	rrm.assignments[key] = ra
+	return nil
+}
+
+func (rrm *roleRepositoryMock) RetrieveAssignments(ctx context.Context, ra auth.RoleAssignment) ([]auth.RoleAssignment, error) {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	var assignments []auth.RoleAssignment
+	for _, a := range rrm.assignments {
+		if (ra.Role == "" || ra.Role == a.Role) &&
+			(ra.Subject == "" || ra.Subject == a.Subject) &&
+			(ra.Object == "" || ra.Object == a.Object) {
+			assignments = append(assignments, a)
+		}
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		return assignments[i].CreatedAt.Before(assignments[j].CreatedAt)
+	})
+
+	return assignments, nil
+}
+
+func (rrm *roleRepositoryMock) RemoveAssignment(ctx context.Context, ra auth.RoleAssignment) error {
+	rrm.mu.Lock()
+	defer rrm.mu.Unlock()
+
+	delete(rrm.assignments, assignmentKey(ra))
+	return nil
+}
+
+func assignmentKey(ra auth.RoleAssignment) string {
+	return fmt.Sprintf("%s|%s|%s", ra.Role, ra.Subject, ra.Object)
+}
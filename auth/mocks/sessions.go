@@ -0,0 +1,112 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+var _ auth.SessionRepository = (*sessionRepositoryMock)(nil)
+
+type sessionRepositoryMock struct {
+	mu       sync.Mutex
+	sessions map[string]auth.Session
+}
+
+// NewSessionRepository creates in-memory session repository.
+func NewSessionRepository() auth.SessionRepository {
+	return &sessionRepositoryMock{
+		sessions: make(map[string]auth.Session),
+	}
+}
+
+func (srm *sessionRepositoryMock) Save(ctx context.Context, s auth.Session) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	prev, ok := srm.sessions[s.ID]
+	if !ok {
+		srm.sessions[s.ID] = s
+		return nil
+	}
+	if prev.UserID != s.UserID {
+		return nil
+	}
+	if s.IP != "" {
+		prev.IP = s.IP
+	}
+	if s.LastSeenAt.After(prev.LastSeenAt) {
+		prev.LastSeenAt = s.LastSeenAt
+	}
+	if s.ExpiresAt.After(prev.ExpiresAt) {
+		prev.ExpiresAt = s.ExpiresAt
+	}
+	srm.sessions[s.ID] = prev
+	return nil
+}
+
+func (srm *sessionRepositoryMock) Retrieve(ctx context.Context, userID, id string) (auth.Session, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	s, ok := srm.sessions[id]
+	if !ok || s.UserID != userID || s.ExpiresAt.Before(time.Now()) {
+		return auth.Session{}, auth.ErrNotFound
+	}
+	return s, nil
+}
+
+func (srm *sessionRepositoryMock) RetrieveByUser(ctx context.Context, userID string) ([]auth.Session, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	sessions := []auth.Session{}
+	for _, s := range srm.sessions {
+		if s.UserID == userID && s.ExpiresAt.After(time.Now()) {
+			sessions = append(sessions, s)
+		}
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (srm *sessionRepositoryMock) Touch(ctx context.Context, id string, seenAt time.Time) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if s, ok := srm.sessions[id]; ok && seenAt.After(s.LastSeenAt) {
+		s.LastSeenAt = seenAt
+		srm.sessions[id] = s
+	}
+	return nil
+}
+
+func (srm *sessionRepositoryMock) Remove(ctx context.Context, userID, id string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if s, ok := srm.sessions[id]; ok && s.UserID == userID {
+		delete(srm.sessions, id)
+	}
+	return nil
+}
+
+func (srm *sessionRepositoryMock) RemoveByUser(ctx context.Context, userID string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	for id, s := range srm.sessions {
+		if s.UserID == userID {
+			delete(srm.sessions, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/auth"
+)
+
+var _ auth.ShareRepository = (*shareRepositoryMock)(nil)
+
+type shareRepositoryMock struct {
+	mu     sync.Mutex
+	shares map[string]auth.Share
+}
+
+// NewShareRepository creates in-memory share repository.
+func NewShareRepository() auth.ShareRepository {
+	return &shareRepositoryMock{
+		shares: make(map[string]auth.Share),
+	}
+}
+
+func (srm *shareRepositoryMock) Save(ctx context.Context, s auth.Share) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if _, ok := srm.shares[s.ID]; ok {
+		return auth.ErrConflict
+	}
+
+	srm.shares[s.ID] = s
+	return nil
+}
+
+func (srm *shareRepositoryMock) RetrieveByID(ctx context.Context, id string) (auth.Share, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	if s, ok := srm.shares[id]; ok {
+		return s, nil
+	}
+
+	return auth.Share{}, auth.ErrNotFound
+}
+
+func (srm *shareRepositoryMock) RetrieveByGrantor(ctx context.Context, grantorID string) ([]auth.Share, error) {
+	return srm.filter(func(s auth.Share) bool { return s.GrantorID == grantorID }), nil
+}
+
+func (srm *shareRepositoryMock) RetrieveByGrantee(ctx context.Context, granteeID string) ([]auth.Share, error) {
+	return srm.filter(func(s auth.Share) bool { return s.GranteeID == granteeID }), nil
+}
+
+func (srm *shareRepositoryMock) RetrieveByObject(ctx context.Context, granteeID, object string) ([]auth.Share, error) {
+	return srm.filter(func(s auth.Share) bool { return s.GranteeID == granteeID && s.Object == object }), nil
+}
+
+func (srm *shareRepositoryMock) Remove(ctx context.Context, id string) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	delete(srm.shares, id)
+	return nil
+}
+
+func (srm *shareRepositoryMock) filter(match func(auth.Share) bool) []auth.Share {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	var shares []auth.Share
+	for _, s := range srm.shares {
+		if match(s) {
+			shares = append(shares, s)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+	})
+
+	return shares
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/auth/oidc"
+)
+
+var _ oidc.CodeRepository = (*codeRepositoryMock)(nil)
+
+type codeRepositoryMock struct {
+	mu    sync.Mutex
+	codes map[string]oidc.Code
+}
+
+// NewCodeRepository creates in-memory authorization code repository.
+func NewCodeRepository() oidc.CodeRepository {
+	return &codeRepositoryMock{
+		codes: make(map[string]oidc.Code),
+	}
+}
+
+func (crm *codeRepositoryMock) Save(ctx context.Context, code oidc.Code) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	crm.codes[code.Code] = code
+	return nil
+}
+
+func (crm *codeRepositoryMock) Remove(ctx context.Context, code string) (oidc.Code, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.codes[code]
+	if !ok {
+		return oidc.Code{}, oidc.ErrNotFound
+	}
+	delete(crm.codes, code)
+
+	return c, nil
+}
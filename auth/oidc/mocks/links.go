@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/auth/oidc"
+)
+
+var _ oidc.LinkRepository = (*linkRepositoryMock)(nil)
+
+type linkRepositoryMock struct {
+	mu sync.Mutex
+	// links map[Issuer+Subject]Link
+	links map[string]oidc.Link
+}
+
+// NewLinkRepository creates in-memory identity link repository.
+func NewLinkRepository() oidc.LinkRepository {
+	return &linkRepositoryMock{
+		links: make(map[string]oidc.Link),
+	}
+}
+
+func (lrm *linkRepositoryMock) Save(ctx context.Context, link oidc.Link) error {
+	lrm.mu.Lock()
+	defer lrm.mu.Unlock()
+
+	key := link.Issuer + link.Subject
+	if _, ok := lrm.links[key]; ok {
+		return oidc.ErrConflict
+	}
+	lrm.links[key] = link
+	return nil
+}
+
+func (lrm *linkRepositoryMock) Retrieve(ctx context.Context, issuer, subject string) (oidc.Link, error) {
+	lrm.mu.Lock()
+	defer lrm.mu.Unlock()
+
+	l, ok := lrm.links[issuer+subject]
+	if !ok {
+		return oidc.Link{}, oidc.ErrNotFound
+	}
+	return l, nil
+}
+
+func (lrm *linkRepositoryMock) RetrieveByUser(ctx context.Context, userID string) ([]oidc.Link, error) {
+	lrm.mu.Lock()
+	defer lrm.mu.Unlock()
+
+	var links []oidc.Link
+	for _, l := range lrm.links {
+		if l.UserID == userID {
+			links = append(links, l)
+		}
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].Issuer+links[i].Subject < links[j].Issuer+links[j].Subject
+	})
+	return links, nil
+}
+
+func (lrm *linkRepositoryMock) Remove(ctx context.Context, userID, issuer, subject string) error {
+	lrm.mu.Lock()
+	defer lrm.mu.Unlock()
+
+	l, ok := lrm.links[issuer+subject]
+	if !ok || l.UserID != userID {
+		return oidc.ErrNotFound
+	}
+	delete(lrm.links, issuer+subject)
+	return nil
+}
+
+func (lrm *linkRepositoryMock) RemoveByUser(ctx context.Context, userID string) error {
+	lrm.mu.Lock()
+	defer lrm.mu.Unlock()
+
+	for key, l := range lrm.links {
+		if l.UserID == userID {
+			delete(lrm.links, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth/oidc"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	_ oidc.TokenVerifier = (*verifierMock)(nil)
+
+	errInvalidToken = errors.New("invalid external token")
+)
+
+type verifierMock struct {
+	identities map[string]oidc.ExternalIdentity
+}
+
+// NewVerifier creates the external token verifier which accepts only the
+// given tokens, mapped to their identities.
+func NewVerifier(identities map[string]oidc.ExternalIdentity) oidc.TokenVerifier {
+	return verifierMock{identities: identities}
+}
+
+func (vm verifierMock) Verify(ctx context.Context, token string) (oidc.ExternalIdentity, error) {
+	ei, ok := vm.identities[token]
+	if !ok {
+		return oidc.ExternalIdentity{}, errInvalidToken
+	}
+	return ei, nil
+}
@@ -0,0 +1,319 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oidc makes the auth service act as the OpenID Connect provider, so
+// the external tools, e.g. Grafana, can authenticate the users against
+// Mainflux using the authorization code flow.
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// Supported scopes. The openid scope is required by every request, while
+// the email scope adds the user email to the ID token.
+const (
+	OpenIDScope = "openid"
+	EmailScope  = "email"
+)
+
+// S256 is the only supported PKCE code challenge method.
+const S256 = "S256"
+
+// The only supported response and grant types. The token exchange, specified
+// by RFC 8693, exchanges the token of the trusted external identity provider
+// for the Mainflux token.
+const (
+	CodeResponseType      = "code"
+	AuthorizationCodeType = "authorization_code"
+	TokenExchangeType     = "urn:ietf:params:oauth:grant-type:token-exchange"
+)
+
+// The token types of the token exchange. The external ID token or JWT is
+// exchanged for the access token.
+const (
+	IDTokenType     = "urn:ietf:params:oauth:token-type:id_token"
+	JWTTokenType    = "urn:ietf:params:oauth:token-type:jwt"
+	AccessTokenType = "urn:ietf:params:oauth:token-type:access_token"
+)
+
+// The endpoint paths advertised in the discovery document.
+const (
+	AuthorizationPath = "/oauth/authorize"
+	TokenPath         = "/oauth/token"
+	UserInfoPath      = "/oauth/userinfo"
+	JWKSPath          = "/.well-known/jwks.json"
+	LinksPath         = "/oauth/links"
+)
+
+var (
+	// ErrInvalidRequest indicates the missing or malformed request
+	// parameter.
+	ErrInvalidRequest = errors.New("invalid request")
+
+	// ErrInvalidClient indicates the unknown client or the client which
+	// failed to authenticate.
+	ErrInvalidClient = errors.New("invalid client")
+
+	// ErrInvalidGrant indicates the authorization code which is invalid,
+	// expired, already used or issued to another client.
+	ErrInvalidGrant = errors.New("invalid grant")
+
+	// ErrUnsupportedGrantType indicates the grant type other than the
+	// authorization code.
+	ErrUnsupportedGrantType = errors.New("unsupported grant type")
+
+	// ErrInvalidScope indicates the scope without openid.
+	ErrInvalidScope = errors.New("invalid scope")
+
+	// ErrUnauthorizedAccess indicates the missing or invalid user token.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrNotFound indicates the non-existent authorization code or
+	// identity link.
+	ErrNotFound = errors.New("entity not found")
+
+	// ErrConflict indicates the external identity which is already linked.
+	ErrConflict = errors.New("external identity already linked")
+
+	errInvalidClientConfig = errors.New("invalid client configuration")
+	errInvalidIssuerConfig = errors.New("invalid trusted issuer configuration")
+)
+
+// Client represents the relying party, i.e. the external tool registered by
+// the operator to authenticate the users against Mainflux.
+type Client struct {
+	ID     string
+	Secret string
+	// RedirectURIs are the only URIs the authorization codes are sent to.
+	RedirectURIs []string
+}
+
+func (c Client) validRedirect(uri string) bool {
+	for _, u := range c.RedirectURIs {
+		if u == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseClients parses the comma separated clients, each given as
+// id:secret:redirect_uri. The redirect URI may contain colons, and the client
+// with multiple redirect URIs is given once per URI.
+func ParseClients(s string) ([]Client, error) {
+	var clients []Client
+	index := map[string]int{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		parts := strings.SplitN(v, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, errors.Wrap(errInvalidClientConfig, fmt.Errorf("invalid client '%s'", v))
+		}
+		i, ok := index[parts[0]]
+		if !ok {
+			index[parts[0]] = len(clients)
+			clients = append(clients, Client{ID: parts[0], Secret: parts[1], RedirectURIs: []string{parts[2]}})
+			continue
+		}
+		if clients[i].Secret != parts[1] {
+			return nil, errors.Wrap(errInvalidClientConfig, fmt.Errorf("client '%s' has multiple secrets", parts[0]))
+		}
+		clients[i].RedirectURIs = append(clients[i].RedirectURIs, parts[2])
+	}
+
+	return clients, nil
+}
+
+// TrustedIssuer represents the external identity provider whose tokens are
+// exchanged for the Mainflux tokens.
+type TrustedIssuer struct {
+	// URL is the issuer URL, matched against the iss claim of the token.
+	URL string
+	// Audience is the client ID of Mainflux registered at the identity
+	// provider, matched against the aud claim of the token, so the tokens
+	// issued to the other clients aren't accepted.
+	Audience string
+}
+
+// ParseTrustedIssuers parses the comma separated trusted issuers, each given
+// as audience:issuer_url. The issuer URL may contain colons.
+func ParseTrustedIssuers(s string) ([]TrustedIssuer, error) {
+	var issuers []TrustedIssuer
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		parts := strings.SplitN(v, ":", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Wrap(errInvalidIssuerConfig, fmt.Errorf("invalid trusted issuer '%s'", v))
+		}
+		issuers = append(issuers, TrustedIssuer{URL: parts[1], Audience: parts[0]})
+	}
+
+	return issuers, nil
+}
+
+// ExternalIdentity represents the user authenticated by the trusted external
+// identity provider.
+type ExternalIdentity struct {
+	Issuer  string
+	Subject string
+	// Email is the email claimed by the token, if any. It's trusted only
+	// if the identity provider verified it.
+	Email         string
+	EmailVerified bool
+}
+
+// TokenVerifier verifies the tokens of the trusted external identity
+// providers.
+type TokenVerifier interface {
+	// Verify verifies the token and returns the identity it carries.
+	Verify(ctx context.Context, token string) (ExternalIdentity, error)
+}
+
+// Link represents the external identity linked to the Mainflux user, whom
+// the tokens of the external identity are exchanged for.
+type Link struct {
+	Issuer    string
+	Subject   string
+	UserID    string
+	Email     string
+	CreatedAt time.Time
+}
+
+// LinkRepository specifies the identity links persistence API.
+type LinkRepository interface {
+	// Save persists the link. Each external identity is linked to at most
+	// one user.
+	Save(ctx context.Context, link Link) error
+
+	// Retrieve retrieves the link of the external identity.
+	Retrieve(ctx context.Context, issuer, subject string) (Link, error)
+
+	// RetrieveByUser retrieves the links of the user.
+	RetrieveByUser(ctx context.Context, userID string) ([]Link, error)
+
+	// Remove removes the link of the external identity to the user.
+	Remove(ctx context.Context, userID, issuer, subject string) error
+
+	// RemoveByUser removes the links of all the external identities to the
+	// user.
+	RemoveByUser(ctx context.Context, userID string) error
+}
+
+// AuthRequest represents the authorization request of the client, approved
+// by the signed in user.
+type AuthRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// TokenRequest represents the authorization code exchange.
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	ClientID     string
+	ClientSecret string
+	CodeVerifier string
+
+	// SubjectToken is the external token exchanged with the token exchange
+	// grant, of the SubjectTokenType type.
+	SubjectToken     string
+	SubjectTokenType string
+}
+
+// Tokens are issued in exchange for the authorization code. The access token
+// is the regular Mainflux login token, so the client can use it to call the
+// Mainflux APIs on behalf of the user.
+type Tokens struct {
+	AccessToken     string
+	IDToken         string
+	ExpiresAt       time.Time
+	Scope           string
+	IssuedTokenType string
+}
+
+// UserInfo contains the claims about the signed in user.
+type UserInfo struct {
+	Subject string
+	Email   string
+}
+
+// Discovery is the OpenID Provider metadata, published at the well-known
+// location so the clients can configure themselves.
+type Discovery struct {
+	Issuer                            string
+	AuthorizationEndpoint             string
+	TokenEndpoint                     string
+	UserInfoEndpoint                  string
+	JWKSURI                           string
+	ScopesSupported                   []string
+	ResponseTypesSupported            []string
+	GrantTypesSupported               []string
+	SubjectTypesSupported             []string
+	SigningAlgValuesSupported         []string
+	TokenEndpointAuthMethodsSupported []string
+	ClaimsSupported                   []string
+	CodeChallengeMethodsSupported     []string
+}
+
+// IDToken contains the claims of the ID token issued to the client.
+type IDToken struct {
+	Issuer    string
+	Subject   string
+	Audience  string
+	Email     string
+	Nonce     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Signer signs the ID tokens, using the key published in the JWKS.
+type Signer interface {
+	// Sign returns the signed ID token.
+	Sign(token IDToken) (string, error)
+
+	// Algorithm returns the signing algorithm.
+	Algorithm() string
+}
+
+// Code represents the authorization code issued to the client.
+type Code struct {
+	Code                string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	UserID              string
+	Email               string
+	ExpiresAt           time.Time
+}
+
+// CodeRepository specifies the authorization codes persistence API.
+type CodeRepository interface {
+	// Save persists the authorization code.
+	Save(ctx context.Context, code Code) error
+
+	// Remove removes and returns the authorization code, so each code is
+	// exchanged at most once.
+	Remove(ctx context.Context, code string) (Code, error)
+}
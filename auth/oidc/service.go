@@ -0,0 +1,456 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// codeDuration is the lifetime of the authorization code. The client
+// exchanges the code right after the user is redirected back, so the code
+// is short lived.
+const codeDuration = time.Minute
+
+// Config contains the OpenID Provider configuration.
+type Config struct {
+	// Issuer is the URL the provider is reachable at, e.g.
+	// https://mainflux.example.com. The endpoints are relative to it.
+	Issuer string
+
+	// LoginURL is the page of the first-party UI where the user signs in
+	// and approves the authorization request, given as the query.
+	LoginURL string
+
+	Clients []Client
+}
+
+// Service specifies the OpenID Provider API.
+type Service interface {
+	// Discovery returns the OpenID Provider metadata.
+	Discovery(ctx context.Context) Discovery
+
+	// Login validates the authorization request and returns the URL of the
+	// login page the user is redirected to in order to sign in and approve
+	// the request.
+	Login(ctx context.Context, req AuthRequest) (string, error)
+
+	// Authorize issues the authorization code to the client on behalf of
+	// the user identified by the token, and returns the client redirect URI
+	// carrying the code.
+	Authorize(ctx context.Context, token string, req AuthRequest) (string, error)
+
+	// Exchange exchanges the authorization code for the access and ID
+	// tokens, or the token of the trusted external identity provider for
+	// the access token of the user the external identity is linked to.
+	Exchange(ctx context.Context, req TokenRequest) (Tokens, error)
+
+	// UserInfo returns the claims about the user identified by the access
+	// token.
+	UserInfo(ctx context.Context, token string) (UserInfo, error)
+
+	// LinkIdentity links the external identity carrying the subject token
+	// to the user identified by the token, so the subject tokens of the
+	// external identity are exchanged for the tokens of the user.
+	LinkIdentity(ctx context.Context, token, subjectToken string) (Link, error)
+
+	// ListLinks lists the external identities linked to the user identified
+	// by the token.
+	ListLinks(ctx context.Context, token string) ([]Link, error)
+
+	// UnlinkIdentity removes the link of the external identity to the user
+	// identified by the token.
+	UnlinkIdentity(ctx context.Context, token, issuer, subject string) error
+
+	// RemoveLinks removes the links of all the external identities to the
+	// user, once the user is removed.
+	RemoveLinks(ctx context.Context, userID string) error
+}
+
+var _ Service = (*service)(nil)
+
+type service struct {
+	cfg         Config
+	clients     map[string]Client
+	authn       auth.Authn
+	codes       CodeRepository
+	signer      Signer
+	links       LinkRepository
+	revocations auth.RevocationRepository
+	verifier    TokenVerifier
+}
+
+// New instantiates the OpenID Provider service implementation. The token
+// exchange is disabled if the verifier is nil.
+func New(cfg Config, authn auth.Authn, codes CodeRepository, signer Signer, links LinkRepository, revocations auth.RevocationRepository, verifier TokenVerifier) Service {
+	clients := map[string]Client{}
+	for _, c := range cfg.Clients {
+		clients[c.ID] = c
+	}
+	cfg.Issuer = strings.TrimSuffix(cfg.Issuer, "/")
+
+	return &service{
+		cfg:         cfg,
+		clients:     clients,
+		authn:       authn,
+		codes:       codes,
+		signer:      signer,
+		links:       links,
+		revocations: revocations,
+		verifier:    verifier,
+	}
+}
+
+func (svc service) Discovery(ctx context.Context) Discovery {
+	grantTypes := []string{AuthorizationCodeType}
+	if svc.verifier != nil {
+		grantTypes = append(grantTypes, TokenExchangeType)
+	}
+
+	return Discovery{
+		Issuer:                            svc.cfg.Issuer,
+		AuthorizationEndpoint:             svc.cfg.Issuer + AuthorizationPath,
+		TokenEndpoint:                     svc.cfg.Issuer + TokenPath,
+		UserInfoEndpoint:                  svc.cfg.Issuer + UserInfoPath,
+		JWKSURI:                           svc.cfg.Issuer + JWKSPath,
+		ScopesSupported:                   []string{OpenIDScope, EmailScope},
+		ResponseTypesSupported:            []string{CodeResponseType},
+		GrantTypesSupported:               grantTypes,
+		SubjectTypesSupported:             []string{"public"},
+		SigningAlgValuesSupported:         []string{svc.signer.Algorithm()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post"},
+		ClaimsSupported:                   []string{"iss", "sub", "aud", "exp", "iat", "nonce", "email"},
+		CodeChallengeMethodsSupported:     []string{S256},
+	}
+}
+
+func (svc service) Login(ctx context.Context, req AuthRequest) (string, error) {
+	if err := svc.validate(req); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(svc.cfg.LoginURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for k, v := range req.query() {
+		q[k] = v
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func (svc service) Authorize(ctx context.Context, token string, req AuthRequest) (string, error) {
+	if err := svc.validate(req); err != nil {
+		return "", err
+	}
+
+	id, err := svc.authn.Identify(ctx, token)
+	if err != nil {
+		return "", errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	code, err := newCode()
+	if err != nil {
+		return "", err
+	}
+	c := Code{
+		Code:                code,
+		ClientID:            req.ClientID,
+		RedirectURI:         req.RedirectURI,
+		Scope:               req.Scope,
+		Nonce:               req.Nonce,
+		CodeChallenge:       req.CodeChallenge,
+		CodeChallengeMethod: req.CodeChallengeMethod,
+		UserID:              id.ID,
+		Email:               id.Email,
+		ExpiresAt:           time.Now().UTC().Add(codeDuration),
+	}
+	if err := svc.codes.Save(ctx, c); err != nil {
+		return "", err
+	}
+
+	// The redirect URI is validated, so it's parsed successfully.
+	u, _ := url.Parse(req.RedirectURI)
+	q := u.Query()
+	q.Set("code", code)
+	if req.State != "" {
+		q.Set("state", req.State)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func (svc service) Exchange(ctx context.Context, req TokenRequest) (Tokens, error) {
+	switch req.GrantType {
+	case AuthorizationCodeType:
+		return svc.exchangeCode(ctx, req)
+	case TokenExchangeType:
+		if svc.verifier == nil {
+			return Tokens{}, ErrUnsupportedGrantType
+		}
+		return svc.exchangeToken(ctx, req)
+	default:
+		return Tokens{}, ErrUnsupportedGrantType
+	}
+}
+
+func (svc service) exchangeCode(ctx context.Context, req TokenRequest) (Tokens, error) {
+	client, ok := svc.clients[req.ClientID]
+	if !ok || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(req.ClientSecret)) != 1 {
+		return Tokens{}, ErrInvalidClient
+	}
+	if req.Code == "" {
+		return Tokens{}, ErrInvalidRequest
+	}
+
+	c, err := svc.codes.Remove(ctx, req.Code)
+	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return Tokens{}, errors.Wrap(ErrInvalidGrant, err)
+		}
+		return Tokens{}, err
+	}
+	now := time.Now().UTC()
+	if c.ExpiresAt.Before(now) || c.ClientID != req.ClientID || c.RedirectURI != req.RedirectURI {
+		return Tokens{}, ErrInvalidGrant
+	}
+	if c.CodeChallenge != "" && !verifyChallenge(c.CodeChallenge, req.CodeVerifier) {
+		return Tokens{}, ErrInvalidGrant
+	}
+
+	key := auth.Key{
+		Type:     auth.UserKey,
+		IssuedAt: now,
+		IssuerID: c.UserID,
+		Subject:  c.Email,
+	}
+	key, token, err := svc.authn.Issue(ctx, "", key)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	idt := IDToken{
+		Issuer:    svc.cfg.Issuer,
+		Subject:   c.UserID,
+		Audience:  c.ClientID,
+		Nonce:     c.Nonce,
+		IssuedAt:  now,
+		ExpiresAt: key.ExpiresAt,
+	}
+	if hasScope(c.Scope, EmailScope) {
+		idt.Email = c.Email
+	}
+	idToken, err := svc.signer.Sign(idt)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{
+		AccessToken: token,
+		IDToken:     idToken,
+		ExpiresAt:   key.ExpiresAt,
+		Scope:       c.Scope,
+	}, nil
+}
+
+// exchangeToken exchanges the subject token of the linked external identity.
+// The public clients exchange the tokens as well, so the client is
+// authenticated only if it identifies itself.
+func (svc service) exchangeToken(ctx context.Context, req TokenRequest) (Tokens, error) {
+	if req.ClientID != "" {
+		client, ok := svc.clients[req.ClientID]
+		if !ok || subtle.ConstantTimeCompare([]byte(client.Secret), []byte(req.ClientSecret)) != 1 {
+			return Tokens{}, ErrInvalidClient
+		}
+	}
+	if req.SubjectToken == "" || (req.SubjectTokenType != IDTokenType && req.SubjectTokenType != JWTTokenType) {
+		return Tokens{}, ErrInvalidRequest
+	}
+
+	ei, err := svc.verifier.Verify(ctx, req.SubjectToken)
+	if err != nil {
+		return Tokens{}, errors.Wrap(ErrInvalidGrant, err)
+	}
+	l, err := svc.links.Retrieve(ctx, ei.Issuer, ei.Subject)
+	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return Tokens{}, errors.Wrap(ErrInvalidGrant, err)
+		}
+		return Tokens{}, err
+	}
+	// All the keys of the user are revoked once the user is disabled or
+	// removed, and so are the links created before, which would otherwise
+	// keep issuing the keys to the user. The revocation time is truncated
+	// to the second, so the links created within that second are revoked
+	// as well.
+	revokedAt, err := svc.revocations.RetrieveIssuer(ctx, l.UserID)
+	if err != nil {
+		return Tokens{}, err
+	}
+	if !revokedAt.IsZero() && l.CreatedAt.Before(revokedAt.Add(time.Second)) {
+		return Tokens{}, errors.Wrap(ErrInvalidGrant, auth.ErrKeyRevoked)
+	}
+
+	key := auth.Key{
+		Type:     auth.UserKey,
+		IssuedAt: time.Now().UTC(),
+		IssuerID: l.UserID,
+		Subject:  l.Email,
+	}
+	key, token, err := svc.authn.Issue(ctx, "", key)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	return Tokens{
+		AccessToken:     token,
+		ExpiresAt:       key.ExpiresAt,
+		IssuedTokenType: AccessTokenType,
+	}, nil
+}
+
+func (svc service) UserInfo(ctx context.Context, token string) (UserInfo, error) {
+	id, err := svc.authn.Identify(ctx, token)
+	if err != nil {
+		return UserInfo{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return UserInfo{Subject: id.ID, Email: id.Email}, nil
+}
+
+func (svc service) LinkIdentity(ctx context.Context, token, subjectToken string) (Link, error) {
+	if svc.verifier == nil {
+		return Link{}, ErrInvalidRequest
+	}
+	id, err := svc.authn.Identify(ctx, token)
+	if err != nil {
+		return Link{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	// The user proves the ownership of the external identity by presenting
+	// its token.
+	ei, err := svc.verifier.Verify(ctx, subjectToken)
+	if err != nil {
+		return Link{}, errors.Wrap(ErrInvalidGrant, err)
+	}
+
+	l := Link{
+		Issuer:    ei.Issuer,
+		Subject:   ei.Subject,
+		UserID:    id.ID,
+		Email:     id.Email,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := svc.links.Save(ctx, l); err != nil {
+		return Link{}, err
+	}
+
+	return l, nil
+}
+
+func (svc service) ListLinks(ctx context.Context, token string) ([]Link, error) {
+	id, err := svc.authn.Identify(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return svc.links.RetrieveByUser(ctx, id.ID)
+}
+
+func (svc service) UnlinkIdentity(ctx context.Context, token, issuer, subject string) error {
+	id, err := svc.authn.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return svc.links.Remove(ctx, id.ID, issuer, subject)
+}
+
+func (svc service) RemoveLinks(ctx context.Context, userID string) error {
+	if userID == "" {
+		return ErrInvalidRequest
+	}
+
+	return svc.links.RemoveByUser(ctx, userID)
+}
+
+func (svc service) validate(req AuthRequest) error {
+	client, ok := svc.clients[req.ClientID]
+	if !ok {
+		return ErrInvalidClient
+	}
+	// The redirect URI is checked first, since the errors are reported to
+	// the client only through the registered redirect URI.
+	if !client.validRedirect(req.RedirectURI) {
+		return ErrInvalidRequest
+	}
+	if req.ResponseType != CodeResponseType {
+		return ErrInvalidRequest
+	}
+	if !hasScope(req.Scope, OpenIDScope) {
+		return ErrInvalidScope
+	}
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != S256 {
+		return ErrInvalidRequest
+	}
+
+	return nil
+}
+
+func (req AuthRequest) query() url.Values {
+	q := url.Values{}
+	params := map[string]string{
+		"response_type":         req.ResponseType,
+		"client_id":             req.ClientID,
+		"redirect_uri":          req.RedirectURI,
+		"scope":                 req.Scope,
+		"state":                 req.State,
+		"nonce":                 req.Nonce,
+		"code_challenge":        req.CodeChallenge,
+		"code_challenge_method": req.CodeChallengeMethod,
+	}
+	for k, v := range params {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+
+	return q
+}
+
+func hasScope(scope, s string) bool {
+	for _, v := range strings.Fields(scope) {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyChallenge(challenge, verifier string) bool {
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+func newCode() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
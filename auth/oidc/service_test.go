@@ -0,0 +1,566 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/auth/jwt"
+	"github.com/mainflux/mainflux/auth/mocks"
+	"github.com/mainflux/mainflux/auth/oidc"
+	oidcmocks "github.com/mainflux/mainflux/auth/oidc/mocks"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	secret       = "secret"
+	email        = "user@example.com"
+	userID       = "user-id"
+	issuer       = "https://mainflux.example.com"
+	loginURL     = "https://mainflux.example.com/login"
+	clientID     = "grafana"
+	clientSecret = "grafana-secret"
+	redirectURI  = "https://grafana.example.com/login/generic_oauth"
+	verifier     = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	extIssuer    = "https://idp.example.com"
+	extToken     = "external-token"
+	otherToken   = "other-external-token"
+)
+
+func newService(t *testing.T) (oidc.Service, auth.Service) {
+	revocations := mocks.NewRevocationRepository()
+	authSvc := auth.New(mocks.NewKeyRepository(), mocks.NewGroupRepository(), mocks.NewOrgRepository(), mocks.NewShareRepository(), mocks.NewRoleRepository(), mocks.NewServiceAccountRepository(), mocks.NewQuotaRepository(), mocks.NewPolicyExpirationRepository(), revocations, mocks.NewSessionRepository(), mocks.NewAuditRepository(), uuid.NewMock(), jwt.New(secret), mocks.NewKetoMock(map[string][]mocks.MockSubjectSet{}), nil)
+
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("generating RSA key expected to succeed: %s", err))
+	signer, err := jwt.NewIDTokenSigner(jwt.Key{ID: "kid", Algorithm: jwt.RS256, Sign: priv, Verify: &priv.PublicKey})
+	require.Nil(t, err, fmt.Sprintf("creating ID token signer expected to succeed: %s", err))
+
+	cfg := oidc.Config{
+		Issuer:   issuer + "/",
+		LoginURL: loginURL,
+		Clients:  []oidc.Client{{ID: clientID, Secret: clientSecret, RedirectURIs: []string{redirectURI}}},
+	}
+	identities := map[string]oidc.ExternalIdentity{
+		extToken:   {Issuer: extIssuer, Subject: "external-user"},
+		otherToken: {Issuer: extIssuer, Subject: "other-external-user"},
+	}
+	tv := oidcmocks.NewVerifier(identities)
+	return oidc.New(cfg, authSvc, oidcmocks.NewCodeRepository(), signer, oidcmocks.NewLinkRepository(), revocations, tv), authSvc
+}
+
+func login(t *testing.T, svc auth.Service) string {
+	_, token, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: userID, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("issuing login key expected to succeed: %s", err))
+	return token
+}
+
+func authRequest() oidc.AuthRequest {
+	sum := sha256.Sum256([]byte(verifier))
+	return oidc.AuthRequest{
+		ResponseType:        oidc.CodeResponseType,
+		ClientID:            clientID,
+		RedirectURI:         redirectURI,
+		Scope:               "openid email",
+		State:               "state",
+		Nonce:               "nonce",
+		CodeChallenge:       base64.RawURLEncoding.EncodeToString(sum[:]),
+		CodeChallengeMethod: oidc.S256,
+	}
+}
+
+func TestParseClients(t *testing.T) {
+	cases := []struct {
+		desc    string
+		clients string
+		res     []oidc.Client
+		err     bool
+	}{
+		{
+			desc:    "parse empty clients",
+			clients: "",
+			res:     nil,
+		},
+		{
+			desc:    "parse clients with multiple redirect URIs",
+			clients: "grafana:s1:https://grafana/cb, grafana:s1:http://localhost:3000/cb,ui:s2:https://ui/cb",
+			res: []oidc.Client{
+				{ID: "grafana", Secret: "s1", RedirectURIs: []string{"https://grafana/cb", "http://localhost:3000/cb"}},
+				{ID: "ui", Secret: "s2", RedirectURIs: []string{"https://ui/cb"}},
+			},
+		},
+		{
+			desc:    "parse client without redirect URI",
+			clients: "grafana:s1",
+			err:     true,
+		},
+		{
+			desc:    "parse client with multiple secrets",
+			clients: "grafana:s1:https://grafana/cb,grafana:s2:https://grafana/cb2",
+			err:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := oidc.ParseClients(tc.clients)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.res, res))
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	svc, _ := newService(t)
+
+	d := svc.Discovery(context.Background())
+	assert.Equal(t, issuer, d.Issuer, fmt.Sprintf("expected issuer %s got %s", issuer, d.Issuer))
+	assert.Equal(t, issuer+oidc.AuthorizationPath, d.AuthorizationEndpoint, "unexpected authorization endpoint")
+	assert.Equal(t, issuer+oidc.JWKSPath, d.JWKSURI, "unexpected JWKS URI")
+	assert.Equal(t, []string{jwt.RS256}, d.SigningAlgValuesSupported, "unexpected signing algorithms")
+	assert.Contains(t, d.GrantTypesSupported, oidc.TokenExchangeType, "expected token exchange grant type")
+}
+
+func TestLogin(t *testing.T) {
+	svc, _ := newService(t)
+
+	noOpenID := authRequest()
+	noOpenID.Scope = "email"
+	wrongRedirect := authRequest()
+	wrongRedirect.RedirectURI = "https://attacker.example.com"
+	wrongClient := authRequest()
+	wrongClient.ClientID = "unknown"
+	plain := authRequest()
+	plain.CodeChallengeMethod = "plain"
+
+	cases := []struct {
+		desc string
+		req  oidc.AuthRequest
+		err  error
+	}{
+		{
+			desc: "login with valid request",
+			req:  authRequest(),
+			err:  nil,
+		},
+		{
+			desc: "login without openid scope",
+			req:  noOpenID,
+			err:  oidc.ErrInvalidScope,
+		},
+		{
+			desc: "login with unregistered redirect URI",
+			req:  wrongRedirect,
+			err:  oidc.ErrInvalidRequest,
+		},
+		{
+			desc: "login with unknown client",
+			req:  wrongClient,
+			err:  oidc.ErrInvalidClient,
+		},
+		{
+			desc: "login with unsupported code challenge method",
+			req:  plain,
+			err:  oidc.ErrInvalidRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := svc.Login(context.Background(), tc.req)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		u, err := url.Parse(res)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, clientID, u.Query().Get("client_id"), fmt.Sprintf("%s: expected the login URL to carry the request", tc.desc))
+		assert.Equal(t, tc.req.State, u.Query().Get("state"), fmt.Sprintf("%s: expected the login URL to carry the request", tc.desc))
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	svc, authSvc := newService(t)
+	token := login(t, authSvc)
+
+	cases := []struct {
+		desc  string
+		token string
+		req   oidc.AuthRequest
+		err   error
+	}{
+		{
+			desc:  "authorize valid request",
+			token: token,
+			req:   authRequest(),
+			err:   nil,
+		},
+		{
+			desc:  "authorize request with invalid token",
+			token: "invalid",
+			req:   authRequest(),
+			err:   oidc.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := svc.Authorize(context.Background(), tc.token, tc.req)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		u, err := url.Parse(res)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.NotEmpty(t, u.Query().Get("code"), fmt.Sprintf("%s: expected the code in the redirect URI", tc.desc))
+		assert.Equal(t, tc.req.State, u.Query().Get("state"), fmt.Sprintf("%s: expected the state in the redirect URI", tc.desc))
+	}
+}
+
+func TestExchange(t *testing.T) {
+	svc, authSvc := newService(t)
+	token := login(t, authSvc)
+
+	authorize := func() string {
+		res, err := svc.Authorize(context.Background(), token, authRequest())
+		require.Nil(t, err, fmt.Sprintf("authorizing request expected to succeed: %s", err))
+		u, err := url.Parse(res)
+		require.Nil(t, err, fmt.Sprintf("parsing redirect URI expected to succeed: %s", err))
+		return u.Query().Get("code")
+	}
+	used := authorize()
+
+	tokenReq := func(code string) oidc.TokenRequest {
+		return oidc.TokenRequest{
+			GrantType:    oidc.AuthorizationCodeType,
+			Code:         code,
+			RedirectURI:  redirectURI,
+			ClientID:     clientID,
+			ClientSecret: clientSecret,
+			CodeVerifier: verifier,
+		}
+	}
+	wrongGrant := tokenReq(authorize())
+	wrongGrant.GrantType = "password"
+	wrongSecret := tokenReq(authorize())
+	wrongSecret.ClientSecret = "wrong"
+	wrongRedirect := tokenReq(authorize())
+	wrongRedirect.RedirectURI = "https://grafana.example.com/other"
+	wrongVerifier := tokenReq(authorize())
+	wrongVerifier.CodeVerifier = "wrong"
+
+	cases := []struct {
+		desc string
+		req  oidc.TokenRequest
+		err  error
+	}{
+		{
+			desc: "exchange valid code",
+			req:  tokenReq(used),
+			err:  nil,
+		},
+		{
+			desc: "exchange used code",
+			req:  tokenReq(used),
+			err:  oidc.ErrInvalidGrant,
+		},
+		{
+			desc: "exchange unknown code",
+			req:  tokenReq("unknown"),
+			err:  oidc.ErrInvalidGrant,
+		},
+		{
+			desc: "exchange code with unsupported grant type",
+			req:  wrongGrant,
+			err:  oidc.ErrUnsupportedGrantType,
+		},
+		{
+			desc: "exchange code with wrong client secret",
+			req:  wrongSecret,
+			err:  oidc.ErrInvalidClient,
+		},
+		{
+			desc: "exchange code with different redirect URI",
+			req:  wrongRedirect,
+			err:  oidc.ErrInvalidGrant,
+		},
+		{
+			desc: "exchange code with wrong code verifier",
+			req:  wrongVerifier,
+			err:  oidc.ErrInvalidGrant,
+		},
+	}
+
+	for _, tc := range cases {
+		tokens, err := svc.Exchange(context.Background(), tc.req)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.NotEmpty(t, tokens.IDToken, fmt.Sprintf("%s: expected ID token", tc.desc))
+		id, err := authSvc.Identify(context.Background(), tokens.AccessToken)
+		require.Nil(t, err, fmt.Sprintf("%s: expected the access token to be valid: %s", tc.desc, err))
+		assert.Equal(t, userID, id.ID, fmt.Sprintf("%s: expected access token of %s got %s", tc.desc, userID, id.ID))
+	}
+}
+
+func TestParseTrustedIssuers(t *testing.T) {
+	cases := []struct {
+		desc    string
+		issuers string
+		res     []oidc.TrustedIssuer
+		err     bool
+	}{
+		{
+			desc:    "parse empty trusted issuers",
+			issuers: "",
+			res:     nil,
+		},
+		{
+			desc:    "parse multiple trusted issuers",
+			issuers: "mainflux:https://idp.example.com, mf:https://login.example.com/tenant",
+			res: []oidc.TrustedIssuer{
+				{URL: "https://idp.example.com", Audience: "mainflux"},
+				{URL: "https://login.example.com/tenant", Audience: "mf"},
+			},
+		},
+		{
+			desc:    "parse trusted issuer without audience",
+			issuers: ":https://idp.example.com",
+			err:     true,
+		},
+		{
+			desc:    "parse trusted issuer without URL",
+			issuers: "mainflux",
+			err:     true,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := oidc.ParseTrustedIssuers(tc.issuers)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.res, res))
+	}
+}
+
+func TestTokenExchange(t *testing.T) {
+	svc, authSvc := newService(t)
+	token := login(t, authSvc)
+	_, err := svc.LinkIdentity(context.Background(), token, extToken)
+	require.Nil(t, err, fmt.Sprintf("linking identity expected to succeed: %s", err))
+
+	exchangeReq := func(subjectToken string) oidc.TokenRequest {
+		return oidc.TokenRequest{
+			GrantType:        oidc.TokenExchangeType,
+			SubjectToken:     subjectToken,
+			SubjectTokenType: oidc.IDTokenType,
+		}
+	}
+	wrongType := exchangeReq(extToken)
+	wrongType.SubjectTokenType = oidc.AccessTokenType
+	withClient := exchangeReq(extToken)
+	withClient.ClientID, withClient.ClientSecret = clientID, clientSecret
+	wrongSecret := exchangeReq(extToken)
+	wrongSecret.ClientID, wrongSecret.ClientSecret = clientID, "wrong"
+
+	cases := []struct {
+		desc string
+		req  oidc.TokenRequest
+		err  error
+	}{
+		{
+			desc: "exchange linked identity token",
+			req:  exchangeReq(extToken),
+			err:  nil,
+		},
+		{
+			desc: "exchange linked identity token as authenticated client",
+			req:  withClient,
+			err:  nil,
+		},
+		{
+			desc: "exchange linked identity token with wrong client secret",
+			req:  wrongSecret,
+			err:  oidc.ErrInvalidClient,
+		},
+		{
+			desc: "exchange unlinked identity token",
+			req:  exchangeReq(otherToken),
+			err:  oidc.ErrInvalidGrant,
+		},
+		{
+			desc: "exchange invalid token",
+			req:  exchangeReq("invalid"),
+			err:  oidc.ErrInvalidGrant,
+		},
+		{
+			desc: "exchange empty token",
+			req:  exchangeReq(""),
+			err:  oidc.ErrInvalidRequest,
+		},
+		{
+			desc: "exchange token of unsupported type",
+			req:  wrongType,
+			err:  oidc.ErrInvalidRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		tokens, err := svc.Exchange(context.Background(), tc.req)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, oidc.AccessTokenType, tokens.IssuedTokenType, fmt.Sprintf("%s: unexpected issued token type", tc.desc))
+		id, err := authSvc.Identify(context.Background(), tokens.AccessToken)
+		require.Nil(t, err, fmt.Sprintf("%s: expected the access token to be valid: %s", tc.desc, err))
+		assert.Equal(t, auth.Identity{ID: userID, Email: email}, id, fmt.Sprintf("%s: expected access token of %s got %s", tc.desc, userID, id.ID))
+	}
+}
+
+func TestTokenExchangeRevoked(t *testing.T) {
+	svc, authSvc := newService(t)
+	token := login(t, authSvc)
+	_, err := svc.LinkIdentity(context.Background(), token, extToken)
+	require.Nil(t, err, fmt.Sprintf("linking identity expected to succeed: %s", err))
+
+	req := oidc.TokenRequest{GrantType: oidc.TokenExchangeType, SubjectToken: extToken, SubjectTokenType: oidc.IDTokenType}
+	_, err = svc.Exchange(context.Background(), req)
+	require.Nil(t, err, fmt.Sprintf("exchanging linked identity token expected to succeed: %s", err))
+
+	// The keys of the user are revoked once the user is disabled.
+	_, err = authSvc.RevokeKeys(context.Background(), token, userID)
+	require.Nil(t, err, fmt.Sprintf("revoking user keys expected to succeed: %s", err))
+	_, err = svc.Exchange(context.Background(), req)
+	assert.True(t, errors.Contains(err, oidc.ErrInvalidGrant), fmt.Sprintf("exchanging identity token of user with revoked keys: expected %s got %s\n", oidc.ErrInvalidGrant, err))
+}
+
+func TestRemoveLinks(t *testing.T) {
+	svc, authSvc := newService(t)
+	token := login(t, authSvc)
+	_, err := svc.LinkIdentity(context.Background(), token, extToken)
+	require.Nil(t, err, fmt.Sprintf("linking identity expected to succeed: %s", err))
+
+	err = svc.RemoveLinks(context.Background(), "")
+	assert.True(t, errors.Contains(err, oidc.ErrInvalidRequest), fmt.Sprintf("removing links without user: expected %s got %s\n", oidc.ErrInvalidRequest, err))
+	err = svc.RemoveLinks(context.Background(), userID)
+	assert.Nil(t, err, fmt.Sprintf("removing links expected to succeed: %s", err))
+
+	links, err := svc.ListLinks(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("listing links expected to succeed: %s", err))
+	assert.Empty(t, links, fmt.Sprintf("expected no links got %v", links))
+
+	req := oidc.TokenRequest{GrantType: oidc.TokenExchangeType, SubjectToken: extToken, SubjectTokenType: oidc.IDTokenType}
+	_, err = svc.Exchange(context.Background(), req)
+	assert.True(t, errors.Contains(err, oidc.ErrInvalidGrant), fmt.Sprintf("exchanging identity token of removed link: expected %s got %s\n", oidc.ErrInvalidGrant, err))
+}
+
+func TestTokenExchangeDisabled(t *testing.T) {
+	_, authSvc := newService(t)
+	svc := oidc.New(oidc.Config{Issuer: issuer}, authSvc, oidcmocks.NewCodeRepository(), nil, oidcmocks.NewLinkRepository(), mocks.NewRevocationRepository(), nil)
+
+	req := oidc.TokenRequest{GrantType: oidc.TokenExchangeType, SubjectToken: extToken, SubjectTokenType: oidc.IDTokenType}
+	_, err := svc.Exchange(context.Background(), req)
+	assert.True(t, errors.Contains(err, oidc.ErrUnsupportedGrantType), fmt.Sprintf("expected %s got %s\n", oidc.ErrUnsupportedGrantType, err))
+}
+
+func TestLinkIdentity(t *testing.T) {
+	svc, authSvc := newService(t)
+	token := login(t, authSvc)
+	_, other, err := authSvc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "other-id", Subject: "other@example.com"})
+	require.Nil(t, err, fmt.Sprintf("issuing login key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc         string
+		token        string
+		subjectToken string
+		err          error
+	}{
+		{
+			desc:         "link external identity",
+			token:        token,
+			subjectToken: extToken,
+			err:          nil,
+		},
+		{
+			desc:         "link external identity linked to another user",
+			token:        other,
+			subjectToken: extToken,
+			err:          oidc.ErrConflict,
+		},
+		{
+			desc:         "link external identity with invalid subject token",
+			token:        token,
+			subjectToken: "invalid",
+			err:          oidc.ErrInvalidGrant,
+		},
+		{
+			desc:         "link external identity with invalid token",
+			token:        "invalid",
+			subjectToken: otherToken,
+			err:          oidc.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		l, err := svc.LinkIdentity(context.Background(), tc.token, tc.subjectToken)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, userID, l.UserID, fmt.Sprintf("%s: expected link to %s got %s", tc.desc, userID, l.UserID))
+		}
+	}
+
+	links, err := svc.ListLinks(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("listing links expected to succeed: %s", err))
+	require.Len(t, links, 1, "expected a single link")
+	assert.Equal(t, "external-user", links[0].Subject, "unexpected linked subject")
+
+	err = svc.UnlinkIdentity(context.Background(), other, extIssuer, "external-user")
+	assert.True(t, errors.Contains(err, oidc.ErrNotFound), fmt.Sprintf("unlinking identity of another user: expected %s got %s\n", oidc.ErrNotFound, err))
+	err = svc.UnlinkIdentity(context.Background(), token, extIssuer, "external-user")
+	assert.Nil(t, err, fmt.Sprintf("unlinking identity expected to succeed: %s", err))
+
+	req := oidc.TokenRequest{GrantType: oidc.TokenExchangeType, SubjectToken: extToken, SubjectTokenType: oidc.IDTokenType}
+	_, err = svc.Exchange(context.Background(), req)
+	assert.True(t, errors.Contains(err, oidc.ErrInvalidGrant), fmt.Sprintf("exchanging unlinked identity: expected %s got %s\n", oidc.ErrInvalidGrant, err))
+}
+
+func TestUserInfo(t *testing.T) {
+	svc, authSvc := newService(t)
+	token := login(t, authSvc)
+
+	cases := []struct {
+		desc  string
+		token string
+		res   oidc.UserInfo
+		err   error
+	}{
+		{
+			desc:  "retrieve user info with valid token",
+			token: token,
+			res:   oidc.UserInfo{Subject: userID, Email: email},
+			err:   nil,
+		},
+		{
+			desc:  "retrieve user info with invalid token",
+			token: "invalid",
+			res:   oidc.UserInfo{},
+			err:   oidc.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := svc.UserInfo(context.Background(), tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, res))
+	}
+}
@@ -0,0 +1,5 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package opa contains PolicyAgent implementation using Open Policy Agent.
+package opa
@@ -0,0 +1,170 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package opa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+	acl "github.com/ory/keto/proto/ory/keto/acl/v1alpha1"
+)
+
+const (
+	// The policies are stored in the OPA data document as
+	// data.mainflux.policies[object][relation][subject] = true.
+	policiesPath = "/v1/data/mainflux/policies"
+	// allowPath is the decision of the authz.rego policy.
+	allowPath = "/v1/data/mainflux/authz/allow"
+	namespace = "members"
+)
+
+var errUnexpectedStatus = errors.New("unexpected OPA response status")
+
+type policyAgent struct {
+	client *http.Client
+	url    string
+}
+
+// NewPolicyAgent returns the HTTP communication functionalities to
+// communicate with Open Policy Agent, running the authz.rego policy,
+// at the given URL.
+func NewPolicyAgent(client *http.Client, url string) auth.PolicyAgent {
+	return policyAgent{client: client, url: strings.TrimSuffix(url, "/")}
+}
+
+type checkReq struct {
+	Input policyInput `json:"input"`
+}
+
+type policyInput struct {
+	Subject  string `json:"subject"`
+	Object   string `json:"object"`
+	Relation string `json:"relation"`
+}
+
+type checkRes struct {
+	Result bool `json:"result"`
+}
+
+type policiesRes struct {
+	Result map[string]map[string]map[string]bool `json:"result"`
+}
+
+func (pa policyAgent) CheckPolicy(ctx context.Context, pr auth.PolicyReq) error {
+	body, err := json.Marshal(checkReq{Input: policyInput{Subject: pr.Subject, Object: pr.Object, Relation: pr.Relation}})
+	if err != nil {
+		return errors.Wrap(auth.ErrAuthorization, err)
+	}
+
+	var res checkRes
+	if err := pa.do(ctx, http.MethodPost, allowPath, body, &res); err != nil {
+		return errors.Wrap(auth.ErrAuthorization, err)
+	}
+	// The undefined decision, e.g. when the policy isn't loaded, denies
+	// the access as well.
+	if !res.Result {
+		return auth.ErrAuthorization
+	}
+	return nil
+}
+
+func (pa policyAgent) AddPolicy(ctx context.Context, pr auth.PolicyReq) error {
+	return pa.do(ctx, http.MethodPut, policyPath(pr), []byte("true"), nil)
+}
+
+func (pa policyAgent) DeletePolicy(ctx context.Context, pr auth.PolicyReq) error {
+	err := pa.do(ctx, http.MethodDelete, policyPath(pr), nil, nil)
+	// Like in Keto, deleting the non-existing policy succeeds.
+	if errors.Contains(err, auth.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (pa policyAgent) RetrievePolicies(ctx context.Context, pr auth.PolicyReq) ([]*acl.RelationTuple, error) {
+	var res policiesRes
+	if err := pa.do(ctx, http.MethodGet, policiesPath, nil, &res); err != nil {
+		if errors.Contains(err, auth.ErrNotFound) {
+			return []*acl.RelationTuple{}, nil
+		}
+		return []*acl.RelationTuple{}, err
+	}
+
+	tuples := []*acl.RelationTuple{}
+	for object, relations := range res.Result {
+		if pr.Object != "" && pr.Object != object {
+			continue
+		}
+		for relation, subjects := range relations {
+			if pr.Relation != "" && pr.Relation != relation {
+				continue
+			}
+			for subject := range subjects {
+				if pr.Subject != "" && pr.Subject != subject {
+					continue
+				}
+				tuples = append(tuples, &acl.RelationTuple{
+					Namespace: namespace,
+					Object:    object,
+					Relation:  relation,
+					Subject:   toSubject(subject),
+				})
+			}
+		}
+	}
+	return tuples, nil
+}
+
+func (pa policyAgent) do(ctx context.Context, method, path string, body []byte, res interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, pa.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := pa.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+	case http.StatusNotFound:
+		return auth.ErrNotFound
+	default:
+		return errors.Wrap(errUnexpectedStatus, fmt.Errorf("%s %s: %d", method, path, resp.StatusCode))
+	}
+
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
+
+// policyPath returns the path of the policy in the OPA data document.
+func policyPath(pr auth.PolicyReq) string {
+	return fmt.Sprintf("%s/%s/%s/%s", policiesPath, url.PathEscape(pr.Object), url.PathEscape(pr.Relation), url.PathEscape(pr.Subject))
+}
+
+// toSubject returns the subject set reference if the given subject is the
+// subject set, given as <namespace>:<object>#<relation>, or the subject ID
+// reference otherwise.
+func toSubject(subject string) *acl.Subject {
+	i, j := strings.Index(subject, ":"), strings.LastIndex(subject, "#")
+	if i > 0 && j > i+1 && j < len(subject)-1 {
+		return &acl.Subject{
+			Ref: &acl.Subject_Set{Set: &acl.SubjectSet{Namespace: subject[:i], Object: subject[i+1 : j], Relation: subject[j+1:]}},
+		}
+	}
+
+	return &acl.Subject{Ref: &acl.Subject_Id{Id: subject}}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package opa_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/auth/opa"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	policiesPath = "/v1/data/mainflux/policies"
+	allowPath    = "/v1/data/mainflux/authz/allow"
+)
+
+// opaMock mimics the OPA data API storing the policies, and evaluates the
+// direct policies only instead of the authz.rego policy.
+type opaMock struct {
+	mu       sync.Mutex
+	policies map[string]map[string]map[string]bool
+}
+
+func (om *opaMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	om.mu.Lock()
+	defer om.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == allowPath:
+		var req struct {
+			Input struct {
+				Subject  string `json:"subject"`
+				Object   string `json:"object"`
+				Relation string `json:"relation"`
+			} `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		in := req.Input
+		json.NewEncoder(w).Encode(map[string]bool{"result": om.policies[in.Object][in.Relation][in.Subject]})
+	case r.Method == http.MethodGet && r.URL.Path == policiesPath:
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": om.policies})
+	case strings.HasPrefix(r.URL.EscapedPath(), policiesPath+"/"):
+		parts := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), policiesPath+"/"), "/")
+		if len(parts) != 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for i := range parts {
+			parts[i], _ = url.PathUnescape(parts[i])
+		}
+		obj, rel, sub := parts[0], parts[1], parts[2]
+		switch r.Method {
+		case http.MethodPut:
+			if om.policies[obj] == nil {
+				om.policies[obj] = map[string]map[string]bool{}
+			}
+			if om.policies[obj][rel] == nil {
+				om.policies[obj][rel] = map[string]bool{}
+			}
+			om.policies[obj][rel][sub] = true
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			if !om.policies[obj][rel][sub] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(om.policies[obj][rel], sub)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func newAgent(t *testing.T) auth.PolicyAgent {
+	ts := httptest.NewServer(&opaMock{policies: map[string]map[string]map[string]bool{}})
+	t.Cleanup(ts.Close)
+	return opa.NewPolicyAgent(ts.Client(), ts.URL)
+}
+
+func TestPolicies(t *testing.T) {
+	pa := newAgent(t)
+
+	policies := []auth.PolicyReq{
+		{Subject: "user1", Object: "thing1", Relation: "read"},
+		{Subject: "user1", Object: "thing1", Relation: "write"},
+		{Subject: "members:group1#member", Object: "thing2", Relation: "read"},
+	}
+	for _, pr := range policies {
+		err := pa.AddPolicy(context.Background(), pr)
+		require.Nil(t, err, fmt.Sprintf("adding policy %v expected to succeed: %s", pr, err))
+	}
+
+	checkCases := []struct {
+		desc   string
+		policy auth.PolicyReq
+		err    error
+	}{
+		{
+			desc:   "check existing policy",
+			policy: auth.PolicyReq{Subject: "user1", Object: "thing1", Relation: "write"},
+			err:    nil,
+		},
+		{
+			desc:   "check existing subject set policy",
+			policy: auth.PolicyReq{Subject: "members:group1#member", Object: "thing2", Relation: "read"},
+			err:    nil,
+		},
+		{
+			desc:   "check non-existing policy",
+			policy: auth.PolicyReq{Subject: "user1", Object: "thing1", Relation: "delete"},
+			err:    auth.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range checkCases {
+		err := pa.CheckPolicy(context.Background(), tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	retrieveCases := []struct {
+		desc   string
+		policy auth.PolicyReq
+		size   int
+	}{
+		{
+			desc:   "retrieve all policies",
+			policy: auth.PolicyReq{},
+			size:   3,
+		},
+		{
+			desc:   "retrieve policies of subject",
+			policy: auth.PolicyReq{Subject: "user1"},
+			size:   2,
+		},
+		{
+			desc:   "retrieve policies of object and relation",
+			policy: auth.PolicyReq{Object: "thing1", Relation: "read"},
+			size:   1,
+		},
+		{
+			desc:   "retrieve policies of subject set",
+			policy: auth.PolicyReq{Subject: "members:group1#member"},
+			size:   1,
+		},
+	}
+
+	for _, tc := range retrieveCases {
+		tuples, err := pa.RetrievePolicies(context.Background(), tc.policy)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Len(t, tuples, tc.size, fmt.Sprintf("%s: expected %d policies got %d\n", tc.desc, tc.size, len(tuples)))
+	}
+
+	tuples, err := pa.RetrievePolicies(context.Background(), auth.PolicyReq{Object: "thing2"})
+	require.Nil(t, err, fmt.Sprintf("retrieving policies expected to succeed: %s", err))
+	require.Len(t, tuples, 1)
+	set := tuples[0].GetSubject().GetSet()
+	assert.Equal(t, "group1", set.GetObject(), fmt.Sprintf("expected subject set object group1 got %s", set.GetObject()))
+	assert.Equal(t, "member", set.GetRelation(), fmt.Sprintf("expected subject set relation member got %s", set.GetRelation()))
+
+	err = pa.DeletePolicy(context.Background(), policies[0])
+	assert.Nil(t, err, fmt.Sprintf("deleting policy expected to succeed: %s", err))
+	err = pa.CheckPolicy(context.Background(), policies[0])
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("checking deleted policy: expected %s got %s\n", auth.ErrAuthorization, err))
+
+	err = pa.DeletePolicy(context.Background(), policies[0])
+	assert.Nil(t, err, fmt.Sprintf("deleting non-existing policy expected to succeed: %s", err))
+}
+
+func TestCheckPolicyUnavailable(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	pa := opa.NewPolicyAgent(ts.Client(), ts.URL)
+	err := pa.CheckPolicy(context.Background(), auth.PolicyReq{Subject: "user1", Object: "thing1", Relation: "read"})
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("expected %s got %s\n", auth.ErrAuthorization, err))
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	// ErrOrgNotEmpty indicates the removal of the organization which still
+	// has groups.
+	ErrOrgNotEmpty = errors.New("organization is not empty")
+
+	// ErrOrgMember indicates the user isn't a member of the organization.
+	ErrOrgMember = errors.New("user is not a member of the organization")
+)
+
+// OrgMetadata represents the custom organization metadata.
+type OrgMetadata map[string]interface{}
+
+// Org represents the organization (the tenant) the keys, the groups and the
+// policies are scoped to. The entities of the organization are visible only
+// through the keys of the organization, and the keys of the organization
+// can't reach the entities of the other organizations, nor the entities
+// outside of any organization.
+type Org struct {
+	ID          string
+	OwnerID     string
+	Name        string
+	Description string
+	Metadata    OrgMetadata
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// OrgMember represents the user who is a member of the organization.
+type OrgMember struct {
+	ID        string
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// OrgsPage contains the page of the organizations.
+type OrgsPage struct {
+	Total  uint64
+	Offset uint64
+	Limit  uint64
+	Orgs   []Org
+}
+
+// OrgMembersPage contains the page of the organization members.
+type OrgMembersPage struct {
+	Total   uint64
+	Offset  uint64
+	Limit   uint64
+	Members []OrgMember
+}
+
+// Orgs specifies an API for managing the organizations. The keys scoped to
+// the organization can only reach their own organization.
+type Orgs interface {
+	// CreateOrg creates the organization owned by the user identified by
+	// the token, who becomes its first member.
+	CreateOrg(ctx context.Context, token string, o Org) (Org, error)
+
+	// ViewOrg retrieves the organization the user is a member of.
+	ViewOrg(ctx context.Context, token, id string) (Org, error)
+
+	// UpdateOrg updates the name, the description and the metadata of the
+	// organization. This method is only allowed to use as the owner.
+	UpdateOrg(ctx context.Context, token string, o Org) (Org, error)
+
+	// RemoveOrg removes the organization without the groups, along with its
+	// memberships. This method is only allowed to use as the owner.
+	RemoveOrg(ctx context.Context, token, id string) error
+
+	// ListOrgs lists the organizations the user is a member of.
+	ListOrgs(ctx context.Context, token string, offset, limit uint64) (OrgsPage, error)
+
+	// AssignOrgMembers adds the users to the organization. This method is
+	// only allowed to use as the owner.
+	AssignOrgMembers(ctx context.Context, token, orgID string, memberIDs ...string) error
+
+	// UnassignOrgMembers removes the users other than the owner from the
+	// organization, which revokes their keys scoped to the organization.
+	// This method is only allowed to use as the owner.
+	UnassignOrgMembers(ctx context.Context, token, orgID string, memberIDs ...string) error
+
+	// ListOrgMembers lists the members of the organization the user is a
+	// member of.
+	ListOrgMembers(ctx context.Context, token, orgID string, offset, limit uint64) (OrgMembersPage, error)
+
+	// SwitchOrg issues the User key scoped to the organization the user is
+	// a member of, in exchange for the User key. The empty organization ID
+	// stands for the entities outside of any organization.
+	SwitchOrg(ctx context.Context, token, orgID string) (Key, string, error)
+}
+
+// OrgRepository specifies Org persistence API.
+type OrgRepository interface {
+	// Save persists the organization along with its owner membership.
+	Save(ctx context.Context, o Org) error
+
+	// Update updates the name, the description and the metadata of the
+	// organization.
+	Update(ctx context.Context, o Org) error
+
+	// Remove removes the organization along with its memberships.
+	Remove(ctx context.Context, id string) error
+
+	// RetrieveByID retrieves the organization by its unique identifier.
+	RetrieveByID(ctx context.Context, id string) (Org, error)
+
+	// RetrieveByMember retrieves the organizations the user is a member of.
+	RetrieveByMember(ctx context.Context, memberID string, offset, limit uint64) (OrgsPage, error)
+
+	// AssignMembers adds the users to the organization.
+	AssignMembers(ctx context.Context, orgID, createdBy string, memberIDs ...string) error
+
+	// UnassignMembers removes the users from the organization.
+	UnassignMembers(ctx context.Context, orgID string, memberIDs ...string) error
+
+	// RetrieveMembers retrieves the members of the organization.
+	RetrieveMembers(ctx context.Context, orgID string, offset, limit uint64) (OrgMembersPage, error)
+
+	// IsMember returns nil if the user is a member of the organization, and
+	// ErrOrgMember otherwise.
+	IsMember(ctx context.Context, orgID, memberID string) error
+}
@@ -5,22 +5,83 @@ package auth
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	acl "github.com/ory/keto/proto/ory/keto/acl/v1alpha1"
 )
 
+// The namespaces of the policy objects. Each entity type has its own
+// namespace, so the policies of the different entities sharing the ID don't
+// collide. The policies without the namespace belong to MembersNamespace.
+const (
+	MembersNamespace  = "members"
+	ThingsNamespace   = "things"
+	ChannelsNamespace = "channels"
+	GroupsNamespace   = "groups"
+	UsersNamespace    = "users"
+)
+
+// ValidNamespace returns true if the namespace is supported. The empty
+// namespace stands for MembersNamespace.
+func ValidNamespace(namespace string) bool {
+	switch namespace {
+	case "", MembersNamespace, ThingsNamespace, ChannelsNamespace, GroupsNamespace, UsersNamespace:
+		return true
+	default:
+		return false
+	}
+}
+
 // PolicyReq represents an argument struct for making a policy related
 // function calls.
 type PolicyReq struct {
 	Subject  string
 	Object   string
 	Relation string
+
+	// Namespace is the optional namespace of the Object, which defaults
+	// to MembersNamespace.
+	Namespace string
+
+	// TTL is the optional lifetime of the policy added with AddPolicy,
+	// after which the policy is revoked. Zero TTL never expires.
+	TTL time.Duration
+
+	// Token is the optional token of the subject checked with Authorize
+	// and ExplainPolicy. If the token is the scoped API key, the key has to
+	// allow the Scope of the checked action, e.g. "things:write".
+	Token string
+	Scope string
+}
+
+// PolicyResult represents the outcome of applying the single policy.
+type PolicyResult struct {
+	PolicyReq
+	Err error
 }
 
 type PolicyPage struct {
 	Policies []string
 }
 
+// Explanation represents the outcome of the authorization check along with
+// the policies it was decided by.
+type Explanation struct {
+	Allowed bool
+	Reason  string
+
+	// Path lists the policies granting the access, from the policy of the
+	// checked object to the policy of the subject. Each policy but the last
+	// one has the subject set the next policy belongs to.
+	Path []PolicyReq
+
+	// Expanded lists the subject sets, in the <namespace>:<object>#<relation>
+	// format, searched for the subject.
+	Expanded []string
+}
+
 // Authz represents a authorization service. It exposes
 // functionalities through `auth` to perform authorization.
 type Authz interface {
@@ -40,6 +101,16 @@ type Authz interface {
 	// only allowed to use as an admin.
 	AddPolicies(ctx context.Context, token, object string, subjectIDs, relations []string) error
 
+	// AddPolicyTriples adds the explicit (subject, relation, object) policies
+	// and returns the result of each of them, in the order they are given.
+	// This method is only allowed to use as an admin.
+	AddPolicyTriples(ctx context.Context, token string, prs []PolicyReq) ([]PolicyResult, error)
+
+	// ApplyPolicyTemplate adds the policies of the template of the created
+	// entity's type. If any of the policies can't be added, the policies
+	// added so far are removed.
+	ApplyPolicyTemplate(ctx context.Context, e Entity) error
+
 	// DeletePolicy removes a policy.
 	DeletePolicy(ctx context.Context, pr PolicyReq) error
 
@@ -48,7 +119,43 @@ type Authz interface {
 	DeletePolicies(ctx context.Context, token, object string, subjectIDs, relations []string) error
 
 	// ListPolicies lists policies based on the given PolicyReq structure.
+	// The objects listed for the subject include the members of the group
+	// subtrees the subject is the admin of. Given the object and no
+	// subject, the subjects of the matching policies are listed instead.
 	ListPolicies(ctx context.Context, pr PolicyReq) (PolicyPage, error)
+
+	// InspectPolicies retrieves the policies matching the non-empty fields
+	// of the given PolicyReq. The admin can inspect any policy, while the
+	// other users can only inspect the policies they are the subject of.
+	InspectPolicies(ctx context.Context, token string, pr PolicyReq) ([]PolicyReq, error)
+
+	// ExplainPolicy checks the access of the subject like Authorize does,
+	// without revoking the expired policies, and explains which policies
+	// granted it, or which subject sets were searched when it's denied. The admin can explain any access, while the other
+	// users can only explain their own access.
+	ExplainPolicy(ctx context.Context, token string, pr PolicyReq) (Explanation, error)
+}
+
+// PolicyExpiration represents the deadline of the temporary policy.
+type PolicyExpiration struct {
+	Subject   string
+	Object    string
+	Relation  string
+	Namespace string
+	ExpiresAt time.Time
+}
+
+// PolicyExpirationRepository specifies PolicyExpiration persistence API.
+type PolicyExpirationRepository interface {
+	// Save persists the policy expiration, replacing the existing deadline
+	// of the same policy.
+	Save(ctx context.Context, pe PolicyExpiration) error
+
+	// Retrieve retrieves the expiration of the policy.
+	Retrieve(ctx context.Context, pr PolicyReq) (PolicyExpiration, error)
+
+	// Remove removes the expiration of the policy, if any.
+	Remove(ctx context.Context, pr PolicyReq) error
 }
 
 // PolicyAgent facilitates the communication to authorization
@@ -68,5 +175,26 @@ type PolicyAgent interface {
 	// DeletePolicy removes a policy.
 	DeletePolicy(ctx context.Context, pr PolicyReq) error
 
+	// RetrievePolicies retrieves the policies matching the non-empty
+	// subject, object and relation of the given PolicyReq.
 	RetrievePolicies(ctx context.Context, pr PolicyReq) ([]*acl.RelationTuple, error)
 }
+
+// subjectString returns the policy subject in the PolicyReq format, which is
+// either the subject ID or the <namespace>:<object>#<relation> subject set.
+func subjectString(s *acl.Subject) string {
+	if set := s.GetSet(); set != nil {
+		return fmt.Sprintf("%s:%s#%s", set.GetNamespace(), set.GetObject(), set.GetRelation())
+	}
+	return s.GetId()
+}
+
+// splitSubjectSet splits the <namespace>:<object>#<relation> subject set.
+func splitSubjectSet(subject string) (string, string, string, bool) {
+	i := strings.Index(subject, ":")
+	j := strings.LastIndex(subject, "#")
+	if i < 1 || j <= i+1 || j == len(subject)-1 {
+		return "", "", "", false
+	}
+	return subject[:i], subject[i+1 : j], subject[j+1:], true
+}
@@ -0,0 +1,184 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveAccount     = errors.New("failed to save service account in database")
+	errRetrieveAccount = errors.New("failed to retrieve service account from database")
+	errUpdateAccount   = errors.New("failed to update service account in database")
+	errDeleteAccount   = errors.New("failed to delete service account from database")
+)
+
+var _ auth.ServiceAccountRepository = (*accountRepository)(nil)
+
+type accountRepository struct {
+	db Database
+}
+
+// NewServiceAccountRepo instantiates a PostgreSQL implementation of service
+// account repository.
+func NewServiceAccountRepo(db Database) auth.ServiceAccountRepository {
+	return &accountRepository{
+		db: db,
+	}
+}
+
+func (ar accountRepository) Save(ctx context.Context, sa auth.ServiceAccount) error {
+	q := `INSERT INTO service_accounts (id, name, owner_id, scopes, disabled, created_by, created_at)
+	      VALUES (:id, :name, :owner_id, :scopes, :disabled, :created_by, :created_at)`
+
+	if _, err := ar.db.NamedExecContext(ctx, q, toDBAccount(sa)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(auth.ErrConflict, pqErr)
+		}
+
+		return errors.Wrap(errSaveAccount, err)
+	}
+
+	return nil
+}
+
+func (ar accountRepository) RetrieveByID(ctx context.Context, id string) (auth.ServiceAccount, error) {
+	q := `SELECT id, name, owner_id, scopes, disabled, created_by, created_at FROM service_accounts WHERE id = $1`
+
+	dba := dbAccount{}
+	if err := ar.db.QueryRowxContext(ctx, q, id).StructScan(&dba); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return auth.ServiceAccount{}, errors.Wrap(auth.ErrNotFound, err)
+		}
+
+		return auth.ServiceAccount{}, errors.Wrap(errRetrieveAccount, err)
+	}
+
+	return toAccount(dba), nil
+}
+
+func (ar accountRepository) RetrieveByOwner(ctx context.Context, ownerID string) ([]auth.ServiceAccount, error) {
+	q := `SELECT id, name, owner_id, scopes, disabled, created_by, created_at FROM service_accounts
+	      WHERE owner_id = $1 ORDER BY created_at`
+
+	rows, err := ar.db.QueryxContext(ctx, q, ownerID)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveAccount, err)
+	}
+	defer rows.Close()
+
+	var accounts []auth.ServiceAccount
+	for rows.Next() {
+		dba := dbAccount{}
+		if err := rows.StructScan(&dba); err != nil {
+			return nil, errors.Wrap(errRetrieveAccount, err)
+		}
+		accounts = append(accounts, toAccount(dba))
+	}
+
+	return accounts, nil
+}
+
+func (ar accountRepository) UpdateScopes(ctx context.Context, id string, scopes []string) error {
+	q := `UPDATE service_accounts SET scopes = :scopes WHERE id = :id`
+
+	res, err := ar.db.NamedExecContext(ctx, q, dbAccount{ID: id, Scopes: scopes})
+	if err != nil {
+		return errors.Wrap(errUpdateAccount, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateAccount, err)
+	}
+	if cnt != 1 {
+		return auth.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ar accountRepository) UpdateStatus(ctx context.Context, id string, disabled bool) error {
+	q := `UPDATE service_accounts SET disabled = :disabled WHERE id = :id`
+
+	res, err := ar.db.NamedExecContext(ctx, q, dbAccount{ID: id, Disabled: disabled})
+	if err != nil {
+		return errors.Wrap(errUpdateAccount, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateAccount, err)
+	}
+	if cnt != 1 {
+		return auth.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ar accountRepository) Remove(ctx context.Context, id string) error {
+	tx, err := ar.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errDeleteAccount, err)
+	}
+
+	qKeys := `DELETE FROM keys WHERE issuer_id = :id`
+	qAccount := `DELETE FROM service_accounts WHERE id = :id`
+
+	for _, q := range []string{qKeys, qAccount} {
+		if _, err := tx.NamedExecContext(ctx, q, dbAccount{ID: id}); err != nil {
+			tx.Rollback()
+			return errors.Wrap(errDeleteAccount, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errDeleteAccount, err)
+	}
+
+	return nil
+}
+
+type dbAccount struct {
+	ID        string         `db:"id"`
+	Name      string         `db:"name"`
+	OwnerID   string         `db:"owner_id"`
+	Scopes    pq.StringArray `db:"scopes"`
+	Disabled  bool           `db:"disabled"`
+	CreatedBy string         `db:"created_by"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func toDBAccount(sa auth.ServiceAccount) dbAccount {
+	return dbAccount{
+		ID:        sa.ID,
+		Name:      sa.Name,
+		OwnerID:   sa.OwnerID,
+		Scopes:    sa.Scopes,
+		Disabled:  sa.Disabled,
+		CreatedBy: sa.CreatedBy,
+		CreatedAt: sa.CreatedAt,
+	}
+}
+
+func toAccount(dba dbAccount) auth.ServiceAccount {
+	return auth.ServiceAccount{
+		ID:        dba.ID,
+		Name:      dba.Name,
+		OwnerID:   dba.OwnerID,
+		Scopes:    dba.Scopes,
+		Disabled:  dba.Disabled,
+		CreatedBy: dba.CreatedBy,
+		CreatedAt: dba.CreatedAt,
+	}
+}
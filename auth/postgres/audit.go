@@ -0,0 +1,149 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveAuditRecord     = errors.New("failed to save audit record in database")
+	errRetrieveAuditRecord = errors.New("failed to retrieve audit records from database")
+)
+
+var _ auth.AuditRepository = (*auditRepository)(nil)
+
+type auditRepository struct {
+	db Database
+}
+
+// NewAuditRepo instantiates a PostgreSQL implementation of audit
+// repository.
+func NewAuditRepo(db Database) auth.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (ar auditRepository) Save(ctx context.Context, r auth.AuditRecord) error {
+	q := `INSERT INTO audit_records (operation, subject, object, relation, decision, created_at)
+	      VALUES (:operation, :subject, :object, :relation, :decision, :created_at)`
+
+	if _, err := ar.db.NamedExecContext(ctx, q, toDBAuditRecord(r)); err != nil {
+		return errors.Wrap(errSaveAuditRecord, err)
+	}
+
+	return nil
+}
+
+func (ar auditRepository) RetrieveAll(ctx context.Context, filter auth.AuditFilter, offset, limit uint64) (auth.AuditPage, error) {
+	params := map[string]interface{}{
+		"offset": offset,
+		"limit":  limit,
+	}
+
+	var query []string
+	filters := []struct {
+		col string
+		val string
+	}{
+		{"operation", filter.Operation},
+		{"subject", filter.Subject},
+		{"object", filter.Object},
+		{"relation", filter.Relation},
+		{"decision", filter.Decision},
+	}
+	for _, f := range filters {
+		if f.val != "" {
+			params[f.col] = f.val
+			query = append(query, fmt.Sprintf("%s = :%s", f.col, f.col))
+		}
+	}
+	if !filter.From.IsZero() {
+		params["from"] = filter.From
+		query = append(query, "created_at >= :from")
+	}
+	if !filter.To.IsZero() {
+		params["to"] = filter.To
+		query = append(query, "created_at < :to")
+	}
+
+	var whereClause string
+	if len(query) > 0 {
+		whereClause = fmt.Sprintf("WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT id, operation, subject, object, relation, decision, created_at FROM audit_records
+	      %s ORDER BY id LIMIT :limit OFFSET :offset`, whereClause)
+
+	rows, err := ar.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return auth.AuditPage{}, errors.Wrap(errRetrieveAuditRecord, err)
+	}
+	defer rows.Close()
+
+	records := []auth.AuditRecord{}
+	for rows.Next() {
+		dbr := dbAuditRecord{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return auth.AuditPage{}, errors.Wrap(errRetrieveAuditRecord, err)
+		}
+		records = append(records, toAuditRecord(dbr))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM audit_records %s`, whereClause)
+	total, err := total(ctx, ar.db, cq, params)
+	if err != nil {
+		return auth.AuditPage{}, errors.Wrap(errRetrieveAuditRecord, err)
+	}
+
+	page := auth.AuditPage{
+		Total:   total,
+		Offset:  offset,
+		Limit:   limit,
+		Records: records,
+	}
+
+	return page, nil
+}
+
+type dbAuditRecord struct {
+	ID        uint64    `db:"id"`
+	Operation string    `db:"operation"`
+	Subject   string    `db:"subject"`
+	Object    string    `db:"object"`
+	Relation  string    `db:"relation"`
+	Decision  string    `db:"decision"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBAuditRecord(r auth.AuditRecord) dbAuditRecord {
+	return dbAuditRecord{
+		ID:        r.ID,
+		Operation: r.Operation,
+		Subject:   r.Subject,
+		Object:    r.Object,
+		Relation:  r.Relation,
+		Decision:  r.Decision,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func toAuditRecord(dbr dbAuditRecord) auth.AuditRecord {
+	return auth.AuditRecord{
+		ID:        dbr.ID,
+		Operation: dbr.Operation,
+		Subject:   dbr.Subject,
+		Object:    dbr.Object,
+		Relation:  dbr.Relation,
+		Decision:  dbr.Decision,
+		CreatedAt: dbr.CreatedAt.UTC(),
+	}
+}
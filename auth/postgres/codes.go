@@ -0,0 +1,129 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"github.com/mainflux/mainflux/auth/oidc"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveCode   = errors.New("failed to save authorization code in database")
+	errRemoveCode = errors.New("failed to remove authorization code from database")
+)
+
+var _ oidc.CodeRepository = (*codeRepository)(nil)
+
+type codeRepository struct {
+	db Database
+}
+
+// NewCodeRepo instantiates a PostgreSQL implementation of the OpenID Connect
+// authorization code repository.
+func NewCodeRepo(db Database) oidc.CodeRepository {
+	return &codeRepository{
+		db: db,
+	}
+}
+
+func (cr codeRepository) Save(ctx context.Context, code oidc.Code) error {
+	tx, err := cr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errSaveCode, err)
+	}
+
+	// The codes which were never exchanged are pruned once they expire.
+	qDel := `DELETE FROM oidc_codes WHERE expires_at < $1`
+	if _, err := tx.ExecContext(ctx, qDel, time.Now().UTC()); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errSaveCode, err)
+	}
+
+	q := `INSERT INTO oidc_codes (code, client_id, redirect_uri, scope, nonce, code_challenge, code_challenge_method, user_id, email, expires_at)
+		VALUES (:code, :client_id, :redirect_uri, :scope, :nonce, :code_challenge, :code_challenge_method, :user_id, :email, :expires_at)`
+	if _, err := tx.NamedExecContext(ctx, q, toDBCode(code)); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errSaveCode, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errSaveCode, err)
+	}
+
+	return nil
+}
+
+func (cr codeRepository) Remove(ctx context.Context, code string) (oidc.Code, error) {
+	// The code is removed and returned in the same statement, so the
+	// concurrent exchanges of the same code can't both succeed.
+	q := `DELETE FROM oidc_codes WHERE code = $1
+		RETURNING code, client_id, redirect_uri, scope, nonce, code_challenge, code_challenge_method, user_id, email, expires_at`
+
+	var dbc dbCode
+	if err := cr.db.QueryRowxContext(ctx, q, hashCode(code)).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return oidc.Code{}, errors.Wrap(oidc.ErrNotFound, err)
+		}
+		return oidc.Code{}, errors.Wrap(errRemoveCode, err)
+	}
+
+	c := toCode(dbc)
+	c.Code = code
+	return c, nil
+}
+
+type dbCode struct {
+	Code                string    `db:"code"`
+	ClientID            string    `db:"client_id"`
+	RedirectURI         string    `db:"redirect_uri"`
+	Scope               string    `db:"scope"`
+	Nonce               string    `db:"nonce"`
+	CodeChallenge       string    `db:"code_challenge"`
+	CodeChallengeMethod string    `db:"code_challenge_method"`
+	UserID              string    `db:"user_id"`
+	Email               string    `db:"email"`
+	ExpiresAt           time.Time `db:"expires_at"`
+}
+
+// hashCode hashes the code, so the stored codes can't be exchanged if the
+// database leaks.
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func toDBCode(c oidc.Code) dbCode {
+	return dbCode{
+		Code:                hashCode(c.Code),
+		ClientID:            c.ClientID,
+		RedirectURI:         c.RedirectURI,
+		Scope:               c.Scope,
+		Nonce:               c.Nonce,
+		CodeChallenge:       c.CodeChallenge,
+		CodeChallengeMethod: c.CodeChallengeMethod,
+		UserID:              c.UserID,
+		Email:               c.Email,
+		ExpiresAt:           c.ExpiresAt,
+	}
+}
+
+func toCode(dbc dbCode) oidc.Code {
+	return oidc.Code{
+		ClientID:            dbc.ClientID,
+		RedirectURI:         dbc.RedirectURI,
+		Scope:               dbc.Scope,
+		Nonce:               dbc.Nonce,
+		CodeChallenge:       dbc.CodeChallenge,
+		CodeChallengeMethod: dbc.CodeChallengeMethod,
+		UserID:              dbc.UserID,
+		Email:               dbc.Email,
+		ExpiresAt:           dbc.ExpiresAt,
+	}
+}
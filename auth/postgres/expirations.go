@@ -0,0 +1,110 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveExpiration     = errors.New("failed to save policy expiration in database")
+	errRetrieveExpiration = errors.New("failed to retrieve policy expiration from database")
+	errDeleteExpiration   = errors.New("failed to delete policy expiration from database")
+)
+
+var _ auth.PolicyExpirationRepository = (*expirationRepository)(nil)
+
+type expirationRepository struct {
+	db Database
+}
+
+// NewPolicyExpirationRepo instantiates a PostgreSQL implementation of policy
+// expiration repository.
+func NewPolicyExpirationRepo(db Database) auth.PolicyExpirationRepository {
+	return &expirationRepository{
+		db: db,
+	}
+}
+
+func (er expirationRepository) Save(ctx context.Context, pe auth.PolicyExpiration) error {
+	q := `INSERT INTO policy_expirations (subject, object, relation, namespace, expires_at)
+		VALUES (:subject, :object, :relation, :namespace, :expires_at)
+		ON CONFLICT (subject, object, relation, namespace) DO UPDATE SET expires_at = :expires_at`
+
+	if _, err := er.db.NamedExecContext(ctx, q, toDBExpiration(pe)); err != nil {
+		return errors.Wrap(errSaveExpiration, err)
+	}
+
+	return nil
+}
+
+func (er expirationRepository) Retrieve(ctx context.Context, pr auth.PolicyReq) (auth.PolicyExpiration, error) {
+	q := `SELECT subject, object, relation, namespace, expires_at FROM policy_expirations
+		WHERE subject = $1 AND object = $2 AND relation = $3 AND namespace = $4`
+
+	dbe := dbExpiration{}
+	if err := er.db.QueryRowxContext(ctx, q, pr.Subject, pr.Object, pr.Relation, toDBNamespace(pr.Namespace)).StructScan(&dbe); err != nil {
+		if err == sql.ErrNoRows {
+			return auth.PolicyExpiration{}, errors.Wrap(auth.ErrNotFound, err)
+		}
+
+		return auth.PolicyExpiration{}, errors.Wrap(errRetrieveExpiration, err)
+	}
+
+	return toExpiration(dbe), nil
+}
+
+func (er expirationRepository) Remove(ctx context.Context, pr auth.PolicyReq) error {
+	q := `DELETE FROM policy_expirations
+		WHERE subject = :subject AND object = :object AND relation = :relation AND namespace = :namespace`
+
+	dbe := dbExpiration{Subject: pr.Subject, Object: pr.Object, Relation: pr.Relation, Namespace: toDBNamespace(pr.Namespace)}
+	if _, err := er.db.NamedExecContext(ctx, q, dbe); err != nil {
+		return errors.Wrap(errDeleteExpiration, err)
+	}
+
+	return nil
+}
+
+type dbExpiration struct {
+	Subject   string    `db:"subject"`
+	Object    string    `db:"object"`
+	Relation  string    `db:"relation"`
+	Namespace string    `db:"namespace"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func toDBExpiration(pe auth.PolicyExpiration) dbExpiration {
+	return dbExpiration{
+		Subject:   pe.Subject,
+		Object:    pe.Object,
+		Relation:  pe.Relation,
+		Namespace: toDBNamespace(pe.Namespace),
+		ExpiresAt: pe.ExpiresAt,
+	}
+}
+
+func toExpiration(dbe dbExpiration) auth.PolicyExpiration {
+	return auth.PolicyExpiration{
+		Subject:   dbe.Subject,
+		Object:    dbe.Object,
+		Relation:  dbe.Relation,
+		Namespace: dbe.Namespace,
+		ExpiresAt: dbe.ExpiresAt,
+	}
+}
+
+// toDBNamespace stores the policies without the namespace under the members
+// namespace, which the policy agent falls back to.
+func toDBNamespace(namespace string) string {
+	if namespace == "" {
+		return auth.MembersNamespace
+	}
+	return namespace
+}
@@ -9,6 +9,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gofrs/uuid"
@@ -17,6 +18,7 @@ import (
 	"github.com/lib/pq"
 	"github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/users"
 )
 
@@ -46,14 +48,14 @@ func NewGroupRepo(db Database) auth.GroupRepository {
 
 func (gr groupRepository) Save(ctx context.Context, g auth.Group) (auth.Group, error) {
 	// For root group path is initialized with id
-	q := `INSERT INTO groups (name, description, id, path, owner_id, metadata, created_at, updated_at) 
-		  VALUES (:name, :description, :id, :id, :owner_id, :metadata, :created_at, :updated_at) 
-		  RETURNING id, name, owner_id, parent_id, description, metadata, path, nlevel(path) as level, created_at, updated_at`
+	q := `INSERT INTO groups (name, description, id, path, owner_id, org_id, metadata, labels, created_at, updated_at) 
+		  VALUES (:name, :description, :id, :id, :owner_id, :org_id, :metadata, :labels, :created_at, :updated_at) 
+		  RETURNING id, name, owner_id, org_id, parent_id, description, metadata, labels, path, nlevel(path) as level, created_at, updated_at`
 	if g.ParentID != "" {
 		// Path is constructed in insert_group_tr - init.go
-		q = `INSERT INTO groups (name, description, id, owner_id, parent_id, metadata, created_at, updated_at) 
-			 VALUES ( :name, :description, :id, :owner_id, :parent_id, :metadata, :created_at, :updated_at) 
-			 RETURNING id, name, owner_id, parent_id, description, metadata, path, nlevel(path) as level, created_at, updated_at`
+		q = `INSERT INTO groups (name, description, id, owner_id, org_id, parent_id, metadata, labels, created_at, updated_at) 
+			 VALUES ( :name, :description, :id, :owner_id, :org_id, :parent_id, :metadata, :labels, :created_at, :updated_at) 
+			 RETURNING id, name, owner_id, org_id, parent_id, description, metadata, labels, path, nlevel(path) as level, created_at, updated_at`
 	}
 
 	dbg, err := toDBGroup(g)
@@ -89,8 +91,8 @@ func (gr groupRepository) Save(ctx context.Context, g auth.Group) (auth.Group, e
 }
 
 func (gr groupRepository) Update(ctx context.Context, g auth.Group) (auth.Group, error) {
-	q := `UPDATE groups SET name = :name, description = :description, metadata = :metadata, updated_at = :updated_at WHERE id = :id 
-		  RETURNING id, name, owner_id, parent_id, description, metadata, path, nlevel(path) as level, created_at, updated_at`
+	q := `UPDATE groups SET name = :name, description = :description, metadata = :metadata, labels = :labels, updated_at = :updated_at WHERE id = :id 
+		  RETURNING id, name, owner_id, org_id, parent_id, description, metadata, labels, path, nlevel(path) as level, created_at, updated_at`
 
 	dbu, err := toDBGroup(g)
 	if err != nil {
@@ -164,7 +166,7 @@ func (gr groupRepository) RetrieveByID(ctx context.Context, id string) (auth.Gro
 	dbu := dbGroup{
 		ID: id,
 	}
-	q := `SELECT id, name, owner_id, parent_id, description, metadata, path, nlevel(path) as level, created_at, updated_at FROM groups WHERE id = $1`
+	q := `SELECT id, name, owner_id, org_id, parent_id, description, metadata, labels, path, nlevel(path) as level, created_at, updated_at FROM groups WHERE id = $1`
 	if err := gr.db.QueryRowxContext(ctx, q, id).StructScan(&dbu); err != nil {
 		if err == sql.ErrNoRows {
 			return auth.Group{}, errors.Wrap(auth.ErrGroupNotFound, err)
@@ -181,12 +183,18 @@ func (gr groupRepository) RetrieveAll(ctx context.Context, pm auth.PageMetadata)
 		return auth.GroupPage{}, errors.Wrap(auth.ErrFailedToRetrieveAll, err)
 	}
 
-	var mq string
+	// The groups of the organization are listed apart from the others.
+	conds := []string{"org_id IS NOT DISTINCT FROM :org_id"}
 	if metaQuery != "" {
-		mq = fmt.Sprintf(" AND %s", metaQuery)
+		conds = append(conds, metaQuery)
 	}
+	if sq := getGroupsSelectorQuery("groups", pm.Selector); sq != "" {
+		conds = append(conds, sq)
+	}
+
+	mq := fmt.Sprintf(" AND %s", strings.Join(conds, " AND "))
 
-	q := fmt.Sprintf(`SELECT id, owner_id, parent_id, name, description, metadata, path, nlevel(path) as level, created_at, updated_at FROM groups 
+	q := fmt.Sprintf(`SELECT id, owner_id, org_id, parent_id, name, description, metadata, labels, path, nlevel(path) as level, created_at, updated_at FROM groups 
 					  WHERE nlevel(path) <= :level %s ORDER BY path`, mq)
 
 	dbPage, err := toDBGroupPage("", "", pm)
@@ -205,10 +213,7 @@ func (gr groupRepository) RetrieveAll(ctx context.Context, pm auth.PageMetadata)
 		return auth.GroupPage{}, errors.Wrap(auth.ErrFailedToRetrieveAll, err)
 	}
 
-	cq := "SELECT COUNT(*) FROM groups"
-	if metaQuery != "" {
-		cq = fmt.Sprintf(" %s WHERE %s", cq, metaQuery)
-	}
+	cq := fmt.Sprintf("SELECT COUNT(*) FROM groups WHERE %s", strings.Join(conds, " AND "))
 
 	total, err := total(ctx, gr.db, cq, dbPage)
 	if err != nil {
@@ -227,7 +232,7 @@ func (gr groupRepository) RetrieveAll(ctx context.Context, pm auth.PageMetadata)
 }
 
 func (gr groupRepository) RetrieveAllParents(ctx context.Context, groupID string, pm auth.PageMetadata) (auth.GroupPage, error) {
-	q := `SELECT g.id, g.name, g.owner_id, g.parent_id, g.description, g.metadata, g.path, nlevel(g.path) as level, g.created_at, g.updated_at
+	q := `SELECT g.id, g.name, g.owner_id, g.org_id, g.parent_id, g.description, g.metadata, g.labels, g.path, nlevel(g.path) as level, g.created_at, g.updated_at
 		  FROM groups parent, groups g
 	      WHERE parent.id = :id AND g.path @> parent.path AND nlevel(parent.path) - nlevel(g.path) <= :level`
 	cq := `SELECT COUNT(*) FROM groups parent, groups g WHERE parent.id = :id AND g.path @> parent.path`
@@ -240,7 +245,7 @@ func (gr groupRepository) RetrieveAllParents(ctx context.Context, groupID string
 }
 
 func (gr groupRepository) RetrieveAllChildren(ctx context.Context, groupID string, pm auth.PageMetadata) (auth.GroupPage, error) {
-	q := `SELECT g.id, g.name, g.owner_id, g.parent_id, g.description, g.metadata, g.path,  nlevel(g.path) as level, g.created_at, g.updated_at 
+	q := `SELECT g.id, g.name, g.owner_id, g.org_id, g.parent_id, g.description, g.metadata, g.labels, g.path,  nlevel(g.path) as level, g.created_at, g.updated_at 
 	FROM groups parent, groups g
 	WHERE parent.id = :id AND g.path <@ parent.path AND nlevel(g.path) - nlevel(parent.path) < :level`
 
@@ -263,6 +268,9 @@ func (gr groupRepository) retrieve(ctx context.Context, groupID, retQuery, cntQu
 	if mq != "" {
 		mq = fmt.Sprintf("AND %s", mq)
 	}
+	if sq := getGroupsSelectorQuery("g", pm.Selector); sq != "" {
+		mq = fmt.Sprintf("%s AND %s", mq, sq)
+	}
 
 	retQuery = fmt.Sprintf(`%s %s`, retQuery, mq)
 	cntQuery = fmt.Sprintf(`%s %s`, cntQuery, mq)
@@ -302,19 +310,36 @@ func (gr groupRepository) retrieve(ctx context.Context, groupID, retQuery, cntQu
 }
 
 func (gr groupRepository) Members(ctx context.Context, groupID, groupType string, pm auth.PageMetadata) (auth.MemberPage, error) {
-	_, mq, err := getGroupsMetadataQuery("groups", pm.Metadata)
+	_, mq, err := getGroupsMetadataQuery("g", pm.Metadata)
 	if err != nil {
 		return auth.MemberPage{}, errors.Wrap(auth.ErrFailedToRetrieveMembers, err)
 	}
 
-	q := fmt.Sprintf(`SELECT gr.member_id, gr.group_id, gr.type, gr.created_at, gr.updated_at FROM group_relations gr
-					  WHERE gr.group_id = :group_id AND gr.type = :type %s`, mq)
+	if mq != "" {
+		mq = fmt.Sprintf("AND %s", mq)
+	}
+
+	tq := ""
+	if groupType != "" {
+		tq = "AND gr.type = :type"
+	}
+
+	rq := ""
+	if pm.Relation != "" {
+		rq = "AND gr.relation = :relation"
+	}
 
-	if groupType == "" {
-		q = fmt.Sprintf(`SELECT gr.member_id, gr.group_id, gr.type, gr.created_at, gr.updated_at FROM group_relations gr
-		                 WHERE gr.group_id = :group_id %s`, mq)
+	// The zero limit lists all the members.
+	lq := ""
+	if pm.Limit > 0 {
+		lq = "LIMIT :limit OFFSET :offset"
 	}
 
+	q := fmt.Sprintf(`SELECT gr.member_id, gr.group_id, gr.type, gr.relation, gr.created_by, gr.created_at, gr.updated_at
+					  FROM group_relations gr, groups g
+					  WHERE gr.group_id = :group_id AND gr.group_id = g.id %s %s %s
+					  ORDER BY gr.created_at %s`, tq, rq, mq, lq)
+
 	params, err := toDBMemberPage("", groupID, groupType, pm)
 	if err != nil {
 		return auth.MemberPage{}, err
@@ -337,11 +362,17 @@ func (gr groupRepository) Members(ctx context.Context, groupID, groupType string
 			return auth.MemberPage{}, err
 		}
 
-		items = append(items, auth.Member{ID: member.MemberID, Type: member.Type})
+		items = append(items, auth.Member{
+			ID:        member.MemberID,
+			Type:      member.Type,
+			Relation:  member.Relation,
+			CreatedBy: member.CreatedBy,
+			CreatedAt: member.CreatedAt,
+		})
 	}
 
 	cq := fmt.Sprintf(`SELECT COUNT(*) FROM groups g, group_relations gr
-					   WHERE gr.group_id = :group_id AND gr.group_id = g.id AND gr.type = :type %s;`, mq)
+					   WHERE gr.group_id = :group_id AND gr.group_id = g.id %s %s %s;`, tq, rq, mq)
 
 	total, err := total(ctx, gr.db, cq, params)
 	if err != nil {
@@ -370,7 +401,11 @@ func (gr groupRepository) Memberships(ctx context.Context, memberID string, pm a
 	if mq != "" {
 		mq = fmt.Sprintf("AND %s", mq)
 	}
-	q := fmt.Sprintf(`SELECT g.id, g.owner_id, g.parent_id, g.name, g.description, g.metadata 
+	if sq := getGroupsSelectorQuery("g", pm.Selector); sq != "" {
+		mq = fmt.Sprintf("%s AND %s", mq, sq)
+	}
+	mq = fmt.Sprintf("%s AND g.org_id IS NOT DISTINCT FROM :org_id", mq)
+	q := fmt.Sprintf(`SELECT g.id, g.owner_id, g.org_id, g.parent_id, g.name, g.description, g.metadata, g.labels 
 					  FROM group_relations gr, groups g
 					  WHERE gr.group_id = g.id and gr.member_id = :member_id
 		  			  %s ORDER BY id LIMIT :limit OFFSET :offset;`, mq)
@@ -420,20 +455,22 @@ func (gr groupRepository) Memberships(ctx context.Context, memberID string, pm a
 	return page, nil
 }
 
-func (gr groupRepository) Assign(ctx context.Context, groupID, groupType string, ids ...string) error {
+func (gr groupRepository) Assign(ctx context.Context, groupID, groupType string, m auth.Membership, ids ...string) error {
 	tx, err := gr.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return errors.Wrap(auth.ErrAssignToGroup, err)
 	}
 
-	qIns := `INSERT INTO group_relations (group_id, member_id, type, created_at, updated_at) 
-			 VALUES(:group_id, :member_id, :type, :created_at, :updated_at)`
+	qIns := `INSERT INTO group_relations (group_id, member_id, type, relation, created_by, created_at, updated_at)
+			 VALUES(:group_id, :member_id, :type, :relation, :created_by, :created_at, :updated_at)`
 
 	for _, id := range ids {
 		dbg, err := toDBGroupRelation(id, groupID, groupType)
 		if err != nil {
 			return errors.Wrap(auth.ErrAssignToGroup, err)
 		}
+		dbg.Relation = m.Relation
+		dbg.CreatedBy = m.CreatedBy
 		created := time.Now()
 		dbg.CreatedAt = created
 		dbg.UpdatedAt = created
@@ -500,10 +537,119 @@ func (gr groupRepository) Unassign(ctx context.Context, groupID string, ids ...s
 	return nil
 }
 
+func (gr groupRepository) SetExpiration(ctx context.Context, groupID string, expiresAt time.Time, ids ...string) error {
+	tx, err := gr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(auth.ErrAssignToGroup, err)
+	}
+
+	q := `UPDATE group_relations SET expires_at = :expires_at, updated_at = :updated_at
+		  WHERE group_id = :group_id AND member_id = :member_id`
+
+	for _, id := range ids {
+		dbg, err := toDBGroupRelation(id, groupID, "")
+		if err != nil {
+			return errors.Wrap(auth.ErrAssignToGroup, err)
+		}
+		dbg.ExpiresAt = sql.NullTime{Time: expiresAt, Valid: true}
+		dbg.UpdatedAt = time.Now()
+
+		res, err := tx.NamedExecContext(ctx, q, dbg)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(auth.ErrAssignToGroup, err)
+		}
+		cnt, err := res.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(auth.ErrAssignToGroup, err)
+		}
+		if cnt != 1 {
+			tx.Rollback()
+			return errors.Wrap(auth.ErrAssignToGroup, auth.ErrNotFound)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(auth.ErrAssignToGroup, err)
+	}
+
+	return nil
+}
+
+func (gr groupRepository) SetRelation(ctx context.Context, groupID, relation string, ids ...string) error {
+	tx, err := gr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(auth.ErrAssignToGroup, err)
+	}
+
+	q := `UPDATE group_relations SET relation = :relation, updated_at = :updated_at
+		  WHERE group_id = :group_id AND member_id = :member_id`
+
+	for _, id := range ids {
+		dbg, err := toDBGroupRelation(id, groupID, "")
+		if err != nil {
+			return errors.Wrap(auth.ErrAssignToGroup, err)
+		}
+		dbg.Relation = relation
+		dbg.UpdatedAt = time.Now()
+
+		res, err := tx.NamedExecContext(ctx, q, dbg)
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(auth.ErrAssignToGroup, err)
+		}
+		cnt, err := res.RowsAffected()
+		if err != nil {
+			tx.Rollback()
+			return errors.Wrap(auth.ErrAssignToGroup, err)
+		}
+		if cnt != 1 {
+			tx.Rollback()
+			return errors.Wrap(auth.ErrAssignToGroup, auth.ErrNotFound)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(auth.ErrAssignToGroup, err)
+	}
+
+	return nil
+}
+
+func (gr groupRepository) RetrieveExpired(ctx context.Context, before time.Time) ([]auth.MembershipExpiration, error) {
+	q := `SELECT group_id, member_id, type, created_at, updated_at, expires_at FROM group_relations
+		  WHERE expires_at IS NOT NULL AND expires_at < $1 ORDER BY expires_at`
+
+	rows, err := gr.db.QueryxContext(ctx, q, before)
+	if err != nil {
+		return nil, errors.Wrap(auth.ErrFailedToRetrieveMembers, err)
+	}
+	defer rows.Close()
+
+	var items []auth.MembershipExpiration
+	for rows.Next() {
+		dbg := dbGroupRelation{}
+		if err := rows.StructScan(&dbg); err != nil {
+			return nil, errors.Wrap(auth.ErrFailedToRetrieveMembers, err)
+		}
+		items = append(items, auth.MembershipExpiration{
+			GroupID:   dbg.GroupID.String,
+			MemberID:  dbg.MemberID.String,
+			Type:      dbg.Type,
+			ExpiresAt: dbg.ExpiresAt.Time,
+		})
+	}
+
+	return items, nil
+}
+
 type dbMember struct {
 	MemberID  string    `db:"member_id"`
 	GroupID   string    `db:"group_id"`
 	Type      string    `db:"type"`
+	Relation  string    `db:"relation"`
+	CreatedBy string    `db:"created_by"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
@@ -512,9 +658,11 @@ type dbGroup struct {
 	ID          string         `db:"id"`
 	ParentID    sql.NullString `db:"parent_id"`
 	OwnerID     uuid.NullUUID  `db:"owner_id"`
+	OrgID       sql.NullString `db:"org_id"`
 	Name        string         `db:"name"`
 	Description string         `db:"description"`
 	Metadata    dbMetadata     `db:"metadata"`
+	Labels      dbLabels       `db:"labels"`
 	Level       int            `db:"level"`
 	Path        string         `db:"path"`
 	CreatedAt   time.Time      `db:"created_at"`
@@ -522,24 +670,29 @@ type dbGroup struct {
 }
 
 type dbGroupPage struct {
-	ID       string        `db:"id"`
-	ParentID string        `db:"parent_id"`
-	OwnerID  uuid.NullUUID `db:"owner_id"`
-	Metadata dbMetadata    `db:"metadata"`
-	Path     string        `db:"path"`
-	Level    uint64        `db:"level"`
-	Total    uint64        `db:"total"`
-	Limit    uint64        `db:"limit"`
-	Offset   uint64        `db:"offset"`
+	dbSelector
+	ID       string         `db:"id"`
+	ParentID string         `db:"parent_id"`
+	OwnerID  uuid.NullUUID  `db:"owner_id"`
+	OrgID    sql.NullString `db:"org_id"`
+	Metadata dbMetadata     `db:"metadata"`
+	Path     string         `db:"path"`
+	Level    uint64         `db:"level"`
+	Total    uint64         `db:"total"`
+	Limit    uint64         `db:"limit"`
+	Offset   uint64         `db:"offset"`
 }
 
 type dbMemberPage struct {
-	GroupID  string     `db:"group_id"`
-	MemberID string     `db:"member_id"`
-	Type     string     `db:"type"`
-	Metadata dbMetadata `db:"metadata"`
-	Limit    uint64     `db:"limit"`
-	Offset   uint64     `db:"offset"`
+	dbSelector
+	GroupID  string         `db:"group_id"`
+	MemberID string         `db:"member_id"`
+	OrgID    sql.NullString `db:"org_id"`
+	Type     string         `db:"type"`
+	Relation string         `db:"relation"`
+	Metadata dbMetadata     `db:"metadata"`
+	Limit    uint64         `db:"limit"`
+	Offset   uint64         `db:"offset"`
 	Size     uint64
 }
 
@@ -562,6 +715,13 @@ func toString(id uuid.NullUUID) (string, error) {
 	return "", errStringToUUID
 }
 
+func toNullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
 func toDBGroup(g auth.Group) (dbGroup, error) {
 	ownerID, err := toUUID(g.OwnerID)
 	if err != nil {
@@ -580,8 +740,10 @@ func toDBGroup(g auth.Group) (dbGroup, error) {
 		Name:        g.Name,
 		ParentID:    parentID,
 		OwnerID:     ownerID,
+		OrgID:       toNullString(g.OrgID),
 		Description: g.Description,
 		Metadata:    meta,
+		Labels:      dbLabels(g.Labels),
 		Path:        g.Path,
 		CreatedAt:   g.CreatedAt,
 		UpdatedAt:   g.UpdatedAt,
@@ -594,24 +756,29 @@ func toDBGroupPage(id, path string, pm auth.PageMetadata) (dbGroupPage, error) {
 		level = pm.Level
 	}
 	return dbGroupPage{
-		Metadata: dbMetadata(pm.Metadata),
-		ID:       id,
-		Path:     path,
-		Level:    level,
-		Total:    pm.Total,
-		Offset:   pm.Offset,
-		Limit:    pm.Limit,
+		dbSelector: toDBSelector(pm.Selector),
+		Metadata:   dbMetadata(pm.Metadata),
+		OrgID:      toNullString(pm.OrgID),
+		ID:         id,
+		Path:       path,
+		Level:      level,
+		Total:      pm.Total,
+		Offset:     pm.Offset,
+		Limit:      pm.Limit,
 	}, nil
 }
 
 func toDBMemberPage(memberID, groupID, groupType string, pm auth.PageMetadata) (dbMemberPage, error) {
 	return dbMemberPage{
-		GroupID:  groupID,
-		MemberID: memberID,
-		Type:     groupType,
-		Metadata: dbMetadata(pm.Metadata),
-		Offset:   pm.Offset,
-		Limit:    pm.Limit,
+		dbSelector: toDBSelector(pm.Selector),
+		GroupID:    groupID,
+		MemberID:   memberID,
+		OrgID:      toNullString(pm.OrgID),
+		Type:       groupType,
+		Relation:   pm.Relation,
+		Metadata:   dbMetadata(pm.Metadata),
+		Offset:     pm.Offset,
+		Limit:      pm.Limit,
 	}, nil
 }
 
@@ -626,8 +793,10 @@ func toGroup(dbu dbGroup) (auth.Group, error) {
 		Name:        dbu.Name,
 		ParentID:    dbu.ParentID.String,
 		OwnerID:     ownerID,
+		OrgID:       dbu.OrgID.String,
 		Description: dbu.Description,
 		Metadata:    auth.GroupMetadata(dbu.Metadata),
+		Labels:      labels.Labels(dbu.Labels),
 		Level:       dbu.Level,
 		Path:        dbu.Path,
 		UpdatedAt:   dbu.UpdatedAt,
@@ -641,6 +810,9 @@ type dbGroupRelation struct {
 	CreatedAt time.Time      `db:"created_at"`
 	UpdatedAt time.Time      `db:"updated_at"`
 	Type      string         `db:"type"`
+	Relation  string         `db:"relation"`
+	CreatedBy string         `db:"created_by"`
+	ExpiresAt sql.NullTime   `db:"expires_at"`
 }
 
 func toDBGroupRelation(memberID, groupID, groupType string) (dbGroupRelation, error) {
@@ -677,6 +849,33 @@ func getGroupsMetadataQuery(db string, m auth.GroupMetadata) (mb []byte, mq stri
 	return mb, mq, nil
 }
 
+// getGroupsSelectorQuery creates the condition selecting the groups whose
+// labels satisfy the selector. The requirements are grouped by the operator
+// and bound to the fixed parameters of dbSelector.
+func getGroupsSelectorQuery(db string, sel labels.Selector) string {
+	col := "labels"
+	if db != "" {
+		col = db + "." + col
+	}
+
+	s := toDBSelector(sel)
+	var conds []string
+	if len(s.Equals) > 0 {
+		conds = append(conds, fmt.Sprintf("%s @> ALL (CAST(:selector_eq AS jsonb[]))", col))
+	}
+	if len(s.NotEquals) > 0 {
+		conds = append(conds, fmt.Sprintf("NOT (%s @> ANY (CAST(:selector_neq AS jsonb[])))", col))
+	}
+	if len(s.Exists) > 0 {
+		conds = append(conds, fmt.Sprintf("%s ?& :selector_exists", col))
+	}
+	if len(s.NotExists) > 0 {
+		conds = append(conds, fmt.Sprintf("NOT (%s ?| :selector_not_exists)", col))
+	}
+
+	return strings.Join(conds, " AND ")
+}
+
 func (gr groupRepository) processRows(rows *sqlx.Rows) ([]auth.Group, error) {
 	var items []auth.Group
 	for rows.Next() {
@@ -741,3 +940,60 @@ func (m dbMetadata) Value() (driver.Value, error) {
 	}
 	return b, err
 }
+
+// dbLabels type for handling labels properly in database/sql
+type dbLabels labels.Labels
+
+// Scan - Implement the database/sql scanner interface
+func (l *dbLabels) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+
+	b, ok := value.([]byte)
+	if !ok {
+		return users.ErrScanMetadata
+	}
+
+	return json.Unmarshal(b, l)
+}
+
+// Value Implements valuer, storing the missing labels as the empty ones
+func (l dbLabels) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(l)
+}
+
+// dbSelector holds the selector requirements grouped by the operator. The
+// equality requirements are kept as the single label JSON objects.
+type dbSelector struct {
+	Equals    pq.StringArray `db:"selector_eq"`
+	NotEquals pq.StringArray `db:"selector_neq"`
+	Exists    pq.StringArray `db:"selector_exists"`
+	NotExists pq.StringArray `db:"selector_not_exists"`
+}
+
+func toDBSelector(sel labels.Selector) dbSelector {
+	var s dbSelector
+	for _, r := range sel {
+		switch r.Operator {
+		case labels.Equals, labels.NotEquals:
+			// Marshaling the map of strings can't fail.
+			b, _ := json.Marshal(labels.Labels{r.Key: r.Value})
+			if r.Operator == labels.Equals {
+				s.Equals = append(s.Equals, string(b))
+				continue
+			}
+			s.NotEquals = append(s.NotEquals, string(b))
+		case labels.Exists:
+			s.Exists = append(s.Exists, r.Key)
+		case labels.NotExists:
+			s.NotExists = append(s.NotExists, r.Key)
+		}
+	}
+
+	return s
+}
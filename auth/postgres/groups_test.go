@@ -333,7 +333,7 @@ func TestGroupDelete(t *testing.T) {
 	thingID, err := idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("thing id create unexpected error: %s", err))
 
-	err = groupRepo.Assign(context.Background(), groupChild1.ID, "things", thingID)
+	err = groupRepo.Assign(context.Background(), groupChild1.ID, "things", auth.Membership{Relation: auth.ViewerRelation}, thingID)
 	require.Nil(t, err, fmt.Sprintf("thing assign got unexpected error: %s", err))
 
 	err = groupRepo.Delete(context.Background(), groupChild1.ID)
@@ -710,17 +710,144 @@ func TestAssign(t *testing.T) {
 	mid, err := idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 
-	err = groupRepo.Assign(context.Background(), group.ID, "things", mid)
+	err = groupRepo.Assign(context.Background(), group.ID, "things", auth.Membership{Relation: auth.ViewerRelation}, mid)
 	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
 
 	mp, err := groupRepo.Members(context.Background(), group.ID, "things", pm)
 	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
 	assert.True(t, mp.Total == 1, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, mp.Total))
 
-	err = groupRepo.Assign(context.Background(), group.ID, "things", mid)
+	err = groupRepo.Assign(context.Background(), group.ID, "things", auth.Membership{Relation: auth.ViewerRelation}, mid)
 	assert.True(t, errors.Contains(err, auth.ErrMemberAlreadyAssigned), fmt.Sprintf("assign member again: expected %v got %v\n", auth.ErrMemberAlreadyAssigned, err))
 }
 
+func TestMembersByRelation(t *testing.T) {
+	t.Cleanup(func() { cleanUp(t) })
+	dbMiddleware := postgres.NewDatabase(db)
+	groupRepo := postgres.NewGroupRepo(dbMiddleware)
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	creationTime := time.Now().UTC()
+	group := auth.Group{
+		ID:        generateGroupID(t),
+		Name:      groupName + "Relations",
+		OwnerID:   uid,
+		CreatedAt: creationTime,
+		UpdatedAt: creationTime,
+	}
+
+	group, err = groupRepo.Save(context.Background(), group)
+	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
+
+	viewer, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	editor, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = groupRepo.Assign(context.Background(), group.ID, "users", auth.Membership{Relation: auth.ViewerRelation, CreatedBy: uid}, viewer, editor)
+	require.Nil(t, err, fmt.Sprintf("member assign unexpected error: %s", err))
+
+	err = groupRepo.SetRelation(context.Background(), group.ID, auth.EditorRelation, editor)
+	require.Nil(t, err, fmt.Sprintf("set relation unexpected error: %s", err))
+
+	stranger, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = groupRepo.SetRelation(context.Background(), group.ID, auth.EditorRelation, stranger)
+	assert.True(t, errors.Contains(err, auth.ErrNotFound), fmt.Sprintf("set relation of non-member: expected %s got %s\n", auth.ErrNotFound, err))
+
+	cases := map[string]struct {
+		relation string
+		members  []string
+	}{
+		"retrieve all members": {
+			relation: "",
+			members:  []string{viewer, editor},
+		},
+		"retrieve viewers": {
+			relation: auth.ViewerRelation,
+			members:  []string{viewer},
+		},
+		"retrieve editors": {
+			relation: auth.EditorRelation,
+			members:  []string{editor},
+		},
+		"retrieve owners": {
+			relation: auth.OwnerRelation,
+			members:  []string{},
+		},
+	}
+
+	for desc, tc := range cases {
+		mp, err := groupRepo.Members(context.Background(), group.ID, "users", auth.PageMetadata{Limit: 10, Relation: tc.relation})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+		assert.Equal(t, uint64(len(tc.members)), mp.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, len(tc.members), mp.Total))
+		for i, m := range mp.Members {
+			assert.Equal(t, tc.members[i], m.ID, fmt.Sprintf("%s: expected member %s got %s\n", desc, tc.members[i], m.ID))
+			assert.Equal(t, uid, m.CreatedBy, fmt.Sprintf("%s: expected creator %s got %s\n", desc, uid, m.CreatedBy))
+		}
+	}
+}
+
+func TestMembersPage(t *testing.T) {
+	t.Cleanup(func() { cleanUp(t) })
+	dbMiddleware := postgres.NewDatabase(db)
+	groupRepo := postgres.NewGroupRepo(dbMiddleware)
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	creationTime := time.Now().UTC()
+	group := auth.Group{
+		ID:        generateGroupID(t),
+		Name:      groupName + "Page",
+		OwnerID:   uid,
+		CreatedAt: creationTime,
+		UpdatedAt: creationTime,
+	}
+
+	group, err = groupRepo.Save(context.Background(), group)
+	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
+
+	n := uint64(5)
+	for i := uint64(0); i < n; i++ {
+		mid, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		err = groupRepo.Assign(context.Background(), group.ID, "users", auth.Membership{Relation: auth.ViewerRelation}, mid)
+		require.Nil(t, err, fmt.Sprintf("member assign unexpected error: %s", err))
+	}
+
+	cases := map[string]struct {
+		offset uint64
+		limit  uint64
+		size   uint64
+	}{
+		"retrieve page of members": {
+			offset: 1,
+			limit:  2,
+			size:   2,
+		},
+		"retrieve last page of members": {
+			offset: 4,
+			limit:  2,
+			size:   1,
+		},
+		"retrieve all members with zero limit": {
+			offset: 0,
+			limit:  0,
+			size:   n,
+		},
+	}
+
+	for desc, tc := range cases {
+		mp, err := groupRepo.Members(context.Background(), group.ID, "users", auth.PageMetadata{Offset: tc.offset, Limit: tc.limit})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", desc, err))
+		assert.Equal(t, tc.size, uint64(len(mp.Members)), fmt.Sprintf("%s: expected size %d got %d\n", desc, tc.size, len(mp.Members)))
+		assert.Equal(t, n, mp.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, n, mp.Total))
+	}
+}
+
 func TestUnassign(t *testing.T) {
 	t.Cleanup(func() { cleanUp(t) })
 	dbMiddleware := postgres.NewDatabase(db)
@@ -749,12 +876,12 @@ func TestUnassign(t *testing.T) {
 	mid, err := idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
 
-	err = groupRepo.Assign(context.Background(), group.ID, "things", mid)
+	err = groupRepo.Assign(context.Background(), group.ID, "things", auth.Membership{Relation: auth.ViewerRelation}, mid)
 	require.Nil(t, err, fmt.Sprintf("member assign unexpected error: %s", err))
 
 	mid, err = idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-	err = groupRepo.Assign(context.Background(), group.ID, "things", mid)
+	err = groupRepo.Assign(context.Background(), group.ID, "things", auth.Membership{Relation: auth.ViewerRelation}, mid)
 	require.Nil(t, err, fmt.Sprintf("member assign unexpected error: %s", err))
 
 	mp, err := groupRepo.Members(context.Background(), group.ID, "things", pm)
@@ -109,6 +109,327 @@ func migrateDB(db *sqlx.DB) error {
 					`DROP TRIGGER IF EXISTS inherit_group_tr ON groups`,
 				},
 			},
+			{
+				Id: "auth_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS shares (
+						id          VARCHAR(254) PRIMARY KEY,
+						grantor_id  VARCHAR(254) NOT NULL,
+						grantee_id  VARCHAR(254) NOT NULL,
+						object      VARCHAR(254) NOT NULL,
+						actions     TEXT[] NOT NULL,
+						created_at  TIMESTAMPTZ
+					)`,
+					`CREATE INDEX shares_grantor_idx ON shares (grantor_id)`,
+					`CREATE INDEX shares_grantee_idx ON shares (grantee_id, object)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS shares`,
+				},
+			},
+			{
+				Id: "auth_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS service_accounts (
+						id          VARCHAR(254) PRIMARY KEY,
+						name        VARCHAR(254) NOT NULL,
+						owner_id    VARCHAR(254) NOT NULL,
+						scopes      TEXT[] NOT NULL,
+						created_by  VARCHAR(254) NOT NULL,
+						created_at  TIMESTAMPTZ,
+						UNIQUE      (owner_id, name)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS service_accounts`,
+				},
+			},
+			{
+				Id: "auth_4",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS quotas (
+						org_id        VARCHAR(254) PRIMARY KEY,
+						max_users     BIGINT NOT NULL DEFAULT 0,
+						max_things    BIGINT NOT NULL DEFAULT 0,
+						max_channels  BIGINT NOT NULL DEFAULT 0,
+						message_rate  DOUBLE PRECISION NOT NULL DEFAULT 0,
+						updated_at    TIMESTAMPTZ
+					)`,
+					`CREATE TABLE IF NOT EXISTS quota_usage (
+						org_id       VARCHAR(254) NOT NULL,
+						resource     VARCHAR(254) NOT NULL,
+						resource_id  VARCHAR(254) NOT NULL,
+						PRIMARY KEY  (org_id, resource, resource_id)
+					)`,
+					`CREATE INDEX quota_usage_resource_idx ON quota_usage (resource, resource_id)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS quota_usage`,
+					`DROP TABLE IF EXISTS quotas`,
+				},
+			},
+			{
+				Id: "auth_5",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS roles (
+						name        VARCHAR(254) PRIMARY KEY,
+						relations   TEXT[] NOT NULL,
+						created_at  TIMESTAMPTZ
+					)`,
+					`CREATE TABLE IF NOT EXISTS role_assignments (
+						role         VARCHAR(254) NOT NULL,
+						subject      VARCHAR(254) NOT NULL,
+						object       VARCHAR(254) NOT NULL,
+						relations    TEXT[] NOT NULL,
+						assigned_by  VARCHAR(254) NOT NULL,
+						created_at   TIMESTAMPTZ,
+						PRIMARY KEY  (role, subject, object)
+					)`,
+					`CREATE INDEX role_assignments_subject_idx ON role_assignments (subject, object)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS role_assignments`,
+					`DROP TABLE IF EXISTS roles`,
+				},
+			},
+			{
+				Id: "auth_6",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS policy_expirations (
+						subject     VARCHAR(254) NOT NULL,
+						object      VARCHAR(254) NOT NULL,
+						relation    VARCHAR(254) NOT NULL,
+						expires_at  TIMESTAMPTZ NOT NULL,
+						PRIMARY KEY (subject, object, relation)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS policy_expirations`,
+				},
+			},
+			{
+				Id: "auth_7",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS audit_records (
+						id          BIGSERIAL PRIMARY KEY,
+						operation   VARCHAR(16) NOT NULL,
+						subject     VARCHAR(254) NOT NULL,
+						object      VARCHAR(254) NOT NULL,
+						relation    VARCHAR(254) NOT NULL,
+						decision    VARCHAR(16) NOT NULL,
+						created_at  TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS audit_records_subject_idx ON audit_records (subject, created_at)`,
+					`CREATE INDEX IF NOT EXISTS audit_records_object_idx ON audit_records (object, created_at)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS audit_records`,
+				},
+			},
+			{
+				Id: "auth_8",
+				Up: []string{
+					`ALTER TABLE IF EXISTS keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{}'`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS keys DROP COLUMN IF EXISTS scopes`,
+				},
+			},
+			{
+				Id: "auth_9",
+				Up: []string{
+					`ALTER TABLE IF EXISTS group_relations ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ`,
+					`CREATE INDEX IF NOT EXISTS group_relations_expires_at_idx ON group_relations (expires_at) WHERE expires_at IS NOT NULL`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS group_relations_expires_at_idx`,
+					`ALTER TABLE IF EXISTS group_relations DROP COLUMN IF EXISTS expires_at`,
+				},
+			},
+			{
+				Id: "auth_10",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS revocations (
+						key_id      VARCHAR(254) PRIMARY KEY,
+						issuer_id   VARCHAR(254) NOT NULL,
+						revoked_at  TIMESTAMPTZ NOT NULL,
+						expires_at  TIMESTAMPTZ
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS revocations`,
+				},
+			},
+			{
+				Id: "auth_11",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS issuer_revocations (
+						issuer_id   VARCHAR(254) PRIMARY KEY,
+						revoked_at  TIMESTAMPTZ NOT NULL
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS issuer_revocations`,
+				},
+			},
+			{
+				Id: "auth_12",
+				Up: []string{
+					`ALTER TABLE IF EXISTS group_relations ADD COLUMN IF NOT EXISTS relation VARCHAR(16) NOT NULL DEFAULT 'viewer'`,
+					`ALTER TABLE IF EXISTS group_relations ADD COLUMN IF NOT EXISTS created_by VARCHAR(254) NOT NULL DEFAULT ''`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS group_relations DROP COLUMN IF EXISTS relation`,
+					`ALTER TABLE IF EXISTS group_relations DROP COLUMN IF EXISTS created_by`,
+				},
+			},
+			{
+				Id: "auth_13",
+				Up: []string{
+					`ALTER TABLE IF EXISTS policy_expirations ADD COLUMN IF NOT EXISTS namespace VARCHAR(254) NOT NULL DEFAULT 'members'`,
+					`ALTER TABLE IF EXISTS policy_expirations DROP CONSTRAINT IF EXISTS policy_expirations_pkey`,
+					`ALTER TABLE IF EXISTS policy_expirations ADD PRIMARY KEY (subject, object, relation, namespace)`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS policy_expirations DROP CONSTRAINT IF EXISTS policy_expirations_pkey`,
+					`DELETE FROM policy_expirations WHERE namespace != 'members'`,
+					`ALTER TABLE IF EXISTS policy_expirations DROP COLUMN IF EXISTS namespace`,
+					`ALTER TABLE IF EXISTS policy_expirations ADD PRIMARY KEY (subject, object, relation)`,
+				},
+			},
+			{
+				Id: "auth_14",
+				Up: []string{
+					`ALTER TABLE IF EXISTS groups ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}'`,
+					`CREATE INDEX IF NOT EXISTS groups_labels ON groups USING GIN (labels)`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS groups DROP COLUMN IF EXISTS labels`,
+				},
+			},
+			{
+				Id: "auth_15",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS oidc_codes (
+						code                  CHAR(64) PRIMARY KEY,
+						client_id             VARCHAR(254) NOT NULL,
+						redirect_uri          TEXT NOT NULL,
+						scope                 TEXT NOT NULL,
+						nonce                 TEXT NOT NULL,
+						code_challenge        TEXT NOT NULL,
+						code_challenge_method VARCHAR(16) NOT NULL,
+						user_id               VARCHAR(254) NOT NULL,
+						email                 VARCHAR(254) NOT NULL,
+						expires_at            TIMESTAMP NOT NULL
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS oidc_codes`,
+				},
+			},
+			{
+				Id: "auth_16",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS orgs (
+						id          UUID PRIMARY KEY,
+						owner_id    VARCHAR(254) NOT NULL,
+						name        VARCHAR(254) NOT NULL,
+						description VARCHAR(1024),
+						metadata    JSONB,
+						created_at  TIMESTAMPTZ NOT NULL,
+						updated_at  TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE TABLE IF NOT EXISTS org_members (
+						org_id      UUID NOT NULL,
+						member_id   VARCHAR(254) NOT NULL,
+						created_by  VARCHAR(254) NOT NULL,
+						created_at  TIMESTAMPTZ NOT NULL,
+						FOREIGN KEY (org_id) REFERENCES orgs (id) ON DELETE CASCADE,
+						PRIMARY KEY (org_id, member_id)
+					)`,
+					`ALTER TABLE IF EXISTS groups ADD COLUMN IF NOT EXISTS org_id UUID REFERENCES orgs (id)`,
+					`ALTER TABLE IF EXISTS keys ADD COLUMN IF NOT EXISTS org_id VARCHAR(254) NOT NULL DEFAULT ''`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS keys DROP COLUMN IF EXISTS org_id`,
+					`ALTER TABLE IF EXISTS groups DROP COLUMN IF EXISTS org_id`,
+					`DROP TABLE IF EXISTS org_members`,
+					`DROP TABLE IF EXISTS orgs`,
+				},
+			},
+			{
+				Id: "auth_17",
+				Up: []string{
+					`ALTER TABLE IF EXISTS keys ADD COLUMN IF NOT EXISTS allowed_ips TEXT[]`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS keys DROP COLUMN IF EXISTS allowed_ips`,
+				},
+			},
+			{
+				Id: "auth_18",
+				Up: []string{
+					`ALTER TABLE IF EXISTS keys ADD COLUMN IF NOT EXISTS label VARCHAR(254) NOT NULL DEFAULT ''`,
+					`CREATE INDEX IF NOT EXISTS keys_issuer_idx ON keys (issuer_id, org_id, issued_at)`,
+				},
+				Down: []string{
+					`DROP INDEX IF EXISTS keys_issuer_idx`,
+					`ALTER TABLE IF EXISTS keys DROP COLUMN IF EXISTS label`,
+				},
+			},
+			{
+				Id: "auth_19",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS oidc_links (
+						issuer     VARCHAR(1024) NOT NULL,
+						subject    VARCHAR(254) NOT NULL,
+						user_id    VARCHAR(254) NOT NULL,
+						email      VARCHAR(254) NOT NULL,
+						created_at TIMESTAMPTZ NOT NULL,
+						PRIMARY KEY (issuer, subject)
+					)`,
+					`CREATE INDEX IF NOT EXISTS oidc_links_user_idx ON oidc_links (user_id)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS oidc_links`,
+				},
+			},
+			{
+				Id: "auth_20",
+				Up: []string{
+					`ALTER TABLE IF EXISTS keys ADD COLUMN IF NOT EXISTS claims JSONB NOT NULL DEFAULT '{}'`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS keys DROP COLUMN IF EXISTS claims`,
+				},
+			},
+			{
+				Id: "auth_21",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS sessions (
+						id            VARCHAR(254) PRIMARY KEY,
+						user_id       VARCHAR(254) NOT NULL,
+						device        VARCHAR(254) NOT NULL DEFAULT '',
+						ip            VARCHAR(64) NOT NULL DEFAULT '',
+						issued_at     TIMESTAMPTZ NOT NULL,
+						last_seen_at  TIMESTAMPTZ NOT NULL,
+						expires_at    TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions (user_id)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS sessions`,
+				},
+			},
+			{
+				Id: "auth_22",
+				Up: []string{
+					`ALTER TABLE IF EXISTS service_accounts ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS service_accounts DROP COLUMN IF EXISTS disabled`,
+				},
+			},
 		},
 	}
 
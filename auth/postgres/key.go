@@ -14,6 +14,7 @@ var (
 	errSave     = errors.New("failed to save key in database")
 	errRetrieve = errors.New("failed to retrieve key from database")
 	errDelete   = errors.New("failed to delete key from database")
+	errUpdate   = errors.New("failed to update key in database")
 )
 var _ auth.KeyRepository = (*repo)(nil)
 
@@ -34,8 +35,8 @@ func New(db Database) auth.KeyRepository {
 }
 
 func (kr repo) Save(ctx context.Context, key auth.Key) (string, error) {
-	q := `INSERT INTO keys (id, type, issuer_id, subject, issued_at, expires_at)
-	      VALUES (:id, :type, :issuer_id, :subject, :issued_at, :expires_at)`
+	q := `INSERT INTO keys (id, type, issuer_id, subject, org_id, issued_at, expires_at, scopes, allowed_ips, label, claims)
+	      VALUES (:id, :type, :issuer_id, :subject, :org_id, :issued_at, :expires_at, :scopes, :allowed_ips, :label, :claims)`
 
 	dbKey := toDBKey(key)
 	if _, err := kr.db.NamedExecContext(ctx, q, dbKey); err != nil {
@@ -54,7 +55,7 @@ func (kr repo) Save(ctx context.Context, key auth.Key) (string, error) {
 }
 
 func (kr repo) Retrieve(ctx context.Context, issuerID, id string) (auth.Key, error) {
-	q := `SELECT id, type, issuer_id, subject, issued_at, expires_at FROM keys WHERE issuer_id = $1 AND id = $2`
+	q := `SELECT id, type, issuer_id, subject, org_id, issued_at, expires_at, scopes, allowed_ips, label, claims FROM keys WHERE issuer_id = $1 AND id = $2`
 	key := dbKey{}
 	if err := kr.db.QueryRowxContext(ctx, q, issuerID, id).StructScan(&key); err != nil {
 		pqErr, ok := err.(*pq.Error)
@@ -81,23 +82,119 @@ func (kr repo) Remove(ctx context.Context, issuerID, id string) error {
 	return nil
 }
 
+func (kr repo) RetrieveByIssuer(ctx context.Context, issuerID, orgID string, offset, limit uint64) (auth.KeysPage, error) {
+	q := `SELECT id, type, issuer_id, subject, org_id, issued_at, expires_at, scopes, allowed_ips, label, claims FROM keys
+	      WHERE issuer_id = :issuer_id AND org_id = :org_id ORDER BY issued_at, id LIMIT :limit OFFSET :offset`
+	params := dbKeysPage{IssuerID: issuerID, OrgID: orgID, Offset: offset, Limit: limit}
+
+	rows, err := kr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return auth.KeysPage{}, errors.Wrap(errRetrieve, err)
+	}
+	defer rows.Close()
+
+	var keys []auth.Key
+	for rows.Next() {
+		key := dbKey{}
+		if err := rows.StructScan(&key); err != nil {
+			return auth.KeysPage{}, errors.Wrap(errRetrieve, err)
+		}
+		keys = append(keys, toKey(key))
+	}
+
+	cq := `SELECT COUNT(*) FROM keys WHERE issuer_id = :issuer_id AND org_id = :org_id`
+	total, err := total(ctx, kr.db, cq, params)
+	if err != nil {
+		return auth.KeysPage{}, errors.Wrap(errRetrieve, err)
+	}
+
+	return auth.KeysPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Keys:   keys,
+	}, nil
+}
+
+func (kr repo) UpdateLabel(ctx context.Context, issuerID, id, label string) error {
+	q := `UPDATE keys SET label = :label WHERE issuer_id = :issuer_id AND id = :id`
+
+	res, err := kr.db.NamedExecContext(ctx, q, dbKey{ID: id, IssuerID: issuerID, Label: label})
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return errors.Wrap(auth.ErrNotFound, err)
+		}
+		return errors.Wrap(errUpdate, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdate, err)
+	}
+	if cnt != 1 {
+		return auth.ErrNotFound
+	}
+
+	return nil
+}
+
+func (kr repo) RemoveByIssuer(ctx context.Context, issuerID string) ([]auth.Key, error) {
+	q := `DELETE FROM keys WHERE issuer_id = $1
+	      RETURNING id, type, issuer_id, subject, org_id, issued_at, expires_at, scopes, allowed_ips, label, claims`
+
+	rows, err := kr.db.QueryxContext(ctx, q, issuerID)
+	if err != nil {
+		return nil, errors.Wrap(errDelete, err)
+	}
+	defer rows.Close()
+
+	var keys []auth.Key
+	for rows.Next() {
+		key := dbKey{}
+		if err := rows.StructScan(&key); err != nil {
+			return nil, errors.Wrap(errDelete, err)
+		}
+		keys = append(keys, toKey(key))
+	}
+
+	return keys, nil
+}
+
 type dbKey struct {
-	ID        string       `db:"id"`
-	Type      uint32       `db:"type"`
-	IssuerID  string       `db:"issuer_id"`
-	Subject   string       `db:"subject"`
-	Revoked   bool         `db:"revoked"`
-	IssuedAt  time.Time    `db:"issued_at"`
-	ExpiresAt sql.NullTime `db:"expires_at"`
+	ID         string         `db:"id"`
+	Type       uint32         `db:"type"`
+	IssuerID   string         `db:"issuer_id"`
+	Subject    string         `db:"subject"`
+	OrgID      string         `db:"org_id"`
+	Revoked    bool           `db:"revoked"`
+	IssuedAt   time.Time      `db:"issued_at"`
+	ExpiresAt  sql.NullTime   `db:"expires_at"`
+	Scopes     pq.StringArray `db:"scopes"`
+	AllowedIPs pq.StringArray `db:"allowed_ips"`
+	Label      string         `db:"label"`
+	Claims     dbLabels       `db:"claims"`
+}
+
+type dbKeysPage struct {
+	IssuerID string `db:"issuer_id"`
+	OrgID    string `db:"org_id"`
+	Offset   uint64 `db:"offset"`
+	Limit    uint64 `db:"limit"`
 }
 
 func toDBKey(key auth.Key) dbKey {
 	ret := dbKey{
-		ID:       key.ID,
-		Type:     key.Type,
-		IssuerID: key.IssuerID,
-		Subject:  key.Subject,
-		IssuedAt: key.IssuedAt,
+		ID:         key.ID,
+		Type:       key.Type,
+		IssuerID:   key.IssuerID,
+		Subject:    key.Subject,
+		OrgID:      key.OrgID,
+		IssuedAt:   key.IssuedAt,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		Label:      key.Label,
+		Claims:     dbLabels(key.Claims),
 	}
 	if !key.ExpiresAt.IsZero() {
 		ret.ExpiresAt = sql.NullTime{Time: key.ExpiresAt, Valid: true}
@@ -108,11 +205,18 @@ func toDBKey(key auth.Key) dbKey {
 
 func toKey(key dbKey) auth.Key {
 	ret := auth.Key{
-		ID:       key.ID,
-		Type:     key.Type,
-		IssuerID: key.IssuerID,
-		Subject:  key.Subject,
-		IssuedAt: key.IssuedAt,
+		ID:         key.ID,
+		Type:       key.Type,
+		IssuerID:   key.IssuerID,
+		Subject:    key.Subject,
+		OrgID:      key.OrgID,
+		IssuedAt:   key.IssuedAt,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		Label:      key.Label,
+	}
+	if len(key.Claims) > 0 {
+		ret.Claims = key.Claims
 	}
 	if key.ExpiresAt.Valid {
 		ret.ExpiresAt = key.ExpiresAt.Time
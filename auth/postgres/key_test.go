@@ -158,3 +158,136 @@ func TestKeyRemove(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
+
+func TestKeyRetrieveByIssuer(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.New(dbMiddleware)
+
+	issuerID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	n := uint64(5)
+	issued := time.Now()
+	for i := uint64(0); i < n; i++ {
+		id, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		key := auth.Key{
+			ID:       id,
+			Type:     auth.APIKey,
+			Subject:  email,
+			IssuerID: issuerID,
+			IssuedAt: issued.Add(time.Duration(i) * time.Second),
+			Label:    fmt.Sprintf("key-%d", i),
+		}
+		_, err = repo.Save(context.Background(), key)
+		require.Nil(t, err, fmt.Sprintf("Storing Key expected to succeed: %s", err))
+	}
+
+	cases := []struct {
+		desc   string
+		issuer string
+		orgID  string
+		offset uint64
+		limit  uint64
+		size   uint64
+		total  uint64
+	}{
+		{
+			desc:   "retrieve all keys of the issuer",
+			issuer: issuerID,
+			offset: 0,
+			limit:  n,
+			size:   n,
+			total:  n,
+		},
+		{
+			desc:   "retrieve the last page of the keys",
+			issuer: issuerID,
+			offset: 3,
+			limit:  n,
+			size:   2,
+			total:  n,
+		},
+		{
+			desc:   "retrieve the keys of another organization",
+			issuer: issuerID,
+			orgID:  "org",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+		{
+			desc:   "retrieve the keys of unknown issuer",
+			issuer: "unknown",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			total:  0,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := repo.RetrieveByIssuer(context.Background(), tc.issuer, tc.orgID, tc.offset, tc.limit)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.size, uint64(len(page.Keys)), fmt.Sprintf("%s: expected size %d got %d\n", tc.desc, tc.size, len(page.Keys)))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+	}
+}
+
+func TestKeyUpdateLabel(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.New(dbMiddleware)
+
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	key := auth.Key{
+		ID:       id,
+		Type:     auth.APIKey,
+		Subject:  email,
+		IssuerID: id,
+		IssuedAt: time.Now(),
+	}
+	_, err = repo.Save(context.Background(), key)
+	require.Nil(t, err, fmt.Sprintf("Storing Key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		id    string
+		owner string
+		label string
+		err   error
+	}{
+		{
+			desc:  "update label of an existing key",
+			id:    key.ID,
+			owner: key.IssuerID,
+			label: "ci",
+			err:   nil,
+		},
+		{
+			desc:  "update label of the key of another issuer",
+			id:    key.ID,
+			owner: "",
+			label: "other",
+			err:   auth.ErrNotFound,
+		},
+		{
+			desc:  "update label of unknown key",
+			id:    "unknown",
+			owner: key.IssuerID,
+			label: "other",
+			err:   auth.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.UpdateLabel(context.Background(), tc.owner, tc.id, tc.label)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	saved, err := repo.Retrieve(context.Background(), key.IssuerID, key.ID)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	assert.Equal(t, "ci", saved.Label, fmt.Sprintf("expected label %s got %s\n", "ci", saved.Label))
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/auth/oidc"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveLink     = errors.New("failed to save identity link in database")
+	errRetrieveLink = errors.New("failed to retrieve identity link from database")
+	errRemoveLink   = errors.New("failed to remove identity link from database")
+)
+
+var _ oidc.LinkRepository = (*linkRepository)(nil)
+
+type linkRepository struct {
+	db Database
+}
+
+// NewLinkRepo instantiates a PostgreSQL implementation of the external
+// identity link repository.
+func NewLinkRepo(db Database) oidc.LinkRepository {
+	return &linkRepository{
+		db: db,
+	}
+}
+
+func (lr linkRepository) Save(ctx context.Context, link oidc.Link) error {
+	q := `INSERT INTO oidc_links (issuer, subject, user_id, email, created_at)
+	      VALUES (:issuer, :subject, :user_id, :email, :created_at)`
+
+	if _, err := lr.db.NamedExecContext(ctx, q, toDBLink(link)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(oidc.ErrConflict, err)
+		}
+		return errors.Wrap(errSaveLink, err)
+	}
+
+	return nil
+}
+
+func (lr linkRepository) Retrieve(ctx context.Context, issuer, subject string) (oidc.Link, error) {
+	q := `SELECT issuer, subject, user_id, email, created_at FROM oidc_links WHERE issuer = $1 AND subject = $2`
+
+	var dbl dbLink
+	if err := lr.db.QueryRowxContext(ctx, q, issuer, subject).StructScan(&dbl); err != nil {
+		if err == sql.ErrNoRows {
+			return oidc.Link{}, errors.Wrap(oidc.ErrNotFound, err)
+		}
+		return oidc.Link{}, errors.Wrap(errRetrieveLink, err)
+	}
+
+	return toLink(dbl), nil
+}
+
+func (lr linkRepository) RetrieveByUser(ctx context.Context, userID string) ([]oidc.Link, error) {
+	q := `SELECT issuer, subject, user_id, email, created_at FROM oidc_links
+	      WHERE user_id = $1 ORDER BY issuer, subject`
+
+	rows, err := lr.db.QueryxContext(ctx, q, userID)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveLink, err)
+	}
+	defer rows.Close()
+
+	var links []oidc.Link
+	for rows.Next() {
+		var dbl dbLink
+		if err := rows.StructScan(&dbl); err != nil {
+			return nil, errors.Wrap(errRetrieveLink, err)
+		}
+		links = append(links, toLink(dbl))
+	}
+
+	return links, nil
+}
+
+func (lr linkRepository) Remove(ctx context.Context, userID, issuer, subject string) error {
+	q := `DELETE FROM oidc_links WHERE user_id = :user_id AND issuer = :issuer AND subject = :subject`
+
+	res, err := lr.db.NamedExecContext(ctx, q, dbLink{Issuer: issuer, Subject: subject, UserID: userID})
+	if err != nil {
+		return errors.Wrap(errRemoveLink, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errRemoveLink, err)
+	}
+	if cnt != 1 {
+		return oidc.ErrNotFound
+	}
+
+	return nil
+}
+
+func (lr linkRepository) RemoveByUser(ctx context.Context, userID string) error {
+	q := `DELETE FROM oidc_links WHERE user_id = :user_id`
+
+	if _, err := lr.db.NamedExecContext(ctx, q, dbLink{UserID: userID}); err != nil {
+		return errors.Wrap(errRemoveLink, err)
+	}
+
+	return nil
+}
+
+type dbLink struct {
+	Issuer    string    `db:"issuer"`
+	Subject   string    `db:"subject"`
+	UserID    string    `db:"user_id"`
+	Email     string    `db:"email"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBLink(l oidc.Link) dbLink {
+	return dbLink{
+		Issuer:    l.Issuer,
+		Subject:   l.Subject,
+		UserID:    l.UserID,
+		Email:     l.Email,
+		CreatedAt: l.CreatedAt,
+	}
+}
+
+func toLink(dbl dbLink) oidc.Link {
+	return oidc.Link{
+		Issuer:    dbl.Issuer,
+		Subject:   dbl.Subject,
+		UserID:    dbl.UserID,
+		Email:     dbl.Email,
+		CreatedAt: dbl.CreatedAt,
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveOrg          = errors.New("failed to save organization in database")
+	errUpdateOrg        = errors.New("failed to update organization in database")
+	errRetrieveOrg      = errors.New("failed to retrieve organization from database")
+	errDeleteOrg        = errors.New("failed to delete organization from database")
+	errAssignOrgMembers = errors.New("failed to assign organization members in database")
+	errUnassignMembers  = errors.New("failed to unassign organization members in database")
+	errRetrieveMembers  = errors.New("failed to retrieve organization members from database")
+)
+
+var _ auth.OrgRepository = (*orgRepository)(nil)
+
+type orgRepository struct {
+	db Database
+}
+
+// NewOrgRepo instantiates a PostgreSQL implementation of organization
+// repository.
+func NewOrgRepo(db Database) auth.OrgRepository {
+	return &orgRepository{
+		db: db,
+	}
+}
+
+func (or orgRepository) Save(ctx context.Context, o auth.Org) error {
+	tx, err := or.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errSaveOrg, err)
+	}
+
+	qOrg := `INSERT INTO orgs (id, owner_id, name, description, metadata, created_at, updated_at)
+	         VALUES (:id, :owner_id, :name, :description, :metadata, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, qOrg, toDBOrg(o)); err != nil {
+		tx.Rollback()
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(auth.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(auth.ErrConflict, err)
+			}
+		}
+		return errors.Wrap(errSaveOrg, err)
+	}
+
+	qMember := `INSERT INTO org_members (org_id, member_id, created_by, created_at)
+	            VALUES (:org_id, :member_id, :created_by, :created_at)`
+	owner := dbOrgMember{OrgID: o.ID, MemberID: o.OwnerID, CreatedBy: o.OwnerID, CreatedAt: o.CreatedAt}
+	if _, err := tx.NamedExecContext(ctx, qMember, owner); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errSaveOrg, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errSaveOrg, err)
+	}
+
+	return nil
+}
+
+func (or orgRepository) Update(ctx context.Context, o auth.Org) error {
+	q := `UPDATE orgs SET name = :name, description = :description, metadata = :metadata, updated_at = :updated_at
+	      WHERE id = :id`
+
+	res, err := or.db.NamedExecContext(ctx, q, toDBOrg(o))
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && (pqErr.Code.Name() == errInvalid || pqErr.Code.Name() == errTruncation) {
+			return errors.Wrap(auth.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errUpdateOrg, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateOrg, err)
+	}
+	if cnt != 1 {
+		return auth.ErrNotFound
+	}
+
+	return nil
+}
+
+func (or orgRepository) Remove(ctx context.Context, id string) error {
+	// The memberships are removed along with the organization.
+	q := `DELETE FROM orgs WHERE id = :id`
+
+	if _, err := or.db.NamedExecContext(ctx, q, dbOrg{ID: id}); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errFK {
+			return errors.Wrap(auth.ErrOrgNotEmpty, err)
+		}
+		return errors.Wrap(errDeleteOrg, err)
+	}
+
+	return nil
+}
+
+func (or orgRepository) RetrieveByID(ctx context.Context, id string) (auth.Org, error) {
+	q := `SELECT id, owner_id, name, description, metadata, created_at, updated_at FROM orgs WHERE id = $1`
+
+	dbo := dbOrg{}
+	if err := or.db.QueryRowxContext(ctx, q, id).StructScan(&dbo); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return auth.Org{}, errors.Wrap(auth.ErrNotFound, err)
+		}
+		return auth.Org{}, errors.Wrap(errRetrieveOrg, err)
+	}
+
+	return toOrg(dbo), nil
+}
+
+func (or orgRepository) RetrieveByMember(ctx context.Context, memberID string, offset, limit uint64) (auth.OrgsPage, error) {
+	q := `SELECT o.id, o.owner_id, o.name, o.description, o.metadata, o.created_at, o.updated_at
+	      FROM orgs o, org_members om
+	      WHERE om.org_id = o.id AND om.member_id = :member_id
+	      ORDER BY o.created_at, o.id LIMIT :limit OFFSET :offset`
+	params := dbOrgMembersPage{MemberID: memberID, Offset: offset, Limit: limit}
+
+	rows, err := or.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return auth.OrgsPage{}, errors.Wrap(errRetrieveOrg, err)
+	}
+	defer rows.Close()
+
+	var orgs []auth.Org
+	for rows.Next() {
+		dbo := dbOrg{}
+		if err := rows.StructScan(&dbo); err != nil {
+			return auth.OrgsPage{}, errors.Wrap(errRetrieveOrg, err)
+		}
+		orgs = append(orgs, toOrg(dbo))
+	}
+
+	cq := `SELECT COUNT(*) FROM org_members WHERE member_id = :member_id`
+	total, err := total(ctx, or.db, cq, params)
+	if err != nil {
+		return auth.OrgsPage{}, errors.Wrap(errRetrieveOrg, err)
+	}
+
+	return auth.OrgsPage{
+		Total:  total,
+		Offset: offset,
+		Limit:  limit,
+		Orgs:   orgs,
+	}, nil
+}
+
+func (or orgRepository) AssignMembers(ctx context.Context, orgID, createdBy string, memberIDs ...string) error {
+	tx, err := or.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errAssignOrgMembers, err)
+	}
+
+	q := `INSERT INTO org_members (org_id, member_id, created_by, created_at)
+	      VALUES (:org_id, :member_id, :created_by, :created_at)`
+
+	created := time.Now()
+	for _, memberID := range memberIDs {
+		m := dbOrgMember{OrgID: orgID, MemberID: memberID, CreatedBy: createdBy, CreatedAt: created}
+		if _, err := tx.NamedExecContext(ctx, q, m); err != nil {
+			tx.Rollback()
+			pqErr, ok := err.(*pq.Error)
+			if ok {
+				switch pqErr.Code.Name() {
+				case errInvalid, errTruncation:
+					return errors.Wrap(auth.ErrMalformedEntity, err)
+				case errFK:
+					return errors.Wrap(auth.ErrNotFound, err)
+				case errDuplicate:
+					return errors.Wrap(auth.ErrMemberAlreadyAssigned, err)
+				}
+			}
+			return errors.Wrap(errAssignOrgMembers, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errAssignOrgMembers, err)
+	}
+
+	return nil
+}
+
+func (or orgRepository) UnassignMembers(ctx context.Context, orgID string, memberIDs ...string) error {
+	tx, err := or.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errUnassignMembers, err)
+	}
+
+	q := `DELETE FROM org_members WHERE org_id = :org_id AND member_id = :member_id`
+
+	for _, memberID := range memberIDs {
+		if _, err := tx.NamedExecContext(ctx, q, dbOrgMember{OrgID: orgID, MemberID: memberID}); err != nil {
+			tx.Rollback()
+			return errors.Wrap(errUnassignMembers, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errUnassignMembers, err)
+	}
+
+	return nil
+}
+
+func (or orgRepository) RetrieveMembers(ctx context.Context, orgID string, offset, limit uint64) (auth.OrgMembersPage, error) {
+	q := `SELECT org_id, member_id, created_by, created_at FROM org_members
+	      WHERE org_id = :org_id ORDER BY created_at, member_id LIMIT :limit OFFSET :offset`
+	params := dbOrgMembersPage{OrgID: orgID, Offset: offset, Limit: limit}
+
+	rows, err := or.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return auth.OrgMembersPage{}, errors.Wrap(errRetrieveMembers, err)
+	}
+	defer rows.Close()
+
+	var members []auth.OrgMember
+	for rows.Next() {
+		dbm := dbOrgMember{}
+		if err := rows.StructScan(&dbm); err != nil {
+			return auth.OrgMembersPage{}, errors.Wrap(errRetrieveMembers, err)
+		}
+		members = append(members, auth.OrgMember{ID: dbm.MemberID, CreatedBy: dbm.CreatedBy, CreatedAt: dbm.CreatedAt})
+	}
+
+	cq := `SELECT COUNT(*) FROM org_members WHERE org_id = :org_id`
+	total, err := total(ctx, or.db, cq, params)
+	if err != nil {
+		return auth.OrgMembersPage{}, errors.Wrap(errRetrieveMembers, err)
+	}
+
+	return auth.OrgMembersPage{
+		Total:   total,
+		Offset:  offset,
+		Limit:   limit,
+		Members: members,
+	}, nil
+}
+
+func (or orgRepository) IsMember(ctx context.Context, orgID, memberID string) error {
+	q := `SELECT member_id FROM org_members WHERE org_id = $1 AND member_id = $2`
+
+	var id string
+	if err := or.db.QueryRowxContext(ctx, q, orgID, memberID).Scan(&id); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return auth.ErrOrgMember
+		}
+		return errors.Wrap(errRetrieveMembers, err)
+	}
+
+	return nil
+}
+
+type dbOrg struct {
+	ID          string         `db:"id"`
+	OwnerID     string         `db:"owner_id"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	Metadata    dbMetadata     `db:"metadata"`
+	CreatedAt   time.Time      `db:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at"`
+}
+
+type dbOrgMember struct {
+	OrgID     string    `db:"org_id"`
+	MemberID  string    `db:"member_id"`
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type dbOrgMembersPage struct {
+	OrgID    string `db:"org_id"`
+	MemberID string `db:"member_id"`
+	Limit    uint64 `db:"limit"`
+	Offset   uint64 `db:"offset"`
+}
+
+func toDBOrg(o auth.Org) dbOrg {
+	return dbOrg{
+		ID:          o.ID,
+		OwnerID:     o.OwnerID,
+		Name:        o.Name,
+		Description: toNullString(o.Description),
+		Metadata:    dbMetadata(o.Metadata),
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+	}
+}
+
+func toOrg(dbo dbOrg) auth.Org {
+	return auth.Org{
+		ID:          dbo.ID,
+		OwnerID:     dbo.OwnerID,
+		Name:        dbo.Name,
+		Description: dbo.Description.String,
+		Metadata:    auth.OrgMetadata(dbo.Metadata),
+		CreatedAt:   dbo.CreatedAt,
+		UpdatedAt:   dbo.UpdatedAt,
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveQuota     = errors.New("failed to save quota in database")
+	errRetrieveQuota = errors.New("failed to retrieve quota from database")
+	errChargeQuota   = errors.New("failed to charge quota in database")
+	errReleaseQuota  = errors.New("failed to release quota in database")
+)
+
+var _ auth.QuotaRepository = (*quotaRepository)(nil)
+
+type quotaRepository struct {
+	db Database
+}
+
+// NewQuotaRepo instantiates a PostgreSQL implementation of quota
+// repository.
+func NewQuotaRepo(db Database) auth.QuotaRepository {
+	return &quotaRepository{
+		db: db,
+	}
+}
+
+func (qr quotaRepository) Save(ctx context.Context, q auth.Quota) error {
+	q.UpdatedAt = q.UpdatedAt.UTC()
+	query := `INSERT INTO quotas (org_id, max_users, max_things, max_channels, message_rate, updated_at)
+	          VALUES (:org_id, :max_users, :max_things, :max_channels, :message_rate, :updated_at)
+	          ON CONFLICT (org_id) DO UPDATE SET max_users = :max_users, max_things = :max_things,
+	          max_channels = :max_channels, message_rate = :message_rate, updated_at = :updated_at`
+
+	if _, err := qr.db.NamedExecContext(ctx, query, toDBQuota(q)); err != nil {
+		return errors.Wrap(errSaveQuota, err)
+	}
+
+	return nil
+}
+
+func (qr quotaRepository) RetrieveByOrg(ctx context.Context, orgID string) (auth.Quota, error) {
+	q := `SELECT org_id, max_users, max_things, max_channels, message_rate, updated_at FROM quotas WHERE org_id = $1`
+
+	dbq := dbQuota{}
+	if err := qr.db.QueryRowxContext(ctx, q, orgID).StructScan(&dbq); err != nil {
+		if err == sql.ErrNoRows {
+			return auth.Quota{}, errors.Wrap(auth.ErrNotFound, err)
+		}
+
+		return auth.Quota{}, errors.Wrap(errRetrieveQuota, err)
+	}
+
+	return toQuota(dbq), nil
+}
+
+func (qr quotaRepository) Usage(ctx context.Context, orgID string) (auth.Usage, error) {
+	q := `SELECT resource, COUNT(*) FROM quota_usage WHERE org_id = $1 GROUP BY resource`
+
+	rows, err := qr.db.QueryxContext(ctx, q, orgID)
+	if err != nil {
+		return auth.Usage{}, errors.Wrap(errRetrieveQuota, err)
+	}
+	defer rows.Close()
+
+	var u auth.Usage
+	for rows.Next() {
+		var resource string
+		var count uint64
+		if err := rows.Scan(&resource, &count); err != nil {
+			return auth.Usage{}, errors.Wrap(errRetrieveQuota, err)
+		}
+		switch resource {
+		case auth.ThingsResource:
+			u.Things = count
+		case auth.ChannelsResource:
+			u.Channels = count
+		}
+	}
+
+	return u, nil
+}
+
+func (qr quotaRepository) Charge(ctx context.Context, orgID, resource string, limit uint64, ids ...string) error {
+	tx, err := qr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errChargeQuota, err)
+	}
+
+	// Locking the quota serializes the charges of the organization, so the
+	// concurrent charges can't exceed the limit.
+	if _, err := tx.ExecContext(ctx, `SELECT org_id FROM quotas WHERE org_id = $1 FOR UPDATE`, orgID); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errChargeQuota, err)
+	}
+
+	if limit > 0 {
+		var count uint64
+		q := `SELECT COUNT(*) FROM quota_usage WHERE org_id = $1 AND resource = $2`
+		if err := tx.QueryRowxContext(ctx, q, orgID, resource).Scan(&count); err != nil {
+			tx.Rollback()
+			return errors.Wrap(errChargeQuota, err)
+		}
+		if count+uint64(len(ids)) > limit {
+			tx.Rollback()
+			return errors.Wrap(auth.ErrQuotaExceeded, fmt.Errorf("organization %s allows at most %d %s, %d used", orgID, limit, resource, count))
+		}
+	}
+
+	q := `INSERT INTO quota_usage (org_id, resource, resource_id) VALUES ($1, $2, $3)
+	      ON CONFLICT DO NOTHING`
+	for _, id := range ids {
+		if _, err := tx.ExecContext(ctx, q, orgID, resource, id); err != nil {
+			tx.Rollback()
+			return errors.Wrap(errChargeQuota, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errChargeQuota, err)
+	}
+
+	return nil
+}
+
+func (qr quotaRepository) Release(ctx context.Context, resource string, ids ...string) error {
+	q := `DELETE FROM quota_usage WHERE resource = :resource AND resource_id = ANY(:ids)`
+
+	if _, err := qr.db.NamedExecContext(ctx, q, dbRelease{Resource: resource, IDs: ids}); err != nil {
+		return errors.Wrap(errReleaseQuota, err)
+	}
+
+	return nil
+}
+
+func (qr quotaRepository) RetrieveOrgs(ctx context.Context, resource, id string) ([]string, error) {
+	q := `SELECT org_id FROM quota_usage WHERE resource = $1 AND resource_id = $2`
+
+	rows, err := qr.db.QueryxContext(ctx, q, resource, id)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveQuota, err)
+	}
+	defer rows.Close()
+
+	var orgs []string
+	for rows.Next() {
+		var orgID string
+		if err := rows.Scan(&orgID); err != nil {
+			return nil, errors.Wrap(errRetrieveQuota, err)
+		}
+		orgs = append(orgs, orgID)
+	}
+
+	return orgs, nil
+}
+
+type dbRelease struct {
+	Resource string         `db:"resource"`
+	IDs      pq.StringArray `db:"ids"`
+}
+
+type dbQuota struct {
+	OrgID       string    `db:"org_id"`
+	MaxUsers    int64     `db:"max_users"`
+	MaxThings   int64     `db:"max_things"`
+	MaxChannels int64     `db:"max_channels"`
+	MessageRate float64   `db:"message_rate"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+func toDBQuota(q auth.Quota) dbQuota {
+	return dbQuota{
+		OrgID:       q.OrgID,
+		MaxUsers:    int64(q.MaxUsers),
+		MaxThings:   int64(q.MaxThings),
+		MaxChannels: int64(q.MaxChannels),
+		MessageRate: q.MessageRate,
+		UpdatedAt:   q.UpdatedAt,
+	}
+}
+
+func toQuota(q dbQuota) auth.Quota {
+	return auth.Quota{
+		OrgID:       q.OrgID,
+		MaxUsers:    uint64(q.MaxUsers),
+		MaxThings:   uint64(q.MaxThings),
+		MaxChannels: uint64(q.MaxChannels),
+		MessageRate: q.MessageRate,
+		UpdatedAt:   q.UpdatedAt,
+	}
+}
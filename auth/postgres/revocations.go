@@ -0,0 +1,118 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveRevocation     = errors.New("failed to save key revocation in database")
+	errRetrieveRevocation = errors.New("failed to retrieve key revocation from database")
+)
+
+var _ auth.RevocationRepository = (*revocationRepository)(nil)
+
+type revocationRepository struct {
+	db Database
+}
+
+// NewRevocationRepo instantiates a PostgreSQL implementation of key
+// revocation repository.
+func NewRevocationRepo(db Database) auth.RevocationRepository {
+	return &revocationRepository{
+		db: db,
+	}
+}
+
+func (rr revocationRepository) Save(ctx context.Context, r auth.Revocation) error {
+	tx, err := rr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errSaveRevocation, err)
+	}
+
+	// The expired keys are rejected anyway, so their revocations are
+	// pruned to keep the revocation list small.
+	qDel := `DELETE FROM revocations WHERE expires_at IS NOT NULL AND expires_at < $1`
+	if _, err := tx.ExecContext(ctx, qDel, r.RevokedAt); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errSaveRevocation, err)
+	}
+
+	q := `INSERT INTO revocations (key_id, issuer_id, revoked_at, expires_at)
+		VALUES (:key_id, :issuer_id, :revoked_at, :expires_at)
+		ON CONFLICT (key_id) DO NOTHING`
+	if _, err := tx.NamedExecContext(ctx, q, toDBRevocation(r)); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errSaveRevocation, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errSaveRevocation, err)
+	}
+
+	return nil
+}
+
+func (rr revocationRepository) Contains(ctx context.Context, keyID string) (bool, error) {
+	q := `SELECT EXISTS (SELECT 1 FROM revocations WHERE key_id = $1)`
+
+	var ok bool
+	if err := rr.db.QueryRowxContext(ctx, q, keyID).Scan(&ok); err != nil {
+		return false, errors.Wrap(errRetrieveRevocation, err)
+	}
+
+	return ok, nil
+}
+
+func (rr revocationRepository) SaveIssuer(ctx context.Context, issuerID string, revokedAt time.Time) error {
+	q := `INSERT INTO issuer_revocations (issuer_id, revoked_at) VALUES (:issuer_id, :revoked_at)
+		ON CONFLICT (issuer_id) DO UPDATE
+		SET revoked_at = GREATEST(issuer_revocations.revoked_at, EXCLUDED.revoked_at)`
+	dbr := dbRevocation{IssuerID: issuerID, RevokedAt: revokedAt}
+	if _, err := rr.db.NamedExecContext(ctx, q, dbr); err != nil {
+		return errors.Wrap(errSaveRevocation, err)
+	}
+
+	return nil
+}
+
+func (rr revocationRepository) RetrieveIssuer(ctx context.Context, issuerID string) (time.Time, error) {
+	q := `SELECT revoked_at FROM issuer_revocations WHERE issuer_id = $1`
+
+	var revokedAt time.Time
+	if err := rr.db.QueryRowxContext(ctx, q, issuerID).Scan(&revokedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return time.Time{}, nil
+		}
+		return time.Time{}, errors.Wrap(errRetrieveRevocation, err)
+	}
+
+	return revokedAt, nil
+}
+
+type dbRevocation struct {
+	KeyID     string       `db:"key_id"`
+	IssuerID  string       `db:"issuer_id"`
+	RevokedAt time.Time    `db:"revoked_at"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+}
+
+func toDBRevocation(r auth.Revocation) dbRevocation {
+	dbr := dbRevocation{
+		KeyID:     r.KeyID,
+		IssuerID:  r.IssuerID,
+		RevokedAt: r.RevokedAt,
+	}
+	if !r.ExpiresAt.IsZero() {
+		dbr.ExpiresAt = sql.NullTime{Time: r.ExpiresAt, Valid: true}
+	}
+
+	return dbr
+}
@@ -0,0 +1,227 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveRole               = errors.New("failed to save role in database")
+	errRetrieveRole           = errors.New("failed to retrieve role from database")
+	errDeleteRole             = errors.New("failed to delete role from database")
+	errSaveRoleAssignment     = errors.New("failed to save role assignment in database")
+	errRetrieveRoleAssignment = errors.New("failed to retrieve role assignment from database")
+	errDeleteRoleAssignment   = errors.New("failed to delete role assignment from database")
+)
+
+var _ auth.RoleRepository = (*roleRepository)(nil)
+
+type roleRepository struct {
+	db Database
+}
+
+// NewRoleRepo instantiates a PostgreSQL implementation of role
+// repository.
+func NewRoleRepo(db Database) auth.RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func (rr roleRepository) Save(ctx context.Context, r auth.Role) error {
+	q := `INSERT INTO roles (name, relations, created_at) VALUES (:name, :relations, :created_at)`
+
+	if _, err := rr.db.NamedExecContext(ctx, q, toDBRole(r)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(auth.ErrConflict, pqErr)
+		}
+
+		return errors.Wrap(errSaveRole, err)
+	}
+
+	return nil
+}
+
+func (rr roleRepository) RetrieveByName(ctx context.Context, name string) (auth.Role, error) {
+	q := `SELECT name, relations, created_at FROM roles WHERE name = $1`
+
+	dbr := dbRole{}
+	if err := rr.db.QueryRowxContext(ctx, q, name).StructScan(&dbr); err != nil {
+		if err == sql.ErrNoRows {
+			return auth.Role{}, errors.Wrap(auth.ErrNotFound, err)
+		}
+
+		return auth.Role{}, errors.Wrap(errRetrieveRole, err)
+	}
+
+	return toRole(dbr), nil
+}
+
+func (rr roleRepository) RetrieveAll(ctx context.Context) ([]auth.Role, error) {
+	q := `SELECT name, relations, created_at FROM roles ORDER BY name`
+
+	rows, err := rr.db.QueryxContext(ctx, q)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveRole, err)
+	}
+	defer rows.Close()
+
+	var roles []auth.Role
+	for rows.Next() {
+		dbr := dbRole{}
+		if err := rows.StructScan(&dbr); err != nil {
+			return nil, errors.Wrap(errRetrieveRole, err)
+		}
+		roles = append(roles, toRole(dbr))
+	}
+
+	return roles, nil
+}
+
+func (rr roleRepository) Remove(ctx context.Context, name string) error {
+	q := `DELETE FROM roles WHERE name = :name`
+
+	if _, err := rr.db.NamedExecContext(ctx, q, dbRole{Name: name}); err != nil {
+		return errors.Wrap(errDeleteRole, err)
+	}
+
+	return nil
+}
+
+func (rr roleRepository) SaveAssignment(ctx context.Context, ra auth.RoleAssignment) error {
+	q := `INSERT INTO role_assignments (role, subject, object, relations, assigned_by, created_at)
+	      VALUES (:role, :subject, :object, :relations, :assigned_by, :created_at)`
+
+	if _, err := rr.db.NamedExecContext(ctx, q, toDBRoleAssignment(ra)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(auth.ErrConflict, pqErr)
+		}
+
+		return errors.Wrap(errSaveRoleAssignment, err)
+	}
+
+	return nil
+}
+
+func (rr roleRepository) RetrieveAssignments(ctx context.Context, ra auth.RoleAssignment) ([]auth.RoleAssignment, error) {
+	var query []string
+	var args []interface{}
+	filters := []struct {
+		col string
+		val string
+	}{
+		{"role", ra.Role},
+		{"subject", ra.Subject},
+		{"object", ra.Object},
+	}
+	for _, f := range filters {
+		if f.val != "" {
+			args = append(args, f.val)
+			query = append(query, fmt.Sprintf("%s = $%d", f.col, len(args)))
+		}
+	}
+
+	var whereClause string
+	if len(query) > 0 {
+		whereClause = fmt.Sprintf("WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT role, subject, object, relations, assigned_by, created_at FROM role_assignments
+	      %s ORDER BY created_at`, whereClause)
+
+	rows, err := rr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveRoleAssignment, err)
+	}
+	defer rows.Close()
+
+	var assignments []auth.RoleAssignment
+	for rows.Next() {
+		dbra := dbRoleAssignment{}
+		if err := rows.StructScan(&dbra); err != nil {
+			return nil, errors.Wrap(errRetrieveRoleAssignment, err)
+		}
+		assignments = append(assignments, toRoleAssignment(dbra))
+	}
+
+	return assignments, nil
+}
+
+func (rr roleRepository) RemoveAssignment(ctx context.Context, ra auth.RoleAssignment) error {
+	q := `DELETE FROM role_assignments WHERE role = :role AND subject = :subject AND object = :object`
+
+	if _, err := rr.db.NamedExecContext(ctx, q, toDBRoleAssignment(ra)); err != nil {
+		return errors.Wrap(errDeleteRoleAssignment, err)
+	}
+
+	return nil
+}
+
+type dbRole struct {
+	Name      string         `db:"name"`
+	Relations pq.StringArray `db:"relations"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func toDBRole(r auth.Role) dbRole {
+	return dbRole{
+		Name:      r.Name,
+		Relations: r.Relations,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+func toRole(r dbRole) auth.Role {
+	return auth.Role{
+		Name:      r.Name,
+		Relations: r.Relations,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type dbRoleAssignment struct {
+	Role       string         `db:"role"`
+	Subject    string         `db:"subject"`
+	Object     string         `db:"object"`
+	Relations  pq.StringArray `db:"relations"`
+	AssignedBy string         `db:"assigned_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func toDBRoleAssignment(ra auth.RoleAssignment) dbRoleAssignment {
+	// The assignment may add no relations the subject didn't already have.
+	relations := pq.StringArray{}
+	relations = append(relations, ra.Relations...)
+
+	return dbRoleAssignment{
+		Role:       ra.Role,
+		Subject:    ra.Subject,
+		Object:     ra.Object,
+		Relations:  relations,
+		AssignedBy: ra.AssignedBy,
+		CreatedAt:  ra.CreatedAt,
+	}
+}
+
+func toRoleAssignment(ra dbRoleAssignment) auth.RoleAssignment {
+	return auth.RoleAssignment{
+		Role:       ra.Role,
+		Subject:    ra.Subject,
+		Object:     ra.Object,
+		Relations:  ra.Relations,
+		AssignedBy: ra.AssignedBy,
+		CreatedAt:  ra.CreatedAt,
+	}
+}
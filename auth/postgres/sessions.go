@@ -0,0 +1,173 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// seenInterval is the minimal interval between the updates of the session
+// last seen time, sparing the writes on every identification.
+const seenInterval = time.Minute
+
+var (
+	errSaveSession     = errors.New("failed to save session in database")
+	errRetrieveSession = errors.New("failed to retrieve session from database")
+	errRemoveSession   = errors.New("failed to remove session from database")
+)
+
+var _ auth.SessionRepository = (*sessionRepository)(nil)
+
+type sessionRepository struct {
+	db Database
+}
+
+// NewSessionRepo instantiates a PostgreSQL implementation of session
+// repository.
+func NewSessionRepo(db Database) auth.SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (sr sessionRepository) Save(ctx context.Context, s auth.Session) error {
+	tx, err := sr.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(errSaveSession, err)
+	}
+
+	qDel := `DELETE FROM sessions WHERE user_id = $1 AND expires_at < $2`
+	if _, err := tx.ExecContext(ctx, qDel, s.UserID, s.IssuedAt); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errSaveSession, err)
+	}
+
+	// The device and the issue time are those of the login, while the
+	// refreshes extend the session.
+	q := `INSERT INTO sessions (id, user_id, device, ip, issued_at, last_seen_at, expires_at)
+		VALUES (:id, :user_id, :device, :ip, :issued_at, :last_seen_at, :expires_at)
+		ON CONFLICT (id) DO UPDATE SET
+			ip = COALESCE(NULLIF(EXCLUDED.ip, ''), sessions.ip),
+			last_seen_at = GREATEST(sessions.last_seen_at, EXCLUDED.last_seen_at),
+			expires_at = GREATEST(sessions.expires_at, EXCLUDED.expires_at)
+		WHERE sessions.user_id = EXCLUDED.user_id`
+	if _, err := tx.NamedExecContext(ctx, q, toDBSession(s)); err != nil {
+		tx.Rollback()
+		return errors.Wrap(errSaveSession, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(errSaveSession, err)
+	}
+
+	return nil
+}
+
+func (sr sessionRepository) Retrieve(ctx context.Context, userID, id string) (auth.Session, error) {
+	q := `SELECT id, user_id, device, ip, issued_at, last_seen_at, expires_at FROM sessions
+		WHERE user_id = $1 AND id = $2 AND expires_at > $3`
+
+	var dbs dbSession
+	if err := sr.db.QueryRowxContext(ctx, q, userID, id, time.Now().UTC()).StructScan(&dbs); err != nil {
+		if err == sql.ErrNoRows {
+			return auth.Session{}, errors.Wrap(auth.ErrNotFound, err)
+		}
+		return auth.Session{}, errors.Wrap(errRetrieveSession, err)
+	}
+
+	return toSession(dbs), nil
+}
+
+func (sr sessionRepository) RetrieveByUser(ctx context.Context, userID string) ([]auth.Session, error) {
+	q := `SELECT id, user_id, device, ip, issued_at, last_seen_at, expires_at FROM sessions
+		WHERE user_id = $1 AND expires_at > $2 ORDER BY last_seen_at DESC`
+
+	rows, err := sr.db.QueryxContext(ctx, q, userID, time.Now().UTC())
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveSession, err)
+	}
+	defer rows.Close()
+
+	sessions := []auth.Session{}
+	for rows.Next() {
+		var dbs dbSession
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, errors.Wrap(errRetrieveSession, err)
+		}
+		sessions = append(sessions, toSession(dbs))
+	}
+
+	return sessions, nil
+}
+
+func (sr sessionRepository) Touch(ctx context.Context, id string, seenAt time.Time) error {
+	q := `UPDATE sessions SET last_seen_at = :seen_at WHERE id = :id AND last_seen_at < :seen_before`
+	params := map[string]interface{}{
+		"id":          id,
+		"seen_at":     seenAt,
+		"seen_before": seenAt.Add(-seenInterval),
+	}
+	if _, err := sr.db.NamedExecContext(ctx, q, params); err != nil {
+		return errors.Wrap(errSaveSession, err)
+	}
+
+	return nil
+}
+
+func (sr sessionRepository) Remove(ctx context.Context, userID, id string) error {
+	q := `DELETE FROM sessions WHERE user_id = :user_id AND id = :id`
+	if _, err := sr.db.NamedExecContext(ctx, q, dbSession{ID: id, UserID: userID}); err != nil {
+		return errors.Wrap(errRemoveSession, err)
+	}
+
+	return nil
+}
+
+func (sr sessionRepository) RemoveByUser(ctx context.Context, userID string) error {
+	q := `DELETE FROM sessions WHERE user_id = :user_id`
+	if _, err := sr.db.NamedExecContext(ctx, q, dbSession{UserID: userID}); err != nil {
+		return errors.Wrap(errRemoveSession, err)
+	}
+
+	return nil
+}
+
+type dbSession struct {
+	ID         string    `db:"id"`
+	UserID     string    `db:"user_id"`
+	Device     string    `db:"device"`
+	IP         string    `db:"ip"`
+	IssuedAt   time.Time `db:"issued_at"`
+	LastSeenAt time.Time `db:"last_seen_at"`
+	ExpiresAt  time.Time `db:"expires_at"`
+}
+
+func toDBSession(s auth.Session) dbSession {
+	return dbSession{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		Device:     s.Device,
+		IP:         s.IP,
+		IssuedAt:   s.IssuedAt,
+		LastSeenAt: s.LastSeenAt,
+		ExpiresAt:  s.ExpiresAt,
+	}
+}
+
+func toSession(dbs dbSession) auth.Session {
+	return auth.Session{
+		ID:         dbs.ID,
+		UserID:     dbs.UserID,
+		Device:     dbs.Device,
+		IP:         dbs.IP,
+		IssuedAt:   dbs.IssuedAt,
+		LastSeenAt: dbs.LastSeenAt,
+		ExpiresAt:  dbs.ExpiresAt,
+	}
+}
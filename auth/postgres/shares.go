@@ -0,0 +1,147 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+var (
+	errSaveShare     = errors.New("failed to save share in database")
+	errRetrieveShare = errors.New("failed to retrieve share from database")
+	errDeleteShare   = errors.New("failed to delete share from database")
+)
+
+var _ auth.ShareRepository = (*shareRepository)(nil)
+
+type shareRepository struct {
+	db Database
+}
+
+// NewShareRepo instantiates a PostgreSQL implementation of share
+// repository.
+func NewShareRepo(db Database) auth.ShareRepository {
+	return &shareRepository{
+		db: db,
+	}
+}
+
+func (sr shareRepository) Save(ctx context.Context, s auth.Share) error {
+	q := `INSERT INTO shares (id, grantor_id, grantee_id, object, actions, created_at)
+	      VALUES (:id, :grantor_id, :grantee_id, :object, :actions, :created_at)`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, toDBShare(s)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(auth.ErrConflict, pqErr)
+		}
+
+		return errors.Wrap(errSaveShare, err)
+	}
+
+	return nil
+}
+
+func (sr shareRepository) RetrieveByID(ctx context.Context, id string) (auth.Share, error) {
+	q := `SELECT id, grantor_id, grantee_id, object, actions, created_at FROM shares WHERE id = $1`
+
+	dbs := dbShare{}
+	if err := sr.db.QueryRowxContext(ctx, q, id).StructScan(&dbs); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return auth.Share{}, errors.Wrap(auth.ErrNotFound, err)
+		}
+
+		return auth.Share{}, errors.Wrap(errRetrieveShare, err)
+	}
+
+	return toShare(dbs), nil
+}
+
+func (sr shareRepository) RetrieveByGrantor(ctx context.Context, grantorID string) ([]auth.Share, error) {
+	q := `SELECT id, grantor_id, grantee_id, object, actions, created_at FROM shares
+	      WHERE grantor_id = $1 ORDER BY created_at`
+
+	return sr.retrieve(ctx, q, grantorID)
+}
+
+func (sr shareRepository) RetrieveByGrantee(ctx context.Context, granteeID string) ([]auth.Share, error) {
+	q := `SELECT id, grantor_id, grantee_id, object, actions, created_at FROM shares
+	      WHERE grantee_id = $1 ORDER BY created_at`
+
+	return sr.retrieve(ctx, q, granteeID)
+}
+
+func (sr shareRepository) RetrieveByObject(ctx context.Context, granteeID, object string) ([]auth.Share, error) {
+	q := `SELECT id, grantor_id, grantee_id, object, actions, created_at FROM shares
+	      WHERE grantee_id = $1 AND object = $2 ORDER BY created_at`
+
+	return sr.retrieve(ctx, q, granteeID, object)
+}
+
+func (sr shareRepository) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM shares WHERE id = :id`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbShare{ID: id}); err != nil {
+		return errors.Wrap(errDeleteShare, err)
+	}
+
+	return nil
+}
+
+func (sr shareRepository) retrieve(ctx context.Context, q string, args ...interface{}) ([]auth.Share, error) {
+	rows, err := sr.db.QueryxContext(ctx, q, args...)
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveShare, err)
+	}
+	defer rows.Close()
+
+	var shares []auth.Share
+	for rows.Next() {
+		dbs := dbShare{}
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, errors.Wrap(errRetrieveShare, err)
+		}
+		shares = append(shares, toShare(dbs))
+	}
+
+	return shares, nil
+}
+
+type dbShare struct {
+	ID        string         `db:"id"`
+	GrantorID string         `db:"grantor_id"`
+	GranteeID string         `db:"grantee_id"`
+	Object    string         `db:"object"`
+	Actions   pq.StringArray `db:"actions"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func toDBShare(s auth.Share) dbShare {
+	return dbShare{
+		ID:        s.ID,
+		GrantorID: s.GrantorID,
+		GranteeID: s.GranteeID,
+		Object:    s.Object,
+		Actions:   s.Actions,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func toShare(s dbShare) auth.Share {
+	return auth.Share{
+		ID:        s.ID,
+		GrantorID: s.GrantorID,
+		GranteeID: s.GranteeID,
+		Object:    s.Object,
+		Actions:   s.Actions,
+		CreatedAt: s.CreatedAt,
+	}
+}
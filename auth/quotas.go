@@ -0,0 +1,136 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// Resources limited by the quotas.
+const (
+	UsersResource    = "users"
+	ThingsResource   = "things"
+	ChannelsResource = "channels"
+	MessagesResource = "messages"
+)
+
+// ErrQuotaExceeded indicates that the request would exceed the quota of the
+// organization.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota represents the limits of the resources used by the organization.
+// Zero value of the limit means no limit.
+type Quota struct {
+	OrgID       string
+	MaxUsers    uint64
+	MaxThings   uint64
+	MaxChannels uint64
+	// MessageRate is the number of messages per second the things of the
+	// organization are allowed to publish.
+	MessageRate float64
+	UpdatedAt   time.Time
+}
+
+// Usage represents the number of resources used by the organization.
+type Usage struct {
+	Users    uint64
+	Things   uint64
+	Channels uint64
+}
+
+// Limit returns the limit of the resource, and false if the resource is not
+// limited.
+func (q Quota) Limit(resource string) (uint64, bool) {
+	var limit uint64
+	switch resource {
+	case UsersResource:
+		limit = q.MaxUsers
+	case ThingsResource:
+		limit = q.MaxThings
+	case ChannelsResource:
+		limit = q.MaxChannels
+	}
+	return limit, limit > 0
+}
+
+// Quotas specifies an API for the management and the enforcement of the
+// organization quotas.
+type Quotas interface {
+	// SetQuota sets the quota of the organization. Only the admin is
+	// allowed to set the quotas.
+	SetQuota(ctx context.Context, token string, q Quota) error
+
+	// ViewQuota retrieves the quota and the usage of the organization the
+	// user identified by the token is the member of.
+	ViewQuota(ctx context.Context, token, orgID string) (Quota, Usage, error)
+
+	// ReserveQuota charges the resources created by the user identified by
+	// the token to the organization the token is scoped to. For the
+	// messages resource, the IDs are the IDs of the publishing things and
+	// the token is not used.
+	ReserveQuota(ctx context.Context, token, resource string, ids ...string) error
+
+	// ReleaseQuota releases the removed resources from the organizations
+	// they are charged to.
+	ReleaseQuota(ctx context.Context, resource string, ids ...string) error
+}
+
+// QuotaRepository specifies Quota persistence API.
+type QuotaRepository interface {
+	// Save saves the quota, replacing the existing quota of the
+	// organization.
+	Save(ctx context.Context, q Quota) error
+
+	// RetrieveByOrg retrieves the quota of the organization.
+	RetrieveByOrg(ctx context.Context, orgID string) (Quota, error)
+
+	// Usage retrieves the number of resources charged to the organization.
+	Usage(ctx context.Context, orgID string) (Usage, error)
+
+	// Charge charges the resources to the organization, unless that
+	// exceeds the given limit. Zero limit means no limit.
+	Charge(ctx context.Context, orgID, resource string, limit uint64, ids ...string) error
+
+	// Release removes the resources from all the organizations.
+	Release(ctx context.Context, resource string, ids ...string) error
+
+	// RetrieveOrgs retrieves the IDs of the organizations the resource is
+	// charged to.
+	RetrieveOrgs(ctx context.Context, resource, id string) ([]string, error)
+}
+
+// limiters holds the message rate limiters of the organizations.
+type limiters struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+func newLimiters() *limiters {
+	return &limiters{
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+// allow reports whether the organization is allowed to publish the message,
+// replacing the limiter of the organization if its rate changed.
+func (l *limiters) allow(orgID string, r float64) bool {
+	l.mu.Lock()
+	lim, ok := l.limiters[orgID]
+	if !ok || lim.Limit() != rate.Limit(r) {
+		burst := int(r)
+		if burst < 1 {
+			burst = 1
+		}
+		lim = rate.NewLimiter(rate.Limit(r), burst)
+		l.limiters[orgID] = lim
+	}
+	l.mu.Unlock()
+
+	return lim.Allow()
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains the event store middleware publishing the auth
+// events to the Redis stream.
+package redis
@@ -0,0 +1,187 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+const (
+	groupPrefix   = "group."
+	groupCreate   = groupPrefix + "create"
+	groupUpdate   = groupPrefix + "update"
+	groupRemove   = groupPrefix + "remove"
+	groupAssign   = groupPrefix + "assign"
+	groupUnassign = groupPrefix + "unassign"
+	groupRelation = groupPrefix + "relation"
+
+	policyPrefix = "policy."
+	policyAdd    = policyPrefix + "add"
+	policyDelete = policyPrefix + "delete"
+)
+
+type event interface {
+	Encode() map[string]interface{}
+}
+
+var (
+	_ event = (*createGroupEvent)(nil)
+	_ event = (*updateGroupEvent)(nil)
+	_ event = (*removeGroupEvent)(nil)
+	_ event = (*assignGroupEvent)(nil)
+	_ event = (*unassignGroupEvent)(nil)
+	_ event = (*setRelationEvent)(nil)
+	_ event = (*policyEvent)(nil)
+)
+
+type createGroupEvent struct {
+	id       string
+	ownerID  string
+	orgID    string
+	parentID string
+	name     string
+	metadata map[string]interface{}
+}
+
+func (cge createGroupEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        cge.id,
+		"owner_id":  cge.ownerID,
+		"name":      cge.name,
+		"operation": groupCreate,
+	}
+
+	if cge.orgID != "" {
+		val["org_id"] = cge.orgID
+	}
+
+	if cge.parentID != "" {
+		val["parent_id"] = cge.parentID
+	}
+
+	if cge.metadata != nil {
+		metadata, err := json.Marshal(cge.metadata)
+		if err != nil {
+			return val
+		}
+
+		val["metadata"] = string(metadata)
+	}
+
+	return val
+}
+
+type updateGroupEvent struct {
+	id       string
+	name     string
+	metadata map[string]interface{}
+}
+
+func (uge updateGroupEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"id":        uge.id,
+		"operation": groupUpdate,
+	}
+
+	if uge.name != "" {
+		val["name"] = uge.name
+	}
+
+	if uge.metadata != nil {
+		metadata, err := json.Marshal(uge.metadata)
+		if err != nil {
+			return val
+		}
+
+		val["metadata"] = string(metadata)
+	}
+
+	return val
+}
+
+type removeGroupEvent struct {
+	id string
+}
+
+func (rge removeGroupEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"id":        rge.id,
+		"operation": groupRemove,
+	}
+}
+
+type assignGroupEvent struct {
+	groupID   string
+	groupType string
+	memberIDs []string
+	expiresAt time.Time
+}
+
+func (age assignGroupEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"group_id":  age.groupID,
+		"type":      age.groupType,
+		"members":   strings.Join(age.memberIDs, ","),
+		"operation": groupAssign,
+	}
+
+	if !age.expiresAt.IsZero() {
+		val["expires_at"] = age.expiresAt.UTC().Format(time.RFC3339)
+	}
+
+	return val
+}
+
+type unassignGroupEvent struct {
+	groupID   string
+	memberIDs []string
+}
+
+func (uge unassignGroupEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"group_id":  uge.groupID,
+		"members":   strings.Join(uge.memberIDs, ","),
+		"operation": groupUnassign,
+	}
+}
+
+type setRelationEvent struct {
+	groupID   string
+	relation  string
+	memberIDs []string
+}
+
+func (sre setRelationEvent) Encode() map[string]interface{} {
+	return map[string]interface{}{
+		"group_id":  sre.groupID,
+		"relation":  sre.relation,
+		"members":   strings.Join(sre.memberIDs, ","),
+		"operation": groupRelation,
+	}
+}
+
+type policyEvent struct {
+	operation string
+	subject   string
+	object    string
+	relation  string
+	namespace string
+}
+
+func (pe policyEvent) Encode() map[string]interface{} {
+	val := map[string]interface{}{
+		"subject":   pe.subject,
+		"object":    pe.object,
+		"relation":  pe.relation,
+		"operation": pe.operation,
+	}
+
+	if pe.namespace != "" {
+		val["namespace"] = pe.namespace
+	}
+
+	return val
+}
@@ -0,0 +1,251 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/auth"
+)
+
+const (
+	streamID  = "mainflux.auth"
+	streamLen = 1000
+)
+
+var _ auth.Service = (*eventStore)(nil)
+
+// eventStore publishes the group and the policy changes, passing the other
+// calls through to the embedded service.
+type eventStore struct {
+	auth.Service
+	client *redis.Client
+}
+
+// NewEventStoreMiddleware returns wrapper around auth service that sends
+// events to event store.
+func NewEventStoreMiddleware(svc auth.Service, client *redis.Client) auth.Service {
+	return eventStore{
+		Service: svc,
+		client:  client,
+	}
+}
+
+func (es eventStore) CreateGroup(ctx context.Context, token string, g auth.Group) (auth.Group, error) {
+	group, err := es.Service.CreateGroup(ctx, token, g)
+	if err != nil {
+		return group, err
+	}
+
+	event := createGroupEvent{
+		id:       group.ID,
+		ownerID:  group.OwnerID,
+		orgID:    group.OrgID,
+		parentID: group.ParentID,
+		name:     group.Name,
+		metadata: group.Metadata,
+	}
+	es.publish(ctx, event)
+
+	return group, nil
+}
+
+func (es eventStore) UpdateGroup(ctx context.Context, token string, g auth.Group) (auth.Group, error) {
+	group, err := es.Service.UpdateGroup(ctx, token, g)
+	if err != nil {
+		return group, err
+	}
+
+	event := updateGroupEvent{
+		id:       group.ID,
+		name:     group.Name,
+		metadata: group.Metadata,
+	}
+	es.publish(ctx, event)
+
+	return group, nil
+}
+
+func (es eventStore) RemoveGroup(ctx context.Context, token, id string) error {
+	if err := es.Service.RemoveGroup(ctx, token, id); err != nil {
+		return err
+	}
+
+	es.publish(ctx, removeGroupEvent{id: id})
+
+	return nil
+}
+
+func (es eventStore) Assign(ctx context.Context, token, groupID, groupType string, memberIDs ...string) error {
+	if err := es.Service.Assign(ctx, token, groupID, groupType, memberIDs...); err != nil {
+		return err
+	}
+
+	event := assignGroupEvent{
+		groupID:   groupID,
+		groupType: groupType,
+		memberIDs: memberIDs,
+	}
+	es.publish(ctx, event)
+
+	return nil
+}
+
+func (es eventStore) AssignTemporary(ctx context.Context, token, groupID, groupType string, expiresAt time.Time, memberIDs ...string) error {
+	if err := es.Service.AssignTemporary(ctx, token, groupID, groupType, expiresAt, memberIDs...); err != nil {
+		return err
+	}
+
+	event := assignGroupEvent{
+		groupID:   groupID,
+		groupType: groupType,
+		memberIDs: memberIDs,
+		expiresAt: expiresAt,
+	}
+	es.publish(ctx, event)
+
+	return nil
+}
+
+func (es eventStore) Unassign(ctx context.Context, token, groupID string, memberIDs ...string) error {
+	if err := es.Service.Unassign(ctx, token, groupID, memberIDs...); err != nil {
+		return err
+	}
+
+	event := unassignGroupEvent{
+		groupID:   groupID,
+		memberIDs: memberIDs,
+	}
+	es.publish(ctx, event)
+
+	return nil
+}
+
+func (es eventStore) SetRelation(ctx context.Context, token, groupID, relation string, memberIDs ...string) error {
+	if err := es.Service.SetRelation(ctx, token, groupID, relation, memberIDs...); err != nil {
+		return err
+	}
+
+	event := setRelationEvent{
+		groupID:   groupID,
+		relation:  relation,
+		memberIDs: memberIDs,
+	}
+	es.publish(ctx, event)
+
+	return nil
+}
+
+func (es eventStore) DelegateGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) error {
+	if err := es.Service.DelegateGroupAdmin(ctx, token, groupID, userID, relations...); err != nil {
+		return err
+	}
+
+	for _, rel := range relations {
+		es.publishPolicy(ctx, policyAdd, auth.PolicyReq{Subject: userID, Object: groupID, Relation: rel})
+	}
+
+	return nil
+}
+
+func (es eventStore) RevokeGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) error {
+	if err := es.Service.RevokeGroupAdmin(ctx, token, groupID, userID, relations...); err != nil {
+		return err
+	}
+
+	for _, rel := range relations {
+		es.publishPolicy(ctx, policyDelete, auth.PolicyReq{Subject: userID, Object: groupID, Relation: rel})
+	}
+
+	return nil
+}
+
+func (es eventStore) AddPolicy(ctx context.Context, pr auth.PolicyReq) error {
+	if err := es.Service.AddPolicy(ctx, pr); err != nil {
+		return err
+	}
+
+	es.publishPolicy(ctx, policyAdd, pr)
+
+	return nil
+}
+
+func (es eventStore) AddPolicies(ctx context.Context, token, object string, subjectIDs, relations []string) error {
+	if err := es.Service.AddPolicies(ctx, token, object, subjectIDs, relations); err != nil {
+		return err
+	}
+
+	for _, subjectID := range subjectIDs {
+		for _, rel := range relations {
+			es.publishPolicy(ctx, policyAdd, auth.PolicyReq{Subject: subjectID, Object: object, Relation: rel})
+		}
+	}
+
+	return nil
+}
+
+func (es eventStore) AddPolicyTriples(ctx context.Context, token string, prs []auth.PolicyReq) ([]auth.PolicyResult, error) {
+	res, err := es.Service.AddPolicyTriples(ctx, token, prs)
+	if err != nil {
+		return res, err
+	}
+
+	// The policies are added one by one, so only the added ones are
+	// published.
+	for _, r := range res {
+		if r.Err == nil {
+			es.publishPolicy(ctx, policyAdd, r.PolicyReq)
+		}
+	}
+
+	return res, nil
+}
+
+func (es eventStore) DeletePolicy(ctx context.Context, pr auth.PolicyReq) error {
+	if err := es.Service.DeletePolicy(ctx, pr); err != nil {
+		return err
+	}
+
+	es.publishPolicy(ctx, policyDelete, pr)
+
+	return nil
+}
+
+func (es eventStore) DeletePolicies(ctx context.Context, token, object string, subjectIDs, relations []string) error {
+	if err := es.Service.DeletePolicies(ctx, token, object, subjectIDs, relations); err != nil {
+		return err
+	}
+
+	for _, subjectID := range subjectIDs {
+		for _, rel := range relations {
+			es.publishPolicy(ctx, policyDelete, auth.PolicyReq{Subject: subjectID, Object: object, Relation: rel})
+		}
+	}
+
+	return nil
+}
+
+func (es eventStore) publishPolicy(ctx context.Context, operation string, pr auth.PolicyReq) {
+	event := policyEvent{
+		operation: operation,
+		subject:   pr.Subject,
+		object:    pr.Object,
+		relation:  pr.Relation,
+		namespace: pr.Namespace,
+	}
+	es.publish(ctx, event)
+}
+
+// publish sends the event to the event store. The failure to send the event
+// doesn't fail the operation, which has already been performed.
+func (es eventStore) publish(ctx context.Context, e event) {
+	record := &redis.XAddArgs{
+		Stream:       streamID,
+		MaxLenApprox: streamLen,
+		Values:       e.Encode(),
+	}
+	es.client.XAdd(ctx, record).Err()
+}
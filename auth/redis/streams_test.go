@@ -0,0 +1,247 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	r "github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/auth/jwt"
+	"github.com/mainflux/mainflux/auth/mocks"
+	"github.com/mainflux/mainflux/auth/redis"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	streamID       = "mainflux.auth"
+	secret         = "secret"
+	email          = "user@example.com"
+	id             = "testID"
+	memberRelation = "member"
+	authoritiesObj = "authorities"
+
+	groupPrefix   = "group."
+	groupCreate   = groupPrefix + "create"
+	groupRemove   = groupPrefix + "remove"
+	groupAssign   = groupPrefix + "assign"
+	groupUnassign = groupPrefix + "unassign"
+
+	policyPrefix = "policy."
+	policyAdd    = policyPrefix + "add"
+	policyDelete = policyPrefix + "delete"
+)
+
+func newService() auth.Service {
+	mockAuthzDB := map[string][]mocks.MockSubjectSet{}
+	mockAuthzDB[id] = append(mockAuthzDB[id], mocks.MockSubjectSet{Object: authoritiesObj, Relation: memberRelation})
+	ketoMock := mocks.NewKetoMock(mockAuthzDB)
+
+	t := jwt.New(secret)
+	return auth.New(mocks.NewKeyRepository(), mocks.NewGroupRepository(), mocks.NewOrgRepository(), mocks.NewShareRepository(), mocks.NewRoleRepository(), mocks.NewServiceAccountRepository(), mocks.NewQuotaRepository(), mocks.NewPolicyExpirationRepository(), mocks.NewRevocationRepository(), mocks.NewSessionRepository(), mocks.NewAuditRepository(), uuid.NewMock(), t, ketoMock, nil)
+}
+
+func issueToken(t *testing.T, svc auth.Service) string {
+	_, token, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	return token
+}
+
+// readEvent reads the event following the lastID, or nil if there is none.
+func readEvent(lastID string) (map[string]interface{}, string) {
+	streams := redisClient.XRead(context.Background(), &r.XReadArgs{
+		Streams: []string{streamID, lastID},
+		Count:   1,
+		Block:   time.Second,
+	}).Val()
+
+	if len(streams) > 0 && len(streams[0].Messages) > 0 {
+		msg := streams[0].Messages[0]
+		return msg.Values, msg.ID
+	}
+	return nil, lastID
+}
+
+func TestCreateGroup(t *testing.T) {
+	_ = redisClient.FlushAll(context.Background()).Err()
+
+	svc := redis.NewEventStoreMiddleware(newService(), redisClient)
+	token := issueToken(t, svc)
+
+	cases := []struct {
+		desc  string
+		group auth.Group
+		token string
+		err   error
+		event bool
+	}{
+		{
+			desc:  "create group successfully",
+			group: auth.Group{Name: "a", Metadata: auth.GroupMetadata{"test": "test"}},
+			token: token,
+			err:   nil,
+			event: true,
+		},
+		{
+			desc:  "create group with invalid credentials",
+			group: auth.Group{Name: "b"},
+			token: "",
+			err:   auth.ErrUnauthorizedAccess,
+			event: false,
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		group, err := svc.CreateGroup(context.Background(), tc.token, tc.group)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		var expected map[string]interface{}
+		if tc.event {
+			expected = map[string]interface{}{
+				"id":        group.ID,
+				"owner_id":  group.OwnerID,
+				"name":      tc.group.Name,
+				"metadata":  "{\"test\":\"test\"}",
+				"operation": groupCreate,
+			}
+		}
+
+		var event map[string]interface{}
+		event, lastID = readEvent(lastID)
+		assert.Equal(t, expected, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, expected, event))
+	}
+}
+
+func TestRemoveGroup(t *testing.T) {
+	_ = redisClient.FlushAll(context.Background()).Err()
+
+	svc := newService()
+	token := issueToken(t, svc)
+	// Create group without sending event.
+	group, err := svc.CreateGroup(context.Background(), token, auth.Group{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
+		event map[string]interface{}
+	}{
+		{
+			desc:  "remove group with invalid credentials",
+			id:    group.ID,
+			token: "",
+			err:   auth.ErrUnauthorizedAccess,
+			event: nil,
+		},
+		{
+			desc:  "remove group successfully",
+			id:    group.ID,
+			token: token,
+			err:   nil,
+			event: map[string]interface{}{
+				"id":        group.ID,
+				"operation": groupRemove,
+			},
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := svc.RemoveGroup(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		var event map[string]interface{}
+		event, lastID = readEvent(lastID)
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
+
+func TestAssignAndUnassign(t *testing.T) {
+	_ = redisClient.FlushAll(context.Background()).Err()
+
+	svc := newService()
+	token := issueToken(t, svc)
+	// Create group without sending event.
+	group, err := svc.CreateGroup(context.Background(), token, auth.Group{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	svc = redis.NewEventStoreMiddleware(svc, redisClient)
+	members := []string{"member1", "member2"}
+
+	err = svc.Assign(context.Background(), token, group.ID, "things", members...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	event, lastID := readEvent("0")
+	expected := map[string]interface{}{
+		"group_id":  group.ID,
+		"type":      "things",
+		"members":   "member1,member2",
+		"operation": groupAssign,
+	}
+	assert.Equal(t, expected, event, fmt.Sprintf("assign members: expected %v got %v\n", expected, event))
+
+	err = svc.Unassign(context.Background(), token, group.ID, members...)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	event, _ = readEvent(lastID)
+	expected = map[string]interface{}{
+		"group_id":  group.ID,
+		"members":   "member1,member2",
+		"operation": groupUnassign,
+	}
+	assert.Equal(t, expected, event, fmt.Sprintf("unassign members: expected %v got %v\n", expected, event))
+}
+
+func TestPolicies(t *testing.T) {
+	_ = redisClient.FlushAll(context.Background()).Err()
+
+	svc := redis.NewEventStoreMiddleware(newService(), redisClient)
+	pr := auth.PolicyReq{Subject: "subject", Object: "object", Relation: "read"}
+
+	cases := []struct {
+		desc  string
+		op    func() error
+		event map[string]interface{}
+	}{
+		{
+			desc: "add policy",
+			op:   func() error { return svc.AddPolicy(context.Background(), pr) },
+			event: map[string]interface{}{
+				"subject":   pr.Subject,
+				"object":    pr.Object,
+				"relation":  pr.Relation,
+				"operation": policyAdd,
+			},
+		},
+		{
+			desc: "delete policy",
+			op:   func() error { return svc.DeletePolicy(context.Background(), pr) },
+			event: map[string]interface{}{
+				"subject":   pr.Subject,
+				"object":    pr.Object,
+				"relation":  pr.Relation,
+				"operation": policyDelete,
+			},
+		},
+	}
+
+	lastID := "0"
+	for _, tc := range cases {
+		err := tc.op()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+
+		var event map[string]interface{}
+		event, lastID = readEvent(lastID)
+		assert.Equal(t, tc.event, event, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.event, event))
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Revocation represents the key revoked before its expiration.
+type Revocation struct {
+	KeyID     string
+	IssuerID  string
+	RevokedAt time.Time
+
+	// ExpiresAt is the expiration time of the revoked key, after which the
+	// revocation is not needed anymore. It's zero for the keys that don't
+	// expire.
+	ExpiresAt time.Time
+}
+
+// Introspection represents the state of the key token.
+type Introspection struct {
+	// Active indicates that the token is valid, i.e. it's not malformed,
+	// expired nor revoked.
+	Active bool
+	Key    Key
+}
+
+// RevocationRepository specifies Revocation persistence API.
+type RevocationRepository interface {
+	// Save persists the revocation. The revocations of the keys that have
+	// expired in the meantime may be removed.
+	Save(ctx context.Context, r Revocation) error
+
+	// Contains checks if the key with the given ID is revoked.
+	Contains(ctx context.Context, keyID string) (bool, error)
+
+	// SaveIssuer revokes all the keys of the issuer issued before the
+	// given time.
+	SaveIssuer(ctx context.Context, issuerID string, revokedAt time.Time) error
+
+	// RetrieveIssuer retrieves the time before which all the keys of the
+	// issuer are revoked. Zero time is returned if the issuer's keys are
+	// not revoked in bulk.
+	RetrieveIssuer(ctx context.Context, issuerID string) (time.Time, error)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// AdminRole allows the user to perform all the actions on the object.
+	AdminRole = "admin"
+
+	// EditorRole allows the user to read, update and connect to the object.
+	EditorRole = "editor"
+
+	// ViewerRole allows the user to read the object.
+	ViewerRole = "viewer"
+)
+
+// builtinRoles are the roles available without being created.
+var builtinRoles = []Role{
+	{Name: AdminRole, Relations: []string{"read", "write", "delete", "access"}, Builtin: true},
+	{Name: EditorRole, Relations: []string{"read", "write", "access"}, Builtin: true},
+	{Name: ViewerRole, Relations: []string{"read"}, Builtin: true},
+}
+
+// Role represents the named set of relations. Assigning the role to the
+// user over the object adds the policy of each of the role relations.
+type Role struct {
+	Name      string
+	Relations []string
+	Builtin   bool
+	CreatedAt time.Time
+}
+
+// RoleAssignment represents the role assigned to the subject over the
+// object. Relations contain the policies added to the subject by the
+// assignment, the ones the subject already had are not part of it.
+type RoleAssignment struct {
+	Role       string
+	Subject    string
+	Object     string
+	Relations  []string
+	AssignedBy string
+	CreatedAt  time.Time
+}
+
+// Roles specifies an API for the role-based access control.
+type Roles interface {
+	// CreateRole creates the custom role. This method is only allowed to
+	// use as an admin.
+	CreateRole(ctx context.Context, token string, r Role) (Role, error)
+
+	// ListRoles lists the builtin and the custom roles.
+	ListRoles(ctx context.Context, token string) ([]Role, error)
+
+	// RemoveRole removes the custom role that isn't assigned to anyone.
+	// This method is only allowed to use as an admin.
+	RemoveRole(ctx context.Context, token, name string) error
+
+	// AssignRole assigns the role to the subject over the object. The user
+	// identified by the token has to have all the role relations on the
+	// object, unless the user is an admin.
+	AssignRole(ctx context.Context, token string, ra RoleAssignment) (RoleAssignment, error)
+
+	// ListRoleAssignments lists the role assignments matching the non-empty
+	// role, subject and object of the given assignment. The admin can list
+	// any assignment, while the other users can only list their own ones.
+	ListRoleAssignments(ctx context.Context, token string, ra RoleAssignment) ([]RoleAssignment, error)
+
+	// UnassignRole removes the role assignment and the policies added by
+	// it, except for the ones added by the other assignments of the object.
+	UnassignRole(ctx context.Context, token string, ra RoleAssignment) error
+}
+
+// RoleRepository specifies Role and RoleAssignment persistence API.
+type RoleRepository interface {
+	// Save persists the custom role.
+	Save(ctx context.Context, r Role) error
+
+	// RetrieveByName retrieves the custom role by its name.
+	RetrieveByName(ctx context.Context, name string) (Role, error)
+
+	// RetrieveAll retrieves all the custom roles.
+	RetrieveAll(ctx context.Context) ([]Role, error)
+
+	// Remove removes the custom role.
+	Remove(ctx context.Context, name string) error
+
+	// SaveAssignment persists the role assignment.
+	SaveAssignment(ctx context.Context, ra RoleAssignment) error
+
+	// RetrieveAssignments retrieves the role assignments matching the
+	// non-empty role, subject and object of the given assignment.
+	RetrieveAssignments(ctx context.Context, ra RoleAssignment) ([]RoleAssignment, error)
+
+	// RemoveAssignment removes the role assignment.
+	RemoveAssignment(ctx context.Context, ra RoleAssignment) error
+}
+
+func builtinRole(name string) (Role, bool) {
+	for _, r := range builtinRoles {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return Role{}, false
+}
@@ -14,13 +14,31 @@ import (
 )
 
 const (
-	loginDuration    = 10 * time.Hour
+	loginDuration    = 15 * time.Minute
+	refreshDuration  = 7 * 24 * time.Hour
 	recoveryDuration = 5 * time.Minute
 
 	thingsGroupType = "things"
 
 	authoritiesObject = "authorities"
 	memberRelation    = "member"
+
+	maxOrgUsers = 10000
+
+	// maxMemberships is the maximum number of the member's groups searched
+	// for the subtree admins.
+	maxMemberships = 1000
+
+	// maxExplainDepth is the maximum number of the nested subject sets
+	// searched by ExplainPolicy.
+	maxExplainDepth = 8
+
+	reasonPath    = "granted by the policy path"
+	reasonAgent   = "granted by the policy agent"
+	reasonSubtree = "granted by the subtree admin"
+	reasonKey     = "denied by the key"
+	reasonScopes  = "denied by the service account scopes"
+	reasonNoPath  = "no policy path grants the relation"
 )
 
 var (
@@ -60,6 +78,7 @@ var (
 
 	errIssueUser = errors.New("failed to issue new user key")
 	errIssueTmp  = errors.New("failed to issue new temporary key")
+	errRefresh   = errors.New("failed to refresh key")
 	errRevoke    = errors.New("failed to remove key")
 	errRetrieve  = errors.New("failed to retrieve key data")
 	errIdentify  = errors.New("failed to validate token")
@@ -81,10 +100,46 @@ type Authn interface {
 	// ID, that is issued by the user identified by the provided key.
 	RetrieveKey(ctx context.Context, token, id string) (Key, error)
 
+	// ListKeys retrieves the stored Keys, such as the API keys, issued by
+	// the user identified by the provided key within its organization.
+	ListKeys(ctx context.Context, token string, offset, limit uint64) (KeysPage, error)
+
+	// LabelKey updates the label of the Key identified by the provided ID,
+	// that is issued by the user identified by the provided key.
+	LabelKey(ctx context.Context, token, id, label string) error
+
 	// Identify validates token token. If token is valid, content
 	// is returned. If token is invalid, or invocation failed for some
 	// other reason, non-nil error value is returned in response.
 	Identify(ctx context.Context, token string) (Identity, error)
+
+	// RevokeToken revokes the given token before its expiration, so that
+	// the compromised token can't be used anymore. The users can revoke
+	// their own tokens, while the admin can revoke any token.
+	RevokeToken(ctx context.Context, token, revoked string) error
+
+	// Introspect retrieves the state of the given token. The token is
+	// reported as inactive if it's invalid, expired, revoked, or if it's
+	// not issued to the user identified by the provided token, unless
+	// the user is the admin.
+	Introspect(ctx context.Context, token, introspected string) (Introspection, error)
+
+	// Refresh issues a new User key to the user identified by the provided
+	// refresh token, returning the new User key token and the new refresh
+	// token. The provided refresh token is revoked, so it can be used only
+	// once.
+	Refresh(ctx context.Context, refreshToken string) (string, string, error)
+
+	// RevokeKeys revokes all the keys of the given subject, i.e. the user
+	// or the service account, returning the number of the removed API keys.
+	// The keys that are not stored, such as the login and the refresh
+	// keys, are revoked as well. The users can revoke their own keys, while
+	// the admin can revoke the keys of any subject.
+	RevokeKeys(ctx context.Context, token, subject string) (uint64, error)
+
+	// PublicKeys retrieves the public keys used to verify the tokens, so
+	// the tokens can be verified without calling the service.
+	PublicKeys(ctx context.Context) ([]PublicKey, error)
 }
 
 // Service specifies an API that must be fulfilled by the domain service
@@ -94,6 +149,13 @@ type Authn interface {
 type Service interface {
 	Authn
 	Authz
+	Sharing
+	Roles
+	ServiceAccounts
+	Quotas
+	Audit
+	Orgs
+	Sessions
 
 	// GroupService implements groups API, creating groups, assigning members
 	GroupService
@@ -104,21 +166,47 @@ var _ Service = (*service)(nil)
 type service struct {
 	keys         KeyRepository
 	groups       GroupRepository
+	orgs         OrgRepository
+	shares       ShareRepository
+	roles        RoleRepository
+	accounts     ServiceAccountRepository
+	quotas       QuotaRepository
+	expirations  PolicyExpirationRepository
+	revocations  RevocationRepository
+	sessions     SessionRepository
+	audit        AuditRepository
+	limiters     *limiters
 	idProvider   mainflux.IDProvider
 	ulidProvider mainflux.IDProvider
 	agent        PolicyAgent
 	tokenizer    Tokenizer
+	templates    PolicyTemplates
 }
 
-// New instantiates the auth service implementation.
-func New(keys KeyRepository, groups GroupRepository, idp mainflux.IDProvider, tokenizer Tokenizer, policyAgent PolicyAgent) Service {
+// New instantiates the auth service implementation. The nil templates stand
+// for the default policy templates.
+func New(keys KeyRepository, groups GroupRepository, orgs OrgRepository, shares ShareRepository, roles RoleRepository, accounts ServiceAccountRepository, quotas QuotaRepository, expirations PolicyExpirationRepository, revocations RevocationRepository, sessions SessionRepository, audit AuditRepository, idp mainflux.IDProvider, tokenizer Tokenizer, policyAgent PolicyAgent, templates PolicyTemplates) Service {
+	if templates == nil {
+		templates = DefaultPolicyTemplates()
+	}
 	return &service{
 		tokenizer:    tokenizer,
 		keys:         keys,
 		groups:       groups,
+		orgs:         orgs,
+		shares:       shares,
+		roles:        roles,
+		accounts:     accounts,
+		quotas:       quotas,
+		expirations:  expirations,
+		revocations:  revocations,
+		sessions:     sessions,
+		audit:        audit,
+		limiters:     newLimiters(),
 		idProvider:   idp,
 		ulidProvider: ulid.New(),
-		agent:        policyAgent,
+		agent:        auditAgent{agent: policyAgent, audit: audit},
+		templates:    templates,
 	}
 }
 
@@ -126,326 +214,2472 @@ func (svc service) Issue(ctx context.Context, token string, key Key) (Key, strin
 	if key.IssuedAt.IsZero() {
 		return Key{}, "", ErrInvalidKeyIssuedAt
 	}
+	// Only the API keys can be scoped.
+	if len(key.Scopes) > 0 && (key.Type != APIKey || !validKeyScopes(key.Scopes)) {
+		return Key{}, "", ErrMalformedEntity
+	}
+	// Only the API keys can be bound to the networks.
+	if len(key.AllowedIPs) > 0 && (key.Type != APIKey || !validNetworks(key.AllowedIPs)) {
+		return Key{}, "", ErrMalformedEntity
+	}
+	// Only the API keys are stored, so only they can be labeled.
+	if key.Label != "" && (key.Type != APIKey || len(key.Label) > maxKeyLabelLength) {
+		return Key{}, "", ErrMalformedEntity
+	}
+	if !validClaims(key.Claims) {
+		return Key{}, "", ErrMalformedEntity
+	}
+	// Only the login and the refresh keys belong to the sessions.
+	if _, ok := key.Claims[SessionClaim]; ok && key.Type != UserKey && key.Type != RefreshKey {
+		return Key{}, "", ErrMalformedEntity
+	}
 	switch key.Type {
 	case APIKey:
 		return svc.userKey(ctx, token, key)
+	case ServiceAccountKey:
+		// Service account keys are issued using the service accounts API.
+		return Key{}, "", ErrMalformedEntity
 	case RecoveryKey:
 		return svc.tmpKey(recoveryDuration, key)
+	case RefreshKey:
+		return svc.sessionKey(ctx, refreshDuration, key)
 	default:
-		return svc.tmpKey(loginDuration, key)
+		return svc.sessionKey(ctx, loginDuration, key)
 	}
 }
 
 func (svc service) Revoke(ctx context.Context, token, id string) error {
-	issuerID, _, err := svc.login(token)
+	user, err := svc.login(ctx, token)
+	if err != nil {
+		return errors.Wrap(errRevoke, err)
+	}
+
+	// The issued tokens of the removed key remain valid until they
+	// expire, so the key is revoked as well.
+	key, err := svc.keys.Retrieve(ctx, user.IssuerID, id)
 	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return nil
+		}
 		return errors.Wrap(errRevoke, err)
 	}
-	if err := svc.keys.Remove(ctx, issuerID, id); err != nil {
+	// The keys of the other organizations are out of reach.
+	if key.OrgID != user.OrgID {
+		return nil
+	}
+	if err := svc.revoke(ctx, key); err != nil {
+		return err
+	}
+	if err := svc.keys.Remove(ctx, user.IssuerID, id); err != nil {
 		return errors.Wrap(errRevoke, err)
 	}
 	return nil
 }
 
-func (svc service) RetrieveKey(ctx context.Context, token, id string) (Key, error) {
-	issuerID, _, err := svc.login(token)
+func (svc service) RevokeToken(ctx context.Context, token, revoked string) error {
+	user, err := svc.identify(ctx, token)
 	if err != nil {
-		return Key{}, errors.Wrap(errRetrieve, err)
+		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	return svc.keys.Retrieve(ctx, issuerID, id)
-}
+	key, err := svc.tokenizer.Parse(revoked)
+	switch {
+	case errors.Contains(err, ErrKeyExpired), errors.Contains(err, ErrAPIKeyExpired):
+		// The expired token can't be used anyway.
+		return nil
+	case err != nil:
+		return errors.Wrap(ErrMalformedEntity, err)
+	case key.ID == "":
+		// The tokens issued without ID can't be told apart.
+		return ErrMalformedEntity
+	}
 
-func (svc service) Identify(ctx context.Context, token string) (Identity, error) {
-	key, err := svc.tokenizer.Parse(token)
-	if err == ErrAPIKeyExpired {
-		err = svc.keys.Remove(ctx, key.IssuerID, key.ID)
-		return Identity{}, errors.Wrap(ErrAPIKeyExpired, err)
+	if key.IssuerID != user.IssuerID {
+		if err := svc.authorizeAdmin(ctx, user.IssuerID, user.OrgID); err != nil {
+			return err
+		}
+	}
+
+	if err := svc.revoke(ctx, key); err != nil {
+		return err
+	}
+	if key.Type == APIKey {
+		if err := svc.keys.Remove(ctx, key.IssuerID, key.ID); err != nil {
+			return errors.Wrap(errRevoke, err)
+		}
 	}
+	return nil
+}
+
+func (svc service) Introspect(ctx context.Context, token, introspected string) (Introspection, error) {
+	user, err := svc.identify(ctx, token)
 	if err != nil {
-		return Identity{}, errors.Wrap(errIdentify, err)
+		return Introspection{}, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	switch key.Type {
-	case APIKey, RecoveryKey, UserKey:
-		return Identity{ID: key.IssuerID, Email: key.Subject}, nil
-	default:
-		return Identity{}, ErrUnauthorizedAccess
+	key, err := svc.validateKey(ctx, introspected)
+	if err != nil {
+		return Introspection{}, nil
 	}
-}
 
-func (svc service) Authorize(ctx context.Context, pr PolicyReq) error {
-	return svc.agent.CheckPolicy(ctx, pr)
-}
+	if key.IssuerID != user.IssuerID {
+		if err := svc.authorizeAdmin(ctx, user.IssuerID, user.OrgID); err != nil {
+			return Introspection{}, nil
+		}
+	}
 
-func (svc service) AddPolicy(ctx context.Context, pr PolicyReq) error {
-	return svc.agent.AddPolicy(ctx, pr)
+	return Introspection{Active: true, Key: key}, nil
 }
 
-func (svc service) AddPolicies(ctx context.Context, token, object string, subjectIDs, relations []string) error {
-	user, err := svc.Identify(ctx, token)
+func (svc service) Refresh(ctx context.Context, refreshToken string) (string, string, error) {
+	key, err := svc.tokenizer.Parse(refreshToken)
 	if err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+		return "", "", errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if key.Type != RefreshKey || key.ID == "" || key.IssuerID == "" {
+		return "", "", ErrUnauthorizedAccess
+	}
+	if err := svc.checkRevoked(ctx, key); err != nil {
+		return "", "", err
+	}
+	if err := svc.checkOrgMember(ctx, key); err != nil {
+		return "", "", err
 	}
 
-	if err := svc.Authorize(ctx, PolicyReq{Object: authoritiesObject, Relation: memberRelation, Subject: user.ID}); err != nil {
-		return err
+	// Refresh tokens are rotated, so the stolen refresh token can be used
+	// only until its legitimate owner refreshes the key.
+	if err := svc.revoke(ctx, key); err != nil {
+		return "", "", errors.Wrap(errRefresh, err)
 	}
 
-	var errs error
-	for _, subjectID := range subjectIDs {
-		for _, relation := range relations {
-			if err := svc.AddPolicy(ctx, PolicyReq{Object: object, Relation: relation, Subject: subjectID}); err != nil {
-				errs = errors.Wrap(fmt.Errorf("cannot add '%s' policy on object '%s' for subject '%s': %s", relation, object, subjectID, err), errs)
-			}
-		}
+	now := getTimestmap()
+	userKey := Key{
+		Type:     UserKey,
+		IssuerID: key.IssuerID,
+		Subject:  key.Subject,
+		OrgID:    key.OrgID,
+		IssuedAt: now,
+		Claims:   key.Claims,
+	}
+	_, token, err := svc.tmpKey(loginDuration, userKey)
+	if err != nil {
+		return "", "", errors.Wrap(errRefresh, err)
 	}
-	return errs
-}
 
-func (svc service) DeletePolicy(ctx context.Context, pr PolicyReq) error {
-	return svc.agent.DeletePolicy(ctx, pr)
+	userKey.Type = RefreshKey
+	_, refresh, err := svc.sessionKey(ctx, refreshDuration, userKey)
+	if err != nil {
+		return "", "", errors.Wrap(errRefresh, err)
+	}
+
+	return token, refresh, nil
 }
 
-func (svc service) DeletePolicies(ctx context.Context, token, object string, subjectIDs, relations []string) error {
-	user, err := svc.Identify(ctx, token)
+func (svc service) RevokeKeys(ctx context.Context, token, subject string) (uint64, error) {
+	user, err := svc.identify(ctx, token)
 	if err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+		return 0, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
-
-	// Check if the user identified by token is the admin.
-	if err := svc.Authorize(ctx, PolicyReq{Object: authoritiesObject, Relation: memberRelation, Subject: user.ID}); err != nil {
-		return err
+	if subject == "" {
+		return 0, ErrMalformedEntity
 	}
 
-	var errs error
-	for _, subjectID := range subjectIDs {
-		for _, relation := range relations {
-			if err := svc.DeletePolicy(ctx, PolicyReq{Object: object, Relation: relation, Subject: subjectID}); err != nil {
-				errs = errors.Wrap(fmt.Errorf("cannot delete '%s' policy on object '%s' for subject '%s': %s", relation, object, subjectID, err), errs)
-			}
+	if subject != user.IssuerID {
+		if err := svc.authorizeAdmin(ctx, user.IssuerID, user.OrgID); err != nil {
+			return 0, err
 		}
 	}
-	return errs
-}
 
-func (svc service) AssignGroupAccessRights(ctx context.Context, token, thingGroupID, userGroupID string) error {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+	// The tokens carry the issue time in seconds, so the keys issued in
+	// the same second as the revocation remain valid.
+	now := getTimestmap().Truncate(time.Second)
+	if err := svc.revocations.SaveIssuer(ctx, subject, now); err != nil {
+		return 0, errors.Wrap(errRevoke, err)
+	}
+	if err := svc.sessions.RemoveByUser(ctx, subject); err != nil {
+		return 0, errors.Wrap(errRevoke, err)
 	}
-	return svc.agent.AddPolicy(ctx, PolicyReq{Object: thingGroupID, Relation: memberRelation, Subject: fmt.Sprintf("%s:%s#%s", "members", userGroupID, memberRelation)})
-}
 
-func (svc service) ListPolicies(ctx context.Context, pr PolicyReq) (PolicyPage, error) {
-	res, err := svc.agent.RetrievePolicies(ctx, pr)
+	keys, err := svc.keys.RemoveByIssuer(ctx, subject)
 	if err != nil {
-		return PolicyPage{}, err
+		return 0, errors.Wrap(errRevoke, err)
 	}
-	var page PolicyPage
-	for _, tuple := range res {
-		page.Policies = append(page.Policies, tuple.GetObject())
+	for _, key := range keys {
+		if err := svc.revoke(ctx, key); err != nil {
+			return 0, err
+		}
 	}
-	return page, err
+
+	return uint64(len(keys)), nil
 }
 
-func (svc service) tmpKey(duration time.Duration, key Key) (Key, string, error) {
-	key.ExpiresAt = key.IssuedAt.Add(duration)
-	secret, err := svc.tokenizer.Issue(key)
+func (svc service) PublicKeys(ctx context.Context) ([]PublicKey, error) {
+	return svc.tokenizer.PublicKeys(), nil
+}
+
+func (svc service) ListSessions(ctx context.Context, token string) ([]Session, error) {
+	user, err := svc.sessionUser(ctx, token)
 	if err != nil {
-		return Key{}, "", errors.Wrap(errIssueTmp, err)
+		return nil, err
 	}
 
-	return key, secret, nil
+	sessions, err := svc.sessions.RetrieveByUser(ctx, user.IssuerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == user.Claims[SessionClaim]
+	}
+	return sessions, nil
 }
 
-func (svc service) userKey(ctx context.Context, token string, key Key) (Key, string, error) {
-	id, sub, err := svc.login(token)
+func (svc service) RevokeSession(ctx context.Context, token, id string) error {
+	user, err := svc.sessionUser(ctx, token)
 	if err != nil {
-		return Key{}, "", errors.Wrap(errIssueUser, err)
+		return err
 	}
 
-	key.IssuerID = id
-	if key.Subject == "" {
-		key.Subject = sub
+	s, err := svc.sessions.Retrieve(ctx, user.IssuerID, id)
+	if err != nil {
+		return err
 	}
+	return svc.revokeSession(ctx, s)
+}
 
-	keyID, err := svc.idProvider.ID()
+func (svc service) RevokeOtherSessions(ctx context.Context, token string) (uint64, error) {
+	user, err := svc.sessionUser(ctx, token)
 	if err != nil {
-		return Key{}, "", errors.Wrap(errIssueUser, err)
+		return 0, err
 	}
-	key.ID = keyID
 
-	if _, err := svc.keys.Save(ctx, key); err != nil {
-		return Key{}, "", errors.Wrap(errIssueUser, err)
+	sessions, err := svc.sessions.RetrieveByUser(ctx, user.IssuerID)
+	if err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, s := range sessions {
+		if s.ID == user.Claims[SessionClaim] {
+			continue
+		}
+		if err := svc.revokeSession(ctx, s); err != nil {
+			return n, err
+		}
+		n++
 	}
+	return n, nil
+}
 
-	secret, err := svc.tokenizer.Issue(key)
+// sessionUser identifies the user managing the sessions, which is allowed
+// using the User keys only.
+func (svc service) sessionUser(ctx context.Context, token string) (Key, error) {
+	key, err := svc.identify(ctx, token)
 	if err != nil {
-		return Key{}, "", errors.Wrap(errIssueUser, err)
+		return Key{}, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
-
-	return key, secret, nil
+	if key.Type != UserKey {
+		return Key{}, ErrUnauthorizedAccess
+	}
+	return key, nil
 }
 
-func (svc service) login(token string) (string, string, error) {
-	key, err := svc.tokenizer.Parse(token)
-	if err != nil {
-		return "", "", err
+// revokeSession revokes the keys of the session by putting the session
+// itself on the revocation list, until its last refresh key expires.
+func (svc service) revokeSession(ctx context.Context, s Session) error {
+	r := Revocation{
+		KeyID:     s.ID,
+		IssuerID:  s.UserID,
+		RevokedAt: getTimestmap(),
+		ExpiresAt: s.ExpiresAt,
 	}
-	// Only user key token is valid for login.
-	if key.Type != UserKey || key.IssuerID == "" {
-		return "", "", ErrUnauthorizedAccess
+	if err := svc.revocations.Save(ctx, r); err != nil {
+		return errors.Wrap(errRevoke, err)
+	}
+	if err := svc.sessions.Remove(ctx, s.UserID, s.ID); err != nil {
+		return errors.Wrap(errRevoke, err)
 	}
+	return nil
+}
 
-	return key.IssuerID, key.Subject, nil
+func (svc service) revoke(ctx context.Context, key Key) error {
+	r := Revocation{
+		KeyID:     key.ID,
+		IssuerID:  key.IssuerID,
+		RevokedAt: getTimestmap(),
+		ExpiresAt: key.ExpiresAt,
+	}
+	if err := svc.revocations.Save(ctx, r); err != nil {
+		return errors.Wrap(errRevoke, err)
+	}
+	return nil
 }
 
-func (svc service) CreateGroup(ctx context.Context, token string, group Group) (Group, error) {
-	user, err := svc.Identify(ctx, token)
+func (svc service) RetrieveKey(ctx context.Context, token, id string) (Key, error) {
+	user, err := svc.login(ctx, token)
 	if err != nil {
-		return Group{}, errors.Wrap(ErrUnauthorizedAccess, err)
+		return Key{}, errors.Wrap(errRetrieve, err)
 	}
 
-	ulid, err := svc.ulidProvider.ID()
+	key, err := svc.keys.Retrieve(ctx, user.IssuerID, id)
 	if err != nil {
-		return Group{}, errors.Wrap(ErrGenerateGroupID, err)
+		return Key{}, err
 	}
+	if key.OrgID != user.OrgID {
+		return Key{}, ErrNotFound
+	}
+	return key, nil
+}
 
-	timestamp := getTimestmap()
-	group.UpdatedAt = timestamp
-	group.CreatedAt = timestamp
+func (svc service) ListKeys(ctx context.Context, token string, offset, limit uint64) (KeysPage, error) {
+	user, err := svc.login(ctx, token)
+	if err != nil {
+		return KeysPage{}, errors.Wrap(errRetrieve, err)
+	}
 
-	group.ID = ulid
-	group.OwnerID = user.ID
+	return svc.keys.RetrieveByIssuer(ctx, user.IssuerID, user.OrgID, offset, limit)
+}
 
-	group, err = svc.groups.Save(ctx, group)
+func (svc service) LabelKey(ctx context.Context, token, id, label string) error {
+	if len(label) > maxKeyLabelLength {
+		return ErrMalformedEntity
+	}
+
+	user, err := svc.login(ctx, token)
 	if err != nil {
-		return Group{}, err
+		return errors.Wrap(errRetrieve, err)
 	}
 
-	if err := svc.agent.AddPolicy(ctx, PolicyReq{Object: group.ID, Relation: memberRelation, Subject: user.ID}); err != nil {
-		return Group{}, err
+	key, err := svc.keys.Retrieve(ctx, user.IssuerID, id)
+	if err != nil {
+		return err
+	}
+	// The keys of the other organizations are out of reach.
+	if key.OrgID != user.OrgID {
+		return ErrNotFound
 	}
 
-	return group, nil
+	return svc.keys.UpdateLabel(ctx, user.IssuerID, id, label)
 }
 
-func (svc service) ListGroups(ctx context.Context, token string, pm PageMetadata) (GroupPage, error) {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return GroupPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+func (svc service) Identify(ctx context.Context, token string) (Identity, error) {
+	key, err := svc.identify(ctx, token)
+	if err != nil {
+		return Identity{}, err
 	}
-	return svc.groups.RetrieveAll(ctx, pm)
-}
 
-func (svc service) ListParents(ctx context.Context, token string, childID string, pm PageMetadata) (GroupPage, error) {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return GroupPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	// The session activity is tracked on the best effort basis, so the
+	// failure to track it doesn't fail the identification.
+	if sid := key.Claims[SessionClaim]; sid != "" && key.Type == UserKey {
+		svc.sessions.Touch(ctx, sid, getTimestmap())
 	}
-	return svc.groups.RetrieveAllParents(ctx, childID, pm)
+
+	return Identity{ID: key.IssuerID, Email: key.Subject, OrgID: key.OrgID, Claims: key.Claims}, nil
 }
 
-func (svc service) ListChildren(ctx context.Context, token string, parentID string, pm PageMetadata) (GroupPage, error) {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return GroupPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+func (svc service) identify(ctx context.Context, token string) (Key, error) {
+	key, err := svc.validateKey(ctx, token)
+	if err != nil {
+		return Key{}, err
 	}
-	return svc.groups.RetrieveAllChildren(ctx, parentID, pm)
+	if !key.AllowsIP(ClientIP(ctx)) {
+		return Key{}, errors.Wrap(ErrUnauthorizedAccess, ErrKeyIPNotAllowed)
+	}
+	// The scoped keys are usable only for the actions declaring the scope,
+	// so they are rejected by the services not aware of the scopes.
+	if !key.Allows(Scope(ctx)) {
+		return Key{}, errors.Wrap(ErrUnauthorizedAccess, ErrKeyScopeNotAllowed)
+	}
+	return key, nil
 }
 
-func (svc service) ListMembers(ctx context.Context, token string, groupID, groupType string, pm PageMetadata) (MemberPage, error) {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return MemberPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+// validateKey parses the token and checks that the key is still valid,
+// regardless of the client the key is used from.
+func (svc service) validateKey(ctx context.Context, token string) (Key, error) {
+	key, err := svc.tokenizer.Parse(token)
+	if err == ErrAPIKeyExpired {
+		err = svc.keys.Remove(ctx, key.IssuerID, key.ID)
+		return Key{}, errors.Wrap(ErrAPIKeyExpired, err)
 	}
-	mp, err := svc.groups.Members(ctx, groupID, groupType, pm)
 	if err != nil {
-		return MemberPage{}, errors.Wrap(ErrFailedToRetrieveMembers, err)
+		return Key{}, errors.Wrap(errIdentify, err)
 	}
-	return mp, nil
-}
 
-func (svc service) RemoveGroup(ctx context.Context, token, id string) error {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+	if err := svc.checkRevoked(ctx, key); err != nil {
+		return Key{}, err
 	}
-	return svc.groups.Delete(ctx, id)
-}
-
-func (svc service) UpdateGroup(ctx context.Context, token string, group Group) (Group, error) {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return Group{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	if err := svc.checkOrgMember(ctx, key); err != nil {
+		return Key{}, err
 	}
 
-	group.UpdatedAt = getTimestmap()
-	return svc.groups.Update(ctx, group)
+	switch key.Type {
+	case APIKey, RecoveryKey, UserKey:
+		return key, nil
+	case ServiceAccountKey:
+		// The key is valid as long as both the enabled service account
+		// and the key itself exist, so removing either, or disabling the
+		// service account, revokes the access.
+		sa, err := svc.accounts.RetrieveByID(ctx, key.IssuerID)
+		if err != nil {
+			return Key{}, errors.Wrap(ErrUnauthorizedAccess, err)
+		}
+		if sa.Disabled {
+			return Key{}, ErrUnauthorizedAccess
+		}
+		if _, err := svc.keys.Retrieve(ctx, key.IssuerID, key.ID); err != nil {
+			return Key{}, errors.Wrap(ErrUnauthorizedAccess, err)
+		}
+		return key, nil
+	default:
+		return Key{}, ErrUnauthorizedAccess
+	}
 }
 
-func (svc service) ViewGroup(ctx context.Context, token, id string) (Group, error) {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return Group{}, errors.Wrap(ErrUnauthorizedAccess, err)
+// checkRevoked returns an error if the key or its session is on the
+// revocation list, or if it's issued before all the keys of its issuer are
+// revoked.
+func (svc service) checkRevoked(ctx context.Context, key Key) error {
+	if key.ID != "" {
+		revoked, err := svc.revocations.Contains(ctx, key.ID)
+		if err != nil {
+			return errors.Wrap(errIdentify, err)
+		}
+		if revoked {
+			return errors.Wrap(ErrUnauthorizedAccess, ErrKeyRevoked)
+		}
+	}
+	if sid := key.Claims[SessionClaim]; sid != "" {
+		revoked, err := svc.revocations.Contains(ctx, sid)
+		if err != nil {
+			return errors.Wrap(errIdentify, err)
+		}
+		if revoked {
+			return errors.Wrap(ErrUnauthorizedAccess, ErrKeyRevoked)
+		}
+	}
+	if key.IssuerID != "" {
+		revokedAt, err := svc.revocations.RetrieveIssuer(ctx, key.IssuerID)
+		if err != nil {
+			return errors.Wrap(errIdentify, err)
+		}
+		if key.IssuedAt.Before(revokedAt) {
+			return errors.Wrap(ErrUnauthorizedAccess, ErrKeyRevoked)
+		}
 	}
-	return svc.groups.RetrieveByID(ctx, id)
+	return nil
 }
 
-func (svc service) Assign(ctx context.Context, token string, groupID, groupType string, memberIDs ...string) error {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+func (svc service) Authorize(ctx context.Context, pr PolicyReq) error {
+	orgID, reason, err := svc.precheck(ctx, pr)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		saveAudit(ctx, svc.audit, CheckOperation, pr, DeniedDecision)
+		return ErrAuthorization
 	}
 
-	if err := svc.groups.Assign(ctx, groupID, groupType, memberIDs...); err != nil {
+	if err := svc.revokeExpired(ctx, pr); err != nil {
 		return err
 	}
 
-	if groupType == thingsGroupType {
-		ss := fmt.Sprintf("%s:%s#%s", "members", groupID, memberRelation)
-		var errs error
-		for _, memberID := range memberIDs {
-			for _, action := range []string{"read", "write", "delete"} {
-				if err := svc.agent.AddPolicy(ctx, PolicyReq{Object: memberID, Relation: action, Subject: ss}); err != nil {
-					errs = errors.Wrap(fmt.Errorf("cannot add thing: '%s' to thing group: '%s'", memberID, groupID), errs)
-				}
-			}
-		}
-		return errs
+	err = svc.agent.CheckPolicy(ctx, pr)
+	if errors.Contains(err, ErrAuthorization) && svc.managesMember(ctx, pr.Subject, orgID, pr.Object) {
+		return nil
 	}
+	return err
+}
 
-	var errs error
-	for _, memberID := range memberIDs {
-		if err := svc.agent.AddPolicy(ctx, PolicyReq{Object: groupID, Relation: memberRelation, Subject: memberID}); err != nil {
-			errs = errors.Wrap(fmt.Errorf("cannot add user: '%s' to user group: '%s'", memberID, groupID), errs)
+// precheck applies the checks Authorize and ExplainPolicy make before
+// asking the policy agent. It returns the organization the subtree admins
+// are reached within, and the reason of the denial if the request is denied
+// before reaching the agent.
+func (svc service) precheck(ctx context.Context, pr PolicyReq) (string, string, error) {
+	var orgID string
+
+	// Scoped API keys are allowed to perform only the scoped actions.
+	if pr.Token != "" {
+		key, err := svc.identify(WithScope(ctx, pr.Scope), pr.Token)
+		if errors.Contains(err, ErrKeyScopeNotAllowed) {
+			return "", reasonKey, nil
+		}
+		if err != nil {
+			return "", "", errors.Wrap(ErrUnauthorizedAccess, err)
+		}
+		orgID = key.OrgID
+		if key.IssuerID != pr.Subject {
+			return "", reasonKey, nil
+		}
+		// The keys can't reach the groups of the other organizations. The
+		// checks without the token, as well as the things and channels, aren't
+		// scoped to the organization.
+		err = svc.checkGroupOrg(ctx, key.OrgID, pr.Object)
+		if errors.Contains(err, ErrGroupNotFound) {
+			return "", reasonKey, nil
+		}
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	// Service accounts are allowed to perform only the scoped actions.
+	sa, err := svc.accounts.RetrieveByID(ctx, pr.Subject)
+	switch {
+	case err == nil:
+		if !contains(sa.Scopes, pr.Relation) {
+			return "", reasonScopes, nil
+		}
+	case !errors.Contains(err, ErrNotFound):
+		return "", "", err
+	}
+
+	return orgID, "", nil
+}
+
+func (svc service) AddPolicy(ctx context.Context, pr PolicyReq) error {
+	if pr.TTL < 0 {
+		return ErrMalformedEntity
+	}
+
+	ttl := pr.TTL
+	pr.TTL = 0
+	if err := svc.agent.AddPolicy(ctx, pr); err != nil {
+		return err
+	}
+
+	// Adding the policy without TTL makes the temporary policy permanent.
+	if ttl == 0 {
+		return svc.expirations.Remove(ctx, pr)
+	}
+
+	pe := PolicyExpiration{
+		Subject:   pr.Subject,
+		Object:    pr.Object,
+		Relation:  pr.Relation,
+		Namespace: pr.Namespace,
+		ExpiresAt: getTimestmap().Add(ttl),
+	}
+	return svc.expirations.Save(ctx, pe)
+}
+
+// revokeExpired deletes the policy matching the request if its deadline
+// has passed, so that the policy agent only checks the policies in effect.
+func (svc service) revokeExpired(ctx context.Context, pr PolicyReq) error {
+	expired, err := svc.expired(ctx, pr)
+	if err != nil || !expired {
+		return err
+	}
+
+	pr.TTL = 0
+	if err := svc.agent.DeletePolicy(ctx, pr); err != nil {
+		return err
+	}
+
+	return svc.expirations.Remove(ctx, pr)
+}
+
+// expired checks if the deadline of the policy matching the request has
+// passed.
+func (svc service) expired(ctx context.Context, pr PolicyReq) (bool, error) {
+	pe, err := svc.expirations.Retrieve(ctx, pr)
+	if errors.Contains(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return !getTimestmap().Before(pe.ExpiresAt), nil
+}
+
+func (svc service) AddPolicies(ctx context.Context, token, object string, subjectIDs, relations []string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		return err
+	}
+
+	var errs error
+	for _, subjectID := range subjectIDs {
+		for _, relation := range relations {
+			if err := svc.AddPolicy(ctx, PolicyReq{Object: object, Relation: relation, Subject: subjectID}); err != nil {
+				errs = errors.Wrap(fmt.Errorf("cannot add '%s' policy on object '%s' for subject '%s': %s", relation, object, subjectID, err), errs)
+			}
 		}
 	}
 	return errs
 }
 
-func (svc service) Unassign(ctx context.Context, token string, groupID string, memberIDs ...string) error {
-	if _, err := svc.Identify(ctx, token); err != nil {
+func (svc service) AddPolicyTriples(ctx context.Context, token string, prs []PolicyReq) ([]PolicyResult, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		return nil, err
+	}
+
+	res := make([]PolicyResult, len(prs))
+	for i, pr := range prs {
+		res[i] = PolicyResult{PolicyReq: pr, Err: svc.AddPolicy(ctx, pr)}
+	}
+	return res, nil
+}
+
+func (svc service) ApplyPolicyTemplate(ctx context.Context, e Entity) error {
+	prs, err := svc.templates.policies(e)
+	if err != nil {
+		return err
+	}
+
+	for i, pr := range prs {
+		if err := svc.AddPolicy(ctx, pr); err != nil {
+			for _, added := range prs[:i] {
+				svc.DeletePolicy(ctx, added)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc service) DeletePolicy(ctx context.Context, pr PolicyReq) error {
+	pr.TTL = 0
+	if err := svc.agent.DeletePolicy(ctx, pr); err != nil {
+		return err
+	}
+
+	return svc.expirations.Remove(ctx, pr)
+}
+
+func (svc service) DeletePolicies(ctx context.Context, token, object string, subjectIDs, relations []string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	ss := fmt.Sprintf("%s:%s#%s", "members", groupID, memberRelation)
+	// Check if the user identified by token is the admin.
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		return err
+	}
+
 	var errs error
-	for _, memberID := range memberIDs {
-		// If the member is a user, <groupID>#member@memberID must be deleted.
-		if err := svc.agent.DeletePolicy(ctx, PolicyReq{Object: groupID, Relation: memberRelation, Subject: memberID}); err != nil {
-			errs = errors.Wrap(fmt.Errorf("cannot delete a membership of member '%s' from group '%s'", memberID, groupID), errs)
+	for _, subjectID := range subjectIDs {
+		for _, relation := range relations {
+			if err := svc.DeletePolicy(ctx, PolicyReq{Object: object, Relation: relation, Subject: subjectID}); err != nil {
+				errs = errors.Wrap(fmt.Errorf("cannot delete '%s' policy on object '%s' for subject '%s': %s", relation, object, subjectID, err), errs)
+			}
 		}
+	}
+	return errs
+}
 
-		// If the member is a Thing, memberID#read|write|delete@(members:groupID#member) must be deleted.
-		for _, action := range []string{"read", "write", "delete"} {
-			if err := svc.agent.DeletePolicy(ctx, PolicyReq{Object: memberID, Relation: action, Subject: ss}); err != nil {
-				errs = errors.Wrap(fmt.Errorf("cannot delete '%s' policy from member '%s'", action, memberID), errs)
+func (svc service) ListAuditRecords(ctx context.Context, token string, filter AuditFilter, offset, limit uint64) (AuditPage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return AuditPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		return AuditPage{}, err
+	}
+
+	return svc.audit.RetrieveAll(ctx, filter, offset, limit)
+}
+
+func (svc service) AssignGroupAccessRights(ctx context.Context, token, thingGroupID, userGroupID string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	for _, id := range []string{thingGroupID, userGroupID} {
+		if err := svc.checkGroupOrg(ctx, user.OrgID, id); err != nil {
+			return err
+		}
+	}
+	return svc.agent.AddPolicy(ctx, PolicyReq{Object: thingGroupID, Relation: memberRelation, Subject: fmt.Sprintf("%s:%s#%s", "members", userGroupID, memberRelation)})
+}
+
+func (svc service) ListPolicies(ctx context.Context, pr PolicyReq) (PolicyPage, error) {
+	res, err := svc.agent.RetrievePolicies(ctx, pr)
+	if err != nil {
+		return PolicyPage{}, err
+	}
+	var page PolicyPage
+	for _, tuple := range res {
+		// Given the object alone, the subjects of the policies are listed.
+		if pr.Subject == "" && pr.Object != "" {
+			page.Policies = append(page.Policies, subjectString(tuple.GetSubject()))
+			continue
+		}
+		page.Policies = append(page.Policies, tuple.GetObject())
+	}
+
+	// The subtree admins act on the members of the managed groups, which
+	// are listed along with the objects of the policies.
+	if pr.Subject == "" || pr.Object != "" || ValidAdminRelation(pr.Relation) {
+		return page, nil
+	}
+	members, err := svc.managedMembers(ctx, pr.Subject)
+	if err != nil {
+		return PolicyPage{}, err
+	}
+	for _, id := range members {
+		if !contains(page.Policies, id) {
+			page.Policies = append(page.Policies, id)
+		}
+	}
+	return page, nil
+}
+
+func (svc service) InspectPolicies(ctx context.Context, token string, pr PolicyReq) ([]PolicyReq, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	// Users other than the admin can only inspect their own policies.
+	if pr.Subject != user.ID {
+		if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+			return nil, err
+		}
+	}
+
+	tuples, err := svc.agent.RetrievePolicies(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := []PolicyReq{}
+	for _, t := range tuples {
+		policies = append(policies, PolicyReq{
+			Subject:   subjectString(t.GetSubject()),
+			Object:    t.GetObject(),
+			Relation:  t.GetRelation(),
+			Namespace: t.GetNamespace(),
+		})
+	}
+	return policies, nil
+}
+
+func (svc service) ExplainPolicy(ctx context.Context, token string, pr PolicyReq) (Explanation, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Explanation{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	// Users other than the admin can only explain their own access.
+	if pr.Subject != user.ID {
+		if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+			return Explanation{}, err
+		}
+	}
+
+	// The user explaining their own access is checked with their key, the
+	// same way Authorize checks it.
+	if pr.Token == "" && pr.Subject == user.ID {
+		pr.Token = token
+	}
+	orgID, reason, err := svc.precheck(ctx, pr)
+	if err != nil {
+		return Explanation{}, err
+	}
+	if reason != "" {
+		return Explanation{Reason: reason}, nil
+	}
+
+	// Unlike Authorize, the explanation doesn't revoke the expired policy,
+	// but leaves it out of the decision.
+	expired, err := svc.expired(ctx, pr)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	expl, err := svc.explainPath(ctx, pr, expired)
+	if err != nil {
+		return Explanation{}, err
+	}
+	if expl.Allowed {
+		return expl, nil
+	}
+
+	// The policy agent may grant the access the path search can't see,
+	// e.g. through the subject sets nested deeper than the search goes.
+	// The agent still holds the expired policy, so it isn't asked then.
+	if !expired {
+		if err := svc.agent.CheckPolicy(ctx, pr); err == nil {
+			expl.Allowed = true
+			expl.Reason = reasonAgent
+			return expl, nil
+		}
+	}
+	if svc.managesMember(ctx, pr.Subject, orgID, pr.Object) {
+		expl.Allowed = true
+		expl.Reason = reasonSubtree
+		return expl, nil
+	}
+	expl.Reason = reasonNoPath
+	return expl, nil
+}
+
+// explainPath searches the subject sets, breadth first, for the path of
+// the policies from the object of the request to its subject. The direct
+// policy of the subject is skipped if it has expired.
+func (svc service) explainPath(ctx context.Context, pr PolicyReq, expired bool) (Explanation, error) {
+	type step struct {
+		set  PolicyReq
+		path []PolicyReq
+	}
+
+	var expl Explanation
+	visited := map[string]bool{}
+	steps := []step{{set: PolicyReq{Object: pr.Object, Relation: pr.Relation, Namespace: pr.Namespace}}}
+	for depth := 0; depth < maxExplainDepth && len(steps) > 0; depth++ {
+		var next []step
+		for _, s := range steps {
+			ns := s.set.Namespace
+			if ns == "" {
+				ns = MembersNamespace
+			}
+			set := fmt.Sprintf("%s:%s#%s", ns, s.set.Object, s.set.Relation)
+			if visited[set] {
+				continue
+			}
+			visited[set] = true
+			expl.Expanded = append(expl.Expanded, set)
+
+			tuples, err := svc.agent.RetrievePolicies(ctx, s.set)
+			if err != nil {
+				return Explanation{}, err
+			}
+			for _, t := range tuples {
+				policy := PolicyReq{
+					Subject:   subjectString(t.GetSubject()),
+					Object:    t.GetObject(),
+					Relation:  t.GetRelation(),
+					Namespace: t.GetNamespace(),
+				}
+				if expired && depth == 0 && policy.Subject == pr.Subject {
+					continue
+				}
+				path := append(append([]PolicyReq{}, s.path...), policy)
+				if policy.Subject == pr.Subject {
+					expl.Allowed = true
+					expl.Reason = reasonPath
+					expl.Path = path
+					return expl, nil
+				}
+				if ns, obj, rel, ok := splitSubjectSet(policy.Subject); ok {
+					next = append(next, step{set: PolicyReq{Object: obj, Relation: rel, Namespace: ns}, path: path})
+				}
 			}
 		}
+		steps = next
 	}
 
-	err := svc.groups.Unassign(ctx, groupID, memberIDs...)
-	return errors.Wrap(err, errs)
+	return expl, nil
 }
 
-func (svc service) ListMemberships(ctx context.Context, token string, memberID string, pm PageMetadata) (GroupPage, error) {
-	if _, err := svc.Identify(ctx, token); err != nil {
-		return GroupPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+func (svc service) tmpKey(duration time.Duration, key Key) (Key, string, error) {
+	// The ID makes the temporary key revocable.
+	if key.ID == "" {
+		id, err := svc.idProvider.ID()
+		if err != nil {
+			return Key{}, "", errors.Wrap(errIssueTmp, err)
+		}
+		key.ID = id
 	}
-	return svc.groups.Memberships(ctx, memberID, pm)
+	key.ExpiresAt = key.IssuedAt.Add(duration)
+	secret, err := svc.tokenizer.Issue(key)
+	if err != nil {
+		return Key{}, "", errors.Wrap(errIssueTmp, err)
+	}
+
+	return key, secret, nil
+}
+
+// sessionKey issues the temporary key, saving or extending the session the
+// key belongs to.
+func (svc service) sessionKey(ctx context.Context, duration time.Duration, key Key) (Key, string, error) {
+	key, secret, err := svc.tmpKey(duration, key)
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	sid := key.Claims[SessionClaim]
+	if sid == "" {
+		return key, secret, nil
+	}
+	device := UserAgent(ctx)
+	if len(device) > maxDeviceLength {
+		device = device[:maxDeviceLength]
+	}
+	s := Session{
+		ID:         sid,
+		UserID:     key.IssuerID,
+		Device:     device,
+		IP:         ClientIP(ctx),
+		IssuedAt:   key.IssuedAt,
+		LastSeenAt: key.IssuedAt,
+		ExpiresAt:  key.ExpiresAt,
+	}
+	if err := svc.sessions.Save(ctx, s); err != nil {
+		return Key{}, "", errors.Wrap(errIssueTmp, err)
+	}
+	return key, secret, nil
+}
+
+func (svc service) userKey(ctx context.Context, token string, key Key) (Key, string, error) {
+	user, err := svc.login(ctx, token)
+	if err != nil {
+		return Key{}, "", errors.Wrap(errIssueUser, err)
+	}
+
+	key.IssuerID = user.IssuerID
+	if key.Subject == "" {
+		key.Subject = user.Subject
+	}
+	// The API keys are scoped to the organization of the User key.
+	key.OrgID = user.OrgID
+
+	keyID, err := svc.idProvider.ID()
+	if err != nil {
+		return Key{}, "", errors.Wrap(errIssueUser, err)
+	}
+	key.ID = keyID
+
+	if _, err := svc.keys.Save(ctx, key); err != nil {
+		return Key{}, "", errors.Wrap(errIssueUser, err)
+	}
+
+	secret, err := svc.tokenizer.Issue(key)
+	if err != nil {
+		return Key{}, "", errors.Wrap(errIssueUser, err)
+	}
+
+	return key, secret, nil
+}
+
+// login identifies the user managing the keys, which is allowed using the
+// User keys only.
+func (svc service) login(ctx context.Context, token string) (Key, error) {
+	key, err := svc.identify(ctx, token)
+	if err != nil {
+		return Key{}, err
+	}
+	// Only user key token is valid for login.
+	if key.Type != UserKey || key.IssuerID == "" {
+		return Key{}, ErrUnauthorizedAccess
+	}
+
+	return key, nil
+}
+
+// checkOrgMember returns an error if the key is scoped to the organization
+// its issuer isn't a member of anymore.
+func (svc service) checkOrgMember(ctx context.Context, key Key) error {
+	if key.OrgID == "" {
+		return nil
+	}
+	if err := svc.orgs.IsMember(ctx, key.OrgID, key.IssuerID); err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	return nil
+}
+
+func (svc service) CreateGroup(ctx context.Context, token string, group Group) (Group, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Group{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	ulid, err := svc.ulidProvider.ID()
+	if err != nil {
+		return Group{}, errors.Wrap(ErrGenerateGroupID, err)
+	}
+
+	timestamp := getTimestmap()
+	group.UpdatedAt = timestamp
+	group.CreatedAt = timestamp
+
+	group.ID = ulid
+	group.OwnerID = user.ID
+	group.OrgID = user.OrgID
+
+	if group.ParentID != "" {
+		if err := svc.checkGroupOrg(ctx, user.OrgID, group.ParentID); err != nil {
+			return Group{}, errors.Wrap(ErrCreateGroup, err)
+		}
+		// The missing parent is reported by the repository.
+		if parent, err := svc.groups.RetrieveByID(ctx, group.ParentID); err == nil {
+			if err := svc.authorizeGroup(ctx, user, parent, CreateRelation); err != nil {
+				return Group{}, err
+			}
+		}
+	}
+
+	group, err = svc.groups.Save(ctx, group)
+	if err != nil {
+		return Group{}, err
+	}
+
+	if err := svc.agent.AddPolicy(ctx, PolicyReq{Object: group.ID, Relation: memberRelation, Subject: user.ID}); err != nil {
+		return Group{}, err
+	}
+
+	return group, nil
+}
+
+func (svc service) ListGroups(ctx context.Context, token string, pm PageMetadata) (GroupPage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return GroupPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	pm.OrgID = user.OrgID
+	return svc.groups.RetrieveAll(ctx, pm)
+}
+
+func (svc service) ListParents(ctx context.Context, token string, childID string, pm PageMetadata) (GroupPage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return GroupPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if _, err := svc.group(ctx, user.OrgID, childID); err != nil {
+		return GroupPage{}, err
+	}
+	pm.OrgID = user.OrgID
+	return svc.groups.RetrieveAllParents(ctx, childID, pm)
+}
+
+func (svc service) ListChildren(ctx context.Context, token string, parentID string, pm PageMetadata) (GroupPage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return GroupPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if _, err := svc.group(ctx, user.OrgID, parentID); err != nil {
+		return GroupPage{}, err
+	}
+	pm.OrgID = user.OrgID
+	return svc.groups.RetrieveAllChildren(ctx, parentID, pm)
+}
+
+func (svc service) ListMembers(ctx context.Context, token string, groupID, groupType string, pm PageMetadata) (MemberPage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return MemberPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if _, err := svc.group(ctx, user.OrgID, groupID); err != nil {
+		return MemberPage{}, err
+	}
+	if pm.Relation != "" && !ValidRelation(pm.Relation) {
+		return MemberPage{}, ErrMalformedEntity
+	}
+	mp, err := svc.groups.Members(ctx, groupID, groupType, pm)
+	if err != nil {
+		return MemberPage{}, errors.Wrap(ErrFailedToRetrieveMembers, err)
+	}
+	return mp, nil
+}
+
+func (svc service) RemoveGroup(ctx context.Context, token, id string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if _, err := svc.group(ctx, user.OrgID, id); err != nil {
+		return err
+	}
+	return svc.groups.Delete(ctx, id)
+}
+
+func (svc service) UpdateGroup(ctx context.Context, token string, group Group) (Group, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Group{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if _, err := svc.group(ctx, user.OrgID, group.ID); err != nil {
+		return Group{}, err
+	}
+
+	group.UpdatedAt = getTimestmap()
+	return svc.groups.Update(ctx, group)
+}
+
+func (svc service) ViewGroup(ctx context.Context, token, id string) (Group, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Group{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	return svc.group(ctx, user.OrgID, id)
+}
+
+func (svc service) DelegateGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	group, err := svc.group(ctx, user.OrgID, groupID)
+	if err != nil {
+		return err
+	}
+	if err := svc.authorizeGroup(ctx, user, group, ""); err != nil {
+		return err
+	}
+
+	if !validAdminRelations(relations) {
+		return ErrMalformedEntity
+	}
+	// Only the members of the organization can administer its groups.
+	if group.OrgID != "" {
+		if err := svc.orgs.IsMember(ctx, group.OrgID, userID); err != nil {
+			return err
+		}
+	}
+
+	for _, rel := range relations {
+		if err := svc.agent.AddPolicy(ctx, PolicyReq{Object: groupID, Relation: rel, Subject: userID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc service) RevokeGroupAdmin(ctx context.Context, token, groupID, userID string, relations ...string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	group, err := svc.group(ctx, user.OrgID, groupID)
+	if err != nil {
+		return err
+	}
+	if err := svc.authorizeGroup(ctx, user, group, ""); err != nil {
+		return err
+	}
+
+	if !validAdminRelations(relations) {
+		return ErrMalformedEntity
+	}
+
+	return svc.deletePolicies(ctx, userID, groupID, relations)
+}
+
+func (svc service) ListGroupAdmins(ctx context.Context, token, groupID string) ([]PolicyReq, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if _, err := svc.group(ctx, user.OrgID, groupID); err != nil {
+		return nil, err
+	}
+
+	admins := []PolicyReq{}
+	for _, rel := range []string{InviteRelation, CreateRelation, AdminRelation} {
+		tuples, err := svc.agent.RetrievePolicies(ctx, PolicyReq{Object: groupID, Relation: rel})
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range tuples {
+			admins = append(admins, PolicyReq{
+				Subject:  subjectString(t.GetSubject()),
+				Object:   t.GetObject(),
+				Relation: t.GetRelation(),
+			})
+		}
+	}
+	return admins, nil
+}
+
+// authorizeGroup checks if the user is the owner of the group, was delegated
+// the admin relation on the group, or is the admin. The empty relation
+// leaves out the delegated admins.
+func (svc service) authorizeGroup(ctx context.Context, user Identity, group Group, relation string) error {
+	if group.OwnerID == user.ID {
+		return nil
+	}
+	if relation != "" {
+		if err := svc.Authorize(ctx, PolicyReq{Object: group.ID, Relation: relation, Subject: user.ID}); err == nil {
+			return nil
+		}
+		if svc.administersGroup(ctx, user.ID, group.ID) {
+			return nil
+		}
+	}
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		return ErrAuthorization
+	}
+	return nil
+}
+
+// administersGroup checks if the user is the admin of the subtree of the
+// group, i.e. holds the admin relation on the group or on its ancestors.
+func (svc service) administersGroup(ctx context.Context, userID, groupID string) bool {
+	page, err := svc.groups.RetrieveAllParents(ctx, groupID, PageMetadata{Level: MaxLevel})
+	if err != nil {
+		return false
+	}
+	for _, g := range page.Groups {
+		if err := svc.agent.CheckPolicy(ctx, PolicyReq{Object: g.ID, Relation: AdminRelation, Subject: userID}); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// managesMember checks if the user is the admin of the subtree of any of
+// the groups of the organization the member is assigned to.
+func (svc service) managesMember(ctx context.Context, userID, orgID, memberID string) bool {
+	if userID == "" || memberID == "" {
+		return false
+	}
+	page, err := svc.groups.Memberships(ctx, memberID, PageMetadata{OrgID: orgID, Limit: maxMemberships})
+	if err != nil {
+		return false
+	}
+	for _, g := range page.Groups {
+		if svc.administersGroup(ctx, userID, g.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// managedMembers lists the IDs of the users, things and channels assigned to
+// the groups of the subtrees the user is the admin of.
+func (svc service) managedMembers(ctx context.Context, userID string) ([]string, error) {
+	tuples, err := svc.agent.RetrievePolicies(ctx, PolicyReq{Subject: userID, Relation: AdminRelation})
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, t := range tuples {
+		page, err := svc.groups.RetrieveAllChildren(ctx, t.GetObject(), PageMetadata{Level: MaxLevel})
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range page.Groups {
+			mp, err := svc.groups.Members(ctx, g.ID, "", PageMetadata{Limit: maxOrgUsers})
+			if err != nil {
+				return nil, err
+			}
+			for _, m := range mp.Members {
+				if !contains(ids, m.ID) {
+					ids = append(ids, m.ID)
+				}
+			}
+		}
+	}
+	return ids, nil
+}
+
+// checkGroupOrg returns an error if the group belongs to the other
+// organization. The missing groups, including the objects which aren't
+// groups at all, are left to the caller.
+func (svc service) checkGroupOrg(ctx context.Context, orgID, id string) error {
+	g, err := svc.groups.RetrieveByID(ctx, id)
+	switch {
+	case errors.Contains(err, ErrGroupNotFound):
+		return nil
+	case err != nil:
+		return err
+	case g.OrgID != orgID:
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+// group retrieves the group of the organization, so the groups of the other
+// organizations are reported as missing.
+func (svc service) group(ctx context.Context, orgID, id string) (Group, error) {
+	g, err := svc.groups.RetrieveByID(ctx, id)
+	if err != nil {
+		return Group{}, err
+	}
+	if g.OrgID != orgID {
+		return Group{}, ErrGroupNotFound
+	}
+	return g, nil
+}
+
+func (svc service) Assign(ctx context.Context, token string, groupID, groupType string, memberIDs ...string) error {
+	identity, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	group, err := svc.group(ctx, identity.OrgID, groupID)
+	if err != nil {
+		return err
+	}
+	if err := svc.authorizeGroup(ctx, identity, group, InviteRelation); err != nil {
+		return err
+	}
+
+	if groupType == UsersResource {
+		// Only the members of the organization can join its groups.
+		if group.OrgID != "" {
+			for _, memberID := range memberIDs {
+				if err := svc.orgs.IsMember(ctx, group.OrgID, memberID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	m := Membership{Relation: ViewerRelation, CreatedBy: identity.ID}
+	if err := svc.groups.Assign(ctx, groupID, groupType, m, memberIDs...); err != nil {
+		return err
+	}
+
+	if groupType == thingsGroupType {
+		ss := fmt.Sprintf("%s:%s#%s", "members", groupID, memberRelation)
+		var errs error
+		for _, memberID := range memberIDs {
+			for _, action := range []string{"read", "write", "delete"} {
+				if err := svc.agent.AddPolicy(ctx, PolicyReq{Object: memberID, Relation: action, Subject: ss}); err != nil {
+					errs = errors.Wrap(fmt.Errorf("cannot add thing: '%s' to thing group: '%s'", memberID, groupID), errs)
+				}
+			}
+		}
+		return errs
+	}
+
+	var errs error
+	for _, memberID := range memberIDs {
+		if err := svc.agent.AddPolicy(ctx, PolicyReq{Object: groupID, Relation: memberRelation, Subject: memberID}); err != nil {
+			errs = errors.Wrap(fmt.Errorf("cannot add user: '%s' to user group: '%s'", memberID, groupID), errs)
+		}
+	}
+	return errs
+}
+
+func (svc service) Unassign(ctx context.Context, token string, groupID string, memberIDs ...string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	group, err := svc.group(ctx, user.OrgID, groupID)
+	if err != nil {
+		return err
+	}
+	if err := svc.authorizeGroup(ctx, user, group, InviteRelation); err != nil {
+		return err
+	}
+
+	return svc.unassign(ctx, groupID, memberIDs...)
+}
+
+func (svc service) AssignTemporary(ctx context.Context, token, groupID, groupType string, expiresAt time.Time, memberIDs ...string) error {
+	if !expiresAt.After(getTimestmap()) {
+		return ErrMalformedEntity
+	}
+
+	if err := svc.Assign(ctx, token, groupID, groupType, memberIDs...); err != nil {
+		return err
+	}
+
+	return svc.groups.SetExpiration(ctx, groupID, expiresAt, memberIDs...)
+}
+
+func (svc service) SetRelation(ctx context.Context, token, groupID, relation string, memberIDs ...string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	group, err := svc.group(ctx, user.OrgID, groupID)
+	if err != nil {
+		return err
+	}
+	if err := svc.authorizeGroup(ctx, user, group, InviteRelation); err != nil {
+		return err
+	}
+
+	if !ValidRelation(relation) {
+		return ErrMalformedEntity
+	}
+
+	return svc.groups.SetRelation(ctx, groupID, relation, memberIDs...)
+}
+
+func (svc service) RevokeExpiredMemberships(ctx context.Context) error {
+	expired, err := svc.groups.RetrieveExpired(ctx, getTimestmap())
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, me := range expired {
+		if err := svc.unassign(ctx, me.GroupID, me.MemberID); err != nil {
+			errs = errors.Wrap(fmt.Errorf("cannot revoke membership of member '%s' in group '%s': %s", me.MemberID, me.GroupID, err), errs)
+		}
+	}
+	return errs
+}
+
+// unassign removes the members from the group along with the policies
+// derived from their membership.
+func (svc service) unassign(ctx context.Context, groupID string, memberIDs ...string) error {
+	ss := fmt.Sprintf("%s:%s#%s", "members", groupID, memberRelation)
+	var errs error
+	for _, memberID := range memberIDs {
+		// If the member is a user, <groupID>#member@memberID must be deleted.
+		if err := svc.agent.DeletePolicy(ctx, PolicyReq{Object: groupID, Relation: memberRelation, Subject: memberID}); err != nil {
+			errs = errors.Wrap(fmt.Errorf("cannot delete a membership of member '%s' from group '%s'", memberID, groupID), errs)
+		}
+
+		// If the member is a Thing, memberID#read|write|delete@(members:groupID#member) must be deleted.
+		for _, action := range []string{"read", "write", "delete"} {
+			if err := svc.agent.DeletePolicy(ctx, PolicyReq{Object: memberID, Relation: action, Subject: ss}); err != nil {
+				errs = errors.Wrap(fmt.Errorf("cannot delete '%s' policy from member '%s'", action, memberID), errs)
+			}
+		}
+	}
+
+	err := svc.groups.Unassign(ctx, groupID, memberIDs...)
+	return errors.Wrap(err, errs)
+}
+
+func (svc service) ListMemberships(ctx context.Context, token string, memberID string, pm PageMetadata) (GroupPage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return GroupPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	pm.OrgID = user.OrgID
+	return svc.groups.Memberships(ctx, memberID, pm)
+}
+
+func (svc service) CreateOrg(ctx context.Context, token string, o Org) (Org, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Org{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	// The keys scoped to the organization can't reach the other ones.
+	if user.OrgID != "" {
+		return Org{}, ErrAuthorization
+	}
+	if o.Name == "" {
+		return Org{}, ErrMalformedEntity
+	}
+
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		return Org{}, err
+	}
+
+	timestamp := getTimestmap()
+	o.ID = id
+	o.OwnerID = user.ID
+	o.CreatedAt = timestamp
+	o.UpdatedAt = timestamp
+
+	if err := svc.orgs.Save(ctx, o); err != nil {
+		return Org{}, err
+	}
+	return o, nil
+}
+
+func (svc service) ViewOrg(ctx context.Context, token, id string) (Org, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Org{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	return svc.org(ctx, user, id, false)
+}
+
+func (svc service) UpdateOrg(ctx context.Context, token string, o Org) (Org, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Org{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if o.Name == "" {
+		return Org{}, ErrMalformedEntity
+	}
+
+	org, err := svc.org(ctx, user, o.ID, true)
+	if err != nil {
+		return Org{}, err
+	}
+
+	org.Name = o.Name
+	org.Description = o.Description
+	org.Metadata = o.Metadata
+	org.UpdatedAt = getTimestmap()
+	if err := svc.orgs.Update(ctx, org); err != nil {
+		return Org{}, err
+	}
+	return org, nil
+}
+
+func (svc service) RemoveOrg(ctx context.Context, token, id string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if _, err := svc.org(ctx, user, id, true); err != nil {
+		return err
+	}
+
+	// The groups are removed first, so no entity outlives its organization.
+	gp, err := svc.groups.RetrieveAll(ctx, PageMetadata{OrgID: id, Limit: 1})
+	if err != nil {
+		return err
+	}
+	if gp.Total > 0 {
+		return ErrOrgNotEmpty
+	}
+
+	return svc.orgs.Remove(ctx, id)
+}
+
+func (svc service) ListOrgs(ctx context.Context, token string, offset, limit uint64) (OrgsPage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return OrgsPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	// The keys scoped to the organization only see their own organization.
+	if user.OrgID != "" {
+		org, err := svc.org(ctx, user, user.OrgID, false)
+		if err != nil {
+			return OrgsPage{}, err
+		}
+		page := OrgsPage{Total: 1, Offset: offset, Limit: limit}
+		if offset == 0 && limit > 0 {
+			page.Orgs = []Org{org}
+		}
+		return page, nil
+	}
+
+	return svc.orgs.RetrieveByMember(ctx, user.ID, offset, limit)
+}
+
+func (svc service) AssignOrgMembers(ctx context.Context, token, orgID string, memberIDs ...string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if len(memberIDs) == 0 {
+		return ErrMalformedEntity
+	}
+	if _, err := svc.org(ctx, user, orgID, true); err != nil {
+		return err
+	}
+	if err := svc.checkUsersQuota(ctx, orgID, len(memberIDs)); err != nil {
+		return err
+	}
+
+	return svc.orgs.AssignMembers(ctx, orgID, user.ID, memberIDs...)
+}
+
+func (svc service) UnassignOrgMembers(ctx context.Context, token, orgID string, memberIDs ...string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if len(memberIDs) == 0 {
+		return ErrMalformedEntity
+	}
+	org, err := svc.org(ctx, user, orgID, true)
+	if err != nil {
+		return err
+	}
+	// The organization can't be left without its owner.
+	if contains(memberIDs, org.OwnerID) {
+		return ErrMalformedEntity
+	}
+
+	// The policies granted by the groups of the organization don't outlive
+	// the membership in the organization.
+	for _, memberID := range memberIDs {
+		if err := svc.unassignOrgGroups(ctx, orgID, memberID); err != nil {
+			return err
+		}
+	}
+
+	return svc.orgs.UnassignMembers(ctx, orgID, memberIDs...)
+}
+
+func (svc service) ListOrgMembers(ctx context.Context, token, orgID string, offset, limit uint64) (OrgMembersPage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return OrgMembersPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if _, err := svc.org(ctx, user, orgID, false); err != nil {
+		return OrgMembersPage{}, err
+	}
+
+	return svc.orgs.RetrieveMembers(ctx, orgID, offset, limit)
+}
+
+func (svc service) SwitchOrg(ctx context.Context, token, orgID string) (Key, string, error) {
+	user, err := svc.login(ctx, token)
+	if err != nil {
+		return Key{}, "", errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if err := svc.checkRevoked(ctx, user); err != nil {
+		return Key{}, "", err
+	}
+
+	if orgID != "" {
+		if err := svc.orgs.IsMember(ctx, orgID, user.IssuerID); err != nil {
+			return Key{}, "", err
+		}
+	}
+
+	key := Key{
+		Type:     UserKey,
+		IssuerID: user.IssuerID,
+		Subject:  user.Subject,
+		OrgID:    orgID,
+		IssuedAt: getTimestmap(),
+	}
+	return svc.tmpKey(loginDuration, key)
+}
+
+// org retrieves the organization the user is a member of, or the owner of
+// if the owner is required. The organizations out of the reach of the user
+// are reported as missing.
+func (svc service) org(ctx context.Context, user Identity, id string, owner bool) (Org, error) {
+	if user.OrgID != "" && user.OrgID != id {
+		return Org{}, ErrNotFound
+	}
+	if err := svc.orgs.IsMember(ctx, id, user.ID); err != nil {
+		return Org{}, errors.Wrap(ErrNotFound, err)
+	}
+
+	org, err := svc.orgs.RetrieveByID(ctx, id)
+	if err != nil {
+		return Org{}, err
+	}
+	if owner && org.OwnerID != user.ID {
+		return Org{}, ErrAuthorization
+	}
+	return org, nil
+}
+
+// unassignOrgGroups removes the member from all the groups of the
+// organization.
+func (svc service) unassignOrgGroups(ctx context.Context, orgID, memberID string) error {
+	var ids []string
+	pm := PageMetadata{OrgID: orgID, Limit: 100}
+	for {
+		gp, err := svc.groups.Memberships(ctx, memberID, pm)
+		if err != nil {
+			return err
+		}
+		for _, g := range gp.Groups {
+			ids = append(ids, g.ID)
+		}
+		pm.Offset += pm.Limit
+		if len(gp.Groups) == 0 || pm.Offset >= gp.Total {
+			break
+		}
+	}
+
+	for _, id := range ids {
+		if err := svc.unassign(ctx, id, memberID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc service) Share(ctx context.Context, token string, s Share) (Share, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Share{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if s.Object == "" || s.GranteeID == "" || s.GranteeID == user.ID || len(s.Actions) == 0 {
+		return Share{}, ErrMalformedEntity
+	}
+
+	// Users can share only the actions they are allowed to perform, while
+	// the admin can share any action.
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		for _, action := range s.Actions {
+			if err := svc.Authorize(ctx, PolicyReq{Object: s.Object, Relation: action, Subject: user.ID}); err != nil {
+				return Share{}, err
+			}
+		}
+	}
+
+	existing, err := svc.shares.RetrieveByObject(ctx, s.GranteeID, s.Object)
+	if err != nil {
+		return Share{}, err
+	}
+	shared := sharedActions(existing)
+
+	// The actions the grantee is already allowed to perform are not part of
+	// the share, unless they are granted by another share, so revoking the
+	// share doesn't remove the access the grantee had before.
+	var actions, added []string
+	for _, action := range s.Actions {
+		pr := PolicyReq{Object: s.Object, Relation: action, Subject: s.GranteeID}
+		if err := svc.Authorize(ctx, pr); err == nil {
+			if shared[action] {
+				actions = append(actions, action)
+			}
+			continue
+		}
+		if err := svc.agent.AddPolicy(ctx, pr); err != nil {
+			svc.deletePolicies(ctx, s.GranteeID, s.Object, added)
+			return Share{}, err
+		}
+		actions = append(actions, action)
+		added = append(added, action)
+	}
+	if len(actions) == 0 {
+		return Share{}, ErrConflict
+	}
+
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		svc.deletePolicies(ctx, s.GranteeID, s.Object, added)
+		return Share{}, err
+	}
+	s.ID = id
+	s.GrantorID = user.ID
+	s.Actions = actions
+	s.CreatedAt = getTimestmap()
+
+	if err := svc.shares.Save(ctx, s); err != nil {
+		svc.deletePolicies(ctx, s.GranteeID, s.Object, added)
+		return Share{}, err
+	}
+
+	return s, nil
+}
+
+func (svc service) ListShares(ctx context.Context, token string, received bool) ([]Share, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if received {
+		return svc.shares.RetrieveByGrantee(ctx, user.ID)
+	}
+	return svc.shares.RetrieveByGrantor(ctx, user.ID)
+}
+
+func (svc service) RevokeShare(ctx context.Context, token, id string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	s, err := svc.shares.RetrieveByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if s.GrantorID != user.ID && s.GranteeID != user.ID {
+		if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+			return err
+		}
+	}
+
+	if err := svc.shares.Remove(ctx, id); err != nil {
+		return err
+	}
+
+	// The policies granted by the remaining shares of the object are kept.
+	remaining, err := svc.shares.RetrieveByObject(ctx, s.GranteeID, s.Object)
+	if err != nil {
+		return err
+	}
+	shared := sharedActions(remaining)
+
+	var actions []string
+	for _, action := range s.Actions {
+		if !shared[action] {
+			actions = append(actions, action)
+		}
+	}
+
+	return svc.deletePolicies(ctx, s.GranteeID, s.Object, actions)
+}
+
+func (svc service) CreateRole(ctx context.Context, token string, r Role) (Role, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Role{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if r.Name == "" || !validScopes(r.Relations) {
+		return Role{}, ErrMalformedEntity
+	}
+
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		return Role{}, err
+	}
+
+	if _, ok := builtinRole(r.Name); ok {
+		return Role{}, ErrConflict
+	}
+
+	r.Builtin = false
+	r.CreatedAt = getTimestmap()
+	if err := svc.roles.Save(ctx, r); err != nil {
+		return Role{}, err
+	}
+
+	return r, nil
+}
+
+func (svc service) ListRoles(ctx context.Context, token string) ([]Role, error) {
+	if _, err := svc.Identify(ctx, token); err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	custom, err := svc.roles.RetrieveAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append([]Role{}, builtinRoles...), custom...), nil
+}
+
+func (svc service) RemoveRole(ctx context.Context, token, name string) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		return err
+	}
+
+	if _, ok := builtinRole(name); ok {
+		return ErrMalformedEntity
+	}
+
+	if _, err := svc.roles.RetrieveByName(ctx, name); err != nil {
+		return err
+	}
+
+	assignments, err := svc.roles.RetrieveAssignments(ctx, RoleAssignment{Role: name})
+	if err != nil {
+		return err
+	}
+	if len(assignments) > 0 {
+		return ErrConflict
+	}
+
+	return svc.roles.Remove(ctx, name)
+}
+
+func (svc service) AssignRole(ctx context.Context, token string, ra RoleAssignment) (RoleAssignment, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return RoleAssignment{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if ra.Subject == "" || ra.Object == "" {
+		return RoleAssignment{}, ErrMalformedEntity
+	}
+
+	role, err := svc.role(ctx, ra.Role)
+	if err != nil {
+		return RoleAssignment{}, err
+	}
+
+	if err := svc.authorizeRole(ctx, user, ra.Object, role); err != nil {
+		return RoleAssignment{}, err
+	}
+
+	existing, err := svc.roles.RetrieveAssignments(ctx, RoleAssignment{Subject: ra.Subject, Object: ra.Object})
+	if err != nil {
+		return RoleAssignment{}, err
+	}
+	for _, a := range existing {
+		if a.Role == role.Name {
+			return RoleAssignment{}, ErrConflict
+		}
+	}
+	assigned := assignedRelations(existing)
+
+	// As with the shares, the relations the subject already has are not
+	// part of the assignment, unless they are added by another assignment,
+	// so unassigning the role doesn't remove the access the subject had.
+	var relations, added []string
+	for _, rel := range role.Relations {
+		pr := PolicyReq{Object: ra.Object, Relation: rel, Subject: ra.Subject}
+		if err := svc.Authorize(ctx, pr); err == nil {
+			if assigned[rel] {
+				relations = append(relations, rel)
+			}
+			continue
+		}
+		if err := svc.agent.AddPolicy(ctx, pr); err != nil {
+			svc.deletePolicies(ctx, ra.Subject, ra.Object, added)
+			return RoleAssignment{}, err
+		}
+		relations = append(relations, rel)
+		added = append(added, rel)
+	}
+
+	ra.Role = role.Name
+	ra.Relations = relations
+	ra.AssignedBy = user.ID
+	ra.CreatedAt = getTimestmap()
+	if err := svc.roles.SaveAssignment(ctx, ra); err != nil {
+		svc.deletePolicies(ctx, ra.Subject, ra.Object, added)
+		return RoleAssignment{}, err
+	}
+
+	return ra, nil
+}
+
+func (svc service) ListRoleAssignments(ctx context.Context, token string, ra RoleAssignment) ([]RoleAssignment, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	// Users other than the admin can only list their own assignments.
+	if ra.Subject != user.ID {
+		if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+			if ra.Subject != "" {
+				return nil, err
+			}
+			ra.Subject = user.ID
+		}
+	}
+
+	return svc.roles.RetrieveAssignments(ctx, ra)
+}
+
+func (svc service) UnassignRole(ctx context.Context, token string, ra RoleAssignment) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if ra.Role == "" || ra.Subject == "" || ra.Object == "" {
+		return ErrMalformedEntity
+	}
+
+	existing, err := svc.roles.RetrieveAssignments(ctx, ra)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return ErrNotFound
+	}
+	ra = existing[0]
+
+	role, err := svc.role(ctx, ra.Role)
+	if err != nil {
+		return err
+	}
+	if err := svc.authorizeRole(ctx, user, ra.Object, role); err != nil {
+		return err
+	}
+
+	if err := svc.roles.RemoveAssignment(ctx, ra); err != nil {
+		return err
+	}
+
+	// The relations added by the remaining assignments of the object are kept.
+	remaining, err := svc.roles.RetrieveAssignments(ctx, RoleAssignment{Subject: ra.Subject, Object: ra.Object})
+	if err != nil {
+		return err
+	}
+	assigned := assignedRelations(remaining)
+
+	var relations []string
+	for _, rel := range ra.Relations {
+		if !assigned[rel] {
+			relations = append(relations, rel)
+		}
+	}
+
+	return svc.deletePolicies(ctx, ra.Subject, ra.Object, relations)
+}
+
+// role returns the builtin or the custom role with the given name.
+func (svc service) role(ctx context.Context, name string) (Role, error) {
+	if r, ok := builtinRole(name); ok {
+		return r, nil
+	}
+	return svc.roles.RetrieveByName(ctx, name)
+}
+
+// authorizeRole checks if the user has all the role relations on the
+// object, or is the admin.
+func (svc service) authorizeRole(ctx context.Context, user Identity, object string, role Role) error {
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err == nil {
+		return nil
+	}
+	for _, rel := range role.Relations {
+		if err := svc.Authorize(ctx, PolicyReq{Object: object, Relation: rel, Subject: user.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (svc service) CreateServiceAccount(ctx context.Context, token string, sa ServiceAccount) (ServiceAccount, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return ServiceAccount{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if sa.Name == "" || sa.OwnerID == "" || !validScopes(sa.Scopes) {
+		return ServiceAccount{}, ErrMalformedEntity
+	}
+
+	if err := svc.authorizeOwner(ctx, user, sa.OwnerID); err != nil {
+		return ServiceAccount{}, err
+	}
+
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		return ServiceAccount{}, err
+	}
+	sa.ID = id
+	sa.CreatedBy = user.ID
+	sa.CreatedAt = getTimestmap()
+
+	if err := svc.accounts.Save(ctx, sa); err != nil {
+		return ServiceAccount{}, err
+	}
+
+	return sa, nil
+}
+
+func (svc service) ListServiceAccounts(ctx context.Context, token, ownerID string) ([]ServiceAccount, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if err := svc.authorizeOwner(ctx, user, ownerID); err != nil {
+		return nil, err
+	}
+
+	return svc.accounts.RetrieveByOwner(ctx, ownerID)
+}
+
+func (svc service) ViewServiceAccount(ctx context.Context, token, id string) (ServiceAccount, error) {
+	return svc.serviceAccount(ctx, token, id, true)
+}
+
+func (svc service) UpdateServiceAccountScopes(ctx context.Context, token, id string, scopes []string) (ServiceAccount, error) {
+	if !validScopes(scopes) {
+		return ServiceAccount{}, ErrMalformedEntity
+	}
+
+	sa, err := svc.serviceAccount(ctx, token, id, false)
+	if err != nil {
+		return ServiceAccount{}, err
+	}
+
+	if err := svc.accounts.UpdateScopes(ctx, id, scopes); err != nil {
+		return ServiceAccount{}, err
+	}
+	sa.Scopes = scopes
+
+	return sa, nil
+}
+
+func (svc service) DisableServiceAccount(ctx context.Context, token, id string) error {
+	return svc.changeAccountStatus(ctx, token, id, true)
+}
+
+func (svc service) EnableServiceAccount(ctx context.Context, token, id string) error {
+	return svc.changeAccountStatus(ctx, token, id, false)
+}
+
+func (svc service) changeAccountStatus(ctx context.Context, token, id string, disabled bool) error {
+	if _, err := svc.serviceAccount(ctx, token, id, false); err != nil {
+		return err
+	}
+
+	return svc.accounts.UpdateStatus(ctx, id, disabled)
+}
+
+func (svc service) RemoveServiceAccount(ctx context.Context, token, id string) error {
+	if _, err := svc.serviceAccount(ctx, token, id, false); err != nil {
+		return err
+	}
+
+	return svc.accounts.Remove(ctx, id)
+}
+
+func (svc service) IssueServiceAccountKey(ctx context.Context, token, id string, duration time.Duration) (Key, string, error) {
+	if duration < 0 {
+		return Key{}, "", ErrMalformedEntity
+	}
+
+	sa, err := svc.serviceAccount(ctx, token, id, false)
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	return svc.accountKey(ctx, sa, duration)
+}
+
+func (svc service) RotateServiceAccountKey(ctx context.Context, token, id, keyID string) (Key, string, error) {
+	// The service account is allowed to rotate its own keys, so the
+	// pipelines can refresh the key before it expires.
+	sa, err := svc.serviceAccount(ctx, token, id, true)
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	old, err := svc.keys.Retrieve(ctx, sa.ID, keyID)
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	var duration time.Duration
+	if !old.ExpiresAt.IsZero() {
+		duration = old.ExpiresAt.Sub(old.IssuedAt)
+	}
+
+	key, secret, err := svc.accountKey(ctx, sa, duration)
+	if err != nil {
+		return Key{}, "", err
+	}
+
+	if err := svc.keys.Remove(ctx, sa.ID, keyID); err != nil {
+		return Key{}, "", errors.Wrap(errRevoke, err)
+	}
+
+	return key, secret, nil
+}
+
+func (svc service) RevokeServiceAccountKey(ctx context.Context, token, id, keyID string) error {
+	sa, err := svc.serviceAccount(ctx, token, id, true)
+	if err != nil {
+		return err
+	}
+
+	if err := svc.keys.Remove(ctx, sa.ID, keyID); err != nil {
+		return errors.Wrap(errRevoke, err)
+	}
+	return nil
+}
+
+// serviceAccount retrieves the service account managed by the user
+// identified by the token. If self is set, the service account identified
+// by the token is allowed to manage itself as well.
+func (svc service) serviceAccount(ctx context.Context, token, id string, self bool) (ServiceAccount, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return ServiceAccount{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	sa, err := svc.accounts.RetrieveByID(ctx, id)
+	if err != nil {
+		return ServiceAccount{}, err
+	}
+
+	if self && user.ID == sa.ID {
+		return sa, nil
+	}
+
+	if err := svc.authorizeOwner(ctx, user, sa.OwnerID); err != nil {
+		return ServiceAccount{}, err
+	}
+
+	return sa, nil
+}
+
+// authorizeOwner checks if the user is the member of the group owning the
+// service accounts, or the admin.
+func (svc service) authorizeOwner(ctx context.Context, user Identity, ownerID string) error {
+	if err := svc.Authorize(ctx, PolicyReq{Object: ownerID, Relation: memberRelation, Subject: user.ID}); err != nil {
+		return svc.authorizeAdmin(ctx, user.ID, user.OrgID)
+	}
+	return nil
+}
+
+// authorizeAdmin checks if the user is the admin. The keys scoped to the
+// organization can't act as the admin, since the admin reaches the entities
+// of all the organizations.
+func (svc service) authorizeAdmin(ctx context.Context, userID, orgID string) error {
+	if orgID != "" {
+		return ErrAuthorization
+	}
+	return svc.Authorize(ctx, PolicyReq{Object: authoritiesObject, Relation: memberRelation, Subject: userID})
+}
+
+func (svc service) accountKey(ctx context.Context, sa ServiceAccount, duration time.Duration) (Key, string, error) {
+	keyID, err := svc.idProvider.ID()
+	if err != nil {
+		return Key{}, "", errors.Wrap(errIssueUser, err)
+	}
+
+	key := Key{
+		ID:       keyID,
+		Type:     ServiceAccountKey,
+		IssuerID: sa.ID,
+		Subject:  sa.ID,
+		IssuedAt: getTimestmap(),
+	}
+	if duration > 0 {
+		key.ExpiresAt = key.IssuedAt.Add(duration)
+	}
+
+	if _, err := svc.keys.Save(ctx, key); err != nil {
+		return Key{}, "", errors.Wrap(errIssueUser, err)
+	}
+
+	secret, err := svc.tokenizer.Issue(key)
+	if err != nil {
+		return Key{}, "", errors.Wrap(errIssueUser, err)
+	}
+
+	return key, secret, nil
+}
+
+func (svc service) SetQuota(ctx context.Context, token string, q Quota) error {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if q.OrgID == "" || q.MessageRate < 0 {
+		return ErrMalformedEntity
+	}
+
+	if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+		return err
+	}
+
+	if _, err := svc.orgs.RetrieveByID(ctx, q.OrgID); err != nil {
+		return err
+	}
+
+	q.UpdatedAt = getTimestmap()
+	return svc.quotas.Save(ctx, q)
+}
+
+func (svc service) ViewQuota(ctx context.Context, token, orgID string) (Quota, Usage, error) {
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return Quota{}, Usage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	// Members see the quota of their organization, admins see all of them.
+	if _, err := svc.org(ctx, user, orgID, false); err != nil {
+		if err := svc.authorizeAdmin(ctx, user.ID, user.OrgID); err != nil {
+			return Quota{}, Usage{}, err
+		}
+	}
+
+	q, err := svc.quotas.RetrieveByOrg(ctx, orgID)
+	if err != nil {
+		return Quota{}, Usage{}, err
+	}
+
+	u, err := svc.quotas.Usage(ctx, orgID)
+	if err != nil {
+		return Quota{}, Usage{}, err
+	}
+	if u.Users, err = svc.orgUsers(ctx, orgID); err != nil {
+		return Quota{}, Usage{}, err
+	}
+
+	return q, u, nil
+}
+
+func (svc service) ReserveQuota(ctx context.Context, token, resource string, ids ...string) error {
+	if resource == MessagesResource {
+		return svc.reserveMessages(ctx, ids...)
+	}
+
+	if resource != ThingsResource && resource != ChannelsResource {
+		return ErrMalformedEntity
+	}
+
+	user, err := svc.Identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	// Resources are charged only to the organization the key is scoped to.
+	if user.OrgID == "" {
+		return nil
+	}
+
+	q, err := svc.quotas.RetrieveByOrg(ctx, user.OrgID)
+	if errors.Contains(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	limit, _ := q.Limit(resource)
+	return svc.quotas.Charge(ctx, user.OrgID, resource, limit, ids...)
+}
+
+func (svc service) ReleaseQuota(ctx context.Context, resource string, ids ...string) error {
+	if resource != ThingsResource && resource != ChannelsResource {
+		return ErrMalformedEntity
+	}
+
+	return svc.quotas.Release(ctx, resource, ids...)
+}
+
+// reserveMessages checks the message rate of the organizations the
+// publishing things are charged to.
+func (svc service) reserveMessages(ctx context.Context, thingIDs ...string) error {
+	for _, thingID := range thingIDs {
+		orgs, err := svc.quotas.RetrieveOrgs(ctx, ThingsResource, thingID)
+		if err != nil {
+			return err
+		}
+
+		for _, orgID := range orgs {
+			q, err := svc.quotas.RetrieveByOrg(ctx, orgID)
+			if errors.Contains(err, ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			if q.MessageRate > 0 && !svc.limiters.allow(orgID, q.MessageRate) {
+				return errors.Wrap(ErrQuotaExceeded, fmt.Errorf("organization %s allows at most %g messages per second", orgID, q.MessageRate))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (svc service) checkUsersQuota(ctx context.Context, orgID string, n int) error {
+	q, err := svc.quotas.RetrieveByOrg(ctx, orgID)
+	if errors.Contains(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	limit, ok := q.Limit(UsersResource)
+	if !ok {
+		return nil
+	}
+
+	users, err := svc.orgUsers(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if users+uint64(n) > limit {
+		return errors.Wrap(ErrQuotaExceeded, fmt.Errorf("organization %s allows at most %d users", orgID, limit))
+	}
+
+	return nil
+}
+
+func (svc service) orgUsers(ctx context.Context, orgID string) (uint64, error) {
+	mp, err := svc.orgs.RetrieveMembers(ctx, orgID, 0, 1)
+	if err != nil {
+		return 0, err
+	}
+	return mp.Total, nil
+}
+
+func (svc service) deletePolicies(ctx context.Context, subject, object string, actions []string) error {
+	var errs error
+	for _, action := range actions {
+		if err := svc.agent.DeletePolicy(ctx, PolicyReq{Object: object, Relation: action, Subject: subject}); err != nil {
+			errs = errors.Wrap(fmt.Errorf("cannot delete '%s' policy on object '%s' for subject '%s': %s", action, object, subject, err), errs)
+		}
+	}
+	return errs
+}
+
+func sharedActions(shares []Share) map[string]bool {
+	ret := make(map[string]bool)
+	for _, s := range shares {
+		for _, action := range s.Actions {
+			ret[action] = true
+		}
+	}
+	return ret
+}
+
+func assignedRelations(assignments []RoleAssignment) map[string]bool {
+	ret := make(map[string]bool)
+	for _, ra := range assignments {
+		for _, rel := range ra.Relations {
+			ret[rel] = true
+		}
+	}
+	return ret
+}
+
+func validAdminRelations(relations []string) bool {
+	if len(relations) == 0 {
+		return false
+	}
+	for _, rel := range relations {
+		if !ValidAdminRelation(rel) {
+			return false
+		}
+	}
+	return true
+}
+
+func validNetworks(networks []string) bool {
+	for _, n := range networks {
+		if _, err := ParseNetwork(n); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func validClaims(claims map[string]string) bool {
+	if len(claims) > maxKeyClaims {
+		return false
+	}
+	for name, val := range claims {
+		if name == "" || len(name) > maxKeyLabelLength || len(val) > maxKeyLabelLength {
+			return false
+		}
+	}
+	return true
+}
+
+func validKeyScopes(scopes []string) bool {
+	for _, scope := range scopes {
+		if _, _, ok := splitScope(scope); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func validScopes(scopes []string) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	for _, scope := range scopes {
+		if scope == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
 }
 
 func getTimestmap() time.Time {
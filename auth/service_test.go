@@ -41,7 +41,7 @@ func newService() auth.Service {
 	ketoMock := mocks.NewKetoMock(mockAuthzDB)
 
 	t := jwt.New(secret)
-	return auth.New(repo, groupRepo, idProvider, t, ketoMock)
+	return auth.New(repo, groupRepo, mocks.NewOrgRepository(), mocks.NewShareRepository(), mocks.NewRoleRepository(), mocks.NewServiceAccountRepository(), mocks.NewQuotaRepository(), mocks.NewPolicyExpirationRepository(), mocks.NewRevocationRepository(), mocks.NewSessionRepository(), mocks.NewAuditRepository(), idProvider, t, ketoMock, nil)
 }
 
 func TestIssue(t *testing.T) {
@@ -98,6 +98,56 @@ func TestIssue(t *testing.T) {
 			token: secret,
 			err:   auth.ErrInvalidKeyIssuedAt,
 		},
+		{
+			desc: "issue scoped API key",
+			key: auth.Key{
+				Type:     auth.APIKey,
+				IssuedAt: time.Now(),
+				Scopes:   []string{"things:read", "messages:*"},
+			},
+			token: secret,
+			err:   nil,
+		},
+		{
+			desc: "issue API key with malformed scope",
+			key: auth.Key{
+				Type:     auth.APIKey,
+				IssuedAt: time.Now(),
+				Scopes:   []string{"things"},
+			},
+			token: secret,
+			err:   auth.ErrMalformedEntity,
+		},
+		{
+			desc: "issue scoped user key",
+			key: auth.Key{
+				Type:     auth.UserKey,
+				IssuedAt: time.Now(),
+				Scopes:   []string{"things:read"},
+			},
+			token: secret,
+			err:   auth.ErrMalformedEntity,
+		},
+		{
+			desc: "issue API key with claims",
+			key: auth.Key{
+				Type:     auth.APIKey,
+				IssuedAt: time.Now(),
+				Claims:   map[string]string{"device_id": "dev-1", "tenant": "acme"},
+			},
+			token: secret,
+			err:   nil,
+		},
+		{
+			desc: "issue API key with unnamed claim",
+			key: auth.Key{
+				Type:     auth.APIKey,
+				IssuedAt: time.Now(),
+				Claims:   map[string]string{"": "dev-1"},
+			},
+			token: secret,
+			err:   auth.ErrMalformedEntity,
+		},
 		{
 			desc: "issue recovery key",
 			key: auth.Key{
@@ -168,407 +218,468 @@ func TestRevoke(t *testing.T) {
 	}
 }
 
-func TestRetrieve(t *testing.T) {
+func TestRevokeToken(t *testing.T) {
 	svc := newService()
-	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), Subject: email, IssuerID: id})
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
-	}
 
-	_, userToken, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
-	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+	otherID := "other"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
 
-	apiKey, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+	_, apiToken, err := svc.Issue(context.Background(), secret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's API key expected to succeed: %s", err))
 
-	_, resetToken, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.RecoveryKey, IssuedAt: time.Now()})
-	assert.Nil(t, err, fmt.Sprintf("Issuing reset key expected to succeed: %s", err))
+	_, otherAPIToken, err := svc.Issue(context.Background(), otherSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's API key expected to succeed: %s", err))
+
+	_, expiredToken, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now().Add(-24 * time.Hour), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing expired login key expected to succeed: %s", err))
 
 	cases := []struct {
-		desc  string
-		id    string
-		token string
-		err   error
+		desc    string
+		token   string
+		revoked string
+		err     error
 	}{
 		{
-			desc:  "retrieve user key",
-			id:    apiKey.ID,
-			token: userToken,
-			err:   nil,
+			desc:    "revoke token with invalid token",
+			token:   "invalid",
+			revoked: apiToken,
+			err:     auth.ErrUnauthorizedAccess,
 		},
 		{
-			desc:  "retrieve non-existing user key",
-			id:    "invalid",
-			token: userToken,
-			err:   auth.ErrNotFound,
+			desc:    "revoke malformed token",
+			token:   secret,
+			revoked: "invalid",
+			err:     auth.ErrMalformedEntity,
 		},
 		{
-			desc:  "retrieve unauthorized",
-			id:    apiKey.ID,
-			token: "wrong",
-			err:   auth.ErrUnauthorizedAccess,
+			desc:    "revoke other user's token as non-admin",
+			token:   otherSecret,
+			revoked: apiToken,
+			err:     auth.ErrAuthorization,
 		},
 		{
-			desc:  "retrieve with API token",
-			id:    apiKey.ID,
-			token: apiToken,
-			err:   auth.ErrUnauthorizedAccess,
+			desc:    "revoke expired token",
+			token:   otherSecret,
+			revoked: expiredToken,
+			err:     nil,
 		},
 		{
-			desc:  "retrieve with reset token",
-			id:    apiKey.ID,
-			token: resetToken,
-			err:   auth.ErrUnauthorizedAccess,
+			desc:    "revoke own token",
+			token:   secret,
+			revoked: apiToken,
+			err:     nil,
+		},
+		{
+			desc:    "revoke already revoked token",
+			token:   secret,
+			revoked: apiToken,
+			err:     nil,
+		},
+		{
+			desc:    "revoke other user's token as admin",
+			token:   secret,
+			revoked: otherAPIToken,
+			err:     nil,
+		},
+		{
+			desc:    "revoke token using revoked token",
+			token:   otherAPIToken,
+			revoked: otherSecret,
+			err:     auth.ErrUnauthorizedAccess,
 		},
 	}
 
 	for _, tc := range cases {
-		_, err := svc.RetrieveKey(context.Background(), tc.token, tc.id)
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s expected %s got %s\n", tc.desc, tc.err, err))
+		err := svc.RevokeToken(context.Background(), tc.token, tc.revoked)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
-}
 
-func TestIdentify(t *testing.T) {
-	svc := newService()
+	for _, token := range []string{apiToken, otherAPIToken} {
+		_, err := svc.Identify(context.Background(), token)
+		assert.True(t, errors.Contains(err, auth.ErrKeyRevoked), fmt.Sprintf("identifying revoked token: expected %s got %s\n", auth.ErrKeyRevoked, err))
+	}
 
-	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
-	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+	_, loginToken, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+	err = svc.RevokeToken(context.Background(), secret, loginToken)
+	require.Nil(t, err, fmt.Sprintf("Revoking login key expected to succeed: %s", err))
 
-	_, recoverySecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.RecoveryKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
-	assert.Nil(t, err, fmt.Sprintf("Issuing reset key expected to succeed: %s", err))
+	_, _, err = svc.Issue(context.Background(), loginToken, auth.Key{Type: auth.APIKey, IssuedAt: time.Now()})
+	assert.True(t, errors.Contains(err, auth.ErrKeyRevoked), fmt.Sprintf("issuing API key using revoked login key: expected %s got %s\n", auth.ErrKeyRevoked, err))
+	_, err = svc.ListKeys(context.Background(), loginToken, 0, 10)
+	assert.True(t, errors.Contains(err, auth.ErrKeyRevoked), fmt.Sprintf("listing keys using revoked login key: expected %s got %s\n", auth.ErrKeyRevoked, err))
+}
 
-	_, apiSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuerID: id, Subject: email, IssuedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)})
-	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+func TestRevokeKeys(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	exp1 := time.Now().Add(-2 * time.Second)
-	_, expSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), ExpiresAt: exp1})
-	assert.Nil(t, err, fmt.Sprintf("Issuing expired user key expected to succeed: %s", err))
+	// The keys issued in the second of the revocation remain valid, so the
+	// revoked keys are issued in the past.
+	issuedAt := time.Now().Add(-time.Minute)
+	otherID := "other"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: issuedAt, IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
 
-	_, invalidSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: 22, IssuedAt: time.Now()})
-	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+	_, otherAPIToken, err := svc.Issue(context.Background(), otherSecret, auth.Key{Type: auth.APIKey, IssuedAt: issuedAt, IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's API key expected to succeed: %s", err))
 
 	cases := []struct {
-		desc string
-		key  string
-		idt  auth.Identity
-		err  error
+		desc    string
+		token   string
+		subject string
+		count   uint64
+		err     error
 	}{
 		{
-			desc: "identify login key",
-			key:  loginSecret,
-			idt:  auth.Identity{id, email},
-			err:  nil,
+			desc:    "revoke keys with invalid token",
+			token:   "invalid",
+			subject: otherID,
+			err:     auth.ErrUnauthorizedAccess,
 		},
 		{
-			desc: "identify recovery key",
-			key:  recoverySecret,
-			idt:  auth.Identity{id, email},
-			err:  nil,
+			desc:    "revoke keys without subject",
+			token:   secret,
+			subject: "",
+			err:     auth.ErrMalformedEntity,
 		},
 		{
-			desc: "identify API key",
-			key:  apiSecret,
-			idt:  auth.Identity{id, email},
-			err:  nil,
+			desc:    "revoke other user's keys as non-admin",
+			token:   otherSecret,
+			subject: id,
+			err:     auth.ErrAuthorization,
 		},
 		{
-			desc: "identify expired API key",
-			key:  expSecret,
-			idt:  auth.Identity{},
-			err:  auth.ErrAPIKeyExpired,
+			desc:    "revoke other user's keys as admin",
+			token:   secret,
+			subject: otherID,
+			count:   1,
+			err:     nil,
 		},
 		{
-			desc: "identify expired key",
-			key:  invalidSecret,
-			idt:  auth.Identity{},
-			err:  auth.ErrUnauthorizedAccess,
+			desc:    "revoke already revoked keys",
+			token:   secret,
+			subject: otherID,
+			count:   0,
+			err:     nil,
 		},
 		{
-			desc: "identify invalid key",
-			key:  "invalid",
-			idt:  auth.Identity{},
-			err:  auth.ErrUnauthorizedAccess,
+			desc:    "revoke keys using revoked token",
+			token:   otherSecret,
+			subject: otherID,
+			err:     auth.ErrUnauthorizedAccess,
 		},
 	}
 
 	for _, tc := range cases {
-		idt, err := svc.Identify(context.Background(), tc.key)
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s expected %s got %s\n", tc.desc, tc.err, err))
-		assert.Equal(t, tc.idt, idt, fmt.Sprintf("%s expected %s got %s\n", tc.desc, tc.idt, idt))
+		count, err := svc.RevokeKeys(context.Background(), tc.token, tc.subject)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.count, count, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.count, count))
+	}
+
+	for _, token := range []string{otherSecret, otherAPIToken} {
+		_, err := svc.Identify(context.Background(), token)
+		assert.True(t, errors.Contains(err, auth.ErrKeyRevoked), fmt.Sprintf("identifying revoked token: expected %s got %s\n", auth.ErrKeyRevoked, err))
 	}
+
+	_, newSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+	_, err = svc.Identify(context.Background(), newSecret)
+	assert.Nil(t, err, fmt.Sprintf("identifying key issued after revocation expected to succeed: %s", err))
 }
 
-func TestCreateGroup(t *testing.T) {
+func TestIntrospect(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
-	}
+	otherID := "other"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
 
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+	key, apiToken, err := svc.Issue(context.Background(), secret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), IssuerID: id, Subject: email, Scopes: []string{"things:read"}})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's API key expected to succeed: %s", err))
 
-	group := auth.Group{
-		Name:        "Group",
-		Description: description,
+	_, revokedToken, err := svc.Issue(context.Background(), secret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's API key expected to succeed: %s", err))
+	err = svc.RevokeToken(context.Background(), secret, revokedToken)
+	assert.Nil(t, err, fmt.Sprintf("Revoking user's API key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc         string
+		token        string
+		introspected string
+		active       bool
+		err          error
+	}{
+		{
+			desc:         "introspect with invalid token",
+			token:        "invalid",
+			introspected: apiToken,
+			err:          auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:         "introspect own token",
+			token:        secret,
+			introspected: apiToken,
+			active:       true,
+		},
+		{
+			desc:         "introspect other user's token as admin",
+			token:        secret,
+			introspected: otherSecret,
+			active:       true,
+		},
+		{
+			desc:         "introspect other user's token as non-admin",
+			token:        otherSecret,
+			introspected: apiToken,
+			active:       false,
+		},
+		{
+			desc:         "introspect revoked token",
+			token:        secret,
+			introspected: revokedToken,
+			active:       false,
+		},
+		{
+			desc:         "introspect malformed token",
+			token:        secret,
+			introspected: "invalid",
+			active:       false,
+		},
 	}
 
-	parentGroup := auth.Group{
-		Name:        "ParentGroup",
-		Description: description,
+	for _, tc := range cases {
+		in, err := svc.Introspect(context.Background(), tc.token, tc.introspected)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.active, in.Active, fmt.Sprintf("%s: expected active %t got %t\n", tc.desc, tc.active, in.Active))
 	}
 
-	parent, err := svc.CreateGroup(context.Background(), apiToken, parentGroup)
-	assert.Nil(t, err, fmt.Sprintf("Creating parent group expected to succeed: %s", err))
+	in, err := svc.Introspect(context.Background(), secret, apiToken)
+	assert.Nil(t, err, fmt.Sprintf("Introspecting API key expected to succeed: %s", err))
+	assert.Equal(t, key.ID, in.Key.ID, fmt.Sprintf("introspecting API key: expected ID %s got %s\n", key.ID, in.Key.ID))
+	assert.Equal(t, key.Scopes, in.Key.Scopes, fmt.Sprintf("introspecting API key: expected scopes %v got %v\n", key.Scopes, in.Key.Scopes))
+}
 
-	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: parent.ID, Relation: memberRelation, Subject: id})
-	assert.Nil(t, err, fmt.Sprintf("Checking parent group owner's policy expected to succeed: %s", err))
+func TestRefresh(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	_, refresh, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.RefreshKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing refresh key expected to succeed: %s", err))
+
+	_, used, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.RefreshKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing refresh key expected to succeed: %s", err))
+	_, _, err = svc.Refresh(context.Background(), used)
+	assert.Nil(t, err, fmt.Sprintf("Refreshing key expected to succeed: %s", err))
+
+	_, expired, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.RefreshKey, IssuedAt: time.Now().Add(-30 * 24 * time.Hour), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing expired refresh key expected to succeed: %s", err))
 
 	cases := []struct {
 		desc  string
-		group auth.Group
+		token string
 		err   error
 	}{
 		{
-			desc:  "create new group",
-			group: group,
+			desc:  "refresh with refresh token",
+			token: refresh,
 			err:   nil,
 		},
 		{
-			desc:  "create group with existing name",
-			group: group,
-			err:   nil,
+			desc:  "refresh with used refresh token",
+			token: used,
+			err:   auth.ErrKeyRevoked,
 		},
 		{
-			desc: "create group with parent",
-			group: auth.Group{
-				Name:     groupName,
-				ParentID: parent.ID,
-			},
-			err: nil,
+			desc:  "refresh with expired refresh token",
+			token: expired,
+			err:   auth.ErrUnauthorizedAccess,
 		},
 		{
-			desc: "create group with invalid parent",
-			group: auth.Group{
-				Name:     groupName,
-				ParentID: "xxxxxxxxxx",
-			},
-			err: auth.ErrCreateGroup,
+			desc:  "refresh with login token",
+			token: secret,
+			err:   auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "refresh with invalid token",
+			token: "invalid",
+			err:   auth.ErrUnauthorizedAccess,
 		},
 	}
 
 	for _, tc := range cases {
-		g, err := svc.CreateGroup(context.Background(), apiToken, tc.group)
+		token, newRefresh, err := svc.Refresh(context.Background(), tc.token)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
-
-		if err == nil {
-			authzErr := svc.Authorize(context.Background(), auth.PolicyReq{Object: g.ID, Relation: memberRelation, Subject: g.OwnerID})
-			assert.Nil(t, authzErr, fmt.Sprintf("%s - Checking group owner's policy expected to succeed: %s", tc.desc, authzErr))
+		if tc.err != nil {
+			continue
 		}
+
+		_, err = svc.Identify(context.Background(), token)
+		assert.Nil(t, err, fmt.Sprintf("%s: identifying refreshed key expected to succeed: %s", tc.desc, err))
+		_, err = svc.Identify(context.Background(), newRefresh)
+		assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("%s: identifying refresh key expected to fail: %s", tc.desc, err))
+		_, _, err = svc.Refresh(context.Background(), newRefresh)
+		assert.Nil(t, err, fmt.Sprintf("%s: refreshing with new refresh key expected to succeed: %s", tc.desc, err))
 	}
 }
 
-func TestUpdateGroup(t *testing.T) {
-	svc := newService()
-	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
-	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+func login(t *testing.T, svc auth.Service, session, device string) (string, string) {
+	ctx := auth.WithUserAgent(auth.WithClientIP(context.Background(), "10.0.0.1"), device)
+	claims := map[string]string{auth.SessionClaim: session}
 
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
-	}
+	_, token, err := svc.Issue(ctx, "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email, Claims: claims})
+	require.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+	_, refresh, err := svc.Issue(ctx, "", auth.Key{Type: auth.RefreshKey, IssuedAt: time.Now(), IssuerID: id, Subject: email, Claims: claims})
+	require.Nil(t, err, fmt.Sprintf("Issuing refresh key expected to succeed: %s", err))
+	return token, refresh
+}
 
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+func TestListSessions(t *testing.T) {
+	svc := newService()
+	token, _ := login(t, svc, "laptop", "Firefox")
+	login(t, svc, "phone", "Safari")
 
-	group := auth.Group{
-		Name:        "Group",
-		Description: description,
-		Metadata: auth.GroupMetadata{
-			"field": "value",
-		},
-	}
+	_, plain, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+	_, apiToken, err := svc.Issue(context.Background(), token, auth.Key{Type: auth.APIKey, IssuedAt: time.Now()})
+	require.Nil(t, err, fmt.Sprintf("Issuing API key expected to succeed: %s", err))
 
-	group, err = svc.CreateGroup(context.Background(), apiToken, group)
-	assert.Nil(t, err, fmt.Sprintf("Creating parent group failed: %s", err))
+	_, _, err = svc.Issue(context.Background(), token, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), Claims: map[string]string{auth.SessionClaim: "laptop"}})
+	assert.True(t, errors.Contains(err, auth.ErrMalformedEntity), fmt.Sprintf("issuing API key with session: expected %s got %s\n", auth.ErrMalformedEntity, err))
 
 	cases := []struct {
-		desc  string
-		group auth.Group
-		err   error
+		desc    string
+		token   string
+		devices map[string]string
+		current string
+		err     error
 	}{
 		{
-			desc: "update group",
-			group: auth.Group{
-				ID:          group.ID,
-				Name:        "NewName",
-				Description: "NewDescription",
-				Metadata: auth.GroupMetadata{
-					"field": "value2",
-				},
-			},
-			err: nil,
+			desc:    "list sessions",
+			token:   token,
+			devices: map[string]string{"laptop": "Firefox", "phone": "Safari"},
+			current: "laptop",
+			err:     nil,
+		},
+		{
+			desc:    "list sessions with key without session",
+			token:   plain,
+			devices: map[string]string{"laptop": "Firefox", "phone": "Safari"},
+			err:     nil,
+		},
+		{
+			desc:  "list sessions with API key",
+			token: apiToken,
+			err:   auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "list sessions with invalid token",
+			token: "invalid",
+			err:   auth.ErrUnauthorizedAccess,
 		},
 	}
 
 	for _, tc := range cases {
-		g, err := svc.UpdateGroup(context.Background(), apiToken, tc.group)
+		sessions, err := svc.ListSessions(context.Background(), tc.token)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
-		assert.Equal(t, g.ID, tc.group.ID, fmt.Sprintf("ID: expected %s got %s\n", g.ID, tc.group.ID))
-		assert.Equal(t, g.Name, tc.group.Name, fmt.Sprintf("Name: expected %s got %s\n", g.Name, tc.group.Name))
-		assert.Equal(t, g.Description, tc.group.Description, fmt.Sprintf("Description: expected %s got %s\n", g.Description, tc.group.Description))
-		assert.Equal(t, g.Metadata["field"], g.Metadata["field"], fmt.Sprintf("Metadata: expected %s got %s\n", g.Metadata, tc.group.Metadata))
+		if tc.err != nil {
+			continue
+		}
+		assert.Equal(t, len(tc.devices), len(sessions), fmt.Sprintf("%s: expected %d sessions got %d\n", tc.desc, len(tc.devices), len(sessions)))
+		for _, s := range sessions {
+			assert.Equal(t, tc.devices[s.ID], s.Device, fmt.Sprintf("%s: expected device %s got %s\n", tc.desc, tc.devices[s.ID], s.Device))
+			assert.Equal(t, "10.0.0.1", s.IP, fmt.Sprintf("%s: expected IP %s got %s\n", tc.desc, "10.0.0.1", s.IP))
+			assert.Equal(t, tc.current == s.ID, s.Current, fmt.Sprintf("%s: unexpected current flag of session %s\n", tc.desc, s.ID))
+		}
 	}
-
 }
 
-func TestViewGroup(t *testing.T) {
+func TestRevokeSession(t *testing.T) {
 	svc := newService()
-	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
-	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
-
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
-	}
-
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
-
-	group := auth.Group{
-		Name:        "Group",
-		Description: description,
-		Metadata: auth.GroupMetadata{
-			"field": "value",
-		},
-	}
-
-	group, err = svc.CreateGroup(context.Background(), apiToken, group)
-	assert.Nil(t, err, fmt.Sprintf("Creating parent group failed: %s", err))
+	token, _ := login(t, svc, "laptop", "Firefox")
+	phoneToken, phoneRefresh := login(t, svc, "phone", "Safari")
 
 	cases := []struct {
-		desc    string
-		token   string
-		groupID string
-		err     error
+		desc  string
+		token string
+		id    string
+		err   error
 	}{
 		{
-
-			desc:    "view group",
-			token:   apiToken,
-			groupID: group.ID,
-			err:     nil,
+			desc:  "revoke session",
+			token: token,
+			id:    "phone",
+			err:   nil,
 		},
 		{
-			desc:    "view group with unauthorized token",
-			token:   "wrongtoken",
-			groupID: group.ID,
-			err:     auth.ErrUnauthorizedAccess,
+			desc:  "revoke revoked session",
+			token: token,
+			id:    "phone",
+			err:   auth.ErrNotFound,
 		},
 		{
-			desc:    "view group for wrong id",
-			token:   apiToken,
-			groupID: "wrong",
-			err:     auth.ErrGroupNotFound,
+			desc:  "revoke non-existing session",
+			token: token,
+			id:    "invalid",
+			err:   auth.ErrNotFound,
+		},
+		{
+			desc:  "revoke session with invalid token",
+			token: "invalid",
+			id:    "laptop",
+			err:   auth.ErrUnauthorizedAccess,
 		},
 	}
 
 	for _, tc := range cases {
-		_, err := svc.ViewGroup(context.Background(), tc.token, tc.groupID)
+		err := svc.RevokeSession(context.Background(), tc.token, tc.id)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
+
+	_, err := svc.Identify(context.Background(), phoneToken)
+	assert.True(t, errors.Contains(err, auth.ErrKeyRevoked), fmt.Sprintf("identifying key of revoked session: expected %s got %s\n", auth.ErrKeyRevoked, err))
+	_, _, err = svc.Refresh(context.Background(), phoneRefresh)
+	assert.True(t, errors.Contains(err, auth.ErrKeyRevoked), fmt.Sprintf("refreshing key of revoked session: expected %s got %s\n", auth.ErrKeyRevoked, err))
+	_, err = svc.Identify(context.Background(), token)
+	assert.Nil(t, err, fmt.Sprintf("identifying key of active session expected to succeed: %s", err))
 }
 
-func TestListGroups(t *testing.T) {
+func TestRevokeOtherSessions(t *testing.T) {
 	svc := newService()
-	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
-	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
-
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
-	}
-
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
-
-	group := auth.Group{
-		Description: description,
-		Metadata: auth.GroupMetadata{
-			"field": "value",
-		},
-	}
-	n := uint64(10)
-	parentID := ""
-	for i := uint64(0); i < n; i++ {
-		group.Name = fmt.Sprintf("Group%d", i)
-		group.ParentID = parentID
-		g, err := svc.CreateGroup(context.Background(), apiToken, group)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
-		parentID = g.ID
-	}
+	token, refresh := login(t, svc, "laptop", "Firefox")
+	phoneToken, _ := login(t, svc, "phone", "Safari")
+	tabletToken, _ := login(t, svc, "tablet", "Chrome")
 
-	cases := map[string]struct {
-		token    string
-		level    uint64
-		size     uint64
-		metadata auth.GroupMetadata
-		err      error
-	}{
-		"list all groups": {
-			token: apiToken,
-			level: 5,
-			size:  n,
-			err:   nil,
-		},
-		"list groups for level 1": {
-			token: apiToken,
-			level: 1,
-			size:  n,
-			err:   nil,
-		},
-		"list all groups with wrong token": {
-			token: "wrongToken",
-			level: 5,
-			size:  0,
-			err:   auth.ErrUnauthorizedAccess,
-		},
-	}
+	n, err := svc.RevokeOtherSessions(context.Background(), token)
+	assert.Nil(t, err, fmt.Sprintf("revoking other sessions expected to succeed: %s", err))
+	assert.Equal(t, uint64(2), n, fmt.Sprintf("expected %d revoked sessions got %d\n", 2, n))
 
-	for desc, tc := range cases {
-		page, err := svc.ListGroups(context.Background(), tc.token, auth.PageMetadata{Level: tc.level, Metadata: tc.metadata})
-		size := uint64(len(page.Groups))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, revoked := range []string{phoneToken, tabletToken} {
+		_, err := svc.Identify(context.Background(), revoked)
+		assert.True(t, errors.Contains(err, auth.ErrKeyRevoked), fmt.Sprintf("identifying key of revoked session: expected %s got %s\n", auth.ErrKeyRevoked, err))
 	}
 
+	// The refreshed keys remain in the current session.
+	token, _, err = svc.Refresh(context.Background(), refresh)
+	require.Nil(t, err, fmt.Sprintf("refreshing key of current session expected to succeed: %s", err))
+	sessions, err := svc.ListSessions(context.Background(), token)
+	assert.Nil(t, err, fmt.Sprintf("listing sessions expected to succeed: %s", err))
+	require.Equal(t, 1, len(sessions), fmt.Sprintf("expected %d sessions got %d\n", 1, len(sessions)))
+	assert.True(t, sessions[0].Current, "expected the remaining session to be current")
+	assert.Equal(t, "Firefox", sessions[0].Device, fmt.Sprintf("expected device %s got %s\n", "Firefox", sessions[0].Device))
 }
 
-func TestListChildren(t *testing.T) {
+func TestRetrieve(t *testing.T) {
 	svc := newService()
-	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), Subject: email, IssuerID: id})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
-
 	key := auth.Key{
 		ID:       "id",
 		Type:     auth.APIKey,
@@ -577,124 +688,274 @@ func TestListChildren(t *testing.T) {
 		IssuedAt: time.Now(),
 	}
 
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	_, userToken, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	apiKey, apiToken, err := svc.Issue(context.Background(), secret, key)
 	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
-	group := auth.Group{
-		Description: description,
-		Metadata: auth.GroupMetadata{
-			"field": "value",
-		},
-	}
-	n := uint64(10)
-	parentID := ""
-	groupIDs := make([]string, n)
-	for i := uint64(0); i < n; i++ {
-		group.Name = fmt.Sprintf("Group%d", i)
-		group.ParentID = parentID
-		g, err := svc.CreateGroup(context.Background(), apiToken, group)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
-		parentID = g.ID
-		groupIDs[i] = g.ID
-	}
+	_, resetToken, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.RecoveryKey, IssuedAt: time.Now()})
+	assert.Nil(t, err, fmt.Sprintf("Issuing reset key expected to succeed: %s", err))
 
-	cases := map[string]struct {
-		token    string
-		level    uint64
-		size     uint64
-		id       string
-		metadata auth.GroupMetadata
-		err      error
+	cases := []struct {
+		desc  string
+		id    string
+		token string
+		err   error
 	}{
-		"list all children": {
-			token: apiToken,
-			level: 5,
-			id:    groupIDs[0],
-			size:  n,
+		{
+			desc:  "retrieve user key",
+			id:    apiKey.ID,
+			token: userToken,
 			err:   nil,
 		},
-		"list all groups with wrong token": {
-			token: "wrongToken",
-			level: 5,
-			size:  0,
+		{
+			desc:  "retrieve non-existing user key",
+			id:    "invalid",
+			token: userToken,
+			err:   auth.ErrNotFound,
+		},
+		{
+			desc:  "retrieve unauthorized",
+			id:    apiKey.ID,
+			token: "wrong",
+			err:   auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "retrieve with API token",
+			id:    apiKey.ID,
+			token: apiToken,
+			err:   auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "retrieve with reset token",
+			id:    apiKey.ID,
+			token: resetToken,
 			err:   auth.ErrUnauthorizedAccess,
 		},
 	}
 
-	for desc, tc := range cases {
-		page, err := svc.ListChildren(context.Background(), tc.token, tc.id, auth.PageMetadata{Level: tc.level, Metadata: tc.metadata})
-		size := uint64(len(page.Groups))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, tc := range cases {
+		_, err := svc.RetrieveKey(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
 
-func TestListParents(t *testing.T) {
+func TestIdentify(t *testing.T) {
 	svc := newService()
-	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
-	}
+	_, recoverySecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.RecoveryKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing reset key expected to succeed: %s", err))
 
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+	_, apiSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuerID: id, Subject: email, IssuedAt: time.Now(), ExpiresAt: time.Now().Add(time.Minute)})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
 
-	group := auth.Group{
-		Description: description,
-		Metadata: auth.GroupMetadata{
-			"field": "value",
+	exp1 := time.Now().Add(-2 * time.Second)
+	_, expSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), ExpiresAt: exp1})
+	assert.Nil(t, err, fmt.Sprintf("Issuing expired user key expected to succeed: %s", err))
+
+	_, invalidSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: 22, IssuedAt: time.Now()})
+	assert.Nil(t, err, fmt.Sprintf("Issuing user key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc string
+		key  string
+		idt  auth.Identity
+		err  error
+	}{
+		{
+			desc: "identify login key",
+			key:  loginSecret,
+			idt:  auth.Identity{ID: id, Email: email},
+			err:  nil,
+		},
+		{
+			desc: "identify recovery key",
+			key:  recoverySecret,
+			idt:  auth.Identity{ID: id, Email: email},
+			err:  nil,
+		},
+		{
+			desc: "identify API key",
+			key:  apiSecret,
+			idt:  auth.Identity{ID: id, Email: email},
+			err:  nil,
+		},
+		{
+			desc: "identify expired API key",
+			key:  expSecret,
+			idt:  auth.Identity{},
+			err:  auth.ErrAPIKeyExpired,
+		},
+		{
+			desc: "identify expired key",
+			key:  invalidSecret,
+			idt:  auth.Identity{},
+			err:  auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "identify invalid key",
+			key:  "invalid",
+			idt:  auth.Identity{},
+			err:  auth.ErrUnauthorizedAccess,
 		},
 	}
-	n := uint64(10)
-	parentID := ""
-	groupIDs := make([]string, n)
-	for i := uint64(0); i < n; i++ {
-		group.Name = fmt.Sprintf("Group%d", i)
-		group.ParentID = parentID
-		g, err := svc.CreateGroup(context.Background(), apiToken, group)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
-		parentID = g.ID
-		groupIDs[i] = g.ID
+
+	for _, tc := range cases {
+		idt, err := svc.Identify(context.Background(), tc.key)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.idt, idt, fmt.Sprintf("%s expected %s got %s\n", tc.desc, tc.idt, idt))
 	}
+}
 
-	cases := map[string]struct {
-		token    string
-		level    uint64
-		size     uint64
-		id       string
-		metadata auth.GroupMetadata
-		err      error
+func TestIdentifyScopedKey(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("issuing login key expected to succeed: %s", err))
+
+	key := auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), Scopes: []string{"things:read"}}
+	_, scoped, err := svc.Issue(context.Background(), loginSecret, key)
+	require.Nil(t, err, fmt.Sprintf("issuing scoped API key expected to succeed: %s", err))
+
+	key.Scopes = nil
+	_, unscoped, err := svc.Issue(context.Background(), loginSecret, key)
+	require.Nil(t, err, fmt.Sprintf("issuing API key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		scope string
+		err   error
 	}{
-		"list all parents": {
-			token: apiToken,
-			level: 5,
-			id:    groupIDs[n-1],
-			size:  n,
+		{
+			desc:  "identify scoped key for scoped action",
+			token: scoped,
+			scope: "things:read",
 			err:   nil,
 		},
-		"list all parents with wrong token": {
-			token: "wrongToken",
-			level: 5,
-			size:  0,
-			err:   auth.ErrUnauthorizedAccess,
+		{
+			desc:  "identify scoped key for action out of scope",
+			token: scoped,
+			scope: "users:read",
+			err:   auth.ErrKeyScopeNotAllowed,
+		},
+		{
+			desc:  "identify scoped key for action without scope",
+			token: scoped,
+			scope: "",
+			err:   auth.ErrKeyScopeNotAllowed,
+		},
+		{
+			desc:  "identify unscoped key for action without scope",
+			token: unscoped,
+			scope: "",
+			err:   nil,
 		},
 	}
 
-	for desc, tc := range cases {
-		page, err := svc.ListParents(context.Background(), tc.token, tc.id, auth.PageMetadata{Level: tc.level, Metadata: tc.metadata})
-		size := uint64(len(page.Groups))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, tc := range cases {
+		_, err := svc.Identify(auth.WithScope(context.Background(), tc.scope), tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
+
+	_, err = svc.ListGroups(context.Background(), scoped, auth.PageMetadata{Limit: 10})
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("listing groups with scoped key: expected %s got %s\n", auth.ErrUnauthorizedAccess, err))
+	_, err = svc.ListGroups(context.Background(), unscoped, auth.PageMetadata{Limit: 10})
+	assert.Nil(t, err, fmt.Sprintf("listing groups with unscoped key: unexpected error: %s\n", err))
 }
 
-func TestListMembers(t *testing.T) {
+func TestIdentifyAllowedIPs(t *testing.T) {
+	svc := newService()
+
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	_, _, err = svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), AllowedIPs: []string{"10.0.0.0/33"}})
+	assert.True(t, errors.Contains(err, auth.ErrMalformedEntity), fmt.Sprintf("Issuing key with invalid network: expected %s got %s\n", auth.ErrMalformedEntity, err))
+	_, _, err = svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email, AllowedIPs: []string{"10.0.0.0/8"}})
+	assert.True(t, errors.Contains(err, auth.ErrMalformedEntity), fmt.Sprintf("Issuing login key with allowlist: expected %s got %s\n", auth.ErrMalformedEntity, err))
+
+	key, apiSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), AllowedIPs: []string{"10.0.0.0/8", "203.0.113.7"}})
+	assert.Nil(t, err, fmt.Sprintf("Issuing API key with allowlist expected to succeed: %s", err))
+
+	cases := []struct {
+		desc string
+		ip   string
+		err  error
+	}{
+		{
+			desc: "identify API key from allowed network",
+			ip:   "10.1.2.3",
+			err:  nil,
+		},
+		{
+			desc: "identify API key from allowed address",
+			ip:   "203.0.113.7",
+			err:  nil,
+		},
+		{
+			desc: "identify API key from other network",
+			ip:   "203.0.113.8",
+			err:  auth.ErrKeyIPNotAllowed,
+		},
+		{
+			desc: "identify API key from unknown address",
+			ip:   "",
+			err:  auth.ErrKeyIPNotAllowed,
+		},
+	}
+
+	for _, tc := range cases {
+		ctx := auth.WithClientIP(context.Background(), tc.ip)
+		_, err := svc.Identify(ctx, apiSecret)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		err = svc.Authorize(ctx, auth.PolicyReq{Object: authoritiesObj, Relation: memberRelation, Subject: id, Token: apiSecret})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: authorizing expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	in, err := svc.Introspect(auth.WithClientIP(context.Background(), "203.0.113.8"), loginSecret, apiSecret)
+	assert.Nil(t, err, fmt.Sprintf("introspecting API key expected to succeed: %s", err))
+	assert.True(t, in.Active, "introspecting API key from other network: expected the key to be active")
+	assert.Equal(t, key.AllowedIPs, in.Key.AllowedIPs, fmt.Sprintf("introspecting API key: expected allowlist %v got %v\n", key.AllowedIPs, in.Key.AllowedIPs))
+}
+
+func TestIdentifyClaims(t *testing.T) {
+	svc := newService()
+
+	claims := map[string]string{"device_id": "dev-1", "tenant": "acme"}
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email, Claims: claims})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key with claims expected to succeed: %s", err))
+	_, apiSecret, err := svc.Issue(context.Background(), loginSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), Claims: map[string]string{"scope": "telemetry"}})
+	assert.Nil(t, err, fmt.Sprintf("Issuing API key with claims expected to succeed: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		claims map[string]string
+	}{
+		{
+			desc:   "identify login key with claims",
+			token:  loginSecret,
+			claims: claims,
+		},
+		{
+			desc:   "identify API key with claims",
+			token:  apiSecret,
+			claims: map[string]string{"scope": "telemetry"},
+		},
+	}
+
+	for _, tc := range cases {
+		identity, err := svc.Identify(context.Background(), tc.token)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Equal(t, tc.claims, identity.Claims, fmt.Sprintf("%s: expected claims %v got %v\n", tc.desc, tc.claims, identity.Claims))
+	}
+}
+
+func TestCreateGroup(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
@@ -711,68 +972,66 @@ func TestListMembers(t *testing.T) {
 	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
 	group := auth.Group{
+		Name:        "Group",
 		Description: description,
-		Metadata: auth.GroupMetadata{
-			"field": "value",
-		},
 	}
-	g, err := svc.CreateGroup(context.Background(), apiToken, group)
-	assert.Nil(t, err, fmt.Sprintf("Creating group expected to succeed: %s", err))
-	group.ID = g.ID
-
-	n := uint64(10)
-	for i := uint64(0); i < n; i++ {
-		uid, err := idProvider.ID()
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
-		err = svc.Assign(context.Background(), apiToken, group.ID, "things", uid)
-		require.Nil(t, err, fmt.Sprintf("Assign member expected to succeed: %s\n", err))
+	parentGroup := auth.Group{
+		Name:        "ParentGroup",
+		Description: description,
 	}
 
-	cases := map[string]struct {
-		token    string
-		size     uint64
-		offset   uint64
-		limit    uint64
-		group    auth.Group
-		metadata auth.GroupMetadata
-		err      error
+	parent, err := svc.CreateGroup(context.Background(), apiToken, parentGroup)
+	assert.Nil(t, err, fmt.Sprintf("Creating parent group expected to succeed: %s", err))
+
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: parent.ID, Relation: memberRelation, Subject: id})
+	assert.Nil(t, err, fmt.Sprintf("Checking parent group owner's policy expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		group auth.Group
+		err   error
 	}{
-		"list all members": {
-			token:  apiToken,
-			offset: 0,
-			limit:  n,
-			group:  group,
-			size:   n,
-			err:    nil,
+		{
+			desc:  "create new group",
+			group: group,
+			err:   nil,
 		},
-		"list half members": {
-			token:  apiToken,
-			offset: n / 2,
-			limit:  n,
-			group:  group,
-			size:   n / 2,
-			err:    nil,
+		{
+			desc:  "create group with existing name",
+			group: group,
+			err:   nil,
 		},
-		"list all members with wrong token": {
-			token:  "wrongToken",
-			offset: 0,
-			limit:  n,
-			size:   0,
-			err:    auth.ErrUnauthorizedAccess,
+		{
+			desc: "create group with parent",
+			group: auth.Group{
+				Name:     groupName,
+				ParentID: parent.ID,
+			},
+			err: nil,
+		},
+		{
+			desc: "create group with invalid parent",
+			group: auth.Group{
+				Name:     groupName,
+				ParentID: "xxxxxxxxxx",
+			},
+			err: auth.ErrCreateGroup,
 		},
 	}
 
-	for desc, tc := range cases {
-		page, err := svc.ListMembers(context.Background(), tc.token, tc.group.ID, "things", auth.PageMetadata{Offset: tc.offset, Limit: tc.limit, Metadata: tc.metadata})
-		size := uint64(len(page.Members))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
-	}
+	for _, tc := range cases {
+		g, err := svc.CreateGroup(context.Background(), apiToken, tc.group)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
+		if err == nil {
+			authzErr := svc.Authorize(context.Background(), auth.PolicyReq{Object: g.ID, Relation: memberRelation, Subject: g.OwnerID})
+			assert.Nil(t, authzErr, fmt.Sprintf("%s - Checking group owner's policy expected to succeed: %s", tc.desc, authzErr))
+		}
+	}
 }
 
-func TestListMemberships(t *testing.T) {
+func TestUpdateGroup(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
@@ -789,69 +1048,47 @@ func TestListMemberships(t *testing.T) {
 	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
 	group := auth.Group{
+		Name:        "Group",
 		Description: description,
 		Metadata: auth.GroupMetadata{
 			"field": "value",
 		},
 	}
 
-	memberID, err := idProvider.ID()
-	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
-
-	n := uint64(10)
-	for i := uint64(0); i < n; i++ {
-		group.Name = fmt.Sprintf("Group%d", i)
-		g, err := svc.CreateGroup(context.Background(), apiToken, group)
-		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	group, err = svc.CreateGroup(context.Background(), apiToken, group)
+	assert.Nil(t, err, fmt.Sprintf("Creating parent group failed: %s", err))
 
-		_ = svc.AddPolicy(context.Background(), auth.PolicyReq{Subject: id, Object: memberID, Relation: "owner"})
-		err = svc.Assign(context.Background(), apiToken, g.ID, "things", memberID)
-		require.Nil(t, err, fmt.Sprintf("Assign member expected to succeed: %s\n", err))
-	}
-
-	cases := map[string]struct {
-		token    string
-		size     uint64
-		offset   uint64
-		limit    uint64
-		group    auth.Group
-		metadata auth.GroupMetadata
-		err      error
+	cases := []struct {
+		desc  string
+		group auth.Group
+		err   error
 	}{
-		"list all members": {
-			token:  apiToken,
-			offset: 0,
-			limit:  n,
-			group:  group,
-			size:   n,
-			err:    nil,
-		},
-		"list half members": {
-			token:  apiToken,
-			offset: n / 2,
-			limit:  n,
-			group:  group,
-			size:   n / 2,
-			err:    nil,
-		},
-		"list all members with wrong token": {
-			token:  "wrongToken",
-			offset: 0,
-			limit:  n,
-			size:   0,
-			err:    auth.ErrUnauthorizedAccess,
+		{
+			desc: "update group",
+			group: auth.Group{
+				ID:          group.ID,
+				Name:        "NewName",
+				Description: "NewDescription",
+				Metadata: auth.GroupMetadata{
+					"field": "value2",
+				},
+			},
+			err: nil,
 		},
 	}
 
-	for desc, tc := range cases {
-		page, err := svc.ListMemberships(context.Background(), tc.token, memberID, auth.PageMetadata{Limit: tc.limit, Offset: tc.offset, Metadata: tc.metadata})
-		size := uint64(len(page.Groups))
-		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	for _, tc := range cases {
+		g, err := svc.UpdateGroup(context.Background(), apiToken, tc.group)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, g.ID, tc.group.ID, fmt.Sprintf("ID: expected %s got %s\n", g.ID, tc.group.ID))
+		assert.Equal(t, g.Name, tc.group.Name, fmt.Sprintf("Name: expected %s got %s\n", g.Name, tc.group.Name))
+		assert.Equal(t, g.Description, tc.group.Description, fmt.Sprintf("Description: expected %s got %s\n", g.Description, tc.group.Description))
+		assert.Equal(t, g.Metadata["field"], g.Metadata["field"], fmt.Sprintf("Metadata: expected %s got %s\n", g.Metadata, tc.group.Metadata))
 	}
+
 }
 
-func TestRemoveGroup(t *testing.T) {
+func TestViewGroup(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
@@ -867,40 +1104,51 @@ func TestRemoveGroup(t *testing.T) {
 	_, apiToken, err := svc.Issue(context.Background(), secret, key)
 	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
-	uid, err := idProvider.ID()
-	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-
-	creationTime := time.Now().UTC()
 	group := auth.Group{
-		Name:      groupName,
-		OwnerID:   uid,
-		CreatedAt: creationTime,
-		UpdatedAt: creationTime,
+		Name:        "Group",
+		Description: description,
+		Metadata: auth.GroupMetadata{
+			"field": "value",
+		},
 	}
 
 	group, err = svc.CreateGroup(context.Background(), apiToken, group)
-	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
-
-	err = svc.RemoveGroup(context.Background(), "wrongToken", group.ID)
-	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("Unauthorized access: expected %v got %v", auth.ErrUnauthorizedAccess, err))
-
-	err = svc.RemoveGroup(context.Background(), apiToken, "wrongID")
-	assert.True(t, errors.Contains(err, auth.ErrGroupNotFound), fmt.Sprintf("Remove group with wrong id: expected %v got %v", auth.ErrGroupNotFound, err))
-
-	gp, err := svc.ListGroups(context.Background(), apiToken, auth.PageMetadata{Level: auth.MaxLevel})
-	require.Nil(t, err, fmt.Sprintf("list groups unexpected error: %s", err))
-	assert.True(t, gp.Total == 1, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, gp.Total))
+	assert.Nil(t, err, fmt.Sprintf("Creating parent group failed: %s", err))
 
-	err = svc.RemoveGroup(context.Background(), apiToken, group.ID)
-	assert.True(t, errors.Contains(err, nil), fmt.Sprintf("Unauthorized access: expected %v got %v", nil, err))
+	cases := []struct {
+		desc    string
+		token   string
+		groupID string
+		err     error
+	}{
+		{
 
-	gp, err = svc.ListGroups(context.Background(), apiToken, auth.PageMetadata{Level: auth.MaxLevel})
-	require.Nil(t, err, fmt.Sprintf("list groups save unexpected error: %s", err))
-	assert.True(t, gp.Total == 0, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 0, gp.Total))
+			desc:    "view group",
+			token:   apiToken,
+			groupID: group.ID,
+			err:     nil,
+		},
+		{
+			desc:    "view group with unauthorized token",
+			token:   "wrongtoken",
+			groupID: group.ID,
+			err:     auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "view group for wrong id",
+			token:   apiToken,
+			groupID: "wrong",
+			err:     auth.ErrGroupNotFound,
+		},
+	}
 
+	for _, tc := range cases {
+		_, err := svc.ViewGroup(context.Background(), tc.token, tc.groupID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
 }
 
-func TestAssign(t *testing.T) {
+func TestListGroups(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
@@ -916,45 +1164,73 @@ func TestAssign(t *testing.T) {
 	_, apiToken, err := svc.Issue(context.Background(), secret, key)
 	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
-	uid, err := idProvider.ID()
-	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-
-	creationTime := time.Now().UTC()
 	group := auth.Group{
-		Name:      groupName + "Updated",
-		OwnerID:   uid,
-		CreatedAt: creationTime,
-		UpdatedAt: creationTime,
+		Description: description,
+		Metadata: auth.GroupMetadata{
+			"field": "value",
+		},
+	}
+	n := uint64(10)
+	parentID := ""
+	for i := uint64(0); i < n; i++ {
+		group.Name = fmt.Sprintf("Group%d", i)
+		group.ParentID = parentID
+		g, err := svc.CreateGroup(context.Background(), apiToken, group)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		parentID = g.ID
 	}
 
-	group, err = svc.CreateGroup(context.Background(), apiToken, group)
-	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
-
-	mid, err := idProvider.ID()
-	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-
-	err = svc.Assign(context.Background(), apiToken, group.ID, "things", mid)
-	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
-
-	// check access control policies things members.
-	subjectSet := fmt.Sprintf("%s:%s#%s", "members", group.ID, memberRelation)
-	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: mid, Relation: "read", Subject: subjectSet})
-	require.Nil(t, err, fmt.Sprintf("entites having an access to group %s must have %s policy on %s: %s", group.ID, "read", mid, err))
-	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: mid, Relation: "write", Subject: subjectSet})
-	require.Nil(t, err, fmt.Sprintf("entites having an access to group %s must have %s policy on %s: %s", group.ID, "write", mid, err))
-	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: mid, Relation: "delete", Subject: subjectSet})
-	require.Nil(t, err, fmt.Sprintf("entites having an access to group %s must have %s policy on %s: %s", group.ID, "delete", mid, err))
-
-	mp, err := svc.ListMembers(context.Background(), apiToken, group.ID, "things", auth.PageMetadata{Offset: 0, Limit: 10})
-	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
-	assert.True(t, mp.Total == 1, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, mp.Total))
+	cases := map[string]struct {
+		token    string
+		level    uint64
+		size     uint64
+		metadata auth.GroupMetadata
+		err      error
+	}{
+		"list all groups": {
+			token: apiToken,
+			level: 5,
+			size:  n,
+			err:   nil,
+		},
+		"list groups for level 1": {
+			token: apiToken,
+			level: 1,
+			size:  n,
+			err:   nil,
+		},
+		"list groups by existing metadata": {
+			token:    apiToken,
+			level:    5,
+			size:     n,
+			metadata: auth.GroupMetadata{"field": "value"},
+			err:      nil,
+		},
+		"list groups by non-existing metadata": {
+			token:    apiToken,
+			level:    5,
+			size:     0,
+			metadata: auth.GroupMetadata{"field": "other"},
+			err:      nil,
+		},
+		"list all groups with wrong token": {
+			token: "wrongToken",
+			level: 5,
+			size:  0,
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
 
-	err = svc.Assign(context.Background(), "wrongToken", group.ID, "things", mid)
-	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("Unauthorized access: expected %v got %v", auth.ErrUnauthorizedAccess, err))
+	for desc, tc := range cases {
+		page, err := svc.ListGroups(context.Background(), tc.token, auth.PageMetadata{Level: tc.level, Metadata: tc.metadata})
+		size := uint64(len(page.Groups))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
 
 }
 
-func TestUnassign(t *testing.T) {
+func TestListChildren(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
@@ -970,74 +1246,279 @@ func TestUnassign(t *testing.T) {
 	_, apiToken, err := svc.Issue(context.Background(), secret, key)
 	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
-	uid, err := idProvider.ID()
-	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
-
-	creationTime := time.Now().UTC()
 	group := auth.Group{
-		Name:      groupName + "Updated",
-		OwnerID:   uid,
-		CreatedAt: creationTime,
-		UpdatedAt: creationTime,
+		Description: description,
+		Metadata: auth.GroupMetadata{
+			"field": "value",
+		},
+	}
+	n := uint64(10)
+	parentID := ""
+	groupIDs := make([]string, n)
+	for i := uint64(0); i < n; i++ {
+		group.Name = fmt.Sprintf("Group%d", i)
+		group.ParentID = parentID
+		g, err := svc.CreateGroup(context.Background(), apiToken, group)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		parentID = g.ID
+		groupIDs[i] = g.ID
 	}
 
-	group, err = svc.CreateGroup(context.Background(), apiToken, group)
-	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
-
-	mid, err := idProvider.ID()
-	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	cases := map[string]struct {
+		token    string
+		level    uint64
+		size     uint64
+		id       string
+		metadata auth.GroupMetadata
+		err      error
+	}{
+		"list all children": {
+			token: apiToken,
+			level: 5,
+			id:    groupIDs[0],
+			size:  n,
+			err:   nil,
+		},
+		"list all groups with wrong token": {
+			token: "wrongToken",
+			level: 5,
+			size:  0,
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
 
-	err = svc.Assign(context.Background(), apiToken, group.ID, "things", mid)
-	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
+	for desc, tc := range cases {
+		page, err := svc.ListChildren(context.Background(), tc.token, tc.id, auth.PageMetadata{Level: tc.level, Metadata: tc.metadata})
+		size := uint64(len(page.Groups))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
 
-	mp, err := svc.ListMembers(context.Background(), apiToken, group.ID, "things", auth.PageMetadata{Limit: 10, Offset: 0})
-	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
-	assert.True(t, mp.Total == 1, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, mp.Total))
+func TestListParents(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	err = svc.Unassign(context.Background(), apiToken, group.ID, mid)
-	require.Nil(t, err, fmt.Sprintf("member unassign save unexpected error: %s", err))
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
 
-	mp, err = svc.ListMembers(context.Background(), apiToken, group.ID, "things", auth.PageMetadata{Limit: 10, Offset: 0})
-	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
-	assert.True(t, mp.Total == 0, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 0, mp.Total))
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
-	err = svc.Unassign(context.Background(), "wrongToken", group.ID, mid)
-	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("Unauthorized access: expected %v got %v", auth.ErrUnauthorizedAccess, err))
+	group := auth.Group{
+		Description: description,
+		Metadata: auth.GroupMetadata{
+			"field": "value",
+		},
+	}
+	n := uint64(10)
+	parentID := ""
+	groupIDs := make([]string, n)
+	for i := uint64(0); i < n; i++ {
+		group.Name = fmt.Sprintf("Group%d", i)
+		group.ParentID = parentID
+		g, err := svc.CreateGroup(context.Background(), apiToken, group)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+		parentID = g.ID
+		groupIDs[i] = g.ID
+	}
 
-	err = svc.Unassign(context.Background(), apiToken, group.ID, mid)
-	assert.True(t, errors.Contains(err, auth.ErrGroupNotFound), fmt.Sprintf("Unauthorized access: expected %v got %v", nil, err))
+	cases := map[string]struct {
+		token    string
+		level    uint64
+		size     uint64
+		id       string
+		metadata auth.GroupMetadata
+		err      error
+	}{
+		"list all parents": {
+			token: apiToken,
+			level: 5,
+			id:    groupIDs[n-1],
+			size:  n,
+			err:   nil,
+		},
+		"list all parents with wrong token": {
+			token: "wrongToken",
+			level: 5,
+			size:  0,
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListParents(context.Background(), tc.token, tc.id, auth.PageMetadata{Level: tc.level, Metadata: tc.metadata})
+		size := uint64(len(page.Groups))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
 }
 
-func TestAuthorize(t *testing.T) {
+func TestListMembers(t *testing.T) {
 	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	pr := auth.PolicyReq{Object: authoritiesObj, Relation: memberRelation, Subject: id}
-	err := svc.Authorize(context.Background(), pr)
-	require.Nil(t, err, fmt.Sprintf("authorizing initial %v policy expected to succeed: %s", pr, err))
-}
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
 
-func TestAddPolicy(t *testing.T) {
-	svc := newService()
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
-	pr := auth.PolicyReq{Object: "obj", Relation: "rel", Subject: "sub"}
-	err := svc.AddPolicy(context.Background(), pr)
-	require.Nil(t, err, fmt.Sprintf("adding %v policy expected to succeed: %v", pr, err))
+	group := auth.Group{
+		Description: description,
+		Metadata: auth.GroupMetadata{
+			"field": "value",
+		},
+	}
+	g, err := svc.CreateGroup(context.Background(), apiToken, group)
+	assert.Nil(t, err, fmt.Sprintf("Creating group expected to succeed: %s", err))
+	group.ID = g.ID
+
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		uid, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+		err = svc.Assign(context.Background(), apiToken, group.ID, "things", uid)
+		require.Nil(t, err, fmt.Sprintf("Assign member expected to succeed: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		token    string
+		size     uint64
+		offset   uint64
+		limit    uint64
+		group    auth.Group
+		metadata auth.GroupMetadata
+		err      error
+	}{
+		"list all members": {
+			token:  apiToken,
+			offset: 0,
+			limit:  n,
+			group:  group,
+			size:   n,
+			err:    nil,
+		},
+		"list half members": {
+			token:  apiToken,
+			offset: n / 2,
+			limit:  n,
+			group:  group,
+			size:   n / 2,
+			err:    nil,
+		},
+		"list all members with wrong token": {
+			token:  "wrongToken",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListMembers(context.Background(), tc.token, tc.group.ID, "things", auth.PageMetadata{Offset: tc.offset, Limit: tc.limit, Metadata: tc.metadata})
+		size := uint64(len(page.Members))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
 
-	err = svc.Authorize(context.Background(), pr)
-	require.Nil(t, err, fmt.Sprintf("checking shared %v policy expected to be succeed: %#v", pr, err))
 }
 
-func TestDeletePolicy(t *testing.T) {
+func TestListMemberships(t *testing.T) {
 	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	pr := auth.PolicyReq{Object: authoritiesObj, Relation: memberRelation, Subject: id}
-	err := svc.DeletePolicy(context.Background(), pr)
-	require.Nil(t, err, fmt.Sprintf("deleting %v policy expected to succeed: %s", pr, err))
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
+
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+
+	group := auth.Group{
+		Description: description,
+		Metadata: auth.GroupMetadata{
+			"field": "value",
+		},
+	}
+
+	memberID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	n := uint64(10)
+	for i := uint64(0); i < n; i++ {
+		group.Name = fmt.Sprintf("Group%d", i)
+		g, err := svc.CreateGroup(context.Background(), apiToken, group)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+		_ = svc.AddPolicy(context.Background(), auth.PolicyReq{Subject: id, Object: memberID, Relation: "owner"})
+		err = svc.Assign(context.Background(), apiToken, g.ID, "things", memberID)
+		require.Nil(t, err, fmt.Sprintf("Assign member expected to succeed: %s\n", err))
+	}
+
+	cases := map[string]struct {
+		token    string
+		size     uint64
+		offset   uint64
+		limit    uint64
+		group    auth.Group
+		metadata auth.GroupMetadata
+		err      error
+	}{
+		"list all members": {
+			token:  apiToken,
+			offset: 0,
+			limit:  n,
+			group:  group,
+			size:   n,
+			err:    nil,
+		},
+		"list half members": {
+			token:  apiToken,
+			offset: n / 2,
+			limit:  n,
+			group:  group,
+			size:   n / 2,
+			err:    nil,
+		},
+		"list all members with wrong token": {
+			token:  "wrongToken",
+			offset: 0,
+			limit:  n,
+			size:   0,
+			err:    auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListMemberships(context.Background(), tc.token, memberID, auth.PageMetadata{Limit: tc.limit, Offset: tc.offset, Metadata: tc.metadata})
+		size := uint64(len(page.Groups))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", desc, tc.size, size))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
 }
 
-func TestAssignAccessRights(t *testing.T) {
+func TestRemoveGroup(t *testing.T) {
 	svc := newService()
-
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
@@ -1052,217 +1533,2225 @@ func TestAssignAccessRights(t *testing.T) {
 	_, apiToken, err := svc.Issue(context.Background(), secret, key)
 	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
 
-	userGroupID := "user-group"
-	thingGroupID := "thing-group"
-	err = svc.AssignGroupAccessRights(context.Background(), apiToken, thingGroupID, userGroupID)
-	require.Nil(t, err, fmt.Sprintf("sharing the user group with thing group expected to succeed: %v", err))
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	creationTime := time.Now().UTC()
+	group := auth.Group{
+		Name:      groupName,
+		OwnerID:   uid,
+		CreatedAt: creationTime,
+		UpdatedAt: creationTime,
+	}
+
+	group, err = svc.CreateGroup(context.Background(), apiToken, group)
+	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
+
+	err = svc.RemoveGroup(context.Background(), "wrongToken", group.ID)
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("Unauthorized access: expected %v got %v", auth.ErrUnauthorizedAccess, err))
+
+	err = svc.RemoveGroup(context.Background(), apiToken, "wrongID")
+	assert.True(t, errors.Contains(err, auth.ErrGroupNotFound), fmt.Sprintf("Remove group with wrong id: expected %v got %v", auth.ErrGroupNotFound, err))
+
+	gp, err := svc.ListGroups(context.Background(), apiToken, auth.PageMetadata{Level: auth.MaxLevel})
+	require.Nil(t, err, fmt.Sprintf("list groups unexpected error: %s", err))
+	assert.True(t, gp.Total == 1, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, gp.Total))
+
+	err = svc.RemoveGroup(context.Background(), apiToken, group.ID)
+	assert.True(t, errors.Contains(err, nil), fmt.Sprintf("Unauthorized access: expected %v got %v", nil, err))
+
+	gp, err = svc.ListGroups(context.Background(), apiToken, auth.PageMetadata{Level: auth.MaxLevel})
+	require.Nil(t, err, fmt.Sprintf("list groups save unexpected error: %s", err))
+	assert.True(t, gp.Total == 0, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 0, gp.Total))
+
+}
+
+func TestAssign(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
+
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	creationTime := time.Now().UTC()
+	group := auth.Group{
+		Name:      groupName + "Updated",
+		OwnerID:   uid,
+		CreatedAt: creationTime,
+		UpdatedAt: creationTime,
+	}
+
+	group, err = svc.CreateGroup(context.Background(), apiToken, group)
+	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
+
+	mid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = svc.Assign(context.Background(), apiToken, group.ID, "things", mid)
+	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
+
+	// check access control policies things members.
+	subjectSet := fmt.Sprintf("%s:%s#%s", "members", group.ID, memberRelation)
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: mid, Relation: "read", Subject: subjectSet})
+	require.Nil(t, err, fmt.Sprintf("entites having an access to group %s must have %s policy on %s: %s", group.ID, "read", mid, err))
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: mid, Relation: "write", Subject: subjectSet})
+	require.Nil(t, err, fmt.Sprintf("entites having an access to group %s must have %s policy on %s: %s", group.ID, "write", mid, err))
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: mid, Relation: "delete", Subject: subjectSet})
+	require.Nil(t, err, fmt.Sprintf("entites having an access to group %s must have %s policy on %s: %s", group.ID, "delete", mid, err))
+
+	mp, err := svc.ListMembers(context.Background(), apiToken, group.ID, "things", auth.PageMetadata{Offset: 0, Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
+	assert.True(t, mp.Total == 1, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, mp.Total))
+
+	err = svc.Assign(context.Background(), "wrongToken", group.ID, "things", mid)
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("Unauthorized access: expected %v got %v", auth.ErrUnauthorizedAccess, err))
+
+}
+
+func TestUnassign(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
+
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	creationTime := time.Now().UTC()
+	group := auth.Group{
+		Name:      groupName + "Updated",
+		OwnerID:   uid,
+		CreatedAt: creationTime,
+		UpdatedAt: creationTime,
+	}
+
+	group, err = svc.CreateGroup(context.Background(), apiToken, group)
+	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
+
+	mid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = svc.Assign(context.Background(), apiToken, group.ID, "things", mid)
+	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
+
+	mp, err := svc.ListMembers(context.Background(), apiToken, group.ID, "things", auth.PageMetadata{Limit: 10, Offset: 0})
+	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
+	assert.True(t, mp.Total == 1, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, mp.Total))
+
+	err = svc.Unassign(context.Background(), apiToken, group.ID, mid)
+	require.Nil(t, err, fmt.Sprintf("member unassign save unexpected error: %s", err))
+
+	mp, err = svc.ListMembers(context.Background(), apiToken, group.ID, "things", auth.PageMetadata{Limit: 10, Offset: 0})
+	require.Nil(t, err, fmt.Sprintf("member assign save unexpected error: %s", err))
+	assert.True(t, mp.Total == 0, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 0, mp.Total))
+
+	err = svc.Unassign(context.Background(), "wrongToken", group.ID, mid)
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("Unauthorized access: expected %v got %v", auth.ErrUnauthorizedAccess, err))
+
+	err = svc.Unassign(context.Background(), apiToken, group.ID, mid)
+	assert.True(t, errors.Contains(err, auth.ErrGroupNotFound), fmt.Sprintf("Unauthorized access: expected %v got %v", nil, err))
+}
+
+func TestAssignTemporary(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	group, err := svc.CreateGroup(context.Background(), secret, auth.Group{Name: groupName})
+	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
+
+	mid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		token     string
+		expiresAt time.Time
+		err       error
+	}{
+		{
+			desc:      "assign member temporarily with expired deadline",
+			token:     secret,
+			expiresAt: time.Now().Add(-time.Minute),
+			err:       auth.ErrMalformedEntity,
+		},
+		{
+			desc:      "assign member temporarily with invalid token",
+			token:     "wrongToken",
+			expiresAt: time.Now().Add(time.Hour),
+			err:       auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:      "assign member temporarily",
+			token:     secret,
+			expiresAt: time.Now().Add(time.Hour),
+			err:       nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AssignTemporary(context.Background(), tc.token, group.ID, "users", tc.expiresAt, mid)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	mp, err := svc.ListMembers(context.Background(), secret, group.ID, "users", auth.PageMetadata{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("retrieving members expected to succeed: %s", err))
+	assert.Equal(t, uint64(1), mp.Total, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, mp.Total))
+}
+
+func TestSetRelation(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	group, err := svc.CreateGroup(context.Background(), secret, auth.Group{Name: groupName})
+	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
+
+	mid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = svc.Assign(context.Background(), secret, group.ID, "users", mid)
+	require.Nil(t, err, fmt.Sprintf("member assign unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		relation string
+		member   string
+		err      error
+	}{
+		{
+			desc:     "set relation with invalid token",
+			token:    "wrongToken",
+			relation: auth.EditorRelation,
+			member:   mid,
+			err:      auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "set invalid relation",
+			token:    secret,
+			relation: "invalid",
+			member:   mid,
+			err:      auth.ErrMalformedEntity,
+		},
+		{
+			desc:     "set relation of non-member",
+			token:    secret,
+			relation: auth.EditorRelation,
+			member:   "unknown",
+			err:      auth.ErrNotFound,
+		},
+		{
+			desc:     "set relation",
+			token:    secret,
+			relation: auth.EditorRelation,
+			member:   mid,
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.SetRelation(context.Background(), tc.token, group.ID, tc.relation, tc.member)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	mp, err := svc.ListMembers(context.Background(), secret, group.ID, "users", auth.PageMetadata{Limit: 10, Relation: auth.EditorRelation})
+	require.Nil(t, err, fmt.Sprintf("retrieving members expected to succeed: %s", err))
+	require.Equal(t, uint64(1), mp.Total, fmt.Sprintf("retrieve editors of a group: expected %d got %d\n", 1, mp.Total))
+	assert.Equal(t, id, mp.Members[0].CreatedBy, fmt.Sprintf("expected member created by %s got %s\n", id, mp.Members[0].CreatedBy))
+
+	mp, err = svc.ListMembers(context.Background(), secret, group.ID, "users", auth.PageMetadata{Limit: 10, Relation: auth.ViewerRelation})
+	require.Nil(t, err, fmt.Sprintf("retrieving members expected to succeed: %s", err))
+	assert.Equal(t, uint64(0), mp.Total, fmt.Sprintf("retrieve viewers of a group: expected %d got %d\n", 0, mp.Total))
+
+	_, err = svc.ListMembers(context.Background(), secret, group.ID, "users", auth.PageMetadata{Limit: 10, Relation: "invalid"})
+	assert.True(t, errors.Contains(err, auth.ErrMalformedEntity), fmt.Sprintf("list members by invalid relation: expected %s got %s\n", auth.ErrMalformedEntity, err))
+}
+
+func TestDelegateGroupAdmin(t *testing.T) {
+	svc := newService()
+	_, ownerSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "owner", Subject: "owner@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing owner's login key expected to succeed: %s", err))
+	_, delegateSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "delegate", Subject: "delegate@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing delegate's login key expected to succeed: %s", err))
+
+	group, err := svc.CreateGroup(context.Background(), ownerSecret, auth.Group{Name: groupName})
+	require.Nil(t, err, fmt.Sprintf("Creating group expected to succeed: %s", err))
+
+	err = svc.Assign(context.Background(), delegateSecret, group.ID, auth.UsersResource, "member")
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("assigning member without delegation: expected %s got %s\n", auth.ErrAuthorization, err))
+	_, err = svc.CreateGroup(context.Background(), delegateSecret, auth.Group{Name: "child", ParentID: group.ID})
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("creating sub-group without delegation: expected %s got %s\n", auth.ErrAuthorization, err))
+
+	cases := []struct {
+		desc      string
+		token     string
+		relations []string
+		err       error
+	}{
+		{
+			desc:      "delegate as non-owner",
+			token:     delegateSecret,
+			relations: []string{auth.InviteRelation},
+			err:       auth.ErrAuthorization,
+		},
+		{
+			desc:      "delegate invalid relation",
+			token:     ownerSecret,
+			relations: []string{memberRelation},
+			err:       auth.ErrMalformedEntity,
+		},
+		{
+			desc:      "delegate without relations",
+			token:     ownerSecret,
+			relations: []string{},
+			err:       auth.ErrMalformedEntity,
+		},
+		{
+			desc:      "delegate as owner",
+			token:     ownerSecret,
+			relations: []string{auth.InviteRelation, auth.CreateRelation},
+			err:       nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.DelegateGroupAdmin(context.Background(), tc.token, group.ID, "delegate", tc.relations...)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	admins, err := svc.ListGroupAdmins(context.Background(), ownerSecret, group.ID)
+	require.Nil(t, err, fmt.Sprintf("listing group admins expected to succeed: %s", err))
+	assert.Len(t, admins, 2, fmt.Sprintf("listing group admins: expected 2 admin relations got %d\n", len(admins)))
+
+	err = svc.Assign(context.Background(), delegateSecret, group.ID, auth.UsersResource, "member")
+	assert.Nil(t, err, fmt.Sprintf("assigning member as delegate expected to succeed: %s", err))
+	_, err = svc.CreateGroup(context.Background(), delegateSecret, auth.Group{Name: "child", ParentID: group.ID})
+	assert.Nil(t, err, fmt.Sprintf("creating sub-group as delegate expected to succeed: %s", err))
+	err = svc.DelegateGroupAdmin(context.Background(), delegateSecret, group.ID, "other", auth.InviteRelation)
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("delegating as delegate: expected %s got %s\n", auth.ErrAuthorization, err))
+
+	// The delegation doesn't extend to the sub-groups.
+	child, err := svc.CreateGroup(context.Background(), ownerSecret, auth.Group{Name: "owned", ParentID: group.ID})
+	require.Nil(t, err, fmt.Sprintf("creating sub-group as owner expected to succeed: %s", err))
+	err = svc.Assign(context.Background(), delegateSecret, child.ID, auth.UsersResource, "member")
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("assigning member to sub-group: expected %s got %s\n", auth.ErrAuthorization, err))
+
+	err = svc.RevokeGroupAdmin(context.Background(), ownerSecret, group.ID, "delegate", auth.InviteRelation)
+	assert.Nil(t, err, fmt.Sprintf("revoking group admin expected to succeed: %s", err))
+	err = svc.Unassign(context.Background(), delegateSecret, group.ID, "member")
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("unassigning member after revocation: expected %s got %s\n", auth.ErrAuthorization, err))
+}
+
+func TestSubtreeGroupAdmin(t *testing.T) {
+	svc := newService()
+	_, ownerSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "owner", Subject: "owner@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing owner's login key expected to succeed: %s", err))
+	_, adminSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "admin", Subject: "admin@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing subtree admin's login key expected to succeed: %s", err))
+
+	root, err := svc.CreateGroup(context.Background(), ownerSecret, auth.Group{Name: "root"})
+	require.Nil(t, err, fmt.Sprintf("Creating group expected to succeed: %s", err))
+	child, err := svc.CreateGroup(context.Background(), ownerSecret, auth.Group{Name: "child", ParentID: root.ID})
+	require.Nil(t, err, fmt.Sprintf("Creating sub-group expected to succeed: %s", err))
+	other, err := svc.CreateGroup(context.Background(), ownerSecret, auth.Group{Name: "other"})
+	require.Nil(t, err, fmt.Sprintf("Creating group expected to succeed: %s", err))
+
+	err = svc.Assign(context.Background(), ownerSecret, root.ID, auth.UsersResource, "root-user")
+	require.Nil(t, err, fmt.Sprintf("Assigning user expected to succeed: %s", err))
+	err = svc.Assign(context.Background(), ownerSecret, child.ID, auth.ThingsResource, "child-thing")
+	require.Nil(t, err, fmt.Sprintf("Assigning thing expected to succeed: %s", err))
+	err = svc.Assign(context.Background(), ownerSecret, other.ID, auth.ThingsResource, "other-thing")
+	require.Nil(t, err, fmt.Sprintf("Assigning thing expected to succeed: %s", err))
+
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: "child-thing", Relation: "write", Subject: "admin"})
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("authorizing without delegation: expected %s got %s\n", auth.ErrAuthorization, err))
+
+	err = svc.DelegateGroupAdmin(context.Background(), ownerSecret, root.ID, "admin", auth.AdminRelation)
+	require.Nil(t, err, fmt.Sprintf("Delegating subtree admin expected to succeed: %s", err))
+
+	cases := []struct {
+		desc     string
+		object   string
+		relation string
+		err      error
+	}{
+		{
+			desc:     "authorize subtree admin on the user of the group",
+			object:   "root-user",
+			relation: "write",
+			err:      nil,
+		},
+		{
+			desc:     "authorize subtree admin on the thing of the sub-group",
+			object:   "child-thing",
+			relation: "delete",
+			err:      nil,
+		},
+		{
+			desc:     "authorize subtree admin on the thing outside of the subtree",
+			object:   "other-thing",
+			relation: "write",
+			err:      auth.ErrAuthorization,
+		},
+		{
+			desc:     "authorize subtree admin as the platform admin",
+			object:   authoritiesObj,
+			relation: memberRelation,
+			err:      auth.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Authorize(context.Background(), auth.PolicyReq{Object: tc.object, Relation: tc.relation, Subject: "admin"})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := svc.ListPolicies(context.Background(), auth.PolicyReq{Subject: "admin", Relation: "read"})
+	require.Nil(t, err, fmt.Sprintf("Listing policies expected to succeed: %s", err))
+	assert.ElementsMatch(t, []string{"root-user", "child-thing"}, page.Policies, fmt.Sprintf("listing policies: expected managed members got %v\n", page.Policies))
+
+	// The subtree admin holds the other admin relations on the sub-groups.
+	err = svc.Assign(context.Background(), adminSecret, child.ID, auth.ChannelsResource, "child-channel")
+	assert.Nil(t, err, fmt.Sprintf("assigning member to sub-group as subtree admin expected to succeed: %s", err))
+	_, err = svc.CreateGroup(context.Background(), adminSecret, auth.Group{Name: "grandchild", ParentID: child.ID})
+	assert.Nil(t, err, fmt.Sprintf("creating sub-group as subtree admin expected to succeed: %s", err))
+	err = svc.Assign(context.Background(), adminSecret, other.ID, auth.ThingsResource, "thing")
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("assigning member outside of the subtree: expected %s got %s\n", auth.ErrAuthorization, err))
+	err = svc.DelegateGroupAdmin(context.Background(), adminSecret, child.ID, "other", auth.AdminRelation)
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("delegating as subtree admin: expected %s got %s\n", auth.ErrAuthorization, err))
+
+	err = svc.RevokeGroupAdmin(context.Background(), ownerSecret, root.ID, "admin", auth.AdminRelation)
+	require.Nil(t, err, fmt.Sprintf("Revoking subtree admin expected to succeed: %s", err))
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: "child-thing", Relation: "write", Subject: "admin"})
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("authorizing after revocation: expected %s got %s\n", auth.ErrAuthorization, err))
+}
+
+func TestRevokeExpiredMemberships(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	group, err := svc.CreateGroup(context.Background(), secret, auth.Group{Name: groupName})
+	require.Nil(t, err, fmt.Sprintf("group save got unexpected error: %s", err))
+
+	temporary, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	permanent, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	expiresAt := time.Now().Add(100 * time.Millisecond)
+	err = svc.AssignTemporary(context.Background(), secret, group.ID, "users", expiresAt, temporary)
+	require.Nil(t, err, fmt.Sprintf("assigning temporary member expected to succeed: %s", err))
+	err = svc.Assign(context.Background(), secret, group.ID, "users", permanent)
+	require.Nil(t, err, fmt.Sprintf("assigning permanent member expected to succeed: %s", err))
+
+	err = svc.RevokeExpiredMemberships(context.Background())
+	require.Nil(t, err, fmt.Sprintf("revoking expired memberships expected to succeed: %s", err))
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: group.ID, Relation: memberRelation, Subject: temporary})
+	assert.Nil(t, err, fmt.Sprintf("authorizing member before deadline expected to succeed: %s", err))
+
+	time.Sleep(time.Until(expiresAt) + 10*time.Millisecond)
+
+	err = svc.RevokeExpiredMemberships(context.Background())
+	require.Nil(t, err, fmt.Sprintf("revoking expired memberships expected to succeed: %s", err))
+
+	cases := []struct {
+		desc     string
+		memberID string
+		err      error
+	}{
+		{
+			desc:     "authorize member with expired membership",
+			memberID: temporary,
+			err:      auth.ErrAuthorization,
+		},
+		{
+			desc:     "authorize member with permanent membership",
+			memberID: permanent,
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Authorize(context.Background(), auth.PolicyReq{Object: group.ID, Relation: memberRelation, Subject: tc.memberID})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	mp, err := svc.ListMembers(context.Background(), secret, group.ID, "users", auth.PageMetadata{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("retrieving members expected to succeed: %s", err))
+	assert.Equal(t, uint64(1), mp.Total, fmt.Sprintf("retrieve members of a group: expected %d got %d\n", 1, mp.Total))
+}
+
+func TestAuthorize(t *testing.T) {
+	svc := newService()
+
+	pr := auth.PolicyReq{Object: authoritiesObj, Relation: memberRelation, Subject: id}
+	err := svc.Authorize(context.Background(), pr)
+	require.Nil(t, err, fmt.Sprintf("authorizing initial %v policy expected to succeed: %s", pr, err))
+}
+
+func TestAuthorizeScopedKey(t *testing.T) {
+	svc := newService()
+	_, loginSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	require.Nil(t, err, fmt.Sprintf("issuing login key expected to succeed: %s", err))
+
+	key := auth.Key{Type: auth.APIKey, IssuedAt: time.Now(), Scopes: []string{"things:read"}}
+	_, scoped, err := svc.Issue(context.Background(), loginSecret, key)
+	require.Nil(t, err, fmt.Sprintf("issuing scoped API key expected to succeed: %s", err))
+
+	key.Scopes = nil
+	_, unscoped, err := svc.Issue(context.Background(), loginSecret, key)
+	require.Nil(t, err, fmt.Sprintf("issuing API key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		sub   string
+		scope string
+		err   error
+	}{
+		{
+			desc:  "authorize scoped action",
+			token: scoped,
+			sub:   id,
+			scope: "things:read",
+			err:   nil,
+		},
+		{
+			desc:  "authorize action out of scope",
+			token: scoped,
+			sub:   id,
+			scope: "things:write",
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:  "authorize action without scope",
+			token: scoped,
+			sub:   id,
+			scope: "",
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:  "authorize other subject",
+			token: scoped,
+			sub:   "other",
+			scope: "things:read",
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:  "authorize with unscoped key",
+			token: unscoped,
+			sub:   id,
+			scope: "channels:delete",
+			err:   nil,
+		},
+		{
+			desc:  "authorize with invalid token",
+			token: "invalid",
+			sub:   id,
+			scope: "things:read",
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		pr := auth.PolicyReq{Object: authoritiesObj, Relation: memberRelation, Subject: tc.sub, Token: tc.token, Scope: tc.scope}
+		err := svc.Authorize(context.Background(), pr)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestAddPolicy(t *testing.T) {
+	svc := newService()
+
+	pr := auth.PolicyReq{Object: "obj", Relation: "rel", Subject: "sub"}
+	err := svc.AddPolicy(context.Background(), pr)
+	require.Nil(t, err, fmt.Sprintf("adding %v policy expected to succeed: %v", pr, err))
+
+	err = svc.Authorize(context.Background(), pr)
+	require.Nil(t, err, fmt.Sprintf("checking shared %v policy expected to be succeed: %#v", pr, err))
+}
+
+func TestAddPolicyWithTTL(t *testing.T) {
+	svc := newService()
+
+	ttl := 50 * time.Millisecond
+	cases := []struct {
+		desc       string
+		policy     auth.PolicyReq
+		permanent  bool
+		err        error
+		authorized bool
+	}{
+		{
+			desc:       "add temporary policy",
+			policy:     auth.PolicyReq{Object: "obj", Relation: "read", Subject: "temporary", TTL: ttl},
+			err:        nil,
+			authorized: false,
+		},
+		{
+			desc:       "add temporary policy made permanent",
+			policy:     auth.PolicyReq{Object: "obj", Relation: "read", Subject: "permanent", TTL: ttl},
+			permanent:  true,
+			err:        nil,
+			authorized: true,
+		},
+		{
+			desc:       "add policy with negative TTL",
+			policy:     auth.PolicyReq{Object: "obj", Relation: "read", Subject: "negative", TTL: -ttl},
+			err:        auth.ErrMalformedEntity,
+			authorized: false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AddPolicy(context.Background(), tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		pr := tc.policy
+		pr.TTL = 0
+		err = svc.Authorize(context.Background(), pr)
+		assert.Nil(t, err, fmt.Sprintf("%s: checking policy before deadline expected to succeed: %s", tc.desc, err))
+
+		if tc.permanent {
+			err := svc.AddPolicy(context.Background(), pr)
+			require.Nil(t, err, fmt.Sprintf("%s: adding permanent policy expected to succeed: %s", tc.desc, err))
+		}
+	}
+
+	time.Sleep(2 * ttl)
+
+	for _, tc := range cases {
+		pr := tc.policy
+		pr.TTL = 0
+		authorized := svc.Authorize(context.Background(), pr) == nil
+		assert.Equal(t, tc.authorized, authorized, fmt.Sprintf("%s: expected authorized %t after deadline got %t\n", tc.desc, tc.authorized, authorized))
+	}
+}
+
+func TestApplyPolicyTemplate(t *testing.T) {
+	svc := newService()
+
+	admins := fmt.Sprintf("members:%s#%s", authoritiesObj, memberRelation)
+	cases := []struct {
+		desc     string
+		entity   auth.Entity
+		policies []auth.PolicyReq
+		err      error
+	}{
+		{
+			desc:   "apply user template",
+			entity: auth.Entity{Type: auth.UserEntity, ID: "user"},
+			policies: []auth.PolicyReq{
+				{Subject: "user", Object: "users", Relation: memberRelation},
+			},
+			err: nil,
+		},
+		{
+			desc:   "apply thing template",
+			entity: auth.Entity{Type: auth.ThingEntity, ID: "thing", Owner: "owner"},
+			policies: []auth.PolicyReq{
+				{Subject: "owner", Object: "thing", Relation: "read"},
+				{Subject: "owner", Object: "thing", Relation: "write"},
+				{Subject: "owner", Object: "thing", Relation: "delete"},
+				{Subject: admins, Object: "thing", Relation: "read"},
+				{Subject: admins, Object: "thing", Relation: "delete"},
+			},
+			err: nil,
+		},
+		{
+			desc:   "apply channel template",
+			entity: auth.Entity{Type: auth.ChannelEntity, ID: "channel", Owner: "owner"},
+			policies: []auth.PolicyReq{
+				{Subject: "owner", Object: "channel", Relation: "read"},
+				{Subject: admins, Object: "channel", Relation: "write"},
+			},
+			err: nil,
+		},
+		{
+			desc:   "apply thing template without owner",
+			entity: auth.Entity{Type: auth.ThingEntity, ID: "ownerless"},
+			err:    auth.ErrMalformedEntity,
+		},
+		{
+			desc:   "apply template of unknown entity type",
+			entity: auth.Entity{Type: "unknown", ID: "unknown", Owner: "owner"},
+			err:    auth.ErrMalformedEntity,
+		},
+		{
+			desc:   "apply template without entity ID",
+			entity: auth.Entity{Type: auth.ThingEntity, Owner: "owner"},
+			err:    auth.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ApplyPolicyTemplate(context.Background(), tc.entity)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		for _, pr := range tc.policies {
+			err := svc.Authorize(context.Background(), pr)
+			assert.Nil(t, err, fmt.Sprintf("%s: expected %v policy to be added: %s", tc.desc, pr, err))
+		}
+	}
+
+	err := svc.Authorize(context.Background(), auth.PolicyReq{Subject: "owner", Object: "ownerless", Relation: "read"})
+	assert.NotNil(t, err, "expected no policies of the failed template to be added")
+}
+
+func TestDeletePolicy(t *testing.T) {
+	svc := newService()
+
+	pr := auth.PolicyReq{Object: authoritiesObj, Relation: memberRelation, Subject: id}
+	err := svc.DeletePolicy(context.Background(), pr)
+	require.Nil(t, err, fmt.Sprintf("deleting %v policy expected to succeed: %s", pr, err))
+}
+
+func TestAssignAccessRights(t *testing.T) {
+	svc := newService()
+
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
+
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+
+	userGroupID := "user-group"
+	thingGroupID := "thing-group"
+	err = svc.AssignGroupAccessRights(context.Background(), apiToken, thingGroupID, userGroupID)
+	require.Nil(t, err, fmt.Sprintf("sharing the user group with thing group expected to succeed: %v", err))
+
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: thingGroupID, Relation: memberRelation, Subject: fmt.Sprintf("%s:%s#%s", "members", userGroupID, memberRelation)})
+	require.Nil(t, err, fmt.Sprintf("checking shared group access policy expected to be succeed: %#v", err))
+}
+
+func TestAddPolicies(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
+
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+
+	thingID, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	tmpID := "tmpid"
+	readPolicy := "read"
+	writePolicy := "write"
+	deletePolicy := "delete"
+
+	// Add read policy to users.
+	err = svc.AddPolicies(context.Background(), apiToken, thingID, []string{id, tmpID}, []string{readPolicy})
+	assert.Nil(t, err, fmt.Sprintf("adding policies expected to succeed: %s", err))
+
+	// Add write and delete policies to users.
+	err = svc.AddPolicies(context.Background(), apiToken, thingID, []string{id, tmpID}, []string{writePolicy, deletePolicy})
+	assert.Nil(t, err, fmt.Sprintf("adding multiple policies expected to succeed: %s", err))
+
+	cases := []struct {
+		desc   string
+		policy auth.PolicyReq
+		err    error
+	}{
+		{
+			desc:   "check valid 'read' policy of user with id",
+			policy: auth.PolicyReq{Object: thingID, Relation: readPolicy, Subject: id},
+			err:    nil,
+		},
+		{
+			desc:   "check valid 'write' policy of user with id",
+			policy: auth.PolicyReq{Object: thingID, Relation: writePolicy, Subject: id},
+			err:    nil,
+		},
+		{
+			desc:   "check valid 'delete' policy of user with id",
+			policy: auth.PolicyReq{Object: thingID, Relation: deletePolicy, Subject: id},
+			err:    nil,
+		},
+		{
+			desc:   "check valid 'read' policy of user with tmpid",
+			policy: auth.PolicyReq{Object: thingID, Relation: readPolicy, Subject: tmpID},
+			err:    nil,
+		},
+		{
+			desc:   "check valid 'write' policy of user with tmpid",
+			policy: auth.PolicyReq{Object: thingID, Relation: writePolicy, Subject: tmpID},
+			err:    nil,
+		},
+		{
+			desc:   "check valid 'delete' policy of user with tmpid",
+			policy: auth.PolicyReq{Object: thingID, Relation: deletePolicy, Subject: tmpID},
+			err:    nil,
+		},
+		{
+			desc:   "check invalid 'access' policy of user with id",
+			policy: auth.PolicyReq{Object: thingID, Relation: "access", Subject: id},
+			err:    auth.ErrAuthorization,
+		},
+		{
+			desc:   "check invalid 'access' policy of user with tmpid",
+			policy: auth.PolicyReq{Object: thingID, Relation: "access", Subject: tmpID},
+			err:    auth.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Authorize(context.Background(), tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %v, got %v", tc.desc, tc.err, err))
+	}
+}
+
+func TestDeletePolicies(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
+
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+
+	thingID, err := idProvider.ID()
+	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	tmpID := "tmpid"
+	readPolicy := "read"
+	writePolicy := "write"
+	deletePolicy := "delete"
+	memberPolicy := "member"
+
+	// Add read, write and delete policies to users.
+	err = svc.AddPolicies(context.Background(), apiToken, thingID, []string{id, tmpID}, []string{readPolicy, writePolicy, deletePolicy, memberPolicy})
+	assert.Nil(t, err, fmt.Sprintf("adding policies expected to succeed: %s", err))
+
+	// Delete multiple policies from single user.
+	err = svc.DeletePolicies(context.Background(), apiToken, thingID, []string{id}, []string{readPolicy, writePolicy})
+	assert.Nil(t, err, fmt.Sprintf("deleting policies from single user expected to succeed: %s", err))
+
+	// Delete multiple policies from multiple user.
+	err = svc.DeletePolicies(context.Background(), apiToken, thingID, []string{id, tmpID}, []string{deletePolicy, memberPolicy})
+	assert.Nil(t, err, fmt.Sprintf("deleting policies from multiple user expected to succeed: %s", err))
+
+	cases := []struct {
+		desc   string
+		policy auth.PolicyReq
+		err    error
+	}{
+		{
+			desc:   "check non-existing 'read' policy of user with id",
+			policy: auth.PolicyReq{Object: thingID, Relation: readPolicy, Subject: id},
+			err:    auth.ErrAuthorization,
+		},
+		{
+			desc:   "check non-existing 'write' policy of user with id",
+			policy: auth.PolicyReq{Object: thingID, Relation: writePolicy, Subject: id},
+			err:    auth.ErrAuthorization,
+		},
+		{
+			desc:   "check non-existing 'delete' policy of user with id",
+			policy: auth.PolicyReq{Object: thingID, Relation: deletePolicy, Subject: id},
+			err:    auth.ErrAuthorization,
+		},
+		{
+			desc:   "check non-existing 'member' policy of user with id",
+			policy: auth.PolicyReq{Object: thingID, Relation: memberPolicy, Subject: id},
+			err:    auth.ErrAuthorization,
+		},
+		{
+			desc:   "check non-existing 'delete' policy of user with tmpid",
+			policy: auth.PolicyReq{Object: thingID, Relation: deletePolicy, Subject: tmpID},
+			err:    auth.ErrAuthorization,
+		},
+		{
+			desc:   "check non-existing 'member' policy of user with tmpid",
+			policy: auth.PolicyReq{Object: thingID, Relation: memberPolicy, Subject: tmpID},
+			err:    auth.ErrAuthorization,
+		},
+		{
+			desc:   "check valid 'read' policy of user with tmpid",
+			policy: auth.PolicyReq{Object: thingID, Relation: readPolicy, Subject: tmpID},
+			err:    nil,
+		},
+		{
+			desc:   "check valid 'write' policy of user with tmpid",
+			policy: auth.PolicyReq{Object: thingID, Relation: writePolicy, Subject: tmpID},
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Authorize(context.Background(), tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %v, got %v", tc.desc, tc.err, err))
+	}
+}
+
+func TestListPolicies(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	key := auth.Key{
+		ID:       "id",
+		Type:     auth.APIKey,
+		IssuerID: id,
+		Subject:  email,
+		IssuedAt: time.Now(),
+	}
+
+	_, apiToken, err := svc.Issue(context.Background(), secret, key)
+	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+
+	readPolicy := "read"
+	pageLen := 15
+
+	// Add arbitrary policies to the user.
+	for i := 0; i < pageLen; i++ {
+		err = svc.AddPolicies(context.Background(), apiToken, fmt.Sprintf("thing-%d", i), []string{id}, []string{readPolicy})
+		assert.Nil(t, err, fmt.Sprintf("adding policies expected to succeed: %s", err))
+	}
+
+	page, err := svc.ListPolicies(context.Background(), auth.PolicyReq{Subject: id, Relation: readPolicy})
+	assert.Nil(t, err, fmt.Sprintf("listing policies expected to succeed: %s", err))
+	assert.Equal(t, pageLen, len(page.Policies), fmt.Sprintf("unexpected listing page size, expected %d, got %d: %v", pageLen, len(page.Policies), err))
+
+	page, err = svc.ListPolicies(context.Background(), auth.PolicyReq{Object: "thing-0", Relation: readPolicy})
+	assert.Nil(t, err, fmt.Sprintf("listing policy subjects expected to succeed: %s", err))
+	assert.Equal(t, []string{id}, page.Policies, fmt.Sprintf("listing policy subjects: expected %v, got %v", []string{id}, page.Policies))
+}
+
+func TestExplainPolicy(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	memberID := "member"
+	_, memberSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: memberID, Subject: "member@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing member's login key expected to succeed: %s", err))
+
+	groupSet := fmt.Sprintf("%s:group#%s", auth.MembersNamespace, memberRelation)
+	thingPolicy := auth.PolicyReq{Subject: groupSet, Object: "thing", Relation: "read", Namespace: auth.ThingsNamespace}
+	groupPolicy := auth.PolicyReq{Subject: memberID, Object: "group", Relation: memberRelation}
+	for _, pr := range []auth.PolicyReq{thingPolicy, groupPolicy} {
+		err := svc.AddPolicy(context.Background(), pr)
+		require.Nil(t, err, fmt.Sprintf("adding policy expected to succeed: %s", err))
+	}
+
+	cases := []struct {
+		desc  string
+		token string
+		pr    auth.PolicyReq
+		expl  auth.Explanation
+		err   error
+	}{
+		{
+			desc:  "explain access granted by subject set",
+			token: memberSecret,
+			pr:    auth.PolicyReq{Subject: memberID, Object: "thing", Relation: "read", Namespace: auth.ThingsNamespace},
+			expl: auth.Explanation{
+				Allowed:  true,
+				Reason:   "granted by the policy path",
+				Path:     []auth.PolicyReq{{Subject: groupSet, Object: "thing", Relation: "read"}, {Subject: memberID, Object: "group", Relation: memberRelation}},
+				Expanded: []string{"things:thing#read", groupSet},
+			},
+			err: nil,
+		},
+		{
+			desc:  "explain access granted by direct policy",
+			token: secret,
+			pr:    auth.PolicyReq{Subject: id, Object: authoritiesObj, Relation: memberRelation},
+			expl: auth.Explanation{
+				Allowed:  true,
+				Reason:   "granted by the policy path",
+				Path:     []auth.PolicyReq{{Subject: id, Object: authoritiesObj, Relation: memberRelation}},
+				Expanded: []string{fmt.Sprintf("%s:%s#%s", auth.MembersNamespace, authoritiesObj, memberRelation)},
+			},
+			err: nil,
+		},
+		{
+			desc:  "explain denied access",
+			token: memberSecret,
+			pr:    auth.PolicyReq{Subject: memberID, Object: "thing", Relation: "write", Namespace: auth.ThingsNamespace},
+			expl: auth.Explanation{
+				Reason:   "no policy path grants the relation",
+				Expanded: []string{"things:thing#write"},
+			},
+			err: nil,
+		},
+		{
+			desc:  "explain other user's access as admin",
+			token: secret,
+			pr:    auth.PolicyReq{Subject: memberID, Object: "group", Relation: memberRelation},
+			expl: auth.Explanation{
+				Allowed:  true,
+				Reason:   "granted by the policy path",
+				Path:     []auth.PolicyReq{groupPolicy},
+				Expanded: []string{groupSet},
+			},
+			err: nil,
+		},
+		{
+			desc:  "explain other user's access as non-admin",
+			token: memberSecret,
+			pr:    auth.PolicyReq{Subject: id, Object: authoritiesObj, Relation: memberRelation},
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:  "explain access with invalid token",
+			token: "invalid",
+			pr:    auth.PolicyReq{Subject: memberID, Object: "thing", Relation: "read"},
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		expl, err := svc.ExplainPolicy(context.Background(), tc.token, tc.pr)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.expl, expl, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.expl, expl))
+	}
+}
+
+func TestExplainPolicyDecision(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+	_, adminSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "admin", Subject: "admin@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing subtree admin's login key expected to succeed: %s", err))
+
+	group, err := svc.CreateGroup(context.Background(), secret, auth.Group{Name: groupName})
+	require.Nil(t, err, fmt.Sprintf("Creating group expected to succeed: %s", err))
+	err = svc.Assign(context.Background(), secret, group.ID, auth.UsersResource, "group-user")
+	require.Nil(t, err, fmt.Sprintf("Assigning user expected to succeed: %s", err))
+	err = svc.DelegateGroupAdmin(context.Background(), secret, group.ID, "admin", auth.AdminRelation)
+	require.Nil(t, err, fmt.Sprintf("Delegating subtree admin expected to succeed: %s", err))
+
+	org, err := svc.CreateOrg(context.Background(), secret, auth.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("creating org expected to succeed: %s", err))
+	_, orgSecret, err := svc.SwitchOrg(context.Background(), secret, org.ID)
+	require.Nil(t, err, fmt.Sprintf("switching org expected to succeed: %s", err))
+
+	ttl := 50 * time.Millisecond
+	expiring := auth.PolicyReq{Subject: id, Object: "obj", Relation: "read", TTL: ttl}
+	err = svc.AddPolicy(context.Background(), expiring)
+	require.Nil(t, err, fmt.Sprintf("adding temporary policy expected to succeed: %s", err))
+	time.Sleep(2 * ttl)
+
+	cases := []struct {
+		desc  string
+		token string
+		pr    auth.PolicyReq
+		expl  auth.Explanation
+	}{
+		{
+			desc:  "explain access granted to subtree admin",
+			token: adminSecret,
+			pr:    auth.PolicyReq{Subject: "admin", Object: "group-user", Relation: "write"},
+			expl: auth.Explanation{
+				Allowed:  true,
+				Reason:   "granted by the subtree admin",
+				Expanded: []string{fmt.Sprintf("%s:group-user#write", auth.MembersNamespace)},
+			},
+		},
+		{
+			desc:  "explain access to group of other organization",
+			token: orgSecret,
+			pr:    auth.PolicyReq{Subject: id, Object: group.ID, Relation: "read"},
+			expl:  auth.Explanation{Reason: "denied by the key"},
+		},
+		{
+			desc:  "explain access granted by expired policy",
+			token: secret,
+			pr:    auth.PolicyReq{Subject: id, Object: "obj", Relation: "read"},
+			expl: auth.Explanation{
+				Reason:   "no policy path grants the relation",
+				Expanded: []string{fmt.Sprintf("%s:obj#read", auth.MembersNamespace)},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		expl, err := svc.ExplainPolicy(context.Background(), tc.token, tc.pr)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.expl, expl, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.expl, expl))
+	}
+
+	// Explaining the access doesn't revoke the expired policy.
+	policies, err := svc.InspectPolicies(context.Background(), secret, auth.PolicyReq{Subject: id, Object: "obj"})
+	require.Nil(t, err, fmt.Sprintf("inspecting policies expected to succeed: %s", err))
+	assert.Len(t, policies, 1, fmt.Sprintf("expected expired policy to be kept got %v\n", policies))
+}
+
+func TestShare(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	grantorID := "grantor"
+	_, grantorSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: grantorID, Subject: "grantor@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing grantor's login key expected to succeed: %s", err))
+
+	thingID := "thing"
+	err = svc.AddPolicy(context.Background(), auth.PolicyReq{Object: thingID, Relation: "read", Subject: grantorID})
+	require.Nil(t, err, fmt.Sprintf("adding read policy expected to succeed: %s", err))
+
+	granteeID := "grantee"
+	cases := []struct {
+		desc    string
+		token   string
+		share   auth.Share
+		actions []string
+		err     error
+	}{
+		{
+			desc:    "share thing as owner",
+			token:   grantorSecret,
+			share:   auth.Share{GranteeID: granteeID, Object: thingID, Actions: []string{"read"}},
+			actions: []string{"read"},
+			err:     nil,
+		},
+		{
+			desc:  "share the action the grantor is not allowed to perform",
+			token: grantorSecret,
+			share: auth.Share{GranteeID: granteeID, Object: thingID, Actions: []string{"write"}},
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:    "share the action granted by another share as admin",
+			token:   secret,
+			share:   auth.Share{GranteeID: granteeID, Object: thingID, Actions: []string{"read", "write"}},
+			actions: []string{"read", "write"},
+			err:     nil,
+		},
+		{
+			desc:  "share with oneself",
+			token: grantorSecret,
+			share: auth.Share{GranteeID: grantorID, Object: thingID, Actions: []string{"read"}},
+			err:   auth.ErrMalformedEntity,
+		},
+		{
+			desc:  "share with invalid token",
+			token: "invalid",
+			share: auth.Share{GranteeID: granteeID, Object: thingID, Actions: []string{"read"}},
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		s, err := svc.Share(context.Background(), tc.token, tc.share)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.actions, s.Actions, fmt.Sprintf("%s: expected actions %v got %v\n", tc.desc, tc.actions, s.Actions))
+	}
+
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "write", Subject: granteeID})
+	assert.Nil(t, err, fmt.Sprintf("checking shared policy expected to succeed: %s", err))
+}
+
+func TestListShares(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	granteeID := "grantee"
+	_, granteeSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: granteeID, Subject: "grantee@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing grantee's login key expected to succeed: %s", err))
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := svc.Share(context.Background(), secret, auth.Share{GranteeID: granteeID, Object: fmt.Sprintf("thing-%d", i), Actions: []string{"read"}})
+		require.Nil(t, err, fmt.Sprintf("sharing thing expected to succeed: %s", err))
+	}
+
+	cases := []struct {
+		desc     string
+		token    string
+		received bool
+		size     int
+		err      error
+	}{
+		{
+			desc:     "list granted shares",
+			token:    secret,
+			received: false,
+			size:     n,
+			err:      nil,
+		},
+		{
+			desc:     "list received shares",
+			token:    granteeSecret,
+			received: true,
+			size:     n,
+			err:      nil,
+		},
+		{
+			desc:     "list granted shares without any",
+			token:    granteeSecret,
+			received: false,
+			size:     0,
+			err:      nil,
+		},
+		{
+			desc:     "list shares with invalid token",
+			token:    "invalid",
+			received: false,
+			size:     0,
+			err:      auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		shares, err := svc.ListShares(context.Background(), tc.token, tc.received)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(shares), fmt.Sprintf("%s: expected %d shares got %d\n", tc.desc, tc.size, len(shares)))
+	}
+}
+
+func TestRevokeShare(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	otherID := "other"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+
+	granteeID := "grantee"
+	thingID := "thing"
+	first, err := svc.Share(context.Background(), secret, auth.Share{GranteeID: granteeID, Object: thingID, Actions: []string{"read", "write"}})
+	require.Nil(t, err, fmt.Sprintf("sharing thing expected to succeed: %s", err))
+	second, err := svc.Share(context.Background(), secret, auth.Share{GranteeID: granteeID, Object: thingID, Actions: []string{"read"}})
+	require.Nil(t, err, fmt.Sprintf("sharing thing expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+		read  bool
+		write bool
+	}{
+		{
+			desc:  "revoke share as unrelated user",
+			token: otherSecret,
+			id:    first.ID,
+			err:   auth.ErrAuthorization,
+			read:  true,
+			write: true,
+		},
+		{
+			desc:  "revoke share with the action granted by another share",
+			token: secret,
+			id:    first.ID,
+			err:   nil,
+			read:  true,
+			write: false,
+		},
+		{
+			desc:  "revoke last share of the object",
+			token: secret,
+			id:    second.ID,
+			err:   nil,
+			read:  false,
+			write: false,
+		},
+		{
+			desc:  "revoke non-existing share",
+			token: secret,
+			id:    first.ID,
+			err:   auth.ErrNotFound,
+			read:  false,
+			write: false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RevokeShare(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		read := svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "read", Subject: granteeID}) == nil
+		assert.Equal(t, tc.read, read, fmt.Sprintf("%s: expected read access %t got %t\n", tc.desc, tc.read, read))
+		write := svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "write", Subject: granteeID}) == nil
+		assert.Equal(t, tc.write, write, fmt.Sprintf("%s: expected write access %t got %t\n", tc.desc, tc.write, write))
+	}
+}
+
+func TestCreateRole(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	otherID := "other"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		role  auth.Role
+		err   error
+	}{
+		{
+			desc:  "create role as admin",
+			token: secret,
+			role:  auth.Role{Name: "operator", Relations: []string{"read", "access"}},
+			err:   nil,
+		},
+		{
+			desc:  "create existing role",
+			token: secret,
+			role:  auth.Role{Name: "operator", Relations: []string{"read"}},
+			err:   auth.ErrConflict,
+		},
+		{
+			desc:  "create role with builtin name",
+			token: secret,
+			role:  auth.Role{Name: auth.ViewerRole, Relations: []string{"read"}},
+			err:   auth.ErrConflict,
+		},
+		{
+			desc:  "create role without relations",
+			token: secret,
+			role:  auth.Role{Name: "empty"},
+			err:   auth.ErrMalformedEntity,
+		},
+		{
+			desc:  "create role as non-admin",
+			token: otherSecret,
+			role:  auth.Role{Name: "reader", Relations: []string{"read"}},
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:  "create role with invalid token",
+			token: "invalid",
+			role:  auth.Role{Name: "reader", Relations: []string{"read"}},
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateRole(context.Background(), tc.token, tc.role)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	roles, err := svc.ListRoles(context.Background(), otherSecret)
+	assert.Nil(t, err, fmt.Sprintf("listing roles expected to succeed: %s", err))
+	assert.Equal(t, 4, len(roles), fmt.Sprintf("expected %d roles got %d\n", 4, len(roles)))
+}
+
+func TestAssignRole(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	editorID := "editor"
+	_, editorSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: editorID, Subject: "editor@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing editor's login key expected to succeed: %s", err))
+
+	thingID := "thing"
+	_, err = svc.AssignRole(context.Background(), secret, auth.RoleAssignment{Role: auth.EditorRole, Subject: editorID, Object: thingID})
+	require.Nil(t, err, fmt.Sprintf("assigning editor role expected to succeed: %s", err))
+
+	userID := "user"
+	cases := []struct {
+		desc       string
+		token      string
+		assignment auth.RoleAssignment
+		relations  []string
+		err        error
+	}{
+		{
+			desc:       "assign role the user has the relations of",
+			token:      editorSecret,
+			assignment: auth.RoleAssignment{Role: auth.ViewerRole, Subject: userID, Object: thingID},
+			relations:  []string{"read"},
+			err:        nil,
+		},
+		{
+			desc:       "assign role the user doesn't have the relations of",
+			token:      editorSecret,
+			assignment: auth.RoleAssignment{Role: auth.AdminRole, Subject: userID, Object: thingID},
+			err:        auth.ErrAuthorization,
+		},
+		{
+			desc:       "assign role overlapping with another assignment as admin",
+			token:      secret,
+			assignment: auth.RoleAssignment{Role: auth.EditorRole, Subject: userID, Object: thingID},
+			relations:  []string{"read", "write", "access"},
+			err:        nil,
+		},
+		{
+			desc:       "assign already assigned role",
+			token:      secret,
+			assignment: auth.RoleAssignment{Role: auth.ViewerRole, Subject: userID, Object: thingID},
+			err:        auth.ErrConflict,
+		},
+		{
+			desc:       "assign non-existing role",
+			token:      secret,
+			assignment: auth.RoleAssignment{Role: "non-existing", Subject: userID, Object: thingID},
+			err:        auth.ErrNotFound,
+		},
+		{
+			desc:       "assign role without object",
+			token:      secret,
+			assignment: auth.RoleAssignment{Role: auth.ViewerRole, Subject: userID},
+			err:        auth.ErrMalformedEntity,
+		},
+		{
+			desc:       "assign role with invalid token",
+			token:      "invalid",
+			assignment: auth.RoleAssignment{Role: auth.ViewerRole, Subject: userID, Object: thingID},
+			err:        auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		ra, err := svc.AssignRole(context.Background(), tc.token, tc.assignment)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.relations, ra.Relations, fmt.Sprintf("%s: expected relations %v got %v\n", tc.desc, tc.relations, ra.Relations))
+	}
+
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "write", Subject: userID})
+	assert.Nil(t, err, fmt.Sprintf("checking assigned policy expected to succeed: %s", err))
+}
+
+func TestUnassignRole(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	otherID := "other"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+
+	userID := "user"
+	thingID := "thing"
+	editor := auth.RoleAssignment{Role: auth.EditorRole, Subject: userID, Object: thingID}
+	viewer := auth.RoleAssignment{Role: auth.ViewerRole, Subject: userID, Object: thingID}
+	_, err = svc.AssignRole(context.Background(), secret, editor)
+	require.Nil(t, err, fmt.Sprintf("assigning editor role expected to succeed: %s", err))
+	_, err = svc.AssignRole(context.Background(), secret, viewer)
+	require.Nil(t, err, fmt.Sprintf("assigning viewer role expected to succeed: %s", err))
+
+	cases := []struct {
+		desc       string
+		token      string
+		assignment auth.RoleAssignment
+		err        error
+		read       bool
+		write      bool
+	}{
+		{
+			desc:       "unassign role as unrelated user",
+			token:      otherSecret,
+			assignment: editor,
+			err:        auth.ErrAuthorization,
+			read:       true,
+			write:      true,
+		},
+		{
+			desc:       "unassign role with the relation assigned by another role",
+			token:      secret,
+			assignment: editor,
+			err:        nil,
+			read:       true,
+			write:      false,
+		},
+		{
+			desc:       "unassign last role of the object",
+			token:      secret,
+			assignment: viewer,
+			err:        nil,
+			read:       false,
+			write:      false,
+		},
+		{
+			desc:       "unassign non-existing assignment",
+			token:      secret,
+			assignment: editor,
+			err:        auth.ErrNotFound,
+			read:       false,
+			write:      false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UnassignRole(context.Background(), tc.token, tc.assignment)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+
+		read := svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "read", Subject: userID}) == nil
+		assert.Equal(t, tc.read, read, fmt.Sprintf("%s: expected read access %t got %t\n", tc.desc, tc.read, read))
+		write := svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "write", Subject: userID}) == nil
+		assert.Equal(t, tc.write, write, fmt.Sprintf("%s: expected write access %t got %t\n", tc.desc, tc.write, write))
+	}
+}
+
+func TestCreateServiceAccount(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	memberID := "member"
+	_, memberSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: memberID, Subject: "member@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing member's login key expected to succeed: %s", err))
+
+	otherID := "other"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: otherID, Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+
+	ownerID := "org"
+	err = svc.AddPolicy(context.Background(), auth.PolicyReq{Object: ownerID, Relation: memberRelation, Subject: memberID})
+	require.Nil(t, err, fmt.Sprintf("adding membership policy expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		sa    auth.ServiceAccount
+		err   error
+	}{
+		{
+			desc:  "create service account as member",
+			token: memberSecret,
+			sa:    auth.ServiceAccount{Name: "ci", OwnerID: ownerID, Scopes: []string{"read"}},
+			err:   nil,
+		},
+		{
+			desc:  "create service account as admin",
+			token: secret,
+			sa:    auth.ServiceAccount{Name: "cd", OwnerID: ownerID, Scopes: []string{"read", "write"}},
+			err:   nil,
+		},
+		{
+			desc:  "create service account with existing name",
+			token: memberSecret,
+			sa:    auth.ServiceAccount{Name: "ci", OwnerID: ownerID, Scopes: []string{"read"}},
+			err:   auth.ErrConflict,
+		},
+		{
+			desc:  "create service account as non-member",
+			token: otherSecret,
+			sa:    auth.ServiceAccount{Name: "other", OwnerID: ownerID, Scopes: []string{"read"}},
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:  "create service account without scopes",
+			token: memberSecret,
+			sa:    auth.ServiceAccount{Name: "empty", OwnerID: ownerID},
+			err:   auth.ErrMalformedEntity,
+		},
+		{
+			desc:  "create service account with empty scope",
+			token: memberSecret,
+			sa:    auth.ServiceAccount{Name: "empty", OwnerID: ownerID, Scopes: []string{""}},
+			err:   auth.ErrMalformedEntity,
+		},
+		{
+			desc:  "create service account with invalid token",
+			token: "invalid",
+			sa:    auth.ServiceAccount{Name: "invalid", OwnerID: ownerID, Scopes: []string{"read"}},
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		sa, err := svc.CreateServiceAccount(context.Background(), tc.token, tc.sa)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.NotEmpty(t, sa.ID, fmt.Sprintf("%s: expected service account ID\n", tc.desc))
+		}
+	}
+
+	accounts, err := svc.ListServiceAccounts(context.Background(), memberSecret, ownerID)
+	assert.Nil(t, err, fmt.Sprintf("listing service accounts expected to succeed: %s", err))
+	assert.Equal(t, 2, len(accounts), fmt.Sprintf("expected %d service accounts got %d\n", 2, len(accounts)))
+
+	_, err = svc.ListServiceAccounts(context.Background(), otherSecret, ownerID)
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("listing service accounts as non-member: expected %s got %s\n", auth.ErrAuthorization, err))
+}
+
+func TestServiceAccountKeys(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	_, _, err = svc.Issue(context.Background(), secret, auth.Key{Type: auth.ServiceAccountKey, IssuedAt: time.Now()})
+	assert.True(t, errors.Contains(err, auth.ErrMalformedEntity), fmt.Sprintf("issuing service account key directly: expected %s got %s\n", auth.ErrMalformedEntity, err))
+
+	sa, err := svc.CreateServiceAccount(context.Background(), secret, auth.ServiceAccount{Name: "ci", OwnerID: "org", Scopes: []string{"read"}})
+	require.Nil(t, err, fmt.Sprintf("creating service account expected to succeed: %s", err))
+
+	thingID := "thing"
+	for _, action := range []string{"read", "write"} {
+		err = svc.AddPolicy(context.Background(), auth.PolicyReq{Object: thingID, Relation: action, Subject: sa.ID})
+		require.Nil(t, err, fmt.Sprintf("adding %s policy expected to succeed: %s", action, err))
+	}
+
+	key, saSecret, err := svc.IssueServiceAccountKey(context.Background(), secret, sa.ID, time.Hour)
+	require.Nil(t, err, fmt.Sprintf("issuing service account key expected to succeed: %s", err))
+	assert.Equal(t, time.Hour, key.ExpiresAt.Sub(key.IssuedAt), fmt.Sprintf("expected key duration %s got %s\n", time.Hour, key.ExpiresAt.Sub(key.IssuedAt)))
+
+	identity, err := svc.Identify(context.Background(), saSecret)
+	assert.Nil(t, err, fmt.Sprintf("identifying service account expected to succeed: %s", err))
+	assert.Equal(t, sa.ID, identity.ID, fmt.Sprintf("expected identity %s got %s\n", sa.ID, identity.ID))
+
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "read", Subject: sa.ID})
+	assert.Nil(t, err, fmt.Sprintf("authorizing scoped action expected to succeed: %s", err))
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "write", Subject: sa.ID})
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("authorizing action out of scope: expected %s got %s\n", auth.ErrAuthorization, err))
+
+	_, err = svc.UpdateServiceAccountScopes(context.Background(), secret, sa.ID, []string{"read", "write"})
+	assert.Nil(t, err, fmt.Sprintf("updating service account scopes expected to succeed: %s", err))
+	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: thingID, Relation: "write", Subject: sa.ID})
+	assert.Nil(t, err, fmt.Sprintf("authorizing updated scope expected to succeed: %s", err))
+
+	rotated, rotatedSecret, err := svc.RotateServiceAccountKey(context.Background(), saSecret, sa.ID, key.ID)
+	assert.Nil(t, err, fmt.Sprintf("rotating service account key by itself expected to succeed: %s", err))
+	assert.Equal(t, time.Hour, rotated.ExpiresAt.Sub(rotated.IssuedAt), fmt.Sprintf("expected rotated key duration %s got %s\n", time.Hour, rotated.ExpiresAt.Sub(rotated.IssuedAt)))
+
+	_, err = svc.Identify(context.Background(), saSecret)
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("identifying with rotated key: expected %s got %s\n", auth.ErrUnauthorizedAccess, err))
+	_, err = svc.Identify(context.Background(), rotatedSecret)
+	assert.Nil(t, err, fmt.Sprintf("identifying with new key expected to succeed: %s", err))
+
+	err = svc.RevokeServiceAccountKey(context.Background(), secret, sa.ID, rotated.ID)
+	assert.Nil(t, err, fmt.Sprintf("revoking service account key expected to succeed: %s", err))
+	_, err = svc.Identify(context.Background(), rotatedSecret)
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("identifying with revoked key: expected %s got %s\n", auth.ErrUnauthorizedAccess, err))
+
+	_, saSecret, err = svc.IssueServiceAccountKey(context.Background(), secret, sa.ID, 0)
+	require.Nil(t, err, fmt.Sprintf("issuing non-expiring service account key expected to succeed: %s", err))
+	err = svc.RemoveServiceAccount(context.Background(), secret, sa.ID)
+	assert.Nil(t, err, fmt.Sprintf("removing service account expected to succeed: %s", err))
+	_, err = svc.Identify(context.Background(), saSecret)
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("identifying removed service account: expected %s got %s\n", auth.ErrUnauthorizedAccess, err))
+}
+
+func TestServiceAccountStatus(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "other", Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+
+	sa, err := svc.CreateServiceAccount(context.Background(), secret, auth.ServiceAccount{Name: "ci", OwnerID: "org", Scopes: []string{"read"}})
+	require.Nil(t, err, fmt.Sprintf("creating service account expected to succeed: %s", err))
+	_, saSecret, err := svc.IssueServiceAccountKey(context.Background(), secret, sa.ID, 0)
+	require.Nil(t, err, fmt.Sprintf("issuing service account key expected to succeed: %s", err))
+
+	viewCases := []struct {
+		desc  string
+		token string
+		id    string
+		err   error
+	}{
+		{"view service account", secret, sa.ID, nil},
+		{"view service account by itself", saSecret, sa.ID, nil},
+		{"view service account as non-member", otherSecret, sa.ID, auth.ErrAuthorization},
+		{"view non-existing service account", secret, "non-existing", auth.ErrNotFound},
+		{"view service account with invalid token", "invalid", sa.ID, auth.ErrUnauthorizedAccess},
+	}
+
+	for _, tc := range viewCases {
+		account, err := svc.ViewServiceAccount(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, sa, account, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, sa, account))
+		}
+	}
+
+	cases := []struct {
+		desc     string
+		token    string
+		disabled bool
+		err      error
+		identify error
+	}{
+		{"disable service account as non-member", otherSecret, true, auth.ErrAuthorization, nil},
+		{"disable service account by itself", saSecret, true, auth.ErrAuthorization, nil},
+		{"disable service account", secret, true, nil, auth.ErrUnauthorizedAccess},
+		{"enable service account", secret, false, nil, nil},
+	}
+
+	for _, tc := range cases {
+		switch tc.disabled {
+		case true:
+			err = svc.DisableServiceAccount(context.Background(), tc.token, sa.ID)
+		default:
+			err = svc.EnableServiceAccount(context.Background(), tc.token, sa.ID)
+		}
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		_, err = svc.Identify(context.Background(), saSecret)
+		assert.True(t, errors.Contains(err, tc.identify), fmt.Sprintf("%s: identify expected %s got %s\n", tc.desc, tc.identify, err))
+	}
+
+	account, err := svc.ViewServiceAccount(context.Background(), secret, sa.ID)
+	require.Nil(t, err, fmt.Sprintf("viewing service account expected to succeed: %s", err))
+	assert.False(t, account.Disabled, "expected enabled service account")
+}
+
+func TestSetQuota(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "other", Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+
+	org, err := svc.CreateOrg(context.Background(), secret, auth.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("creating org expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		quota auth.Quota
+		err   error
+	}{
+		{
+			desc:  "set quota",
+			token: secret,
+			quota: auth.Quota{OrgID: org.ID, MaxUsers: 10, MaxThings: 10, MaxChannels: 10, MessageRate: 5},
+			err:   nil,
+		},
+		{
+			desc:  "set quota as non-admin",
+			token: otherSecret,
+			quota: auth.Quota{OrgID: org.ID, MaxThings: 100},
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:  "set quota with invalid token",
+			token: "invalid",
+			quota: auth.Quota{OrgID: org.ID, MaxThings: 100},
+			err:   auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "set quota with negative message rate",
+			token: secret,
+			quota: auth.Quota{OrgID: org.ID, MessageRate: -1},
+			err:   auth.ErrMalformedEntity,
+		},
+		{
+			desc:  "set quota of non-existing organization",
+			token: secret,
+			quota: auth.Quota{OrgID: "non-existing", MaxThings: 100},
+			err:   auth.ErrNotFound,
+		},
+	}
 
-	err = svc.Authorize(context.Background(), auth.PolicyReq{Object: thingGroupID, Relation: memberRelation, Subject: fmt.Sprintf("%s:%s#%s", "members", userGroupID, memberRelation)})
-	require.Nil(t, err, fmt.Sprintf("checking shared group access policy expected to be succeed: %#v", err))
+	for _, tc := range cases {
+		err := svc.SetQuota(context.Background(), tc.token, tc.quota)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
 }
 
-func TestAddPolicies(t *testing.T) {
+func TestReserveQuota(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
+	org, err := svc.CreateOrg(context.Background(), secret, auth.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("creating org expected to succeed: %s", err))
+	_, orgSecret, err := svc.SwitchOrg(context.Background(), secret, org.ID)
+	require.Nil(t, err, fmt.Sprintf("switching org expected to succeed: %s", err))
+
+	quota := auth.Quota{OrgID: org.ID, MaxUsers: 1, MaxThings: 1, MessageRate: 1}
+	err = svc.SetQuota(context.Background(), secret, quota)
+	require.Nil(t, err, fmt.Sprintf("setting quota got unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		token    string
+		resource string
+		ids      []string
+		err      error
+	}{
+		{
+			desc:     "reserve thing",
+			token:    orgSecret,
+			resource: auth.ThingsResource,
+			ids:      []string{"thing1"},
+			err:      nil,
+		},
+		{
+			desc:     "reserve thing over the quota",
+			token:    orgSecret,
+			resource: auth.ThingsResource,
+			ids:      []string{"thing2"},
+			err:      auth.ErrQuotaExceeded,
+		},
+		{
+			desc:     "reserve thing with unscoped key",
+			token:    secret,
+			resource: auth.ThingsResource,
+			ids:      []string{"thing2"},
+			err:      nil,
+		},
+		{
+			desc:     "reserve channel without the quota",
+			token:    orgSecret,
+			resource: auth.ChannelsResource,
+			ids:      []string{"channel1", "channel2"},
+			err:      nil,
+		},
+		{
+			desc:     "reserve message",
+			resource: auth.MessagesResource,
+			ids:      []string{"thing1"},
+			err:      nil,
+		},
+		{
+			desc:     "reserve message over the rate",
+			resource: auth.MessagesResource,
+			ids:      []string{"thing1"},
+			err:      auth.ErrQuotaExceeded,
+		},
+		{
+			desc:     "reserve message of thing without organization",
+			resource: auth.MessagesResource,
+			ids:      []string{"thing2"},
+			err:      nil,
+		},
+		{
+			desc:     "reserve thing with invalid token",
+			token:    "invalid",
+			resource: auth.ThingsResource,
+			ids:      []string{"thing3"},
+			err:      auth.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "reserve unknown resource",
+			token:    secret,
+			resource: "unknown",
+			ids:      []string{"thing3"},
+			err:      auth.ErrMalformedEntity,
+		},
 	}
 
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+	for _, tc := range cases {
+		err := svc.ReserveQuota(context.Background(), tc.token, tc.resource, tc.ids...)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
 
-	thingID, err := idProvider.ID()
-	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	err = svc.AssignOrgMembers(context.Background(), secret, org.ID, "user")
+	assert.True(t, errors.Contains(err, auth.ErrQuotaExceeded), fmt.Sprintf("assigning user over the quota: expected %s got %s\n", auth.ErrQuotaExceeded, err))
 
-	tmpID := "tmpid"
-	readPolicy := "read"
-	writePolicy := "write"
-	deletePolicy := "delete"
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "other", Subject: "other@example.com"})
+	require.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+	_, _, err = svc.ViewQuota(context.Background(), otherSecret, org.ID)
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("viewing quota as non-member: expected %s got %s\n", auth.ErrAuthorization, err))
 
-	// Add read policy to users.
-	err = svc.AddPolicies(context.Background(), apiToken, thingID, []string{id, tmpID}, []string{readPolicy})
-	assert.Nil(t, err, fmt.Sprintf("adding policies expected to succeed: %s", err))
+	q, u, err := svc.ViewQuota(context.Background(), orgSecret, org.ID)
+	require.Nil(t, err, fmt.Sprintf("viewing quota got unexpected error: %s", err))
+	assert.Equal(t, quota.MaxThings, q.MaxThings, fmt.Sprintf("view quota: expected %d got %d\n", quota.MaxThings, q.MaxThings))
+	assert.Equal(t, auth.Usage{Users: 1, Things: 1, Channels: 2}, u, fmt.Sprintf("view usage: expected %v got %v\n", auth.Usage{Users: 1, Things: 1, Channels: 2}, u))
 
-	// Add write and delete policies to users.
-	err = svc.AddPolicies(context.Background(), apiToken, thingID, []string{id, tmpID}, []string{writePolicy, deletePolicy})
-	assert.Nil(t, err, fmt.Sprintf("adding multiple policies expected to succeed: %s", err))
+	err = svc.ReleaseQuota(context.Background(), auth.ThingsResource, "thing1")
+	require.Nil(t, err, fmt.Sprintf("releasing quota got unexpected error: %s", err))
+	err = svc.ReserveQuota(context.Background(), orgSecret, auth.ThingsResource, "thing2")
+	assert.Nil(t, err, fmt.Sprintf("reserving released quota got unexpected error: %s", err))
+}
+
+func TestListAuditRecords(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "other", Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+
+	pr := auth.PolicyReq{Object: "obj", Relation: "read", Subject: "audited"}
+	err = svc.Authorize(context.Background(), pr)
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("checking missing policy: expected %s got %s\n", auth.ErrAuthorization, err))
+	err = svc.AddPolicy(context.Background(), pr)
+	require.Nil(t, err, fmt.Sprintf("adding policy expected to succeed: %s", err))
+	err = svc.Authorize(context.Background(), pr)
+	assert.Nil(t, err, fmt.Sprintf("checking added policy expected to succeed: %s", err))
+	err = svc.DeletePolicy(context.Background(), pr)
+	require.Nil(t, err, fmt.Sprintf("deleting policy expected to succeed: %s", err))
 
 	cases := []struct {
-		desc   string
-		policy auth.PolicyReq
-		err    error
+		desc      string
+		token     string
+		filter    auth.AuditFilter
+		offset    uint64
+		limit     uint64
+		decisions []string
+		total     uint64
+		err       error
 	}{
 		{
-			desc:   "check valid 'read' policy of user with id",
-			policy: auth.PolicyReq{Object: thingID, Relation: readPolicy, Subject: id},
-			err:    nil,
-		},
-		{
-			desc:   "check valid 'write' policy of user with id",
-			policy: auth.PolicyReq{Object: thingID, Relation: writePolicy, Subject: id},
-			err:    nil,
+			desc:      "list subject audit records",
+			token:     secret,
+			filter:    auth.AuditFilter{Subject: pr.Subject},
+			limit:     10,
+			decisions: []string{auth.DeniedDecision, auth.SuccessDecision, auth.AllowedDecision, auth.SuccessDecision},
+			total:     4,
+			err:       nil,
 		},
 		{
-			desc:   "check valid 'delete' policy of user with id",
-			policy: auth.PolicyReq{Object: thingID, Relation: deletePolicy, Subject: id},
-			err:    nil,
+			desc:      "list subject audit records with offset and limit",
+			token:     secret,
+			filter:    auth.AuditFilter{Subject: pr.Subject},
+			offset:    1,
+			limit:     2,
+			decisions: []string{auth.SuccessDecision, auth.AllowedDecision},
+			total:     4,
+			err:       nil,
 		},
 		{
-			desc:   "check valid 'read' policy of user with tmpid",
-			policy: auth.PolicyReq{Object: thingID, Relation: readPolicy, Subject: tmpID},
-			err:    nil,
+			desc:      "list subject checks",
+			token:     secret,
+			filter:    auth.AuditFilter{Subject: pr.Subject, Operation: auth.CheckOperation},
+			limit:     10,
+			decisions: []string{auth.DeniedDecision, auth.AllowedDecision},
+			total:     2,
+			err:       nil,
 		},
 		{
-			desc:   "check valid 'write' policy of user with tmpid",
-			policy: auth.PolicyReq{Object: thingID, Relation: writePolicy, Subject: tmpID},
-			err:    nil,
+			desc:      "list subject denials",
+			token:     secret,
+			filter:    auth.AuditFilter{Subject: pr.Subject, Decision: auth.DeniedDecision},
+			limit:     10,
+			decisions: []string{auth.DeniedDecision},
+			total:     1,
+			err:       nil,
 		},
 		{
-			desc:   "check valid 'delete' policy of user with tmpid",
-			policy: auth.PolicyReq{Object: thingID, Relation: deletePolicy, Subject: tmpID},
-			err:    nil,
+			desc:      "list future audit records",
+			token:     secret,
+			filter:    auth.AuditFilter{Subject: pr.Subject, From: time.Now().Add(time.Hour)},
+			limit:     10,
+			decisions: []string{},
+			total:     0,
+			err:       nil,
 		},
 		{
-			desc:   "check invalid 'access' policy of user with id",
-			policy: auth.PolicyReq{Object: thingID, Relation: "access", Subject: id},
+			desc:   "list audit records as non-admin",
+			token:  otherSecret,
+			filter: auth.AuditFilter{Subject: pr.Subject},
+			limit:  10,
 			err:    auth.ErrAuthorization,
 		},
 		{
-			desc:   "check invalid 'access' policy of user with tmpid",
-			policy: auth.PolicyReq{Object: thingID, Relation: "access", Subject: tmpID},
-			err:    auth.ErrAuthorization,
+			desc:   "list audit records with invalid token",
+			token:  "invalid",
+			filter: auth.AuditFilter{Subject: pr.Subject},
+			limit:  10,
+			err:    auth.ErrUnauthorizedAccess,
 		},
 	}
 
 	for _, tc := range cases {
-		err := svc.Authorize(context.Background(), tc.policy)
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %v, got %v", tc.desc, tc.err, err))
+		page, err := svc.ListAuditRecords(context.Background(), tc.token, tc.filter, tc.offset, tc.limit)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		decisions := []string{}
+		for _, r := range page.Records {
+			decisions = append(decisions, r.Decision)
+		}
+		assert.Equal(t, tc.decisions, decisions, fmt.Sprintf("%s: expected decisions %v got %v\n", tc.desc, tc.decisions, decisions))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
 	}
 }
 
-func TestDeletePolicies(t *testing.T) {
+func TestCreateOrg(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
-	}
+	org, err := svc.CreateOrg(context.Background(), secret, auth.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("creating org expected to succeed: %s", err))
+	_, orgSecret, err := svc.SwitchOrg(context.Background(), secret, org.ID)
+	require.Nil(t, err, fmt.Sprintf("switching org expected to succeed: %s", err))
 
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+	cases := []struct {
+		desc  string
+		token string
+		org   auth.Org
+		err   error
+	}{
+		{
+			desc:  "create org",
+			token: secret,
+			org:   auth.Org{Name: "other", Description: description},
+			err:   nil,
+		},
+		{
+			desc:  "create org without name",
+			token: secret,
+			org:   auth.Org{Description: description},
+			err:   auth.ErrMalformedEntity,
+		},
+		{
+			desc:  "create org with org key",
+			token: orgSecret,
+			org:   auth.Org{Name: "nested"},
+			err:   auth.ErrAuthorization,
+		},
+		{
+			desc:  "create org with invalid token",
+			token: "invalid",
+			org:   auth.Org{Name: "invalid"},
+			err:   auth.ErrUnauthorizedAccess,
+		},
+	}
 
-	thingID, err := idProvider.ID()
-	assert.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	for _, tc := range cases {
+		org, err := svc.CreateOrg(context.Background(), tc.token, tc.org)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, id, org.OwnerID, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, id, org.OwnerID))
+			_, err := svc.ViewOrg(context.Background(), tc.token, org.ID)
+			assert.Nil(t, err, fmt.Sprintf("%s: viewing created org expected to succeed: %s", tc.desc, err))
+		}
+	}
+}
 
-	tmpID := "tmpid"
-	readPolicy := "read"
-	writePolicy := "write"
-	deletePolicy := "delete"
-	memberPolicy := "member"
+func TestOrgMembers(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+	_, memberSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "member", Subject: "member@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing member's login key expected to succeed: %s", err))
 
-	// Add read, write and delete policies to users.
-	err = svc.AddPolicies(context.Background(), apiToken, thingID, []string{id, tmpID}, []string{readPolicy, writePolicy, deletePolicy, memberPolicy})
-	assert.Nil(t, err, fmt.Sprintf("adding policies expected to succeed: %s", err))
+	org, err := svc.CreateOrg(context.Background(), secret, auth.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("creating org expected to succeed: %s", err))
 
-	// Delete multiple policies from single user.
-	err = svc.DeletePolicies(context.Background(), apiToken, thingID, []string{id}, []string{readPolicy, writePolicy})
-	assert.Nil(t, err, fmt.Sprintf("deleting policies from single user expected to succeed: %s", err))
+	_, err = svc.ViewOrg(context.Background(), memberSecret, org.ID)
+	assert.True(t, errors.Contains(err, auth.ErrNotFound), fmt.Sprintf("viewing org as non-member: expected %s got %s\n", auth.ErrNotFound, err))
 
-	// Delete multiple policies from multiple user.
-	err = svc.DeletePolicies(context.Background(), apiToken, thingID, []string{id, tmpID}, []string{deletePolicy, memberPolicy})
-	assert.Nil(t, err, fmt.Sprintf("deleting policies from multiple user expected to succeed: %s", err))
+	err = svc.AssignOrgMembers(context.Background(), secret, org.ID, "member")
+	require.Nil(t, err, fmt.Sprintf("assigning org member expected to succeed: %s", err))
 
 	cases := []struct {
-		desc   string
-		policy auth.PolicyReq
-		err    error
+		desc    string
+		token   string
+		members []string
+		err     error
 	}{
 		{
-			desc:   "check non-existing 'read' policy of user with id",
-			policy: auth.PolicyReq{Object: thingID, Relation: readPolicy, Subject: id},
-			err:    auth.ErrAuthorization,
-		},
-		{
-			desc:   "check non-existing 'write' policy of user with id",
-			policy: auth.PolicyReq{Object: thingID, Relation: writePolicy, Subject: id},
-			err:    auth.ErrAuthorization,
+			desc:    "assign member as non-owner",
+			token:   memberSecret,
+			members: []string{"other"},
+			err:     auth.ErrAuthorization,
 		},
 		{
-			desc:   "check non-existing 'delete' policy of user with id",
-			policy: auth.PolicyReq{Object: thingID, Relation: deletePolicy, Subject: id},
-			err:    auth.ErrAuthorization,
+			desc:    "assign assigned member",
+			token:   secret,
+			members: []string{"member"},
+			err:     auth.ErrMemberAlreadyAssigned,
 		},
 		{
-			desc:   "check non-existing 'member' policy of user with id",
-			policy: auth.PolicyReq{Object: thingID, Relation: memberPolicy, Subject: id},
-			err:    auth.ErrAuthorization,
+			desc:    "assign no members",
+			token:   secret,
+			members: []string{},
+			err:     auth.ErrMalformedEntity,
 		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AssignOrgMembers(context.Background(), tc.token, org.ID, tc.members...)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := svc.ListOrgMembers(context.Background(), memberSecret, org.ID, 0, 10)
+	require.Nil(t, err, fmt.Sprintf("listing org members expected to succeed: %s", err))
+	assert.Equal(t, uint64(2), page.Total, fmt.Sprintf("listing org members: expected total %d got %d\n", 2, page.Total))
+
+	err = svc.UnassignOrgMembers(context.Background(), secret, org.ID, id)
+	assert.True(t, errors.Contains(err, auth.ErrMalformedEntity), fmt.Sprintf("unassigning org owner: expected %s got %s\n", auth.ErrMalformedEntity, err))
+
+	_, orgSecret, err := svc.SwitchOrg(context.Background(), memberSecret, org.ID)
+	require.Nil(t, err, fmt.Sprintf("switching org expected to succeed: %s", err))
+
+	err = svc.UnassignOrgMembers(context.Background(), secret, org.ID, "member")
+	require.Nil(t, err, fmt.Sprintf("unassigning org member expected to succeed: %s", err))
+
+	_, err = svc.Identify(context.Background(), orgSecret)
+	assert.True(t, errors.Contains(err, auth.ErrUnauthorizedAccess), fmt.Sprintf("identifying unassigned member's org key: expected %s got %s\n", auth.ErrUnauthorizedAccess, err))
+
+	err = svc.RemoveOrg(context.Background(), memberSecret, org.ID)
+	assert.True(t, errors.Contains(err, auth.ErrNotFound), fmt.Sprintf("removing org as non-member: expected %s got %s\n", auth.ErrNotFound, err))
+	err = svc.RemoveOrg(context.Background(), secret, org.ID)
+	assert.Nil(t, err, fmt.Sprintf("removing org expected to succeed: %s", err))
+}
+
+func TestSwitchOrg(t *testing.T) {
+	svc := newService()
+	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
+	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
+	_, otherSecret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: "other", Subject: "other@example.com"})
+	assert.Nil(t, err, fmt.Sprintf("Issuing other user's login key expected to succeed: %s", err))
+
+	org, err := svc.CreateOrg(context.Background(), secret, auth.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("creating org expected to succeed: %s", err))
+
+	_, apiSecret, err := svc.Issue(context.Background(), secret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now()})
+	require.Nil(t, err, fmt.Sprintf("issuing API key expected to succeed: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		orgID string
+		err   error
+	}{
 		{
-			desc:   "check non-existing 'delete' policy of user with tmpid",
-			policy: auth.PolicyReq{Object: thingID, Relation: deletePolicy, Subject: tmpID},
-			err:    auth.ErrAuthorization,
+			desc:  "switch to org",
+			token: secret,
+			orgID: org.ID,
+			err:   nil,
 		},
 		{
-			desc:   "check non-existing 'member' policy of user with tmpid",
-			policy: auth.PolicyReq{Object: thingID, Relation: memberPolicy, Subject: tmpID},
-			err:    auth.ErrAuthorization,
+			desc:  "switch out of orgs",
+			token: secret,
+			orgID: "",
+			err:   nil,
 		},
 		{
-			desc:   "check valid 'read' policy of user with tmpid",
-			policy: auth.PolicyReq{Object: thingID, Relation: readPolicy, Subject: tmpID},
-			err:    nil,
+			desc:  "switch to org as non-member",
+			token: otherSecret,
+			orgID: org.ID,
+			err:   auth.ErrOrgMember,
 		},
 		{
-			desc:   "check valid 'write' policy of user with tmpid",
-			policy: auth.PolicyReq{Object: thingID, Relation: writePolicy, Subject: tmpID},
-			err:    nil,
+			desc:  "switch to org with API key",
+			token: apiSecret,
+			orgID: org.ID,
+			err:   auth.ErrUnauthorizedAccess,
 		},
 	}
 
 	for _, tc := range cases {
-		err := svc.Authorize(context.Background(), tc.policy)
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %v, got %v", tc.desc, tc.err, err))
+		_, token, err := svc.SwitchOrg(context.Background(), tc.token, tc.orgID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			idt, err := svc.Identify(context.Background(), token)
+			assert.Nil(t, err, fmt.Sprintf("%s: identifying switched key expected to succeed: %s", tc.desc, err))
+			assert.Equal(t, tc.orgID, idt.OrgID, fmt.Sprintf("%s: expected org %s got %s\n", tc.desc, tc.orgID, idt.OrgID))
+		}
 	}
 }
 
-func TestListPolicies(t *testing.T) {
+func TestOrgIsolation(t *testing.T) {
 	svc := newService()
 	_, secret, err := svc.Issue(context.Background(), "", auth.Key{Type: auth.UserKey, IssuedAt: time.Now(), IssuerID: id, Subject: email})
 	assert.Nil(t, err, fmt.Sprintf("Issuing login key expected to succeed: %s", err))
 
-	key := auth.Key{
-		ID:       "id",
-		Type:     auth.APIKey,
-		IssuerID: id,
-		Subject:  email,
-		IssuedAt: time.Now(),
-	}
+	org, err := svc.CreateOrg(context.Background(), secret, auth.Org{Name: "org"})
+	require.Nil(t, err, fmt.Sprintf("creating org expected to succeed: %s", err))
+	_, orgSecret, err := svc.SwitchOrg(context.Background(), secret, org.ID)
+	require.Nil(t, err, fmt.Sprintf("switching org expected to succeed: %s", err))
 
-	_, apiToken, err := svc.Issue(context.Background(), secret, key)
-	assert.Nil(t, err, fmt.Sprintf("Issuing user's key expected to succeed: %s", err))
+	group, err := svc.CreateGroup(context.Background(), secret, auth.Group{Name: groupName})
+	require.Nil(t, err, fmt.Sprintf("creating group expected to succeed: %s", err))
+	orgGroup, err := svc.CreateGroup(context.Background(), orgSecret, auth.Group{Name: groupName})
+	require.Nil(t, err, fmt.Sprintf("creating org group expected to succeed: %s", err))
+	assert.Equal(t, org.ID, orgGroup.OrgID, fmt.Sprintf("creating org group: expected org %s got %s\n", org.ID, orgGroup.OrgID))
 
-	readPolicy := "read"
-	pageLen := 15
+	_, err = svc.CreateGroup(context.Background(), orgSecret, auth.Group{Name: "child", ParentID: group.ID})
+	assert.NotNil(t, err, "creating org group with the parent outside of the org expected to fail")
 
-	// Add arbitrary policies to the user.
-	for i := 0; i < pageLen; i++ {
-		err = svc.AddPolicies(context.Background(), apiToken, fmt.Sprintf("thing-%d", i), []string{id}, []string{readPolicy})
-		assert.Nil(t, err, fmt.Sprintf("adding policies expected to succeed: %s", err))
+	cases := []struct {
+		desc    string
+		token   string
+		visible string
+		hidden  string
+	}{
+		{
+			desc:    "groups outside of orgs",
+			token:   secret,
+			visible: group.ID,
+			hidden:  orgGroup.ID,
+		},
+		{
+			desc:    "groups of org",
+			token:   orgSecret,
+			visible: orgGroup.ID,
+			hidden:  group.ID,
+		},
 	}
 
-	page, err := svc.ListPolicies(context.Background(), auth.PolicyReq{Subject: id, Relation: readPolicy})
-	assert.Nil(t, err, fmt.Sprintf("listing policies expected to succeed: %s", err))
-	assert.Equal(t, pageLen, len(page.Policies), fmt.Sprintf("unexpected listing page size, expected %d, got %d: %v", pageLen, len(page.Policies), err))
+	for _, tc := range cases {
+		page, err := svc.ListGroups(context.Background(), tc.token, auth.PageMetadata{Level: auth.MaxLevel, Limit: 10})
+		require.Nil(t, err, fmt.Sprintf("%s: listing groups expected to succeed: %s", tc.desc, err))
+		require.Len(t, page.Groups, 1, fmt.Sprintf("%s: expected 1 group got %d\n", tc.desc, len(page.Groups)))
+		assert.Equal(t, tc.visible, page.Groups[0].ID, fmt.Sprintf("%s: expected group %s got %s\n", tc.desc, tc.visible, page.Groups[0].ID))
+
+		_, err = svc.ViewGroup(context.Background(), tc.token, tc.hidden)
+		assert.True(t, errors.Contains(err, auth.ErrGroupNotFound), fmt.Sprintf("%s: viewing hidden group: expected %s got %s\n", tc.desc, auth.ErrGroupNotFound, err))
+		err = svc.Assign(context.Background(), tc.token, tc.hidden, "things", "thing")
+		assert.True(t, errors.Contains(err, auth.ErrGroupNotFound), fmt.Sprintf("%s: assigning to hidden group: expected %s got %s\n", tc.desc, auth.ErrGroupNotFound, err))
+		err = svc.RemoveGroup(context.Background(), tc.token, tc.hidden)
+		assert.True(t, errors.Contains(err, auth.ErrGroupNotFound), fmt.Sprintf("%s: removing hidden group: expected %s got %s\n", tc.desc, auth.ErrGroupNotFound, err))
+		err = svc.Authorize(context.Background(), auth.PolicyReq{Object: tc.hidden, Relation: memberRelation, Subject: id, Token: tc.token})
+		assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("%s: authorizing over hidden group: expected %s got %s\n", tc.desc, auth.ErrAuthorization, err))
+		err = svc.Authorize(context.Background(), auth.PolicyReq{Object: tc.visible, Relation: memberRelation, Subject: id, Token: tc.token})
+		assert.Nil(t, err, fmt.Sprintf("%s: authorizing over visible group expected to succeed: %s", tc.desc, err))
+	}
+
+	err = svc.Assign(context.Background(), orgSecret, orgGroup.ID, auth.UsersResource, "other")
+	assert.True(t, errors.Contains(err, auth.ErrOrgMember), fmt.Sprintf("assigning non-member to org group: expected %s got %s\n", auth.ErrOrgMember, err))
+
+	key, apiSecret, err := svc.Issue(context.Background(), orgSecret, auth.Key{Type: auth.APIKey, IssuedAt: time.Now()})
+	require.Nil(t, err, fmt.Sprintf("issuing API key expected to succeed: %s", err))
+	assert.Equal(t, org.ID, key.OrgID, fmt.Sprintf("issuing API key: expected org %s got %s\n", org.ID, key.OrgID))
+
+	_, err = svc.RetrieveKey(context.Background(), secret, key.ID)
+	assert.True(t, errors.Contains(err, auth.ErrNotFound), fmt.Sprintf("retrieving org key outside of org: expected %s got %s\n", auth.ErrNotFound, err))
+
+	_, err = svc.ListAuditRecords(context.Background(), apiSecret, auth.AuditFilter{}, 0, 10)
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("listing audit records with org key: expected %s got %s\n", auth.ErrAuthorization, err))
 
+	err = svc.RemoveOrg(context.Background(), secret, org.ID)
+	assert.True(t, errors.Contains(err, auth.ErrOrgNotEmpty), fmt.Sprintf("removing org with groups: expected %s got %s\n", auth.ErrOrgNotEmpty, err))
 }
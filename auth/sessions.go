@@ -0,0 +1,97 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// SessionClaim is the claim carrying the ID of the session the login and
+// the refresh keys belong to. The keys issued on the same login, and the
+// keys refreshed from them, share the session.
+const SessionClaim = "session"
+
+// maxDeviceLength is the maximal length of the session device, longer user
+// agents are truncated.
+const maxDeviceLength = 254
+
+// Session represents the login of the user on the single device, tracked
+// from the login until its refresh key expires or the session is revoked.
+type Session struct {
+	ID     string
+	UserID string
+
+	// Device is the user agent of the client the user logged in from.
+	Device string
+
+	// IP is the address of the client the session was last refreshed from.
+	IP string
+
+	IssuedAt   time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+
+	// Current tells whether the session is the one of the key used to
+	// list the sessions.
+	Current bool
+}
+
+// Sessions specifies an API for managing the login sessions of the user.
+// The sessions are managed using the User keys only.
+type Sessions interface {
+	// ListSessions retrieves the active sessions of the user identified
+	// by the provided key, the most recently seen first.
+	ListSessions(ctx context.Context, token string) ([]Session, error)
+
+	// RevokeSession revokes the session of the user identified by the
+	// provided key, so that none of its keys can be used or refreshed
+	// anymore.
+	RevokeSession(ctx context.Context, token, id string) error
+
+	// RevokeOtherSessions revokes all the sessions of the user identified
+	// by the provided key but the current one, returning the number of
+	// the revoked sessions.
+	RevokeOtherSessions(ctx context.Context, token string) (uint64, error)
+}
+
+// SessionRepository specifies Session persistence API.
+type SessionRepository interface {
+	// Save persists the session. The existing session is extended instead,
+	// updating its IP address, the last seen and the expiration time. The
+	// expired sessions of the user may be removed.
+	Save(ctx context.Context, s Session) error
+
+	// Retrieve retrieves the active session of the user.
+	Retrieve(ctx context.Context, userID, id string) (Session, error)
+
+	// RetrieveByUser retrieves the active sessions of the user, the most
+	// recently seen first.
+	RetrieveByUser(ctx context.Context, userID string) ([]Session, error)
+
+	// Touch marks the session as seen at the given time. The repository
+	// may skip the update if the session has been seen shortly before.
+	Touch(ctx context.Context, id string, seenAt time.Time) error
+
+	// Remove removes the session of the user.
+	Remove(ctx context.Context, userID, id string) error
+
+	// RemoveByUser removes all the sessions of the user.
+	RemoveByUser(ctx context.Context, userID string) error
+}
+
+type userAgentKey struct{}
+
+// WithUserAgent returns the context carrying the user agent of the client
+// logging in, as propagated by the transports.
+func WithUserAgent(ctx context.Context, ua string) context.Context {
+	return context.WithValue(ctx, userAgentKey{}, ua)
+}
+
+// UserAgent returns the user agent of the client stored in the context, and
+// the empty string if it's unknown.
+func UserAgent(ctx context.Context) string {
+	ua, _ := ctx.Value(userAgentKey{}).(string)
+	return ua
+}
@@ -0,0 +1,60 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Share represents the access to the object delegated by the grantor to the
+// grantee. Actions contain the policies added to the grantee by the share,
+// the ones the grantee already had are not part of the share.
+type Share struct {
+	ID        string
+	GrantorID string
+	GranteeID string
+	Object    string
+	Actions   []string
+	CreatedAt time.Time
+}
+
+// Sharing specifies an API for the delegation of the access to the
+// things and channels between the users.
+type Sharing interface {
+	// Share grants the actions on the object to the grantee. The user
+	// identified by the token has to be allowed to perform the shared
+	// actions on the object.
+	Share(ctx context.Context, token string, s Share) (Share, error)
+
+	// ListShares lists the shares granted by the user identified by the
+	// token or, if received is set, the shares granted to the user.
+	ListShares(ctx context.Context, token string, received bool) ([]Share, error)
+
+	// RevokeShare revokes the share, removing the policies added by it.
+	// The share can be revoked by both the grantor and the grantee.
+	RevokeShare(ctx context.Context, token, id string) error
+}
+
+// ShareRepository specifies Share persistence API.
+type ShareRepository interface {
+	// Save persists the share.
+	Save(ctx context.Context, s Share) error
+
+	// RetrieveByID retrieves the share by its ID.
+	RetrieveByID(ctx context.Context, id string) (Share, error)
+
+	// RetrieveByGrantor retrieves the shares granted by the user.
+	RetrieveByGrantor(ctx context.Context, grantorID string) ([]Share, error)
+
+	// RetrieveByGrantee retrieves the shares granted to the user.
+	RetrieveByGrantee(ctx context.Context, granteeID string) ([]Share, error)
+
+	// RetrieveByObject retrieves the shares of the object granted to the
+	// user.
+	RetrieveByObject(ctx context.Context, granteeID, object string) ([]Share, error)
+
+	// Remove removes the share.
+	Remove(ctx context.Context, id string) error
+}
@@ -0,0 +1,5 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package spicedb contains PolicyAgent implementation using SpiceDB.
+package spicedb
@@ -0,0 +1,323 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package spicedb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+	acl "github.com/ory/keto/proto/ory/keto/acl/v1alpha1"
+)
+
+const (
+	checkPath       = "/v1/permissions/check"
+	writePath       = "/v1/relationships/write"
+	deletePath      = "/v1/relationships/delete"
+	readPath        = "/v1/relationships/read"
+	schemaWritePath = "/v1/schema/write"
+
+	namespace = "members"
+	userType  = "user"
+	// SpiceDB reserves "*" for the wildcard subjects, while Mainflux uses
+	// it as the literal subject, e.g. of the self-registration policy.
+	anySubject   = "*"
+	anySubjectID = "_any"
+
+	hasPermission = "PERMISSIONSHIP_HAS_PERMISSION"
+	opTouch       = "OPERATION_TOUCH"
+)
+
+var errUnexpectedStatus = errors.New("unexpected SpiceDB response status")
+
+type objectRef struct {
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
+}
+
+type subjectRef struct {
+	Object           objectRef `json:"object"`
+	OptionalRelation string    `json:"optionalRelation,omitempty"`
+}
+
+type relationship struct {
+	Resource objectRef  `json:"resource"`
+	Relation string     `json:"relation"`
+	Subject  subjectRef `json:"subject"`
+}
+
+type consistency struct {
+	FullyConsistent bool `json:"fullyConsistent"`
+}
+
+type checkReq struct {
+	Consistency consistency `json:"consistency"`
+	Resource    objectRef   `json:"resource"`
+	Permission  string      `json:"permission"`
+	Subject     subjectRef  `json:"subject"`
+}
+
+type checkRes struct {
+	Permissionship string `json:"permissionship"`
+}
+
+type update struct {
+	Operation    string       `json:"operation"`
+	Relationship relationship `json:"relationship"`
+}
+
+type writeReq struct {
+	Updates []update `json:"updates"`
+}
+
+type subjectRelationFilter struct {
+	Relation string `json:"relation"`
+}
+
+type subjectFilter struct {
+	SubjectType       string                 `json:"subjectType"`
+	OptionalSubjectID string                 `json:"optionalSubjectId,omitempty"`
+	OptionalRelation  *subjectRelationFilter `json:"optionalRelation,omitempty"`
+}
+
+type relationshipFilter struct {
+	ResourceType          string         `json:"resourceType"`
+	OptionalResourceID    string         `json:"optionalResourceId,omitempty"`
+	OptionalRelation      string         `json:"optionalRelation,omitempty"`
+	OptionalSubjectFilter *subjectFilter `json:"optionalSubjectFilter,omitempty"`
+}
+
+type deleteReq struct {
+	RelationshipFilter relationshipFilter `json:"relationshipFilter"`
+}
+
+type readReq struct {
+	Consistency        consistency        `json:"consistency"`
+	RelationshipFilter relationshipFilter `json:"relationshipFilter"`
+}
+
+// readRes is the single message of the streamed read response.
+type readRes struct {
+	Result struct {
+		Relationship relationship `json:"relationship"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type api struct {
+	client *http.Client
+	url    string
+	key    string
+}
+
+func newAPI(client *http.Client, url, key string) api {
+	return api{client: client, url: strings.TrimSuffix(url, "/"), key: key}
+}
+
+type policyAgent struct {
+	api api
+}
+
+// NewPolicyAgent returns the HTTP communication functionalities to
+// communicate with SpiceDB through its HTTP API gateway, authenticating
+// using the given preshared key. The SpiceDB is expected to be bootstrapped
+// with the Schema.
+func NewPolicyAgent(client *http.Client, url, key string) auth.PolicyAgent {
+	return policyAgent{api: newAPI(client, url, key)}
+}
+
+func (pa policyAgent) CheckPolicy(ctx context.Context, pr auth.PolicyReq) error {
+	req := checkReq{
+		Consistency: consistency{FullyConsistent: true},
+		Resource:    objectRef{ObjectType: namespace, ObjectID: pr.Object},
+		Permission:  pr.Relation,
+		Subject:     toSubject(pr.Subject),
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return errors.Wrap(auth.ErrAuthorization, err)
+	}
+
+	var res checkRes
+	if err := pa.api.do(ctx, checkPath, body, &res); err != nil {
+		return errors.Wrap(auth.ErrAuthorization, err)
+	}
+	if res.Permissionship != hasPermission {
+		return auth.ErrAuthorization
+	}
+	return nil
+}
+
+func (pa policyAgent) AddPolicy(ctx context.Context, pr auth.PolicyReq) error {
+	req := writeReq{
+		Updates: []update{
+			{
+				// Touch, unlike create, doesn't fail for the existing policy.
+				Operation: opTouch,
+				Relationship: relationship{
+					Resource: objectRef{ObjectType: namespace, ObjectID: pr.Object},
+					Relation: pr.Relation,
+					Subject:  toSubject(pr.Subject),
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	return pa.api.do(ctx, writePath, body, nil)
+}
+
+func (pa policyAgent) DeletePolicy(ctx context.Context, pr auth.PolicyReq) error {
+	filter := relationshipFilter{
+		ResourceType:          namespace,
+		OptionalResourceID:    pr.Object,
+		OptionalRelation:      pr.Relation,
+		OptionalSubjectFilter: toSubjectFilter(pr.Subject),
+	}
+	body, err := json.Marshal(deleteReq{RelationshipFilter: filter})
+	if err != nil {
+		return err
+	}
+
+	return pa.api.do(ctx, deletePath, body, nil)
+}
+
+func (pa policyAgent) RetrievePolicies(ctx context.Context, pr auth.PolicyReq) ([]*acl.RelationTuple, error) {
+	req := readReq{
+		Consistency: consistency{FullyConsistent: true},
+		RelationshipFilter: relationshipFilter{
+			ResourceType:       namespace,
+			OptionalResourceID: pr.Object,
+			OptionalRelation:   pr.Relation,
+		},
+	}
+	if pr.Subject != "" {
+		req.RelationshipFilter.OptionalSubjectFilter = toSubjectFilter(pr.Subject)
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return []*acl.RelationTuple{}, err
+	}
+
+	resp, err := pa.api.post(ctx, readPath, body)
+	if err != nil {
+		return []*acl.RelationTuple{}, err
+	}
+	defer resp.Close()
+
+	// The relationships are streamed as the sequence of JSON messages.
+	tuples := []*acl.RelationTuple{}
+	dec := json.NewDecoder(resp)
+	for {
+		var res readRes
+		err := dec.Decode(&res)
+		if err == io.EOF {
+			return tuples, nil
+		}
+		if err != nil {
+			return []*acl.RelationTuple{}, err
+		}
+		if res.Error != nil {
+			return []*acl.RelationTuple{}, errors.New(res.Error.Message)
+		}
+
+		rel := res.Result.Relationship
+		tuples = append(tuples, &acl.RelationTuple{
+			Namespace: namespace,
+			Object:    rel.Resource.ObjectID,
+			Relation:  rel.Relation,
+			Subject:   fromSubject(rel.Subject),
+		})
+	}
+}
+
+func (a api) do(ctx context.Context, path string, body []byte, res interface{}) error {
+	resp, err := a.post(ctx, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Close()
+
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp).Decode(res)
+}
+
+func (a api) post(ctx context.Context, path string, body []byte) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.key != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", a.key))
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, errors.Wrap(errUnexpectedStatus, fmt.Errorf("%s: %d", path, resp.StatusCode))
+	}
+	return resp.Body, nil
+}
+
+// toSubject returns the SpiceDB subject of the given subject, which is
+// either the subject set, given as <namespace>:<object>#<relation>, or
+// the user ID.
+func toSubject(subject string) subjectRef {
+	if ns, obj, rel, ok := parseSubjectSet(subject); ok {
+		return subjectRef{Object: objectRef{ObjectType: ns, ObjectID: obj}, OptionalRelation: rel}
+	}
+	if subject == anySubject {
+		subject = anySubjectID
+	}
+	return subjectRef{Object: objectRef{ObjectType: userType, ObjectID: subject}}
+}
+
+func toSubjectFilter(subject string) *subjectFilter {
+	s := toSubject(subject)
+	f := &subjectFilter{SubjectType: s.Object.ObjectType, OptionalSubjectID: s.Object.ObjectID}
+	if s.OptionalRelation != "" {
+		f.OptionalRelation = &subjectRelationFilter{Relation: s.OptionalRelation}
+	}
+	return f
+}
+
+func fromSubject(s subjectRef) *acl.Subject {
+	if s.OptionalRelation != "" {
+		return &acl.Subject{
+			Ref: &acl.Subject_Set{Set: &acl.SubjectSet{Namespace: s.Object.ObjectType, Object: s.Object.ObjectID, Relation: s.OptionalRelation}},
+		}
+	}
+
+	id := s.Object.ObjectID
+	if id == anySubjectID {
+		id = anySubject
+	}
+	return &acl.Subject{Ref: &acl.Subject_Id{Id: id}}
+}
+
+func parseSubjectSet(subject string) (namespace, object, relation string, ok bool) {
+	i, j := strings.Index(subject, ":"), strings.LastIndex(subject, "#")
+	if i <= 0 || j <= i+1 || j == len(subject)-1 {
+		return "", "", "", false
+	}
+	return subject[:i], subject[i+1 : j], subject[j+1:], true
+}
@@ -0,0 +1,249 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package spicedb_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/auth/spicedb"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const key = "preshared-key"
+
+type object struct {
+	ObjectType string `json:"objectType"`
+	ObjectID   string `json:"objectId"`
+}
+
+type subject struct {
+	Object           object `json:"object"`
+	OptionalRelation string `json:"optionalRelation,omitempty"`
+}
+
+type relationship struct {
+	Resource object  `json:"resource"`
+	Relation string  `json:"relation"`
+	Subject  subject `json:"subject"`
+}
+
+type filter struct {
+	ResourceType          string `json:"resourceType"`
+	OptionalResourceID    string `json:"optionalResourceId"`
+	OptionalRelation      string `json:"optionalRelation"`
+	OptionalSubjectFilter *struct {
+		SubjectType       string `json:"subjectType"`
+		OptionalSubjectID string `json:"optionalSubjectId"`
+	} `json:"optionalSubjectFilter"`
+}
+
+func (f filter) matches(r relationship) bool {
+	if f.OptionalResourceID != "" && f.OptionalResourceID != r.Resource.ObjectID {
+		return false
+	}
+	if f.OptionalRelation != "" && f.OptionalRelation != r.Relation {
+		return false
+	}
+	if sf := f.OptionalSubjectFilter; sf != nil {
+		return sf.SubjectType == r.Subject.Object.ObjectType && (sf.OptionalSubjectID == "" || sf.OptionalSubjectID == r.Subject.Object.ObjectID)
+	}
+	return true
+}
+
+// spiceDBMock mimics the SpiceDB HTTP API gateway, checking the direct
+// relationships only.
+type spiceDBMock struct {
+	mu            sync.Mutex
+	schema        string
+	relationships []relationship
+}
+
+func (sm *spiceDBMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer "+key {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/schema/write":
+		var req struct {
+			Schema string `json:"schema"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		sm.schema = req.Schema
+		w.Write([]byte("{}"))
+	case "/v1/relationships/write":
+		var req struct {
+			Updates []struct {
+				Relationship relationship `json:"relationship"`
+			} `json:"updates"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		for _, u := range req.Updates {
+			sm.relationships = append(sm.relationships, u.Relationship)
+		}
+		w.Write([]byte("{}"))
+	case "/v1/relationships/delete":
+		var req struct {
+			RelationshipFilter filter `json:"relationshipFilter"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		rels := []relationship{}
+		for _, rel := range sm.relationships {
+			if !req.RelationshipFilter.matches(rel) {
+				rels = append(rels, rel)
+			}
+		}
+		sm.relationships = rels
+		w.Write([]byte("{}"))
+	case "/v1/relationships/read":
+		var req struct {
+			RelationshipFilter filter `json:"relationshipFilter"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		enc := json.NewEncoder(w)
+		for _, rel := range sm.relationships {
+			if req.RelationshipFilter.matches(rel) {
+				enc.Encode(map[string]interface{}{"result": map[string]interface{}{"relationship": rel}})
+			}
+		}
+	case "/v1/permissions/check":
+		var req struct {
+			Resource   object  `json:"resource"`
+			Permission string  `json:"permission"`
+			Subject    subject `json:"subject"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		res := "PERMISSIONSHIP_NO_PERMISSION"
+		for _, rel := range sm.relationships {
+			if rel.Resource == req.Resource && rel.Relation == req.Permission && rel.Subject == req.Subject {
+				res = "PERMISSIONSHIP_HAS_PERMISSION"
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]string{"permissionship": res})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestWriteSchema(t *testing.T) {
+	mock := &spiceDBMock{}
+	ts := httptest.NewServer(mock)
+	defer ts.Close()
+
+	err := spicedb.WriteSchema(context.Background(), ts.Client(), ts.URL, key)
+	assert.Nil(t, err, fmt.Sprintf("writing schema expected to succeed: %s", err))
+	assert.Equal(t, spicedb.Schema, mock.schema, "expected schema to be written")
+
+	err = spicedb.WriteSchema(context.Background(), ts.Client(), ts.URL, "invalid")
+	assert.NotNil(t, err, "writing schema with invalid key expected to fail")
+}
+
+func TestPolicies(t *testing.T) {
+	ts := httptest.NewServer(&spiceDBMock{})
+	defer ts.Close()
+	pa := spicedb.NewPolicyAgent(ts.Client(), ts.URL, key)
+
+	policies := []auth.PolicyReq{
+		{Subject: "user1", Object: "thing1", Relation: "read"},
+		{Subject: "user1", Object: "thing1", Relation: "write"},
+		{Subject: "members:group1#member", Object: "thing2", Relation: "read"},
+		{Subject: "*", Object: "user", Relation: "create"},
+	}
+	for _, pr := range policies {
+		err := pa.AddPolicy(context.Background(), pr)
+		require.Nil(t, err, fmt.Sprintf("adding policy %v expected to succeed: %s", pr, err))
+	}
+
+	checkCases := []struct {
+		desc   string
+		policy auth.PolicyReq
+		err    error
+	}{
+		{
+			desc:   "check existing policy",
+			policy: auth.PolicyReq{Subject: "user1", Object: "thing1", Relation: "write"},
+			err:    nil,
+		},
+		{
+			desc:   "check existing subject set policy",
+			policy: auth.PolicyReq{Subject: "members:group1#member", Object: "thing2", Relation: "read"},
+			err:    nil,
+		},
+		{
+			desc:   "check existing any subject policy",
+			policy: auth.PolicyReq{Subject: "*", Object: "user", Relation: "create"},
+			err:    nil,
+		},
+		{
+			desc:   "check non-existing policy",
+			policy: auth.PolicyReq{Subject: "user1", Object: "thing1", Relation: "delete"},
+			err:    auth.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range checkCases {
+		err := pa.CheckPolicy(context.Background(), tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	retrieveCases := []struct {
+		desc   string
+		policy auth.PolicyReq
+		size   int
+	}{
+		{
+			desc:   "retrieve all policies",
+			policy: auth.PolicyReq{},
+			size:   4,
+		},
+		{
+			desc:   "retrieve policies of subject",
+			policy: auth.PolicyReq{Subject: "user1"},
+			size:   2,
+		},
+		{
+			desc:   "retrieve policies of object and relation",
+			policy: auth.PolicyReq{Object: "thing1", Relation: "read"},
+			size:   1,
+		},
+	}
+
+	for _, tc := range retrieveCases {
+		tuples, err := pa.RetrievePolicies(context.Background(), tc.policy)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s\n", tc.desc, err))
+		assert.Len(t, tuples, tc.size, fmt.Sprintf("%s: expected %d policies got %d\n", tc.desc, tc.size, len(tuples)))
+	}
+
+	tuples, err := pa.RetrievePolicies(context.Background(), auth.PolicyReq{Object: "thing2"})
+	require.Nil(t, err, fmt.Sprintf("retrieving policies expected to succeed: %s", err))
+	require.Len(t, tuples, 1)
+	set := tuples[0].GetSubject().GetSet()
+	assert.Equal(t, "group1", set.GetObject(), fmt.Sprintf("expected subject set object group1 got %s", set.GetObject()))
+	assert.Equal(t, "member", set.GetRelation(), fmt.Sprintf("expected subject set relation member got %s", set.GetRelation()))
+
+	tuples, err = pa.RetrievePolicies(context.Background(), auth.PolicyReq{Object: "user"})
+	require.Nil(t, err, fmt.Sprintf("retrieving policies expected to succeed: %s", err))
+	require.Len(t, tuples, 1)
+	assert.Equal(t, "*", tuples[0].GetSubject().GetId(), fmt.Sprintf("expected subject * got %s", tuples[0].GetSubject().GetId()))
+
+	err = pa.DeletePolicy(context.Background(), policies[0])
+	assert.Nil(t, err, fmt.Sprintf("deleting policy expected to succeed: %s", err))
+	err = pa.CheckPolicy(context.Background(), policies[0])
+	assert.True(t, errors.Contains(err, auth.ErrAuthorization), fmt.Sprintf("checking deleted policy: expected %s got %s\n", auth.ErrAuthorization, err))
+	err = pa.CheckPolicy(context.Background(), policies[1])
+	assert.Nil(t, err, fmt.Sprintf("checking remaining policy expected to succeed: %s", err))
+}
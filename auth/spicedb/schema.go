@@ -0,0 +1,42 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package spicedb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Schema is the SpiceDB schema of the "members" namespace. Unlike Keto,
+// SpiceDB requires the relations to be declared upfront, so the policies
+// are limited to the relations below. The subject is either the user, or
+// the members of the group given as the "members:<group_id>#member"
+// subject set.
+const Schema = `definition user {}
+
+definition members {
+	relation create: user | members#member
+	relation read: user | members#member
+	relation write: user | members#member
+	relation delete: user | members#member
+	relation access: user | members#member
+	relation member: user | members#member
+}`
+
+type writeSchemaReq struct {
+	Schema string `json:"schema"`
+}
+
+// WriteSchema bootstraps the SpiceDB running at the given URL with the
+// Schema. Writing the schema is idempotent, so it's written on every
+// service start.
+func WriteSchema(ctx context.Context, client *http.Client, url, key string) error {
+	body, err := json.Marshal(writeSchemaReq{Schema: Schema})
+	if err != nil {
+		return err
+	}
+
+	return newAPI(client, url, key).do(ctx, schemaWritePath, body, nil)
+}
@@ -0,0 +1,136 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// The types of the entities the policy templates are applied to.
+const (
+	UserEntity    = "user"
+	ThingEntity   = "thing"
+	ChannelEntity = "channel"
+)
+
+// The placeholders of the template policies, replaced by the ID of the
+// created entity and the ID of its owner.
+const (
+	idPlaceholder    = "{id}"
+	ownerPlaceholder = "{owner}"
+)
+
+var errInvalidTemplate = errors.New("invalid policy template")
+
+// Entity represents the created entity the policy template is applied to.
+type Entity struct {
+	Type string
+	ID   string
+	// Owner is the optional ID of the user who created the entity, required
+	// by the templates referring to the owner.
+	Owner string
+}
+
+// TemplatePolicy represents the policy added when the entity is created.
+// The Subject and the Object may contain the {id} and {owner} placeholders.
+// The empty Object stands for the created entity.
+type TemplatePolicy struct {
+	Subject   string `json:"subject"`
+	Object    string `json:"object,omitempty"`
+	Relation  string `json:"relation"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// PolicyTemplates maps the entity types to the policies added when the
+// entity of the type is created.
+type PolicyTemplates map[string][]TemplatePolicy
+
+// DefaultPolicyTemplates returns the templates making the users the members
+// of the users object, and giving the owner and the admins the read, write
+// and delete relations on the things and the channels.
+func DefaultPolicyTemplates() PolicyTemplates {
+	admins := fmt.Sprintf("%s:%s#%s", MembersNamespace, authoritiesObject, memberRelation)
+	owned := func() []TemplatePolicy {
+		var tps []TemplatePolicy
+		for _, subject := range []string{ownerPlaceholder, admins} {
+			for _, relation := range []string{"read", "write", "delete"} {
+				tps = append(tps, TemplatePolicy{Subject: subject, Relation: relation})
+			}
+		}
+		return tps
+	}
+
+	return PolicyTemplates{
+		UserEntity:    {{Subject: idPlaceholder, Object: "users", Relation: memberRelation}},
+		ThingEntity:   owned(),
+		ChannelEntity: owned(),
+	}
+}
+
+// ParsePolicyTemplates parses the JSON object mapping the entity types to
+// their template policies. The entity types missing from the object keep
+// the default templates, while the empty list disables the template.
+func ParsePolicyTemplates(data []byte) (PolicyTemplates, error) {
+	var pt PolicyTemplates
+	if err := json.Unmarshal(data, &pt); err != nil {
+		return nil, errors.Wrap(errInvalidTemplate, err)
+	}
+
+	templates := DefaultPolicyTemplates()
+	for entityType, tps := range pt {
+		if _, ok := templates[entityType]; !ok {
+			return nil, errors.Wrap(errInvalidTemplate, fmt.Errorf("unknown entity type '%s'", entityType))
+		}
+		for _, tp := range tps {
+			if err := tp.validate(); err != nil {
+				return nil, errors.Wrap(errInvalidTemplate, err)
+			}
+		}
+		templates[entityType] = tps
+	}
+
+	return templates, nil
+}
+
+func (tp TemplatePolicy) validate() error {
+	if tp.Subject == "" || tp.Relation == "" {
+		return fmt.Errorf("policy '%s#%s' without subject or relation", tp.Object, tp.Relation)
+	}
+	if !ValidNamespace(tp.Namespace) {
+		return fmt.Errorf("unknown namespace '%s'", tp.Namespace)
+	}
+	return nil
+}
+
+// policies materializes the template of the entity type.
+func (pt PolicyTemplates) policies(e Entity) ([]PolicyReq, error) {
+	tps, ok := pt[e.Type]
+	if !ok || e.ID == "" {
+		return nil, ErrMalformedEntity
+	}
+
+	r := strings.NewReplacer(idPlaceholder, e.ID, ownerPlaceholder, e.Owner)
+	prs := make([]PolicyReq, len(tps))
+	for i, tp := range tps {
+		if e.Owner == "" && (strings.Contains(tp.Subject, ownerPlaceholder) || strings.Contains(tp.Object, ownerPlaceholder)) {
+			return nil, ErrMalformedEntity
+		}
+		obj := tp.Object
+		if obj == "" {
+			obj = idPlaceholder
+		}
+		prs[i] = PolicyReq{
+			Subject:   r.Replace(tp.Subject),
+			Object:    r.Replace(obj),
+			Relation:  tp.Relation,
+			Namespace: tp.Namespace,
+		}
+	}
+
+	return prs, nil
+}
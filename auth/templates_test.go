@@ -0,0 +1,73 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package auth_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePolicyTemplates(t *testing.T) {
+	defaults := auth.DefaultPolicyTemplates()
+
+	cases := []struct {
+		desc      string
+		data      string
+		templates auth.PolicyTemplates
+		err       bool
+	}{
+		{
+			desc:      "parse empty templates",
+			data:      `{}`,
+			templates: defaults,
+		},
+		{
+			desc: "parse thing template",
+			data: `{"thing": [{"subject": "{owner}", "relation": "admin", "namespace": "things"}]}`,
+			templates: auth.PolicyTemplates{
+				auth.UserEntity:    defaults[auth.UserEntity],
+				auth.ThingEntity:   {{Subject: "{owner}", Relation: "admin", Namespace: auth.ThingsNamespace}},
+				auth.ChannelEntity: defaults[auth.ChannelEntity],
+			},
+		},
+		{
+			desc: "parse disabled user template",
+			data: `{"user": []}`,
+			templates: auth.PolicyTemplates{
+				auth.UserEntity:    {},
+				auth.ThingEntity:   defaults[auth.ThingEntity],
+				auth.ChannelEntity: defaults[auth.ChannelEntity],
+			},
+		},
+		{
+			desc: "parse template of unknown entity type",
+			data: `{"group": [{"subject": "{owner}", "relation": "admin"}]}`,
+			err:  true,
+		},
+		{
+			desc: "parse template without relation",
+			data: `{"thing": [{"subject": "{owner}"}]}`,
+			err:  true,
+		},
+		{
+			desc: "parse template with unknown namespace",
+			data: `{"thing": [{"subject": "{owner}", "relation": "admin", "namespace": "unknown"}]}`,
+			err:  true,
+		},
+		{
+			desc: "parse malformed templates",
+			data: `[]`,
+			err:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		templates, err := auth.ParsePolicyTemplates([]byte(tc.data))
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.templates, templates, fmt.Sprintf("%s: expected %v got %v", tc.desc, tc.templates, templates))
+	}
+}
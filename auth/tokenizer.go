@@ -3,6 +3,15 @@
 
 package auth
 
+import "crypto"
+
+// PublicKey represents the public key used to verify the tokens.
+type PublicKey struct {
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
 // Tokenizer specifies API for encoding and decoding between string and Key.
 type Tokenizer interface {
 	// Issue converts API Key to its string representation.
@@ -10,4 +19,8 @@ type Tokenizer interface {
 
 	// Parse extracts API Key data from string token.
 	Parse(string) (Key, error)
+
+	// PublicKeys returns the public keys used to verify the tokens. The
+	// HMAC secrets are never returned.
+	PublicKeys() []PublicKey
 }
@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveAccount           = "save_service_account"
+	retrieveAccount       = "retrieve_service_account"
+	retrieveAccountsOwner = "retrieve_service_accounts_by_owner"
+	updateAccountScopes   = "update_service_account_scopes"
+	updateAccountStatus   = "update_service_account_status"
+	removeAccount         = "remove_service_account"
+)
+
+var _ auth.ServiceAccountRepository = (*accountRepositoryMiddleware)(nil)
+
+type accountRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.ServiceAccountRepository
+}
+
+// ServiceAccountRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func ServiceAccountRepositoryMiddleware(tracer opentracing.Tracer, ar auth.ServiceAccountRepository) auth.ServiceAccountRepository {
+	return accountRepositoryMiddleware{
+		tracer: tracer,
+		repo:   ar,
+	}
+}
+
+func (arm accountRepositoryMiddleware) Save(ctx context.Context, sa auth.ServiceAccount) error {
+	span := createSpan(ctx, arm.tracer, saveAccount)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.Save(ctx, sa)
+}
+
+func (arm accountRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (auth.ServiceAccount, error) {
+	span := createSpan(ctx, arm.tracer, retrieveAccount)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.RetrieveByID(ctx, id)
+}
+
+func (arm accountRepositoryMiddleware) RetrieveByOwner(ctx context.Context, ownerID string) ([]auth.ServiceAccount, error) {
+	span := createSpan(ctx, arm.tracer, retrieveAccountsOwner)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.RetrieveByOwner(ctx, ownerID)
+}
+
+func (arm accountRepositoryMiddleware) UpdateScopes(ctx context.Context, id string, scopes []string) error {
+	span := createSpan(ctx, arm.tracer, updateAccountScopes)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.UpdateScopes(ctx, id, scopes)
+}
+
+func (arm accountRepositoryMiddleware) UpdateStatus(ctx context.Context, id string, disabled bool) error {
+	span := createSpan(ctx, arm.tracer, updateAccountStatus)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.UpdateStatus(ctx, id, disabled)
+}
+
+func (arm accountRepositoryMiddleware) Remove(ctx context.Context, id string) error {
+	span := createSpan(ctx, arm.tracer, removeAccount)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.Remove(ctx, id)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveAuditRecord      = "save_audit_record"
+	retrieveAuditRecords = "retrieve_audit_records"
+)
+
+var _ auth.AuditRepository = (*auditRepositoryMiddleware)(nil)
+
+type auditRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.AuditRepository
+}
+
+// AuditRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func AuditRepositoryMiddleware(tracer opentracing.Tracer, ar auth.AuditRepository) auth.AuditRepository {
+	return auditRepositoryMiddleware{
+		tracer: tracer,
+		repo:   ar,
+	}
+}
+
+func (arm auditRepositoryMiddleware) Save(ctx context.Context, r auth.AuditRecord) error {
+	span := createSpan(ctx, arm.tracer, saveAuditRecord)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.Save(ctx, r)
+}
+
+func (arm auditRepositoryMiddleware) RetrieveAll(ctx context.Context, filter auth.AuditFilter, offset, limit uint64) (auth.AuditPage, error) {
+	span := createSpan(ctx, arm.tracer, retrieveAuditRecords)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.RetrieveAll(ctx, filter, offset, limit)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth/oidc"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveCode   = "save_code"
+	removeCode = "remove_code"
+)
+
+var _ oidc.CodeRepository = (*codeRepositoryMiddleware)(nil)
+
+type codeRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   oidc.CodeRepository
+}
+
+// CodeRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func CodeRepositoryMiddleware(tracer opentracing.Tracer, cr oidc.CodeRepository) oidc.CodeRepository {
+	return codeRepositoryMiddleware{
+		tracer: tracer,
+		repo:   cr,
+	}
+}
+
+func (crm codeRepositoryMiddleware) Save(ctx context.Context, code oidc.Code) error {
+	span := createSpan(ctx, crm.tracer, saveCode)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Save(ctx, code)
+}
+
+func (crm codeRepositoryMiddleware) Remove(ctx context.Context, code string) (oidc.Code, error) {
+	span := createSpan(ctx, crm.tracer, removeCode)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.Remove(ctx, code)
+}
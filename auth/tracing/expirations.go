@@ -0,0 +1,56 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveExpiration     = "save_policy_expiration"
+	retrieveExpiration = "retrieve_policy_expiration"
+	removeExpiration   = "remove_policy_expiration"
+)
+
+var _ auth.PolicyExpirationRepository = (*expirationRepositoryMiddleware)(nil)
+
+type expirationRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.PolicyExpirationRepository
+}
+
+// PolicyExpirationRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func PolicyExpirationRepositoryMiddleware(tracer opentracing.Tracer, er auth.PolicyExpirationRepository) auth.PolicyExpirationRepository {
+	return expirationRepositoryMiddleware{
+		tracer: tracer,
+		repo:   er,
+	}
+}
+
+func (erm expirationRepositoryMiddleware) Save(ctx context.Context, pe auth.PolicyExpiration) error {
+	span := createSpan(ctx, erm.tracer, saveExpiration)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.Save(ctx, pe)
+}
+
+func (erm expirationRepositoryMiddleware) Retrieve(ctx context.Context, pr auth.PolicyReq) (auth.PolicyExpiration, error) {
+	span := createSpan(ctx, erm.tracer, retrieveExpiration)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.Retrieve(ctx, pr)
+}
+
+func (erm expirationRepositoryMiddleware) Remove(ctx context.Context, pr auth.PolicyReq) error {
+	span := createSpan(ctx, erm.tracer, removeExpiration)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.Remove(ctx, pr)
+}
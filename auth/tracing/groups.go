@@ -6,6 +6,7 @@ package tracing
 
 import (
 	"context"
+	"time"
 
 	"github.com/mainflux/mainflux/auth"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -23,6 +24,9 @@ const (
 	memberships         = "memberships"
 	members             = "members"
 	unassign            = "unassign"
+	setExpiration       = "set_expiration"
+	setRelation         = "set_relation"
+	retrieveExpired     = "retrieve_expired"
 )
 
 var _ auth.GroupRepository = (*groupRepositoryMiddleware)(nil)
@@ -112,12 +116,12 @@ func (grm groupRepositoryMiddleware) Members(ctx context.Context, groupID, group
 	return grm.repo.Members(ctx, groupID, groupType, pm)
 }
 
-func (grm groupRepositoryMiddleware) Assign(ctx context.Context, groupID, groupType string, memberIDs ...string) error {
+func (grm groupRepositoryMiddleware) Assign(ctx context.Context, groupID, groupType string, m auth.Membership, memberIDs ...string) error {
 	span := createSpan(ctx, grm.tracer, assign)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return grm.repo.Assign(ctx, groupID, groupType, memberIDs...)
+	return grm.repo.Assign(ctx, groupID, groupType, m, memberIDs...)
 }
 
 func (grm groupRepositoryMiddleware) Unassign(ctx context.Context, groupID string, memberIDs ...string) error {
@@ -127,3 +131,27 @@ func (grm groupRepositoryMiddleware) Unassign(ctx context.Context, groupID strin
 
 	return grm.repo.Unassign(ctx, groupID, memberIDs...)
 }
+
+func (grm groupRepositoryMiddleware) SetExpiration(ctx context.Context, groupID string, expiresAt time.Time, memberIDs ...string) error {
+	span := createSpan(ctx, grm.tracer, setExpiration)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.SetExpiration(ctx, groupID, expiresAt, memberIDs...)
+}
+
+func (grm groupRepositoryMiddleware) SetRelation(ctx context.Context, groupID, relation string, memberIDs ...string) error {
+	span := createSpan(ctx, grm.tracer, setRelation)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.SetRelation(ctx, groupID, relation, memberIDs...)
+}
+
+func (grm groupRepositoryMiddleware) RetrieveExpired(ctx context.Context, before time.Time) ([]auth.MembershipExpiration, error) {
+	span := createSpan(ctx, grm.tracer, retrieveExpired)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return grm.repo.RetrieveExpired(ctx, before)
+}
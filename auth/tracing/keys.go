@@ -16,6 +16,9 @@ const (
 	saveOp     = "save"
 	retrieveOp = "retrieve_by_id"
 	revokeOp   = "remove"
+	revokeAll  = "remove_by_issuer"
+	listOp     = "retrieve_by_issuer"
+	labelOp    = "update_label"
 )
 
 var _ auth.KeyRepository = (*keyRepositoryMiddleware)(nil)
@@ -61,6 +64,30 @@ func (krm keyRepositoryMiddleware) Remove(ctx context.Context, owner, id string)
 	return krm.repo.Remove(ctx, owner, id)
 }
 
+func (krm keyRepositoryMiddleware) RetrieveByIssuer(ctx context.Context, owner, orgID string, offset, limit uint64) (auth.KeysPage, error) {
+	span := createSpan(ctx, krm.tracer, listOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return krm.repo.RetrieveByIssuer(ctx, owner, orgID, offset, limit)
+}
+
+func (krm keyRepositoryMiddleware) UpdateLabel(ctx context.Context, owner, id, label string) error {
+	span := createSpan(ctx, krm.tracer, labelOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return krm.repo.UpdateLabel(ctx, owner, id, label)
+}
+
+func (krm keyRepositoryMiddleware) RemoveByIssuer(ctx context.Context, owner string) ([]auth.Key, error) {
+	span := createSpan(ctx, krm.tracer, revokeAll)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return krm.repo.RemoveByIssuer(ctx, owner)
+}
+
 func createSpan(ctx context.Context, tracer opentracing.Tracer, opName string) opentracing.Span {
 	if parentSpan := opentracing.SpanFromContext(ctx); parentSpan != nil {
 		return tracer.StartSpan(
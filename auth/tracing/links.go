@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth/oidc"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveLink          = "save_link"
+	retrieveLink      = "retrieve_link"
+	retrieveUserLinks = "retrieve_user_links"
+	removeLink        = "remove_link"
+	removeUserLinks   = "remove_user_links"
+)
+
+var _ oidc.LinkRepository = (*linkRepositoryMiddleware)(nil)
+
+type linkRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   oidc.LinkRepository
+}
+
+// LinkRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func LinkRepositoryMiddleware(tracer opentracing.Tracer, lr oidc.LinkRepository) oidc.LinkRepository {
+	return linkRepositoryMiddleware{
+		tracer: tracer,
+		repo:   lr,
+	}
+}
+
+func (lrm linkRepositoryMiddleware) Save(ctx context.Context, link oidc.Link) error {
+	span := createSpan(ctx, lrm.tracer, saveLink)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lrm.repo.Save(ctx, link)
+}
+
+func (lrm linkRepositoryMiddleware) Retrieve(ctx context.Context, issuer, subject string) (oidc.Link, error) {
+	span := createSpan(ctx, lrm.tracer, retrieveLink)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lrm.repo.Retrieve(ctx, issuer, subject)
+}
+
+func (lrm linkRepositoryMiddleware) RetrieveByUser(ctx context.Context, userID string) ([]oidc.Link, error) {
+	span := createSpan(ctx, lrm.tracer, retrieveUserLinks)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lrm.repo.RetrieveByUser(ctx, userID)
+}
+
+func (lrm linkRepositoryMiddleware) Remove(ctx context.Context, userID, issuer, subject string) error {
+	span := createSpan(ctx, lrm.tracer, removeLink)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lrm.repo.Remove(ctx, userID, issuer, subject)
+}
+
+func (lrm linkRepositoryMiddleware) RemoveByUser(ctx context.Context, userID string) error {
+	span := createSpan(ctx, lrm.tracer, removeUserLinks)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return lrm.repo.RemoveByUser(ctx, userID)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveOrg              = "save_org"
+	updateOrg            = "update_org"
+	removeOrg            = "remove_org"
+	retrieveOrg          = "retrieve_org"
+	retrieveOrgsByMember = "retrieve_orgs_by_member"
+	assignOrgMembers     = "assign_org_members"
+	unassignOrgMembers   = "unassign_org_members"
+	retrieveOrgMembers   = "retrieve_org_members"
+	isOrgMember          = "is_org_member"
+)
+
+var _ auth.OrgRepository = (*orgRepositoryMiddleware)(nil)
+
+type orgRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.OrgRepository
+}
+
+// OrgRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func OrgRepositoryMiddleware(tracer opentracing.Tracer, or auth.OrgRepository) auth.OrgRepository {
+	return orgRepositoryMiddleware{
+		tracer: tracer,
+		repo:   or,
+	}
+}
+
+func (orm orgRepositoryMiddleware) Save(ctx context.Context, o auth.Org) error {
+	span := createSpan(ctx, orm.tracer, saveOrg)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.Save(ctx, o)
+}
+
+func (orm orgRepositoryMiddleware) Update(ctx context.Context, o auth.Org) error {
+	span := createSpan(ctx, orm.tracer, updateOrg)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.Update(ctx, o)
+}
+
+func (orm orgRepositoryMiddleware) Remove(ctx context.Context, id string) error {
+	span := createSpan(ctx, orm.tracer, removeOrg)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.Remove(ctx, id)
+}
+
+func (orm orgRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (auth.Org, error) {
+	span := createSpan(ctx, orm.tracer, retrieveOrg)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.RetrieveByID(ctx, id)
+}
+
+func (orm orgRepositoryMiddleware) RetrieveByMember(ctx context.Context, memberID string, offset, limit uint64) (auth.OrgsPage, error) {
+	span := createSpan(ctx, orm.tracer, retrieveOrgsByMember)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.RetrieveByMember(ctx, memberID, offset, limit)
+}
+
+func (orm orgRepositoryMiddleware) AssignMembers(ctx context.Context, orgID, createdBy string, memberIDs ...string) error {
+	span := createSpan(ctx, orm.tracer, assignOrgMembers)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.AssignMembers(ctx, orgID, createdBy, memberIDs...)
+}
+
+func (orm orgRepositoryMiddleware) UnassignMembers(ctx context.Context, orgID string, memberIDs ...string) error {
+	span := createSpan(ctx, orm.tracer, unassignOrgMembers)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.UnassignMembers(ctx, orgID, memberIDs...)
+}
+
+func (orm orgRepositoryMiddleware) RetrieveMembers(ctx context.Context, orgID string, offset, limit uint64) (auth.OrgMembersPage, error) {
+	span := createSpan(ctx, orm.tracer, retrieveOrgMembers)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.RetrieveMembers(ctx, orgID, offset, limit)
+}
+
+func (orm orgRepositoryMiddleware) IsMember(ctx context.Context, orgID, memberID string) error {
+	span := createSpan(ctx, orm.tracer, isOrgMember)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return orm.repo.IsMember(ctx, orgID, memberID)
+}
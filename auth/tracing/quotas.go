@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveQuota         = "save_quota"
+	retrieveQuota     = "retrieve_quota"
+	retrieveUsage     = "retrieve_quota_usage"
+	chargeQuota       = "charge_quota"
+	releaseQuota      = "release_quota"
+	retrieveQuotaOrgs = "retrieve_quota_orgs"
+)
+
+var _ auth.QuotaRepository = (*quotaRepositoryMiddleware)(nil)
+
+type quotaRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.QuotaRepository
+}
+
+// QuotaRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func QuotaRepositoryMiddleware(tracer opentracing.Tracer, qr auth.QuotaRepository) auth.QuotaRepository {
+	return quotaRepositoryMiddleware{
+		tracer: tracer,
+		repo:   qr,
+	}
+}
+
+func (qrm quotaRepositoryMiddleware) Save(ctx context.Context, q auth.Quota) error {
+	span := createSpan(ctx, qrm.tracer, saveQuota)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.Save(ctx, q)
+}
+
+func (qrm quotaRepositoryMiddleware) RetrieveByOrg(ctx context.Context, orgID string) (auth.Quota, error) {
+	span := createSpan(ctx, qrm.tracer, retrieveQuota)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.RetrieveByOrg(ctx, orgID)
+}
+
+func (qrm quotaRepositoryMiddleware) Usage(ctx context.Context, orgID string) (auth.Usage, error) {
+	span := createSpan(ctx, qrm.tracer, retrieveUsage)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.Usage(ctx, orgID)
+}
+
+func (qrm quotaRepositoryMiddleware) Charge(ctx context.Context, orgID, resource string, limit uint64, ids ...string) error {
+	span := createSpan(ctx, qrm.tracer, chargeQuota)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.Charge(ctx, orgID, resource, limit, ids...)
+}
+
+func (qrm quotaRepositoryMiddleware) Release(ctx context.Context, resource string, ids ...string) error {
+	span := createSpan(ctx, qrm.tracer, releaseQuota)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.Release(ctx, resource, ids...)
+}
+
+func (qrm quotaRepositoryMiddleware) RetrieveOrgs(ctx context.Context, resource, id string) ([]string, error) {
+	span := createSpan(ctx, qrm.tracer, retrieveQuotaOrgs)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return qrm.repo.RetrieveOrgs(ctx, resource, id)
+}
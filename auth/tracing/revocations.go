@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveRevocation     = "save_revocation"
+	containsRevocation = "contains_revocation"
+	saveIssuerRevoke   = "save_issuer_revocation"
+	retrieveIssuer     = "retrieve_issuer_revocation"
+)
+
+var _ auth.RevocationRepository = (*revocationRepositoryMiddleware)(nil)
+
+type revocationRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.RevocationRepository
+}
+
+// RevocationRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func RevocationRepositoryMiddleware(tracer opentracing.Tracer, rr auth.RevocationRepository) auth.RevocationRepository {
+	return revocationRepositoryMiddleware{
+		tracer: tracer,
+		repo:   rr,
+	}
+}
+
+func (rrm revocationRepositoryMiddleware) Save(ctx context.Context, r auth.Revocation) error {
+	span := createSpan(ctx, rrm.tracer, saveRevocation)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.Save(ctx, r)
+}
+
+func (rrm revocationRepositoryMiddleware) Contains(ctx context.Context, keyID string) (bool, error) {
+	span := createSpan(ctx, rrm.tracer, containsRevocation)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.Contains(ctx, keyID)
+}
+
+func (rrm revocationRepositoryMiddleware) SaveIssuer(ctx context.Context, issuerID string, revokedAt time.Time) error {
+	span := createSpan(ctx, rrm.tracer, saveIssuerRevoke)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.SaveIssuer(ctx, issuerID, revokedAt)
+}
+
+func (rrm revocationRepositoryMiddleware) RetrieveIssuer(ctx context.Context, issuerID string) (time.Time, error) {
+	span := createSpan(ctx, rrm.tracer, retrieveIssuer)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RetrieveIssuer(ctx, issuerID)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveRole                = "save_role"
+	retrieveRole            = "retrieve_role"
+	retrieveAllRoles        = "retrieve_all_roles"
+	removeRole              = "remove_role"
+	saveRoleAssignment      = "save_role_assignment"
+	retrieveRoleAssignments = "retrieve_role_assignments"
+	removeRoleAssignment    = "remove_role_assignment"
+)
+
+var _ auth.RoleRepository = (*roleRepositoryMiddleware)(nil)
+
+type roleRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.RoleRepository
+}
+
+// RoleRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func RoleRepositoryMiddleware(tracer opentracing.Tracer, rr auth.RoleRepository) auth.RoleRepository {
+	return roleRepositoryMiddleware{
+		tracer: tracer,
+		repo:   rr,
+	}
+}
+
+func (rrm roleRepositoryMiddleware) Save(ctx context.Context, r auth.Role) error {
+	span := createSpan(ctx, rrm.tracer, saveRole)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.Save(ctx, r)
+}
+
+func (rrm roleRepositoryMiddleware) RetrieveByName(ctx context.Context, name string) (auth.Role, error) {
+	span := createSpan(ctx, rrm.tracer, retrieveRole)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RetrieveByName(ctx, name)
+}
+
+func (rrm roleRepositoryMiddleware) RetrieveAll(ctx context.Context) ([]auth.Role, error) {
+	span := createSpan(ctx, rrm.tracer, retrieveAllRoles)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RetrieveAll(ctx)
+}
+
+func (rrm roleRepositoryMiddleware) Remove(ctx context.Context, name string) error {
+	span := createSpan(ctx, rrm.tracer, removeRole)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.Remove(ctx, name)
+}
+
+func (rrm roleRepositoryMiddleware) SaveAssignment(ctx context.Context, ra auth.RoleAssignment) error {
+	span := createSpan(ctx, rrm.tracer, saveRoleAssignment)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.SaveAssignment(ctx, ra)
+}
+
+func (rrm roleRepositoryMiddleware) RetrieveAssignments(ctx context.Context, ra auth.RoleAssignment) ([]auth.RoleAssignment, error) {
+	span := createSpan(ctx, rrm.tracer, retrieveRoleAssignments)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RetrieveAssignments(ctx, ra)
+}
+
+func (rrm roleRepositoryMiddleware) RemoveAssignment(ctx context.Context, ra auth.RoleAssignment) error {
+	span := createSpan(ctx, rrm.tracer, removeRoleAssignment)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return rrm.repo.RemoveAssignment(ctx, ra)
+}
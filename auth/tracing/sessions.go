@@ -0,0 +1,84 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveSession          = "save_session"
+	retrieveSession      = "retrieve_session"
+	retrieveUserSessions = "retrieve_user_sessions"
+	touchSession         = "touch_session"
+	removeSession        = "remove_session"
+	removeUserSessions   = "remove_user_sessions"
+)
+
+var _ auth.SessionRepository = (*sessionRepositoryMiddleware)(nil)
+
+type sessionRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.SessionRepository
+}
+
+// SessionRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func SessionRepositoryMiddleware(tracer opentracing.Tracer, sr auth.SessionRepository) auth.SessionRepository {
+	return sessionRepositoryMiddleware{
+		tracer: tracer,
+		repo:   sr,
+	}
+}
+
+func (srm sessionRepositoryMiddleware) Save(ctx context.Context, s auth.Session) error {
+	span := createSpan(ctx, srm.tracer, saveSession)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Save(ctx, s)
+}
+
+func (srm sessionRepositoryMiddleware) Retrieve(ctx context.Context, userID, id string) (auth.Session, error) {
+	span := createSpan(ctx, srm.tracer, retrieveSession)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Retrieve(ctx, userID, id)
+}
+
+func (srm sessionRepositoryMiddleware) RetrieveByUser(ctx context.Context, userID string) ([]auth.Session, error) {
+	span := createSpan(ctx, srm.tracer, retrieveUserSessions)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveByUser(ctx, userID)
+}
+
+func (srm sessionRepositoryMiddleware) Touch(ctx context.Context, id string, seenAt time.Time) error {
+	span := createSpan(ctx, srm.tracer, touchSession)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Touch(ctx, id, seenAt)
+}
+
+func (srm sessionRepositoryMiddleware) Remove(ctx context.Context, userID, id string) error {
+	span := createSpan(ctx, srm.tracer, removeSession)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Remove(ctx, userID, id)
+}
+
+func (srm sessionRepositoryMiddleware) RemoveByUser(ctx context.Context, userID string) error {
+	span := createSpan(ctx, srm.tracer, removeUserSessions)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RemoveByUser(ctx, userID)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveShare              = "save_share"
+	retrieveShare          = "retrieve_share"
+	retrieveSharesGrantor  = "retrieve_shares_by_grantor"
+	retrieveSharesGrantee  = "retrieve_shares_by_grantee"
+	retrieveSharesByObject = "retrieve_shares_by_object"
+	removeShare            = "remove_share"
+)
+
+var _ auth.ShareRepository = (*shareRepositoryMiddleware)(nil)
+
+type shareRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   auth.ShareRepository
+}
+
+// ShareRepositoryMiddleware tracks request and their latency, and adds spans to context.
+func ShareRepositoryMiddleware(tracer opentracing.Tracer, sr auth.ShareRepository) auth.ShareRepository {
+	return shareRepositoryMiddleware{
+		tracer: tracer,
+		repo:   sr,
+	}
+}
+
+func (srm shareRepositoryMiddleware) Save(ctx context.Context, s auth.Share) error {
+	span := createSpan(ctx, srm.tracer, saveShare)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Save(ctx, s)
+}
+
+func (srm shareRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (auth.Share, error) {
+	span := createSpan(ctx, srm.tracer, retrieveShare)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveByID(ctx, id)
+}
+
+func (srm shareRepositoryMiddleware) RetrieveByGrantor(ctx context.Context, grantorID string) ([]auth.Share, error) {
+	span := createSpan(ctx, srm.tracer, retrieveSharesGrantor)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveByGrantor(ctx, grantorID)
+}
+
+func (srm shareRepositoryMiddleware) RetrieveByGrantee(ctx context.Context, granteeID string) ([]auth.Share, error) {
+	span := createSpan(ctx, srm.tracer, retrieveSharesGrantee)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveByGrantee(ctx, granteeID)
+}
+
+func (srm shareRepositoryMiddleware) RetrieveByObject(ctx context.Context, granteeID, object string) ([]auth.Share, error) {
+	span := createSpan(ctx, srm.tracer, retrieveSharesByObject)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.RetrieveByObject(ctx, granteeID, object)
+}
+
+func (srm shareRepositoryMiddleware) Remove(ctx context.Context, id string) error {
+	span := createSpan(ctx, srm.tracer, removeShare)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return srm.repo.Remove(ctx, id)
+}
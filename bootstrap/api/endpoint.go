@@ -65,6 +65,23 @@ func updateCertEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 	}
 }
 
+func updateFingerprintEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateFingerprintReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.UpdateFingerprint(ctx, req.key, req.id, req.Fingerprint); err != nil {
+			return nil, err
+		}
+
+		res := configRes{}
+
+		return res, nil
+	}
+}
+
 func viewEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(entityReq)
@@ -88,14 +105,15 @@ func viewEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 		}
 
 		res := viewRes{
-			MFThing:     config.MFThing,
-			MFKey:       config.MFKey,
-			Channels:    channels,
-			ExternalID:  config.ExternalID,
-			ExternalKey: config.ExternalKey,
-			Name:        config.Name,
-			Content:     config.Content,
-			State:       config.State,
+			MFThing:         config.MFThing,
+			MFKey:           config.MFKey,
+			Channels:        channels,
+			ExternalID:      config.ExternalID,
+			ExternalKey:     config.ExternalKey,
+			CertFingerprint: config.CertFingerprint,
+			Name:            config.Name,
+			Content:         toContentRes(config.Content),
+			State:           config.State,
 		}
 
 		return res, nil
@@ -129,6 +147,37 @@ func updateEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 	}
 }
 
+func patchEndpoint(svc bootstrap.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(patchReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		config := bootstrap.Config{
+			MFThing: req.id,
+			Name:    req.Name,
+			Content: req.Content,
+		}
+
+		saved, err := svc.Patch(ctx, req.key, config)
+		if err != nil {
+			return nil, err
+		}
+
+		res := viewRes{
+			MFThing:    saved.MFThing,
+			ExternalID: saved.ExternalID,
+			Name:       saved.Name,
+			Content:    toContentRes(saved.Content),
+			State:      saved.State,
+		}
+
+		return res, nil
+	}
+}
+
 func updateConnEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(updateConnReq)
@@ -186,7 +235,7 @@ func listEndpoint(svc bootstrap.Service) endpoint.Endpoint {
 				ExternalID:  cfg.ExternalID,
 				ExternalKey: cfg.ExternalKey,
 				Name:        cfg.Name,
-				Content:     cfg.Content,
+				Content:     toContentRes(cfg.Content),
 				State:       cfg.State,
 			}
 			res.Configs = append(res.Configs, view)
@@ -219,7 +268,16 @@ func bootstrapEndpoint(svc bootstrap.Service, reader bootstrap.ConfigReader, sec
 			return nil, err
 		}
 
-		cfg, err := svc.Bootstrap(ctx, req.key, req.id, secure)
+		// The key takes precedence over the client certificate, so the Things
+		// presenting the certificate for other purposes can still bootstrap
+		// using the external key.
+		var cfg bootstrap.Config
+		var err error
+		if req.key != "" {
+			cfg, err = svc.Bootstrap(ctx, req.key, req.id, secure)
+		} else {
+			cfg, err = svc.BootstrapByCert(ctx, req.fingerprint, req.id)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -42,10 +42,10 @@ const (
 	addExternalID  = "external-id"
 	addExternalKey = "external-key"
 	addName        = "name"
-	addContent     = "config"
 )
 
 var (
+	addContent  = bootstrap.Content{App: map[string]interface{}{"content": "config"}}
 	encKey      = []byte("1234567891011121")
 	addChannels = []string{"1"}
 	metadata    = map[string]interface{}{"meta": "data"}
@@ -185,7 +185,7 @@ func newThingsService(auth mainflux.AuthServiceClient) things.Service {
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := thingsapi.MakeHandler(mocktracer.New(), svc)
+	mux := thingsapi.MakeHandler(mocktracer.New(), svc, nil)
 	return httptest.NewServer(mux)
 }
 
@@ -357,7 +357,7 @@ func TestView(t *testing.T) {
 		ExternalID:  saved.ExternalID,
 		ExternalKey: saved.ExternalKey,
 		Name:        saved.Name,
-		Content:     saved.Content,
+		Content:     &saved.Content,
 	}
 
 	cases := []struct {
@@ -417,7 +417,7 @@ func TestView(t *testing.T) {
 		// Empty channels to prevent order mismatch.
 		tc.res.Channels = []channel{}
 		view.Channels = []channel{}
-		assert.Equal(t, tc.res, view, fmt.Sprintf("%s: expected response '%s' got '%s'", tc.desc, tc.res, view))
+		assert.Equal(t, tc.res, view, fmt.Sprintf("%s: expected response '%v' got '%v'", tc.desc, tc.res, view))
 	}
 }
 
@@ -515,6 +515,103 @@ func TestUpdate(t *testing.T) {
 		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
 	}
 }
+func TestPatch(t *testing.T) {
+	auth := mocks.NewAuthClient(map[string]string{validToken: email})
+
+	ts := newThingsServer(newThingsService(auth))
+	svc := newService(auth, ts.URL)
+	bs := newBootstrapServer(svc)
+
+	c := newConfig([]bootstrap.Channel{{ID: "1"}})
+
+	saved, err := svc.Add(context.Background(), validToken, c)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	data := `{"content": {"network": {"host": "localhost"}, "app": {"content": null}}}`
+	patched := bootstrap.Content{Network: map[string]interface{}{"host": "localhost"}}
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		auth        string
+		contentType string
+		status      int
+		res         *bootstrap.Content
+	}{
+		{
+			desc:        "patch unauthorized",
+			req:         data,
+			id:          saved.MFThing,
+			auth:        invalidToken,
+			contentType: contentType,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "patch a valid config",
+			req:         data,
+			id:          saved.MFThing,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusOK,
+			res:         &patched,
+		},
+		{
+			desc:        "patch a config with wrong content type",
+			req:         data,
+			id:          saved.MFThing,
+			auth:        validToken,
+			contentType: "",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "patch a non-existing config",
+			req:         data,
+			id:          wrongID,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "patch a config with unknown content section",
+			req:         `{"content": {"unknown": {}}}`,
+			id:          saved.MFThing,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "patch a config with invalid request format",
+			req:         "}",
+			id:          saved.MFThing,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      bs.Client(),
+			method:      http.MethodPatch,
+			url:         fmt.Sprintf("%s/things/configs/%s", bs.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.res == nil {
+			continue
+		}
+		var view config
+		err = json.NewDecoder(res.Body).Decode(&view)
+		assert.Nil(t, err, fmt.Sprintf("%s: decoding response body expected to succeed: %s", tc.desc, err))
+		assert.Equal(t, tc.res, view.Content, fmt.Sprintf("%s: expected content %v got %v", tc.desc, tc.res, view.Content))
+	}
+}
+
 func TestUpdateCert(t *testing.T) {
 	auth := mocks.NewAuthClient(map[string]string{validToken: email})
 
@@ -610,6 +707,102 @@ func TestUpdateCert(t *testing.T) {
 	}
 }
 
+func TestUpdateFingerprint(t *testing.T) {
+	auth := mocks.NewAuthClient(map[string]string{validToken: email})
+
+	ts := newThingsServer(newThingsService(auth))
+	svc := newService(auth, ts.URL)
+	bs := newBootstrapServer(svc)
+
+	c := newConfig([]bootstrap.Channel{{ID: "1"}})
+
+	saved, err := svc.Add(context.Background(), validToken, c)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	data := toJSON(map[string]string{"fingerprint": strings.Repeat("ab", 32)})
+	invalid := toJSON(map[string]string{"fingerprint": "invalid"})
+
+	cases := []struct {
+		desc        string
+		req         string
+		id          string
+		auth        string
+		contentType string
+		status      int
+	}{
+		{
+			desc:        "update fingerprint unauthorized",
+			req:         data,
+			id:          saved.MFThing,
+			auth:        invalidToken,
+			contentType: contentType,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update fingerprint with an empty token",
+			req:         data,
+			id:          saved.MFThing,
+			auth:        "",
+			contentType: contentType,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "update fingerprint of a valid config",
+			req:         data,
+			id:          saved.MFThing,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusOK,
+		},
+		{
+			desc:        "update fingerprint with wrong content type",
+			req:         data,
+			id:          saved.MFThing,
+			auth:        validToken,
+			contentType: "",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			desc:        "update fingerprint of a non-existing config",
+			req:         data,
+			id:          wrongID,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusNotFound,
+		},
+		{
+			desc:        "update fingerprint with invalid fingerprint",
+			req:         invalid,
+			id:          saved.MFThing,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "update fingerprint with invalid request format",
+			req:         "}",
+			id:          saved.MFThing,
+			auth:        validToken,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      bs.Client(),
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/things/configs/fingerprints/%s", bs.URL, tc.id),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
 func TestUpdateConnections(t *testing.T) {
 	auth := mocks.NewAuthClient(map[string]string{validToken: email})
 
@@ -752,7 +945,7 @@ func TestList(t *testing.T) {
 			ExternalID:  saved.ExternalID,
 			ExternalKey: saved.ExternalKey,
 			Name:        saved.Name,
-			Content:     saved.Content,
+			Content:     &saved.Content,
 			State:       saved.State,
 		}
 		list[i] = s
@@ -966,7 +1159,7 @@ func TestList(t *testing.T) {
 
 		json.NewDecoder(res.Body).Decode(&body)
 		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
-		assert.ElementsMatch(t, tc.res.Configs, body.Configs, fmt.Sprintf("%s: expected response '%s' got '%s'", tc.desc, tc.res.Configs, body.Configs))
+		assert.ElementsMatch(t, tc.res.Configs, body.Configs, fmt.Sprintf("%s: expected response '%v' got '%v'", tc.desc, tc.res.Configs, body.Configs))
 		assert.Equal(t, tc.res.Total, body.Total, fmt.Sprintf("%s: expected response total '%d' got '%d'", tc.desc, tc.res.Total, body.Total))
 	}
 }
@@ -1054,18 +1247,18 @@ func TestBootstrap(t *testing.T) {
 	}
 
 	s := struct {
-		MFThing    string    `json:"mainflux_id"`
-		MFKey      string    `json:"mainflux_key"`
-		MFChannels []channel `json:"mainflux_channels"`
-		Content    string    `json:"content"`
-		ClientCert string    `json:"client_cert"`
-		ClientKey  string    `json:"client_key"`
-		CACert     string    `json:"ca_cert"`
+		MFThing    string             `json:"mainflux_id"`
+		MFKey      string             `json:"mainflux_key"`
+		MFChannels []channel          `json:"mainflux_channels"`
+		Content    *bootstrap.Content `json:"content"`
+		ClientCert string             `json:"client_cert"`
+		ClientKey  string             `json:"client_key"`
+		CACert     string             `json:"ca_cert"`
 	}{
 		MFThing:    saved.MFThing,
 		MFKey:      saved.MFKey,
 		MFChannels: channels,
-		Content:    saved.Content,
+		Content:    &saved.Content,
 		ClientCert: saved.ClientCert,
 		ClientKey:  saved.ClientKey,
 		CACert:     saved.CACert,
@@ -1272,14 +1465,14 @@ type channel struct {
 }
 
 type config struct {
-	MFThing     string          `json:"mainflux_id,omitempty"`
-	MFKey       string          `json:"mainflux_key,omitempty"`
-	Channels    []channel       `json:"mainflux_channels,omitempty"`
-	ExternalID  string          `json:"external_id"`
-	ExternalKey string          `json:"external_key,omitempty"`
-	Content     string          `json:"content,omitempty"`
-	Name        string          `json:"name"`
-	State       bootstrap.State `json:"state"`
+	MFThing     string             `json:"mainflux_id,omitempty"`
+	MFKey       string             `json:"mainflux_key,omitempty"`
+	Channels    []channel          `json:"mainflux_channels,omitempty"`
+	ExternalID  string             `json:"external_id"`
+	ExternalKey string             `json:"external_key,omitempty"`
+	Content     *bootstrap.Content `json:"content,omitempty"`
+	Name        string             `json:"name"`
+	State       bootstrap.State    `json:"state"`
 }
 
 type configPage struct {
@@ -65,6 +65,19 @@ func (lm *loggingMiddleware) Update(ctx context.Context, token string, cfg boots
 	return lm.svc.Update(ctx, token, cfg)
 }
 
+func (lm *loggingMiddleware) Patch(ctx context.Context, token string, cfg bootstrap.Config) (saved bootstrap.Config, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method patch for token %s and thing %s took %s to complete", token, cfg.MFThing, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Patch(ctx, token, cfg)
+}
+
 func (lm *loggingMiddleware) UpdateCert(ctx context.Context, token, thingID, clientCert, clientKey, caCert string) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method update_cert for thing with id %s took %s to complete", thingID, time.Since(begin))
@@ -78,6 +91,19 @@ func (lm *loggingMiddleware) UpdateCert(ctx context.Context, token, thingID, cli
 	return lm.svc.UpdateCert(ctx, token, thingID, clientCert, clientKey, caCert)
 }
 
+func (lm *loggingMiddleware) UpdateFingerprint(ctx context.Context, token, id, fingerprint string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_fingerprint for thing with id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateFingerprint(ctx, token, id, fingerprint)
+}
+
 func (lm *loggingMiddleware) UpdateConnections(ctx context.Context, token, id string, connections []string) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method update_connections for token %s and thing %s took %s to complete", token, id, time.Since(begin))
@@ -130,6 +156,19 @@ func (lm *loggingMiddleware) Bootstrap(ctx context.Context, externalKey, externa
 	return lm.svc.Bootstrap(ctx, externalKey, externalID, secure)
 }
 
+func (lm *loggingMiddleware) BootstrapByCert(ctx context.Context, fingerprint, externalID string) (cfg bootstrap.Config, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method bootstrap_by_cert for thing with external id %s took %s to complete", externalID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.BootstrapByCert(ctx, fingerprint, externalID)
+}
+
 func (lm *loggingMiddleware) ChangeState(ctx context.Context, token, id string, state bootstrap.State) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method change_state for token %s and thing %s took %s to complete", token, id, time.Since(begin))
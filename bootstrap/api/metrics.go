@@ -57,6 +57,15 @@ func (mm *metricsMiddleware) Update(ctx context.Context, token string, cfg boots
 	return mm.svc.Update(ctx, token, cfg)
 }
 
+func (mm *metricsMiddleware) Patch(ctx context.Context, token string, cfg bootstrap.Config) (saved bootstrap.Config, err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "patch").Add(1)
+		mm.latency.With("method", "patch").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Patch(ctx, token, cfg)
+}
+
 func (mm *metricsMiddleware) UpdateCert(ctx context.Context, token, thingKey, clientCert, clientKey, caCert string) (err error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "update_cert").Add(1)
@@ -66,6 +75,15 @@ func (mm *metricsMiddleware) UpdateCert(ctx context.Context, token, thingKey, cl
 	return mm.svc.UpdateCert(ctx, token, thingKey, clientCert, clientKey, caCert)
 }
 
+func (mm *metricsMiddleware) UpdateFingerprint(ctx context.Context, token, id, fingerprint string) (err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update_fingerprint").Add(1)
+		mm.latency.With("method", "update_fingerprint").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.UpdateFingerprint(ctx, token, id, fingerprint)
+}
+
 func (mm *metricsMiddleware) UpdateConnections(ctx context.Context, token, id string, connections []string) (err error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "update_connections").Add(1)
@@ -102,6 +120,15 @@ func (mm *metricsMiddleware) Bootstrap(ctx context.Context, externalKey, externa
 	return mm.svc.Bootstrap(ctx, externalKey, externalID, secure)
 }
 
+func (mm *metricsMiddleware) BootstrapByCert(ctx context.Context, fingerprint, externalID string) (cfg bootstrap.Config, err error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bootstrap_by_cert").Add(1)
+		mm.latency.With("method", "bootstrap_by_cert").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.BootstrapByCert(ctx, fingerprint, externalID)
+}
+
 func (mm *metricsMiddleware) ChangeState(ctx context.Context, token, id string, state bootstrap.State) (err error) {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "change_state").Add(1)
@@ -11,15 +11,15 @@ type apiReq interface {
 
 type addReq struct {
 	token       string
-	ThingID     string   `json:"thing_id"`
-	ExternalID  string   `json:"external_id"`
-	ExternalKey string   `json:"external_key"`
-	Channels    []string `json:"channels"`
-	Name        string   `json:"name"`
-	Content     string   `json:"content"`
-	ClientCert  string   `json:"client_cert"`
-	ClientKey   string   `json:"client_key"`
-	CACert      string   `json:"ca_cert"`
+	ThingID     string            `json:"thing_id"`
+	ExternalID  string            `json:"external_id"`
+	ExternalKey string            `json:"external_key"`
+	Channels    []string          `json:"channels"`
+	Name        string            `json:"name"`
+	Content     bootstrap.Content `json:"content"`
+	ClientCert  string            `json:"client_cert"`
+	ClientKey   string            `json:"client_key"`
+	CACert      string            `json:"ca_cert"`
 }
 
 func (req addReq) validate() error {
@@ -54,8 +54,8 @@ func (req entityReq) validate() error {
 type updateReq struct {
 	key     string
 	id      string
-	Name    string `json:"name"`
-	Content string `json:"content"`
+	Name    string            `json:"name"`
+	Content bootstrap.Content `json:"content"`
 }
 
 func (req updateReq) validate() error {
@@ -70,6 +70,25 @@ func (req updateReq) validate() error {
 	return nil
 }
 
+type patchReq struct {
+	key     string
+	id      string
+	Name    string            `json:"name"`
+	Content bootstrap.Content `json:"content"`
+}
+
+func (req patchReq) validate() error {
+	if req.key == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type updateCertReq struct {
 	key        string
 	thingID    string
@@ -127,13 +146,32 @@ func (req listReq) validate() error {
 	return nil
 }
 
+type updateFingerprintReq struct {
+	key         string
+	id          string
+	Fingerprint string `json:"fingerprint"`
+}
+
+func (req updateFingerprintReq) validate() error {
+	if req.key == "" {
+		return bootstrap.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return bootstrap.ErrMalformedEntity
+	}
+
+	return nil
+}
+
 type bootstrapReq struct {
-	key string
-	id  string
+	key         string
+	fingerprint string
+	id          string
 }
 
 func (req bootstrapReq) validate() error {
-	if req.key == "" {
+	if req.key == "" && req.fingerprint == "" {
 		return bootstrap.ErrUnauthorizedAccess
 	}
 
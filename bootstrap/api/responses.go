@@ -67,14 +67,15 @@ type channelRes struct {
 }
 
 type viewRes struct {
-	MFThing     string          `json:"mainflux_id,omitempty"`
-	MFKey       string          `json:"mainflux_key,omitempty"`
-	Channels    []channelRes    `json:"mainflux_channels,omitempty"`
-	ExternalID  string          `json:"external_id"`
-	ExternalKey string          `json:"external_key,omitempty"`
-	Content     string          `json:"content,omitempty"`
-	Name        string          `json:"name,omitempty"`
-	State       bootstrap.State `json:"state"`
+	MFThing         string             `json:"mainflux_id,omitempty"`
+	MFKey           string             `json:"mainflux_key,omitempty"`
+	Channels        []channelRes       `json:"mainflux_channels,omitempty"`
+	ExternalID      string             `json:"external_id"`
+	ExternalKey     string             `json:"external_key,omitempty"`
+	CertFingerprint string             `json:"cert_fingerprint,omitempty"`
+	Content         *bootstrap.Content `json:"content,omitempty"`
+	Name            string             `json:"name,omitempty"`
+	State           bootstrap.State    `json:"state"`
 }
 
 func (res viewRes) Code() int {
@@ -89,6 +90,14 @@ func (res viewRes) Empty() bool {
 	return false
 }
 
+// toContentRes omits the empty content from the response.
+func toContentRes(c bootstrap.Content) *bootstrap.Content {
+	if c.IsEmpty() {
+		return nil
+	}
+	return &c
+}
+
 type listRes struct {
 	Total   uint64    `json:"total"`
 	Offset  uint64    `json:"offset"`
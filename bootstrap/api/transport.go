@@ -9,19 +9,21 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/bootstrap"
+	"github.com/mainflux/mainflux/internal/httputil"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 const (
 	contentType  = "application/json"
+	offsetKey    = "offset"
+	limitKey     = "limit"
 	maxLimit     = 100
 	defaultLimit = 10
 )
@@ -37,6 +39,7 @@ var (
 func MakeHandler(svc bootstrap.Service, reader bootstrap.ConfigReader) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
 	}
 	r := bone.New()
 
@@ -58,12 +61,24 @@ func MakeHandler(svc bootstrap.Service, reader bootstrap.ConfigReader) http.Hand
 		encodeResponse,
 		opts...))
 
+	r.Patch("/things/configs/:id", kithttp.NewServer(
+		patchEndpoint(svc),
+		decodePatchRequest,
+		encodeResponse,
+		opts...))
+
 	r.Patch("/things/configs/certs/:id", kithttp.NewServer(
 		updateCertEndpoint(svc),
 		decodeUpdateCertRequest,
 		encodeResponse,
 		opts...))
 
+	r.Put("/things/configs/fingerprints/:id", kithttp.NewServer(
+		updateFingerprintEndpoint(svc),
+		decodeUpdateFingerprintRequest,
+		encodeResponse,
+		opts...))
+
 	r.Put("/things/configs/connections/:id", kithttp.NewServer(
 		updateConnEndpoint(svc),
 		decodeUpdateConnRequest,
@@ -133,6 +148,20 @@ func decodeUpdateRequest(_ context.Context, r *http.Request) (interface{}, error
 	return req, nil
 }
 
+func decodePatchRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := patchReq{key: r.Header.Get("Authorization")}
+	req.id = bone.GetValue(r, "id")
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(bootstrap.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeUpdateCertRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errors.ErrUnsupportedContentType
@@ -150,6 +179,23 @@ func decodeUpdateCertRequest(_ context.Context, r *http.Request) (interface{}, e
 	return req, nil
 }
 
+func decodeUpdateFingerprintRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := updateFingerprintReq{
+		key: r.Header.Get("Authorization"),
+		id:  bone.GetValue(r, "id"),
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(bootstrap.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
 func decodeUpdateConnRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errors.ErrUnsupportedContentType
@@ -170,7 +216,7 @@ func decodeListRequest(_ context.Context, r *http.Request) (interface{}, error)
 		return nil, errors.ErrInvalidQueryParams
 	}
 
-	offset, limit, err := parsePagePrams(q)
+	offset, limit, err := parsePageParams(r)
 	if err != nil {
 		return nil, err
 	}
@@ -193,6 +239,10 @@ func decodeBootstrapRequest(_ context.Context, r *http.Request) (interface{}, er
 		key: r.Header.Get("Authorization"),
 	}
 
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		req.fingerprint = bootstrap.Fingerprint(r.TLS.PeerCertificates[0])
+	}
+
 	return req, nil
 }
 
@@ -284,28 +334,13 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	}
 }
 
-func parseUint(s string) (uint64, error) {
-	if s == "" {
-		return 0, nil
-	}
-
-	ret, err := strconv.ParseUint(s, 10, 64)
-	if err != nil {
-		return 0, errors.ErrInvalidQueryParams
-	}
-
-	return ret, nil
-}
-
-func parsePagePrams(q url.Values) (uint64, uint64, error) {
-	offset, err := parseUint(q.Get("offset"))
-	q.Del("offset")
+func parsePageParams(r *http.Request) (uint64, uint64, error) {
+	offset, err := httputil.ReadUintQuery(r, offsetKey, 0)
 	if err != nil {
 		return 0, 0, errors.Wrap(errInvalidOffsetParam, err)
 	}
 
-	limit, err := parseUint(q.Get("limit"))
-	q.Del("limit")
+	limit, err := httputil.ReadUintQuery(r, limitKey, 0)
 	if err != nil {
 		return 0, 0, errors.Wrap(errInvalidLimitParam, err)
 	}
@@ -8,19 +8,22 @@ package bootstrap
 // MFThing represents corresponding Mainflux Thing ID.
 // MFKey is key of corresponding Mainflux Thing.
 // MFChannels is a list of Mainflux Channels corresponding Mainflux Thing connects to.
+// CertFingerprint is the fingerprint of the client certificate the Thing can
+// bootstrap with in place of the external key.
 type Config struct {
-	MFThing     string
-	Owner       string
-	Name        string
-	ClientCert  string
-	ClientKey   string
-	CACert      string
-	MFKey       string
-	MFChannels  []Channel
-	ExternalID  string
-	ExternalKey string
-	Content     string
-	State       State
+	MFThing         string
+	Owner           string
+	Name            string
+	ClientCert      string
+	ClientKey       string
+	CACert          string
+	MFKey           string
+	MFChannels      []Channel
+	ExternalID      string
+	ExternalKey     string
+	CertFingerprint string
+	Content         Content
+	State           State
 }
 
 // Channel represents Mainflux channel corresponding Mainflux Thing is connected to.
@@ -70,6 +73,10 @@ type ConfigRepository interface {
 	// A non-nil error is returned to indicate operation failure.
 	UpdateCert(owner, thingID, clientCert, clientKey, caCert string) error
 
+	// UpdateFingerprint updates the client certificate fingerprint of an
+	// existing Config. A non-nil error is returned to indicate operation failure.
+	UpdateFingerprint(owner, id, fingerprint string) error
+
 	// UpdateConnections updates a list of Channels the Config is connected to
 	// adding new Channels if needed.
 	UpdateConnections(owner, id string, channels []Channel, connections []string) error
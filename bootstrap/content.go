@@ -0,0 +1,142 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// The keys of the Content sections.
+const (
+	NetworkSection     = "network"
+	CredentialsSection = "credentials"
+	AppSection         = "app"
+
+	// legacyKey is the key of the app section holding the legacy content
+	// which isn't the JSON object.
+	legacyKey = "content"
+)
+
+// Content represents the configuration the Thing receives when it
+// bootstraps, split into the network, the credentials and the application
+// sections. The sections are free-form JSON objects.
+type Content struct {
+	Network     map[string]interface{} `json:"network,omitempty"`
+	Credentials map[string]interface{} `json:"credentials,omitempty"`
+	App         map[string]interface{} `json:"app,omitempty"`
+}
+
+// IsEmpty returns true if none of the sections has any values.
+func (c Content) IsEmpty() bool {
+	return len(c.Network) == 0 && len(c.Credentials) == 0 && len(c.App) == 0
+}
+
+// Merge returns the content with the patch deep-merged into it, following
+// the JSON merge patch semantics: the nested objects are merged, the null
+// values remove the keys, and the other values replace the existing ones.
+// The sections missing from the patch are left intact.
+func (c Content) Merge(patch Content) Content {
+	return Content{
+		Network:     mergeSection(c.Network, patch.Network),
+		Credentials: mergeSection(c.Credentials, patch.Credentials),
+		App:         mergeSection(c.App, patch.App),
+	}
+}
+
+// UnmarshalJSON decodes the content object, rejecting the unknown sections.
+// The JSON string is decoded as the legacy content.
+func (c *Content) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		*c = Content{}
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*c = LegacyContent(s)
+		return nil
+	}
+
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+
+	var content Content
+	for key, raw := range sections {
+		var section map[string]interface{}
+		if err := json.Unmarshal(raw, &section); err != nil {
+			return fmt.Errorf("content section '%s' is not an object", key)
+		}
+		switch key {
+		case NetworkSection:
+			content.Network = section
+		case CredentialsSection:
+			content.Credentials = section
+		case AppSection:
+			content.App = section
+		default:
+			return fmt.Errorf("unknown content section '%s'", key)
+		}
+	}
+	*c = content
+
+	return nil
+}
+
+// LegacyContent converts the opaque string content, used before the content
+// was split into the sections. The JSON object made of the sections only is
+// used as is, while any other JSON object becomes the app section. Any other
+// value is kept in the app section under the "content" key.
+func LegacyContent(s string) Content {
+	if s == "" {
+		return Content{}
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return Content{App: map[string]interface{}{legacyKey: s}}
+	}
+
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return Content{App: map[string]interface{}{legacyKey: v}}
+	}
+
+	var c Content
+	if err := json.Unmarshal([]byte(s), &c); err != nil {
+		return Content{App: obj}
+	}
+
+	return c
+}
+
+func mergeSection(dst, patch map[string]interface{}) map[string]interface{} {
+	if patch == nil {
+		return dst
+	}
+
+	res := make(map[string]interface{}, len(dst)+len(patch))
+	for k, v := range dst {
+		res[k] = v
+	}
+	for k, v := range patch {
+		switch pv := v.(type) {
+		case nil:
+			delete(res, k)
+		case map[string]interface{}:
+			dv, _ := res[k].(map[string]interface{})
+			res[k] = mergeSection(dv, pv)
+		default:
+			res[k] = v
+		}
+	}
+
+	return res
+}
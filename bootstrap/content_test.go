@@ -0,0 +1,169 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/bootstrap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentMerge(t *testing.T) {
+	content := bootstrap.Content{
+		Network: map[string]interface{}{
+			"host": "localhost",
+			"mqtt": map[string]interface{}{"port": 1883.0, "qos": 1.0},
+		},
+		App: map[string]interface{}{"log": "debug"},
+	}
+
+	cases := []struct {
+		desc  string
+		patch bootstrap.Content
+		res   bootstrap.Content
+	}{
+		{
+			desc:  "merge an empty patch",
+			patch: bootstrap.Content{},
+			res:   content,
+		},
+		{
+			desc: "merge a nested object",
+			patch: bootstrap.Content{
+				Network: map[string]interface{}{"mqtt": map[string]interface{}{"port": 8883.0}},
+			},
+			res: bootstrap.Content{
+				Network: map[string]interface{}{
+					"host": "localhost",
+					"mqtt": map[string]interface{}{"port": 8883.0, "qos": 1.0},
+				},
+				App: map[string]interface{}{"log": "debug"},
+			},
+		},
+		{
+			desc: "remove a key with null",
+			patch: bootstrap.Content{
+				Network: map[string]interface{}{"host": nil},
+				App:     map[string]interface{}{"log": nil},
+			},
+			res: bootstrap.Content{
+				Network: map[string]interface{}{"mqtt": map[string]interface{}{"port": 1883.0, "qos": 1.0}},
+				App:     map[string]interface{}{},
+			},
+		},
+		{
+			desc: "replace an object with a value",
+			patch: bootstrap.Content{
+				Network:     map[string]interface{}{"mqtt": "disabled"},
+				Credentials: map[string]interface{}{"user": "user"},
+			},
+			res: bootstrap.Content{
+				Network:     map[string]interface{}{"host": "localhost", "mqtt": "disabled"},
+				Credentials: map[string]interface{}{"user": "user"},
+				App:         map[string]interface{}{"log": "debug"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		res := content.Merge(tc.patch)
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, res))
+	}
+	assert.Equal(t, "localhost", content.Network["host"], "merge expected to leave the original content intact")
+}
+
+func TestContentUnmarshalJSON(t *testing.T) {
+	cases := []struct {
+		desc    string
+		data    string
+		content bootstrap.Content
+		err     bool
+	}{
+		{
+			desc: "unmarshal sections",
+			data: `{"network": {"host": "localhost"}, "credentials": {"user": "user"}, "app": {"log": "debug"}}`,
+			content: bootstrap.Content{
+				Network:     map[string]interface{}{"host": "localhost"},
+				Credentials: map[string]interface{}{"user": "user"},
+				App:         map[string]interface{}{"log": "debug"},
+			},
+		},
+		{
+			desc:    "unmarshal null",
+			data:    `null`,
+			content: bootstrap.Content{},
+		},
+		{
+			desc:    "unmarshal legacy string",
+			data:    `"config"`,
+			content: bootstrap.Content{App: map[string]interface{}{"content": "config"}},
+		},
+		{
+			desc:    "unmarshal legacy JSON string",
+			data:    `"{\"log\": \"debug\"}"`,
+			content: bootstrap.Content{App: map[string]interface{}{"log": "debug"}},
+		},
+		{
+			desc: "unmarshal unknown section",
+			data: `{"unknown": {}}`,
+			err:  true,
+		},
+		{
+			desc: "unmarshal non-object section",
+			data: `{"network": "localhost"}`,
+			err:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		var content bootstrap.Content
+		err := json.Unmarshal([]byte(tc.data), &content)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, tc.content, content, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.content, content))
+		}
+	}
+}
+
+func TestLegacyContent(t *testing.T) {
+	cases := []struct {
+		desc    string
+		legacy  string
+		content bootstrap.Content
+	}{
+		{
+			desc:    "convert empty content",
+			legacy:  "",
+			content: bootstrap.Content{},
+		},
+		{
+			desc:    "convert plain text",
+			legacy:  "config",
+			content: bootstrap.Content{App: map[string]interface{}{"content": "config"}},
+		},
+		{
+			desc:    "convert JSON value",
+			legacy:  "[1, 2]",
+			content: bootstrap.Content{App: map[string]interface{}{"content": []interface{}{1.0, 2.0}}},
+		},
+		{
+			desc:    "convert JSON object",
+			legacy:  `{"log": "debug", "network": "localhost"}`,
+			content: bootstrap.Content{App: map[string]interface{}{"log": "debug", "network": "localhost"}},
+		},
+		{
+			desc:    "convert JSON sections",
+			legacy:  `{"network": {"host": "localhost"}}`,
+			content: bootstrap.Content{Network: map[string]interface{}{"host": "localhost"}},
+		},
+	}
+
+	for _, tc := range cases {
+		content := bootstrap.LegacyContent(tc.legacy)
+		assert.Equal(t, tc.content, content, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.content, content))
+	}
+}
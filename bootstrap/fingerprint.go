@@ -0,0 +1,30 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package bootstrap
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"strings"
+)
+
+// Fingerprint returns the fingerprint of the certificate, the hex encoded
+// SHA-256 digest of its DER encoding.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeFingerprint converts the SHA-256 fingerprint to the format
+// returned by Fingerprint, accepting both the upper and lower case digits
+// optionally separated by colons, as printed by OpenSSL.
+func NormalizeFingerprint(fingerprint string) (string, error) {
+	fp := strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+	b, err := hex.DecodeString(fp)
+	if err != nil || len(b) != sha256.Size {
+		return "", ErrMalformedEntity
+	}
+	return fp, nil
+}
@@ -177,6 +177,29 @@ func (crm *configRepositoryMock) UpdateCert(owner, thingID, clientCert, clientKe
 	return nil
 }
 
+func (crm *configRepositoryMock) UpdateFingerprint(owner, id, fingerprint string) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	cfg, ok := crm.configs[id]
+	if !ok || cfg.Owner != owner {
+		return bootstrap.ErrNotFound
+	}
+
+	if fingerprint != "" {
+		for _, c := range crm.configs {
+			if c.MFThing != id && c.CertFingerprint == fingerprint {
+				return bootstrap.ErrConflict
+			}
+		}
+	}
+
+	cfg.CertFingerprint = fingerprint
+	crm.configs[id] = cfg
+
+	return nil
+}
+
 func (crm *configRepositoryMock) UpdateConnections(token, id string, channels []bootstrap.Channel, connections []string) error {
 	crm.mu.Lock()
 	defer crm.mu.Unlock()
@@ -70,7 +70,7 @@ func (svc *mainfluxThings) ViewThing(_ context.Context, owner, id string) (thing
 	return things.Thing{}, things.ErrNotFound
 }
 
-func (svc *mainfluxThings) Connect(_ context.Context, owner string, chIDs, thIDs []string) error {
+func (svc *mainfluxThings) Connect(_ context.Context, owner string, chIDs, thIDs []string, _ things.ConnectionMetadata) error {
 	svc.mu.Lock()
 	defer svc.mu.Unlock()
 
@@ -212,11 +212,11 @@ func (svc *mainfluxThings) RemoveChannel(context.Context, string, string) error
 	panic("not implemented")
 }
 
-func (svc *mainfluxThings) CanAccessByKey(context.Context, string, string) (string, error) {
+func (svc *mainfluxThings) CanAccessByKey(context.Context, string, string, string) (string, error) {
 	panic("not implemented")
 }
 
-func (svc *mainfluxThings) CanAccessByID(context.Context, string, string) error {
+func (svc *mainfluxThings) CanAccessByID(context.Context, string, string, string) error {
 	panic("not implemented")
 }
 
@@ -245,3 +245,19 @@ func findIndex(list []string, val string) int {
 func (svc *mainfluxThings) ListMembers(ctx context.Context, token, groupID string, pm things.PageMetadata) (things.Page, error) {
 	panic("not implemented")
 }
+
+func (svc *mainfluxThings) CreateWebhook(context.Context, string, things.Webhook) (things.Webhook, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ListWebhooks(context.Context, string, string) ([]things.Webhook, error) {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) RemoveWebhook(context.Context, string, string, string) error {
+	panic("not implemented")
+}
+
+func (svc *mainfluxThings) ListDeliveries(context.Context, string, string, string, things.PageMetadata) (things.DeliveriesPage, error) {
+	panic("not implemented")
+}
@@ -63,3 +63,23 @@ func (svc serviceMock) Members(ctx context.Context, req *mainflux.MembersReq, _
 func (svc serviceMock) Assign(ctx context.Context, req *mainflux.Assignment, _ ...grpc.CallOption) (r *empty.Empty, err error) {
 	panic("not implemented")
 }
+
+func (svc serviceMock) ReserveQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	panic("not implemented")
+}
+
+func (svc serviceMock) ReleaseQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	panic("not implemented")
+}
+
+func (svc serviceMock) RevokeKeys(ctx context.Context, req *mainflux.RevokeKeysReq, _ ...grpc.CallOption) (*mainflux.RevokeKeysRes, error) {
+	panic("not implemented")
+}
+
+func (svc serviceMock) ApplyPolicyTemplate(ctx context.Context, req *mainflux.PolicyTemplateReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (svc serviceMock) RemoveLinks(ctx context.Context, req *mainflux.RemoveLinksReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
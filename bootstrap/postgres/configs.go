@@ -32,6 +32,8 @@ var (
 	errSaveDB           = errors.New("failed to save bootstrap configuration to database")
 	errMarshalChannel   = errors.New("failed to marshal channel into json")
 	errUnmarshalChannel = errors.New("failed to unmarshal json to channel")
+	errMarshalContent   = errors.New("failed to marshal content into json")
+	errUnmarshalContent = errors.New("failed to unmarshal json to content")
 	errSaveChannels     = errors.New("failed to insert channels to database")
 	errSaveConnections  = errors.New("failed to insert connections to database")
 	errRetrieve         = errors.New("failed to retreive bootstrap configuration from database")
@@ -64,7 +66,11 @@ func (cr configRepository) Save(cfg bootstrap.Config, chsConnIDs []string) (stri
 		return "", errors.Wrap(errSaveDB, err)
 	}
 
-	dbcfg := toDBConfig(cfg)
+	dbcfg, err := toDBConfig(cfg)
+	if err != nil {
+		cr.rollback("Failed to serialize a Config", tx)
+		return "", errors.Wrap(errSaveDB, err)
+	}
 
 	if _, err := tx.NamedExec(q, dbcfg); err != nil {
 		e := err
@@ -95,7 +101,7 @@ func (cr configRepository) Save(cfg bootstrap.Config, chsConnIDs []string) (stri
 }
 
 func (cr configRepository) RetrieveByID(owner, id string) (bootstrap.Config, error) {
-	q := `SELECT mainflux_thing, mainflux_key, external_id, external_key, name, content, state
+	q := `SELECT mainflux_thing, mainflux_key, external_id, external_key, cert_fingerprint, name, content, state
 		  FROM configs
 		  WHERE mainflux_thing = $1 AND owner = $2`
 
@@ -141,7 +147,10 @@ func (cr configRepository) RetrieveByID(owner, id string) (bootstrap.Config, err
 		chans = append(chans, ch)
 	}
 
-	cfg := toConfig(dbcfg)
+	cfg, err := toConfig(dbcfg)
+	if err != nil {
+		return bootstrap.Config{}, errors.Wrap(errRetrieve, err)
+	}
 	cfg.MFChannels = chans
 
 	return cfg, nil
@@ -173,7 +182,10 @@ func (cr configRepository) RetrieveAll(owner string, filter bootstrap.Filter, of
 		}
 
 		c.Name = name.String
-		c.Content = content.String
+		if c.Content, err = toContent(content); err != nil {
+			cr.log.Error(fmt.Sprintf("Failed to deserialize config content due to %s", err))
+			return bootstrap.ConfigsPage{}
+		}
 		configs = append(configs, c)
 	}
 
@@ -194,7 +206,7 @@ func (cr configRepository) RetrieveAll(owner string, filter bootstrap.Filter, of
 }
 
 func (cr configRepository) RetrieveByExternalID(externalID string) (bootstrap.Config, error) {
-	q := `SELECT mainflux_thing, mainflux_key, external_key, owner, name, client_cert, client_key, ca_cert, content, state
+	q := `SELECT mainflux_thing, mainflux_key, external_key, cert_fingerprint, owner, name, client_cert, client_key, ca_cert, content, state
 		  FROM configs
 		  WHERE external_id = $1`
 	dbcfg := dbConfig{
@@ -238,7 +250,10 @@ func (cr configRepository) RetrieveByExternalID(externalID string) (bootstrap.Co
 		channels = append(channels, ch)
 	}
 
-	cfg := toConfig(dbcfg)
+	cfg, err := toConfig(dbcfg)
+	if err != nil {
+		return bootstrap.Config{}, errors.Wrap(errRetrieve, err)
+	}
 	cfg.MFChannels = channels
 
 	return cfg, nil
@@ -247,7 +262,10 @@ func (cr configRepository) RetrieveByExternalID(externalID string) (bootstrap.Co
 func (cr configRepository) Update(cfg bootstrap.Config) error {
 	q := `UPDATE configs SET name = $1, content = $2 WHERE mainflux_thing = $3 AND owner = $4`
 
-	content := nullString(cfg.Content)
+	content, err := toDBContent(cfg.Content)
+	if err != nil {
+		return errors.Wrap(errUpdate, err)
+	}
 	name := nullString(cfg.Name)
 
 	res, err := cr.db.Exec(q, name, content, cfg.MFThing, cfg.Owner)
@@ -287,6 +305,29 @@ func (cr configRepository) UpdateCert(owner, thingID, clientCert, clientKey, caC
 	return nil
 }
 
+func (cr configRepository) UpdateFingerprint(owner, id, fingerprint string) error {
+	q := `UPDATE configs SET cert_fingerprint = $1 WHERE mainflux_thing = $2 AND owner = $3`
+
+	res, err := cr.db.Exec(q, nullString(fingerprint), id, owner)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == duplicateErr {
+			return errors.Wrap(errUpdate, bootstrap.ErrConflict)
+		}
+		return errors.Wrap(errUpdate, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdate, err)
+	}
+
+	if cnt == 0 {
+		return bootstrap.ErrNotFound
+	}
+
+	return nil
+}
+
 func (cr configRepository) UpdateConnections(owner, id string, channels []bootstrap.Channel, connections []string) error {
 	tx, err := cr.db.Beginx()
 	if err != nil {
@@ -559,20 +600,26 @@ func nullString(s string) sql.NullString {
 }
 
 type dbConfig struct {
-	MFThing     string          `db:"mainflux_thing"`
-	Owner       string          `db:"owner"`
-	Name        sql.NullString  `db:"name"`
-	ClientCert  sql.NullString  `db:"client_cert"`
-	ClientKey   sql.NullString  `db:"client_key"`
-	CaCert      sql.NullString  `db:"ca_cert"`
-	MFKey       string          `db:"mainflux_key"`
-	ExternalID  string          `db:"external_id"`
-	ExternalKey string          `db:"external_key"`
-	Content     sql.NullString  `db:"content"`
-	State       bootstrap.State `db:"state"`
-}
-
-func toDBConfig(cfg bootstrap.Config) dbConfig {
+	MFThing         string          `db:"mainflux_thing"`
+	Owner           string          `db:"owner"`
+	Name            sql.NullString  `db:"name"`
+	ClientCert      sql.NullString  `db:"client_cert"`
+	ClientKey       sql.NullString  `db:"client_key"`
+	CaCert          sql.NullString  `db:"ca_cert"`
+	MFKey           string          `db:"mainflux_key"`
+	ExternalID      string          `db:"external_id"`
+	ExternalKey     string          `db:"external_key"`
+	CertFingerprint sql.NullString  `db:"cert_fingerprint"`
+	Content         sql.NullString  `db:"content"`
+	State           bootstrap.State `db:"state"`
+}
+
+func toDBConfig(cfg bootstrap.Config) (dbConfig, error) {
+	content, err := toDBContent(cfg.Content)
+	if err != nil {
+		return dbConfig{}, err
+	}
+
 	return dbConfig{
 		MFThing:     cfg.MFThing,
 		Owner:       cfg.Owner,
@@ -583,12 +630,12 @@ func toDBConfig(cfg bootstrap.Config) dbConfig {
 		MFKey:       cfg.MFKey,
 		ExternalID:  cfg.ExternalID,
 		ExternalKey: cfg.ExternalKey,
-		Content:     nullString(cfg.Content),
+		Content:     content,
 		State:       cfg.State,
-	}
+	}, nil
 }
 
-func toConfig(dbcfg dbConfig) bootstrap.Config {
+func toConfig(dbcfg dbConfig) (bootstrap.Config, error) {
 	cfg := bootstrap.Config{
 		MFThing:     dbcfg.MFThing,
 		Owner:       dbcfg.Owner,
@@ -602,10 +649,6 @@ func toConfig(dbcfg dbConfig) bootstrap.Config {
 		cfg.Name = dbcfg.Name.String
 	}
 
-	if dbcfg.Content.Valid {
-		cfg.Content = dbcfg.Content.String
-	}
-
 	if dbcfg.ClientCert.Valid {
 		cfg.ClientCert = dbcfg.ClientCert.String
 	}
@@ -617,7 +660,45 @@ func toConfig(dbcfg dbConfig) bootstrap.Config {
 	if dbcfg.CaCert.Valid {
 		cfg.CACert = dbcfg.CaCert.String
 	}
-	return cfg
+
+	if dbcfg.CertFingerprint.Valid {
+		cfg.CertFingerprint = dbcfg.CertFingerprint.String
+	}
+
+	content, err := toContent(dbcfg.Content)
+	if err != nil {
+		return bootstrap.Config{}, err
+	}
+	cfg.Content = content
+
+	return cfg, nil
+}
+
+// toDBContent serializes the content, storing the empty content as NULL.
+func toDBContent(c bootstrap.Content) (sql.NullString, error) {
+	if c.IsEmpty() {
+		return sql.NullString{}, nil
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return sql.NullString{}, errors.Wrap(errMarshalContent, err)
+	}
+
+	return nullString(string(data)), nil
+}
+
+func toContent(content sql.NullString) (bootstrap.Content, error) {
+	var c bootstrap.Content
+	if !content.Valid {
+		return c, nil
+	}
+
+	if err := json.Unmarshal([]byte(content.String), &c); err != nil {
+		return bootstrap.Content{}, errors.Wrap(errUnmarshalContent, err)
+	}
+
+	return c, nil
 }
 
 type dbChannel struct {
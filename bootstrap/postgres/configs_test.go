@@ -29,7 +29,7 @@ var (
 			{ID: "1", Name: "name 1", Metadata: map[string]interface{}{"meta": 1.0}},
 			{ID: "2", Name: "name 2", Metadata: map[string]interface{}{"meta": 2.0}},
 		},
-		Content: "content",
+		Content: bootstrap.Content{App: map[string]interface{}{"content": "content"}},
 		State:   bootstrap.Inactive,
 	}
 
@@ -286,7 +286,7 @@ func TestUpdate(t *testing.T) {
 	_, err = repo.Save(c, channels)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
 
-	c.Content = "new content"
+	c.Content = bootstrap.Content{App: map[string]interface{}{"content": "new content"}}
 	c.Name = "new name"
 
 	wrongOwner := c
@@ -331,7 +331,7 @@ func TestUpdateCert(t *testing.T) {
 	_, err = repo.Save(c, channels)
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
 
-	c.Content = "new content"
+	c.Content = bootstrap.Content{App: map[string]interface{}{"content": "new content"}}
 	c.Name = "new name"
 
 	wrongOwner := c
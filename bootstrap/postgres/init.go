@@ -100,6 +100,51 @@ func migrateDB(db *sqlx.DB) error {
 					"CREATE TABLE IF NOT EXISTS unknown_configs",
 				},
 			},
+			{
+				Id: "configs_3",
+				Up: []string{
+					"ALTER TABLE configs ADD COLUMN IF NOT EXISTS cert_fingerprint TEXT UNIQUE",
+				},
+				Down: []string{
+					"ALTER TABLE configs DROP COLUMN IF EXISTS cert_fingerprint",
+				},
+			},
+			{
+				// The legacy string content is converted to the sections the
+				// same way as bootstrap.LegacyContent does.
+				Id: "configs_4",
+				Up: []string{
+					`CREATE OR REPLACE FUNCTION legacy_content(content TEXT) RETURNS JSONB AS $$
+					DECLARE
+						parsed JSONB;
+					BEGIN
+						IF content IS NULL OR content = '' THEN
+							RETURN NULL;
+						END IF;
+						BEGIN
+							parsed := content::JSONB;
+						EXCEPTION WHEN others THEN
+							RETURN jsonb_build_object('app', jsonb_build_object('content', content));
+						END;
+						IF jsonb_typeof(parsed) <> 'object' THEN
+							RETURN jsonb_build_object('app', jsonb_build_object('content', parsed));
+						END IF;
+						IF (parsed - 'network' - 'credentials' - 'app') = '{}'::JSONB
+							AND COALESCE(jsonb_typeof(parsed->'network'), 'object') IN ('object', 'null')
+							AND COALESCE(jsonb_typeof(parsed->'credentials'), 'object') IN ('object', 'null')
+							AND COALESCE(jsonb_typeof(parsed->'app'), 'object') IN ('object', 'null') THEN
+							RETURN jsonb_strip_nulls(parsed);
+						END IF;
+						RETURN jsonb_build_object('app', parsed);
+					END;
+					$$ LANGUAGE plpgsql`,
+					"ALTER TABLE configs ALTER COLUMN content TYPE JSONB USING legacy_content(content)",
+					"DROP FUNCTION legacy_content(TEXT)",
+				},
+				Down: []string{
+					"ALTER TABLE configs ALTER COLUMN content TYPE TEXT USING content::TEXT",
+				},
+			},
 		},
 	}
 
@@ -24,13 +24,13 @@ type readChan struct {
 }
 
 type readResp struct {
-	MFThing    string     `json:"mainflux_id"`
-	MFKey      string     `json:"mainflux_key"`
-	MFChannels []readChan `json:"mainflux_channels"`
-	Content    string     `json:"content,omitempty"`
-	ClientCert string     `json:"client_cert,omitempty"`
-	ClientKey  string     `json:"client_key,omitempty"`
-	CACert     string     `json:"ca_cert,omitempty"`
+	MFThing    string             `json:"mainflux_id"`
+	MFKey      string             `json:"mainflux_key"`
+	MFChannels []readChan         `json:"mainflux_channels"`
+	Content    *bootstrap.Content `json:"content,omitempty"`
+	ClientCert string             `json:"client_cert,omitempty"`
+	ClientKey  string             `json:"client_key,omitempty"`
+	CACert     string             `json:"ca_cert,omitempty"`
 }
 
 func dec(in []byte) ([]byte, error) {
@@ -62,7 +62,7 @@ func TestReadConfig(t *testing.T) {
 				Metadata: map[string]interface{}{"key": "value}"},
 			},
 		},
-		Content: "content",
+		Content: bootstrap.Content{Network: map[string]interface{}{"host": "localhost"}},
 	}
 	ret := readResp{
 		MFThing: "mf_id",
@@ -74,7 +74,7 @@ func TestReadConfig(t *testing.T) {
 				Metadata: map[string]interface{}{"key": "value}"},
 			},
 		},
-		Content:    "content",
+		Content:    &bootstrap.Content{Network: map[string]interface{}{"host": "localhost"}},
 		ClientCert: "client_cert",
 		ClientKey:  "client_key",
 		CACert:     "ca_cert",
@@ -4,6 +4,7 @@
 package producer
 
 import (
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -75,6 +76,19 @@ func (uce updateConfigEvent) encode() map[string]interface{} {
 	}
 }
 
+// encodeContent serializes the content into the JSON string, leaving the
+// empty content out of the event.
+func encodeContent(c bootstrap.Content) string {
+	if c.IsEmpty() {
+		return ""
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
 type removeConfigEvent struct {
 	mfThing   string
 	timestamp time.Time
@@ -49,7 +49,7 @@ func (es eventStore) Add(ctx context.Context, token string, cfg bootstrap.Config
 		name:       saved.Name,
 		mfChannels: channels,
 		externalID: saved.ExternalID,
-		content:    saved.Content,
+		content:    encodeContent(saved.Content),
 		timestamp:  time.Now(),
 	}
 
@@ -70,7 +70,7 @@ func (es eventStore) Update(ctx context.Context, token string, cfg bootstrap.Con
 	ev := updateConfigEvent{
 		mfThing:   cfg.MFThing,
 		name:      cfg.Name,
-		content:   cfg.Content,
+		content:   encodeContent(cfg.Content),
 		timestamp: time.Now(),
 	}
 
@@ -79,10 +79,32 @@ func (es eventStore) Update(ctx context.Context, token string, cfg bootstrap.Con
 	return nil
 }
 
+func (es eventStore) Patch(ctx context.Context, token string, cfg bootstrap.Config) (bootstrap.Config, error) {
+	saved, err := es.svc.Patch(ctx, token, cfg)
+	if err != nil {
+		return saved, err
+	}
+
+	ev := updateConfigEvent{
+		mfThing:   saved.MFThing,
+		name:      saved.Name,
+		content:   encodeContent(saved.Content),
+		timestamp: time.Now(),
+	}
+
+	es.add(ctx, ev)
+
+	return saved, nil
+}
+
 func (es eventStore) UpdateCert(ctx context.Context, token, thingKey, clientCert, clientKey, caCert string) error {
 	return es.svc.UpdateCert(ctx, token, thingKey, clientCert, clientKey, caCert)
 }
 
+func (es eventStore) UpdateFingerprint(ctx context.Context, token, id, fingerprint string) error {
+	return es.svc.UpdateFingerprint(ctx, token, id, fingerprint)
+}
+
 func (es eventStore) UpdateConnections(ctx context.Context, token, id string, connections []string) error {
 	if err := es.svc.UpdateConnections(ctx, token, id, connections); err != nil {
 		return err
@@ -136,6 +158,24 @@ func (es eventStore) Bootstrap(ctx context.Context, externalKey, externalID stri
 	return cfg, err
 }
 
+func (es eventStore) BootstrapByCert(ctx context.Context, fingerprint, externalID string) (bootstrap.Config, error) {
+	cfg, err := es.svc.BootstrapByCert(ctx, fingerprint, externalID)
+
+	ev := bootstrapEvent{
+		externalID: externalID,
+		timestamp:  time.Now(),
+		success:    true,
+	}
+
+	if err != nil {
+		ev.success = false
+	}
+
+	es.add(ctx, ev)
+
+	return cfg, err
+}
+
 func (es eventStore) ChangeState(ctx context.Context, token, id string, state bootstrap.State) error {
 	if err := es.svc.ChangeState(ctx, token, id, state); err != nil {
 		return err
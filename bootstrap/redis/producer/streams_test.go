@@ -58,7 +58,7 @@ var (
 		ExternalID:  "external_id",
 		ExternalKey: "external_key",
 		MFChannels:  []bootstrap.Channel{channel},
-		Content:     "config",
+		Content:     bootstrap.Content{App: map[string]interface{}{"content": "config"}},
 	}
 )
 
@@ -87,7 +87,7 @@ func newThingsService(auth mainflux.AuthServiceClient) things.Service {
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, nil)
 	return httptest.NewServer(mux)
 }
 func TestAdd(t *testing.T) {
@@ -124,7 +124,7 @@ func TestAdd(t *testing.T) {
 				"name":        config.Name,
 				"channels":    strings.Join(channels, ", "),
 				"external_id": config.ExternalID,
-				"content":     config.Content,
+				"content":     `{"app":{"content":"config"}}`,
 				"timestamp":   time.Now().Unix(),
 				"operation":   configCreate,
 			},
@@ -195,7 +195,7 @@ func TestUpdate(t *testing.T) {
 	redisClient.FlushAll(context.Background()).Err()
 
 	modified := saved
-	modified.Content = "new-config"
+	modified.Content = bootstrap.Content{App: map[string]interface{}{"content": "new-config"}}
 	modified.Name = "new name"
 
 	nonExisting := config
@@ -216,7 +216,7 @@ func TestUpdate(t *testing.T) {
 			event: map[string]interface{}{
 				"thing_id":  modified.MFThing,
 				"name":      modified.Name,
-				"content":   modified.Content,
+				"content":   `{"app":{"content":"new-config"}}`,
 				"timestamp": time.Now().Unix(),
 				"operation": configUpdate,
 			},
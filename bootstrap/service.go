@@ -39,6 +39,9 @@ var (
 	// ErrSecureBootstrap indicates error in getting bootstrap configuration for given encrypted external key
 	ErrSecureBootstrap = errors.New("failed to get bootstrap configuration for given encrypted external key")
 
+	// ErrCertNotFound indicates a non-existent bootstrap configuration for given client certificate.
+	ErrCertNotFound = errors.New("failed to get bootstrap configuration for given client certificate")
+
 	// ErrBootstrap indicates error in getting bootstrap configuration.
 	ErrBootstrap = errors.New("failed to read bootstrap configuration")
 
@@ -55,6 +58,7 @@ var (
 	errCheckChannels      = errors.New("failed to check if channels exists")
 	errConnectionChannels = errors.New("failed to check channels connections")
 	errUpdateCert         = errors.New("failed to update cert")
+	errUpdateFingerprint  = errors.New("failed to update cert fingerprint")
 )
 
 var _ Service = (*bootstrapService)(nil)
@@ -71,10 +75,19 @@ type Service interface {
 	// Update updates editable fields of the provided Config.
 	Update(ctx context.Context, token string, cfg Config) error
 
+	// Patch renames the Config if the name is given, and deep-merges the
+	// given Content into the Config content. The patched Config is returned.
+	Patch(ctx context.Context, token string, cfg Config) (Config, error)
+
 	// UpdateCert updates an existing Config certificate and token.
 	// A non-nil error is returned to indicate operation failure.
 	UpdateCert(ctx context.Context, token, thingID, clientCert, clientKey, caCert string) error
 
+	// UpdateFingerprint maps the client certificate fingerprint to the Config
+	// with given ID, so the Thing can bootstrap using its certificate instead
+	// of the external key. An empty fingerprint removes the mapping.
+	UpdateFingerprint(ctx context.Context, token, id, fingerprint string) error
+
 	// UpdateConnections updates list of Channels related to given Config.
 	UpdateConnections(ctx context.Context, token, id string, connections []string) error
 
@@ -88,6 +101,10 @@ type Service interface {
 	// Bootstrap returns Config to the Thing with provided external ID using external key.
 	Bootstrap(ctx context.Context, externalKey, externalID string, secure bool) (Config, error)
 
+	// BootstrapByCert returns Config to the Thing with provided external ID
+	// using the fingerprint of the client certificate presented by the Thing.
+	BootstrapByCert(ctx context.Context, fingerprint, externalID string) (Config, error)
+
 	// ChangeState changes state of the Thing with given ID and owner.
 	ChangeState(ctx context.Context, token, id string, state State) error
 
@@ -200,6 +217,29 @@ func (bs bootstrapService) Update(ctx context.Context, token string, cfg Config)
 	return bs.configs.Update(cfg)
 }
 
+func (bs bootstrapService) Patch(ctx context.Context, token string, cfg Config) (Config, error) {
+	owner, err := bs.identify(token)
+	if err != nil {
+		return Config{}, err
+	}
+
+	saved, err := bs.configs.RetrieveByID(owner, cfg.MFThing)
+	if err != nil {
+		return Config{}, err
+	}
+
+	if cfg.Name != "" {
+		saved.Name = cfg.Name
+	}
+	saved.Content = saved.Content.Merge(cfg.Content)
+
+	if err := bs.configs.Update(saved); err != nil {
+		return Config{}, err
+	}
+
+	return saved, nil
+}
+
 func (bs bootstrapService) UpdateCert(ctx context.Context, token, thingID, clientCert, clientKey, caCert string) error {
 	owner, err := bs.identify(token)
 	if err != nil {
@@ -211,6 +251,25 @@ func (bs bootstrapService) UpdateCert(ctx context.Context, token, thingID, clien
 	return nil
 }
 
+func (bs bootstrapService) UpdateFingerprint(ctx context.Context, token, id, fingerprint string) error {
+	owner, err := bs.identify(token)
+	if err != nil {
+		return err
+	}
+
+	if fingerprint != "" {
+		fingerprint, err = NormalizeFingerprint(fingerprint)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := bs.configs.UpdateFingerprint(owner, id, fingerprint); err != nil {
+		return errors.Wrap(errUpdateFingerprint, err)
+	}
+	return nil
+}
+
 func (bs bootstrapService) UpdateConnections(ctx context.Context, token, id string, connections []string) error {
 	owner, err := bs.identify(token)
 	if err != nil {
@@ -309,6 +368,19 @@ func (bs bootstrapService) Bootstrap(ctx context.Context, externalKey, externalI
 	return cfg, nil
 }
 
+func (bs bootstrapService) BootstrapByCert(ctx context.Context, fingerprint, externalID string) (Config, error) {
+	cfg, err := bs.configs.RetrieveByExternalID(externalID)
+	if err != nil {
+		return cfg, errors.Wrap(ErrBootstrap, err)
+	}
+
+	if cfg.CertFingerprint == "" || cfg.CertFingerprint != fingerprint {
+		return Config{}, errors.Wrap(ErrCertNotFound, ErrNotFound)
+	}
+
+	return cfg, nil
+}
+
 func (bs bootstrapService) ChangeState(ctx context.Context, token, id string, state State) error {
 	owner, err := bs.identify(token)
 	if err != nil {
@@ -8,11 +8,13 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/opentracing/opentracing-go/mocktracer"
@@ -50,7 +52,7 @@ var (
 		ExternalID:  "external_id",
 		ExternalKey: "external_key",
 		MFChannels:  []bootstrap.Channel{channel},
-		Content:     "config",
+		Content:     bootstrap.Content{App: map[string]interface{}{"content": "config"}},
 	}
 )
 
@@ -79,7 +81,7 @@ func newThingsService(auth mainflux.AuthServiceClient) things.Service {
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, nil)
 	return httptest.NewServer(mux)
 }
 
@@ -205,7 +207,7 @@ func TestUpdate(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
 
 	modifiedCreated := saved
-	modifiedCreated.Content = "new-config"
+	modifiedCreated.Content = bootstrap.Content{App: map[string]interface{}{"content": "new-config"}}
 	modifiedCreated.Name = "new name"
 
 	nonExisting := config
@@ -243,6 +245,90 @@ func TestUpdate(t *testing.T) {
 	}
 }
 
+func TestPatch(t *testing.T) {
+	users := mocks.NewAuthClient(map[string]string{validToken: email})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+	c := config
+	c.Name = "name"
+	c.Content = bootstrap.Content{
+		Network: map[string]interface{}{"host": "localhost", "port": "1883"},
+		App:     map[string]interface{}{"content": "config"},
+	}
+
+	saved, err := svc.Add(context.Background(), validToken, c)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	cases := []struct {
+		desc   string
+		config bootstrap.Config
+		token  string
+		res    bootstrap.Config
+		err    error
+	}{
+		{
+			desc: "patch a config content",
+			config: bootstrap.Config{
+				MFThing: saved.MFThing,
+				Content: bootstrap.Content{
+					Network:     map[string]interface{}{"port": "8883", "host": nil},
+					Credentials: map[string]interface{}{"user": "user"},
+				},
+			},
+			token: validToken,
+			res: bootstrap.Config{
+				Name: "name",
+				Content: bootstrap.Content{
+					Network:     map[string]interface{}{"port": "8883"},
+					Credentials: map[string]interface{}{"user": "user"},
+					App:         map[string]interface{}{"content": "config"},
+				},
+			},
+			err: nil,
+		},
+		{
+			desc:   "patch a config name",
+			config: bootstrap.Config{MFThing: saved.MFThing, Name: "new name"},
+			token:  validToken,
+			res: bootstrap.Config{
+				Name: "new name",
+				Content: bootstrap.Content{
+					Network:     map[string]interface{}{"port": "8883"},
+					Credentials: map[string]interface{}{"user": "user"},
+					App:         map[string]interface{}{"content": "config"},
+				},
+			},
+			err: nil,
+		},
+		{
+			desc:   "patch a non-existing config",
+			config: bootstrap.Config{MFThing: unknown, Name: "new name"},
+			token:  validToken,
+			err:    bootstrap.ErrNotFound,
+		},
+		{
+			desc:   "patch a config with wrong credentials",
+			config: bootstrap.Config{MFThing: saved.MFThing, Name: "new name"},
+			token:  invalidToken,
+			err:    bootstrap.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := svc.Patch(context.Background(), tc.token, tc.config)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+		assert.Equal(t, tc.res.Name, res.Name, fmt.Sprintf("%s: expected name %s got %s\n", tc.desc, tc.res.Name, res.Name))
+		assert.Equal(t, tc.res.Content, res.Content, fmt.Sprintf("%s: expected content %v got %v\n", tc.desc, tc.res.Content, res.Content))
+		view, err := svc.View(context.Background(), tc.token, saved.MFThing)
+		require.Nil(t, err, fmt.Sprintf("%s: viewing config expected to succeed: %s.\n", tc.desc, err))
+		assert.Equal(t, tc.res.Content, view.Content, fmt.Sprintf("%s: expected saved content %v got %v\n", tc.desc, tc.res.Content, view.Content))
+	}
+}
+
 func TestUpdateCert(t *testing.T) {
 	users := mocks.NewAuthClient(map[string]string{validToken: email})
 
@@ -591,6 +677,141 @@ func TestBootstrap(t *testing.T) {
 	}
 }
 
+func TestUpdateFingerprint(t *testing.T) {
+	users := mocks.NewAuthClient(map[string]string{validToken: email})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+
+	saved, err := svc.Add(context.Background(), validToken, config)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	fingerprint := strings.Repeat("ab", sha256.Size)
+	colons := strings.ToUpper(strings.TrimSuffix(strings.Repeat("ab:", sha256.Size), ":"))
+
+	cases := []struct {
+		desc        string
+		token       string
+		id          string
+		fingerprint string
+		expected    string
+		err         error
+	}{
+		{
+			desc:        "update fingerprint with wrong credentials",
+			token:       invalidToken,
+			id:          saved.MFThing,
+			fingerprint: fingerprint,
+			err:         bootstrap.ErrUnauthorizedAccess,
+		},
+		{
+			desc:        "update fingerprint of non-existing config",
+			token:       validToken,
+			id:          unknown,
+			fingerprint: fingerprint,
+			err:         bootstrap.ErrNotFound,
+		},
+		{
+			desc:        "update fingerprint with invalid fingerprint",
+			token:       validToken,
+			id:          saved.MFThing,
+			fingerprint: "invalid",
+			err:         bootstrap.ErrMalformedEntity,
+		},
+		{
+			desc:        "update fingerprint",
+			token:       validToken,
+			id:          saved.MFThing,
+			fingerprint: colons,
+			expected:    fingerprint,
+			err:         nil,
+		},
+		{
+			desc:        "remove fingerprint",
+			token:       validToken,
+			id:          saved.MFThing,
+			fingerprint: "",
+			expected:    "",
+			err:         nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateFingerprint(context.Background(), tc.token, tc.id, tc.fingerprint)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		cfg, err := svc.View(context.Background(), validToken, tc.id)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.expected, cfg.CertFingerprint, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.expected, cfg.CertFingerprint))
+	}
+}
+
+func TestBootstrapByCert(t *testing.T) {
+	users := mocks.NewAuthClient(map[string]string{validToken: email})
+
+	server := newThingsServer(newThingsService(users))
+	svc := newService(users, server.URL)
+
+	saved, err := svc.Add(context.Background(), validToken, config)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	c := config
+	c.ExternalID = "unmapped"
+	c.MFThing = ""
+	unmapped, err := svc.Add(context.Background(), validToken, c)
+	require.Nil(t, err, fmt.Sprintf("Saving config expected to succeed: %s.\n", err))
+
+	fingerprint := strings.Repeat("ab", sha256.Size)
+	err = svc.UpdateFingerprint(context.Background(), validToken, saved.MFThing, fingerprint)
+	require.Nil(t, err, fmt.Sprintf("Updating fingerprint expected to succeed: %s.\n", err))
+	saved.CertFingerprint = fingerprint
+
+	cases := []struct {
+		desc        string
+		config      bootstrap.Config
+		externalID  string
+		fingerprint string
+		err         error
+	}{
+		{
+			desc:        "bootstrap using invalid external id",
+			config:      bootstrap.Config{},
+			externalID:  "invalid",
+			fingerprint: fingerprint,
+			err:         bootstrap.ErrNotFound,
+		},
+		{
+			desc:        "bootstrap using wrong certificate",
+			config:      bootstrap.Config{},
+			externalID:  saved.ExternalID,
+			fingerprint: strings.Repeat("cd", sha256.Size),
+			err:         bootstrap.ErrNotFound,
+		},
+		{
+			desc:        "bootstrap config without mapped certificate",
+			config:      bootstrap.Config{},
+			externalID:  unmapped.ExternalID,
+			fingerprint: "",
+			err:         bootstrap.ErrNotFound,
+		},
+		{
+			desc:        "bootstrap using mapped certificate",
+			config:      saved,
+			externalID:  saved.ExternalID,
+			fingerprint: fingerprint,
+			err:         nil,
+		},
+	}
+
+	for _, tc := range cases {
+		config, err := svc.BootstrapByCert(context.Background(), tc.fingerprint, tc.externalID)
+		assert.Equal(t, tc.config, config, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.config, config))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
 func TestChangeState(t *testing.T) {
 	users := mocks.NewAuthClient(map[string]string{validToken: email})
 
@@ -73,3 +73,65 @@ func revokeCert(svc certs.Service) endpoint.Endpoint {
 		return svc.RevokeCert(ctx, req.token, req.certID)
 	}
 }
+
+func searchCerts(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(searchReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.SearchCerts(ctx, req.token, req.filter, req.offset, req.limit)
+		if err != nil {
+			return certsSearchRes{}, err
+		}
+
+		res := certsSearchRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Certs: []certViewRes{},
+		}
+		for _, cert := range page.Certs {
+			res.Certs = append(res.Certs, toCertView(cert))
+		}
+		return res, nil
+	}
+}
+
+func exportCerts(svc certs.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(exportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		crts, err := svc.ExportCerts(ctx, req.token, req.filter)
+		if err != nil {
+			return exportRes{}, err
+		}
+
+		res := exportRes{certs: []certViewRes{}}
+		for _, cert := range crts {
+			res.certs = append(res.certs, toCertView(cert))
+		}
+		return res, nil
+	}
+}
+
+func toCertView(cert certs.Cert) certViewRes {
+	view := certViewRes{
+		ThingID:    cert.ThingID,
+		CertSerial: cert.Serial,
+		Expire:     cert.Expire,
+		Status:     certs.ActiveStatus,
+	}
+	if !cert.Revoked.IsZero() {
+		revoked := cert.Revoked
+		view.Revoked = &revoked
+		view.Status = certs.RevokedStatus
+	}
+	return view
+}
@@ -62,3 +62,29 @@ func (lm *loggingMiddleware) RevokeCert(ctx context.Context, token, thingID stri
 
 	return lm.svc.RevokeCert(ctx, token, thingID)
 }
+
+func (lm *loggingMiddleware) SearchCerts(ctx context.Context, token string, filter certs.Filter, offset, limit uint64) (cp certs.Page, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method search_certs for token: %s and status: %s took %s to complete", token, filter.Status, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.SearchCerts(ctx, token, filter, offset, limit)
+}
+
+func (lm *loggingMiddleware) ExportCerts(ctx context.Context, token string, filter certs.Filter) (crts []certs.Cert, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_certs for token: %s and status: %s took %s to complete", token, filter.Status, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportCerts(ctx, token, filter)
+}
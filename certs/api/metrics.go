@@ -54,3 +54,21 @@ func (ms *metricsMiddleware) RevokeCert(ctx context.Context, token, thingID stri
 
 	return ms.svc.RevokeCert(ctx, token, thingID)
 }
+
+func (ms *metricsMiddleware) SearchCerts(ctx context.Context, token string, filter certs.Filter, offset, limit uint64) (certs.Page, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "search_certs").Add(1)
+		ms.latency.With("method", "search_certs").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.SearchCerts(ctx, token, filter, offset, limit)
+}
+
+func (ms *metricsMiddleware) ExportCerts(ctx context.Context, token string, filter certs.Filter) ([]certs.Cert, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "export_certs").Add(1)
+		ms.latency.With("method", "export_certs").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExportCerts(ctx, token, filter)
+}
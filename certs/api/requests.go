@@ -51,3 +51,42 @@ func (req *revokeReq) validate() error {
 
 	return nil
 }
+
+type searchReq struct {
+	token  string
+	filter certs.Filter
+	offset uint64
+	limit  uint64
+}
+
+func (req *searchReq) validate() error {
+	if req.token == "" {
+		return certs.ErrUnauthorizedAccess
+	}
+	if req.limit == 0 || req.limit > maxLimitSize {
+		return certs.ErrMalformedEntity
+	}
+	return validateFilter(req.filter)
+}
+
+type exportReq struct {
+	token  string
+	filter certs.Filter
+}
+
+func (req *exportReq) validate() error {
+	if req.token == "" {
+		return certs.ErrUnauthorizedAccess
+	}
+	return validateFilter(req.filter)
+}
+
+func validateFilter(filter certs.Filter) error {
+	if !certs.ValidStatus(filter.Status) {
+		return certs.ErrMalformedEntity
+	}
+	if !filter.ExpiresAfter.IsZero() && !filter.ExpiresBefore.IsZero() && !filter.ExpiresAfter.Before(filter.ExpiresBefore) {
+		return certs.ErrMalformedEntity
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 )
 
 type pageRes struct {
@@ -49,3 +50,32 @@ func (res certsRes) Headers() map[string]string {
 func (res certsRes) Empty() bool {
 	return false
 }
+
+type certsSearchRes struct {
+	pageRes
+	Certs []certViewRes `json:"certs"`
+}
+
+type certViewRes struct {
+	ThingID    string     `json:"thing_id"`
+	CertSerial string     `json:"cert_serial"`
+	Expire     time.Time  `json:"expire"`
+	Revoked    *time.Time `json:"revoked,omitempty"`
+	Status     string     `json:"status"`
+}
+
+func (res certsSearchRes) Code() int {
+	return http.StatusOK
+}
+
+func (res certsSearchRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res certsSearchRes) Empty() bool {
+	return false
+}
+
+type exportRes struct {
+	certs []certViewRes
+}
@@ -5,9 +5,11 @@ package api
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"io"
 	"net/http"
+	"time"
 
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
@@ -19,11 +21,17 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	offsetKey   = "offset"
-	limitKey    = "limit"
-	defOffset   = 0
-	defLimit    = 10
+	contentType    = "application/json"
+	csvContentType = "text/csv"
+	offsetKey      = "offset"
+	limitKey       = "limit"
+	thingIDKey     = "thing_id"
+	serialKey      = "serial"
+	expiresAfter   = "expires_after"
+	expiresBefore  = "expires_before"
+	statusKey      = "status"
+	defOffset      = 0
+	defLimit       = 10
 )
 
 var (
@@ -35,6 +43,7 @@ var (
 func MakeHandler(svc certs.Service) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
 	}
 
 	r := bone.New()
@@ -46,6 +55,20 @@ func MakeHandler(svc certs.Service) http.Handler {
 		opts...,
 	))
 
+	r.Get("/certs", kithttp.NewServer(
+		searchCerts(svc),
+		decodeSearchCerts,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/certs/export", kithttp.NewServer(
+		exportCerts(svc),
+		decodeExportCerts,
+		encodeCSVResponse,
+		opts...,
+	))
+
 	r.Get("/certs/:thingId", kithttp.NewServer(
 		listCerts(svc),
 		decodeListCerts,
@@ -102,6 +125,71 @@ func decodeListCerts(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeSearchCerts(_ context.Context, r *http.Request) (interface{}, error) {
+	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return nil, err
+	}
+	f, err := readFilter(r)
+	if err != nil {
+		return nil, err
+	}
+	req := searchReq{
+		token:  r.Header.Get("Authorization"),
+		filter: f,
+		limit:  l,
+		offset: o,
+	}
+	return req, nil
+}
+
+func decodeExportCerts(_ context.Context, r *http.Request) (interface{}, error) {
+	f, err := readFilter(r)
+	if err != nil {
+		return nil, err
+	}
+	req := exportReq{
+		token:  r.Header.Get("Authorization"),
+		filter: f,
+	}
+	return req, nil
+}
+
+func readFilter(r *http.Request) (certs.Filter, error) {
+	thingID, err := httputil.ReadStringQuery(r, thingIDKey, "")
+	if err != nil {
+		return certs.Filter{}, err
+	}
+	serial, err := httputil.ReadStringQuery(r, serialKey, "")
+	if err != nil {
+		return certs.Filter{}, err
+	}
+	after, err := httputil.ReadTimeQuery(r, expiresAfter, time.Time{})
+	if err != nil {
+		return certs.Filter{}, err
+	}
+	before, err := httputil.ReadTimeQuery(r, expiresBefore, time.Time{})
+	if err != nil {
+		return certs.Filter{}, err
+	}
+	status, err := httputil.ReadEnumQuery(r, statusKey, certs.AllStatus, certs.AllStatus, certs.ActiveStatus, certs.RevokedStatus)
+	if err != nil {
+		return certs.Filter{}, err
+	}
+
+	return certs.Filter{
+		ThingID:       thingID,
+		Serial:        serial,
+		ExpiresAfter:  after,
+		ExpiresBefore: before,
+		Status:        status,
+	}, nil
+}
+
 func decodeCerts(_ context.Context, r *http.Request) (interface{}, error) {
 	if r.Header.Get("Content-Type") != contentType {
 		return nil, errors.ErrUnsupportedContentType
@@ -124,14 +212,44 @@ func decodeRevokeCerts(_ context.Context, r *http.Request) (interface{}, error)
 	return req, nil
 }
 
+// encodeCSVResponse writes the exported certificates as CSV, one row per
+// certificate, preceded by the header row.
+func encodeCSVResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(exportRes)
+	w.Header().Set("Content-Type", csvContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="certs.csv"`)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"thing_id", "serial", "expire", "revoked", "status"}); err != nil {
+		return err
+	}
+	for _, c := range res.certs {
+		revoked := ""
+		if c.Revoked != nil {
+			revoked = c.Revoked.Format(time.RFC3339)
+		}
+		row := []string{c.ThingID, c.CertSerial, c.Expire.Format(time.RFC3339), revoked, c.Status}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 
+	if errors.Contains(err, errors.ErrInvalidQueryParams) || errors.Contains(err, certs.ErrMalformedEntity) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	switch err {
 	case errors.ErrUnsupportedContentType:
 		w.WriteHeader(http.StatusUnsupportedMediaType)
-	case io.EOF, errors.ErrMalformedEntity,
-		errors.ErrInvalidQueryParams:
+	case io.EOF, errors.ErrMalformedEntity:
 		w.WriteHeader(http.StatusBadRequest)
 	case errConflict:
 		w.WriteHeader(http.StatusConflict)
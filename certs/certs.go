@@ -3,7 +3,21 @@
 
 package certs
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+const (
+	// AllStatus matches both the active and the revoked certificates.
+	AllStatus = "all"
+
+	// ActiveStatus matches the certificates that are not revoked.
+	ActiveStatus = "active"
+
+	// RevokedStatus matches the revoked certificates.
+	RevokedStatus = "revoked"
+)
 
 // ConfigsPage contains page related metadata as well as list
 type Page struct {
@@ -13,6 +27,16 @@ type Page struct {
 	Certs  []Cert
 }
 
+// Filter contains the certificates search criteria. Empty fields match
+// all the certificates.
+type Filter struct {
+	ThingID       string
+	Serial        string
+	ExpiresAfter  time.Time
+	ExpiresBefore time.Time
+	Status        string
+}
+
 // Repository specifies a Config persistence API.
 type Repository interface {
 	// Save  saves cert for thing into database
@@ -21,9 +45,26 @@ type Repository interface {
 	// RetrieveAll retrieve all issued certificates for given owner and thing id
 	RetrieveAll(ctx context.Context, ownerID, thingID string, offset, limit uint64) (Page, error)
 
+	// RetrieveBySearch retrieves the certificates of the given owner
+	// matching the filter, ordered by the expiration time.
+	RetrieveBySearch(ctx context.Context, ownerID string, filter Filter, offset, limit uint64) (Page, error)
+
 	// Remove certificate from DB for given thing
 	Remove(ctx context.Context, thingID string) error
 
-	// RetrieveByThing certificate by given thing
+	// Revoke marks the active certificate with the given serial as revoked.
+	Revoke(ctx context.Context, serial string, revoked time.Time) error
+
+	// RetrieveByThing retrieves the active certificate by given thing
 	RetrieveByThing(ctx context.Context, thingID string) (Cert, error)
 }
+
+// ValidStatus reports whether the status is one of the known statuses.
+func ValidStatus(status string) bool {
+	switch status {
+	case AllStatus, ActiveStatus, RevokedStatus:
+		return true
+	default:
+		return false
+	}
+}
@@ -5,7 +5,9 @@ package mocks
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux/certs"
 )
@@ -64,6 +66,56 @@ func (c *certsRepoMock) RetrieveAll(ctx context.Context, ownerID, thingID string
 	return page, nil
 }
 
+func (c *certsRepoMock) RetrieveBySearch(ctx context.Context, ownerID string, filter certs.Filter, offset, limit uint64) (certs.Page, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var crts []certs.Cert
+	for _, v := range c.certs {
+		if v.OwnerID == ownerID && matches(v, filter) {
+			crts = append(crts, v)
+		}
+	}
+	sort.Slice(crts, func(i, j int) bool {
+		if crts[i].Expire.Equal(crts[j].Expire) {
+			return crts[i].Serial < crts[j].Serial
+		}
+		return crts[i].Expire.Before(crts[j].Expire)
+	})
+
+	page := certs.Page{
+		Certs:  []certs.Cert{},
+		Total:  uint64(len(crts)),
+		Offset: offset,
+		Limit:  limit,
+	}
+	if offset >= uint64(len(crts)) {
+		return page, nil
+	}
+	end := offset + limit
+	if end > uint64(len(crts)) {
+		end = uint64(len(crts))
+	}
+	page.Certs = crts[offset:end]
+
+	return page, nil
+}
+
+func (c *certsRepoMock) Revoke(ctx context.Context, serial string, revoked time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	crt, ok := c.certs[serial]
+	if !ok || !crt.Revoked.IsZero() {
+		return certs.ErrNotFound
+	}
+	crt.Revoked = revoked
+	c.certs[serial] = crt
+	if active, ok := c.certsByThingID[crt.ThingID]; ok && active.Serial == serial {
+		delete(c.certsByThingID, crt.ThingID)
+	}
+	return nil
+}
+
 func (c *certsRepoMock) Remove(ctx context.Context, serial string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -85,3 +137,17 @@ func (c *certsRepoMock) RetrieveByThing(ctx context.Context, thingID string) (ce
 	}
 	return crt, nil
 }
+
+func matches(crt certs.Cert, filter certs.Filter) bool {
+	switch {
+	case filter.ThingID != "" && crt.ThingID != filter.ThingID,
+		filter.Serial != "" && crt.Serial != filter.Serial,
+		!filter.ExpiresAfter.IsZero() && crt.Expire.Before(filter.ExpiresAfter),
+		!filter.ExpiresBefore.IsZero() && !crt.Expire.Before(filter.ExpiresBefore),
+		filter.Status == certs.ActiveStatus && !crt.Revoked.IsZero(),
+		filter.Status == certs.RevokedStatus && crt.Revoked.IsZero():
+		return false
+	default:
+		return true
+	}
+}
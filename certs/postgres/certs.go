@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -23,6 +24,7 @@ var (
 	errSaveDB     = errors.New("failed to save certificate to database")
 	errRetrieveDB = errors.New("failed to retrieve certificate from db")
 	errRemove     = errors.New("failed to remove certificate from database")
+	errRevoke     = errors.New("failed to revoke certificate in database")
 	errInvalid    = "invalid_text_representation"
 )
 
@@ -81,6 +83,72 @@ func (cr certsRepository) RetrieveAll(ctx context.Context, ownerID, thingID stri
 	}, nil
 }
 
+func (cr certsRepository) RetrieveBySearch(ctx context.Context, ownerID string, filter certs.Filter, offset, limit uint64) (certs.Page, error) {
+	params := map[string]interface{}{
+		"owner_id":       ownerID,
+		"thing_id":       filter.ThingID,
+		"serial":         filter.Serial,
+		"expires_after":  filter.ExpiresAfter,
+		"expires_before": filter.ExpiresBefore,
+		"limit":          limit,
+		"offset":         offset,
+	}
+
+	query := []string{"owner_id = :owner_id"}
+	if filter.ThingID != "" {
+		query = append(query, "thing_id = :thing_id")
+	}
+	if filter.Serial != "" {
+		query = append(query, "serial = :serial")
+	}
+	if !filter.ExpiresAfter.IsZero() {
+		query = append(query, "expire >= :expires_after")
+	}
+	if !filter.ExpiresBefore.IsZero() {
+		query = append(query, "expire < :expires_before")
+	}
+	switch filter.Status {
+	case certs.ActiveStatus:
+		query = append(query, "revoked IS NULL")
+	case certs.RevokedStatus:
+		query = append(query, "revoked IS NOT NULL")
+	}
+	whereClause := fmt.Sprintf("WHERE %s", strings.Join(query, " AND "))
+
+	q := fmt.Sprintf(`SELECT thing_id, owner_id, serial, expire, revoked FROM certs %s
+		ORDER BY expire, serial LIMIT :limit OFFSET :offset;`, whereClause)
+	rows, err := cr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		cr.log.Error(fmt.Sprintf("Failed to search certs due to %s", err))
+		return certs.Page{}, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	certificates := []certs.Cert{}
+	for rows.Next() {
+		var dbcrt dbCert
+		if err := rows.StructScan(&dbcrt); err != nil {
+			cr.log.Error(fmt.Sprintf("Failed to read retrieved cert due to %s", err))
+			return certs.Page{}, errors.Wrap(errRetrieveDB, err)
+		}
+		certificates = append(certificates, toCert(dbcrt))
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM certs %s;`, whereClause)
+	total, err := total(ctx, cr.db, cq, params)
+	if err != nil {
+		cr.log.Error(fmt.Sprintf("Failed to count certs due to %s", err))
+		return certs.Page{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return certs.Page{
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+		Certs:  certificates,
+	}, nil
+}
+
 func (cr certsRepository) Save(ctx context.Context, cert certs.Cert) (string, error) {
 	q := `INSERT INTO certs (thing_id, owner_id, serial, expire) VALUES (:thing_id, :owner_id, :serial, :expire)`
 
@@ -123,8 +191,26 @@ func (cr certsRepository) Remove(ctx context.Context, serial string) error {
 	return nil
 }
 
+func (cr certsRepository) Revoke(ctx context.Context, serial string, revoked time.Time) error {
+	q := `UPDATE certs SET revoked = $2 WHERE serial = $1 AND revoked IS NULL`
+	res, err := cr.db.ExecContext(ctx, q, serial, revoked)
+	if err != nil {
+		return errors.Wrap(errRevoke, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errRevoke, err)
+	}
+	if cnt == 0 {
+		return errors.Wrap(errRevoke, things.ErrNotFound)
+	}
+
+	return nil
+}
+
 func (cr certsRepository) RetrieveByThing(ctx context.Context, thingID string) (certs.Cert, error) {
-	q := `SELECT thing_id, owner_id, serial, expire FROM certs WHERE thing_id = $1`
+	q := `SELECT thing_id, owner_id, serial, expire FROM certs WHERE thing_id = $1 AND revoked IS NULL`
 	var dbcrt dbCert
 	var c certs.Cert
 
@@ -170,10 +256,11 @@ func (cr certsRepository) rollback(content string, tx *sqlx.Tx, err error) {
 }
 
 type dbCert struct {
-	ThingID string    `db:"thing_id"`
-	Serial  string    `db:"serial"`
-	Expire  time.Time `db:"expire"`
-	OwnerID string    `db:"owner_id"`
+	ThingID string       `db:"thing_id"`
+	Serial  string       `db:"serial"`
+	Expire  time.Time    `db:"expire"`
+	OwnerID string       `db:"owner_id"`
+	Revoked sql.NullTime `db:"revoked"`
 }
 
 func toDBCert(c certs.Cert) dbCert {
@@ -191,5 +278,25 @@ func toCert(cdb dbCert) certs.Cert {
 	c.ThingID = cdb.ThingID
 	c.Serial = cdb.Serial
 	c.Expire = cdb.Expire
+	if cdb.Revoked.Valid {
+		c.Revoked = cdb.Revoked.Time
+	}
 	return c
 }
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
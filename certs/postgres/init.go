@@ -71,6 +71,17 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE IF EXISTS certs;",
 				},
 			},
+			{
+				Id: "certs_2",
+				Up: []string{
+					`ALTER TABLE IF EXISTS certs ADD COLUMN IF NOT EXISTS revoked TIMESTAMPTZ;`,
+					`CREATE INDEX IF NOT EXISTS certs_expire_idx ON certs (owner_id, expire);`,
+				},
+				Down: []string{
+					"DROP INDEX IF EXISTS certs_expire_idx;",
+					"ALTER TABLE IF EXISTS certs DROP COLUMN IF EXISTS revoked;",
+				},
+			},
 		},
 	}
 
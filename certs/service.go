@@ -32,9 +32,12 @@ var (
 	// ErrFailedCertRevocation failed to revoke certificate
 	ErrFailedCertRevocation = errors.New("failed to revoke certificate")
 
-	errFailedToRemoveCertFromDB = errors.New("failed to remove cert serial from db")
+	errFailedToRevokeCertInDB = errors.New("failed to revoke cert serial in db")
 )
 
+// exportPageSize is the number of certificates retrieved at once on export.
+const exportPageSize = 100
+
 var _ Service = (*certsService)(nil)
 
 // Service specifies an API that must be fulfilled by the domain service
@@ -48,6 +51,14 @@ type Service interface {
 
 	// RevokeCert revokes certificate for given thing
 	RevokeCert(ctx context.Context, token, thingID string) (Revoke, error)
+
+	// SearchCerts lists the certificates issued for given owner matching the
+	// filter, including the revoked ones
+	SearchCerts(ctx context.Context, token string, filter Filter, offset, limit uint64) (Page, error)
+
+	// ExportCerts returns all the certificates issued for given owner
+	// matching the filter
+	ExportCerts(ctx context.Context, token string, filter Filter) ([]Cert, error)
 }
 
 // Config defines the service parameters
@@ -107,6 +118,7 @@ type Cert struct {
 	PrivateKeyType string    `json:"private_key_type" mapstructure:"private_key_type"`
 	Serial         string    `json:"serial" mapstructure:"serial_number"`
 	Expire         time.Time `json:"expire" mapstructure:"-"`
+	Revoked        time.Time `json:"revoked" mapstructure:"-"`
 }
 
 func (cs *certsService) IssueCert(ctx context.Context, token, thingID string, daysValid string, keyBits int, keyType string) (Cert, error) {
@@ -162,8 +174,9 @@ func (cs *certsService) RevokeCert(ctx context.Context, token, thingID string) (
 		return revoke, errors.Wrap(ErrFailedCertRevocation, err)
 	}
 	revoke.RevocationTime = revTime
-	if err = cs.certsRepo.Remove(context.Background(), cert.Serial); err != nil {
-		return revoke, errors.Wrap(errFailedToRemoveCertFromDB, err)
+	// The certificate is kept in the inventory, marked as revoked.
+	if err = cs.certsRepo.Revoke(context.Background(), cert.Serial, revTime); err != nil {
+		return revoke, errors.Wrap(errFailedToRevokeCertInDB, err)
 	}
 	return revoke, nil
 }
@@ -176,3 +189,45 @@ func (cs *certsService) ListCerts(ctx context.Context, token, thingID string, of
 
 	return cs.certsRepo.RetrieveAll(ctx, u.GetEmail(), thingID, offset, limit)
 }
+
+func (cs *certsService) SearchCerts(ctx context.Context, token string, filter Filter, offset, limit uint64) (Page, error) {
+	u, err := cs.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Page{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if filter.Status == "" {
+		filter.Status = AllStatus
+	}
+	if !ValidStatus(filter.Status) {
+		return Page{}, ErrMalformedEntity
+	}
+
+	return cs.certsRepo.RetrieveBySearch(ctx, u.GetEmail(), filter, offset, limit)
+}
+
+func (cs *certsService) ExportCerts(ctx context.Context, token string, filter Filter) ([]Cert, error) {
+	u, err := cs.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if filter.Status == "" {
+		filter.Status = AllStatus
+	}
+	if !ValidStatus(filter.Status) {
+		return nil, ErrMalformedEntity
+	}
+
+	crts := []Cert{}
+	for offset := uint64(0); ; offset += exportPageSize {
+		page, err := cs.certsRepo.RetrieveBySearch(ctx, u.GetEmail(), filter, offset, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		crts = append(crts, page.Certs...)
+		if len(page.Certs) == 0 || uint64(len(crts)) >= page.Total {
+			return crts, nil
+		}
+	}
+}
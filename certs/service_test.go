@@ -290,8 +290,127 @@ func TestListCerts(t *testing.T) {
 
 }
 
+func TestSearchCerts(t *testing.T) {
+	svc, err := newService(map[string]string{token: email})
+	require.Nil(t, err, fmt.Sprintf("unexpected service creation error: %s\n", err))
+
+	var issued []certs.Cert
+	for i := 0; i < certNum; i++ {
+		c, err := svc.IssueCert(context.Background(), token, thingID, daysValid, keyBits, key)
+		require.Nil(t, err, fmt.Sprintf("unexpected cert creation error: %s\n", err))
+		issued = append(issued, c)
+	}
+
+	_, err = svc.RevokeCert(context.Background(), token, thingID)
+	require.Nil(t, err, fmt.Sprintf("unexpected cert revocation error: %s\n", err))
+
+	cases := []struct {
+		token  string
+		desc   string
+		filter certs.Filter
+		offset uint64
+		limit  uint64
+		size   uint64
+		total  uint64
+		err    error
+	}{
+		{
+			desc:   "search all certs",
+			token:  token,
+			filter: certs.Filter{Status: certs.AllStatus},
+			offset: 0,
+			limit:  certNum,
+			size:   certNum,
+			total:  certNum,
+			err:    nil,
+		},
+		{
+			desc:   "search active certs",
+			token:  token,
+			filter: certs.Filter{Status: certs.ActiveStatus},
+			offset: 0,
+			limit:  certNum,
+			size:   certNum - 1,
+			total:  certNum - 1,
+			err:    nil,
+		},
+		{
+			desc:   "search revoked certs",
+			token:  token,
+			filter: certs.Filter{Status: certs.RevokedStatus},
+			offset: 0,
+			limit:  certNum,
+			size:   1,
+			total:  1,
+			err:    nil,
+		},
+		{
+			desc:   "search certs by serial",
+			token:  token,
+			filter: certs.Filter{Serial: issued[0].Serial},
+			offset: 0,
+			limit:  certNum,
+			size:   1,
+			total:  1,
+			err:    nil,
+		},
+		{
+			desc:   "search certs by thing with offset",
+			token:  token,
+			filter: certs.Filter{ThingID: thingID},
+			offset: certNum / 2,
+			limit:  certNum,
+			size:   certNum / 2,
+			total:  certNum,
+			err:    nil,
+		},
+		{
+			desc:   "search certs expiring before now",
+			token:  token,
+			filter: certs.Filter{ExpiresBefore: time.Now()},
+			offset: 0,
+			limit:  certNum,
+			size:   0,
+			total:  0,
+			err:    nil,
+		},
+		{
+			desc:   "search certs with invalid status",
+			token:  token,
+			filter: certs.Filter{Status: wrongValue},
+			offset: 0,
+			limit:  certNum,
+			size:   0,
+			total:  0,
+			err:    certs.ErrMalformedEntity,
+		},
+		{
+			desc:   "search certs with invalid token",
+			token:  wrongValue,
+			filter: certs.Filter{},
+			offset: 0,
+			limit:  certNum,
+			size:   0,
+			total:  0,
+			err:    certs.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.SearchCerts(context.Background(), tc.token, tc.filter, tc.offset, tc.limit)
+		size := uint64(len(page.Certs))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.total, page.Total))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	exported, err := svc.ExportCerts(context.Background(), token, certs.Filter{Status: certs.RevokedStatus})
+	assert.Nil(t, err, fmt.Sprintf("unexpected certs export error: %s\n", err))
+	assert.Equal(t, 1, len(exported), fmt.Sprintf("expected %d exported certs got %d\n", 1, len(exported)))
+}
+
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, nil)
 	return httptest.NewServer(mux)
 }
 
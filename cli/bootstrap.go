@@ -79,6 +79,31 @@ var cmdBootstrap = []cobra.Command{
 			logOK()
 		},
 	},
+	cobra.Command{
+		Use:   "patch",
+		Short: "patch <JSON_config> <user_auth_token>",
+		Long:  `Renames the Config and deep-merges the provided content into the Config content`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			var cfg mfxsdk.BootstrapConfig
+			if err := json.Unmarshal([]byte(args[0]), &cfg); err != nil {
+				logError(err)
+				return
+			}
+
+			c, err := sdk.PatchBootstrap(args[1], cfg)
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(c)
+		},
+	},
 	cobra.Command{
 		Use:   "remove",
 		Short: "remove <thing_id> <user_auth_token>",
@@ -0,0 +1,443 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+const (
+	shellPrompt = "mainflux> "
+
+	thingVar   = "thing"
+	channelVar = "channel"
+	tokenVar   = "token"
+
+	// completionLimit is the max number of entities fetched for completion.
+	completionLimit = 100
+)
+
+var (
+	errUnterminatedQuote = errors.New("unterminated quote")
+	errNestedShell       = errors.New("shell is already running")
+	errUnknownVariable   = errors.New("unknown session variable, expected thing, channel or token")
+
+	shellVars = []string{thingVar, channelVar, tokenVar}
+	builtins  = []string{"use", "unset", "history", "exit", "quit"}
+)
+
+// shell is the interactive session that runs the CLI commands. The session
+// keeps the selected thing, channel and user token, which are referenced as
+// $thing, $channel and $token in the command arguments.
+type shell struct {
+	root    *cobra.Command
+	flags   map[*pflag.Flag]string
+	vars    map[string]string
+	history []string
+
+	// Entity IDs used for completion, fetched lazily once the token is set.
+	thingIDs   []string
+	channelIDs []string
+	fetched    bool
+}
+
+// NewShellCmd returns interactive shell command.
+func NewShellCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Interactive shell",
+		Long: `Interactive shell: run CLI commands with tab completion and command history.
+Select the session thing, channel or user token with "use [thing | channel | token] <value>"
+and reference them as $thing, $channel and $token in the command arguments.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 0 {
+				logUsage(cmd.Use)
+				return
+			}
+
+			s := newShell(cmd.Root())
+			if err := s.run(); err != nil {
+				logError(err)
+			}
+		},
+	}
+}
+
+func newShell(root *cobra.Command) *shell {
+	s := &shell{
+		root:  root,
+		flags: make(map[*pflag.Flag]string),
+		vars:  make(map[string]string),
+	}
+
+	// Remember the flags the shell was started with, so that the flags
+	// passed to one command don't leak into the next one.
+	visitCommands(root, func(f *pflag.Flag) {
+		s.flags[f] = f.Value.String()
+	})
+
+	return s
+}
+
+func (s *shell) run() error {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return s.runScript(os.Stdin)
+	}
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, shellPrompt)
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		return s.complete(t, line, pos, key)
+	}
+
+	for {
+		// The terminal is in raw mode only while the line is being read,
+		// so that the command output is printed as usual.
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		if w, h, err := term.GetSize(fd); err == nil {
+			t.SetSize(w, h)
+		}
+		line, err := t.ReadLine()
+		term.Restore(fd, state)
+
+		if err == io.EOF {
+			fmt.Println()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !s.exec(line) {
+			return nil
+		}
+	}
+}
+
+func (s *shell) runScript(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if !s.exec(scanner.Text()) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// exec executes the line and reports whether the shell should continue.
+func (s *shell) exec(line string) bool {
+	args, err := splitArgs(line)
+	if err != nil {
+		logError(err)
+		return true
+	}
+	if len(args) == 0 {
+		return true
+	}
+	s.history = append(s.history, line)
+
+	switch args[0] {
+	case "exit", "quit":
+		return false
+	case "history":
+		for i, l := range s.history {
+			fmt.Printf("%5d  %s\n", i+1, l)
+		}
+		return true
+	case "use":
+		s.use(args[1:])
+		return true
+	case "unset":
+		s.unset(args[1:])
+		return true
+	case "shell":
+		logError(errNestedShell)
+		return true
+	}
+
+	args, err = s.expand(args)
+	if err != nil {
+		logError(err)
+		return true
+	}
+
+	s.root.SetArgs(args)
+	s.root.Execute()
+	s.resetFlags()
+
+	// The command may have created or removed the entities.
+	s.fetched = false
+	return true
+}
+
+func (s *shell) use(args []string) {
+	switch len(args) {
+	case 0:
+		for _, v := range shellVars {
+			fmt.Printf("%-8s %s\n", v, s.vars[v])
+		}
+	case 2:
+		if !isShellVar(args[0]) {
+			logError(errUnknownVariable)
+			return
+		}
+		s.vars[args[0]] = args[1]
+		if args[0] == tokenVar {
+			s.fetched = false
+		}
+	default:
+		logUsage("use [thing | channel | token] <value>")
+	}
+}
+
+func (s *shell) unset(args []string) {
+	if len(args) != 1 {
+		logUsage("unset [thing | channel | token]")
+		return
+	}
+	if !isShellVar(args[0]) {
+		logError(errUnknownVariable)
+		return
+	}
+	delete(s.vars, args[0])
+}
+
+// expand replaces the session variable references with their values.
+func (s *shell) expand(args []string) ([]string, error) {
+	ret := make([]string, len(args))
+	for i, arg := range args {
+		ret[i] = arg
+		if !strings.HasPrefix(arg, "$") || !isShellVar(arg[1:]) {
+			continue
+		}
+		v, ok := s.vars[arg[1:]]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("%s is not set, select it with \"use %s <value>\"", arg, arg[1:]))
+		}
+		ret[i] = v
+	}
+	return ret, nil
+}
+
+func (s *shell) resetFlags() {
+	for f, v := range s.flags {
+		f.Value.Set(v)
+		f.Changed = false
+	}
+}
+
+func (s *shell) complete(t *term.Terminal, line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' {
+		return "", 0, false
+	}
+
+	head := line[:pos]
+	words := strings.Fields(head)
+	prefix := ""
+	if len(words) > 0 && !strings.HasSuffix(head, " ") {
+		prefix = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var matches []string
+	for _, c := range s.candidates(words) {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+
+	var completion string
+	switch len(matches) {
+	case 0:
+		return "", 0, false
+	case 1:
+		completion = matches[0] + " "
+	default:
+		completion = commonPrefix(matches)
+		if completion == prefix {
+			fmt.Fprintln(t, strings.Join(matches, "  "))
+			return "", 0, false
+		}
+	}
+
+	head = head[:len(head)-len(prefix)] + completion
+	return head + line[pos:], len(head), true
+}
+
+// candidates returns the completion candidates of the word following
+// the given words.
+func (s *shell) candidates(words []string) []string {
+	if len(words) == 0 {
+		return append(subcommands(s.root), builtins...)
+	}
+
+	switch words[0] {
+	case "use", "unset":
+		if len(words) == 1 {
+			return shellVars
+		}
+		if words[0] == "use" && len(words) == 2 {
+			switch words[1] {
+			case thingVar:
+				return s.entities(thingVar)
+			case channelVar:
+				return s.entities(channelVar)
+			}
+		}
+		return nil
+	case "history", "exit", "quit":
+		return nil
+	}
+
+	cmd, rest, err := s.root.Find(words)
+	if err != nil {
+		return nil
+	}
+	if len(rest) == 0 && cmd.HasAvailableSubCommands() {
+		return subcommands(cmd)
+	}
+
+	var refs []string
+	for _, v := range shellVars {
+		if _, ok := s.vars[v]; ok {
+			refs = append(refs, "$"+v)
+		}
+	}
+
+	// Suggest the IDs of the entities the command most likely refers to first.
+	switch words[0] {
+	case "things":
+		return append(refs, append(s.entities(thingVar), s.entities(channelVar)...)...)
+	case "channels", "messages":
+		return append(refs, append(s.entities(channelVar), s.entities(thingVar)...)...)
+	default:
+		return refs
+	}
+}
+
+// entities returns the IDs of the things or channels, fetching them with
+// the session token on the first use.
+func (s *shell) entities(kind string) []string {
+	token, ok := s.vars[tokenVar]
+	if !ok {
+		return nil
+	}
+
+	if !s.fetched {
+		s.thingIDs, s.channelIDs = nil, nil
+		if tp, err := sdk.Things(token, 0, completionLimit, ""); err == nil {
+			for _, th := range tp.Things {
+				s.thingIDs = append(s.thingIDs, th.ID)
+			}
+		}
+		if cp, err := sdk.Channels(token, 0, completionLimit, ""); err == nil {
+			for _, ch := range cp.Channels {
+				s.channelIDs = append(s.channelIDs, ch.ID)
+			}
+		}
+		s.fetched = true
+	}
+
+	if kind == thingVar {
+		return s.thingIDs
+	}
+	return s.channelIDs
+}
+
+func subcommands(cmd *cobra.Command) []string {
+	var names []string
+	for _, c := range cmd.Commands() {
+		if c.IsAvailableCommand() {
+			names = append(names, c.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func visitCommands(cmd *cobra.Command, fn func(*pflag.Flag)) {
+	cmd.Flags().VisitAll(fn)
+	cmd.PersistentFlags().VisitAll(fn)
+	for _, c := range cmd.Commands() {
+		visitCommands(c, fn)
+	}
+}
+
+func isShellVar(name string) bool {
+	for _, v := range shellVars {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// splitArgs splits the line into the arguments separated by whitespace,
+// respecting the single and double quotes and the backslash escapes.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var arg strings.Builder
+	var quote rune
+	inArg, escaped := false, false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			arg.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			arg.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, arg.String())
+				arg.Reset()
+				inArg = false
+			}
+		default:
+			arg.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errUnterminatedQuote
+	}
+	if inArg {
+		args = append(args, arg.String())
+	}
+	return args, nil
+}
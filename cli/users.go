@@ -4,7 +4,12 @@
 package cli
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 
 	mfxsdk "github.com/mainflux/mainflux/pkg/sdk/go"
 	"github.com/spf13/cobra"
@@ -128,17 +133,72 @@ func NewUsersCmd() *cobra.Command {
 		},
 	}
 
+	importCmd := cobra.Command{
+		Use:   "import",
+		Short: "import <users_file> <user_auth_token>",
+		Long:  `Bulk import users from json or csv file`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) != 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			users, err := usersFromFile(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			res, err := sdk.ImportUsers(users, args[1])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			logJSON(res)
+		},
+	}
+
+	exportCmd := cobra.Command{
+		Use:   "export",
+		Short: "export <user_auth_token> [<users_file>]",
+		Long:  `Export all users, to json or csv file if given`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) < 1 || len(args) > 2 {
+				logUsage(cmd.Short)
+				return
+			}
+
+			users, err := sdk.ExportUsers(args[0])
+			if err != nil {
+				logError(err)
+				return
+			}
+
+			if len(args) == 1 {
+				logJSON(users)
+				return
+			}
+			if err := usersToFile(args[1], users); err != nil {
+				logError(err)
+				return
+			}
+
+			logOK()
+		},
+	}
+
 	cmd := cobra.Command{
 		Use:   "users",
 		Short: "Users management",
 		Long:  `Users management: create accounts and tokens"`,
 		Run: func(cmd *cobra.Command, args []string) {
-			logUsage("users [create | get | update | token | password]")
+			logUsage("users [create | get | update | token | password | import | export]")
 		},
 	}
 
 	cmdUsers := []cobra.Command{
-		createCmd, getCmd, tokenCmd, updateCmd, passwordCmd,
+		createCmd, getCmd, tokenCmd, updateCmd, passwordCmd, importCmd, exportCmd,
 	}
 
 	for i := range cmdUsers {
@@ -147,3 +207,94 @@ func NewUsersCmd() *cobra.Command {
 
 	return &cmd
 }
+
+// usersFromFile reads the imported users. Each line of the csv file holds the
+// email, the password, the password hash and the IDs of the groups of the
+// user, the trailing columns being optional.
+func usersFromFile(path string) ([]mfxsdk.UserRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return []mfxsdk.UserRecord{}, err
+	}
+	defer file.Close()
+
+	users := []mfxsdk.UserRecord{}
+	switch filepath.Ext(path) {
+	case csvExt:
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+
+		for {
+			l, err := reader.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return []mfxsdk.UserRecord{}, err
+			}
+
+			if len(l) < 1 || l[0] == "" {
+				return []mfxsdk.UserRecord{}, errors.New("empty line found in file")
+			}
+
+			user := mfxsdk.UserRecord{
+				Email: l[0],
+			}
+			if len(l) > 1 {
+				user.Password = l[1]
+			}
+			if len(l) > 2 {
+				user.PasswordHash = l[2]
+			}
+			if len(l) > 3 {
+				for _, g := range l[3:] {
+					if g != "" {
+						user.Groups = append(user.Groups, g)
+					}
+				}
+			}
+
+			users = append(users, user)
+		}
+	case jsonExt:
+		if err := json.NewDecoder(file).Decode(&users); err != nil {
+			return []mfxsdk.UserRecord{}, err
+		}
+	default:
+		return []mfxsdk.UserRecord{}, errors.New("unsupported file format")
+	}
+
+	return users, nil
+}
+
+// usersToFile writes the exported users in the format they're imported from.
+func usersToFile(path string, users []mfxsdk.UserRecord) error {
+	ext := filepath.Ext(path)
+	if ext != csvExt && ext != jsonExt {
+		return errors.New("unsupported file format")
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch ext {
+	case csvExt:
+		writer := csv.NewWriter(file)
+		for _, u := range users {
+			l := append([]string{u.Email, "", u.PasswordHash}, u.Groups...)
+			if err := writer.Write(l); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case jsonExt:
+		enc := json.NewEncoder(file)
+		enc.SetIndent("", "  ")
+		return enc.Encode(users)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -9,19 +10,32 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
+	vault "github.com/hashicorp/vault/api"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
 	api "github.com/mainflux/mainflux/auth/api"
 	grpcapi "github.com/mainflux/mainflux/auth/api/grpc"
 	httpapi "github.com/mainflux/mainflux/auth/api/http"
+	oidcapi "github.com/mainflux/mainflux/auth/api/http/oidc"
+	"github.com/mainflux/mainflux/auth/cache"
 	"github.com/mainflux/mainflux/auth/jwt"
 	"github.com/mainflux/mainflux/auth/keto"
+	"github.com/mainflux/mainflux/auth/oidc"
+	"github.com/mainflux/mainflux/auth/opa"
 	"github.com/mainflux/mainflux/auth/postgres"
+	authredis "github.com/mainflux/mainflux/auth/redis"
+	"github.com/mainflux/mainflux/auth/spicedb"
 	"github.com/mainflux/mainflux/auth/tracing"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/internal/ratelimit"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/opentracing/opentracing-go"
@@ -29,11 +43,11 @@ import (
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
 	defLogLevel      = "error"
+	defI18nDir       = ""
 	defDBHost        = "localhost"
 	defDBPort        = "5432"
 	defDBUser        = "mainflux"
@@ -48,12 +62,42 @@ const (
 	defSecret        = "auth"
 	defServerCert    = ""
 	defServerKey     = ""
+	defClientCACerts = ""
 	defJaegerURL     = ""
 	defKetoHost      = "mainflux-keto"
 	defKetoWritePort = "4467"
 	defKetoReadPort  = "4466"
+	defPolicyBackend = ketoBackend
+	defOPAURL        = "http://mainflux-opa:8181"
+	defSpiceDBURL    = "http://mainflux-spicedb:8443"
+	defSpiceDBKey    = ""
+	defCache         = ""
+	defCacheTTL      = "10s"
+	defCacheSize     = "10000"
+	defCacheURL      = "localhost:6379"
+	defCachePass     = ""
+	defCacheDB       = "0"
+	defESURL         = ""
+	defESPass        = ""
+	defESDB          = "0"
+	defRevokePeriod  = "1m"
+	defJWTAlg        = jwt.HS256
+	defJWTKeyID      = ""
+	defJWTKey        = ""
+	defJWTVerifyKeys = ""
+	defVaultHost     = ""
+	defVaultToken    = ""
+	defOIDCIssuer    = ""
+	defOIDCLoginURL  = ""
+	defOIDCClients   = ""
+	defOIDCTrusted   = ""
+	defTemplates     = ""
+	defRateLimitIP   = "0"
+	defRateLimitAcc  = "0"
+	defRateLimitStr  = memoryStore
 
 	envLogLevel      = "MF_AUTH_LOG_LEVEL"
+	envI18nDir       = "MF_AUTH_I18N_DIR"
 	envDBHost        = "MF_AUTH_DB_HOST"
 	envDBPort        = "MF_AUTH_DB_PORT"
 	envDBUser        = "MF_AUTH_DB_USER"
@@ -68,25 +112,96 @@ const (
 	envSecret        = "MF_AUTH_SECRET"
 	envServerCert    = "MF_AUTH_SERVER_CERT"
 	envServerKey     = "MF_AUTH_SERVER_KEY"
+	envClientCACerts = "MF_AUTH_GRPC_CLIENT_CA_CERTS"
 	envJaegerURL     = "MF_JAEGER_URL"
 	envKetoHost      = "MF_KETO_HOST"
 	envKetoWritePort = "MF_KETO_WRITE_REMOTE_PORT"
 	envKetoReadPort  = "MF_KETO_READ_REMOTE_PORT"
+	envPolicyBackend = "MF_AUTH_POLICY_BACKEND"
+	envOPAURL        = "MF_AUTH_OPA_URL"
+	envSpiceDBURL    = "MF_AUTH_SPICEDB_URL"
+	envSpiceDBKey    = "MF_AUTH_SPICEDB_PRESHARED_KEY"
+	envCache         = "MF_AUTH_POLICY_CACHE"
+	envCacheTTL      = "MF_AUTH_POLICY_CACHE_TTL"
+	envCacheSize     = "MF_AUTH_POLICY_CACHE_SIZE"
+	envCacheURL      = "MF_AUTH_CACHE_URL"
+	envCachePass     = "MF_AUTH_CACHE_PASS"
+	envCacheDB       = "MF_AUTH_CACHE_DB"
+	envESURL         = "MF_AUTH_ES_URL"
+	envESPass        = "MF_AUTH_ES_PASS"
+	envESDB          = "MF_AUTH_ES_DB"
+	envRevokePeriod  = "MF_AUTH_MEMBERSHIP_SWEEP"
+	envJWTAlg        = "MF_AUTH_JWT_ALGORITHM"
+	envJWTKeyID      = "MF_AUTH_JWT_KEY_ID"
+	envJWTKey        = "MF_AUTH_JWT_KEY"
+	envJWTVerifyKeys = "MF_AUTH_JWT_VERIFICATION_KEYS"
+	envVaultHost     = "MF_AUTH_VAULT_HOST"
+	envVaultToken    = "MF_AUTH_VAULT_TOKEN"
+	envOIDCIssuer    = "MF_AUTH_OIDC_ISSUER"
+	envOIDCLoginURL  = "MF_AUTH_OIDC_LOGIN_URL"
+	envOIDCClients   = "MF_AUTH_OIDC_CLIENTS"
+	envOIDCTrusted   = "MF_AUTH_OIDC_TRUSTED_ISSUERS"
+	envTemplates     = "MF_AUTH_POLICY_TEMPLATES"
+	envRateLimitIP   = "MF_AUTH_RATE_LIMIT_IP"
+	envRateLimitAcc  = "MF_AUTH_RATE_LIMIT_ACCOUNT"
+	envRateLimitStr  = "MF_AUTH_RATE_LIMIT_STORE"
+
+	ketoBackend    = "keto"
+	opaBackend     = "opa"
+	opaTimeout     = 5 * time.Second
+	spiceDBBackend = "spicedb"
+	spiceDBTimeout = 5 * time.Second
+
+	memoryCache = "memory"
+	redisCache  = "redis"
+
+	memoryStore = "memory"
+	redisStore  = "redis"
 )
 
 type config struct {
 	logLevel      string
+	i18nDir       string
 	dbConfig      postgres.Config
 	httpPort      string
 	grpcPort      string
 	secret        string
 	serverCert    string
 	serverKey     string
+	clientCACerts string
 	jaegerURL     string
 	resetURL      string
 	ketoHost      string
 	ketoWritePort string
 	ketoReadPort  string
+	policyBackend string
+	opaURL        string
+	spiceDBURL    string
+	spiceDBKey    string
+	cache         string
+	cacheTTL      time.Duration
+	cacheSize     int
+	cacheURL      string
+	cachePass     string
+	cacheDB       string
+	esURL         string
+	esPass        string
+	esDB          string
+	revokePeriod  time.Duration
+	jwtAlg        string
+	jwtKeyID      string
+	jwtKey        string
+	jwtVerifyKeys string
+	vaultHost     string
+	vaultToken    string
+	oidcIssuer    string
+	oidcLoginURL  string
+	oidcClients   string
+	oidcTrusted   string
+	templates     string
+	rateLimitIP   int
+	rateLimitAcc  int
+	rateLimitStr  string
 }
 
 type tokenConfig struct {
@@ -97,11 +212,16 @@ type tokenConfig struct {
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
 
+	if err := i18n.LoadCatalogs(cfg.i18nDir); err != nil {
+		logger.Error(fmt.Sprintf("Failed to load message catalogs: %s", err))
+		os.Exit(1)
+	}
+
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
 
@@ -111,13 +231,28 @@ func main() {
 	dbTracer, dbCloser := initJaeger("auth_db", cfg.jaegerURL, logger)
 	defer dbCloser.Close()
 
-	readerConn, writerConn := initKeto(cfg.ketoHost, cfg.ketoReadPort, cfg.ketoWritePort, logger)
+	pa := initPolicyAgent(cfg, logger)
+	pa = cachePolicyAgent(pa, cfg, logger)
+
+	t, signing := newTokenizer(cfg, logger)
 
-	svc := newService(db, dbTracer, cfg.secret, logger, readerConn, writerConn)
+	templates := loadPolicyTemplates(cfg.templates, logger)
+
+	svc := newService(db, dbTracer, t, logger, pa, templates)
+	svc = eventStoreService(svc, cfg, logger)
+	svc = rateLimitService(svc, cfg, logger)
 	errs := make(chan error, 2)
 
-	go startHTTPServer(tracer, svc, cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
-	go startGRPCServer(tracer, svc, cfg.grpcPort, cfg.serverCert, cfg.serverKey, logger, errs)
+	mux := httpapi.MakeHandler(svc, tracer)
+	var oidcSvc oidc.Service
+	if cfg.oidcIssuer != "" {
+		oidcSvc = newOIDCService(db, dbTracer, svc, signing, cfg, logger)
+		mux = oidcapi.MakeHandler(oidcSvc, mux, tracer)
+	}
+
+	go startHTTPServer(mux, cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
+	go startGRPCServer(tracer, svc, oidcSvc, cfg.grpcPort, cfg.serverCert, cfg.serverKey, cfg.clientCACerts, logger, errs)
+	go revokeExpiredMemberships(svc, cfg.revokePeriod, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -142,18 +277,73 @@ func loadConfig() config {
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
 	}
 
+	cacheTTL, err := time.ParseDuration(mainflux.Env(envCacheTTL, defCacheTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheTTL, err.Error())
+	}
+
+	cacheSize, err := strconv.Atoi(mainflux.Env(envCacheSize, defCacheSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheSize, err.Error())
+	}
+
+	revokePeriod, err := time.ParseDuration(mainflux.Env(envRevokePeriod, defRevokePeriod))
+	if err != nil || revokePeriod <= 0 {
+		log.Fatalf("Invalid %s value: %s", envRevokePeriod, mainflux.Env(envRevokePeriod, defRevokePeriod))
+	}
+
+	rateLimitIP, err := strconv.Atoi(mainflux.Env(envRateLimitIP, defRateLimitIP))
+	if err != nil || rateLimitIP < 0 {
+		log.Fatalf("Invalid %s value: %s", envRateLimitIP, mainflux.Env(envRateLimitIP, defRateLimitIP))
+	}
+
+	rateLimitAcc, err := strconv.Atoi(mainflux.Env(envRateLimitAcc, defRateLimitAcc))
+	if err != nil || rateLimitAcc < 0 {
+		log.Fatalf("Invalid %s value: %s", envRateLimitAcc, mainflux.Env(envRateLimitAcc, defRateLimitAcc))
+	}
+
 	return config{
 		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		i18nDir:       mainflux.Env(envI18nDir, defI18nDir),
 		dbConfig:      dbConfig,
 		httpPort:      mainflux.Env(envHTTPPort, defHTTPPort),
 		grpcPort:      mainflux.Env(envGRPCPort, defGRPCPort),
 		secret:        mainflux.Env(envSecret, defSecret),
 		serverCert:    mainflux.Env(envServerCert, defServerCert),
 		serverKey:     mainflux.Env(envServerKey, defServerKey),
+		clientCACerts: mainflux.Env(envClientCACerts, defClientCACerts),
 		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
 		ketoHost:      mainflux.Env(envKetoHost, defKetoHost),
 		ketoReadPort:  mainflux.Env(envKetoReadPort, defKetoReadPort),
 		ketoWritePort: mainflux.Env(envKetoWritePort, defKetoWritePort),
+		policyBackend: mainflux.Env(envPolicyBackend, defPolicyBackend),
+		opaURL:        mainflux.Env(envOPAURL, defOPAURL),
+		spiceDBURL:    mainflux.Env(envSpiceDBURL, defSpiceDBURL),
+		spiceDBKey:    mainflux.Env(envSpiceDBKey, defSpiceDBKey),
+		cache:         mainflux.Env(envCache, defCache),
+		cacheTTL:      cacheTTL,
+		cacheSize:     cacheSize,
+		cacheURL:      mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:     mainflux.Env(envCachePass, defCachePass),
+		cacheDB:       mainflux.Env(envCacheDB, defCacheDB),
+		esURL:         mainflux.Env(envESURL, defESURL),
+		esPass:        mainflux.Env(envESPass, defESPass),
+		esDB:          mainflux.Env(envESDB, defESDB),
+		revokePeriod:  revokePeriod,
+		jwtAlg:        mainflux.Env(envJWTAlg, defJWTAlg),
+		jwtKeyID:      mainflux.Env(envJWTKeyID, defJWTKeyID),
+		jwtKey:        mainflux.Env(envJWTKey, defJWTKey),
+		jwtVerifyKeys: mainflux.Env(envJWTVerifyKeys, defJWTVerifyKeys),
+		vaultHost:     mainflux.Env(envVaultHost, defVaultHost),
+		vaultToken:    mainflux.Env(envVaultToken, defVaultToken),
+		oidcIssuer:    mainflux.Env(envOIDCIssuer, defOIDCIssuer),
+		oidcLoginURL:  mainflux.Env(envOIDCLoginURL, defOIDCLoginURL),
+		oidcClients:   mainflux.Env(envOIDCClients, defOIDCClients),
+		oidcTrusted:   mainflux.Env(envOIDCTrusted, defOIDCTrusted),
+		templates:     mainflux.Env(envTemplates, defTemplates),
+		rateLimitIP:   rateLimitIP,
+		rateLimitAcc:  rateLimitAcc,
+		rateLimitStr:  mainflux.Env(envRateLimitStr, defRateLimitStr),
 	}
 
 }
@@ -182,6 +372,85 @@ func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer,
 	return tracer, closer
 }
 
+func initPolicyAgent(cfg config, logger logger.Logger) auth.PolicyAgent {
+	switch cfg.policyBackend {
+	case ketoBackend:
+		readerConn, writerConn := initKeto(cfg.ketoHost, cfg.ketoReadPort, cfg.ketoWritePort, logger)
+		return keto.NewPolicyAgent(acl.NewCheckServiceClient(readerConn), acl.NewWriteServiceClient(writerConn), acl.NewReadServiceClient(readerConn))
+	case opaBackend:
+		return opa.NewPolicyAgent(&http.Client{Timeout: opaTimeout}, cfg.opaURL)
+	case spiceDBBackend:
+		client := &http.Client{Timeout: spiceDBTimeout}
+		if err := spicedb.WriteSchema(context.Background(), client, cfg.spiceDBURL, cfg.spiceDBKey); err != nil {
+			logger.Error(fmt.Sprintf("Failed to write SpiceDB schema: %s", err))
+			os.Exit(1)
+		}
+		return spicedb.NewPolicyAgent(client, cfg.spiceDBURL, cfg.spiceDBKey)
+	default:
+		logger.Error(fmt.Sprintf("Unknown policy backend %s, expected %s, %s or %s", cfg.policyBackend, ketoBackend, opaBackend, spiceDBBackend))
+		os.Exit(1)
+		return nil
+	}
+}
+
+func cachePolicyAgent(pa auth.PolicyAgent, cfg config, logger logger.Logger) auth.PolicyAgent {
+	switch cfg.cache {
+	case "":
+		return pa
+	case memoryCache:
+		return cache.NewPolicyAgent(pa, cache.NewMemoryStore(cfg.cacheSize, cfg.cacheTTL))
+	case redisCache:
+		client := connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger)
+		return cache.NewPolicyAgent(pa, cache.NewRedisStore(client, cfg.cacheTTL))
+	default:
+		logger.Error(fmt.Sprintf("Unknown policy cache %s, expected %s or %s", cfg.cache, memoryCache, redisCache))
+		os.Exit(1)
+		return nil
+	}
+}
+
+func rateLimitService(svc auth.Service, cfg config, logger logger.Logger) auth.Service {
+	if cfg.rateLimitIP == 0 && cfg.rateLimitAcc == 0 {
+		return svc
+	}
+
+	var store ratelimit.Store
+	switch cfg.rateLimitStr {
+	case memoryStore:
+		store = ratelimit.NewMemoryStore()
+	case redisStore:
+		store = ratelimit.NewRedisStore(connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger))
+	default:
+		logger.Error(fmt.Sprintf("Unknown rate limit store %s, expected %s or %s", cfg.rateLimitStr, memoryStore, redisStore))
+		os.Exit(1)
+	}
+
+	limiter := ratelimit.NewLimiter(store, "issue", ratelimit.PerMinute(cfg.rateLimitIP), ratelimit.PerMinute(cfg.rateLimitAcc))
+	return api.RateLimitMiddleware(svc, limiter)
+}
+
+func eventStoreService(svc auth.Service, cfg config, logger logger.Logger) auth.Service {
+	if cfg.esURL == "" {
+		return svc
+	}
+
+	client := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	return authredis.NewEventStoreMiddleware(svc, client)
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to Redis: %s", err))
+		os.Exit(1)
+	}
+	return redis.NewClient(&redis.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
+
 func initKeto(hostAddress, readPort, writePort string, logger logger.Logger) (readerConnection, writerConnection *grpc.ClientConn) {
 	checkConn, err := grpc.Dial(fmt.Sprintf("%s:%s", hostAddress, readPort), grpc.WithInsecure())
 	if err != nil {
@@ -207,19 +476,114 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 	return db
 }
 
-func newService(db *sqlx.DB, tracer opentracing.Tracer, secret string, logger logger.Logger, readerConn, writerConn *grpc.ClientConn) auth.Service {
+func newTokenizer(cfg config, logger logger.Logger) (auth.Tokenizer, jwt.Key) {
+	load := func(id, alg, path string) (jwt.Key, error) {
+		return jwt.LoadKey(id, alg, path)
+	}
+	if cfg.vaultHost != "" {
+		client, err := vault.NewClient(&vault.Config{Address: cfg.vaultHost})
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create Vault client: %s", err))
+			os.Exit(1)
+		}
+		client.SetToken(cfg.vaultToken)
+		load = func(id, alg, path string) (jwt.Key, error) {
+			return jwt.ReadVaultKey(client, id, alg, path)
+		}
+	}
+
+	// The HMAC secret is used unless the signing key is provided.
+	signing, err := jwt.ParseKey(cfg.jwtKeyID, cfg.jwtAlg, []byte(cfg.secret))
+	if cfg.jwtKey != "" {
+		signing, err = load(cfg.jwtKeyID, cfg.jwtAlg, cfg.jwtKey)
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load JWT signing key: %s", err))
+		os.Exit(1)
+	}
+
+	// Verification keys are specified as <kid>:<algorithm>:<path>.
+	var keys []jwt.Key
+	for _, v := range strings.Split(cfg.jwtVerifyKeys, ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		parts := strings.SplitN(v, ":", 3)
+		if len(parts) != 3 {
+			logger.Error(fmt.Sprintf("Invalid %s value: %s", envJWTVerifyKeys, v))
+			os.Exit(1)
+		}
+		key, err := load(parts[0], parts[1], parts[2])
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load JWT verification key %s: %s", v, err))
+			os.Exit(1)
+		}
+		keys = append(keys, key)
+	}
+
+	t, err := jwt.NewWithKeys(signing, keys...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create JWT tokenizer: %s", err))
+		os.Exit(1)
+	}
+	return t, signing
+}
+
+func loadPolicyTemplates(path string, logger logger.Logger) auth.PolicyTemplates {
+	if path == "" {
+		return auth.DefaultPolicyTemplates()
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to read policy templates: %s", err))
+		os.Exit(1)
+	}
+	templates, err := auth.ParsePolicyTemplates(data)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to parse policy templates: %s", err))
+		os.Exit(1)
+	}
+	return templates
+}
+
+func newService(db *sqlx.DB, tracer opentracing.Tracer, t auth.Tokenizer, logger logger.Logger, pa auth.PolicyAgent, templates auth.PolicyTemplates) auth.Service {
 	database := postgres.NewDatabase(db)
 	keysRepo := tracing.New(postgres.New(database), tracer)
 
 	groupsRepo := postgres.NewGroupRepo(database)
 	groupsRepo = tracing.GroupRepositoryMiddleware(tracer, groupsRepo)
 
-	pa := keto.NewPolicyAgent(acl.NewCheckServiceClient(readerConn), acl.NewWriteServiceClient(writerConn), acl.NewReadServiceClient(readerConn))
+	orgsRepo := postgres.NewOrgRepo(database)
+	orgsRepo = tracing.OrgRepositoryMiddleware(tracer, orgsRepo)
+
+	sharesRepo := postgres.NewShareRepo(database)
+	sharesRepo = tracing.ShareRepositoryMiddleware(tracer, sharesRepo)
+
+	rolesRepo := postgres.NewRoleRepo(database)
+	rolesRepo = tracing.RoleRepositoryMiddleware(tracer, rolesRepo)
+
+	accountsRepo := postgres.NewServiceAccountRepo(database)
+	accountsRepo = tracing.ServiceAccountRepositoryMiddleware(tracer, accountsRepo)
+
+	quotasRepo := postgres.NewQuotaRepo(database)
+	quotasRepo = tracing.QuotaRepositoryMiddleware(tracer, quotasRepo)
+
+	expirationsRepo := postgres.NewPolicyExpirationRepo(database)
+	expirationsRepo = tracing.PolicyExpirationRepositoryMiddleware(tracer, expirationsRepo)
+
+	revocationsRepo := postgres.NewRevocationRepo(database)
+	revocationsRepo = tracing.RevocationRepositoryMiddleware(tracer, revocationsRepo)
+
+	sessionsRepo := postgres.NewSessionRepo(database)
+	sessionsRepo = tracing.SessionRepositoryMiddleware(tracer, sessionsRepo)
+
+	auditRepo := postgres.NewAuditRepo(database)
+	auditRepo = tracing.AuditRepositoryMiddleware(tracer, auditRepo)
 
 	idProvider := uuid.New()
-	t := jwt.New(secret)
 
-	svc := auth.New(keysRepo, groupsRepo, idProvider, t, pa)
+	svc := auth.New(keysRepo, groupsRepo, orgsRepo, sharesRepo, rolesRepo, accountsRepo, quotasRepo, expirationsRepo, revocationsRepo, sessionsRepo, auditRepo, idProvider, t, pa, templates)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -240,19 +604,77 @@ func newService(db *sqlx.DB, tracer opentracing.Tracer, secret string, logger lo
 	return svc
 }
 
-func startHTTPServer(tracer opentracing.Tracer, svc auth.Service, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
+func newOIDCService(db *sqlx.DB, tracer opentracing.Tracer, authn auth.Authn, signing jwt.Key, cfg config, logger logger.Logger) oidc.Service {
+	clients, err := oidc.ParseClients(cfg.oidcClients)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Invalid %s value: %s", envOIDCClients, err))
+		os.Exit(1)
+	}
+	if cfg.oidcLoginURL == "" && len(clients) > 0 {
+		logger.Error(fmt.Sprintf("%s is required by the OpenID Connect provider", envOIDCLoginURL))
+		os.Exit(1)
+	}
+	issuers, err := oidc.ParseTrustedIssuers(cfg.oidcTrusted)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Invalid %s value: %s", envOIDCTrusted, err))
+		os.Exit(1)
+	}
+
+	// The ID tokens are verified by the clients using the published JWKS,
+	// so the signing key must be asymmetric.
+	signer, err := jwt.NewIDTokenSigner(signing)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create ID token signer: %s", err))
+		os.Exit(1)
+	}
+
+	codesRepo := postgres.NewCodeRepo(postgres.NewDatabase(db))
+	codesRepo = tracing.CodeRepositoryMiddleware(tracer, codesRepo)
+
+	linksRepo := postgres.NewLinkRepo(postgres.NewDatabase(db))
+	linksRepo = tracing.LinkRepositoryMiddleware(tracer, linksRepo)
+
+	revocationsRepo := postgres.NewRevocationRepo(postgres.NewDatabase(db))
+	revocationsRepo = tracing.RevocationRepositoryMiddleware(tracer, revocationsRepo)
+
+	// The token exchange is enabled only if there are trusted issuers.
+	var verifier oidc.TokenVerifier
+	if len(issuers) > 0 {
+		verifier = jwt.NewExternalVerifier(&http.Client{Timeout: 10 * time.Second}, issuers)
+	}
+
+	oc := oidc.Config{
+		Issuer:   cfg.oidcIssuer,
+		LoginURL: cfg.oidcLoginURL,
+		Clients:  clients,
+	}
+	return oidc.New(oc, authn, codesRepo, signer, linksRepo, revocationsRepo, verifier)
+}
+
+func revokeExpiredMemberships(svc auth.Service, period time.Duration, logger logger.Logger) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := svc.RevokeExpiredMemberships(context.Background()); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to revoke expired group memberships: %s", err))
+		}
+	}
+}
+
+func startHTTPServer(handler http.Handler, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	if certFile != "" || keyFile != "" {
 		logger.Info(fmt.Sprintf("Authentication service started using https, cert %s key %s, exposed port %s", certFile, keyFile, port))
-		errs <- http.ListenAndServeTLS(p, certFile, keyFile, httpapi.MakeHandler(svc, tracer))
+		errs <- http.ListenAndServeTLS(p, certFile, keyFile, handler)
 		return
 	}
 	logger.Info(fmt.Sprintf("Authentication service started using http, exposed port %s", port))
-	errs <- http.ListenAndServe(p, httpapi.MakeHandler(svc, tracer))
+	errs <- http.ListenAndServe(p, handler)
 
 }
 
-func startGRPCServer(tracer opentracing.Tracer, svc auth.Service, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
+func startGRPCServer(tracer opentracing.Tracer, svc auth.Service, oidcSvc oidc.Service, port string, certFile string, keyFile string, clientCACerts string, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	listener, err := net.Listen("tcp", p)
 	if err != nil {
@@ -261,19 +683,26 @@ func startGRPCServer(tracer opentracing.Tracer, svc auth.Service, port string, c
 
 	var server *grpc.Server
 	if certFile != "" || keyFile != "" {
-		creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+		creds, err := grpcapi.ServerCredentials(certFile, keyFile, clientCACerts)
 		if err != nil {
 			logger.Error(fmt.Sprintf("Failed to load auth certificates: %s", err))
 			os.Exit(1)
 		}
+		if clientCACerts != "" {
+			logger.Info(fmt.Sprintf("Authentication gRPC service requires client certificates signed by %s", clientCACerts))
+		}
 		logger.Info(fmt.Sprintf("Authentication gRPC service started using https on port %s with cert %s key %s", port, certFile, keyFile))
 		server = grpc.NewServer(grpc.Creds(creds))
 	} else {
+		if clientCACerts != "" {
+			logger.Error("Failed to require client certificates: server certificate and key are not set")
+			os.Exit(1)
+		}
 		logger.Info(fmt.Sprintf("Authentication gRPC service started using http on port %s", port))
 		server = grpc.NewServer()
 	}
 
-	mainflux.RegisterAuthServiceServer(server, grpcapi.NewServer(tracer, svc))
+	mainflux.RegisterAuthServiceServer(server, grpcapi.NewServer(tracer, svc, oidcSvc))
 	logger.Info(fmt.Sprintf("Authentication gRPC service started, exposed port %s", port))
 	errs <- server.Serve(listener)
 }
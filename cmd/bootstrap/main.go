@@ -5,6 +5,7 @@ package main
 import (
 	"context"
 	"crypto/aes"
+	"crypto/tls"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -35,7 +36,6 @@ import (
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
@@ -52,6 +52,8 @@ const (
 	defEncryptKey     = "12345678910111213141516171819202"
 	defClientTLS      = "false"
 	defCACerts        = ""
+	defClientCert     = ""
+	defClientKey      = ""
 	defPort           = "8180"
 	defServerCert     = ""
 	defServerKey      = ""
@@ -81,6 +83,8 @@ const (
 	envEncryptKey     = "MF_BOOTSTRAP_ENCRYPT_KEY"
 	envClientTLS      = "MF_BOOTSTRAP_CLIENT_TLS"
 	envCACerts        = "MF_BOOTSTRAP_CA_CERTS"
+	envClientCert     = "MF_AUTH_CLIENT_CERT"
+	envClientKey      = "MF_AUTH_CLIENT_KEY"
 	envPort           = "MF_BOOTSTRAP_PORT"
 	envServerCert     = "MF_BOOTSTRAP_SERVER_CERT"
 	envServerKey      = "MF_BOOTSTRAP_SERVER_KEY"
@@ -104,6 +108,8 @@ type config struct {
 	clientTLS      bool
 	encKey         []byte
 	caCerts        string
+	clientCert     string
+	clientKey      string
 	httpPort       string
 	serverCert     string
 	serverKey      string
@@ -124,7 +130,7 @@ type config struct {
 func main() {
 	cfg := loadConfig()
 
-	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	logger, err := mflog.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -200,6 +206,8 @@ func loadConfig() config {
 		clientTLS:      tls,
 		encKey:         encKey,
 		caCerts:        mainflux.Env(envCACerts, defCACerts),
+		clientCert:     mainflux.Env(envClientCert, defClientCert),
+		clientKey:      mainflux.Env(envClientKey, defClientKey),
 		httpPort:       mainflux.Env(envPort, defPort),
 		serverCert:     mainflux.Env(envServerCert, defServerCert),
 		serverKey:      mainflux.Env(envServerKey, defServerKey),
@@ -298,14 +306,12 @@ func newService(auth mainflux.AuthServiceClient, db *sqlx.DB, logger mflog.Logge
 func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := authapi.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 		logger.Info("gRPC communication is not encrypted")
@@ -325,7 +331,14 @@ func startHTTPServer(svc bootstrap.Service, cfg config, logger mflog.Logger, err
 	if cfg.serverCert != "" || cfg.serverKey != "" {
 		logger.Info(fmt.Sprintf("Bootstrap service started using https on port %s with cert %s key %s",
 			cfg.httpPort, cfg.serverCert, cfg.serverKey))
-		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(svc, bootstrap.NewConfigReader(cfg.encKey)))
+		// Client certificates are requested, but not verified, so the Things
+		// can bootstrap using the certificate mapped to their Config.
+		server := &http.Server{
+			Addr:      p,
+			Handler:   api.MakeHandler(svc, bootstrap.NewConfigReader(cfg.encKey)),
+			TLSConfig: &tls.Config{ClientAuth: tls.RequestClientCert},
+		}
+		errs <- server.ListenAndServeTLS(cfg.serverCert, cfg.serverKey)
 		return
 	}
 	logger.Info(fmt.Sprintf("Bootstrap service started using http on port %s", cfg.httpPort))
@@ -17,12 +17,14 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
 	"github.com/gocql/gocql"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/cassandra"
+	rediscache "github.com/mainflux/mainflux/readers/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -48,6 +50,11 @@ const (
 	defJaegerURL         = ""
 	defThingsAuthURL     = "localhost:8181"
 	defThingsAuthTimeout = "1s"
+	defCacheURL          = ""
+	defCachePass         = ""
+	defCacheDB           = "0"
+	defCacheTTL          = "5s"
+	defCacheBucket       = "1s"
 
 	envLogLevel          = "MF_CASSANDRA_READER_LOG_LEVEL"
 	envPort              = "MF_CASSANDRA_READER_PORT"
@@ -63,6 +70,11 @@ const (
 	envJaegerURL         = "MF_JAEGER_URL"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envCacheURL          = "MF_CASSANDRA_READER_CACHE_URL"
+	envCachePass         = "MF_CASSANDRA_READER_CACHE_PASS"
+	envCacheDB           = "MF_CASSANDRA_READER_CACHE_DB"
+	envCacheTTL          = "MF_CASSANDRA_READER_CACHE_TTL"
+	envCacheBucket       = "MF_CASSANDRA_READER_CACHE_BUCKET"
 )
 
 type config struct {
@@ -76,12 +88,17 @@ type config struct {
 	jaegerURL         string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	cacheURL          string
+	cachePass         string
+	cacheDB           string
+	cacheTTL          time.Duration
+	cacheBucket       time.Duration
 }
 
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -98,6 +115,13 @@ func main() {
 	tc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsAuthTimeout)
 	repo := newService(session, logger)
 
+	if cfg.cacheURL != "" {
+		cacheClient := connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger)
+		defer cacheClient.Close()
+
+		repo = rediscache.NewCachedRepository(cacheClient, repo, cfg.cacheTTL, cfg.cacheBucket)
+	}
+
 	errs := make(chan error, 2)
 
 	go startHTTPServer(repo, tc, cfg, errs, logger)
@@ -136,6 +160,16 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	cacheTTL, err := time.ParseDuration(mainflux.Env(envCacheTTL, defCacheTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheTTL, err.Error())
+	}
+
+	cacheBucket, err := time.ParseDuration(mainflux.Env(envCacheBucket, defCacheBucket))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheBucket, err.Error())
+	}
+
 	return config{
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		port:              mainflux.Env(envPort, defPort),
@@ -147,6 +181,11 @@ func loadConfig() config {
 		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: authTimeout,
+		cacheURL:          mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:         mainflux.Env(envCachePass, defCachePass),
+		cacheDB:           mainflux.Env(envCacheDB, defCacheDB),
+		cacheTTL:          cacheTTL,
+		cacheBucket:       cacheBucket,
 	}
 }
 
@@ -208,6 +247,20 @@ func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer,
 	return tracer, closer
 }
 
+func connectToRedis(cacheURL, cachePass, cacheDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:     cacheURL,
+		Password: cachePass,
+		DB:       db,
+	})
+}
+
 func newService(session *gocql.Session, logger logger.Logger) readers.MessageRepository {
 	repo := cassandra.New(session)
 	repo = api.LoggingMiddleware(repo, logger)
@@ -235,9 +288,9 @@ func startHTTPServer(repo readers.MessageRepository, tc mainflux.ThingsServiceCl
 	if cfg.serverCert != "" || cfg.serverKey != "" {
 		logger.Info(fmt.Sprintf("Cassandra reader service started using https on port %s with cert %s key %s",
 			cfg.port, cfg.serverCert, cfg.serverKey))
-		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(repo, tc, "cassandra-reader"))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(repo, tc, nil, nil, "cassandra-reader"))
 		return
 	}
 	logger.Info(fmt.Sprintf("Cassandra reader service started, exposed port %s", cfg.port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, "cassandra-reader"))
+	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, nil, nil, "cassandra-reader"))
 }
@@ -4,6 +4,8 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,55 +14,80 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis/v8"
 	"github.com/gocql/gocql"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/redis"
 	"github.com/mainflux/mainflux/consumers/writers/api"
 	"github.com/mainflux/mainflux/consumers/writers/cassandra"
+	"github.com/mainflux/mainflux/internal/chaos"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 )
 
 const (
-	svcName = "cassandra-writer"
-	sep     = ","
-
-	defNatsURL    = "nats://localhost:4222"
-	defLogLevel   = "error"
-	defPort       = "8180"
-	defCluster    = "127.0.0.1"
-	defKeyspace   = "mainflux"
-	defDBUser     = "mainflux"
-	defDBPass     = "mainflux"
-	defDBPort     = "9042"
-	defConfigPath = "/config.toml"
-
-	envNatsURL    = "MF_NATS_URL"
-	envLogLevel   = "MF_CASSANDRA_WRITER_LOG_LEVEL"
-	envPort       = "MF_CASSANDRA_WRITER_PORT"
-	envCluster    = "MF_CASSANDRA_WRITER_DB_CLUSTER"
-	envKeyspace   = "MF_CASSANDRA_WRITER_DB_KEYSPACE"
-	envDBUser     = "MF_CASSANDRA_WRITER_DB_USER"
-	envDBPass     = "MF_CASSANDRA_WRITER_DB_PASS"
-	envDBPort     = "MF_CASSANDRA_WRITER_DB_PORT"
-	envConfigPath = "MF_CASSANDRA_WRITER_CONFIG_PATH"
+	svcName     = "cassandra-writer"
+	chaosPrefix = "MF_CASSANDRA_WRITER_"
+	sep         = ","
+
+	defNatsURL      = "nats://localhost:4222"
+	defLogLevel     = "error"
+	defPort         = "8180"
+	defCluster      = "127.0.0.1"
+	defKeyspace     = "mainflux"
+	defDBUser       = "mainflux"
+	defDBPass       = "mainflux"
+	defDBPort       = "9042"
+	defConfigPath   = "/config.toml"
+	defESURL        = ""
+	defESPass       = ""
+	defESDB         = "0"
+	defBackfillRate = "100"
+	defBackfillIdle = "5s"
+
+	envNatsURL      = "MF_NATS_URL"
+	envLogLevel     = "MF_CASSANDRA_WRITER_LOG_LEVEL"
+	envPort         = "MF_CASSANDRA_WRITER_PORT"
+	envCluster      = "MF_CASSANDRA_WRITER_DB_CLUSTER"
+	envKeyspace     = "MF_CASSANDRA_WRITER_DB_KEYSPACE"
+	envDBUser       = "MF_CASSANDRA_WRITER_DB_USER"
+	envDBPass       = "MF_CASSANDRA_WRITER_DB_PASS"
+	envDBPort       = "MF_CASSANDRA_WRITER_DB_PORT"
+	envConfigPath   = "MF_CASSANDRA_WRITER_CONFIG_PATH"
+	envESURL        = "MF_THINGS_ES_URL"
+	envESPass       = "MF_THINGS_ES_PASS"
+	envESDB         = "MF_THINGS_ES_DB"
+	envBackfillRate = "MF_CASSANDRA_WRITER_BACKFILL_RATE"
+	envBackfillIdle = "MF_CASSANDRA_WRITER_BACKFILL_IDLE_TIMEOUT"
 )
 
 type config struct {
-	natsURL    string
-	logLevel   string
-	port       string
-	configPath string
-	dbCfg      cassandra.DBConfig
+	natsURL      string
+	logLevel     string
+	port         string
+	configPath   string
+	esURL        string
+	esPass       string
+	esDB         string
+	backfillRate int
+	backfillIdle time.Duration
+	dbCfg        cassandra.DBConfig
+	chaos        chaos.Settings
 }
 
 func main() {
+	backfill := flag.String("backfill", "", `backfill the messages from the NDJSON file, or from the NATS subject prefixed with "nats:", and exit`)
+	flag.Parse()
+
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -72,18 +99,35 @@ func main() {
 	}
 	defer pubSub.Close()
 
+	var sub messaging.Subscriber = pubSub
+	var inj *chaos.Injector
+	if cfg.chaos.Enabled {
+		logger.Warn("Fault injection is enabled")
+		inj = chaos.NewInjector(cfg.chaos.Config)
+		sub = chaos.NewPubSub(inj, pubSub)
+	}
+
 	session := connectToCassandra(cfg.dbCfg, logger)
 	defer session.Close()
 
 	repo := newService(session, logger)
 
-	if err := consumers.Start(pubSub, repo, cfg.configPath, logger); err != nil {
+	if *backfill != "" {
+		runBackfill(*backfill, pubSub, repo, cfg, logger)
+		return
+	}
+
+	if err := consumers.Start(sub, repo, newChannelSource(cfg, logger), cfg.configPath, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Cassandra writer: %s", err))
 	}
 
 	errs := make(chan error, 2)
 
-	go startHTTPServer(cfg.port, errs, logger)
+	handler := api.MakeHandler(svcName)
+	if inj != nil {
+		handler = chaos.Wrap(inj, cfg.chaos.AdminToken, handler)
+	}
+	go startHTTPServer(cfg.port, handler, errs, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -109,12 +153,33 @@ func loadConfig() config {
 		Port:     dbPort,
 	}
 
+	backfillRate, err := strconv.Atoi(mainflux.Env(envBackfillRate, defBackfillRate))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackfillRate, err.Error())
+	}
+
+	backfillIdle, err := time.ParseDuration(mainflux.Env(envBackfillIdle, defBackfillIdle))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackfillIdle, err.Error())
+	}
+
+	chaosSettings, err := chaos.LoadSettings(chaosPrefix)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
 	return config{
-		natsURL:    mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
-		port:       mainflux.Env(envPort, defPort),
-		configPath: mainflux.Env(envConfigPath, defConfigPath),
-		dbCfg:      dbCfg,
+		natsURL:      mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		port:         mainflux.Env(envPort, defPort),
+		configPath:   mainflux.Env(envConfigPath, defConfigPath),
+		esURL:        mainflux.Env(envESURL, defESURL),
+		esPass:       mainflux.Env(envESPass, defESPass),
+		esDB:         mainflux.Env(envESDB, defESDB),
+		backfillRate: backfillRate,
+		backfillIdle: backfillIdle,
+		chaos:        chaosSettings,
+		dbCfg:        dbCfg,
 	}
 }
 
@@ -150,8 +215,54 @@ func newService(session *gocql.Session, logger logger.Logger) consumers.Consumer
 	return repo
 }
 
-func startHTTPServer(port string, errs chan error, logger logger.Logger) {
+func startHTTPServer(port string, handler http.Handler, errs chan error, logger logger.Logger) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("Cassandra writer service started, exposed port %s", port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
+	errs <- http.ListenAndServe(p, handler)
+}
+
+// runBackfill consumes the messages read from the backfill source through the
+// same transformation and storage path as the live messages.
+func runBackfill(source string, sub messaging.Subscriber, repo consumers.Consumer, cfg config, logger logger.Logger) {
+	src, err := consumers.NewSource(sub, source, cfg.backfillIdle)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to open backfill source: %s", err))
+		os.Exit(1)
+	}
+	defer src.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		<-c
+		cancel()
+	}()
+
+	n, err := consumers.Backfill(ctx, src, repo, cfg.configPath, cfg.backfillRate, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Backfill stopped after %d messages: %s", n, err))
+		return
+	}
+	logger.Info(fmt.Sprintf("Backfilled %d messages", n))
+}
+
+func newChannelSource(cfg config, logger logger.Logger) consumers.ChannelSource {
+	if cfg.esURL == "" {
+		return nil
+	}
+
+	db, err := strconv.Atoi(cfg.esDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things event store: %s", err))
+		os.Exit(1)
+	}
+
+	client := r.NewClient(&r.Options{
+		Addr:     cfg.esURL,
+		Password: cfg.esPass,
+		DB:       db,
+	})
+	return redis.NewChannelSource(client)
 }
@@ -28,7 +28,6 @@ import (
 	"github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 
 	"github.com/jmoiron/sqlx"
 	mflog "github.com/mainflux/mainflux/logger"
@@ -50,6 +49,8 @@ const (
 	defDBSSLRootCert = ""
 	defClientTLS     = "false"
 	defCACerts       = ""
+	defClientCert    = ""
+	defClientKey     = ""
 	defPort          = "8204"
 	defServerCert    = ""
 	defServerKey     = ""
@@ -83,6 +84,8 @@ const (
 	envEncryptKey    = "MF_CERTS_ENCRYPT_KEY"
 	envClientTLS     = "MF_CERTS_CLIENT_TLS"
 	envCACerts       = "MF_CERTS_CA_CERTS"
+	envClientCert    = "MF_AUTH_CLIENT_CERT"
+	envClientKey     = "MF_AUTH_CLIENT_KEY"
 	envServerCert    = "MF_CERTS_SERVER_CERT"
 	envServerKey     = "MF_CERTS_SERVER_KEY"
 	envCertsURL      = "MF_SDK_CERTS_URL"
@@ -118,6 +121,8 @@ type config struct {
 	clientTLS   bool
 	encKey      []byte
 	caCerts     string
+	clientCert  string
+	clientKey   string
 	httpPort    string
 	serverCert  string
 	serverKey   string
@@ -141,7 +146,7 @@ type config struct {
 func main() {
 	cfg := loadConfig()
 
-	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	logger, err := mflog.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -218,6 +223,8 @@ func loadConfig() config {
 		dbConfig:    dbConfig,
 		clientTLS:   tls,
 		caCerts:     mainflux.Env(envCACerts, defCACerts),
+		clientCert:  mainflux.Env(envClientCert, defClientCert),
+		clientKey:   mainflux.Env(envClientKey, defClientKey),
 		httpPort:    mainflux.Env(envPort, defPort),
 		serverCert:  mainflux.Env(envServerCert, defServerCert),
 		serverKey:   mainflux.Env(envServerKey, defServerKey),
@@ -266,14 +273,12 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := authapi.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 		logger.Info("gRPC communication is not encrypted")
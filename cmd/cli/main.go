@@ -50,6 +50,7 @@ func main() {
 	provisionCmd := cli.NewProvisionCmd()
 	bootstrapCmd := cli.NewBootstrapCmd()
 	certsCmd := cli.NewCertsCmd()
+	shellCmd := cli.NewShellCmd()
 
 	// Root Commands
 	rootCmd.AddCommand(versionCmd)
@@ -61,6 +62,7 @@ func main() {
 	rootCmd.AddCommand(provisionCmd)
 	rootCmd.AddCommand(bootstrapCmd)
 	rootCmd.AddCommand(certsCmd)
+	rootCmd.AddCommand(shellCmd)
 
 	// Root Flags
 	rootCmd.PersistentFlags().StringVarP(
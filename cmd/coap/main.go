@@ -19,6 +19,8 @@ import (
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/coap"
 	"github.com/mainflux/mainflux/coap/api"
+	"github.com/mainflux/mainflux/internal/lineage"
+	"github.com/mainflux/mainflux/internal/payload"
 	logger "github.com/mainflux/mainflux/logger"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	broker "github.com/nats-io/nats.go"
@@ -33,6 +35,7 @@ import (
 const (
 	defPort              = "5683"
 	defNatsURL           = "nats://localhost:4222"
+	defNatsPartitions    = "0"
 	defLogLevel          = "error"
 	defClientTLS         = "false"
 	defCACerts           = ""
@@ -42,6 +45,7 @@ const (
 
 	envPort              = "MF_COAP_ADAPTER_PORT"
 	envNatsURL           = "MF_NATS_URL"
+	envNatsPartitions    = "MF_NATS_PARTITIONS"
 	envLogLevel          = "MF_COAP_ADAPTER_LOG_LEVEL"
 	envClientTLS         = "MF_COAP_ADAPTER_CLIENT_TLS"
 	envCACerts           = "MF_COAP_ADAPTER_CA_CERTS"
@@ -53,18 +57,20 @@ const (
 type config struct {
 	port              string
 	natsURL           string
+	natsPartitions    int
 	logLevel          string
 	clientTLS         bool
 	caCerts           string
 	jaegerURL         string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	payloads          payload.Policies
 }
 
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -83,7 +89,16 @@ func main() {
 	}
 	defer nc.Close()
 
-	svc := coap.New(tc, nc)
+	payloads := payload.MetricsMiddleware(
+		payload.NewChecker(cfg.payloads),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "coap_adapter",
+			Subsystem: "payload",
+			Name:      "rejected_count",
+			Help:      "Number of messages rejected by the payload policies.",
+		}, []string{"protocol", "reason"}),
+	)
+	svc := coap.New(tc, nc, cfg.natsPartitions, payloads, lineage.Instance("coap-adapter"))
 
 	svc = api.LoggingMiddleware(svc, logger)
 
@@ -129,8 +144,19 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	natsPartitions, err := strconv.Atoi(mainflux.Env(envNatsPartitions, defNatsPartitions))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envNatsPartitions, err.Error())
+	}
+
+	payloads, err := payload.LoadPolicies()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
 	return config{
 		natsURL:           mainflux.Env(envNatsURL, defNatsURL),
+		natsPartitions:    natsPartitions,
 		port:              mainflux.Env(envPort, defPort),
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		clientTLS:         tls,
@@ -138,6 +164,7 @@ func loadConfig() config {
 		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: authTimeout,
+		payloads:          payloads,
 	}
 }
 
@@ -0,0 +1,339 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux"
+	authapi "github.com/mainflux/mainflux/auth/api/grpc"
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/commands/api"
+	"github.com/mainflux/mainflux/commands/postgres"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	queue = "commands"
+
+	defLogLevel          = "error"
+	defHTTPPort          = "8216"
+	defJaegerURL         = ""
+	defServerCert        = ""
+	defServerKey         = ""
+	defDBHost            = "localhost"
+	defDBPort            = "5432"
+	defDBUser            = "mainflux"
+	defDBPass            = "mainflux"
+	defDB                = "commands"
+	defDBSSLMode         = "disable"
+	defDBSSLCert         = ""
+	defDBSSLKey          = ""
+	defDBSSLRootCert     = ""
+	defTimeout           = "30s"
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defClientCert        = ""
+	defClientKey         = ""
+	defNatsURL           = "nats://localhost:4222"
+	defAuthURL           = "localhost:8181"
+	defAuthTimeout       = "1s"
+	defThingsAuthURL     = "localhost:8183"
+	defThingsAuthTimeout = "1s"
+
+	envLogLevel          = "MF_COMMANDS_LOG_LEVEL"
+	envHTTPPort          = "MF_COMMANDS_HTTP_PORT"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envServerCert        = "MF_COMMANDS_SERVER_CERT"
+	envServerKey         = "MF_COMMANDS_SERVER_KEY"
+	envDBHost            = "MF_COMMANDS_DB_HOST"
+	envDBPort            = "MF_COMMANDS_DB_PORT"
+	envDBUser            = "MF_COMMANDS_DB_USER"
+	envDBPass            = "MF_COMMANDS_DB_PASS"
+	envDB                = "MF_COMMANDS_DB"
+	envDBSSLMode         = "MF_COMMANDS_DB_SSL_MODE"
+	envDBSSLCert         = "MF_COMMANDS_DB_SSL_CERT"
+	envDBSSLKey          = "MF_COMMANDS_DB_SSL_KEY"
+	envDBSSLRootCert     = "MF_COMMANDS_DB_SSL_ROOT_CERT"
+	envTimeout           = "MF_COMMANDS_TIMEOUT"
+	envClientTLS         = "MF_COMMANDS_CLIENT_TLS"
+	envCACerts           = "MF_COMMANDS_CA_CERTS"
+	envClientCert        = "MF_AUTH_CLIENT_CERT"
+	envClientKey         = "MF_AUTH_CLIENT_KEY"
+	envNatsURL           = "MF_NATS_URL"
+	envAuthURL           = "MF_AUTH_GRPC_URL"
+	envAuthTimeout       = "MF_AUTH_GRPC_TIMEOUT"
+	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
+	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+)
+
+type config struct {
+	logLevel          string
+	httpPort          string
+	jaegerURL         string
+	serverCert        string
+	serverKey         string
+	dbConfig          postgres.Config
+	timeout           time.Duration
+	clientTLS         bool
+	caCerts           string
+	clientCert        string
+	clientKey         string
+	natsURL           string
+	authURL           string
+	authTimeout       time.Duration
+	thingsAuthURL     string
+	thingsAuthTimeout time.Duration
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	db := connectToDB(cfg.dbConfig, logger)
+	defer db.Close()
+
+	authTracer, authCloser := initJaeger("auth", cfg.jaegerURL, logger)
+	defer authCloser.Close()
+
+	authConn := connectToAuth(cfg, logger)
+	defer authConn.Close()
+
+	auth := authapi.NewClient(authTracer, authConn, cfg.authTimeout)
+
+	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	defer thingsCloser.Close()
+
+	thingsConn := connectToThings(cfg, logger)
+	defer thingsConn.Close()
+
+	things := thingsapi.NewClient(thingsConn, thingsTracer, cfg.thingsAuthTimeout)
+
+	pubSub, err := nats.NewPubSub(cfg.natsURL, queue, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer pubSub.Close()
+
+	svc := newService(auth, things, db, pubSub, cfg, logger)
+
+	tracer, closer := initJaeger("commands", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	errs := make(chan error, 2)
+	go startHTTPServer(api.MakeHandler(tracer, svc), cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Commands service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	timeout, err := time.ParseDuration(mainflux.Env(envTimeout, defTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envTimeout, err.Error())
+	}
+	if timeout <= 0 || timeout > commands.MaxTimeout {
+		log.Fatalf("Invalid %s value: %s", envTimeout, timeout)
+	}
+
+	authTimeout, err := time.ParseDuration(mainflux.Env(envAuthTimeout, defAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
+	}
+
+	thingsAuthTimeout, err := time.ParseDuration(mainflux.Env(envThingsAuthTimeout, defThingsAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
+	}
+
+	dbConfig := postgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, defDBPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDB, defDB),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
+	}
+
+	return config{
+		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:          mainflux.Env(envHTTPPort, defHTTPPort),
+		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
+		serverCert:        mainflux.Env(envServerCert, defServerCert),
+		serverKey:         mainflux.Env(envServerKey, defServerKey),
+		dbConfig:          dbConfig,
+		timeout:           timeout,
+		clientTLS:         tls,
+		caCerts:           mainflux.Env(envCACerts, defCACerts),
+		clientCert:        mainflux.Env(envClientCert, defClientCert),
+		clientKey:         mainflux.Env(envClientKey, defClientKey),
+		natsURL:           mainflux.Env(envNatsURL, defNatsURL),
+		authURL:           mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout:       authTimeout,
+		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
+		thingsAuthTimeout: thingsAuthTimeout,
+	}
+}
+
+func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
+	db, err := postgres.Connect(dbConfig)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+		os.Exit(1)
+	}
+	return db
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := authapi.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.authURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to auth service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.thingsAuthURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func newService(auth mainflux.AuthServiceClient, things mainflux.ThingsServiceClient, db *sqlx.DB, ps messaging.PubSub, cfg config, logger logger.Logger) commands.Service {
+	repo := postgres.NewCommandRepository(db)
+
+	svc := commands.New(auth, things, repo, ps, uuid.New(), cfg.timeout)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "commands",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "commands",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	err := ps.Subscribe(commands.AckTopic(), func(msg messaging.Message) error {
+		return svc.Ack(context.Background(), msg)
+	})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to command acknowledgements: %s", err))
+		os.Exit(1)
+	}
+
+	return svc
+}
+
+func startHTTPServer(handler http.Handler, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Commands service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
+		return
+	}
+	logger.Info(fmt.Sprintf("Commands service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, handler)
+}
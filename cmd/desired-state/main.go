@@ -0,0 +1,178 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/desiredstate"
+	"github.com/mainflux/mainflux/desiredstate/api"
+	"github.com/mainflux/mainflux/desiredstate/file"
+	"github.com/mainflux/mainflux/logger"
+	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+)
+
+const (
+	defLogLevel   = "error"
+	defHTTPPort   = "8214"
+	defJaegerURL  = ""
+	defServerCert = ""
+	defServerKey  = ""
+	defStateDir   = "/tmp/mainflux/desired-state"
+	defAuthURL    = "http://localhost:8189"
+	defUsersURL   = "http://localhost:8180"
+	defThingsURL  = "http://localhost:8182"
+	defTLSVerify  = "true"
+
+	envLogLevel   = "MF_DESIRED_STATE_LOG_LEVEL"
+	envHTTPPort   = "MF_DESIRED_STATE_HTTP_PORT"
+	envJaegerURL  = "MF_JAEGER_URL"
+	envServerCert = "MF_DESIRED_STATE_SERVER_CERT"
+	envServerKey  = "MF_DESIRED_STATE_SERVER_KEY"
+	envStateDir   = "MF_DESIRED_STATE_DIR"
+	envAuthURL    = "MF_DESIRED_STATE_AUTH_URL"
+	envUsersURL   = "MF_DESIRED_STATE_USERS_URL"
+	envThingsURL  = "MF_DESIRED_STATE_THINGS_URL"
+	envTLSVerify  = "MF_DESIRED_STATE_TLS_VERIFICATION"
+)
+
+type config struct {
+	logLevel   string
+	httpPort   string
+	jaegerURL  string
+	serverCert string
+	serverKey  string
+	stateDir   string
+	sdkConfig  mfsdk.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	state, err := file.NewStateRepository(cfg.stateDir)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create state directory: %s", err))
+		os.Exit(1)
+	}
+
+	svc := newService(state, cfg, logger)
+
+	tracer, closer := initJaeger("desired-state", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	errs := make(chan error, 2)
+	go startHTTPServer(api.MakeHandler(tracer, svc), cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Desired-state service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tlsVerify, err := strconv.ParseBool(mainflux.Env(envTLSVerify, defTLSVerify))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envTLSVerify)
+	}
+
+	sdkConfig := mfsdk.Config{
+		AuthURL:         mainflux.Env(envAuthURL, defAuthURL),
+		UsersURL:        mainflux.Env(envUsersURL, defUsersURL),
+		ThingsURL:       mainflux.Env(envThingsURL, defThingsURL),
+		MsgContentType:  mfsdk.CTJSONSenML,
+		TLSVerification: tlsVerify,
+	}
+
+	return config{
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:   mainflux.Env(envHTTPPort, defHTTPPort),
+		jaegerURL:  mainflux.Env(envJaegerURL, defJaegerURL),
+		serverCert: mainflux.Env(envServerCert, defServerCert),
+		serverKey:  mainflux.Env(envServerKey, defServerKey),
+		stateDir:   mainflux.Env(envStateDir, defStateDir),
+		sdkConfig:  sdkConfig,
+	}
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func newService(state desiredstate.StateRepository, cfg config, logger logger.Logger) desiredstate.Service {
+	sdk := mfsdk.NewSDK(cfg.sdkConfig)
+
+	svc := desiredstate.New(sdk, state)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "desired_state",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "desired_state",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(handler http.Handler, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Desired-state service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
+		return
+	}
+	logger.Info(fmt.Sprintf("Desired-state service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, handler)
+}
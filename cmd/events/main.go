@@ -0,0 +1,256 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux"
+	authapi "github.com/mainflux/mainflux/auth/api/grpc"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/events/api"
+	"github.com/mainflux/mainflux/events/redis"
+	"github.com/mainflux/mainflux/logger"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel    = "error"
+	defHTTPPort    = "8211"
+	defJaegerURL   = ""
+	defServerCert  = ""
+	defServerKey   = ""
+	defClientTLS   = "false"
+	defCACerts     = ""
+	defClientCert  = ""
+	defClientKey   = ""
+	defAuthURL     = "localhost:8181"
+	defAuthTimeout = "1s"
+	defESURL       = "localhost:6379"
+	defESPass      = ""
+	defESDB        = "0"
+	defStreams     = "mainflux.things,mainflux.bootstrap"
+
+	envLogLevel    = "MF_EVENTS_LOG_LEVEL"
+	envHTTPPort    = "MF_EVENTS_HTTP_PORT"
+	envJaegerURL   = "MF_JAEGER_URL"
+	envServerCert  = "MF_EVENTS_SERVER_CERT"
+	envServerKey   = "MF_EVENTS_SERVER_KEY"
+	envClientTLS   = "MF_EVENTS_CLIENT_TLS"
+	envCACerts     = "MF_EVENTS_CA_CERTS"
+	envClientCert  = "MF_AUTH_CLIENT_CERT"
+	envClientKey   = "MF_AUTH_CLIENT_KEY"
+	envAuthURL     = "MF_AUTH_GRPC_URL"
+	envAuthTimeout = "MF_AUTH_GRPC_TIMEOUT"
+	envESURL       = "MF_EVENTS_ES_URL"
+	envESPass      = "MF_EVENTS_ES_PASS"
+	envESDB        = "MF_EVENTS_ES_DB"
+	envStreams     = "MF_EVENTS_STREAMS"
+)
+
+type config struct {
+	logLevel    string
+	httpPort    string
+	jaegerURL   string
+	serverCert  string
+	serverKey   string
+	clientTLS   bool
+	caCerts     string
+	clientCert  string
+	clientKey   string
+	authURL     string
+	authTimeout time.Duration
+	esURL       string
+	esPass      string
+	esDB        string
+	streams     []string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	esClient := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
+	defer esClient.Close()
+
+	authTracer, authCloser := initJaeger("auth", cfg.jaegerURL, logger)
+	defer authCloser.Close()
+
+	conn := connectToAuth(cfg, logger)
+	defer conn.Close()
+
+	auth := authapi.NewClient(authTracer, conn, cfg.authTimeout)
+	svc := newService(auth, esClient, cfg, logger)
+
+	tracer, closer := initJaeger("events", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	errs := make(chan error, 2)
+	go startHTTPServer(api.MakeHandler(tracer, svc), cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Events service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	authTimeout, err := time.ParseDuration(mainflux.Env(envAuthTimeout, defAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
+	}
+
+	var streams []string
+	for _, s := range strings.Split(mainflux.Env(envStreams, defStreams), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			streams = append(streams, s)
+		}
+	}
+	if len(streams) == 0 {
+		log.Fatalf("Invalid %s value: at least one stream is required", envStreams)
+	}
+
+	return config{
+		logLevel:    mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:    mainflux.Env(envHTTPPort, defHTTPPort),
+		jaegerURL:   mainflux.Env(envJaegerURL, defJaegerURL),
+		serverCert:  mainflux.Env(envServerCert, defServerCert),
+		serverKey:   mainflux.Env(envServerKey, defServerKey),
+		clientTLS:   tls,
+		caCerts:     mainflux.Env(envCACerts, defCACerts),
+		clientCert:  mainflux.Env(envClientCert, defClientCert),
+		clientKey:   mainflux.Env(envClientKey, defClientKey),
+		authURL:     mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout: authTimeout,
+		esURL:       mainflux.Env(envESURL, defESURL),
+		esPass:      mainflux.Env(envESPass, defESPass),
+		esDB:        mainflux.Env(envESDB, defESDB),
+		streams:     streams,
+	}
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *r.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return r.NewClient(&r.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := authapi.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.authURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to auth service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(auth mainflux.AuthServiceClient, esClient *r.Client, cfg config, logger logger.Logger) events.Service {
+	store := redis.NewEventStore(esClient, cfg.streams)
+
+	svc := events.New(auth, store, logger)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "events",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "events",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(handler http.Handler, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Events service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
+		return
+	}
+	logger.Info(fmt.Sprintf("Events service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, handler)
+}
@@ -0,0 +1,270 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/mainflux/mainflux"
+	authapi "github.com/mainflux/mainflux/auth/api/grpc"
+	"github.com/mainflux/mainflux/graphql"
+	"github.com/mainflux/mainflux/graphql/api"
+	"github.com/mainflux/mainflux/logger"
+	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+	"google.golang.org/grpc"
+)
+
+const (
+	defLogLevel      = "error"
+	defHTTPPort      = "8210"
+	defJaegerURL     = ""
+	defServerCert    = ""
+	defServerKey     = ""
+	defClientTLS     = "false"
+	defCACerts       = ""
+	defClientCert    = ""
+	defClientKey     = ""
+	defAuthURL       = "localhost:8181"
+	defAuthTimeout   = "1s"
+	defAuthHTTPURL   = "http://localhost:8189"
+	defUsersURL      = "http://localhost:8180"
+	defThingsURL     = "http://localhost:8182"
+	defReaderURL     = "http://localhost:8905"
+	defTLSVerify     = "true"
+	defFieldPolicies = ""
+
+	envLogLevel      = "MF_GRAPHQL_LOG_LEVEL"
+	envHTTPPort      = "MF_GRAPHQL_HTTP_PORT"
+	envJaegerURL     = "MF_JAEGER_URL"
+	envServerCert    = "MF_GRAPHQL_SERVER_CERT"
+	envServerKey     = "MF_GRAPHQL_SERVER_KEY"
+	envClientTLS     = "MF_GRAPHQL_CLIENT_TLS"
+	envCACerts       = "MF_GRAPHQL_CA_CERTS"
+	envClientCert    = "MF_AUTH_CLIENT_CERT"
+	envClientKey     = "MF_AUTH_CLIENT_KEY"
+	envAuthURL       = "MF_AUTH_GRPC_URL"
+	envAuthTimeout   = "MF_AUTH_GRPC_TIMEOUT"
+	envAuthHTTPURL   = "MF_GRAPHQL_AUTH_URL"
+	envUsersURL      = "MF_GRAPHQL_USERS_URL"
+	envThingsURL     = "MF_GRAPHQL_THINGS_URL"
+	envReaderURL     = "MF_GRAPHQL_READER_URL"
+	envTLSVerify     = "MF_GRAPHQL_TLS_VERIFICATION"
+	envFieldPolicies = "MF_GRAPHQL_FIELD_POLICIES"
+)
+
+type config struct {
+	logLevel      string
+	httpPort      string
+	jaegerURL     string
+	serverCert    string
+	serverKey     string
+	clientTLS     bool
+	caCerts       string
+	clientCert    string
+	clientKey     string
+	authURL       string
+	authTimeout   time.Duration
+	sdkConfig     mfsdk.Config
+	fieldPolicies map[string]string
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	authTracer, authCloser := initJaeger("auth", cfg.jaegerURL, logger)
+	defer authCloser.Close()
+
+	conn := connectToAuth(cfg, logger)
+	defer conn.Close()
+
+	auth := authapi.NewClient(authTracer, conn, cfg.authTimeout)
+	svc := newService(auth, cfg, logger)
+
+	tracer, closer := initJaeger("graphql", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	errs := make(chan error, 2)
+	go startHTTPServer(api.MakeHandler(tracer, svc, auth), cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("GraphQL service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	tlsVerify, err := strconv.ParseBool(mainflux.Env(envTLSVerify, defTLSVerify))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envTLSVerify)
+	}
+
+	authTimeout, err := time.ParseDuration(mainflux.Env(envAuthTimeout, defAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
+	}
+
+	policies, err := parseFieldPolicies(mainflux.Env(envFieldPolicies, defFieldPolicies))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envFieldPolicies, err.Error())
+	}
+
+	sdkConfig := mfsdk.Config{
+		AuthURL:         mainflux.Env(envAuthHTTPURL, defAuthHTTPURL),
+		UsersURL:        mainflux.Env(envUsersURL, defUsersURL),
+		ThingsURL:       mainflux.Env(envThingsURL, defThingsURL),
+		ReaderURL:       mainflux.Env(envReaderURL, defReaderURL),
+		MsgContentType:  mfsdk.CTJSONSenML,
+		TLSVerification: tlsVerify,
+	}
+
+	return config{
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:      mainflux.Env(envHTTPPort, defHTTPPort),
+		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
+		serverCert:    mainflux.Env(envServerCert, defServerCert),
+		serverKey:     mainflux.Env(envServerKey, defServerKey),
+		clientTLS:     tls,
+		caCerts:       mainflux.Env(envCACerts, defCACerts),
+		clientCert:    mainflux.Env(envClientCert, defClientCert),
+		clientKey:     mainflux.Env(envClientKey, defClientKey),
+		authURL:       mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout:   authTimeout,
+		sdkConfig:     sdkConfig,
+		fieldPolicies: policies,
+	}
+}
+
+// parseFieldPolicies parses comma separated list of "Type.field:relation"
+// pairs. Listed policies are added to the default ones.
+func parseFieldPolicies(s string) (map[string]string, error) {
+	policies := make(map[string]string)
+	for k, v := range graphql.DefaultFieldPolicies {
+		policies[k] = v
+	}
+
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		kv := strings.Split(p, ":")
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid field policy %q", p)
+		}
+		policies[kv[0]] = kv[1]
+	}
+
+	return policies, nil
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := authapi.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+		logger.Info("gRPC communication is not encrypted")
+	}
+
+	conn, err := grpc.Dial(cfg.authURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to auth service: %s", err))
+		os.Exit(1)
+	}
+
+	return conn
+}
+
+func newService(auth mainflux.AuthServiceClient, cfg config, logger logger.Logger) graphql.Service {
+	sdk := mfsdk.NewSDK(cfg.sdkConfig)
+
+	svc := graphql.New(sdk, auth, cfg.fieldPolicies)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "graphql",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "graphql",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(handler http.Handler, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("GraphQL service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
+		return
+	}
+	logger.Info(fmt.Sprintf("GraphQL service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, handler)
+}
@@ -21,6 +21,9 @@ import (
 	"github.com/mainflux/mainflux"
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
+	"github.com/mainflux/mainflux/internal/chaos"
+	"github.com/mainflux/mainflux/internal/lineage"
+	"github.com/mainflux/mainflux/internal/payload"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
@@ -36,6 +39,7 @@ const (
 	defCACerts           = ""
 	defPort              = "8180"
 	defNatsURL           = "nats://localhost:4222"
+	defNatsPartitions    = "0"
 	defJaegerURL         = ""
 	defThingsAuthURL     = "localhost:8181"
 	defThingsAuthTimeout = "1s"
@@ -45,13 +49,17 @@ const (
 	envCACerts           = "MF_HTTP_ADAPTER_CA_CERTS"
 	envPort              = "MF_HTTP_ADAPTER_PORT"
 	envNatsURL           = "MF_NATS_URL"
+	envNatsPartitions    = "MF_NATS_PARTITIONS"
 	envJaegerURL         = "MF_JAEGER_URL"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+
+	chaosPrefix = "MF_HTTP_ADAPTER_"
 )
 
 type config struct {
 	natsURL           string
+	natsPartitions    int
 	logLevel          string
 	port              string
 	clientTLS         bool
@@ -59,12 +67,14 @@ type config struct {
 	jaegerURL         string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	chaos             chaos.Settings
+	payloads          payload.Policies
 }
 
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -78,15 +88,32 @@ func main() {
 	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
 	defer thingsCloser.Close()
 
-	pub, err := nats.NewPublisher(cfg.natsURL)
+	pub, err := nats.NewPublisher(cfg.natsURL, nats.Partitions(cfg.natsPartitions))
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
 	}
 	defer pub.Close()
 
+	var publisher = lineage.NewPublisher(pub, lineage.Instance("http-adapter"))
+	var inj *chaos.Injector
+	if cfg.chaos.Enabled {
+		logger.Warn("Fault injection is enabled")
+		inj = chaos.NewInjector(cfg.chaos.Config)
+		publisher = chaos.NewPublisher(inj, publisher)
+	}
+
 	tc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsAuthTimeout)
-	svc := adapter.New(pub, tc)
+	payloads := payload.MetricsMiddleware(
+		payload.NewChecker(cfg.payloads),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "http_adapter",
+			Subsystem: "payload",
+			Name:      "rejected_count",
+			Help:      "Number of messages rejected by the payload policies.",
+		}, []string{"protocol", "reason"}),
+	)
+	svc := adapter.New(publisher, tc, payloads)
 
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
@@ -105,12 +132,17 @@ func main() {
 		}, []string{"method"}),
 	)
 
+	handler := api.MakeHandler(svc, tracer)
+	if inj != nil {
+		handler = chaos.Wrap(inj, cfg.chaos.AdminToken, handler)
+	}
+
 	errs := make(chan error, 2)
 
 	go func() {
 		p := fmt.Sprintf(":%s", cfg.port)
 		logger.Info(fmt.Sprintf("HTTP adapter service started on port %s", cfg.port))
-		errs <- http.ListenAndServe(p, api.MakeHandler(svc, tracer))
+		errs <- http.ListenAndServe(p, handler)
 	}()
 
 	go func() {
@@ -134,8 +166,24 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	natsPartitions, err := strconv.Atoi(mainflux.Env(envNatsPartitions, defNatsPartitions))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envNatsPartitions, err.Error())
+	}
+
+	chaosSettings, err := chaos.LoadSettings(chaosPrefix)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	payloads, err := payload.LoadPolicies()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
 	return config{
 		natsURL:           mainflux.Env(envNatsURL, defNatsURL),
+		natsPartitions:    natsPartitions,
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		port:              mainflux.Env(envPort, defPort),
 		clientTLS:         tls,
@@ -143,6 +191,8 @@ func loadConfig() config {
 		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: authTimeout,
+		chaos:             chaosSettings,
+		payloads:          payloads,
 	}
 }
 
@@ -13,12 +13,14 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
 	influxdata "github.com/influxdata/influxdb/client/v2"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/influxdb"
+	rediscache "github.com/mainflux/mainflux/readers/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -42,6 +44,11 @@ const (
 	defJaegerURL         = ""
 	defThingsAuthURL     = "localhost:8181"
 	defThingsAuthTimeout = "1s"
+	defCacheURL          = ""
+	defCachePass         = ""
+	defCacheDB           = "0"
+	defCacheTTL          = "5s"
+	defCacheBucket       = "1s"
 
 	envLogLevel          = "MF_INFLUX_READER_LOG_LEVEL"
 	envPort              = "MF_INFLUX_READER_PORT"
@@ -57,6 +64,11 @@ const (
 	envJaegerURL         = "MF_JAEGER_URL"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envCacheURL          = "MF_INFLUX_READER_CACHE_URL"
+	envCachePass         = "MF_INFLUX_READER_CACHE_PASS"
+	envCacheDB           = "MF_INFLUX_READER_CACHE_DB"
+	envCacheTTL          = "MF_INFLUX_READER_CACHE_TTL"
+	envCacheBucket       = "MF_INFLUX_READER_CACHE_BUCKET"
 )
 
 type config struct {
@@ -74,11 +86,16 @@ type config struct {
 	jaegerURL         string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	cacheURL          string
+	cachePass         string
+	cacheDB           string
+	cacheTTL          time.Duration
+	cacheBucket       time.Duration
 }
 
 func main() {
 	cfg, clientCfg := loadConfigs()
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -99,6 +116,13 @@ func main() {
 
 	repo := newService(client, cfg.dbName, logger)
 
+	if cfg.cacheURL != "" {
+		cacheClient := connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger)
+		defer cacheClient.Close()
+
+		repo = rediscache.NewCachedRepository(cacheClient, repo, cfg.cacheTTL, cfg.cacheBucket)
+	}
+
 	errs := make(chan error, 2)
 	go func() {
 		c := make(chan os.Signal)
@@ -123,6 +147,16 @@ func loadConfigs() (config, influxdata.HTTPConfig) {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	cacheTTL, err := time.ParseDuration(mainflux.Env(envCacheTTL, defCacheTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheTTL, err.Error())
+	}
+
+	cacheBucket, err := time.ParseDuration(mainflux.Env(envCacheBucket, defCacheBucket))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheBucket, err.Error())
+	}
+
 	cfg := config{
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		port:              mainflux.Env(envPort, defPort),
@@ -138,6 +172,11 @@ func loadConfigs() (config, influxdata.HTTPConfig) {
 		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: authTimeout,
+		cacheURL:          mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:         mainflux.Env(envCachePass, defCachePass),
+		cacheDB:           mainflux.Env(envCacheDB, defCacheDB),
+		cacheTTL:          cacheTTL,
+		cacheBucket:       cacheBucket,
 	}
 
 	clientCfg := influxdata.HTTPConfig{
@@ -197,6 +236,20 @@ func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer,
 	return tracer, closer
 }
 
+func connectToRedis(cacheURL, cachePass, cacheDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:     cacheURL,
+		Password: cachePass,
+		DB:       db,
+	})
+}
+
 func newService(client influxdata.Client, dbName string, logger logger.Logger) readers.MessageRepository {
 	repo := influxdb.New(client, dbName)
 	repo = api.LoggingMiddleware(repo, logger)
@@ -224,9 +277,9 @@ func startHTTPServer(repo readers.MessageRepository, tc mainflux.ThingsServiceCl
 	if cfg.serverCert != "" || cfg.serverKey != "" {
 		logger.Info(fmt.Sprintf("InfluxDB reader service started using https on port %s with cert %s key %s",
 			cfg.port, cfg.serverCert, cfg.serverKey))
-		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(repo, tc, "influxdb-reader"))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(repo, tc, nil, nil, "influxdb-reader"))
 		return
 	}
 	logger.Info(fmt.Sprintf("InfluxDB reader service started, exposed port %s", cfg.port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, "influxdb-reader"))
+	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, nil, nil, "influxdb-reader"))
 }
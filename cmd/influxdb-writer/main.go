@@ -4,64 +4,92 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis/v8"
 	influxdata "github.com/influxdata/influxdb/client/v2"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/redis"
 	"github.com/mainflux/mainflux/consumers/writers/api"
 	"github.com/mainflux/mainflux/consumers/writers/influxdb"
+	"github.com/mainflux/mainflux/internal/chaos"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 )
 
 const (
-	svcName = "influxdb-writer"
-
-	defNatsURL    = "nats://localhost:4222"
-	defLogLevel   = "error"
-	defPort       = "8180"
-	defDB         = "mainflux"
-	defDBHost     = "localhost"
-	defDBPort     = "8086"
-	defDBUser     = "mainflux"
-	defDBPass     = "mainflux"
-	defConfigPath = "/config.toml"
-
-	envNatsURL    = "MF_NATS_URL"
-	envLogLevel   = "MF_INFLUX_WRITER_LOG_LEVEL"
-	envPort       = "MF_INFLUX_WRITER_PORT"
-	envDB         = "MF_INFLUXDB_DB"
-	envDBHost     = "MF_INFLUX_WRITER_DB_HOST"
-	envDBPort     = "MF_INFLUXDB_PORT"
-	envDBUser     = "MF_INFLUXDB_ADMIN_USER"
-	envDBPass     = "MF_INFLUXDB_ADMIN_PASSWORD"
-	envConfigPath = "MF_INFLUX_WRITER_CONFIG_PATH"
+	svcName     = "influxdb-writer"
+	chaosPrefix = "MF_INFLUX_WRITER_"
+
+	defNatsURL      = "nats://localhost:4222"
+	defLogLevel     = "error"
+	defPort         = "8180"
+	defDB           = "mainflux"
+	defDBHost       = "localhost"
+	defDBPort       = "8086"
+	defDBUser       = "mainflux"
+	defDBPass       = "mainflux"
+	defConfigPath   = "/config.toml"
+	defESURL        = ""
+	defESPass       = ""
+	defESDB         = "0"
+	defBackfillRate = "100"
+	defBackfillIdle = "5s"
+
+	envNatsURL      = "MF_NATS_URL"
+	envLogLevel     = "MF_INFLUX_WRITER_LOG_LEVEL"
+	envPort         = "MF_INFLUX_WRITER_PORT"
+	envDB           = "MF_INFLUXDB_DB"
+	envDBHost       = "MF_INFLUX_WRITER_DB_HOST"
+	envDBPort       = "MF_INFLUXDB_PORT"
+	envDBUser       = "MF_INFLUXDB_ADMIN_USER"
+	envDBPass       = "MF_INFLUXDB_ADMIN_PASSWORD"
+	envConfigPath   = "MF_INFLUX_WRITER_CONFIG_PATH"
+	envESURL        = "MF_THINGS_ES_URL"
+	envESPass       = "MF_THINGS_ES_PASS"
+	envESDB         = "MF_THINGS_ES_DB"
+	envBackfillRate = "MF_INFLUX_WRITER_BACKFILL_RATE"
+	envBackfillIdle = "MF_INFLUX_WRITER_BACKFILL_IDLE_TIMEOUT"
 )
 
 type config struct {
-	natsURL    string
-	logLevel   string
-	port       string
-	dbName     string
-	dbHost     string
-	dbPort     string
-	dbUser     string
-	dbPass     string
-	configPath string
+	natsURL      string
+	logLevel     string
+	port         string
+	dbName       string
+	dbHost       string
+	dbPort       string
+	dbUser       string
+	dbPass       string
+	configPath   string
+	esURL        string
+	esPass       string
+	esDB         string
+	backfillRate int
+	backfillIdle time.Duration
+	chaos        chaos.Settings
 }
 
 func main() {
+	backfill := flag.String("backfill", "", `backfill the messages from the NDJSON file, or from the NATS subject prefixed with "nats:", and exit`)
+	flag.Parse()
+
 	cfg, clientCfg := loadConfigs()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -73,6 +101,14 @@ func main() {
 	}
 	defer pubSub.Close()
 
+	var sub messaging.Subscriber = pubSub
+	var inj *chaos.Injector
+	if cfg.chaos.Enabled {
+		logger.Warn("Fault injection is enabled")
+		inj = chaos.NewInjector(cfg.chaos.Config)
+		sub = chaos.NewPubSub(inj, pubSub)
+	}
+
 	client, err := influxdata.NewHTTPClient(clientCfg)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to create InfluxDB client: %s", err))
@@ -86,7 +122,12 @@ func main() {
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(repo, counter, latency)
 
-	if err := consumers.Start(pubSub, repo, cfg.configPath, logger); err != nil {
+	if *backfill != "" {
+		runBackfill(*backfill, pubSub, repo, cfg, logger)
+		return
+	}
+
+	if err := consumers.Start(sub, repo, newChannelSource(cfg, logger), cfg.configPath, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to start InfluxDB writer: %s", err))
 		os.Exit(1)
 	}
@@ -98,23 +139,48 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	go startHTTPService(cfg.port, logger, errs)
+	handler := api.MakeHandler(svcName)
+	if inj != nil {
+		handler = chaos.Wrap(inj, cfg.chaos.AdminToken, handler)
+	}
+	go startHTTPService(cfg.port, handler, logger, errs)
 
 	err = <-errs
 	logger.Error(fmt.Sprintf("InfluxDB writer service terminated: %s", err))
 }
 
 func loadConfigs() (config, influxdata.HTTPConfig) {
+	backfillRate, err := strconv.Atoi(mainflux.Env(envBackfillRate, defBackfillRate))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackfillRate, err.Error())
+	}
+
+	backfillIdle, err := time.ParseDuration(mainflux.Env(envBackfillIdle, defBackfillIdle))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackfillIdle, err.Error())
+	}
+
+	chaosSettings, err := chaos.LoadSettings(chaosPrefix)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
 	cfg := config{
-		natsURL:    mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
-		port:       mainflux.Env(envPort, defPort),
-		dbName:     mainflux.Env(envDB, defDB),
-		dbHost:     mainflux.Env(envDBHost, defDBHost),
-		dbPort:     mainflux.Env(envDBPort, defDBPort),
-		dbUser:     mainflux.Env(envDBUser, defDBUser),
-		dbPass:     mainflux.Env(envDBPass, defDBPass),
-		configPath: mainflux.Env(envConfigPath, defConfigPath),
+		natsURL:      mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		port:         mainflux.Env(envPort, defPort),
+		dbName:       mainflux.Env(envDB, defDB),
+		dbHost:       mainflux.Env(envDBHost, defDBHost),
+		dbPort:       mainflux.Env(envDBPort, defDBPort),
+		dbUser:       mainflux.Env(envDBUser, defDBUser),
+		dbPass:       mainflux.Env(envDBPass, defDBPass),
+		configPath:   mainflux.Env(envConfigPath, defConfigPath),
+		esURL:        mainflux.Env(envESURL, defESURL),
+		esPass:       mainflux.Env(envESPass, defESPass),
+		esDB:         mainflux.Env(envESDB, defESDB),
+		backfillRate: backfillRate,
+		backfillIdle: backfillIdle,
+		chaos:        chaosSettings,
 	}
 
 	clientCfg := influxdata.HTTPConfig{
@@ -144,8 +210,54 @@ func makeMetrics() (*kitprometheus.Counter, *kitprometheus.Summary) {
 	return counter, latency
 }
 
-func startHTTPService(port string, logger logger.Logger, errs chan error) {
+func startHTTPService(port string, handler http.Handler, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("InfluxDB writer service started, exposed port %s", p))
-	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
+	errs <- http.ListenAndServe(p, handler)
+}
+
+// runBackfill consumes the messages read from the backfill source through the
+// same transformation and storage path as the live messages.
+func runBackfill(source string, sub messaging.Subscriber, repo consumers.Consumer, cfg config, logger logger.Logger) {
+	src, err := consumers.NewSource(sub, source, cfg.backfillIdle)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to open backfill source: %s", err))
+		os.Exit(1)
+	}
+	defer src.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		<-c
+		cancel()
+	}()
+
+	n, err := consumers.Backfill(ctx, src, repo, cfg.configPath, cfg.backfillRate, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Backfill stopped after %d messages: %s", n, err))
+		return
+	}
+	logger.Info(fmt.Sprintf("Backfilled %d messages", n))
+}
+
+func newChannelSource(cfg config, logger logger.Logger) consumers.ChannelSource {
+	if cfg.esURL == "" {
+		return nil
+	}
+
+	db, err := strconv.Atoi(cfg.esDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things event store: %s", err))
+		os.Exit(1)
+	}
+
+	client := r.NewClient(&r.Options{
+		Addr:     cfg.esURL,
+		Password: cfg.esPass,
+		DB:       db,
+	})
+	return redis.NewChannelSource(client)
 }
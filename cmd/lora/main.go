@@ -17,6 +17,7 @@ import (
 
 	r "github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/lineage"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/lora"
 	"github.com/mainflux/mainflux/lora/api"
@@ -35,6 +36,7 @@ const (
 	defLoraMsgURL     = "tcp://localhost:1883"
 	defSubTimeout     = "30s" // 30 seconds
 	defNatsURL        = "nats://localhost:4222"
+	defNatsPartitions = "0"
 	defESURL          = "localhost:6379"
 	defESPass         = ""
 	defESDB           = "0"
@@ -47,6 +49,7 @@ const (
 	envLoraMsgURL     = "MF_LORA_ADAPTER_MESSAGES_URL"
 	envSubTimeout     = "MF_LORA_ADAPTER_SUBSCRIBER_TIMEOUT"
 	envNatsURL        = "MF_NATS_URL"
+	envNatsPartitions = "MF_NATS_PARTITIONS"
 	envLogLevel       = "MF_LORA_ADAPTER_LOG_LEVEL"
 	envESURL          = "MF_THINGS_ES_URL"
 	envESPass         = "MF_THINGS_ES_PASS"
@@ -67,6 +70,7 @@ type config struct {
 	httpPort       string
 	loraMsgURL     string
 	natsURL        string
+	natsPartitions int
 	subTimeout     time.Duration
 	logLevel       string
 	esURL          string
@@ -81,7 +85,7 @@ type config struct {
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -92,7 +96,7 @@ func main() {
 	esConn := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 	defer esConn.Close()
 
-	pub, err := nats.NewPublisher(cfg.natsURL)
+	pub, err := nats.NewPublisher(cfg.natsURL, nats.Partitions(cfg.natsPartitions))
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -103,7 +107,7 @@ func main() {
 	chansRM := newRouteMapRepository(rmConn, channelsRMPrefix, logger)
 	connsRM := newRouteMapRepository(rmConn, connsRMPrefix, logger)
 
-	svc := lora.New(pub, thingsRM, chansRM, connsRM)
+	svc := lora.New(lineage.NewPublisher(pub, lineage.Instance("lora-adapter")), thingsRM, chansRM, connsRM)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -150,11 +154,16 @@ func loadConfig() config {
 	if err != nil {
 		log.Fatalf("Invalid %s value: %s", envSubTimeout, err.Error())
 	}
+	natsPartitions, err := strconv.Atoi(mainflux.Env(envNatsPartitions, defNatsPartitions))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envNatsPartitions, err.Error())
+	}
 	return config{
 		httpPort:       mainflux.Env(envHTTPPort, defHTTPPort),
 		loraMsgURL:     mainflux.Env(envLoraMsgURL, defLoraMsgURL),
 		subTimeout:     mqttTimeout,
 		natsURL:        mainflux.Env(envNatsURL, defNatsURL),
+		natsPartitions: natsPartitions,
 		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
 		esURL:          mainflux.Env(envESURL, defESURL),
 		esPass:         mainflux.Env(envESPass, defESPass),
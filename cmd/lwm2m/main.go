@@ -0,0 +1,274 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/lineage"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/lwm2m/api"
+	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/pkg/ulid"
+	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
+	opentracing "github.com/opentracing/opentracing-go"
+	gocoap "github.com/plgd-dev/go-coap/v2"
+	"github.com/plgd-dev/go-coap/v2/dtls"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	svcName = "lwm2m-adapter"
+
+	defLogLevel          = "error"
+	defHTTPPort          = "8213"
+	defPort              = "5783"
+	defDTLSPort          = "5784"
+	defServerURI         = "coaps://localhost:5784"
+	defLifetime          = "24h"
+	defNatsURL           = "nats://localhost:4222"
+	defThingsURL         = "http://localhost:8182"
+	defThingsToken       = ""
+	defClientTLS         = "false"
+	defCACerts           = ""
+	defJaegerURL         = ""
+	defThingsAuthURL     = "localhost:8181"
+	defThingsAuthTimeout = "1s"
+
+	envLogLevel          = "MF_LWM2M_ADAPTER_LOG_LEVEL"
+	envHTTPPort          = "MF_LWM2M_ADAPTER_HTTP_PORT"
+	envPort              = "MF_LWM2M_ADAPTER_PORT"
+	envDTLSPort          = "MF_LWM2M_ADAPTER_DTLS_PORT"
+	envServerURI         = "MF_LWM2M_ADAPTER_SERVER_URI"
+	envLifetime          = "MF_LWM2M_ADAPTER_LIFETIME"
+	envNatsURL           = "MF_NATS_URL"
+	envThingsURL         = "MF_LWM2M_ADAPTER_THINGS_URL"
+	envThingsToken       = "MF_LWM2M_ADAPTER_THINGS_TOKEN"
+	envClientTLS         = "MF_LWM2M_ADAPTER_CLIENT_TLS"
+	envCACerts           = "MF_LWM2M_ADAPTER_CA_CERTS"
+	envJaegerURL         = "MF_JAEGER_URL"
+	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
+	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+)
+
+type config struct {
+	logLevel          string
+	httpPort          string
+	port              string
+	dtlsPort          string
+	serverURI         string
+	lifetime          time.Duration
+	natsURL           string
+	thingsURL         string
+	thingsToken       string
+	clientTLS         bool
+	caCerts           string
+	jaegerURL         string
+	thingsAuthURL     string
+	thingsAuthTimeout time.Duration
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	conn := connectToThings(cfg, logger)
+	defer conn.Close()
+
+	thingsTracer, thingsCloser := initJaeger("things", cfg.jaegerURL, logger)
+	defer thingsCloser.Close()
+
+	tc := thingsapi.NewClient(conn, thingsTracer, cfg.thingsAuthTimeout)
+
+	pubSub, err := nats.NewPubSub(cfg.natsURL, "", logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer pubSub.Close()
+
+	sdk := mfsdk.NewSDK(mfsdk.Config{
+		ThingsURL: cfg.thingsURL,
+	})
+	things := lwm2m.NewThings(tc, sdk, cfg.thingsToken)
+
+	svc := newService(things, pubSub, cfg, logger)
+
+	errs := make(chan error, 3)
+
+	go startHTTPServer(cfg.httpPort, logger, errs)
+	if cfg.port != "" {
+		go startCoAPServer(cfg.port, svc, logger, errs)
+	}
+	if cfg.dtlsPort != "" {
+		go startDTLSServer(cfg.dtlsPort, svc, logger, errs)
+	}
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("LwM2M adapter terminated: %s", err))
+}
+
+func loadConfig() config {
+	tls, err := strconv.ParseBool(mainflux.Env(envClientTLS, defClientTLS))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envClientTLS)
+	}
+
+	lifetime, err := time.ParseDuration(mainflux.Env(envLifetime, defLifetime))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envLifetime, err.Error())
+	}
+
+	authTimeout, err := time.ParseDuration(mainflux.Env(envThingsAuthTimeout, defThingsAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
+	}
+
+	return config{
+		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:          mainflux.Env(envHTTPPort, defHTTPPort),
+		port:              mainflux.Env(envPort, defPort),
+		dtlsPort:          mainflux.Env(envDTLSPort, defDTLSPort),
+		serverURI:         mainflux.Env(envServerURI, defServerURI),
+		lifetime:          lifetime,
+		natsURL:           mainflux.Env(envNatsURL, defNatsURL),
+		thingsURL:         mainflux.Env(envThingsURL, defThingsURL),
+		thingsToken:       mainflux.Env(envThingsToken, defThingsToken),
+		clientTLS:         tls,
+		caCerts:           mainflux.Env(envCACerts, defCACerts),
+		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
+		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
+		thingsAuthTimeout: authTimeout,
+	}
+}
+
+func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		if cfg.caCerts != "" {
+			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+				os.Exit(1)
+			}
+			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		}
+	} else {
+		logger.Info("gRPC communication is not encrypted")
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.thingsAuthURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func newService(things lwm2m.Things, pubSub nats.PubSub, cfg config, logger logger.Logger) lwm2m.Service {
+	svc := lwm2m.New(things, lineage.NewPubSub(pubSub, lineage.Instance("lwm2m-adapter")), ulid.New(), lwm2m.Config{
+		ServerURI: cfg.serverURI,
+		Lifetime:  cfg.lifetime,
+		PSK:       strings.HasPrefix(cfg.serverURI, "coaps://"),
+	})
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "lwm2m_adapter",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "lwm2m_adapter",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(port string, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("LwM2M adapter HTTP server started, exposed port %s", port))
+	errs <- http.ListenAndServe(p, api.MakeHTTPHandler())
+}
+
+func startCoAPServer(port string, svc lwm2m.Service, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("LwM2M adapter CoAP server started, exposed port %s", port))
+	errs <- gocoap.ListenAndServe("udp", p, api.MakeCoAPHandler(svc, logger))
+}
+
+func startDTLSServer(port string, svc lwm2m.Service, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	l, err := api.NewPSKListener(svc, "udp", p)
+	if err != nil {
+		errs <- err
+		return
+	}
+	defer l.Close()
+
+	s := dtls.NewServer(
+		dtls.WithMux(api.MakeCoAPHandler(svc, logger)),
+		dtls.WithOnNewClientConn(l.OnNewClientConn),
+	)
+	logger.Info(fmt.Sprintf("LwM2M adapter DTLS server started, exposed port %s", port))
+	errs <- s.Serve(l)
+}
@@ -17,11 +17,13 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/mongodb"
+	rediscache "github.com/mainflux/mainflux/readers/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -45,6 +47,11 @@ const (
 	defJaegerURL         = ""
 	defThingsAuthURL     = "localhost:8181"
 	defThingsAuthTimeout = "1s"
+	defCacheURL          = ""
+	defCachePass         = ""
+	defCacheDB           = "0"
+	defCacheTTL          = "5s"
+	defCacheBucket       = "1s"
 
 	envLogLevel          = "MF_MONGO_READER_LOG_LEVEL"
 	envPort              = "MF_MONGO_READER_PORT"
@@ -58,6 +65,11 @@ const (
 	envJaegerURL         = "MF_JAEGER_URL"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envCacheURL          = "MF_MONGO_READER_CACHE_URL"
+	envCachePass         = "MF_MONGO_READER_CACHE_PASS"
+	envCacheDB           = "MF_MONGO_READER_CACHE_DB"
+	envCacheTTL          = "MF_MONGO_READER_CACHE_TTL"
+	envCacheBucket       = "MF_MONGO_READER_CACHE_BUCKET"
 )
 
 type config struct {
@@ -73,11 +85,16 @@ type config struct {
 	jaegerURL         string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	cacheURL          string
+	cachePass         string
+	cacheDB           string
+	cacheTTL          time.Duration
+	cacheBucket       time.Duration
 }
 
 func main() {
 	cfg := loadConfigs()
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -94,6 +111,13 @@ func main() {
 
 	repo := newService(db, logger)
 
+	if cfg.cacheURL != "" {
+		cacheClient := connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger)
+		defer cacheClient.Close()
+
+		repo = rediscache.NewCachedRepository(cacheClient, repo, cfg.cacheTTL, cfg.cacheBucket)
+	}
+
 	errs := make(chan error, 2)
 	go func() {
 		c := make(chan os.Signal)
@@ -118,6 +142,16 @@ func loadConfigs() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	cacheTTL, err := time.ParseDuration(mainflux.Env(envCacheTTL, defCacheTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheTTL, err.Error())
+	}
+
+	cacheBucket, err := time.ParseDuration(mainflux.Env(envCacheBucket, defCacheBucket))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheBucket, err.Error())
+	}
+
 	return config{
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		port:              mainflux.Env(envPort, defPort),
@@ -131,6 +165,11 @@ func loadConfigs() config {
 		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: authTimeout,
+		cacheURL:          mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:         mainflux.Env(envCachePass, defCachePass),
+		cacheDB:           mainflux.Env(envCacheDB, defCacheDB),
+		cacheTTL:          cacheTTL,
+		cacheBucket:       cacheBucket,
 	}
 }
 
@@ -193,6 +232,20 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
+func connectToRedis(cacheURL, cachePass, cacheDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:     cacheURL,
+		Password: cachePass,
+		DB:       db,
+	})
+}
+
 func newService(db *mongo.Database, logger logger.Logger) readers.MessageRepository {
 	repo := mongodb.New(db)
 	repo = api.LoggingMiddleware(repo, logger)
@@ -220,9 +273,9 @@ func startHTTPServer(repo readers.MessageRepository, tc mainflux.ThingsServiceCl
 	if cfg.serverCert != "" || cfg.serverKey != "" {
 		logger.Info(fmt.Sprintf("Mongo reader service started using https on port %s with cert %s key %s",
 			cfg.port, cfg.serverCert, cfg.serverKey))
-		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(repo, tc, "mongodb-reader"))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, api.MakeHandler(repo, tc, nil, nil, "mongodb-reader"))
 		return
 	}
 	logger.Info(fmt.Sprintf("Mongo reader service started, exposed port %s", cfg.port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, "mongodb-reader"))
+	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, nil, nil, "mongodb-reader"))
 }
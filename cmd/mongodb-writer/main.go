@@ -5,19 +5,26 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/redis"
 	"github.com/mainflux/mainflux/consumers/writers/api"
 	"github.com/mainflux/mainflux/consumers/writers/mongodb"
+	"github.com/mainflux/mainflux/internal/chaos"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -25,39 +32,59 @@ import (
 )
 
 const (
-	svcName = "mongodb-writer"
-
-	defLogLevel   = "error"
-	defNatsURL    = "nats://localhost:4222"
-	defPort       = "8180"
-	defDB         = "mainflux"
-	defDBHost     = "localhost"
-	defDBPort     = "27017"
-	defConfigPath = "/config.toml"
-
-	envNatsURL    = "MF_NATS_URL"
-	envLogLevel   = "MF_MONGO_WRITER_LOG_LEVEL"
-	envPort       = "MF_MONGO_WRITER_PORT"
-	envDB         = "MF_MONGO_WRITER_DB"
-	envDBHost     = "MF_MONGO_WRITER_DB_HOST"
-	envDBPort     = "MF_MONGO_WRITER_DB_PORT"
-	envConfigPath = "MF_MONGO_WRITER_CONFIG_PATH"
+	svcName     = "mongodb-writer"
+	chaosPrefix = "MF_MONGO_WRITER_"
+
+	defLogLevel     = "error"
+	defNatsURL      = "nats://localhost:4222"
+	defPort         = "8180"
+	defDB           = "mainflux"
+	defDBHost       = "localhost"
+	defDBPort       = "27017"
+	defConfigPath   = "/config.toml"
+	defESURL        = ""
+	defESPass       = ""
+	defESDB         = "0"
+	defBackfillRate = "100"
+	defBackfillIdle = "5s"
+
+	envNatsURL      = "MF_NATS_URL"
+	envLogLevel     = "MF_MONGO_WRITER_LOG_LEVEL"
+	envPort         = "MF_MONGO_WRITER_PORT"
+	envDB           = "MF_MONGO_WRITER_DB"
+	envDBHost       = "MF_MONGO_WRITER_DB_HOST"
+	envDBPort       = "MF_MONGO_WRITER_DB_PORT"
+	envConfigPath   = "MF_MONGO_WRITER_CONFIG_PATH"
+	envESURL        = "MF_THINGS_ES_URL"
+	envESPass       = "MF_THINGS_ES_PASS"
+	envESDB         = "MF_THINGS_ES_DB"
+	envBackfillRate = "MF_MONGO_WRITER_BACKFILL_RATE"
+	envBackfillIdle = "MF_MONGO_WRITER_BACKFILL_IDLE_TIMEOUT"
 )
 
 type config struct {
-	natsURL    string
-	logLevel   string
-	port       string
-	dbName     string
-	dbHost     string
-	dbPort     string
-	configPath string
+	natsURL      string
+	logLevel     string
+	port         string
+	dbName       string
+	dbHost       string
+	dbPort       string
+	configPath   string
+	esURL        string
+	esPass       string
+	esDB         string
+	backfillRate int
+	backfillIdle time.Duration
+	chaos        chaos.Settings
 }
 
 func main() {
+	backfill := flag.String("backfill", "", `backfill the messages from the NDJSON file, or from the NATS subject prefixed with "nats:", and exit`)
+	flag.Parse()
+
 	cfg := loadConfigs()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -69,6 +96,14 @@ func main() {
 	}
 	defer pubSub.Close()
 
+	var sub messaging.Subscriber = pubSub
+	var inj *chaos.Injector
+	if cfg.chaos.Enabled {
+		logger.Warn("Fault injection is enabled")
+		inj = chaos.NewInjector(cfg.chaos.Config)
+		sub = chaos.NewPubSub(inj, pubSub)
+	}
+
 	addr := fmt.Sprintf("mongodb://%s:%s", cfg.dbHost, cfg.dbPort)
 	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(addr))
 	if err != nil {
@@ -83,7 +118,12 @@ func main() {
 	repo = api.LoggingMiddleware(repo, logger)
 	repo = api.MetricsMiddleware(repo, counter, latency)
 
-	if err := consumers.Start(pubSub, repo, cfg.configPath, logger); err != nil {
+	if *backfill != "" {
+		runBackfill(*backfill, pubSub, repo, cfg, logger)
+		return
+	}
+
+	if err := consumers.Start(sub, repo, newChannelSource(cfg, logger), cfg.configPath, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to start MongoDB writer: %s", err))
 		os.Exit(1)
 	}
@@ -95,21 +135,46 @@ func main() {
 		errs <- fmt.Errorf("%s", <-c)
 	}()
 
-	go startHTTPService(cfg.port, logger, errs)
+	handler := api.MakeHandler(svcName)
+	if inj != nil {
+		handler = chaos.Wrap(inj, cfg.chaos.AdminToken, handler)
+	}
+	go startHTTPService(cfg.port, handler, logger, errs)
 
 	err = <-errs
 	logger.Error(fmt.Sprintf("MongoDB writer service terminated: %s", err))
 }
 
 func loadConfigs() config {
+	backfillRate, err := strconv.Atoi(mainflux.Env(envBackfillRate, defBackfillRate))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackfillRate, err.Error())
+	}
+
+	backfillIdle, err := time.ParseDuration(mainflux.Env(envBackfillIdle, defBackfillIdle))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackfillIdle, err.Error())
+	}
+
+	chaosSettings, err := chaos.LoadSettings(chaosPrefix)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
 	return config{
-		natsURL:    mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
-		port:       mainflux.Env(envPort, defPort),
-		dbName:     mainflux.Env(envDB, defDB),
-		dbHost:     mainflux.Env(envDBHost, defDBHost),
-		dbPort:     mainflux.Env(envDBPort, defDBPort),
-		configPath: mainflux.Env(envConfigPath, defConfigPath),
+		natsURL:      mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:     mainflux.Env(envLogLevel, defLogLevel),
+		port:         mainflux.Env(envPort, defPort),
+		dbName:       mainflux.Env(envDB, defDB),
+		dbHost:       mainflux.Env(envDBHost, defDBHost),
+		dbPort:       mainflux.Env(envDBPort, defDBPort),
+		configPath:   mainflux.Env(envConfigPath, defConfigPath),
+		esURL:        mainflux.Env(envESURL, defESURL),
+		esPass:       mainflux.Env(envESPass, defESPass),
+		esDB:         mainflux.Env(envESDB, defESDB),
+		backfillRate: backfillRate,
+		backfillIdle: backfillIdle,
+		chaos:        chaosSettings,
 	}
 }
 
@@ -131,8 +196,54 @@ func makeMetrics() (*kitprometheus.Counter, *kitprometheus.Summary) {
 	return counter, latency
 }
 
-func startHTTPService(port string, logger logger.Logger, errs chan error) {
+func startHTTPService(port string, handler http.Handler, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("Mongodb writer service started, exposed port %s", p))
-	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
+	errs <- http.ListenAndServe(p, handler)
+}
+
+// runBackfill consumes the messages read from the backfill source through the
+// same transformation and storage path as the live messages.
+func runBackfill(source string, sub messaging.Subscriber, repo consumers.Consumer, cfg config, logger logger.Logger) {
+	src, err := consumers.NewSource(sub, source, cfg.backfillIdle)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to open backfill source: %s", err))
+		os.Exit(1)
+	}
+	defer src.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		<-c
+		cancel()
+	}()
+
+	n, err := consumers.Backfill(ctx, src, repo, cfg.configPath, cfg.backfillRate, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Backfill stopped after %d messages: %s", n, err))
+		return
+	}
+	logger.Info(fmt.Sprintf("Backfilled %d messages", n))
+}
+
+func newChannelSource(cfg config, logger logger.Logger) consumers.ChannelSource {
+	if cfg.esURL == "" {
+		return nil
+	}
+
+	db, err := strconv.Atoi(cfg.esDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things event store: %s", err))
+		os.Exit(1)
+	}
+
+	client := r.NewClient(&r.Options{
+		Addr:     cfg.esURL,
+		Password: cfg.esPass,
+		DB:       db,
+	})
+	return redis.NewChannelSource(client)
 }
@@ -13,10 +13,15 @@ import (
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
 	"github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
+	authapi "github.com/mainflux/mainflux/auth/api/grpc"
+	"github.com/mainflux/mainflux/internal/lineage"
+	"github.com/mainflux/mainflux/internal/payload"
 	mflog "github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/mqtt"
+	"github.com/mainflux/mainflux/mqtt/api"
 	mqttredis "github.com/mainflux/mainflux/mqtt/redis"
 	"github.com/mainflux/mainflux/pkg/auth"
 	"github.com/mainflux/mainflux/pkg/errors"
@@ -28,6 +33,7 @@ import (
 	"github.com/mainflux/mproxy/pkg/session"
 	ws "github.com/mainflux/mproxy/pkg/websocket"
 	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -57,14 +63,24 @@ const (
 	envHTTPTargetHost = "MF_MQTT_ADAPTER_WS_TARGET_HOST"
 	envHTTPTargetPort = "MF_MQTT_ADAPTER_WS_TARGET_PORT"
 	envHTTPTargetPath = "MF_MQTT_ADAPTER_WS_TARGET_PATH"
+	// API
+	defAPIPort = "8215"
+	envAPIPort = "MF_MQTT_ADAPTER_HTTP_PORT"
 	// Things
 	defThingsAuthURL     = "localhost:8181"
 	defThingsAuthTimeout = "1s"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	// Auth
+	defAuthURL     = "localhost:8181"
+	defAuthTimeout = "1s"
+	envAuthURL     = "MF_AUTH_GRPC_URL"
+	envAuthTimeout = "MF_AUTH_GRPC_TIMEOUT"
 	// Nats
-	defNatsURL = "nats://localhost:4222"
-	envNatsURL = "MF_NATS_URL"
+	defNatsURL        = "nats://localhost:4222"
+	defNatsPartitions = "0"
+	envNatsURL        = "MF_NATS_URL"
+	envNatsPartitions = "MF_NATS_PARTITIONS"
 	// Jaeger
 	defJaegerURL = ""
 	envJaegerURL = "MF_JAEGER_URL"
@@ -102,12 +118,16 @@ type config struct {
 	httpTargetHost        string
 	httpTargetPort        string
 	httpTargetPath        string
+	apiPort               string
 	jaegerURL             string
 	logLevel              string
 	thingsURL             string
 	thingsAuthURL         string
 	thingsAuthTimeout     time.Duration
+	usersAuthURL          string
+	usersAuthTimeout      time.Duration
 	natsURL               string
+	natsPartitions        int
 	clientTLS             bool
 	caCerts               string
 	instance              string
@@ -117,12 +137,13 @@ type config struct {
 	authURL               string
 	authPass              string
 	authDB                string
+	payloads              payload.Policies
 }
 
 func main() {
 	cfg := loadConfig()
 
-	logger, err := mflog.New(os.Stdout, cfg.logLevel)
+	logger, err := mflog.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -139,9 +160,12 @@ func main() {
 		}
 	}
 
-	conn := connectToThings(cfg, logger)
+	conn := connectToGRPC(cfg.thingsAuthURL, "things", cfg, logger)
 	defer conn.Close()
 
+	authConn := connectToGRPC(cfg.usersAuthURL, "auth", cfg, logger)
+	defer authConn.Close()
+
 	ec := connectToRedis(cfg.esURL, cfg.esPass, cfg.esDB, logger)
 	defer ec.Close()
 
@@ -164,7 +188,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	np, err := nats.NewPublisher(cfg.natsURL)
+	np, err := nats.NewPublisher(cfg.natsURL, nats.Partitions(cfg.natsPartitions))
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
 		os.Exit(1)
@@ -182,10 +206,22 @@ func main() {
 
 	authClient := auth.New(ac, tc)
 
+	authTracer, authCloser := initJaeger("auth", cfg.jaegerURL, logger)
+	defer authCloser.Close()
+	usersAuth := authapi.NewClient(authTracer, authConn, cfg.usersAuthTimeout)
+
+	clients := newClientRegistry()
+
 	// Event handler for MQTT hooks
-	h := mqtt.NewHandler([]messaging.Publisher{np}, es, logger, authClient)
+	h := mqtt.NewHandler([]messaging.Publisher{lineage.NewPublisher(np, lineage.Instance("mqtt-adapter"))}, es, logger, authClient, clients, newPayloadChecker(cfg.payloads))
+
+	svc := newService(usersAuth, clients, logger)
 
-	errs := make(chan error, 2)
+	errs := make(chan error, 3)
+
+	apiTracer, apiCloser := initJaeger("mqtt", cfg.jaegerURL, logger)
+	defer apiCloser.Close()
+	go startHTTPServer(api.MakeHandler(apiTracer, svc), cfg.apiPort, logger, errs)
 
 	logger.Info(fmt.Sprintf("Starting MQTT proxy on port %s", cfg.mqttPort))
 	go proxyMQTT(cfg, logger, h, errs)
@@ -214,11 +250,26 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	usersAuthTimeout, err := time.ParseDuration(mainflux.Env(envAuthTimeout, defAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
+	}
+
 	mqttTimeout, err := time.ParseDuration(mainflux.Env(envMQTTForwarderTimeout, defMQTTForwarderTimeout))
 	if err != nil {
 		log.Fatalf("Invalid %s value: %s", envMQTTForwarderTimeout, err.Error())
 	}
 
+	natsPartitions, err := strconv.Atoi(mainflux.Env(envNatsPartitions, defNatsPartitions))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envNatsPartitions, err.Error())
+	}
+
+	payloads, err := payload.LoadPolicies()
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
 	return config{
 		mqttPort:              mainflux.Env(envMQTTPort, defMQTTPort),
 		mqttTargetHost:        mainflux.Env(envMQTTTargetHost, defMQTTTargetHost),
@@ -229,11 +280,15 @@ func loadConfig() config {
 		httpTargetHost:        mainflux.Env(envHTTPTargetHost, defHTTPTargetHost),
 		httpTargetPort:        mainflux.Env(envHTTPTargetPort, defHTTPTargetPort),
 		httpTargetPath:        mainflux.Env(envHTTPTargetPath, defHTTPTargetPath),
+		apiPort:               mainflux.Env(envAPIPort, defAPIPort),
 		jaegerURL:             mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:         mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout:     authTimeout,
 		thingsURL:             mainflux.Env(envThingsAuthURL, defThingsAuthURL),
+		usersAuthURL:          mainflux.Env(envAuthURL, defAuthURL),
+		usersAuthTimeout:      usersAuthTimeout,
 		natsURL:               mainflux.Env(envNatsURL, defNatsURL),
+		natsPartitions:        natsPartitions,
 		logLevel:              mainflux.Env(envLogLevel, defLogLevel),
 		clientTLS:             tls,
 		caCerts:               mainflux.Env(envCACerts, defCACerts),
@@ -244,6 +299,7 @@ func loadConfig() config {
 		authURL:               mainflux.Env(envAuthCacheURL, defAuthcacheURL),
 		authPass:              mainflux.Env(envAuthCachePass, defAuthCachePass),
 		authDB:                mainflux.Env(envAuthCacheDB, defAuthCacheDB),
+		payloads:              payloads,
 	}
 }
 
@@ -271,7 +327,7 @@ func initJaeger(svcName, url string, logger mflog.Logger) (opentracing.Tracer, i
 	return tracer, closer
 }
 
-func connectToThings(cfg config, logger mflog.Logger) *grpc.ClientConn {
+func connectToGRPC(url, name string, cfg config, logger mflog.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
 		if cfg.caCerts != "" {
@@ -287,9 +343,9 @@ func connectToThings(cfg config, logger mflog.Logger) *grpc.ClientConn {
 		opts = append(opts, grpc.WithInsecure())
 	}
 
-	conn, err := grpc.Dial(cfg.thingsAuthURL, opts...)
+	conn, err := grpc.Dial(url, opts...)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Failed to connect to things service: %s", err))
+		logger.Error(fmt.Sprintf("Failed to connect to %s service: %s", name, err))
 		os.Exit(1)
 	}
 	return conn
@@ -309,6 +365,79 @@ func connectToRedis(redisURL, redisPass, redisDB string, logger mflog.Logger) *r
 	})
 }
 
+func newClientRegistry() mqtt.ClientRegistry {
+	clients := mqtt.NewClientRegistry()
+	clients = api.ClientsMetricsMiddleware(clients, api.ClientsMetrics{
+		Messages: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "clients",
+			Name:      "messages_count",
+			Help:      "Number of messages published and topics subscribed to by the clients.",
+		}, []string{"operation"}),
+		Bytes: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "clients",
+			Name:      "bytes_count",
+			Help:      "Total size of the payloads published by the clients.",
+		}, []string{"operation"}),
+		Connected: kitprometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "clients",
+			Name:      "connected",
+			Help:      "Number of currently connected clients.",
+		}, []string{}),
+		Duration: kitprometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "clients",
+			Name:      "connection_duration_seconds",
+			Help:      "Duration of the client connections in seconds.",
+			Buckets:   stdprometheus.ExponentialBuckets(1, 4, 10),
+		}, []string{}),
+	})
+
+	return clients
+}
+
+func newPayloadChecker(ps payload.Policies) payload.Checker {
+	return payload.MetricsMiddleware(
+		payload.NewChecker(ps),
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "payload",
+			Name:      "rejected_count",
+			Help:      "Number of messages rejected by the payload policies.",
+		}, []string{"protocol", "reason"}),
+	)
+}
+
+func newService(auth mainflux.AuthServiceClient, clients mqtt.ClientRegistry, logger mflog.Logger) mqtt.Service {
+	svc := mqtt.NewService(auth, clients)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "mqtt_adapter",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(handler http.Handler, port string, logger mflog.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", port)
+	logger.Info(fmt.Sprintf("MQTT adapter API started using http on port %s", port))
+	errs <- http.ListenAndServe(p, handler)
+}
+
 func proxyMQTT(cfg config, logger mflog.Logger, handler session.Handler, errs chan error) {
 	address := fmt.Sprintf(":%s", cfg.mqttPort)
 	target := fmt.Sprintf("%s:%s", cfg.mqttTargetHost, cfg.mqttTargetPort)
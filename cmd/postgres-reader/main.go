@@ -16,12 +16,15 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
+	authapi "github.com/mainflux/mainflux/auth/api/grpc"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/postgres"
+	rediscache "github.com/mainflux/mainflux/readers/redis"
 	thingsapi "github.com/mainflux/mainflux/things/api/auth/grpc"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
@@ -38,6 +41,8 @@ const (
 	defPort              = "8180"
 	defClientTLS         = "false"
 	defCACerts           = ""
+	defClientCert        = ""
+	defClientKey         = ""
 	defDBHost            = "localhost"
 	defDBPort            = "5432"
 	defDBUser            = "mainflux"
@@ -50,11 +55,21 @@ const (
 	defJaegerURL         = ""
 	defThingsAuthURL     = "localhost:8181"
 	defThingsAuthTimeout = "1s"
+	defCacheURL          = ""
+	defCachePass         = ""
+	defCacheDB           = "0"
+	defCacheTTL          = "5s"
+	defCacheBucket       = "1s"
+	defQueryLog          = "false"
+	defAuthURL           = "localhost:8181"
+	defAuthTimeout       = "1s"
 
 	envLogLevel          = "MF_POSTGRES_READER_LOG_LEVEL"
 	envPort              = "MF_POSTGRES_READER_PORT"
 	envClientTLS         = "MF_POSTGRES_READER_CLIENT_TLS"
 	envCACerts           = "MF_POSTGRES_READER_CA_CERTS"
+	envClientCert        = "MF_AUTH_CLIENT_CERT"
+	envClientKey         = "MF_AUTH_CLIENT_KEY"
 	envDBHost            = "MF_POSTGRES_READER_DB_HOST"
 	envDBPort            = "MF_POSTGRES_READER_DB_PORT"
 	envDBUser            = "MF_POSTGRES_READER_DB_USER"
@@ -67,6 +82,14 @@ const (
 	envJaegerURL         = "MF_JAEGER_URL"
 	envThingsAuthURL     = "MF_THINGS_AUTH_GRPC_URL"
 	envThingsAuthTimeout = "MF_THINGS_AUTH_GRPC_TIMEOUT"
+	envCacheURL          = "MF_POSTGRES_READER_CACHE_URL"
+	envCachePass         = "MF_POSTGRES_READER_CACHE_PASS"
+	envCacheDB           = "MF_POSTGRES_READER_CACHE_DB"
+	envCacheTTL          = "MF_POSTGRES_READER_CACHE_TTL"
+	envCacheBucket       = "MF_POSTGRES_READER_CACHE_BUCKET"
+	envQueryLog          = "MF_POSTGRES_READER_QUERY_LOG"
+	envAuthURL           = "MF_AUTH_GRPC_URL"
+	envAuthTimeout       = "MF_AUTH_GRPC_TIMEOUT"
 )
 
 type config struct {
@@ -74,16 +97,26 @@ type config struct {
 	port              string
 	clientTLS         bool
 	caCerts           string
+	clientCert        string
+	clientKey         string
 	dbConfig          postgres.Config
 	jaegerURL         string
 	thingsAuthURL     string
 	thingsAuthTimeout time.Duration
+	cacheURL          string
+	cachePass         string
+	cacheDB           string
+	cacheTTL          time.Duration
+	cacheBucket       time.Duration
+	queryLog          bool
+	authURL           string
+	authTimeout       time.Duration
 }
 
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -101,9 +134,29 @@ func main() {
 
 	repo := newService(db, logger)
 
+	if cfg.cacheURL != "" {
+		cacheClient := connectToRedis(cfg.cacheURL, cfg.cachePass, cfg.cacheDB, logger)
+		defer cacheClient.Close()
+
+		repo = rediscache.NewCachedRepository(cacheClient, repo, cfg.cacheTTL, cfg.cacheBucket)
+	}
+
+	var logs readers.QueryLogRepository
+	var ac mainflux.AuthServiceClient
+	if cfg.queryLog {
+		authConn := connectToAuth(cfg, logger)
+		defer authConn.Close()
+
+		authTracer, authCloser := initJaeger("auth", cfg.jaegerURL, logger)
+		defer authCloser.Close()
+
+		ac = authapi.NewClient(authTracer, authConn, cfg.authTimeout)
+		logs = api.QueryLogLoggingMiddleware(postgres.NewQueryLogRepository(db), logger)
+	}
+
 	errs := make(chan error, 2)
 
-	go startHTTPServer(repo, tc, cfg.port, logger, errs)
+	go startHTTPServer(repo, tc, logs, ac, cfg.port, logger, errs)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -138,15 +191,45 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envThingsAuthTimeout, err.Error())
 	}
 
+	cacheTTL, err := time.ParseDuration(mainflux.Env(envCacheTTL, defCacheTTL))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheTTL, err.Error())
+	}
+
+	cacheBucket, err := time.ParseDuration(mainflux.Env(envCacheBucket, defCacheBucket))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envCacheBucket, err.Error())
+	}
+
+	queryLog, err := strconv.ParseBool(mainflux.Env(envQueryLog, defQueryLog))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envQueryLog)
+	}
+
+	timeout, err := time.ParseDuration(mainflux.Env(envAuthTimeout, defAuthTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
+	}
+
 	return config{
 		logLevel:          mainflux.Env(envLogLevel, defLogLevel),
 		port:              mainflux.Env(envPort, defPort),
 		clientTLS:         tls,
 		caCerts:           mainflux.Env(envCACerts, defCACerts),
+		clientCert:        mainflux.Env(envClientCert, defClientCert),
+		clientKey:         mainflux.Env(envClientKey, defClientKey),
 		dbConfig:          dbConfig,
 		jaegerURL:         mainflux.Env(envJaegerURL, defJaegerURL),
 		thingsAuthURL:     mainflux.Env(envThingsAuthURL, defThingsAuthURL),
 		thingsAuthTimeout: authTimeout,
+		cacheURL:          mainflux.Env(envCacheURL, defCacheURL),
+		cachePass:         mainflux.Env(envCachePass, defCachePass),
+		cacheDB:           mainflux.Env(envCacheDB, defCacheDB),
+		cacheTTL:          cacheTTL,
+		cacheBucket:       cacheBucket,
+		queryLog:          queryLog,
+		authURL:           mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout:       timeout,
 	}
 }
 
@@ -207,6 +290,41 @@ func connectToThings(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
+func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
+	var opts []grpc.DialOption
+	if cfg.clientTLS {
+		tpc, err := authapi.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to load certs: %s", err))
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+
+	conn, err := grpc.Dial(cfg.authURL, opts...)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to auth service: %s", err))
+		os.Exit(1)
+	}
+	return conn
+}
+
+func connectToRedis(cacheURL, cachePass, cacheDB string, logger logger.Logger) *redis.Client {
+	db, err := strconv.Atoi(cacheDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to cache: %s", err))
+		os.Exit(1)
+	}
+
+	return redis.NewClient(&redis.Options{
+		Addr:     cacheURL,
+		Password: cachePass,
+		DB:       db,
+	})
+}
+
 func newService(db *sqlx.DB, logger logger.Logger) readers.MessageRepository {
 	svc := postgres.New(db)
 	svc = api.LoggingMiddleware(svc, logger)
@@ -229,8 +347,8 @@ func newService(db *sqlx.DB, logger logger.Logger) readers.MessageRepository {
 	return svc
 }
 
-func startHTTPServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient, port string, logger logger.Logger, errs chan error) {
+func startHTTPServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient, logs readers.QueryLogRepository, ac mainflux.AuthServiceClient, port string, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("Postgres reader service started, exposed port %s", port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, svcName))
+	errs <- http.ListenAndServe(p, api.MakeHandler(repo, tc, logs, ac, svcName))
 }
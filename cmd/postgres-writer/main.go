@@ -4,27 +4,38 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis/v8"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/redis"
 	"github.com/mainflux/mainflux/consumers/writers/api"
 	"github.com/mainflux/mainflux/consumers/writers/postgres"
+	"github.com/mainflux/mainflux/internal/cardinality"
+	"github.com/mainflux/mainflux/internal/chaos"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 )
 
 const (
-	svcName = "postgres-writer"
-	sep     = ","
+	svcName     = "postgres-writer"
+	chaosPrefix = "MF_POSTGRES_WRITER_"
+	sep         = ","
 
 	defLogLevel      = "error"
 	defNatsURL       = "nats://localhost:4222"
@@ -39,6 +50,17 @@ const (
 	defDBSSLKey      = ""
 	defDBSSLRootCert = ""
 	defConfigPath    = "/config.toml"
+	defESURL         = ""
+	defESPass        = ""
+	defESDB          = "0"
+	defMetricsLimit  = "0"
+	defMigrationSize = "1000"
+	defMigrationWait = "100ms"
+	defBackfillRate  = "100"
+	defBackfillIdle  = "5s"
+	defStream        = ""
+	defConsumerName  = "postgres-writer"
+	defEvolution     = "none"
 
 	envNatsURL       = "MF_NATS_URL"
 	envLogLevel      = "MF_POSTGRES_WRITER_LOG_LEVEL"
@@ -53,20 +75,46 @@ const (
 	envDBSSLKey      = "MF_POSTGRES_WRITER_DB_SSL_KEY"
 	envDBSSLRootCert = "MF_POSTGRES_WRITER_DB_SSL_ROOT_CERT"
 	envConfigPath    = "MF_POSTGRES_WRITER_CONFIG_PATH"
+	envESURL         = "MF_THINGS_ES_URL"
+	envESPass        = "MF_THINGS_ES_PASS"
+	envESDB          = "MF_THINGS_ES_DB"
+	envMetricsLimit  = "MF_POSTGRES_WRITER_METRICS_CHANNEL_LIMIT"
+	envMigrationSize = "MF_POSTGRES_WRITER_MIGRATION_BATCH_SIZE"
+	envMigrationWait = "MF_POSTGRES_WRITER_MIGRATION_INTERVAL"
+	envBackfillRate  = "MF_POSTGRES_WRITER_BACKFILL_RATE"
+	envBackfillIdle  = "MF_POSTGRES_WRITER_BACKFILL_IDLE_TIMEOUT"
+	envStream        = "MF_POSTGRES_WRITER_STREAM"
+	envConsumerName  = "MF_POSTGRES_WRITER_CONSUMER_NAME"
+	envEvolution     = "MF_POSTGRES_WRITER_SCHEMA_EVOLUTION"
 )
 
 type config struct {
-	natsURL    string
-	logLevel   string
-	port       string
-	configPath string
-	dbConfig   postgres.Config
+	natsURL       string
+	logLevel      string
+	port          string
+	configPath    string
+	esURL         string
+	esPass        string
+	esDB          string
+	dbConfig      postgres.Config
+	metricsLimit  int
+	migrationSize int
+	migrationWait time.Duration
+	backfillRate  int
+	backfillIdle  time.Duration
+	stream        string
+	consumerName  string
+	evolution     postgres.EvolutionPolicy
+	chaos         chaos.Settings
 }
 
 func main() {
+	backfill := flag.String("backfill", "", `backfill the messages from the NDJSON file, or from the NATS subject prefixed with "nats:", and exit`)
+	flag.Parse()
+
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -78,18 +126,69 @@ func main() {
 	}
 	defer pubSub.Close()
 
+	var sub messaging.Subscriber = pubSub
+	var inj *chaos.Injector
+	if cfg.chaos.Enabled {
+		logger.Warn("Fault injection is enabled")
+		inj = chaos.NewInjector(cfg.chaos.Config)
+		sub = chaos.NewPubSub(inj, pubSub)
+	}
+
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
 
-	repo := newService(db, logger)
+	routing, err := postgres.LoadRoutingConfig(cfg.configPath)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to load routing config: %s", err))
+		os.Exit(1)
+	}
+
+	targets := make(map[string]*sqlx.DB)
+	for name, dbConfig := range routing.Databases {
+		tdb := connectToDB(routeDBConfig(dbConfig, cfg.dbConfig), logger)
+		defer tdb.Close()
+		targets[name] = tdb
+	}
+
+	go migrateOnline(db, cfg, logger)
+	for _, tdb := range targets {
+		go migrateOnline(tdb, cfg, logger)
+	}
+
+	repo := newService(db, targets, routing.Channels, cfg, logger)
+
+	if *backfill != "" {
+		runBackfill(*backfill, pubSub, repo, cfg, logger)
+		return
+	}
 
-	if err = consumers.Start(pubSub, repo, cfg.configPath, logger); err != nil {
+	if cfg.stream != "" {
+		if len(targets) > 0 {
+			logger.Error("Failed to create Postgres writer: stream checkpointing doesn't support routing")
+			os.Exit(1)
+		}
+		streamSub, err := nats.NewStreamSubscriber(cfg.natsURL, cfg.stream, logger)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to connect to NATS stream: %s", err))
+			os.Exit(1)
+		}
+		defer streamSub.Close()
+
+		err = consumers.StartStream(streamSub, newCheckpointService(db, cfg, logger), cfg.consumerName, cfg.configPath, logger)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create Postgres writer: %s", err))
+		}
+	} else if err = consumers.Start(sub, repo, newChannelSource(cfg, logger), cfg.configPath, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Postgres writer: %s", err))
 	}
 
 	errs := make(chan error, 2)
 
-	go startHTTPServer(cfg.port, errs, logger)
+	handler := api.MakeHandler(svcName)
+	if inj != nil {
+		handler = chaos.Wrap(inj, cfg.chaos.AdminToken, handler)
+	}
+	go startHTTPServer(cfg.port, handler, errs, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -114,12 +213,59 @@ func loadConfig() config {
 		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
 	}
 
+	metricsLimit, err := strconv.Atoi(mainflux.Env(envMetricsLimit, defMetricsLimit))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMetricsLimit, err.Error())
+	}
+
+	migrationSize, err := strconv.Atoi(mainflux.Env(envMigrationSize, defMigrationSize))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMigrationSize, err.Error())
+	}
+
+	migrationWait, err := time.ParseDuration(mainflux.Env(envMigrationWait, defMigrationWait))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMigrationWait, err.Error())
+	}
+
+	backfillRate, err := strconv.Atoi(mainflux.Env(envBackfillRate, defBackfillRate))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackfillRate, err.Error())
+	}
+
+	backfillIdle, err := time.ParseDuration(mainflux.Env(envBackfillIdle, defBackfillIdle))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBackfillIdle, err.Error())
+	}
+
+	evolution, err := postgres.ParseEvolutionPolicy(mainflux.Env(envEvolution, defEvolution))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envEvolution, err.Error())
+	}
+
+	chaosSettings, err := chaos.LoadSettings(chaosPrefix)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
 	return config{
-		natsURL:    mainflux.Env(envNatsURL, defNatsURL),
-		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
-		port:       mainflux.Env(envPort, defPort),
-		configPath: mainflux.Env(envConfigPath, defConfigPath),
-		dbConfig:   dbConfig,
+		natsURL:       mainflux.Env(envNatsURL, defNatsURL),
+		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
+		port:          mainflux.Env(envPort, defPort),
+		configPath:    mainflux.Env(envConfigPath, defConfigPath),
+		esURL:         mainflux.Env(envESURL, defESURL),
+		esPass:        mainflux.Env(envESPass, defESPass),
+		esDB:          mainflux.Env(envESDB, defESDB),
+		dbConfig:      dbConfig,
+		metricsLimit:  metricsLimit,
+		migrationSize: migrationSize,
+		migrationWait: migrationWait,
+		backfillRate:  backfillRate,
+		backfillIdle:  backfillIdle,
+		chaos:         chaosSettings,
+		stream:        mainflux.Env(envStream, defStream),
+		consumerName:  mainflux.Env(envConsumerName, defConsumerName),
+		evolution:     evolution,
 	}
 }
 
@@ -132,30 +278,145 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 	return db
 }
 
-func newService(db *sqlx.DB, logger logger.Logger) consumers.Consumer {
-	svc := postgres.New(db)
+// migrateOnline applies the online migrations while the writer consumes the
+// messages.
+func migrateOnline(db *sqlx.DB, cfg config, logger logger.Logger) {
+	mr := postgres.NewMigrator(db, cfg.migrationSize, cfg.migrationWait)
+	switch err := mr.Migrate(context.Background(), postgres.OnlineMigrations); {
+	case err == nil:
+	case errors.Contains(err, postgres.ErrMigrationLocked):
+		logger.Info(err.Error())
+	default:
+		logger.Error(fmt.Sprintf("Failed to apply online migrations: %s", err))
+	}
+}
+
+// routeDBConfig fills the connection parameters missing from the routing
+// target config with the ones of the default writer database.
+func routeDBConfig(target, def postgres.Config) postgres.Config {
+	if target.Host == "" {
+		target.Host = def.Host
+	}
+	if target.Port == "" {
+		target.Port = def.Port
+	}
+	if target.User == "" {
+		target.User = def.User
+	}
+	if target.Pass == "" {
+		target.Pass = def.Pass
+	}
+	if target.Name == "" {
+		target.Name = def.Name
+	}
+	if target.SSLMode == "" {
+		target.SSLMode = def.SSLMode
+	}
+	return target
+}
+
+func newService(db *sqlx.DB, targets map[string]*sqlx.DB, channels map[string]string, cfg config, logger logger.Logger) consumers.Consumer {
+	evolution := postgres.WithEvolution(cfg.evolution)
+	svc := postgres.New(db, evolution)
+	if len(targets) > 0 {
+		routes := make(map[string]consumers.Consumer)
+		for name, tdb := range targets {
+			routes[name] = postgres.New(tdb, evolution)
+		}
+		svc = postgres.NewRouter(svc, routes, channels)
+	}
 	svc = api.LoggingMiddleware(svc, logger)
-	svc = api.MetricsMiddleware(
-		svc,
-		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
-			Namespace: "postgres",
-			Subsystem: "message_writer",
-			Name:      "request_count",
-			Help:      "Number of requests received.",
-		}, []string{"method"}),
-		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
-			Namespace: "postgres",
-			Subsystem: "message_writer",
-			Name:      "request_latency_microseconds",
-			Help:      "Total duration of requests in microseconds.",
-		}, []string{"method"}),
-	)
-
-	return svc
+
+	counter, latency := makeMetrics(cfg.metricsLimit)
+	if cfg.metricsLimit > 0 {
+		return api.ChannelMetricsMiddleware(svc, counter, latency, cardinality.NewLimiter(cfg.metricsLimit))
+	}
+	return api.MetricsMiddleware(svc, counter, latency)
+}
+
+// newCheckpointService returns the writer storing the stream checkpoint
+// together with the written messages.
+func newCheckpointService(db *sqlx.DB, cfg config, logger logger.Logger) consumers.CheckpointConsumer {
+	svc := postgres.NewCheckpointConsumer(db, postgres.WithEvolution(cfg.evolution))
+	svc = api.CheckpointLoggingMiddleware(svc, logger)
+
+	counter, latency := makeMetrics(cfg.metricsLimit)
+	var channels *cardinality.Limiter
+	if cfg.metricsLimit > 0 {
+		channels = cardinality.NewLimiter(cfg.metricsLimit)
+	}
+	return api.CheckpointMetricsMiddleware(svc, counter, latency, channels)
 }
 
-func startHTTPServer(port string, errs chan error, logger logger.Logger) {
+func makeMetrics(metricsLimit int) (*kitprometheus.Counter, *kitprometheus.Summary) {
+	labels := []string{"method"}
+	if metricsLimit > 0 {
+		labels = append(labels, "channel")
+	}
+	counter := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "postgres",
+		Subsystem: "message_writer",
+		Name:      "request_count",
+		Help:      "Number of requests received.",
+	}, labels)
+	latency := kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+		Namespace: "postgres",
+		Subsystem: "message_writer",
+		Name:      "request_latency_microseconds",
+		Help:      "Total duration of requests in microseconds.",
+	}, labels)
+
+	return counter, latency
+}
+
+func startHTTPServer(port string, handler http.Handler, errs chan error, logger logger.Logger) {
 	p := fmt.Sprintf(":%s", port)
 	logger.Info(fmt.Sprintf("Postgres writer service started, exposed port %s", port))
-	errs <- http.ListenAndServe(p, api.MakeHandler(svcName))
+	errs <- http.ListenAndServe(p, handler)
+}
+
+// runBackfill consumes the messages read from the backfill source through the
+// same transformation and storage path as the live messages.
+func runBackfill(source string, sub messaging.Subscriber, repo consumers.Consumer, cfg config, logger logger.Logger) {
+	src, err := consumers.NewSource(sub, source, cfg.backfillIdle)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to open backfill source: %s", err))
+		os.Exit(1)
+	}
+	defer src.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		<-c
+		cancel()
+	}()
+
+	n, err := consumers.Backfill(ctx, src, repo, cfg.configPath, cfg.backfillRate, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Backfill stopped after %d messages: %s", n, err))
+		return
+	}
+	logger.Info(fmt.Sprintf("Backfilled %d messages", n))
+}
+
+func newChannelSource(cfg config, logger logger.Logger) consumers.ChannelSource {
+	if cfg.esURL == "" {
+		return nil
+	}
+
+	db, err := strconv.Atoi(cfg.esDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things event store: %s", err))
+		os.Exit(1)
+	}
+
+	client := r.NewClient(&r.Options{
+		Addr:     cfg.esURL,
+		Password: cfg.esPass,
+		DB:       db,
+	})
+	return redis.NewChannelSource(client)
 }
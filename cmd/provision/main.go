@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -10,13 +11,17 @@ import (
 	"reflect"
 	"strconv"
 	"syscall"
+	"time"
 
+	r "github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	mfSDK "github.com/mainflux/mainflux/pkg/sdk/go"
 	"github.com/mainflux/mainflux/provision"
 	"github.com/mainflux/mainflux/provision/api"
+	"github.com/mainflux/mainflux/provision/redis"
 )
 
 const (
@@ -40,6 +45,13 @@ const (
 	defBSContent       = ""
 	defCertsHoursValid = "2400h"
 	defCertsKeyBits    = "4096"
+	defFleetStaleAfter = "5m"
+	defFleetCheck      = "1m"
+	defFleetAlertChan  = ""
+	defFleetURL        = "localhost:6379"
+	defFleetPass       = ""
+	defFleetDB         = "0"
+	defNatsURL         = "nats://localhost:4222"
 
 	envConfigFile       = "MF_PROVISION_CONFIG_FILE"
 	envLogLevel         = "MF_PROVISION_LOG_LEVEL"
@@ -61,6 +73,13 @@ const (
 	envBSContent        = "MF_PROVISION_BS_CONTENT"
 	envCertsHoursValid  = "MF_PROVISION_CERTS_HOURS_VALID"
 	envCertsKeyBits     = "MF_PROVISION_CERTS_RSA_BITS"
+	envFleetStaleAfter  = "MF_PROVISION_FLEET_STALE_AFTER"
+	envFleetCheck       = "MF_PROVISION_FLEET_CHECK_INTERVAL"
+	envFleetAlertChan   = "MF_PROVISION_FLEET_ALERT_CHANNEL"
+	envFleetURL         = "MF_PROVISION_FLEET_URL"
+	envFleetPass        = "MF_PROVISION_FLEET_PASS"
+	envFleetDB          = "MF_PROVISION_FLEET_DB"
+	envNatsURL          = "MF_NATS_URL"
 
 	contentType = "application/json"
 )
@@ -74,6 +93,7 @@ var (
 	errFailGettingProvBS            = errors.New("failed to get BS url setting")
 	errFailSettingKeyBits           = errors.New("failed to set rsa number of bits")
 	errFailedToReadBootstrapContent = errors.New("failed to read bootstrap content from envs")
+	errFailGettingFleetSettings     = errors.New("failed to get fleet settings")
 )
 
 func main() {
@@ -81,7 +101,7 @@ func main() {
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
-	logger, err := logger.New(os.Stdout, cfg.Server.LogLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.Server.LogLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -103,12 +123,30 @@ func main() {
 	}
 	SDK := mfSDK.NewSDK(SDKCfg)
 
-	svc := provision.New(cfg, SDK, logger)
+	checkInterval, err := time.ParseDuration(cfg.Fleet.CheckInterval)
+	if err != nil || checkInterval <= 0 {
+		logger.Error(fmt.Sprintf("Invalid fleet check interval: %s", cfg.Fleet.CheckInterval))
+		os.Exit(1)
+	}
+
+	fleetClient := connectToRedis(mainflux.Env(envFleetURL, defFleetURL), mainflux.Env(envFleetPass, defFleetPass), mainflux.Env(envFleetDB, defFleetDB), logger)
+	defer fleetClient.Close()
+
+	pub, err := nats.NewPublisher(mainflux.Env(envNatsURL, defNatsURL))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer pub.Close()
+
+	fleet := redis.NewFleetRepository(fleetClient)
+	svc := provision.New(cfg, SDK, fleet, pub, logger)
 	svc = api.NewLoggingMiddleware(svc, logger)
 
 	errs := make(chan error, 2)
 
 	go startHTTPServer(svc, cfg, logger, errs)
+	go checkFleet(svc, checkInterval, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -132,6 +170,31 @@ func startHTTPServer(svc provision.Service, cfg provision.Config, logger logger.
 	errs <- http.ListenAndServe(p, api.MakeHandler(svc))
 }
 
+func checkFleet(svc provision.Service, interval time.Duration, logger logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := svc.CheckFleet(context.Background()); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to check gateway fleet: %s", err))
+		}
+	}
+}
+
+func connectToRedis(redisURL, redisPass, redisDB string, logger logger.Logger) *r.Client {
+	db, err := strconv.Atoi(redisDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to redis: %s", err))
+		os.Exit(1)
+	}
+
+	return r.NewClient(&r.Options{
+		Addr:     redisURL,
+		Password: redisPass,
+		DB:       db,
+	})
+}
+
 func loadConfigFromFile(file string) (provision.Config, error) {
 	_, err := os.Stat(file)
 	if os.IsNotExist(err) {
@@ -170,6 +233,15 @@ func loadConfig() (provision.Config, error) {
 		return provision.Config{}, errFailSettingKeyBits
 	}
 
+	staleAfter := mainflux.Env(envFleetStaleAfter, defFleetStaleAfter)
+	if _, err := time.ParseDuration(staleAfter); err != nil {
+		return provision.Config{}, errors.Wrap(errFailGettingFleetSettings, err)
+	}
+	checkInterval := mainflux.Env(envFleetCheck, defFleetCheck)
+	if d, err := time.ParseDuration(checkInterval); err != nil || d <= 0 {
+		return provision.Config{}, errors.Wrap(errFailGettingFleetSettings, fmt.Errorf(" for %s", envFleetCheck))
+	}
+
 	var content map[string]interface{}
 	if c := mainflux.Env(envBSContent, defBSContent); c != "" {
 		if err = json.Unmarshal([]byte(c), content); err != nil {
@@ -203,6 +275,11 @@ func loadConfig() (provision.Config, error) {
 			AutoWhiteList: autoWhiteList,
 			Content:       content,
 		},
+		Fleet: provision.Fleet{
+			StaleAfter:    staleAfter,
+			CheckInterval: checkInterval,
+			AlertChannel:  mainflux.Env(envFleetAlertChan, defFleetAlertChan),
+		},
 
 		// This is default conf for provision if there is no config file
 		Channels: []provision.Channel{
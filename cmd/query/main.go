@@ -0,0 +1,170 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/query"
+	"github.com/mainflux/mainflux/query/api"
+	opentracing "github.com/opentracing/opentracing-go"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	jconfig "github.com/uber/jaeger-client-go/config"
+)
+
+const (
+	defLogLevel   = "error"
+	defHTTPPort   = "8217"
+	defJaegerURL  = ""
+	defServerCert = ""
+	defServerKey  = ""
+	defReaderURL  = "http://localhost:8905"
+	defTLSVerify  = "true"
+	defMaxScan    = "100000"
+
+	envLogLevel   = "MF_QUERY_LOG_LEVEL"
+	envHTTPPort   = "MF_QUERY_HTTP_PORT"
+	envJaegerURL  = "MF_JAEGER_URL"
+	envServerCert = "MF_QUERY_SERVER_CERT"
+	envServerKey  = "MF_QUERY_SERVER_KEY"
+	envReaderURL  = "MF_QUERY_READER_URL"
+	envTLSVerify  = "MF_QUERY_TLS_VERIFICATION"
+	envMaxScan    = "MF_QUERY_MAX_SCAN"
+)
+
+type config struct {
+	logLevel   string
+	httpPort   string
+	jaegerURL  string
+	serverCert string
+	serverKey  string
+	maxScan    uint64
+	sdkConfig  mfsdk.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	svc := newService(cfg, logger)
+
+	tracer, closer := initJaeger("query", cfg.jaegerURL, logger)
+	defer closer.Close()
+
+	errs := make(chan error, 2)
+	go startHTTPServer(api.MakeHandler(tracer, svc), cfg, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Query service terminated: %s", err))
+}
+
+func loadConfig() config {
+	tlsVerify, err := strconv.ParseBool(mainflux.Env(envTLSVerify, defTLSVerify))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envTLSVerify)
+	}
+
+	maxScan, err := strconv.ParseUint(mainflux.Env(envMaxScan, defMaxScan), 10, 64)
+	if err != nil || maxScan == 0 {
+		log.Fatalf("Invalid value passed for %s\n", envMaxScan)
+	}
+
+	sdkConfig := mfsdk.Config{
+		ReaderURL:       mainflux.Env(envReaderURL, defReaderURL),
+		MsgContentType:  mfsdk.CTJSONSenML,
+		TLSVerification: tlsVerify,
+	}
+
+	return config{
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:   mainflux.Env(envHTTPPort, defHTTPPort),
+		jaegerURL:  mainflux.Env(envJaegerURL, defJaegerURL),
+		serverCert: mainflux.Env(envServerCert, defServerCert),
+		serverKey:  mainflux.Env(envServerKey, defServerKey),
+		maxScan:    maxScan,
+		sdkConfig:  sdkConfig,
+	}
+}
+
+func initJaeger(svcName, url string, logger logger.Logger) (opentracing.Tracer, io.Closer) {
+	if url == "" {
+		return opentracing.NoopTracer{}, ioutil.NopCloser(nil)
+	}
+
+	tracer, closer, err := jconfig.Configuration{
+		ServiceName: svcName,
+		Sampler: &jconfig.SamplerConfig{
+			Type:  "const",
+			Param: 1,
+		},
+		Reporter: &jconfig.ReporterConfig{
+			LocalAgentHostPort: url,
+			LogSpans:           true,
+		},
+	}.NewTracer()
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to init Jaeger client: %s", err))
+		os.Exit(1)
+	}
+
+	return tracer, closer
+}
+
+func newService(cfg config, logger logger.Logger) query.Service {
+	sdk := mfsdk.NewSDK(cfg.sdkConfig)
+
+	svc := query.New(sdk, cfg.maxScan)
+	svc = api.LoggingMiddleware(svc, logger)
+	svc = api.MetricsMiddleware(
+		svc,
+		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "query",
+			Subsystem: "api",
+			Name:      "request_count",
+			Help:      "Number of requests received.",
+		}, []string{"method"}),
+		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "query",
+			Subsystem: "api",
+			Name:      "request_latency_microseconds",
+			Help:      "Total duration of requests in microseconds.",
+		}, []string{"method"}),
+	)
+
+	return svc
+}
+
+func startHTTPServer(handler http.Handler, cfg config, logger logger.Logger, errs chan error) {
+	p := fmt.Sprintf(":%s", cfg.httpPort)
+	if cfg.serverCert != "" || cfg.serverKey != "" {
+		logger.Info(fmt.Sprintf("Query service started using https on port %s with cert %s key %s",
+			cfg.httpPort, cfg.serverCert, cfg.serverKey))
+		errs <- http.ListenAndServeTLS(p, cfg.serverCert, cfg.serverKey, handler)
+		return
+	}
+	logger.Info(fmt.Sprintf("Query service started using http on port %s", cfg.httpPort))
+	errs <- http.ListenAndServe(p, handler)
+}
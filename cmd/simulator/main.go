@@ -0,0 +1,256 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/simulator"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	defLogLevel   = "error"
+	defHTTPPort   = "8212"
+	defUsersURL   = "http://localhost:8180"
+	defThingsURL  = "http://localhost:8182"
+	defProtocol   = simulator.HTTP
+	defAdapterURL = "http://localhost:8185"
+	defTLSVerify  = "true"
+	defEmail      = ""
+	defPassword   = ""
+	defThings     = "10"
+	defChannels   = "1"
+	defRate       = "1"
+	defDuration   = "0s"
+	defPattern    = simulator.Sine
+	defBase       = "20"
+	defAmplitude  = "5"
+	defPeriod     = "1m"
+	defName       = "temperature"
+	defUnit       = "Cel"
+
+	envLogLevel   = "MF_SIMULATOR_LOG_LEVEL"
+	envHTTPPort   = "MF_SIMULATOR_HTTP_PORT"
+	envUsersURL   = "MF_SIMULATOR_USERS_URL"
+	envThingsURL  = "MF_SIMULATOR_THINGS_URL"
+	envProtocol   = "MF_SIMULATOR_PROTOCOL"
+	envAdapterURL = "MF_SIMULATOR_ADAPTER_URL"
+	envTLSVerify  = "MF_SIMULATOR_TLS_VERIFICATION"
+	envEmail      = "MF_SIMULATOR_USER_EMAIL"
+	envPassword   = "MF_SIMULATOR_USER_PASSWORD"
+	envThings     = "MF_SIMULATOR_THINGS"
+	envChannels   = "MF_SIMULATOR_CHANNELS"
+	envRate       = "MF_SIMULATOR_RATE"
+	envDuration   = "MF_SIMULATOR_DURATION"
+	envPattern    = "MF_SIMULATOR_PATTERN"
+	envBase       = "MF_SIMULATOR_BASE"
+	envAmplitude  = "MF_SIMULATOR_AMPLITUDE"
+	envPeriod     = "MF_SIMULATOR_PERIOD"
+	envName       = "MF_SIMULATOR_NAME"
+	envUnit       = "MF_SIMULATOR_UNIT"
+)
+
+type config struct {
+	logLevel   string
+	httpPort   string
+	protocol   string
+	adapterURL string
+	email      string
+	password   string
+	sdkConfig  mfsdk.Config
+	simConfig  simulator.Config
+}
+
+func main() {
+	cfg := loadConfig()
+
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
+	if err != nil {
+		log.Fatalf(err.Error())
+	}
+
+	sdk := mfsdk.NewSDK(cfg.sdkConfig)
+	dial, err := simulator.NewDialer(cfg.protocol, cfg.adapterURL, sdk)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create %s dialer: %s", cfg.protocol, err))
+		os.Exit(1)
+	}
+
+	sim, err := simulator.New(cfg.simConfig, sdk, dial, newMetrics(cfg.protocol), logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to create simulator: %s", err))
+		os.Exit(1)
+	}
+
+	token, err := sdk.CreateToken(mfsdk.User{Email: cfg.email, Password: cfg.password})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to log in as %s: %s", cfg.email, err))
+		os.Exit(1)
+	}
+
+	ths, err := sim.Provision(token)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to provision things: %s", err))
+		os.Exit(1)
+	}
+	logger.Info(fmt.Sprintf("Provisioned %d things over %d channels", len(ths), cfg.simConfig.Channels))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errs := make(chan error, 2)
+	go startHTTPServer(cfg.httpPort, logger, errs)
+
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGINT)
+		errs <- fmt.Errorf("%s", <-c)
+	}()
+
+	go func() {
+		stats, err := sim.Run(ctx, ths)
+		if err != nil {
+			errs <- err
+			return
+		}
+		rate := float64(stats.Published) / stats.Elapsed.Seconds()
+		logger.Info(fmt.Sprintf("Published %d messages, %d failed, in %s (%.2f msg/s)", stats.Published, stats.Failed, stats.Elapsed, rate))
+		errs <- fmt.Errorf("simulation completed")
+	}()
+
+	err = <-errs
+	logger.Error(fmt.Sprintf("Simulator terminated: %s", err))
+}
+
+func loadConfig() config {
+	tlsVerify, err := strconv.ParseBool(mainflux.Env(envTLSVerify, defTLSVerify))
+	if err != nil {
+		log.Fatalf("Invalid value passed for %s\n", envTLSVerify)
+	}
+
+	things, err := strconv.Atoi(mainflux.Env(envThings, defThings))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envThings, err.Error())
+	}
+
+	channels, err := strconv.Atoi(mainflux.Env(envChannels, defChannels))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envChannels, err.Error())
+	}
+
+	rate, err := strconv.ParseFloat(mainflux.Env(envRate, defRate), 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envRate, err.Error())
+	}
+
+	duration, err := time.ParseDuration(mainflux.Env(envDuration, defDuration))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envDuration, err.Error())
+	}
+
+	base, err := strconv.ParseFloat(mainflux.Env(envBase, defBase), 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envBase, err.Error())
+	}
+
+	amplitude, err := strconv.ParseFloat(mainflux.Env(envAmplitude, defAmplitude), 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envAmplitude, err.Error())
+	}
+
+	period, err := time.ParseDuration(mainflux.Env(envPeriod, defPeriod))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envPeriod, err.Error())
+	}
+
+	adapterURL := mainflux.Env(envAdapterURL, defAdapterURL)
+
+	return config{
+		logLevel:   mainflux.Env(envLogLevel, defLogLevel),
+		httpPort:   mainflux.Env(envHTTPPort, defHTTPPort),
+		protocol:   mainflux.Env(envProtocol, defProtocol),
+		adapterURL: adapterURL,
+		email:      mainflux.Env(envEmail, defEmail),
+		password:   mainflux.Env(envPassword, defPassword),
+		sdkConfig: mfsdk.Config{
+			UsersURL:        mainflux.Env(envUsersURL, defUsersURL),
+			ThingsURL:       mainflux.Env(envThingsURL, defThingsURL),
+			HTTPAdapterURL:  adapterURL,
+			MsgContentType:  mfsdk.CTJSONSenML,
+			TLSVerification: tlsVerify,
+		},
+		simConfig: simulator.Config{
+			Things:   things,
+			Channels: channels,
+			Rate:     rate,
+			Duration: duration,
+			Pattern:  mainflux.Env(envPattern, defPattern),
+			PatternConfig: simulator.PatternConfig{
+				Base:      base,
+				Amplitude: amplitude,
+				Period:    period,
+			},
+			Name: mainflux.Env(envName, defName),
+			Unit: mainflux.Env(envUnit, defUnit),
+		},
+	}
+}
+
+type publishMetrics struct {
+	counter  metrics.Counter
+	latency  metrics.Histogram
+	protocol string
+}
+
+func newMetrics(protocol string) simulator.Metrics {
+	return publishMetrics{
+		counter: kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: "simulator",
+			Subsystem: "publisher",
+			Name:      "message_count",
+			Help:      "Number of messages published.",
+		}, []string{"protocol", "status"}),
+		latency: kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+			Namespace: "simulator",
+			Subsystem: "publisher",
+			Name:      "publish_latency_seconds",
+			Help:      "Duration of publish requests in seconds.",
+		}, []string{"protocol"}),
+		protocol: protocol,
+	}
+}
+
+func (pm publishMetrics) Observe(_ string, latency time.Duration, err error) {
+	status := "success"
+	if err != nil {
+		status = "failure"
+	}
+	pm.counter.With("protocol", pm.protocol, "status", status).Add(1)
+	pm.latency.With("protocol", pm.protocol).Observe(latency.Seconds())
+}
+
+func startHTTPServer(port string, logger logger.Logger, errs chan error) {
+	r := bone.New()
+	r.GetFunc("/version", mainflux.Version("simulator"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	logger.Info(fmt.Sprintf("Simulator metrics available using http on port %s", port))
+	errs <- http.ListenAndServe(fmt.Sprintf(":%s", port), r)
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -16,6 +17,7 @@ import (
 	"time"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	r "github.com/go-redis/redis/v8"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	authapi "github.com/mainflux/mainflux/auth/api/grpc"
@@ -23,9 +25,12 @@ import (
 	"github.com/mainflux/mainflux/consumers/notifiers"
 	"github.com/mainflux/mainflux/consumers/notifiers/api"
 	"github.com/mainflux/mainflux/consumers/notifiers/postgres"
+	"github.com/mainflux/mainflux/consumers/redis"
 
 	mfsmpp "github.com/mainflux/mainflux/consumers/notifiers/smpp"
 	"github.com/mainflux/mainflux/consumers/notifiers/tracing"
+	"github.com/mainflux/mainflux/consumers/notifiers/webhook"
+	"github.com/mainflux/mainflux/internal/i18n"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	"github.com/mainflux/mainflux/pkg/ulid"
@@ -33,17 +38,20 @@ import (
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
 	defLogLevel      = "error"
+	defI18nDir       = ""
 	defDBHost        = "localhost"
 	defDBPort        = "5432"
 	defDBUser        = "mainflux"
 	defDBPass        = "mainflux"
 	defDB            = "subscriptions"
 	defConfigPath    = "/config.toml"
+	defESURL         = ""
+	defESPass        = ""
+	defESDB          = "0"
 	defDBSSLMode     = "disable"
 	defDBSSLCert     = ""
 	defDBSSLKey      = ""
@@ -55,6 +63,9 @@ const (
 	defJaegerURL     = ""
 	defNatsURL       = "nats://localhost:4222"
 
+	defEscalationInterval = "1m"
+	defWebhookTimeout     = "10s"
+
 	defSmppAddress    = ""
 	defSmppUsername   = ""
 	defSmppPassword   = ""
@@ -66,16 +77,22 @@ const (
 
 	defAuthTLS     = "false"
 	defAuthCACerts = ""
+	defAuthCert    = ""
+	defAuthKey     = ""
 	defAuthURL     = "localhost:8181"
 	defAuthTimeout = "1s"
 
 	envLogLevel      = "MF_SMPP_NOTIFIER_LOG_LEVEL"
+	envI18nDir       = "MF_SMPP_NOTIFIER_I18N_DIR"
 	envDBHost        = "MF_SMPP_NOTIFIER_DB_HOST"
 	envDBPort        = "MF_SMPP_NOTIFIER_DB_PORT"
 	envDBUser        = "MF_SMPP_NOTIFIER_DB_USER"
 	envDBPass        = "MF_SMPP_NOTIFIER_DB_PASS"
 	envDB            = "MF_SMPP_NOTIFIER_DB"
 	envConfigPath    = "MF_SMPP_NOTIFIER_WRITER_CONFIG_PATH"
+	envESURL         = "MF_THINGS_ES_URL"
+	envESPass        = "MF_THINGS_ES_PASS"
+	envESDB          = "MF_THINGS_ES_DB"
 	envDBSSLMode     = "MF_SMPP_NOTIFIER_DB_SSL_MODE"
 	envDBSSLCert     = "MF_SMPP_NOTIFIER_DB_SSL_CERT"
 	envDBSSLKey      = "MF_SMPP_NOTIFIER_DB_SSL_KEY"
@@ -87,6 +104,9 @@ const (
 	envJaegerURL     = "MF_JAEGER_URL"
 	envNatsURL       = "MF_NATS_URL"
 
+	envEscalationInterval = "MF_SMPP_NOTIFIER_ESCALATION_INTERVAL"
+	envWebhookTimeout     = "MF_SMPP_NOTIFIER_WEBHOOK_TIMEOUT"
+
 	envSmppAddress    = "MF_SMPP_ADDRESS"
 	envSmppUsername   = "MF_SMPP_USERNAME"
 	envSmppPassword   = "MF_SMPP_PASSWORD"
@@ -98,13 +118,19 @@ const (
 
 	envAuthTLS     = "MF_AUTH_CLIENT_TLS"
 	envAuthCACerts = "MF_AUTH_CA_CERTS"
+	envAuthCert    = "MF_AUTH_CLIENT_CERT"
+	envAuthKey     = "MF_AUTH_CLIENT_KEY"
 	envAuthURL     = "MF_AUTH_GRPC_URL"
 	envAuthTimeout = "MF_AUTH_GRPC_TIMEOUT"
 )
 
 type config struct {
+	i18nDir     string
 	natsURL     string
 	configPath  string
+	esURL       string
+	esPass      string
+	esDB        string
 	logLevel    string
 	dbConfig    postgres.Config
 	smppConf    mfsmpp.Config
@@ -115,18 +141,28 @@ type config struct {
 	jaegerURL   string
 	authTLS     bool
 	authCACerts string
+	authCert    string
+	authKey     string
 	authURL     string
 	authTimeout time.Duration
+
+	escalationInterval time.Duration
+	webhookTimeout     time.Duration
 }
 
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
 
+	if err := i18n.LoadCatalogs(cfg.i18nDir); err != nil {
+		logger.Error(fmt.Sprintf("Failed to load message catalogs: %s", err))
+		os.Exit(1)
+	}
+
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
 
@@ -154,11 +190,12 @@ func main() {
 	svc := newService(db, dbTracer, auth, cfg, logger)
 	errs := make(chan error, 2)
 
-	if err = consumers.Start(pubSub, svc, cfg.configPath, logger); err != nil {
+	if err = consumers.Start(pubSub, svc, newChannelSource(cfg, logger), cfg.configPath, logger); err != nil {
 		logger.Error(fmt.Sprintf("Failed to create Postgres writer: %s", err))
 	}
 
 	go startHTTPServer(tracer, svc, cfg.httpPort, cfg.serverCert, cfg.serverKey, logger, errs)
+	go escalate(svc, cfg.escalationInterval, logger)
 
 	go func() {
 		c := make(chan os.Signal)
@@ -176,6 +213,16 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
 	}
 
+	escalationInterval, err := time.ParseDuration(mainflux.Env(envEscalationInterval, defEscalationInterval))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envEscalationInterval, err.Error())
+	}
+
+	webhookTimeout, err := time.ParseDuration(mainflux.Env(envWebhookTimeout, defWebhookTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhookTimeout, err.Error())
+	}
+
 	tls, err := strconv.ParseBool(mainflux.Env(envAuthTLS, defAuthTLS))
 	if err != nil {
 		log.Fatalf("Invalid value passed for %s\n", envAuthTLS)
@@ -223,8 +270,12 @@ func loadConfig() config {
 
 	return config{
 		logLevel:    mainflux.Env(envLogLevel, defLogLevel),
+		i18nDir:     mainflux.Env(envI18nDir, defI18nDir),
 		natsURL:     mainflux.Env(envNatsURL, defNatsURL),
 		configPath:  mainflux.Env(envConfigPath, defConfigPath),
+		esURL:       mainflux.Env(envESURL, defESURL),
+		esPass:      mainflux.Env(envESPass, defESPass),
+		esDB:        mainflux.Env(envESDB, defESDB),
 		dbConfig:    dbConfig,
 		smppConf:    smppConf,
 		from:        mainflux.Env(envFrom, defFrom),
@@ -234,8 +285,13 @@ func loadConfig() config {
 		jaegerURL:   mainflux.Env(envJaegerURL, defJaegerURL),
 		authTLS:     tls,
 		authCACerts: mainflux.Env(envAuthCACerts, defAuthCACerts),
+		authCert:    mainflux.Env(envAuthCert, defAuthCert),
+		authKey:     mainflux.Env(envAuthKey, defAuthKey),
 		authURL:     mainflux.Env(envAuthURL, defAuthURL),
 		authTimeout: authTimeout,
+
+		escalationInterval: escalationInterval,
+		webhookTimeout:     webhookTimeout,
 	}
 
 }
@@ -276,14 +332,12 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 func connectToAuth(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.AuthServiceClient, func() error) {
 	var opts []grpc.DialOption
 	if cfg.authTLS {
-		if cfg.authCACerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.authCACerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := authapi.ClientCredentials(cfg.authCACerts, cfg.authCert, cfg.authKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 		logger.Info("gRPC communication is not encrypted")
@@ -301,9 +355,14 @@ func connectToAuth(cfg config, tracer opentracing.Tracer, logger logger.Logger)
 func newService(db *sqlx.DB, tracer opentracing.Tracer, auth mainflux.AuthServiceClient, c config, logger logger.Logger) notifiers.Service {
 	database := postgres.NewDatabase(db)
 	repo := tracing.New(postgres.New(database), tracer)
+	escalations := tracing.NewEscalationRepository(postgres.NewEscalationRepo(database), tracer)
 	idp := ulid.New()
 	notifier := mfsmpp.New(c.smppConf)
-	svc := notifiers.New(auth, repo, idp, notifier, c.from)
+	channels := map[string]notifiers.Notifier{
+		notifiers.SMSChannel:     notifier,
+		notifiers.WebhookChannel: webhook.New(&http.Client{Timeout: c.webhookTimeout}),
+	}
+	svc := notifiers.New(auth, repo, escalations, idp, notifier, channels, c.from)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
 		svc,
@@ -323,6 +382,17 @@ func newService(db *sqlx.DB, tracer opentracing.Tracer, auth mainflux.AuthServic
 	return svc
 }
 
+func escalate(svc notifiers.Service, interval time.Duration, logger logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := svc.Escalate(context.Background()); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to escalate alerts: %s", err))
+		}
+	}
+}
+
 func startHTTPServer(tracer opentracing.Tracer, svc notifiers.Service, port string, certFile string, keyFile string, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	if certFile != "" || keyFile != "" {
@@ -333,3 +403,22 @@ func startHTTPServer(tracer opentracing.Tracer, svc notifiers.Service, port stri
 		errs <- http.ListenAndServe(p, api.MakeHandler(svc, tracer))
 	}
 }
+
+func newChannelSource(cfg config, logger logger.Logger) consumers.ChannelSource {
+	if cfg.esURL == "" {
+		return nil
+	}
+
+	db, err := strconv.Atoi(cfg.esDB)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to things event store: %s", err))
+		os.Exit(1)
+	}
+
+	client := r.NewClient(&r.Options{
+		Addr:     cfg.esURL,
+		Password: cfg.esPass,
+		DB:       db,
+	})
+	return redis.NewChannelSource(client)
+}
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
 	"strconv"
 	"syscall"
 	"time"
@@ -21,7 +22,10 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	authapi "github.com/mainflux/mainflux/auth/api/grpc"
+	"github.com/mainflux/mainflux/internal/jobs"
 	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/messaging/nats"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/api"
@@ -32,6 +36,7 @@ import (
 	rediscache "github.com/mainflux/mainflux/things/redis"
 	localusers "github.com/mainflux/mainflux/things/standalone"
 	"github.com/mainflux/mainflux/things/tracing"
+	"github.com/mainflux/mainflux/things/webhook"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
@@ -52,6 +57,8 @@ const (
 	defDBSSLRootCert   = ""
 	defClientTLS       = "false"
 	defCACerts         = ""
+	defClientCert      = ""
+	defClientKey       = ""
 	defCacheURL        = "localhost:6379"
 	defCachePass       = ""
 	defCacheDB         = "0"
@@ -68,6 +75,14 @@ const (
 	defJaegerURL       = ""
 	defAuthURL         = "localhost:8181"
 	defAuthTimeout     = "1s"
+	defConsistency     = consistencyEventual
+	defNatsURL         = "nats://localhost:4222"
+	defWebhooksLimit   = "10"
+	defWebhooksFlush   = "5s"
+	defWebhooksTimeout = "5s"
+	defJobTimeout      = "1h"
+	defJobRetention    = "24h"
+	defSerialFormat    = ""
 
 	envLogLevel        = "MF_THINGS_LOG_LEVEL"
 	envDBHost          = "MF_THINGS_DB_HOST"
@@ -81,6 +96,8 @@ const (
 	envDBSSLRootCert   = "MF_THINGS_DB_SSL_ROOT_CERT"
 	envClientTLS       = "MF_THINGS_CLIENT_TLS"
 	envCACerts         = "MF_THINGS_CA_CERTS"
+	envClientCert      = "MF_AUTH_CLIENT_CERT"
+	envClientKey       = "MF_AUTH_CLIENT_KEY"
 	envCacheURL        = "MF_THINGS_CACHE_URL"
 	envCachePass       = "MF_THINGS_CACHE_PASS"
 	envCacheDB         = "MF_THINGS_CACHE_DB"
@@ -97,6 +114,21 @@ const (
 	envJaegerURL       = "MF_JAEGER_URL"
 	envAuthURL         = "MF_AUTH_GRPC_URL"
 	envAuthTimeout     = "MF_AUTH_GRPC_TIMEOUT"
+	envConsistency     = "MF_THINGS_CACHE_CONSISTENCY"
+	envNatsURL         = "MF_NATS_URL"
+	envWebhooksLimit   = "MF_THINGS_WEBHOOKS_LIMIT"
+	envWebhooksFlush   = "MF_THINGS_WEBHOOKS_FLUSH_INTERVAL"
+	envWebhooksTimeout = "MF_THINGS_WEBHOOKS_TIMEOUT"
+	envJobTimeout      = "MF_THINGS_JOB_TIMEOUT"
+	envJobRetention    = "MF_THINGS_JOB_RETENTION"
+	envSerialFormat    = "MF_THINGS_SERIAL_FORMAT"
+
+	consistencyEventual       = "eventual"
+	consistencyReadYourWrites = "read-your-writes"
+
+	// serialMetadataKey is the thing metadata holding the device serial
+	// number, checked against the serial format.
+	serialMetadataKey = "serial"
 )
 
 type config struct {
@@ -104,6 +136,8 @@ type config struct {
 	dbConfig        postgres.Config
 	clientTLS       bool
 	caCerts         string
+	clientCert      string
+	clientKey       string
 	cacheURL        string
 	cachePass       string
 	cacheDB         string
@@ -120,12 +154,20 @@ type config struct {
 	jaegerURL       string
 	authURL         string
 	authTimeout     time.Duration
+	readYourWrites  bool
+	natsURL         string
+	webhooksLimit   uint64
+	webhooksFlush   time.Duration
+	webhooksTimeout time.Duration
+	jobTimeout      time.Duration
+	jobRetention    time.Duration
+	serialFormat    *regexp.Regexp
 }
 
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -154,10 +196,30 @@ func main() {
 	cacheTracer, cacheCloser := initJaeger("things_cache", cfg.jaegerURL, logger)
 	defer cacheCloser.Close()
 
-	svc := newService(auth, dbTracer, cacheTracer, db, cacheClient, esClient, logger)
+	pubSub, err := nats.NewPubSub(cfg.natsURL, "things", logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Failed to connect to NATS: %s", err))
+		os.Exit(1)
+	}
+	defer pubSub.Close()
+
+	webhooksRepo := postgres.NewWebhookRepository(postgres.NewDatabase(db))
+	webhooksRepo = tracing.WebhookRepositoryMiddleware(dbTracer, webhooksRepo)
+
+	svc := newService(auth, dbTracer, cacheTracer, db, webhooksRepo, cacheClient, esClient, cfg, logger)
 	errs := make(chan error, 2)
 
-	go startHTTPServer(thhttpapi.MakeHandler(thingsTracer, svc), cfg.httpPort, cfg, logger, errs)
+	dispatcher := webhook.New(webhooksRepo, &http.Client{Timeout: cfg.webhooksTimeout}, logger)
+	if err := subscribeToWebhooks(pubSub, dispatcher, cfg.webhooksFlush, logger); err != nil {
+		logger.Error(fmt.Sprintf("Failed to subscribe to messages: %s", err))
+		os.Exit(1)
+	}
+
+	// The jobs are kept in the cache, so they're shared by the instances.
+	jobManager := jobs.NewManager(jobs.NewRedisStore(cacheClient), uuid.New(), cfg.jobTimeout, cfg.jobRetention)
+	jsvc := things.NewJobService(svc, auth, jobManager)
+
+	go startHTTPServer(thhttpapi.MakeHandler(thingsTracer, svc, jsvc), cfg.httpPort, cfg, logger, errs)
 	go startHTTPServer(authhttpapi.MakeHandler(thingsTracer, svc), cfg.authHTTPPort, cfg, logger, errs)
 	go startGRPCServer(svc, thingsTracer, cfg, logger, errs)
 
@@ -182,6 +244,43 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
 	}
 
+	consistency := mainflux.Env(envConsistency, defConsistency)
+	if consistency != consistencyEventual && consistency != consistencyReadYourWrites {
+		log.Fatalf("Invalid %s value: %s", envConsistency, consistency)
+	}
+
+	webhooksLimit, err := strconv.ParseUint(mainflux.Env(envWebhooksLimit, defWebhooksLimit), 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhooksLimit, err.Error())
+	}
+
+	var serialFormat *regexp.Regexp
+	if s := mainflux.Env(envSerialFormat, defSerialFormat); s != "" {
+		if serialFormat, err = regexp.Compile(s); err != nil {
+			log.Fatalf("Invalid %s value: %s", envSerialFormat, err.Error())
+		}
+	}
+
+	webhooksFlush, err := time.ParseDuration(mainflux.Env(envWebhooksFlush, defWebhooksFlush))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhooksFlush, err.Error())
+	}
+
+	webhooksTimeout, err := time.ParseDuration(mainflux.Env(envWebhooksTimeout, defWebhooksTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhooksTimeout, err.Error())
+	}
+
+	jobTimeout, err := time.ParseDuration(mainflux.Env(envJobTimeout, defJobTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJobTimeout, err.Error())
+	}
+
+	jobRetention, err := time.ParseDuration(mainflux.Env(envJobRetention, defJobRetention))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envJobRetention, err.Error())
+	}
+
 	dbConfig := postgres.Config{
 		Host:        mainflux.Env(envDBHost, defDBHost),
 		Port:        mainflux.Env(envDBPort, defDBPort),
@@ -199,6 +298,8 @@ func loadConfig() config {
 		dbConfig:        dbConfig,
 		clientTLS:       tls,
 		caCerts:         mainflux.Env(envCACerts, defCACerts),
+		clientCert:      mainflux.Env(envClientCert, defClientCert),
+		clientKey:       mainflux.Env(envClientKey, defClientKey),
 		cacheURL:        mainflux.Env(envCacheURL, defCacheURL),
 		cachePass:       mainflux.Env(envCachePass, defCachePass),
 		cacheDB:         mainflux.Env(envCacheDB, defCacheDB),
@@ -215,6 +316,14 @@ func loadConfig() config {
 		jaegerURL:       mainflux.Env(envJaegerURL, defJaegerURL),
 		authURL:         mainflux.Env(envAuthURL, defAuthURL),
 		authTimeout:     authTimeout,
+		readYourWrites:  consistency == consistencyReadYourWrites,
+		natsURL:         mainflux.Env(envNatsURL, defNatsURL),
+		webhooksLimit:   webhooksLimit,
+		webhooksFlush:   webhooksFlush,
+		webhooksTimeout: webhooksTimeout,
+		jobTimeout:      jobTimeout,
+		jobRetention:    jobRetention,
+		serialFormat:    serialFormat,
 	}
 }
 
@@ -277,14 +386,12 @@ func createAuthClient(cfg config, tracer opentracing.Tracer, logger logger.Logge
 func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := authapi.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 		logger.Info("gRPC communication is not encrypted")
@@ -299,7 +406,7 @@ func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
 	return conn
 }
 
-func newService(auth mainflux.AuthServiceClient, dbTracer opentracing.Tracer, cacheTracer opentracing.Tracer, db *sqlx.DB, cacheClient *redis.Client, esClient *redis.Client, logger logger.Logger) things.Service {
+func newService(auth mainflux.AuthServiceClient, dbTracer opentracing.Tracer, cacheTracer opentracing.Tracer, db *sqlx.DB, webhooksRepo things.WebhookRepository, cacheClient *redis.Client, esClient *redis.Client, cfg config, logger logger.Logger) things.Service {
 	database := postgres.NewDatabase(db)
 
 	thingsRepo := postgres.NewThingRepository(database)
@@ -315,7 +422,10 @@ func newService(auth mainflux.AuthServiceClient, dbTracer opentracing.Tracer, ca
 	thingCache = tracing.ThingCacheMiddleware(cacheTracer, thingCache)
 	idProvider := uuid.New()
 
-	svc := things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	svc := things.New(auth, thingsRepo, channelsRepo, webhooksRepo, cfg.webhooksLimit, chanCache, thingCache, idProvider, validator(cfg))
+	if cfg.readYourWrites {
+		svc = rediscache.NewConsistencyMiddleware(svc, thingCache, chanCache)
+	}
 	svc = rediscache.NewEventStoreMiddleware(svc, esClient)
 	svc = api.LoggingMiddleware(svc, logger)
 	svc = api.MetricsMiddleware(
@@ -336,6 +446,35 @@ func newService(auth mainflux.AuthServiceClient, dbTracer opentracing.Tracer, ca
 	return svc
 }
 
+// validator returns the validator enforcing the custom rules of the
+// deployment on the thing creation. The deployments plug their own
+// validators in here.
+func validator(c config) things.Validator {
+	vs := things.Validators{}
+	if c.serialFormat != nil {
+		vs = append(vs, things.NewMetadataValidator(serialMetadataKey, c.serialFormat))
+	}
+	return vs
+}
+
+func subscribeToWebhooks(sub messaging.Subscriber, dispatcher webhook.Dispatcher, flush time.Duration, logger logger.Logger) error {
+	// Pending batches are flushed periodically, so the messages of the
+	// channels with low traffic are not held back indefinitely.
+	go func() {
+		for range time.Tick(flush) {
+			dispatcher.Flush()
+		}
+	}()
+
+	return sub.Subscribe(nats.SubjectAllChannels, func(msg messaging.Message) error {
+		if err := dispatcher.Dispatch(msg); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to dispatch message to webhooks: %s", err))
+			return err
+		}
+		return nil
+	})
+}
+
 func startHTTPServer(handler http.Handler, port string, cfg config, logger logger.Logger, errs chan error) {
 	p := fmt.Sprintf(":%s", port)
 	if cfg.serverCert != "" || cfg.serverKey != "" {
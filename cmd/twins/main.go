@@ -28,14 +28,13 @@ import (
 	"github.com/mainflux/mainflux/twins/api"
 	twapi "github.com/mainflux/mainflux/twins/api/http"
 	twmongodb "github.com/mainflux/mainflux/twins/mongodb"
+	twpostgres "github.com/mainflux/mainflux/twins/postgres"
 	rediscache "github.com/mainflux/mainflux/twins/redis"
 	"github.com/mainflux/mainflux/twins/tracing"
 	opentracing "github.com/opentracing/opentracing-go"
 	stdprometheus "github.com/prometheus/client_golang/prometheus"
 	jconfig "github.com/uber/jaeger-client-go/config"
-	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 )
 
 const (
@@ -46,9 +45,17 @@ const (
 	defJaegerURL       = ""
 	defServerCert      = ""
 	defServerKey       = ""
+	defDBBackend       = mongoBackend
 	defDB              = "mainflux-twins"
 	defDBHost          = "localhost"
 	defDBPort          = "27017"
+	defPostgresDBPort  = "5432"
+	defDBUser          = "mainflux"
+	defDBPass          = "mainflux"
+	defDBSSLMode       = "disable"
+	defDBSSLCert       = ""
+	defDBSSLKey        = ""
+	defDBSSLRootCert   = ""
 	defCacheURL        = "localhost:6379"
 	defCachePass       = ""
 	defCacheDB         = "0"
@@ -56,6 +63,8 @@ const (
 	defStandaloneToken = ""
 	defClientTLS       = "false"
 	defCACerts         = ""
+	defClientCert      = ""
+	defClientKey       = ""
 	defChannelID       = ""
 	defNatsURL         = "nats://localhost:4222"
 	defAuthURL         = "localhost:8181"
@@ -66,9 +75,16 @@ const (
 	envJaegerURL       = "MF_JAEGER_URL"
 	envServerCert      = "MF_TWINS_SERVER_CERT"
 	envServerKey       = "MF_TWINS_SERVER_KEY"
+	envDBBackend       = "MF_TWINS_DB_BACKEND"
 	envDB              = "MF_TWINS_DB"
 	envDBHost          = "MF_TWINS_DB_HOST"
 	envDBPort          = "MF_TWINS_DB_PORT"
+	envDBUser          = "MF_TWINS_DB_USER"
+	envDBPass          = "MF_TWINS_DB_PASS"
+	envDBSSLMode       = "MF_TWINS_DB_SSL_MODE"
+	envDBSSLCert       = "MF_TWINS_DB_SSL_CERT"
+	envDBSSLKey        = "MF_TWINS_DB_SSL_KEY"
+	envDBSSLRootCert   = "MF_TWINS_DB_SSL_ROOT_CERT"
 	envCacheURL        = "MF_TWINS_CACHE_URL"
 	envCachePass       = "MF_TWINS_CACHE_PASS"
 	envCacheDB         = "MF_TWINS_CACHE_DB"
@@ -76,10 +92,15 @@ const (
 	envStandaloneToken = "MF_TWINS_STANDALONE_TOKEN"
 	envClientTLS       = "MF_TWINS_CLIENT_TLS"
 	envCACerts         = "MF_TWINS_CA_CERTS"
+	envClientCert      = "MF_AUTH_CLIENT_CERT"
+	envClientKey       = "MF_AUTH_CLIENT_KEY"
 	envChannelID       = "MF_TWINS_CHANNEL_ID"
 	envNatsURL         = "MF_NATS_URL"
 	envAuthURL         = "MF_AUTH_GRPC_URL"
 	envAuthTimeout     = "MF_AUTH_GRPC_TIMEOUT"
+
+	mongoBackend    = "mongodb"
+	postgresBackend = "postgres"
 )
 
 type config struct {
@@ -88,7 +109,9 @@ type config struct {
 	jaegerURL       string
 	serverCert      string
 	serverKey       string
+	dbBackend       string
 	dbCfg           twmongodb.Config
+	pgCfg           twpostgres.Config
 	cacheURL        string
 	cachePass       string
 	cacheDB         string
@@ -96,6 +119,8 @@ type config struct {
 	standaloneToken string
 	clientTLS       bool
 	caCerts         string
+	clientCert      string
+	clientKey       string
 	channelID       string
 	natsURL         string
 
@@ -106,7 +131,7 @@ type config struct {
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
@@ -115,11 +140,7 @@ func main() {
 	cacheTracer, cacheCloser := initJaeger("twins_cache", cfg.jaegerURL, logger)
 	defer cacheCloser.Close()
 
-	db, err := twmongodb.Connect(cfg.dbCfg, logger)
-	if err != nil {
-		logger.Error(err.Error())
-		os.Exit(1)
-	}
+	twinRepo, stateRepo := newRepositories(cfg, logger)
 	dbTracer, dbCloser := initJaeger("twins_db", cfg.jaegerURL, logger)
 	defer dbCloser.Close()
 
@@ -134,7 +155,7 @@ func main() {
 	}
 	defer pubSub.Close()
 
-	svc := newService(pubSub, cfg.channelID, auth, dbTracer, db, cacheTracer, cacheClient, logger)
+	svc := newService(pubSub, cfg.channelID, auth, dbTracer, twinRepo, stateRepo, cacheTracer, cacheClient, logger)
 
 	tracer, closer := initJaeger("twins", cfg.jaegerURL, logger)
 	defer closer.Close()
@@ -162,10 +183,28 @@ func loadConfig() config {
 		log.Fatalf("Invalid %s value: %s", envAuthTimeout, err.Error())
 	}
 
+	dbBackend := mainflux.Env(envDBBackend, defDBBackend)
+	dbPort := defDBPort
+	if dbBackend == postgresBackend {
+		dbPort = defPostgresDBPort
+	}
+
 	dbCfg := twmongodb.Config{
 		Name: mainflux.Env(envDB, defDB),
 		Host: mainflux.Env(envDBHost, defDBHost),
-		Port: mainflux.Env(envDBPort, defDBPort),
+		Port: mainflux.Env(envDBPort, dbPort),
+	}
+
+	pgCfg := twpostgres.Config{
+		Host:        mainflux.Env(envDBHost, defDBHost),
+		Port:        mainflux.Env(envDBPort, dbPort),
+		User:        mainflux.Env(envDBUser, defDBUser),
+		Pass:        mainflux.Env(envDBPass, defDBPass),
+		Name:        mainflux.Env(envDB, defDB),
+		SSLMode:     mainflux.Env(envDBSSLMode, defDBSSLMode),
+		SSLCert:     mainflux.Env(envDBSSLCert, defDBSSLCert),
+		SSLKey:      mainflux.Env(envDBSSLKey, defDBSSLKey),
+		SSLRootCert: mainflux.Env(envDBSSLRootCert, defDBSSLRootCert),
 	}
 
 	return config{
@@ -174,7 +213,9 @@ func loadConfig() config {
 		serverCert:      mainflux.Env(envServerCert, defServerCert),
 		serverKey:       mainflux.Env(envServerKey, defServerKey),
 		jaegerURL:       mainflux.Env(envJaegerURL, defJaegerURL),
+		dbBackend:       dbBackend,
 		dbCfg:           dbCfg,
+		pgCfg:           pgCfg,
 		cacheURL:        mainflux.Env(envCacheURL, defCacheURL),
 		cachePass:       mainflux.Env(envCachePass, defCachePass),
 		cacheDB:         mainflux.Env(envCacheDB, defCacheDB),
@@ -182,6 +223,8 @@ func loadConfig() config {
 		standaloneToken: mainflux.Env(envStandaloneToken, defStandaloneToken),
 		clientTLS:       tls,
 		caCerts:         mainflux.Env(envCACerts, defCACerts),
+		clientCert:      mainflux.Env(envClientCert, defClientCert),
+		clientKey:       mainflux.Env(envClientKey, defClientKey),
 		channelID:       mainflux.Env(envChannelID, defChannelID),
 		natsURL:         mainflux.Env(envNatsURL, defNatsURL),
 		authURL:         mainflux.Env(envAuthURL, defAuthURL),
@@ -225,14 +268,12 @@ func createAuthClient(cfg config, tracer opentracing.Tracer, logger logger.Logge
 func connectToAuth(cfg config, logger logger.Logger) *grpc.ClientConn {
 	var opts []grpc.DialOption
 	if cfg.clientTLS {
-		if cfg.caCerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.caCerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := authapi.ClientCredentials(cfg.caCerts, cfg.clientCert, cfg.clientKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 		logger.Info("gRPC communication is not encrypted")
@@ -261,11 +302,31 @@ func connectToRedis(cacheURL, cachePass, cacheDB string, logger logger.Logger) *
 	})
 }
 
-func newService(ps messaging.PubSub, chanID string, users mainflux.AuthServiceClient, dbTracer opentracing.Tracer, db *mongo.Database, cacheTracer opentracing.Tracer, cacheClient *redis.Client, logger logger.Logger) twins.Service {
-	twinRepo := twmongodb.NewTwinRepository(db)
-	twinRepo = tracing.TwinRepositoryMiddleware(dbTracer, twinRepo)
+func newRepositories(cfg config, logger logger.Logger) (twins.TwinRepository, twins.StateRepository) {
+	switch cfg.dbBackend {
+	case mongoBackend:
+		db, err := twmongodb.Connect(cfg.dbCfg, logger)
+		if err != nil {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+		return twmongodb.NewTwinRepository(db), twmongodb.NewStateRepository(db)
+	case postgresBackend:
+		db, err := twpostgres.Connect(cfg.pgCfg)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to connect to postgres: %s", err))
+			os.Exit(1)
+		}
+		return twpostgres.NewTwinRepository(db), twpostgres.NewStateRepository(db)
+	default:
+		logger.Error(fmt.Sprintf("Unknown database backend %s, expected %s or %s", cfg.dbBackend, mongoBackend, postgresBackend))
+		os.Exit(1)
+		return nil, nil
+	}
+}
 
-	stateRepo := twmongodb.NewStateRepository(db)
+func newService(ps messaging.PubSub, chanID string, users mainflux.AuthServiceClient, dbTracer opentracing.Tracer, twinRepo twins.TwinRepository, stateRepo twins.StateRepository, cacheTracer opentracing.Tracer, cacheClient *redis.Client, logger logger.Logger) twins.Service {
+	twinRepo = tracing.TwinRepositoryMiddleware(dbTracer, twinRepo)
 	stateRepo = tracing.StateRepositoryMiddleware(dbTracer, stateRepo)
 
 	idProvider := uuid.New()
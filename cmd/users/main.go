@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -14,19 +15,29 @@ import (
 	"os/signal"
 	"regexp"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/mainflux/mainflux/internal/cardinality"
 	"github.com/mainflux/mainflux/internal/email"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/internal/ratelimit"
+	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/argon2"
 	"github.com/mainflux/mainflux/users/bcrypt"
 	"github.com/mainflux/mainflux/users/emailer"
+	"github.com/mainflux/mainflux/users/hibp"
+	"github.com/mainflux/mainflux/users/jsonschema"
+	"github.com/mainflux/mainflux/users/oauth"
 	"github.com/mainflux/mainflux/users/tracing"
+	"github.com/mainflux/mainflux/users/webhook"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials"
 
 	kitprometheus "github.com/go-kit/kit/metrics/prometheus"
+	"github.com/go-redis/redis/v8"
 	"github.com/jmoiron/sqlx"
 	"github.com/mainflux/mainflux"
 	authapi "github.com/mainflux/mainflux/auth/api/grpc"
@@ -40,6 +51,8 @@ import (
 
 const (
 	defLogLevel      = "error"
+	defI18nDir       = ""
+	defMetricsLimit  = "0"
 	defDBHost        = "localhost"
 	defDBPort        = "5432"
 	defDBUser        = "mainflux"
@@ -64,19 +77,73 @@ const (
 	defEmailTemplate    = "email.tmpl"
 	defAdminEmail       = ""
 	defAdminPassword    = ""
-	defPassRegex        = "^.{8,}$"
 	defAdminGroup       = "mainflux"
 
 	defTokenResetEndpoint = "/reset-request" // URL where user lands after click on the reset link from email
 
 	defAuthTLS     = "false"
 	defAuthCACerts = ""
+	defAuthCert    = ""
+	defAuthKey     = ""
 	defAuthURL     = "localhost:8181"
 	defAuthTimeout = "1s"
 
 	defSelfRegister = "true" // By default, everybody can create a user. Otherwise, only admin can create a user.
+	defVerifyURL    = "http://localhost/users/verify"
+	defInviteURL    = "http://localhost/invitations/accept"
+	defVerifyEmail  = "false"
+	defLoginAlerts  = "false"
+
+	defEmailDomains   = ""
+	defMetadataSchema = ""
+
+	defWebhookURLs    = ""
+	defWebhookSecret  = ""
+	defWebhookTimeout = "5s"
+
+	defExportSecret  = ""
+	defAuthHTTPURL   = "http://localhost:8189"
+	defThingsHTTPURL = "http://localhost:8182"
+
+	defRateLimitIP    = "0"
+	defRateLimitAcc   = "0"
+	defRateLimitReset = "5"
+	defRateLimitStore = memoryStore
+	defRateLimitURL   = "localhost:6379"
+	defRateLimitPass  = ""
+	defRateLimitDB    = "0"
+
+	defLockoutThreshold = "0"
+	defLockoutCooldown  = "15m"
+
+	defPassMinLength      = "8"
+	defPassRequireUpper   = "false"
+	defPassRequireLower   = "false"
+	defPassRequireDigit   = "false"
+	defPassRequireSpecial = "false"
+	defPassRegex          = ""
+	defPassBreachCheck    = "false"
+	defPassBreachURL      = hibp.URL
+	defPassHistory        = "0"
+	defPassMaxAge         = "0"
+
+	defHasher        = bcryptHasher
+	defArgon2Time    = "3"
+	defArgon2Memory  = "65536"
+	defArgon2Threads = "2"
+
+	defOAuthCallbackURL   = "http://localhost/login"
+	defGoogleClientID     = ""
+	defGoogleClientSecret = ""
+	defGitHubClientID     = ""
+	defGitHubClientSecret = ""
+	defOIDCIssuer         = ""
+	defOIDCClientID       = ""
+	defOIDCClientSecret   = ""
 
 	envLogLevel      = "MF_USERS_LOG_LEVEL"
+	envI18nDir       = "MF_USERS_I18N_DIR"
+	envMetricsLimit  = "MF_USERS_METRICS_TENANT_LIMIT"
 	envDBHost        = "MF_USERS_DB_HOST"
 	envDBPort        = "MF_USERS_DB_PORT"
 	envDBUser        = "MF_USERS_DB_USER"
@@ -93,7 +160,6 @@ const (
 
 	envAdminEmail    = "MF_USERS_ADMIN_EMAIL"
 	envAdminPassword = "MF_USERS_ADMIN_PASSWORD"
-	envPassRegex     = "MF_USERS_PASS_REGEX"
 
 	envEmailHost        = "MF_EMAIL_HOST"
 	envEmailPort        = "MF_EMAIL_PORT"
@@ -109,38 +175,140 @@ const (
 
 	envAuthTLS     = "MF_AUTH_CLIENT_TLS"
 	envAuthCACerts = "MF_AUTH_CA_CERTS"
+	envAuthCert    = "MF_AUTH_CLIENT_CERT"
+	envAuthKey     = "MF_AUTH_CLIENT_KEY"
 	envAuthURL     = "MF_AUTH_GRPC_URL"
 	envAuthTimeout = "MF_AUTH_GRPC_TIMEOUT"
 
 	envSelfRegister = "MF_USERS_ALLOW_SELF_REGISTER"
+	envVerifyURL    = "MF_USERS_VERIFY_URL"
+	envInviteURL    = "MF_USERS_INVITE_URL"
+	envVerifyEmail  = "MF_USERS_VERIFY_EMAIL"
+	envLoginAlerts  = "MF_USERS_LOGIN_ALERTS"
+
+	envEmailDomains   = "MF_USERS_EMAIL_DOMAINS"
+	envMetadataSchema = "MF_USERS_METADATA_SCHEMA"
+
+	envWebhookURLs    = "MF_USERS_WEBHOOK_URLS"
+	envWebhookSecret  = "MF_USERS_WEBHOOK_SECRET"
+	envWebhookTimeout = "MF_USERS_WEBHOOK_TIMEOUT"
+
+	envExportSecret  = "MF_USERS_EXPORT_SECRET"
+	envAuthHTTPURL   = "MF_USERS_AUTH_URL"
+	envThingsHTTPURL = "MF_USERS_THINGS_URL"
+
+	envRateLimitIP    = "MF_USERS_RATE_LIMIT_IP"
+	envRateLimitAcc   = "MF_USERS_RATE_LIMIT_ACCOUNT"
+	envRateLimitReset = "MF_USERS_RATE_LIMIT_RESET"
+	envRateLimitStore = "MF_USERS_RATE_LIMIT_STORE"
+	envRateLimitURL   = "MF_USERS_RATE_LIMIT_REDIS_URL"
+	envRateLimitPass  = "MF_USERS_RATE_LIMIT_REDIS_PASS"
+	envRateLimitDB    = "MF_USERS_RATE_LIMIT_REDIS_DB"
+
+	envLockoutThreshold = "MF_USERS_LOCKOUT_THRESHOLD"
+	envLockoutCooldown  = "MF_USERS_LOCKOUT_COOLDOWN"
+
+	envPassMinLength      = "MF_USERS_PASS_MIN_LENGTH"
+	envPassRequireUpper   = "MF_USERS_PASS_REQUIRE_UPPER"
+	envPassRequireLower   = "MF_USERS_PASS_REQUIRE_LOWER"
+	envPassRequireDigit   = "MF_USERS_PASS_REQUIRE_DIGIT"
+	envPassRequireSpecial = "MF_USERS_PASS_REQUIRE_SPECIAL"
+	envPassRegex          = "MF_USERS_PASS_REGEX"
+	envPassBreachCheck    = "MF_USERS_PASS_BREACH_CHECK"
+	envPassBreachURL      = "MF_USERS_PASS_BREACH_URL"
+	envPassHistory        = "MF_USERS_PASS_HISTORY"
+	envPassMaxAge         = "MF_USERS_PASS_MAX_AGE"
+
+	envHasher        = "MF_USERS_HASHER"
+	envArgon2Time    = "MF_USERS_ARGON2_TIME"
+	envArgon2Memory  = "MF_USERS_ARGON2_MEMORY"
+	envArgon2Threads = "MF_USERS_ARGON2_THREADS"
+
+	envOAuthCallbackURL   = "MF_USERS_OAUTH_CALLBACK_URL"
+	envGoogleClientID     = "MF_USERS_GOOGLE_CLIENT_ID"
+	envGoogleClientSecret = "MF_USERS_GOOGLE_CLIENT_SECRET"
+	envGitHubClientID     = "MF_USERS_GITHUB_CLIENT_ID"
+	envGitHubClientSecret = "MF_USERS_GITHUB_CLIENT_SECRET"
+	envOIDCIssuer         = "MF_USERS_OIDC_ISSUER"
+	envOIDCClientID       = "MF_USERS_OIDC_CLIENT_ID"
+	envOIDCClientSecret   = "MF_USERS_OIDC_CLIENT_SECRET"
+
+	memoryStore = "memory"
+	redisStore  = "redis"
+
+	bcryptHasher = "bcrypt"
+	argon2Hasher = "argon2id"
+
+	googleProvider = "google"
+	githubProvider = "github"
+	oidcProvider   = "oidc"
+
+	// oauthTimeout limits the requests to the identity providers.
+	oauthTimeout = 10 * time.Second
+	// breachTimeout limits the lookups of the breached passwords.
+	breachTimeout = 5 * time.Second
 )
 
 type config struct {
-	logLevel      string
-	dbConfig      postgres.Config
-	emailConf     email.Config
-	httpPort      string
-	serverCert    string
-	serverKey     string
-	jaegerURL     string
-	resetURL      string
-	authTLS       bool
-	authCACerts   string
-	authURL       string
-	authTimeout   time.Duration
-	adminEmail    string
-	adminPassword string
-	passRegex     *regexp.Regexp
-	selfRegister  bool
+	logLevel       string
+	i18nDir        string
+	metricsLimit   int
+	dbConfig       postgres.Config
+	emailConf      email.Config
+	httpPort       string
+	serverCert     string
+	serverKey      string
+	jaegerURL      string
+	resetURL       string
+	verifyURL      string
+	inviteURL      string
+	authTLS        bool
+	authCACerts    string
+	authCert       string
+	authKey        string
+	authURL        string
+	authTimeout    time.Duration
+	adminEmail     string
+	adminPassword  string
+	selfRegister   users.SelfRegister
+	verifyEmail    bool
+	loginAlerts    bool
+	emailDomains   []string
+	metaSchema     string
+	webhookURLs    []string
+	webhookSecret  string
+	webhookTimeout time.Duration
+	rateLimitIP    int
+	rateLimitAcc   int
+	rateLimitRst   int
+	rateLimitStr   string
+	rateLimitURL   string
+	rateLimitPass  string
+	rateLimitDB    string
+	lockout        users.Lockout
+	passPolicy     users.PasswordPolicy
+	hasher         users.Hasher
+	exportSecret   string
+	sdkConfig      mfsdk.Config
+	oauthURL       string
+	google         oauth.Config
+	github         oauth.Config
+	oidcIssuer     string
+	oidc           oauth.Config
 }
 
 func main() {
 	cfg := loadConfig()
 
-	logger, err := logger.New(os.Stdout, cfg.logLevel)
+	logger, err := logger.NewFromEnv(os.Stdout, cfg.logLevel)
 	if err != nil {
 		log.Fatalf(err.Error())
 	}
+
+	if err := i18n.LoadCatalogs(cfg.i18nDir); err != nil {
+		logger.Error(fmt.Sprintf("Failed to load message catalogs: %s", err))
+		os.Exit(1)
+	}
 	db := connectToDB(cfg.dbConfig, logger)
 	defer db.Close()
 
@@ -184,14 +352,29 @@ func loadConfig() config {
 		log.Fatalf("Invalid value passed for %s\n", envAuthTLS)
 	}
 
-	passRegex, err := regexp.Compile(mainflux.Env(envPassRegex, defPassRegex))
+	selfRegister, err := selfRegisterMode(mainflux.Env(envSelfRegister, defSelfRegister))
 	if err != nil {
-		log.Fatalf("Invalid password validation rules %s\n", envPassRegex)
+		log.Fatalf("Invalid %s value: %s", envSelfRegister, err.Error())
 	}
 
-	selfRegister, err := strconv.ParseBool(mainflux.Env(envSelfRegister, defSelfRegister))
+	verifyEmail, err := strconv.ParseBool(mainflux.Env(envVerifyEmail, defVerifyEmail))
 	if err != nil {
-		log.Fatalf("Invalid %s value: %s", envSelfRegister, err.Error())
+		log.Fatalf("Invalid %s value: %s", envVerifyEmail, err.Error())
+	}
+
+	loginAlerts, err := strconv.ParseBool(mainflux.Env(envLoginAlerts, defLoginAlerts))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envLoginAlerts, err.Error())
+	}
+
+	webhookTimeout, err := time.ParseDuration(mainflux.Env(envWebhookTimeout, defWebhookTimeout))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envWebhookTimeout, err.Error())
+	}
+
+	metricsLimit, err := strconv.Atoi(mainflux.Env(envMetricsLimit, defMetricsLimit))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envMetricsLimit, err.Error())
 	}
 
 	dbConfig := postgres.Config{
@@ -217,23 +400,93 @@ func loadConfig() config {
 		Template:    mainflux.Env(envEmailTemplate, defEmailTemplate),
 	}
 
+	rateLimitIP, err := strconv.Atoi(mainflux.Env(envRateLimitIP, defRateLimitIP))
+	if err != nil || rateLimitIP < 0 {
+		log.Fatalf("Invalid %s value: %s", envRateLimitIP, mainflux.Env(envRateLimitIP, defRateLimitIP))
+	}
+
+	rateLimitAcc, err := strconv.Atoi(mainflux.Env(envRateLimitAcc, defRateLimitAcc))
+	if err != nil || rateLimitAcc < 0 {
+		log.Fatalf("Invalid %s value: %s", envRateLimitAcc, mainflux.Env(envRateLimitAcc, defRateLimitAcc))
+	}
+
+	rateLimitRst, err := strconv.Atoi(mainflux.Env(envRateLimitReset, defRateLimitReset))
+	if err != nil || rateLimitRst < 0 {
+		log.Fatalf("Invalid %s value: %s", envRateLimitReset, mainflux.Env(envRateLimitReset, defRateLimitReset))
+	}
+
+	lockoutThreshold, err := strconv.ParseUint(mainflux.Env(envLockoutThreshold, defLockoutThreshold), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envLockoutThreshold, mainflux.Env(envLockoutThreshold, defLockoutThreshold))
+	}
+
+	lockoutCooldown, err := time.ParseDuration(mainflux.Env(envLockoutCooldown, defLockoutCooldown))
+	if err != nil || lockoutCooldown <= 0 {
+		log.Fatalf("Invalid %s value: %s", envLockoutCooldown, mainflux.Env(envLockoutCooldown, defLockoutCooldown))
+	}
+
 	return config{
-		logLevel:      mainflux.Env(envLogLevel, defLogLevel),
-		dbConfig:      dbConfig,
-		emailConf:     emailConf,
-		httpPort:      mainflux.Env(envHTTPPort, defHTTPPort),
-		serverCert:    mainflux.Env(envServerCert, defServerCert),
-		serverKey:     mainflux.Env(envServerKey, defServerKey),
-		jaegerURL:     mainflux.Env(envJaegerURL, defJaegerURL),
-		resetURL:      mainflux.Env(envTokenResetEndpoint, defTokenResetEndpoint),
-		authTLS:       tls,
-		authCACerts:   mainflux.Env(envAuthCACerts, defAuthCACerts),
-		authURL:       mainflux.Env(envAuthURL, defAuthURL),
-		authTimeout:   authTimeout,
-		adminEmail:    mainflux.Env(envAdminEmail, defAdminEmail),
-		adminPassword: mainflux.Env(envAdminPassword, defAdminPassword),
-		passRegex:     passRegex,
-		selfRegister:  selfRegister,
+		logLevel:       mainflux.Env(envLogLevel, defLogLevel),
+		i18nDir:        mainflux.Env(envI18nDir, defI18nDir),
+		metricsLimit:   metricsLimit,
+		dbConfig:       dbConfig,
+		emailConf:      emailConf,
+		httpPort:       mainflux.Env(envHTTPPort, defHTTPPort),
+		serverCert:     mainflux.Env(envServerCert, defServerCert),
+		serverKey:      mainflux.Env(envServerKey, defServerKey),
+		jaegerURL:      mainflux.Env(envJaegerURL, defJaegerURL),
+		resetURL:       mainflux.Env(envTokenResetEndpoint, defTokenResetEndpoint),
+		verifyURL:      mainflux.Env(envVerifyURL, defVerifyURL),
+		inviteURL:      mainflux.Env(envInviteURL, defInviteURL),
+		authTLS:        tls,
+		authCACerts:    mainflux.Env(envAuthCACerts, defAuthCACerts),
+		authCert:       mainflux.Env(envAuthCert, defAuthCert),
+		authKey:        mainflux.Env(envAuthKey, defAuthKey),
+		authURL:        mainflux.Env(envAuthURL, defAuthURL),
+		authTimeout:    authTimeout,
+		adminEmail:     mainflux.Env(envAdminEmail, defAdminEmail),
+		adminPassword:  mainflux.Env(envAdminPassword, defAdminPassword),
+		selfRegister:   selfRegister,
+		verifyEmail:    verifyEmail,
+		loginAlerts:    loginAlerts,
+		emailDomains:   splitList(mainflux.Env(envEmailDomains, defEmailDomains)),
+		metaSchema:     mainflux.Env(envMetadataSchema, defMetadataSchema),
+		webhookURLs:    splitList(mainflux.Env(envWebhookURLs, defWebhookURLs)),
+		webhookSecret:  mainflux.Env(envWebhookSecret, defWebhookSecret),
+		webhookTimeout: webhookTimeout,
+		rateLimitIP:    rateLimitIP,
+		rateLimitAcc:   rateLimitAcc,
+		rateLimitRst:   rateLimitRst,
+		rateLimitStr:   mainflux.Env(envRateLimitStore, defRateLimitStore),
+		rateLimitURL:   mainflux.Env(envRateLimitURL, defRateLimitURL),
+		rateLimitPass:  mainflux.Env(envRateLimitPass, defRateLimitPass),
+		rateLimitDB:    mainflux.Env(envRateLimitDB, defRateLimitDB),
+		exportSecret:   mainflux.Env(envExportSecret, defExportSecret),
+		sdkConfig: mfsdk.Config{
+			AuthURL:         mainflux.Env(envAuthHTTPURL, defAuthHTTPURL),
+			ThingsURL:       mainflux.Env(envThingsHTTPURL, defThingsHTTPURL),
+			TLSVerification: true,
+		},
+		passPolicy: passwordPolicy(),
+		hasher:     passwordHasher(),
+		lockout: users.Lockout{
+			Threshold: uint32(lockoutThreshold),
+			Cooldown:  lockoutCooldown,
+		},
+		oauthURL: mainflux.Env(envOAuthCallbackURL, defOAuthCallbackURL),
+		google: oauth.Config{
+			ClientID:     mainflux.Env(envGoogleClientID, defGoogleClientID),
+			ClientSecret: mainflux.Env(envGoogleClientSecret, defGoogleClientSecret),
+		},
+		github: oauth.Config{
+			ClientID:     mainflux.Env(envGitHubClientID, defGitHubClientID),
+			ClientSecret: mainflux.Env(envGitHubClientSecret, defGitHubClientSecret),
+		},
+		oidcIssuer: mainflux.Env(envOIDCIssuer, defOIDCIssuer),
+		oidc: oauth.Config{
+			ClientID:     mainflux.Env(envOIDCClientID, defOIDCClientID),
+			ClientSecret: mainflux.Env(envOIDCClientSecret, defOIDCClientSecret),
+		},
 	}
 
 }
@@ -273,14 +526,12 @@ func connectToDB(dbConfig postgres.Config, logger logger.Logger) *sqlx.DB {
 func connectToAuth(cfg config, tracer opentracing.Tracer, logger logger.Logger) (mainflux.AuthServiceClient, func() error) {
 	var opts []grpc.DialOption
 	if cfg.authTLS {
-		if cfg.authCACerts != "" {
-			tpc, err := credentials.NewClientTLSFromFile(cfg.authCACerts, "")
-			if err != nil {
-				logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
-				os.Exit(1)
-			}
-			opts = append(opts, grpc.WithTransportCredentials(tpc))
+		tpc, err := authapi.ClientCredentials(cfg.authCACerts, cfg.authCert, cfg.authKey)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to create tls credentials: %s", err))
+			os.Exit(1)
 		}
+		opts = append(opts, grpc.WithTransportCredentials(tpc))
 	} else {
 		opts = append(opts, grpc.WithInsecure())
 		logger.Info("gRPC communication is not encrypted")
@@ -295,81 +546,316 @@ func connectToAuth(cfg config, tracer opentracing.Tracer, logger logger.Logger)
 	return authapi.NewClient(tracer, conn, cfg.authTimeout), conn.Close
 }
 
+func newMetricsMiddleware(svc users.Service, limit int) users.Service {
+	labels := []string{"method"}
+	if limit > 0 {
+		labels = append(labels, "tenant")
+	}
+	counter := kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
+		Namespace: "users",
+		Subsystem: "api",
+		Name:      "request_count",
+		Help:      "Number of requests received.",
+	}, labels)
+	latency := kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
+		Namespace: "users",
+		Subsystem: "api",
+		Name:      "request_latency_microseconds",
+		Help:      "Total duration of requests in microseconds.",
+	}, labels)
+
+	if limit > 0 {
+		return api.TenantMetricsMiddleware(svc, counter, latency, cardinality.NewLimiter(limit))
+	}
+	return api.MetricsMiddleware(svc, counter, latency)
+}
+
 func newService(db *sqlx.DB, tracer opentracing.Tracer, auth mainflux.AuthServiceClient, c config, logger logger.Logger) users.Service {
 	database := postgres.NewDatabase(db)
-	hasher := bcrypt.New()
+	hasher := c.hasher
 	userRepo := tracing.UserRepositoryMiddleware(postgres.NewUserRepo(database), tracer)
 
-	emailer, err := emailer.New(c.resetURL, &c.emailConf)
+	emailer, err := emailer.New(c.resetURL, c.verifyURL, c.inviteURL, &c.emailConf)
 	if err != nil {
 		logger.Error(fmt.Sprintf("Failed to configure e-mailing util: %s", err.Error()))
 	}
 
 	idProvider := uuid.New()
 
-	svc := users.New(userRepo, hasher, auth, emailer, idProvider, c.passRegex)
+	exportRepo := tracing.ExportRepositoryMiddleware(postgres.NewExportRepo(database), tracer)
+	invitationRepo := tracing.InvitationRepositoryMiddleware(postgres.NewInvitationRepo(database), tracer)
+	activityRepo := tracing.ActivityRepositoryMiddleware(postgres.NewActivityRepo(database), tracer)
+	source := users.NewExportSource(mfsdk.NewSDK(c.sdkConfig))
+
+	usersConfig := users.Config{
+		ExportKey:      exportKey(c.exportSecret, logger),
+		Validator:      validator(c, logger),
+		SelfRegister:   c.selfRegister,
+		VerifyEmail:    c.verifyEmail,
+		Providers:      identityProviders(c, logger),
+		Lockout:        c.lockout,
+		PasswordPolicy: c.passPolicy,
+		LoginAlerts:    c.loginAlerts,
+		Events:         eventNotifier(c, logger),
+	}
+	svc := users.New(userRepo, hasher, auth, emailer, idProvider, exportRepo, invitationRepo, activityRepo, source, usersConfig)
 	svc = api.LoggingMiddleware(svc, logger)
-	svc = api.MetricsMiddleware(
-		svc,
-		kitprometheus.NewCounterFrom(stdprometheus.CounterOpts{
-			Namespace: "users",
-			Subsystem: "api",
-			Name:      "request_count",
-			Help:      "Number of requests received.",
-		}, []string{"method"}),
-		kitprometheus.NewSummaryFrom(stdprometheus.SummaryOpts{
-			Namespace: "users",
-			Subsystem: "api",
-			Name:      "request_latency_microseconds",
-			Help:      "Total duration of requests in microseconds.",
-		}, []string{"method"}),
-	)
-	if err := createAdmin(svc, userRepo, c, auth); err != nil {
+	svc = newMetricsMiddleware(svc, c.metricsLimit)
+	svc = rateLimitService(svc, c, logger)
+	if err := createAdmin(userRepo, hasher, idProvider, c, auth); err != nil {
 		logger.Error("failed to create admin user: " + err.Error())
 		os.Exit(1)
 	}
 
-	switch c.selfRegister {
-	case true:
-		// If MF_USERS_ALLOW_SELF_REGISTER environment variable is "true",
-		// everybody can create a new user. Here, check the existence of that
-		// policy. If the policy does not exist, create it; otherwise, there is
-		// no need to do anything further.
-		_, err := auth.Authorize(context.Background(), &mainflux.AuthorizeReq{Obj: "user", Act: "create", Sub: "*"})
+	// The registration is authorized by the service, so the policy allowing
+	// everybody to create a user, added by the previous versions, is removed.
+	dpr, err := auth.DeletePolicy(context.Background(), &mainflux.DeletePolicyReq{Obj: "user", Act: "create", Sub: "*"})
+	if err != nil {
+		logger.Error("failed to delete a policy: " + err.Error())
+		os.Exit(1)
+	}
+	if !dpr.GetDeleted() {
+		logger.Error("deleting a policy expected to succeed.")
+		os.Exit(1)
+	}
+
+	return svc
+}
+
+// exportKey returns the key the export download links are signed with. If
+// the secret isn't set, the random key is used, and the links issued before
+// the restart are no longer valid.
+func exportKey(secret string, logger logger.Logger) []byte {
+	if secret != "" {
+		return []byte(secret)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		logger.Error(fmt.Sprintf("Failed to generate export signing key: %s", err))
+		os.Exit(1)
+	}
+	logger.Warn(fmt.Sprintf("%s is not set, the export download links are valid until the restart", envExportSecret))
+	return key
+}
+
+// validator returns the validator enforcing the custom rules of the
+// deployment on the user registration. The deployments plug their own
+// validators in here. The metadata schema is read from the file at the
+// configured path.
+func validator(c config, logger logger.Logger) users.Validator {
+	vs := users.Validators{}
+	if len(c.emailDomains) > 0 {
+		vs = append(vs, users.NewEmailDomainValidator(c.emailDomains...))
+	}
+	if c.metaSchema != "" {
+		schema, err := ioutil.ReadFile(c.metaSchema)
 		if err != nil {
-			// Add a policy that allows anybody to create a user
-			apr, err := auth.AddPolicy(context.Background(), &mainflux.AddPolicyReq{Obj: "user", Act: "create", Sub: "*"})
-			if err != nil {
-				logger.Error("failed to add the policy related to MF_USERS_ALLOW_SELF_REGISTER: " + err.Error())
-				os.Exit(1)
-			}
-			if !apr.GetAuthorized() {
-				logger.Error("failed to authorized the policy result related to MF_USERS_ALLOW_SELF_REGISTER: " + users.ErrAuthorization.Error())
-				os.Exit(1)
-			}
+			logger.Error(fmt.Sprintf("Failed to read metadata schema: %s", err))
+			os.Exit(1)
 		}
-	default:
-		// If MF_USERS_ALLOW_SELF_REGISTER environment variable is "false",
-		// everybody cannot create a new user. Therefore, delete a policy that
-		// allows everybody to create a new user.
-		dpr, err := auth.DeletePolicy(context.Background(), &mainflux.DeletePolicyReq{Obj: "user", Act: "create", Sub: "*"})
+		v, err := jsonschema.New(schema)
 		if err != nil {
-			logger.Error("failed to delete a policy: " + err.Error())
+			logger.Error(fmt.Sprintf("Failed to configure metadata schema: %s", err))
 			os.Exit(1)
 		}
-		if !dpr.GetDeleted() {
-			logger.Error("deleting a policy expected to succeed.")
+		vs = append(vs, v)
+	}
+	return vs
+}
+
+// eventNotifier returns the notifier posting the user lifecycle events to
+// the webhooks, or nil if no webhook is configured.
+func eventNotifier(c config, logger logger.Logger) users.EventNotifier {
+	if len(c.webhookURLs) == 0 {
+		return nil
+	}
+	return webhook.New(c.webhookURLs, c.webhookSecret, &http.Client{Timeout: c.webhookTimeout}, logger)
+}
+
+// identityProviders returns the identity providers the users log in with.
+// The provider is enabled once its client ID is set, and the provider
+// redirects the user to its callback, under the OAuth callback URL.
+func identityProviders(c config, logger logger.Logger) map[string]users.IdentityProvider {
+	client := &http.Client{Timeout: oauthTimeout}
+	providers := map[string]users.IdentityProvider{}
+	redirect := func(cfg oauth.Config, name string) oauth.Config {
+		cfg.RedirectURL = fmt.Sprintf("%s/%s/callback", strings.TrimSuffix(c.oauthURL, "/"), name)
+		return cfg
+	}
+
+	if c.google.ClientID != "" {
+		p, err := oauth.NewGoogle(context.Background(), client, redirect(c.google, googleProvider))
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to configure Google identity provider: %s", err))
 			os.Exit(1)
 		}
+		providers[googleProvider] = p
+	}
+	if c.github.ClientID != "" {
+		providers[githubProvider] = oauth.NewGitHub(client, redirect(c.github, githubProvider))
+	}
+	if c.oidc.ClientID != "" {
+		p, err := oauth.NewOIDC(context.Background(), client, c.oidcIssuer, redirect(c.oidc, oidcProvider))
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to configure OpenID Connect identity provider: %s", err))
+			os.Exit(1)
+		}
+		providers[oidcProvider] = p
 	}
 
-	return svc
+	return providers
 }
 
-func createAdmin(svc users.Service, userRepo users.UserRepository, c config, auth mainflux.AuthServiceClient) error {
+// selfRegisterMode parses the self registration mode, which is either the
+// boolean or "verify", requiring the self registered users to verify the
+// email.
+func selfRegisterMode(s string) (users.SelfRegister, error) {
+	if s == "verify" {
+		return users.SelfRegisterVerified, nil
+	}
+	enabled, err := strconv.ParseBool(s)
+	if err != nil {
+		return users.SelfRegisterDisabled, err
+	}
+	if enabled {
+		return users.SelfRegisterEnabled, nil
+	}
+	return users.SelfRegisterDisabled, nil
+}
+
+// passwordPolicy returns the password policy configured by the environment.
+// The breached passwords are rejected using the Pwned Passwords API once the
+// breach check is enabled.
+func passwordPolicy() users.PasswordPolicy {
+	minLength, err := strconv.Atoi(mainflux.Env(envPassMinLength, defPassMinLength))
+	if err != nil || minLength < 0 {
+		log.Fatalf("Invalid %s value: %s", envPassMinLength, mainflux.Env(envPassMinLength, defPassMinLength))
+	}
+
+	policy := users.PasswordPolicy{MinLength: minLength}
+	classes := []struct {
+		env, def string
+		require  *bool
+	}{
+		{envPassRequireUpper, defPassRequireUpper, &policy.RequireUpper},
+		{envPassRequireLower, defPassRequireLower, &policy.RequireLower},
+		{envPassRequireDigit, defPassRequireDigit, &policy.RequireDigit},
+		{envPassRequireSpecial, defPassRequireSpecial, &policy.RequireSpecial},
+	}
+	for _, c := range classes {
+		if *c.require, err = strconv.ParseBool(mainflux.Env(c.env, c.def)); err != nil {
+			log.Fatalf("Invalid %s value: %s", c.env, err.Error())
+		}
+	}
+
+	if pattern := mainflux.Env(envPassRegex, defPassRegex); pattern != "" {
+		if policy.Pattern, err = regexp.Compile(pattern); err != nil {
+			log.Fatalf("Invalid password validation rules %s\n", envPassRegex)
+		}
+	}
+
+	breachCheck, err := strconv.ParseBool(mainflux.Env(envPassBreachCheck, defPassBreachCheck))
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envPassBreachCheck, err.Error())
+	}
+	if breachCheck {
+		policy.Breaches = hibp.New(&http.Client{Timeout: breachTimeout}, mainflux.Env(envPassBreachURL, defPassBreachURL))
+	}
+
+	history, err := strconv.ParseUint(mainflux.Env(envPassHistory, defPassHistory), 10, 32)
+	if err != nil {
+		log.Fatalf("Invalid %s value: %s", envPassHistory, mainflux.Env(envPassHistory, defPassHistory))
+	}
+	policy.History = uint32(history)
+
+	policy.MaxAge, err = time.ParseDuration(mainflux.Env(envPassMaxAge, defPassMaxAge))
+	if err != nil || policy.MaxAge < 0 {
+		log.Fatalf("Invalid %s value: %s", envPassMaxAge, mainflux.Env(envPassMaxAge, defPassMaxAge))
+	}
+
+	return policy
+}
+
+// passwordHasher returns the hasher the new password hashes are made with.
+// Argon2id hasher still accepts the bcrypt hashes, which are replaced on the
+// next login.
+func passwordHasher() users.Hasher {
+	switch h := mainflux.Env(envHasher, defHasher); h {
+	case bcryptHasher:
+		return bcrypt.New()
+	case argon2Hasher:
+		params := argon2.Params{}
+		for _, p := range []struct {
+			env, def string
+			bits     int
+			val      func(uint64)
+		}{
+			{envArgon2Time, defArgon2Time, 32, func(v uint64) { params.Time = uint32(v) }},
+			{envArgon2Memory, defArgon2Memory, 32, func(v uint64) { params.Memory = uint32(v) }},
+			{envArgon2Threads, defArgon2Threads, 8, func(v uint64) { params.Threads = uint8(v) }},
+		} {
+			v, err := strconv.ParseUint(mainflux.Env(p.env, p.def), 10, p.bits)
+			if err != nil || v == 0 {
+				log.Fatalf("Invalid %s value: %s", p.env, mainflux.Env(p.env, p.def))
+			}
+			p.val(v)
+		}
+		return argon2.New(params, bcrypt.New())
+	default:
+		log.Fatalf("Invalid %s value: %s, expected %s or %s", envHasher, h, bcryptHasher, argon2Hasher)
+		return nil
+	}
+}
+
+// splitList splits the comma separated list, skipping the empty items.
+func splitList(s string) []string {
+	var items []string
+	for _, i := range strings.Split(s, ",") {
+		if i = strings.TrimSpace(i); i != "" {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+func rateLimitService(svc users.Service, c config, logger logger.Logger) users.Service {
+	if c.rateLimitIP == 0 && c.rateLimitAcc == 0 && c.rateLimitRst == 0 {
+		return svc
+	}
+
+	var store ratelimit.Store
+	switch c.rateLimitStr {
+	case memoryStore:
+		store = ratelimit.NewMemoryStore()
+	case redisStore:
+		db, err := strconv.Atoi(c.rateLimitDB)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to connect to Redis: %s", err))
+			os.Exit(1)
+		}
+		store = ratelimit.NewRedisStore(redis.NewClient(&redis.Options{
+			Addr:     c.rateLimitURL,
+			Password: c.rateLimitPass,
+			DB:       db,
+		}))
+	default:
+		logger.Error(fmt.Sprintf("Unknown rate limit store %s, expected %s or %s", c.rateLimitStr, memoryStore, redisStore))
+		os.Exit(1)
+	}
+
+	login := ratelimit.NewLimiter(store, "login", ratelimit.PerMinute(c.rateLimitIP), ratelimit.PerMinute(c.rateLimitAcc))
+	reset := ratelimit.NewLimiter(store, "reset", ratelimit.Limit{}, ratelimit.PerHour(c.rateLimitRst))
+	return api.RateLimitMiddleware(svc, login, reset)
+}
+
+// createAdmin saves the admin directly, since there's no admin to authorize
+// the registration yet.
+func createAdmin(userRepo users.UserRepository, hasher users.Hasher, idp mainflux.IDProvider, c config, auth mainflux.AuthServiceClient) error {
 	user := users.User{
 		Email:    c.adminEmail,
 		Password: c.adminPassword,
+		Verified: true,
 	}
 
 	if admin, err := userRepo.RetrieveByEmail(context.Background(), user.Email); err == nil {
@@ -387,22 +873,27 @@ func createAdmin(svc users.Service, userRepo users.UserRepository, c config, aut
 		return nil
 	}
 
-	// Add a policy that allows anybody to create a user
-	apr, err := auth.AddPolicy(context.Background(), &mainflux.AddPolicyReq{Obj: "user", Act: "create", Sub: "*"})
+	// Create an admin
+	if err := user.Validate(); err != nil {
+		return err
+	}
+	hash, err := hasher.Hash(user.Password)
 	if err != nil {
 		return err
 	}
-	if !apr.GetAuthorized() {
-		return users.ErrAuthorization
+	user.Password = hash
+	if user.ID, err = idp.ID(); err != nil {
+		return err
 	}
-
-	// Create an admin
-	uid, err := svc.Register(context.Background(), "", user)
+	uid, err := userRepo.Save(context.Background(), user)
 	if err != nil {
 		return err
 	}
+	if _, err := auth.ApplyPolicyTemplate(context.Background(), &mainflux.PolicyTemplateReq{EntityType: "user", EntityID: uid}); err != nil {
+		return err
+	}
 
-	apr, err = auth.AddPolicy(context.Background(), &mainflux.AddPolicyReq{Obj: "authorities", Act: "member", Sub: uid})
+	apr, err := auth.AddPolicy(context.Background(), &mainflux.AddPolicyReq{Obj: "authorities", Act: "member", Sub: uid})
 	if err != nil {
 		return err
 	}
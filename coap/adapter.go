@@ -8,7 +8,6 @@ package coap
 
 import (
 	"context"
-	"fmt"
 	"sync"
 
 	"github.com/gogo/protobuf/proto"
@@ -16,11 +15,13 @@ import (
 	broker "github.com/nats-io/nats.go"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/lineage"
+	"github.com/mainflux/mainflux/internal/payload"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	"github.com/mainflux/mainflux/things"
 )
 
-const chansPrefix = "channels"
-
 // Exported errors
 var (
 	ErrUnauthorized = errors.New("unauthorized access")
@@ -33,8 +34,9 @@ type Service interface {
 	Publish(ctx context.Context, key string, msg messaging.Message) error
 
 	// Subscribes to channel with specified id, subtopic and adds subscription to
-	// service map of subscriptions under given ID.
-	Subscribe(ctx context.Context, key, chanID, subtopic string, c Client) error
+	// service map of subscriptions under given ID. Only the messages matching
+	// the filter are forwarded to the client.
+	Subscribe(ctx context.Context, key, chanID, subtopic string, f Filter, c Client) error
 
 	// Unsubscribe method is used to stop observing resource.
 	Unsubscribe(ctx context.Context, key, chanID, subptopic, token string) error
@@ -44,19 +46,28 @@ var _ Service = (*adapterService)(nil)
 
 // Observers is a map of maps,
 type adapterService struct {
-	auth      mainflux.ThingsServiceClient
-	conn      *broker.Conn
-	observers map[string]observers
-	obsLock   sync.Mutex
+	auth       mainflux.ThingsServiceClient
+	conn       *broker.Conn
+	partitions int
+	payloads   payload.Checker
+	instance   string
+	observers  map[string]observers
+	obsLock    sync.Mutex
 }
 
-// New instantiates the CoAP adapter implementation.
-func New(auth mainflux.ThingsServiceClient, nc *broker.Conn) Service {
+// New instantiates the CoAP adapter implementation. The messages are
+// published to the given number of the subject partitions, if more than one.
+// The messages violating the payload policies aren't published, the rest are
+// stamped with the lineage of the given adapter instance.
+func New(auth mainflux.ThingsServiceClient, nc *broker.Conn, partitions int, payloads payload.Checker, instance string) Service {
 	as := &adapterService{
-		auth:      auth,
-		conn:      nc,
-		observers: make(map[string]observers),
-		obsLock:   sync.Mutex{},
+		auth:       auth,
+		conn:       nc,
+		partitions: partitions,
+		payloads:   payloads,
+		instance:   instance,
+		observers:  make(map[string]observers),
+		obsLock:    sync.Mutex{},
 	}
 
 	return as
@@ -64,8 +75,9 @@ func New(auth mainflux.ThingsServiceClient, nc *broker.Conn) Service {
 
 func (svc *adapterService) Publish(ctx context.Context, key string, msg messaging.Message) error {
 	ar := &mainflux.AccessByKeyReq{
-		Token:  key,
-		ChanID: msg.Channel,
+		Token:     key,
+		ChanID:    msg.Channel,
+		Operation: things.PublishOp,
 	}
 	thid, err := svc.auth.CanAccessByKey(ctx, ar)
 	if err != nil {
@@ -73,39 +85,38 @@ func (svc *adapterService) Publish(ctx context.Context, key string, msg messagin
 	}
 	msg.Publisher = thid.GetValue()
 
-	data, err := proto.Marshal(&msg)
-	if err != nil {
+	if err := svc.payloads.Check(msg); err != nil {
 		return err
 	}
+	lineage.Stamp(&msg, svc.instance)
 
-	subject := fmt.Sprintf("%s.%s", chansPrefix, msg.Channel)
-	if msg.Subtopic != "" {
-		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	data, err := proto.Marshal(&msg)
+	if err != nil {
+		return err
 	}
 
+	subject := nats.ChannelSubject(msg.Channel, msg.Subtopic, svc.partitions)
 	return svc.conn.Publish(subject, data)
 }
 
-func (svc *adapterService) Subscribe(ctx context.Context, key, chanID, subtopic string, c Client) error {
+func (svc *adapterService) Subscribe(ctx context.Context, key, chanID, subtopic string, f Filter, c Client) error {
 	ar := &mainflux.AccessByKeyReq{
-		Token:  key,
-		ChanID: chanID,
+		Token:     key,
+		ChanID:    chanID,
+		Operation: things.SubscribeOp,
 	}
 	if _, err := svc.auth.CanAccessByKey(ctx, ar); err != nil {
 		return errors.Wrap(ErrUnauthorized, err)
 	}
 
-	subject := fmt.Sprintf("%s.%s", chansPrefix, chanID)
-	if subtopic != "" {
-		subject = fmt.Sprintf("%s.%s", subject, subtopic)
-	}
+	subject := nats.ChannelSubject(chanID, subtopic, svc.partitions)
 
 	go func() {
 		<-c.Done()
 		svc.remove(subject, c.Token())
 	}()
 
-	obs, err := NewObserver(subject, c, svc.conn)
+	obs, err := NewObserver(subject, f, c, svc.conn)
 	if err != nil {
 		c.Cancel()
 		return err
@@ -115,16 +126,14 @@ func (svc *adapterService) Subscribe(ctx context.Context, key, chanID, subtopic
 
 func (svc *adapterService) Unsubscribe(ctx context.Context, key, chanID, subtopic, token string) error {
 	ar := &mainflux.AccessByKeyReq{
-		Token:  key,
-		ChanID: chanID,
+		Token:     key,
+		ChanID:    chanID,
+		Operation: things.SubscribeOp,
 	}
 	if _, err := svc.auth.CanAccessByKey(ctx, ar); err != nil {
 		return errors.Wrap(ErrUnauthorized, err)
 	}
-	subject := fmt.Sprintf("%s.%s", chansPrefix, chanID)
-	if subtopic != "" {
-		subject = fmt.Sprintf("%s.%s", subject, subtopic)
-	}
+	subject := nats.ChannelSubject(chanID, subtopic, svc.partitions)
 
 	return svc.remove(subject, token)
 }
@@ -44,7 +44,7 @@ func (lm *loggingMiddleware) Publish(ctx context.Context, key string, msg messag
 	return lm.svc.Publish(ctx, key, msg)
 }
 
-func (lm *loggingMiddleware) Subscribe(ctx context.Context, key, chanID, subtopic string, c coap.Client) (err error) {
+func (lm *loggingMiddleware) Subscribe(ctx context.Context, key, chanID, subtopic string, f coap.Filter, c coap.Client) (err error) {
 	defer func(begin time.Time) {
 		destChannel := chanID
 		if subtopic != "" {
@@ -58,7 +58,7 @@ func (lm *loggingMiddleware) Subscribe(ctx context.Context, key, chanID, subtopi
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Subscribe(ctx, key, chanID, subtopic, c)
+	return lm.svc.Subscribe(ctx, key, chanID, subtopic, f, c)
 }
 
 func (lm *loggingMiddleware) Unsubscribe(ctx context.Context, key, chanID, subtopic, token string) error {
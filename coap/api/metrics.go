@@ -40,13 +40,13 @@ func (mm *metricsMiddleware) Publish(ctx context.Context, key string, msg messag
 	return mm.svc.Publish(ctx, key, msg)
 }
 
-func (mm *metricsMiddleware) Subscribe(ctx context.Context, key, chanID, subtopic string, c coap.Client) error {
+func (mm *metricsMiddleware) Subscribe(ctx context.Context, key, chanID, subtopic string, f coap.Filter, c coap.Client) error {
 	defer func(begin time.Time) {
 		mm.counter.With("method", "subscribe").Add(1)
 		mm.latency.With("method", "subscribe").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return mm.svc.Subscribe(ctx, key, chanID, subtopic, c)
+	return mm.svc.Subscribe(ctx, key, chanID, subtopic, f, c)
 }
 
 func (mm *metricsMiddleware) Unsubscribe(ctx context.Context, key, chanID, subtopic, token string) error {
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/coap"
+	"github.com/mainflux/mainflux/internal/payload"
 	log "github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/messaging"
 	"github.com/plgd-dev/go-coap/v2/message"
@@ -27,13 +29,30 @@ import (
 )
 
 const (
-	protocol  = "coap"
-	authQuery = "auth"
+	protocol       = "coap"
+	authQuery      = "auth"
+	nameQuery      = "name"
+	subtopicQuery  = "subtopic"
+	minChangeQuery = "delta"
 )
 
 var channelPartRegExp = regexp.MustCompile(`^channels/([\w\-]+)/messages(/[^?]*)?(\?.*)?$`)
 
-var errMalformedSubtopic = errors.New("malformed subtopic")
+var (
+	errMalformedSubtopic = errors.New("malformed subtopic")
+	errMalformedFilter   = errors.New("malformed filter")
+)
+
+// contentTypes maps CoAP content formats to the message content types.
+var contentTypes = map[message.MediaType]string{
+	message.TextPlain:   "text/plain",
+	message.AppOctets:   "application/octet-stream",
+	message.AppJSON:     "application/json",
+	message.AppCBOR:     "application/cbor",
+	110:                 "application/senml+json",
+	112:                 "application/senml+cbor",
+	message.AppLwm2mTLV: "application/vnd.oma.lwm2m+tlv",
+}
 
 var (
 	logger  log.Logger
@@ -98,8 +117,15 @@ func handler(w mux.ResponseWriter, m *mux.Message) {
 			return
 		}
 		if obs == 0 {
+			var f coap.Filter
+			f, err = parseFilter(m)
+			if err != nil {
+				logger.Warn(fmt.Sprintf("Error parsing filter: %s", err))
+				resp.Code = codes.BadRequest
+				return
+			}
 			c := coap.NewClient(w.Client(), m.Token, logger)
-			err = service.Subscribe(context.Background(), key, msg.Channel, msg.Subtopic, c)
+			err = service.Subscribe(context.Background(), key, msg.Channel, msg.Subtopic, f, c)
 			break
 		}
 		service.Unsubscribe(context.Background(), key, msg.Channel, msg.Subtopic, m.Token.String())
@@ -116,6 +142,10 @@ func handler(w mux.ResponseWriter, m *mux.Message) {
 			return
 		case errors.Contains(err, coap.ErrUnsubscribe):
 			resp.Code = codes.InternalServerError
+		case errors.Contains(err, payload.ErrTooLarge):
+			resp.Code = codes.RequestEntityTooLarge
+		case errors.Contains(err, payload.ErrUnsupportedContentType):
+			resp.Code = codes.UnsupportedMediaType
 		}
 	}
 }
@@ -141,6 +171,9 @@ func decodeMessage(msg *mux.Message) (messaging.Message, error) {
 		Payload:  []byte{},
 		Created:  time.Now().UnixNano(),
 	}
+	if cf, err := msg.Options.ContentFormat(); err == nil {
+		ret.ContentType = contentTypes[cf]
+	}
 
 	if msg.Body != nil {
 		buff, err := ioutil.ReadAll(msg.Body)
@@ -161,15 +194,50 @@ func parseID(path string) string {
 }
 
 func parseKey(msg *mux.Message) (string, error) {
-	auth, err := msg.Options.GetString(message.URIQuery)
+	queries, err := msg.Options.Queries()
 	if err != nil {
 		return "", err
 	}
-	vars := strings.Split(auth, "=")
-	if len(vars) != 2 || vars[0] != authQuery {
-		return "", coap.ErrUnauthorized
+	for _, q := range queries {
+		vars := strings.Split(q, "=")
+		if len(vars) == 2 && vars[0] == authQuery {
+			return vars[1], nil
+		}
+	}
+	return "", coap.ErrUnauthorized
+}
+
+// parseFilter parses the filter of the observed messages from the name,
+// subtopic and delta URI queries.
+func parseFilter(msg *mux.Message) (coap.Filter, error) {
+	var f coap.Filter
+	queries, err := msg.Options.Queries()
+	if err != nil {
+		return f, err
+	}
+	for _, q := range queries {
+		vars := strings.SplitN(q, "=", 2)
+		if len(vars) != 2 {
+			continue
+		}
+		switch vars[0] {
+		case nameQuery:
+			f.Name = vars[1]
+		case subtopicQuery:
+			st, err := parseSubtopic(vars[1])
+			if err != nil {
+				return f, err
+			}
+			f.Subtopic = st
+		case minChangeQuery:
+			d, err := strconv.ParseFloat(vars[1], 64)
+			if err != nil || d < 0 {
+				return f, errMalformedFilter
+			}
+			f.MinChange = d
+		}
 	}
-	return vars[1], nil
+	return f, nil
 }
 
 func parseSubtopic(subtopic string) (string, error) {
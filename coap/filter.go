@@ -0,0 +1,115 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package coap
+
+import (
+	"math"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+	mfsenml "github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/mainflux/senml"
+)
+
+const (
+	singleWildcard = "*"
+	multiWildcard  = ">"
+)
+
+// Filter represents the server-side filter of the observed messages. Only the
+// messages matching all of the set criteria are forwarded to the observer,
+// while the zero Filter forwards all the messages.
+type Filter struct {
+	// Name is the name of the SenML record the message has to contain.
+	Name string
+
+	// Subtopic is the pattern the message subtopic has to match. The "*"
+	// element matches any single subtopic element, and the trailing ">"
+	// matches one or more of the remaining elements.
+	Subtopic string
+
+	// MinChange is the minimal change of the SenML record value since the
+	// last value forwarded for the record with the same name.
+	MinChange float64
+}
+
+// Empty returns true if the filter forwards all the messages.
+func (f Filter) Empty() bool {
+	return f.Name == "" && f.Subtopic == "" && f.MinChange == 0
+}
+
+// matcher matches the messages against the filter, keeping the last
+// forwarded values to apply the minimal value change.
+type matcher struct {
+	filter Filter
+	last   map[string]float64
+}
+
+func newMatcher(f Filter) *matcher {
+	return &matcher{
+		filter: f,
+		last:   make(map[string]float64),
+	}
+}
+
+// Match returns true if the message should be forwarded to the observer.
+// The messages are matched one at a time, in the order of reception.
+func (m *matcher) Match(msg messaging.Message) bool {
+	if m.filter.Subtopic != "" && !matchSubtopic(m.filter.Subtopic, msg.Subtopic) {
+		return false
+	}
+	if m.filter.Name == "" && m.filter.MinChange == 0 {
+		return true
+	}
+
+	format := senml.JSON
+	if msg.ContentType == mfsenml.CBOR {
+		format = senml.CBOR
+	}
+	raw, err := senml.Decode(msg.Payload, format)
+	if err != nil {
+		return false
+	}
+	pack, err := senml.Normalize(raw)
+	if err != nil {
+		return false
+	}
+
+	match := false
+	for _, r := range pack.Records {
+		if m.filter.Name != "" && r.Name != m.filter.Name {
+			continue
+		}
+		if m.filter.MinChange == 0 {
+			match = true
+			continue
+		}
+		if r.Value == nil {
+			continue
+		}
+		if last, ok := m.last[r.Name]; ok && math.Abs(*r.Value-last) < m.filter.MinChange {
+			continue
+		}
+		m.last[r.Name] = *r.Value
+		match = true
+	}
+	return match
+}
+
+func matchSubtopic(pattern, subtopic string) bool {
+	pe := strings.Split(pattern, ".")
+	se := strings.Split(subtopic, ".")
+	if subtopic == "" {
+		se = []string{}
+	}
+	for i, p := range pe {
+		if p == multiWildcard {
+			return i == len(pe)-1 && len(se) > i
+		}
+		if i >= len(se) || (p != singleWildcard && p != se[i]) {
+			return false
+		}
+	}
+	return len(pe) == len(se)
+}
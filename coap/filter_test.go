@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package coap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func senmlMsg(subtopic, payload string) messaging.Message {
+	return messaging.Message{
+		Subtopic:    subtopic,
+		ContentType: "application/senml+json",
+		Payload:     []byte(payload),
+	}
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		desc   string
+		filter Filter
+		msgs   []messaging.Message
+		match  []bool
+	}{
+		{
+			desc:   "match with empty filter",
+			filter: Filter{},
+			msgs:   []messaging.Message{{Subtopic: "a.b", Payload: []byte("raw")}},
+			match:  []bool{true},
+		},
+		{
+			desc:   "match by subtopic pattern",
+			filter: Filter{Subtopic: "room.*.temp"},
+			msgs: []messaging.Message{
+				{Subtopic: "room.1.temp"},
+				{Subtopic: "room.1.hum"},
+				{Subtopic: "room.temp"},
+			},
+			match: []bool{true, false, false},
+		},
+		{
+			desc:   "match by trailing wildcard subtopic pattern",
+			filter: Filter{Subtopic: "room.>"},
+			msgs: []messaging.Message{
+				{Subtopic: "room.1.temp"},
+				{Subtopic: "room"},
+				{Subtopic: "hall.1"},
+			},
+			match: []bool{true, false, false},
+		},
+		{
+			desc:   "match by record name",
+			filter: Filter{Name: "dev:temp"},
+			msgs: []messaging.Message{
+				senmlMsg("", `[{"bn":"dev:","n":"temp","v":20}]`),
+				senmlMsg("", `[{"bn":"dev:","n":"hum","v":40}]`),
+				{Payload: []byte("raw")},
+			},
+			match: []bool{true, false, false},
+		},
+		{
+			desc:   "match by minimal value change",
+			filter: Filter{Name: "temp", MinChange: 1},
+			msgs: []messaging.Message{
+				senmlMsg("", `[{"n":"temp","v":20}]`),
+				senmlMsg("", `[{"n":"temp","v":20.5}]`),
+				senmlMsg("", `[{"n":"temp","v":21}]`),
+				senmlMsg("", `[{"n":"temp","v":20.5}]`),
+				senmlMsg("", `[{"n":"temp","v":19.9}]`),
+			},
+			match: []bool{true, false, true, false, true},
+		},
+	}
+
+	for _, tc := range cases {
+		m := newMatcher(tc.filter)
+		for i, msg := range tc.msgs {
+			match := m.Match(msg)
+			assert.Equal(t, tc.match[i], match, fmt.Sprintf("%s: message %d: expected %t got %t", tc.desc, i, tc.match[i], match))
+		}
+	}
+}
@@ -14,13 +14,20 @@ type Observer interface {
 	Cancel() error
 }
 
-// NewObserver returns a new Observer instance.
-func NewObserver(subject string, c Client, conn *broker.Conn) (Observer, error) {
+// NewObserver returns a new Observer instance, forwarding only the messages
+// matching the given filter.
+func NewObserver(subject string, f Filter, c Client, conn *broker.Conn) (Observer, error) {
+	// The subscription handler is invoked sequentially, so the matcher state
+	// isn't shared between the concurrent calls.
+	mt := newMatcher(f)
 	sub, err := conn.Subscribe(subject, func(m *broker.Msg) {
 		var msg messaging.Message
 		if err := proto.Unmarshal(m.Data, &msg); err != nil {
 			return
 		}
+		if !mt.Match(msg) {
+			return
+		}
 		// There is no error handling, but the client takes care to log the error.
 		c.SendMessage(msg)
 	})
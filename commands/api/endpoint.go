@@ -0,0 +1,49 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/commands"
+)
+
+func sendCommandEndpoint(svc commands.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(sendCommandReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		cmd := commands.Command{
+			ChannelID:   req.ChannelID,
+			Payload:     []byte(req.Payload),
+			ContentType: req.ContentType,
+		}
+		c, err := svc.Send(ctx, req.token, cmd, time.Duration(req.Timeout)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		return newCommandRes(c, true), nil
+	}
+}
+
+func viewCommandEndpoint(svc commands.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewCommandReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		c, err := svc.View(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return newCommandRes(c, false), nil
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/commands"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var _ commands.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    commands.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc commands.Service, logger log.Logger) commands.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Send(ctx context.Context, token string, c commands.Command, timeout time.Duration) (cmd commands.Command, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method send for channel %s and command %s took %s to complete", c.ChannelID, cmd.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Send(ctx, token, c, timeout)
+}
+
+func (lm *loggingMiddleware) View(ctx context.Context, token, id string) (c commands.Command, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view for command %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.View(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Ack(ctx context.Context, msg messaging.Message) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method ack for channel %s and subtopic %s took %s to complete", msg.Channel, msg.Subtopic, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Ack(ctx, msg)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var _ commands.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     commands.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(svc commands.Service, counter metrics.Counter, latency metrics.Histogram) commands.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Send(ctx context.Context, token string, c commands.Command, timeout time.Duration) (commands.Command, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "send").Add(1)
+		ms.latency.With("method", "send").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Send(ctx, token, c, timeout)
+}
+
+func (ms *metricsMiddleware) View(ctx context.Context, token, id string) (commands.Command, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view").Add(1)
+		ms.latency.With("method", "view").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.View(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Ack(ctx context.Context, msg messaging.Message) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "ack").Add(1)
+		ms.latency.With("method", "ack").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Ack(ctx, msg)
+}
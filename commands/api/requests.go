@@ -0,0 +1,51 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"time"
+
+	"github.com/mainflux/mainflux/commands"
+)
+
+type sendCommandReq struct {
+	token       string
+	ChannelID   string `json:"channel_id"`
+	Payload     string `json:"payload"`
+	ContentType string `json:"content_type,omitempty"`
+	Timeout     uint64 `json:"timeout,omitempty"`
+}
+
+func (req sendCommandReq) validate() error {
+	if req.token == "" {
+		return commands.ErrUnauthorizedAccess
+	}
+
+	if req.ChannelID == "" || req.Payload == "" {
+		return commands.ErrMalformedEntity
+	}
+
+	if time.Duration(req.Timeout)*time.Second > commands.MaxTimeout {
+		return commands.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type viewCommandReq struct {
+	token string
+	id    string
+}
+
+func (req viewCommandReq) validate() error {
+	if req.token == "" {
+		return commands.ErrUnauthorizedAccess
+	}
+
+	if req.id == "" {
+		return commands.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/commands"
+)
+
+var _ mainflux.Response = (*commandRes)(nil)
+
+type commandRes struct {
+	ID          string     `json:"id"`
+	ChannelID   string     `json:"channel_id"`
+	Subtopic    string     `json:"subtopic"`
+	Payload     string     `json:"payload"`
+	ContentType string     `json:"content_type,omitempty"`
+	Status      string     `json:"status"`
+	Reply       string     `json:"reply,omitempty"`
+	AckedBy     string     `json:"acked_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	AckedAt     *time.Time `json:"acked_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	created     bool
+}
+
+func newCommandRes(c commands.Command, created bool) commandRes {
+	return commandRes{
+		ID:          c.ID,
+		ChannelID:   c.ChannelID,
+		Subtopic:    c.Subtopic(),
+		Payload:     string(c.Payload),
+		ContentType: c.ContentType,
+		Status:      c.Status,
+		Reply:       string(c.Reply),
+		AckedBy:     c.AckedBy,
+		CreatedAt:   c.CreatedAt,
+		DeliveredAt: optionalTime(c.DeliveredAt),
+		AckedAt:     optionalTime(c.AckedAt),
+		ExpiresAt:   c.ExpiresAt,
+		created:     created,
+	}
+}
+
+func (res commandRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res commandRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/commands/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res commandRes) Empty() bool {
+	return false
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/pkg/errors"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const contentType = "application/json"
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(tracer opentracing.Tracer, svc commands.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Post("/commands", kithttp.NewServer(
+		kitot.TraceServer(tracer, "send_command")(sendCommandEndpoint(svc)),
+		decodeSendCommand,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/commands/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_command")(viewCommandEndpoint(svc)),
+		decodeViewCommand,
+		encodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("commands"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeSendCommand(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := sendCommandReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(commands.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeViewCommand(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewCommandReq{
+		token: r.Header.Get("Authorization"),
+		id:    bone.GetValue(r, "id"),
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch {
+	case errors.Contains(err, commands.ErrUnauthorizedAccess):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, commands.ErrAuthorization):
+		w.WriteHeader(http.StatusForbidden)
+	case errors.Contains(err, commands.ErrMalformedEntity):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, commands.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, commands.ErrPublish):
+		w.WriteHeader(http.StatusBadGateway)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if err := json.NewEncoder(w).Encode(errorRes{Err: err.Error()}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
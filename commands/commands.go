@@ -0,0 +1,107 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The command statuses.
+const (
+	// Pending is the status of the command which is not yet published.
+	Pending = "pending"
+
+	// Delivered is the status of the command accepted by the message
+	// broker, which the device didn't acknowledge yet.
+	Delivered = "delivered"
+
+	// Acked is the status of the command acknowledged by the device.
+	Acked = "acked"
+
+	// Expired is the status of the command the device didn't acknowledge
+	// before the command timeout.
+	Expired = "expired"
+)
+
+const (
+	subtopicPrefix = "commands"
+	ackSuffix      = "ack"
+)
+
+// Command represents the command published to the devices connected to the
+// channel. The command is published to the "commands.<id>" subtopic of the
+// channel, and the device acknowledges it by publishing the reply to the
+// "commands.<id>.ack" subtopic of the same channel.
+type Command struct {
+	ID          string
+	Owner       string
+	ChannelID   string
+	Payload     []byte
+	ContentType string
+	Status      string
+	Reply       []byte
+	AckedBy     string
+	CreatedAt   time.Time
+	DeliveredAt time.Time
+	AckedAt     time.Time
+	ExpiresAt   time.Time
+}
+
+// Subtopic returns the subtopic the command is published to.
+func (c Command) Subtopic() string {
+	return fmt.Sprintf("%s.%s", subtopicPrefix, c.ID)
+}
+
+// AckSubtopic returns the subtopic the device acknowledges the command on.
+func (c Command) AckSubtopic() string {
+	return fmt.Sprintf("%s.%s", c.Subtopic(), ackSuffix)
+}
+
+// StatusAt returns the status of the command at the given time. The command
+// which isn't acknowledged before it expires is expired.
+func (c Command) StatusAt(t time.Time) string {
+	if c.Status != Acked && c.Status != Expired && !c.ExpiresAt.IsZero() && !t.Before(c.ExpiresAt) {
+		return Expired
+	}
+	return c.Status
+}
+
+// AckID returns the ID of the command acknowledged by the message published
+// to the subtopic, and false if the subtopic isn't the acknowledgement
+// subtopic.
+func AckID(subtopic string) (string, bool) {
+	parts := strings.Split(subtopic, ".")
+	if len(parts) != 3 || parts[0] != subtopicPrefix || parts[1] == "" || parts[2] != ackSuffix {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// AckTopic returns the NATS subject of the command acknowledgements on all
+// the channels.
+func AckTopic() string {
+	return fmt.Sprintf("channels.*.%s.*.%s", subtopicPrefix, ackSuffix)
+}
+
+// CommandRepository specifies the command persistence API.
+type CommandRepository interface {
+	// Save persists the pending command.
+	Save(ctx context.Context, c Command) error
+
+	// RetrieveByID retrieves the command of the owner by its unique
+	// identifier.
+	RetrieveByID(ctx context.Context, owner, id string) (Command, error)
+
+	// Deliver marks the pending command as delivered. The command which
+	// isn't pending anymore is left intact.
+	Deliver(ctx context.Context, id string, at time.Time) error
+
+	// Ack marks the unexpired command sent to the channel as acknowledged
+	// by the publisher, along with the reply. It returns ErrNotFound if
+	// there is no such command awaiting the acknowledgement.
+	Ack(ctx context.Context, c Command) error
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package commands contains the domain concept definitions needed to support
+// the commands service, which publishes the commands to the devices and
+// tracks their delivery until the devices acknowledge them.
+package commands
@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/commands"
+	"google.golang.org/grpc"
+)
+
+var _ mainflux.AuthServiceClient = (*authServiceClient)(nil)
+
+type authServiceClient struct {
+	users map[string]string
+}
+
+// NewAuthServiceClient creates mock of auth service.
+func NewAuthServiceClient(users map[string]string) mainflux.AuthServiceClient {
+	return &authServiceClient{users}
+}
+
+func (svc authServiceClient) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserIdentity, error) {
+	if id, ok := svc.users[in.Value]; ok {
+		return &mainflux.UserIdentity{Id: id, Email: id}, nil
+	}
+	return nil, commands.ErrUnauthorizedAccess
+}
+
+func (svc authServiceClient) Issue(ctx context.Context, in *mainflux.IssueReq, opts ...grpc.CallOption) (*mainflux.Token, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) Authorize(ctx context.Context, req *mainflux.AuthorizeReq, _ ...grpc.CallOption) (*mainflux.AuthorizeRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) AddPolicy(ctx context.Context, in *mainflux.AddPolicyReq, opts ...grpc.CallOption) (*mainflux.AddPolicyRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) DeletePolicy(ctx context.Context, in *mainflux.DeletePolicyReq, opts ...grpc.CallOption) (*mainflux.DeletePolicyRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) ListPolicies(ctx context.Context, in *mainflux.ListPoliciesReq, opts ...grpc.CallOption) (*mainflux.ListPoliciesRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) Members(ctx context.Context, req *mainflux.MembersReq, _ ...grpc.CallOption) (*mainflux.MembersRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) Assign(ctx context.Context, req *mainflux.Assignment, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) ReserveQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) ReleaseQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) RevokeKeys(ctx context.Context, req *mainflux.RevokeKeysReq, _ ...grpc.CallOption) (*mainflux.RevokeKeysRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) ApplyPolicyTemplate(ctx context.Context, req *mainflux.PolicyTemplateReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceClient) RemoveLinks(ctx context.Context, req *mainflux.RemoveLinksReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/commands"
+)
+
+var _ commands.CommandRepository = (*commandRepositoryMock)(nil)
+
+type commandRepositoryMock struct {
+	mu       sync.Mutex
+	commands map[string]commands.Command
+}
+
+// NewCommandRepository creates in-memory command repository.
+func NewCommandRepository() commands.CommandRepository {
+	return &commandRepositoryMock{
+		commands: make(map[string]commands.Command),
+	}
+}
+
+func (crm *commandRepositoryMock) Save(ctx context.Context, c commands.Command) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	crm.commands[c.ID] = c
+	return nil
+}
+
+func (crm *commandRepositoryMock) RetrieveByID(ctx context.Context, owner, id string) (commands.Command, error) {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.commands[id]
+	if !ok || c.Owner != owner {
+		return commands.Command{}, commands.ErrNotFound
+	}
+	return c, nil
+}
+
+func (crm *commandRepositoryMock) Deliver(ctx context.Context, id string, at time.Time) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.commands[id]
+	if !ok || c.Status != commands.Pending {
+		return nil
+	}
+	c.Status = commands.Delivered
+	c.DeliveredAt = at
+	crm.commands[id] = c
+	return nil
+}
+
+func (crm *commandRepositoryMock) Ack(ctx context.Context, ack commands.Command) error {
+	crm.mu.Lock()
+	defer crm.mu.Unlock()
+
+	c, ok := crm.commands[ack.ID]
+	if !ok || c.ChannelID != ack.ChannelID || c.StatusAt(ack.AckedAt) == commands.Acked || c.StatusAt(ack.AckedAt) == commands.Expired {
+		return commands.ErrNotFound
+	}
+	c.Status = commands.Acked
+	c.Reply = ack.Reply
+	c.AckedBy = ack.AckedBy
+	c.AckedAt = ack.AckedAt
+	crm.commands[ack.ID] = c
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var _ messaging.Publisher = (*Publisher)(nil)
+
+// Publisher is the mock message publisher recording the published messages.
+type Publisher struct {
+	mu       sync.Mutex
+	messages []messaging.Message
+}
+
+// NewPublisher returns mock message publisher.
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+// Publish records the message. The message without the payload fails to be
+// published.
+func (p *Publisher) Publish(topic string, msg messaging.Message) error {
+	if len(msg.Payload) == 0 {
+		return errors.New("failed to publish")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, msg)
+	return nil
+}
+
+// Messages returns the published messages.
+func (p *Publisher) Messages() []messaging.Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]messaging.Message{}, p.messages...)
+}
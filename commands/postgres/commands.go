@@ -0,0 +1,162 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	errInvalid    = "invalid_text_representation"
+	errTruncation = "string_data_right_truncation"
+)
+
+var (
+	errSaveDB     = errors.New("failed to save command to database")
+	errRetrieveDB = errors.New("failed to retrieve command from database")
+	errUpdateDB   = errors.New("failed to update command in database")
+)
+
+var _ commands.CommandRepository = (*commandRepository)(nil)
+
+type commandRepository struct {
+	db *sqlx.DB
+}
+
+// NewCommandRepository instantiates a PostgreSQL implementation of command
+// repository.
+func NewCommandRepository(db *sqlx.DB) commands.CommandRepository {
+	return &commandRepository{db: db}
+}
+
+func (cr commandRepository) Save(ctx context.Context, c commands.Command) error {
+	q := `INSERT INTO commands (id, owner, channel_id, payload, content_type, status, created_at, expires_at)
+	      VALUES (:id, :owner, :channel_id, :payload, :content_type, :status, :created_at, :expires_at)`
+
+	if _, err := cr.db.NamedExecContext(ctx, q, toDBCommand(c)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && (pqErr.Code.Name() == errInvalid || pqErr.Code.Name() == errTruncation) {
+			return errors.Wrap(commands.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errSaveDB, err)
+	}
+
+	return nil
+}
+
+func (cr commandRepository) RetrieveByID(ctx context.Context, owner, id string) (commands.Command, error) {
+	q := `SELECT id, owner, channel_id, payload, content_type, status, reply, acked_by, created_at, delivered_at, acked_at, expires_at
+	      FROM commands WHERE owner = $1 AND id = $2`
+
+	dbc := dbCommand{}
+	if err := cr.db.QueryRowxContext(ctx, q, owner, id).StructScan(&dbc); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return commands.Command{}, errors.Wrap(commands.ErrNotFound, err)
+		}
+		return commands.Command{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toCommand(dbc), nil
+}
+
+func (cr commandRepository) Deliver(ctx context.Context, id string, at time.Time) error {
+	q := `UPDATE commands SET status = $1, delivered_at = $2 WHERE id = $3 AND status = $4`
+
+	if _, err := cr.db.ExecContext(ctx, q, commands.Delivered, at, id, commands.Pending); err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	return nil
+}
+
+func (cr commandRepository) Ack(ctx context.Context, c commands.Command) error {
+	q := `UPDATE commands SET status = :status, reply = :reply, acked_by = :acked_by, acked_at = :acked_at
+	      WHERE id = :id AND channel_id = :channel_id AND status IN ('pending', 'delivered') AND expires_at > :acked_at`
+
+	dbc := toDBCommand(c)
+	dbc.Status = commands.Acked
+	res, err := cr.db.NamedExecContext(ctx, q, dbc)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(commands.ErrNotFound, err)
+		}
+		return errors.Wrap(errUpdateDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateDB, err)
+	}
+	if cnt != 1 {
+		return commands.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbCommand struct {
+	ID          string         `db:"id"`
+	Owner       string         `db:"owner"`
+	ChannelID   string         `db:"channel_id"`
+	Payload     []byte         `db:"payload"`
+	ContentType sql.NullString `db:"content_type"`
+	Status      string         `db:"status"`
+	Reply       []byte         `db:"reply"`
+	AckedBy     sql.NullString `db:"acked_by"`
+	CreatedAt   time.Time      `db:"created_at"`
+	DeliveredAt sql.NullTime   `db:"delivered_at"`
+	AckedAt     sql.NullTime   `db:"acked_at"`
+	ExpiresAt   time.Time      `db:"expires_at"`
+}
+
+func toDBCommand(c commands.Command) dbCommand {
+	return dbCommand{
+		ID:          c.ID,
+		Owner:       c.Owner,
+		ChannelID:   c.ChannelID,
+		Payload:     c.Payload,
+		ContentType: toNullString(c.ContentType),
+		Status:      c.Status,
+		Reply:       c.Reply,
+		AckedBy:     toNullString(c.AckedBy),
+		CreatedAt:   c.CreatedAt,
+		DeliveredAt: toNullTime(c.DeliveredAt),
+		AckedAt:     toNullTime(c.AckedAt),
+		ExpiresAt:   c.ExpiresAt,
+	}
+}
+
+func toCommand(dbc dbCommand) commands.Command {
+	return commands.Command{
+		ID:          dbc.ID,
+		Owner:       dbc.Owner,
+		ChannelID:   dbc.ChannelID,
+		Payload:     dbc.Payload,
+		ContentType: dbc.ContentType.String,
+		Status:      dbc.Status,
+		Reply:       dbc.Reply,
+		AckedBy:     dbc.AckedBy.String,
+		CreatedAt:   dbc.CreatedAt,
+		DeliveredAt: dbc.DeliveredAt.Time,
+		AckedAt:     dbc.AckedAt.Time,
+		ExpiresAt:   dbc.ExpiresAt,
+	}
+}
+
+func toNullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func toNullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
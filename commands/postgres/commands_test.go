@@ -0,0 +1,142 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/commands/postgres"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner  = "user@example.com"
+	chanID = "chan"
+)
+
+func newCommand(t *testing.T, timeout time.Duration) commands.Command {
+	id, err := uuid.New().ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	now := time.Now().UTC().Round(time.Microsecond)
+	return commands.Command{
+		ID:          id,
+		Owner:       owner,
+		ChannelID:   chanID,
+		Payload:     []byte(`{"set": "on"}`),
+		ContentType: "application/json",
+		Status:      commands.Pending,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(timeout),
+	}
+}
+
+func TestCommandSave(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+
+	c := newCommand(t, time.Minute)
+	err := repo.Save(context.Background(), c)
+	assert.Nil(t, err, fmt.Sprintf("save command: unexpected error: %s\n", err))
+
+	err = repo.Save(context.Background(), c)
+	assert.NotNil(t, err, "save existing command: expected error got nil\n")
+
+	invalid := newCommand(t, time.Minute)
+	invalid.ID = "invalid"
+	err = repo.Save(context.Background(), invalid)
+	assert.True(t, errors.Contains(err, commands.ErrMalformedEntity), fmt.Sprintf("save command with invalid ID: expected %s got %s\n", commands.ErrMalformedEntity, err))
+}
+
+func TestCommandRetrieveByID(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+
+	c := newCommand(t, time.Minute)
+	err := repo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{desc: "retrieve command", owner: owner, id: c.ID, err: nil},
+		{desc: "retrieve command of another owner", owner: "other@example.com", id: c.ID, err: commands.ErrNotFound},
+		{desc: "retrieve command with invalid ID", owner: owner, id: "invalid", err: commands.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		res, err := repo.RetrieveByID(context.Background(), tc.owner, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.Equal(t, c.Payload, res.Payload, fmt.Sprintf("%s: expected payload %s got %s\n", tc.desc, c.Payload, res.Payload))
+			assert.Equal(t, commands.Pending, res.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, commands.Pending, res.Status))
+		}
+	}
+}
+
+func TestCommandDeliverAndAck(t *testing.T) {
+	repo := postgres.NewCommandRepository(db)
+
+	c := newCommand(t, time.Minute)
+	err := repo.Save(context.Background(), c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	expired := newCommand(t, -time.Second)
+	err = repo.Save(context.Background(), expired)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = repo.Deliver(context.Background(), c.ID, time.Now())
+	assert.Nil(t, err, fmt.Sprintf("deliver command: unexpected error: %s\n", err))
+
+	reply := []byte(`{"result": "done"}`)
+	cases := []struct {
+		desc string
+		ack  commands.Command
+		err  error
+	}{
+		{
+			desc: "ack command on another channel",
+			ack:  commands.Command{ID: c.ID, ChannelID: "other", Reply: reply, AckedBy: "thing", AckedAt: time.Now()},
+			err:  commands.ErrNotFound,
+		},
+		{
+			desc: "ack command",
+			ack:  commands.Command{ID: c.ID, ChannelID: chanID, Reply: reply, AckedBy: "thing", AckedAt: time.Now()},
+			err:  nil,
+		},
+		{
+			desc: "ack acknowledged command",
+			ack:  commands.Command{ID: c.ID, ChannelID: chanID, Reply: reply, AckedBy: "thing", AckedAt: time.Now()},
+			err:  commands.ErrNotFound,
+		},
+		{
+			desc: "ack expired command",
+			ack:  commands.Command{ID: expired.ID, ChannelID: chanID, Reply: reply, AckedBy: "thing", AckedAt: time.Now()},
+			err:  commands.ErrNotFound,
+		},
+		{
+			desc: "ack command with invalid ID",
+			ack:  commands.Command{ID: "invalid", ChannelID: chanID, Reply: reply, AckedBy: "thing", AckedAt: time.Now()},
+			err:  commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.Ack(context.Background(), tc.ack)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	res, err := repo.RetrieveByID(context.Background(), owner, c.ID)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, commands.Acked, res.Status, fmt.Sprintf("expected status %s got %s\n", commands.Acked, res.Status))
+	assert.Equal(t, reply, res.Reply, fmt.Sprintf("expected reply %s got %s\n", reply, res.Reply))
+	assert.Equal(t, "thing", res.AckedBy, fmt.Sprintf("expected acked by %s got %s\n", "thing", res.AckedBy))
+}
@@ -0,0 +1,186 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+const (
+	protocol = "commands"
+
+	// MaxTimeout is the longest command timeout.
+	MaxTimeout = 24 * time.Hour
+)
+
+var (
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// the command without the channel).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrAuthorization indicates that the user doesn't own the channel the
+	// command is sent to.
+	ErrAuthorization = errors.New("channel is not owned by the user")
+
+	// ErrNotFound indicates a non-existent entity request.
+	ErrNotFound = errors.New("non-existent entity")
+
+	// ErrPublish indicates that the command failed to be published.
+	ErrPublish = errors.New("failed to publish command")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Send publishes the command to the devices connected to the channel
+	// owned by the user identified by the token. The command expires if
+	// the device doesn't acknowledge it within the timeout, and the zero
+	// timeout stands for the default one.
+	Send(ctx context.Context, token string, c Command, timeout time.Duration) (Command, error)
+
+	// View retrieves the command sent by the user identified by the token,
+	// along with its current status.
+	View(ctx context.Context, token, id string) (Command, error)
+
+	// Ack handles the message published to the acknowledgement subtopic of
+	// the command. The acknowledgements of the unknown, already acknowledged
+	// or expired commands are ignored.
+	Ack(ctx context.Context, msg messaging.Message) error
+}
+
+var _ Service = (*commandsService)(nil)
+
+type commandsService struct {
+	auth       mainflux.AuthServiceClient
+	things     mainflux.ThingsServiceClient
+	commands   CommandRepository
+	publisher  messaging.Publisher
+	idProvider mainflux.IDProvider
+	timeout    time.Duration
+}
+
+// New instantiates the commands service implementation. The timeout is the
+// default command timeout.
+func New(auth mainflux.AuthServiceClient, things mainflux.ThingsServiceClient, commands CommandRepository, publisher messaging.Publisher, idp mainflux.IDProvider, timeout time.Duration) Service {
+	return &commandsService{
+		auth:       auth,
+		things:     things,
+		commands:   commands,
+		publisher:  publisher,
+		idProvider: idp,
+		timeout:    timeout,
+	}
+}
+
+func (svc *commandsService) Send(ctx context.Context, token string, c Command, timeout time.Duration) (Command, error) {
+	if c.ChannelID == "" || timeout < 0 || timeout > MaxTimeout {
+		return Command{}, ErrMalformedEntity
+	}
+	if timeout == 0 {
+		timeout = svc.timeout
+	}
+
+	owner, err := svc.identify(ctx, token)
+	if err != nil {
+		return Command{}, err
+	}
+
+	if _, err := svc.things.IsChannelOwner(ctx, &mainflux.ChannelOwnerReq{Owner: owner, ChanID: c.ChannelID}); err != nil {
+		return Command{}, errors.Wrap(ErrAuthorization, err)
+	}
+
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		return Command{}, err
+	}
+
+	now := time.Now().UTC()
+	c.ID = id
+	c.Owner = owner
+	c.Status = Pending
+	c.Reply = nil
+	c.AckedBy = ""
+	c.CreatedAt = now
+	c.DeliveredAt = time.Time{}
+	c.AckedAt = time.Time{}
+	c.ExpiresAt = now.Add(timeout)
+
+	// The command is saved before it's published, since the device may
+	// acknowledge it right away.
+	if err := svc.commands.Save(ctx, c); err != nil {
+		return Command{}, err
+	}
+
+	msg := messaging.Message{
+		Channel:     c.ChannelID,
+		Subtopic:    c.Subtopic(),
+		Protocol:    protocol,
+		Payload:     c.Payload,
+		ContentType: c.ContentType,
+		Created:     now.UnixNano(),
+	}
+	if err := svc.publisher.Publish(c.ChannelID, msg); err != nil {
+		return Command{}, errors.Wrap(ErrPublish, err)
+	}
+
+	delivered := time.Now().UTC()
+	if err := svc.commands.Deliver(ctx, c.ID, delivered); err != nil {
+		return Command{}, err
+	}
+
+	return svc.commands.RetrieveByID(ctx, owner, c.ID)
+}
+
+func (svc *commandsService) View(ctx context.Context, token, id string) (Command, error) {
+	owner, err := svc.identify(ctx, token)
+	if err != nil {
+		return Command{}, err
+	}
+
+	c, err := svc.commands.RetrieveByID(ctx, owner, id)
+	if err != nil {
+		return Command{}, err
+	}
+	c.Status = c.StatusAt(time.Now())
+
+	return c, nil
+}
+
+func (svc *commandsService) Ack(ctx context.Context, msg messaging.Message) error {
+	id, ok := AckID(msg.Subtopic)
+	if !ok {
+		return nil
+	}
+
+	c := Command{
+		ID:        id,
+		ChannelID: msg.Channel,
+		Reply:     msg.Payload,
+		AckedBy:   msg.Publisher,
+		AckedAt:   time.Now().UTC(),
+	}
+	if err := svc.commands.Ack(ctx, c); err != nil && !errors.Contains(err, ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (svc *commandsService) identify(ctx context.Context, token string) (string, error) {
+	res, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return res.GetEmail(), nil
+}
@@ -0,0 +1,252 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package commands_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/commands"
+	"github.com/mainflux/mainflux/commands/mocks"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token      = "token"
+	wrongToken = "wrong-token"
+	owner      = "user@example.com"
+	otherToken = "other-token"
+	other      = "other@example.com"
+	chanID     = "chan"
+	otherChan  = "other-chan"
+	thingID    = "thing"
+)
+
+var payload = []byte(`{"set": "on"}`)
+
+func newService() (commands.Service, *mocks.Publisher) {
+	auth := mocks.NewAuthServiceClient(map[string]string{token: owner, otherToken: other})
+	things := mocks.NewThingsService(map[string]string{chanID: owner, otherChan: other})
+	pub := mocks.NewPublisher()
+	return commands.New(auth, things, mocks.NewCommandRepository(), pub, uuid.NewMock(), time.Minute), pub
+}
+
+func TestSend(t *testing.T) {
+	svc, pub := newService()
+
+	cases := []struct {
+		desc    string
+		token   string
+		cmd     commands.Command
+		timeout time.Duration
+		err     error
+	}{
+		{
+			desc:  "send command",
+			token: token,
+			cmd:   commands.Command{ChannelID: chanID, Payload: payload, ContentType: "application/json"},
+			err:   nil,
+		},
+		{
+			desc:    "send command with timeout",
+			token:   token,
+			cmd:     commands.Command{ChannelID: chanID, Payload: payload},
+			timeout: time.Second,
+			err:     nil,
+		},
+		{
+			desc:  "send command with invalid credentials",
+			token: wrongToken,
+			cmd:   commands.Command{ChannelID: chanID, Payload: payload},
+			err:   commands.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "send command to channel of another user",
+			token: token,
+			cmd:   commands.Command{ChannelID: otherChan, Payload: payload},
+			err:   commands.ErrAuthorization,
+		},
+		{
+			desc:  "send command without channel",
+			token: token,
+			cmd:   commands.Command{Payload: payload},
+			err:   commands.ErrMalformedEntity,
+		},
+		{
+			desc:    "send command with too long timeout",
+			token:   token,
+			cmd:     commands.Command{ChannelID: chanID, Payload: payload},
+			timeout: commands.MaxTimeout + time.Second,
+			err:     commands.ErrMalformedEntity,
+		},
+		{
+			desc:  "send command failing to publish",
+			token: token,
+			cmd:   commands.Command{ChannelID: chanID},
+			err:   commands.ErrPublish,
+		},
+	}
+
+	for _, tc := range cases {
+		before := len(pub.Messages())
+		c, err := svc.Send(context.Background(), tc.token, tc.cmd, tc.timeout)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err != nil {
+			continue
+		}
+
+		timeout := tc.timeout
+		if timeout == 0 {
+			timeout = time.Minute
+		}
+		assert.Equal(t, commands.Delivered, c.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, commands.Delivered, c.Status))
+		assert.Equal(t, owner, c.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, owner, c.Owner))
+		assert.Equal(t, timeout, c.ExpiresAt.Sub(c.CreatedAt), fmt.Sprintf("%s: expected timeout %s got %s\n", tc.desc, timeout, c.ExpiresAt.Sub(c.CreatedAt)))
+
+		msgs := pub.Messages()
+		require.Len(t, msgs, before+1, fmt.Sprintf("%s: expected the command to be published\n", tc.desc))
+		msg := msgs[before]
+		assert.Equal(t, chanID, msg.Channel, fmt.Sprintf("%s: expected channel %s got %s\n", tc.desc, chanID, msg.Channel))
+		assert.Equal(t, c.Subtopic(), msg.Subtopic, fmt.Sprintf("%s: expected subtopic %s got %s\n", tc.desc, c.Subtopic(), msg.Subtopic))
+		assert.Equal(t, tc.cmd.Payload, msg.Payload, fmt.Sprintf("%s: expected payload %s got %s\n", tc.desc, tc.cmd.Payload, msg.Payload))
+	}
+}
+
+func TestView(t *testing.T) {
+	svc, _ := newService()
+
+	c, err := svc.Send(context.Background(), token, commands.Command{ChannelID: chanID, Payload: payload}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		id     string
+		status string
+		err    error
+	}{
+		{
+			desc:   "view command",
+			token:  token,
+			id:     c.ID,
+			status: commands.Delivered,
+			err:    nil,
+		},
+		{
+			desc:  "view command with invalid credentials",
+			token: wrongToken,
+			id:    c.ID,
+			err:   commands.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "view command of another user",
+			token: otherToken,
+			id:    c.ID,
+			err:   commands.ErrNotFound,
+		},
+		{
+			desc:  "view non-existing command",
+			token: token,
+			id:    "unknown",
+			err:   commands.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := svc.View(context.Background(), tc.token, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.status, res.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, res.Status))
+	}
+}
+
+func TestAck(t *testing.T) {
+	svc, _ := newService()
+
+	acked, err := svc.Send(context.Background(), token, commands.Command{ChannelID: chanID, Payload: payload}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	expired, err := svc.Send(context.Background(), token, commands.Command{ChannelID: chanID, Payload: payload}, time.Millisecond)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	pending, err := svc.Send(context.Background(), token, commands.Command{ChannelID: chanID, Payload: payload}, 0)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	time.Sleep(5 * time.Millisecond)
+
+	reply := []byte(`{"result": "done"}`)
+	cases := []struct {
+		desc   string
+		msg    messaging.Message
+		id     string
+		status string
+		reply  []byte
+	}{
+		{
+			desc:   "ack command",
+			msg:    messaging.Message{Channel: chanID, Subtopic: acked.AckSubtopic(), Publisher: thingID, Payload: reply},
+			id:     acked.ID,
+			status: commands.Acked,
+			reply:  reply,
+		},
+		{
+			desc:   "ack acknowledged command",
+			msg:    messaging.Message{Channel: chanID, Subtopic: acked.AckSubtopic(), Publisher: thingID, Payload: []byte("again")},
+			id:     acked.ID,
+			status: commands.Acked,
+			reply:  reply,
+		},
+		{
+			desc:   "ack expired command",
+			msg:    messaging.Message{Channel: chanID, Subtopic: expired.AckSubtopic(), Publisher: thingID, Payload: reply},
+			id:     expired.ID,
+			status: commands.Expired,
+		},
+		{
+			desc:   "ack command on another channel",
+			msg:    messaging.Message{Channel: otherChan, Subtopic: pending.AckSubtopic(), Publisher: thingID, Payload: reply},
+			id:     pending.ID,
+			status: commands.Delivered,
+		},
+		{
+			desc:   "publish command reply to non-ack subtopic",
+			msg:    messaging.Message{Channel: chanID, Subtopic: pending.Subtopic(), Publisher: thingID, Payload: reply},
+			id:     pending.ID,
+			status: commands.Delivered,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Ack(context.Background(), tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		c, err := svc.View(context.Background(), token, tc.id)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.status, c.Status, fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, tc.status, c.Status))
+		assert.Equal(t, tc.reply, c.Reply, fmt.Sprintf("%s: expected reply %s got %s\n", tc.desc, tc.reply, c.Reply))
+	}
+}
+
+func TestAckID(t *testing.T) {
+	cases := []struct {
+		desc     string
+		subtopic string
+		id       string
+		ok       bool
+	}{
+		{desc: "parse ack subtopic", subtopic: "commands.id.ack", id: "id", ok: true},
+		{desc: "parse command subtopic", subtopic: "commands.id", ok: false},
+		{desc: "parse other subtopic", subtopic: "events.id.ack", ok: false},
+		{desc: "parse subtopic without id", subtopic: "commands..ack", ok: false},
+		{desc: "parse nested subtopic", subtopic: "commands.id.ack.more", ok: false},
+	}
+
+	for _, tc := range cases {
+		id, ok := commands.AckID(tc.subtopic)
+		assert.Equal(t, tc.ok, ok, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.ok, ok))
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected id %s got %s\n", tc.desc, tc.id, id))
+	}
+}
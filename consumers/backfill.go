@@ -0,0 +1,207 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+// SubjectSourcePrefix marks the backfill source as the messaging subject
+// (e.g. the DLQ subject) instead of the file path.
+const SubjectSourcePrefix = "nats:"
+
+const maxLineSize = 1024 * 1024
+
+var (
+	// ErrMalformedEnvelope indicates that the backfill source contains a line
+	// that is not a valid message envelope.
+	ErrMalformedEnvelope = errors.New("malformed message envelope")
+
+	errEmptySource = errors.New("empty backfill source")
+)
+
+// Source provides the messages to backfill.
+type Source interface {
+	// Next returns the next message, or io.EOF once the source is exhausted.
+	Next(ctx context.Context) (messaging.Message, error)
+
+	// Close releases the resources held by the source.
+	Close() error
+}
+
+// NewSource opens the backfill source. The source prefixed with
+// SubjectSourcePrefix is consumed from the messaging subject until no
+// message is received during the idle period. Any other source is treated
+// as the path to the file containing the NDJSON encoded message envelopes.
+func NewSource(sub messaging.Subscriber, source string, idle time.Duration) (Source, error) {
+	if source == "" {
+		return nil, errEmptySource
+	}
+
+	if strings.HasPrefix(source, SubjectSourcePrefix) {
+		return NewSubjectSource(sub, strings.TrimPrefix(source, SubjectSourcePrefix), idle)
+	}
+
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	return NewFileSource(f), nil
+}
+
+type fileSource struct {
+	rc      io.ReadCloser
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewFileSource returns the source reading the NDJSON encoded message
+// envelopes, one per line. Empty lines are skipped.
+func NewFileSource(rc io.ReadCloser) Source {
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	return &fileSource{
+		rc:      rc,
+		scanner: scanner,
+	}
+}
+
+func (fs *fileSource) Next(ctx context.Context) (messaging.Message, error) {
+	for fs.scanner.Scan() {
+		fs.line++
+		line := strings.TrimSpace(fs.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var msg messaging.Message
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			return messaging.Message{}, errors.Wrap(ErrMalformedEnvelope, fmt.Errorf("line %d: %s", fs.line, err))
+		}
+		return msg, nil
+	}
+
+	if err := fs.scanner.Err(); err != nil {
+		return messaging.Message{}, err
+	}
+	return messaging.Message{}, io.EOF
+}
+
+func (fs *fileSource) Close() error {
+	return fs.rc.Close()
+}
+
+type subjectSource struct {
+	sub      messaging.Subscriber
+	subject  string
+	idle     time.Duration
+	messages chan messaging.Message
+}
+
+// NewSubjectSource returns the source consuming the messages published to
+// the subject. The source is exhausted once no message is received during
+// the idle period.
+func NewSubjectSource(sub messaging.Subscriber, subject string, idle time.Duration) (Source, error) {
+	if subject == "" {
+		return nil, errEmptySource
+	}
+
+	ss := &subjectSource{
+		sub:      sub,
+		subject:  subject,
+		idle:     idle,
+		messages: make(chan messaging.Message),
+	}
+
+	// The handler blocks until the message is taken, which keeps the
+	// consumption at the pace of the backfill.
+	handler := func(msg messaging.Message) error {
+		ss.messages <- msg
+		return nil
+	}
+	if err := sub.Subscribe(subject, handler); err != nil {
+		return nil, err
+	}
+
+	return ss, nil
+}
+
+func (ss *subjectSource) Next(ctx context.Context) (messaging.Message, error) {
+	timer := time.NewTimer(ss.idle)
+	defer timer.Stop()
+
+	select {
+	case msg := <-ss.messages:
+		return msg, nil
+	case <-timer.C:
+		return messaging.Message{}, io.EOF
+	case <-ctx.Done():
+		return messaging.Message{}, ctx.Err()
+	}
+}
+
+func (ss *subjectSource) Close() error {
+	return ss.sub.Unsubscribe(ss.subject)
+}
+
+// Backfill consumes the messages read from the source at most at the given
+// rate of messages per second, or as fast as possible if the rate is not
+// positive. The messages are transformed the same way as the ones received
+// by the consumer started with Start. The messages that fail to be consumed
+// are logged and skipped. Backfill returns the number of consumed messages.
+func Backfill(ctx context.Context, src Source, consumer Consumer, configPath string, rate int, logger logger.Logger) (uint64, error) {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to load consumer config: %s", err))
+	}
+
+	h := handler(makeTransformer(cfg, logger), consumer)
+
+	var tick <-chan time.Time
+	if rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var consumed uint64
+	for {
+		msg, err := src.Next(ctx)
+		switch {
+		case err == io.EOF:
+			return consumed, nil
+		case errors.Contains(err, ErrMalformedEnvelope):
+			logger.Warn(fmt.Sprintf("Skipping backfill message: %s", err))
+			continue
+		case err != nil:
+			return consumed, err
+		}
+
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				return consumed, ctx.Err()
+			}
+		}
+
+		if err := h(msg); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to backfill message from channel %s: %s", msg.Channel, err))
+			continue
+		}
+		consumed++
+	}
+}
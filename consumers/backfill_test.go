@@ -0,0 +1,81 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumers_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type consumerMock struct {
+	consumed []interface{}
+}
+
+func (cm *consumerMock) Consume(msgs interface{}) error {
+	cm.consumed = append(cm.consumed, msgs)
+	return nil
+}
+
+func TestBackfill(t *testing.T) {
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msg := `{"channel":"1","publisher":"2","protocol":"http","payload":"W3siYm4iOiJ0ZW1wIiwidiI6MjB9XQ==","content_type":"application/senml+json"}`
+
+	cases := []struct {
+		desc     string
+		data     string
+		rate     int
+		consumed uint64
+	}{
+		{
+			desc:     "backfill messages",
+			data:     strings.Join([]string{msg, msg}, "\n"),
+			consumed: 2,
+		},
+		{
+			desc:     "backfill messages at limited rate",
+			data:     strings.Join([]string{msg, msg}, "\n"),
+			rate:     100,
+			consumed: 2,
+		},
+		{
+			desc:     "backfill messages skipping empty lines",
+			data:     strings.Join([]string{msg, "", msg, ""}, "\n"),
+			consumed: 2,
+		},
+		{
+			desc:     "backfill messages skipping malformed envelopes",
+			data:     strings.Join([]string{msg, "{", msg}, "\n"),
+			consumed: 2,
+		},
+		{
+			desc:     "backfill messages skipping untransformable payloads",
+			data:     strings.Join([]string{msg, `{"channel":"1","payload":"e30="}`}, "\n"),
+			consumed: 1,
+		},
+		{
+			desc:     "backfill empty source",
+			data:     "",
+			consumed: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		src := consumers.NewFileSource(ioutil.NopCloser(strings.NewReader(tc.data)))
+		c := &consumerMock{}
+		consumed, err := consumers.Backfill(context.Background(), src, c, "", tc.rate, logger)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, tc.consumed, consumed, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.consumed, consumed))
+		assert.Equal(t, int(tc.consumed), len(c.consumed), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.consumed, len(c.consumed)))
+	}
+}
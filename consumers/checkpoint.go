@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumers
+
+import (
+	"fmt"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+// Checkpoint represents the position of the named consumer in the message
+// stream.
+type Checkpoint struct {
+	Consumer string
+	Sequence uint64
+}
+
+// CheckpointConsumer specifies the API of the consumer storing its position
+// in the message stream together with the consumed messages.
+type CheckpointConsumer interface {
+	Consumer
+
+	// ConsumeAt consumes the messages received at the checkpoint and
+	// stores the checkpoint atomically with them. The messages received
+	// at or before the stored checkpoint are ignored, since they are
+	// already consumed.
+	ConsumeAt(messages interface{}, cp Checkpoint) error
+
+	// Checkpoint returns the sequence of the last message consumed by the
+	// named consumer, or 0 if the consumer hasn't consumed any message.
+	Checkpoint(name string) (uint64, error)
+}
+
+// StartStream starts consuming messages from the persistent message stream,
+// resuming after the last checkpoint of the named consumer. The messages
+// are transformed the same way as the ones received by the consumer started
+// with Start. Since only the first configured subject is consumed, the
+// configured channels and patterns are not supported. The messages that
+// fail to be transformed are logged and skipped, while the ones that fail
+// to be consumed are redelivered.
+func StartStream(sub messaging.StreamSubscriber, consumer CheckpointConsumer, name, configPath string, logger logger.Logger) error {
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("Failed to load consumer config: %s", err))
+	}
+
+	transformer := makeTransformer(cfg, logger)
+
+	seq, err := consumer.Checkpoint(name)
+	if err != nil {
+		return err
+	}
+
+	subject := defSubjects[0]
+	if len(cfg.SubscriberCfg.Subjects) > 0 {
+		subject = cfg.SubscriberCfg.Subjects[0]
+	}
+	if len(cfg.SubscriberCfg.Subjects) > 1 || len(cfg.SubscriberCfg.Channels) > 0 || len(cfg.SubscriberCfg.Patterns) > 0 {
+		logger.Warn(fmt.Sprintf("Consuming the stream only from %s", subject))
+	}
+
+	h := func(msg messaging.Message, seq uint64) error {
+		m := interface{}(msg)
+		if transformer != nil {
+			var err error
+			if m, err = transformer.Transform(msg); err != nil {
+				logger.Warn(fmt.Sprintf("Skipping message %d from channel %s: %s", seq, msg.Channel, err))
+				return nil
+			}
+		}
+		return consumer.ConsumeAt(m, Checkpoint{Consumer: name, Sequence: seq})
+	}
+
+	logger.Info(fmt.Sprintf("Consuming the stream as %s after message %d", name, seq))
+	return sub.SubscribeFrom(subject, seq+1, h)
+}
@@ -0,0 +1,156 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumers_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errCheckpoint = errors.New("checkpoint unavailable")
+
+type streamSubscriberMock struct {
+	topic   string
+	seq     uint64
+	handler messaging.StreamHandler
+}
+
+func (ssm *streamSubscriberMock) SubscribeFrom(topic string, seq uint64, handler messaging.StreamHandler) error {
+	ssm.topic = topic
+	ssm.seq = seq
+	ssm.handler = handler
+	return nil
+}
+
+func (ssm *streamSubscriberMock) Unsubscribe(topic string) error {
+	return nil
+}
+
+type checkpointConsumerMock struct {
+	consumerMock
+	seq uint64
+	err error
+}
+
+func (ccm *checkpointConsumerMock) ConsumeAt(msgs interface{}, cp consumers.Checkpoint) error {
+	if cp.Sequence <= ccm.seq {
+		return nil
+	}
+	ccm.seq = cp.Sequence
+	return ccm.Consume(msgs)
+}
+
+func (ccm *checkpointConsumerMock) Checkpoint(name string) (uint64, error) {
+	return ccm.seq, ccm.err
+}
+
+func TestStartStream(t *testing.T) {
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	dir, err := ioutil.TempDir("", "consumers")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	msg := messaging.Message{
+		Channel:     "1",
+		Publisher:   "2",
+		Protocol:    "http",
+		Payload:     []byte(`[{"bn":"temp","v":20}]`),
+		ContentType: "application/senml+json",
+	}
+	malformed := msg
+	malformed.Payload = []byte("{")
+
+	cases := []struct {
+		desc     string
+		config   string
+		seq      uint64
+		err      error
+		msgs     []messaging.Message
+		seqs     []uint64
+		topic    string
+		start    uint64
+		consumed int
+		last     uint64
+	}{
+		{
+			desc:     "consume stream from the start",
+			msgs:     []messaging.Message{msg, msg},
+			seqs:     []uint64{1, 2},
+			topic:    "channels.>",
+			start:    1,
+			consumed: 2,
+			last:     2,
+		},
+		{
+			desc:     "consume stream after the checkpoint",
+			config:   `subjects = ["channels.1.>"]`,
+			seq:      5,
+			msgs:     []messaging.Message{msg, msg},
+			seqs:     []uint64{6, 7},
+			topic:    "channels.1.>",
+			start:    6,
+			consumed: 2,
+			last:     7,
+		},
+		{
+			desc:     "consume stream skipping redelivered messages",
+			seq:      5,
+			msgs:     []messaging.Message{msg, msg},
+			seqs:     []uint64{5, 6},
+			topic:    "channels.>",
+			start:    6,
+			consumed: 1,
+			last:     6,
+		},
+		{
+			desc:     "consume stream skipping malformed messages",
+			msgs:     []messaging.Message{malformed, msg},
+			seqs:     []uint64{1, 2},
+			topic:    "channels.>",
+			start:    1,
+			consumed: 1,
+			last:     2,
+		},
+		{
+			desc: "consume stream with unavailable checkpoint",
+			err:  errCheckpoint,
+		},
+	}
+
+	for i, tc := range cases {
+		path := filepath.Join(dir, fmt.Sprintf("config-%d.toml", i))
+		err := ioutil.WriteFile(path, []byte("[subscriber]\n"+tc.config), 0644)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		sub := &streamSubscriberMock{}
+		consumer := &checkpointConsumerMock{seq: tc.seq, err: tc.err}
+		err = consumers.StartStream(sub, consumer, "writer", path, logger)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+
+		assert.Equal(t, tc.topic, sub.topic, fmt.Sprintf("%s: expected topic %s got %s", tc.desc, tc.topic, sub.topic))
+		assert.Equal(t, tc.start, sub.seq, fmt.Sprintf("%s: expected start %d got %d", tc.desc, tc.start, sub.seq))
+
+		for j, m := range tc.msgs {
+			err := sub.handler(m, tc.seqs[j])
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		}
+		assert.Equal(t, tc.consumed, len(consumer.consumed), fmt.Sprintf("%s: expected %d consumed got %d", tc.desc, tc.consumed, len(consumer.consumed)))
+		assert.Equal(t, tc.last, consumer.seq, fmt.Sprintf("%s: expected checkpoint %d got %d", tc.desc, tc.last, consumer.seq))
+	}
+}
@@ -17,36 +17,43 @@ import (
 	pubsub "github.com/mainflux/mainflux/pkg/messaging/nats"
 	"github.com/mainflux/mainflux/pkg/transformers"
 	"github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/lwm2m"
+	"github.com/mainflux/mainflux/pkg/transformers/redaction"
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
 )
 
 const (
-	defContentType = "application/senml+json"
-	defFormat      = "senml"
+	defContentType  = "application/senml+json"
+	defFormat       = "senml"
+	jsonContentType = "application/json"
 )
 
 var (
+	defSubjects = []string{pubsub.SubjectAllChannels}
+
 	errOpenConfFile  = errors.New("unable to open configuration file")
 	errParseConfFile = errors.New("unable to parse configuration file")
+	errUnknownFormat = errors.New("unknown transformer format")
 )
 
 // Start method starts consuming messages received from NATS.
-// This method transforms messages to SenML format before
-// using MessageRepository to store them.
-func Start(sub messaging.Subscriber, consumer Consumer, configPath string, logger logger.Logger) error {
+// This method transforms messages using the transformer registered
+// for the message content type before using MessageRepository to
+// store them. Messages without known content type are transformed
+// using the configured transformer. Transformed messages are redacted
+// using the configured redaction rules. Besides the configured subjects,
+// consumer subscribes to the configured channels and to the channels
+// matching the configured patterns, resolved using the channel source.
+// The channel source may be nil if no patterns are configured.
+func Start(sub messaging.Subscriber, consumer Consumer, src ChannelSource, configPath string, logger logger.Logger) error {
 	cfg, err := loadConfig(configPath)
 	if err != nil {
 		logger.Warn(fmt.Sprintf("Failed to load consumer config: %s", err))
 	}
 
-	transformer := makeTransformer(cfg.TransformerCfg, logger)
+	transformer := makeTransformer(cfg, logger)
 
-	for _, subject := range cfg.SubscriberCfg.Subjects {
-		if err := sub.Subscribe(subject, handler(transformer, consumer)); err != nil {
-			return err
-		}
-	}
-	return nil
+	return subscribe(sub, handler(transformer, consumer), src, cfg.SubscriberCfg, logger)
 }
 
 func handler(t transformers.Transformer, c Consumer) messaging.MessageHandler {
@@ -65,24 +72,29 @@ func handler(t transformers.Transformer, c Consumer) messaging.MessageHandler {
 
 type subscriberConfig struct {
 	Subjects []string `toml:"subjects"`
+	Channels []string `toml:"channels"`
+	Patterns []string `toml:"patterns"`
+	Exclude  []string `toml:"exclude"`
+	// Partition is the subject partition the consumer joins instead of
+	// subscribing to all the channels, if the messages are partitioned.
+	Partition *int `toml:"partition"`
 }
 
 type transformerConfig struct {
-	Format      string           `toml:"format"`
-	ContentType string           `toml:"content_type"`
-	TimeFields  []json.TimeField `toml:"time_fields"`
+	Format      string            `toml:"format"`
+	ContentType string            `toml:"content_type"`
+	TimeFields  []json.TimeField  `toml:"time_fields"`
+	Codecs      map[string]string `toml:"codecs"`
 }
 
 type config struct {
 	SubscriberCfg  subscriberConfig  `toml:"subscriber"`
 	TransformerCfg transformerConfig `toml:"transformer"`
+	RedactionCfg   redaction.Config  `toml:"redaction"`
 }
 
 func loadConfig(configPath string) (config, error) {
 	cfg := config{
-		SubscriberCfg: subscriberConfig{
-			Subjects: []string{pubsub.SubjectAllChannels},
-		},
 		TransformerCfg: transformerConfig{
 			Format:      defFormat,
 			ContentType: defContentType,
@@ -101,17 +113,52 @@ func loadConfig(configPath string) (config, error) {
 	return cfg, nil
 }
 
-func makeTransformer(cfg transformerConfig, logger logger.Logger) transformers.Transformer {
-	switch strings.ToUpper(cfg.Format) {
+func makeTransformer(c config, logger logger.Logger) transformers.Transformer {
+	cfg := c.TransformerCfg
+	def, err := newTransformer(cfg.Format, cfg.ContentType, cfg.TimeFields)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Can't create transformer: %s %s", err, cfg.Format))
+		os.Exit(1)
+	}
+	logger.Info(fmt.Sprintf("Using %s transformer", cfg.Format))
+
+	registry := transformers.NewRegistry(def)
+	registry.Register(senml.JSON, senml.New(senml.JSON))
+	registry.Register(senml.CBOR, senml.New(senml.CBOR))
+	registry.Register(jsonContentType, json.New(cfg.TimeFields))
+	registry.Register(lwm2m.TLVContentType, lwm2m.New())
+
+	for contentType, format := range cfg.Codecs {
+		t, err := newTransformer(format, contentType, cfg.TimeFields)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Can't create transformer for %s: %s %s", contentType, err, format))
+			os.Exit(1)
+		}
+		registry.Register(contentType, t)
+	}
+
+	if len(c.RedactionCfg.Rules) == 0 {
+		return registry
+	}
+
+	t, err := redaction.New(registry, c.RedactionCfg, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Can't create redaction transformer: %s", err))
+		os.Exit(1)
+	}
+
+	return t
+}
+
+func newTransformer(format, contentType string, tfs []json.TimeField) (transformers.Transformer, error) {
+	switch strings.ToUpper(format) {
 	case "SENML":
-		logger.Info("Using SenML transformer")
-		return senml.New(cfg.ContentType)
+		return senml.New(contentType), nil
 	case "JSON":
-		logger.Info("Using JSON transformer")
-		return json.New(cfg.TimeFields)
+		return json.New(tfs), nil
+	case "LWM2M":
+		return lwm2m.New(), nil
 	default:
-		logger.Error(fmt.Sprintf("Can't create transformer: unknown transformer type %s", cfg.Format))
-		os.Exit(1)
-		return nil
+		return nil, errUnknownFormat
 	}
 }
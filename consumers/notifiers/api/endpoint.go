@@ -5,6 +5,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
@@ -98,3 +99,166 @@ func deleteSubscriptionEndpint(svc notifiers.Service) endpoint.Endpoint {
 		return removeSubRes{}, nil
 	}
 }
+
+func createPolicyEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createPolicyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p := notifiers.EscalationPolicy{
+			Name:  req.Name,
+			Topic: req.Topic,
+		}
+		for _, s := range req.Steps {
+			step := notifiers.EscalationStep{
+				Channel:  s.Channel,
+				Contacts: s.Contacts,
+				OnCall:   s.OnCall,
+			}
+			if s.Delay != "" {
+				// The delay is validated with the request.
+				step.Delay, _ = time.ParseDuration(s.Delay)
+			}
+			p.Steps = append(p.Steps, step)
+		}
+		for _, s := range req.Calendar {
+			p.Calendar = append(p.Calendar, notifiers.Shift(s))
+		}
+
+		id, err := svc.CreateEscalationPolicy(ctx, req.token, p)
+		if err != nil {
+			return nil, err
+		}
+
+		return createPolicyRes{ID: id}, nil
+	}
+}
+
+func viewPolicyEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(policyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.ViewEscalationPolicy(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toPolicyRes(p), nil
+	}
+}
+
+func listPoliciesEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listPoliciesReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		policies, err := svc.ListEscalationPolicies(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listPoliciesRes{Policies: []viewPolicyRes{}}
+		for _, p := range policies {
+			res.Policies = append(res.Policies, toPolicyRes(p))
+		}
+
+		return res, nil
+	}
+}
+
+func removePolicyEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(policyReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveEscalationPolicy(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return removePolicyRes{}, nil
+	}
+}
+
+func listAlertsEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listAlertsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		alerts, err := svc.ListAlerts(ctx, req.token, req.pending)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listAlertsRes{Alerts: []alertRes{}}
+		for _, a := range alerts {
+			ar := alertRes{
+				ID:             a.ID,
+				PolicyID:       a.PolicyID,
+				Channel:        a.Message.Channel,
+				Subtopic:       a.Message.Subtopic,
+				Publisher:      a.Message.Publisher,
+				Step:           a.Step,
+				CreatedAt:      a.CreatedAt,
+				NotifiedAt:     a.NotifiedAt,
+				AcknowledgedBy: a.AcknowledgedBy,
+			}
+			if !a.EscalateAt.IsZero() {
+				ar.EscalateAt = &a.EscalateAt
+			}
+			if a.Acknowledged() {
+				ar.AcknowledgedAt = &a.AcknowledgedAt
+			}
+			res.Alerts = append(res.Alerts, ar)
+		}
+
+		return res, nil
+	}
+}
+
+func ackAlertEndpoint(svc notifiers.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(alertReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.AcknowledgeAlert(ctx, req.token, req.id); err != nil {
+			return nil, err
+		}
+
+		return ackAlertRes{}, nil
+	}
+}
+
+func toPolicyRes(p notifiers.EscalationPolicy) viewPolicyRes {
+	res := viewPolicyRes{
+		ID:      p.ID,
+		OwnerID: p.OwnerID,
+		Name:    p.Name,
+		Topic:   p.Topic,
+	}
+	for _, s := range p.Steps {
+		res.Steps = append(res.Steps, stepRes{
+			Channel:  s.Channel,
+			Contacts: s.Contacts,
+			OnCall:   s.OnCall,
+			Delay:    s.Delay.String(),
+		})
+	}
+	for _, s := range p.Calendar {
+		res.Calendar = append(res.Calendar, shiftRes(s))
+	}
+
+	return res
+}
@@ -69,7 +69,8 @@ func newService(tokens map[string]string) notifiers.Service {
 	idp := uuid.NewMock()
 	notif := mocks.NewNotifier()
 	from := "exampleFrom"
-	return notifiers.New(auth, repo, idp, notif, from)
+	channels := map[string]notifiers.Notifier{notifiers.EmailChannel: notif}
+	return notifiers.New(auth, repo, mocks.NewEscalationRepo(), idp, notif, channels, from)
 }
 
 func newServer(svc notifiers.Service) *httptest.Server {
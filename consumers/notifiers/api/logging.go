@@ -76,6 +76,97 @@ func (lm *loggingMiddleware) RemoveSubscription(ctx context.Context, token, id s
 	return lm.svc.RemoveSubscription(ctx, token, id)
 }
 
+func (lm *loggingMiddleware) CreateEscalationPolicy(ctx context.Context, token string, p notifiers.EscalationPolicy) (id string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_escalation_policy with the id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateEscalationPolicy(ctx, token, p)
+}
+
+func (lm *loggingMiddleware) ViewEscalationPolicy(ctx context.Context, token, id string) (p notifiers.EscalationPolicy, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method view_escalation_policy with the id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ViewEscalationPolicy(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListEscalationPolicies(ctx context.Context, token string) (ps []notifiers.EscalationPolicy, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_escalation_policies took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListEscalationPolicies(ctx, token)
+}
+
+func (lm *loggingMiddleware) RemoveEscalationPolicy(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_escalation_policy with the id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveEscalationPolicy(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) ListAlerts(ctx context.Context, token string, pending bool) (alerts []notifiers.Alert, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_alerts took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListAlerts(ctx, token, pending)
+}
+
+func (lm *loggingMiddleware) AcknowledgeAlert(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method acknowledge_alert with the id %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AcknowledgeAlert(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) Escalate(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method escalate took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Escalate(ctx)
+}
+
 func (lm *loggingMiddleware) Consume(msg interface{}) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method consume took %s to complete", time.Since(begin))
@@ -64,6 +64,69 @@ func (ms *metricsMiddleware) RemoveSubscription(ctx context.Context, token, id s
 	return ms.svc.RemoveSubscription(ctx, token, id)
 }
 
+func (ms *metricsMiddleware) CreateEscalationPolicy(ctx context.Context, token string, p notifiers.EscalationPolicy) (string, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_escalation_policy").Add(1)
+		ms.latency.With("method", "create_escalation_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateEscalationPolicy(ctx, token, p)
+}
+
+func (ms *metricsMiddleware) ViewEscalationPolicy(ctx context.Context, token, id string) (notifiers.EscalationPolicy, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "view_escalation_policy").Add(1)
+		ms.latency.With("method", "view_escalation_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ViewEscalationPolicy(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListEscalationPolicies(ctx context.Context, token string) ([]notifiers.EscalationPolicy, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_escalation_policies").Add(1)
+		ms.latency.With("method", "list_escalation_policies").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListEscalationPolicies(ctx, token)
+}
+
+func (ms *metricsMiddleware) RemoveEscalationPolicy(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_escalation_policy").Add(1)
+		ms.latency.With("method", "remove_escalation_policy").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveEscalationPolicy(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) ListAlerts(ctx context.Context, token string, pending bool) ([]notifiers.Alert, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_alerts").Add(1)
+		ms.latency.With("method", "list_alerts").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListAlerts(ctx, token, pending)
+}
+
+func (ms *metricsMiddleware) AcknowledgeAlert(ctx context.Context, token, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "acknowledge_alert").Add(1)
+		ms.latency.With("method", "acknowledge_alert").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AcknowledgeAlert(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) Escalate(ctx context.Context) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "escalate").Add(1)
+		ms.latency.With("method", "escalate").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Escalate(ctx)
+}
+
 func (ms *metricsMiddleware) Consume(msg interface{}) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "consume").Add(1)
@@ -4,6 +4,8 @@
 package api
 
 import (
+	"time"
+
 	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
 	"github.com/mainflux/mainflux/pkg/errors"
 )
@@ -12,6 +14,7 @@ var (
 	errInvalidTopic   = errors.New("invalid Subscription topic")
 	errInvalidContact = errors.New("invalid Subscription contact")
 	errNotFound       = errors.New("invalid or empty Subscription id")
+	errMissingID      = errors.New("missing escalation policy or alert id")
 )
 
 type createSubReq struct {
@@ -62,3 +65,106 @@ func (req listSubsReq) validate() error {
 	}
 	return nil
 }
+
+type stepReq struct {
+	Channel  string   `json:"channel"`
+	Contacts []string `json:"contacts,omitempty"`
+	OnCall   bool     `json:"on_call,omitempty"`
+	Delay    string   `json:"delay,omitempty"`
+}
+
+type shiftReq struct {
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Contacts map[string]string `json:"contacts"`
+}
+
+type createPolicyReq struct {
+	token    string
+	Name     string     `json:"name,omitempty"`
+	Topic    string     `json:"topic"`
+	Steps    []stepReq  `json:"steps"`
+	Calendar []shiftReq `json:"calendar,omitempty"`
+}
+
+func (req createPolicyReq) validate() error {
+	if req.token == "" {
+		return notifiers.ErrUnauthorizedAccess
+	}
+	if req.Topic == "" {
+		return errInvalidTopic
+	}
+	if len(req.Steps) == 0 {
+		return errors.ErrMalformedEntity
+	}
+	for _, s := range req.Steps {
+		if s.Channel == "" || (len(s.Contacts) == 0 && !s.OnCall) {
+			return errors.ErrMalformedEntity
+		}
+		if s.Delay == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(s.Delay); err != nil || d < 0 {
+			return errors.ErrMalformedEntity
+		}
+	}
+	for _, s := range req.Calendar {
+		if !s.Start.Before(s.End) || len(s.Contacts) == 0 {
+			return errors.ErrMalformedEntity
+		}
+	}
+	return nil
+}
+
+type policyReq struct {
+	token string
+	id    string
+}
+
+func (req policyReq) validate() error {
+	if req.token == "" {
+		return notifiers.ErrUnauthorizedAccess
+	}
+	if req.id == "" {
+		return errMissingID
+	}
+	return nil
+}
+
+type listPoliciesReq struct {
+	token string
+}
+
+func (req listPoliciesReq) validate() error {
+	if req.token == "" {
+		return notifiers.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+type listAlertsReq struct {
+	token   string
+	pending bool
+}
+
+func (req listAlertsReq) validate() error {
+	if req.token == "" {
+		return notifiers.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+type alertReq struct {
+	token string
+	id    string
+}
+
+func (req alertReq) validate() error {
+	if req.token == "" {
+		return notifiers.ErrUnauthorizedAccess
+	}
+	if req.id == "" {
+		return errMissingID
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/mainflux/mainflux"
 )
@@ -15,6 +16,12 @@ var (
 	_ mainflux.Response = (*viewSubRes)(nil)
 	_ mainflux.Response = (*listSubsRes)(nil)
 	_ mainflux.Response = (*removeSubRes)(nil)
+	_ mainflux.Response = (*createPolicyRes)(nil)
+	_ mainflux.Response = (*viewPolicyRes)(nil)
+	_ mainflux.Response = (*listPoliciesRes)(nil)
+	_ mainflux.Response = (*removePolicyRes)(nil)
+	_ mainflux.Response = (*listAlertsRes)(nil)
+	_ mainflux.Response = (*ackAlertRes)(nil)
 )
 
 type createSubRes struct {
@@ -88,6 +95,132 @@ func (res removeSubRes) Empty() bool {
 	return true
 }
 
+type createPolicyRes struct {
+	ID string
+}
+
+func (res createPolicyRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res createPolicyRes) Headers() map[string]string {
+	return map[string]string{
+		"Location": fmt.Sprintf("/escalations/%s", res.ID),
+	}
+}
+
+func (res createPolicyRes) Empty() bool {
+	return true
+}
+
+type stepRes struct {
+	Channel  string   `json:"channel"`
+	Contacts []string `json:"contacts,omitempty"`
+	OnCall   bool     `json:"on_call,omitempty"`
+	Delay    string   `json:"delay"`
+}
+
+type shiftRes struct {
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Contacts map[string]string `json:"contacts"`
+}
+
+type viewPolicyRes struct {
+	ID       string     `json:"id"`
+	OwnerID  string     `json:"owner_id"`
+	Name     string     `json:"name,omitempty"`
+	Topic    string     `json:"topic"`
+	Steps    []stepRes  `json:"steps"`
+	Calendar []shiftRes `json:"calendar,omitempty"`
+}
+
+func (res viewPolicyRes) Code() int {
+	return http.StatusOK
+}
+
+func (res viewPolicyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res viewPolicyRes) Empty() bool {
+	return false
+}
+
+type listPoliciesRes struct {
+	Policies []viewPolicyRes `json:"escalations"`
+}
+
+func (res listPoliciesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listPoliciesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listPoliciesRes) Empty() bool {
+	return false
+}
+
+type removePolicyRes struct{}
+
+func (res removePolicyRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res removePolicyRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res removePolicyRes) Empty() bool {
+	return true
+}
+
+type alertRes struct {
+	ID             string     `json:"id"`
+	PolicyID       string     `json:"escalation_id"`
+	Channel        string     `json:"channel"`
+	Subtopic       string     `json:"subtopic,omitempty"`
+	Publisher      string     `json:"publisher"`
+	Step           int        `json:"step"`
+	CreatedAt      time.Time  `json:"created_at"`
+	NotifiedAt     time.Time  `json:"notified_at"`
+	EscalateAt     *time.Time `json:"escalate_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string     `json:"acknowledged_by,omitempty"`
+}
+
+type listAlertsRes struct {
+	Alerts []alertRes `json:"alerts"`
+}
+
+func (res listAlertsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listAlertsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listAlertsRes) Empty() bool {
+	return false
+}
+
+type ackAlertRes struct{}
+
+func (res ackAlertRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res ackAlertRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res ackAlertRes) Empty() bool {
+	return true
+}
+
 type errorRes struct {
 	Err string `json:"error"`
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/mainflux/mainflux"
 	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
 	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
 	"github.com/mainflux/mainflux/pkg/errors"
 	opentracing "github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -27,6 +28,8 @@ const contentType = "application/json"
 func MakeHandler(svc notifiers.Service, tracer opentracing.Tracer) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
 	}
 
 	mux := bone.New()
@@ -59,6 +62,48 @@ func MakeHandler(svc notifiers.Service, tracer opentracing.Tracer) http.Handler
 		opts...,
 	))
 
+	mux.Post("/escalations", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_escalation_policy")(createPolicyEndpoint(svc)),
+		decodeCreatePolicy,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/escalations/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_escalation_policy")(viewPolicyEndpoint(svc)),
+		decodePolicy,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/escalations", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_escalation_policies")(listPoliciesEndpoint(svc)),
+		decodeListPolicies,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/escalations/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_escalation_policy")(removePolicyEndpoint(svc)),
+		decodePolicy,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/alerts", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_alerts")(listAlertsEndpoint(svc)),
+		decodeListAlerts,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/alerts/:id/ack", kithttp.NewServer(
+		kitot.TraceServer(tracer, "acknowledge_alert")(ackAlertEndpoint(svc)),
+		decodeAlert,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.GetFunc("/version", mainflux.Version("notifier"))
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -91,15 +136,17 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 	req := listSubsReq{
 		token: r.Header.Get("Authorization"),
 	}
-	vals := bone.GetQuery(r, "topic")
-	if len(vals) > 0 {
-		req.topic = vals[0]
+	topic, err := httputil.ReadStringQuery(r, "topic", "")
+	if err != nil {
+		return listSubsReq{}, err
 	}
+	req.topic = topic
 
-	vals = bone.GetQuery(r, "contact")
-	if len(vals) > 0 {
-		req.contact = vals[0]
+	contact, err := httputil.ReadStringQuery(r, "contact", "")
+	if err != nil {
+		return listSubsReq{}, err
 	}
+	req.contact = contact
 
 	offset, err := httputil.ReadUintQuery(r, "offset", 0)
 	if err != nil {
@@ -116,6 +163,59 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
+func decodeCreatePolicy(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+	var req createPolicyReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	req.token = r.Header.Get("Authorization")
+	return req, nil
+}
+
+func decodePolicy(_ context.Context, r *http.Request) (interface{}, error) {
+	req := policyReq{
+		id:    bone.GetValue(r, "id"),
+		token: r.Header.Get("Authorization"),
+	}
+
+	return req, nil
+}
+
+func decodeListPolicies(_ context.Context, r *http.Request) (interface{}, error) {
+	req := listPoliciesReq{
+		token: r.Header.Get("Authorization"),
+	}
+
+	return req, nil
+}
+
+func decodeListAlerts(_ context.Context, r *http.Request) (interface{}, error) {
+	pending, err := httputil.ReadBoolQuery(r, "pending", false)
+	if err != nil {
+		return listAlertsReq{}, err
+	}
+
+	req := listAlertsReq{
+		token:   r.Header.Get("Authorization"),
+		pending: pending,
+	}
+
+	return req, nil
+}
+
+func decodeAlert(_ context.Context, r *http.Request) (interface{}, error) {
+	req := alertReq{
+		id:    bone.GetValue(r, "id"),
+		token: r.Header.Get("Authorization"),
+	}
+
+	return req, nil
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	if ar, ok := response.(mainflux.Response); ok {
 		for k, v := range ar.Headers() {
@@ -132,7 +232,7 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	switch errorVal := err.(type) {
 	case errors.Error:
 		w.Header().Set("Content-Type", contentType)
@@ -140,10 +240,12 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		case errors.Contains(errorVal, errors.ErrMalformedEntity),
 			errors.Contains(errorVal, errInvalidContact),
 			errors.Contains(errorVal, errInvalidTopic),
+			errors.Contains(errorVal, notifiers.ErrUnsupportedChannel),
 			errors.Contains(errorVal, errors.ErrInvalidQueryParams):
 			w.WriteHeader(http.StatusBadRequest)
 		case errors.Contains(errorVal, notifiers.ErrNotFound),
-			errors.Contains(errorVal, errNotFound):
+			errors.Contains(errorVal, errNotFound),
+			errors.Contains(errorVal, errMissingID):
 			w.WriteHeader(http.StatusNotFound)
 		case errors.Contains(errorVal, notifiers.ErrUnauthorizedAccess):
 			w.WriteHeader(http.StatusUnauthorized)
@@ -159,7 +261,7 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 		if errorVal.Msg() != "" {
-			if err := json.NewEncoder(w).Encode(errorRes{Err: errorVal.Msg()}); err != nil {
+			if err := json.NewEncoder(w).Encode(errorRes{Err: i18n.Localize(ctx, errorVal.Msg())}); err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
 			}
 		}
@@ -0,0 +1,132 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package notifiers
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+// Channels the escalation steps notify the contacts over.
+const (
+	EmailChannel   = "email"
+	SMSChannel     = "sms"
+	WebhookChannel = "webhook"
+)
+
+// EscalationStep represents the single step of the escalation chain.
+type EscalationStep struct {
+	// Channel is the channel the step notifies the contacts over.
+	Channel string
+
+	// Contacts are notified by the step, in the format of the channel,
+	// e.g. the email addresses, the phone numbers or the webhook URLs.
+	Contacts []string
+
+	// OnCall notifies the contacts on call at the time of the step,
+	// according to the calendar of the policy, besides the Contacts.
+	OnCall bool
+
+	// Delay is the time the alert has to stay unacknowledged after the
+	// previous step for the step to notify its contacts. The delay of the
+	// first step is ignored, as it's notified when the alert is raised.
+	Delay time.Duration
+}
+
+// Shift represents the on-call calendar entry.
+type Shift struct {
+	Start time.Time
+	End   time.Time
+
+	// Contacts holds the contacts on call, mapped by the channel.
+	Contacts map[string]string
+}
+
+// EscalationPolicy represents the chain of the contacts notified until
+// the alerts raised by the messages published to the topic are
+// acknowledged.
+type EscalationPolicy struct {
+	ID       string
+	OwnerID  string
+	Name     string
+	Topic    string
+	Steps    []EscalationStep
+	Calendar []Shift
+}
+
+// onCall returns the contacts on call for the channel at the given time.
+func (p EscalationPolicy) onCall(channel string, t time.Time) []string {
+	var contacts []string
+	for _, s := range p.Calendar {
+		if t.Before(s.Start) || !t.Before(s.End) {
+			continue
+		}
+		if c, ok := s.Contacts[channel]; ok {
+			contacts = append(contacts, c)
+		}
+	}
+	return contacts
+}
+
+// Alert represents the message escalated by the escalation policy.
+type Alert struct {
+	ID       string
+	PolicyID string
+	OwnerID  string
+	Message  messaging.Message
+
+	// Step is the index of the last notified step of the policy.
+	Step       int
+	CreatedAt  time.Time
+	NotifiedAt time.Time
+
+	// EscalateAt is the time the next step is due, zero if the chain is
+	// exhausted.
+	EscalateAt     time.Time
+	AcknowledgedAt time.Time
+	AcknowledgedBy string
+}
+
+// Acknowledged reports whether the alert is acknowledged.
+func (a Alert) Acknowledged() bool {
+	return !a.AcknowledgedAt.IsZero()
+}
+
+// EscalationRepository specifies escalation policies and alerts
+// persistence API.
+type EscalationRepository interface {
+	// SavePolicy persists the escalation policy.
+	SavePolicy(ctx context.Context, p EscalationPolicy) (string, error)
+
+	// RetrievePolicy retrieves the escalation policy for the given id.
+	RetrievePolicy(ctx context.Context, id string) (EscalationPolicy, error)
+
+	// RetrievePolicies retrieves the escalation policies of the owner, or
+	// the policies of the topic if the owner is empty.
+	RetrievePolicies(ctx context.Context, ownerID, topic string) ([]EscalationPolicy, error)
+
+	// RemovePolicy removes the escalation policy of the owner, alongside
+	// its alerts.
+	RemovePolicy(ctx context.Context, ownerID, id string) error
+
+	// SaveAlert persists the alert.
+	SaveAlert(ctx context.Context, a Alert) error
+
+	// UpdateAlert updates the escalation and the acknowledgement of the
+	// alert.
+	UpdateAlert(ctx context.Context, a Alert) error
+
+	// RetrieveAlert retrieves the alert for the given id.
+	RetrieveAlert(ctx context.Context, id string) (Alert, error)
+
+	// RetrieveAlerts retrieves the alerts of the owner, newest first.
+	// Only the unacknowledged alerts are retrieved if pending is set.
+	RetrieveAlerts(ctx context.Context, ownerID string, pending bool) ([]Alert, error)
+
+	// RetrieveDueAlerts retrieves the unacknowledged alerts whose next
+	// step is due at the given time.
+	RetrieveDueAlerts(ctx context.Context, t time.Time) ([]Alert, error)
+}
@@ -63,3 +63,23 @@ func (svc authServiceMock) Members(ctx context.Context, req *mainflux.MembersReq
 func (svc authServiceMock) Assign(ctx context.Context, req *mainflux.Assignment, _ ...grpc.CallOption) (r *empty.Empty, err error) {
 	panic("not implemented")
 }
+
+func (svc authServiceMock) ReserveQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) ReleaseQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) RevokeKeys(ctx context.Context, req *mainflux.RevokeKeysReq, _ ...grpc.CallOption) (*mainflux.RevokeKeysRes, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) ApplyPolicyTemplate(ctx context.Context, req *mainflux.PolicyTemplateReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) RemoveLinks(ctx context.Context, req *mainflux.RemoveLinksReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+)
+
+var _ notifiers.EscalationRepository = (*escalationRepoMock)(nil)
+
+type escalationRepoMock struct {
+	mu       sync.Mutex
+	policies map[string]notifiers.EscalationPolicy
+	alerts   map[string]notifiers.Alert
+}
+
+// NewEscalationRepo returns a new escalation repository mock.
+func NewEscalationRepo() notifiers.EscalationRepository {
+	return &escalationRepoMock{
+		policies: make(map[string]notifiers.EscalationPolicy),
+		alerts:   make(map[string]notifiers.Alert),
+	}
+}
+
+func (erm *escalationRepoMock) SavePolicy(_ context.Context, p notifiers.EscalationPolicy) (string, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	if _, ok := erm.policies[p.ID]; ok {
+		return "", notifiers.ErrConflict
+	}
+
+	erm.policies[p.ID] = p
+	return p.ID, nil
+}
+
+func (erm *escalationRepoMock) RetrievePolicy(_ context.Context, id string) (notifiers.EscalationPolicy, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	p, ok := erm.policies[id]
+	if !ok {
+		return notifiers.EscalationPolicy{}, notifiers.ErrNotFound
+	}
+	return p, nil
+}
+
+func (erm *escalationRepoMock) RetrievePolicies(_ context.Context, ownerID, topic string) ([]notifiers.EscalationPolicy, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	var policies []notifiers.EscalationPolicy
+	for _, p := range erm.policies {
+		if (ownerID == "" || p.OwnerID == ownerID) && (topic == "" || p.Topic == topic) {
+			policies = append(policies, p)
+		}
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].ID < policies[j].ID })
+
+	return policies, nil
+}
+
+func (erm *escalationRepoMock) RemovePolicy(_ context.Context, ownerID, id string) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	if p, ok := erm.policies[id]; !ok || p.OwnerID != ownerID {
+		return nil
+	}
+
+	delete(erm.policies, id)
+	for aid, a := range erm.alerts {
+		if a.PolicyID == id {
+			delete(erm.alerts, aid)
+		}
+	}
+	return nil
+}
+
+func (erm *escalationRepoMock) SaveAlert(_ context.Context, a notifiers.Alert) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	if _, ok := erm.alerts[a.ID]; ok {
+		return notifiers.ErrConflict
+	}
+
+	erm.alerts[a.ID] = a
+	return nil
+}
+
+func (erm *escalationRepoMock) UpdateAlert(_ context.Context, a notifiers.Alert) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	if _, ok := erm.alerts[a.ID]; !ok {
+		return notifiers.ErrNotFound
+	}
+
+	erm.alerts[a.ID] = a
+	return nil
+}
+
+func (erm *escalationRepoMock) RetrieveAlert(_ context.Context, id string) (notifiers.Alert, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	a, ok := erm.alerts[id]
+	if !ok {
+		return notifiers.Alert{}, notifiers.ErrNotFound
+	}
+	return a, nil
+}
+
+func (erm *escalationRepoMock) RetrieveAlerts(_ context.Context, ownerID string, pending bool) ([]notifiers.Alert, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	var alerts []notifiers.Alert
+	for _, a := range erm.alerts {
+		if a.OwnerID == ownerID && !(pending && a.Acknowledged()) {
+			alerts = append(alerts, a)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].CreatedAt.After(alerts[j].CreatedAt) })
+
+	return alerts, nil
+}
+
+func (erm *escalationRepoMock) RetrieveDueAlerts(_ context.Context, t time.Time) ([]notifiers.Alert, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	var alerts []notifiers.Alert
+	for _, a := range erm.alerts {
+		if !a.Acknowledged() && !a.EscalateAt.IsZero() && !a.EscalateAt.After(t) {
+			alerts = append(alerts, a)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].ID < alerts[j].ID })
+
+	return alerts, nil
+}
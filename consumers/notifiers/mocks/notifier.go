@@ -4,6 +4,8 @@
 package mocks
 
 import (
+	"sync"
+
 	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
 	"github.com/mainflux/mainflux/pkg/messaging"
 )
@@ -27,3 +29,35 @@ func (n notifier) Notify(from string, to []string, msg messaging.Message) error
 	}
 	return nil
 }
+
+var _ notifiers.Notifier = (*Recorder)(nil)
+
+// Recorder is the Notifier mock recording the notified contacts.
+type Recorder struct {
+	mu       sync.Mutex
+	contacts []string
+}
+
+// NewRecorder returns a new Notifier mock recording the notified contacts.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Notify records the contacts, failing like the Notifier mock.
+func (r *Recorder) Notify(from string, to []string, msg messaging.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.contacts = append(r.contacts, to...)
+	return notifier{}.Notify(from, to, msg)
+}
+
+// Flush returns the contacts notified since the last flush.
+func (r *Recorder) Flush() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	contacts := r.contacts
+	r.contacts = nil
+	return contacts
+}
@@ -0,0 +1,354 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/lib/pq"
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var _ notifiers.EscalationRepository = (*escalationRepo)(nil)
+
+type escalationRepo struct {
+	db Database
+}
+
+// NewEscalationRepo instantiates a PostgreSQL implementation of escalation
+// repository.
+func NewEscalationRepo(db Database) notifiers.EscalationRepository {
+	return &escalationRepo{
+		db: db,
+	}
+}
+
+func (repo escalationRepo) SavePolicy(ctx context.Context, p notifiers.EscalationPolicy) (string, error) {
+	q := `INSERT INTO escalation_policies (id, owner_id, name, topic, steps, calendar)
+	      VALUES (:id, :owner_id, :name, :topic, :steps, :calendar)`
+
+	dbp, err := toDBPolicy(p)
+	if err != nil {
+		return "", errors.Wrap(notifiers.ErrSave, err)
+	}
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbp); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return "", errors.Wrap(notifiers.ErrConflict, err)
+		}
+		return "", errors.Wrap(notifiers.ErrSave, err)
+	}
+
+	return p.ID, nil
+}
+
+func (repo escalationRepo) RetrievePolicy(ctx context.Context, id string) (notifiers.EscalationPolicy, error) {
+	q := `SELECT id, owner_id, name, topic, steps, calendar FROM escalation_policies WHERE id = $1`
+
+	dbp := dbPolicy{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dbp); err != nil {
+		if err == sql.ErrNoRows {
+			return notifiers.EscalationPolicy{}, errors.Wrap(notifiers.ErrNotFound, err)
+		}
+		return notifiers.EscalationPolicy{}, errors.Wrap(notifiers.ErrSelectEntity, err)
+	}
+
+	return toPolicy(dbp)
+}
+
+func (repo escalationRepo) RetrievePolicies(ctx context.Context, ownerID, topic string) ([]notifiers.EscalationPolicy, error) {
+	q := `SELECT id, owner_id, name, topic, steps, calendar FROM escalation_policies
+	      WHERE (:owner_id = '' OR owner_id = :owner_id) AND (:topic = '' OR topic = :topic) ORDER BY id`
+
+	params := map[string]interface{}{
+		"owner_id": ownerID,
+		"topic":    topic,
+	}
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(notifiers.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var policies []notifiers.EscalationPolicy
+	for rows.Next() {
+		dbp := dbPolicy{}
+		if err := rows.StructScan(&dbp); err != nil {
+			return nil, errors.Wrap(notifiers.ErrSelectEntity, err)
+		}
+		p, err := toPolicy(dbp)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, p)
+	}
+
+	return policies, nil
+}
+
+func (repo escalationRepo) RemovePolicy(ctx context.Context, ownerID, id string) error {
+	q := `DELETE FROM escalation_policies WHERE id = :id AND owner_id = :owner_id`
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dbPolicy{ID: id, OwnerID: ownerID}); err != nil {
+		return errors.Wrap(notifiers.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+func (repo escalationRepo) SaveAlert(ctx context.Context, a notifiers.Alert) error {
+	q := `INSERT INTO alerts (id, policy_id, owner_id, message, step, created_at, notified_at, escalate_at, acknowledged_at, acknowledged_by)
+	      VALUES (:id, :policy_id, :owner_id, :message, :step, :created_at, :notified_at, :escalate_at, :acknowledged_at, :acknowledged_by)`
+
+	dba, err := toDBAlert(a)
+	if err != nil {
+		return errors.Wrap(notifiers.ErrSave, err)
+	}
+
+	if _, err := repo.db.NamedExecContext(ctx, q, dba); err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == errDuplicate {
+			return errors.Wrap(notifiers.ErrConflict, err)
+		}
+		return errors.Wrap(notifiers.ErrSave, err)
+	}
+
+	return nil
+}
+
+func (repo escalationRepo) UpdateAlert(ctx context.Context, a notifiers.Alert) error {
+	q := `UPDATE alerts SET step = :step, notified_at = :notified_at, escalate_at = :escalate_at,
+	      acknowledged_at = :acknowledged_at, acknowledged_by = :acknowledged_by WHERE id = :id`
+
+	dba, err := toDBAlert(a)
+	if err != nil {
+		return errors.Wrap(notifiers.ErrSave, err)
+	}
+
+	res, err := repo.db.NamedExecContext(ctx, q, dba)
+	if err != nil {
+		return errors.Wrap(notifiers.ErrSave, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(notifiers.ErrSave, err)
+	}
+	if cnt == 0 {
+		return notifiers.ErrNotFound
+	}
+
+	return nil
+}
+
+func (repo escalationRepo) RetrieveAlert(ctx context.Context, id string) (notifiers.Alert, error) {
+	q := `SELECT id, policy_id, owner_id, message, step, created_at, notified_at, escalate_at, acknowledged_at, acknowledged_by
+	      FROM alerts WHERE id = $1`
+
+	dba := dbAlert{}
+	if err := repo.db.QueryRowxContext(ctx, q, id).StructScan(&dba); err != nil {
+		if err == sql.ErrNoRows {
+			return notifiers.Alert{}, errors.Wrap(notifiers.ErrNotFound, err)
+		}
+		return notifiers.Alert{}, errors.Wrap(notifiers.ErrSelectEntity, err)
+	}
+
+	return toAlert(dba)
+}
+
+func (repo escalationRepo) RetrieveAlerts(ctx context.Context, ownerID string, pending bool) ([]notifiers.Alert, error) {
+	q := `SELECT id, policy_id, owner_id, message, step, created_at, notified_at, escalate_at, acknowledged_at, acknowledged_by
+	      FROM alerts WHERE owner_id = :owner_id AND (NOT :pending OR acknowledged_at IS NULL) ORDER BY created_at DESC`
+
+	params := map[string]interface{}{
+		"owner_id": ownerID,
+		"pending":  pending,
+	}
+
+	return repo.retrieveAlerts(ctx, q, params)
+}
+
+func (repo escalationRepo) RetrieveDueAlerts(ctx context.Context, t time.Time) ([]notifiers.Alert, error) {
+	q := `SELECT id, policy_id, owner_id, message, step, created_at, notified_at, escalate_at, acknowledged_at, acknowledged_by
+	      FROM alerts WHERE acknowledged_at IS NULL AND escalate_at <= :time ORDER BY escalate_at`
+
+	params := map[string]interface{}{
+		"time": t,
+	}
+
+	return repo.retrieveAlerts(ctx, q, params)
+}
+
+func (repo escalationRepo) retrieveAlerts(ctx context.Context, q string, params interface{}) ([]notifiers.Alert, error) {
+	rows, err := repo.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return nil, errors.Wrap(notifiers.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	var alerts []notifiers.Alert
+	for rows.Next() {
+		dba := dbAlert{}
+		if err := rows.StructScan(&dba); err != nil {
+			return nil, errors.Wrap(notifiers.ErrSelectEntity, err)
+		}
+		a, err := toAlert(dba)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, nil
+}
+
+type dbStep struct {
+	Channel  string   `json:"channel"`
+	Contacts []string `json:"contacts"`
+	OnCall   bool     `json:"on_call"`
+	Delay    int64    `json:"delay"`
+}
+
+type dbShift struct {
+	Start    time.Time         `json:"start"`
+	End      time.Time         `json:"end"`
+	Contacts map[string]string `json:"contacts"`
+}
+
+type dbPolicy struct {
+	ID       string `db:"id"`
+	OwnerID  string `db:"owner_id"`
+	Name     string `db:"name"`
+	Topic    string `db:"topic"`
+	Steps    []byte `db:"steps"`
+	Calendar []byte `db:"calendar"`
+}
+
+func toDBPolicy(p notifiers.EscalationPolicy) (dbPolicy, error) {
+	steps := []dbStep{}
+	for _, s := range p.Steps {
+		steps = append(steps, dbStep{
+			Channel:  s.Channel,
+			Contacts: s.Contacts,
+			OnCall:   s.OnCall,
+			Delay:    int64(s.Delay),
+		})
+	}
+	calendar := []dbShift{}
+	for _, s := range p.Calendar {
+		calendar = append(calendar, dbShift(s))
+	}
+
+	st, err := json.Marshal(steps)
+	if err != nil {
+		return dbPolicy{}, err
+	}
+	cal, err := json.Marshal(calendar)
+	if err != nil {
+		return dbPolicy{}, err
+	}
+
+	return dbPolicy{
+		ID:       p.ID,
+		OwnerID:  p.OwnerID,
+		Name:     p.Name,
+		Topic:    p.Topic,
+		Steps:    st,
+		Calendar: cal,
+	}, nil
+}
+
+func toPolicy(dbp dbPolicy) (notifiers.EscalationPolicy, error) {
+	var steps []dbStep
+	if err := json.Unmarshal(dbp.Steps, &steps); err != nil {
+		return notifiers.EscalationPolicy{}, errors.Wrap(notifiers.ErrSelectEntity, err)
+	}
+	var calendar []dbShift
+	if err := json.Unmarshal(dbp.Calendar, &calendar); err != nil {
+		return notifiers.EscalationPolicy{}, errors.Wrap(notifiers.ErrSelectEntity, err)
+	}
+
+	p := notifiers.EscalationPolicy{
+		ID:      dbp.ID,
+		OwnerID: dbp.OwnerID,
+		Name:    dbp.Name,
+		Topic:   dbp.Topic,
+	}
+	for _, s := range steps {
+		p.Steps = append(p.Steps, notifiers.EscalationStep{
+			Channel:  s.Channel,
+			Contacts: s.Contacts,
+			OnCall:   s.OnCall,
+			Delay:    time.Duration(s.Delay),
+		})
+	}
+	for _, s := range calendar {
+		p.Calendar = append(p.Calendar, notifiers.Shift(s))
+	}
+
+	return p, nil
+}
+
+type dbAlert struct {
+	ID             string         `db:"id"`
+	PolicyID       string         `db:"policy_id"`
+	OwnerID        string         `db:"owner_id"`
+	Message        []byte         `db:"message"`
+	Step           int            `db:"step"`
+	CreatedAt      time.Time      `db:"created_at"`
+	NotifiedAt     time.Time      `db:"notified_at"`
+	EscalateAt     sql.NullTime   `db:"escalate_at"`
+	AcknowledgedAt sql.NullTime   `db:"acknowledged_at"`
+	AcknowledgedBy sql.NullString `db:"acknowledged_by"`
+}
+
+func toDBAlert(a notifiers.Alert) (dbAlert, error) {
+	msg, err := proto.Marshal(&a.Message)
+	if err != nil {
+		return dbAlert{}, err
+	}
+
+	return dbAlert{
+		ID:             a.ID,
+		PolicyID:       a.PolicyID,
+		OwnerID:        a.OwnerID,
+		Message:        msg,
+		Step:           a.Step,
+		CreatedAt:      a.CreatedAt,
+		NotifiedAt:     a.NotifiedAt,
+		EscalateAt:     sql.NullTime{Time: a.EscalateAt, Valid: !a.EscalateAt.IsZero()},
+		AcknowledgedAt: sql.NullTime{Time: a.AcknowledgedAt, Valid: !a.AcknowledgedAt.IsZero()},
+		AcknowledgedBy: sql.NullString{String: a.AcknowledgedBy, Valid: a.AcknowledgedBy != ""},
+	}, nil
+}
+
+func toAlert(dba dbAlert) (notifiers.Alert, error) {
+	var msg messaging.Message
+	if err := proto.Unmarshal(dba.Message, &msg); err != nil {
+		return notifiers.Alert{}, errors.Wrap(notifiers.ErrSelectEntity, err)
+	}
+
+	a := notifiers.Alert{
+		ID:             dba.ID,
+		PolicyID:       dba.PolicyID,
+		OwnerID:        dba.OwnerID,
+		Message:        msg,
+		Step:           dba.Step,
+		CreatedAt:      dba.CreatedAt.UTC(),
+		NotifiedAt:     dba.NotifiedAt.UTC(),
+		AcknowledgedBy: dba.AcknowledgedBy.String,
+	}
+	if dba.EscalateAt.Valid {
+		a.EscalateAt = dba.EscalateAt.Time.UTC()
+	}
+	if dba.AcknowledgedAt.Valid {
+		a.AcknowledgedAt = dba.AcknowledgedAt.Time.UTC()
+	}
+
+	return a, nil
+}
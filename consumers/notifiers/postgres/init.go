@@ -60,6 +60,37 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE IF EXISTS subscriptions",
 				},
 			},
+			{
+				Id: "subscriptions_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS escalation_policies (
+                        id          VARCHAR(254) PRIMARY KEY,
+                        owner_id    VARCHAR(254) NOT NULL,
+                        name        VARCHAR(254),
+                        topic       TEXT NOT NULL,
+                        steps       JSONB NOT NULL,
+                        calendar    JSONB NOT NULL
+                    )`,
+					`CREATE INDEX IF NOT EXISTS escalation_policies_topic_idx ON escalation_policies (topic)`,
+					`CREATE TABLE IF NOT EXISTS alerts (
+                        id              VARCHAR(254) PRIMARY KEY,
+                        policy_id       VARCHAR(254) NOT NULL REFERENCES escalation_policies (id) ON DELETE CASCADE,
+                        owner_id        VARCHAR(254) NOT NULL,
+                        message         BYTEA NOT NULL,
+                        step            INTEGER NOT NULL,
+                        created_at      TIMESTAMPTZ NOT NULL,
+                        notified_at     TIMESTAMPTZ NOT NULL,
+                        escalate_at     TIMESTAMPTZ,
+                        acknowledged_at TIMESTAMPTZ,
+                        acknowledged_by VARCHAR(254)
+                    )`,
+					`CREATE INDEX IF NOT EXISTS alerts_escalate_at_idx ON alerts (escalate_at) WHERE acknowledged_at IS NULL`,
+				},
+				Down: []string{
+					"DROP TABLE IF EXISTS alerts",
+					"DROP TABLE IF EXISTS escalation_policies",
+				},
+			},
 		},
 	}
 
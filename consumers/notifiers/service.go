@@ -6,6 +6,7 @@ package notifiers
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/consumers"
@@ -38,6 +39,10 @@ var (
 
 	// ErrMessage indicates an error converting a message to Mainflux message.
 	ErrMessage = errors.New("failed to convert to Mainflux message")
+
+	// ErrUnsupportedChannel indicates the escalation step over the channel
+	// the service has no notifier for.
+	ErrUnsupportedChannel = errors.New("unsupported notification channel")
 )
 
 // Service reprents a notification service.
@@ -55,27 +60,58 @@ type Service interface {
 	// RemoveSubscription removes the subscription having the provided identifier.
 	RemoveSubscription(ctx context.Context, token, id string) error
 
+	// CreateEscalationPolicy persists the escalation policy of the user
+	// identified by the token.
+	CreateEscalationPolicy(ctx context.Context, token string, p EscalationPolicy) (string, error)
+
+	// ViewEscalationPolicy retrieves the escalation policy of the user.
+	ViewEscalationPolicy(ctx context.Context, token, id string) (EscalationPolicy, error)
+
+	// ListEscalationPolicies lists the escalation policies of the user.
+	ListEscalationPolicies(ctx context.Context, token string) ([]EscalationPolicy, error)
+
+	// RemoveEscalationPolicy removes the escalation policy of the user,
+	// alongside its alerts.
+	RemoveEscalationPolicy(ctx context.Context, token, id string) error
+
+	// ListAlerts lists the alerts raised by the escalation policies of the
+	// user. Only the unacknowledged alerts are listed if pending is set.
+	ListAlerts(ctx context.Context, token string, pending bool) ([]Alert, error)
+
+	// AcknowledgeAlert acknowledges the alert, stopping its escalation.
+	AcknowledgeAlert(ctx context.Context, token, id string) error
+
+	// Escalate notifies the next step of the unacknowledged alerts whose
+	// step is due.
+	Escalate(ctx context.Context) error
+
 	consumers.Consumer
 }
 
 var _ Service = (*notifierService)(nil)
 
 type notifierService struct {
-	auth     mainflux.AuthServiceClient
-	subs     SubscriptionsRepository
-	idp      mainflux.IDProvider
-	notifier Notifier
-	from     string
+	auth        mainflux.AuthServiceClient
+	subs        SubscriptionsRepository
+	escalations EscalationRepository
+	idp         mainflux.IDProvider
+	notifier    Notifier
+	channels    map[string]Notifier
+	from        string
 }
 
-// New instantiates the subscriptions service implementation.
-func New(auth mainflux.AuthServiceClient, subs SubscriptionsRepository, idp mainflux.IDProvider, notifier Notifier, from string) Service {
+// New instantiates the subscriptions service implementation. The
+// subscribers are notified using the notifier, while the escalation steps
+// are notified using the notifier of their channel.
+func New(auth mainflux.AuthServiceClient, subs SubscriptionsRepository, escalations EscalationRepository, idp mainflux.IDProvider, notifier Notifier, channels map[string]Notifier, from string) Service {
 	return &notifierService{
-		auth:     auth,
-		subs:     subs,
-		idp:      idp,
-		notifier: notifier,
-		from:     from,
+		auth:        auth,
+		subs:        subs,
+		escalations: escalations,
+		idp:         idp,
+		notifier:    notifier,
+		channels:    channels,
+		from:        from,
 	}
 }
 
@@ -117,6 +153,147 @@ func (ns *notifierService) RemoveSubscription(ctx context.Context, token, id str
 	return ns.subs.Remove(ctx, id)
 }
 
+func (ns *notifierService) CreateEscalationPolicy(ctx context.Context, token string, p EscalationPolicy) (string, error) {
+	res, err := ns.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return "", errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if p.Topic == "" || len(p.Steps) == 0 {
+		return "", errors.ErrMalformedEntity
+	}
+	for _, s := range p.Steps {
+		if s.Delay < 0 || (len(s.Contacts) == 0 && !s.OnCall) {
+			return "", errors.ErrMalformedEntity
+		}
+		if _, ok := ns.channels[s.Channel]; !ok {
+			return "", errors.Wrap(ErrUnsupportedChannel, fmt.Errorf("channel %s", s.Channel))
+		}
+	}
+	for _, s := range p.Calendar {
+		if !s.Start.Before(s.End) {
+			return "", errors.ErrMalformedEntity
+		}
+	}
+
+	p.ID, err = ns.idp.ID()
+	if err != nil {
+		return "", errors.Wrap(ErrCreateID, err)
+	}
+
+	p.OwnerID = res.GetId()
+	return ns.escalations.SavePolicy(ctx, p)
+}
+
+func (ns *notifierService) ViewEscalationPolicy(ctx context.Context, token, id string) (EscalationPolicy, error) {
+	res, err := ns.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return EscalationPolicy{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	p, err := ns.escalations.RetrievePolicy(ctx, id)
+	if err != nil {
+		return EscalationPolicy{}, err
+	}
+	if p.OwnerID != res.GetId() {
+		return EscalationPolicy{}, ErrNotFound
+	}
+
+	return p, nil
+}
+
+func (ns *notifierService) ListEscalationPolicies(ctx context.Context, token string) ([]EscalationPolicy, error) {
+	res, err := ns.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return ns.escalations.RetrievePolicies(ctx, res.GetId(), "")
+}
+
+func (ns *notifierService) RemoveEscalationPolicy(ctx context.Context, token, id string) error {
+	res, err := ns.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return ns.escalations.RemovePolicy(ctx, res.GetId(), id)
+}
+
+func (ns *notifierService) ListAlerts(ctx context.Context, token string, pending bool) ([]Alert, error) {
+	res, err := ns.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return ns.escalations.RetrieveAlerts(ctx, res.GetId(), pending)
+}
+
+func (ns *notifierService) AcknowledgeAlert(ctx context.Context, token, id string) error {
+	res, err := ns.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	a, err := ns.escalations.RetrieveAlert(ctx, id)
+	if err != nil {
+		return err
+	}
+	if a.OwnerID != res.GetId() {
+		return ErrNotFound
+	}
+	if a.Acknowledged() {
+		return nil
+	}
+
+	a.AcknowledgedAt = time.Now().UTC()
+	a.AcknowledgedBy = res.GetEmail()
+	a.EscalateAt = time.Time{}
+	return ns.escalations.UpdateAlert(ctx, a)
+}
+
+func (ns *notifierService) Escalate(ctx context.Context) error {
+	now := time.Now().UTC()
+	alerts, err := ns.escalations.RetrieveDueAlerts(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	// The failure to escalate the single alert doesn't stop the
+	// escalation of the others.
+	var errs error
+	for _, a := range alerts {
+		if err := ns.escalate(ctx, a, now); err != nil {
+			errs = errors.Wrap(fmt.Errorf("failed to escalate alert %s: %s", a.ID, err), errs)
+		}
+	}
+
+	return errs
+}
+
+func (ns *notifierService) escalate(ctx context.Context, a Alert, now time.Time) error {
+	p, err := ns.escalations.RetrievePolicy(ctx, a.PolicyID)
+	if err != nil {
+		return err
+	}
+
+	a.Step++
+	a.EscalateAt = time.Time{}
+	if a.Step >= len(p.Steps) {
+		return ns.escalations.UpdateAlert(ctx, a)
+	}
+
+	a.NotifiedAt = now
+	a.EscalateAt = nextStep(p, a.Step, now)
+	// The alert moves to the next step even if the notification fails,
+	// so that the chain still reaches the other contacts.
+	if err := ns.escalations.UpdateAlert(ctx, a); err != nil {
+		return err
+	}
+
+	return ns.notifyStep(p, a.Step, a.Message, now)
+}
+
 func (ns *notifierService) Consume(message interface{}) error {
 	msg, ok := message.(messaging.Message)
 	if !ok {
@@ -126,6 +303,16 @@ func (ns *notifierService) Consume(message interface{}) error {
 	if msg.Subtopic != "" {
 		topic = fmt.Sprintf("%s.%s", msg.Channel, msg.Subtopic)
 	}
+
+	errSubs := ns.notifySubscribers(topic, msg)
+	if err := ns.raiseAlerts(topic, msg); err != nil {
+		return err
+	}
+
+	return errSubs
+}
+
+func (ns *notifierService) notifySubscribers(topic string, msg messaging.Message) error {
 	pm := PageMetadata{
 		Topic:  topic,
 		Offset: 0,
@@ -133,6 +320,9 @@ func (ns *notifierService) Consume(message interface{}) error {
 	}
 	page, err := ns.subs.RetrieveAll(context.Background(), pm)
 	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return nil
+		}
 		return err
 	}
 
@@ -149,3 +339,72 @@ func (ns *notifierService) Consume(message interface{}) error {
 
 	return nil
 }
+
+// raiseAlerts raises the alert for every escalation policy of the topic,
+// notifying the first step of the policy.
+func (ns *notifierService) raiseAlerts(topic string, msg messaging.Message) error {
+	ctx := context.Background()
+	policies, err := ns.escalations.RetrievePolicies(ctx, "", topic)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var errs error
+	for _, p := range policies {
+		id, err := ns.idp.ID()
+		if err != nil {
+			return errors.Wrap(ErrCreateID, err)
+		}
+
+		a := Alert{
+			ID:         id,
+			PolicyID:   p.ID,
+			OwnerID:    p.OwnerID,
+			Message:    msg,
+			Step:       0,
+			CreatedAt:  now,
+			NotifiedAt: now,
+			EscalateAt: nextStep(p, 0, now),
+		}
+		if err := ns.escalations.SaveAlert(ctx, a); err != nil {
+			errs = errors.Wrap(err, errs)
+			continue
+		}
+		if err := ns.notifyStep(p, 0, msg, now); err != nil {
+			errs = errors.Wrap(err, errs)
+		}
+	}
+
+	return errs
+}
+
+func (ns *notifierService) notifyStep(p EscalationPolicy, step int, msg messaging.Message, now time.Time) error {
+	s := p.Steps[step]
+	to := append([]string{}, s.Contacts...)
+	if s.OnCall {
+		to = append(to, p.onCall(s.Channel, now)...)
+	}
+	if len(to) == 0 {
+		return nil
+	}
+
+	n, ok := ns.channels[s.Channel]
+	if !ok {
+		return ErrUnsupportedChannel
+	}
+	if err := n.Notify(ns.from, to, msg); err != nil {
+		return errors.Wrap(ErrNotify, err)
+	}
+
+	return nil
+}
+
+// nextStep returns the time the step following the given one is due, or
+// zero time if the given step is the last one.
+func nextStep(p EscalationPolicy, step int, t time.Time) time.Time {
+	if step+1 >= len(p.Steps) {
+		return time.Time{}
+	}
+	return t.Add(p.Steps[step+1].Delay)
+}
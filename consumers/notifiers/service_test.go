@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
 	"github.com/mainflux/mainflux/consumers/notifiers/mocks"
@@ -25,12 +26,20 @@ const (
 )
 
 func newService() notifiers.Service {
+	return newEscalationService(mocks.NewRecorder())
+}
+
+func newEscalationService(recorder *mocks.Recorder) notifiers.Service {
 	repo := mocks.NewRepo(make(map[string]notifiers.Subscription))
 	auth := mocks.NewAuth(map[string]string{exampleUser1: exampleUser1, exampleUser2: exampleUser2, invalidUser: invalidUser})
 	notifier := mocks.NewNotifier()
+	channels := map[string]notifiers.Notifier{
+		notifiers.EmailChannel:   recorder,
+		notifiers.WebhookChannel: recorder,
+	}
 	idp := uuid.NewMock()
 	from := "exampleFrom"
-	return notifiers.New(auth, repo, idp, notifier, from)
+	return notifiers.New(auth, repo, mocks.NewEscalationRepo(), idp, notifier, channels, from)
 }
 
 func TestCreateSubscription(t *testing.T) {
@@ -325,3 +334,111 @@ func TestConsume(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
+
+func TestCreateEscalationPolicy(t *testing.T) {
+	svc := newService()
+
+	step := notifiers.EscalationStep{Channel: notifiers.EmailChannel, Contacts: []string{exampleUser1}}
+	cases := []struct {
+		desc   string
+		token  string
+		policy notifiers.EscalationPolicy
+		err    error
+	}{
+		{
+			desc:   "create escalation policy",
+			token:  exampleUser1,
+			policy: notifiers.EscalationPolicy{Topic: "topic", Steps: []notifiers.EscalationStep{step}},
+			err:    nil,
+		},
+		{
+			desc:   "create escalation policy with invalid token",
+			token:  "invalid",
+			policy: notifiers.EscalationPolicy{Topic: "topic", Steps: []notifiers.EscalationStep{step}},
+			err:    notifiers.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "create escalation policy without steps",
+			token:  exampleUser1,
+			policy: notifiers.EscalationPolicy{Topic: "topic"},
+			err:    errors.ErrMalformedEntity,
+		},
+		{
+			desc:  "create escalation policy with step without contacts",
+			token: exampleUser1,
+			policy: notifiers.EscalationPolicy{
+				Topic: "topic",
+				Steps: []notifiers.EscalationStep{{Channel: notifiers.EmailChannel}},
+			},
+			err: errors.ErrMalformedEntity,
+		},
+		{
+			desc:  "create escalation policy with unsupported channel",
+			token: exampleUser1,
+			policy: notifiers.EscalationPolicy{
+				Topic: "topic",
+				Steps: []notifiers.EscalationStep{step, {Channel: notifiers.SMSChannel, Contacts: []string{"+381600000000"}}},
+			},
+			err: notifiers.ErrUnsupportedChannel,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateEscalationPolicy(context.Background(), tc.token, tc.policy)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	recorder := mocks.NewRecorder()
+	svc := newEscalationService(recorder)
+
+	delay := 50 * time.Millisecond
+	now := time.Now()
+	policy := notifiers.EscalationPolicy{
+		Topic: "topic.alerts",
+		Steps: []notifiers.EscalationStep{
+			{Channel: notifiers.EmailChannel, Contacts: []string{"first@example.com"}},
+			{Channel: notifiers.EmailChannel, OnCall: true, Delay: delay},
+			{Channel: notifiers.WebhookChannel, Contacts: []string{"http://example.com/hook"}, Delay: delay},
+		},
+		Calendar: []notifiers.Shift{
+			{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Contacts: map[string]string{notifiers.EmailChannel: "oncall@example.com"}},
+			{Start: now.Add(time.Hour), End: now.Add(2 * time.Hour), Contacts: map[string]string{notifiers.EmailChannel: "later@example.com"}},
+		},
+	}
+	_, err := svc.CreateEscalationPolicy(context.Background(), exampleUser1, policy)
+	require.Nil(t, err, fmt.Sprintf("creating escalation policy expected to succeed: %s", err))
+
+	msg := messaging.Message{Channel: "topic", Subtopic: "alerts"}
+	err = svc.Consume(msg)
+	require.Nil(t, err, fmt.Sprintf("consuming message expected to succeed: %s", err))
+	assert.Equal(t, []string{"first@example.com"}, recorder.Flush(), "raising alert expected to notify the first step")
+
+	err = svc.Escalate(context.Background())
+	require.Nil(t, err, fmt.Sprintf("escalating expected to succeed: %s", err))
+	assert.Empty(t, recorder.Flush(), "escalating before the delay expected to notify no one")
+
+	time.Sleep(2 * delay)
+	err = svc.Escalate(context.Background())
+	require.Nil(t, err, fmt.Sprintf("escalating expected to succeed: %s", err))
+	assert.Equal(t, []string{"oncall@example.com"}, recorder.Flush(), "escalating expected to notify the contact on call")
+
+	alerts, err := svc.ListAlerts(context.Background(), exampleUser1, true)
+	require.Nil(t, err, fmt.Sprintf("listing alerts expected to succeed: %s", err))
+	require.Len(t, alerts, 1, "expected single pending alert")
+
+	err = svc.AcknowledgeAlert(context.Background(), exampleUser2, alerts[0].ID)
+	assert.True(t, errors.Contains(err, notifiers.ErrNotFound), fmt.Sprintf("acknowledging other user's alert: expected %s got %s\n", notifiers.ErrNotFound, err))
+	err = svc.AcknowledgeAlert(context.Background(), exampleUser1, alerts[0].ID)
+	require.Nil(t, err, fmt.Sprintf("acknowledging alert expected to succeed: %s", err))
+
+	time.Sleep(2 * delay)
+	err = svc.Escalate(context.Background())
+	require.Nil(t, err, fmt.Sprintf("escalating expected to succeed: %s", err))
+	assert.Empty(t, recorder.Flush(), "escalating acknowledged alert expected to notify no one")
+
+	alerts, err = svc.ListAlerts(context.Background(), exampleUser1, true)
+	require.Nil(t, err, fmt.Sprintf("listing alerts expected to succeed: %s", err))
+	assert.Empty(t, alerts, "expected no pending alerts after acknowledgement")
+}
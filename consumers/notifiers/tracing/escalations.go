@@ -0,0 +1,112 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+	"time"
+
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	savePolicyOp        = "save_policy_op"
+	retrievePolicyOp    = "retrieve_policy_op"
+	retrievePoliciesOp  = "retrieve_policies_op"
+	removePolicyOp      = "remove_policy_op"
+	saveAlertOp         = "save_alert_op"
+	updateAlertOp       = "update_alert_op"
+	retrieveAlertOp     = "retrieve_alert_op"
+	retrieveAlertsOp    = "retrieve_alerts_op"
+	retrieveDueAlertsOp = "retrieve_due_alerts_op"
+)
+
+var _ notifiers.EscalationRepository = (*escalationRepositoryMiddleware)(nil)
+
+type escalationRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   notifiers.EscalationRepository
+}
+
+// NewEscalationRepository instantiates a new escalation repository that
+// tracks request and their latency, and adds spans to context.
+func NewEscalationRepository(repo notifiers.EscalationRepository, tracer opentracing.Tracer) notifiers.EscalationRepository {
+	return escalationRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (erm escalationRepositoryMiddleware) SavePolicy(ctx context.Context, p notifiers.EscalationPolicy) (string, error) {
+	span := createSpan(ctx, erm.tracer, savePolicyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.SavePolicy(ctx, p)
+}
+
+func (erm escalationRepositoryMiddleware) RetrievePolicy(ctx context.Context, id string) (notifiers.EscalationPolicy, error) {
+	span := createSpan(ctx, erm.tracer, retrievePolicyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrievePolicy(ctx, id)
+}
+
+func (erm escalationRepositoryMiddleware) RetrievePolicies(ctx context.Context, ownerID, topic string) ([]notifiers.EscalationPolicy, error) {
+	span := createSpan(ctx, erm.tracer, retrievePoliciesOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrievePolicies(ctx, ownerID, topic)
+}
+
+func (erm escalationRepositoryMiddleware) RemovePolicy(ctx context.Context, ownerID, id string) error {
+	span := createSpan(ctx, erm.tracer, removePolicyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RemovePolicy(ctx, ownerID, id)
+}
+
+func (erm escalationRepositoryMiddleware) SaveAlert(ctx context.Context, a notifiers.Alert) error {
+	span := createSpan(ctx, erm.tracer, saveAlertOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.SaveAlert(ctx, a)
+}
+
+func (erm escalationRepositoryMiddleware) UpdateAlert(ctx context.Context, a notifiers.Alert) error {
+	span := createSpan(ctx, erm.tracer, updateAlertOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.UpdateAlert(ctx, a)
+}
+
+func (erm escalationRepositoryMiddleware) RetrieveAlert(ctx context.Context, id string) (notifiers.Alert, error) {
+	span := createSpan(ctx, erm.tracer, retrieveAlertOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveAlert(ctx, id)
+}
+
+func (erm escalationRepositoryMiddleware) RetrieveAlerts(ctx context.Context, ownerID string, pending bool) ([]notifiers.Alert, error) {
+	span := createSpan(ctx, erm.tracer, retrieveAlertsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveAlerts(ctx, ownerID, pending)
+}
+
+func (erm escalationRepositoryMiddleware) RetrieveDueAlerts(ctx context.Context, t time.Time) ([]notifiers.Alert, error) {
+	span := createSpan(ctx, erm.tracer, retrieveDueAlertsOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveDueAlerts(ctx, t)
+}
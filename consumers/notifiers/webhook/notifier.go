@@ -0,0 +1,85 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	notifiers "github.com/mainflux/mainflux/consumers/notifiers"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+const contentType = "application/json"
+
+var _ notifiers.Notifier = (*notifier)(nil)
+
+type notifier struct {
+	client *http.Client
+}
+
+type notification struct {
+	From      string      `json:"from,omitempty"`
+	Channel   string      `json:"channel"`
+	Subtopic  string      `json:"subtopic,omitempty"`
+	Publisher string      `json:"publisher"`
+	Protocol  string      `json:"protocol"`
+	Created   int64       `json:"created"`
+	Payload   interface{} `json:"payload"`
+}
+
+// New instantiates webhook notifier, posting the messages to the contacts
+// given as the URLs.
+func New(client *http.Client) notifiers.Notifier {
+	return &notifier{client: client}
+}
+
+func (n *notifier) Notify(from string, to []string, msg messaging.Message) error {
+	nt := notification{
+		From:      from,
+		Channel:   msg.Channel,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Created:   msg.Created,
+		Payload:   string(msg.Payload),
+	}
+	if json.Valid(msg.Payload) {
+		nt.Payload = json.RawMessage(msg.Payload)
+	}
+
+	body, err := json.Marshal(nt)
+	if err != nil {
+		return err
+	}
+
+	// All the contacts are notified even if some of them fail.
+	var errs []string
+	for _, url := range to {
+		if err := n.post(url, body); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", url, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to notify %v", errs)
+	}
+
+	return nil
+}
+
+func (n *notifier) post(url string, body []byte) error {
+	res, err := n.client.Post(url, contentType, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	return nil
+}
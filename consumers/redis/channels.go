@@ -0,0 +1,96 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/consumers"
+)
+
+const (
+	stream = "mainflux.things"
+
+	channelPrefix = "channel."
+	channelCreate = channelPrefix + "create"
+	channelUpdate = channelPrefix + "update"
+	channelRemove = channelPrefix + "remove"
+
+	batchSize = 100
+	// block is the time the stream read blocks waiting for the new
+	// events, so that the context is checked periodically.
+	block = time.Second
+)
+
+var _ consumers.ChannelSource = (*channelSource)(nil)
+
+type channelSource struct {
+	client *redis.Client
+}
+
+// NewChannelSource returns the channel source reading the channel events
+// from the things service event stream. The existing channels are resolved
+// by replaying the events retained in the stream.
+func NewChannelSource(client *redis.Client) consumers.ChannelSource {
+	return channelSource{client: client}
+}
+
+func (cs channelSource) Watch(ctx context.Context, handle func(consumers.ChannelEvent)) error {
+	// Unlike the consumer groups, reading the stream from the beginning
+	// replays the retained events to every consumer instance.
+	lastID := "0"
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+
+		streams, err := cs.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{stream, lastID},
+			Count:   batchSize,
+			Block:   block,
+		}).Result()
+		switch {
+		case err == redis.Nil:
+			continue
+		case err != nil && ctx.Err() != nil:
+			return nil
+		case err != nil:
+			return err
+		}
+
+		for _, msg := range streams[0].Messages {
+			lastID = msg.ID
+			if e, ok := decodeChannelEvent(msg.Values); ok {
+				handle(e)
+			}
+		}
+	}
+}
+
+func decodeChannelEvent(event map[string]interface{}) (consumers.ChannelEvent, bool) {
+	id := read(event, "id", "")
+	if id == "" {
+		return consumers.ChannelEvent{}, false
+	}
+
+	switch read(event, "operation", "") {
+	case channelCreate, channelUpdate:
+		return consumers.ChannelEvent{ID: id, Name: read(event, "name", "")}, true
+	case channelRemove:
+		return consumers.ChannelEvent{ID: id, Removed: true}, true
+	default:
+		return consumers.ChannelEvent{}, false
+	}
+}
+
+func read(event map[string]interface{}, key, def string) string {
+	val, ok := event[key].(string)
+	if !ok {
+		return def
+	}
+
+	return val
+}
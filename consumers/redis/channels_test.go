@@ -0,0 +1,64 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/consumers"
+	consumerredis "github.com/mainflux/mainflux/consumers/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stream = "mainflux.things"
+
+func TestWatch(t *testing.T) {
+	ctx := context.Background()
+	err := redisClient.FlushAll(ctx).Err()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	events := []map[string]interface{}{
+		{"id": "1", "name": "sensors", "operation": "channel.create"},
+		{"id": "1", "operation": "thing.create"},
+		{"id": "2", "operation": "channel.create"},
+		{"id": "1", "name": "actuators", "operation": "channel.update"},
+		{"id": "2", "operation": "channel.remove"},
+	}
+	for _, e := range events {
+		err := redisClient.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: e}).Err()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	expected := []consumers.ChannelEvent{
+		{ID: "1", Name: "sensors"},
+		{ID: "2"},
+		{ID: "1", Name: "actuators"},
+		{ID: "2", Removed: true},
+		{ID: "3", Name: "created"},
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	received := []consumers.ChannelEvent{}
+	src := consumerredis.NewChannelSource(redisClient)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		redisClient.XAdd(ctx, &redis.XAddArgs{Stream: stream, Values: map[string]interface{}{"id": "3", "name": "created", "operation": "channel.create"}})
+	}()
+
+	err = src.Watch(ctx, func(e consumers.ChannelEvent) {
+		received = append(received, e)
+		if len(received) == len(expected) {
+			cancel()
+		}
+	})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, expected, received, fmt.Sprintf("expected events %v got %v", expected, received))
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumers
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	pubsub "github.com/mainflux/mainflux/pkg/messaging/nats"
+)
+
+const chansPrefix = "channels"
+
+var errMissingChannelSource = errors.New("channel patterns require the channel source")
+
+// ChannelEvent represents the creation, update or removal of the channel.
+type ChannelEvent struct {
+	ID      string
+	Name    string
+	Removed bool
+}
+
+// ChannelSource provides the channels the subscription patterns are
+// resolved against.
+type ChannelSource interface {
+	// Watch calls the handler for each existing channel, and then for
+	// each channel created, updated or removed, until the context is done.
+	Watch(ctx context.Context, handle func(ChannelEvent)) error
+}
+
+// channelFilter selects the channels to subscribe to. The channel is
+// selected if it's listed explicitly or its ID or name matches the
+// pattern, unless its ID or name matches the exclusion pattern.
+type channelFilter struct {
+	channels map[string]bool
+	patterns []string
+	exclude  []string
+}
+
+func newChannelFilter(cfg subscriberConfig) (channelFilter, error) {
+	for _, p := range append(cfg.Patterns, cfg.Exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return channelFilter{}, errors.Wrap(errParseConfFile, fmt.Errorf("invalid channel pattern %s: %s", p, err))
+		}
+	}
+
+	f := channelFilter{
+		channels: make(map[string]bool),
+		patterns: cfg.Patterns,
+		exclude:  cfg.Exclude,
+	}
+	for _, id := range cfg.Channels {
+		f.channels[id] = true
+	}
+	return f, nil
+}
+
+func (f channelFilter) excluded(id, name string) bool {
+	return matchAny(f.exclude, id, name)
+}
+
+func (f channelFilter) matches(id, name string) bool {
+	if f.excluded(id, name) {
+		return false
+	}
+	return f.channels[id] || matchAny(f.patterns, id, name)
+}
+
+func matchAny(patterns []string, id, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, id); ok {
+			return true
+		}
+		if ok, _ := path.Match(p, name); ok && name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// channelSubjects returns the broker subjects of the channel messages,
+// with and without the subtopic.
+func channelSubjects(id string) []string {
+	subject := fmt.Sprintf("%s.%s", chansPrefix, id)
+	return []string{subject, subject + ".>"}
+}
+
+// subscriptions keeps the subscriptions to the channels matching the
+// patterns in sync with the created, updated and removed channels.
+type subscriptions struct {
+	mu         sync.Mutex
+	sub        messaging.Subscriber
+	handler    messaging.MessageHandler
+	filter     channelFilter
+	subscribed map[string]bool
+	logger     logger.Logger
+}
+
+func (s *subscriptions) handle(e ChannelEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Explicitly listed channels are subscribed on start.
+	if s.filter.channels[e.ID] {
+		return
+	}
+
+	match := !e.Removed && s.filter.matches(e.ID, e.Name)
+	switch {
+	case match && !s.subscribed[e.ID]:
+		for _, subject := range channelSubjects(e.ID) {
+			if err := s.sub.Subscribe(subject, s.handler); err != nil {
+				s.logger.Warn(fmt.Sprintf("Failed to subscribe to %s: %s", subject, err))
+				return
+			}
+		}
+		s.subscribed[e.ID] = true
+		s.logger.Info(fmt.Sprintf("Subscribed to channel %s", e.ID))
+	case !match && s.subscribed[e.ID]:
+		for _, subject := range channelSubjects(e.ID) {
+			if err := s.sub.Unsubscribe(subject); err != nil {
+				s.logger.Warn(fmt.Sprintf("Failed to unsubscribe from %s: %s", subject, err))
+			}
+		}
+		delete(s.subscribed, e.ID)
+		s.logger.Info(fmt.Sprintf("Unsubscribed from channel %s", e.ID))
+	}
+}
+
+// subscribe subscribes to the configured subjects and explicitly listed
+// channels, and keeps the subscriptions to the channels matching the
+// patterns in sync with the channel source.
+func subscribe(sub messaging.Subscriber, handler messaging.MessageHandler, src ChannelSource, cfg subscriberConfig, logger logger.Logger) error {
+	filter, err := newChannelFilter(cfg)
+	if err != nil {
+		return err
+	}
+	if len(cfg.Patterns) > 0 && src == nil {
+		return errMissingChannelSource
+	}
+
+	subjects := cfg.Subjects
+	for _, id := range cfg.Channels {
+		if filter.excluded(id, "") {
+			continue
+		}
+		subjects = append(subjects, channelSubjects(id)...)
+	}
+	if len(subjects) == 0 && len(cfg.Patterns) == 0 {
+		subjects = defSubjects
+		if cfg.Partition != nil {
+			subjects = pubsub.PartitionSubjects(*cfg.Partition)
+		}
+	}
+
+	for _, subject := range subjects {
+		if err := sub.Subscribe(subject, handler); err != nil {
+			return err
+		}
+	}
+
+	if len(cfg.Patterns) == 0 {
+		return nil
+	}
+
+	s := &subscriptions{
+		sub:        sub,
+		handler:    handler,
+		filter:     filter,
+		subscribed: make(map[string]bool),
+		logger:     logger,
+	}
+	go func() {
+		if err := src.Watch(context.Background(), s.handle); err != nil {
+			logger.Error(fmt.Sprintf("Failed to watch channels: %s", err))
+		}
+	}()
+	return nil
+}
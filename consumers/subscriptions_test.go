@@ -0,0 +1,171 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package consumers_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type subscriberMock struct {
+	mu       sync.Mutex
+	subjects map[string]bool
+}
+
+func (sm *subscriberMock) Subscribe(topic string, handler messaging.MessageHandler) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.subjects[topic] = true
+	return nil
+}
+
+func (sm *subscriberMock) Unsubscribe(topic string) error {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.subjects, topic)
+	return nil
+}
+
+func (sm *subscriberMock) list() []string {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	subjects := []string{}
+	for s := range sm.subjects {
+		subjects = append(subjects, s)
+	}
+	sort.Strings(subjects)
+	return subjects
+}
+
+type channelSourceMock struct {
+	events []consumers.ChannelEvent
+	done   chan struct{}
+}
+
+func (cs channelSourceMock) Watch(ctx context.Context, handle func(consumers.ChannelEvent)) error {
+	for _, e := range cs.events {
+		handle(e)
+	}
+	close(cs.done)
+	return nil
+}
+
+func TestStartSubscriptions(t *testing.T) {
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	dir, err := ioutil.TempDir("", "consumers")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	events := []consumers.ChannelEvent{
+		{ID: "1", Name: "sensors-temp"},
+		{ID: "2", Name: "sensors-hum"},
+		{ID: "3", Name: "sensors-private"},
+		{ID: "4", Name: "actuators"},
+		{ID: "5", Name: "sensors-co2"},
+		{ID: "5", Removed: true},
+		{ID: "4", Name: "sensors-door"},
+		{ID: "2", Name: "hum"},
+	}
+
+	cases := []struct {
+		desc     string
+		config   string
+		src      bool
+		subjects []string
+		err      bool
+	}{
+		{
+			desc:     "subscribe to all channels by default",
+			config:   "",
+			subjects: []string{"channels.>"},
+		},
+		{
+			desc:     "subscribe to partition",
+			config:   `partition = 2`,
+			subjects: []string{"channels.*.part2", "channels.*.part2.>"},
+		},
+		{
+			desc:     "subscribe to subjects instead of partition",
+			config:   `subjects = ["channels.1.>"]` + "\n" + `partition = 2`,
+			subjects: []string{"channels.1.>"},
+		},
+		{
+			desc:     "subscribe to subjects",
+			config:   `subjects = ["channels.1.>"]`,
+			subjects: []string{"channels.1.>"},
+		},
+		{
+			desc:     "subscribe to channels without excluded",
+			config:   `channels = ["1", "2"]` + "\n" + `exclude = ["2"]`,
+			subjects: []string{"channels.1", "channels.1.>"},
+		},
+		{
+			desc:   "subscribe to channels matching patterns",
+			config: `patterns = ["sensors-*"]` + "\n" + `exclude = ["*-private"]`,
+			src:    true,
+			subjects: []string{
+				"channels.1", "channels.1.>",
+				"channels.4", "channels.4.>",
+			},
+		},
+		{
+			desc:   "subscribe to channels and channels matching patterns",
+			config: `channels = ["2"]` + "\n" + `patterns = ["sensors-t*"]`,
+			src:    true,
+			subjects: []string{
+				"channels.1", "channels.1.>",
+				"channels.2", "channels.2.>",
+			},
+		},
+		{
+			desc:   "subscribe to channels matching patterns without source",
+			config: `patterns = ["sensors-*"]`,
+			err:    true,
+		},
+		{
+			desc:   "subscribe to channels matching invalid pattern",
+			config: `patterns = ["sensors-["]`,
+			src:    true,
+			err:    true,
+		},
+	}
+
+	for i, tc := range cases {
+		path := filepath.Join(dir, fmt.Sprintf("config-%d.toml", i))
+		err := ioutil.WriteFile(path, []byte("[subscriber]\n"+tc.config), 0644)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		sub := &subscriberMock{subjects: make(map[string]bool)}
+		var src consumers.ChannelSource
+		done := make(chan struct{})
+		if tc.src {
+			src = channelSourceMock{events: events, done: done}
+		} else {
+			close(done)
+		}
+
+		err = consumers.Start(sub, &consumerMock{}, src, path, logger)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		if tc.err {
+			continue
+		}
+
+		<-done
+		assert.Equal(t, tc.subjects, sub.list(), fmt.Sprintf("%s: expected subjects %v got %v", tc.desc, tc.subjects, sub.list()))
+	}
+}
@@ -40,3 +40,48 @@ func (lm *loggingMiddleware) Consume(msgs interface{}) (err error) {
 
 	return lm.consumer.Consume(msgs)
 }
+
+var _ consumers.CheckpointConsumer = (*checkpointLoggingMiddleware)(nil)
+
+type checkpointLoggingMiddleware struct {
+	loggingMiddleware
+	consumer consumers.CheckpointConsumer
+}
+
+// CheckpointLoggingMiddleware adds logging facilities to the checkpoint
+// consumer.
+func CheckpointLoggingMiddleware(consumer consumers.CheckpointConsumer, logger log.Logger) consumers.CheckpointConsumer {
+	return &checkpointLoggingMiddleware{
+		loggingMiddleware: loggingMiddleware{
+			logger:   logger,
+			consumer: consumer,
+		},
+		consumer: consumer,
+	}
+}
+
+func (lm *checkpointLoggingMiddleware) ConsumeAt(msgs interface{}, cp consumers.Checkpoint) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method consume_at for message %d took %s to complete", cp.Sequence, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.consumer.ConsumeAt(msgs, cp)
+}
+
+func (lm *checkpointLoggingMiddleware) Checkpoint(name string) (seq uint64, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method checkpoint for consumer %s took %s to complete", name, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.consumer.Checkpoint(name)
+}
@@ -8,6 +8,9 @@ import (
 
 	"github.com/go-kit/kit/metrics"
 	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/internal/cardinality"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
 )
 
 var _ consumers.Consumer = (*metricsMiddleware)(nil)
@@ -15,6 +18,7 @@ var _ consumers.Consumer = (*metricsMiddleware)(nil)
 type metricsMiddleware struct {
 	counter  metrics.Counter
 	latency  metrics.Histogram
+	channels *cardinality.Limiter
 	consumer consumers.Consumer
 }
 
@@ -28,10 +32,89 @@ func MetricsMiddleware(consumer consumers.Consumer, counter metrics.Counter, lat
 	}
 }
 
+// ChannelMetricsMiddleware returns new message repository with Save method
+// wrapped to expose metrics labeled by the channel of the consumed messages.
+// Counter and latency have to declare the "method" and "channel" labels.
+// Channels exceeding the limiter are reported as cardinality.Other.
+func ChannelMetricsMiddleware(consumer consumers.Consumer, counter metrics.Counter, latency metrics.Histogram, channels *cardinality.Limiter) consumers.Consumer {
+	return &metricsMiddleware{
+		counter:  counter,
+		latency:  latency,
+		channels: channels,
+		consumer: consumer,
+	}
+}
+
 func (mm *metricsMiddleware) Consume(msgs interface{}) error {
 	defer func(begin time.Time) {
-		mm.counter.With("method", "consume").Add(1)
-		mm.latency.With("method", "consume").Observe(time.Since(begin).Seconds())
+		lvs := []string{"method", "consume"}
+		if mm.channels != nil {
+			lvs = append(lvs, "channel", mm.channels.Label(channel(msgs)))
+		}
+		mm.counter.With(lvs...).Add(1)
+		mm.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 	return mm.consumer.Consume(msgs)
 }
+
+var _ consumers.CheckpointConsumer = (*checkpointMetricsMiddleware)(nil)
+
+type checkpointMetricsMiddleware struct {
+	metricsMiddleware
+	consumer consumers.CheckpointConsumer
+}
+
+// CheckpointMetricsMiddleware returns new checkpoint consumer with methods
+// wrapped to expose metrics. If the channels limiter is set, the metrics are
+// labeled by the channel the same way as in ChannelMetricsMiddleware.
+func CheckpointMetricsMiddleware(consumer consumers.CheckpointConsumer, counter metrics.Counter, latency metrics.Histogram, channels *cardinality.Limiter) consumers.CheckpointConsumer {
+	return &checkpointMetricsMiddleware{
+		metricsMiddleware: metricsMiddleware{
+			counter:  counter,
+			latency:  latency,
+			channels: channels,
+			consumer: consumer,
+		},
+		consumer: consumer,
+	}
+}
+
+func (mm *checkpointMetricsMiddleware) ConsumeAt(msgs interface{}, cp consumers.Checkpoint) error {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "consume_at"}
+		if mm.channels != nil {
+			lvs = append(lvs, "channel", mm.channels.Label(channel(msgs)))
+		}
+		mm.counter.With(lvs...).Add(1)
+		mm.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return mm.consumer.ConsumeAt(msgs, cp)
+}
+
+func (mm *checkpointMetricsMiddleware) Checkpoint(name string) (uint64, error) {
+	defer func(begin time.Time) {
+		lvs := []string{"method", "checkpoint"}
+		if mm.channels != nil {
+			lvs = append(lvs, "channel", cardinality.Other)
+		}
+		mm.counter.With(lvs...).Add(1)
+		mm.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+	return mm.consumer.Checkpoint(name)
+}
+
+// channel returns the channel of the messages. Messages consumed at once are
+// received from the same channel, so the first message is used.
+func channel(msgs interface{}) string {
+	switch m := msgs.(type) {
+	case []senml.Message:
+		if len(m) > 0 {
+			return m[0].Channel
+		}
+	case mfjson.Messages:
+		if len(m.Data) > 0 {
+			return m.Data[0].Channel
+		}
+	}
+	return ""
+}
@@ -5,8 +5,10 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/gofrs/uuid"
 	"github.com/jmoiron/sqlx"
@@ -18,8 +20,9 @@ import (
 )
 
 const (
-	errInvalid        = "invalid_text_representation"
-	errUndefinedTable = "undefined_table"
+	errInvalid         = "invalid_text_representation"
+	errUndefinedTable  = "undefined_table"
+	errUndefinedColumn = "undefined_column"
 )
 
 var (
@@ -27,92 +30,179 @@ var (
 	errSaveMessage    = errors.New("failed to save message to postgres database")
 	errTransRollback  = errors.New("failed to rollback transaction")
 	errNoTable        = errors.New("relation does not exist")
+	errNoLineage      = errors.New("lineage columns do not exist")
+
+	errRetrieveCheckpoint = errors.New("failed to retrieve consumer checkpoint from postgres database")
+	errSaveCheckpoint     = errors.New("failed to save consumer checkpoint to postgres database")
 )
 
-var _ consumers.Consumer = (*postgresRepo)(nil)
+var _ consumers.CheckpointConsumer = (*postgresRepo)(nil)
 
 type postgresRepo struct {
-	db *sqlx.DB
+	db        *sqlx.DB
+	evolution EvolutionPolicy
+}
+
+// Option configures the PostgreSQL writer.
+type Option func(*postgresRepo)
+
+// WithEvolution sets the policy the JSON messages tables evolve by.
+func WithEvolution(policy EvolutionPolicy) Option {
+	return func(pr *postgresRepo) {
+		pr.evolution = policy
+	}
 }
 
 // New returns new PostgreSQL writer.
-func New(db *sqlx.DB) consumers.Consumer {
-	return &postgresRepo{db: db}
+func New(db *sqlx.DB, opts ...Option) consumers.Consumer {
+	return newRepo(db, opts)
+}
+
+// NewCheckpointConsumer returns new PostgreSQL writer storing the stream
+// checkpoint in the same transaction as the written messages.
+func NewCheckpointConsumer(db *sqlx.DB, opts ...Option) consumers.CheckpointConsumer {
+	return newRepo(db, opts)
+}
+
+func newRepo(db *sqlx.DB, opts []Option) *postgresRepo {
+	pr := &postgresRepo{db: db, evolution: NoEvolution}
+	for _, opt := range opts {
+		opt(pr)
+	}
+	return pr
 }
 
-func (pr postgresRepo) Consume(message interface{}) (err error) {
+func (pr postgresRepo) Consume(message interface{}) error {
+	return pr.consume(message, nil)
+}
+
+func (pr postgresRepo) ConsumeAt(message interface{}, cp consumers.Checkpoint) error {
+	return pr.consume(message, &cp)
+}
+
+func (pr postgresRepo) Checkpoint(name string) (uint64, error) {
+	q := `SELECT sequence FROM checkpoints WHERE consumer = $1`
+
+	var seq int64
+	if err := pr.db.QueryRowx(q, name).Scan(&seq); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, errors.Wrap(errRetrieveCheckpoint, err)
+	}
+	return uint64(seq), nil
+}
+
+func (pr postgresRepo) consume(message interface{}, cp *consumers.Checkpoint) error {
 	switch m := message.(type) {
 	case mfjson.Messages:
-		return pr.saveJSON(m)
+		return pr.saveJSON(m, cp)
 	default:
-		return pr.saveSenml(m)
+		return pr.saveSenml(m, cp)
 	}
 }
 
-func (pr postgresRepo) saveSenml(messages interface{}) (err error) {
+func (pr postgresRepo) saveSenml(messages interface{}, cp *consumers.Checkpoint) error {
 	msgs, ok := messages.([]senml.Message)
 	if !ok {
 		return errSaveMessage
 	}
 	q := `INSERT INTO messages (id, channel, subtopic, publisher, protocol,
-          name, unit, value, string_value, bool_value, data_value, sum,
-          time, update_time)
-          VALUES (:id, :channel, :subtopic, :publisher, :protocol, :name, :unit,
-          :value, :string_value, :bool_value, :data_value, :sum,
-          :time, :update_time);`
+          adapter, trace_id, hops, name, unit, value, string_value, bool_value,
+          data_value, sum, time, update_time)
+          VALUES (:id, :channel, :subtopic, :publisher, :protocol, :adapter,
+          :trace_id, :hops, :name, :unit, :value, :string_value, :bool_value,
+          :data_value, :sum, :time, :update_time);`
 
-	tx, err := pr.db.BeginTxx(context.Background(), nil)
-	if err != nil {
-		return errors.Wrap(errSaveMessage, err)
-	}
-	defer func() {
-		if err != nil {
-			if txErr := tx.Rollback(); txErr != nil {
-				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
+	return pr.transact(cp, func(tx *sqlx.Tx) error {
+		for _, msg := range msgs {
+			id, err := uuid.NewV4()
+			if err != nil {
+				return err
 			}
-			return
-		}
+			m := senmlMessage{Message: msg, ID: id.String()}
+			if _, err := tx.NamedExec(q, m); err != nil {
+				pqErr, ok := err.(*pq.Error)
+				if ok {
+					switch pqErr.Code.Name() {
+					case errInvalid:
+						return errors.Wrap(errSaveMessage, errInvalidMessage)
+					}
+				}
 
-		if err = tx.Commit(); err != nil {
-			err = errors.Wrap(errSaveMessage, err)
+				return errors.Wrap(errSaveMessage, err)
+			}
 		}
-	}()
+		return nil
+	})
+}
 
-	for _, msg := range msgs {
-		id, err := uuid.NewV4()
+func (pr postgresRepo) saveJSON(msgs mfjson.Messages, cp *consumers.Checkpoint) error {
+	if pr.evolution == ColumnsEvolution {
+		cols, err := pr.evolve(msgs)
 		if err != nil {
 			return err
 		}
-		m := senmlMessage{Message: msg, ID: id.String()}
-		if _, err := tx.NamedExec(q, m); err != nil {
-			pqErr, ok := err.(*pq.Error)
-			if ok {
-				switch pqErr.Code.Name() {
-				case errInvalid:
-					return errors.Wrap(errSaveMessage, errInvalidMessage)
-				}
+		err = pr.insertEvolvedJSON(msgs, cols, cp)
+		if err == errNoLineage {
+			if err := pr.addLineage(msgs.Format); err != nil {
+				return err
 			}
+			return pr.insertEvolvedJSON(msgs, cols, cp)
+		}
+		return err
+	}
 
-			return errors.Wrap(errSaveMessage, err)
+	err := pr.insertJSON(msgs, cp)
+	switch err {
+	case errNoTable:
+		if err := pr.createTable(msgs.Format); err != nil {
+			return err
 		}
+		return pr.insertJSON(msgs, cp)
+	case errNoLineage:
+		if err := pr.addLineage(msgs.Format); err != nil {
+			return err
+		}
+		return pr.insertJSON(msgs, cp)
 	}
 	return err
 }
 
-func (pr postgresRepo) saveJSON(msgs mfjson.Messages) error {
-	if err := pr.insertJSON(msgs); err != nil {
-		if err == errNoTable {
-			if err := pr.createTable(msgs.Format); err != nil {
+func (pr postgresRepo) insertJSON(msgs mfjson.Messages, cp *consumers.Checkpoint) error {
+	q := `INSERT INTO %s (id, channel, created, subtopic, publisher, protocol, adapter, trace_id, hops, payload)
+          VALUES (:id, :channel, :created, :subtopic, :publisher, :protocol, :adapter, :trace_id, :hops, :payload);`
+	q = fmt.Sprintf(q, msgs.Format)
+
+	return pr.transact(cp, func(tx *sqlx.Tx) error {
+		for _, m := range msgs.Data {
+			dbmsg, err := toJSONMessage(m)
+			if err != nil {
+				return errors.Wrap(errSaveMessage, err)
+			}
+			if _, err = tx.NamedExec(q, dbmsg); err != nil {
+				pqErr, ok := err.(*pq.Error)
+				if ok {
+					switch pqErr.Code.Name() {
+					case errInvalid:
+						return errors.Wrap(errSaveMessage, errInvalidMessage)
+					case errUndefinedTable:
+						return errNoTable
+					case errUndefinedColumn:
+						return errNoLineage
+					}
+				}
 				return err
 			}
-			return pr.insertJSON(msgs)
 		}
-		return err
-	}
-	return nil
+		return nil
+	})
 }
 
-func (pr postgresRepo) insertJSON(msgs mfjson.Messages) error {
+// transact runs the insert in the transaction. If the checkpoint is set,
+// the insert is skipped for the already consumed messages, and the
+// checkpoint is stored in the same transaction otherwise.
+func (pr postgresRepo) transact(cp *consumers.Checkpoint, insert func(tx *sqlx.Tx) error) (err error) {
 	tx, err := pr.db.BeginTxx(context.Background(), nil)
 	if err != nil {
 		return errors.Wrap(errSaveMessage, err)
@@ -130,29 +220,46 @@ func (pr postgresRepo) insertJSON(msgs mfjson.Messages) error {
 		}
 	}()
 
-	q := `INSERT INTO %s (id, channel, created, subtopic, publisher, protocol, payload)
-          VALUES (:id, :channel, :created, :subtopic, :publisher, :protocol, :payload);`
-	q = fmt.Sprintf(q, msgs.Format)
-
-	for _, m := range msgs.Data {
-		var dbmsg jsonMessage
-		dbmsg, err = toJSONMessage(m)
-		if err != nil {
-			return errors.Wrap(errSaveMessage, err)
-		}
-		if _, err = tx.NamedExec(q, dbmsg); err != nil {
-			pqErr, ok := err.(*pq.Error)
-			if ok {
-				switch pqErr.Code.Name() {
-				case errInvalid:
-					return errors.Wrap(errSaveMessage, errInvalidMessage)
-				case errUndefinedTable:
-					return errNoTable
-				}
-			}
+	if cp != nil {
+		var consumed bool
+		if consumed, err = consumedAt(tx, *cp); err != nil || consumed {
 			return err
 		}
 	}
+
+	if err = insert(tx); err != nil {
+		return err
+	}
+
+	if cp != nil {
+		err = saveCheckpoint(tx, *cp)
+	}
+	return err
+}
+
+// consumedAt reports whether the messages received at the checkpoint are
+// already consumed. The checkpoint row is locked until the end of the
+// transaction, so the concurrent writers can't consume the same messages.
+func consumedAt(tx *sqlx.Tx, cp consumers.Checkpoint) (bool, error) {
+	q := `SELECT sequence FROM checkpoints WHERE consumer = $1 FOR UPDATE`
+
+	var seq int64
+	if err := tx.QueryRowx(q, cp.Consumer).Scan(&seq); err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, errors.Wrap(errRetrieveCheckpoint, err)
+	}
+	return uint64(seq) >= cp.Sequence, nil
+}
+
+func saveCheckpoint(tx *sqlx.Tx, cp consumers.Checkpoint) error {
+	q := `INSERT INTO checkpoints (consumer, sequence, updated_at) VALUES ($1, $2, $3)
+          ON CONFLICT (consumer) DO UPDATE SET sequence = EXCLUDED.sequence, updated_at = EXCLUDED.updated_at`
+
+	if _, err := tx.Exec(q, cp.Consumer, int64(cp.Sequence), time.Now()); err != nil {
+		return errors.Wrap(errSaveCheckpoint, err)
+	}
 	return nil
 }
 
@@ -164,6 +271,9 @@ func (pr postgresRepo) createTable(name string) error {
                         subtopic      VARCHAR(254),
                         publisher     VARCHAR(254),
                         protocol      TEXT,
+                        adapter       TEXT NOT NULL DEFAULT '',
+                        trace_id      TEXT NOT NULL DEFAULT '',
+                        hops          INTEGER NOT NULL DEFAULT 0,
                         payload       JSONB,
                         PRIMARY KEY (id)
                     )`
@@ -173,6 +283,20 @@ func (pr postgresRepo) createTable(name string) error {
 	return err
 }
 
+// addLineage adds the lineage columns to the JSON messages table created
+// before the messages carried the lineage.
+func (pr postgresRepo) addLineage(name string) error {
+	q := `ALTER TABLE %s ADD COLUMN IF NOT EXISTS adapter TEXT NOT NULL DEFAULT '',
+          ADD COLUMN IF NOT EXISTS trace_id TEXT NOT NULL DEFAULT '',
+          ADD COLUMN IF NOT EXISTS hops INTEGER NOT NULL DEFAULT 0`
+	q = fmt.Sprintf(q, name)
+
+	if _, err := pr.db.Exec(q); err != nil {
+		return errors.Wrap(errSaveMessage, err)
+	}
+	return nil
+}
+
 type senmlMessage struct {
 	senml.Message
 	ID string `db:"id"`
@@ -185,6 +309,9 @@ type jsonMessage struct {
 	Subtopic  string `db:"subtopic"`
 	Publisher string `db:"publisher"`
 	Protocol  string `db:"protocol"`
+	Adapter   string `db:"adapter"`
+	TraceID   string `db:"trace_id"`
+	Hops      uint32 `db:"hops"`
 	Payload   []byte `db:"payload"`
 }
 
@@ -210,6 +337,9 @@ func toJSONMessage(msg mfjson.Message) (jsonMessage, error) {
 		Subtopic:  msg.Subtopic,
 		Publisher: msg.Publisher,
 		Protocol:  msg.Protocol,
+		Adapter:   msg.Adapter,
+		TraceID:   msg.TraceID,
+		Hops:      msg.Hops,
 		Payload:   data,
 	}
 
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/mainflux/mainflux/consumers"
 	"github.com/mainflux/mainflux/consumers/writers/postgres"
 	"github.com/mainflux/mainflux/pkg/transformers/json"
 	"github.com/mainflux/mainflux/pkg/transformers/senml"
@@ -111,3 +112,107 @@ func TestSaveJSON(t *testing.T) {
 	err = repo.Consume(msgs)
 	assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
 }
+
+func TestSaveJSONEvolution(t *testing.T) {
+	repo := postgres.New(db, postgres.WithEvolution(postgres.ColumnsEvolution))
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	msg := json.Message{
+		Channel:  chid.String(),
+		Created:  time.Now().Unix(),
+		Protocol: "http",
+		Payload: map[string]interface{}{
+			"temperature": 21.5,
+			"status":      "ok",
+			"nested":      map[string]interface{}{"field": "value"},
+			"Invalid-Key": 1.0,
+			"channel":     "overridden",
+		},
+	}
+	err = repo.Consume(json.Messages{Format: "evolved_json", Data: []json.Message{msg}})
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	// The value of the other type is kept only in the payload.
+	msg.Payload = map[string]interface{}{"temperature": "hot", "online": true}
+	err = repo.Consume(json.Messages{Format: "evolved_json", Data: []json.Message{msg}})
+	require.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	var columns []string
+	err = db.Select(&columns, `SELECT column_name FROM information_schema.columns
+	                           WHERE table_name = 'evolved_json' ORDER BY column_name`)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	for _, col := range []string{"temperature", "status", "nested", "online"} {
+		assert.Contains(t, columns, col, fmt.Sprintf("expected column %s to be added", col))
+	}
+	assert.NotContains(t, columns, "Invalid-Key", "expected invalid field to stay in payload")
+
+	var temps []*float64
+	err = db.Select(&temps, `SELECT temperature FROM evolved_json WHERE channel = $1 ORDER BY online NULLS FIRST`, chid.String())
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	require.Len(t, temps, 2, "expected two stored messages")
+	assert.Equal(t, 21.5, *temps[0], "expected temperature column to be populated")
+	assert.Nil(t, temps[1], "expected mismatched temperature to be NULL")
+}
+
+func TestConsumeAt(t *testing.T) {
+	repo := postgres.NewCheckpointConsumer(db)
+
+	chid, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	name := fmt.Sprintf("writer-%s", chid)
+
+	msg := senml.Message{
+		Channel: chid.String(),
+		Name:    "temp",
+		Value:   &v,
+		Time:    float64(time.Now().Unix()),
+	}
+
+	cases := []struct {
+		desc  string
+		seq   uint64
+		count int
+		last  uint64
+	}{
+		{
+			desc:  "consume messages at the first checkpoint",
+			seq:   1,
+			count: 1,
+			last:  1,
+		},
+		{
+			desc:  "consume messages at the next checkpoint",
+			seq:   5,
+			count: 2,
+			last:  5,
+		},
+		{
+			desc:  "consume redelivered messages",
+			seq:   5,
+			count: 2,
+			last:  5,
+		},
+		{
+			desc:  "consume messages at the past checkpoint",
+			seq:   3,
+			count: 2,
+			last:  5,
+		},
+	}
+
+	for _, tc := range cases {
+		err := repo.ConsumeAt([]senml.Message{msg}, consumers.Checkpoint{Consumer: name, Sequence: tc.seq})
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+
+		var count int
+		err = db.Get(&count, "SELECT COUNT(*) FROM messages WHERE channel = $1", chid.String())
+		require.Nil(t, err, fmt.Sprintf("%s: got unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.count, count, fmt.Sprintf("%s: expected %d messages got %d", tc.desc, tc.count, count))
+
+		last, err := repo.Checkpoint(name)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s", tc.desc, err))
+		assert.Equal(t, tc.last, last, fmt.Sprintf("%s: expected checkpoint %d got %d", tc.desc, tc.last, last))
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+)
+
+// EvolutionPolicy represents the way the JSON messages tables evolve when
+// the messages carry the new payload fields.
+type EvolutionPolicy string
+
+const (
+	// NoEvolution keeps the payload fields only in the payload column.
+	NoEvolution EvolutionPolicy = "none"
+
+	// ColumnsEvolution adds the column for each new top-level payload
+	// field, along with keeping the field in the payload column.
+	ColumnsEvolution EvolutionPolicy = "columns"
+)
+
+// maxEvolvedColumns is the maximum number of the columns added to the table,
+// so that the devices sending the arbitrary fields can't blow up the table.
+const maxEvolvedColumns = 100
+
+// The types of the evolved columns, as reported by information_schema.
+const (
+	doubleType  = "double precision"
+	textType    = "text"
+	booleanType = "boolean"
+	jsonbType   = "jsonb"
+)
+
+var (
+	// ErrEvolutionPolicy indicates the unknown schema evolution policy.
+	ErrEvolutionPolicy = errors.New("unknown schema evolution policy")
+
+	errEvolveTable = errors.New("failed to evolve messages table")
+
+	// fieldRegexp matches the fields usable as the column names without
+	// quoting, so the field names differing only in case can't collide.
+	fieldRegexp = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+	// baseColumns are the columns of the JSON messages table which the
+	// payload fields can't evolve into.
+	baseColumns = map[string]bool{
+		"id":        true,
+		"created":   true,
+		"channel":   true,
+		"subtopic":  true,
+		"publisher": true,
+		"protocol":  true,
+		"adapter":   true,
+		"trace_id":  true,
+		"hops":      true,
+		"payload":   true,
+	}
+)
+
+// ParseEvolutionPolicy returns the policy of the given name. The empty name
+// stands for NoEvolution.
+func ParseEvolutionPolicy(name string) (EvolutionPolicy, error) {
+	switch p := EvolutionPolicy(name); p {
+	case "":
+		return NoEvolution, nil
+	case NoEvolution, ColumnsEvolution:
+		return p, nil
+	default:
+		return "", errors.Wrap(ErrEvolutionPolicy, errors.New(name))
+	}
+}
+
+// evolvedColumns returns the columns of the table added for the payload
+// fields, mapped to their types. The missing table has no columns.
+func (pr postgresRepo) evolvedColumns(table string) (map[string]string, bool, error) {
+	q := `SELECT column_name, data_type FROM information_schema.columns
+          WHERE table_schema = current_schema() AND table_name = $1`
+
+	rows, err := pr.db.Queryx(q, strings.ToLower(table))
+	if err != nil {
+		return nil, false, errors.Wrap(errEvolveTable, err)
+	}
+	defer rows.Close()
+
+	cols := map[string]string{}
+	exists := false
+	for rows.Next() {
+		var name, typ string
+		if err := rows.Scan(&name, &typ); err != nil {
+			return nil, false, errors.Wrap(errEvolveTable, err)
+		}
+		exists = true
+		if !baseColumns[name] {
+			cols[name] = typ
+		}
+	}
+	return cols, exists, rows.Err()
+}
+
+// evolve adds the nullable columns for the new payload fields of the
+// messages. The columns are never altered or dropped, so the existing rows
+// and readers are left intact. The fields which aren't valid column names,
+// whose type is unknown, or which exceed the column limit, are kept only in
+// the payload.
+func (pr postgresRepo) evolve(msgs mfjson.Messages) (map[string]string, error) {
+	cols, exists, err := pr.evolvedColumns(msgs.Format)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if err := pr.createTable(msgs.Format); err != nil {
+			return nil, errors.Wrap(errEvolveTable, err)
+		}
+	}
+
+	added := map[string]string{}
+	for _, m := range msgs.Data {
+		for field, v := range m.Payload {
+			if _, ok := cols[field]; ok || baseColumns[field] || !fieldRegexp.MatchString(field) {
+				continue
+			}
+			if _, ok := added[field]; ok {
+				continue
+			}
+			if typ := columnType(v); typ != "" && len(cols)+len(added) < maxEvolvedColumns {
+				added[field] = typ
+			}
+		}
+	}
+
+	for field, typ := range added {
+		q := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s`, msgs.Format, pq.QuoteIdentifier(field), typ)
+		if _, err := pr.db.Exec(q); err != nil {
+			return nil, errors.Wrap(errEvolveTable, err)
+		}
+	}
+
+	// The concurrent writer may have added the same column with the other
+	// type, so the types are read back from the table.
+	if len(added) > 0 {
+		if cols, _, err = pr.evolvedColumns(msgs.Format); err != nil {
+			return nil, err
+		}
+	}
+	return cols, nil
+}
+
+func (pr postgresRepo) insertEvolvedJSON(msgs mfjson.Messages, cols map[string]string, cp *consumers.Checkpoint) error {
+	names := make([]string, 0, len(cols))
+	for name := range cols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	columns := []string{"id", "channel", "created", "subtopic", "publisher", "protocol", "adapter", "trace_id", "hops", "payload"}
+	params := make([]string, len(columns))
+	for i, c := range columns {
+		params[i] = ":" + c
+	}
+	for i, name := range names {
+		columns = append(columns, pq.QuoteIdentifier(name))
+		params = append(params, fmt.Sprintf(":field_%d", i))
+	}
+	q := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s);`, msgs.Format, strings.Join(columns, ", "), strings.Join(params, ", "))
+
+	return pr.transact(cp, func(tx *sqlx.Tx) error {
+		for _, m := range msgs.Data {
+			dbmsg, err := toJSONMessage(m)
+			if err != nil {
+				return errors.Wrap(errSaveMessage, err)
+			}
+			args := map[string]interface{}{
+				"id":        dbmsg.ID,
+				"channel":   dbmsg.Channel,
+				"created":   dbmsg.Created,
+				"subtopic":  dbmsg.Subtopic,
+				"publisher": dbmsg.Publisher,
+				"protocol":  dbmsg.Protocol,
+				"adapter":   dbmsg.Adapter,
+				"trace_id":  dbmsg.TraceID,
+				"hops":      dbmsg.Hops,
+				"payload":   dbmsg.Payload,
+			}
+			for i, name := range names {
+				args[fmt.Sprintf("field_%d", i)] = columnValue(m.Payload[name], cols[name])
+			}
+			if _, err := tx.NamedExec(q, args); err != nil {
+				pqErr, ok := err.(*pq.Error)
+				if ok {
+					switch pqErr.Code.Name() {
+					case errInvalid:
+						return errors.Wrap(errSaveMessage, errInvalidMessage)
+					case errUndefinedColumn:
+						return errNoLineage
+					}
+				}
+				return errors.Wrap(errSaveMessage, err)
+			}
+		}
+		return nil
+	})
+}
+
+// columnType returns the type of the column the payload value is stored
+// in, or the empty string if the value can't evolve into the column.
+func columnType(v interface{}) string {
+	switch v.(type) {
+	case float64, float32, int, int64, int32:
+		return doubleType
+	case string:
+		return textType
+	case bool:
+		return booleanType
+	case map[string]interface{}, []interface{}:
+		return jsonbType
+	default:
+		return ""
+	}
+}
+
+// columnValue returns the payload value stored in the column of the given
+// type. The value of the other type is stored as NULL, and it's kept only
+// in the payload.
+func columnValue(v interface{}, typ string) interface{} {
+	if v == nil || columnType(v) != typ {
+		return nil
+	}
+	if typ == jsonbType {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		return b
+	}
+	return v
+}
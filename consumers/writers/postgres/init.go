@@ -7,34 +7,46 @@ import (
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // required for SQL access
+	"github.com/lib/pq" // required for SQL access
 	migrate "github.com/rubenv/sql-migrate"
 )
 
 // Config defines the options that are used when connecting to a PostgreSQL instance
 type Config struct {
-	Host        string
-	Port        string
-	User        string
-	Pass        string
-	Name        string
-	SSLMode     string
-	SSLCert     string
-	SSLKey      string
-	SSLRootCert string
+	Host        string `toml:"host"`
+	Port        string `toml:"port"`
+	User        string `toml:"user"`
+	Pass        string `toml:"pass"`
+	Name        string `toml:"name"`
+	Schema      string `toml:"schema"`
+	SSLMode     string `toml:"ssl_mode"`
+	SSLCert     string `toml:"ssl_cert"`
+	SSLKey      string `toml:"ssl_key"`
+	SSLRootCert string `toml:"ssl_root_cert"`
 }
 
 // Connect creates a connection to the PostgreSQL instance and applies any
-// unapplied database migrations. A non-nil error is returned to indicate
+// unapplied database migrations. If the schema is set, it is created when
+// missing and used as the search path of the connection, so the messages
+// tables are isolated within it. A non-nil error is returned to indicate
 // failure.
 func Connect(cfg Config) (*sqlx.DB, error) {
 	url := fmt.Sprintf("host=%s port=%s user=%s dbname=%s password=%s sslmode=%s sslcert=%s sslkey=%s sslrootcert=%s", cfg.Host, cfg.Port, cfg.User, cfg.Name, cfg.Pass, cfg.SSLMode, cfg.SSLCert, cfg.SSLKey, cfg.SSLRootCert)
+	if cfg.Schema != "" {
+		url = fmt.Sprintf("%s search_path=%s", url, cfg.Schema)
+	}
 
 	db, err := sqlx.Open("postgres", url)
 	if err != nil {
 		return nil, err
 	}
 
+	if cfg.Schema != "" {
+		if _, err := db.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", pq.QuoteIdentifier(cfg.Schema))); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := migrateDB(db); err != nil {
 		return nil, err
 	}
@@ -70,6 +82,46 @@ func migrateDB(db *sqlx.DB) error {
 					"DROP TABLE messages",
 				},
 			},
+			{
+				Id: "messages_2",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS online_migrations (
+                        id          TEXT,
+                        state       TEXT NOT NULL,
+                        cursor      TEXT,
+                        updated_at  TIMESTAMPTZ NOT NULL,
+                        PRIMARY KEY (id)
+                    )`,
+				},
+				Down: []string{
+					"DROP TABLE online_migrations",
+				},
+			},
+			{
+				Id: "messages_3",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS checkpoints (
+                        consumer    TEXT,
+                        sequence    BIGINT NOT NULL,
+                        updated_at  TIMESTAMPTZ NOT NULL,
+                        PRIMARY KEY (consumer)
+                    )`,
+				},
+				Down: []string{
+					"DROP TABLE checkpoints",
+				},
+			},
+			{
+				Id: "messages_4",
+				Up: []string{
+					`ALTER TABLE messages ADD COLUMN IF NOT EXISTS adapter TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE messages ADD COLUMN IF NOT EXISTS trace_id TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE messages ADD COLUMN IF NOT EXISTS hops INTEGER NOT NULL DEFAULT 0`,
+				},
+				Down: []string{
+					"ALTER TABLE messages DROP COLUMN adapter, DROP COLUMN trace_id, DROP COLUMN hops",
+				},
+			},
 		},
 	}
 
@@ -0,0 +1,334 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// Online migration states. The migration moves through the states in the
+// given order and is resumed from the last recorded state.
+const (
+	StateCreated   = "created"
+	StateDualWrite = "dual_write"
+	StateBackfill  = "backfilled"
+	StateSwapped   = "swapped"
+)
+
+var (
+	// ErrMigrationLocked indicates that the online migrations are run by
+	// another writer instance.
+	ErrMigrationLocked = errors.New("online migrations are run by another instance")
+
+	errMigrationState = errors.New("unexpected online migration state")
+)
+
+// OnlineMigrations contains the online migrations of the messages tables.
+// They are applied after the regular migrations without stopping the
+// ingestion of messages.
+var OnlineMigrations = []OnlineMigration{}
+
+// OnlineMigration represents the schema change applied while the messages
+// are written to the table. The new table is created alongside the migrated
+// one, the rows inserted into the migrated table are written to the new one
+// as well, the existing rows are copied in batches and, finally, the new
+// table replaces the migrated one. The migrated table is kept as
+// <table>_<id>_old.
+type OnlineMigration struct {
+	// ID uniquely identifies the migration.
+	ID string
+
+	// Table is the migrated table.
+	Table string
+
+	// Target is the name of the new table.
+	Target string
+
+	// Create contains the statements creating the new table.
+	Create []string
+
+	// Columns lists the columns of the new table written by the migration.
+	Columns []string
+
+	// Values lists the expressions over the migrated table row, aliased as
+	// src, computing the respective columns. The values of the columns with
+	// the same name are copied if omitted.
+	Values []string
+
+	// Key is the unique column the rows are copied in the order of.
+	// Defaults to "id".
+	Key string
+}
+
+// Migrator applies the online migrations.
+type Migrator interface {
+	// Migrate applies the migrations, resuming the ones interrupted by the
+	// previous runs. Only one instance applies the migrations at a time,
+	// the others get ErrMigrationLocked.
+	Migrate(ctx context.Context, migrations []OnlineMigration) error
+}
+
+var _ Migrator = (*migrator)(nil)
+
+type migrator struct {
+	db       *sqlx.DB
+	batch    int
+	interval time.Duration
+}
+
+// NewMigrator returns the online migrator copying batch rows at a time and
+// waiting for the interval between the batches to spare the database.
+func NewMigrator(db *sqlx.DB, batch int, interval time.Duration) Migrator {
+	return migrator{
+		db:       db,
+		batch:    batch,
+		interval: interval,
+	}
+}
+
+func (mr migrator) Migrate(ctx context.Context, migrations []OnlineMigration) error {
+	if len(migrations) == 0 {
+		return nil
+	}
+
+	// Advisory lock is held by the session, so all the steps have to use
+	// the same connection.
+	conn, err := mr.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(hashtext('online_migrations'))").Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return ErrMigrationLocked
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock(hashtext('online_migrations'))")
+
+	for _, m := range migrations {
+		if err := mr.migrate(ctx, conn, m); err != nil {
+			return errors.Wrap(errors.New(m.ID), err)
+		}
+	}
+
+	return nil
+}
+
+func (mr migrator) migrate(ctx context.Context, conn *sql.Conn, m OnlineMigration) error {
+	if m.Key == "" {
+		m.Key = "id"
+	}
+
+	state, cursor, err := loadState(ctx, conn, m.ID)
+	if err != nil {
+		return err
+	}
+
+	for state != StateSwapped {
+		switch state {
+		case "":
+			err = mr.create(ctx, conn, m)
+			state = StateCreated
+		case StateCreated:
+			err = mr.dualWrite(ctx, conn, m)
+			state = StateDualWrite
+		case StateDualWrite:
+			err = mr.backfill(ctx, conn, m, cursor)
+			state = StateBackfill
+		case StateBackfill:
+			err = mr.swap(ctx, conn, m)
+			state = StateSwapped
+		default:
+			return errors.Wrap(errMigrationState, errors.New(state))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// create creates the new table.
+func (mr migrator) create(ctx context.Context, conn *sql.Conn, m OnlineMigration) error {
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		for _, q := range m.Create {
+			if _, err := tx.ExecContext(ctx, q); err != nil {
+				return err
+			}
+		}
+		return saveState(ctx, tx, m.ID, StateCreated, nil)
+	})
+}
+
+// dualWrite installs the trigger writing the rows inserted into the migrated
+// table to the new table as well. Since the trigger is installed before the
+// backfill starts, every row is either copied by the backfill or by the
+// trigger.
+func (mr migrator) dualWrite(ctx context.Context, conn *sql.Conn, m OnlineMigration) error {
+	fn := pq.QuoteIdentifier(dualWriteName(m))
+	fnq := fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger AS $$
+                        BEGIN
+                            INSERT INTO %s (%s) SELECT %s FROM (SELECT (NEW).*) AS src ON CONFLICT DO NOTHING;
+                            RETURN NEW;
+                        END;
+                        $$ LANGUAGE plpgsql`, fn, pq.QuoteIdentifier(m.Target), columns(m), values(m))
+	tq := fmt.Sprintf("CREATE TRIGGER %s AFTER INSERT ON %s FOR EACH ROW EXECUTE PROCEDURE %s()",
+		fn, pq.QuoteIdentifier(m.Table), fn)
+
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, fnq); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, tq); err != nil {
+			return err
+		}
+		return saveState(ctx, tx, m.ID, StateDualWrite, nil)
+	})
+}
+
+// backfill copies the existing rows to the new table in batches ordered by
+// the key, recording the last copied key so the backfill can be resumed.
+func (mr migrator) backfill(ctx context.Context, conn *sql.Conn, m OnlineMigration, cursor *string) error {
+	key := pq.QuoteIdentifier(m.Key)
+	q := `WITH batch AS (SELECT * FROM %s %s ORDER BY %s LIMIT %d),
+          copied AS (INSERT INTO %s (%s) SELECT %s FROM batch AS src ON CONFLICT DO NOTHING)
+          SELECT %s::text FROM batch ORDER BY %s DESC LIMIT 1`
+
+	for {
+		var where string
+		var args []interface{}
+		if cursor != nil {
+			where = fmt.Sprintf("WHERE %s > $1", key)
+			args = append(args, *cursor)
+		}
+		bq := fmt.Sprintf(q, pq.QuoteIdentifier(m.Table), where, key, mr.batch,
+			pq.QuoteIdentifier(m.Target), columns(m), values(m), key, key)
+
+		var last string
+		err := inTx(ctx, conn, func(tx *sql.Tx) error {
+			if err := tx.QueryRowContext(ctx, bq, args...).Scan(&last); err != nil {
+				return err
+			}
+			return saveState(ctx, tx, m.ID, StateDualWrite, &last)
+		})
+		switch err {
+		case nil:
+			cursor = &last
+		case sql.ErrNoRows:
+			return saveState(ctx, conn, m.ID, StateBackfill, cursor)
+		default:
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(mr.interval):
+		}
+	}
+}
+
+// swap replaces the migrated table with the new one. The migrated table is
+// locked for the duration of the swap, so no rows are missed.
+func (mr migrator) swap(ctx context.Context, conn *sql.Conn, m OnlineMigration) error {
+	fn := pq.QuoteIdentifier(dualWriteName(m))
+	table := pq.QuoteIdentifier(m.Table)
+	stmts := []string{
+		fmt.Sprintf("LOCK TABLE %s IN ACCESS EXCLUSIVE MODE", table),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS %s ON %s", fn, table),
+		fmt.Sprintf("DROP FUNCTION IF EXISTS %s()", fn),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", table, pq.QuoteIdentifier(fmt.Sprintf("%s_%s_old", m.Table, m.ID))),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", pq.QuoteIdentifier(m.Target), table),
+	}
+
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		for _, q := range stmts {
+			if _, err := tx.ExecContext(ctx, q); err != nil {
+				return err
+			}
+		}
+		return saveState(ctx, tx, m.ID, StateSwapped, nil)
+	})
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func loadState(ctx context.Context, conn *sql.Conn, id string) (string, *string, error) {
+	var state string
+	var cursor sql.NullString
+	q := `SELECT state, cursor FROM online_migrations WHERE id = $1`
+	switch err := conn.QueryRowContext(ctx, q, id).Scan(&state, &cursor); err {
+	case nil:
+	case sql.ErrNoRows:
+		return "", nil, nil
+	default:
+		return "", nil, err
+	}
+	if !cursor.Valid {
+		return state, nil, nil
+	}
+	return state, &cursor.String, nil
+}
+
+func saveState(ctx context.Context, e execer, id, state string, cursor *string) error {
+	q := `INSERT INTO online_migrations (id, state, cursor, updated_at) VALUES ($1, $2, $3, now())
+          ON CONFLICT (id) DO UPDATE SET state = $2, cursor = $3, updated_at = now()`
+	_, err := e.ExecContext(ctx, q, id, state, cursor)
+	return err
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if txErr := tx.Rollback(); txErr != nil {
+				err = errors.Wrap(err, errors.Wrap(errTransRollback, txErr))
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	return fn(tx)
+}
+
+func dualWriteName(m OnlineMigration) string {
+	return fmt.Sprintf("%s_%s_dual_write", m.Table, m.ID)
+}
+
+func columns(m OnlineMigration) string {
+	cols := make([]string, len(m.Columns))
+	for i, c := range m.Columns {
+		cols[i] = pq.QuoteIdentifier(c)
+	}
+	return strings.Join(cols, ", ")
+}
+
+func values(m OnlineMigration) string {
+	if len(m.Values) == 0 {
+		cols := make([]string, len(m.Columns))
+		for i, c := range m.Columns {
+			cols[i] = "src." + pq.QuoteIdentifier(c)
+		}
+		return strings.Join(cols, ", ")
+	}
+	return strings.Join(m.Values, ", ")
+}
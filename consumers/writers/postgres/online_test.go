@@ -0,0 +1,53 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/consumers/writers/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnlineMigration(t *testing.T) {
+	_, err := db.Exec(`CREATE TABLE migrated (id UUID, name TEXT, PRIMARY KEY (id))`)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	rows := 25
+	for i := 0; i < rows; i++ {
+		id, err := uuid.NewV4()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		_, err = db.Exec(`INSERT INTO migrated (id, name) VALUES ($1, $2)`, id.String(), fmt.Sprintf("name-%d", i))
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	m := postgres.OnlineMigration{
+		ID:      "migrated_1",
+		Table:   "migrated",
+		Target:  "migrated_new",
+		Create:  []string{`CREATE TABLE migrated_new (id UUID, name TEXT, label TEXT, PRIMARY KEY (id))`},
+		Columns: []string{"id", "name", "label"},
+		Values:  []string{"src.id", "src.name", "upper(src.name)"},
+	}
+
+	mr := postgres.NewMigrator(db, 10, 0)
+	err = mr.Migrate(context.Background(), []postgres.OnlineMigration{m})
+	assert.Nil(t, err, fmt.Sprintf("online migration: expected no error got %s\n", err))
+
+	var count int
+	err = db.QueryRow(`SELECT count(*) FROM migrated WHERE label = upper(name)`).Scan(&count)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, rows, count, fmt.Sprintf("online migration: expected %d migrated rows got %d\n", rows, count))
+
+	err = db.QueryRow(`SELECT count(*) FROM migrated_migrated_1_old`).Scan(&count)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, rows, count, fmt.Sprintf("online migration: expected %d retired rows got %d\n", rows, count))
+
+	err = mr.Migrate(context.Background(), []postgres.OnlineMigration{m})
+	assert.Nil(t, err, fmt.Sprintf("applied online migration: expected no error got %s\n", err))
+}
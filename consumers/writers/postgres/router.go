@@ -0,0 +1,129 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfjson "github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/pelletier/go-toml"
+)
+
+var (
+	// ErrUnknownRoute indicates that a channel is routed to a database
+	// which is not defined in the routing configuration.
+	ErrUnknownRoute = errors.New("channel routed to an unknown database")
+
+	errOpenRoutingFile  = errors.New("unable to open routing configuration file")
+	errParseRoutingFile = errors.New("unable to parse routing configuration file")
+)
+
+// RoutingConfig maps channels to the databases (or schemas) their messages
+// are written to. Channels that are not listed are written to the default
+// database of the writer.
+type RoutingConfig struct {
+	Databases map[string]Config `toml:"databases"`
+	Channels  map[string]string `toml:"channels"`
+}
+
+type routingFile struct {
+	Routing RoutingConfig `toml:"routing"`
+}
+
+// LoadRoutingConfig reads the `routing` section of the TOML file located
+// at the given path. The missing file configures no routing.
+func LoadRoutingConfig(path string) (RoutingConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return RoutingConfig{}, nil
+	}
+	if err != nil {
+		return RoutingConfig{}, errors.Wrap(errOpenRoutingFile, err)
+	}
+
+	var rf routingFile
+	if err := toml.Unmarshal(data, &rf); err != nil {
+		return RoutingConfig{}, errors.Wrap(errParseRoutingFile, err)
+	}
+
+	for _, db := range rf.Routing.Channels {
+		if _, ok := rf.Routing.Databases[db]; !ok {
+			return RoutingConfig{}, errors.Wrap(ErrUnknownRoute, errors.New(db))
+		}
+	}
+
+	return rf.Routing, nil
+}
+
+var _ consumers.Consumer = (*router)(nil)
+
+type router struct {
+	def      consumers.Consumer
+	targets  map[string]consumers.Consumer
+	channels map[string]string
+}
+
+// NewRouter returns a consumer that splits received messages by channel and
+// writes each part using the target consumer the channel is routed to.
+// Targets are keyed by database name and channels maps channel IDs to
+// those names. Messages of unrouted channels are passed to the default
+// consumer.
+func NewRouter(def consumers.Consumer, targets map[string]consumers.Consumer, channels map[string]string) consumers.Consumer {
+	return &router{
+		def:      def,
+		targets:  targets,
+		channels: channels,
+	}
+}
+
+func (r router) Consume(message interface{}) error {
+	switch m := message.(type) {
+	case mfjson.Messages:
+		parts := map[string][]mfjson.Message{}
+		var order []string
+		for _, msg := range m.Data {
+			t := r.channels[msg.Channel]
+			if _, ok := parts[t]; !ok {
+				order = append(order, t)
+			}
+			parts[t] = append(parts[t], msg)
+		}
+		for _, t := range order {
+			msgs := mfjson.Messages{Data: parts[t], Format: m.Format}
+			if err := r.target(t).Consume(msgs); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []senml.Message:
+		parts := map[string][]senml.Message{}
+		var order []string
+		for _, msg := range m {
+			t := r.channels[msg.Channel]
+			if _, ok := parts[t]; !ok {
+				order = append(order, t)
+			}
+			parts[t] = append(parts[t], msg)
+		}
+		for _, t := range order {
+			if err := r.target(t).Consume(parts[t]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return r.def.Consume(message)
+	}
+}
+
+func (r router) target(name string) consumers.Consumer {
+	if c, ok := r.targets[name]; ok {
+		return c
+	}
+	return r.def
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"github.com/mainflux/mainflux/consumers"
+	"github.com/mainflux/mainflux/consumers/writers/postgres"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const routedSchema = "routed"
+
+func TestRouteSenml(t *testing.T) {
+	cfg := dbConfig
+	cfg.Schema = routedSchema
+	routedDB, err := postgres.Connect(cfg)
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	defer routedDB.Close()
+
+	defChan, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	routedChan, err := uuid.NewV4()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	targets := map[string]consumers.Consumer{routedSchema: postgres.New(routedDB)}
+	channels := map[string]string{routedChan.String(): routedSchema}
+	repo := postgres.NewRouter(postgres.New(db), targets, channels)
+
+	var msgs []senml.Message
+	for i := 0; i < msgsNum; i++ {
+		msg := senml.Message{Channel: defChan.String(), Value: &v, Time: float64(i)}
+		if i%2 == 0 {
+			msg.Channel = routedChan.String()
+		}
+		msgs = append(msgs, msg)
+	}
+
+	err = repo.Consume(msgs)
+	assert.Nil(t, err, fmt.Sprintf("expected no error got %s\n", err))
+
+	cases := []struct {
+		desc    string
+		query   string
+		channel string
+		count   int
+	}{
+		{
+			desc:    "count messages of the routed channel in the routed schema",
+			query:   fmt.Sprintf("SELECT COUNT(*) FROM %s.messages WHERE channel = $1", routedSchema),
+			channel: routedChan.String(),
+			count:   msgsNum / 2,
+		},
+		{
+			desc:    "count messages of the routed channel in the default schema",
+			query:   "SELECT COUNT(*) FROM public.messages WHERE channel = $1",
+			channel: routedChan.String(),
+			count:   0,
+		},
+		{
+			desc:    "count messages of the unrouted channel in the default schema",
+			query:   "SELECT COUNT(*) FROM public.messages WHERE channel = $1",
+			channel: defChan.String(),
+			count:   msgsNum / 2,
+		},
+	}
+
+	for _, tc := range cases {
+		var count int
+		err := db.Get(&count, tc.query, tc.channel)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s\n", tc.desc, err))
+		assert.Equal(t, tc.count, count, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.count, count))
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/desiredstate"
+)
+
+func planEndpoint(svc desiredstate.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(manifestReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.Plan(ctx, req.token, req.manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		return newPlanRes(p), nil
+	}
+}
+
+func applyEndpoint(svc desiredstate.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(manifestReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.Apply(ctx, req.token, req.manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		return newPlanRes(p), nil
+	}
+}
+
+func driftEndpoint(svc desiredstate.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(stateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		p, err := svc.Drift(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		return newPlanRes(p), nil
+	}
+}
+
+func stateEndpoint(svc desiredstate.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(stateReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		st, err := svc.State(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		return stateRes{st}, nil
+	}
+}
+
+func newPlanRes(p desiredstate.Plan) planRes {
+	if p.Changes == nil {
+		p.Changes = []desiredstate.Change{}
+	}
+
+	return planRes{
+		Plan:   p,
+		InSync: p.Empty(),
+	}
+}
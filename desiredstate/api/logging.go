@@ -0,0 +1,79 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/desiredstate"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var _ desiredstate.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    desiredstate.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc desiredstate.Service, logger log.Logger) desiredstate.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Plan(ctx context.Context, token string, m desiredstate.Manifest) (p desiredstate.Plan, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method plan with %d changes took %s to complete", len(p.Changes), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Plan(ctx, token, m)
+}
+
+func (lm *loggingMiddleware) Apply(ctx context.Context, token string, m desiredstate.Manifest) (p desiredstate.Plan, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method apply with %d applied changes took %s to complete", len(p.Changes), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Apply(ctx, token, m)
+}
+
+func (lm *loggingMiddleware) Drift(ctx context.Context, token string) (p desiredstate.Plan, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method drift with %d drifted changes took %s to complete", len(p.Changes), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Drift(ctx, token)
+}
+
+func (lm *loggingMiddleware) State(ctx context.Context, token string) (st desiredstate.State, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method state took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.State(ctx, token)
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/desiredstate"
+)
+
+var _ desiredstate.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     desiredstate.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(svc desiredstate.Service, counter metrics.Counter, latency metrics.Histogram) desiredstate.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Plan(ctx context.Context, token string, m desiredstate.Manifest) (desiredstate.Plan, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "plan").Add(1)
+		ms.latency.With("method", "plan").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Plan(ctx, token, m)
+}
+
+func (ms *metricsMiddleware) Apply(ctx context.Context, token string, m desiredstate.Manifest) (desiredstate.Plan, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "apply").Add(1)
+		ms.latency.With("method", "apply").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Apply(ctx, token, m)
+}
+
+func (ms *metricsMiddleware) Drift(ctx context.Context, token string) (desiredstate.Plan, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "drift").Add(1)
+		ms.latency.With("method", "drift").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Drift(ctx, token)
+}
+
+func (ms *metricsMiddleware) State(ctx context.Context, token string) (desiredstate.State, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "state").Add(1)
+		ms.latency.With("method", "state").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.State(ctx, token)
+}
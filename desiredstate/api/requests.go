@@ -0,0 +1,31 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/desiredstate"
+
+type manifestReq struct {
+	token    string
+	manifest desiredstate.Manifest
+}
+
+func (req manifestReq) validate() error {
+	if req.token == "" {
+		return desiredstate.ErrUnauthorizedAccess
+	}
+
+	return req.manifest.Validate()
+}
+
+type stateReq struct {
+	token string
+}
+
+func (req stateReq) validate() error {
+	if req.token == "" {
+		return desiredstate.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/desiredstate"
+)
+
+var (
+	_ mainflux.Response = (*planRes)(nil)
+	_ mainflux.Response = (*stateRes)(nil)
+)
+
+type planRes struct {
+	desiredstate.Plan
+	InSync bool `json:"in_sync"`
+}
+
+func (res planRes) Code() int {
+	return http.StatusOK
+}
+
+func (res planRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res planRes) Empty() bool {
+	return false
+}
+
+type stateRes struct {
+	desiredstate.State
+}
+
+func (res stateRes) Code() int {
+	return http.StatusOK
+}
+
+func (res stateRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res stateRes) Empty() bool {
+	return false
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
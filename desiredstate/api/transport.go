@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/desiredstate"
+	"github.com/mainflux/mainflux/pkg/errors"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const contentType = "application/json"
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(tracer opentracing.Tracer, svc desiredstate.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Post("/plan", kithttp.NewServer(
+		kitot.TraceServer(tracer, "plan")(planEndpoint(svc)),
+		decodeManifest,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Post("/apply", kithttp.NewServer(
+		kitot.TraceServer(tracer, "apply")(applyEndpoint(svc)),
+		decodeManifest,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/drift", kithttp.NewServer(
+		kitot.TraceServer(tracer, "drift")(driftEndpoint(svc)),
+		decodeState,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/state", kithttp.NewServer(
+		kitot.TraceServer(tracer, "state")(stateEndpoint(svc)),
+		decodeState,
+		encodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("desired-state"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodeManifest(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := manifestReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req.manifest); err != nil {
+		return nil, errors.Wrap(desiredstate.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeState(_ context.Context, r *http.Request) (interface{}, error) {
+	req := stateReq{token: r.Header.Get("Authorization")}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch {
+	case errors.Contains(err, desiredstate.ErrUnauthorizedAccess):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, desiredstate.ErrMalformedEntity):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	case errors.Contains(err, desiredstate.ErrFailedFetch),
+		errors.Contains(err, desiredstate.ErrFailedApply):
+		w.WriteHeader(http.StatusBadGateway)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if err := json.NewEncoder(w).Encode(errorRes{Err: err.Error()}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
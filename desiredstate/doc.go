@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package desiredstate contains the domain concept definitions needed to
+// support the desired-state service, which reconciles the platform entities
+// with the declarative manifest using the plan/apply workflow.
+package desiredstate
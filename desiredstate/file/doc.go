@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package file contains the applied state repository implementation
+// storing the state of each user to the separate JSON file.
+package file
@@ -0,0 +1,87 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package file
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mainflux/mainflux/desiredstate"
+)
+
+var _ desiredstate.StateRepository = (*stateRepository)(nil)
+
+type stateRepository struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewStateRepository instantiates the state repository storing the files to
+// the given directory, which is created if it doesn't exist.
+func NewStateRepository(dir string) (desiredstate.StateRepository, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &stateRepository{dir: dir}, nil
+}
+
+func (sr *stateRepository) Save(_ context.Context, owner string, st desiredstate.State) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	// Write to the temporary file first so that the state is never left
+	// partially written.
+	tmp, err := ioutil.TempFile(sr.dir, "state-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), sr.path(owner))
+}
+
+func (sr *stateRepository) Retrieve(_ context.Context, owner string) (desiredstate.State, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	data, err := ioutil.ReadFile(sr.path(owner))
+	if os.IsNotExist(err) {
+		return desiredstate.State{}, nil
+	}
+	if err != nil {
+		return desiredstate.State{}, err
+	}
+
+	var st desiredstate.State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return desiredstate.State{}, err
+	}
+
+	return st, nil
+}
+
+func (sr *stateRepository) path(owner string) string {
+	sum := sha256.Sum256([]byte(owner))
+	return filepath.Join(sr.dir, hex.EncodeToString(sum[:])+".json")
+}
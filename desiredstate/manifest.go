@@ -0,0 +1,173 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package desiredstate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+// Reference prefixes used by the policy objects and subjects to refer to the
+// entities declared in the manifest. Any other value is used as the entity ID.
+const (
+	UserRef    = "user:"
+	GroupRef   = "group:"
+	ThingRef   = "thing:"
+	ChannelRef = "channel:"
+)
+
+// Manifest represents the desired state of the platform entities owned by
+// the user. Users are identified by their email and all the other entities
+// by their name, so the names must be unique within the manifest.
+type Manifest struct {
+	Users       []User       `json:"users,omitempty"`
+	Groups      []Group      `json:"groups,omitempty"`
+	Things      []Thing      `json:"things,omitempty"`
+	Channels    []Channel    `json:"channels,omitempty"`
+	Connections []Connection `json:"connections,omitempty"`
+	Policies    []Policy     `json:"policies,omitempty"`
+}
+
+// User represents the desired user.
+type User struct {
+	Email    string                 `json:"email"`
+	Password string                 `json:"password,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Group represents the desired group.
+type Group struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Thing represents the desired thing.
+type Thing struct {
+	Name     string                 `json:"name"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Channel represents the desired channel.
+type Channel struct {
+	Name     string                 `json:"name"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// Connection represents the desired connection of the thing to the channel,
+// both referred to by name.
+type Connection struct {
+	Channel string `json:"channel"`
+	Thing   string `json:"thing"`
+	Role    string `json:"role,omitempty"`
+	Label   string `json:"label,omitempty"`
+}
+
+// Policy represents the actions the subjects are allowed to perform on the
+// object. The object and the subjects are either entity IDs or references
+// to the entities declared in the manifest (e.g. "thing:sensor").
+type Policy struct {
+	Object   string   `json:"object"`
+	Subjects []string `json:"subjects"`
+	Actions  []string `json:"actions"`
+}
+
+// Validate returns an error if the manifest is not valid.
+func (m Manifest) Validate() error {
+	users := make(map[string]bool)
+	for _, u := range m.Users {
+		if u.Email == "" || users[u.Email] {
+			return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid or duplicate user %q", u.Email))
+		}
+		users[u.Email] = true
+	}
+
+	groups := make(map[string]bool)
+	for _, g := range m.Groups {
+		if g.Name == "" || groups[g.Name] {
+			return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid or duplicate group %q", g.Name))
+		}
+		groups[g.Name] = true
+	}
+
+	ths := make(map[string]bool)
+	for _, t := range m.Things {
+		if t.Name == "" || ths[t.Name] {
+			return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid or duplicate thing %q", t.Name))
+		}
+		ths[t.Name] = true
+	}
+
+	chs := make(map[string]bool)
+	for _, c := range m.Channels {
+		if c.Name == "" || chs[c.Name] {
+			return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid or duplicate channel %q", c.Name))
+		}
+		chs[c.Name] = true
+	}
+
+	conns := make(map[string]bool)
+	for _, c := range m.Connections {
+		key := connectionName(c.Channel, c.Thing)
+		if !chs[c.Channel] || !ths[c.Thing] || conns[key] {
+			return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid or duplicate connection %q", key))
+		}
+		meta := things.ConnectionMetadata{Role: c.Role, Label: c.Label}
+		if err := meta.Validate(); err != nil {
+			return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid connection %q role %q", key, c.Role))
+		}
+		conns[key] = true
+	}
+
+	declared := map[string]map[string]bool{
+		UserRef:    users,
+		GroupRef:   groups,
+		ThingRef:   ths,
+		ChannelRef: chs,
+	}
+	for _, p := range m.Policies {
+		if p.Object == "" || len(p.Subjects) == 0 || len(p.Actions) == 0 {
+			return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid policy on object %q", p.Object))
+		}
+		for _, ref := range append([]string{p.Object}, p.Subjects...) {
+			if !validRef(ref, declared) {
+				return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid policy reference %q", ref))
+			}
+		}
+		for _, a := range p.Actions {
+			if a == "" {
+				return errors.Wrap(ErrMalformedEntity, fmt.Errorf("invalid policy action on object %q", p.Object))
+			}
+		}
+	}
+
+	return nil
+}
+
+func validRef(ref string, declared map[string]map[string]bool) bool {
+	if ref == "" {
+		return false
+	}
+	for prefix, names := range declared {
+		if strings.HasPrefix(ref, prefix) {
+			return names[strings.TrimPrefix(ref, prefix)]
+		}
+	}
+	return true
+}
+
+// withoutSecrets returns the copy of the manifest without user passwords,
+// which is safe to be stored as the applied state.
+func (m Manifest) withoutSecrets() Manifest {
+	users := make([]User, len(m.Users))
+	for i, u := range m.Users {
+		u.Password = ""
+		users[i] = u
+	}
+	m.Users = users
+	return m
+}
@@ -0,0 +1,324 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+)
+
+var _ sdk.SDK = (*SDK)(nil)
+
+// SDK is the in-memory platform implementing the SDK methods used by the
+// desired-state service.
+type SDK struct {
+	sdk.SDK
+
+	mu           sync.Mutex
+	counter      int
+	tokens       map[string]string
+	UsersByID    map[string]sdk.User
+	GroupsByID   map[string]sdk.Group
+	ThingsByID   map[string]sdk.Thing
+	ChannelsByID map[string]sdk.Channel
+	Connections  map[string]sdk.ConnectionIDs
+	Policies     map[string]bool
+}
+
+// NewSDK returns the SDK mock. Tokens map the valid tokens to the user IDs.
+func NewSDK(tokens map[string]string) *SDK {
+	return &SDK{
+		tokens:       tokens,
+		UsersByID:    make(map[string]sdk.User),
+		GroupsByID:   make(map[string]sdk.Group),
+		ThingsByID:   make(map[string]sdk.Thing),
+		ChannelsByID: make(map[string]sdk.Channel),
+		Connections:  make(map[string]sdk.ConnectionIDs),
+		Policies:     make(map[string]bool),
+	}
+}
+
+// PolicyKey returns the key of the policy stored to the Policies map.
+func PolicyKey(object, subject, action string) string {
+	return fmt.Sprintf("%s#%s@%s", object, action, subject)
+}
+
+func (s *SDK) id() string {
+	s.counter++
+	return fmt.Sprintf("%03d", s.counter)
+}
+
+func (s *SDK) User(token string) (sdk.User, error) {
+	id, ok := s.tokens[token]
+	if !ok {
+		return sdk.User{}, sdk.ErrUnauthorized
+	}
+	return sdk.User{ID: id}, nil
+}
+
+func (s *SDK) CreateUser(token string, u sdk.User) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, user := range s.UsersByID {
+		if user.Email == u.Email {
+			return "", sdk.ErrFailedCreation
+		}
+	}
+	u.ID = s.id()
+	s.UsersByID[u.ID] = u
+	return u.ID, nil
+}
+
+func (s *SDK) Groups(offset, limit uint64, token string) (sdk.GroupsPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var page sdk.GroupsPage
+	for _, id := range keys(s.GroupsByID) {
+		page.Groups = append(page.Groups, s.GroupsByID[id])
+	}
+	page.Groups = page.Groups[lower(offset, len(page.Groups)):upper(offset, limit, len(page.Groups))]
+	page.Total = uint64(len(s.GroupsByID))
+	return page, nil
+}
+
+func (s *SDK) CreateGroup(g sdk.Group, token string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g.ID = s.id()
+	s.GroupsByID[g.ID] = g
+	return g.ID, nil
+}
+
+func (s *SDK) UpdateGroup(g sdk.Group, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.GroupsByID[g.ID]; !ok {
+		return sdk.ErrFailedUpdate
+	}
+	s.GroupsByID[g.ID] = g
+	return nil
+}
+
+func (s *SDK) DeleteGroup(id, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.GroupsByID[id]; !ok {
+		return sdk.ErrFailedRemoval
+	}
+	delete(s.GroupsByID, id)
+	return nil
+}
+
+func (s *SDK) Things(token string, offset, limit uint64, name string) (sdk.ThingsPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var page sdk.ThingsPage
+	for _, id := range keys(s.ThingsByID) {
+		page.Things = append(page.Things, s.ThingsByID[id])
+	}
+	page.Things = page.Things[lower(offset, len(page.Things)):upper(offset, limit, len(page.Things))]
+	page.Total = uint64(len(s.ThingsByID))
+	return page, nil
+}
+
+func (s *SDK) ThingsByChannel(token, chanID string, offset, limit uint64, connected bool) (sdk.ThingsPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var page sdk.ThingsPage
+	for _, key := range keys(s.Connections) {
+		conn := s.Connections[key]
+		if conn.ChannelIDs[0] == chanID {
+			page.Things = append(page.Things, s.ThingsByID[conn.ThingIDs[0]])
+		}
+	}
+	page.Total = uint64(len(page.Things))
+	page.Things = page.Things[lower(offset, len(page.Things)):upper(offset, limit, len(page.Things))]
+	return page, nil
+}
+
+func (s *SDK) CreateThing(t sdk.Thing, token string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t.ID = s.id()
+	s.ThingsByID[t.ID] = t
+	return t.ID, nil
+}
+
+func (s *SDK) UpdateThing(t sdk.Thing, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ThingsByID[t.ID]; !ok {
+		return sdk.ErrFailedUpdate
+	}
+	s.ThingsByID[t.ID] = t
+	return nil
+}
+
+func (s *SDK) DeleteThing(id, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ThingsByID, id)
+	for key, conn := range s.Connections {
+		if conn.ThingIDs[0] == id {
+			delete(s.Connections, key)
+		}
+	}
+	return nil
+}
+
+func (s *SDK) Channels(token string, offset, limit uint64, name string) (sdk.ChannelsPage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var page sdk.ChannelsPage
+	for _, id := range keys(s.ChannelsByID) {
+		page.Channels = append(page.Channels, s.ChannelsByID[id])
+	}
+	page.Channels = page.Channels[lower(offset, len(page.Channels)):upper(offset, limit, len(page.Channels))]
+	page.Total = uint64(len(s.ChannelsByID))
+	return page, nil
+}
+
+func (s *SDK) CreateChannel(c sdk.Channel, token string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.ID = s.id()
+	s.ChannelsByID[c.ID] = c
+	return c.ID, nil
+}
+
+func (s *SDK) UpdateChannel(c sdk.Channel, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.ChannelsByID[c.ID]; !ok {
+		return sdk.ErrFailedUpdate
+	}
+	s.ChannelsByID[c.ID] = c
+	return nil
+}
+
+func (s *SDK) DeleteChannel(id, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ChannelsByID, id)
+	for key, conn := range s.Connections {
+		if conn.ChannelIDs[0] == id {
+			delete(s.Connections, key)
+		}
+	}
+	return nil
+}
+
+func (s *SDK) Connect(conns sdk.ConnectionIDs, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, chID := range conns.ChannelIDs {
+		for _, thID := range conns.ThingIDs {
+			if _, ok := s.ChannelsByID[chID]; !ok {
+				return sdk.ErrFailedConnect
+			}
+			if _, ok := s.ThingsByID[thID]; !ok {
+				return sdk.ErrFailedConnect
+			}
+			s.Connections[chID+"/"+thID] = sdk.ConnectionIDs{
+				ChannelIDs: []string{chID},
+				ThingIDs:   []string{thID},
+				Role:       conns.Role,
+				Label:      conns.Label,
+			}
+		}
+	}
+	return nil
+}
+
+func (s *SDK) DisconnectThing(thingID, chanID, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := chanID + "/" + thingID
+	if _, ok := s.Connections[key]; !ok {
+		return sdk.ErrFailedDisconnect
+	}
+	delete(s.Connections, key)
+	return nil
+}
+
+func (s *SDK) CreatePolicy(p sdk.Policy, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, subject := range p.Subjects {
+		for _, action := range p.Actions {
+			s.Policies[PolicyKey(p.Object, subject, action)] = true
+		}
+	}
+	return nil
+}
+
+func (s *SDK) DeletePolicy(p sdk.Policy, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, subject := range p.Subjects {
+		for _, action := range p.Actions {
+			delete(s.Policies, PolicyKey(p.Object, subject, action))
+		}
+	}
+	return nil
+}
+
+func keys(m interface{}) []string {
+	var ret []string
+	switch m := m.(type) {
+	case map[string]sdk.Group:
+		for k := range m {
+			ret = append(ret, k)
+		}
+	case map[string]sdk.Thing:
+		for k := range m {
+			ret = append(ret, k)
+		}
+	case map[string]sdk.Channel:
+		for k := range m {
+			ret = append(ret, k)
+		}
+	case map[string]sdk.ConnectionIDs:
+		for k := range m {
+			ret = append(ret, k)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func lower(offset uint64, n int) int {
+	if offset > uint64(n) {
+		return n
+	}
+	return int(offset)
+}
+
+func upper(offset, limit uint64, n int) int {
+	if offset+limit > uint64(n) {
+		return n
+	}
+	return int(offset + limit)
+}
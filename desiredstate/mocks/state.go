@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/desiredstate"
+)
+
+var _ desiredstate.StateRepository = (*stateRepositoryMock)(nil)
+
+type stateRepositoryMock struct {
+	mu     sync.Mutex
+	states map[string]desiredstate.State
+}
+
+// NewStateRepository returns the in-memory state repository.
+func NewStateRepository() desiredstate.StateRepository {
+	return &stateRepositoryMock{
+		states: make(map[string]desiredstate.State),
+	}
+}
+
+func (srm *stateRepositoryMock) Save(_ context.Context, owner string, st desiredstate.State) error {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	srm.states[owner] = st
+	return nil
+}
+
+func (srm *stateRepositoryMock) Retrieve(_ context.Context, owner string) (desiredstate.State, error) {
+	srm.mu.Lock()
+	defer srm.mu.Unlock()
+
+	return srm.states[owner], nil
+}
@@ -0,0 +1,253 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package desiredstate
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+)
+
+// Operation represents the operation performed by the change.
+type Operation string
+
+// Operations performed by the plan changes.
+const (
+	Create Operation = "create"
+	Update Operation = "update"
+	Delete Operation = "delete"
+)
+
+// Kind represents the kind of the entity affected by the change.
+type Kind string
+
+// Kinds of the entities the manifest declares.
+const (
+	UserKind       Kind = "user"
+	GroupKind      Kind = "group"
+	ThingKind      Kind = "thing"
+	ChannelKind    Kind = "channel"
+	ConnectionKind Kind = "connection"
+	PolicyKind     Kind = "policy"
+)
+
+// Change represents the single operation needed to reconcile the platform
+// with the manifest. Connections are named "<channel>/<thing>" and policies
+// "<object>#<action>@<subject>".
+type Change struct {
+	Operation Operation `json:"operation"`
+	Kind      Kind      `json:"kind"`
+	Name      string    `json:"name"`
+	ID        string    `json:"id,omitempty"`
+}
+
+// Plan represents the ordered list of changes. Entities are created and
+// updated before being connected and referred to by the policies, and
+// deleted only after their connections and policies are removed.
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// Empty returns true if the platform is in sync with the manifest.
+func (p Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+func (p *Plan) add(op Operation, kind Kind, name, id string) {
+	p.Changes = append(p.Changes, Change{
+		Operation: op,
+		Kind:      kind,
+		Name:      name,
+		ID:        id,
+	})
+}
+
+// State represents the last applied manifest, used to find the entities
+// removed from the manifest and to detect the drift.
+type State struct {
+	Manifest Manifest `json:"manifest"`
+
+	// Users maps the emails of the created users to their IDs, since the
+	// users can not be looked up by email.
+	Users map[string]string `json:"users,omitempty"`
+}
+
+// live represents the entities found on the platform, looked up by name.
+type live struct {
+	groups   map[string]sdk.Group
+	things   map[string]sdk.Thing
+	channels map[string]sdk.Channel
+
+	// connections maps the channel IDs to the IDs of connected things.
+	connections map[string]map[string]bool
+}
+
+func (l live) connected(c Connection) bool {
+	ch, ok := l.channels[c.Channel]
+	if !ok {
+		return false
+	}
+	th, ok := l.things[c.Thing]
+	if !ok {
+		return false
+	}
+	return l.connections[ch.ID][th.ID]
+}
+
+// diff returns the plan reconciling the live platform with the desired
+// manifest. The entities declared by the state manifest only are deleted,
+// while the entities never declared by the manifest are left intact.
+func diff(desired Manifest, st State, l live) Plan {
+	var p Plan
+
+	for _, u := range desired.Users {
+		if _, ok := st.Users[u.Email]; !ok {
+			p.add(Create, UserKind, u.Email, "")
+		}
+	}
+
+	for _, g := range desired.Groups {
+		lg, ok := l.groups[g.Name]
+		switch {
+		case !ok:
+			p.add(Create, GroupKind, g.Name, "")
+		case lg.Description != g.Description || !sameMetadata(lg.Metadata, g.Metadata):
+			p.add(Update, GroupKind, g.Name, lg.ID)
+		}
+	}
+
+	for _, t := range desired.Things {
+		lt, ok := l.things[t.Name]
+		switch {
+		case !ok:
+			p.add(Create, ThingKind, t.Name, "")
+		case !sameMetadata(lt.Metadata, t.Metadata):
+			p.add(Update, ThingKind, t.Name, lt.ID)
+		}
+	}
+
+	for _, c := range desired.Channels {
+		lc, ok := l.channels[c.Name]
+		switch {
+		case !ok:
+			p.add(Create, ChannelKind, c.Name, "")
+		case !sameMetadata(lc.Metadata, c.Metadata):
+			p.add(Update, ChannelKind, c.Name, lc.ID)
+		}
+	}
+
+	conns := connections(desired)
+	applied := connections(st.Manifest)
+	for _, c := range st.Manifest.Connections {
+		name := connectionName(c.Channel, c.Thing)
+		if _, ok := conns[name]; !ok && l.connected(c) {
+			p.add(Delete, ConnectionKind, name, "")
+		}
+	}
+	for _, c := range desired.Connections {
+		name := connectionName(c.Channel, c.Thing)
+		a, managed := applied[name]
+		switch {
+		case !l.connected(c):
+			p.add(Create, ConnectionKind, name, "")
+		case managed && (a.Role != c.Role || a.Label != c.Label):
+			p.add(Update, ConnectionKind, name, "")
+		}
+	}
+
+	policies := triples(desired)
+	appliedPolicies := triples(st.Manifest)
+	for _, name := range sortedKeys(appliedPolicies) {
+		if _, ok := policies[name]; !ok {
+			p.add(Delete, PolicyKind, name, "")
+		}
+	}
+	for _, name := range sortedKeys(policies) {
+		if _, ok := appliedPolicies[name]; !ok {
+			p.add(Create, PolicyKind, name, "")
+		}
+	}
+
+	chs := make(map[string]bool)
+	for _, c := range desired.Channels {
+		chs[c.Name] = true
+	}
+	for _, c := range st.Manifest.Channels {
+		if lc, ok := l.channels[c.Name]; ok && !chs[c.Name] {
+			p.add(Delete, ChannelKind, c.Name, lc.ID)
+		}
+	}
+
+	ths := make(map[string]bool)
+	for _, t := range desired.Things {
+		ths[t.Name] = true
+	}
+	for _, t := range st.Manifest.Things {
+		if lt, ok := l.things[t.Name]; ok && !ths[t.Name] {
+			p.add(Delete, ThingKind, t.Name, lt.ID)
+		}
+	}
+
+	groups := make(map[string]bool)
+	for _, g := range desired.Groups {
+		groups[g.Name] = true
+	}
+	for _, g := range st.Manifest.Groups {
+		if lg, ok := l.groups[g.Name]; ok && !groups[g.Name] {
+			p.add(Delete, GroupKind, g.Name, lg.ID)
+		}
+	}
+
+	return p
+}
+
+type triple struct {
+	object  string
+	subject string
+	action  string
+}
+
+// triples expands the manifest policies to the single object, subject and
+// action triples, keyed by their names.
+func triples(m Manifest) map[string]triple {
+	ret := make(map[string]triple)
+	for _, p := range m.Policies {
+		for _, s := range p.Subjects {
+			for _, a := range p.Actions {
+				ret[fmt.Sprintf("%s#%s@%s", p.Object, a, s)] = triple{object: p.Object, subject: s, action: a}
+			}
+		}
+	}
+	return ret
+}
+
+func connections(m Manifest) map[string]Connection {
+	ret := make(map[string]Connection)
+	for _, c := range m.Connections {
+		ret[connectionName(c.Channel, c.Thing)] = c
+	}
+	return ret
+}
+
+func connectionName(channel, thing string) string {
+	return fmt.Sprintf("%s/%s", channel, thing)
+}
+
+func sortedKeys(m map[string]triple) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sameMetadata(a, b map[string]interface{}) bool {
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,491 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package desiredstate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+)
+
+const pageSize = 100
+
+var (
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrMalformedEntity indicates malformed entity specification (e.g.
+	// invalid manifest).
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrFailedFetch indicates that the platform entities can not be retrieved.
+	ErrFailedFetch = errors.New("failed to retrieve platform entities")
+
+	// ErrFailedApply indicates that the plan change failed to be applied.
+	ErrFailedApply = errors.New("failed to apply change")
+)
+
+// StateRepository specifies the applied state persistence API.
+type StateRepository interface {
+	// Save persists the state applied by the user.
+	Save(ctx context.Context, owner string, st State) error
+
+	// Retrieve returns the state applied by the user, or the empty state if
+	// the user never applied any manifest.
+	Retrieve(ctx context.Context, owner string) (State, error)
+}
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Plan returns the changes needed to reconcile the platform with the
+	// manifest, without applying them.
+	Plan(ctx context.Context, token string, m Manifest) (Plan, error)
+
+	// Apply reconciles the platform with the manifest and stores it as the
+	// applied state. Apply is idempotent, so it can be safely repeated after
+	// the failure. It returns the applied changes.
+	Apply(ctx context.Context, token string, m Manifest) (Plan, error)
+
+	// Drift returns the changes needed to bring the platform back to the
+	// last applied manifest, i.e. the changes made outside of the service.
+	Drift(ctx context.Context, token string) (Plan, error)
+
+	// State returns the last applied state.
+	State(ctx context.Context, token string) (State, error)
+}
+
+var _ Service = (*desiredStateService)(nil)
+
+type desiredStateService struct {
+	sdk   sdk.SDK
+	state StateRepository
+	mu    sync.Mutex
+}
+
+// New instantiates the desired-state service implementation.
+func New(sdk sdk.SDK, state StateRepository) Service {
+	return &desiredStateService{
+		sdk:   sdk,
+		state: state,
+	}
+}
+
+func (svc *desiredStateService) Plan(ctx context.Context, token string, m Manifest) (Plan, error) {
+	if err := m.Validate(); err != nil {
+		return Plan{}, err
+	}
+
+	_, st, l, err := svc.retrieve(ctx, token, m)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return diff(m, st, l), nil
+}
+
+func (svc *desiredStateService) Apply(ctx context.Context, token string, m Manifest) (Plan, error) {
+	if err := m.Validate(); err != nil {
+		return Plan{}, err
+	}
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	owner, st, l, err := svc.retrieve(ctx, token, m)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	a := newApplier(svc.sdk, token, m, st, l)
+	var done Plan
+	for _, c := range diff(m, st, l).Changes {
+		if err := a.apply(c); err != nil {
+			// Keep the IDs of the created users, since they can't be
+			// looked up on the retry.
+			st.Users = a.users
+			if serr := svc.state.Save(ctx, owner, st); serr != nil {
+				return done, serr
+			}
+			return done, errors.Wrap(ErrFailedApply, fmt.Errorf("%s %s %q: %s", c.Operation, c.Kind, c.Name, err))
+		}
+		done.Changes = append(done.Changes, c)
+	}
+
+	st = State{
+		Manifest: m.withoutSecrets(),
+		Users:    a.users,
+	}
+	if err := svc.state.Save(ctx, owner, st); err != nil {
+		return done, err
+	}
+
+	return done, nil
+}
+
+func (svc *desiredStateService) Drift(ctx context.Context, token string) (Plan, error) {
+	owner, err := svc.identify(token)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	st, err := svc.state.Retrieve(ctx, owner)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	l, err := svc.live(token, st.Manifest, st.Manifest)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	return diff(st.Manifest, st, l), nil
+}
+
+func (svc *desiredStateService) State(ctx context.Context, token string) (State, error) {
+	owner, err := svc.identify(token)
+	if err != nil {
+		return State{}, err
+	}
+
+	return svc.state.Retrieve(ctx, owner)
+}
+
+func (svc *desiredStateService) identify(token string) (string, error) {
+	u, err := svc.sdk.User(token)
+	if err != nil {
+		return "", errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return u.ID, nil
+}
+
+func (svc *desiredStateService) retrieve(ctx context.Context, token string, m Manifest) (string, State, live, error) {
+	owner, err := svc.identify(token)
+	if err != nil {
+		return "", State{}, live{}, err
+	}
+
+	st, err := svc.state.Retrieve(ctx, owner)
+	if err != nil {
+		return "", State{}, live{}, err
+	}
+
+	l, err := svc.live(token, m, st.Manifest)
+	if err != nil {
+		return "", State{}, live{}, err
+	}
+
+	return owner, st, l, nil
+}
+
+// live retrieves the platform entities owned by the user. The connections
+// are retrieved only for the channels the manifests connect things to.
+func (svc *desiredStateService) live(token string, manifests ...Manifest) (live, error) {
+	l := live{
+		groups:      make(map[string]sdk.Group),
+		things:      make(map[string]sdk.Thing),
+		channels:    make(map[string]sdk.Channel),
+		connections: make(map[string]map[string]bool),
+	}
+
+	for offset := uint64(0); ; offset += pageSize {
+		gp, err := svc.sdk.Groups(offset, pageSize, token)
+		if err != nil {
+			return live{}, errors.Wrap(ErrFailedFetch, err)
+		}
+		for _, g := range gp.Groups {
+			l.groups[g.Name] = g
+		}
+		if offset+pageSize >= gp.Total {
+			break
+		}
+	}
+
+	for offset := uint64(0); ; offset += pageSize {
+		tp, err := svc.sdk.Things(token, offset, pageSize, "")
+		if err != nil {
+			return live{}, errors.Wrap(ErrFailedFetch, err)
+		}
+		for _, t := range tp.Things {
+			l.things[t.Name] = t
+		}
+		if offset+pageSize >= tp.Total {
+			break
+		}
+	}
+
+	for offset := uint64(0); ; offset += pageSize {
+		cp, err := svc.sdk.Channels(token, offset, pageSize, "")
+		if err != nil {
+			return live{}, errors.Wrap(ErrFailedFetch, err)
+		}
+		for _, c := range cp.Channels {
+			l.channels[c.Name] = c
+		}
+		if offset+pageSize >= cp.Total {
+			break
+		}
+	}
+
+	for _, m := range manifests {
+		for _, c := range m.Connections {
+			ch, ok := l.channels[c.Channel]
+			if !ok {
+				continue
+			}
+			if _, ok := l.connections[ch.ID]; ok {
+				continue
+			}
+			connected, err := svc.connected(token, ch.ID)
+			if err != nil {
+				return live{}, err
+			}
+			l.connections[ch.ID] = connected
+		}
+	}
+
+	return l, nil
+}
+
+func (svc *desiredStateService) connected(token, chanID string) (map[string]bool, error) {
+	ret := make(map[string]bool)
+	for offset := uint64(0); ; offset += pageSize {
+		tp, err := svc.sdk.ThingsByChannel(token, chanID, offset, pageSize, true)
+		if err != nil {
+			return nil, errors.Wrap(ErrFailedFetch, err)
+		}
+		for _, t := range tp.Things {
+			ret[t.ID] = true
+		}
+		if offset+pageSize >= tp.Total {
+			return ret, nil
+		}
+	}
+}
+
+// applier applies the plan changes, keeping track of the IDs of the
+// entities it creates so that they can be referred to by the later changes.
+type applier struct {
+	sdk      sdk.SDK
+	token    string
+	desired  Manifest
+	applied  Manifest
+	users    map[string]string
+	ids      map[Kind]map[string]string
+	policies map[string]triple
+	removed  map[string]triple
+}
+
+func newApplier(s sdk.SDK, token string, desired Manifest, st State, l live) *applier {
+	a := &applier{
+		sdk:      s,
+		token:    token,
+		desired:  desired,
+		applied:  st.Manifest,
+		users:    make(map[string]string),
+		ids:      make(map[Kind]map[string]string),
+		policies: triples(desired),
+		removed:  triples(st.Manifest),
+	}
+
+	for email, id := range st.Users {
+		a.users[email] = id
+	}
+	a.ids[UserKind] = a.users
+	a.ids[GroupKind] = make(map[string]string)
+	for name, g := range l.groups {
+		a.ids[GroupKind][name] = g.ID
+	}
+	a.ids[ThingKind] = make(map[string]string)
+	for name, t := range l.things {
+		a.ids[ThingKind][name] = t.ID
+	}
+	a.ids[ChannelKind] = make(map[string]string)
+	for name, c := range l.channels {
+		a.ids[ChannelKind][name] = c.ID
+	}
+
+	return a
+}
+
+func (a *applier) apply(c Change) error {
+	switch c.Kind {
+	case UserKind:
+		return a.applyUser(c)
+	case GroupKind:
+		return a.applyGroup(c)
+	case ThingKind:
+		return a.applyThing(c)
+	case ChannelKind:
+		return a.applyChannel(c)
+	case ConnectionKind:
+		return a.applyConnection(c)
+	case PolicyKind:
+		return a.applyPolicy(c)
+	default:
+		return ErrMalformedEntity
+	}
+}
+
+func (a *applier) applyUser(c Change) error {
+	for _, u := range a.desired.Users {
+		if u.Email != c.Name {
+			continue
+		}
+		id, err := a.sdk.CreateUser(a.token, sdk.User{Email: u.Email, Password: u.Password, Metadata: u.Metadata})
+		if err != nil {
+			return err
+		}
+		a.users[u.Email] = id
+		return nil
+	}
+	return ErrMalformedEntity
+}
+
+func (a *applier) applyGroup(c Change) error {
+	if c.Operation == Delete {
+		return a.sdk.DeleteGroup(c.ID, a.token)
+	}
+
+	for _, g := range a.desired.Groups {
+		if g.Name != c.Name {
+			continue
+		}
+		group := sdk.Group{ID: c.ID, Name: g.Name, Description: g.Description, Metadata: g.Metadata}
+		if c.Operation == Update {
+			return a.sdk.UpdateGroup(group, a.token)
+		}
+		id, err := a.sdk.CreateGroup(group, a.token)
+		if err != nil {
+			return err
+		}
+		a.ids[GroupKind][g.Name] = id
+		return nil
+	}
+	return ErrMalformedEntity
+}
+
+func (a *applier) applyThing(c Change) error {
+	if c.Operation == Delete {
+		return a.sdk.DeleteThing(c.ID, a.token)
+	}
+
+	for _, t := range a.desired.Things {
+		if t.Name != c.Name {
+			continue
+		}
+		thing := sdk.Thing{ID: c.ID, Name: t.Name, Metadata: t.Metadata}
+		if c.Operation == Update {
+			return a.sdk.UpdateThing(thing, a.token)
+		}
+		id, err := a.sdk.CreateThing(thing, a.token)
+		if err != nil {
+			return err
+		}
+		a.ids[ThingKind][t.Name] = id
+		return nil
+	}
+	return ErrMalformedEntity
+}
+
+func (a *applier) applyChannel(c Change) error {
+	if c.Operation == Delete {
+		return a.sdk.DeleteChannel(c.ID, a.token)
+	}
+
+	for _, ch := range a.desired.Channels {
+		if ch.Name != c.Name {
+			continue
+		}
+		channel := sdk.Channel{ID: c.ID, Name: ch.Name, Metadata: ch.Metadata}
+		if c.Operation == Update {
+			return a.sdk.UpdateChannel(channel, a.token)
+		}
+		id, err := a.sdk.CreateChannel(channel, a.token)
+		if err != nil {
+			return err
+		}
+		a.ids[ChannelKind][ch.Name] = id
+		return nil
+	}
+	return ErrMalformedEntity
+}
+
+func (a *applier) applyConnection(c Change) error {
+	conns := connections(a.desired)
+	if c.Operation == Delete {
+		conns = connections(a.applied)
+	}
+	conn, ok := conns[c.Name]
+	if !ok {
+		return ErrMalformedEntity
+	}
+
+	chanID, thingID := a.ids[ChannelKind][conn.Channel], a.ids[ThingKind][conn.Thing]
+	if c.Operation != Create {
+		if err := a.sdk.DisconnectThing(thingID, chanID, a.token); err != nil {
+			return err
+		}
+	}
+	if c.Operation == Delete {
+		return nil
+	}
+
+	ids := sdk.ConnectionIDs{
+		ChannelIDs: []string{chanID},
+		ThingIDs:   []string{thingID},
+		Role:       conn.Role,
+		Label:      conn.Label,
+	}
+	return a.sdk.Connect(ids, a.token)
+}
+
+func (a *applier) applyPolicy(c Change) error {
+	if c.Operation == Delete {
+		t := a.removed[c.Name]
+		object, ok := a.resolve(t.object)
+		if !ok {
+			return nil
+		}
+		subject, ok := a.resolve(t.subject)
+		if !ok {
+			return nil
+		}
+		return a.sdk.DeletePolicy(sdk.Policy{Object: object, Subjects: []string{subject}, Actions: []string{t.action}}, a.token)
+	}
+
+	t := a.policies[c.Name]
+	object, ok := a.resolve(t.object)
+	if !ok {
+		return errors.Wrap(ErrMalformedEntity, fmt.Errorf("unresolved reference %q", t.object))
+	}
+	subject, ok := a.resolve(t.subject)
+	if !ok {
+		return errors.Wrap(ErrMalformedEntity, fmt.Errorf("unresolved reference %q", t.subject))
+	}
+	return a.sdk.CreatePolicy(sdk.Policy{Object: object, Subjects: []string{subject}, Actions: []string{t.action}}, a.token)
+}
+
+// resolve returns the ID of the entity the policy reference refers to.
+func (a *applier) resolve(ref string) (string, bool) {
+	refs := map[string]Kind{
+		UserRef:    UserKind,
+		GroupRef:   GroupKind,
+		ThingRef:   ThingKind,
+		ChannelRef: ChannelKind,
+	}
+	for prefix, kind := range refs {
+		if strings.HasPrefix(ref, prefix) {
+			id, ok := a.ids[kind][strings.TrimPrefix(ref, prefix)]
+			return id, ok
+		}
+	}
+	return ref, true
+}
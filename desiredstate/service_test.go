@@ -0,0 +1,247 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package desiredstate_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/desiredstate"
+	"github.com/mainflux/mainflux/desiredstate/mocks"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token      = "token"
+	wrongToken = "wrong-token"
+	owner      = "owner"
+)
+
+var manifest = desiredstate.Manifest{
+	Users:    []desiredstate.User{{Email: "operator@example.com", Password: "12345678"}},
+	Groups:   []desiredstate.Group{{Name: "site", Description: "first site"}},
+	Things:   []desiredstate.Thing{{Name: "sensor"}, {Name: "gateway", Metadata: map[string]interface{}{"type": "gateway"}}},
+	Channels: []desiredstate.Channel{{Name: "telemetry"}},
+	Connections: []desiredstate.Connection{
+		{Channel: "telemetry", Thing: "sensor", Role: "publisher"},
+		{Channel: "telemetry", Thing: "gateway"},
+	},
+	Policies: []desiredstate.Policy{
+		{Object: "thing:sensor", Subjects: []string{"user:operator@example.com"}, Actions: []string{"read", "write"}},
+	},
+}
+
+func newService() (desiredstate.Service, *mocks.SDK) {
+	sdk := mocks.NewSDK(map[string]string{token: owner})
+	return desiredstate.New(sdk, mocks.NewStateRepository()), sdk
+}
+
+func changes(p desiredstate.Plan) []string {
+	var ret []string
+	for _, c := range p.Changes {
+		ret = append(ret, fmt.Sprintf("%s %s %s", c.Operation, c.Kind, c.Name))
+	}
+	return ret
+}
+
+func TestPlan(t *testing.T) {
+	svc, _ := newService()
+
+	duplicate := manifest
+	duplicate.Things = []desiredstate.Thing{{Name: "sensor"}, {Name: "sensor"}}
+
+	unknown := manifest
+	unknown.Connections = []desiredstate.Connection{{Channel: "telemetry", Thing: "unknown"}}
+
+	cases := []struct {
+		desc     string
+		token    string
+		manifest desiredstate.Manifest
+		changes  []string
+		err      error
+	}{
+		{
+			desc:     "plan manifest against empty platform",
+			token:    token,
+			manifest: manifest,
+			changes: []string{
+				"create user operator@example.com",
+				"create group site",
+				"create thing sensor",
+				"create thing gateway",
+				"create channel telemetry",
+				"create connection telemetry/sensor",
+				"create connection telemetry/gateway",
+				"create policy thing:sensor#read@user:operator@example.com",
+				"create policy thing:sensor#write@user:operator@example.com",
+			},
+			err: nil,
+		},
+		{
+			desc:     "plan empty manifest",
+			token:    token,
+			manifest: desiredstate.Manifest{},
+			changes:  nil,
+			err:      nil,
+		},
+		{
+			desc:     "plan manifest with invalid token",
+			token:    wrongToken,
+			manifest: manifest,
+			err:      desiredstate.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "plan manifest with duplicate names",
+			token:    token,
+			manifest: duplicate,
+			err:      desiredstate.ErrMalformedEntity,
+		},
+		{
+			desc:     "plan manifest connecting undeclared thing",
+			token:    token,
+			manifest: unknown,
+			err:      desiredstate.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := svc.Plan(context.Background(), tc.token, tc.manifest)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.changes, changes(p), fmt.Sprintf("%s: unexpected plan\n", tc.desc))
+	}
+}
+
+func TestApply(t *testing.T) {
+	svc, sdk := newService()
+
+	p, err := svc.Apply(context.Background(), token, manifest)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, 9, len(p.Changes), fmt.Sprintf("expected 9 applied changes got %d", len(p.Changes)))
+	assert.Equal(t, 2, len(sdk.ThingsByID), fmt.Sprintf("expected 2 things got %d", len(sdk.ThingsByID)))
+	assert.Equal(t, 2, len(sdk.Connections), fmt.Sprintf("expected 2 connections got %d", len(sdk.Connections)))
+	assert.Equal(t, 2, len(sdk.Policies), fmt.Sprintf("expected 2 policies got %d", len(sdk.Policies)))
+
+	var sensorID, userID string
+	for id, th := range sdk.ThingsByID {
+		if th.Name == "sensor" {
+			sensorID = id
+		}
+	}
+	for id := range sdk.UsersByID {
+		userID = id
+	}
+	assert.True(t, sdk.Policies[mocks.PolicyKey(sensorID, userID, "read")], "expected policy to refer to the created entities")
+
+	st, err := svc.State(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, "", st.Manifest.Users[0].Password, "expected applied state not to contain passwords")
+	assert.Equal(t, userID, st.Users["operator@example.com"], "expected applied state to contain created user")
+
+	updated := manifest
+	updated.Things = []desiredstate.Thing{{Name: "sensor", Metadata: map[string]interface{}{"location": "hall"}}}
+	updated.Connections = []desiredstate.Connection{{Channel: "telemetry", Thing: "sensor", Role: "subscriber"}}
+	updated.Policies = []desiredstate.Policy{
+		{Object: "thing:sensor", Subjects: []string{"user:operator@example.com"}, Actions: []string{"read"}},
+	}
+
+	cases := []struct {
+		desc     string
+		token    string
+		manifest desiredstate.Manifest
+		changes  []string
+		err      error
+	}{
+		{
+			desc:     "apply already applied manifest",
+			token:    token,
+			manifest: manifest,
+			changes:  nil,
+			err:      nil,
+		},
+		{
+			desc:     "apply updated manifest",
+			token:    token,
+			manifest: updated,
+			changes: []string{
+				"update thing sensor",
+				"delete connection telemetry/gateway",
+				"update connection telemetry/sensor",
+				"delete policy thing:sensor#write@user:operator@example.com",
+				"delete thing gateway",
+			},
+			err: nil,
+		},
+		{
+			desc:     "apply manifest with invalid token",
+			token:    wrongToken,
+			manifest: manifest,
+			err:      desiredstate.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := svc.Apply(context.Background(), tc.token, tc.manifest)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.changes, changes(p), fmt.Sprintf("%s: unexpected applied changes\n", tc.desc))
+	}
+
+	assert.Equal(t, 1, len(sdk.ThingsByID), fmt.Sprintf("expected 1 thing got %d", len(sdk.ThingsByID)))
+	assert.Equal(t, 1, len(sdk.Policies), fmt.Sprintf("expected 1 policy got %d", len(sdk.Policies)))
+	for _, conn := range sdk.Connections {
+		assert.Equal(t, "subscriber", conn.Role, fmt.Sprintf("expected subscriber role got %s", conn.Role))
+	}
+}
+
+func TestDrift(t *testing.T) {
+	svc, sdk := newService()
+
+	_, err := svc.Apply(context.Background(), token, manifest)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	p, err := svc.Drift(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, p.Empty(), "expected no drift after apply")
+
+	for id, th := range sdk.ThingsByID {
+		if th.Name == "gateway" {
+			require.Nil(t, sdk.DeleteThing(id, token), "unexpected error deleting thing")
+		}
+	}
+	for _, ch := range sdk.ChannelsByID {
+		ch.Metadata = map[string]interface{}{"changed": true}
+		require.Nil(t, sdk.UpdateChannel(ch, token), "unexpected error updating channel")
+	}
+
+	cases := []struct {
+		desc    string
+		token   string
+		changes []string
+		err     error
+	}{
+		{
+			desc:  "detect drift",
+			token: token,
+			changes: []string{
+				"create thing gateway",
+				"update channel telemetry",
+				"create connection telemetry/gateway",
+			},
+			err: nil,
+		},
+		{
+			desc:  "detect drift with invalid token",
+			token: wrongToken,
+			err:   desiredstate.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := svc.Drift(context.Background(), tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.changes, changes(p), fmt.Sprintf("%s: unexpected drift\n", tc.desc))
+	}
+}
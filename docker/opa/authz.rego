@@ -0,0 +1,26 @@
+# Copyright (c) Mainflux
+# SPDX-License-Identifier: Apache-2.0
+
+# Authorization policy of the Mainflux auth service OPA backend. It follows
+# the Keto semantics: the policies are the (subject, relation, object)
+# tuples stored in data.mainflux.policies[object][relation][subject], and
+# the subject may be the subject set "members:<object>#<relation>",
+# granting the relation to everyone having that relation on that object.
+package mainflux.authz
+
+default allow = false
+
+# Every subject set points to its direct subjects, which may be the other
+# subject sets.
+usersets[userset] = subjects {
+	some object, relation
+	data.mainflux.policies[object][relation]
+	userset := sprintf("members:%s#%s", [object, relation])
+	subjects := {subject | data.mainflux.policies[object][relation][subject]}
+}
+
+allow {
+	userset := sprintf("members:%s#%s", [input.object, input.relation])
+	reachable := graph.reachable(usersets, {userset})
+	reachable[input.subject]
+}
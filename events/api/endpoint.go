@@ -0,0 +1,27 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/events"
+)
+
+func subscribeEndpoint(svc events.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(subscribeReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		evs, err := svc.Subscribe(ctx, req.token, req.lastID)
+		if err != nil {
+			return nil, err
+		}
+
+		return subscribeRes{events: evs}, nil
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/events"
+
+type subscribeReq struct {
+	token  string
+	lastID string
+}
+
+func (req subscribeReq) validate() error {
+	if req.token == "" {
+		return events.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/events"
+
+type subscribeRes struct {
+	events <-chan events.Event
+}
+
+type eventRes struct {
+	Stream    string                 `json:"stream"`
+	Operation string                 `json:"operation"`
+	Payload   map[string]interface{} `json:"payload"`
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
@@ -0,0 +1,130 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/pkg/errors"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType    = "application/json"
+	sseContentType = "text/event-stream"
+
+	lastIDHeader = "Last-Event-ID"
+	lastIDKey    = "last_event_id"
+
+	// heartbeat is the interval of comments sent to keep idle connections
+	// from being closed by proxies.
+	heartbeat = 30 * time.Second
+)
+
+var errStreamingUnsupported = errors.New("streaming unsupported")
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(tracer opentracing.Tracer, svc events.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Get("/events", kithttp.NewServer(
+		kitot.TraceServer(tracer, "subscribe")(subscribeEndpoint(svc)),
+		decodeSubscribe,
+		encodeEvents,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("events"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+// decodeSubscribe reads the ID of the last received event from the header
+// set by EventSource on reconnect or, if missing, from the query parameter.
+func decodeSubscribe(_ context.Context, r *http.Request) (interface{}, error) {
+	req := subscribeReq{
+		token:  r.Header.Get("Authorization"),
+		lastID: r.Header.Get(lastIDHeader),
+	}
+	if req.lastID == "" {
+		req.lastID = r.URL.Query().Get(lastIDKey)
+	}
+
+	return req, nil
+}
+
+func encodeEvents(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(subscribeRes)
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errStreamingUnsupported
+	}
+
+	w.Header().Set("Content-Type", sseContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case ev, ok := <-res.events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(eventRes{
+				Stream:    ev.Stream,
+				Operation: ev.Operation,
+				Payload:   ev.Payload,
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", ev.ID, ev.Operation, data); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return err
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch {
+	case errors.Contains(err, events.ErrUnauthorizedAccess):
+		w.WriteHeader(http.StatusUnauthorized)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if errorVal, ok := err.(errors.Error); ok {
+		if err := json.NewEncoder(w).Encode(errorRes{Err: errorVal.Msg()}); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package events contains the domain concept definitions needed to support
+// Mainflux events service functionality. Events service streams platform
+// events, such as entity lifecycle changes, to the users allowed to see them.
+package events
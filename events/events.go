@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import "context"
+
+// Event represents a single platform event, e.g. creation of a thing or
+// an update of a bootstrap config.
+type Event struct {
+	// ID is the event store assigned ID of the event. Events with greater
+	// IDs are newer.
+	ID string
+
+	// Stream is the name of the stream the event was published to.
+	Stream string
+
+	// Operation is the event type, e.g. "thing.create".
+	Operation string
+
+	// Payload contains the event fields as published by the producer.
+	Payload map[string]interface{}
+}
+
+// Entity returns the ID of the entity the event refers to. Events which
+// refer to a thing (e.g. connection and bootstrap events) return the thing
+// ID, other events return the ID of the created, updated or removed entity.
+func (e Event) Entity() string {
+	if id, ok := e.Payload["thing_id"].(string); ok && id != "" {
+		return id
+	}
+	if id, ok := e.Payload["id"].(string); ok {
+		return id
+	}
+	return ""
+}
+
+// EventStore specifies an API for reading events.
+type EventStore interface {
+	// LastID returns the ID preceding all the events published after the
+	// call. It's used as the starting point of new subscriptions.
+	LastID(ctx context.Context) (string, error)
+
+	// Read waits for the events newer than the one with the given ID and
+	// returns them ordered by ID. Empty slice is returned if no events are
+	// published within the store's polling interval.
+	Read(ctx context.Context, lastID string) ([]Event, error)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/events"
+)
+
+const pollInterval = 10 * time.Millisecond
+
+var _ events.EventStore = (*eventStoreMock)(nil)
+
+// EventStore is the events store mock which allows publishing events.
+type EventStore interface {
+	events.EventStore
+
+	// Publish appends the event to the store and assigns it an ID.
+	Publish(stream string, payload map[string]interface{})
+}
+
+type eventStoreMock struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+// NewEventStore creates in-memory event store.
+func NewEventStore() EventStore {
+	return &eventStoreMock{}
+}
+
+func (es *eventStoreMock) Publish(stream string, payload map[string]interface{}) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	op, _ := payload["operation"].(string)
+	es.events = append(es.events, events.Event{
+		ID:        fmt.Sprintf("%d-0", len(es.events)+1),
+		Stream:    stream,
+		Operation: op,
+		Payload:   payload,
+	})
+}
+
+func (es *eventStoreMock) LastID(ctx context.Context) (string, error) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	return fmt.Sprintf("%d-0", len(es.events)), nil
+}
+
+func (es *eventStoreMock) Read(ctx context.Context, lastID string) ([]events.Event, error) {
+	var last int
+	if _, err := fmt.Sscanf(lastID, "%d-0", &last); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(pollInterval):
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	if last >= len(es.events) {
+		return []events.Event{}, nil
+	}
+	return append([]events.Event{}, es.events[last:]...), nil
+}
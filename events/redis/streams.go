@@ -0,0 +1,124 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/events"
+)
+
+const (
+	count = 100
+	block = 5 * time.Second
+)
+
+var _ events.EventStore = (*eventStore)(nil)
+
+type eventStore struct {
+	client  *redis.Client
+	streams []string
+}
+
+// NewEventStore returns new event store reading the given Redis streams.
+// Stream entries are expected to contain the "operation" field, as published
+// by the event sourcing middlewares of the services.
+func NewEventStore(client *redis.Client, streams []string) events.EventStore {
+	return eventStore{
+		client:  client,
+		streams: streams,
+	}
+}
+
+// LastID uses Redis server time since stream IDs are millisecond timestamps
+// and the same ID has to be used as the starting point of all the streams.
+func (es eventStore) LastID(ctx context.Context) (string, error) {
+	t, err := es.client.Time(ctx).Result()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-0", t.UnixNano()/int64(time.Millisecond)), nil
+}
+
+func (es eventStore) Read(ctx context.Context, lastID string) ([]events.Event, error) {
+	args := &redis.XReadArgs{
+		Streams: make([]string, 0, 2*len(es.streams)),
+		Count:   count,
+		Block:   block,
+	}
+	args.Streams = append(args.Streams, es.streams...)
+	for range es.streams {
+		args.Streams = append(args.Streams, lastID)
+	}
+
+	streams, err := es.client.XRead(ctx, args).Result()
+	switch err {
+	case nil:
+	case redis.Nil:
+		return []events.Event{}, nil
+	default:
+		return nil, err
+	}
+
+	// Streams which returned a full batch may have more pending entries, so
+	// the events newer than the oldest last entry of such streams are left
+	// for the next read in order not to skip them.
+	limit := ""
+	for _, s := range streams {
+		if len(s.Messages) < count {
+			continue
+		}
+		if id := s.Messages[len(s.Messages)-1].ID; limit == "" || less(id, limit) {
+			limit = id
+		}
+	}
+
+	evs := []events.Event{}
+	for _, s := range streams {
+		for _, msg := range s.Messages {
+			if limit != "" && less(limit, msg.ID) {
+				break
+			}
+			op, _ := msg.Values["operation"].(string)
+			evs = append(evs, events.Event{
+				ID:        msg.ID,
+				Stream:    s.Stream,
+				Operation: op,
+				Payload:   msg.Values,
+			})
+		}
+	}
+
+	sort.SliceStable(evs, func(i, j int) bool {
+		return less(evs[i].ID, evs[j].ID)
+	})
+
+	return evs, nil
+}
+
+// less compares Redis stream IDs formatted as "<milliseconds>-<sequence>".
+func less(a, b string) bool {
+	ams, aseq := splitID(a)
+	bms, bseq := splitID(b)
+	if ams != bms {
+		return ams < bms
+	}
+	return aseq < bseq
+}
+
+func splitID(id string) (uint64, uint64) {
+	parts := strings.SplitN(id, "-", 2)
+	ms, _ := strconv.ParseUint(parts[0], 10, 64)
+	var seq uint64
+	if len(parts) == 2 {
+		seq, _ = strconv.ParseUint(parts[1], 10, 64)
+	}
+	return ms, seq
+}
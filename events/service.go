@@ -0,0 +1,141 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	readRelation      = "read"
+	memberRelation    = "member"
+	authoritiesObject = "authorities"
+
+	// retryInterval is the delay between failed event store reads.
+	retryInterval = time.Second
+)
+
+var (
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrSubscribe indicates failure to subscribe to the event stream.
+	ErrSubscribe = errors.New("failed to subscribe to events")
+)
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Subscribe streams the events newer than the one with the given ID
+	// which refer to the entities the user identified by the token is
+	// allowed to read. Empty last ID streams only the events published
+	// after the call. Returned channel is closed once the context is done.
+	Subscribe(ctx context.Context, token, lastID string) (<-chan Event, error)
+}
+
+var _ Service = (*eventsService)(nil)
+
+type eventsService struct {
+	auth   mainflux.AuthServiceClient
+	store  EventStore
+	logger logger.Logger
+}
+
+// New instantiates the events service implementation.
+func New(auth mainflux.AuthServiceClient, store EventStore, logger logger.Logger) Service {
+	return &eventsService{
+		auth:   auth,
+		store:  store,
+		logger: logger,
+	}
+}
+
+func (es *eventsService) Subscribe(ctx context.Context, token, lastID string) (<-chan Event, error) {
+	res, err := es.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if lastID == "" {
+		if lastID, err = es.store.LastID(ctx); err != nil {
+			return nil, errors.Wrap(ErrSubscribe, err)
+		}
+	}
+
+	s := subscription{
+		svc:    es,
+		userID: res.GetId(),
+		admin:  es.authorize(ctx, res.GetId(), authoritiesObject, memberRelation),
+		events: make(chan Event),
+	}
+	go s.run(ctx, lastID)
+
+	return s.events, nil
+}
+
+func (es *eventsService) authorize(ctx context.Context, subject, object, relation string) bool {
+	req := &mainflux.AuthorizeReq{Sub: subject, Obj: object, Act: relation}
+	res, err := es.auth.Authorize(ctx, req)
+	return err == nil && res.GetAuthorized()
+}
+
+// subscription forwards the events the user is allowed to read.
+type subscription struct {
+	svc    *eventsService
+	userID string
+	admin  bool
+	events chan Event
+}
+
+func (s subscription) run(ctx context.Context, lastID string) {
+	defer close(s.events)
+
+	for {
+		evs, err := s.svc.store.Read(ctx, lastID)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			s.svc.logger.Warn(fmt.Sprintf("Failed to read events: %s", err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+
+		for _, ev := range evs {
+			lastID = ev.ID
+			if !s.allowed(ctx, ev) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case s.events <- ev:
+			}
+		}
+	}
+}
+
+// allowed checks if the user can read the entity the event refers to.
+// Members of the authorities object receive all the events.
+func (s subscription) allowed(ctx context.Context, ev Event) bool {
+	if s.admin {
+		return true
+	}
+	id := ev.Entity()
+	if id == "" {
+		return false
+	}
+	return s.svc.authorize(ctx, s.userID, id, readRelation)
+}
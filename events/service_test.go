@@ -0,0 +1,132 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package events_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/events"
+	"github.com/mainflux/mainflux/events/mocks"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	thmocks "github.com/mainflux/mainflux/things/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	token      = "token"
+	adminToken = "admin-token"
+	wrongToken = "wrong-token"
+	userID     = "user"
+	adminID    = "admin"
+	stream     = "mainflux.things"
+	timeout    = time.Second
+)
+
+var testLog, _ = log.New(os.Stdout, log.Info.String())
+
+func newService(store events.EventStore) events.Service {
+	users := map[string]string{token: userID, adminToken: adminID}
+	policies := map[string][]thmocks.MockSubjectSet{
+		userID:  {{Object: "1", Relation: "read"}},
+		adminID: {{Object: "authorities", Relation: "member"}},
+	}
+	auth := thmocks.NewAuthService(users, policies)
+	return events.New(auth, store, testLog)
+}
+
+func publish(store mocks.EventStore) {
+	store.Publish(stream, map[string]interface{}{"operation": "thing.create", "id": "1"})
+	store.Publish(stream, map[string]interface{}{"operation": "thing.create", "id": "2"})
+	store.Publish(stream, map[string]interface{}{"operation": "thing.connect", "thing_id": "1", "chan_id": "3"})
+	store.Publish(stream, map[string]interface{}{"operation": "unknown"})
+}
+
+func receive(t *testing.T, evs <-chan events.Event, n int) []string {
+	var ops []string
+	for len(ops) < n {
+		select {
+		case ev := <-evs:
+			ops = append(ops, fmt.Sprintf("%s:%s", ev.ID, ev.Operation))
+		case <-time.After(timeout):
+			t.Fatalf("expected %d events got %d", n, len(ops))
+		}
+	}
+	return ops
+}
+
+func TestSubscribe(t *testing.T) {
+	cases := []struct {
+		desc   string
+		token  string
+		lastID string
+		events []string
+		err    error
+	}{
+		{
+			desc:   "subscribe to events of readable entities",
+			token:  token,
+			events: []string{"1-0:thing.create", "3-0:thing.connect"},
+		},
+		{
+			desc:   "subscribe to all events as admin",
+			token:  adminToken,
+			events: []string{"1-0:thing.create", "2-0:thing.create", "3-0:thing.connect", "4-0:unknown"},
+		},
+		{
+			desc:   "resume subscription from the last received event",
+			token:  token,
+			lastID: "1-0",
+			events: []string{"3-0:thing.connect"},
+		},
+		{
+			desc:  "subscribe with invalid token",
+			token: wrongToken,
+			err:   events.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		store := mocks.NewEventStore()
+		svc := newService(store)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		evs, err := svc.Subscribe(ctx, tc.token, tc.lastID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			cancel()
+			continue
+		}
+
+		publish(store)
+		ops := receive(t, evs, len(tc.events))
+		assert.Equal(t, tc.events, ops, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.events, ops))
+
+		cancel()
+		for range evs {
+		}
+	}
+}
+
+func TestSubscribeClose(t *testing.T) {
+	store := mocks.NewEventStore()
+	svc := newService(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	evs, err := svc.Subscribe(ctx, token, "")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cancel()
+	select {
+	case _, ok := <-evs:
+		assert.False(t, ok, "expected events channel to be closed")
+	case <-time.After(timeout):
+		t.Fatal("expected events channel to be closed after the context is done")
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/graphql"
+)
+
+func queryEndpoint(svc graphql.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(queryReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		gr := graphql.Request{
+			Query:         req.Query,
+			OperationName: req.OperationName,
+			Variables:     req.Variables,
+		}
+		res, err := svc.Execute(ctx, req.userID, req.token, gr)
+		if err != nil {
+			return nil, err
+		}
+
+		return queryRes{res}, nil
+	}
+}
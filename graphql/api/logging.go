@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/graphql"
+	log "github.com/mainflux/mainflux/logger"
+)
+
+var _ graphql.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    graphql.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc graphql.Service, logger log.Logger) graphql.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Execute(ctx context.Context, userID, token string, req graphql.Request) (res graphql.Response, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method execute for operation %q took %s to complete", req.OperationName, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		if len(res.Errors) > 0 {
+			lm.logger.Warn(fmt.Sprintf("%s with %d field errors.", message, len(res.Errors)))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Execute(ctx, userID, token, req)
+}
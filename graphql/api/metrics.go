@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/graphql"
+)
+
+var _ graphql.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     graphql.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(svc graphql.Service, counter metrics.Counter, latency metrics.Histogram) graphql.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Execute(ctx context.Context, userID, token string, req graphql.Request) (graphql.Response, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "execute").Add(1)
+		ms.latency.With("method", "execute").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Execute(ctx, userID, token, req)
+}
@@ -0,0 +1,26 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/graphql"
+
+type queryReq struct {
+	userID        string
+	token         string
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+func (req queryReq) validate() error {
+	if req.userID == "" || req.token == "" {
+		return graphql.ErrUnauthorizedAccess
+	}
+
+	if req.Query == "" {
+		return graphql.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/graphql"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/pkg/errors"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType        = "application/json"
+	graphqlContentType = "application/graphql"
+
+	queryKey         = "query"
+	operationNameKey = "operationName"
+	variablesKey     = "variables"
+
+	// maxBodySize limits the size of the query request body.
+	maxBodySize = 1 << 20
+)
+
+// MakeHandler returns a HTTP handler for API endpoints. The queries are
+// executed on behalf of the user identified by the request token.
+func MakeHandler(tracer opentracing.Tracer, svc graphql.Service, auth mainflux.AuthServiceClient) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+	authn := httputil.Authenticate(auth)
+
+	r := bone.New()
+
+	r.Post("/graphql", authn(kithttp.NewServer(
+		kitot.TraceServer(tracer, "query")(queryEndpoint(svc)),
+		decodePostQuery,
+		encodeResponse,
+		opts...,
+	)))
+
+	r.Get("/graphql", authn(kithttp.NewServer(
+		kitot.TraceServer(tracer, "query")(queryEndpoint(svc)),
+		decodeGetQuery,
+		encodeResponse,
+		opts...,
+	)))
+
+	r.GetFunc("/version", mainflux.Version("graphql"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodePostQuery(ctx context.Context, r *http.Request) (interface{}, error) {
+	id, _ := httputil.IdentityFromContext(ctx)
+	req := queryReq{userID: id.ID, token: id.Token}
+
+	body := http.MaxBytesReader(nil, r.Body, maxBodySize)
+	ct := r.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(ct, graphqlContentType):
+		body, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, errors.Wrap(graphql.ErrMalformedEntity, err)
+		}
+		req.Query = string(body)
+	case strings.Contains(ct, contentType):
+		if err := json.NewDecoder(body).Decode(&req); err != nil {
+			return nil, errors.Wrap(graphql.ErrMalformedEntity, err)
+		}
+	default:
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	return req, nil
+}
+
+func decodeGetQuery(ctx context.Context, r *http.Request) (interface{}, error) {
+	q, err := httputil.ReadStringQuery(r, queryKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := httputil.ReadStringQuery(r, operationNameKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := httputil.ReadMetadataQuery(r, variablesKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	id, _ := httputil.IdentityFromContext(ctx)
+	req := queryReq{
+		userID:        id.ID,
+		token:         id.Token,
+		Query:         q,
+		OperationName: op,
+		Variables:     vars,
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch {
+	case errors.Contains(err, graphql.ErrUnauthorizedAccess):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, graphql.ErrMalformedEntity),
+		errors.Contains(err, graphql.ErrSyntax),
+		errors.Contains(err, graphql.ErrUnknownOperation),
+		errors.Contains(err, graphql.ErrQueryTooComplex),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	res := errorRes{Errors: []graphql.Error{{Message: err.Error()}}}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,7 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package graphql contains the domain concept definitions needed to support
+// the GraphQL gateway, which exposes users, things, channels, groups and
+// message history as a single graph on top of the platform HTTP APIs.
+package graphql
@@ -0,0 +1,442 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+const (
+	defOffset = 0
+	defLimit  = 10
+	maxLimit  = 100
+)
+
+// object represents a GraphQL object type value.
+type object interface {
+	// typename returns the name of the GraphQL type of the object.
+	typename() string
+
+	// id returns the ID of the object, used as the policy object
+	// when authorizing access to its fields.
+	id() string
+
+	// resolve returns the value of the named field. Returned value is
+	// either a scalar, an object or a list of objects.
+	resolve(e *executor, name string, args arguments) (interface{}, error)
+}
+
+// arguments contains field arguments with variables substituted.
+type arguments map[string]interface{}
+
+func (a arguments) string(name, def string) (string, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", errors.Wrap(errInvalidArg, fmt.Errorf("argument %q must be a string", name))
+	}
+	return s, nil
+}
+
+func (a arguments) required(name string) (string, error) {
+	s, err := a.string(name, "")
+	if err != nil {
+		return "", err
+	}
+	if s == "" {
+		return "", errors.Wrap(errInvalidArg, fmt.Errorf("argument %q is required", name))
+	}
+	return s, nil
+}
+
+func (a arguments) uint(name string, def, max uint64) (uint64, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+
+	var n float64
+	switch v := v.(type) {
+	case int64:
+		n = float64(v)
+	case float64:
+		// JSON encoded variables are decoded as floats.
+		n = v
+	default:
+		return 0, errors.Wrap(errInvalidArg, fmt.Errorf("argument %q must be an integer", name))
+	}
+	if n < 0 || n != float64(uint64(n)) {
+		return 0, errors.Wrap(errInvalidArg, fmt.Errorf("argument %q must be a non-negative integer", name))
+	}
+	if max > 0 && uint64(n) > max {
+		return 0, errors.Wrap(errInvalidArg, fmt.Errorf("argument %q must not be greater than %d", name, max))
+	}
+	return uint64(n), nil
+}
+
+func (a arguments) bool(name string, def bool) (bool, error) {
+	v, ok := a[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, errors.Wrap(errInvalidArg, fmt.Errorf("argument %q must be a boolean", name))
+	}
+	return b, nil
+}
+
+func (a arguments) page() (uint64, uint64, error) {
+	offset, err := a.uint("offset", defOffset, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, err := a.uint("limit", defLimit, maxLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	return offset, limit, nil
+}
+
+func unknownField(typename, name string) error {
+	return errors.Wrap(errUnknownField, fmt.Errorf("%s.%s", typename, name))
+}
+
+// query is the root query type.
+type query struct{}
+
+func (query) typename() string { return "Query" }
+
+func (query) id() string { return "" }
+
+func (q query) resolve(e *executor, name string, args arguments) (interface{}, error) {
+	s := e.svc.sdk
+	switch name {
+	case "me":
+		u, err := s.User(e.token)
+		if err != nil {
+			return nil, err
+		}
+		return user(u), nil
+	case "thing":
+		id, err := args.required("id")
+		if err != nil {
+			return nil, err
+		}
+		t, err := s.Thing(id, e.token)
+		if err != nil {
+			return nil, err
+		}
+		return thing(t), nil
+	case "things":
+		offset, limit, err := args.page()
+		if err != nil {
+			return nil, err
+		}
+		n, err := args.string("name", "")
+		if err != nil {
+			return nil, err
+		}
+		page, err := s.Things(e.token, offset, limit, n)
+		if err != nil {
+			return nil, err
+		}
+		return things(page.Things), nil
+	case "channel":
+		id, err := args.required("id")
+		if err != nil {
+			return nil, err
+		}
+		c, err := s.Channel(id, e.token)
+		if err != nil {
+			return nil, err
+		}
+		return channel(c), nil
+	case "channels":
+		offset, limit, err := args.page()
+		if err != nil {
+			return nil, err
+		}
+		n, err := args.string("name", "")
+		if err != nil {
+			return nil, err
+		}
+		page, err := s.Channels(e.token, offset, limit, n)
+		if err != nil {
+			return nil, err
+		}
+		return channels(page.Channels), nil
+	case "group":
+		id, err := args.required("id")
+		if err != nil {
+			return nil, err
+		}
+		g, err := s.Group(id, e.token)
+		if err != nil {
+			return nil, err
+		}
+		return group(g), nil
+	case "groups":
+		offset, limit, err := args.page()
+		if err != nil {
+			return nil, err
+		}
+		page, err := s.Groups(offset, limit, e.token)
+		if err != nil {
+			return nil, err
+		}
+		return groups(page.Groups), nil
+	default:
+		return nil, unknownField(q.typename(), name)
+	}
+}
+
+type user sdk.User
+
+func (user) typename() string { return "User" }
+
+func (u user) id() string { return u.ID }
+
+func (u user) resolve(e *executor, name string, args arguments) (interface{}, error) {
+	switch name {
+	case "id":
+		return u.ID, nil
+	case "email":
+		return u.Email, nil
+	case "metadata":
+		return u.Metadata, nil
+	case "groups":
+		offset, limit, err := args.page()
+		if err != nil {
+			return nil, err
+		}
+		page, err := e.svc.sdk.Memberships(u.ID, e.token, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		return groups(page.Groups), nil
+	default:
+		return nil, unknownField(u.typename(), name)
+	}
+}
+
+type thing sdk.Thing
+
+func (thing) typename() string { return "Thing" }
+
+func (t thing) id() string { return t.ID }
+
+func (t thing) resolve(e *executor, name string, args arguments) (interface{}, error) {
+	switch name {
+	case "id":
+		return t.ID, nil
+	case "name":
+		return t.Name, nil
+	case "key":
+		return t.Key, nil
+	case "metadata":
+		return t.Metadata, nil
+	case "channels":
+		offset, limit, err := args.page()
+		if err != nil {
+			return nil, err
+		}
+		connected, err := args.bool("connected", true)
+		if err != nil {
+			return nil, err
+		}
+		page, err := e.svc.sdk.ChannelsByThing(e.token, t.ID, offset, limit, connected)
+		if err != nil {
+			return nil, err
+		}
+		return channels(page.Channels), nil
+	default:
+		return nil, unknownField(t.typename(), name)
+	}
+}
+
+type channel sdk.Channel
+
+func (channel) typename() string { return "Channel" }
+
+func (c channel) id() string { return c.ID }
+
+func (c channel) resolve(e *executor, name string, args arguments) (interface{}, error) {
+	switch name {
+	case "id":
+		return c.ID, nil
+	case "name":
+		return c.Name, nil
+	case "metadata":
+		return c.Metadata, nil
+	case "things":
+		offset, limit, err := args.page()
+		if err != nil {
+			return nil, err
+		}
+		connected, err := args.bool("connected", true)
+		if err != nil {
+			return nil, err
+		}
+		page, err := e.svc.sdk.ThingsByChannel(e.token, c.ID, offset, limit, connected)
+		if err != nil {
+			return nil, err
+		}
+		return things(page.Things), nil
+	case "messages":
+		page, err := e.svc.sdk.ReadMessages(c.ID, e.token)
+		if err != nil {
+			return nil, err
+		}
+		return messages(page.Messages), nil
+	default:
+		return nil, unknownField(c.typename(), name)
+	}
+}
+
+type group sdk.Group
+
+func (group) typename() string { return "Group" }
+
+func (g group) id() string { return g.ID }
+
+func (g group) resolve(e *executor, name string, args arguments) (interface{}, error) {
+	switch name {
+	case "id":
+		return g.ID, nil
+	case "name":
+		return g.Name, nil
+	case "description":
+		return g.Description, nil
+	case "parentID":
+		return g.ParentID, nil
+	case "metadata":
+		return g.Metadata, nil
+	case "parents", "children":
+		offset, limit, err := args.page()
+		if err != nil {
+			return nil, err
+		}
+		list := e.svc.sdk.Parents
+		if name == "children" {
+			list = e.svc.sdk.Children
+		}
+		page, err := list(g.ID, offset, limit, e.token)
+		if err != nil {
+			return nil, err
+		}
+		return groups(page.Groups), nil
+	case "members":
+		offset, limit, err := args.page()
+		if err != nil {
+			return nil, err
+		}
+		page, err := e.svc.sdk.Members(g.ID, e.token, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		var objs []object
+		for _, m := range page.Members {
+			objs = append(objs, member(m))
+		}
+		return objs, nil
+	default:
+		return nil, unknownField(g.typename(), name)
+	}
+}
+
+type member sdk.Member
+
+func (member) typename() string { return "Member" }
+
+func (m member) id() string { return m.ID }
+
+func (m member) resolve(_ *executor, name string, _ arguments) (interface{}, error) {
+	switch name {
+	case "id":
+		return m.ID, nil
+	case "type":
+		return m.Type, nil
+	default:
+		return nil, unknownField(m.typename(), name)
+	}
+}
+
+type message senml.Message
+
+func (message) typename() string { return "Message" }
+
+func (m message) id() string { return m.Channel }
+
+func (m message) resolve(_ *executor, name string, _ arguments) (interface{}, error) {
+	switch name {
+	case "channel":
+		return m.Channel, nil
+	case "subtopic":
+		return m.Subtopic, nil
+	case "publisher":
+		return m.Publisher, nil
+	case "protocol":
+		return m.Protocol, nil
+	case "name":
+		return m.Name, nil
+	case "unit":
+		return m.Unit, nil
+	case "time":
+		return m.Time, nil
+	case "updateTime":
+		return m.UpdateTime, nil
+	case "value":
+		return m.Value, nil
+	case "stringValue":
+		return m.StringValue, nil
+	case "dataValue":
+		return m.DataValue, nil
+	case "boolValue":
+		return m.BoolValue, nil
+	case "sum":
+		return m.Sum, nil
+	default:
+		return nil, unknownField(m.typename(), name)
+	}
+}
+
+func things(ts []sdk.Thing) []object {
+	objs := []object{}
+	for _, t := range ts {
+		objs = append(objs, thing(t))
+	}
+	return objs
+}
+
+func channels(cs []sdk.Channel) []object {
+	objs := []object{}
+	for _, c := range cs {
+		objs = append(objs, channel(c))
+	}
+	return objs
+}
+
+func groups(gs []sdk.Group) []object {
+	objs := []object{}
+	for _, g := range gs {
+		objs = append(objs, group(g))
+	}
+	return objs
+}
+
+func messages(ms []senml.Message) []object {
+	objs := []object{}
+	for _, m := range ms {
+		objs = append(objs, message(m))
+	}
+	return objs
+}
@@ -0,0 +1,552 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrSyntax indicates that the query document is not a valid GraphQL
+// document or that it uses an unsupported feature.
+var ErrSyntax = errors.New("invalid query syntax")
+
+// document is a parsed GraphQL query document. Only the executable subset
+// needed by the gateway is supported: operations, fields, aliases,
+// arguments, variables and the @include/@skip directives.
+type document struct {
+	operations []operation
+}
+
+type operation struct {
+	kind       string
+	name       string
+	selections []field
+}
+
+type field struct {
+	alias      string
+	name       string
+	args       map[string]value
+	directives []directive
+	selections []field
+}
+
+// key returns the response key of the field.
+func (f field) key() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type directive struct {
+	name string
+	args map[string]value
+}
+
+// value is a literal argument value. Variables are represented by the
+// variable type and resolved at execution time.
+type value interface{}
+
+type variable string
+
+type enum string
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+type parser struct {
+	src   string
+	pos   int
+	tok   token
+	depth int
+}
+
+func parse(src string) (document, error) {
+	p := &parser{src: src}
+	if err := p.next(); err != nil {
+		return document{}, err
+	}
+
+	var doc document
+	for p.tok.kind != tokEOF {
+		op, err := p.parseOperation()
+		if err != nil {
+			return document{}, err
+		}
+		doc.operations = append(doc.operations, op)
+	}
+	if len(doc.operations) == 0 {
+		return document{}, p.errorf("empty document")
+	}
+	return doc, nil
+}
+
+func (p *parser) parseOperation() (operation, error) {
+	op := operation{kind: "query"}
+	if p.peek(tokPunct, "{") {
+		sels, err := p.parseSelectionSet()
+		if err != nil {
+			return operation{}, err
+		}
+		op.selections = sels
+		return op, nil
+	}
+
+	if p.tok.kind != tokName {
+		return operation{}, p.errorf("expected operation, got %q", p.tok.val)
+	}
+	switch p.tok.val {
+	case "query", "mutation", "subscription":
+		op.kind = p.tok.val
+	case "fragment":
+		return operation{}, p.errorf("fragments are not supported")
+	default:
+		return operation{}, p.errorf("unknown operation type %q", p.tok.val)
+	}
+	if err := p.next(); err != nil {
+		return operation{}, err
+	}
+
+	if p.tok.kind == tokName {
+		op.name = p.tok.val
+		if err := p.next(); err != nil {
+			return operation{}, err
+		}
+	}
+	if p.peek(tokPunct, "(") {
+		if err := p.skipVariableDefinitions(); err != nil {
+			return operation{}, err
+		}
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return operation{}, err
+	}
+
+	sels, err := p.parseSelectionSet()
+	if err != nil {
+		return operation{}, err
+	}
+	op.selections = sels
+	return op, nil
+}
+
+// skipVariableDefinitions skips variable definitions since variable values
+// are coerced by the resolvers and default values are not supported.
+func (p *parser) skipVariableDefinitions() error {
+	depth := 0
+	for {
+		switch {
+		case p.tok.kind == tokEOF:
+			return p.errorf("unterminated variable definitions")
+		case p.peek(tokPunct, "("):
+			depth++
+		case p.peek(tokPunct, ")"):
+			depth--
+		case p.peek(tokPunct, "="):
+			return p.errorf("variable default values are not supported")
+		}
+		if err := p.next(); err != nil {
+			return err
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseSelectionSet() ([]field, error) {
+	if err := p.nest(); err != nil {
+		return nil, err
+	}
+	defer p.unnest()
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var fields []field
+	for !p.peek(tokPunct, "}") {
+		if p.peek(tokPunct, "...") {
+			return nil, p.errorf("fragments are not supported")
+		}
+		f, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+
+	return fields, p.next()
+}
+
+func (p *parser) parseField() (field, error) {
+	name, err := p.parseName()
+	if err != nil {
+		return field{}, err
+	}
+
+	f := field{name: name}
+	if p.peek(tokPunct, ":") {
+		if err := p.next(); err != nil {
+			return field{}, err
+		}
+		if f.name, err = p.parseName(); err != nil {
+			return field{}, err
+		}
+		f.alias = name
+	}
+
+	if p.peek(tokPunct, "(") {
+		if f.args, err = p.parseArguments(); err != nil {
+			return field{}, err
+		}
+	}
+
+	if f.directives, err = p.parseDirectives(); err != nil {
+		return field{}, err
+	}
+
+	if p.peek(tokPunct, "{") {
+		if f.selections, err = p.parseSelectionSet(); err != nil {
+			return field{}, err
+		}
+	}
+
+	return f, nil
+}
+
+func (p *parser) parseDirectives() ([]directive, error) {
+	var dirs []directive
+	for p.peek(tokPunct, "@") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		d := directive{name: name}
+		if p.peek(tokPunct, "(") {
+			if d.args, err = p.parseArguments(); err != nil {
+				return nil, err
+			}
+		}
+		dirs = append(dirs, d)
+	}
+	return dirs, nil
+}
+
+func (p *parser) parseArguments() (map[string]value, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+
+	args := map[string]value{}
+	for !p.peek(tokPunct, ")") {
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		val, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = val
+	}
+
+	return args, p.next()
+}
+
+func (p *parser) parseValue() (value, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokInt:
+		v, err := strconv.ParseInt(tok.val, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid integer %q", tok.val)
+		}
+		return v, p.next()
+	case tokFloat:
+		v, err := strconv.ParseFloat(tok.val, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %q", tok.val)
+		}
+		return v, p.next()
+	case tokString:
+		return tok.val, p.next()
+	case tokName:
+		var v value
+		switch tok.val {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = enum(tok.val)
+		}
+		return v, p.next()
+	}
+
+	if p.peek(tokPunct, "[") || p.peek(tokPunct, "{") {
+		if err := p.nest(); err != nil {
+			return nil, err
+		}
+		defer p.unnest()
+	}
+
+	switch {
+	case p.peek(tokPunct, "$"):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		name, err := p.parseName()
+		if err != nil {
+			return nil, err
+		}
+		return variable(name), nil
+	case p.peek(tokPunct, "["):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		list := []value{}
+		for !p.peek(tokPunct, "]") {
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, p.next()
+	case p.peek(tokPunct, "{"):
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		obj := map[string]value{}
+		for !p.peek(tokPunct, "}") {
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(":"); err != nil {
+				return nil, err
+			}
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			obj[name] = v
+		}
+		return obj, p.next()
+	}
+
+	return nil, p.errorf("unexpected %q", tok.val)
+}
+
+// nest enters the nested selection set or value. The depth is checked while
+// parsing, so the deeply nested documents are rejected before they are
+// recursed into any further.
+func (p *parser) nest() error {
+	if p.depth++; p.depth > maxDepth {
+		return errors.Wrap(ErrQueryTooComplex, fmt.Errorf("query is nested deeper than %d levels", maxDepth))
+	}
+	return nil
+}
+
+func (p *parser) unnest() {
+	p.depth--
+}
+
+func (p *parser) parseName() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.errorf("expected name, got %q", p.tok.val)
+	}
+	name := p.tok.val
+	return name, p.next()
+}
+
+func (p *parser) peek(kind tokenKind, val string) bool {
+	return p.tok.kind == kind && p.tok.val == val
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(tokPunct, punct) {
+		return p.errorf("expected %q, got %q", punct, p.tok.val)
+	}
+	return p.next()
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	return errors.Wrap(ErrSyntax, fmt.Errorf("%s at position %d", msg, p.tok.pos))
+}
+
+// next reads the next token from the source, skipping white space,
+// commas and comments which are insignificant in GraphQL.
+func (p *parser) next() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+			continue
+		}
+		if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+			continue
+		}
+		break
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return nil
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokPunct, val: "...", pos: start}
+	case strings.IndexByte("!$()[]{}:=@|", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokPunct, val: string(c), pos: start}
+	case c == '_' || isLetter(c):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokName, val: p.src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		return p.readNumber()
+	case c == '"':
+		return p.readString()
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.tok = token{kind: tokPunct, val: string(r), pos: start}
+		return p.errorf("unexpected character %q", r)
+	}
+
+	return nil
+}
+
+func (p *parser) readNumber() error {
+	start := p.pos
+	kind := tokInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case isDigit(c):
+		case c == '.' || c == 'e' || c == 'E' || ((c == '+' || c == '-') && kind == tokFloat):
+			kind = tokFloat
+		default:
+			p.tok = token{kind: kind, val: p.src[start:p.pos], pos: start}
+			return nil
+		}
+		p.pos++
+	}
+	p.tok = token{kind: kind, val: p.src[start:p.pos], pos: start}
+	return nil
+}
+
+func (p *parser) readString() error {
+	start := p.pos
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		p.tok = token{kind: tokString, pos: start}
+		return p.errorf("block strings are not supported")
+	}
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			p.tok = token{kind: tokString, val: sb.String(), pos: start}
+			return nil
+		case '\n':
+			p.tok = token{kind: tokString, pos: start}
+			return p.errorf("unterminated string")
+		case '\\':
+			if p.pos+1 >= len(p.src) {
+				break
+			}
+			esc := p.src[p.pos+1]
+			p.pos += 2
+			switch esc {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'f':
+				sb.WriteByte('\f')
+			case 'u':
+				if p.pos+4 > len(p.src) {
+					p.tok = token{kind: tokString, pos: start}
+					return p.errorf("invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32)
+				if err != nil {
+					p.tok = token{kind: tokString, pos: start}
+					return p.errorf("invalid unicode escape")
+				}
+				sb.WriteRune(rune(r))
+				p.pos += 4
+			default:
+				sb.WriteByte(esc)
+			}
+			continue
+		default:
+			sb.WriteByte(c)
+		}
+		p.pos++
+	}
+
+	p.tok = token{kind: tokString, pos: start}
+	return p.errorf("unterminated string")
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,373 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+)
+
+const (
+	authoritiesObject = "authorities"
+	memberRelation    = "member"
+
+	// maxDepth is the maximal nesting of the selection sets and the argument
+	// values of the query.
+	maxDepth = 10
+	// maxCost is the maximal cost of the query, i.e. the maximal number of
+	// the fields resolved by the query.
+	maxCost = 10000
+)
+
+var (
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrMalformedEntity indicates malformed request.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrUnknownOperation indicates that the requested operation is not
+	// present in the query document or that it is not a query.
+	ErrUnknownOperation = errors.New("unknown or unsupported operation")
+
+	// ErrFieldAccess indicates that the caller is not allowed to read the
+	// requested field.
+	ErrFieldAccess = errors.New("not allowed to access field")
+
+	// ErrQueryTooComplex indicates that the query exceeds the maximal depth
+	// or cost, so it's rejected without being executed.
+	ErrQueryTooComplex = errors.New("query is too complex")
+
+	errUnknownField = errors.New("unknown field")
+	errInvalidArg   = errors.New("invalid argument")
+)
+
+// DefaultFieldPolicies lists the fields which require the caller to have
+// the given relation over the object the field belongs to.
+var DefaultFieldPolicies = map[string]string{
+	"Thing.key": "write",
+}
+
+// Request represents a GraphQL request.
+type Request struct {
+	Query         string
+	OperationName string
+	Variables     map[string]interface{}
+}
+
+// Error represents an error which occurred while resolving a field.
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Response represents the result of GraphQL query execution. Fields that
+// failed to resolve are set to null and the reason is reported in Errors.
+type Response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []Error                `json:"errors,omitempty"`
+}
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Execute executes the GraphQL query on behalf of the identified user,
+	// whose token is forwarded to the underlying services. A non-nil error
+	// is returned only if the request can't be executed at all; field level
+	// failures are reported in the response.
+	Execute(ctx context.Context, userID, token string, req Request) (Response, error)
+}
+
+var _ Service = (*graphqlService)(nil)
+
+type graphqlService struct {
+	sdk      sdk.SDK
+	auth     mainflux.AuthServiceClient
+	policies map[string]string
+}
+
+// New instantiates the GraphQL gateway service implementation. Field
+// policies map "Type.field" keys to the relation the caller must have over
+// the object to read the field.
+func New(sdk sdk.SDK, auth mainflux.AuthServiceClient, policies map[string]string) Service {
+	return &graphqlService{
+		sdk:      sdk,
+		auth:     auth,
+		policies: policies,
+	}
+}
+
+func (gs *graphqlService) Execute(ctx context.Context, userID, token string, req Request) (Response, error) {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return Response{}, err
+	}
+
+	op, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return Response{}, err
+	}
+
+	if c := cost(op.selections, req.Variables); c > maxCost {
+		return Response{}, errors.Wrap(ErrQueryTooComplex, fmt.Errorf("query cost exceeds the maximum of %d", maxCost))
+	}
+
+	e := &executor{
+		ctx:    ctx,
+		svc:    gs,
+		token:  token,
+		userID: userID,
+		vars:   req.Variables,
+	}
+
+	return Response{
+		Data:   e.selectionSet(query{}, op.selections, nil),
+		Errors: e.errors,
+	}, nil
+}
+
+// authorize checks if the user has the relation over the object. Members of
+// the authorities object are allowed to access any field.
+func (gs *graphqlService) authorize(ctx context.Context, subject, object, relation string) error {
+	req := &mainflux.AuthorizeReq{Sub: subject, Obj: object, Act: relation}
+	res, err := gs.auth.Authorize(ctx, req)
+	if err == nil && res.GetAuthorized() {
+		return nil
+	}
+
+	req = &mainflux.AuthorizeReq{Sub: subject, Obj: authoritiesObject, Act: memberRelation}
+	if res, err := gs.auth.Authorize(ctx, req); err == nil && res.GetAuthorized() {
+		return nil
+	}
+
+	return ErrFieldAccess
+}
+
+func selectOperation(doc document, name string) (operation, error) {
+	var op *operation
+	for i := range doc.operations {
+		if name == "" || doc.operations[i].name == name {
+			if op != nil {
+				return operation{}, errors.Wrap(ErrUnknownOperation, fmt.Errorf("operation name is required"))
+			}
+			op = &doc.operations[i]
+		}
+	}
+	if op == nil {
+		return operation{}, errors.Wrap(ErrUnknownOperation, fmt.Errorf("operation %q not found", name))
+	}
+	if op.kind != "query" {
+		return operation{}, errors.Wrap(ErrUnknownOperation, fmt.Errorf("%s operations are not supported", op.kind))
+	}
+	return *op, nil
+}
+
+// listFields are the fields resolved to the lists of objects, whose
+// selections are resolved for each object of the list.
+var listFields = map[string]bool{
+	"things":   true,
+	"channels": true,
+	"groups":   true,
+	"parents":  true,
+	"children": true,
+	"members":  true,
+	"messages": true,
+}
+
+// cost estimates the number of the fields the selections resolve to. The
+// selections of the list fields are counted once per the object of the
+// page, where the limit which can't be determined counts as the maximal
+// one. The cost exceeding the maximum is reported as the maximum plus one.
+func cost(sels []field, vars map[string]interface{}) uint64 {
+	var total uint64
+	for _, f := range sels {
+		n := uint64(1)
+		if listFields[f.name] {
+			n = pageLimit(f, vars)
+		}
+		total += 1 + n*cost(f.selections, vars)
+		if total > maxCost {
+			return maxCost + 1
+		}
+	}
+	return total
+}
+
+func pageLimit(f field, vars map[string]interface{}) uint64 {
+	v, ok := f.args["limit"]
+	if !ok {
+		return defLimit
+	}
+	if name, ok := v.(variable); ok {
+		if v, ok = vars[string(name)]; !ok {
+			return defLimit
+		}
+	}
+	switch v := v.(type) {
+	case int64:
+		if v >= 0 && v <= maxLimit {
+			return uint64(v)
+		}
+	case float64:
+		if v >= 0 && v <= maxLimit {
+			return uint64(v)
+		}
+	}
+	return maxLimit
+}
+
+// executor holds the state of a single query execution.
+type executor struct {
+	ctx    context.Context
+	svc    *graphqlService
+	token  string
+	userID string
+	vars   map[string]interface{}
+	errors []Error
+}
+
+func (e *executor) selectionSet(obj object, sels []field, path []interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	for _, f := range sels {
+		include, err := e.included(f)
+		if err != nil {
+			e.fail(append(path, f.key()), err)
+			continue
+		}
+		if !include {
+			continue
+		}
+
+		fpath := append(append([]interface{}{}, path...), f.key())
+		if f.name == "__typename" {
+			res[f.key()] = obj.typename()
+			continue
+		}
+
+		args, err := e.arguments(f.args)
+		if err != nil {
+			res[f.key()] = nil
+			e.fail(fpath, err)
+			continue
+		}
+
+		if relation, ok := e.svc.policies[obj.typename()+"."+f.name]; ok {
+			if err := e.svc.authorize(e.ctx, e.userID, obj.id(), relation); err != nil {
+				res[f.key()] = nil
+				e.fail(fpath, err)
+				continue
+			}
+		}
+
+		val, err := obj.resolve(e, f.name, args)
+		if err != nil {
+			res[f.key()] = nil
+			e.fail(fpath, err)
+			continue
+		}
+		res[f.key()] = e.complete(val, f, fpath)
+	}
+	return res
+}
+
+// complete converts the resolved value to the response representation,
+// resolving the sub-selections of objects and lists of objects.
+func (e *executor) complete(val interface{}, f field, path []interface{}) interface{} {
+	switch v := val.(type) {
+	case object:
+		if len(f.selections) == 0 {
+			e.fail(path, errors.Wrap(ErrMalformedEntity, fmt.Errorf("field %q of type %s requires a selection", f.name, v.typename())))
+			return nil
+		}
+		return e.selectionSet(v, f.selections, path)
+	case []object:
+		list := make([]interface{}, len(v))
+		for i, o := range v {
+			list[i] = e.complete(o, f, append(append([]interface{}{}, path...), i))
+		}
+		return list
+	default:
+		if len(f.selections) > 0 {
+			e.fail(path, errors.Wrap(ErrMalformedEntity, fmt.Errorf("field %q is a scalar and has no sub-fields", f.name)))
+			return nil
+		}
+		return val
+	}
+}
+
+func (e *executor) included(f field) (bool, error) {
+	for _, d := range f.directives {
+		if d.name != "include" && d.name != "skip" {
+			continue
+		}
+		args, err := e.arguments(d.args)
+		if err != nil {
+			return false, err
+		}
+		cond, ok := args["if"].(bool)
+		if !ok {
+			return false, errors.Wrap(errInvalidArg, fmt.Errorf("@%s requires a boolean argument \"if\"", d.name))
+		}
+		if (d.name == "include") != cond {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (e *executor) arguments(args map[string]value) (arguments, error) {
+	res := arguments{}
+	for name, v := range args {
+		val, err := e.value(v)
+		if err != nil {
+			return nil, err
+		}
+		res[name] = val
+	}
+	return res, nil
+}
+
+func (e *executor) value(v value) (interface{}, error) {
+	switch v := v.(type) {
+	case variable:
+		val, ok := e.vars[string(v)]
+		if !ok {
+			return nil, errors.Wrap(errInvalidArg, fmt.Errorf("variable $%s is not provided", v))
+		}
+		return val, nil
+	case enum:
+		return string(v), nil
+	case []value:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			val, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = val
+		}
+		return list, nil
+	case map[string]value:
+		obj := map[string]interface{}{}
+		for k, item := range v {
+			val, err := e.value(item)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = val
+		}
+		return obj, nil
+	default:
+		return v, nil
+	}
+}
+
+func (e *executor) fail(path []interface{}, err error) {
+	e.errors = append(e.errors, Error{Message: err.Error(), Path: path})
+}
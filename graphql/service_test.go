@@ -0,0 +1,197 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/graphql"
+	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/things/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	token      = "token"
+	adminToken = "admin-token"
+	userID     = "user"
+	adminID    = "admin"
+)
+
+var thingList = []sdk.Thing{
+	{ID: "1", Name: "first", Key: "key1"},
+	{ID: "2", Name: "second", Key: "key2"},
+	{ID: "3", Name: "third", Key: "key3"},
+}
+
+// sdkMock implements only the SDK methods needed to resolve things.
+type sdkMock struct {
+	sdk.SDK
+}
+
+func (sdkMock) Thing(id, token string) (sdk.Thing, error) {
+	for _, t := range thingList {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return sdk.Thing{}, sdk.ErrFailedFetch
+}
+
+func (sdkMock) Things(token string, offset, limit uint64, name string) (sdk.ThingsPage, error) {
+	page := sdk.ThingsPage{}
+	for i, t := range thingList {
+		if uint64(i) >= offset && uint64(i) < offset+limit {
+			page.Things = append(page.Things, t)
+		}
+	}
+	return page, nil
+}
+
+func newService() graphql.Service {
+	users := map[string]string{token: userID, adminToken: adminID}
+	policies := map[string][]mocks.MockSubjectSet{
+		userID:  {{Object: "1", Relation: "write"}},
+		adminID: {{Object: "authorities", Relation: "member"}},
+	}
+	auth := mocks.NewAuthService(users, policies)
+	return graphql.New(sdkMock{}, auth, graphql.DefaultFieldPolicies)
+}
+
+func TestExecute(t *testing.T) {
+	svc := newService()
+	users := map[string]string{token: userID, adminToken: adminID}
+
+	cases := []struct {
+		desc   string
+		token  string
+		req    graphql.Request
+		data   map[string]interface{}
+		errors int
+		err    error
+	}{
+		{
+			desc:  "execute query with aliases and arguments",
+			token: token,
+			req:   graphql.Request{Query: `{ first: thing(id: "1") { name } things(offset: 1, limit: 1) { id } }`},
+			data: map[string]interface{}{
+				"first":  map[string]interface{}{"name": "first"},
+				"things": []interface{}{map[string]interface{}{"id": "2"}},
+			},
+		},
+		{
+			desc:  "execute query with variables and directives",
+			token: token,
+			req: graphql.Request{
+				Query:     `query Thing($id: String!, $withName: Boolean!) { thing(id: $id) { id name @include(if: $withName) __typename } }`,
+				Variables: map[string]interface{}{"id": "2", "withName": false},
+			},
+			data: map[string]interface{}{
+				"thing": map[string]interface{}{"id": "2", "__typename": "Thing"},
+			},
+		},
+		{
+			desc:  "read key of thing with write relation",
+			token: token,
+			req:   graphql.Request{Query: `{ thing(id: "1") { key } }`},
+			data: map[string]interface{}{
+				"thing": map[string]interface{}{"key": "key1"},
+			},
+		},
+		{
+			desc:  "read key of thing without write relation",
+			token: token,
+			req:   graphql.Request{Query: `{ thing(id: "2") { name key } }`},
+			data: map[string]interface{}{
+				"thing": map[string]interface{}{"name": "second", "key": nil},
+			},
+			errors: 1,
+		},
+		{
+			desc:  "read key of thing as admin",
+			token: adminToken,
+			req:   graphql.Request{Query: `{ thing(id: "2") { key } }`},
+			data: map[string]interface{}{
+				"thing": map[string]interface{}{"key": "key2"},
+			},
+		},
+		{
+			desc:  "read unknown field",
+			token: token,
+			req:   graphql.Request{Query: `{ thing(id: "1") { unknown } }`},
+			data: map[string]interface{}{
+				"thing": map[string]interface{}{"unknown": nil},
+			},
+			errors: 1,
+		},
+		{
+			desc:  "read things with limit over the maximum",
+			token: token,
+			req:   graphql.Request{Query: `{ things(limit: 1000) { id } }`},
+			data: map[string]interface{}{
+				"things": nil,
+			},
+			errors: 1,
+		},
+		{
+			desc:  "execute query nested deeper than the maximum",
+			token: token,
+			req:   graphql.Request{Query: `{ me { groups { children { children { children { children { children { children { children { children { children { id } } } } } } } } } } } }`},
+			err:   graphql.ErrQueryTooComplex,
+		},
+		{
+			desc:  "execute query with argument nested deeper than the maximum",
+			token: token,
+			req:   graphql.Request{Query: "{ things(limit: " + strings.Repeat("[", 100000) + ") { id } }"},
+			err:   graphql.ErrQueryTooComplex,
+		},
+		{
+			desc:  "execute query over the maximal cost",
+			token: token,
+			req:   graphql.Request{Query: `{ things(limit: 100) { channels(limit: 100) { id name } } }`},
+			err:   graphql.ErrQueryTooComplex,
+		},
+		{
+			desc:  "execute query over the maximal cost using variables",
+			token: token,
+			req: graphql.Request{
+				Query:     `query Things($limit: Int) { things(limit: $limit) { channels(limit: $limit) { id name } } }`,
+				Variables: map[string]interface{}{"limit": float64(100)},
+			},
+			err: graphql.ErrQueryTooComplex,
+		},
+		{
+			desc:  "execute malformed query",
+			token: token,
+			req:   graphql.Request{Query: `{ things { id }`},
+			err:   graphql.ErrSyntax,
+		},
+		{
+			desc:  "execute mutation",
+			token: token,
+			req:   graphql.Request{Query: `mutation { things { id } }`},
+			err:   graphql.ErrUnknownOperation,
+		},
+		{
+			desc:  "execute unknown operation",
+			token: token,
+			req:   graphql.Request{Query: `query A { things { id } } query B { me { id } }`, OperationName: "C"},
+			err:   graphql.ErrUnknownOperation,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := svc.Execute(context.Background(), users[tc.token], tc.token, tc.req)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		assert.Equal(t, tc.data, res.Data, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.data, res.Data))
+		assert.Len(t, res.Errors, tc.errors, fmt.Sprintf("%s: expected %d errors got %v\n", tc.desc, tc.errors, res.Errors))
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"context"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/payload"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/things"
 )
 
 // Service specifies coap service API.
@@ -23,20 +25,24 @@ var _ Service = (*adapterService)(nil)
 type adapterService struct {
 	publisher messaging.Publisher
 	things    mainflux.ThingsServiceClient
+	payloads  payload.Checker
 }
 
-// New instantiates the HTTP adapter implementation.
-func New(publisher messaging.Publisher, things mainflux.ThingsServiceClient) Service {
+// New instantiates the HTTP adapter implementation. The messages violating
+// the payload policies aren't published.
+func New(publisher messaging.Publisher, things mainflux.ThingsServiceClient, payloads payload.Checker) Service {
 	return &adapterService{
 		publisher: publisher,
 		things:    things,
+		payloads:  payloads,
 	}
 }
 
 func (as *adapterService) Publish(ctx context.Context, token string, msg messaging.Message) error {
 	ar := &mainflux.AccessByKeyReq{
-		Token:  token,
-		ChanID: msg.Channel,
+		Token:     token,
+		ChanID:    msg.Channel,
+		Operation: things.PublishOp,
 	}
 	thid, err := as.things.CanAccessByKey(ctx, ar)
 	if err != nil {
@@ -44,5 +50,9 @@ func (as *adapterService) Publish(ctx context.Context, token string, msg messagi
 	}
 	msg.Publisher = thid.GetValue()
 
+	if err := as.payloads.Check(msg); err != nil {
+		return err
+	}
+
 	return as.publisher.Publish(msg.Channel, msg)
 }
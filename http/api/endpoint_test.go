@@ -17,12 +17,18 @@ import (
 	adapter "github.com/mainflux/mainflux/http"
 	"github.com/mainflux/mainflux/http/api"
 	"github.com/mainflux/mainflux/http/mocks"
+	"github.com/mainflux/mainflux/internal/payload"
 	"github.com/stretchr/testify/assert"
 )
 
+const maxPayloadSize = 1024
+
 func newService(cc mainflux.ThingsServiceClient) adapter.Service {
 	pub := mocks.NewPublisher()
-	return adapter.New(pub, cc)
+	payloads := payload.NewChecker(payload.Policies{
+		Default: payload.Policy{MaxSize: maxPayloadSize, ContentTypes: []string{"application/senml+json"}},
+	})
+	return adapter.New(pub, cc, payloads)
 }
 
 func newHTTPServer(svc adapter.Service) *httptest.Server {
@@ -121,6 +127,20 @@ func TestPublish(t *testing.T) {
 			auth:        token,
 			status:      http.StatusAccepted,
 		},
+		"publish too large message": {
+			chanID:      chanID,
+			msg:         strings.Repeat("a", maxPayloadSize+1),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusRequestEntityTooLarge,
+		},
+		"publish message of unsupported content type": {
+			chanID:      chanID,
+			msg:         msg,
+			contentType: "text/plain",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
 		"publish message to invalid channel": {
 			chanID:      "",
 			msg:         msg,
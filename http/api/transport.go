@@ -19,6 +19,7 @@ import (
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	adapter "github.com/mainflux/mainflux/http"
+	"github.com/mainflux/mainflux/internal/payload"
 	"github.com/mainflux/mainflux/pkg/messaging"
 	"github.com/mainflux/mainflux/things"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -114,11 +115,12 @@ func decodeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
 	}
 
 	msg := messaging.Message{
-		Protocol: protocol,
-		Channel:  chanID,
-		Subtopic: subtopic,
-		Payload:  payload,
-		Created:  time.Now().UnixNano(),
+		Protocol:    protocol,
+		Channel:     chanID,
+		Subtopic:    subtopic,
+		Payload:     payload,
+		ContentType: r.Header.Get("Content-Type"),
+		Created:     time.Now().UnixNano(),
 	}
 
 	req := publishReq{
@@ -150,11 +152,17 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusBadRequest)
 	case things.ErrUnauthorizedAccess:
 		w.WriteHeader(http.StatusForbidden)
+	case payload.ErrTooLarge:
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	case payload.ErrUnsupportedContentType:
+		w.WriteHeader(http.StatusUnsupportedMediaType)
 	default:
 		if e, ok := status.FromError(err); ok {
 			switch e.Code() {
 			case codes.PermissionDenied:
 				w.WriteHeader(http.StatusForbidden)
+			case codes.ResourceExhausted:
+				w.WriteHeader(http.StatusTooManyRequests)
 			default:
 				w.WriteHeader(http.StatusServiceUnavailable)
 			}
@@ -0,0 +1,116 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cardinality guards metrics labels against unbounded cardinality.
+// Every distinct label value creates a new time series in Prometheus, so
+// labeling metrics with values such as channel or tenant IDs is only safe if
+// the number of distinct values is bounded.
+package cardinality
+
+import "sync"
+
+// Other is the label value reported for the values which are not tracked.
+const Other = "other"
+
+const (
+	// candidatesFactor bounds the number of the values counted, as the
+	// multiple of the limit, so the memory used doesn't grow with the
+	// number of distinct values.
+	candidatesFactor = 10
+
+	// agingFactor is the number of the observations, as the multiple of
+	// the counted values, after which the counts are halved, so the values
+	// which stopped being observed give way to the current ones.
+	agingFactor = 100
+)
+
+// Limiter bounds the number of distinct label values to the limit. The
+// values observed most frequently are reported as is, while the rest of the
+// values are reported as Other. The frequencies are estimated using the
+// bounded number of counters, and the past observations are gradually
+// forgotten, so the value observed frequently enough replaces the tracked
+// value observed the least.
+type Limiter struct {
+	mu       sync.Mutex
+	limit    int
+	counts   map[string]uint64
+	top      map[string]bool
+	observed int
+}
+
+// NewLimiter returns the limiter tracking at most limit label values.
+func NewLimiter(limit int) *Limiter {
+	return &Limiter{
+		limit:  limit,
+		counts: make(map[string]uint64),
+		top:    make(map[string]bool),
+	}
+}
+
+// Label returns the label value to report for the value. Empty values are
+// reported as is.
+func (l *Limiter) Label(value string) string {
+	if value == "" {
+		return value
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limit <= 0 {
+		return Other
+	}
+
+	l.count(value)
+
+	if l.top[value] {
+		return value
+	}
+	if len(l.top) < l.limit {
+		l.top[value] = true
+		return value
+	}
+
+	min, minCount := "", uint64(0)
+	for v := range l.top {
+		if c := l.counts[v]; min == "" || c < minCount {
+			min, minCount = v, c
+		}
+	}
+	if l.counts[value] <= minCount {
+		return Other
+	}
+	delete(l.top, min)
+	l.top[value] = true
+	return value
+}
+
+// count increments the count of the value. Once all the counters are taken,
+// the value replaces the least observed value which isn't tracked, taking
+// over its count, so the values observed often enough eventually get
+// tracked.
+func (l *Limiter) count(value string) {
+	if _, ok := l.counts[value]; !ok && len(l.counts) >= l.limit*candidatesFactor {
+		min, minCount := "", uint64(0)
+		for v, c := range l.counts {
+			if !l.top[v] && (min == "" || c < minCount) {
+				min, minCount = v, c
+			}
+		}
+		delete(l.counts, min)
+		l.counts[value] = minCount
+	}
+	l.counts[value]++
+
+	if l.observed++; l.observed < l.limit*candidatesFactor*agingFactor {
+		return
+	}
+	l.observed = 0
+	for v, c := range l.counts {
+		if c /= 2; c == 0 && !l.top[v] {
+			delete(l.counts, v)
+			continue
+		}
+		l.counts[v] = c
+	}
+}
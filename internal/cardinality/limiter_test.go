@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package cardinality_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/internal/cardinality"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabel(t *testing.T) {
+	l := cardinality.NewLimiter(2)
+
+	cases := []struct {
+		desc     string
+		value    string
+		expected string
+	}{
+		{
+			desc:     "label first value",
+			value:    "ch1",
+			expected: "ch1",
+		},
+		{
+			desc:     "label empty value",
+			value:    "",
+			expected: "",
+		},
+		{
+			desc:     "label second value",
+			value:    "ch2",
+			expected: "ch2",
+		},
+		{
+			desc:     "label value exceeding limit",
+			value:    "ch3",
+			expected: cardinality.Other,
+		},
+		{
+			desc:     "label tracked value",
+			value:    "ch1",
+			expected: "ch1",
+		},
+		{
+			desc:     "label value observed more than tracked value",
+			value:    "ch3",
+			expected: "ch3",
+		},
+		{
+			desc:     "label replaced value",
+			value:    "ch2",
+			expected: cardinality.Other,
+		},
+	}
+
+	for _, tc := range cases {
+		label := l.Label(tc.value)
+		assert.Equal(t, tc.expected, label, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.expected, label))
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package chaos provides opt-in fault injection for Mainflux services. It is
+// meant to be used in staging environments to rehearse failure scenarios,
+// such as slow responses, failing requests and lost broker messages.
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	envEnabled    = "CHAOS_ENABLED"
+	envLatency    = "CHAOS_LATENCY"
+	envJitter     = "CHAOS_JITTER"
+	envErrorRate  = "CHAOS_ERROR_RATE"
+	envDropRate   = "CHAOS_DROP_RATE"
+	envAdminToken = "CHAOS_ADMIN_TOKEN"
+)
+
+var (
+	// ErrInjected is returned by the operations failed on purpose.
+	ErrInjected = errors.New("injected fault")
+
+	// ErrMalformedConfig indicates invalid fault injection configuration.
+	ErrMalformedConfig = errors.New("malformed fault injection configuration")
+)
+
+// Config contains fault injection parameters.
+type Config struct {
+	// Latency is added to each operation.
+	Latency time.Duration
+	// Jitter is the maximal random duration added to the latency.
+	Jitter time.Duration
+	// ErrorRate is the probability, between 0 and 1, of an operation failure.
+	ErrorRate float64
+	// DropRate is the probability, between 0 and 1, of a message being
+	// silently dropped by the message broker wrappers.
+	DropRate float64
+}
+
+// Validate checks if the configuration values are in range.
+func (cfg Config) Validate() error {
+	if cfg.Latency < 0 || cfg.Jitter < 0 ||
+		cfg.ErrorRate < 0 || cfg.ErrorRate > 1 ||
+		cfg.DropRate < 0 || cfg.DropRate > 1 {
+		return ErrMalformedConfig
+	}
+	return nil
+}
+
+// Settings contain the fault injection configuration loaded from the
+// environment.
+type Settings struct {
+	// Enabled turns the fault injection on.
+	Enabled bool
+	// AdminToken protects the admin endpoint used to change the
+	// configuration at runtime. Admin endpoint is disabled if it's empty.
+	AdminToken string
+	// Config is the initial fault injection configuration.
+	Config Config
+}
+
+// LoadSettings reads the settings from the environment variables prefixed
+// with the service prefix, e.g. MF_HTTP_ADAPTER_CHAOS_LATENCY for the
+// "MF_HTTP_ADAPTER_" prefix.
+func LoadSettings(prefix string) (Settings, error) {
+	enabled, err := strconv.ParseBool(mainflux.Env(prefix+envEnabled, "false"))
+	if err != nil {
+		return Settings{}, errors.Wrap(ErrMalformedConfig, fmt.Errorf("%s%s: %s", prefix, envEnabled, err))
+	}
+
+	latency, err := time.ParseDuration(mainflux.Env(prefix+envLatency, "0s"))
+	if err != nil {
+		return Settings{}, errors.Wrap(ErrMalformedConfig, fmt.Errorf("%s%s: %s", prefix, envLatency, err))
+	}
+
+	jitter, err := time.ParseDuration(mainflux.Env(prefix+envJitter, "0s"))
+	if err != nil {
+		return Settings{}, errors.Wrap(ErrMalformedConfig, fmt.Errorf("%s%s: %s", prefix, envJitter, err))
+	}
+
+	errorRate, err := strconv.ParseFloat(mainflux.Env(prefix+envErrorRate, "0"), 64)
+	if err != nil {
+		return Settings{}, errors.Wrap(ErrMalformedConfig, fmt.Errorf("%s%s: %s", prefix, envErrorRate, err))
+	}
+
+	dropRate, err := strconv.ParseFloat(mainflux.Env(prefix+envDropRate, "0"), 64)
+	if err != nil {
+		return Settings{}, errors.Wrap(ErrMalformedConfig, fmt.Errorf("%s%s: %s", prefix, envDropRate, err))
+	}
+
+	cfg := Config{
+		Latency:   latency,
+		Jitter:    jitter,
+		ErrorRate: errorRate,
+		DropRate:  dropRate,
+	}
+	if err := cfg.Validate(); err != nil {
+		return Settings{}, err
+	}
+
+	return Settings{
+		Enabled:    enabled,
+		AdminToken: mainflux.Env(prefix+envAdminToken, ""),
+		Config:     cfg,
+	}, nil
+}
+
+// Injector decides which faults are injected. It's safe for concurrent use
+// and its configuration can be changed at runtime.
+type Injector struct {
+	mu  sync.Mutex
+	cfg Config
+	rnd *rand.Rand
+}
+
+// NewInjector returns new injector using the given configuration.
+func NewInjector(cfg Config) *Injector {
+	return &Injector{
+		cfg: cfg,
+		rnd: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Config returns the current configuration.
+func (inj *Injector) Config() Config {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	return inj.cfg
+}
+
+// Update replaces the current configuration.
+func (inj *Injector) Update(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	inj.cfg = cfg
+	return nil
+}
+
+// Delay returns the latency to be added to the operation.
+func (inj *Injector) Delay() time.Duration {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	d := inj.cfg.Latency
+	if inj.cfg.Jitter > 0 {
+		d += time.Duration(inj.rnd.Int63n(int64(inj.cfg.Jitter) + 1))
+	}
+	return d
+}
+
+// Fail reports whether the operation should fail.
+func (inj *Injector) Fail() bool {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	return inj.cfg.ErrorRate > 0 && inj.rnd.Float64() < inj.cfg.ErrorRate
+}
+
+// Drop reports whether the message should be dropped.
+func (inj *Injector) Drop() bool {
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+
+	return inj.cfg.DropRate > 0 && inj.rnd.Float64() < inj.cfg.DropRate
+}
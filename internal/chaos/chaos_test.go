@@ -0,0 +1,212 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package chaos_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/internal/chaos"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+)
+
+const adminToken = "admin-token"
+
+type publisherMock struct {
+	published int
+}
+
+func (pm *publisherMock) Publish(topic string, msg messaging.Message) error {
+	pm.published++
+	return nil
+}
+
+type pubSubMock struct {
+	publisherMock
+	handler messaging.MessageHandler
+}
+
+func (ps *pubSubMock) Subscribe(topic string, handler messaging.MessageHandler) error {
+	ps.handler = handler
+	return nil
+}
+
+func (ps *pubSubMock) Unsubscribe(topic string) error {
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	cases := []struct {
+		desc      string
+		cfg       chaos.Config
+		published int
+		err       error
+	}{
+		{
+			desc:      "publish without faults",
+			cfg:       chaos.Config{},
+			published: 1,
+		},
+		{
+			desc:      "publish with injected error",
+			cfg:       chaos.Config{ErrorRate: 1},
+			published: 0,
+			err:       chaos.ErrInjected,
+		},
+		{
+			desc:      "publish dropped message",
+			cfg:       chaos.Config{DropRate: 1},
+			published: 0,
+		},
+		{
+			desc:      "publish with latency",
+			cfg:       chaos.Config{Latency: 10 * time.Millisecond},
+			published: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		pm := &publisherMock{}
+		pub := chaos.NewPublisher(chaos.NewInjector(tc.cfg), pm)
+
+		start := time.Now()
+		err := pub.Publish("topic", messaging.Message{})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.published, pm.published, fmt.Sprintf("%s: expected %d published messages got %d\n", tc.desc, tc.published, pm.published))
+		assert.True(t, time.Since(start) >= tc.cfg.Latency, fmt.Sprintf("%s: expected latency of at least %s\n", tc.desc, tc.cfg.Latency))
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	cases := []struct {
+		desc     string
+		cfg      chaos.Config
+		received int
+		err      error
+	}{
+		{
+			desc:     "receive without faults",
+			cfg:      chaos.Config{},
+			received: 1,
+		},
+		{
+			desc:     "receive with injected error",
+			cfg:      chaos.Config{ErrorRate: 1},
+			received: 0,
+			err:      chaos.ErrInjected,
+		},
+		{
+			desc:     "receive dropped message",
+			cfg:      chaos.Config{DropRate: 1},
+			received: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		psm := &pubSubMock{}
+		ps := chaos.NewPubSub(chaos.NewInjector(tc.cfg), psm)
+
+		received := 0
+		err := ps.Subscribe("topic", func(messaging.Message) error {
+			received++
+			return nil
+		})
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+
+		err = psm.handler(messaging.Message{})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.received, received, fmt.Sprintf("%s: expected %d received messages got %d\n", tc.desc, tc.received, received))
+	}
+}
+
+func TestHandler(t *testing.T) {
+	inj := chaos.NewInjector(chaos.Config{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	ts := httptest.NewServer(chaos.Wrap(inj, adminToken, next))
+	defer ts.Close()
+
+	cases := []struct {
+		desc   string
+		method string
+		path   string
+		token  string
+		body   string
+		status int
+	}{
+		{
+			desc:   "request without faults",
+			method: http.MethodPost,
+			path:   "/channels/1/messages",
+			status: http.StatusAccepted,
+		},
+		{
+			desc:   "view fault injection config",
+			method: http.MethodGet,
+			path:   chaos.AdminPath,
+			token:  adminToken,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view fault injection config with invalid token",
+			method: http.MethodGet,
+			path:   chaos.AdminPath,
+			token:  "invalid",
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "update fault injection config with invalid error rate",
+			method: http.MethodPut,
+			path:   chaos.AdminPath,
+			token:  adminToken,
+			body:   `{"error_rate": 2}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "update fault injection config with invalid latency",
+			method: http.MethodPut,
+			path:   chaos.AdminPath,
+			token:  adminToken,
+			body:   `{"latency": "invalid"}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "update fault injection config",
+			method: http.MethodPut,
+			path:   chaos.AdminPath,
+			token:  adminToken,
+			body:   `{"error_rate": 1}`,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "request with injected error",
+			method: http.MethodPost,
+			path:   "/channels/1/messages",
+			status: http.StatusServiceUnavailable,
+		},
+		{
+			desc:   "access admin endpoint with injected error",
+			method: http.MethodGet,
+			path:   chaos.AdminPath,
+			token:  adminToken,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range cases {
+		req, err := http.NewRequest(tc.method, ts.URL+tc.path, strings.NewReader(tc.body))
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		req.Header.Set("Authorization", tc.token)
+		res, err := ts.Client().Do(req)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package chaos
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+const (
+	// AdminPath is the path of the admin endpoint.
+	AdminPath = "/chaos"
+
+	contentType = "application/json"
+)
+
+type configRes struct {
+	Latency   string  `json:"latency"`
+	Jitter    string  `json:"jitter"`
+	ErrorRate float64 `json:"error_rate"`
+	DropRate  float64 `json:"drop_rate"`
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
+
+// Handler injects latency and failures into the HTTP requests handled by
+// the next handler. Failed requests are answered with 503 Service
+// Unavailable. Requests to the admin endpoint are not affected.
+func Handler(inj *Injector, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == AdminPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if d := inj.Delay(); d > 0 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(d):
+			}
+		}
+
+		if inj.Fail() {
+			encode(w, http.StatusServiceUnavailable, errorRes{Err: ErrInjected.Error()})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AdminHandler returns the handler used to view (GET) and change (PUT) the
+// injector configuration at runtime. Requests have to provide the admin
+// token in the Authorization header. Durations are formatted as Go
+// durations, e.g. "250ms".
+func AdminHandler(inj *Injector, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if token == "" || subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+			encode(w, http.StatusUnauthorized, errorRes{Err: "missing or invalid credentials provided"})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req configRes
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				encode(w, http.StatusBadRequest, errorRes{Err: ErrMalformedConfig.Error()})
+				return
+			}
+			cfg, err := req.config()
+			if err != nil {
+				encode(w, http.StatusBadRequest, errorRes{Err: err.Error()})
+				return
+			}
+			if err := inj.Update(cfg); err != nil {
+				encode(w, http.StatusBadRequest, errorRes{Err: err.Error()})
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		cfg := inj.Config()
+		encode(w, http.StatusOK, configRes{
+			Latency:   cfg.Latency.String(),
+			Jitter:    cfg.Jitter.String(),
+			ErrorRate: cfg.ErrorRate,
+			DropRate:  cfg.DropRate,
+		})
+	})
+}
+
+func (req configRes) config() (Config, error) {
+	cfg := Config{
+		ErrorRate: req.ErrorRate,
+		DropRate:  req.DropRate,
+	}
+
+	var err error
+	if req.Latency != "" {
+		if cfg.Latency, err = time.ParseDuration(req.Latency); err != nil {
+			return Config{}, ErrMalformedConfig
+		}
+	}
+	if req.Jitter != "" {
+		if cfg.Jitter, err = time.ParseDuration(req.Jitter); err != nil {
+			return Config{}, ErrMalformedConfig
+		}
+	}
+	return cfg, nil
+}
+
+func encode(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Wrap injects faults into the requests handled by the handler and, if the
+// admin token is set, exposes the admin endpoint along with it.
+func Wrap(inj *Injector, adminToken string, h http.Handler) http.Handler {
+	if adminToken == "" {
+		return Handler(inj, h)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", h)
+	mux.Handle(AdminPath, AdminHandler(inj, adminToken))
+	return Handler(inj, mux)
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package chaos
+
+import (
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var (
+	_ messaging.Publisher = (*publisher)(nil)
+	_ messaging.PubSub    = (*pubsub)(nil)
+)
+
+type publisher struct {
+	inj *Injector
+	pub messaging.Publisher
+}
+
+// NewPublisher wraps the publisher with fault injection. Published messages
+// are delayed, failed with ErrInjected or silently dropped.
+func NewPublisher(inj *Injector, pub messaging.Publisher) messaging.Publisher {
+	return publisher{
+		inj: inj,
+		pub: pub,
+	}
+}
+
+func (p publisher) Publish(topic string, msg messaging.Message) error {
+	if d := p.inj.Delay(); d > 0 {
+		time.Sleep(d)
+	}
+	if p.inj.Fail() {
+		return ErrInjected
+	}
+	if p.inj.Drop() {
+		return nil
+	}
+	return p.pub.Publish(topic, msg)
+}
+
+type pubsub struct {
+	publisher
+	ps messaging.PubSub
+}
+
+// NewPubSub wraps the pubsub with fault injection. Both published and
+// received messages are subject to the injected faults. Received messages
+// which are dropped never reach the handler, while failed ones are passed to
+// the broker as handler errors.
+func NewPubSub(inj *Injector, ps messaging.PubSub) messaging.PubSub {
+	return pubsub{
+		publisher: publisher{inj: inj, pub: ps},
+		ps:        ps,
+	}
+}
+
+func (ps pubsub) Subscribe(topic string, handler messaging.MessageHandler) error {
+	return ps.ps.Subscribe(topic, func(msg messaging.Message) error {
+		if d := ps.inj.Delay(); d > 0 {
+			time.Sleep(d)
+		}
+		if ps.inj.Fail() {
+			return ErrInjected
+		}
+		if ps.inj.Drop() {
+			return nil
+		}
+		return handler(msg)
+	})
+}
+
+func (ps pubsub) Unsubscribe(topic string) error {
+	return ps.ps.Unsubscribe(topic)
+}
@@ -8,8 +8,11 @@ import (
 	"fmt"
 	"net/mail"
 	"net/smtp"
+	"path/filepath"
+	"strings"
 	"text/template"
 
+	"github.com/mainflux/mainflux/internal/i18n"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/errors"
 )
@@ -50,6 +53,8 @@ type Agent struct {
 	addr string
 	log  logger.Logger
 	tmpl *template.Template
+	// localized templates keyed by language tag
+	localized map[string]*template.Template
 }
 
 // New creates new email agent
@@ -71,12 +76,63 @@ func New(c *Config) (*Agent, error) {
 		return a, errors.Wrap(errParseTemplate, err)
 	}
 	a.tmpl = tmpl
+
+	localized, err := parseLocalized(c.Template)
+	if err != nil {
+		return a, errors.Wrap(errParseTemplate, err)
+	}
+	a.localized = localized
+
 	return a, nil
 }
 
+// parseLocalized parses the translations of the template. Translations are
+// stored next to the template and named after the language tag, e.g.
+// email.de.tmpl is the German translation of email.tmpl.
+func parseLocalized(path string) (map[string]*template.Template, error) {
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	files, err := filepath.Glob(base + ".*" + ext)
+	if err != nil {
+		return nil, err
+	}
+
+	localized := make(map[string]*template.Template)
+	for _, file := range files {
+		lang := strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(file, base+"."), ext))
+		tmpl, err := template.ParseFiles(file)
+		if err != nil {
+			return nil, err
+		}
+		localized[lang] = tmpl
+	}
+	return localized, nil
+}
+
 // Send sends e-mail
 func (a *Agent) Send(To []string, From, Subject, Header, Content, Footer string) error {
-	if a.tmpl == nil {
+	return a.send(a.tmpl, To, From, Subject, Header, Content, Footer)
+}
+
+// SendLocalized sends e-mail using the template translated to the first of
+// the accepted languages, given as the Accept-Language header value. The
+// default template is used if there is no matching translation.
+func (a *Agent) SendLocalized(languages string, To []string, From, Subject, Header, Content, Footer string) error {
+	tmpl := a.tmpl
+	for _, lang := range i18n.Preferred(languages) {
+		if t, ok := a.localized[lang]; ok {
+			tmpl = t
+			break
+		}
+		if lang == i18n.DefaultLanguage {
+			break
+		}
+	}
+	return a.send(tmpl, To, From, Subject, Header, Content, Footer)
+}
+
+func (a *Agent) send(t *template.Template, To []string, From, Subject, Header, Content, Footer string) error {
+	if t == nil {
 		return errMissingEmailTemplate
 	}
 
@@ -94,7 +150,7 @@ func (a *Agent) Send(To []string, From, Subject, Header, Content, Footer string)
 		tmpl.From = from.String()
 	}
 
-	if err := a.tmpl.Execute(email, tmpl); err != nil {
+	if err := t.Execute(email, tmpl); err != nil {
 		return errors.Wrap(errExecTemplate, err)
 	}
 
@@ -0,0 +1,219 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authzHeader  = "Authorization"
+	realIPHeader = "X-Real-IP"
+	bearerPrefix = "Bearer "
+	contentType  = "application/json"
+
+	// envTrustedProxies lists the comma separated addresses, or the
+	// networks in the CIDR notation, of the reverse proxies the X-Real-IP
+	// header is taken from.
+	envTrustedProxies = "MF_TRUSTED_PROXIES"
+)
+
+var (
+	// ErrUnauthorizedAccess indicates the missing or invalid token.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrAuthorization indicates the identified user isn't allowed to
+	// perform the request.
+	ErrAuthorization = errors.New("failed to perform authorization over the entity")
+
+	// ErrAuthUnavailable indicates the failure to reach the auth service.
+	ErrAuthUnavailable = errors.New("failed to reach auth service")
+)
+
+type identityKey struct{}
+
+// Identity represents the user identified by the request token.
+type Identity struct {
+	ID    string
+	Email string
+	// Token is the token the user is identified by, so the handlers can
+	// call the other services on behalf of the user.
+	Token string
+}
+
+// ExtractToken returns the token of the Authorization header. The token is
+// given either as is or with the Bearer scheme.
+func ExtractToken(r *http.Request) string {
+	token := r.Header.Get(authzHeader)
+	if len(token) > len(bearerPrefix) && strings.EqualFold(token[:len(bearerPrefix)], bearerPrefix) {
+		return token[len(bearerPrefix):]
+	}
+	return token
+}
+
+// ClientIP returns the IP address of the client. The X-Real-IP header takes
+// precedence over the address of the connection only if the connection comes
+// from one of the reverse proxies listed in MF_TRUSTED_PROXIES, since any
+// other client can set the header to any address.
+func ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if ip := strings.TrimSpace(r.Header.Get(realIPHeader)); ip != "" && trustedProxy(host) {
+		return ip
+	}
+	return host
+}
+
+// trustedProxy tells whether the address is one of the trusted reverse
+// proxies. The malformed entries of MF_TRUSTED_PROXIES are ignored, so they
+// don't make any address trusted.
+func trustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, s := range strings.Split(mainflux.Env(envTrustedProxies, ""), ",") {
+		if network, err := auth.ParseNetwork(strings.TrimSpace(s)); err == nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// PopulateClientIP stores the IP address of the client in the context, so
+// it's propagated to the auth service along with the token. It's meant to be
+// used as the go-kit server before function.
+func PopulateClientIP(ctx context.Context, r *http.Request) context.Context {
+	return auth.WithClientIP(ctx, ClientIP(r))
+}
+
+// PopulateUserAgent stores the user agent of the client in the context, so
+// it's propagated to the auth service when the user logs in. It's meant to
+// be used as the go-kit server before function.
+func PopulateUserAgent(ctx context.Context, r *http.Request) context.Context {
+	return auth.WithUserAgent(ctx, r.UserAgent())
+}
+
+// WithIdentity returns the context carrying the identity.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// IdentityFromContext returns the identity injected by Authenticate, and
+// false if the request isn't authenticated.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
+
+// Authenticate returns the middleware identifying the user by the request
+// token and injecting the identity into the request context. The requests
+// without the valid token are rejected with 401 Unauthorized.
+func Authenticate(auth mainflux.AuthServiceClient) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := ExtractToken(r)
+			if token == "" {
+				writeError(w, r, ErrUnauthorizedAccess)
+				return
+			}
+
+			ctx := PopulateClientIP(r.Context(), r)
+			res, err := auth.Identify(ctx, &mainflux.Token{Value: token})
+			if err != nil {
+				writeError(w, r, authError(ErrUnauthorizedAccess, err))
+				return
+			}
+
+			id := Identity{ID: res.GetId(), Email: res.GetEmail(), Token: token}
+			next.ServeHTTP(w, r.WithContext(WithIdentity(ctx, id)))
+		})
+	}
+}
+
+// Authorize returns the middleware allowing only the users having the
+// relation on the object, e.g. the members of the authorities object. The
+// scope, if any, is checked against the scoped API keys, e.g. "things:read".
+// The middleware has to be chained after Authenticate.
+func Authorize(auth mainflux.AuthServiceClient, object, relation, scope string) func(http.Handler) http.Handler {
+	return AuthorizeFunc(auth, func(*http.Request) string { return object }, relation, scope)
+}
+
+// AuthorizeFunc is like Authorize, but reads the object from the request,
+// e.g. the entity ID from the path.
+func AuthorizeFunc(auth mainflux.AuthServiceClient, object func(*http.Request) string, relation, scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, ok := IdentityFromContext(r.Context())
+			if !ok {
+				writeError(w, r, ErrUnauthorizedAccess)
+				return
+			}
+
+			req := &mainflux.AuthorizeReq{
+				Sub:   id.ID,
+				Obj:   object(r),
+				Act:   relation,
+				Token: id.Token,
+				Scope: scope,
+			}
+			res, err := auth.Authorize(r.Context(), req)
+			if err != nil {
+				writeError(w, r, authError(ErrAuthorization, err))
+				return
+			}
+			if !res.GetAuthorized() {
+				writeError(w, r, ErrAuthorization)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// authError tells the rejected credentials from the failure to reach the
+// auth service, so the auth outage isn't reported as the client error.
+func authError(rejected error, err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown:
+		return errors.Wrap(ErrAuthUnavailable, err)
+	default:
+		return errors.Wrap(rejected, err)
+	}
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	code, msg := http.StatusServiceUnavailable, ErrAuthUnavailable.Error()
+	switch {
+	case errors.Contains(err, ErrUnauthorizedAccess):
+		w.Header().Set("WWW-Authenticate", strings.TrimSpace(bearerPrefix))
+		code, msg = http.StatusUnauthorized, ErrUnauthorizedAccess.Error()
+	case errors.Contains(err, ErrAuthorization):
+		code, msg = http.StatusForbidden, ErrAuthorization.Error()
+	}
+
+	ctx := i18n.PopulateRequestContext(r.Context(), r)
+	res, _ := json.Marshal(errorRes{Err: i18n.Localize(ctx, msg)})
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	w.Write(res)
+}
@@ -0,0 +1,188 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package httputil_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	token   = "token"
+	userID  = "user-id"
+	email   = "user@example.com"
+	object  = "authorities"
+	outage  = "outage"
+	noAdmin = "no-admin"
+)
+
+var _ mainflux.AuthServiceClient = (*authServiceMock)(nil)
+
+type authServiceMock struct {
+	mainflux.AuthServiceClient
+}
+
+func (svc authServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserIdentity, error) {
+	switch in.GetValue() {
+	case token, noAdmin:
+		return &mainflux.UserIdentity{Id: userID + in.GetValue(), Email: email}, nil
+	case outage:
+		return nil, status.Error(codes.Unavailable, "auth is down")
+	default:
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+}
+
+func (svc authServiceMock) Authorize(ctx context.Context, req *mainflux.AuthorizeReq, _ ...grpc.CallOption) (*mainflux.AuthorizeRes, error) {
+	return &mainflux.AuthorizeRes{Authorized: req.GetToken() == token && req.GetObj() == object}, nil
+}
+
+func TestExtractToken(t *testing.T) {
+	cases := []struct {
+		desc   string
+		header string
+		token  string
+	}{
+		{desc: "extract raw token", header: token, token: token},
+		{desc: "extract bearer token", header: "Bearer " + token, token: token},
+		{desc: "extract bearer token in lowercase", header: "bearer " + token, token: token},
+		{desc: "extract missing token", header: "", token: ""},
+		{desc: "extract bearer scheme without token", header: "Bearer ", token: "Bearer "},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", tc.header)
+		assert.Equal(t, tc.token, httputil.ExtractToken(r), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.token, httputil.ExtractToken(r)))
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	t.Setenv("MF_TRUSTED_PROXIES", "172.18.0.5, 10.0.0.0/8")
+
+	cases := []struct {
+		desc   string
+		remote string
+		header string
+		ip     string
+	}{
+		{desc: "client IP from connection", remote: "203.0.113.7:51234", ip: "203.0.113.7"},
+		{desc: "client IP from IPv6 connection", remote: "[2001:db8::1]:51234", ip: "2001:db8::1"},
+		{desc: "client IP from trusted proxy header", remote: "172.18.0.5:51234", header: "203.0.113.7", ip: "203.0.113.7"},
+		{desc: "client IP from trusted proxy network header", remote: "10.1.2.3:51234", header: "203.0.113.7", ip: "203.0.113.7"},
+		{desc: "client IP from untrusted proxy header", remote: "198.51.100.9:51234", header: "203.0.113.7", ip: "198.51.100.9"},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tc.remote
+		r.Header.Set("X-Real-IP", tc.header)
+		ip := httputil.ClientIP(r)
+		assert.Equal(t, tc.ip, ip, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.ip, ip))
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	var id httputil.Identity
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ = httputil.IdentityFromContext(r.Context())
+	})
+	h := httputil.Authenticate(authServiceMock{})(next)
+
+	cases := []struct {
+		desc     string
+		token    string
+		status   int
+		identity httputil.Identity
+	}{
+		{
+			desc:     "authenticate with valid token",
+			token:    token,
+			status:   http.StatusOK,
+			identity: httputil.Identity{ID: userID + token, Email: email, Token: token},
+		},
+		{
+			desc:   "authenticate with invalid token",
+			token:  "invalid",
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "authenticate with empty token",
+			token:  "",
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "authenticate with auth service unavailable",
+			token:  outage,
+			status: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range cases {
+		id = httputil.Identity{}
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", tc.token)
+		h.ServeHTTP(w, r)
+		assert.Equal(t, tc.status, w.Code, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, w.Code))
+		assert.Equal(t, tc.identity, id, fmt.Sprintf("%s: expected identity %v got %v", tc.desc, tc.identity, id))
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	auth := authServiceMock{}
+
+	cases := []struct {
+		desc    string
+		token   string
+		handler http.Handler
+		status  int
+	}{
+		{
+			desc:    "authorize admin",
+			token:   token,
+			handler: httputil.Authenticate(auth)(httputil.Authorize(auth, object, "member", "")(next)),
+			status:  http.StatusOK,
+		},
+		{
+			desc:    "authorize user without relation",
+			token:   noAdmin,
+			handler: httputil.Authenticate(auth)(httputil.Authorize(auth, object, "member", "")(next)),
+			status:  http.StatusForbidden,
+		},
+		{
+			desc:  "authorize admin over object from request",
+			token: token,
+			handler: httputil.Authenticate(auth)(httputil.AuthorizeFunc(auth, func(r *http.Request) string {
+				return r.URL.Query().Get("obj")
+			}, "member", "")(next)),
+			status: http.StatusForbidden,
+		},
+		{
+			desc:    "authorize unauthenticated request",
+			token:   token,
+			handler: httputil.Authorize(auth, object, "member", "")(next),
+			status:  http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/?obj=things", nil)
+		r.Header.Set("Authorization", tc.token)
+		tc.handler.ServeHTTP(w, r)
+		assert.Equal(t, tc.status, w.Code, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, w.Code))
+	}
+}
@@ -5,81 +5,114 @@ package httputil
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 )
 
 // ReadUintQuery reads the value of uint64 http query parameters for a given key
 func ReadUintQuery(r *http.Request, key string, def uint64) (uint64, error) {
-	vals := bone.GetQuery(r, key)
-	if len(vals) > 1 {
-		return 0, errors.ErrInvalidQueryParams
+	val, ok, err := readQuery(r, key)
+	if err != nil || !ok {
+		return def, err
 	}
 
-	if len(vals) == 0 {
-		return def, nil
+	u, err := strconv.ParseUint(val, 10, 64)
+	if err != nil {
+		return 0, invalidQuery(key, err)
 	}
 
-	strval := vals[0]
-	val, err := strconv.ParseUint(strval, 10, 64)
+	return u, nil
+}
+
+// ReadBoundedUintQuery reads the value of uint64 http query parameters for a
+// given key, rejecting the values out of the [min, max] range.
+func ReadBoundedUintQuery(r *http.Request, key string, def, min, max uint64) (uint64, error) {
+	u, err := ReadUintQuery(r, key, def)
 	if err != nil {
-		return 0, errors.ErrInvalidQueryParams
+		return 0, err
 	}
 
-	return val, nil
+	if u < min || u > max {
+		return 0, invalidQuery(key, fmt.Errorf("value %d out of range [%d, %d]", u, min, max))
+	}
+
+	return u, nil
 }
 
 // ReadStringQuery reads the value of string http query parameters for a given key
 func ReadStringQuery(r *http.Request, key string, def string) (string, error) {
-	vals := bone.GetQuery(r, key)
-	if len(vals) > 1 {
-		return "", errors.ErrInvalidQueryParams
+	val, ok, err := readQuery(r, key)
+	if err != nil || !ok {
+		return def, err
+	}
+
+	return val, nil
+}
+
+// ReadEnumQuery reads the value of string http query parameters for a given
+// key, rejecting the values other than the allowed ones.
+func ReadEnumQuery(r *http.Request, key string, def string, allowed ...string) (string, error) {
+	val, ok, err := readQuery(r, key)
+	if err != nil || !ok {
+		return def, err
 	}
 
-	if len(vals) == 0 {
-		return def, nil
+	for _, a := range allowed {
+		if val == a {
+			return val, nil
+		}
 	}
 
-	return vals[0], nil
+	return "", invalidQuery(key, fmt.Errorf("value %s not one of %v", val, allowed))
 }
 
 // ReadMetadataQuery reads the value of json http query parameters for a given key
 func ReadMetadataQuery(r *http.Request, key string, def map[string]interface{}) (map[string]interface{}, error) {
-	vals := bone.GetQuery(r, key)
-	if len(vals) > 1 {
-		return nil, errors.ErrInvalidQueryParams
+	val, ok, err := readRawQuery(r, key)
+	if err != nil || !ok {
+		return def, err
 	}
 
-	if len(vals) == 0 {
-		return def, nil
+	m := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(val), &m); err != nil {
+		return nil, invalidQuery(key, err)
 	}
 
-	m := make(map[string]interface{})
-	err := json.Unmarshal([]byte(vals[0]), &m)
+	return m, nil
+}
+
+// ReadSelectorQuery reads the label selector http query parameter for a
+// given key. The missing parameter is read as the empty selector.
+func ReadSelectorQuery(r *http.Request, key string) (labels.Selector, error) {
+	val, ok, err := readRawQuery(r, key)
+	if err != nil || !ok {
+		return labels.Selector{}, err
+	}
+
+	sel, err := labels.Parse(val)
 	if err != nil {
-		return nil, errors.Wrap(errors.ErrInvalidQueryParams, err)
+		return labels.Selector{}, invalidQuery(key, err)
 	}
 
-	return m, nil
+	return sel, nil
 }
 
 // ReadBoolQuery reads boolean query parameters in a given http request
 func ReadBoolQuery(r *http.Request, key string, def bool) (bool, error) {
-	vals := bone.GetQuery(r, key)
-	if len(vals) > 1 {
-		return false, errors.ErrInvalidQueryParams
-	}
-
-	if len(vals) == 0 {
-		return def, nil
+	val, ok, err := readQuery(r, key)
+	if err != nil || !ok {
+		return def, err
 	}
 
-	b, err := strconv.ParseBool(vals[0])
+	b, err := strconv.ParseBool(val)
 	if err != nil {
-		return false, errors.ErrInvalidQueryParams
+		return false, invalidQuery(key, err)
 	}
 
 	return b, nil
@@ -87,20 +120,71 @@ func ReadBoolQuery(r *http.Request, key string, def bool) (bool, error) {
 
 // ReadFloatQuery reads the value of float64 http query parameters for a given key
 func ReadFloatQuery(r *http.Request, key string, def float64) (float64, error) {
-	vals := bone.GetQuery(r, key)
-	if len(vals) > 1 {
-		return 0, errors.ErrInvalidQueryParams
+	val, ok, err := readQuery(r, key)
+	if err != nil || !ok {
+		return def, err
+	}
+
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, invalidQuery(key, err)
+	}
+
+	return f, nil
+}
+
+// ReadTimeQuery reads the value of time http query parameters for a given
+// key. The time is given either in the RFC3339 format or as the number of
+// seconds since the Unix epoch.
+func ReadTimeQuery(r *http.Request, key string, def time.Time) (time.Time, error) {
+	val, ok, err := readQuery(r, key)
+	if err != nil || !ok {
+		return def, err
 	}
 
-	if len(vals) == 0 {
-		return def, nil
+	if sec, err := strconv.ParseFloat(val, 64); err == nil {
+		return time.Unix(0, int64(sec*float64(time.Second))).UTC(), nil
 	}
 
-	fval := vals[0]
-	val, err := strconv.ParseFloat(fval, 64)
+	t, err := time.Parse(time.RFC3339, val)
 	if err != nil {
-		return 0, errors.ErrInvalidQueryParams
+		return time.Time{}, invalidQuery(key, err)
 	}
 
-	return val, nil
+	return t, nil
+}
+
+// readQuery returns the value of the query parameter and whether the
+// parameter is present. The parameter is expected to have a single value.
+func readQuery(r *http.Request, key string) (string, bool, error) {
+	vals := bone.GetQuery(r, key)
+	switch len(vals) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return vals[0], true, nil
+	default:
+		return "", false, invalidQuery(key, fmt.Errorf("%d values given", len(vals)))
+	}
+}
+
+// readRawQuery is readQuery keeping the commas in the value, for the values
+// such as the JSON objects and the label selectors. Unlike bone, which
+// splits the values by commas, the standard query keeps them together.
+func readRawQuery(r *http.Request, key string) (string, bool, error) {
+	vals := r.URL.Query()[key]
+	switch len(vals) {
+	case 0:
+		return "", false, nil
+	case 1:
+		return vals[0], true, nil
+	default:
+		return "", false, invalidQuery(key, fmt.Errorf("%d values given", len(vals)))
+	}
+}
+
+// invalidQuery wraps the query parameter parsing error, so that all the
+// parameters are reported as errors.ErrInvalidQueryParams.
+func invalidQuery(key string, err error) error {
+	return errors.Wrap(errors.ErrInvalidQueryParams, fmt.Errorf("%s: %s", key, err))
 }
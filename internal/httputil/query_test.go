@@ -0,0 +1,297 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package httputil_test
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
+	"github.com/stretchr/testify/assert"
+)
+
+const key = "key"
+
+func TestReadUintQuery(t *testing.T) {
+	cases := []struct {
+		desc  string
+		query string
+		value uint64
+		err   error
+	}{
+		{
+			desc:  "read uint",
+			query: "key=5",
+			value: 5,
+		},
+		{
+			desc:  "read missing uint",
+			query: "",
+			value: 10,
+		},
+		{
+			desc:  "read negative uint",
+			query: "key=-5",
+			err:   errors.ErrInvalidQueryParams,
+		},
+		{
+			desc:  "read invalid uint",
+			query: "key=five",
+			err:   errors.ErrInvalidQueryParams,
+		},
+		{
+			desc:  "read uint with multiple values",
+			query: "key=5&key=6",
+			err:   errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?%s", tc.query), nil)
+		val, err := httputil.ReadUintQuery(r, key, 10)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.value, val, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.value, val))
+		}
+	}
+}
+
+func TestReadBoundedUintQuery(t *testing.T) {
+	cases := []struct {
+		desc  string
+		query string
+		value uint64
+		err   error
+	}{
+		{
+			desc:  "read uint within bounds",
+			query: "key=50",
+			value: 50,
+		},
+		{
+			desc:  "read uint on the upper bound",
+			query: "key=100",
+			value: 100,
+		},
+		{
+			desc:  "read missing uint",
+			query: "",
+			value: 10,
+		},
+		{
+			desc:  "read uint below bounds",
+			query: "key=0",
+			err:   errors.ErrInvalidQueryParams,
+		},
+		{
+			desc:  "read uint above bounds",
+			query: "key=101",
+			err:   errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?%s", tc.query), nil)
+		val, err := httputil.ReadBoundedUintQuery(r, key, 10, 1, 100)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.value, val, fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.value, val))
+		}
+	}
+}
+
+func TestReadEnumQuery(t *testing.T) {
+	cases := []struct {
+		desc  string
+		query string
+		value string
+		err   error
+	}{
+		{
+			desc:  "read allowed value",
+			query: "key=desc",
+			value: "desc",
+		},
+		{
+			desc:  "read missing value",
+			query: "",
+			value: "asc",
+		},
+		{
+			desc:  "read not allowed value",
+			query: "key=random",
+			err:   errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?%s", tc.query), nil)
+		val, err := httputil.ReadEnumQuery(r, key, "asc", "asc", "desc")
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.value, val, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.value, val))
+		}
+	}
+}
+
+func TestReadBoolQuery(t *testing.T) {
+	cases := []struct {
+		desc  string
+		query string
+		value bool
+		err   error
+	}{
+		{
+			desc:  "read bool",
+			query: "key=true",
+			value: true,
+		},
+		{
+			desc:  "read missing bool",
+			query: "",
+			value: false,
+		},
+		{
+			desc:  "read invalid bool",
+			query: "key=yes",
+			err:   errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?%s", tc.query), nil)
+		val, err := httputil.ReadBoolQuery(r, key, false)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.value, val, fmt.Sprintf("%s: expected %t got %t\n", tc.desc, tc.value, val))
+		}
+	}
+}
+
+func TestReadTimeQuery(t *testing.T) {
+	ts := time.Date(2021, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		desc  string
+		query string
+		value time.Time
+		err   error
+	}{
+		{
+			desc:  "read RFC3339 time",
+			query: "key=2021-06-01T12:30:00Z",
+			value: ts,
+		},
+		{
+			desc:  "read Unix time",
+			query: fmt.Sprintf("key=%d", ts.Unix()),
+			value: ts,
+		},
+		{
+			desc:  "read fractional Unix time",
+			query: fmt.Sprintf("key=%d.5", ts.Unix()),
+			value: ts.Add(500 * time.Millisecond),
+		},
+		{
+			desc:  "read missing time",
+			query: "",
+			value: time.Time{},
+		},
+		{
+			desc:  "read invalid time",
+			query: "key=yesterday",
+			err:   errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?%s", tc.query), nil)
+		val, err := httputil.ReadTimeQuery(r, key, time.Time{})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.True(t, tc.value.Equal(val), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.value, val))
+		}
+	}
+}
+
+func TestReadMetadataQuery(t *testing.T) {
+	cases := []struct {
+		desc  string
+		query string
+		value map[string]interface{}
+		err   error
+	}{
+		{
+			desc:  "read metadata",
+			query: `key={"field":"value"}`,
+			value: map[string]interface{}{"field": "value"},
+		},
+		{
+			desc:  "read metadata with multiple fields",
+			query: fmt.Sprintf("key=%s", url.QueryEscape(`{"region":"eu","customer":{"id":"acme"}}`)),
+			value: map[string]interface{}{"region": "eu", "customer": map[string]interface{}{"id": "acme"}},
+		},
+		{
+			desc:  "read missing metadata",
+			query: "",
+			value: nil,
+		},
+		{
+			desc:  "read invalid metadata",
+			query: "key={field",
+			err:   errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?%s", tc.query), nil)
+		val, err := httputil.ReadMetadataQuery(r, key, nil)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.value, val, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.value, val))
+		}
+	}
+}
+
+func TestReadSelectorQuery(t *testing.T) {
+	cases := []struct {
+		desc  string
+		query string
+		value labels.Selector
+		err   error
+	}{
+		{
+			desc:  "read selector",
+			query: fmt.Sprintf("key=%s", url.QueryEscape("env=prod,!deprecated")),
+			value: labels.Selector{
+				{Key: "env", Operator: labels.Equals, Value: "prod"},
+				{Key: "deprecated", Operator: labels.NotExists},
+			},
+		},
+		{
+			desc:  "read missing selector",
+			query: "",
+			value: labels.Selector{},
+		},
+		{
+			desc:  "read invalid selector",
+			query: fmt.Sprintf("key=%s", url.QueryEscape("env>prod")),
+			err:   errors.ErrInvalidQueryParams,
+		},
+	}
+
+	for _, tc := range cases {
+		r := httptest.NewRequest("GET", fmt.Sprintf("/?%s", tc.query), nil)
+		val, err := httputil.ReadSelectorQuery(r, key)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.value, val, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.value, val))
+		}
+	}
+}
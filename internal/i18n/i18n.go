@@ -0,0 +1,198 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package i18n provides localization of user-facing messages. Messages are
+// written in English throughout the code base and the English text is used
+// as the key of its translations. Catalogs containing the translations are
+// loaded at startup and the language is negotiated using the
+// Accept-Language HTTP header. Messages without a translation in any of the
+// accepted languages fall back to English.
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// DefaultLanguage is the language messages are written in.
+const DefaultLanguage = "en"
+
+const (
+	acceptLanguageHeader = "Accept-Language"
+	catalogExt           = ".json"
+)
+
+var (
+	errLoadCatalog  = errors.New("failed to load message catalog")
+	errParseCatalog = errors.New("failed to parse message catalog")
+)
+
+type langKey struct{}
+
+// Catalog maps English messages to their translations.
+type Catalog map[string]string
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]Catalog{}
+)
+
+// LoadCatalogs loads all the catalogs from the directory. Each catalog is a
+// JSON object stored in the file named after its language tag, e.g. de.json
+// or pt-br.json. Empty directory path is ignored.
+func LoadCatalogs(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*"+catalogExt))
+	if err != nil {
+		return errors.Wrap(errLoadCatalog, err)
+	}
+
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.Wrap(errLoadCatalog, err)
+		}
+		var c Catalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			return errors.Wrap(errParseCatalog, errors.New(filepath.Base(file)))
+		}
+		lang := strings.TrimSuffix(filepath.Base(file), catalogExt)
+		AddCatalog(lang, c)
+	}
+
+	return nil
+}
+
+// AddCatalog adds translations of the language, replacing the existing
+// translations of the same messages.
+func AddCatalog(lang string, c Catalog) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	lang = normalize(lang)
+	if catalogs[lang] == nil {
+		catalogs[lang] = Catalog{}
+	}
+	for msg, tr := range c {
+		catalogs[lang][msg] = tr
+	}
+}
+
+// Translate returns the translation of the message to the first language
+// with available translation. Languages are given as the Accept-Language
+// header value, e.g. "de-CH, de;q=0.9, en;q=0.8". Regional languages fall
+// back to their base language.
+func Translate(languages, msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for _, lang := range Preferred(languages) {
+		if lang == DefaultLanguage {
+			return msg
+		}
+		if tr, ok := catalogs[lang][msg]; ok {
+			return tr
+		}
+	}
+
+	return msg
+}
+
+// Preferred returns the language tags of the Accept-Language header value
+// ordered by preference. Each regional language is followed by its base
+// language, unless the base language is listed explicitly.
+func Preferred(languages string) []string {
+	tags := parseAcceptLanguage(languages)
+	listed := make(map[string]bool)
+	for _, tag := range tags {
+		listed[tag] = true
+	}
+
+	var ret []string
+	for _, tag := range tags {
+		ret = append(ret, tag)
+		if i := strings.Index(tag, "-"); i > 0 && !listed[tag[:i]] {
+			listed[tag[:i]] = true
+			ret = append(ret, tag[:i])
+		}
+	}
+	return ret
+}
+
+// Localize translates the message to the language stored in the context.
+func Localize(ctx context.Context, msg string) string {
+	return Translate(Language(ctx), msg)
+}
+
+// WithLanguage returns the context carrying the accepted languages.
+func WithLanguage(ctx context.Context, languages string) context.Context {
+	return context.WithValue(ctx, langKey{}, languages)
+}
+
+// Language returns the accepted languages stored in the context.
+func Language(ctx context.Context) string {
+	lang, _ := ctx.Value(langKey{}).(string)
+	return lang
+}
+
+// PopulateRequestContext stores the Accept-Language header of the request
+// in the context. It's meant to be used as the go-kit server before
+// function.
+func PopulateRequestContext(ctx context.Context, r *http.Request) context.Context {
+	return WithLanguage(ctx, r.Header.Get(acceptLanguageHeader))
+}
+
+type weighted struct {
+	lang string
+	q    float64
+}
+
+// parseAcceptLanguage returns the language tags ordered by preference.
+func parseAcceptLanguage(header string) []string {
+	var langs []weighted
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		lang := normalize(fields[0])
+		if lang == "" || lang == "*" {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			langs = append(langs, weighted{lang: lang, q: q})
+		}
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+
+	ret := make([]string, len(langs))
+	for i, l := range langs {
+		ret[i] = l.lang
+	}
+	return ret
+}
+
+func normalize(lang string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(lang), "_", "-", -1))
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package i18n_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const msg = "entity not found"
+
+func TestTranslate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "i18n")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "de.json"), []byte(`{"entity not found": "Entität nicht gefunden"}`), 0644)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = ioutil.WriteFile(filepath.Join(dir, "sr-Latn.json"), []byte(`{"entity not found": "entitet nije pronađen"}`), 0644)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = i18n.LoadCatalogs(dir)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc      string
+		languages string
+		msg       string
+		expected  string
+	}{
+		{
+			desc:      "translate without accepted languages",
+			languages: "",
+			msg:       msg,
+			expected:  msg,
+		},
+		{
+			desc:      "translate to available language",
+			languages: "de",
+			msg:       msg,
+			expected:  "Entität nicht gefunden",
+		},
+		{
+			desc:      "translate to regional language",
+			languages: "de-CH",
+			msg:       msg,
+			expected:  "Entität nicht gefunden",
+		},
+		{
+			desc:      "translate to language with region and script",
+			languages: "sr_latn",
+			msg:       msg,
+			expected:  "entitet nije pronađen",
+		},
+		{
+			desc:      "translate to preferred language by quality",
+			languages: "fr, de;q=0.5, sr-latn;q=0.8",
+			msg:       msg,
+			expected:  "entitet nije pronađen",
+		},
+		{
+			desc:      "translate to English preferred over available language",
+			languages: "en-US, de;q=0.9",
+			msg:       msg,
+			expected:  msg,
+		},
+		{
+			desc:      "translate to unavailable language",
+			languages: "fr",
+			msg:       msg,
+			expected:  msg,
+		},
+		{
+			desc:      "translate message without translation",
+			languages: "de",
+			msg:       "unknown message",
+			expected:  "unknown message",
+		},
+	}
+
+	for _, tc := range cases {
+		tr := i18n.Translate(tc.languages, tc.msg)
+		assert.Equal(t, tc.expected, tr, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.expected, tr))
+
+		ctx := i18n.WithLanguage(context.Background(), tc.languages)
+		tr = i18n.Localize(ctx, tc.msg)
+		assert.Equal(t, tc.expected, tr, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.expected, tr))
+	}
+}
+
+func TestLoadMalformedCatalog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "i18n")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "de.json"), []byte(`{"entity not found"}`), 0644)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = i18n.LoadCatalogs(dir)
+	assert.NotNil(t, err, "expected error loading malformed catalog")
+}
@@ -0,0 +1,215 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jobs runs the long running operations, such as the bulk creation
+// of the things, in the background, tracking their progress in memory or in
+// Redis, so the clients poll the jobs instead of holding the requests open.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// The statuses of the job.
+const (
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+const (
+	// maxErrors limits the number of the partial errors kept per job, while
+	// all of them are counted.
+	maxErrors = 100
+
+	// saveInterval limits how often the progress of the running job is
+	// saved.
+	saveInterval = time.Second
+)
+
+var (
+	// ErrNotFound indicates the non-existent or expired job, or the job
+	// started by someone else.
+	ErrNotFound = errors.New("non-existent job")
+
+	// ErrNotCompleted indicates the result request of the job which hasn't
+	// completed yet.
+	ErrNotCompleted = errors.New("job not completed")
+)
+
+// Error represents the partial error, which failed the single item of the
+// job, identified by its index, while the rest of the job proceeded.
+type Error struct {
+	Index   uint64 `json:"index"`
+	Message string `json:"message"`
+}
+
+// Job represents the operation running in the background. The result of the
+// completed job is kept JSON encoded until the job expires.
+type Job struct {
+	ID        string          `json:"id"`
+	Owner     string          `json:"owner"`
+	Kind      string          `json:"kind"`
+	Status    string          `json:"status"`
+	Total     uint64          `json:"total"`
+	Done      uint64          `json:"done"`
+	Failed    uint64          `json:"failed"`
+	Errors    []Error         `json:"errors,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// Store stores the jobs until they expire.
+type Store interface {
+	// Save saves the job, replacing the existing one, until it expires.
+	Save(ctx context.Context, job Job) error
+
+	// Retrieve retrieves the job with the given ID.
+	Retrieve(ctx context.Context, id string) (Job, error)
+}
+
+// Func runs the job, reporting the progress of each of its items, and
+// returns the JSON encodable result of the job. The job fails as a whole if
+// the error is returned.
+type Func func(ctx context.Context, p *Progress) (interface{}, error)
+
+// Manager starts the jobs and retrieves them on behalf of their owners.
+type Manager struct {
+	store     Store
+	idp       mainflux.IDProvider
+	timeout   time.Duration
+	retention time.Duration
+}
+
+// NewManager returns the manager keeping the jobs in the given store. The
+// jobs are cancelled once they run longer than the timeout, and the
+// finished jobs are kept for the retention period.
+func NewManager(store Store, idp mainflux.IDProvider, timeout, retention time.Duration) *Manager {
+	return &Manager{
+		store:     store,
+		idp:       idp,
+		timeout:   timeout,
+		retention: retention,
+	}
+}
+
+// Start saves the running job of the given kind and total number of items,
+// and runs it in the background. The job outlives the request, so it
+// doesn't use the request context.
+func (m *Manager) Start(ctx context.Context, owner, kind string, total uint64, fn Func) (Job, error) {
+	id, err := m.idp.ID()
+	if err != nil {
+		return Job{}, err
+	}
+
+	now := time.Now().UTC()
+	job := Job{
+		ID:        id,
+		Owner:     owner,
+		Kind:      kind,
+		Status:    StatusRunning,
+		Total:     total,
+		CreatedAt: now,
+		UpdatedAt: now,
+		// The job interrupted by the restart expires along with the
+		// finished ones.
+		ExpiresAt: now.Add(m.timeout + m.retention),
+	}
+	if err := m.store.Save(ctx, job); err != nil {
+		return Job{}, err
+	}
+
+	go m.run(job, fn)
+
+	return job, nil
+}
+
+// Retrieve retrieves the job started by the owner.
+func (m *Manager) Retrieve(ctx context.Context, owner, id string) (Job, error) {
+	job, err := m.store.Retrieve(ctx, id)
+	if err != nil {
+		return Job{}, err
+	}
+	if job.Owner != owner {
+		return Job{}, ErrNotFound
+	}
+	return job, nil
+}
+
+func (m *Manager) run(job Job, fn Func) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	p := &Progress{store: m.store, job: job}
+	res, err := fn(ctx, p)
+	if err == nil {
+		var data []byte
+		if data, err = json.Marshal(res); err == nil {
+			p.job.Result = data
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now().UTC()
+	p.job.Status = StatusCompleted
+	if err != nil {
+		p.job.Status = StatusFailed
+		p.job.Error = err.Error()
+	}
+	p.job.UpdatedAt = now
+	p.job.ExpiresAt = now.Add(m.retention)
+
+	// The finished job is saved even though the job context has expired.
+	m.store.Save(context.Background(), p.job)
+}
+
+// Progress reports the progress of the running job.
+type Progress struct {
+	mu    sync.Mutex
+	store Store
+	job   Job
+	saved time.Time
+}
+
+// Step reports the next item of the job as done, and as failed if the
+// error is not nil.
+func (p *Progress) Step(ctx context.Context, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err != nil {
+		if len(p.job.Errors) < maxErrors {
+			p.job.Errors = append(p.job.Errors, Error{Index: p.job.Done, Message: err.Error()})
+		}
+		p.job.Failed++
+	}
+	p.job.Done++
+
+	now := time.Now().UTC()
+	if now.Sub(p.saved) < saveInterval {
+		return
+	}
+	p.saved = now
+	p.job.UpdatedAt = now
+	p.store.Save(ctx, p.job)
+}
+
+// String returns the progress of the job, e.g. for the logs.
+func (p *Progress) String() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return fmt.Sprintf("%d/%d done, %d failed", p.job.Done, p.job.Total, p.job.Failed)
+}
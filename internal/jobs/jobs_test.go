@@ -0,0 +1,154 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/internal/jobs"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	owner = "user@example.com"
+	kind  = "test"
+)
+
+var errItem = errors.New("item failed")
+
+// wait polls the job until it's finished.
+func wait(t *testing.T, m *jobs.Manager, id string) jobs.Job {
+	for i := 0; i < 100; i++ {
+		job, err := m.Retrieve(context.Background(), owner, id)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		if job.Status != jobs.StatusRunning {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s expected to finish", id)
+	return jobs.Job{}
+}
+
+func TestStart(t *testing.T) {
+	m := jobs.NewManager(jobs.NewMemoryStore(), uuid.NewMock(), time.Minute, time.Hour)
+
+	cases := []struct {
+		desc   string
+		fn     jobs.Func
+		status string
+		failed uint64
+		errs   []jobs.Error
+		err    string
+		result string
+	}{
+		{
+			desc: "complete job",
+			fn: func(ctx context.Context, p *jobs.Progress) (interface{}, error) {
+				for i := 0; i < 3; i++ {
+					p.Step(ctx, nil)
+				}
+				return []int{1, 2, 3}, nil
+			},
+			status: jobs.StatusCompleted,
+			result: "[1,2,3]",
+		},
+		{
+			desc: "complete job with partial errors",
+			fn: func(ctx context.Context, p *jobs.Progress) (interface{}, error) {
+				p.Step(ctx, nil)
+				p.Step(ctx, errItem)
+				p.Step(ctx, nil)
+				return []int{1, 3}, nil
+			},
+			status: jobs.StatusCompleted,
+			failed: 1,
+			errs:   []jobs.Error{{Index: 1, Message: errItem.Error()}},
+			result: "[1,3]",
+		},
+		{
+			desc: "fail job",
+			fn: func(ctx context.Context, p *jobs.Progress) (interface{}, error) {
+				p.Step(ctx, nil)
+				return nil, errItem
+			},
+			status: jobs.StatusFailed,
+			err:    errItem.Error(),
+		},
+	}
+
+	for _, tc := range cases {
+		job, err := m.Start(context.Background(), owner, kind, 3, tc.fn)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, jobs.StatusRunning, job.Status, fmt.Sprintf("%s: expected status %s got %s", tc.desc, jobs.StatusRunning, job.Status))
+
+		job = wait(t, m, job.ID)
+		assert.Equal(t, tc.status, job.Status, fmt.Sprintf("%s: expected status %s got %s", tc.desc, tc.status, job.Status))
+		assert.Equal(t, tc.failed, job.Failed, fmt.Sprintf("%s: expected %d failed got %d", tc.desc, tc.failed, job.Failed))
+		assert.Equal(t, tc.errs, job.Errors, fmt.Sprintf("%s: expected errors %v got %v", tc.desc, tc.errs, job.Errors))
+		assert.Equal(t, tc.err, job.Error, fmt.Sprintf("%s: expected error %s got %s", tc.desc, tc.err, job.Error))
+		if tc.result != "" {
+			assert.JSONEq(t, tc.result, string(job.Result), fmt.Sprintf("%s: expected result %s got %s", tc.desc, tc.result, job.Result))
+		}
+	}
+}
+
+func TestRetrieve(t *testing.T) {
+	m := jobs.NewManager(jobs.NewMemoryStore(), uuid.NewMock(), time.Minute, time.Hour)
+	job, err := m.Start(context.Background(), owner, kind, 0, func(ctx context.Context, p *jobs.Progress) (interface{}, error) {
+		return json.RawMessage("{}"), nil
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	cases := []struct {
+		desc  string
+		owner string
+		id    string
+		err   error
+	}{
+		{
+			desc:  "retrieve job",
+			owner: owner,
+			id:    job.ID,
+			err:   nil,
+		},
+		{
+			desc:  "retrieve job of other owner",
+			owner: "other@example.com",
+			id:    job.ID,
+			err:   jobs.ErrNotFound,
+		},
+		{
+			desc:  "retrieve non-existent job",
+			owner: owner,
+			id:    "non-existent",
+			err:   jobs.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := m.Retrieve(context.Background(), tc.owner, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestExpiration(t *testing.T) {
+	m := jobs.NewManager(jobs.NewMemoryStore(), uuid.NewMock(), time.Minute, 50*time.Millisecond)
+	job, err := m.Start(context.Background(), owner, kind, 0, func(ctx context.Context, p *jobs.Progress) (interface{}, error) {
+		return nil, nil
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	wait(t, m, job.ID)
+	time.Sleep(100 * time.Millisecond)
+	_, err = m.Retrieve(context.Background(), owner, job.ID)
+	assert.True(t, errors.Contains(err, jobs.ErrNotFound), fmt.Sprintf("expected %s got %s", jobs.ErrNotFound, err))
+}
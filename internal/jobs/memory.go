@@ -0,0 +1,48 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var _ Store = (*memoryStore)(nil)
+
+type memoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+// NewMemoryStore returns the in-memory store. The jobs aren't shared between
+// the service instances, so it's meant for the single instance deployments.
+func NewMemoryStore() Store {
+	return &memoryStore{jobs: make(map[string]Job)}
+}
+
+func (ms *memoryStore) Save(ctx context.Context, job Job) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := time.Now()
+	for id, j := range ms.jobs {
+		if now.After(j.ExpiresAt) {
+			delete(ms.jobs, id)
+		}
+	}
+	ms.jobs[job.ID] = job
+	return nil
+}
+
+func (ms *memoryStore) Retrieve(ctx context.Context, id string) (Job, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	job, ok := ms.jobs[id]
+	if !ok || time.Now().After(job.ExpiresAt) {
+		return Job{}, ErrNotFound
+	}
+	return job, nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const keyPrefix = "job"
+
+var _ Store = (*redisStore)(nil)
+
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore returns the Redis store. Since the jobs are kept in Redis,
+// the job started by one service instance is reported by all of them.
+func NewRedisStore(client *redis.Client) Store {
+	return &redisStore{client: client}
+}
+
+func (rs *redisStore) Save(ctx context.Context, job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	ttl := time.Until(job.ExpiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return rs.client.Set(ctx, keyPrefix+":"+job.ID, data, ttl).Err()
+}
+
+func (rs *redisStore) Retrieve(ctx context.Context, id string) (Job, error) {
+	data, err := rs.client.Get(ctx, keyPrefix+":"+id).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return Job{}, errors.Wrap(ErrNotFound, err)
+		}
+		return Job{}, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return Job{}, err
+	}
+	return job, nil
+}
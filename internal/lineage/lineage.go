@@ -0,0 +1,97 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lineage stamps the published messages with their lineage, i.e. the
+// adapter instance which ingested the message, the trace ID assigned on the
+// ingestion and the number of times the message was forwarded by the
+// bridges, so the stored messages can be traced back to their source.
+package lineage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+const envInstance = "MF_INSTANCE_ID"
+
+// Instance returns the name of the adapter instance, given by MF_INSTANCE_ID,
+// or composed of the service and the host name otherwise, e.g.
+// "http-adapter@mainflux-http-1".
+func Instance(service string) string {
+	if id := mainflux.Env(envInstance, ""); id != "" {
+		return id
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		return service
+	}
+	return fmt.Sprintf("%s@%s", service, host)
+}
+
+// NewTraceID returns the random trace ID, formatted as the W3C Trace Context
+// trace ID, i.e. 32 lowercase hex digits.
+func NewTraceID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// Stamp populates the lineage of the message published by the instance. The
+// message without the lineage is stamped as ingested by the instance, under
+// the new trace ID, unless the trace ID is already set. The message which
+// carries the lineage, i.e. forwarded by the bridge, keeps it and its hop
+// count is incremented.
+func Stamp(msg *messaging.Message, instance string) {
+	if msg.Adapter != "" {
+		msg.Hops++
+		return
+	}
+	msg.Adapter = instance
+	if msg.TraceId == "" {
+		msg.TraceId = NewTraceID()
+	}
+}
+
+var _ messaging.Publisher = (*publisher)(nil)
+
+type publisher struct {
+	instance string
+	pub      messaging.Publisher
+}
+
+// NewPublisher returns the publisher stamping the lineage of the messages
+// before publishing them.
+func NewPublisher(pub messaging.Publisher, instance string) messaging.Publisher {
+	return publisher{
+		instance: instance,
+		pub:      pub,
+	}
+}
+
+func (p publisher) Publish(topic string, msg messaging.Message) error {
+	Stamp(&msg, p.instance)
+	return p.pub.Publish(topic, msg)
+}
+
+var _ messaging.PubSub = (*pubSub)(nil)
+
+type pubSub struct {
+	messaging.Subscriber
+	publisher
+}
+
+// NewPubSub returns the pubsub stamping the lineage of the published
+// messages. The subscriptions are left intact.
+func NewPubSub(ps messaging.PubSub, instance string) messaging.PubSub {
+	return pubSub{
+		Subscriber: ps,
+		publisher:  publisher{instance: instance, pub: ps},
+	}
+}
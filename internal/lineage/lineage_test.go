@@ -0,0 +1,82 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lineage_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/mainflux/mainflux/internal/lineage"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+)
+
+const instance = "http-adapter@host"
+
+var traceIDRegexp = regexp.MustCompile("^[0-9a-f]{32}$")
+
+type publisherMock struct {
+	published []messaging.Message
+}
+
+func (pm *publisherMock) Publish(_ string, msg messaging.Message) error {
+	pm.published = append(pm.published, msg)
+	return nil
+}
+
+func TestStamp(t *testing.T) {
+	cases := []struct {
+		desc    string
+		msg     messaging.Message
+		adapter string
+		traceID string
+		hops    uint32
+	}{
+		{
+			desc:    "stamp ingested message",
+			msg:     messaging.Message{Channel: "chan"},
+			adapter: instance,
+			hops:    0,
+		},
+		{
+			desc:    "stamp ingested message with trace ID",
+			msg:     messaging.Message{Channel: "chan", TraceId: "trace"},
+			adapter: instance,
+			traceID: "trace",
+			hops:    0,
+		},
+		{
+			desc:    "stamp forwarded message",
+			msg:     messaging.Message{Channel: "chan", Adapter: "mqtt-adapter@other", TraceId: "trace", Hops: 1},
+			adapter: "mqtt-adapter@other",
+			traceID: "trace",
+			hops:    2,
+		},
+	}
+
+	for _, tc := range cases {
+		msg := tc.msg
+		lineage.Stamp(&msg, instance)
+		assert.Equal(t, tc.adapter, msg.Adapter, fmt.Sprintf("%s: expected adapter %s got %s\n", tc.desc, tc.adapter, msg.Adapter))
+		assert.Equal(t, tc.hops, msg.Hops, fmt.Sprintf("%s: expected hops %d got %d\n", tc.desc, tc.hops, msg.Hops))
+		switch tc.traceID {
+		case "":
+			assert.Regexp(t, traceIDRegexp, msg.TraceId, fmt.Sprintf("%s: expected new trace ID got %s\n", tc.desc, msg.TraceId))
+		default:
+			assert.Equal(t, tc.traceID, msg.TraceId, fmt.Sprintf("%s: expected trace ID %s got %s\n", tc.desc, tc.traceID, msg.TraceId))
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	pm := &publisherMock{}
+	pub := lineage.NewPublisher(pm, instance)
+
+	err := pub.Publish("chan", messaging.Message{Channel: "chan"})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, pm.published, 1)
+	assert.Equal(t, instance, pm.published[0].Adapter, fmt.Sprintf("expected adapter %s got %s\n", instance, pm.published[0].Adapter))
+	assert.Regexp(t, traceIDRegexp, pm.published[0].TraceId, fmt.Sprintf("expected new trace ID got %s\n", pm.published[0].TraceId))
+}
@@ -0,0 +1,36 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package payload
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var _ Checker = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	rejected metrics.Counter
+	checker  Checker
+}
+
+// MetricsMiddleware counts the rejected messages by the protocol and the
+// reason, which is either "size" or "content_type".
+func MetricsMiddleware(c Checker, rejected metrics.Counter) Checker {
+	return metricsMiddleware{
+		rejected: rejected,
+		checker:  c,
+	}
+}
+
+func (mm metricsMiddleware) Check(msg messaging.Message) error {
+	err := mm.checker.Check(msg)
+	switch err {
+	case ErrTooLarge:
+		mm.rejected.With("protocol", msg.Protocol, "reason", "size").Add(1)
+	case ErrUnsupportedContentType:
+		mm.rejected.With("protocol", msg.Protocol, "reason", "content_type").Add(1)
+	}
+	return err
+}
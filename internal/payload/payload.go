@@ -0,0 +1,187 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package payload provides the payload size and content type policies the
+// protocol adapters enforce on the published messages, protecting the
+// message broker and the writers from the abusive publishers.
+package payload
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+const (
+	envMaxSize      = "MF_PAYLOAD_MAX_SIZE"
+	envContentTypes = "MF_PAYLOAD_CONTENT_TYPES"
+	envChannels     = "MF_PAYLOAD_CHANNELS"
+)
+
+var (
+	// ErrTooLarge indicates the payload exceeding the maximum size.
+	ErrTooLarge = errors.New("payload too large")
+
+	// ErrUnsupportedContentType indicates the payload of the content type
+	// which isn't accepted.
+	ErrUnsupportedContentType = errors.New("unsupported payload content type")
+
+	// ErrMalformedPolicy indicates invalid payload policy configuration.
+	ErrMalformedPolicy = errors.New("malformed payload policy")
+)
+
+// Policy limits the payloads of the published messages.
+type Policy struct {
+	// MaxSize is the maximum payload size in bytes. Zero means no limit.
+	MaxSize int
+	// ContentTypes lists the accepted content types. Empty list accepts
+	// any content type.
+	ContentTypes []string
+}
+
+// Policies contain the deployment policy along with the policies of the
+// individual channels.
+type Policies struct {
+	// Default applies to all the channels.
+	Default Policy
+	// Channels override the default policy for the channels, by the
+	// channel ID. The limits which aren't set are taken from the default.
+	Channels map[string]Policy
+}
+
+// LoadPolicies reads the policies from the environment. The channel policies
+// are comma separated, each given as <channel_id>:<max_size>[:<content_type>|...],
+// e.g. "2c1b...:1024:application/senml+json|application/json".
+func LoadPolicies() (Policies, error) {
+	maxSize, err := parseSize(mainflux.Env(envMaxSize, "0"))
+	if err != nil {
+		return Policies{}, errors.Wrap(ErrMalformedPolicy, fmt.Errorf("%s: %s", envMaxSize, err))
+	}
+
+	channels, err := parseChannels(mainflux.Env(envChannels, ""))
+	if err != nil {
+		return Policies{}, errors.Wrap(ErrMalformedPolicy, fmt.Errorf("%s: %s", envChannels, err))
+	}
+
+	return Policies{
+		Default: Policy{
+			MaxSize:      maxSize,
+			ContentTypes: parseContentTypes(mainflux.Env(envContentTypes, ""), ","),
+		},
+		Channels: channels,
+	}, nil
+}
+
+// Policy returns the policy of the channel.
+func (ps Policies) Policy(chanID string) Policy {
+	p := ps.Default
+	cp, ok := ps.Channels[chanID]
+	if !ok {
+		return p
+	}
+	if cp.MaxSize > 0 {
+		p.MaxSize = cp.MaxSize
+	}
+	if len(cp.ContentTypes) > 0 {
+		p.ContentTypes = cp.ContentTypes
+	}
+	return p
+}
+
+// Checker checks the published messages against the policies.
+type Checker interface {
+	// Check returns ErrTooLarge or ErrUnsupportedContentType if the message
+	// violates the policy of its channel. The messages without the content
+	// type, such as the MQTT ones, are subject to the size limit only.
+	Check(msg messaging.Message) error
+}
+
+var _ Checker = (*checker)(nil)
+
+type checker struct {
+	policies Policies
+}
+
+// NewChecker returns the checker enforcing the policies.
+func NewChecker(ps Policies) Checker {
+	return checker{policies: ps}
+}
+
+func (c checker) Check(msg messaging.Message) error {
+	p := c.policies.Policy(msg.Channel)
+	if p.MaxSize > 0 && len(msg.Payload) > p.MaxSize {
+		return ErrTooLarge
+	}
+	if msg.ContentType == "" || len(p.ContentTypes) == 0 {
+		return nil
+	}
+
+	ct := normalize(msg.ContentType)
+	for _, accepted := range p.ContentTypes {
+		if ct == accepted {
+			return nil
+		}
+	}
+	return ErrUnsupportedContentType
+}
+
+func parseChannels(s string) (map[string]Policy, error) {
+	channels := make(map[string]Policy)
+	for _, c := range strings.Split(s, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		parts := strings.SplitN(c, ":", 3)
+		if len(parts) < 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid channel policy %q", c)
+		}
+		size, err := parseSize(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel policy %q: %s", c, err)
+		}
+		p := Policy{MaxSize: size}
+		if len(parts) == 3 {
+			p.ContentTypes = parseContentTypes(parts[2], "|")
+		}
+		channels[parts[0]] = p
+	}
+	return channels, nil
+}
+
+func parseSize(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	size, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	if size < 0 {
+		return 0, fmt.Errorf("negative size %d", size)
+	}
+	return size, nil
+}
+
+func parseContentTypes(s, sep string) []string {
+	var cts []string
+	for _, ct := range strings.Split(s, sep) {
+		if ct = normalize(ct); ct != "" {
+			cts = append(cts, ct)
+		}
+	}
+	return cts
+}
+
+// normalize strips the parameters from the content type, so that e.g.
+// "application/json; charset=utf-8" matches "application/json".
+func normalize(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,133 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package payload_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/mainflux/mainflux/internal/payload"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const chanID = "chan"
+
+func TestCheck(t *testing.T) {
+	checker := payload.NewChecker(payload.Policies{
+		Default: payload.Policy{
+			MaxSize:      4,
+			ContentTypes: []string{"application/senml+json"},
+		},
+		Channels: map[string]payload.Policy{
+			chanID: {MaxSize: 8},
+		},
+	})
+
+	cases := []struct {
+		desc string
+		msg  messaging.Message
+		err  error
+	}{
+		{
+			desc: "check payload within the limits",
+			msg:  messaging.Message{Channel: "other", Payload: []byte("1234"), ContentType: "application/senml+json"},
+			err:  nil,
+		},
+		{
+			desc: "check too large payload",
+			msg:  messaging.Message{Channel: "other", Payload: []byte("12345"), ContentType: "application/senml+json"},
+			err:  payload.ErrTooLarge,
+		},
+		{
+			desc: "check payload of unsupported content type",
+			msg:  messaging.Message{Channel: "other", Payload: []byte("1234"), ContentType: "text/plain"},
+			err:  payload.ErrUnsupportedContentType,
+		},
+		{
+			desc: "check payload of content type with parameters",
+			msg:  messaging.Message{Channel: "other", Payload: []byte("1234"), ContentType: "Application/SenML+JSON; charset=utf-8"},
+			err:  nil,
+		},
+		{
+			desc: "check payload without content type",
+			msg:  messaging.Message{Channel: "other", Payload: []byte("1234")},
+			err:  nil,
+		},
+		{
+			desc: "check payload within the channel limit",
+			msg:  messaging.Message{Channel: chanID, Payload: []byte("12345678"), ContentType: "application/senml+json"},
+			err:  nil,
+		},
+		{
+			desc: "check payload of unsupported content type inherited by the channel",
+			msg:  messaging.Message{Channel: chanID, Payload: []byte("1234"), ContentType: "text/plain"},
+			err:  payload.ErrUnsupportedContentType,
+		},
+	}
+
+	for _, tc := range cases {
+		err := checker.Check(tc.msg)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestLoadPolicies(t *testing.T) {
+	cases := []struct {
+		desc     string
+		env      map[string]string
+		policies payload.Policies
+		err      error
+	}{
+		{
+			desc:     "load empty policies",
+			env:      map[string]string{},
+			policies: payload.Policies{Channels: map[string]payload.Policy{}},
+		},
+		{
+			desc: "load policies",
+			env: map[string]string{
+				"MF_PAYLOAD_MAX_SIZE":      "1024",
+				"MF_PAYLOAD_CONTENT_TYPES": "application/senml+json, application/json",
+				"MF_PAYLOAD_CHANNELS":      "c1:4096, c2::text/plain|application/cbor",
+			},
+			policies: payload.Policies{
+				Default: payload.Policy{MaxSize: 1024, ContentTypes: []string{"application/senml+json", "application/json"}},
+				Channels: map[string]payload.Policy{
+					"c1": {MaxSize: 4096},
+					"c2": {ContentTypes: []string{"text/plain", "application/cbor"}},
+				},
+			},
+		},
+		{
+			desc: "load invalid max size",
+			env:  map[string]string{"MF_PAYLOAD_MAX_SIZE": "-1"},
+			err:  payload.ErrMalformedPolicy,
+		},
+		{
+			desc: "load channel policy without size",
+			env:  map[string]string{"MF_PAYLOAD_CHANNELS": "c1"},
+			err:  payload.ErrMalformedPolicy,
+		},
+		{
+			desc: "load channel policy with invalid size",
+			env:  map[string]string{"MF_PAYLOAD_CHANNELS": "c1:large"},
+			err:  payload.ErrMalformedPolicy,
+		},
+	}
+
+	for _, tc := range cases {
+		for _, key := range []string{"MF_PAYLOAD_MAX_SIZE", "MF_PAYLOAD_CONTENT_TYPES", "MF_PAYLOAD_CHANNELS"} {
+			require.Nil(t, os.Setenv(key, tc.env[key]), fmt.Sprintf("%s: unexpected error setting env", tc.desc))
+		}
+		policies, err := payload.LoadPolicies()
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.policies, policies, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.policies, policies))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ratelimit limits the rate of the requests per client IP address
+// and per account with the token buckets, kept in memory or in Redis.
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrLimitExceeded indicates the client sent too many requests.
+var ErrLimitExceeded = errors.New("rate limit exceeded")
+
+// Limit represents the token bucket holding up to Burst tokens, refilled
+// at Rate tokens per second. Each request takes one token. The zero Limit
+// doesn't limit the requests.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns the limit allowing n requests per minute, in bursts of
+// up to n requests.
+func PerMinute(n int) Limit {
+	return Limit{Rate: float64(n) / time.Minute.Seconds(), Burst: n}
+}
+
+// PerHour returns the limit allowing n requests per hour, in bursts of up
+// to n requests.
+func PerHour(n int) Limit {
+	return Limit{Rate: float64(n) / time.Hour.Seconds(), Burst: n}
+}
+
+// Store stores the token buckets.
+type Store interface {
+	// Take takes the token from the bucket with the given key, and returns
+	// whether the bucket had the token.
+	Take(ctx context.Context, key string, l Limit) (bool, error)
+}
+
+// Limiter limits the requests of the single operation, such as the login,
+// both per client IP address and per account.
+type Limiter struct {
+	store   Store
+	name    string
+	ip      Limit
+	account Limit
+}
+
+// NewLimiter returns the limiter of the named operation, keeping the
+// buckets in the given store.
+func NewLimiter(store Store, name string, ip, account Limit) *Limiter {
+	return &Limiter{
+		store:   store,
+		name:    name,
+		ip:      ip,
+		account: account,
+	}
+}
+
+// Allow takes the token from the buckets of the client IP address and of
+// the account, and returns ErrLimitExceeded if either of them is empty.
+// The empty IP address or account isn't limited. Store failures don't fail
+// the requests, so that the store outage doesn't lock the users out.
+func (l *Limiter) Allow(ctx context.Context, ip, account string) error {
+	if !l.take(ctx, "ip", ip, l.ip) || !l.take(ctx, "account", account, l.account) {
+		return ErrLimitExceeded
+	}
+	return nil
+}
+
+func (l *Limiter) take(ctx context.Context, kind, id string, lim Limit) bool {
+	if id == "" || lim.Burst <= 0 || lim.Rate <= 0 {
+		return true
+	}
+
+	ok, err := l.store.Take(ctx, l.name+":"+kind+":"+id, lim)
+	return err != nil || ok
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/internal/ratelimit"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var errUnavailable = errors.New("store unavailable")
+
+type failingStore struct{}
+
+func (fs failingStore) Take(ctx context.Context, key string, l ratelimit.Limit) (bool, error) {
+	return false, errUnavailable
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	l := ratelimit.Limit{Rate: 20, Burst: 2}
+
+	for i, expected := range []bool{true, true, false} {
+		ok, err := store.Take(context.Background(), "key", l)
+		assert.Nil(t, err, fmt.Sprintf("take %d: unexpected error %s", i, err))
+		assert.Equal(t, expected, ok, fmt.Sprintf("take %d: expected %t got %t", i, expected, ok))
+	}
+
+	ok, err := store.Take(context.Background(), "other", l)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.True(t, ok, "take from other bucket expected to succeed")
+
+	time.Sleep(60 * time.Millisecond)
+	ok, err = store.Take(context.Background(), "key", l)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.True(t, ok, "take from refilled bucket expected to succeed")
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), "login", ratelimit.PerMinute(2), ratelimit.PerMinute(2))
+
+	cases := []struct {
+		desc    string
+		ip      string
+		account string
+		err     error
+	}{
+		{
+			desc:    "allow first request",
+			ip:      "10.0.0.1",
+			account: "user",
+			err:     nil,
+		},
+		{
+			desc:    "allow second request of account",
+			ip:      "10.0.0.1",
+			account: "user",
+			err:     nil,
+		},
+		{
+			desc:    "limit account from other IP",
+			ip:      "10.0.0.2",
+			account: "user",
+			err:     ratelimit.ErrLimitExceeded,
+		},
+		{
+			desc:    "allow other account",
+			ip:      "10.0.0.2",
+			account: "other",
+			err:     nil,
+		},
+		{
+			desc:    "limit IP",
+			ip:      "10.0.0.1",
+			account: "another",
+			err:     ratelimit.ErrLimitExceeded,
+		},
+		{
+			desc:    "allow request without IP",
+			ip:      "",
+			account: "third",
+			err:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := limiter.Allow(context.Background(), tc.ip, tc.account)
+		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	limiter = ratelimit.NewLimiter(failingStore{}, "login", ratelimit.PerMinute(1), ratelimit.PerMinute(1))
+	err := limiter.Allow(context.Background(), "10.0.0.1", "user")
+	assert.Nil(t, err, fmt.Sprintf("store failure expected to allow request: %s", err))
+
+	limiter = ratelimit.NewLimiter(ratelimit.NewMemoryStore(), "login", ratelimit.Limit{}, ratelimit.Limit{})
+	for i := 0; i < 10; i++ {
+		err := limiter.Allow(context.Background(), "10.0.0.1", "user")
+		assert.Nil(t, err, fmt.Sprintf("zero limit expected to allow request: %s", err))
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const keyPrefix = "ratelimit"
+
+// takeScript refills the bucket and takes the token atomically. The bucket
+// expires once it's full again, since the full bucket is the same as the
+// missing one.
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(bucket[1]) or burst
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tokens, "updated", now)
+redis.call("EXPIRE", KEYS[1], tonumber(ARGV[4]))
+return allowed
+`)
+
+var _ Store = (*redisStore)(nil)
+
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore returns the Redis store. Since the buckets are kept in
+// Redis, the requests to all the service instances share the same limits.
+func NewRedisStore(client *redis.Client) Store {
+	return redisStore{client: client}
+}
+
+func (rs redisStore) Take(ctx context.Context, key string, l Limit) (bool, error) {
+	now := float64(time.Now().UnixNano()) / float64(time.Second)
+	ttl := int64(math.Ceil(float64(l.Burst) / l.Rate))
+	allowed, err := takeScript.Run(ctx, rs.client, []string{keyPrefix + ":" + key}, l.Rate, l.Burst, now, ttl).Int()
+	if err != nil {
+		return false, err
+	}
+	return allowed == 1, nil
+}
@@ -3,4 +3,15 @@
 
 // Package logger contains logger API definition, wrapper that
 // can be used around any other logger.
+//
+// The requests logged by the services' logging middlewares can be sampled,
+// labeled with the latency bucket, and stripped of the tokens and the
+// passwords. The services configure it using the following environment
+// variables:
+//
+//	MF_LOG_SAMPLE_RATE      logs 1 in N successful requests, while the failed
+//	                        ones are always logged (default 1)
+//	MF_LOG_LATENCY_BUCKETS  comma separated latency bucket upper bounds, e.g.
+//	                        "10ms,100ms,1s" (default none)
+//	MF_LOG_REDACT           redacts the tokens and the passwords (default true)
 package logger
@@ -5,9 +5,11 @@ package logger
 
 import (
 	"fmt"
-	"github.com/go-kit/kit/log"
 	"io"
+	"sync/atomic"
 	"time"
+
+	"github.com/go-kit/kit/log"
 )
 
 // Logger specifies logging API.
@@ -27,10 +29,18 @@ var _ Logger = (*logger)(nil)
 type logger struct {
 	kitLogger log.Logger
 	level     Level
+	opts      Options
+	requests  *uint64
 }
 
 // New returns wrapped go kit logger.
 func New(out io.Writer, levelText string) (Logger, error) {
+	return NewWithOptions(out, levelText, Options{})
+}
+
+// NewWithOptions returns wrapped go kit logger which samples, labels and
+// redacts the request logs according to the given options.
+func NewWithOptions(out io.Writer, levelText string, opts Options) (Logger, error) {
 	var level Level
 	err := level.UnmarshalText(levelText)
 	if err != nil {
@@ -38,29 +48,51 @@ func New(out io.Writer, levelText string) (Logger, error) {
 	}
 	l := log.NewJSONLogger(log.NewSyncWriter(out))
 	l = log.With(l, "ts", log.DefaultTimestampUTC)
-	return &logger{l, level}, err
+	return &logger{l, level, opts, new(uint64)}, err
 }
 
 func (l logger) Debug(msg string) {
 	if Debug.isAllowed(l.level) {
-		l.kitLogger.Log("level", Debug.String(), "message", msg)
+		l.log(Debug, msg)
 	}
 }
 
 func (l logger) Info(msg string) {
 	if Info.isAllowed(l.level) {
-		l.kitLogger.Log("level", Info.String(), "message", msg)
+		// Successful requests are logged on the info level, and only
+		// those are sampled.
+		if l.opts.SampleRate > 1 && requestMsg.MatchString(msg) {
+			if (atomic.AddUint64(l.requests, 1)-1)%l.opts.SampleRate != 0 {
+				return
+			}
+		}
+		l.log(Info, msg)
 	}
 }
 
 func (l logger) Warn(msg string) {
 	if Warn.isAllowed(l.level) {
-		l.kitLogger.Log("level", Warn.String(), "message", msg)
+		l.log(Warn, msg)
 	}
 }
 
 func (l logger) Error(msg string) {
 	if Error.isAllowed(l.level) {
-		l.kitLogger.Log("level", Error.String(), "message", msg)
+		l.log(Error, msg)
+	}
+}
+
+func (l logger) log(level Level, msg string) {
+	var bucket string
+	if len(l.opts.LatencyBuckets) > 0 {
+		bucket = latencyBucket(msg, l.opts.LatencyBuckets)
+	}
+	if l.opts.Redact {
+		msg = redact(msg)
+	}
+	if bucket != "" {
+		l.kitLogger.Log("level", level.String(), "message", msg, "latency", bucket)
+		return
 	}
+	l.kitLogger.Log("level", level.String(), "message", msg)
 }
@@ -0,0 +1,140 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables configuring the request logging. The variables are
+// shared by all the services.
+const (
+	EnvSampleRate     = "MF_LOG_SAMPLE_RATE"
+	EnvLatencyBuckets = "MF_LOG_LATENCY_BUCKETS"
+	EnvRedact         = "MF_LOG_REDACT"
+
+	defSampleRate = "1"
+	defRedact     = "true"
+
+	redacted = "[REDACTED]"
+)
+
+var (
+	// requestMsg matches the messages logged by the services' logging
+	// middlewares, capturing the request latency.
+	requestMsg = regexp.MustCompile(`took (\S+?),? to complete`)
+
+	// jwt matches the JSON Web Tokens, e.g. the user and the API keys.
+	jwt = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+	// secretFields match the named credentials, capturing the name.
+	secretFields = []*regexp.Regexp{
+		// Credentials named in the message, e.g. "for token <token>".
+		regexp.MustCompile(`(?i)(\b(?:password|secret|token)\b:?\s+)([^"\s,;&}]+)`),
+		// Authorization header values, including the scheme.
+		regexp.MustCompile(`(?i)("?\bauthorization"?\s*[:=]\s*"?)((?:Bearer|Thing|Basic)\s+)?([^"\s,;&}]+)`),
+		// Credentials in the logged payloads, e.g. "password":"<password>".
+		regexp.MustCompile(`(?i)("?\b(?:password|secret|token|key)"?\s*[:=]\s*"?)([^"\s,;&}]+)`),
+	}
+)
+
+// Options configures the logging of the requests handled by the services.
+type Options struct {
+	// SampleRate is the N in logging 1 in N successful requests. The failed
+	// requests are always logged. Zero or one logs all the requests.
+	SampleRate uint64
+
+	// LatencyBuckets are the upper bounds of the latency buckets the
+	// requests are labeled with. No labels are added if empty.
+	LatencyBuckets []time.Duration
+
+	// Redact enables the redaction of the tokens and the passwords in the
+	// logged messages.
+	Redact bool
+}
+
+// ParseOptions parses the request logging options from their textual
+// representation. Latency buckets are the comma separated durations.
+func ParseOptions(sampleRate, latencyBuckets, redact string) (Options, error) {
+	var opts Options
+
+	rate, err := strconv.ParseUint(sampleRate, 10, 64)
+	if err != nil {
+		return Options{}, fmt.Errorf("invalid sample rate %s: %s", sampleRate, err)
+	}
+	opts.SampleRate = rate
+
+	for _, b := range strings.Split(latencyBuckets, ",") {
+		b = strings.TrimSpace(b)
+		if b == "" {
+			continue
+		}
+		d, err := time.ParseDuration(b)
+		if err != nil || d <= 0 {
+			return Options{}, fmt.Errorf("invalid latency bucket %s", b)
+		}
+		opts.LatencyBuckets = append(opts.LatencyBuckets, d)
+	}
+	sort.Slice(opts.LatencyBuckets, func(i, j int) bool {
+		return opts.LatencyBuckets[i] < opts.LatencyBuckets[j]
+	})
+
+	if opts.Redact, err = strconv.ParseBool(redact); err != nil {
+		return Options{}, fmt.Errorf("invalid redact flag %s: %s", redact, err)
+	}
+
+	return opts, nil
+}
+
+// NewFromEnv returns wrapped go kit logger configured using the request
+// logging environment variables.
+func NewFromEnv(out io.Writer, levelText string) (Logger, error) {
+	opts, err := ParseOptions(env(EnvSampleRate, defSampleRate), env(EnvLatencyBuckets, ""), env(EnvRedact, defRedact))
+	if err != nil {
+		return nil, fmt.Errorf(`{"level":"error","message":"%s","ts":"%s"}`, err, time.RFC3339Nano)
+	}
+	return NewWithOptions(out, levelText, opts)
+}
+
+func env(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+// redact replaces the tokens and the passwords in the message.
+func redact(msg string) string {
+	msg = jwt.ReplaceAllString(msg, redacted)
+	for _, re := range secretFields {
+		msg = re.ReplaceAllString(msg, "${1}"+redacted)
+	}
+	return msg
+}
+
+// latencyBucket returns the label of the bucket the request latency falls
+// into, or the empty string if the message is not the request log.
+func latencyBucket(msg string, buckets []time.Duration) string {
+	m := requestMsg.FindStringSubmatch(msg)
+	if m == nil {
+		return ""
+	}
+	d, err := time.ParseDuration(m[1])
+	if err != nil {
+		return ""
+	}
+	for _, b := range buckets {
+		if d <= b {
+			return fmt.Sprintf("<=%s", b)
+		}
+	}
+	return fmt.Sprintf(">%s", buckets[len(buckets)-1])
+}
@@ -0,0 +1,381 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lwm2m contains the domain concept definitions needed to support
+// Mainflux LwM2M adapter service functionality. The adapter acts as the
+// LwM2M bootstrap and device management server. Objects observed on the
+// registered LwM2M clients are published to the Mainflux channels, while the
+// messages sent to the client write subtopic are written to the client.
+package lwm2m
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers/lwm2m"
+)
+
+const (
+	protocol       = "lwm2m"
+	chansPrefix    = "channels"
+	writePrefix    = "lwm2m"
+	defContentType = "text/plain"
+
+	securityPath = "/0"
+	serverPath   = "/1"
+	shortID      = 1
+
+	// Resources of the Security object.
+	securityServerURI = 0
+	securityBootstrap = 1
+	securityMode      = 2
+	securityIdentity  = 3
+	securitySecretKey = 5
+	securityShortID   = 10
+
+	// Resources of the Server object.
+	serverShortID  = 0
+	serverLifetime = 1
+	serverBinding  = 7
+
+	modePSK   = 0
+	modeNoSec = 3
+)
+
+var (
+	// ErrUnauthorized indicates missing or invalid thing credentials.
+	ErrUnauthorized = errors.New("unauthorized access")
+
+	// ErrNotFound indicates non-existent registration.
+	ErrNotFound = errors.New("registration not found")
+
+	// ErrMalformedEntity indicates malformed registration.
+	ErrMalformedEntity = errors.New("malformed registration")
+)
+
+// NotificationHandler handles the observe notifications of the client.
+type NotificationHandler func(contentType string, payload []byte)
+
+// Client represents the connection to the LwM2M client.
+type Client interface {
+	// Observe starts observing the path on the client.
+	Observe(ctx context.Context, path string, h NotificationHandler) error
+
+	// Write writes the payload to the path on the client.
+	Write(ctx context.Context, path, contentType string, payload []byte) error
+
+	// Delete deletes the path on the client.
+	Delete(ctx context.Context, path string) error
+
+	// FinishBootstrap notifies the client that the bootstrap is finished.
+	FinishBootstrap(ctx context.Context) error
+
+	// Done returns the channel closed when the connection is closed.
+	Done() <-chan struct{}
+}
+
+// Registration represents the registration of the LwM2M client.
+type Registration struct {
+	ID       string
+	Endpoint string
+	ThingID  string
+	Channel  string
+	Lifetime time.Duration
+	Objects  []string
+}
+
+// Service specifies LwM2M adapter service API.
+type Service interface {
+	// Register registers the LwM2M client using the thing key and starts
+	// observing the objects of the client. It returns the registration ID.
+	Register(ctx context.Context, key string, r Registration, c Client) (string, error)
+
+	// Update updates the lifetime and objects of the registration.
+	Update(ctx context.Context, key string, r Registration) error
+
+	// Deregister removes the registration.
+	Deregister(ctx context.Context, key, id string) error
+
+	// Bootstrap writes the LwM2M server account to the client.
+	Bootstrap(ctx context.Context, key, endpoint string, c Client) error
+
+	// PSK returns the DTLS pre-shared key of the thing.
+	PSK(ctx context.Context, thingID string) ([]byte, error)
+}
+
+// Config represents the LwM2M server account written to the clients during
+// the bootstrap.
+type Config struct {
+	ServerURI string
+	Lifetime  time.Duration
+	PSK       bool
+}
+
+type registration struct {
+	Registration
+	client Client
+	timer  *time.Timer
+}
+
+var _ Service = (*adapterService)(nil)
+
+type adapterService struct {
+	things        Things
+	pubsub        messaging.PubSub
+	cfg           Config
+	idp           mainflux.IDProvider
+	mu            sync.Mutex
+	registrations map[string]*registration
+}
+
+// New instantiates the LwM2M adapter implementation.
+func New(things Things, pubsub messaging.PubSub, idp mainflux.IDProvider, cfg Config) Service {
+	return &adapterService{
+		things:        things,
+		pubsub:        pubsub,
+		cfg:           cfg,
+		idp:           idp,
+		registrations: make(map[string]*registration),
+	}
+}
+
+func (svc *adapterService) Register(ctx context.Context, key string, r Registration, c Client) (string, error) {
+	if r.Endpoint == "" || strings.ContainsAny(r.Endpoint, ".*>") {
+		return "", ErrMalformedEntity
+	}
+
+	th, err := svc.things.Identify(ctx, key)
+	if err != nil {
+		return "", errors.Wrap(ErrUnauthorized, err)
+	}
+
+	id, err := svc.idp.ID()
+	if err != nil {
+		return "", err
+	}
+	r.ID = id
+	r.ThingID = th.ID
+	r.Channel = th.Channel
+
+	// Registering the same endpoint again replaces the old registration.
+	svc.mu.Lock()
+	for _, old := range svc.registrations {
+		if old.Endpoint == r.Endpoint {
+			svc.remove(old)
+		}
+	}
+	reg := &registration{
+		Registration: r,
+		client:       c,
+	}
+	reg.timer = time.AfterFunc(r.Lifetime, func() { svc.expire(reg.ID) })
+	svc.registrations[r.ID] = reg
+	svc.mu.Unlock()
+
+	if err := svc.pubsub.Subscribe(writeSubject(r), svc.writeHandler(reg)); err != nil {
+		svc.expire(reg.ID)
+		return "", err
+	}
+
+	go func() {
+		<-c.Done()
+		svc.expire(reg.ID)
+	}()
+
+	// Objects are observed asynchronously since the client can't respond
+	// to the requests before it receives the registration response.
+	go svc.observe(reg, r.Objects)
+
+	return r.ID, nil
+}
+
+func (svc *adapterService) Update(ctx context.Context, key string, r Registration) error {
+	th, err := svc.things.Identify(ctx, key)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorized, err)
+	}
+
+	svc.mu.Lock()
+	reg, ok := svc.registrations[r.ID]
+	if !ok || reg.ThingID != th.ID {
+		svc.mu.Unlock()
+		return ErrNotFound
+	}
+	if r.Lifetime > 0 {
+		reg.Lifetime = r.Lifetime
+	}
+	reg.timer.Reset(reg.Lifetime)
+
+	var added []string
+	if r.Objects != nil {
+		observed := make(map[string]bool)
+		for _, o := range reg.Objects {
+			observed[o] = true
+		}
+		for _, o := range r.Objects {
+			if !observed[o] {
+				added = append(added, o)
+			}
+		}
+		reg.Objects = r.Objects
+	}
+	svc.mu.Unlock()
+
+	go svc.observe(reg, added)
+
+	return nil
+}
+
+func (svc *adapterService) Deregister(ctx context.Context, key, id string) error {
+	th, err := svc.things.Identify(ctx, key)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorized, err)
+	}
+
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	reg, ok := svc.registrations[id]
+	if !ok || reg.ThingID != th.ID {
+		return ErrNotFound
+	}
+	svc.remove(reg)
+
+	return nil
+}
+
+func (svc *adapterService) Bootstrap(ctx context.Context, key, endpoint string, c Client) error {
+	th, err := svc.things.Identify(ctx, key)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorized, err)
+	}
+
+	security := []lwm2m.TLV{
+		resource(securityServerURI, []byte(svc.cfg.ServerURI)),
+		resource(securityBootstrap, []byte{0}),
+		resource(securityShortID, []byte{shortID}),
+	}
+	if svc.cfg.PSK {
+		security = append(security,
+			resource(securityMode, []byte{modePSK}),
+			resource(securityIdentity, []byte(th.ID)),
+			resource(securitySecretKey, []byte(key)),
+		)
+	} else {
+		security = append(security, resource(securityMode, []byte{modeNoSec}))
+	}
+
+	lifetime := int32(svc.cfg.Lifetime.Seconds())
+	server := []lwm2m.TLV{
+		resource(serverShortID, []byte{shortID}),
+		resource(serverLifetime, []byte{byte(lifetime >> 24), byte(lifetime >> 16), byte(lifetime >> 8), byte(lifetime)}),
+		resource(serverBinding, []byte("U")),
+	}
+
+	// The client is bootstrapped after it receives the response to the
+	// bootstrap request, so the bootstrap has to be done asynchronously.
+	go func() {
+		ctx := context.Background()
+		writes := []struct {
+			path string
+			tlvs []lwm2m.TLV
+		}{
+			{securityPath, []lwm2m.TLV{instance(0, security)}},
+			{serverPath, []lwm2m.TLV{instance(0, server)}},
+		}
+		// Bootstrap-Delete of all the objects removes the accounts of the
+		// previous bootstraps.
+		if err := c.Delete(ctx, "/"); err != nil {
+			return
+		}
+		for _, w := range writes {
+			if err := c.Write(ctx, w.path, lwm2m.TLVContentType, lwm2m.EncodeTLV(w.tlvs)); err != nil {
+				return
+			}
+		}
+		c.FinishBootstrap(ctx)
+	}()
+
+	return nil
+}
+
+func (svc *adapterService) PSK(ctx context.Context, thingID string) ([]byte, error) {
+	key, err := svc.things.Key(ctx, thingID)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorized, err)
+	}
+	return []byte(key), nil
+}
+
+func (svc *adapterService) observe(reg *registration, objects []string) {
+	for _, o := range objects {
+		path := o
+		subtopic := strings.Replace(strings.Trim(path, "/"), "/", ".", -1)
+		h := func(contentType string, payload []byte) {
+			msg := messaging.Message{
+				Channel:     reg.Channel,
+				Subtopic:    subtopic,
+				Publisher:   reg.ThingID,
+				Protocol:    protocol,
+				Payload:     payload,
+				ContentType: contentType,
+				Created:     time.Now().UnixNano(),
+			}
+			svc.pubsub.Publish(reg.Channel, msg)
+		}
+		reg.client.Observe(context.Background(), path, h)
+	}
+}
+
+// writeHandler writes the messages published to the write subtopic of the
+// registration to the client. The rest of the subtopic is the path written,
+// e.g. the message published to "lwm2m.<endpoint>.3311.0.5850" subtopic is
+// written to the "/3311/0/5850" path.
+func (svc *adapterService) writeHandler(reg *registration) messaging.MessageHandler {
+	prefix := fmt.Sprintf("%s.%s.", writePrefix, reg.Endpoint)
+	return func(msg messaging.Message) error {
+		if !strings.HasPrefix(msg.Subtopic, prefix) {
+			return nil
+		}
+		path := "/" + strings.Replace(strings.TrimPrefix(msg.Subtopic, prefix), ".", "/", -1)
+		ct := msg.ContentType
+		if ct == "" {
+			ct = defContentType
+		}
+		return reg.client.Write(context.Background(), path, ct, msg.Payload)
+	}
+}
+
+func (svc *adapterService) expire(id string) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+
+	if reg, ok := svc.registrations[id]; ok {
+		svc.remove(reg)
+	}
+}
+
+// remove removes the registration. It has to be called with the lock held.
+func (svc *adapterService) remove(reg *registration) {
+	reg.timer.Stop()
+	svc.pubsub.Unsubscribe(writeSubject(reg.Registration))
+	delete(svc.registrations, reg.ID)
+}
+
+func writeSubject(r Registration) string {
+	return fmt.Sprintf("%s.%s.%s.%s.>", chansPrefix, r.Channel, writePrefix, r.Endpoint)
+}
+
+func resource(id uint16, value []byte) lwm2m.TLV {
+	return lwm2m.TLV{Type: lwm2m.Resource, ID: id, Value: value}
+}
+
+func instance(id uint16, children []lwm2m.TLV) lwm2m.TLV {
+	return lwm2m.TLV{Type: lwm2m.ObjectInstance, ID: id, Children: children}
+}
@@ -0,0 +1,293 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lwm2m_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/lwm2m/mocks"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	tlv "github.com/mainflux/mainflux/pkg/transformers/lwm2m"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	key      = "thing-key"
+	thingID  = "thing-id"
+	chanID   = "chan-id"
+	endpoint = "sensor-1"
+	lifetime = time.Minute
+	wait     = time.Second
+	tick     = 10 * time.Millisecond
+)
+
+func newService(ps messaging.PubSub) lwm2m.Service {
+	things := mocks.NewThings(map[string]lwm2m.Thing{
+		key: {ID: thingID, Channel: chanID},
+	})
+	return lwm2m.New(things, ps, uuid.NewMock(), lwm2m.Config{
+		ServerURI: "coaps://localhost:5784",
+		Lifetime:  lifetime,
+		PSK:       true,
+	})
+}
+
+func TestRegister(t *testing.T) {
+	svc := newService(mocks.NewPubSub())
+
+	cases := []struct {
+		desc string
+		key  string
+		reg  lwm2m.Registration
+		err  error
+	}{
+		{
+			desc: "register client",
+			key:  key,
+			reg:  lwm2m.Registration{Endpoint: endpoint, Lifetime: lifetime},
+			err:  nil,
+		},
+		{
+			desc: "register client with invalid key",
+			key:  "invalid",
+			reg:  lwm2m.Registration{Endpoint: endpoint, Lifetime: lifetime},
+			err:  lwm2m.ErrUnauthorized,
+		},
+		{
+			desc: "register client without endpoint",
+			key:  key,
+			reg:  lwm2m.Registration{Lifetime: lifetime},
+			err:  lwm2m.ErrMalformedEntity,
+		},
+		{
+			desc: "register client with wildcard endpoint",
+			key:  key,
+			reg:  lwm2m.Registration{Endpoint: "sensor.>", Lifetime: lifetime},
+			err:  lwm2m.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.Register(context.Background(), tc.key, tc.reg, mocks.NewClient())
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestObserve(t *testing.T) {
+	ps := mocks.NewPubSub()
+	svc := newService(ps)
+	c := mocks.NewClient()
+
+	r := lwm2m.Registration{Endpoint: endpoint, Lifetime: lifetime, Objects: []string{"/3303/0"}}
+	_, err := svc.Register(context.Background(), key, r, c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	payload := []byte(`[{"n":"5700","v":21.5}]`)
+	assert.Eventually(t, func() bool {
+		return c.Notify("/3303/0", "application/senml+json", payload)
+	}, wait, tick, "expected object to be observed")
+
+	published := ps.Published()
+	require.Len(t, published, 1, fmt.Sprintf("expected 1 message got %d\n", len(published)))
+	msg := published[0]
+	assert.Equal(t, chanID, msg.Channel, fmt.Sprintf("expected channel %s got %s\n", chanID, msg.Channel))
+	assert.Equal(t, "3303.0", msg.Subtopic, fmt.Sprintf("expected subtopic 3303.0 got %s\n", msg.Subtopic))
+	assert.Equal(t, thingID, msg.Publisher, fmt.Sprintf("expected publisher %s got %s\n", thingID, msg.Publisher))
+	assert.Equal(t, "application/senml+json", msg.ContentType, fmt.Sprintf("expected content type application/senml+json got %s\n", msg.ContentType))
+	assert.Equal(t, payload, msg.Payload, fmt.Sprintf("expected payload %s got %s\n", payload, msg.Payload))
+}
+
+func TestWrite(t *testing.T) {
+	ps := mocks.NewPubSub()
+	svc := newService(ps)
+	c := mocks.NewClient()
+
+	r := lwm2m.Registration{Endpoint: endpoint, Lifetime: lifetime}
+	id, err := svc.Register(context.Background(), key, r, c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc        string
+		subtopic    string
+		contentType string
+		writes      []mocks.Write
+	}{
+		{
+			desc:     "write resource",
+			subtopic: "lwm2m.sensor-1.3311.0.5850",
+			writes:   []mocks.Write{{Path: "/3311/0/5850", ContentType: "text/plain", Payload: []byte("1")}},
+		},
+		{
+			desc:        "write resource with content type",
+			subtopic:    "lwm2m.sensor-1.3311.0.5851",
+			contentType: "application/vnd.oma.lwm2m+tlv",
+			writes:      []mocks.Write{{Path: "/3311/0/5851", ContentType: "application/vnd.oma.lwm2m+tlv", Payload: []byte("1")}},
+		},
+		{
+			desc:     "write resource of another endpoint",
+			subtopic: "lwm2m.sensor-2.3311.0.5850",
+			writes:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		c := mocks.NewClient()
+		_, err := svc.Register(context.Background(), key, r, c)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		msg := messaging.Message{Channel: chanID, Subtopic: tc.subtopic, ContentType: tc.contentType, Payload: []byte("1")}
+		err = ps.Publish(chanID, msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.ElementsMatch(t, tc.writes, c.Writes(), fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.writes, c.Writes()))
+	}
+
+	err = svc.Deregister(context.Background(), key, id)
+	assert.True(t, errors.Contains(err, lwm2m.ErrNotFound), fmt.Sprintf("replaced registration: expected %s got %s\n", lwm2m.ErrNotFound, err))
+}
+
+func TestUpdate(t *testing.T) {
+	svc := newService(mocks.NewPubSub())
+	c := mocks.NewClient()
+
+	id, err := svc.Register(context.Background(), key, lwm2m.Registration{Endpoint: endpoint, Lifetime: lifetime}, c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		key  string
+		reg  lwm2m.Registration
+		err  error
+	}{
+		{
+			desc: "update registration",
+			key:  key,
+			reg:  lwm2m.Registration{ID: id, Objects: []string{"/3303/0"}},
+			err:  nil,
+		},
+		{
+			desc: "update registration with invalid key",
+			key:  "invalid",
+			reg:  lwm2m.Registration{ID: id},
+			err:  lwm2m.ErrUnauthorized,
+		},
+		{
+			desc: "update non-existing registration",
+			key:  key,
+			reg:  lwm2m.Registration{ID: "non-existing"},
+			err:  lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Update(context.Background(), tc.key, tc.reg)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	assert.Eventually(t, func() bool {
+		return c.Notify("/3303/0", "text/plain", []byte("1"))
+	}, wait, tick, "expected added object to be observed")
+}
+
+func TestDeregister(t *testing.T) {
+	svc := newService(mocks.NewPubSub())
+
+	id, err := svc.Register(context.Background(), key, lwm2m.Registration{Endpoint: endpoint, Lifetime: lifetime}, mocks.NewClient())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		key  string
+		id   string
+		err  error
+	}{
+		{
+			desc: "deregister with invalid key",
+			key:  "invalid",
+			id:   id,
+			err:  lwm2m.ErrUnauthorized,
+		},
+		{
+			desc: "deregister",
+			key:  key,
+			id:   id,
+			err:  nil,
+		},
+		{
+			desc: "deregister removed registration",
+			key:  key,
+			id:   id,
+			err:  lwm2m.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.Deregister(context.Background(), tc.key, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	svc := newService(mocks.NewPubSub())
+
+	err := svc.Bootstrap(context.Background(), "invalid", endpoint, mocks.NewClient())
+	assert.True(t, errors.Contains(err, lwm2m.ErrUnauthorized), fmt.Sprintf("bootstrap with invalid key: expected %s got %s\n", lwm2m.ErrUnauthorized, err))
+
+	c := mocks.NewClient()
+	err = svc.Bootstrap(context.Background(), key, endpoint, c)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Eventually(t, c.Bootstrapped, wait, tick, "expected bootstrap to be finished")
+
+	assert.Equal(t, []string{"/"}, c.Deletes(), fmt.Sprintf("expected / to be deleted got %v\n", c.Deletes()))
+	writes := c.Writes()
+	require.Len(t, writes, 2, fmt.Sprintf("expected 2 writes got %d\n", len(writes)))
+	assert.Equal(t, "/0", writes[0].Path, fmt.Sprintf("expected Security object to be written got %s\n", writes[0].Path))
+	assert.Equal(t, "/1", writes[1].Path, fmt.Sprintf("expected Server object to be written got %s\n", writes[1].Path))
+
+	tlvs, err := tlv.DecodeTLV(writes[0].Payload)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Len(t, tlvs, 1, fmt.Sprintf("expected 1 instance got %d\n", len(tlvs)))
+	res := make(map[uint16][]byte)
+	for _, r := range tlvs[0].Children {
+		res[r.ID] = r.Value
+	}
+	assert.Equal(t, []byte(thingID), res[3], fmt.Sprintf("expected PSK identity %s got %s\n", thingID, res[3]))
+	assert.Equal(t, []byte(key), res[5], fmt.Sprintf("expected PSK %s got %s\n", key, res[5]))
+}
+
+func TestPSK(t *testing.T) {
+	svc := newService(mocks.NewPubSub())
+
+	cases := []struct {
+		desc string
+		id   string
+		psk  []byte
+		err  error
+	}{
+		{
+			desc: "get PSK of existing thing",
+			id:   thingID,
+			psk:  []byte(key),
+			err:  nil,
+		},
+		{
+			desc: "get PSK of non-existing thing",
+			id:   "non-existing",
+			psk:  nil,
+			err:  lwm2m.ErrUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		psk, err := svc.PSK(context.Background(), tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.psk, psk, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.psk, psk))
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/lwm2m"
+	piondtls "github.com/pion/dtls/v2"
+	"github.com/plgd-dev/go-coap/v2/dtls"
+	coapnet "github.com/plgd-dev/go-coap/v2/net"
+	udpclient "github.com/plgd-dev/go-coap/v2/udp/client"
+)
+
+const (
+	identityHint     = "mainflux"
+	handshakeTimeout = 30 * time.Second
+)
+
+var _ dtls.Listener = (*PSKListener)(nil)
+
+// PSKListener is the DTLS listener authenticating the LwM2M clients using
+// the pre-shared keys. PSK identity is the thing ID, while the PSK is the
+// thing key. The thing key of the authenticated connection is made
+// available to the handler through the connection context.
+type PSKListener struct {
+	svc    lwm2m.Service
+	ln     *udpListener
+	cfg    piondtls.Config
+	conns  chan *piondtls.Conn
+	done   chan struct{}
+	mu     sync.Mutex
+	keys   map[*piondtls.Conn]string
+	closed bool
+}
+
+// NewPSKListener returns the PSK listener listening on the address.
+func NewPSKListener(svc lwm2m.Service, network, addr string) (*PSKListener, error) {
+	a, err := net.ResolveUDPAddr(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := listenUDP(network, a)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &PSKListener{
+		svc: svc,
+		ln:  ln,
+		cfg: piondtls.Config{
+			PSKIdentityHint: []byte(identityHint),
+			CipherSuites: []piondtls.CipherSuiteID{
+				piondtls.TLS_PSK_WITH_AES_128_CCM_8,
+				piondtls.TLS_PSK_WITH_AES_128_CCM,
+				piondtls.TLS_PSK_WITH_AES_128_GCM_SHA256,
+			},
+			ConnectContextMaker: func() (context.Context, func()) {
+				return context.WithTimeout(context.Background(), handshakeTimeout)
+			},
+		},
+		conns: make(chan *piondtls.Conn),
+		done:  make(chan struct{}),
+		keys:  make(map[*piondtls.Conn]string),
+	}
+	go l.accept()
+
+	return l, nil
+}
+
+// AcceptWithContext returns the next connection which completed the
+// handshake.
+func (l *PSKListener) AcceptWithContext(ctx context.Context) (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, coapnet.ErrListenerIsClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close closes the listener.
+func (l *PSKListener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.done)
+	l.mu.Unlock()
+
+	return l.ln.Close()
+}
+
+// OnNewClientConn stores the thing key of the connection to its context.
+func (l *PSKListener) OnNewClientConn(cc *udpclient.ClientConn, conn *piondtls.Conn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if key, ok := l.keys[conn]; ok {
+		cc.SetContextValue(keyCtx{}, key)
+		delete(l.keys, conn)
+	}
+}
+
+func (l *PSKListener) accept() {
+	for {
+		conn, err := l.ln.Accept()
+		if err != nil {
+			return
+		}
+		go l.handshake(conn)
+	}
+}
+
+// handshake performs the handshake of the connection, resolving the PSK of
+// the connection in its own callback, so the handshakes of the different
+// clients run concurrently.
+func (l *PSKListener) handshake(conn net.Conn) {
+	var key string
+	cfg := l.cfg
+	cfg.PSK = func(identity []byte) ([]byte, error) {
+		psk, err := l.psk(identity)
+		key = string(psk)
+		return psk, err
+	}
+
+	c, err := piondtls.Server(conn, &cfg)
+	if err != nil {
+		conn.Close()
+		return
+	}
+
+	l.mu.Lock()
+	l.keys[c] = key
+	l.mu.Unlock()
+
+	select {
+	case l.conns <- c:
+	case <-l.done:
+		l.mu.Lock()
+		delete(l.keys, c)
+		l.mu.Unlock()
+		c.Close()
+	}
+}
+
+func (l *PSKListener) psk(identity []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), handshakeTimeout)
+	defer cancel()
+
+	return l.svc.PSK(ctx, string(identity))
+}
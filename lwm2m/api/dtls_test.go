@@ -0,0 +1,72 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/lwm2m/mocks"
+	"github.com/mainflux/mainflux/pkg/uuid"
+	piondtls "github.com/pion/dtls/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPSKListener(t *testing.T) {
+	things := map[string]lwm2m.Thing{
+		"key-1": {ID: "thing-1", Channel: "chan"},
+		"key-2": {ID: "thing-2", Channel: "chan"},
+		"key-3": {ID: "thing-3", Channel: "chan"},
+	}
+	svc := lwm2m.New(mocks.NewThings(things), mocks.NewPubSub(), uuid.NewMock(), lwm2m.Config{Lifetime: time.Minute, PSK: true})
+
+	l, err := NewPSKListener(svc, "udp", "127.0.0.1:0")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	defer l.Close()
+	addr := l.ln.Addr().(*net.UDPAddr)
+
+	// The clients handshake concurrently, so each connection has to get
+	// the key of its own client.
+	type client struct {
+		conn *piondtls.Conn
+		key  string
+		err  error
+	}
+	clients := make(chan client, len(things))
+	for key, th := range things {
+		go func(key, id string) {
+			c, err := piondtls.Dial("udp", addr, &piondtls.Config{
+				PSK:             func([]byte) ([]byte, error) { return []byte(key), nil },
+				PSKIdentityHint: []byte(id),
+				CipherSuites:    []piondtls.CipherSuiteID{piondtls.TLS_PSK_WITH_AES_128_CCM_8},
+			})
+			clients <- client{conn: c, key: key, err: err}
+		}(key, th.ID)
+	}
+
+	keys := make(map[string]string)
+	for i := 0; i < len(things); i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		conn, err := l.AcceptWithContext(ctx)
+		cancel()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		c := conn.(*piondtls.Conn)
+		l.mu.Lock()
+		keys[c.RemoteAddr().String()] = l.keys[c]
+		l.mu.Unlock()
+	}
+
+	for i := 0; i < len(things); i++ {
+		c := <-clients
+		require.Nil(t, c.err, fmt.Sprintf("unexpected handshake error: %s", c.err))
+		defer c.conn.Close()
+		addr := c.conn.LocalAddr().String()
+		assert.Equal(t, c.key, keys[addr], fmt.Sprintf("expected key %s of connection %s got %s", c.key, addr, keys[addr]))
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+var _ lwm2m.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    lwm2m.Service
+}
+
+// LoggingMiddleware adds logging facilities to the adapter.
+func LoggingMiddleware(svc lwm2m.Service, logger log.Logger) lwm2m.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Register(ctx context.Context, key string, r lwm2m.Registration, c lwm2m.Client) (id string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method register for endpoint %s took %s to complete", r.Endpoint, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Register(ctx, key, r, c)
+}
+
+func (lm *loggingMiddleware) Update(ctx context.Context, key string, r lwm2m.Registration) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update for registration %s took %s to complete", r.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Update(ctx, key, r)
+}
+
+func (lm *loggingMiddleware) Deregister(ctx context.Context, key, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method deregister for registration %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Deregister(ctx, key, id)
+}
+
+func (lm *loggingMiddleware) Bootstrap(ctx context.Context, key, endpoint string, c lwm2m.Client) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method bootstrap for endpoint %s took %s to complete", endpoint, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Bootstrap(ctx, key, endpoint, c)
+}
+
+func (lm *loggingMiddleware) PSK(ctx context.Context, thingID string) (psk []byte, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method psk for thing %s took %s to complete", thingID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.PSK(ctx, thingID)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+var _ lwm2m.Service = (*metricsMiddleware)(nil)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     lwm2m.Service
+}
+
+// MetricsMiddleware instruments adapter by tracking request count and latency.
+func MetricsMiddleware(svc lwm2m.Service, counter metrics.Counter, latency metrics.Histogram) lwm2m.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (mm *metricsMiddleware) Register(ctx context.Context, key string, r lwm2m.Registration, c lwm2m.Client) (string, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "register").Add(1)
+		mm.latency.With("method", "register").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Register(ctx, key, r, c)
+}
+
+func (mm *metricsMiddleware) Update(ctx context.Context, key string, r lwm2m.Registration) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "update").Add(1)
+		mm.latency.With("method", "update").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Update(ctx, key, r)
+}
+
+func (mm *metricsMiddleware) Deregister(ctx context.Context, key, id string) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "deregister").Add(1)
+		mm.latency.With("method", "deregister").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Deregister(ctx, key, id)
+}
+
+func (mm *metricsMiddleware) Bootstrap(ctx context.Context, key, endpoint string, c lwm2m.Client) error {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "bootstrap").Add(1)
+		mm.latency.With("method", "bootstrap").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.Bootstrap(ctx, key, endpoint, c)
+}
+
+func (mm *metricsMiddleware) PSK(ctx context.Context, thingID string) ([]byte, error) {
+	defer func(begin time.Time) {
+		mm.counter.With("method", "psk").Add(1)
+		mm.latency.With("method", "psk").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return mm.svc.PSK(ctx, thingID)
+}
@@ -0,0 +1,293 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/plgd-dev/go-coap/v2/message"
+	"github.com/plgd-dev/go-coap/v2/message/codes"
+	"github.com/plgd-dev/go-coap/v2/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	protocol       = "lwm2m"
+	authQuery      = "auth"
+	endpointQuery  = "ep"
+	lifetimeQuery  = "lt"
+	registerPath   = "rd"
+	bootstrapPath  = "bs"
+	defLifetime    = 86400 * time.Second
+	requestTimeout = 30 * time.Second
+)
+
+var (
+	errMalformedLifetime = errors.New("malformed lifetime")
+	errMissingKey        = errors.New("missing thing key")
+	errUnexpectedCode    = errors.New("unexpected response code")
+)
+
+// contentTypes maps CoAP content formats to the message content types.
+var contentTypes = map[message.MediaType]string{
+	message.TextPlain:    "text/plain",
+	message.AppOctets:    "application/octet-stream",
+	message.AppJSON:      "application/json",
+	message.AppCBOR:      "application/cbor",
+	110:                  "application/senml+json",
+	112:                  "application/senml+cbor",
+	message.AppLwm2mTLV:  "application/vnd.oma.lwm2m+tlv",
+	message.AppLwm2mJSON: "application/vnd.oma.lwm2m+json",
+}
+
+type keyCtx struct{}
+
+var (
+	logger  log.Logger
+	service lwm2m.Service
+)
+
+// MakeHTTPHandler creates handler for version and metrics endpoints.
+func MakeHTTPHandler() http.Handler {
+	b := bone.New()
+	b.GetFunc("/version", mainflux.Version(protocol))
+	b.Handle("/metrics", promhttp.Handler())
+
+	return b
+}
+
+// MakeCoAPHandler creates handler for the LwM2M bootstrap and registration
+// interfaces.
+func MakeCoAPHandler(svc lwm2m.Service, l log.Logger) mux.HandlerFunc {
+	logger = l
+	service = svc
+
+	return handler
+}
+
+func handler(w mux.ResponseWriter, m *mux.Message) {
+	resp := message.Message{
+		Code:    codes.Changed,
+		Token:   m.Token,
+		Context: m.Context,
+		Options: make(message.Options, 0, 16),
+	}
+	defer sendResp(w, &resp)
+
+	path, err := m.Options.Path()
+	if err != nil {
+		resp.Code = codes.BadOption
+		return
+	}
+	queries := parseQueries(m.Options)
+	key := queries[authQuery]
+	if k, ok := w.Client().Context().Value(keyCtx{}).(string); ok {
+		key = k
+	}
+	if key == "" {
+		logger.Warn(fmt.Sprintf("Error parsing auth: %s", errMissingKey))
+		resp.Code = codes.Unauthorized
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case m.Code == codes.POST && len(parts) == 1 && parts[0] == bootstrapPath:
+		err = service.Bootstrap(ctx, key, queries[endpointQuery], newClient(w.Client()))
+	case m.Code == codes.POST && len(parts) == 1 && parts[0] == registerPath:
+		var r lwm2m.Registration
+		if r, err = decodeRegistration(m, queries, true); err != nil {
+			break
+		}
+		var id string
+		if id, err = service.Register(ctx, key, r, newClient(w.Client())); err != nil {
+			break
+		}
+		resp.Code = codes.Created
+		resp.Options = resp.Options.Add(message.Option{ID: message.LocationPath, Value: []byte(registerPath)})
+		resp.Options = resp.Options.Add(message.Option{ID: message.LocationPath, Value: []byte(id)})
+	case m.Code == codes.POST && len(parts) == 2 && parts[0] == registerPath:
+		var r lwm2m.Registration
+		if r, err = decodeRegistration(m, queries, false); err != nil {
+			break
+		}
+		r.ID = parts[1]
+		err = service.Update(ctx, key, r)
+	case m.Code == codes.DELETE && len(parts) == 2 && parts[0] == registerPath:
+		resp.Code = codes.Deleted
+		err = service.Deregister(ctx, key, parts[1])
+	default:
+		resp.Code = codes.NotFound
+		return
+	}
+
+	if err != nil {
+		resp.Code = encodeError(err)
+	}
+}
+
+func sendResp(w mux.ResponseWriter, resp *message.Message) {
+	if err := w.Client().WriteMessage(resp); err != nil {
+		logger.Warn(fmt.Sprintf("Can't set response: %s", err))
+	}
+}
+
+func encodeError(err error) codes.Code {
+	switch {
+	case errors.Contains(err, lwm2m.ErrUnauthorized):
+		return codes.Unauthorized
+	case errors.Contains(err, lwm2m.ErrNotFound):
+		return codes.NotFound
+	case errors.Contains(err, lwm2m.ErrMalformedEntity),
+		errors.Contains(err, errMalformedLifetime):
+		return codes.BadRequest
+	default:
+		return codes.InternalServerError
+	}
+}
+
+func parseQueries(opts message.Options) map[string]string {
+	ret := make(map[string]string)
+	queries, err := opts.Queries()
+	if err != nil {
+		return ret
+	}
+	for _, q := range queries {
+		kv := strings.SplitN(q, "=", 2)
+		if len(kv) == 2 {
+			ret[kv[0]] = kv[1]
+		}
+	}
+	return ret
+}
+
+// decodeRegistration decodes the registration or registration update
+// request. Objects are given in the CoRE Link Format, e.g.
+// </1/0>,</3/0>,</3303/0>;ver=1.1
+func decodeRegistration(m *mux.Message, queries map[string]string, register bool) (lwm2m.Registration, error) {
+	r := lwm2m.Registration{
+		Endpoint: queries[endpointQuery],
+	}
+
+	if lt, ok := queries[lifetimeQuery]; ok {
+		sec, err := strconv.ParseUint(lt, 10, 32)
+		if err != nil {
+			return lwm2m.Registration{}, errMalformedLifetime
+		}
+		r.Lifetime = time.Duration(sec) * time.Second
+	}
+	if register && r.Lifetime == 0 {
+		r.Lifetime = defLifetime
+	}
+
+	if m.Body == nil {
+		return r, nil
+	}
+	body, err := ioutil.ReadAll(m.Body)
+	if err != nil || len(body) == 0 {
+		return r, nil
+	}
+
+	r.Objects = []string{}
+	for _, link := range strings.Split(string(body), ",") {
+		link = strings.TrimSpace(strings.SplitN(link, ";", 2)[0])
+		link = strings.TrimSuffix(strings.TrimPrefix(link, "<"), ">")
+		// Security and Server objects are managed by the bootstrap and
+		// the root path only carries the link attributes.
+		parts := strings.Split(strings.Trim(link, "/"), "/")
+		if parts[0] == "" || parts[0] == "0" || parts[0] == "1" {
+			continue
+		}
+		r.Objects = append(r.Objects, link)
+	}
+
+	return r, nil
+}
+
+var _ lwm2m.Client = (*client)(nil)
+
+type client struct {
+	cc mux.Client
+}
+
+func newClient(cc mux.Client) lwm2m.Client {
+	return client{cc: cc}
+}
+
+func (c client) Observe(ctx context.Context, path string, h lwm2m.NotificationHandler) error {
+	_, err := c.cc.Observe(ctx, path, func(n *message.Message) {
+		if n.Body == nil {
+			return
+		}
+		payload, err := ioutil.ReadAll(n.Body)
+		if err != nil {
+			return
+		}
+		var ct string
+		if cf, err := n.Options.ContentFormat(); err == nil {
+			ct = contentTypes[cf]
+		}
+		h(ct, payload)
+	})
+	return err
+}
+
+func (c client) Write(ctx context.Context, path, contentType string, payload []byte) error {
+	res, err := c.cc.Put(ctx, path, mediaType(contentType), bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	return checkCode(res, codes.Changed)
+}
+
+func (c client) Delete(ctx context.Context, path string) error {
+	res, err := c.cc.Delete(ctx, path)
+	if err != nil {
+		return err
+	}
+	return checkCode(res, codes.Deleted)
+}
+
+func (c client) FinishBootstrap(ctx context.Context) error {
+	res, err := c.cc.Post(ctx, bootstrapPath, message.TextPlain, bytes.NewReader(nil))
+	if err != nil {
+		return err
+	}
+	return checkCode(res, codes.Changed)
+}
+
+func (c client) Done() <-chan struct{} {
+	return c.cc.Context().Done()
+}
+
+func checkCode(res *message.Message, expected codes.Code) error {
+	if res.Code != expected {
+		return errors.Wrap(errUnexpectedCode, errors.New(res.Code.String()))
+	}
+	return nil
+}
+
+func mediaType(contentType string) message.MediaType {
+	for mt, ct := range contentTypes {
+		if ct == contentType {
+			return mt
+		}
+	}
+	return message.TextPlain
+}
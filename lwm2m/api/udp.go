@@ -0,0 +1,173 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pion/transport/packetio"
+)
+
+const (
+	maxDatagramSize = 8192
+	maxBufferSize   = 1024 * 1024
+	acceptBacklog   = 128
+)
+
+var errListenerClosed = errors.New("udp listener closed")
+
+// udpListener demultiplexes the datagrams received on the UDP socket to the
+// connections of the remote addresses, so the connections are handled
+// independently of each other.
+type udpListener struct {
+	pc     *net.UDPConn
+	accept chan *udpConn
+	done   chan struct{}
+	once   sync.Once
+	mu     sync.Mutex
+	conns  map[string]*udpConn
+}
+
+func listenUDP(network string, addr *net.UDPAddr) (*udpListener, error) {
+	pc, err := net.ListenUDP(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	l := &udpListener{
+		pc:     pc,
+		accept: make(chan *udpConn, acceptBacklog),
+		done:   make(chan struct{}),
+		conns:  make(map[string]*udpConn),
+	}
+	go l.read()
+
+	return l, nil
+}
+
+// Accept returns the connection of the next new remote address.
+func (l *udpListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.accept:
+		return c, nil
+	case <-l.done:
+		return nil, errListenerClosed
+	}
+}
+
+// Close closes the socket and all of its connections.
+func (l *udpListener) Close() error {
+	var err error
+	l.once.Do(func() {
+		close(l.done)
+		err = l.pc.Close()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		for addr, c := range l.conns {
+			c.buf.Close()
+			delete(l.conns, addr)
+		}
+	})
+	return err
+}
+
+// Addr returns the address of the socket.
+func (l *udpListener) Addr() net.Addr {
+	return l.pc.LocalAddr()
+}
+
+func (l *udpListener) read() {
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, raddr, err := l.pc.ReadFrom(buf)
+		if err != nil {
+			l.Close()
+			return
+		}
+		if c := l.conn(raddr); c != nil {
+			// The datagrams exceeding the buffer are dropped.
+			c.buf.Write(buf[:n])
+		}
+	}
+}
+
+// conn returns the connection of the remote address, creating it if it
+// doesn't exist. The datagrams of the new remote addresses are dropped
+// while the accept backlog is full.
+func (l *udpListener) conn(raddr net.Addr) *udpConn {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if c, ok := l.conns[raddr.String()]; ok {
+		return c
+	}
+	c := &udpConn{
+		l:     l,
+		raddr: raddr,
+		buf:   packetio.NewBuffer(),
+	}
+	c.buf.SetLimitSize(maxBufferSize)
+	select {
+	case l.accept <- c:
+		l.conns[raddr.String()] = c
+		return c
+	default:
+		return nil
+	}
+}
+
+func (l *udpListener) remove(c *udpConn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conns[c.raddr.String()] == c {
+		delete(l.conns, c.raddr.String())
+	}
+}
+
+var _ net.Conn = (*udpConn)(nil)
+
+// udpConn is the connection of the remote address, reading the datagrams
+// received from the address and writing to the shared socket.
+type udpConn struct {
+	l     *udpListener
+	raddr net.Addr
+	buf   *packetio.Buffer
+}
+
+func (c *udpConn) Read(b []byte) (int, error) {
+	return c.buf.Read(b)
+}
+
+func (c *udpConn) Write(b []byte) (int, error) {
+	return c.l.pc.WriteTo(b, c.raddr)
+}
+
+func (c *udpConn) Close() error {
+	c.l.remove(c)
+	return c.buf.Close()
+}
+
+func (c *udpConn) LocalAddr() net.Addr {
+	return c.l.pc.LocalAddr()
+}
+
+func (c *udpConn) RemoteAddr() net.Addr {
+	return c.raddr
+}
+
+func (c *udpConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+func (c *udpConn) SetReadDeadline(t time.Time) error {
+	return c.buf.SetReadDeadline(t)
+}
+
+func (c *udpConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/lwm2m"
+)
+
+// Write represents the write request sent to the client.
+type Write struct {
+	Path        string
+	ContentType string
+	Payload     []byte
+}
+
+// Client is the mock LwM2M client recording the requests it receives.
+type Client struct {
+	mu           sync.Mutex
+	done         chan struct{}
+	observers    map[string]lwm2m.NotificationHandler
+	writes       []Write
+	deletes      []string
+	bootstrapped bool
+}
+
+var _ lwm2m.Client = (*Client)(nil)
+
+// NewClient returns mock LwM2M client.
+func NewClient() *Client {
+	return &Client{
+		done:      make(chan struct{}),
+		observers: make(map[string]lwm2m.NotificationHandler),
+	}
+}
+
+// Observe records the observed path.
+func (c *Client) Observe(_ context.Context, path string, h lwm2m.NotificationHandler) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.observers[path] = h
+	return nil
+}
+
+// Write records the write request.
+func (c *Client) Write(_ context.Context, path, contentType string, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writes = append(c.writes, Write{Path: path, ContentType: contentType, Payload: payload})
+	return nil
+}
+
+// Delete records the deleted path.
+func (c *Client) Delete(_ context.Context, path string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deletes = append(c.deletes, path)
+	return nil
+}
+
+// FinishBootstrap marks the client as bootstrapped.
+func (c *Client) FinishBootstrap(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.bootstrapped = true
+	return nil
+}
+
+// Done returns the channel closed by Close.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close simulates the closed connection.
+func (c *Client) Close() {
+	close(c.done)
+}
+
+// Notify sends the notification of the observed path. It returns false if
+// the path is not observed.
+func (c *Client) Notify(path, contentType string, payload []byte) bool {
+	c.mu.Lock()
+	h, ok := c.observers[path]
+	c.mu.Unlock()
+
+	if ok {
+		h(contentType, payload)
+	}
+	return ok
+}
+
+// Writes returns the write requests received so far.
+func (c *Client) Writes() []Write {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]Write{}, c.writes...)
+}
+
+// Deletes returns the deleted paths.
+func (c *Client) Deletes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string{}, c.deletes...)
+}
+
+// Bootstrapped returns whether the bootstrap was finished.
+func (c *Client) Bootstrapped() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.bootstrapped
+}
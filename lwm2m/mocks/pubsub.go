@@ -0,0 +1,82 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+var _ messaging.PubSub = (*pubSubMock)(nil)
+
+// PubSub is the in-memory message broker delivering the published messages
+// to the subscribers and recording them.
+type PubSub interface {
+	messaging.PubSub
+
+	// Published returns the messages published so far.
+	Published() []messaging.Message
+}
+
+type pubSubMock struct {
+	mu        sync.Mutex
+	handlers  map[string]messaging.MessageHandler
+	published []messaging.Message
+}
+
+// NewPubSub returns mock message broker.
+func NewPubSub() PubSub {
+	return &pubSubMock{
+		handlers: make(map[string]messaging.MessageHandler),
+	}
+}
+
+func (ps *pubSubMock) Publish(topic string, msg messaging.Message) error {
+	ps.mu.Lock()
+	ps.published = append(ps.published, msg)
+	subject := fmt.Sprintf("channels.%s", topic)
+	if msg.Subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
+	}
+	var handlers []messaging.MessageHandler
+	for t, h := range ps.handlers {
+		if strings.HasPrefix(subject, strings.TrimSuffix(t, ">")) {
+			handlers = append(handlers, h)
+		}
+	}
+	ps.mu.Unlock()
+
+	for _, h := range handlers {
+		if err := h(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps *pubSubMock) Subscribe(topic string, handler messaging.MessageHandler) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	ps.handlers[topic] = handler
+	return nil
+}
+
+func (ps *pubSubMock) Unsubscribe(topic string) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	delete(ps.handlers, topic)
+	return nil
+}
+
+func (ps *pubSubMock) Published() []messaging.Message {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	return append([]messaging.Message{}, ps.published...)
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/lwm2m"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ lwm2m.Things = (*thingsMock)(nil)
+
+type thingsMock struct {
+	things map[string]lwm2m.Thing
+}
+
+// NewThings returns mock implementation of the things, identified by their
+// keys.
+func NewThings(data map[string]lwm2m.Thing) lwm2m.Things {
+	return thingsMock{data}
+}
+
+func (tm thingsMock) Identify(_ context.Context, key string) (lwm2m.Thing, error) {
+	th, ok := tm.things[key]
+	if !ok {
+		return lwm2m.Thing{}, things.ErrUnauthorizedAccess
+	}
+	return th, nil
+}
+
+func (tm thingsMock) Key(_ context.Context, id string) (string, error) {
+	for key, th := range tm.things {
+		if th.ID == id {
+			return key, nil
+		}
+	}
+	return "", things.ErrNotFound
+}
@@ -0,0 +1,95 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lwm2m
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
+)
+
+const (
+	metadataKey = "lwm2m"
+	channelKey  = "channel"
+)
+
+// ErrNotConnected indicates that the thing is not connected to any channel.
+var ErrNotConnected = errors.New("thing not connected to any channel")
+
+// Thing represents the thing the LwM2M client is authenticated as.
+type Thing struct {
+	ID      string
+	Channel string
+}
+
+// Things specifies the API used to resolve the things of the LwM2M clients.
+type Things interface {
+	// Identify returns the thing identified by the key.
+	Identify(ctx context.Context, key string) (Thing, error)
+
+	// Key returns the key of the thing.
+	Key(ctx context.Context, id string) (string, error)
+}
+
+var _ Things = (*things)(nil)
+
+type things struct {
+	auth  mainflux.ThingsServiceClient
+	sdk   mfsdk.SDK
+	token string
+}
+
+// NewThings returns Things backed by the things service. The token is the
+// user token or API key of the owner of the things, used to retrieve the
+// thing keys and channels. The channel the client is published to is set in
+// the thing metadata, e.g. {"lwm2m": {"channel": "<channel_id>"}}, falling
+// back to the first channel the thing is connected to.
+func NewThings(auth mainflux.ThingsServiceClient, sdk mfsdk.SDK, token string) Things {
+	return things{
+		auth:  auth,
+		sdk:   sdk,
+		token: token,
+	}
+}
+
+func (t things) Identify(ctx context.Context, key string) (Thing, error) {
+	id, err := t.auth.Identify(ctx, &mainflux.Token{Value: key})
+	if err != nil {
+		return Thing{}, err
+	}
+
+	th, err := t.sdk.Thing(id.GetValue(), t.token)
+	if err != nil {
+		return Thing{}, err
+	}
+
+	if md, ok := th.Metadata[metadataKey].(map[string]interface{}); ok {
+		if ch, ok := md[channelKey].(string); ok && ch != "" {
+			if _, err := t.auth.CanAccessByID(ctx, &mainflux.AccessByIDReq{ThingID: th.ID, ChanID: ch}); err != nil {
+				return Thing{}, err
+			}
+			return Thing{ID: th.ID, Channel: ch}, nil
+		}
+	}
+
+	page, err := t.sdk.ChannelsByThing(t.token, th.ID, 0, 1, false)
+	if err != nil {
+		return Thing{}, err
+	}
+	if len(page.Channels) == 0 {
+		return Thing{}, ErrNotConnected
+	}
+
+	return Thing{ID: th.ID, Channel: page.Channels[0].ID}, nil
+}
+
+func (t things) Key(ctx context.Context, id string) (string, error) {
+	th, err := t.sdk.Thing(id, t.token)
+	if err != nil {
+		return "", err
+	}
+	return th.Key, nil
+}
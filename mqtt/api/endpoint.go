@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/mainflux/mainflux/mqtt"
+)
+
+func listClientsEndpoint(svc mqtt.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listClientsReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		clients, err := svc.ListClients(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := listClientsRes{
+			Total:   len(clients),
+			Clients: []clientRes{},
+		}
+		for _, c := range clients {
+			res.Clients = append(res.Clients, clientRes{
+				ID:             c.ID,
+				ThingID:        c.ThingID,
+				ConnectedAt:    c.ConnectedAt,
+				Duration:       c.Duration().Truncate(time.Second).String(),
+				Published:      c.Published,
+				PublishedBytes: c.PublishedBytes,
+				Subscribed:     c.Subscribed,
+			})
+		}
+
+		return res, nil
+	}
+}
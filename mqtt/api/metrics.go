@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/mqtt"
+	"github.com/mainflux/mproxy/pkg/session"
+)
+
+var (
+	_ mqtt.Service        = (*metricsMiddleware)(nil)
+	_ mqtt.ClientRegistry = (*clientsMetricsMiddleware)(nil)
+)
+
+type metricsMiddleware struct {
+	counter metrics.Counter
+	latency metrics.Histogram
+	svc     mqtt.Service
+}
+
+// MetricsMiddleware instruments core service by tracking request count and latency.
+func MetricsMiddleware(svc mqtt.Service, counter metrics.Counter, latency metrics.Histogram) mqtt.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) ListClients(ctx context.Context, token string) ([]mqtt.Client, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_clients").Add(1)
+		ms.latency.With("method", "list_clients").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListClients(ctx, token)
+}
+
+// ClientsMetrics holds the metrics of the clients connected to the adapter.
+// The metrics are labeled by the operation only, since labeling them by the
+// client or the thing would make their cardinality unbounded.
+type ClientsMetrics struct {
+	// Messages counts the published messages and the subscribed topics.
+	Messages metrics.Counter
+
+	// Bytes counts the size of the published payloads.
+	Bytes metrics.Counter
+
+	// Connected tracks the number of the connected clients.
+	Connected metrics.Gauge
+
+	// Duration observes the connection duration of the disconnected
+	// clients, in seconds.
+	Duration metrics.Histogram
+}
+
+type clientsMetricsMiddleware struct {
+	metrics ClientsMetrics
+	clients mqtt.ClientRegistry
+}
+
+// ClientsMetricsMiddleware instruments the client registry by tracking the
+// clients' traffic and connections.
+func ClientsMetricsMiddleware(clients mqtt.ClientRegistry, metrics ClientsMetrics) mqtt.ClientRegistry {
+	return &clientsMetricsMiddleware{
+		metrics: metrics,
+		clients: clients,
+	}
+}
+
+func (cm *clientsMetricsMiddleware) Connect(c *session.Client) {
+	cm.metrics.Connected.Add(1)
+	cm.clients.Connect(c)
+}
+
+func (cm *clientsMetricsMiddleware) Publish(c *session.Client, bytes int) {
+	cm.metrics.Messages.With("operation", "publish").Add(1)
+	cm.metrics.Bytes.With("operation", "publish").Add(float64(bytes))
+	cm.clients.Publish(c, bytes)
+}
+
+func (cm *clientsMetricsMiddleware) Subscribe(c *session.Client, topics int) {
+	cm.metrics.Messages.With("operation", "subscribe").Add(float64(topics))
+	cm.clients.Subscribe(c, topics)
+}
+
+func (cm *clientsMetricsMiddleware) Disconnect(c *session.Client) (mqtt.Client, bool) {
+	cl, ok := cm.clients.Disconnect(c)
+	if ok {
+		cm.metrics.Connected.Add(-1)
+		cm.metrics.Duration.Observe(cl.Duration().Seconds())
+	}
+
+	return cl, ok
+}
+
+func (cm *clientsMetricsMiddleware) RetrieveAll() []mqtt.Client {
+	return cm.clients.RetrieveAll()
+}
@@ -0,0 +1,18 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/mqtt"
+
+type listClientsReq struct {
+	token string
+}
+
+func (req listClientsReq) validate() error {
+	if req.token == "" {
+		return mqtt.ErrUnauthorizedAccess
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux"
+)
+
+var _ mainflux.Response = (*listClientsRes)(nil)
+
+type clientRes struct {
+	ID             string    `json:"id"`
+	ThingID        string    `json:"thing_id"`
+	ConnectedAt    time.Time `json:"connected_at"`
+	Duration       string    `json:"duration"`
+	Published      uint64    `json:"published"`
+	PublishedBytes uint64    `json:"published_bytes"`
+	Subscribed     uint64    `json:"subscribed"`
+}
+
+type listClientsRes struct {
+	Total   int         `json:"total"`
+	Clients []clientRes `json:"clients"`
+}
+
+func (res listClientsRes) Code() int {
+	return http.StatusOK
+}
+
+func (res listClientsRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res listClientsRes) Empty() bool {
+	return false
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
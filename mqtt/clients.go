@@ -0,0 +1,129 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mproxy/pkg/session"
+)
+
+// Client represents the client connected to the adapter.
+type Client struct {
+	ID          string
+	ThingID     string
+	ConnectedAt time.Time
+
+	// Published is the number of messages published by the client and
+	// PublishedBytes is the total size of their payloads.
+	Published      uint64
+	PublishedBytes uint64
+
+	// Subscribed is the number of topics the client subscribed to.
+	Subscribed uint64
+}
+
+// Duration returns the time the client has been connected for.
+func (c Client) Duration() time.Duration {
+	return time.Since(c.ConnectedAt)
+}
+
+// ClientRegistry tracks the clients connected to the adapter. The clients
+// are tracked per session, so the clients reconnecting using the same ID
+// don't interfere with each other.
+type ClientRegistry interface {
+	// Connect registers the connected client.
+	Connect(c *session.Client)
+
+	// Publish records the message of the given size published by the client.
+	Publish(c *session.Client, bytes int)
+
+	// Subscribe records the subscription of the client to the topics.
+	Subscribe(c *session.Client, topics int)
+
+	// Disconnect unregisters the client, returning its final state. The
+	// flag is false if the client wasn't registered.
+	Disconnect(c *session.Client) (Client, bool)
+
+	// RetrieveAll retrieves the currently connected clients, ordered by
+	// the connection time and the ID.
+	RetrieveAll() []Client
+}
+
+var _ ClientRegistry = (*clientRegistry)(nil)
+
+type clientRegistry struct {
+	mu      sync.Mutex
+	clients map[*session.Client]*Client
+}
+
+// NewClientRegistry instantiates in-memory client registry.
+func NewClientRegistry() ClientRegistry {
+	return &clientRegistry{
+		clients: make(map[*session.Client]*Client),
+	}
+}
+
+func (cr *clientRegistry) Connect(c *session.Client) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	cr.clients[c] = &Client{
+		ID:          c.ID,
+		ThingID:     c.Username,
+		ConnectedAt: time.Now(),
+	}
+}
+
+func (cr *clientRegistry) Publish(c *session.Client, bytes int) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if cl, ok := cr.clients[c]; ok {
+		cl.Published++
+		cl.PublishedBytes += uint64(bytes)
+	}
+}
+
+func (cr *clientRegistry) Subscribe(c *session.Client, topics int) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if cl, ok := cr.clients[c]; ok {
+		cl.Subscribed += uint64(topics)
+	}
+}
+
+func (cr *clientRegistry) Disconnect(c *session.Client) (Client, bool) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	cl, ok := cr.clients[c]
+	if !ok {
+		return Client{}, false
+	}
+	delete(cr.clients, c)
+
+	return *cl, true
+}
+
+func (cr *clientRegistry) RetrieveAll() []Client {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	clients := make([]Client, 0, len(cr.clients))
+	for _, cl := range cr.clients {
+		clients = append(clients, *cl)
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		if clients[i].ConnectedAt.Equal(clients[j].ConnectedAt) {
+			return clients[i].ID < clients[j].ID
+		}
+		return clients[i].ConnectedAt.Before(clients[j].ConnectedAt)
+	})
+
+	return clients
+}
@@ -11,10 +11,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mainflux/mainflux/internal/payload"
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/mqtt/redis"
 	"github.com/mainflux/mainflux/pkg/auth"
 	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mproxy/pkg/session"
 )
 
@@ -40,16 +42,21 @@ type handler struct {
 	auth       auth.Client
 	logger     logger.Logger
 	es         redis.EventStore
+	clients    ClientRegistry
+	payloads   payload.Checker
 }
 
-// NewHandler creates new Handler entity
+// NewHandler creates new Handler entity. The messages violating the payload
+// policies are rejected before they are forwarded to the MQTT broker.
 func NewHandler(publishers []messaging.Publisher, es redis.EventStore,
-	logger logger.Logger, auth auth.Client) session.Handler {
+	logger logger.Logger, auth auth.Client, clients ClientRegistry, payloads payload.Checker) session.Handler {
 	return &handler{
 		es:         es,
 		logger:     logger,
 		publishers: publishers,
 		auth:       auth,
+		clients:    clients,
+		payloads:   payloads,
 	}
 }
 
@@ -86,7 +93,18 @@ func (h *handler) AuthPublish(c *session.Client, topic *string, payload *[]byte)
 		return errNilTopicPub
 	}
 
-	return h.authAccess(c.Username, *topic)
+	if err := h.authAccess(c.Username, *topic, things.PublishOp); err != nil {
+		return err
+	}
+
+	msg := messaging.Message{
+		Protocol: protocol,
+		Channel:  channelRegExp.FindStringSubmatch(*topic)[1],
+	}
+	if payload != nil {
+		msg.Payload = *payload
+	}
+	return h.payloads.Check(msg)
 }
 
 // AuthSubscribe is called on device publish,
@@ -100,7 +118,7 @@ func (h *handler) AuthSubscribe(c *session.Client, topics *[]string) error {
 	}
 
 	for _, v := range *topics {
-		if err := h.authAccess(c.Username, v); err != nil {
+		if err := h.authAccess(c.Username, v, things.SubscribeOp); err != nil {
 			return err
 		}
 
@@ -116,6 +134,7 @@ func (h *handler) Connect(c *session.Client) {
 		return
 	}
 	h.logger.Info("Connect - client with ID: " + c.ID)
+	h.clients.Connect(c)
 }
 
 // Publish - after client successfully published
@@ -125,6 +144,7 @@ func (h *handler) Publish(c *session.Client, topic *string, payload *[]byte) {
 		return
 	}
 	h.logger.Info("Publish - client ID " + c.ID + " to the topic: " + *topic)
+	h.clients.Publish(c, len(*payload))
 	// Topics are in the format:
 	// channels/<channel_id>/messages/<subtopic>/.../ct/<content_type>
 
@@ -166,6 +186,7 @@ func (h *handler) Subscribe(c *session.Client, topics *[]string) {
 		return
 	}
 	h.logger.Info("Subscribe - client ID: " + c.ID + ", to topics: " + strings.Join(*topics, ","))
+	h.clients.Subscribe(c, len(*topics))
 }
 
 // Unsubscribe - after client unsubscribed
@@ -184,12 +205,13 @@ func (h *handler) Disconnect(c *session.Client) {
 		return
 	}
 	h.logger.Info("Disconnect - Client with ID: " + c.ID + " and username " + c.Username + " disconnected")
+	h.clients.Disconnect(c)
 	if err := h.es.Disconnect(c.Username); err != nil {
 		h.logger.Warn("Failed to publish disconnect event: " + err.Error())
 	}
 }
 
-func (h *handler) authAccess(username string, topic string) error {
+func (h *handler) authAccess(username, topic, op string) error {
 	// Topics are in the format:
 	// channels/<channel_id>/messages/<subtopic>/.../ct/<content_type>
 	if !channelRegExp.Match([]byte(topic)) {
@@ -203,7 +225,7 @@ func (h *handler) authAccess(username string, topic string) error {
 	}
 
 	chanID := channelParts[1]
-	return h.auth.Authorize(context.Background(), chanID, username)
+	return h.auth.Authorize(context.Background(), chanID, username, op)
 }
 
 func parseSubtopic(subtopic string) (string, error) {
@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	memberRelation    = "member"
+	authoritiesObject = "authorities"
+)
+
+var (
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrAuthorization indicates that the user isn't allowed to perform
+	// the operation.
+	ErrAuthorization = errors.New("failed to perform authorization over the entity")
+)
+
+// Service specifies an API for inspecting the adapter.
+type Service interface {
+	// ListClients retrieves the clients currently connected to the
+	// adapter. Only the admin is allowed to list the clients.
+	ListClients(ctx context.Context, token string) ([]Client, error)
+}
+
+var _ Service = (*adapterService)(nil)
+
+type adapterService struct {
+	auth    mainflux.AuthServiceClient
+	clients ClientRegistry
+}
+
+// NewService instantiates the MQTT adapter service implementation.
+func NewService(auth mainflux.AuthServiceClient, clients ClientRegistry) Service {
+	return &adapterService{
+		auth:    auth,
+		clients: clients,
+	}
+}
+
+func (as *adapterService) ListClients(ctx context.Context, token string) ([]Client, error) {
+	res, err := as.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	req := &mainflux.AuthorizeReq{Sub: res.GetId(), Obj: authoritiesObject, Act: memberRelation}
+	ar, err := as.auth.Authorize(ctx, req)
+	if err != nil {
+		return nil, errors.Wrap(ErrAuthorization, err)
+	}
+	if !ar.GetAuthorized() {
+		return nil, ErrAuthorization
+	}
+
+	return as.clients.RetrieveAll(), nil
+}
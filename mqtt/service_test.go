@@ -0,0 +1,90 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mqtt_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/mqtt"
+	"github.com/mainflux/mainflux/pkg/errors"
+	thmocks "github.com/mainflux/mainflux/things/mocks"
+	"github.com/mainflux/mproxy/pkg/session"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	token      = "token"
+	adminToken = "admin-token"
+	wrongToken = "wrong-token"
+	userID     = "user"
+	adminID    = "admin"
+)
+
+func newService(clients mqtt.ClientRegistry) mqtt.Service {
+	users := map[string]string{token: userID, adminToken: adminID}
+	policies := map[string][]thmocks.MockSubjectSet{
+		adminID: {{Object: "authorities", Relation: "member"}},
+	}
+	auth := thmocks.NewAuthService(users, policies)
+	return mqtt.NewService(auth, clients)
+}
+
+func TestListClients(t *testing.T) {
+	clients := mqtt.NewClientRegistry()
+	svc := newService(clients)
+
+	c1 := &session.Client{ID: "client-1", Username: "thing-1"}
+	c2 := &session.Client{ID: "client-2", Username: "thing-2"}
+	gone := &session.Client{ID: "client-3", Username: "thing-3"}
+	for _, c := range []*session.Client{c1, c2, gone} {
+		clients.Connect(c)
+	}
+	clients.Publish(c1, 10)
+	clients.Publish(c1, 5)
+	clients.Subscribe(c2, 2)
+	clients.Disconnect(gone)
+
+	// Reconnecting using the same client ID must not affect the tracked one.
+	stale := &session.Client{ID: "client-1", Username: "thing-1"}
+	_, ok := clients.Disconnect(stale)
+	assert.False(t, ok, "disconnecting untracked session: expected not found")
+
+	cases := []struct {
+		desc    string
+		token   string
+		clients []mqtt.Client
+		err     error
+	}{
+		{
+			desc:  "list clients as admin",
+			token: adminToken,
+			clients: []mqtt.Client{
+				{ID: "client-1", ThingID: "thing-1", Published: 2, PublishedBytes: 15},
+				{ID: "client-2", ThingID: "thing-2", Subscribed: 2},
+			},
+		},
+		{
+			desc:  "list clients as non-admin",
+			token: token,
+			err:   mqtt.ErrAuthorization,
+		},
+		{
+			desc:  "list clients with invalid token",
+			token: wrongToken,
+			err:   mqtt.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		cls, err := svc.ListClients(context.Background(), tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		for i := range cls {
+			assert.False(t, cls[i].ConnectedAt.IsZero(), fmt.Sprintf("%s: expected connection time to be set", tc.desc))
+			cls[i].ConnectedAt = tc.clients[i].ConnectedAt
+		}
+		assert.Equal(t, tc.clients, cls, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.clients, cls))
+	}
+}
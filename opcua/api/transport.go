@@ -100,11 +100,14 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 
+	if errors.Contains(err, errors.ErrInvalidQueryParams) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	switch err {
 	case opcua.ErrMalformedEntity:
 		w.WriteHeader(http.StatusBadRequest)
-	case errors.ErrInvalidQueryParams:
-		w.WriteHeader(http.StatusBadRequest)
 	default:
 		w.WriteHeader(http.StatusInternalServerError)
 	}
@@ -8,17 +8,19 @@ import (
 
 	"github.com/go-redis/redis/v8"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/things"
 )
 
 // Client represents Auth cache.
 type Client interface {
-	Authorize(ctx context.Context, chanID, thingID string) error
+	Authorize(ctx context.Context, chanID, thingID, op string) error
 	Identify(ctx context.Context, thingKey string) (string, error)
 }
 
 const (
-	chanPrefix = "channel"
-	keyPrefix  = "thing_key"
+	chanPrefix  = "channel"
+	rolesSuffix = "roles"
+	keyPrefix   = "thing_key"
 )
 
 type client struct {
@@ -51,14 +53,22 @@ func (c client) Identify(ctx context.Context, thingKey string) (string, error) {
 	return thingID, nil
 }
 
-func (c client) Authorize(ctx context.Context, chanID, thingID string) error {
-	if c.redisClient.SIsMember(ctx, chanPrefix+":"+chanID, thingID).Val() {
+func (c client) Authorize(ctx context.Context, chanID, thingID, op string) error {
+	cid := chanPrefix + ":" + chanID
+	if c.redisClient.SIsMember(ctx, cid, thingID).Val() {
+		conn := things.ConnectionMetadata{
+			Role: c.redisClient.HGet(ctx, cid+":"+rolesSuffix, thingID).Val(),
+		}
+		if !conn.Allows(op) {
+			return things.ErrOperationNotAllowed
+		}
 		return nil
 	}
 
 	ar := &mainflux.AccessByIDReq{
-		ThingID: thingID,
-		ChanID:  chanID,
+		ThingID:   thingID,
+		ChanID:    chanID,
+		Operation: op,
 	}
 	_, err := c.thingsClient.CanAccessByID(ctx, ar)
 	return err
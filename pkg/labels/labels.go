@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package labels provides the key=value labels attached to the entities and
+// the selectors used to query the entities by their labels.
+package labels
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// MaxSize is the maximum length of the label key and value.
+const MaxSize = 63
+
+var (
+	// ErrInvalidLabel indicates the label key or value of the invalid format.
+	ErrInvalidLabel = errors.New("invalid label")
+
+	// ErrInvalidSelector indicates the selector of the invalid format.
+	ErrInvalidSelector = errors.New("invalid label selector")
+
+	// The key and the non-empty value start and end with the alphanumeric
+	// character, with dashes, underscores and dots in between.
+	labelRegexp = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`)
+)
+
+// Labels are the key=value pairs identifying the entity, e.g. env=prod.
+// Unlike the metadata, the labels are flat strings, so they can be
+// predictably queried using the selectors.
+type Labels map[string]string
+
+// Validate returns an error if any label key or value is of the invalid
+// format. The value may be empty.
+func (l Labels) Validate() error {
+	for k, v := range l {
+		if err := validateKey(k); err != nil {
+			return err
+		}
+		if err := validateValue(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateKey(key string) error {
+	if len(key) > MaxSize || !labelRegexp.MatchString(key) {
+		return errors.Wrap(ErrInvalidLabel, fmt.Errorf("invalid key '%s'", key))
+	}
+	return nil
+}
+
+func validateValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > MaxSize || !labelRegexp.MatchString(value) {
+		return errors.Wrap(ErrInvalidLabel, fmt.Errorf("invalid value '%s'", value))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package labels_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		desc   string
+		labels labels.Labels
+		err    error
+	}{
+		{
+			desc:   "validate valid labels",
+			labels: labels.Labels{"env": "prod", "app.kubernetes.io": "gateway-1", "empty": ""},
+			err:    nil,
+		},
+		{
+			desc:   "validate empty labels",
+			labels: labels.Labels{},
+			err:    nil,
+		},
+		{
+			desc:   "validate labels with empty key",
+			labels: labels.Labels{"": "prod"},
+			err:    labels.ErrInvalidLabel,
+		},
+		{
+			desc:   "validate labels with invalid key",
+			labels: labels.Labels{"env=": "prod"},
+			err:    labels.ErrInvalidLabel,
+		},
+		{
+			desc:   "validate labels with too long key",
+			labels: labels.Labels{strings.Repeat("k", labels.MaxSize+1): "prod"},
+			err:    labels.ErrInvalidLabel,
+		},
+		{
+			desc:   "validate labels with invalid value",
+			labels: labels.Labels{"env": "-prod"},
+			err:    labels.ErrInvalidLabel,
+		},
+		{
+			desc:   "validate labels with too long value",
+			labels: labels.Labels{"env": strings.Repeat("v", labels.MaxSize+1)},
+			err:    labels.ErrInvalidLabel,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.labels.Validate()
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		desc     string
+		selector string
+		result   labels.Selector
+		err      error
+	}{
+		{
+			desc:     "parse empty selector",
+			selector: "",
+			result:   labels.Selector{},
+		},
+		{
+			desc:     "parse selector of all operators",
+			selector: "env=prod, region!=eu,tier==edge,gpu,!deprecated",
+			result: labels.Selector{
+				{Key: "env", Operator: labels.Equals, Value: "prod"},
+				{Key: "region", Operator: labels.NotEquals, Value: "eu"},
+				{Key: "tier", Operator: labels.Equals, Value: "edge"},
+				{Key: "gpu", Operator: labels.Exists},
+				{Key: "deprecated", Operator: labels.NotExists},
+			},
+		},
+		{
+			desc:     "parse selector with empty value",
+			selector: "env=",
+			result:   labels.Selector{{Key: "env", Operator: labels.Equals, Value: ""}},
+		},
+		{
+			desc:     "parse selector with empty requirement",
+			selector: "env=prod,",
+			err:      labels.ErrInvalidSelector,
+		},
+		{
+			desc:     "parse selector with missing key",
+			selector: "=prod",
+			err:      labels.ErrInvalidSelector,
+		},
+		{
+			desc:     "parse selector with invalid value",
+			selector: "env=prod=eu",
+			err:      labels.ErrInvalidSelector,
+		},
+		{
+			desc:     "parse selector with invalid operator",
+			selector: "env>prod",
+			err:      labels.ErrInvalidSelector,
+		},
+	}
+
+	for _, tc := range cases {
+		sel, err := labels.Parse(tc.selector)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.result, sel, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.result, sel))
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	l := labels.Labels{"env": "prod", "region": "us"}
+
+	cases := []struct {
+		desc     string
+		selector string
+		result   bool
+	}{
+		{
+			desc:     "match empty selector",
+			selector: "",
+			result:   true,
+		},
+		{
+			desc:     "match equal label",
+			selector: "env=prod",
+			result:   true,
+		},
+		{
+			desc:     "match different label",
+			selector: "env=dev",
+			result:   false,
+		},
+		{
+			desc:     "match not equal missing label",
+			selector: "tier!=edge",
+			result:   true,
+		},
+		{
+			desc:     "match not equal label",
+			selector: "region!=us",
+			result:   false,
+		},
+		{
+			desc:     "match existing label",
+			selector: "region",
+			result:   true,
+		},
+		{
+			desc:     "match missing label",
+			selector: "!region",
+			result:   false,
+		},
+		{
+			desc:     "match partially satisfied selector",
+			selector: "env=prod,region=eu",
+			result:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		sel, err := labels.Parse(tc.selector)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		res := sel.Matches(l)
+		assert.Equal(t, tc.result, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.result, res))
+	}
+}
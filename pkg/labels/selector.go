@@ -0,0 +1,158 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package labels
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// Operator is the operator of the selector requirement.
+type Operator string
+
+const (
+	// Equals requires the label to be set to the value.
+	Equals Operator = "="
+	// NotEquals requires the label to be either missing or set to the
+	// value other than the given one.
+	NotEquals Operator = "!="
+	// Exists requires the label to be set, regardless of its value.
+	Exists Operator = "exists"
+	// NotExists requires the label to be missing.
+	NotExists Operator = "!"
+)
+
+// Requirement is the single condition of the selector.
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Value    string
+}
+
+// Matches returns true if the labels satisfy the requirement.
+func (r Requirement) Matches(l Labels) bool {
+	v, ok := l[r.Key]
+	switch r.Operator {
+	case Equals:
+		return ok && v == r.Value
+	case NotEquals:
+		return !ok || v != r.Value
+	case Exists:
+		return ok
+	case NotExists:
+		return !ok
+	default:
+		return false
+	}
+}
+
+// String returns the requirement in the selector syntax.
+func (r Requirement) String() string {
+	switch r.Operator {
+	case Exists:
+		return r.Key
+	case NotExists:
+		return "!" + r.Key
+	default:
+		return r.Key + string(r.Operator) + r.Value
+	}
+}
+
+// Selector selects the entities whose labels satisfy all the requirements.
+// The empty selector selects all the entities.
+type Selector []Requirement
+
+// Parse parses the selector of the comma separated requirements, where
+// each requirement is one of:
+//
+//	key=value   the label is set to the value (key==value is the same)
+//	key!=value  the label is missing or set to the other value
+//	key         the label is set
+//	!key        the label is missing
+//
+// e.g. "env=prod,region!=eu,!deprecated". The empty string is parsed as
+// the empty selector.
+func Parse(selector string) (Selector, error) {
+	sel := Selector{}
+	if strings.TrimSpace(selector) == "" {
+		return sel, nil
+	}
+
+	for _, part := range strings.Split(selector, ",") {
+		r, err := parseRequirement(strings.TrimSpace(part))
+		if err != nil {
+			return Selector{}, errors.Wrap(ErrInvalidSelector, err)
+		}
+		sel = append(sel, r)
+	}
+
+	return sel, nil
+}
+
+func parseRequirement(part string) (Requirement, error) {
+	var r Requirement
+	switch {
+	case strings.Contains(part, "!="):
+		kv := strings.SplitN(part, "!=", 2)
+		r = Requirement{Key: kv[0], Operator: NotEquals, Value: kv[1]}
+	case strings.Contains(part, "=="):
+		kv := strings.SplitN(part, "==", 2)
+		r = Requirement{Key: kv[0], Operator: Equals, Value: kv[1]}
+	case strings.Contains(part, "="):
+		kv := strings.SplitN(part, "=", 2)
+		r = Requirement{Key: kv[0], Operator: Equals, Value: kv[1]}
+	case strings.HasPrefix(part, "!"):
+		r = Requirement{Key: strings.TrimPrefix(part, "!"), Operator: NotExists}
+	default:
+		r = Requirement{Key: part, Operator: Exists}
+	}
+
+	r.Key = strings.TrimSpace(r.Key)
+	r.Value = strings.TrimSpace(r.Value)
+	if err := validateKey(r.Key); err != nil {
+		return Requirement{}, fmt.Errorf("requirement '%s': %s", part, err)
+	}
+	if err := validateValue(r.Value); err != nil {
+		return Requirement{}, fmt.Errorf("requirement '%s': %s", part, err)
+	}
+
+	return r, nil
+}
+
+// Matches returns true if the labels satisfy all the selector requirements.
+func (s Selector) Matches(l Labels) bool {
+	for _, r := range s {
+		if !r.Matches(l) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the selector in the syntax accepted by Parse.
+func (s Selector) String() string {
+	parts := make([]string, len(s))
+	for i, r := range s {
+		parts[i] = r.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// MarshalText encodes the selector in the syntax accepted by Parse, so the
+// selector is encoded as the JSON string.
+func (s Selector) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText parses the selector encoded by MarshalText.
+func (s *Selector) UnmarshalText(text []byte) error {
+	sel, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*s = sel
+	return nil
+}
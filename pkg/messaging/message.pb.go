@@ -30,6 +30,10 @@ type Message struct {
 	Protocol             string   `protobuf:"bytes,4,opt,name=protocol,proto3" json:"protocol,omitempty"`
 	Payload              []byte   `protobuf:"bytes,5,opt,name=payload,proto3" json:"payload,omitempty"`
 	Created              int64    `protobuf:"varint,6,opt,name=created,proto3" json:"created,omitempty"`
+	ContentType          string   `protobuf:"bytes,7,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Adapter              string   `protobuf:"bytes,8,opt,name=adapter,proto3" json:"adapter,omitempty"`
+	TraceId              string   `protobuf:"bytes,9,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	Hops                 uint32   `protobuf:"varint,10,opt,name=hops,proto3" json:"hops,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -110,6 +114,34 @@ func (m *Message) GetCreated() int64 {
 	return 0
 }
 
+func (m *Message) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *Message) GetAdapter() string {
+	if m != nil {
+		return m.Adapter
+	}
+	return ""
+}
+
+func (m *Message) GetTraceId() string {
+	if m != nil {
+		return m.TraceId
+	}
+	return ""
+}
+
+func (m *Message) GetHops() uint32 {
+	if m != nil {
+		return m.Hops
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Message)(nil), "messaging.Message")
 }
@@ -117,19 +149,23 @@ func init() {
 func init() { proto.RegisterFile("pkg/messaging/message.proto", fileDescriptor_e5e29d24c44e4762) }
 
 var fileDescriptor_e5e29d24c44e4762 = []byte{
-	// 187 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x92, 0x2e, 0xc8, 0x4e, 0xd7,
-	0xcf, 0x4d, 0x2d, 0x2e, 0x4e, 0x4c, 0xcf, 0xcc, 0x83, 0xb1, 0x52, 0xf5, 0x0a, 0x8a, 0xf2, 0x4b,
-	0xf2, 0x85, 0x38, 0xe1, 0x12, 0x4a, 0x6b, 0x19, 0xb9, 0xd8, 0x7d, 0x21, 0x92, 0x42, 0x12, 0x5c,
-	0xec, 0xc9, 0x19, 0x89, 0x79, 0x79, 0xa9, 0x39, 0x12, 0x8c, 0x0a, 0x8c, 0x1a, 0x9c, 0x41, 0x30,
-	0xae, 0x90, 0x14, 0x17, 0x47, 0x71, 0x69, 0x52, 0x49, 0x7e, 0x41, 0x66, 0xb2, 0x04, 0x13, 0x58,
-	0x0a, 0xce, 0x17, 0x92, 0xe1, 0xe2, 0x2c, 0x28, 0x4d, 0xca, 0xc9, 0x2c, 0xce, 0x48, 0x2d, 0x92,
-	0x60, 0x06, 0x4b, 0x22, 0x04, 0x40, 0x3a, 0xc1, 0x76, 0x26, 0xe7, 0xe7, 0x48, 0xb0, 0x40, 0x74,
-	0xc2, 0xf8, 0x20, 0xfb, 0x0a, 0x12, 0x2b, 0x73, 0xf2, 0x13, 0x53, 0x24, 0x58, 0x15, 0x18, 0x35,
-	0x78, 0x82, 0x60, 0x5c, 0xb0, 0x4b, 0x8a, 0x52, 0x13, 0x4b, 0x52, 0x53, 0x24, 0xd8, 0x14, 0x18,
-	0x35, 0x98, 0x83, 0x60, 0x5c, 0x27, 0x81, 0x13, 0x8f, 0xe4, 0x18, 0x2f, 0x3c, 0x92, 0x63, 0x7c,
-	0xf0, 0x48, 0x8e, 0x71, 0xc6, 0x63, 0x39, 0x86, 0x24, 0x36, 0xb0, 0x79, 0xc6, 0x80, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0xa9, 0x1e, 0x4a, 0xea, 0xf2, 0x00, 0x00, 0x00,
+	// 251 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x44, 0x8f, 0xbd, 0x4e, 0xc3, 0x30,
+	0x10, 0xc7, 0x71, 0x5b, 0xda, 0xc4, 0x14, 0x09, 0x79, 0x3a, 0x3e, 0x14, 0x05, 0xa6, 0x4c, 0x30,
+	0xf0, 0x06, 0x6c, 0x0c, 0x2c, 0x11, 0x7b, 0xe5, 0xd8, 0xa7, 0x26, 0x22, 0xd8, 0x96, 0xed, 0x0e,
+	0x79, 0x13, 0x78, 0x23, 0x46, 0x1e, 0x01, 0x85, 0x17, 0x41, 0xbd, 0xd4, 0x65, 0xbb, 0xdf, 0xfd,
+	0x74, 0x1f, 0x7f, 0x7e, 0xed, 0xde, 0xb6, 0x0f, 0xef, 0x18, 0x82, 0xdc, 0x76, 0x26, 0x55, 0x78,
+	0xef, 0xbc, 0x8d, 0x56, 0xe4, 0x47, 0x71, 0xf7, 0x39, 0xe3, 0xab, 0x97, 0x49, 0x0a, 0xe0, 0x2b,
+	0xd5, 0x4a, 0x63, 0xb0, 0x07, 0x56, 0xb2, 0x2a, 0xaf, 0x13, 0x8a, 0x2b, 0x9e, 0x85, 0x5d, 0x13,
+	0xad, 0xeb, 0x14, 0xcc, 0x48, 0x1d, 0x59, 0xdc, 0xf0, 0xdc, 0xed, 0x9a, 0xbe, 0x0b, 0x2d, 0x7a,
+	0x98, 0x93, 0xfc, 0x6f, 0xec, 0x27, 0xe9, 0xa6, 0xb2, 0x3d, 0x2c, 0xa6, 0xc9, 0xc4, 0xfb, 0x7b,
+	0x4e, 0x0e, 0xbd, 0x95, 0x1a, 0x4e, 0x4b, 0x56, 0xad, 0xeb, 0x84, 0xf4, 0x89, 0x47, 0x19, 0x51,
+	0xc3, 0xb2, 0x64, 0xd5, 0xbc, 0x4e, 0x28, 0x6e, 0xf9, 0x5a, 0x59, 0x13, 0xd1, 0xc4, 0x4d, 0x1c,
+	0x1c, 0xc2, 0x8a, 0x76, 0x9e, 0x1d, 0x7a, 0xaf, 0x83, 0xa3, 0x18, 0x52, 0x4b, 0x17, 0xd1, 0x43,
+	0x36, 0xc5, 0x38, 0xa0, 0xb8, 0xe4, 0x59, 0xf4, 0x52, 0xe1, 0xa6, 0xd3, 0x90, 0x4f, 0x8a, 0xf8,
+	0x59, 0x0b, 0xc1, 0x17, 0xad, 0x75, 0x01, 0x78, 0xc9, 0xaa, 0xf3, 0x9a, 0xea, 0xa7, 0x8b, 0xaf,
+	0xb1, 0x60, 0xdf, 0x63, 0xc1, 0x7e, 0xc6, 0x82, 0x7d, 0xfc, 0x16, 0x27, 0xcd, 0x92, 0x7e, 0x7f,
+	0xfc, 0x1b, 0x00, 0x83, 0x7b, 0x4b, 0x01, 0x5e, 0x01, 0x00, 0x00,
 }
 
 func (m *Message) Marshal() (dAtA []byte, err error) {
@@ -156,6 +192,32 @@ func (m *Message) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Hops != 0 {
+		i = encodeVarintMessage(dAtA, i, uint64(m.Hops))
+		i--
+		dAtA[i] = 0x50
+	}
+	if len(m.TraceId) > 0 {
+		i -= len(m.TraceId)
+		copy(dAtA[i:], m.TraceId)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.TraceId)))
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Adapter) > 0 {
+		i -= len(m.Adapter)
+		copy(dAtA[i:], m.Adapter)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.Adapter)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if len(m.ContentType) > 0 {
+		i -= len(m.ContentType)
+		copy(dAtA[i:], m.ContentType)
+		i = encodeVarintMessage(dAtA, i, uint64(len(m.ContentType)))
+		i--
+		dAtA[i] = 0x3a
+	}
 	if m.Created != 0 {
 		i = encodeVarintMessage(dAtA, i, uint64(m.Created))
 		i--
@@ -239,6 +301,21 @@ func (m *Message) Size() (n int) {
 	if m.Created != 0 {
 		n += 1 + sovMessage(uint64(m.Created))
 	}
+	l = len(m.ContentType)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	l = len(m.Adapter)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	l = len(m.TraceId)
+	if l > 0 {
+		n += 1 + l + sovMessage(uint64(l))
+	}
+	if m.Hops != 0 {
+		n += 1 + sovMessage(uint64(m.Hops))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -461,6 +538,121 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ContentType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ContentType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Adapter", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Adapter = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceId", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthMessage
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthMessage
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TraceId = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hops", wireType)
+			}
+			m.Hops = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMessage
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Hops |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMessage(dAtA[iNdEx:])
@@ -0,0 +1,131 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"fmt"
+
+	log "github.com/mainflux/mainflux/logger"
+	broker "github.com/nats-io/nats.go"
+)
+
+// DefReconnectBufSize is the default number of bytes of the messages
+// published while the connection is down, kept until it's reestablished.
+const DefReconnectBufSize = broker.DefaultReconnectBufSize
+
+// State represents the state of the NATS connection.
+type State int
+
+const (
+	// Disconnected means that the connection is lost and that it's being
+	// reestablished. The messages aren't received in the meantime.
+	Disconnected State = iota
+	// Reconnected means that the connection is reestablished and that the
+	// subscriptions are resumed.
+	Reconnected
+	// Closed means that the connection is closed and won't be reestablished.
+	Closed
+)
+
+// String returns the state name.
+func (s State) String() string {
+	switch s {
+	case Disconnected:
+		return "disconnected"
+	case Reconnected:
+		return "reconnected"
+	case Closed:
+		return "closed"
+	default:
+		return "unknown"
+	}
+}
+
+// StateHandler is notified about the NATS connection state changes, so the
+// services can e.g. pause the consumption while the connection is down.
+type StateHandler func(state State)
+
+// Option configures the NATS connection.
+type Option func(*options)
+
+type options struct {
+	bufSize    int
+	handler    StateHandler
+	partitions int
+}
+
+func newOptions(opts ...Option) options {
+	o := options{bufSize: DefReconnectBufSize}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// ReconnectBufSize sets the number of bytes of the messages published while
+// the connection is down, kept until the connection is reestablished. Once
+// the buffer is full, publishing fails. Zero or negative size disables the
+// buffering, so publishing fails as soon as the connection is lost.
+func ReconnectBufSize(size int) Option {
+	return func(o *options) {
+		o.bufSize = size
+		if size <= 0 {
+			// NATS client treats zero as the default size.
+			o.bufSize = -1
+		}
+	}
+}
+
+// OnStateChange sets the handler notified about the connection state changes.
+func OnStateChange(h StateHandler) Option {
+	return func(o *options) {
+		o.handler = h
+	}
+}
+
+// Partitions spreads the published messages over the given number of the
+// subject partitions, by the hash of the channel ID, so the consumers can
+// share the load by subscribing to the partitions instead of all the
+// channels. The partition is put after the channel ID in the subject, so
+// the subscriptions to the specific subject of the channel don't receive
+// the partitioned messages. One or less partitions disables partitioning.
+func Partitions(n int) Option {
+	return func(o *options) {
+		o.partitions = n
+	}
+}
+
+// connect connects to NATS, reconnecting indefinitely once the connection is
+// lost. The reconnected callback is invoked before the state handler is
+// notified, so the subscriptions can be resumed first.
+func connect(url string, logger log.Logger, reconnected func(), o options) (*broker.Conn, error) {
+	notify := func(s State) {
+		if o.handler != nil {
+			o.handler(s)
+		}
+	}
+
+	return broker.Connect(url,
+		broker.MaxReconnects(-1),
+		broker.ReconnectBufSize(o.bufSize),
+		broker.DisconnectErrHandler(func(_ *broker.Conn, err error) {
+			if logger != nil && err != nil {
+				logger.Warn(fmt.Sprintf("Lost connection to NATS: %s", err))
+			}
+			notify(Disconnected)
+		}),
+		broker.ReconnectHandler(func(c *broker.Conn) {
+			if logger != nil {
+				logger.Info(fmt.Sprintf("Reconnected to NATS at %s", c.ConnectedUrl()))
+			}
+			if reconnected != nil {
+				reconnected()
+			}
+			notify(Reconnected)
+		}),
+		broker.ClosedHandler(func(_ *broker.Conn) {
+			notify(Closed)
+		}),
+	)
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+const partPrefix = "part"
+
+// ChannelSubject returns the subject the message of the channel is published
+// to. Given more than one partition, the subject carries the partition of the
+// channel ahead of the subtopic, i.e. channels.<id>.part<k>.<subtopic>.
+func ChannelSubject(chanID, subtopic string, partitions int) string {
+	subject := fmt.Sprintf("%s.%s", chansPrefix, chanID)
+	if partitions > 1 {
+		subject = fmt.Sprintf("%s.%s%d", subject, partPrefix, ChannelPartition(chanID, partitions))
+	}
+	if subtopic != "" {
+		subject = fmt.Sprintf("%s.%s", subject, subtopic)
+	}
+	return subject
+}
+
+// ChannelPartition returns the partition, between 0 and partitions - 1, the
+// messages of the channel are published to. The partition depends only on
+// the channel ID, so all the messages of the channel end up in the same one.
+func ChannelPartition(chanID string, partitions int) int {
+	if partitions <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(chanID))
+	return int(h.Sum32() % uint32(partitions))
+}
+
+// PartitionSubjects returns the subjects of the messages published to the
+// partition, with and without the subtopic.
+func PartitionSubjects(partition int) []string {
+	subject := fmt.Sprintf("%s.*.%s%d", chansPrefix, partPrefix, partition)
+	return []string{subject, subject + ".>"}
+}
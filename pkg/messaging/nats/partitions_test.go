@@ -0,0 +1,60 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/messaging/nats"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelSubject(t *testing.T) {
+	part := nats.ChannelPartition(channel, 4)
+	assert.True(t, part >= 0 && part < 4, fmt.Sprintf("expected partition in [0, 4) got %d", part))
+
+	cases := []struct {
+		desc       string
+		subtopic   string
+		partitions int
+		subject    string
+	}{
+		{
+			desc:       "subject without partitions",
+			partitions: 0,
+			subject:    fmt.Sprintf("%s.%s", chansPrefix, channel),
+		},
+		{
+			desc:       "subject with subtopic without partitions",
+			subtopic:   subtopic,
+			partitions: 1,
+			subject:    fmt.Sprintf("%s.%s.%s", chansPrefix, channel, subtopic),
+		},
+		{
+			desc:       "subject with partitions",
+			partitions: 4,
+			subject:    fmt.Sprintf("%s.%s.part%d", chansPrefix, channel, part),
+		},
+		{
+			desc:       "subject with subtopic and partitions",
+			subtopic:   subtopic,
+			partitions: 4,
+			subject:    fmt.Sprintf("%s.%s.part%d.%s", chansPrefix, channel, part, subtopic),
+		},
+	}
+
+	for _, tc := range cases {
+		subject := nats.ChannelSubject(channel, tc.subtopic, tc.partitions)
+		assert.Equal(t, tc.subject, subject, fmt.Sprintf("%s: expected %s got %s", tc.desc, tc.subject, subject))
+	}
+
+	assert.Equal(t, part, nats.ChannelPartition(channel, 4), "expected the same partition of the channel")
+}
+
+func TestPartitionSubjects(t *testing.T) {
+	expected := []string{"channels.*.part3", "channels.*.part3.>"}
+	subjects := nats.PartitionSubjects(3)
+	assert.Equal(t, expected, subjects, fmt.Sprintf("expected %v got %v", expected, subjects))
+}
@@ -4,8 +4,6 @@
 package nats
 
 import (
-	"fmt"
-
 	"github.com/gogo/protobuf/proto"
 	"github.com/mainflux/mainflux/pkg/messaging"
 	broker "github.com/nats-io/nats.go"
@@ -14,7 +12,8 @@ import (
 var _ messaging.Publisher = (*publisher)(nil)
 
 type publisher struct {
-	conn *broker.Conn
+	conn       *broker.Conn
+	partitions int
 }
 
 // Publisher wraps messaging Publisher exposing
@@ -24,14 +23,17 @@ type Publisher interface {
 	Close()
 }
 
-// NewPublisher returns NATS message Publisher.
-func NewPublisher(url string) (Publisher, error) {
-	conn, err := broker.Connect(url)
+// NewPublisher returns NATS message Publisher. The messages published while
+// the connection is being reestablished are buffered, as set by the options.
+func NewPublisher(url string, opts ...Option) (Publisher, error) {
+	o := newOptions(opts...)
+	conn, err := connect(url, nil, nil, o)
 	if err != nil {
 		return nil, err
 	}
 	ret := &publisher{
-		conn: conn,
+		conn:       conn,
+		partitions: o.partitions,
 	}
 	return ret, nil
 }
@@ -42,10 +44,7 @@ func (pub *publisher) Publish(topic string, msg messaging.Message) error {
 		return err
 	}
 
-	subject := fmt.Sprintf("%s.%s", chansPrefix, topic)
-	if msg.Subtopic != "" {
-		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
-	}
+	subject := ChannelSubject(topic, msg.Subtopic, pub.partitions)
 	if err := pub.conn.Publish(subject, data); err != nil {
 		return err
 	}
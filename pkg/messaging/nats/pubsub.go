@@ -40,7 +40,13 @@ type pubsub struct {
 	logger        log.Logger
 	mu            sync.Mutex
 	queue         string
-	subscriptions map[string]*broker.Subscription
+	partitions    int
+	subscriptions map[string]subscription
+}
+
+type subscription struct {
+	*broker.Subscription
+	handler broker.MsgHandler
 }
 
 // NewPubSub returns NATS message publisher/subscriber.
@@ -50,17 +56,24 @@ type pubsub struct {
 // from ordinary subscribe. For more information, please take a look
 // here: https://docs.nats.io/developing-with-nats/receiving/queues.
 // If the queue is empty, Subscribe will be used.
-func NewPubSub(url, queue string, logger log.Logger) (PubSub, error) {
-	conn, err := broker.Connect(url)
-	if err != nil {
-		return nil, err
-	}
+// The connection is reestablished indefinitely once it's lost, resuming
+// the subscriptions, while the options tune the publishing in the meantime
+// and notify the caller about the connection state changes.
+func NewPubSub(url, queue string, logger log.Logger, opts ...Option) (PubSub, error) {
+	o := newOptions(opts...)
 	ret := &pubsub{
-		conn:          conn,
 		queue:         queue,
+		partitions:    o.partitions,
 		logger:        logger,
-		subscriptions: make(map[string]*broker.Subscription),
+		subscriptions: make(map[string]subscription),
 	}
+
+	conn, err := connect(url, logger, ret.resubscribe, o)
+	if err != nil {
+		return nil, err
+	}
+	ret.conn = conn
+
 	return ret, nil
 }
 
@@ -70,10 +83,7 @@ func (ps *pubsub) Publish(topic string, msg messaging.Message) error {
 		return err
 	}
 
-	subject := fmt.Sprintf("%s.%s", chansPrefix, topic)
-	if msg.Subtopic != "" {
-		subject = fmt.Sprintf("%s.%s", subject, msg.Subtopic)
-	}
+	subject := ChannelSubject(topic, msg.Subtopic, ps.partitions)
 	if err := ps.conn.Publish(subject, data); err != nil {
 		return err
 	}
@@ -91,20 +101,11 @@ func (ps *pubsub) Subscribe(topic string, handler messaging.MessageHandler) erro
 		return errAlreadySubscribed
 	}
 	nh := ps.natsHandler(handler)
-
-	if ps.queue != "" {
-		sub, err := ps.conn.QueueSubscribe(topic, ps.queue, nh)
-		if err != nil {
-			return err
-		}
-		ps.subscriptions[topic] = sub
-		return nil
-	}
-	sub, err := ps.conn.Subscribe(topic, nh)
+	sub, err := ps.subscribe(topic, nh)
 	if err != nil {
 		return err
 	}
-	ps.subscriptions[topic] = sub
+	ps.subscriptions[topic] = subscription{Subscription: sub, handler: nh}
 	return nil
 }
 
@@ -120,7 +121,8 @@ func (ps *pubsub) Unsubscribe(topic string) error {
 		return errNotSubscribed
 	}
 
-	if err := sub.Unsubscribe(); err != nil {
+	// The subscription that failed to resume is already gone.
+	if err := sub.Unsubscribe(); err != nil && err != broker.ErrBadSubscription {
 		return err
 	}
 
@@ -132,6 +134,33 @@ func (ps *pubsub) Close() {
 	ps.conn.Close()
 }
 
+func (ps *pubsub) subscribe(topic string, nh broker.MsgHandler) (*broker.Subscription, error) {
+	if ps.queue != "" {
+		return ps.conn.QueueSubscribe(topic, ps.queue, nh)
+	}
+	return ps.conn.Subscribe(topic, nh)
+}
+
+// resubscribe renews the subscriptions that didn't survive the reconnect.
+// NATS client restores the valid subscriptions by itself.
+func (ps *pubsub) resubscribe() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for topic, s := range ps.subscriptions {
+		if s.IsValid() {
+			continue
+		}
+		sub, err := ps.subscribe(topic, s.handler)
+		if err != nil {
+			ps.logger.Error(fmt.Sprintf("Failed to resubscribe to %s: %s", topic, err))
+			continue
+		}
+		ps.subscriptions[topic] = subscription{Subscription: sub, handler: s.handler}
+		ps.logger.Info(fmt.Sprintf("Resubscribed to %s", topic))
+	}
+}
+
 func (ps *pubsub) natsHandler(h messaging.MessageHandler) broker.MsgHandler {
 	return func(m *broker.Msg) {
 		var msg messaging.Message
@@ -0,0 +1,153 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package nats
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	broker "github.com/nats-io/nats.go"
+)
+
+var _ messaging.StreamSubscriber = (*streamSubscriber)(nil)
+
+// StreamSubscriber wraps messaging StreamSubscriber exposing
+// Close() method for NATS connection.
+type StreamSubscriber interface {
+	messaging.StreamSubscriber
+	Close()
+}
+
+type streamSubscriber struct {
+	conn          *broker.Conn
+	js            broker.JetStreamContext
+	stream        string
+	logger        log.Logger
+	mu            sync.Mutex
+	subscriptions map[string]*broker.Subscription
+}
+
+// NewStreamSubscriber returns NATS JetStream subscriber consuming the stream
+// with the given name. If the stream doesn't exist, it's created to capture
+// the messages of all the channels. The subscriptions are ephemeral, since
+// the position in the stream is expected to be tracked by the consumer.
+func NewStreamSubscriber(url, stream string, logger log.Logger, opts ...Option) (StreamSubscriber, error) {
+	conn, err := connect(url, logger, nil, newOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if _, err := js.StreamInfo(stream); err != nil {
+		cfg := &broker.StreamConfig{
+			Name:     stream,
+			Subjects: []string{SubjectAllChannels},
+			Storage:  broker.FileStorage,
+		}
+		if _, err := js.AddStream(cfg); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	ret := &streamSubscriber{
+		conn:          conn,
+		js:            js,
+		stream:        stream,
+		logger:        logger,
+		subscriptions: make(map[string]*broker.Subscription),
+	}
+	return ret, nil
+}
+
+func (ss *streamSubscriber) SubscribeFrom(topic string, seq uint64, handler messaging.StreamHandler) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if _, ok := ss.subscriptions[topic]; ok {
+		return errAlreadySubscribed
+	}
+
+	start := broker.DeliverAll()
+	if seq > 0 {
+		start = broker.StartSequence(seq)
+	}
+
+	// Single pending acknowledgement keeps the messages in the stream
+	// order, including the redelivered ones.
+	sub, err := ss.js.Subscribe(topic, ss.natsHandler(handler), broker.BindStream(ss.stream), start,
+		broker.AckExplicit(), broker.ManualAck(), broker.MaxAckPending(1))
+	if err != nil {
+		return err
+	}
+	ss.subscriptions[topic] = sub
+	return nil
+}
+
+func (ss *streamSubscriber) Unsubscribe(topic string) error {
+	if topic == "" {
+		return errEmptyTopic
+	}
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	sub, ok := ss.subscriptions[topic]
+	if !ok {
+		return errNotSubscribed
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		return err
+	}
+
+	delete(ss.subscriptions, topic)
+	return nil
+}
+
+func (ss *streamSubscriber) Close() {
+	ss.conn.Close()
+}
+
+func (ss *streamSubscriber) natsHandler(h messaging.StreamHandler) broker.MsgHandler {
+	return func(m *broker.Msg) {
+		meta, err := m.Metadata()
+		if err != nil {
+			ss.logger.Warn(fmt.Sprintf("Failed to read received message metadata: %s", err))
+			return
+		}
+
+		var msg messaging.Message
+		if err := proto.Unmarshal(m.Data, &msg); err != nil {
+			ss.logger.Warn(fmt.Sprintf("Failed to unmarshal received message: %s", err))
+			ss.ack(m)
+			return
+		}
+
+		if err := h(msg, meta.Sequence.Stream); err != nil {
+			ss.logger.Warn(fmt.Sprintf("Failed to handle Mainflux message %d: %s", meta.Sequence.Stream, err))
+			if err := m.Nak(); err != nil {
+				ss.logger.Warn(fmt.Sprintf("Failed to reject message %d: %s", meta.Sequence.Stream, err))
+			}
+			return
+		}
+		ss.ack(m)
+	}
+}
+
+func (ss *streamSubscriber) ack(m *broker.Msg) {
+	if err := m.Ack(); err != nil {
+		ss.logger.Warn(fmt.Sprintf("Failed to acknowledge message: %s", err))
+	}
+}
@@ -27,3 +27,20 @@ type PubSub interface {
 	Publisher
 	Subscriber
 }
+
+// StreamHandler represents Message handler for StreamSubscriber. The seq is
+// the position of the message in the stream.
+type StreamHandler func(msg Message, seq uint64) error
+
+// StreamSubscriber specifies the persistent message stream subscription API.
+type StreamSubscriber interface {
+	// SubscribeFrom subscribes to the message stream and consumes messages
+	// starting with the given stream sequence. The messages are handled one
+	// at a time, in the stream order. The message the handler fails to
+	// handle is redelivered.
+	SubscribeFrom(topic string, seq uint64, handler StreamHandler) error
+
+	// Unsubscribe unsubscribes from the message stream and
+	// stops consuming messages.
+	Unsubscribe(topic string) error
+}
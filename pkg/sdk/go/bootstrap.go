@@ -25,19 +25,27 @@ const bootstrapCertsEndpoint = "configs/certs"
 // MFKey is key of corresponding Mainflux Thing.
 // MFChannels is a list of Mainflux Channels corresponding Mainflux Thing connects to.
 type BootstrapConfig struct {
-	ThingID     string    `json:"thing_id,omitempty"`
-	Channels    []string  `json:"channels,omitempty"`
-	ExternalID  string    `json:"external_id,omitempty"`
-	ExternalKey string    `json:"external_key,omitempty"`
-	MFThing     string    `json:"mainflux_id,omitempty"`
-	MFChannels  []Channel `json:"mainflux_channels,omitempty"`
-	MFKey       string    `json:"mainflux_key,omitempty"`
-	Name        string    `json:"name,omitempty"`
-	ClientCert  string    `json:"client_cert,omitempty"`
-	ClientKey   string    `json:"client_key,omitempty"`
-	CACert      string    `json:"ca_cert,omitempty"`
-	Content     string    `json:"content,omitempty"`
-	State       int       `json:"state,omitempty"`
+	ThingID     string            `json:"thing_id,omitempty"`
+	Channels    []string          `json:"channels,omitempty"`
+	ExternalID  string            `json:"external_id,omitempty"`
+	ExternalKey string            `json:"external_key,omitempty"`
+	MFThing     string            `json:"mainflux_id,omitempty"`
+	MFChannels  []Channel         `json:"mainflux_channels,omitempty"`
+	MFKey       string            `json:"mainflux_key,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	ClientCert  string            `json:"client_cert,omitempty"`
+	ClientKey   string            `json:"client_key,omitempty"`
+	CACert      string            `json:"ca_cert,omitempty"`
+	Content     *BootstrapContent `json:"content,omitempty"`
+	State       int               `json:"state,omitempty"`
+}
+
+// BootstrapContent represents the configuration the Thing receives when it
+// bootstraps, split into the network, the credentials and the app sections.
+type BootstrapContent struct {
+	Network     map[string]interface{} `json:"network,omitempty"`
+	Credentials map[string]interface{} `json:"credentials,omitempty"`
+	App         map[string]interface{} `json:"app,omitempty"`
 }
 
 type ConfigUpdateCertReq struct {
@@ -65,7 +73,7 @@ func (sdk mfSDK) AddBootstrap(token string, cfg BootstrapConfig) (string, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return "", errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+		return "", errors.Wrap(ErrFailedCreation, statusError(resp))
 	}
 
 	id := strings.TrimPrefix(resp.Header.Get("Location"), "/things/configs/")
@@ -95,7 +103,7 @@ func (sdk mfSDK) Whitelist(token string, cfg BootstrapConfig) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrFailedWhitelist, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedWhitelist, statusError(resp))
 	}
 
 	return nil
@@ -120,7 +128,7 @@ func (sdk mfSDK) ViewBootstrap(token, id string) (BootstrapConfig, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return BootstrapConfig{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return BootstrapConfig{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var bc BootstrapConfig
@@ -149,11 +157,46 @@ func (sdk mfSDK) UpdateBootstrap(token string, cfg BootstrapConfig) error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrFailedUpdate, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedUpdate, statusError(resp))
 	}
 
 	return nil
 }
+func (sdk mfSDK) PatchBootstrap(token string, cfg BootstrapConfig) (BootstrapConfig, error) {
+	data, err := json.Marshal(BootstrapConfig{Name: cfg.Name, Content: cfg.Content})
+	if err != nil {
+		return BootstrapConfig{}, err
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", sdk.bootstrapURL, configsEndpoint, cfg.MFThing)
+	req, err := http.NewRequest(http.MethodPatch, url, bytes.NewReader(data))
+	if err != nil {
+		return BootstrapConfig{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return BootstrapConfig{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return BootstrapConfig{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return BootstrapConfig{}, errors.Wrap(ErrFailedUpdate, statusError(resp))
+	}
+
+	var bc BootstrapConfig
+	if err := json.Unmarshal(body, &bc); err != nil {
+		return BootstrapConfig{}, err
+	}
+
+	return bc, nil
+}
+
 func (sdk mfSDK) UpdateBootstrapCerts(token, id, clientCert, clientKey, ca string) error {
 	url := fmt.Sprintf("%s/%s/%s", sdk.bootstrapURL, bootstrapCertsEndpoint, id)
 	request := ConfigUpdateCertReq{
@@ -177,7 +220,7 @@ func (sdk mfSDK) UpdateBootstrapCerts(token, id, clientCert, clientKey, ca strin
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrFailedCertUpdate, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedCertUpdate, statusError(resp))
 	}
 
 	return nil
@@ -196,7 +239,7 @@ func (sdk mfSDK) RemoveBootstrap(token, id string) error {
 	}
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.Wrap(ErrFailedRemoval, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedRemoval, statusError(resp))
 	}
 
 	return nil
@@ -221,7 +264,7 @@ func (sdk mfSDK) Bootstrap(externalKey, externalID string) (BootstrapConfig, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return BootstrapConfig{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return BootstrapConfig{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var bc BootstrapConfig
@@ -34,7 +34,7 @@ func (sdk mfSDK) CreateChannel(c Channel, token string) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return "", errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+		return "", errors.Wrap(ErrFailedCreation, statusError(resp))
 	}
 
 	id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", channelsEndpoint))
@@ -60,7 +60,7 @@ func (sdk mfSDK) CreateChannels(chs []Channel, token string) ([]Channel, error)
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return []Channel{}, errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+		return []Channel{}, errors.Wrap(ErrFailedCreation, statusError(resp))
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
@@ -95,7 +95,7 @@ func (sdk mfSDK) Channels(token string, offset, limit uint64, name string) (Chan
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return ChannelsPage{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return ChannelsPage{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var cp ChannelsPage
@@ -125,7 +125,7 @@ func (sdk mfSDK) ChannelsByThing(token, thingID string, offset, limit uint64, di
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return ChannelsPage{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return ChannelsPage{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var cp ChannelsPage
@@ -155,7 +155,7 @@ func (sdk mfSDK) Channel(id, token string) (Channel, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return Channel{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return Channel{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var c Channel
@@ -184,7 +184,7 @@ func (sdk mfSDK) UpdateChannel(c Channel, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrFailedUpdate, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedUpdate, statusError(resp))
 	}
 
 	return nil
@@ -203,7 +203,7 @@ func (sdk mfSDK) DeleteChannel(id, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.Wrap(ErrFailedRemoval, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedRemoval, statusError(resp))
 	}
 
 	return nil
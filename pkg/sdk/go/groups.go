@@ -39,7 +39,7 @@ func (sdk mfSDK) CreateGroup(g Group, token string) (string, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return "", errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+		return "", errors.Wrap(ErrFailedCreation, statusError(resp))
 	}
 
 	id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", groupsEndpoint))
@@ -59,7 +59,7 @@ func (sdk mfSDK) DeleteGroup(id, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.Wrap(ErrFailedRemoval, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedRemoval, statusError(resp))
 	}
 
 	return nil
@@ -90,7 +90,7 @@ func (sdk mfSDK) Assign(memberIDs []string, memberType, groupID string, token st
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrMemberAdd, errors.New(resp.Status))
+		return errors.Wrap(ErrMemberAdd, statusError(resp))
 	}
 
 	return nil
@@ -120,14 +120,14 @@ func (sdk mfSDK) Unassign(token, groupID string, memberIDs ...string) error {
 	}
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.Wrap(ErrFailedRemoval, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedRemoval, statusError(resp))
 	}
 
 	return nil
 }
 
 func (sdk mfSDK) Members(groupID, token string, offset, limit uint64) (MembersPage, error) {
-	url := fmt.Sprintf("%s, %s/%s/members?offset=%d&limit=%d&", sdk.authURL, groupsEndpoint, groupID, offset, limit)
+	url := fmt.Sprintf("%s/%s/%s/members?offset=%d&limit=%d", sdk.authURL, groupsEndpoint, groupID, offset, limit)
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return MembersPage{}, err
@@ -145,7 +145,7 @@ func (sdk mfSDK) Members(groupID, token string, offset, limit uint64) (MembersPa
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return MembersPage{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return MembersPage{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var tp MembersPage
@@ -189,7 +189,7 @@ func (sdk mfSDK) getGroups(token, url string) (GroupsPage, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return GroupsPage{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return GroupsPage{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var tp GroupsPage
@@ -218,7 +218,7 @@ func (sdk mfSDK) Group(id, token string) (Group, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return Group{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return Group{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var t Group
@@ -247,7 +247,7 @@ func (sdk mfSDK) UpdateGroup(t Group, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrFailedUpdate, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedUpdate, statusError(resp))
 	}
 
 	return nil
@@ -272,7 +272,7 @@ func (sdk mfSDK) Memberships(memberID, token string, offset, limit uint64) (Grou
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return GroupsPage{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return GroupsPage{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var tp GroupsPage
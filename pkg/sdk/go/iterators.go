@@ -0,0 +1,183 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+// defPageLimit is the page size used by the iterators when the limit is 0.
+const defPageLimit = 100
+
+// pager keeps the state of the iteration over the consecutive pages.
+type pager struct {
+	offset uint64
+	limit  uint64
+	done   bool
+	err    error
+}
+
+func newPager(limit uint64) pager {
+	if limit == 0 {
+		limit = defPageLimit
+	}
+	return pager{limit: limit}
+}
+
+// next fetches the next page and reports whether it contains any items.
+// The fetch function returns the page metadata and the number of items.
+func (p *pager) next(fetch func(offset, limit uint64) (pageRes, int, error)) bool {
+	if p.done || p.err != nil {
+		return false
+	}
+
+	pm, n, err := fetch(p.offset, p.limit)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.offset += uint64(n)
+	if n == 0 || p.offset >= pm.Total {
+		p.done = true
+	}
+
+	return n > 0
+}
+
+// ThingsIterator iterates over the pages of things.
+type ThingsIterator struct {
+	pager
+	sdk   mfSDK
+	token string
+	name  string
+	page  ThingsPage
+}
+
+// Next fetches the next page of things and reports whether it contains any
+// things. It returns false once all the things are fetched or on error.
+func (it *ThingsIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (pageRes, int, error) {
+		tp, err := it.sdk.Things(it.token, offset, limit, it.name)
+		it.page = tp
+		return tp.pageRes, len(tp.Things), err
+	})
+}
+
+// Page returns the page fetched by the last call to Next.
+func (it *ThingsIterator) Page() ThingsPage {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ThingsIterator) Err() error {
+	return it.err
+}
+
+// All fetches the remaining pages and returns all of their things.
+func (it *ThingsIterator) All() ([]Thing, error) {
+	things := []Thing{}
+	for it.Next() {
+		things = append(things, it.page.Things...)
+	}
+	return things, it.err
+}
+
+// ChannelsIterator iterates over the pages of channels.
+type ChannelsIterator struct {
+	pager
+	sdk   mfSDK
+	token string
+	name  string
+	page  ChannelsPage
+}
+
+// Next fetches the next page of channels and reports whether it contains
+// any channels. It returns false once all the channels are fetched or on
+// error.
+func (it *ChannelsIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (pageRes, int, error) {
+		cp, err := it.sdk.Channels(it.token, offset, limit, it.name)
+		it.page = cp
+		return cp.pageRes, len(cp.Channels), err
+	})
+}
+
+// Page returns the page fetched by the last call to Next.
+func (it *ChannelsIterator) Page() ChannelsPage {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ChannelsIterator) Err() error {
+	return it.err
+}
+
+// All fetches the remaining pages and returns all of their channels.
+func (it *ChannelsIterator) All() ([]Channel, error) {
+	channels := []Channel{}
+	for it.Next() {
+		channels = append(channels, it.page.Channels...)
+	}
+	return channels, it.err
+}
+
+// GroupsIterator iterates over the pages of groups.
+type GroupsIterator struct {
+	pager
+	sdk   mfSDK
+	token string
+	page  GroupsPage
+}
+
+// Next fetches the next page of groups and reports whether it contains any
+// groups. It returns false once all the groups are fetched or on error.
+func (it *GroupsIterator) Next() bool {
+	return it.next(func(offset, limit uint64) (pageRes, int, error) {
+		gp, err := it.sdk.Groups(offset, limit, it.token)
+		it.page = gp
+		return gp.pageRes, len(gp.Groups), err
+	})
+}
+
+// Page returns the page fetched by the last call to Next.
+func (it *GroupsIterator) Page() GroupsPage {
+	return it.page
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *GroupsIterator) Err() error {
+	return it.err
+}
+
+// All fetches the remaining pages and returns all of their groups.
+func (it *GroupsIterator) All() ([]Group, error) {
+	groups := []Group{}
+	for it.Next() {
+		groups = append(groups, it.page.Groups...)
+	}
+	return groups, it.err
+}
+
+func (sdk mfSDK) ThingsPages(token, name string, limit uint64) *ThingsIterator {
+	return &ThingsIterator{
+		pager: newPager(limit),
+		sdk:   sdk,
+		token: token,
+		name:  name,
+	}
+}
+
+func (sdk mfSDK) ChannelsPages(token, name string, limit uint64) *ChannelsIterator {
+	return &ChannelsIterator{
+		pager: newPager(limit),
+		sdk:   sdk,
+		token: token,
+		name:  name,
+	}
+}
+
+func (sdk mfSDK) GroupsPages(token string, limit uint64) *GroupsIterator {
+	return &GroupsIterator{
+		pager: newPager(limit),
+		sdk:   sdk,
+		token: token,
+	}
+}
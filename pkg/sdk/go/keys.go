@@ -0,0 +1,45 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const keysEndpoint = "keys"
+
+func (sdk mfSDK) Keys(token string, offset, limit uint64) (KeysPage, error) {
+	url := fmt.Sprintf("%s/%s?offset=%d&limit=%d", sdk.authURL, keysEndpoint, offset, limit)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return KeysPage{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return KeysPage{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return KeysPage{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return KeysPage{}, errors.Wrap(ErrFailedFetch, statusError(resp))
+	}
+
+	var kp KeysPage
+	if err := json.Unmarshal(body, &kp); err != nil {
+		return KeysPage{}, err
+	}
+
+	return kp, nil
+}
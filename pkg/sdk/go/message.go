@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/mainflux/mainflux/pkg/errors"
@@ -34,7 +36,7 @@ func (sdk mfSDK) SendMessage(chanName, msg, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusAccepted {
-		return errors.Wrap(ErrFailedPublish, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedPublish, statusError(resp))
 	}
 
 	return nil
@@ -65,7 +67,68 @@ func (sdk mfSDK) ReadMessages(chanName, token string) (MessagesPage, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return MessagesPage{}, errors.Wrap(ErrFailedRead, errors.New(resp.Status))
+		return MessagesPage{}, errors.Wrap(ErrFailedRead, statusError(resp))
+	}
+
+	var mp MessagesPage
+	if err := json.Unmarshal(body, &mp); err != nil {
+		return MessagesPage{}, err
+	}
+
+	return mp, nil
+}
+
+func (sdk mfSDK) QueryMessages(chanID, token string, q MessagesQuery) (MessagesPage, error) {
+	params := url.Values{}
+	params.Set("offset", strconv.FormatUint(q.Offset, 10))
+	params.Set("limit", strconv.FormatUint(q.Limit, 10))
+	strs := map[string]string{
+		"subtopic":   q.Subtopic,
+		"publisher":  q.Publisher,
+		"protocol":   q.Protocol,
+		"name":       q.Name,
+		"comparator": q.Comparator,
+		"vs":         q.StringValue,
+		"vd":         q.DataValue,
+	}
+	for k, v := range strs {
+		if v != "" {
+			params.Set(k, v)
+		}
+	}
+	floats := map[string]float64{
+		"v":    q.Value,
+		"from": q.From,
+		"to":   q.To,
+	}
+	for k, v := range floats {
+		if v != 0 {
+			params.Set(k, strconv.FormatFloat(v, 'f', -1, 64))
+		}
+	}
+	if q.BoolValue {
+		params.Set("vb", "true")
+	}
+
+	endpoint := fmt.Sprintf("%s/channels/%s/messages?%s", sdk.readerURL, chanID, params.Encode())
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return MessagesPage{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(sdk.msgContentType))
+	if err != nil {
+		return MessagesPage{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return MessagesPage{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return MessagesPage{}, errors.Wrap(ErrFailedRead, statusError(resp))
 	}
 
 	var mp MessagesPage
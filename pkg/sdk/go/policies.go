@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const policiesEndpoint = "policies"
+
+func (sdk mfSDK) CreatePolicy(p Policy, token string) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s", sdk.authURL, policiesEndpoint)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return errors.Wrap(ErrFailedCreation, statusError(resp))
+	}
+
+	return nil
+}
+
+func (sdk mfSDK) DeletePolicy(p Policy, token string) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s", sdk.authURL, policiesEndpoint)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return errors.Wrap(ErrFailedRemoval, statusError(resp))
+	}
+
+	return nil
+}
@@ -8,6 +8,10 @@ type assignRequest struct {
 	Members []string `json:"members"`
 }
 
+type importUsersReq struct {
+	Users []UserRecord `json:"users"`
+}
+
 // UserPasswordReq contains old and new passwords
 type UserPasswordReq struct {
 	OldPassword string `json:"old_password,omitempty"`
@@ -18,4 +22,6 @@ type UserPasswordReq struct {
 type ConnectionIDs struct {
 	ChannelIDs []string `json:"channel_ids"`
 	ThingIDs   []string `json:"thing_ids"`
+	Role       string   `json:"role,omitempty"`
+	Label      string   `json:"label,omitempty"`
 }
@@ -9,6 +9,14 @@ type tokenRes struct {
 	Token string `json:"token,omitempty"`
 }
 
+type importUsersRes struct {
+	Users []ImportResult `json:"users"`
+}
+
+type exportUsersRes struct {
+	Users []UserRecord `json:"users"`
+}
+
 type createThingsRes struct {
 	Things []Thing `json:"things"`
 }
@@ -51,6 +59,12 @@ type UsersPage struct {
 	pageRes
 }
 
+// KeysPage contains list of keys in a page with proper metadata.
+type KeysPage struct {
+	Keys []Key `json:"keys"`
+	pageRes
+}
+
 type MembersPage struct {
 	Members []Member `json:"members"`
 	pageRes
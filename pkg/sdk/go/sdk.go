@@ -5,8 +5,10 @@ package sdk
 
 import (
 	"crypto/tls"
-	"errors"
 	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
 )
 
 const (
@@ -21,9 +23,19 @@ const (
 )
 
 var (
-	// ErrUnauthorized indicates that entity creation failed.
+	// ErrUnauthorized indicates missing or invalid credentials, or that the
+	// user is not allowed to perform the request.
 	ErrUnauthorized = errors.New("unauthorized, missing credentials")
 
+	// ErrNotFound indicates that the requested entity does not exist.
+	ErrNotFound = errors.New("entity not found")
+
+	// ErrConflict indicates that the entity already exists.
+	ErrConflict = errors.New("entity already exists")
+
+	// ErrMalformedEntity indicates that the request is malformed.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
 	// ErrFailedCreation indicates that entity creation failed.
 	ErrFailedCreation = errors.New("failed to create entity")
 
@@ -83,6 +95,29 @@ type User struct {
 	Groups   []string               `json:"groups,omitempty"`
 	Password string                 `json:"password,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   map[string]string      `json:"labels,omitempty"`
+	Status   string                 `json:"status,omitempty"`
+}
+
+// UserRecord represents mainflux user imported or exported in bulk. The user
+// is imported either with the password or with the password hash, otherwise
+// the password is generated.
+type UserRecord struct {
+	ID           string                 `json:"id,omitempty"`
+	Email        string                 `json:"email"`
+	Password     string                 `json:"password,omitempty"`
+	PasswordHash string                 `json:"password_hash,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Groups       []string               `json:"groups,omitempty"`
+}
+
+// ImportResult represents the outcome of the import of the single user. The
+// password is set only if it was generated.
+type ImportResult struct {
+	Email    string `json:"email"`
+	ID       string `json:"id,omitempty"`
+	Password string `json:"password,omitempty"`
+	Error    string `json:"error,omitempty"`
 }
 
 // Group represents mainflux users group.
@@ -92,6 +127,7 @@ type Group struct {
 	Description string                 `json:"description,omitempty"`
 	ParentID    string                 `json:"parent_id,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Labels      map[string]string      `json:"labels,omitempty"`
 }
 
 // Thing represents mainflux thing.
@@ -100,6 +136,7 @@ type Thing struct {
 	Name     string                 `json:"name,omitempty"`
 	Key      string                 `json:"key,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   map[string]string      `json:"labels,omitempty"`
 }
 
 // Channel represents mainflux channel.
@@ -107,12 +144,55 @@ type Channel struct {
 	ID       string                 `json:"id,omitempty"`
 	Name     string                 `json:"name,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   map[string]string      `json:"labels,omitempty"`
+}
+
+// Policy represents the actions the subjects are allowed to perform on the
+// object.
+type Policy struct {
+	Object   string   `json:"object"`
+	Subjects []string `json:"subjects"`
+	Actions  []string `json:"policies"`
+}
+
+// Key represents the key issued by the user, such as the API key. The key
+// value isn't retrievable once the key is issued.
+type Key struct {
+	ID        string            `json:"id"`
+	Type      uint32            `json:"type,omitempty"`
+	OrgID     string            `json:"org_id,omitempty"`
+	Label     string            `json:"label,omitempty"`
+	Scopes    []string          `json:"scopes,omitempty"`
+	Claims    map[string]string `json:"claims,omitempty"`
+	IssuedAt  time.Time         `json:"issued_at"`
+	ExpiresAt *time.Time        `json:"expires_at,omitempty"`
 }
 
 //Member represents mainflux member.
 type Member struct {
-	ID   string
-	Type string
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Relation  string    `json:"relation,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MessagesQuery represents the filters of the messages read from the reader.
+// The zero values are left out of the query.
+type MessagesQuery struct {
+	Offset      uint64
+	Limit       uint64
+	Subtopic    string
+	Publisher   string
+	Protocol    string
+	Name        string
+	Value       float64
+	Comparator  string
+	BoolValue   bool
+	StringValue string
+	DataValue   string
+	From        float64
+	To          float64
 }
 
 // SDK contains Mainflux API.
@@ -132,6 +212,13 @@ type SDK interface {
 	// UpdatePassword updates user password.
 	UpdatePassword(oldPass, newPass, token string) error
 
+	// ImportUsers creates users in bulk and returns the outcome of each
+	// import.
+	ImportUsers(users []UserRecord, token string) ([]ImportResult, error)
+
+	// ExportUsers returns all users along with their group memberships.
+	ExportUsers(token string) ([]UserRecord, error)
+
 	// CreateThing registers new thing and returns its id.
 	CreateThing(thing Thing, token string) (string, error)
 
@@ -141,6 +228,10 @@ type SDK interface {
 	// Things returns page of things.
 	Things(token string, offset, limit uint64, name string) (ThingsPage, error)
 
+	// ThingsPages returns the iterator over the pages of things of the given
+	// size, or of the default size if the limit is 0.
+	ThingsPages(token, name string, limit uint64) *ThingsIterator
+
 	// ThingsByChannel returns page of things that are connected or not connected
 	// to specified channel.
 	ThingsByChannel(token, chanID string, offset, limit uint64, connected bool) (ThingsPage, error)
@@ -163,6 +254,10 @@ type SDK interface {
 	// Groups returns page of users groups.
 	Groups(offset, limit uint64, token string) (GroupsPage, error)
 
+	// GroupsPages returns the iterator over the pages of users groups of the
+	// given size, or of the default size if the limit is 0.
+	GroupsPages(token string, limit uint64) *GroupsIterator
+
 	// Parents returns page of users groups.
 	Parents(id string, offset, limit uint64, token string) (GroupsPage, error)
 
@@ -184,9 +279,18 @@ type SDK interface {
 	// Memberships lists groups for user.
 	Memberships(userID, token string, offset, limit uint64) (GroupsPage, error)
 
+	// Keys lists the stored keys, such as the API keys, issued by the user.
+	Keys(token string, offset, limit uint64) (KeysPage, error)
+
 	// UpdateGroup updates existing group.
 	UpdateGroup(group Group, token string) error
 
+	// CreatePolicy adds the policy actions for all the policy subjects.
+	CreatePolicy(policy Policy, token string) error
+
+	// DeletePolicy removes the policy actions of all the policy subjects.
+	DeletePolicy(policy Policy, token string) error
+
 	// Connect bulk connects things to channels specified by id.
 	Connect(conns ConnectionIDs, token string) error
 
@@ -202,6 +306,10 @@ type SDK interface {
 	// Channels returns page of channels.
 	Channels(token string, offset, limit uint64, name string) (ChannelsPage, error)
 
+	// ChannelsPages returns the iterator over the pages of channels of the
+	// given size, or of the default size if the limit is 0.
+	ChannelsPages(token, name string, limit uint64) *ChannelsIterator
+
 	// ChannelsByThing returns page of channels that are connected or not connected
 	// to specified thing.
 	ChannelsByThing(token, thingID string, offset, limit uint64, connected bool) (ChannelsPage, error)
@@ -221,6 +329,10 @@ type SDK interface {
 	// ReadMessages read messages of specified channel.
 	ReadMessages(chanID, token string) (MessagesPage, error)
 
+	// QueryMessages reads the page of messages of specified channel
+	// matching the query.
+	QueryMessages(chanID, token string, q MessagesQuery) (MessagesPage, error)
+
 	// SetContentType sets message content type.
 	SetContentType(ct ContentType) error
 
@@ -236,6 +348,10 @@ type SDK interface {
 	// Update updates editable fields of the provided Config.
 	UpdateBootstrap(token string, cfg BootstrapConfig) error
 
+	// PatchBootstrap renames the Config if the name is given and deep-merges
+	// the given content into the Config content.
+	PatchBootstrap(token string, cfg BootstrapConfig) (BootstrapConfig, error)
+
 	// Update boostrap config certificates
 	UpdateBootstrapCerts(token string, id string, clientCert, clientKey, ca string) error
 
@@ -318,3 +434,22 @@ func (sdk mfSDK) sendRequest(req *http.Request, token, contentType string) (*htt
 
 	return sdk.client.Do(req)
 }
+
+// statusError returns the error describing the unexpected response status.
+// The error wraps the typed error matching the status code, if any, so the
+// callers can check it with errors.Contains instead of parsing the status.
+func statusError(resp *http.Response) error {
+	err := errors.New(resp.Status)
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return errors.Wrap(ErrUnauthorized, err)
+	case http.StatusNotFound:
+		return errors.Wrap(ErrNotFound, err)
+	case http.StatusConflict:
+		return errors.Wrap(ErrConflict, err)
+	case http.StatusBadRequest:
+		return errors.Wrap(ErrMalformedEntity, err)
+	default:
+		return err
+	}
+}
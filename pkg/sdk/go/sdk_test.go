@@ -8,9 +8,22 @@ import (
 	"net/http"
 
 	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
 )
 
+var statusErrors = map[int]error{
+	http.StatusUnauthorized: sdk.ErrUnauthorized,
+	http.StatusForbidden:    sdk.ErrUnauthorized,
+	http.StatusNotFound:     sdk.ErrNotFound,
+	http.StatusConflict:     sdk.ErrConflict,
+	http.StatusBadRequest:   sdk.ErrMalformedEntity,
+}
+
 func createError(e error, statusCode int) error {
 	httpStatus := fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode))
-	return errors.Wrap(e, errors.New(httpStatus))
+	var err error = errors.New(httpStatus)
+	if se, ok := statusErrors[statusCode]; ok {
+		err = errors.Wrap(se, err)
+	}
+	return errors.Wrap(e, err)
 }
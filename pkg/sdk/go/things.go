@@ -37,7 +37,7 @@ func (sdk mfSDK) CreateThing(t Thing, token string) (string, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return "", errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+		return "", errors.Wrap(ErrFailedCreation, statusError(resp))
 	}
 
 	id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", thingsEndpoint))
@@ -64,7 +64,7 @@ func (sdk mfSDK) CreateThings(things []Thing, token string) ([]Thing, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return []Thing{}, errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+		return []Thing{}, errors.Wrap(ErrFailedCreation, statusError(resp))
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
@@ -101,7 +101,7 @@ func (sdk mfSDK) Things(token string, offset, limit uint64, name string) (Things
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return ThingsPage{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return ThingsPage{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var tp ThingsPage
@@ -131,7 +131,7 @@ func (sdk mfSDK) ThingsByChannel(token, chanID string, offset, limit uint64, dis
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return ThingsPage{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return ThingsPage{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var tp ThingsPage
@@ -162,7 +162,7 @@ func (sdk mfSDK) Thing(id, token string) (Thing, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return Thing{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return Thing{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var t Thing
@@ -192,7 +192,7 @@ func (sdk mfSDK) UpdateThing(t Thing, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrFailedUpdate, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedUpdate, statusError(resp))
 	}
 
 	return nil
@@ -212,7 +212,7 @@ func (sdk mfSDK) DeleteThing(id, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.Wrap(ErrFailedRemoval, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedRemoval, statusError(resp))
 	}
 
 	return nil
@@ -236,7 +236,7 @@ func (sdk mfSDK) Connect(connIDs ConnectionIDs, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrFailedConnect, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedConnect, statusError(resp))
 	}
 
 	return nil
@@ -255,7 +255,7 @@ func (sdk mfSDK) DisconnectThing(thingID, chanID, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.Wrap(ErrFailedDisconnect, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedDisconnect, statusError(resp))
 	}
 
 	return nil
@@ -8,7 +8,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/mainflux/mainflux/internal/jobs"
+	"github.com/mainflux/mainflux/pkg/errors"
 	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/mainflux/mainflux/things"
@@ -20,15 +23,16 @@ import (
 )
 
 const (
-	contentType = "application/senml+json"
-	email       = "user@example.com"
-	adminEmail  = "admin@example.com"
-	otherEmail  = "other_user@example.com"
-	token       = "token"
-	otherToken  = "other_token"
-	wrongValue  = "wrong_value"
-	badID       = "999"
-	emptyValue  = ""
+	contentType  = "application/senml+json"
+	email        = "user@example.com"
+	adminEmail   = "admin@example.com"
+	otherEmail   = "other_user@example.com"
+	token        = "token"
+	webhookLimit = 10
+	otherToken   = "other_token"
+	wrongValue   = "wrong_value"
+	badID        = "999"
+	emptyValue   = ""
 )
 
 var (
@@ -47,15 +51,19 @@ func newThingsService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	webhooksRepo := mocks.NewWebhookRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, webhooksRepo, webhookLimit, chanCache, thingCache, idProvider, nil)
 }
 
 func newThingsServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	auth := mocks.NewAuthService(map[string]string{token: email}, nil)
+	manager := jobs.NewManager(jobs.NewMemoryStore(), uuid.NewMock(), time.Minute, time.Hour)
+	jsvc := things.NewJobService(svc, auth, manager)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, jsvc)
 	return httptest.NewServer(mux)
 }
 
@@ -343,6 +351,84 @@ func TestThings(t *testing.T) {
 	}
 }
 
+func TestThingsPages(t *testing.T) {
+	svc := newThingsService(map[string]string{token: email})
+	ts := newThingsServer(svc)
+	defer ts.Close()
+	sdkConf := sdk.Config{
+		ThingsURL:       ts.URL,
+		MsgContentType:  contentType,
+		TLSVerification: false,
+	}
+	var things []sdk.Thing
+
+	mainfluxSDK := sdk.NewSDK(sdkConf)
+	for i := 1; i < 13; i++ {
+		id := fmt.Sprintf("%s%012d", chPrefix, i)
+		name := fmt.Sprintf("test-%d", i)
+		th := sdk.Thing{ID: id, Name: name, Metadata: metadata}
+		_, err := mainfluxSDK.CreateThing(th, token)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		th.Key = fmt.Sprintf("%s%012d", uuid.Prefix, i)
+		things = append(things, th)
+	}
+
+	cases := []struct {
+		desc     string
+		token    string
+		limit    uint64
+		pages    int
+		err      error
+		response []sdk.Thing
+	}{
+		{
+			desc:     "iterate over pages of things",
+			token:    token,
+			limit:    5,
+			pages:    3,
+			err:      nil,
+			response: things,
+		},
+		{
+			desc:     "iterate over single page of things",
+			token:    token,
+			limit:    12,
+			pages:    1,
+			err:      nil,
+			response: things,
+		},
+		{
+			desc:     "iterate over pages of things with default limit",
+			token:    token,
+			limit:    0,
+			pages:    1,
+			err:      nil,
+			response: things,
+		},
+		{
+			desc:     "iterate over pages of things with invalid token",
+			token:    wrongValue,
+			limit:    5,
+			pages:    0,
+			err:      sdk.ErrUnauthorized,
+			response: []sdk.Thing{},
+		},
+	}
+	for _, tc := range cases {
+		it := mainfluxSDK.ThingsPages(tc.token, "", tc.limit)
+		pages := 0
+		for it.Next() {
+			pages++
+		}
+		assert.Equal(t, tc.pages, pages, fmt.Sprintf("%s: expected %d pages, got %d", tc.desc, tc.pages, pages))
+		assert.True(t, errors.Contains(it.Err(), tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, it.Err()))
+
+		res, err := mainfluxSDK.ThingsPages(tc.token, "", tc.limit).All()
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected error %s, got %s", tc.desc, tc.err, err))
+		assert.Equal(t, tc.response, res, fmt.Sprintf("%s: expected response things %s, got %s", tc.desc, tc.response, res))
+	}
+}
+
 func TestThingsByChannel(t *testing.T) {
 	svc := newThingsService(map[string]string{token: email})
 	ts := newThingsServer(svc)
@@ -818,8 +904,8 @@ func TestConnect(t *testing.T) {
 
 	for _, tc := range cases {
 		connIDs := sdk.ConnectionIDs{
-			[]string{tc.thingID},
-			[]string{tc.chanID},
+			ChannelIDs: []string{tc.chanID},
+			ThingIDs:   []string{tc.thingID},
 		}
 
 		err := mainfluxSDK.Connect(connIDs, tc.token)
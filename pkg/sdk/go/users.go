@@ -19,6 +19,8 @@ const (
 	tokensEndpoint   = "tokens"
 	passwordEndpoint = "password"
 	membersEndpoint  = "members"
+	importEndpoint   = "import"
+	exportEndpoint   = "export"
 )
 
 func (sdk mfSDK) CreateUser(token string, u User) (string, error) {
@@ -39,7 +41,7 @@ func (sdk mfSDK) CreateUser(token string, u User) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return "", errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+		return "", errors.Wrap(ErrFailedCreation, statusError(resp))
 	}
 
 	id := strings.TrimPrefix(resp.Header.Get("Location"), fmt.Sprintf("/%s/", usersEndpoint))
@@ -65,7 +67,7 @@ func (sdk mfSDK) User(token string) (User, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return User{}, errors.Wrap(ErrFailedFetch, errors.New(resp.Status))
+		return User{}, errors.Wrap(ErrFailedFetch, statusError(resp))
 	}
 
 	var u User
@@ -95,7 +97,7 @@ func (sdk mfSDK) CreateToken(user User) (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return "", errors.Wrap(ErrFailedCreation, errors.New(resp.Status))
+		return "", errors.Wrap(ErrFailedCreation, statusError(resp))
 	}
 
 	var tr tokenRes
@@ -124,7 +126,7 @@ func (sdk mfSDK) UpdateUser(u User, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.Wrap(ErrFailedUpdate, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedUpdate, statusError(resp))
 	}
 
 	return nil
@@ -152,8 +154,73 @@ func (sdk mfSDK) UpdatePassword(oldPass, newPass, token string) error {
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.Wrap(ErrFailedUpdate, errors.New(resp.Status))
+		return errors.Wrap(ErrFailedUpdate, statusError(resp))
 	}
 
 	return nil
 }
+
+func (sdk mfSDK) ImportUsers(users []UserRecord, token string) ([]ImportResult, error) {
+	data, err := json.Marshal(importUsersReq{Users: users})
+	if err != nil {
+		return []ImportResult{}, err
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", sdk.usersURL, usersEndpoint, importEndpoint)
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return []ImportResult{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return []ImportResult{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return []ImportResult{}, errors.Wrap(ErrFailedCreation, statusError(resp))
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return []ImportResult{}, err
+	}
+
+	var ir importUsersRes
+	if err := json.Unmarshal(body, &ir); err != nil {
+		return []ImportResult{}, err
+	}
+
+	return ir.Users, nil
+}
+
+func (sdk mfSDK) ExportUsers(token string) ([]UserRecord, error) {
+	url := fmt.Sprintf("%s/%s/%s", sdk.usersURL, usersEndpoint, exportEndpoint)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return []UserRecord{}, err
+	}
+
+	resp, err := sdk.sendRequest(req, token, string(CTJSON))
+	if err != nil {
+		return []UserRecord{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return []UserRecord{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return []UserRecord{}, errors.Wrap(ErrFailedFetch, statusError(resp))
+	}
+
+	var er exportUsersRes
+	if err := json.Unmarshal(body, &er); err != nil {
+		return []UserRecord{}, err
+	}
+
+	return er.Users, nil
+}
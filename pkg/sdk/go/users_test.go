@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
-	"regexp"
 	"testing"
 
 	"github.com/mainflux/mainflux"
@@ -27,7 +26,7 @@ const (
 )
 
 var (
-	passRegex = regexp.MustCompile("^.{8,}$")
+	passPolicy = users.PasswordPolicy{MinLength: 8}
 )
 
 func newUserService() users.Service {
@@ -42,7 +41,10 @@ func newUserService() users.Service {
 	emailer := mocks.NewEmailer()
 	idProvider := uuid.New()
 
-	return users.New(usersRepo, hasher, auth, emailer, idProvider, passRegex)
+	exports := mocks.NewExportRepository()
+	source := mocks.NewExportSource(map[string]interface{}{}, nil)
+
+	return users.New(usersRepo, hasher, auth, emailer, idProvider, exports, mocks.NewInvitationRepository(), mocks.NewActivityRepository(), source, users.Config{ExportKey: []byte("export-key"), PasswordPolicy: passPolicy})
 }
 
 func newUserServer(svc users.Service) *httptest.Server {
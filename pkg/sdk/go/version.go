@@ -31,7 +31,7 @@ func (sdk mfSDK) Version() (string, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", errors.Wrap(ErrFetchVersion, errors.New(resp.Status))
+		return "", errors.Wrap(ErrFetchVersion, statusError(resp))
 	}
 
 	var ver version
@@ -13,6 +13,9 @@ type Message struct {
 	Subtopic  string  `json:"subtopic,omitempty" db:"subtopic" bson:"subtopic,omitempty"`
 	Publisher string  `json:"publisher,omitempty" db:"publisher" bson:"publisher"`
 	Protocol  string  `json:"protocol,omitempty" db:"protocol" bson:"protocol"`
+	Adapter   string  `json:"adapter,omitempty" db:"adapter" bson:"adapter,omitempty"`
+	TraceID   string  `json:"trace_id,omitempty" db:"trace_id" bson:"trace_id,omitempty"`
+	Hops      uint32  `json:"hops,omitempty" db:"hops" bson:"hops,omitempty"`
 	Payload   Payload `json:"payload,omitempty" db:"payload" bson:"payload,omitempty"`
 }
 
@@ -53,6 +53,9 @@ func (ts *transformerService) Transform(msg messaging.Message) (interface{}, err
 		Publisher: msg.Publisher,
 		Created:   msg.Created,
 		Protocol:  msg.Protocol,
+		Adapter:   msg.Adapter,
+		TraceID:   msg.TraceId,
+		Hops:      msg.Hops,
 		Channel:   msg.Channel,
 		Subtopic:  msg.Subtopic,
 	}
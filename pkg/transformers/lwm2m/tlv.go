@@ -0,0 +1,139 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lwm2m
+
+import (
+	"encoding/binary"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// Identifier types of the TLV entries, as specified by the LwM2M TLV format.
+const (
+	ObjectInstance   byte = 0
+	ResourceInstance byte = 1
+	MultipleResource byte = 2
+	Resource         byte = 3
+)
+
+// ErrMalformedTLV indicates that the payload is not valid LwM2M TLV.
+var ErrMalformedTLV = errors.New("malformed LwM2M TLV payload")
+
+// TLV represents a single LwM2M TLV entry. Object instances and multiple
+// resources contain nested entries, while resources and resource instances
+// contain values.
+type TLV struct {
+	Type     byte
+	ID       uint16
+	Value    []byte
+	Children []TLV
+}
+
+// DecodeTLV decodes the LwM2M TLV payload.
+func DecodeTLV(data []byte) ([]TLV, error) {
+	var ret []TLV
+	for len(data) > 0 {
+		tlv, n, err := decodeEntry(data)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, tlv)
+		data = data[n:]
+	}
+	return ret, nil
+}
+
+// EncodeTLV encodes the entries to the LwM2M TLV payload.
+func EncodeTLV(tlvs []TLV) []byte {
+	var ret []byte
+	for _, tlv := range tlvs {
+		value := tlv.Value
+		if tlv.Type == ObjectInstance || tlv.Type == MultipleResource {
+			value = EncodeTLV(tlv.Children)
+		}
+
+		typ := tlv.Type << 6
+		var id []byte
+		if tlv.ID > 0xFF {
+			typ |= 0x20
+			id = []byte{byte(tlv.ID >> 8), byte(tlv.ID)}
+		} else {
+			id = []byte{byte(tlv.ID)}
+		}
+
+		var length []byte
+		switch l := len(value); {
+		case l < 8:
+			typ |= byte(l)
+		case l <= 0xFF:
+			typ |= 0x08
+			length = []byte{byte(l)}
+		case l <= 0xFFFF:
+			typ |= 0x10
+			length = []byte{byte(l >> 8), byte(l)}
+		default:
+			typ |= 0x18
+			length = []byte{byte(l >> 16), byte(l >> 8), byte(l)}
+		}
+
+		ret = append(ret, typ)
+		ret = append(ret, id...)
+		ret = append(ret, length...)
+		ret = append(ret, value...)
+	}
+	return ret
+}
+
+func decodeEntry(data []byte) (TLV, int, error) {
+	typ := data[0]
+	n := 1
+
+	idLen := 1
+	if typ&0x20 != 0 {
+		idLen = 2
+	}
+	if len(data) < n+idLen {
+		return TLV{}, 0, ErrMalformedTLV
+	}
+	var id uint16
+	if idLen == 2 {
+		id = binary.BigEndian.Uint16(data[n:])
+	} else {
+		id = uint16(data[n])
+	}
+	n += idLen
+
+	length := int(typ & 0x07)
+	if lenLen := int(typ>>3) & 0x03; lenLen > 0 {
+		if len(data) < n+lenLen {
+			return TLV{}, 0, ErrMalformedTLV
+		}
+		length = 0
+		for _, b := range data[n : n+lenLen] {
+			length = length<<8 | int(b)
+		}
+		n += lenLen
+	}
+	if len(data) < n+length {
+		return TLV{}, 0, ErrMalformedTLV
+	}
+
+	tlv := TLV{
+		Type: typ >> 6,
+		ID:   id,
+	}
+	value := data[n : n+length]
+	switch tlv.Type {
+	case ObjectInstance, MultipleResource:
+		children, err := DecodeTLV(value)
+		if err != nil {
+			return TLV{}, 0, err
+		}
+		tlv.Children = children
+	default:
+		tlv.Value = value
+	}
+
+	return tlv, n + length, nil
+}
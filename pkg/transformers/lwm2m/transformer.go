@@ -0,0 +1,101 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package lwm2m contains the transformer of the LwM2M TLV payloads.
+package lwm2m
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+// TLVContentType represents the LwM2M TLV content type.
+const TLVContentType = "application/vnd.oma.lwm2m+tlv"
+
+const sep = "/"
+
+var errDecode = errors.New("failed to decode LwM2M TLV")
+
+type transformer struct{}
+
+// New returns the transformer of the LwM2M TLV payloads to SenML messages.
+// Each resource (instance) is transformed to a message named after its path,
+// prefixed by the message subtopic, which is expected to contain the object
+// ID, e.g. "3303/0/5700". Since the TLV payload doesn't carry value types,
+// values are decoded without the object model: values of 1, 2, 4 or 8 bytes
+// are decoded as integers, valid UTF-8 values as strings and the remaining
+// ones are stored as base64 encoded data values.
+func New() transformers.Transformer {
+	return transformer{}
+}
+
+func (t transformer) Transform(msg messaging.Message) (interface{}, error) {
+	tlvs, err := DecodeTLV(msg.Payload)
+	if err != nil {
+		return nil, errors.Wrap(errDecode, err)
+	}
+
+	var prefix []string
+	if msg.Subtopic != "" {
+		prefix = strings.Split(msg.Subtopic, ".")
+	}
+
+	base := senml.Message{
+		Channel:   msg.Channel,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Time:      float64(msg.Created) / float64(1e9),
+	}
+	return flatten(base, prefix, tlvs), nil
+}
+
+func flatten(base senml.Message, path []string, tlvs []TLV) []senml.Message {
+	var ret []senml.Message
+	for _, tlv := range tlvs {
+		p := append(append([]string{}, path...), strconv.Itoa(int(tlv.ID)))
+		if tlv.Children != nil {
+			ret = append(ret, flatten(base, p, tlv.Children)...)
+			continue
+		}
+
+		msg := base
+		msg.Name = strings.Join(p, sep)
+		setValue(&msg, tlv.Value)
+		ret = append(ret, msg)
+	}
+	return ret
+}
+
+func setValue(msg *senml.Message, value []byte) {
+	switch len(value) {
+	case 1, 2, 4, 8:
+		var v int64
+		for i, b := range value {
+			if i == 0 {
+				v = int64(int8(b))
+				continue
+			}
+			v = v<<8 | int64(b)
+		}
+		f := float64(v)
+		msg.Value = &f
+		return
+	}
+
+	if utf8.Valid(value) {
+		s := string(value)
+		msg.StringValue = &s
+		return
+	}
+
+	d := base64.StdEncoding.EncodeToString(value)
+	msg.DataValue = &d
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package lwm2m_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers/lwm2m"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransform(t *testing.T) {
+	payload := lwm2m.EncodeTLV([]lwm2m.TLV{
+		{
+			Type: lwm2m.ObjectInstance,
+			ID:   0,
+			Children: []lwm2m.TLV{
+				{Type: lwm2m.Resource, ID: 5700, Value: []byte{0xFF, 0xF6}},
+				{Type: lwm2m.Resource, ID: 5701, Value: []byte("Cel")},
+				{
+					Type: lwm2m.MultipleResource,
+					ID:   6,
+					Children: []lwm2m.TLV{
+						{Type: lwm2m.ResourceInstance, ID: 0, Value: []byte{0x01}},
+						{Type: lwm2m.ResourceInstance, ID: 1, Value: []byte{0xC3, 0x28, 0x00}},
+					},
+				},
+			},
+		},
+	})
+
+	msg := messaging.Message{
+		Channel:   "channel",
+		Subtopic:  "3303",
+		Publisher: "publisher",
+		Protocol:  "coap",
+		Payload:   payload,
+		Created:   2e9,
+	}
+
+	value, unit, data := -10.0, "Cel", base64.StdEncoding.EncodeToString([]byte{0xC3, 0x28, 0x00})
+	one := 1.0
+	base := senml.Message{
+		Channel:   "channel",
+		Subtopic:  "3303",
+		Publisher: "publisher",
+		Protocol:  "coap",
+		Time:      2,
+	}
+	expected := []senml.Message{base, base, base, base}
+	expected[0].Name, expected[0].Value = "3303/0/5700", &value
+	expected[1].Name, expected[1].StringValue = "3303/0/5701", &unit
+	expected[2].Name, expected[2].Value = "3303/0/6/0", &one
+	expected[3].Name, expected[3].DataValue = "3303/0/6/1", &data
+
+	malformed := msg
+	malformed.Payload = []byte{0xC8, 0x00, 0x0A, 0x01}
+
+	cases := []struct {
+		desc string
+		msg  messaging.Message
+		msgs interface{}
+		err  error
+	}{
+		{
+			desc: "transform LwM2M TLV payload",
+			msg:  msg,
+			msgs: expected,
+		},
+		{
+			desc: "transform malformed LwM2M TLV payload",
+			msg:  malformed,
+			msgs: nil,
+			err:  lwm2m.ErrMalformedTLV,
+		},
+	}
+
+	tr := lwm2m.New()
+	for _, tc := range cases {
+		msgs, err := tr.Transform(tc.msg)
+		assert.Equal(t, tc.msgs, msgs, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.msgs, msgs))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestDecodeTLV(t *testing.T) {
+	value := make([]byte, 300)
+	tlvs := []lwm2m.TLV{
+		{Type: lwm2m.Resource, ID: 1, Value: []byte{0x01}},
+		{Type: lwm2m.Resource, ID: 300, Value: value},
+	}
+
+	decoded, err := lwm2m.DecodeTLV(lwm2m.EncodeTLV(tlvs))
+	assert.Nil(t, err, fmt.Sprintf("decoding encoded TLV: unexpected error %s", err))
+	assert.Equal(t, tlvs, decoded, fmt.Sprintf("decoding encoded TLV: expected %v got %v", tlvs, decoded))
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redaction contains the transformer decorator which masks or hashes
+// the configured fields of the transformed messages.
+package redaction
@@ -0,0 +1,219 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redaction
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	ejson "encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers"
+	"github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+// Action represents the way the field value is redacted.
+type Action string
+
+const (
+	// Mask replaces the field value with the mask.
+	Mask Action = "mask"
+	// Hash replaces the field value with its salted SHA-256 hash, which
+	// keeps the redacted values comparable.
+	Hash Action = "hash"
+)
+
+const (
+	defMask  = "***"
+	fieldSep = "."
+)
+
+// ErrInvalidRule indicates the malformed redaction rule.
+var ErrInvalidRule = errors.New("invalid redaction rule")
+
+// Rule represents the redaction of the fields of the messages published to
+// the channels. Channels and fields are the patterns in the path.Match
+// syntax, e.g. "location.*". Rule applies to all the channels if no channel
+// patterns are provided. Fields are matched against SenML record names and
+// dot separated JSON payload keys.
+type Rule struct {
+	Name     string   `toml:"name"`
+	Channels []string `toml:"channels"`
+	Fields   []string `toml:"fields"`
+	Action   Action   `toml:"action"`
+}
+
+// Config represents the redaction configuration.
+type Config struct {
+	Mask  string `toml:"mask"`
+	Salt  string `toml:"salt"`
+	Rules []Rule `toml:"rules"`
+}
+
+var _ transformers.Transformer = (*redactor)(nil)
+
+type redactor struct {
+	transformer transformers.Transformer
+	mask        string
+	salt        string
+	rules       []Rule
+	logger      logger.Logger
+}
+
+// New returns the transformer redacting the messages transformed by the
+// given transformer. Each message redacted by the rule is audited using
+// the logger.
+func New(t transformers.Transformer, cfg Config, logger logger.Logger) (transformers.Transformer, error) {
+	for _, r := range cfg.Rules {
+		if err := r.validate(); err != nil {
+			return nil, err
+		}
+		logger.Info(fmt.Sprintf("Using redaction rule %s to %s fields %v of channels %v", r.Name, r.Action, r.Fields, r.Channels))
+	}
+
+	mask := cfg.Mask
+	if mask == "" {
+		mask = defMask
+	}
+
+	return &redactor{
+		transformer: t,
+		mask:        mask,
+		salt:        cfg.Salt,
+		rules:       cfg.Rules,
+		logger:      logger,
+	}, nil
+}
+
+func (r Rule) validate() error {
+	if r.Name == "" || len(r.Fields) == 0 {
+		return errors.Wrap(ErrInvalidRule, fmt.Errorf("rule %q without fields", r.Name))
+	}
+
+	if r.Action != Mask && r.Action != Hash {
+		return errors.Wrap(ErrInvalidRule, fmt.Errorf("rule %q with unknown action %q", r.Name, r.Action))
+	}
+
+	for _, p := range append(r.Channels, r.Fields...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return errors.Wrap(ErrInvalidRule, fmt.Errorf("rule %q with invalid pattern %q", r.Name, p))
+		}
+	}
+
+	return nil
+}
+
+func (rd *redactor) Transform(msg messaging.Message) (interface{}, error) {
+	m, err := rd.transformer.Transform(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, r := range rd.rules {
+		if len(r.Channels) > 0 && !matches(r.Channels, msg.Channel) {
+			continue
+		}
+
+		var n int
+		switch m := m.(type) {
+		case []senml.Message:
+			for i := range m {
+				if matches(r.Fields, m[i].Name) {
+					rd.redactSenML(r.Action, &m[i])
+					n++
+				}
+			}
+		case json.Messages:
+			for _, jm := range m.Data {
+				n += rd.redactPayload(r, "", jm.Payload)
+			}
+		}
+
+		if n > 0 {
+			rd.logger.Info(fmt.Sprintf("Redaction rule %s applied %s to %d fields of message from channel %s published by %s", r.Name, r.Action, n, msg.Channel, msg.Publisher))
+		}
+	}
+
+	return m, nil
+}
+
+func (rd *redactor) redactSenML(action Action, m *senml.Message) {
+	var v string
+	switch {
+	case m.Value != nil:
+		v = strconv.FormatFloat(*m.Value, 'f', -1, 64)
+	case m.StringValue != nil:
+		v = *m.StringValue
+	case m.DataValue != nil:
+		v = *m.DataValue
+	case m.BoolValue != nil:
+		v = strconv.FormatBool(*m.BoolValue)
+	case m.Sum != nil:
+		v = strconv.FormatFloat(*m.Sum, 'f', -1, 64)
+	}
+
+	redacted := rd.redact(action, v)
+	m.StringValue = &redacted
+	m.Value = nil
+	m.DataValue = nil
+	m.BoolValue = nil
+	m.Sum = nil
+}
+
+// redactPayload redacts the payload fields matching the rule and returns
+// the number of redacted fields. Nested objects are matched using the dot
+// separated keys.
+func (rd *redactor) redactPayload(r Rule, prefix string, p map[string]interface{}) int {
+	var n int
+	for k, v := range p {
+		key := k
+		if prefix != "" {
+			key = prefix + fieldSep + k
+		}
+
+		if matches(r.Fields, key) {
+			p[k] = rd.redact(r.Action, v)
+			n++
+			continue
+		}
+
+		if nested, ok := v.(map[string]interface{}); ok {
+			n += rd.redactPayload(r, key, nested)
+		}
+	}
+	return n
+}
+
+func (rd *redactor) redact(action Action, v interface{}) string {
+	if action == Mask {
+		return rd.mask
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		data, err := ejson.Marshal(v)
+		if err != nil {
+			return rd.mask
+		}
+		s = string(data)
+	}
+
+	sum := sha256.Sum256([]byte(rd.salt + s))
+	return hex.EncodeToString(sum[:])
+}
+
+func matches(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
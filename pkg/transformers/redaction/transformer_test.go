@@ -0,0 +1,163 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redaction_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers/json"
+	"github.com/mainflux/mainflux/pkg/transformers/redaction"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	channel = "channel"
+	salt    = "salt"
+)
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(salt + s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestNew(t *testing.T) {
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		rule redaction.Rule
+		err  error
+	}{
+		{
+			desc: "create redactor with valid rule",
+			rule: redaction.Rule{Name: "email", Fields: []string{"email"}, Action: redaction.Hash},
+			err:  nil,
+		},
+		{
+			desc: "create redactor with rule without fields",
+			rule: redaction.Rule{Name: "email", Action: redaction.Hash},
+			err:  redaction.ErrInvalidRule,
+		},
+		{
+			desc: "create redactor with rule with unknown action",
+			rule: redaction.Rule{Name: "email", Fields: []string{"email"}, Action: "drop"},
+			err:  redaction.ErrInvalidRule,
+		},
+		{
+			desc: "create redactor with rule with invalid pattern",
+			rule: redaction.Rule{Name: "email", Channels: []string{"["}, Fields: []string{"email"}, Action: redaction.Mask},
+			err:  redaction.ErrInvalidRule,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := redaction.New(senml.New(senml.JSON), redaction.Config{Rules: []redaction.Rule{tc.rule}}, logger)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestTransformSenML(t *testing.T) {
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cfg := redaction.Config{
+		Salt: salt,
+		Rules: []redaction.Rule{
+			{Name: "location", Channels: []string{channel}, Fields: []string{"lat", "lon"}, Action: redaction.Mask},
+			{Name: "owner", Fields: []string{"owner"}, Action: redaction.Hash},
+		},
+	}
+	tr, err := redaction.New(senml.New(senml.JSON), cfg, logger)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	payload := []byte(`[{"n":"lat","v":44.8},{"n":"owner","vs":"john@example.com"},{"n":"temp","v":20}]`)
+	mask, owner, temp := "***", hash("john@example.com"), 20.0
+
+	cases := []struct {
+		desc    string
+		channel string
+		values  []senml.Message
+	}{
+		{
+			desc:    "redact messages of matching channel",
+			channel: channel,
+			values: []senml.Message{
+				{Name: "lat", StringValue: &mask},
+				{Name: "owner", StringValue: &owner},
+				{Name: "temp", Value: &temp},
+			},
+		},
+		{
+			desc:    "redact messages of other channel",
+			channel: "other",
+			values: []senml.Message{
+				{Name: "lat", Value: func() *float64 { v := 44.8; return &v }()},
+				{Name: "owner", StringValue: &owner},
+				{Name: "temp", Value: &temp},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		msg := messaging.Message{Channel: tc.channel, Payload: payload}
+		res, err := tr.Transform(msg)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		msgs, ok := res.([]senml.Message)
+		require.True(t, ok, fmt.Sprintf("%s: expected SenML messages", tc.desc))
+		for i, m := range msgs {
+			assert.Equal(t, tc.values[i].Name, m.Name, fmt.Sprintf("%s: unexpected name", tc.desc))
+			assert.Equal(t, tc.values[i].Value, m.Value, fmt.Sprintf("%s: unexpected value of %s", tc.desc, m.Name))
+			assert.Equal(t, tc.values[i].StringValue, m.StringValue, fmt.Sprintf("%s: unexpected string value of %s", tc.desc, m.Name))
+		}
+	}
+}
+
+func TestTransformJSON(t *testing.T) {
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cfg := redaction.Config{
+		Mask: "REDACTED",
+		Salt: salt,
+		Rules: []redaction.Rule{
+			{Name: "contact", Fields: []string{"contact.*"}, Action: redaction.Mask},
+			{Name: "id", Fields: []string{"id"}, Action: redaction.Hash},
+		},
+	}
+	tr, err := redaction.New(json.New(nil), cfg, logger)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msg := messaging.Message{
+		Channel:  channel,
+		Subtopic: "readings",
+		Payload:  []byte(`{"id":123,"temp":20,"contact":{"email":"john@example.com","phone":"123"}}`),
+	}
+	res, err := tr.Transform(msg)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	msgs, ok := res.(json.Messages)
+	require.True(t, ok, "expected JSON messages")
+	require.Equal(t, 1, len(msgs.Data), "expected single JSON message")
+
+	expected := json.Payload{
+		"id":   hash("123"),
+		"temp": float64(20),
+		"contact": map[string]interface{}{
+			"email": "REDACTED",
+			"phone": "REDACTED",
+		},
+	}
+	assert.Equal(t, expected, msgs.Data[0].Payload, fmt.Sprintf("expected %v got %v", expected, msgs.Data[0].Payload))
+}
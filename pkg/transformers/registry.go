@@ -0,0 +1,80 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+// ErrUnknownContentType indicates that there is no transformer registered
+// for the content type of the message.
+var ErrUnknownContentType = errors.New("unknown message content type")
+
+var _ Transformer = (*Registry)(nil)
+
+// Registry maps content types to the transformers decoding them. Registry is
+// a Transformer itself, which resolves the transformer of each message at
+// runtime using the message content type. Messages without content type, or
+// with the content type which is not registered, are transformed using the
+// default transformer.
+type Registry struct {
+	mu     sync.RWMutex
+	codecs map[string]Transformer
+	def    Transformer
+}
+
+// NewRegistry returns the empty registry using the default transformer for
+// the messages of unknown content types. If the default transformer is nil,
+// transforming such messages fails with ErrUnknownContentType.
+func NewRegistry(def Transformer) *Registry {
+	return &Registry{
+		codecs: make(map[string]Transformer),
+		def:    def,
+	}
+}
+
+// Register registers the transformer for the content type, replacing the
+// transformer previously registered for it.
+func (r *Registry) Register(contentType string, t Transformer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.codecs[normalize(contentType)] = t
+}
+
+// Lookup returns the transformer registered for the content type.
+func (r *Registry) Lookup(contentType string) (Transformer, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.codecs[normalize(contentType)]
+	return t, ok
+}
+
+// Transform transforms the message using the transformer registered for its
+// content type.
+func (r *Registry) Transform(msg messaging.Message) (interface{}, error) {
+	t, ok := r.Lookup(msg.ContentType)
+	if !ok {
+		t = r.def
+	}
+	if t == nil {
+		return nil, ErrUnknownContentType
+	}
+
+	return t.Transform(msg)
+}
+
+// normalize strips the parameters from the content type, so that e.g.
+// "application/json; charset=utf-8" resolves to "application/json".
+func normalize(contentType string) string {
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package transformers_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/pkg/transformers"
+	"github.com/stretchr/testify/assert"
+)
+
+type transformerMock struct {
+	name string
+}
+
+func (tm transformerMock) Transform(msg messaging.Message) (interface{}, error) {
+	return tm.name, nil
+}
+
+func TestTransform(t *testing.T) {
+	withDefault := transformers.NewRegistry(transformerMock{name: "default"})
+	withDefault.Register("application/senml+json", transformerMock{name: "senml"})
+	withDefault.Register("Application/JSON", transformerMock{name: "json"})
+
+	withoutDefault := transformers.NewRegistry(nil)
+	withoutDefault.Register("application/json", transformerMock{name: "json"})
+
+	cases := []struct {
+		desc        string
+		registry    *transformers.Registry
+		contentType string
+		res         interface{}
+		err         error
+	}{
+		{
+			desc:        "transform message with registered content type",
+			registry:    withDefault,
+			contentType: "application/senml+json",
+			res:         "senml",
+		},
+		{
+			desc:        "transform message with registered content type in different case",
+			registry:    withDefault,
+			contentType: "application/json",
+			res:         "json",
+		},
+		{
+			desc:        "transform message with registered content type with parameters",
+			registry:    withDefault,
+			contentType: "application/json; charset=utf-8",
+			res:         "json",
+		},
+		{
+			desc:        "transform message without content type",
+			registry:    withDefault,
+			contentType: "",
+			res:         "default",
+		},
+		{
+			desc:        "transform message with unknown content type",
+			registry:    withDefault,
+			contentType: "text/plain",
+			res:         "default",
+		},
+		{
+			desc:        "transform message with unknown content type without default transformer",
+			registry:    withoutDefault,
+			contentType: "text/plain",
+			res:         nil,
+			err:         transformers.ErrUnknownContentType,
+		},
+	}
+
+	for _, tc := range cases {
+		res, err := tc.registry.Transform(messaging.Message{ContentType: tc.contentType})
+		assert.Equal(t, tc.res, res, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.res, res))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
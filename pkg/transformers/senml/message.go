@@ -6,6 +6,9 @@ type Message struct {
 	Subtopic    string   `json:"subtopic,omitempty" db:"subtopic" bson:"subtopic,omitempty"`
 	Publisher   string   `json:"publisher,omitempty" db:"publisher" bson:"publisher"`
 	Protocol    string   `json:"protocol,omitempty" db:"protocol" bson:"protocol"`
+	Adapter     string   `json:"adapter,omitempty" db:"adapter" bson:"adapter,omitempty"`
+	TraceID     string   `json:"trace_id,omitempty" db:"trace_id" bson:"trace_id,omitempty"`
+	Hops        uint32   `json:"hops,omitempty" db:"hops" bson:"hops,omitempty"`
 	Name        string   `json:"name,omitempty" db:"name" bson:"name,omitempty"`
 	Unit        string   `json:"unit,omitempty" db:"unit" bson:"unit,omitempty"`
 	Time        float64  `json:"time,omitempty" db:"time" bson:"time,omitempty"`
@@ -68,6 +68,9 @@ func (t transformer) Transform(msg messaging.Message) (interface{}, error) {
 			Subtopic:    msg.Subtopic,
 			Publisher:   msg.Publisher,
 			Protocol:    msg.Protocol,
+			Adapter:     msg.Adapter,
+			TraceID:     msg.TraceId,
+			Hops:        msg.Hops,
 			Name:        v.Name,
 			Unit:        v.Unit,
 			Time:        t,
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/provision"
@@ -44,3 +45,60 @@ func getMapping(svc provision.Service) endpoint.Endpoint {
 		return svc.Mapping(req.token)
 	}
 }
+
+func heartbeatEndpoint(svc provision.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(heartbeatReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		hb := provision.Heartbeat{
+			Version: req.Version,
+			Uptime:  time.Duration(req.Uptime) * time.Second,
+			Devices: req.Devices,
+		}
+		if err := svc.Heartbeat(ctx, req.externalID, req.externalKey, hb); err != nil {
+			return nil, err
+		}
+
+		return heartbeatRes{}, nil
+	}
+}
+
+func listGatewaysEndpoint(svc provision.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(fleetReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		statuses, err := svc.Fleet(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := fleetRes{
+			Total:    len(statuses),
+			Gateways: []gatewayRes{},
+		}
+		for _, st := range statuses {
+			gw := gatewayRes{
+				ID:         st.GatewayID,
+				ExternalID: st.ExternalID,
+				Version:    st.Version,
+				Uptime:     int64(st.Uptime / time.Second),
+				Devices:    st.Devices,
+				LastSeen:   st.ReceivedAt,
+				Stale:      st.Stale,
+			}
+			if !st.AlertedAt.IsZero() {
+				alertedAt := st.AlertedAt
+				gw.AlertedAt = &alertedAt
+			}
+			res.Gateways = append(res.Gateways, gw)
+		}
+
+		return res, nil
+	}
+}
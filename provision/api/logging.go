@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -58,3 +59,42 @@ func (lm *loggingMiddleware) Mapping(token string) (res map[string]interface{},
 
 	return lm.svc.Mapping(token)
 }
+
+func (lm *loggingMiddleware) Heartbeat(ctx context.Context, externalID, externalKey string, hb provision.Heartbeat) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method heartbeat for gateway: %s took %s to complete", externalID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors", message))
+	}(time.Now())
+
+	return lm.svc.Heartbeat(ctx, externalID, externalKey, hb)
+}
+
+func (lm *loggingMiddleware) Fleet(ctx context.Context, token string) (gws []provision.GatewayStatus, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method fleet for token: %s took %s to complete", token, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors", message))
+	}(time.Now())
+
+	return lm.svc.Fleet(ctx, token)
+}
+
+func (lm *loggingMiddleware) CheckFleet(ctx context.Context) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method check_fleet took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s", message, err))
+			return
+		}
+		lm.logger.Debug(fmt.Sprintf("%s without errors", message))
+	}(time.Now())
+
+	return lm.svc.CheckFleet(ctx)
+}
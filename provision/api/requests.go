@@ -26,3 +26,32 @@ func (req mappingReq) validate() error {
 	}
 	return nil
 }
+
+type heartbeatReq struct {
+	externalKey string
+	externalID  string
+	Version     string `json:"version"`
+	Uptime      int64  `json:"uptime"`
+	Devices     int    `json:"devices"`
+}
+
+func (req heartbeatReq) validate() error {
+	if req.externalKey == "" {
+		return errUnauthorized
+	}
+	if req.externalID == "" || req.Uptime < 0 || req.Devices < 0 {
+		return errors.ErrMalformedEntity
+	}
+	return nil
+}
+
+type fleetReq struct {
+	token string
+}
+
+func (req fleetReq) validate() error {
+	if req.token == "" {
+		return errUnauthorized
+	}
+	return nil
+}
@@ -35,3 +35,36 @@ func TestValidate(t *testing.T) {
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected `%v` got `%v`", desc, tc.err, err))
 	}
 }
+
+func TestHeartbeatValidate(t *testing.T) {
+	cases := map[string]struct {
+		req heartbeatReq
+		err error
+	}{
+		"valid heartbeat": {
+			req: heartbeatReq{externalKey: "key12345678", externalID: "11:22:33:44:55:66", Version: "0.1.0", Uptime: 60, Devices: 2},
+			err: nil,
+		},
+		"heartbeat with empty external key": {
+			req: heartbeatReq{externalID: "11:22:33:44:55:66"},
+			err: errUnauthorized,
+		},
+		"heartbeat with empty external id": {
+			req: heartbeatReq{externalKey: "key12345678"},
+			err: errors.ErrMalformedEntity,
+		},
+		"heartbeat with negative uptime": {
+			req: heartbeatReq{externalKey: "key12345678", externalID: "11:22:33:44:55:66", Uptime: -1},
+			err: errors.ErrMalformedEntity,
+		},
+		"heartbeat with negative devices count": {
+			req: heartbeatReq{externalKey: "key12345678", externalID: "11:22:33:44:55:66", Devices: -1},
+			err: errors.ErrMalformedEntity,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := tc.req.validate()
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected `%v` got `%v`", desc, tc.err, err))
+	}
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	SDK "github.com/mainflux/mainflux/pkg/sdk/go"
 )
@@ -27,3 +28,45 @@ func (res provisionRes) Headers() map[string]string {
 func (res provisionRes) Empty() bool {
 	return false
 }
+
+type heartbeatRes struct{}
+
+func (res heartbeatRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res heartbeatRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res heartbeatRes) Empty() bool {
+	return true
+}
+
+type gatewayRes struct {
+	ID         string     `json:"id"`
+	ExternalID string     `json:"external_id"`
+	Version    string     `json:"version"`
+	Uptime     int64      `json:"uptime"`
+	Devices    int        `json:"devices"`
+	LastSeen   time.Time  `json:"last_seen"`
+	Stale      bool       `json:"stale"`
+	AlertedAt  *time.Time `json:"alerted_at,omitempty"`
+}
+
+type fleetRes struct {
+	Total    int          `json:"total"`
+	Gateways []gatewayRes `json:"gateways"`
+}
+
+func (res fleetRes) Code() int {
+	return http.StatusOK
+}
+
+func (res fleetRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res fleetRes) Empty() bool {
+	return false
+}
@@ -46,6 +46,20 @@ func MakeHandler(svc provision.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/heartbeat/:id", kithttp.NewServer(
+		heartbeatEndpoint(svc),
+		decodeHeartbeatRequest,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/gateways", kithttp.NewServer(
+		listGatewaysEndpoint(svc),
+		decodeFleetRequest,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Handle("/metrics", promhttp.Handler())
 	r.GetFunc("/version", mainflux.Version("provision"))
 
@@ -93,15 +107,40 @@ func decodeMappingRequest(_ context.Context, r *http.Request) (interface{}, erro
 	return req, nil
 }
 
+func decodeHeartbeatRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	if r.Header.Get("Content-Type") != contentType {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := heartbeatReq{
+		externalKey: r.Header.Get("Authorization"),
+		externalID:  bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeFleetRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	req := fleetReq{token: r.Header.Get("Authorization")}
+
+	return req, nil
+}
+
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 
-	switch err {
-	case errors.ErrUnsupportedContentType:
+	switch {
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
 		w.WriteHeader(http.StatusUnsupportedMediaType)
-	case io.EOF, errors.ErrMalformedEntity:
+	case err == io.EOF, errors.Contains(err, errors.ErrMalformedEntity):
 		w.WriteHeader(http.StatusBadRequest)
-	case errConflict:
+	case errors.Contains(err, errUnauthorized),
+		errors.Contains(err, provision.ErrUnauthorized):
+		w.WriteHeader(http.StatusUnauthorized)
+	case err == errConflict:
 		w.WriteHeader(http.StatusConflict)
 	default:
 		switch err.(type) {
@@ -60,6 +60,13 @@ type Certs struct {
 	KeyType    string `json:"key_type"`
 }
 
+// Fleet represents the gateway fleet monitoring config.
+type Fleet struct {
+	StaleAfter    string `json:"stale_after" toml:"stale_after"`
+	CheckInterval string `json:"check_interval" toml:"check_interval"`
+	AlertChannel  string `json:"alert_channel" toml:"alert_channel"`
+}
+
 // Config struct of Provision
 type Config struct {
 	File      string      `toml:"file"`
@@ -68,6 +75,7 @@ type Config struct {
 	Things    []Thing     `toml:"things" mapstructure:"things"`
 	Channels  []Channel   `toml:"channels" mapstructure:"channels"`
 	Certs     Certs       `toml:"certs" mapstructure:"certs"`
+	Fleet     Fleet       `toml:"fleet" mapstructure:"fleet"`
 }
 
 // Save - store config in a file
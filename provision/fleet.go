@@ -0,0 +1,190 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package provision
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
+)
+
+const (
+	defStaleAfter = 5 * time.Minute
+
+	fleetProtocol     = "provision"
+	staleSubtopic     = "gateways.stale"
+	recoveredSubtopic = "gateways.recovered"
+)
+
+var (
+	// ErrNotFound indicates that the gateway hasn't sent any heartbeat.
+	ErrNotFound = errors.New("gateway heartbeat not found")
+
+	// ErrFailedHeartbeat indicates that the heartbeat couldn't be saved.
+	ErrFailedHeartbeat = errors.New("failed to save gateway heartbeat")
+
+	// ErrFailedFleetRetrieval indicates that the fleet status couldn't be retrieved.
+	ErrFailedFleetRetrieval = errors.New("failed to retrieve fleet status")
+)
+
+// Heartbeat represents the periodic report sent by the gateway.
+type Heartbeat struct {
+	GatewayID  string        `json:"gateway_id"`
+	ExternalID string        `json:"external_id"`
+	Version    string        `json:"version"`
+	Uptime     time.Duration `json:"uptime"`
+	Devices    int           `json:"devices"`
+	ReceivedAt time.Time     `json:"received_at"`
+
+	// AlertedAt is the time the gateway was reported as stale, or zero if
+	// the gateway hasn't been reported since its last heartbeat.
+	AlertedAt time.Time `json:"alerted_at,omitempty"`
+}
+
+// GatewayStatus represents the status of the gateway based on its last
+// heartbeat.
+type GatewayStatus struct {
+	Heartbeat
+	Stale bool `json:"stale"`
+}
+
+// FleetRepository specifies the gateway heartbeats persistence API.
+type FleetRepository interface {
+	// Save stores the last heartbeat of the gateway, replacing the previous one.
+	Save(ctx context.Context, hb Heartbeat) error
+
+	// Retrieve retrieves the last heartbeat of the gateway with the given ID.
+	Retrieve(ctx context.Context, gatewayID string) (Heartbeat, error)
+
+	// RetrieveAll retrieves the last heartbeats of all the gateways.
+	RetrieveAll(ctx context.Context) ([]Heartbeat, error)
+}
+
+// gatewayAlert represents the payload of the stale and recovered gateway
+// messages sent to the alert channel.
+type gatewayAlert struct {
+	GatewayID  string    `json:"gateway_id"`
+	ExternalID string    `json:"external_id"`
+	Version    string    `json:"version"`
+	LastSeen   time.Time `json:"last_seen"`
+}
+
+func (ps *provisionService) Heartbeat(ctx context.Context, externalID, externalKey string, hb Heartbeat) error {
+	bs, err := ps.sdk.Bootstrap(externalKey, externalID)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorized, err)
+	}
+
+	prev, err := ps.fleet.Retrieve(ctx, bs.MFThing)
+	if err != nil && !errors.Contains(err, ErrNotFound) {
+		return errors.Wrap(ErrFailedHeartbeat, err)
+	}
+
+	hb.GatewayID = bs.MFThing
+	hb.ExternalID = externalID
+	hb.ReceivedAt = time.Now()
+	if err := ps.fleet.Save(ctx, hb); err != nil {
+		return errors.Wrap(ErrFailedHeartbeat, err)
+	}
+
+	if !prev.AlertedAt.IsZero() {
+		ps.alert(recoveredSubtopic, hb)
+	}
+
+	return nil
+}
+
+func (ps *provisionService) Fleet(ctx context.Context, token string) ([]GatewayStatus, error) {
+	things, err := ps.sdk.ThingsPages(token, "", 0).All()
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorized, err)
+	}
+
+	hbs, err := ps.fleet.RetrieveAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(ErrFailedFleetRetrieval, err)
+	}
+
+	owned := make(map[string]bool, len(things))
+	for _, th := range things {
+		owned[th.ID] = true
+	}
+
+	statuses := []GatewayStatus{}
+	for _, hb := range hbs {
+		if !owned[hb.GatewayID] {
+			continue
+		}
+		statuses = append(statuses, GatewayStatus{
+			Heartbeat: hb,
+			Stale:     ps.stale(hb),
+		})
+	}
+
+	return statuses, nil
+}
+
+func (ps *provisionService) CheckFleet(ctx context.Context) error {
+	hbs, err := ps.fleet.RetrieveAll(ctx)
+	if err != nil {
+		return errors.Wrap(ErrFailedFleetRetrieval, err)
+	}
+
+	for _, hb := range hbs {
+		if !ps.stale(hb) || !hb.AlertedAt.IsZero() {
+			continue
+		}
+		if !ps.alert(staleSubtopic, hb) {
+			continue
+		}
+		hb.AlertedAt = time.Now()
+		if err := ps.fleet.Save(ctx, hb); err != nil {
+			return errors.Wrap(ErrFailedHeartbeat, err)
+		}
+	}
+
+	return nil
+}
+
+func (ps *provisionService) stale(hb Heartbeat) bool {
+	return time.Since(hb.ReceivedAt) > ps.staleAfter
+}
+
+// alert publishes the gateway status change to the alert channel, so it can
+// be picked up by the notifiers. It returns false if the alert wasn't sent.
+func (ps *provisionService) alert(subtopic string, hb Heartbeat) bool {
+	if ps.pub == nil || ps.conf.Fleet.AlertChannel == "" {
+		return false
+	}
+
+	payload, err := json.Marshal(gatewayAlert{
+		GatewayID:  hb.GatewayID,
+		ExternalID: hb.ExternalID,
+		Version:    hb.Version,
+		LastSeen:   hb.ReceivedAt,
+	})
+	if err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to encode %s alert for gateway %s: %s", subtopic, hb.GatewayID, err))
+		return false
+	}
+
+	msg := messaging.Message{
+		Channel:   ps.conf.Fleet.AlertChannel,
+		Subtopic:  subtopic,
+		Publisher: hb.GatewayID,
+		Protocol:  fleetProtocol,
+		Payload:   payload,
+		Created:   time.Now().UnixNano(),
+	}
+	if err := ps.pub.Publish(msg.Channel, msg); err != nil {
+		ps.logger.Warn(fmt.Sprintf("Failed to publish %s alert for gateway %s: %s", subtopic, hb.GatewayID, err))
+		return false
+	}
+
+	return true
+}
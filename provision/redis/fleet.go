@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/provision"
+)
+
+const gatewaysKey = "gateways"
+
+var _ provision.FleetRepository = (*fleetRepository)(nil)
+
+type fleetRepository struct {
+	client *redis.Client
+}
+
+// NewFleetRepository returns redis gateway heartbeats repository
+// implementation. The last heartbeat of each gateway is stored in a single
+// hash, keyed by the gateway ID.
+func NewFleetRepository(client *redis.Client) provision.FleetRepository {
+	return &fleetRepository{
+		client: client,
+	}
+}
+
+func (fr *fleetRepository) Save(ctx context.Context, hb provision.Heartbeat) error {
+	data, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+
+	return fr.client.HSet(ctx, gatewaysKey, hb.GatewayID, data).Err()
+}
+
+func (fr *fleetRepository) Retrieve(ctx context.Context, gatewayID string) (provision.Heartbeat, error) {
+	data, err := fr.client.HGet(ctx, gatewaysKey, gatewayID).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return provision.Heartbeat{}, errors.Wrap(provision.ErrNotFound, err)
+		}
+		return provision.Heartbeat{}, err
+	}
+
+	var hb provision.Heartbeat
+	if err := json.Unmarshal(data, &hb); err != nil {
+		return provision.Heartbeat{}, err
+	}
+
+	return hb, nil
+}
+
+func (fr *fleetRepository) RetrieveAll(ctx context.Context) ([]provision.Heartbeat, error) {
+	vals, err := fr.client.HVals(ctx, gatewaysKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	hbs := make([]provision.Heartbeat, 0, len(vals))
+	for _, val := range vals {
+		var hb provision.Heartbeat
+		if err := json.Unmarshal([]byte(val), &hb); err != nil {
+			return nil, err
+		}
+		hbs = append(hbs, hb)
+	}
+
+	return hbs, nil
+}
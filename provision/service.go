@@ -1,11 +1,14 @@
 package provision
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/mainflux/mainflux/logger"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/messaging"
 	SDK "github.com/mainflux/mainflux/pkg/sdk/go"
 )
 
@@ -59,12 +62,29 @@ type Service interface {
 	// Valid time units are "ns", "us" (or "µs"), "ms", "s", "m", "h".
 	// keyBits for certificate key
 	Cert(token, thingID, duration string, keyBits int) (string, string, error)
+
+	// Heartbeat saves the heartbeat sent by the gateway authenticated by
+	// its external ID and key. If the gateway was reported as stale, the
+	// recovery is reported to the alert channel.
+	Heartbeat(ctx context.Context, externalID, externalKey string, hb Heartbeat) error
+
+	// Fleet retrieves the status of the gateways owned by the user
+	// identified by the given token.
+	Fleet(ctx context.Context, token string) ([]GatewayStatus, error)
+
+	// CheckFleet reports the gateways that haven't sent the heartbeat
+	// within the configured period to the alert channel. Each gateway is
+	// reported once until it sends the next heartbeat.
+	CheckFleet(ctx context.Context) error
 }
 
 type provisionService struct {
-	logger logger.Logger
-	sdk    SDK.SDK
-	conf   Config
+	logger     logger.Logger
+	sdk        SDK.SDK
+	fleet      FleetRepository
+	pub        messaging.Publisher
+	conf       Config
+	staleAfter time.Duration
 }
 
 // Result represent what is created with additional info.
@@ -78,12 +98,21 @@ type Result struct {
 	Error       string            `json:"error,omitempty"`
 }
 
-// New returns new provision service.
-func New(cfg Config, sdk SDK.SDK, logger logger.Logger) Service {
+// New returns new provision service. The publisher is used to send the
+// stale gateway alerts and may be nil if the alerts are disabled.
+func New(cfg Config, sdk SDK.SDK, fleet FleetRepository, pub messaging.Publisher, logger logger.Logger) Service {
+	staleAfter, err := time.ParseDuration(cfg.Fleet.StaleAfter)
+	if err != nil || staleAfter <= 0 {
+		staleAfter = defStaleAfter
+	}
+
 	return &provisionService{
-		logger: logger,
-		conf:   cfg,
-		sdk:    sdk,
+		logger:     logger,
+		conf:       cfg,
+		sdk:        sdk,
+		fleet:      fleet,
+		pub:        pub,
+		staleAfter: staleAfter,
 	}
 }
 
@@ -175,11 +204,6 @@ func (ps *provisionService) Provision(token, name, externalID, externalKey strin
 		for _, ch := range channels {
 			chanIDs = append(chanIDs, ch.ID)
 		}
-		content, err := json.Marshal(ps.conf.Bootstrap.Content)
-		if err != nil {
-			return Result{}, errors.Wrap(ErrFailedBootstrap, err)
-		}
-
 		if ps.conf.Bootstrap.Provision && needsBootstrap(thing) {
 			bsReq := SDK.BootstrapConfig{
 				ThingID:     thing.ID,
@@ -189,7 +213,7 @@ func (ps *provisionService) Provision(token, name, externalID, externalKey strin
 				CACert:      res.CACert,
 				ClientCert:  cert.ClientCert,
 				ClientKey:   cert.ClientKey,
-				Content:     string(content),
+				Content:     bootstrapContent(ps.conf.Bootstrap.Content),
 			}
 			bsid, err := ps.sdk.AddBootstrap(token, bsReq)
 			if err != nil {
@@ -398,3 +422,31 @@ func needsBootstrap(th SDK.Thing) bool {
 	}
 	return false
 }
+
+// bootstrapContent splits the configured content into the bootstrap content
+// sections. The network, credentials and app objects become the sections,
+// while the rest of the configured content is added to the app section.
+func bootstrapContent(content map[string]interface{}) *SDK.BootstrapContent {
+	if len(content) == 0 {
+		return nil
+	}
+
+	bc := SDK.BootstrapContent{App: map[string]interface{}{}}
+	for k, v := range content {
+		section, ok := v.(map[string]interface{})
+		switch {
+		case ok && k == "network":
+			bc.Network = section
+		case ok && k == "credentials":
+			bc.Credentials = section
+		case ok && k == "app":
+			for ak, av := range section {
+				bc.App[ak] = av
+			}
+		default:
+			bc.App[k] = v
+		}
+	}
+
+	return &bc
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// +build !test
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	log "github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/query"
+)
+
+var _ query.Service = (*loggingMiddleware)(nil)
+
+type loggingMiddleware struct {
+	logger log.Logger
+	svc    query.Service
+}
+
+// LoggingMiddleware adds logging facilities to the core service.
+func LoggingMiddleware(svc query.Service, logger log.Logger) query.Service {
+	return &loggingMiddleware{logger, svc}
+}
+
+func (lm *loggingMiddleware) Execute(ctx context.Context, token, q string) (res query.Result, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method execute for query %q took %s to complete", q, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s, returning %d rows of %d scanned messages.", message, len(res.Rows), res.Scanned))
+	}(time.Now())
+
+	return lm.svc.Execute(ctx, token, q)
+}
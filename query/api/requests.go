@@ -0,0 +1,23 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import "github.com/mainflux/mainflux/query"
+
+type queryReq struct {
+	token string
+	Query string `json:"query"`
+}
+
+func (req queryReq) validate() error {
+	if req.token == "" {
+		return query.ErrUnauthorizedAccess
+	}
+
+	if req.Query == "" {
+		return query.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/query"
+)
+
+var _ mainflux.Response = (*queryRes)(nil)
+
+type queryRes struct {
+	query.Result
+}
+
+func (res queryRes) Code() int {
+	return http.StatusOK
+}
+
+func (res queryRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res queryRes) Empty() bool {
+	return false
+}
+
+type errorRes struct {
+	Err string `json:"error"`
+}
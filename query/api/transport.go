@@ -0,0 +1,134 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	kitot "github.com/go-kit/kit/tracing/opentracing"
+	kithttp "github.com/go-kit/kit/transport/http"
+	"github.com/go-zoo/bone"
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/query"
+	opentracing "github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	contentType    = "application/json"
+	sqlContentType = "application/sql"
+
+	queryKey = "q"
+)
+
+// MakeHandler returns a HTTP handler for API endpoints.
+func MakeHandler(tracer opentracing.Tracer, svc query.Service) http.Handler {
+	opts := []kithttp.ServerOption{
+		kithttp.ServerErrorEncoder(encodeError),
+	}
+
+	r := bone.New()
+
+	r.Post("/query", kithttp.NewServer(
+		kitot.TraceServer(tracer, "query")(queryEndpoint(svc)),
+		decodePostQuery,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/query", kithttp.NewServer(
+		kitot.TraceServer(tracer, "query")(queryEndpoint(svc)),
+		decodeGetQuery,
+		encodeResponse,
+		opts...,
+	))
+
+	r.GetFunc("/version", mainflux.Version("query"))
+	r.Handle("/metrics", promhttp.Handler())
+
+	return r
+}
+
+func decodePostQuery(_ context.Context, r *http.Request) (interface{}, error) {
+	req := queryReq{token: r.Header.Get("Authorization")}
+
+	ct := r.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(ct, sqlContentType):
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, errors.Wrap(query.ErrMalformedEntity, err)
+		}
+		req.Query = string(body)
+	case strings.Contains(ct, contentType):
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, errors.Wrap(query.ErrMalformedEntity, err)
+		}
+	default:
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	return req, nil
+}
+
+func decodeGetQuery(_ context.Context, r *http.Request) (interface{}, error) {
+	q, err := httputil.ReadStringQuery(r, queryKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	req := queryReq{
+		token: r.Header.Get("Authorization"),
+		Query: q,
+	}
+
+	return req, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	w.Header().Set("Content-Type", contentType)
+
+	if ar, ok := response.(mainflux.Response); ok {
+		for k, v := range ar.Headers() {
+			w.Header().Set(k, v)
+		}
+
+		w.WriteHeader(ar.Code())
+
+		if ar.Empty() {
+			return nil
+		}
+	}
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", contentType)
+
+	switch {
+	case errors.Contains(err, query.ErrUnauthorizedAccess):
+		w.WriteHeader(http.StatusUnauthorized)
+	case errors.Contains(err, query.ErrMalformedEntity),
+		errors.Contains(err, query.ErrSyntax),
+		errors.Contains(err, errors.ErrInvalidQueryParams):
+		w.WriteHeader(http.StatusBadRequest)
+	case errors.Contains(err, query.ErrScanLimit):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	case errors.Contains(err, errors.ErrUnsupportedContentType):
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	if err := json.NewEncoder(w).Encode(errorRes{Err: err.Error()}); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package query contains the domain concept definitions needed to support
+// the query service, which exposes the messages of the channels through the
+// SQL-like query language on top of the reader HTTP API, independently of
+// the storage engine the reader is backed by.
+package query
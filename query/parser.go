@@ -0,0 +1,519 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrSyntax indicates that the query is not a valid statement of the query
+// language or that it uses an unsupported feature.
+var ErrSyntax = errors.New("invalid query syntax")
+
+type kind int
+
+const (
+	textKind kind = iota
+	numberKind
+	boolKind
+)
+
+// columns lists the message fields in the order they are returned by the
+// SELECT * statement.
+var columns = []string{
+	"time",
+	"channel",
+	"subtopic",
+	"publisher",
+	"protocol",
+	"name",
+	"unit",
+	"value",
+	"string_value",
+	"bool_value",
+	"data_value",
+	"sum",
+	"update_time",
+	"adapter",
+	"trace_id",
+	"hops",
+}
+
+var fieldKinds = map[string]kind{
+	"time":         numberKind,
+	"channel":      textKind,
+	"subtopic":     textKind,
+	"publisher":    textKind,
+	"protocol":     textKind,
+	"name":         textKind,
+	"unit":         textKind,
+	"value":        numberKind,
+	"string_value": textKind,
+	"bool_value":   boolKind,
+	"data_value":   textKind,
+	"sum":          numberKind,
+	"update_time":  numberKind,
+	"adapter":      textKind,
+	"trace_id":     textKind,
+	"hops":         numberKind,
+}
+
+const (
+	countFn = "count"
+	sumFn   = "sum"
+	avgFn   = "avg"
+	minFn   = "min"
+	maxFn   = "max"
+)
+
+var aggregates = map[string]bool{
+	countFn: true,
+	sumFn:   true,
+	avgFn:   true,
+	minFn:   true,
+	maxFn:   true,
+}
+
+// statement is a parsed query of the form:
+//
+//	SELECT <selectors> FROM <channel> [WHERE <conditions>]
+//	[GROUP BY time(<duration>)] [LIMIT <n>]
+type statement struct {
+	selectors  []selector
+	channel    string
+	conditions []condition
+	bucket     time.Duration
+	limit      uint64
+}
+
+// aggregated tells whether the statement returns the aggregates instead of
+// the messages.
+func (s statement) aggregated() bool {
+	for _, sel := range s.selectors {
+		if sel.fn != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// selector is the selected field, or the aggregate function of the field.
+// The field of count(*) is "*".
+type selector struct {
+	fn    string
+	field string
+}
+
+// column returns the name of the result column of the selector.
+func (s selector) column() string {
+	if s.fn == "" {
+		return s.field
+	}
+	return fmt.Sprintf("%s(%s)", s.fn, s.field)
+}
+
+// condition compares the message field to the literal. The literal is a
+// float64, string or bool, depending on the kind of the field.
+type condition struct {
+	field string
+	op    string
+	val   interface{}
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokPunct
+	tokWord
+	tokString
+)
+
+type token struct {
+	kind tokenKind
+	val  string
+	pos  int
+}
+
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+func parse(src string) (statement, error) {
+	p := &parser{src: src}
+	if err := p.next(); err != nil {
+		return statement{}, err
+	}
+
+	stmt, err := p.parseStatement()
+	if err != nil {
+		return statement{}, err
+	}
+	if err := stmt.validate(); err != nil {
+		return statement{}, err
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseStatement() (statement, error) {
+	var stmt statement
+	if err := p.expectKeyword("select"); err != nil {
+		return statement{}, err
+	}
+
+	if p.peek(tokPunct, "*") {
+		for _, c := range columns {
+			stmt.selectors = append(stmt.selectors, selector{field: c})
+		}
+		if err := p.next(); err != nil {
+			return statement{}, err
+		}
+	} else {
+		for {
+			sel, err := p.parseSelector()
+			if err != nil {
+				return statement{}, err
+			}
+			stmt.selectors = append(stmt.selectors, sel)
+			if !p.peek(tokPunct, ",") {
+				break
+			}
+			if err := p.next(); err != nil {
+				return statement{}, err
+			}
+		}
+	}
+
+	if err := p.expectKeyword("from"); err != nil {
+		return statement{}, err
+	}
+	if p.tok.kind != tokWord && p.tok.kind != tokString {
+		return statement{}, p.errorf("expected channel, got %q", p.tok.val)
+	}
+	stmt.channel = p.tok.val
+	if err := p.next(); err != nil {
+		return statement{}, err
+	}
+
+	if p.keyword("where") {
+		if err := p.next(); err != nil {
+			return statement{}, err
+		}
+		for {
+			cond, err := p.parseCondition()
+			if err != nil {
+				return statement{}, err
+			}
+			stmt.conditions = append(stmt.conditions, cond)
+			if !p.keyword("and") {
+				break
+			}
+			if err := p.next(); err != nil {
+				return statement{}, err
+			}
+		}
+	}
+
+	if p.keyword("group") {
+		bucket, err := p.parseGroupBy()
+		if err != nil {
+			return statement{}, err
+		}
+		stmt.bucket = bucket
+	}
+
+	if p.keyword("limit") {
+		if err := p.next(); err != nil {
+			return statement{}, err
+		}
+		limit, err := strconv.ParseUint(p.tok.val, 10, 64)
+		if p.tok.kind != tokWord || err != nil || limit == 0 {
+			return statement{}, p.errorf("expected positive limit, got %q", p.tok.val)
+		}
+		stmt.limit = limit
+		if err := p.next(); err != nil {
+			return statement{}, err
+		}
+	}
+
+	if p.tok.kind != tokEOF {
+		return statement{}, p.errorf("unexpected %q", p.tok.val)
+	}
+	return stmt, nil
+}
+
+func (p *parser) parseSelector() (selector, error) {
+	if p.tok.kind != tokWord {
+		return selector{}, p.errorf("expected field, got %q", p.tok.val)
+	}
+	name := strings.ToLower(p.tok.val)
+	if err := p.next(); err != nil {
+		return selector{}, err
+	}
+
+	if !p.peek(tokPunct, "(") {
+		if _, ok := fieldKinds[name]; !ok {
+			return selector{}, p.errorf("unknown field %q", name)
+		}
+		return selector{field: name}, nil
+	}
+
+	if !aggregates[name] {
+		return selector{}, p.errorf("unknown function %q", name)
+	}
+	if err := p.next(); err != nil {
+		return selector{}, err
+	}
+	sel := selector{fn: name}
+	switch {
+	case p.peek(tokPunct, "*") && name == countFn:
+		sel.field = "*"
+	case p.tok.kind == tokWord:
+		sel.field = strings.ToLower(p.tok.val)
+		k, ok := fieldKinds[sel.field]
+		if !ok {
+			return selector{}, p.errorf("unknown field %q", sel.field)
+		}
+		if k != numberKind && name != countFn {
+			return selector{}, p.errorf("function %s requires a numeric field", name)
+		}
+	default:
+		return selector{}, p.errorf("expected field, got %q", p.tok.val)
+	}
+	if err := p.next(); err != nil {
+		return selector{}, err
+	}
+	if err := p.expect(")"); err != nil {
+		return selector{}, err
+	}
+	return sel, nil
+}
+
+func (p *parser) parseCondition() (condition, error) {
+	if p.tok.kind != tokWord {
+		return condition{}, p.errorf("expected field, got %q", p.tok.val)
+	}
+	cond := condition{field: strings.ToLower(p.tok.val)}
+	k, ok := fieldKinds[cond.field]
+	if !ok {
+		return condition{}, p.errorf("unknown field %q", cond.field)
+	}
+	if err := p.next(); err != nil {
+		return condition{}, err
+	}
+
+	switch op := p.tok.val; {
+	case p.tok.kind != tokPunct:
+		return condition{}, p.errorf("expected comparison operator, got %q", op)
+	case op == "=" || op == "!=" || op == "<>":
+		cond.op = op
+		if op == "<>" {
+			cond.op = "!="
+		}
+	case op == "<" || op == "<=" || op == ">" || op == ">=":
+		if k != numberKind {
+			return condition{}, p.errorf("operator %s requires a numeric field", op)
+		}
+		cond.op = op
+	default:
+		return condition{}, p.errorf("expected comparison operator, got %q", op)
+	}
+	if err := p.next(); err != nil {
+		return condition{}, err
+	}
+
+	val, err := p.parseLiteral(cond.field, k)
+	if err != nil {
+		return condition{}, err
+	}
+	cond.val = val
+	return cond, p.next()
+}
+
+// parseLiteral parses the literal compared to the field of the given kind.
+// The time fields are also compared to the RFC3339 timestamps, which are
+// converted to the seconds since the Unix epoch.
+func (p *parser) parseLiteral(field string, k kind) (interface{}, error) {
+	switch k {
+	case numberKind:
+		if p.tok.kind == tokString && (field == "time" || field == "update_time") {
+			t, err := time.Parse(time.RFC3339Nano, p.tok.val)
+			if err != nil {
+				return nil, p.errorf("invalid timestamp %q", p.tok.val)
+			}
+			return float64(t.UnixNano()) / float64(time.Second), nil
+		}
+		v, err := strconv.ParseFloat(p.tok.val, 64)
+		if p.tok.kind != tokWord || err != nil {
+			return nil, p.errorf("expected number, got %q", p.tok.val)
+		}
+		return v, nil
+	case boolKind:
+		v, err := strconv.ParseBool(strings.ToLower(p.tok.val))
+		if p.tok.kind != tokWord || err != nil {
+			return nil, p.errorf("expected true or false, got %q", p.tok.val)
+		}
+		return v, nil
+	default:
+		if p.tok.kind != tokString {
+			return nil, p.errorf("expected string, got %q", p.tok.val)
+		}
+		return p.tok.val, nil
+	}
+}
+
+func (p *parser) parseGroupBy() (time.Duration, error) {
+	if err := p.next(); err != nil {
+		return 0, err
+	}
+	if err := p.expectKeyword("by"); err != nil {
+		return 0, err
+	}
+	if err := p.expectKeyword("time"); err != nil {
+		return 0, err
+	}
+	if err := p.expect("("); err != nil {
+		return 0, err
+	}
+	d, err := time.ParseDuration(p.tok.val)
+	if p.tok.kind != tokWord || err != nil || d <= 0 {
+		return 0, p.errorf("expected positive duration, got %q", p.tok.val)
+	}
+	if err := p.next(); err != nil {
+		return 0, err
+	}
+	if err := p.expect(")"); err != nil {
+		return 0, err
+	}
+	return d, nil
+}
+
+// validate checks that the selectors match the grouping: the aggregated
+// statements can select only the aggregates and the time of the bucket,
+// while the grouped statements have to select at least one aggregate.
+func (s statement) validate() error {
+	if !s.aggregated() {
+		if s.bucket > 0 {
+			return errors.Wrap(ErrSyntax, fmt.Errorf("GROUP BY requires an aggregate function"))
+		}
+		return nil
+	}
+	for _, sel := range s.selectors {
+		if sel.fn != "" {
+			continue
+		}
+		if sel.field != "time" || s.bucket == 0 {
+			return errors.Wrap(ErrSyntax, fmt.Errorf("field %q can't be selected along with the aggregates", sel.field))
+		}
+	}
+	return nil
+}
+
+func (p *parser) keyword(kw string) bool {
+	return p.tok.kind == tokWord && strings.EqualFold(p.tok.val, kw)
+}
+
+func (p *parser) expectKeyword(kw string) error {
+	if !p.keyword(kw) {
+		return p.errorf("expected %s, got %q", strings.ToUpper(kw), p.tok.val)
+	}
+	return p.next()
+}
+
+func (p *parser) peek(kind tokenKind, val string) bool {
+	return p.tok.kind == kind && p.tok.val == val
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.peek(tokPunct, punct) {
+		return p.errorf("expected %q, got %q", punct, p.tok.val)
+	}
+	return p.next()
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	return errors.Wrap(ErrSyntax, fmt.Errorf("%s at position %d", msg, p.tok.pos))
+}
+
+// next reads the next token from the source, skipping white space. The
+// words are the runs of letters, digits and the "_.-:+" characters, so the
+// keywords, field names, channel IDs, numbers and durations are all read
+// as the words and told apart by the parser.
+func (p *parser) next() error {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\n\r", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return nil
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "!="),
+		strings.HasPrefix(p.src[p.pos:], "<>"),
+		strings.HasPrefix(p.src[p.pos:], "<="),
+		strings.HasPrefix(p.src[p.pos:], ">="):
+		p.pos += 2
+		p.tok = token{kind: tokPunct, val: p.src[start:p.pos], pos: start}
+	case strings.IndexByte("(),*=<>", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokPunct, val: string(c), pos: start}
+	case isWordChar(c):
+		for p.pos < len(p.src) && isWordChar(p.src[p.pos]) {
+			p.pos++
+		}
+		p.tok = token{kind: tokWord, val: p.src[start:p.pos], pos: start}
+	case c == '\'':
+		return p.readString()
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.tok = token{kind: tokPunct, val: string(r), pos: start}
+		return p.errorf("unexpected character %q", r)
+	}
+
+	return nil
+}
+
+// readString reads the single quoted string, where the quote is escaped by
+// doubling it, as in SQL.
+func (p *parser) readString() error {
+	start := p.pos
+	p.pos++
+
+	var sb strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+		if c != '\'' {
+			sb.WriteByte(c)
+			continue
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+			sb.WriteByte(c)
+			p.pos++
+			continue
+		}
+		p.tok = token{kind: tokString, val: sb.String(), pos: start}
+		return nil
+	}
+
+	p.tok = token{kind: tokString, pos: start}
+	return p.errorf("unterminated string")
+}
+
+func isWordChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || strings.IndexByte("_.-:+", c) >= 0
+}
@@ -0,0 +1,309 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"math"
+	"sort"
+
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+)
+
+// comparators map the operators to the comparators of the reader API.
+var comparators = map[string]string{
+	"=":  "eq",
+	"<":  "lt",
+	"<=": "le",
+	">":  "gt",
+	">=": "ge",
+}
+
+// plan is the execution plan of the statement. The conditions supported by
+// the reader API are pushed down to the reader, so that it returns fewer
+// messages. Since the readers of the different storage engines don't
+// evaluate the conditions in the same way, e.g. the equality of the text
+// fields, the reader is used only to narrow the scan, and all the
+// conditions are evaluated once more on the returned messages.
+type plan struct {
+	stmt  statement
+	query sdk.MessagesQuery
+}
+
+func newPlan(stmt statement) plan {
+	var q sdk.MessagesQuery
+	for _, c := range stmt.conditions {
+		switch v := c.val.(type) {
+		case string:
+			if c.op != "=" {
+				continue
+			}
+			switch c.field {
+			case "subtopic":
+				q.Subtopic = v
+			case "publisher":
+				q.Publisher = v
+			case "protocol":
+				q.Protocol = v
+			case "name":
+				q.Name = v
+			case "string_value":
+				q.StringValue = v
+			case "data_value":
+				q.DataValue = v
+			}
+		case bool:
+			// The reader can't filter by the false value.
+			if c.field == "bool_value" && c.op == "=" && v {
+				q.BoolValue = true
+			}
+		case float64:
+			switch c.field {
+			case "value":
+				// The reader ignores the zero value, and it accepts
+				// only the single value condition.
+				if cmp, ok := comparators[c.op]; ok && v != 0 && q.Comparator == "" {
+					q.Value = v
+					q.Comparator = cmp
+				}
+			case "time":
+				// The reader filters by the time range including the
+				// start and excluding the end.
+				switch c.op {
+				case "=", ">", ">=":
+					if v > q.From {
+						q.From = v
+					}
+				case "<":
+					if q.To == 0 || v < q.To {
+						q.To = v
+					}
+				}
+			}
+		}
+	}
+	return plan{stmt: stmt, query: q}
+}
+
+// match evaluates all the conditions on the message. The missing values
+// never match.
+func (p plan) match(msg senml.Message) bool {
+	for _, c := range p.stmt.conditions {
+		v, ok := fieldValue(msg, c.field)
+		if !ok || !compare(v, c.op, c.val) {
+			return false
+		}
+	}
+	return true
+}
+
+func compare(v interface{}, op string, lit interface{}) bool {
+	switch v := v.(type) {
+	case float64:
+		l := lit.(float64)
+		switch op {
+		case "=":
+			return v == l
+		case "!=":
+			return v != l
+		case "<":
+			return v < l
+		case "<=":
+			return v <= l
+		case ">":
+			return v > l
+		case ">=":
+			return v >= l
+		}
+	default:
+		switch op {
+		case "=":
+			return v == lit
+		case "!=":
+			return v != lit
+		}
+	}
+	return false
+}
+
+// fieldValue returns the value of the message field, as float64, string or
+// bool, depending on the kind of the field. The unset optional values are
+// reported as missing.
+func fieldValue(msg senml.Message, field string) (interface{}, bool) {
+	switch field {
+	case "time":
+		return msg.Time, true
+	case "channel":
+		return msg.Channel, true
+	case "subtopic":
+		return msg.Subtopic, true
+	case "publisher":
+		return msg.Publisher, true
+	case "protocol":
+		return msg.Protocol, true
+	case "name":
+		return msg.Name, true
+	case "unit":
+		return msg.Unit, true
+	case "value":
+		if msg.Value == nil {
+			return nil, false
+		}
+		return *msg.Value, true
+	case "string_value":
+		if msg.StringValue == nil {
+			return nil, false
+		}
+		return *msg.StringValue, true
+	case "bool_value":
+		if msg.BoolValue == nil {
+			return nil, false
+		}
+		return *msg.BoolValue, true
+	case "data_value":
+		if msg.DataValue == nil {
+			return nil, false
+		}
+		return *msg.DataValue, true
+	case "sum":
+		if msg.Sum == nil {
+			return nil, false
+		}
+		return *msg.Sum, true
+	case "update_time":
+		return msg.UpdateTime, true
+	case "adapter":
+		return msg.Adapter, true
+	case "trace_id":
+		return msg.TraceID, true
+	case "hops":
+		return float64(msg.Hops), true
+	default:
+		return nil, false
+	}
+}
+
+// row returns the selected fields of the message. The missing values are
+// returned as nil.
+func (p plan) row(msg senml.Message) []interface{} {
+	row := make([]interface{}, len(p.stmt.selectors))
+	for i, sel := range p.stmt.selectors {
+		if v, ok := fieldValue(msg, sel.field); ok {
+			row[i] = v
+		}
+	}
+	return row
+}
+
+// accumulator accumulates the values of the single aggregate.
+type accumulator struct {
+	count uint64
+	sum   float64
+	min   float64
+	max   float64
+}
+
+func (a *accumulator) add(v float64) {
+	if a.count == 0 || v < a.min {
+		a.min = v
+	}
+	if a.count == 0 || v > a.max {
+		a.max = v
+	}
+	a.count++
+	a.sum += v
+}
+
+func (a accumulator) result(fn string) interface{} {
+	if fn == countFn {
+		return a.count
+	}
+	if a.count == 0 {
+		return nil
+	}
+	switch fn {
+	case sumFn:
+		return a.sum
+	case avgFn:
+		return a.sum / float64(a.count)
+	case minFn:
+		return a.min
+	default:
+		return a.max
+	}
+}
+
+// grouper aggregates the messages into the time buckets of the statement,
+// or into the single bucket if the statement isn't grouped.
+type grouper struct {
+	plan    plan
+	buckets map[float64][]accumulator
+}
+
+func newGrouper(p plan) *grouper {
+	return &grouper{plan: p, buckets: map[float64][]accumulator{}}
+}
+
+func (g *grouper) add(msg senml.Message) {
+	var key float64
+	if size := g.plan.stmt.bucket.Seconds(); size > 0 {
+		key = math.Floor(msg.Time/size) * size
+	}
+	accs, ok := g.buckets[key]
+	if !ok {
+		accs = make([]accumulator, len(g.plan.stmt.selectors))
+		g.buckets[key] = accs
+	}
+
+	for i, sel := range g.plan.stmt.selectors {
+		if sel.fn == "" {
+			continue
+		}
+		if sel.field == "*" {
+			accs[i].count++
+			continue
+		}
+		v, ok := fieldValue(msg, sel.field)
+		if !ok {
+			continue
+		}
+		if f, ok := v.(float64); ok {
+			accs[i].add(f)
+			continue
+		}
+		accs[i].count++
+	}
+}
+
+// rows returns the aggregates of the buckets, the latest bucket first. The
+// ungrouped statement always returns the single row, even if no message
+// matched.
+func (g *grouper) rows() [][]interface{} {
+	if g.plan.stmt.bucket == 0 && len(g.buckets) == 0 {
+		g.buckets[0] = make([]accumulator, len(g.plan.stmt.selectors))
+	}
+
+	keys := make([]float64, 0, len(g.buckets))
+	for k := range g.buckets {
+		keys = append(keys, k)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(keys)))
+
+	rows := [][]interface{}{}
+	for _, k := range keys {
+		row := make([]interface{}, len(g.plan.stmt.selectors))
+		for i, sel := range g.plan.stmt.selectors {
+			if sel.fn == "" {
+				row[i] = k
+				continue
+			}
+			row[i] = g.buckets[k][i].result(sel.fn)
+		}
+		rows = append(rows, row)
+	}
+	if l := g.plan.stmt.limit; l > 0 && uint64(len(rows)) > l {
+		rows = rows[:l]
+	}
+	return rows
+}
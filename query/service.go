@@ -0,0 +1,128 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+)
+
+// pageSize is the number of messages read from the reader at a time.
+const pageSize = 1000
+
+var (
+	// ErrUnauthorizedAccess indicates missing or invalid credentials provided
+	// when accessing a protected resource.
+	ErrUnauthorizedAccess = errors.New("missing or invalid credentials provided")
+
+	// ErrMalformedEntity indicates malformed request.
+	ErrMalformedEntity = errors.New("malformed entity specification")
+
+	// ErrScanLimit indicates that the query scans more messages than
+	// allowed, so it has to be narrowed, e.g. by the time range.
+	ErrScanLimit = errors.New("query scans too many messages")
+
+	errReadMessages = errors.New("failed to read messages")
+)
+
+// Result represents the result of the query, with the rows holding the
+// values of the selected columns in the same order.
+type Result struct {
+	Columns []string        `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+	Scanned uint64          `json:"scanned"`
+}
+
+// Service specifies an API that must be fulfilled by the domain service
+// implementation, and all of its decorators (e.g. logging & metrics).
+type Service interface {
+	// Execute executes the query on behalf of the user or the thing
+	// identified by the provided token.
+	Execute(ctx context.Context, token, query string) (Result, error)
+}
+
+var _ Service = (*queryService)(nil)
+
+type queryService struct {
+	sdk     sdk.SDK
+	maxScan uint64
+}
+
+// New instantiates the query service implementation. The messages are read
+// from the reader the SDK is configured with, whichever storage engine it's
+// backed by, and at most maxScan messages are read per query.
+func New(sdk sdk.SDK, maxScan uint64) Service {
+	return &queryService{
+		sdk:     sdk,
+		maxScan: maxScan,
+	}
+}
+
+func (qs *queryService) Execute(ctx context.Context, token, query string) (Result, error) {
+	if token == "" {
+		return Result{}, ErrUnauthorizedAccess
+	}
+
+	stmt, err := parse(query)
+	if err != nil {
+		return Result{}, err
+	}
+
+	p := newPlan(stmt)
+	res := Result{Rows: [][]interface{}{}}
+	for _, sel := range stmt.selectors {
+		res.Columns = append(res.Columns, sel.column())
+	}
+
+	var g *grouper
+	if stmt.aggregated() {
+		g = newGrouper(p)
+	}
+
+	q := p.query
+	q.Limit = pageSize
+	if qs.maxScan < pageSize {
+		q.Limit = qs.maxScan
+	}
+	for {
+		page, err := qs.sdk.QueryMessages(stmt.channel, token, q)
+		if err != nil {
+			if errors.Contains(err, sdk.ErrUnauthorized) {
+				return Result{}, errors.Wrap(ErrUnauthorizedAccess, err)
+			}
+			return Result{}, errors.Wrap(errReadMessages, err)
+		}
+
+		for _, msg := range page.Messages {
+			res.Scanned++
+			if !p.match(msg) {
+				continue
+			}
+			if g != nil {
+				g.add(msg)
+				continue
+			}
+			res.Rows = append(res.Rows, p.row(msg))
+			if stmt.limit > 0 && uint64(len(res.Rows)) == stmt.limit {
+				return res, nil
+			}
+		}
+
+		q.Offset += uint64(len(page.Messages))
+		if len(page.Messages) == 0 || q.Offset >= page.Total {
+			break
+		}
+		if res.Scanned >= qs.maxScan {
+			return Result{}, errors.Wrap(ErrScanLimit, fmt.Errorf("scanned %d of %d messages", res.Scanned, page.Total))
+		}
+	}
+
+	if g != nil {
+		res.Rows = g.rows()
+	}
+	return res, nil
+}
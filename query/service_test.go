@@ -0,0 +1,203 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package query_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/mainflux/mainflux/pkg/transformers/senml"
+	"github.com/mainflux/mainflux/query"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	token      = "token"
+	wrongToken = "wrong-token"
+	chanID     = "chan"
+)
+
+// sdkMock implements only the SDK methods needed to read messages. The
+// messages are returned the latest first, like the readers do, filtered
+// only by the time range.
+type sdkMock struct {
+	sdk.SDK
+	msgs    []senml.Message
+	queries []sdk.MessagesQuery
+}
+
+func (m *sdkMock) QueryMessages(chanID, token string, q sdk.MessagesQuery) (sdk.MessagesPage, error) {
+	m.queries = append(m.queries, q)
+	if token != "token" {
+		return sdk.MessagesPage{}, errors.Wrap(sdk.ErrFailedRead, sdk.ErrUnauthorized)
+	}
+
+	var msgs []senml.Message
+	for i := len(m.msgs) - 1; i >= 0; i-- {
+		msg := m.msgs[i]
+		if msg.Time < q.From || (q.To != 0 && msg.Time >= q.To) {
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+
+	page := sdk.MessagesPage{}
+	page.Total = uint64(len(msgs))
+	for i, msg := range msgs {
+		if uint64(i) >= q.Offset && uint64(i) < q.Offset+q.Limit {
+			page.Messages = append(page.Messages, msg)
+		}
+	}
+	return page, nil
+}
+
+func newMock() *sdkMock {
+	mock := &sdkMock{}
+	for i := 0; i < 6; i++ {
+		v := float64(i)
+		name := "temp"
+		if i%2 == 1 {
+			name = "hum"
+		}
+		mock.msgs = append(mock.msgs, senml.Message{
+			Channel: chanID,
+			Name:    name,
+			Unit:    "C",
+			Time:    float64(i * 30),
+			Value:   &v,
+		})
+	}
+	return mock
+}
+
+func TestExecute(t *testing.T) {
+	cases := []struct {
+		desc    string
+		token   string
+		query   string
+		columns []string
+		rows    [][]interface{}
+		err     error
+	}{
+		{
+			desc:    "select fields with conditions",
+			token:   token,
+			query:   "SELECT time, value FROM chan WHERE name = 'temp' AND value > 0",
+			columns: []string{"time", "value"},
+			rows:    [][]interface{}{{float64(120), float64(4)}, {float64(60), float64(2)}},
+		},
+		{
+			desc:    "select fields with limit",
+			token:   token,
+			query:   "select name from chan where time >= 60 limit 2",
+			columns: []string{"name"},
+			rows:    [][]interface{}{{"hum"}, {"temp"}},
+		},
+		{
+			desc:    "select aggregates",
+			token:   token,
+			query:   "SELECT count(*), sum(value), avg(value), min(value), max(value) FROM chan WHERE name != 'hum'",
+			columns: []string{"count(*)", "sum(value)", "avg(value)", "min(value)", "max(value)"},
+			rows:    [][]interface{}{{uint64(3), float64(6), float64(2), float64(0), float64(4)}},
+		},
+		{
+			desc:    "select aggregates of no messages",
+			token:   token,
+			query:   "SELECT count(value), avg(value) FROM chan WHERE unit = 'F'",
+			columns: []string{"count(value)", "avg(value)"},
+			rows:    [][]interface{}{{uint64(0), nil}},
+		},
+		{
+			desc:    "select aggregates grouped by time",
+			token:   token,
+			query:   "SELECT time, max(value) FROM chan WHERE time < '1970-01-01T00:02:30Z' GROUP BY time(1m)",
+			columns: []string{"time", "max(value)"},
+			rows:    [][]interface{}{{float64(120), float64(4)}, {float64(60), float64(3)}, {float64(0), float64(1)}},
+		},
+		{
+			desc:  "select with invalid token",
+			token: wrongToken,
+			query: "SELECT * FROM chan",
+			err:   query.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "select without token",
+			token: "",
+			query: "SELECT * FROM chan",
+			err:   query.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "select unknown field",
+			token: token,
+			query: "SELECT temperature FROM chan",
+			err:   query.ErrSyntax,
+		},
+		{
+			desc:  "select fields along with aggregates",
+			token: token,
+			query: "SELECT name, count(*) FROM chan",
+			err:   query.ErrSyntax,
+		},
+		{
+			desc:  "select aggregate of text field",
+			token: token,
+			query: "SELECT avg(name) FROM chan",
+			err:   query.ErrSyntax,
+		},
+		{
+			desc:  "select grouped fields",
+			token: token,
+			query: "SELECT value FROM chan GROUP BY time(1m)",
+			err:   query.ErrSyntax,
+		},
+		{
+			desc:  "select with invalid condition",
+			token: token,
+			query: "SELECT value FROM chan WHERE name > 'temp'",
+			err:   query.ErrSyntax,
+		},
+		{
+			desc:  "select with unterminated string",
+			token: token,
+			query: "SELECT value FROM chan WHERE name = 'temp",
+			err:   query.ErrSyntax,
+		},
+	}
+
+	for _, tc := range cases {
+		svc := query.New(newMock(), 100)
+		res, err := svc.Execute(context.Background(), tc.token, tc.query)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, tc.columns, res.Columns, fmt.Sprintf("%s: expected columns %v got %v\n", tc.desc, tc.columns, res.Columns))
+			assert.Equal(t, tc.rows, res.Rows, fmt.Sprintf("%s: expected rows %v got %v\n", tc.desc, tc.rows, res.Rows))
+		}
+	}
+}
+
+func TestExecutePushdown(t *testing.T) {
+	mock := newMock()
+	svc := query.New(mock, 100)
+
+	_, err := svc.Execute(context.Background(), token, "SELECT value FROM chan WHERE name = 'temp' AND value >= 2 AND value < 4 AND time > 30 AND time < 150")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expected := sdk.MessagesQuery{Limit: 100, Name: "temp", Value: 2, Comparator: "ge", From: 30, To: 150}
+	assert.Equal(t, []sdk.MessagesQuery{expected}, mock.queries, fmt.Sprintf("expected reader queries %v got %v\n", expected, mock.queries))
+}
+
+func TestExecuteScanLimit(t *testing.T) {
+	mock := newMock()
+	svc := query.New(mock, 4)
+
+	_, err := svc.Execute(context.Background(), token, "SELECT count(*) FROM chan")
+	assert.True(t, errors.Contains(err, query.ErrScanLimit), fmt.Sprintf("expected %s got %s\n", query.ErrScanLimit, err))
+
+	res, err := svc.Execute(context.Background(), token, "SELECT count(*) FROM chan WHERE time >= 60")
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uint64(4), res.Scanned, fmt.Sprintf("expected 4 scanned messages got %d\n", res.Scanned))
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package readers
+
+import "time"
+
+// QueryLog represents the record of the message query served by the reader.
+type QueryLog struct {
+	// Subject is the ID of the thing the query token belongs to.
+	Subject  string
+	ChanID   string
+	From     float64
+	To       float64
+	Rows     uint64
+	Duration time.Duration
+	Time     time.Time
+}
+
+// ConsumerStats represents the aggregated queries of the single subject.
+type ConsumerStats struct {
+	Subject  string
+	Queries  uint64
+	Rows     uint64
+	Duration time.Duration
+}
+
+// QueryLogRepository specifies the reader query analytics API.
+type QueryLogRepository interface {
+	// Save records the served query.
+	Save(ql QueryLog) error
+
+	// TopConsumers returns the limited number of subjects which read the most
+	// rows since the given time.
+	TopConsumers(since time.Time, limit uint64) ([]ConsumerStats, error)
+
+	// SlowQueries returns the limited number of the slowest queries served
+	// since the given time.
+	SlowQueries(since time.Time, limit uint64) ([]QueryLog, error)
+}
@@ -5,12 +5,13 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/readers"
 )
 
-func listMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
+func listMessagesEndpoint(svc readers.MessageRepository, logs readers.QueryLogRepository) endpoint.Endpoint {
 	return func(_ context.Context, request interface{}) (interface{}, error) {
 		req := request.(listMessagesReq)
 
@@ -18,11 +19,26 @@ func listMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
 			return nil, err
 		}
 
+		begin := time.Now()
 		page, err := svc.ReadAll(req.chanID, req.pageMeta)
 		if err != nil {
 			return nil, err
 		}
 
+		if logs != nil {
+			// The query is served regardless of the failure to record it,
+			// which is logged by the repository middleware.
+			_ = logs.Save(readers.QueryLog{
+				Subject:  req.subject,
+				ChanID:   req.chanID,
+				From:     req.pageMeta.From,
+				To:       req.pageMeta.To,
+				Rows:     uint64(len(page.Messages)),
+				Duration: time.Since(begin),
+				Time:     begin.UTC(),
+			})
+		}
+
 		return pageRes{
 			PageMetadata: page.PageMetadata,
 			Total:        page.Total,
@@ -30,3 +46,60 @@ func listMessagesEndpoint(svc readers.MessageRepository) endpoint.Endpoint {
 		}, nil
 	}
 }
+
+func topConsumersEndpoint(logs readers.QueryLogRepository) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(analyticsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		stats, err := logs.TopConsumers(req.since, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := consumersRes{Consumers: []consumerRes{}}
+		for _, cs := range stats {
+			res.Consumers = append(res.Consumers, consumerRes{
+				Subject:  cs.Subject,
+				Queries:  cs.Queries,
+				Rows:     cs.Rows,
+				Duration: cs.Duration.Seconds(),
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func slowQueriesEndpoint(logs readers.QueryLogRepository) endpoint.Endpoint {
+	return func(_ context.Context, request interface{}) (interface{}, error) {
+		req := request.(analyticsReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		qls, err := logs.SlowQueries(req.since, req.limit)
+		if err != nil {
+			return nil, err
+		}
+
+		res := queriesRes{Queries: []queryRes{}}
+		for _, ql := range qls {
+			res.Queries = append(res.Queries, queryRes{
+				Subject:  ql.Subject,
+				ChanID:   ql.ChanID,
+				From:     ql.From,
+				To:       ql.To,
+				Rows:     ql.Rows,
+				Duration: ql.Duration.Seconds(),
+				Time:     ql.Time,
+			})
+		}
+
+		return res, nil
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/mainflux/mainflux/readers"
 	"github.com/mainflux/mainflux/readers/api"
 	"github.com/mainflux/mainflux/readers/mocks"
+	thmocks "github.com/mainflux/mainflux/things/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -44,8 +45,8 @@ var (
 	idProvider = uuid.New()
 )
 
-func newServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient) *httptest.Server {
-	mux := api.MakeHandler(repo, tc, svcName)
+func newServer(repo readers.MessageRepository, tc mainflux.ThingsServiceClient, logs readers.QueryLogRepository, ac mainflux.AuthServiceClient) *httptest.Server {
+	mux := api.MakeHandler(repo, tc, logs, ac, svcName)
 	return httptest.NewServer(mux)
 }
 
@@ -124,7 +125,7 @@ func TestReadAll(t *testing.T) {
 
 	svc := mocks.NewThingsService()
 	repo := mocks.NewMessageRepository(chanID, fromSenml(messages))
-	ts := newServer(repo, svc)
+	ts := newServer(repo, svc, nil, nil)
 	defer ts.Close()
 
 	cases := []struct {
@@ -437,6 +438,116 @@ func TestReadAll(t *testing.T) {
 	}
 }
 
+func TestAnalytics(t *testing.T) {
+	chanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	var messages []senml.Message
+	for i := 0; i < numOfMessages; i++ {
+		messages = append(messages, senml.Message{Channel: chanID, Publisher: token, Protocol: mqttProt, Time: float64(i)})
+	}
+
+	admin, user := "admin-token", "user-token"
+	users := map[string]string{admin: "admin@example.com", user: "user@example.com"}
+	policies := map[string][]thmocks.MockSubjectSet{"admin@example.com": {{Object: "authorities", Relation: "member"}}}
+	ac := thmocks.NewAuthService(users, policies)
+
+	logs := mocks.NewQueryLogRepository()
+	repo := mocks.NewMessageRepository(chanID, fromSenml(messages))
+	ts := newServer(repo, mocks.NewThingsService(), logs, ac)
+	defer ts.Close()
+
+	for _, q := range []string{"limit=10", "limit=50", "limit=10"} {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/messages?%s", ts.URL, chanID, q),
+			token:  token,
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		require.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("expected %d got %d", http.StatusOK, res.StatusCode))
+	}
+
+	cases := []struct {
+		desc   string
+		url    string
+		token  string
+		status int
+		res    string
+	}{
+		{
+			desc:   "get top consumers",
+			url:    fmt.Sprintf("%s/analytics/consumers", ts.URL),
+			token:  admin,
+			status: http.StatusOK,
+			res:    `{"consumers":[{"subject":"1","queries":3,"rows":70}]}`,
+		},
+		{
+			desc:   "get slow queries",
+			url:    fmt.Sprintf("%s/analytics/slow-queries?limit=2", ts.URL),
+			token:  admin,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "get top consumers since future time",
+			url:    fmt.Sprintf("%s/analytics/consumers?since=%d", ts.URL, time.Now().Add(time.Hour).Unix()),
+			token:  admin,
+			status: http.StatusOK,
+			res:    `{"consumers":[]}`,
+		},
+		{
+			desc:   "get top consumers with invalid limit",
+			url:    fmt.Sprintf("%s/analytics/consumers?limit=0", ts.URL),
+			token:  admin,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "get top consumers as non-admin",
+			url:    fmt.Sprintf("%s/analytics/consumers", ts.URL),
+			token:  user,
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "get top consumers with invalid token",
+			url:    fmt.Sprintf("%s/analytics/consumers", ts.URL),
+			token:  invalid,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.res == "" {
+			continue
+		}
+
+		var body struct {
+			Consumers []struct {
+				Subject string `json:"subject"`
+				Queries uint64 `json:"queries"`
+				Rows    uint64 `json:"rows"`
+			} `json:"consumers"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data, _ := json.Marshal(body)
+		assert.JSONEq(t, tc.res, string(data), fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+
+	qls, err := logs.SlowQueries(time.Time{}, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Len(t, qls, 3, fmt.Sprintf("expected 3 recorded queries got %d", len(qls)))
+}
+
 type pageRes struct {
 	readers.PageMetadata
 	Total    uint64          `json:"total"`
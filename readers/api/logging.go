@@ -40,3 +40,58 @@ func (lm *loggingMiddleware) ReadAll(chanID string, rpm readers.PageMetadata) (p
 
 	return lm.svc.ReadAll(chanID, rpm)
 }
+
+var _ readers.QueryLogRepository = (*queryLogLoggingMiddleware)(nil)
+
+type queryLogLoggingMiddleware struct {
+	logger logger.Logger
+	repo   readers.QueryLogRepository
+}
+
+// QueryLogLoggingMiddleware adds logging facilities to the query log
+// repository.
+func QueryLogLoggingMiddleware(repo readers.QueryLogRepository, logger logger.Logger) readers.QueryLogRepository {
+	return &queryLogLoggingMiddleware{
+		logger: logger,
+		repo:   repo,
+	}
+}
+
+func (lm *queryLogLoggingMiddleware) Save(ql readers.QueryLog) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method save_query_log for subject %s and channel %s took %s to complete", ql.Subject, ql.ChanID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Debug(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.repo.Save(ql)
+}
+
+func (lm *queryLogLoggingMiddleware) TopConsumers(since time.Time, limit uint64) (stats []readers.ConsumerStats, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method top_consumers since %s took %s to complete", since, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.repo.TopConsumers(since, limit)
+}
+
+func (lm *queryLogLoggingMiddleware) SlowQueries(since time.Time, limit uint64) (logs []readers.QueryLog, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method slow_queries since %s took %s to complete", since, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.repo.SlowQueries(since, limit)
+}
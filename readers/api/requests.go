@@ -4,16 +4,21 @@
 package api
 
 import (
+	"time"
+
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/readers"
 )
 
+const maxLimit = 100
+
 type apiReq interface {
 	validate() error
 }
 
 type listMessagesReq struct {
 	chanID   string
+	subject  string
 	pageMeta readers.PageMetadata
 }
 
@@ -21,12 +26,17 @@ func (req listMessagesReq) validate() error {
 	if req.pageMeta.Limit < 1 || req.pageMeta.Offset < 0 {
 		return errors.ErrInvalidQueryParams
 	}
-	if req.pageMeta.Comparator != "" &&
-		req.pageMeta.Comparator != readers.EqualKey &&
-		req.pageMeta.Comparator != readers.LowerThanKey &&
-		req.pageMeta.Comparator != readers.LowerThanEqualKey &&
-		req.pageMeta.Comparator != readers.GreaterThanKey &&
-		req.pageMeta.Comparator != readers.GreaterThanEqualKey {
+
+	return nil
+}
+
+type analyticsReq struct {
+	since time.Time
+	limit uint64
+}
+
+func (req analyticsReq) validate() error {
+	if req.limit < 1 || req.limit > maxLimit {
 		return errors.ErrInvalidQueryParams
 	}
 
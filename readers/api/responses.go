@@ -5,12 +5,17 @@ package api
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/readers"
 )
 
-var _ mainflux.Response = (*pageRes)(nil)
+var (
+	_ mainflux.Response = (*pageRes)(nil)
+	_ mainflux.Response = (*consumersRes)(nil)
+	_ mainflux.Response = (*queriesRes)(nil)
+)
 
 type pageRes struct {
 	readers.PageMetadata
@@ -30,6 +35,56 @@ func (res pageRes) Empty() bool {
 	return false
 }
 
+// The durations are given in seconds.
+type consumerRes struct {
+	Subject  string  `json:"subject"`
+	Queries  uint64  `json:"queries"`
+	Rows     uint64  `json:"rows"`
+	Duration float64 `json:"duration"`
+}
+
+type consumersRes struct {
+	Consumers []consumerRes `json:"consumers"`
+}
+
+func (res consumersRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res consumersRes) Code() int {
+	return http.StatusOK
+}
+
+func (res consumersRes) Empty() bool {
+	return false
+}
+
+type queryRes struct {
+	Subject  string    `json:"subject"`
+	ChanID   string    `json:"channel"`
+	From     float64   `json:"from,omitempty"`
+	To       float64   `json:"to,omitempty"`
+	Rows     uint64    `json:"rows"`
+	Duration float64   `json:"duration"`
+	Time     time.Time `json:"time"`
+}
+
+type queriesRes struct {
+	Queries []queryRes `json:"queries"`
+}
+
+func (res queriesRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res queriesRes) Code() int {
+	return http.StatusOK
+}
+
+func (res queriesRes) Empty() bool {
+	return false
+}
+
 type errorRes struct {
 	Err string `json:"error"`
 }
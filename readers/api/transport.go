@@ -6,8 +6,8 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"math"
 	"net/http"
-	"strconv"
 	"time"
 
 	kithttp "github.com/go-kit/kit/transport/http"
@@ -31,14 +31,19 @@ const (
 	protocolKey    = "protocol"
 	nameKey        = "name"
 	valueKey       = "v"
+	boolValueKey   = "vb"
 	stringValueKey = "vs"
 	dataValueKey   = "vd"
 	comparatorKey  = "comparator"
 	fromKey        = "from"
 	toKey          = "to"
+	sinceKey       = "since"
 	defLimit       = 10
 	defOffset      = 0
 	defFormat      = "messages"
+
+	authoritiesObject = "authorities"
+	memberRelation    = "member"
 )
 
 var (
@@ -46,8 +51,10 @@ var (
 	auth                  mainflux.ThingsServiceClient
 )
 
-// MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient, svcName string) http.Handler {
+// MakeHandler returns a HTTP handler for API endpoints. The served queries
+// are recorded to the query log repository, if any, and the analytics
+// endpoints are then available to the admins identified by the auth service.
+func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient, logs readers.QueryLogRepository, ac mainflux.AuthServiceClient, svcName string) http.Handler {
 	auth = tc
 
 	opts := []kithttp.ServerOption{
@@ -56,12 +63,32 @@ func MakeHandler(svc readers.MessageRepository, tc mainflux.ThingsServiceClient,
 
 	mux := bone.New()
 	mux.Get("/channels/:chanID/messages", kithttp.NewServer(
-		listMessagesEndpoint(svc),
+		listMessagesEndpoint(svc, logs),
 		decodeList,
 		encodeResponse,
 		opts...,
 	))
 
+	if logs != nil {
+		admin := func(h http.Handler) http.Handler {
+			return httputil.Authenticate(ac)(httputil.Authorize(ac, authoritiesObject, memberRelation, "")(h))
+		}
+
+		mux.Get("/analytics/consumers", admin(kithttp.NewServer(
+			topConsumersEndpoint(logs),
+			decodeAnalytics,
+			encodeResponse,
+			opts...,
+		)))
+
+		mux.Get("/analytics/slow-queries", admin(kithttp.NewServer(
+			slowQueriesEndpoint(logs),
+			decodeAnalytics,
+			encodeResponse,
+			opts...,
+		)))
+	}
+
 	mux.GetFunc("/version", mainflux.Version(svcName))
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -74,7 +101,8 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, errors.ErrInvalidQueryParams
 	}
 
-	if err := authorize(r, chanID); err != nil {
+	subject, err := authorize(r, chanID)
+	if err != nil {
 		return nil, err
 	}
 
@@ -118,7 +146,7 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	comparator, err := httputil.ReadStringQuery(r, comparatorKey, "")
+	comparator, err := httputil.ReadEnumQuery(r, comparatorKey, "", readers.EqualKey, readers.LowerThanKey, readers.LowerThanEqualKey, readers.GreaterThanKey, readers.GreaterThanEqualKey)
 	if err != nil {
 		return nil, err
 	}
@@ -143,8 +171,14 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
+	vb, err := httputil.ReadBoolQuery(r, boolValueKey, false)
+	if err != nil {
+		return nil, err
+	}
+
 	req := listMessagesReq{
-		chanID: chanID,
+		chanID:  chanID,
+		subject: subject,
 		pageMeta: readers.PageMetadata{
 			Offset:      offset,
 			Limit:       limit,
@@ -155,6 +189,7 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 			Name:        name,
 			Value:       v,
 			Comparator:  comparator,
+			BoolValue:   vb,
 			StringValue: vs,
 			DataValue:   vd,
 			From:        from,
@@ -162,12 +197,24 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 		},
 	}
 
-	vb, err := readBoolValueQuery(r, "vb")
-	if err != nil && err != errors.ErrNotFoundParam {
+	return req, nil
+}
+
+func decodeAnalytics(_ context.Context, r *http.Request) (interface{}, error) {
+	since, err := httputil.ReadFloatQuery(r, sinceKey, 0)
+	if err != nil {
 		return nil, err
 	}
-	if err == nil {
-		req.pageMeta.BoolValue = vb
+
+	limit, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, dec := math.Modf(since)
+	req := analyticsReq{
+		since: time.Unix(int64(sec), int64(dec*1e9)),
+		limit: limit,
 	}
 
 	return req, nil
@@ -210,41 +257,24 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	}
 }
 
-func authorize(r *http.Request, chanID string) error {
+// authorize returns the ID of the thing the token belongs to.
+func authorize(r *http.Request, chanID string) (string, error) {
 	token := r.Header.Get("Authorization")
 	if token == "" {
-		return errUnauthorizedAccess
+		return "", errUnauthorizedAccess
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 
-	_, err := auth.CanAccessByKey(ctx, &mainflux.AccessByKeyReq{Token: token, ChanID: chanID})
+	id, err := auth.CanAccessByKey(ctx, &mainflux.AccessByKeyReq{Token: token, ChanID: chanID})
 	if err != nil {
 		e, ok := status.FromError(err)
 		if ok && e.Code() == codes.PermissionDenied {
-			return errUnauthorizedAccess
+			return "", errUnauthorizedAccess
 		}
-		return err
-	}
-
-	return nil
-}
-
-func readBoolValueQuery(r *http.Request, key string) (bool, error) {
-	vals := bone.GetQuery(r, key)
-	if len(vals) > 1 {
-		return false, errors.ErrInvalidQueryParams
-	}
-
-	if len(vals) == 0 {
-		return false, errors.ErrNotFoundParam
-	}
-
-	b, err := strconv.ParseBool(vals[0])
-	if err != nil {
-		return false, errors.ErrInvalidQueryParams
+		return "", err
 	}
 
-	return b, nil
+	return id.GetValue(), nil
 }
@@ -0,0 +1,86 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/readers"
+)
+
+var _ readers.QueryLogRepository = (*queryLogRepositoryMock)(nil)
+
+type queryLogRepositoryMock struct {
+	mutex sync.Mutex
+	logs  []readers.QueryLog
+}
+
+// NewQueryLogRepository returns mock implementation of query log repository.
+func NewQueryLogRepository() readers.QueryLogRepository {
+	return &queryLogRepositoryMock{}
+}
+
+func (repo *queryLogRepositoryMock) Save(ql readers.QueryLog) error {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	repo.logs = append(repo.logs, ql)
+	return nil
+}
+
+func (repo *queryLogRepositoryMock) TopConsumers(since time.Time, limit uint64) ([]readers.ConsumerStats, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	stats := map[string]readers.ConsumerStats{}
+	for _, ql := range repo.since(since) {
+		cs := stats[ql.Subject]
+		cs.Subject = ql.Subject
+		cs.Queries++
+		cs.Rows += ql.Rows
+		cs.Duration += ql.Duration
+		stats[ql.Subject] = cs
+	}
+
+	res := []readers.ConsumerStats{}
+	for _, cs := range stats {
+		res = append(res, cs)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Rows == res[j].Rows {
+			return res[i].Queries > res[j].Queries
+		}
+		return res[i].Rows > res[j].Rows
+	})
+
+	if uint64(len(res)) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (repo *queryLogRepositoryMock) SlowQueries(since time.Time, limit uint64) ([]readers.QueryLog, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	res := repo.since(since)
+	sort.Slice(res, func(i, j int) bool { return res[i].Duration > res[j].Duration })
+
+	if uint64(len(res)) > limit {
+		res = res[:limit]
+	}
+	return res, nil
+}
+
+func (repo *queryLogRepositoryMock) since(t time.Time) []readers.QueryLog {
+	logs := []readers.QueryLog{}
+	for _, ql := range repo.logs {
+		if !ql.Time.Before(t) {
+			logs = append(logs, ql)
+		}
+	}
+	return logs
+}
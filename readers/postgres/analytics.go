@@ -0,0 +1,134 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/readers"
+)
+
+var (
+	errSaveQueryLog  = errors.New("failed to save query log to postgres database")
+	errReadQueryLogs = errors.New("failed to read query logs from postgres database")
+)
+
+var _ readers.QueryLogRepository = (*queryLogRepository)(nil)
+
+type queryLogRepository struct {
+	db *sqlx.DB
+}
+
+// NewQueryLogRepository returns the PostgreSQL query log repository.
+func NewQueryLogRepository(db *sqlx.DB) readers.QueryLogRepository {
+	return &queryLogRepository{
+		db: db,
+	}
+}
+
+func (qr queryLogRepository) Save(ql readers.QueryLog) error {
+	q := `INSERT INTO query_logs (subject, channel, time_from, time_to, rows, duration, created_at)
+		VALUES (:subject, :channel, :time_from, :time_to, :rows, :duration, :created_at)`
+
+	if _, err := qr.db.NamedExec(q, toDBQueryLog(ql)); err != nil {
+		return errors.Wrap(errSaveQueryLog, err)
+	}
+
+	return nil
+}
+
+func (qr queryLogRepository) TopConsumers(since time.Time, limit uint64) ([]readers.ConsumerStats, error) {
+	q := `SELECT subject, COUNT(*) AS queries, SUM(rows) AS rows, SUM(duration) AS duration
+		FROM query_logs WHERE created_at >= $1
+		GROUP BY subject ORDER BY rows DESC, queries DESC LIMIT $2`
+
+	rows, err := qr.db.Queryx(q, since, limit)
+	if err != nil {
+		return nil, errors.Wrap(errReadQueryLogs, err)
+	}
+	defer rows.Close()
+
+	stats := []readers.ConsumerStats{}
+	for rows.Next() {
+		var dbs dbConsumerStats
+		if err := rows.StructScan(&dbs); err != nil {
+			return nil, errors.Wrap(errReadQueryLogs, err)
+		}
+		stats = append(stats, readers.ConsumerStats{
+			Subject:  dbs.Subject,
+			Queries:  dbs.Queries,
+			Rows:     dbs.Rows,
+			Duration: time.Duration(dbs.Duration) * time.Microsecond,
+		})
+	}
+
+	return stats, nil
+}
+
+func (qr queryLogRepository) SlowQueries(since time.Time, limit uint64) ([]readers.QueryLog, error) {
+	q := `SELECT subject, channel, time_from, time_to, rows, duration, created_at
+		FROM query_logs WHERE created_at >= $1
+		ORDER BY duration DESC LIMIT $2`
+
+	rows, err := qr.db.Queryx(q, since, limit)
+	if err != nil {
+		return nil, errors.Wrap(errReadQueryLogs, err)
+	}
+	defer rows.Close()
+
+	logs := []readers.QueryLog{}
+	for rows.Next() {
+		var dbql dbQueryLog
+		if err := rows.StructScan(&dbql); err != nil {
+			return nil, errors.Wrap(errReadQueryLogs, err)
+		}
+		logs = append(logs, toQueryLog(dbql))
+	}
+
+	return logs, nil
+}
+
+// The durations are stored in microseconds.
+type dbQueryLog struct {
+	Subject   string    `db:"subject"`
+	Channel   string    `db:"channel"`
+	From      float64   `db:"time_from"`
+	To        float64   `db:"time_to"`
+	Rows      uint64    `db:"rows"`
+	Duration  int64     `db:"duration"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type dbConsumerStats struct {
+	Subject  string `db:"subject"`
+	Queries  uint64 `db:"queries"`
+	Rows     uint64 `db:"rows"`
+	Duration int64  `db:"duration"`
+}
+
+func toDBQueryLog(ql readers.QueryLog) dbQueryLog {
+	return dbQueryLog{
+		Subject:   ql.Subject,
+		Channel:   ql.ChanID,
+		From:      ql.From,
+		To:        ql.To,
+		Rows:      ql.Rows,
+		Duration:  ql.Duration.Microseconds(),
+		CreatedAt: ql.Time,
+	}
+}
+
+func toQueryLog(dbql dbQueryLog) readers.QueryLog {
+	return readers.QueryLog{
+		Subject:  dbql.Subject,
+		ChanID:   dbql.Channel,
+		From:     dbql.From,
+		To:       dbql.To,
+		Rows:     dbql.Rows,
+		Duration: time.Duration(dbql.Duration) * time.Microsecond,
+		Time:     dbql.CreatedAt,
+	}
+}
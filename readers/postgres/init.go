@@ -73,6 +73,38 @@ func migrateDB(db *sqlx.DB) error {
 		},
 	}
 
-	_, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up)
+	if _, err := migrate.Exec(db.DB, "postgres", migrations, migrate.Up); err != nil {
+		return err
+	}
+
+	// The reader tables are migrated separately, since the messages
+	// migrations are shared with the Postgres writer.
+	readerMigrations := &migrate.MemoryMigrationSource{
+		Migrations: []*migrate.Migration{
+			{
+				Id: "reader_1",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS query_logs (
+                        id          BIGSERIAL,
+                        subject     TEXT NOT NULL,
+                        channel     TEXT NOT NULL,
+                        time_from   FLOAT,
+                        time_to     FLOAT,
+                        rows        BIGINT NOT NULL,
+                        duration    BIGINT NOT NULL,
+                        created_at  TIMESTAMPTZ NOT NULL,
+                        PRIMARY KEY (id)
+                    )`,
+					`CREATE INDEX IF NOT EXISTS query_logs_created_at_idx ON query_logs (created_at)`,
+				},
+				Down: []string{
+					"DROP TABLE query_logs",
+				},
+			},
+		},
+	}
+
+	ms := migrate.MigrationSet{TableName: "reader_migrations"}
+	_, err := ms.Exec(db.DB, "postgres", readerMigrations, migrate.Up)
 	return err
 }
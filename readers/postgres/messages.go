@@ -175,6 +175,9 @@ type jsonMessage struct {
 	Subtopic  string `db:"subtopic"`
 	Publisher string `db:"publisher"`
 	Protocol  string `db:"protocol"`
+	Adapter   string `db:"adapter"`
+	TraceID   string `db:"trace_id"`
+	Hops      uint32 `db:"hops"`
 	Payload   []byte `db:"payload"`
 }
 
@@ -186,6 +189,9 @@ func (msg jsonMessage) toMap() (map[string]interface{}, error) {
 		"subtopic":  msg.Subtopic,
 		"publisher": msg.Publisher,
 		"protocol":  msg.Protocol,
+		"adapter":   msg.Adapter,
+		"trace_id":  msg.TraceID,
+		"hops":      msg.Hops,
 		"payload":   map[string]interface{}{},
 	}
 	pld := make(map[string]interface{})
@@ -540,6 +540,9 @@ func toMap(msg json.Message) map[string]interface{} {
 		"subtopic":  msg.Subtopic,
 		"publisher": msg.Publisher,
 		"protocol":  msg.Protocol,
+		"adapter":   msg.Adapter,
+		"trace_id":  msg.TraceID,
+		"hops":      msg.Hops,
 		"payload":   map[string]interface{}(msg.Payload),
 	}
 }
@@ -0,0 +1,153 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/mainflux/mainflux/readers"
+)
+
+const (
+	keyPrefix  = "readers"
+	lockSuffix = "lock"
+
+	// lockTimeout bounds the time the concurrent requests wait for the
+	// request holding the lock to cache the page.
+	lockTimeout  = 5 * time.Second
+	pollInterval = 20 * time.Millisecond
+)
+
+var _ readers.MessageRepository = (*cachedRepository)(nil)
+
+type cachedRepository struct {
+	client *redis.Client
+	repo   readers.MessageRepository
+	ttl    time.Duration
+	bucket time.Duration
+}
+
+// NewCachedRepository returns the message repository caching the pages read
+// from the given repository for the ttl duration. The queries are normalized
+// by truncating their time range to the bucket duration, so the polling
+// clients reading the sliding windows of the same length share the cached
+// page. Only one of the concurrent requests for the same page reads it from
+// the repository, while the others wait for it to be cached.
+func NewCachedRepository(client *redis.Client, repo readers.MessageRepository, ttl, bucket time.Duration) readers.MessageRepository {
+	return cachedRepository{
+		client: client,
+		repo:   repo,
+		ttl:    ttl,
+		bucket: bucket,
+	}
+}
+
+func (cr cachedRepository) ReadAll(chanID string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	ctx := context.Background()
+
+	key, err := cr.key(chanID, pm)
+	if err != nil {
+		return cr.repo.ReadAll(chanID, pm)
+	}
+
+	if page, ok := cr.get(ctx, key); ok {
+		return page, nil
+	}
+
+	lock := fmt.Sprintf("%s:%s", key, lockSuffix)
+	locked, err := cr.client.SetNX(ctx, lock, 1, lockTimeout).Result()
+	if err == nil && !locked {
+		// Another request is reading the same page.
+		if page, ok := cr.wait(ctx, key, lock); ok {
+			return page, nil
+		}
+	}
+	if locked {
+		defer cr.client.Del(ctx, lock)
+	}
+
+	page, err := cr.repo.ReadAll(chanID, pm)
+	if err != nil {
+		return page, err
+	}
+
+	// Failing to cache the page doesn't fail the read.
+	if data, err := json.Marshal(page); err == nil {
+		cr.client.Set(ctx, key, data, cr.ttl)
+	}
+
+	return page, nil
+}
+
+// key returns the cache key of the channel page, made of the channel ID and
+// the hash of the normalized page metadata.
+func (cr cachedRepository) key(chanID string, pm readers.PageMetadata) (string, error) {
+	pm.From = cr.truncate(pm.From)
+	pm.To = cr.truncate(pm.To)
+
+	data, err := json.Marshal(pm)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+
+	return fmt.Sprintf("%s:%s:%s", keyPrefix, chanID, hex.EncodeToString(sum[:])), nil
+}
+
+// truncate rounds the timestamp in seconds down to the bucket.
+func (cr cachedRepository) truncate(ts float64) float64 {
+	b := cr.bucket.Seconds()
+	if b <= 0 || ts == 0 {
+		return ts
+	}
+	return math.Floor(ts/b) * b
+}
+
+func (cr cachedRepository) get(ctx context.Context, key string) (readers.MessagesPage, bool) {
+	data, err := cr.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return readers.MessagesPage{}, false
+	}
+
+	// Numbers are kept as json.Number to avoid losing the precision of
+	// the integer values.
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var page readers.MessagesPage
+	if err := dec.Decode(&page); err != nil {
+		return readers.MessagesPage{}, false
+	}
+	return page, true
+}
+
+// wait polls the cache until the page is cached, or the lock is released
+// without caching the page or times out.
+func (cr cachedRepository) wait(ctx context.Context, key, lock string) (readers.MessagesPage, bool) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	timeout := time.After(lockTimeout)
+	for {
+		select {
+		case <-ticker.C:
+			if page, ok := cr.get(ctx, key); ok {
+				return page, true
+			}
+			if n, err := cr.client.Exists(ctx, lock).Result(); err != nil || n == 0 {
+				return readers.MessagesPage{}, false
+			}
+		case <-timeout:
+			return readers.MessagesPage{}, false
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/readers"
+	"github.com/mainflux/mainflux/readers/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var errRead = errors.New("read failed")
+
+type repoMock struct {
+	mu    sync.Mutex
+	reads int
+	err   error
+}
+
+func (rm *repoMock) ReadAll(chanID string, pm readers.PageMetadata) (readers.MessagesPage, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	rm.reads++
+	if rm.err != nil {
+		return readers.MessagesPage{}, rm.err
+	}
+
+	// Slow down the reads to let the concurrent requests pile up.
+	time.Sleep(50 * time.Millisecond)
+	return readers.MessagesPage{
+		PageMetadata: pm,
+		Total:        1,
+		Messages:     []readers.Message{map[string]interface{}{"channel": chanID, "name": "temp"}},
+	}, nil
+}
+
+func TestReadAll(t *testing.T) {
+	cases := []struct {
+		desc   string
+		chanID string
+		pms    []readers.PageMetadata
+		err    error
+		reads  int
+	}{
+		{
+			desc:   "read same page",
+			chanID: "1",
+			pms:    []readers.PageMetadata{{Limit: 10}, {Limit: 10}, {Limit: 10}},
+			reads:  1,
+		},
+		{
+			desc:   "read different pages",
+			chanID: "2",
+			pms:    []readers.PageMetadata{{Limit: 10}, {Limit: 20}, {Offset: 10, Limit: 10}},
+			reads:  3,
+		},
+		{
+			desc:   "read sliding window within the bucket",
+			chanID: "3",
+			pms:    []readers.PageMetadata{{Limit: 10, From: 1000, To: 1300}, {Limit: 10, From: 1002, To: 1302}},
+			reads:  1,
+		},
+		{
+			desc:   "read sliding window across the buckets",
+			chanID: "4",
+			pms:    []readers.PageMetadata{{Limit: 10, From: 1000, To: 1300}, {Limit: 10, From: 1010, To: 1310}},
+			reads:  2,
+		},
+		{
+			desc:   "read failing page",
+			chanID: "5",
+			pms:    []readers.PageMetadata{{Limit: 10}, {Limit: 10}},
+			err:    errRead,
+			reads:  2,
+		},
+	}
+
+	for _, tc := range cases {
+		repo := &repoMock{err: tc.err}
+		cache := redis.NewCachedRepository(redisClient, repo, time.Minute, 5*time.Second)
+		for _, pm := range tc.pms {
+			page, err := cache.ReadAll(tc.chanID, pm)
+			assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+			if tc.err == nil {
+				assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, 1, page.Total))
+				assert.Len(t, page.Messages, 1, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, 1, len(page.Messages)))
+			}
+		}
+		assert.Equal(t, tc.reads, repo.reads, fmt.Sprintf("%s: expected %d reads got %d\n", tc.desc, tc.reads, repo.reads))
+	}
+}
+
+func TestReadAllConcurrently(t *testing.T) {
+	repo := &repoMock{}
+	cache := redis.NewCachedRepository(redisClient, repo, time.Minute, time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.ReadAll("concurrent", readers.PageMetadata{Limit: 10})
+			assert.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, repo.reads, fmt.Sprintf("concurrent reads: expected %d reads got %d\n", 1, repo.reads))
+}
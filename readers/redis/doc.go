@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis contains the message repository decorator caching the
+// results of the reader queries in Redis.
+package redis
@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package simulator contains the device simulator used for load and
+// integration testing. Simulator provisions a set of virtual things and
+// publishes generated SenML measurements on their behalf using one of the
+// supported protocol adapters.
+package simulator
@@ -0,0 +1,111 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	// Sine pattern oscillates around the base value.
+	Sine = "sine"
+	// RandomWalk pattern moves from the previous value by a random step.
+	RandomWalk = "random-walk"
+	// Burst pattern keeps the base value with occasional spikes.
+	Burst = "burst"
+)
+
+// ErrUnknownPattern indicates that the value pattern is not supported.
+var ErrUnknownPattern = errors.New("unknown value pattern")
+
+// PatternConfig contains the parameters shared by all the value patterns.
+type PatternConfig struct {
+	// Base is the value around which generated values vary.
+	Base float64
+	// Amplitude is the maximal deviation from the base value.
+	Amplitude float64
+	// Period is the sine period or the average time between bursts.
+	Period time.Duration
+}
+
+// Pattern generates the values of a simulated measurement.
+type Pattern interface {
+	// Next returns the measurement value at the given time.
+	Next(t time.Time) float64
+}
+
+// NewPattern returns the value pattern with the given name. Each pattern
+// instance keeps its own state, so every simulated thing should use its own
+// instance.
+func NewPattern(name string, cfg PatternConfig, rnd *rand.Rand) (Pattern, error) {
+	switch name {
+	case Sine:
+		return &sine{cfg: cfg, phase: rnd.Float64() * 2 * math.Pi}, nil
+	case RandomWalk:
+		return &randomWalk{cfg: cfg, rnd: rnd, value: cfg.Base}, nil
+	case Burst:
+		return &burst{cfg: cfg, rnd: rnd}, nil
+	default:
+		return nil, errors.Wrap(ErrUnknownPattern, errors.New(name))
+	}
+}
+
+type sine struct {
+	cfg   PatternConfig
+	phase float64
+}
+
+func (s *sine) Next(t time.Time) float64 {
+	if s.cfg.Period <= 0 {
+		return s.cfg.Base
+	}
+	x := 2*math.Pi*float64(t.UnixNano())/float64(s.cfg.Period) + s.phase
+	return s.cfg.Base + s.cfg.Amplitude*math.Sin(x)
+}
+
+// randomWalk stays within the amplitude from the base value by reflecting
+// the steps which would cross the bounds.
+type randomWalk struct {
+	cfg   PatternConfig
+	rnd   *rand.Rand
+	value float64
+}
+
+func (rw *randomWalk) Next(time.Time) float64 {
+	step := rw.cfg.Amplitude / 10 * (2*rw.rnd.Float64() - 1)
+	v := rw.value + step
+	if math.Abs(v-rw.cfg.Base) > rw.cfg.Amplitude {
+		v = rw.value - step
+	}
+	rw.value = v
+	return v
+}
+
+// burst spikes to the base value increased by the amplitude, on average
+// once per period. Burst lasts for a single value.
+type burst struct {
+	cfg  PatternConfig
+	rnd  *rand.Rand
+	last time.Time
+}
+
+func (b *burst) Next(t time.Time) float64 {
+	elapsed := time.Duration(0)
+	if !b.last.IsZero() {
+		elapsed = t.Sub(b.last)
+	}
+	b.last = t
+
+	if b.cfg.Period <= 0 {
+		return b.cfg.Base
+	}
+	if b.rnd.Float64() < float64(elapsed)/float64(b.cfg.Period) {
+		return b.cfg.Base + b.cfg.Amplitude
+	}
+	return b.cfg.Base
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatterns(t *testing.T) {
+	cfg := simulator.PatternConfig{
+		Base:      20,
+		Amplitude: 5,
+		Period:    time.Minute,
+	}
+
+	cases := []struct {
+		desc    string
+		pattern string
+		err     error
+	}{
+		{
+			desc:    "generate sine values",
+			pattern: simulator.Sine,
+		},
+		{
+			desc:    "generate random walk values",
+			pattern: simulator.RandomWalk,
+		},
+		{
+			desc:    "generate burst values",
+			pattern: simulator.Burst,
+		},
+		{
+			desc:    "generate values of unknown pattern",
+			pattern: "unknown",
+			err:     simulator.ErrUnknownPattern,
+		},
+	}
+
+	for _, tc := range cases {
+		p, err := simulator.NewPattern(tc.pattern, cfg, rand.New(rand.NewSource(1)))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+
+		now := time.Now()
+		for i := 0; i < 1000; i++ {
+			v := p.Next(now.Add(time.Duration(i) * time.Second))
+			assert.True(t, math.Abs(v-cfg.Base) <= cfg.Amplitude+1e-9, fmt.Sprintf("%s: value %f out of range\n", tc.desc, v))
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	now := time.Unix(1600000000, 500000000)
+	data, err := simulator.Encode("temperature", "Cel", "thing", now, 21.5)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	var pack []map[string]interface{}
+	err = json.Unmarshal(data, &pack)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expected := []map[string]interface{}{{
+		"bn": "thing:",
+		"n":  "temperature",
+		"u":  "Cel",
+		"t":  1600000000.5,
+		"v":  21.5,
+	}}
+	assert.Equal(t, expected, pack, fmt.Sprintf("expected %v got %v", expected, pack))
+}
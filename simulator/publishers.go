@@ -0,0 +1,174 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+	"github.com/plgd-dev/go-coap/v2/message"
+	"github.com/plgd-dev/go-coap/v2/message/codes"
+	"github.com/plgd-dev/go-coap/v2/udp"
+	"github.com/plgd-dev/go-coap/v2/udp/client"
+)
+
+const (
+	// HTTP protocol publishes messages using the HTTP adapter.
+	HTTP = "http"
+	// MQTT protocol publishes messages using the MQTT adapter.
+	MQTT = "mqtt"
+	// CoAP protocol publishes messages using the CoAP adapter.
+	CoAP = "coap"
+
+	mqttTimeout = 10 * time.Second
+)
+
+var (
+	// ErrUnknownProtocol indicates that the protocol is not supported.
+	ErrUnknownProtocol = errors.New("unknown protocol")
+
+	// ErrConnect indicates failure to connect the thing to the adapter.
+	ErrConnect = errors.New("failed to connect to adapter")
+
+	// ErrPublish indicates failure to publish the message.
+	ErrPublish = errors.New("failed to publish message")
+)
+
+// Thing represents a provisioned thing along with the channel it publishes to.
+type Thing struct {
+	ID        string
+	Key       string
+	ChannelID string
+}
+
+// Publisher publishes messages on behalf of a single thing.
+type Publisher interface {
+	// Publish publishes the SenML JSON encoded payload to the thing channel.
+	Publish(ctx context.Context, payload []byte) error
+
+	// Close releases the publisher resources.
+	Close() error
+}
+
+// Dialer connects the thing to the protocol adapter.
+type Dialer func(th Thing) (Publisher, error)
+
+// NewDialer returns the dialer of the protocol adapter located at the URL.
+// HTTP dialer uses the SDK configured with the HTTP adapter URL instead.
+func NewDialer(protocol, url string, s sdk.SDK) (Dialer, error) {
+	switch protocol {
+	case HTTP:
+		return func(th Thing) (Publisher, error) {
+			return httpPublisher{sdk: s, th: th}, nil
+		}, nil
+	case MQTT:
+		return func(th Thing) (Publisher, error) {
+			return dialMQTT(url, th)
+		}, nil
+	case CoAP:
+		return func(th Thing) (Publisher, error) {
+			return dialCoAP(url, th)
+		}, nil
+	default:
+		return nil, errors.Wrap(ErrUnknownProtocol, errors.New(protocol))
+	}
+}
+
+type httpPublisher struct {
+	sdk sdk.SDK
+	th  Thing
+}
+
+func (hp httpPublisher) Publish(_ context.Context, payload []byte) error {
+	return hp.sdk.SendMessage(hp.th.ChannelID, string(payload), hp.th.Key)
+}
+
+func (hp httpPublisher) Close() error {
+	return nil
+}
+
+type mqttPublisher struct {
+	client mqtt.Client
+	topic  string
+}
+
+func dialMQTT(url string, th Thing) (Publisher, error) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(url).
+		SetClientID(fmt.Sprintf("simulator-%s", th.ID)).
+		SetUsername(th.ID).
+		SetPassword(th.Key).
+		SetCleanSession(true).
+		SetAutoReconnect(true)
+
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(mqttTimeout) {
+		return nil, errors.Wrap(ErrConnect, errors.New("connection timed out"))
+	}
+	if err := token.Error(); err != nil {
+		return nil, errors.Wrap(ErrConnect, err)
+	}
+
+	return mqttPublisher{
+		client: client,
+		topic:  fmt.Sprintf("channels/%s/messages", th.ChannelID),
+	}, nil
+}
+
+func (mp mqttPublisher) Publish(_ context.Context, payload []byte) error {
+	token := mp.client.Publish(mp.topic, 0, false, payload)
+	if !token.WaitTimeout(mqttTimeout) {
+		return errors.Wrap(ErrPublish, errors.New("publish timed out"))
+	}
+	if err := token.Error(); err != nil {
+		return errors.Wrap(ErrPublish, err)
+	}
+	return nil
+}
+
+func (mp mqttPublisher) Close() error {
+	mp.client.Disconnect(0)
+	return nil
+}
+
+type coapPublisher struct {
+	conn *client.ClientConn
+	path string
+	auth string
+}
+
+func dialCoAP(addr string, th Thing) (Publisher, error) {
+	conn, err := udp.Dial(addr)
+	if err != nil {
+		return nil, errors.Wrap(ErrConnect, err)
+	}
+
+	return coapPublisher{
+		conn: conn,
+		path: fmt.Sprintf("/channels/%s/messages", th.ChannelID),
+		auth: fmt.Sprintf("auth=%s", th.Key),
+	}, nil
+}
+
+func (cp coapPublisher) Publish(ctx context.Context, payload []byte) error {
+	opt := message.Option{ID: message.URIQuery, Value: []byte(cp.auth)}
+	res, err := cp.conn.Post(ctx, cp.path, message.AppJSON, bytes.NewReader(payload), opt)
+	if err != nil {
+		return errors.Wrap(ErrPublish, err)
+	}
+	if res.Code() >= codes.BadRequest {
+		return errors.Wrap(ErrPublish, errors.New(res.Code().String()))
+	}
+	return nil
+}
+
+func (cp coapPublisher) Close() error {
+	return cp.conn.Close()
+}
@@ -0,0 +1,255 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/errors"
+	sdk "github.com/mainflux/mainflux/pkg/sdk/go"
+)
+
+const (
+	namePrefix = "simulator"
+
+	// maxRate is the highest rate the publishing interval can be
+	// represented at, as the interval can't be shorter than a nanosecond.
+	maxRate = float64(time.Second)
+)
+
+var (
+	// ErrProvision indicates failure to provision the simulated things.
+	ErrProvision = errors.New("failed to provision simulated things")
+
+	// ErrMalformedConfig indicates invalid simulator configuration.
+	ErrMalformedConfig = errors.New("malformed simulator configuration")
+)
+
+// Config contains simulation parameters.
+type Config struct {
+	// Things is the number of simulated things.
+	Things int
+	// Channels is the number of channels the things are evenly spread over.
+	Channels int
+	// Rate is the number of messages published by each thing per second,
+	// up to a billion.
+	Rate float64
+	// Duration limits the simulation duration; zero means until canceled.
+	Duration time.Duration
+	// Pattern is the name of the value pattern.
+	Pattern string
+	// PatternConfig contains the value pattern parameters.
+	PatternConfig PatternConfig
+	// Name is the SenML name of the published measurement.
+	Name string
+	// Unit is the SenML unit of the published measurement.
+	Unit string
+}
+
+func (cfg Config) validate() error {
+	if cfg.Things < 1 || cfg.Channels < 1 || !(cfg.Rate > 0 && cfg.Rate <= maxRate) || cfg.Duration < 0 {
+		return ErrMalformedConfig
+	}
+	if _, err := NewPattern(cfg.Pattern, cfg.PatternConfig, rand.New(rand.NewSource(0))); err != nil {
+		return errors.Wrap(ErrMalformedConfig, err)
+	}
+	return nil
+}
+
+// Stats contains the simulation results.
+type Stats struct {
+	Published uint64
+	Failed    uint64
+	Elapsed   time.Duration
+}
+
+// Metrics is notified about each publish attempt.
+type Metrics interface {
+	Observe(thingID string, latency time.Duration, err error)
+}
+
+// Simulator provisions simulated things and publishes their measurements.
+type Simulator struct {
+	cfg     Config
+	sdk     sdk.SDK
+	dial    Dialer
+	metrics Metrics
+	logger  logger.Logger
+}
+
+// New returns new simulator instance.
+func New(cfg Config, s sdk.SDK, dial Dialer, metrics Metrics, logger logger.Logger) (*Simulator, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Simulator{
+		cfg:     cfg,
+		sdk:     s,
+		dial:    dial,
+		metrics: metrics,
+		logger:  logger,
+	}, nil
+}
+
+// Provision creates the configured number of things and channels using the
+// given user token and connects each thing to one of the channels.
+func (s *Simulator) Provision(token string) ([]Thing, error) {
+	run := time.Now().Unix()
+
+	var chs []string
+	for i := 0; i < s.cfg.Channels; i++ {
+		name := fmt.Sprintf("%s-%d-channel-%d", namePrefix, run, i)
+		id, err := s.sdk.CreateChannel(sdk.Channel{Name: name}, token)
+		if err != nil {
+			return nil, errors.Wrap(ErrProvision, err)
+		}
+		chs = append(chs, id)
+	}
+
+	req := make([]sdk.Thing, s.cfg.Things)
+	for i := range req {
+		req[i] = sdk.Thing{
+			Name:     fmt.Sprintf("%s-%d-thing-%d", namePrefix, run, i),
+			Metadata: map[string]interface{}{"simulator": true},
+		}
+	}
+	created, err := s.sdk.CreateThings(req, token)
+	if err != nil {
+		return nil, errors.Wrap(ErrProvision, err)
+	}
+
+	conns := make(map[string][]string)
+	var ths []Thing
+	for i, th := range created {
+		chID := chs[i%len(chs)]
+		conns[chID] = append(conns[chID], th.ID)
+		ths = append(ths, Thing{ID: th.ID, Key: th.Key, ChannelID: chID})
+	}
+
+	for chID, ids := range conns {
+		if err := s.sdk.Connect(sdk.ConnectionIDs{ChannelIDs: []string{chID}, ThingIDs: ids}, token); err != nil {
+			return nil, errors.Wrap(ErrProvision, err)
+		}
+	}
+
+	return ths, nil
+}
+
+// Run publishes the measurements of the given things until the configured
+// duration elapses or the context is canceled.
+func (s *Simulator) Run(ctx context.Context, ths []Thing) (Stats, error) {
+	if s.cfg.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Duration)
+		defer cancel()
+	}
+
+	pubs := make([]Publisher, len(ths))
+	for i, th := range ths {
+		pub, err := s.dial(th)
+		if err != nil {
+			for _, p := range pubs[:i] {
+				p.Close()
+			}
+			return Stats{}, err
+		}
+		pubs[i] = pub
+	}
+
+	var (
+		mu    sync.Mutex
+		stats Stats
+		wg    sync.WaitGroup
+	)
+	start := time.Now()
+	interval := time.Duration(float64(time.Second) / s.cfg.Rate)
+
+	for i, th := range ths {
+		wg.Add(1)
+		rnd := rand.New(rand.NewSource(time.Now().UnixNano() + int64(i)))
+		pattern, _ := NewPattern(s.cfg.Pattern, s.cfg.PatternConfig, rnd)
+
+		go func(th Thing, pub Publisher, pattern Pattern, offset time.Duration) {
+			defer wg.Done()
+			defer pub.Close()
+
+			// Spread the first messages of the things over the interval
+			// to avoid publishing all of them at once.
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(offset):
+			}
+
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				err := s.publish(ctx, th, pub, pattern)
+				mu.Lock()
+				if err != nil {
+					stats.Failed++
+				} else {
+					stats.Published++
+				}
+				mu.Unlock()
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(th, pubs[i], pattern, time.Duration(rnd.Int63n(int64(interval)+1)))
+	}
+
+	wg.Wait()
+	stats.Elapsed = time.Since(start)
+	return stats, nil
+}
+
+func (s *Simulator) publish(ctx context.Context, th Thing, pub Publisher, pattern Pattern) error {
+	now := time.Now()
+	payload, err := Encode(s.cfg.Name, s.cfg.Unit, th.ID, now, pattern.Next(now))
+	if err != nil {
+		return err
+	}
+
+	err = pub.Publish(ctx, payload)
+	if err != nil && ctx.Err() == nil {
+		s.logger.Warn(fmt.Sprintf("Failed to publish message of thing %s: %s", th.ID, err))
+	}
+	if s.metrics != nil {
+		s.metrics.Observe(th.ID, time.Since(now), err)
+	}
+	return err
+}
+
+type record struct {
+	BaseName string  `json:"bn"`
+	Name     string  `json:"n"`
+	Unit     string  `json:"u,omitempty"`
+	Time     float64 `json:"t"`
+	Value    float64 `json:"v"`
+}
+
+// Encode returns the SenML JSON pack containing a single measurement. The
+// thing ID is used as the base name, so the records of different things
+// are distinguishable in the storage.
+func Encode(name, unit, thingID string, t time.Time, value float64) ([]byte, error) {
+	pack := []record{{
+		BaseName: fmt.Sprintf("%s:", thingID),
+		Name:     name,
+		Unit:     unit,
+		Time:     float64(t.UnixNano()) / float64(time.Second),
+		Value:    value,
+	}}
+	return json.Marshal(pack)
+}
@@ -37,7 +37,7 @@ func TestCanAccessByKey(t *testing.T) {
 	chs, err := svc.CreateChannels(context.Background(), token, channel)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	ch := chs[0]
-	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th1.ID})
+	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th1.ID}, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	usersAddr := fmt.Sprintf("localhost:%d", port)
@@ -99,7 +99,7 @@ func TestCanAccessByID(t *testing.T) {
 	chs, err := svc.CreateChannels(context.Background(), token, channel)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	ch := chs[0]
-	svc.Connect(context.Background(), token, []string{ch.ID}, []string{th2.ID})
+	svc.Connect(context.Background(), token, []string{ch.ID}, []string{th2.ID}, things.ConnectionMetadata{})
 
 	usersAddr := fmt.Sprintf("localhost:%d", port)
 	conn, err := grpc.Dial(usersAddr, grpc.WithInsecure())
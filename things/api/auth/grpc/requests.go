@@ -6,8 +6,9 @@ package grpc
 import "github.com/mainflux/mainflux/things"
 
 type AccessByKeyReq struct {
-	thingKey string
-	chanID   string
+	thingKey  string
+	chanID    string
+	operation string
 }
 
 func (req AccessByKeyReq) validate() error {
@@ -19,8 +20,9 @@ func (req AccessByKeyReq) validate() error {
 }
 
 type accessByIDReq struct {
-	thingID string
-	chanID  string
+	thingID   string
+	chanID    string
+	operation string
 }
 
 func (req accessByIDReq) validate() error {
@@ -10,6 +10,7 @@ import (
 	kitgrpc "github.com/go-kit/kit/transport/grpc"
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/things"
 	opentracing "github.com/opentracing/opentracing-go"
 	"google.golang.org/grpc/codes"
@@ -89,12 +90,12 @@ func (gs *grpcServer) Identify(ctx context.Context, req *mainflux.Token) (*mainf
 
 func decodeCanAccessByKeyRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AccessByKeyReq)
-	return AccessByKeyReq{thingKey: req.GetToken(), chanID: req.GetChanID()}, nil
+	return AccessByKeyReq{thingKey: req.GetToken(), chanID: req.GetChanID(), operation: req.GetOperation()}, nil
 }
 
 func decodeCanAccessByIDRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
 	req := grpcReq.(*mainflux.AccessByIDReq)
-	return accessByIDReq{thingID: req.GetThingID(), chanID: req.GetChanID()}, nil
+	return accessByIDReq{thingID: req.GetThingID(), chanID: req.GetChanID(), operation: req.GetOperation()}, nil
 }
 
 func decodeIsChannelOwnerRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
@@ -118,6 +119,10 @@ func encodeEmptyResponse(_ context.Context, grpcRes interface{}) (interface{}, e
 }
 
 func encodeError(err error) error {
+	if errors.Contains(err, things.ErrQuotaExceeded) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
 	switch err {
 	case nil:
 		return nil
@@ -127,6 +132,8 @@ func encodeError(err error) error {
 		return status.Error(codes.PermissionDenied, "missing or invalid credentials provided")
 	case things.ErrEntityConnected:
 		return status.Error(codes.PermissionDenied, "entities are not connected")
+	case things.ErrOperationNotAllowed:
+		return status.Error(codes.PermissionDenied, "operation not allowed by connection role")
 	case things.ErrNotFound:
 		return status.Error(codes.NotFound, "entity does not exist")
 	default:
@@ -19,10 +19,11 @@ import (
 )
 
 const (
-	port  = 8080
-	token = "token"
-	wrong = "wrong"
-	email = "john.doe@email.com"
+	port         = 8080
+	token        = "token"
+	webhookLimit = 10
+	wrong        = "wrong"
+	email        = "john.doe@email.com"
 )
 
 var svc things.Service
@@ -47,9 +48,10 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	webhooksRepo := mocks.NewWebhookRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, webhooksRepo, webhookLimit, chanCache, thingCache, idProvider, nil)
 }
@@ -24,10 +24,11 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	email       = "user@example.com"
-	token       = "token"
-	wrong       = "wrong_value"
+	contentType  = "application/json"
+	email        = "user@example.com"
+	token        = "token"
+	webhookLimit = 10
+	wrong        = "wrong_value"
 )
 
 var (
@@ -71,11 +72,12 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	webhooksRepo := mocks.NewWebhookRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, webhooksRepo, webhookLimit, chanCache, thingCache, idProvider, nil)
 }
 
 func newServer(svc things.Service) *httptest.Server {
@@ -156,7 +158,7 @@ func TestCanAccessByKey(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("failed to create channel: %s", err))
 	ch := chs[0]
 
-	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID}, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("failed to connect thing and channel: %s", err))
 
 	data := toJSON(canAccessByKeyReq{
@@ -234,7 +236,7 @@ func TestCanAccessByID(t *testing.T) {
 	require.Nil(t, err, fmt.Sprintf("failed to create channel: %s", err))
 	ch := chs[0]
 
-	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID}, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("failed to connect thing and channel: %s", err))
 
 	data := toJSON(canAccessByIDReq{
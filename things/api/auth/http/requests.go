@@ -18,8 +18,9 @@ func (req identifyReq) validate() error {
 }
 
 type canAccessByKeyReq struct {
-	chanID string
-	Token  string `json:"token"`
+	chanID    string
+	Token     string `json:"token"`
+	Operation string `json:"operation,omitempty"`
 }
 
 func (req canAccessByKeyReq) validate() error {
@@ -31,8 +32,9 @@ func (req canAccessByKeyReq) validate() error {
 }
 
 type canAccessByIDReq struct {
-	chanID  string
-	ThingID string `json:"thing_id"`
+	chanID    string
+	ThingID   string `json:"thing_id"`
+	Operation string `json:"operation,omitempty"`
 }
 
 func (req canAccessByIDReq) validate() error {
@@ -115,12 +115,18 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 
+	if errors.Contains(err, things.ErrQuotaExceeded) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
 	switch err {
 	case things.ErrUnauthorizedAccess:
 		w.WriteHeader(http.StatusUnauthorized)
 	case things.ErrNotFound:
 		w.WriteHeader(http.StatusNotFound)
-	case things.ErrEntityConnected:
+	case things.ErrEntityConnected,
+		things.ErrOperationNotAllowed:
 		w.WriteHeader(http.StatusForbidden)
 
 	case errors.ErrUnsupportedContentType:
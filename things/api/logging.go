@@ -28,7 +28,7 @@ func LoggingMiddleware(svc things.Service, logger log.Logger) things.Service {
 
 func (lm *loggingMiddleware) CreateThings(ctx context.Context, token string, ths ...things.Thing) (saved []things.Thing, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method create_things for token %s and things %s took %s to complete", token, saved, time.Since(begin))
+		message := fmt.Sprintf("Method create_things for token %s and things %v took %s to complete", token, saved, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -135,7 +135,7 @@ func (lm *loggingMiddleware) RemoveThing(ctx context.Context, token, id string)
 
 func (lm *loggingMiddleware) CreateChannels(ctx context.Context, token string, channels ...things.Channel) (saved []things.Channel, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method create_channels for token %s and channels %s took %s to complete", token, saved, time.Since(begin))
+		message := fmt.Sprintf("Method create_channels for token %s and channels %v took %s to complete", token, saved, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -214,9 +214,9 @@ func (lm *loggingMiddleware) RemoveChannel(ctx context.Context, token, id string
 	return lm.svc.RemoveChannel(ctx, token, id)
 }
 
-func (lm *loggingMiddleware) Connect(ctx context.Context, token string, chIDs, thIDs []string) (err error) {
+func (lm *loggingMiddleware) Connect(ctx context.Context, token string, chIDs, thIDs []string, meta things.ConnectionMetadata) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method connect for token %s, channels %s and things %s took %s to complete", token, chIDs, thIDs, time.Since(begin))
+		message := fmt.Sprintf("Method connect for token %s, channels %s and things %s with role %q took %s to complete", token, chIDs, thIDs, meta.Role, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -224,7 +224,7 @@ func (lm *loggingMiddleware) Connect(ctx context.Context, token string, chIDs, t
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.Connect(ctx, token, chIDs, thIDs)
+	return lm.svc.Connect(ctx, token, chIDs, thIDs, meta)
 }
 
 func (lm *loggingMiddleware) Disconnect(ctx context.Context, token string, chIDs, thIDs []string) (err error) {
@@ -240,9 +240,9 @@ func (lm *loggingMiddleware) Disconnect(ctx context.Context, token string, chIDs
 	return lm.svc.Disconnect(ctx, token, chIDs, thIDs)
 }
 
-func (lm *loggingMiddleware) CanAccessByKey(ctx context.Context, id, key string) (thing string, err error) {
+func (lm *loggingMiddleware) CanAccessByKey(ctx context.Context, id, key, op string) (thing string, err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method can_access for channel %s and thing %s took %s to complete", id, thing, time.Since(begin))
+		message := fmt.Sprintf("Method can_access for channel %s, thing %s and operation %q took %s to complete", id, thing, op, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -250,12 +250,12 @@ func (lm *loggingMiddleware) CanAccessByKey(ctx context.Context, id, key string)
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.CanAccessByKey(ctx, id, key)
+	return lm.svc.CanAccessByKey(ctx, id, key, op)
 }
 
-func (lm *loggingMiddleware) CanAccessByID(ctx context.Context, chanID, thingID string) (err error) {
+func (lm *loggingMiddleware) CanAccessByID(ctx context.Context, chanID, thingID, op string) (err error) {
 	defer func(begin time.Time) {
-		message := fmt.Sprintf("Method can_access_by_id for channel %s and thing %s took %s to complete", chanID, thingID, time.Since(begin))
+		message := fmt.Sprintf("Method can_access_by_id for channel %s, thing %s and operation %q took %s to complete", chanID, thingID, op, time.Since(begin))
 		if err != nil {
 			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
 			return
@@ -263,7 +263,7 @@ func (lm *loggingMiddleware) CanAccessByID(ctx context.Context, chanID, thingID
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.CanAccessByID(ctx, chanID, thingID)
+	return lm.svc.CanAccessByID(ctx, chanID, thingID, op)
 }
 
 func (lm *loggingMiddleware) IsChannelOwner(ctx context.Context, owner, chanID string) (err error) {
@@ -304,3 +304,55 @@ func (lm *loggingMiddleware) ListMembers(ctx context.Context, token, groupID str
 
 	return lm.svc.ListMembers(ctx, token, groupID, pm)
 }
+
+func (lm *loggingMiddleware) CreateWebhook(ctx context.Context, token string, w things.Webhook) (saved things.Webhook, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method create_webhook for token %s and channel %s took %s to complete", token, w.ChannelID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.CreateWebhook(ctx, token, w)
+}
+
+func (lm *loggingMiddleware) ListWebhooks(ctx context.Context, token, chID string) (whs []things.Webhook, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_webhooks for token %s and channel %s took %s to complete", token, chID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListWebhooks(ctx, token, chID)
+}
+
+func (lm *loggingMiddleware) RemoveWebhook(ctx context.Context, token, chID, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method remove_webhook for token %s and webhook %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RemoveWebhook(ctx, token, chID, id)
+}
+
+func (lm *loggingMiddleware) ListDeliveries(ctx context.Context, token, chID, id string, pm things.PageMetadata) (dp things.DeliveriesPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_deliveries for token %s and webhook %s took %s to complete", token, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListDeliveries(ctx, token, chID, id, pm)
+}
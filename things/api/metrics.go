@@ -156,13 +156,13 @@ func (ms *metricsMiddleware) RemoveChannel(ctx context.Context, token, id string
 	return ms.svc.RemoveChannel(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) Connect(ctx context.Context, token string, chIDs, thIDs []string) error {
+func (ms *metricsMiddleware) Connect(ctx context.Context, token string, chIDs, thIDs []string, meta things.ConnectionMetadata) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "connect").Add(1)
 		ms.latency.With("method", "connect").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.Connect(ctx, token, chIDs, thIDs)
+	return ms.svc.Connect(ctx, token, chIDs, thIDs, meta)
 }
 
 func (ms *metricsMiddleware) Disconnect(ctx context.Context, token string, chIDs, thIDs []string) error {
@@ -174,22 +174,22 @@ func (ms *metricsMiddleware) Disconnect(ctx context.Context, token string, chIDs
 	return ms.svc.Disconnect(ctx, token, chIDs, thIDs)
 }
 
-func (ms *metricsMiddleware) CanAccessByKey(ctx context.Context, id, key string) (string, error) {
+func (ms *metricsMiddleware) CanAccessByKey(ctx context.Context, id, key, op string) (string, error) {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "can_access_by_key").Add(1)
 		ms.latency.With("method", "can_access_by_key").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.CanAccessByKey(ctx, id, key)
+	return ms.svc.CanAccessByKey(ctx, id, key, op)
 }
 
-func (ms *metricsMiddleware) CanAccessByID(ctx context.Context, chanID, thingID string) error {
+func (ms *metricsMiddleware) CanAccessByID(ctx context.Context, chanID, thingID, op string) error {
 	defer func(begin time.Time) {
 		ms.counter.With("method", "can_access_by_id").Add(1)
 		ms.latency.With("method", "can_access_by_id").Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.CanAccessByID(ctx, chanID, thingID)
+	return ms.svc.CanAccessByID(ctx, chanID, thingID, op)
 }
 
 func (ms *metricsMiddleware) IsChannelOwner(ctx context.Context, owner, chanID string) error {
@@ -218,3 +218,39 @@ func (ms *metricsMiddleware) ListMembers(ctx context.Context, token, groupID str
 
 	return ms.svc.ListMembers(ctx, token, groupID, pm)
 }
+
+func (ms *metricsMiddleware) CreateWebhook(ctx context.Context, token string, w things.Webhook) (things.Webhook, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "create_webhook").Add(1)
+		ms.latency.With("method", "create_webhook").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.CreateWebhook(ctx, token, w)
+}
+
+func (ms *metricsMiddleware) ListWebhooks(ctx context.Context, token, chID string) ([]things.Webhook, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_webhooks").Add(1)
+		ms.latency.With("method", "list_webhooks").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListWebhooks(ctx, token, chID)
+}
+
+func (ms *metricsMiddleware) RemoveWebhook(ctx context.Context, token, chID, id string) error {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "remove_webhook").Add(1)
+		ms.latency.With("method", "remove_webhook").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RemoveWebhook(ctx, token, chID, id)
+}
+
+func (ms *metricsMiddleware) ListDeliveries(ctx context.Context, token, chID, id string, pm things.PageMetadata) (things.DeliveriesPage, error) {
+	defer func(begin time.Time) {
+		ms.counter.With("method", "list_deliveries").Add(1)
+		ms.latency.With("method", "list_deliveries").Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListDeliveries(ctx, token, chID, id, pm)
+}
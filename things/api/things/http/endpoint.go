@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/jobs"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/things"
 )
@@ -25,6 +26,7 @@ func createThingEndpoint(svc things.Service) endpoint.Endpoint {
 			ID:       req.ID,
 			Name:     req.Name,
 			Metadata: req.Metadata,
+			Labels:   req.Labels,
 		}
 		saved, err := svc.CreateThings(ctx, req.token, th)
 		if err != nil {
@@ -40,7 +42,7 @@ func createThingEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
-func createThingsEndpoint(svc things.Service) endpoint.Endpoint {
+func createThingsEndpoint(svc things.Service, jsvc things.JobService) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(createThingsReq)
 
@@ -55,10 +57,21 @@ func createThingsEndpoint(svc things.Service) endpoint.Endpoint {
 				Key:      tReq.Key,
 				ID:       tReq.ID,
 				Metadata: tReq.Metadata,
+				Labels:   tReq.Labels,
 			}
 			ths = append(ths, th)
 		}
 
+		if req.async {
+			job, err := jsvc.CreateThingsAsync(ctx, req.token, ths...)
+			if err != nil {
+				return nil, err
+			}
+			res := toJobRes(job)
+			res.created = true
+			return res, nil
+		}
+
 		saved, err := svc.CreateThings(ctx, req.token, ths...)
 		if err != nil {
 			return nil, err
@@ -75,6 +88,7 @@ func createThingsEndpoint(svc things.Service) endpoint.Endpoint {
 				Name:     th.Name,
 				Key:      th.Key,
 				Metadata: th.Metadata,
+				Labels:   th.Labels,
 			}
 			res.Things = append(res.Things, tRes)
 		}
@@ -83,6 +97,56 @@ func createThingsEndpoint(svc things.Service) endpoint.Endpoint {
 	}
 }
 
+func viewJobEndpoint(jsvc things.JobService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		job, err := jsvc.ViewJob(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		return toJobRes(job), nil
+	}
+}
+
+func viewJobResultEndpoint(jsvc things.JobService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		job, err := jsvc.ViewJob(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+		if job.Status != jobs.StatusCompleted {
+			return nil, jobs.ErrNotCompleted
+		}
+
+		return jobResultRes{result: job.Result}, nil
+	}
+}
+
+func toJobRes(job jobs.Job) jobRes {
+	return jobRes{
+		ID:        job.ID,
+		Kind:      job.Kind,
+		Status:    job.Status,
+		Total:     job.Total,
+		Done:      job.Done,
+		Failed:    job.Failed,
+		Errors:    job.Errors,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+}
+
 func shareThingEndpoint(svc things.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(shareThingReq)
@@ -110,6 +174,7 @@ func updateThingEndpoint(svc things.Service) endpoint.Endpoint {
 			ID:       req.id,
 			Name:     req.Name,
 			Metadata: req.Metadata,
+			Labels:   req.Labels,
 		}
 
 		if err := svc.UpdateThing(ctx, req.token, thing); err != nil {
@@ -157,6 +222,7 @@ func viewThingEndpoint(svc things.Service) endpoint.Endpoint {
 			Name:     thing.Name,
 			Key:      thing.Key,
 			Metadata: thing.Metadata,
+			Labels:   thing.Labels,
 		}
 		return res, nil
 	}
@@ -192,6 +258,7 @@ func listThingsEndpoint(svc things.Service) endpoint.Endpoint {
 				Name:     thing.Name,
 				Key:      thing.Key,
 				Metadata: thing.Metadata,
+				Labels:   thing.Labels,
 			}
 			res.Things = append(res.Things, view)
 		}
@@ -223,11 +290,13 @@ func listThingsByChannelEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 		for _, thing := range page.Things {
 			view := viewThingRes{
-				ID:       thing.ID,
-				Owner:    thing.Owner,
-				Key:      thing.Key,
-				Name:     thing.Name,
-				Metadata: thing.Metadata,
+				ID:         thing.ID,
+				Owner:      thing.Owner,
+				Key:        thing.Key,
+				Name:       thing.Name,
+				Metadata:   thing.Metadata,
+				Labels:     thing.Labels,
+				Connection: toConnectionRes(thing.Connection),
 			}
 			res.Things = append(res.Things, view)
 		}
@@ -268,7 +337,9 @@ func createChannelEndpoint(svc things.Service) endpoint.Endpoint {
 		ch := things.Channel{
 			Name:     req.Name,
 			ID:       req.ID,
-			Metadata: req.Metadata}
+			Metadata: req.Metadata,
+			Labels:   req.Labels,
+		}
 
 		saved, err := svc.CreateChannels(ctx, req.token, ch)
 		if err != nil {
@@ -295,6 +366,7 @@ func createChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 		for _, cReq := range req.Channels {
 			ch := things.Channel{
 				Metadata: cReq.Metadata,
+				Labels:   cReq.Labels,
 				Name:     cReq.Name,
 				ID:       cReq.ID,
 			}
@@ -316,6 +388,7 @@ func createChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 				ID:       ch.ID,
 				Name:     ch.Name,
 				Metadata: ch.Metadata,
+				Labels:   ch.Labels,
 			}
 			res.Channels = append(res.Channels, cRes)
 		}
@@ -336,6 +409,7 @@ func updateChannelEndpoint(svc things.Service) endpoint.Endpoint {
 			ID:       req.id,
 			Name:     req.Name,
 			Metadata: req.Metadata,
+			Labels:   req.Labels,
 		}
 		if err := svc.UpdateChannel(ctx, req.token, channel); err != nil {
 			return nil, err
@@ -367,6 +441,7 @@ func viewChannelEndpoint(svc things.Service) endpoint.Endpoint {
 			Owner:    channel.Owner,
 			Name:     channel.Name,
 			Metadata: channel.Metadata,
+			Labels:   channel.Labels,
 		}
 
 		return res, nil
@@ -403,6 +478,7 @@ func listChannelsEndpoint(svc things.Service) endpoint.Endpoint {
 				Owner:    channel.Owner,
 				Name:     channel.Name,
 				Metadata: channel.Metadata,
+				Labels:   channel.Labels,
 			}
 
 			res.Channels = append(res.Channels, view)
@@ -435,10 +511,12 @@ func listChannelsByThingEndpoint(svc things.Service) endpoint.Endpoint {
 		}
 		for _, channel := range page.Channels {
 			view := viewChannelRes{
-				ID:       channel.ID,
-				Owner:    channel.Owner,
-				Name:     channel.Name,
-				Metadata: channel.Metadata,
+				ID:         channel.ID,
+				Owner:      channel.Owner,
+				Name:       channel.Name,
+				Metadata:   channel.Metadata,
+				Labels:     channel.Labels,
+				Connection: toConnectionRes(channel.Connection),
 			}
 			res.Channels = append(res.Channels, view)
 		}
@@ -474,7 +552,8 @@ func connectThingEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if err := svc.Connect(ctx, cr.token, []string{cr.chanID}, []string{cr.thingID}); err != nil {
+		meta := things.ConnectionMetadata{Role: cr.role, Label: cr.label}
+		if err := svc.Connect(ctx, cr.token, []string{cr.chanID}, []string{cr.thingID}, meta); err != nil {
 			return nil, err
 		}
 
@@ -490,7 +569,8 @@ func connectEndpoint(svc things.Service) endpoint.Endpoint {
 			return nil, err
 		}
 
-		if err := svc.Connect(ctx, cr.token, cr.ChannelIDs, cr.ThingIDs); err != nil {
+		meta := things.ConnectionMetadata{Role: cr.Role, Label: cr.Label}
+		if err := svc.Connect(ctx, cr.token, cr.ChannelIDs, cr.ThingIDs, meta); err != nil {
 			return nil, err
 		}
 
@@ -560,8 +640,130 @@ func buildThingsResponse(up things.Page) thingsPageRes {
 			Key:      th.Key,
 			Owner:    th.Owner,
 			Metadata: th.Metadata,
+			Labels:   th.Labels,
 		}
 		res.Things = append(res.Things, view)
 	}
 	return res
 }
+
+func toConnectionRes(conn *things.ConnectionMetadata) *connectionRes {
+	if conn == nil {
+		return nil
+	}
+
+	return &connectionRes{
+		Role:      conn.Role,
+		Label:     conn.Label,
+		CreatedBy: conn.CreatedBy,
+	}
+}
+
+func createWebhookEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(createWebhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		w := things.Webhook{
+			ChannelID: req.chanID,
+			URL:       req.URL,
+			Secret:    req.Secret,
+			Filter:    req.Filter,
+			BatchSize: req.BatchSize,
+		}
+		saved, err := svc.CreateWebhook(ctx, req.token, w)
+		if err != nil {
+			return nil, err
+		}
+
+		res := toWebhookRes(saved)
+		res.created = true
+		return res, nil
+	}
+}
+
+func listWebhooksEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewResourceReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		whs, err := svc.ListWebhooks(ctx, req.token, req.id)
+		if err != nil {
+			return nil, err
+		}
+
+		res := webhooksRes{Webhooks: []webhookRes{}}
+		for _, w := range whs {
+			res.Webhooks = append(res.Webhooks, toWebhookRes(w))
+		}
+
+		return res, nil
+	}
+}
+
+func removeWebhookEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(webhookReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		if err := svc.RemoveWebhook(ctx, req.token, req.chanID, req.id); err != nil {
+			return nil, err
+		}
+
+		return removeRes{}, nil
+	}
+}
+
+func listDeliveriesEndpoint(svc things.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listDeliveriesReq)
+
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := svc.ListDeliveries(ctx, req.token, req.chanID, req.id, req.pageMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		res := deliveriesPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Deliveries: []deliveryRes{},
+		}
+		for _, d := range page.Deliveries {
+			res.Deliveries = append(res.Deliveries, deliveryRes{
+				Messages:    d.Messages,
+				Status:      d.Status,
+				Error:       d.Error,
+				DeliveredAt: d.DeliveredAt,
+			})
+		}
+
+		return res, nil
+	}
+}
+
+func toWebhookRes(w things.Webhook) webhookRes {
+	return webhookRes{
+		ID:        w.ID,
+		ChannelID: w.ChannelID,
+		URL:       w.URL,
+		Filter:    w.Filter,
+		BatchSize: w.BatchSize,
+		CreatedAt: w.CreatedAt,
+	}
+}
@@ -11,11 +11,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/mainflux/mainflux/internal/jobs"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/mainflux/mainflux/things"
 	httpapi "github.com/mainflux/mainflux/things/api/things/http"
@@ -26,17 +28,18 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	email       = "user@example.com"
-	adminEmail  = "admin@example.com"
-	token       = "token"
-	wrongValue  = "wrong_value"
-	wrongID     = 0
-	maxNameSize = 1024
-	nameKey     = "name"
-	ascKey      = "asc"
-	descKey     = "desc"
-	prefix      = "fe6b4e92-cc98-425e-b0aa-"
+	contentType  = "application/json"
+	email        = "user@example.com"
+	adminEmail   = "admin@example.com"
+	token        = "token"
+	webhookLimit = 10
+	wrongValue   = "wrong_value"
+	wrongID      = 0
+	maxNameSize  = 1024
+	nameKey      = "name"
+	ascKey       = "asc"
+	descKey      = "desc"
+	prefix       = "fe6b4e92-cc98-425e-b0aa-"
 )
 
 var (
@@ -89,15 +92,19 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	webhooksRepo := mocks.NewWebhookRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, webhooksRepo, webhookLimit, chanCache, thingCache, idProvider, nil)
 }
 
 func newServer(svc things.Service) *httptest.Server {
-	mux := httpapi.MakeHandler(mocktracer.New(), svc)
+	auth := mocks.NewAuthService(map[string]string{token: email}, nil)
+	manager := jobs.NewManager(jobs.NewMemoryStore(), uuid.NewMock(), time.Minute, time.Hour)
+	jsvc := things.NewJobService(svc, auth, manager)
+	mux := httpapi.MakeHandler(mocktracer.New(), svc, jsvc)
 	return httptest.NewServer(mux)
 }
 
@@ -326,6 +333,104 @@ func TestCreateThings(t *testing.T) {
 	}
 }
 
+func TestCreateThingsAsync(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	// The second thing fails as a partial error, due to the existing key.
+	data := `[{"name": "1", "key": "1"}, {"name": "2", "key": "1"}, {"name": "3", "key": "3"}]`
+	req := testRequest{
+		client:      ts.Client(),
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/things/bulk?async=true", ts.URL),
+		contentType: contentType,
+		token:       token,
+		body:        strings.NewReader(data),
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, http.StatusAccepted, res.StatusCode, fmt.Sprintf("create things async: expected status code %d got %d", http.StatusAccepted, res.StatusCode))
+
+	var job jobRes
+	err = json.NewDecoder(res.Body).Decode(&job)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, fmt.Sprintf("/jobs/%s", job.ID), res.Header.Get("Location"), "create things async: expected the job location")
+
+	jobURL := fmt.Sprintf("%s/jobs/%s", ts.URL, job.ID)
+	for i := 0; i < 100 && job.Status == jobs.StatusRunning; i++ {
+		time.Sleep(10 * time.Millisecond)
+		req := testRequest{client: ts.Client(), method: http.MethodGet, url: jobURL, token: token}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		err = json.NewDecoder(res.Body).Decode(&job)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+	assert.Equal(t, jobs.StatusCompleted, job.Status, fmt.Sprintf("expected status %s got %s", jobs.StatusCompleted, job.Status))
+	assert.Equal(t, uint64(3), job.Done, fmt.Sprintf("expected 3 things done got %d", job.Done))
+	assert.Equal(t, uint64(1), job.Failed, fmt.Sprintf("expected 1 thing failed got %d", job.Failed))
+	require.Len(t, job.Errors, 1, "expected the partial error")
+	assert.Equal(t, uint64(1), job.Errors[0].Index, fmt.Sprintf("expected the partial error of the thing 1 got %d", job.Errors[0].Index))
+
+	cases := []struct {
+		desc   string
+		url    string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "view job",
+			url:    jobURL,
+			auth:   token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view job result",
+			url:    fmt.Sprintf("%s/result", jobURL),
+			auth:   token,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "view non-existent job",
+			url:    fmt.Sprintf("%s/jobs/%s", ts.URL, wrongValue),
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "view job result with invalid auth token",
+			url:    fmt.Sprintf("%s/result", jobURL),
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "view job with empty auth token",
+			url:    jobURL,
+			auth:   "",
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	req = testRequest{client: ts.Client(), method: http.MethodGet, url: fmt.Sprintf("%s/result", jobURL), token: token}
+	res, err = req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	var result thingsPageRes
+	err = json.NewDecoder(res.Body).Decode(&result)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Len(t, result.Things, 2, fmt.Sprintf("expected 2 created things got %d", len(result.Things)))
+}
+
 func TestUpdateThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
@@ -767,7 +872,7 @@ func TestListThings(t *testing.T) {
 
 	data := []thingRes{}
 	for i := 0; i < 100; i++ {
-		id := fmt.Sprintf("%s%012d", prefix, i + 1)
+		id := fmt.Sprintf("%s%012d", prefix, i+1)
 		thing1 := thing
 		thing1.ID = id
 		ths, err := svc.CreateThings(context.Background(), token, thing1)
@@ -968,6 +1073,91 @@ func TestListThings(t *testing.T) {
 	}
 }
 
+func TestListThingsBySelector(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	envs := []string{"prod", "dev"}
+	data := map[string][]thingRes{}
+	for i := 0; i < 10; i++ {
+		th := thing
+		th.ID = fmt.Sprintf("%s%012d", prefix, i+1)
+		th.Labels = map[string]string{"env": envs[i%len(envs)]}
+		if i < 2 {
+			th.Labels["deprecated"] = ""
+		}
+		ths, err := svc.CreateThings(context.Background(), token, th)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		th = ths[0]
+		res := thingRes{
+			ID:       th.ID,
+			Name:     th.Name,
+			Key:      th.Key,
+			Metadata: th.Metadata,
+			Labels:   th.Labels,
+		}
+		data[th.Labels["env"]] = append(data[th.Labels["env"]], res)
+		if i >= 2 {
+			data["current"] = append(data["current"], res)
+		}
+	}
+
+	thingURL := fmt.Sprintf("%s/things?offset=0&limit=10", ts.URL)
+	cases := []struct {
+		desc     string
+		selector string
+		status   int
+		res      []thingRes
+	}{
+		{
+			desc:     "get a list of things with equality selector",
+			selector: "env=prod",
+			status:   http.StatusOK,
+			res:      data["prod"],
+		},
+		{
+			desc:     "get a list of things with inequality selector",
+			selector: "env!=prod",
+			status:   http.StatusOK,
+			res:      data["dev"],
+		},
+		{
+			desc:     "get a list of things with missing label selector",
+			selector: "env,!deprecated",
+			status:   http.StatusOK,
+			res:      data["current"],
+		},
+		{
+			desc:     "get a list of things with non-matching selector",
+			selector: "env=staging",
+			status:   http.StatusOK,
+			res:      []thingRes{},
+		},
+		{
+			desc:     "get a list of things with invalid selector",
+			selector: "env>prod",
+			status:   http.StatusBadRequest,
+			res:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s&selector=%s", thingURL, url.QueryEscape(tc.selector)),
+			token:  token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var data thingsPageRes
+		json.NewDecoder(res.Body).Decode(&data)
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.ElementsMatch(t, tc.res, data.Things, fmt.Sprintf("%s: expected body %v got %v", tc.desc, tc.res, data.Things))
+	}
+}
+
 func TestSearchThings(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ts := newServer(svc)
@@ -1007,7 +1197,7 @@ func TestSearchThings(t *testing.T) {
 	data := []thingRes{}
 	for i := 0; i < 100; i++ {
 		name := "name_" + fmt.Sprintf("%03d", i+1)
-		id := fmt.Sprintf("%s%012d", prefix, i + 1)
+		id := fmt.Sprintf("%s%012d", prefix, i+1)
 		ths, err := svc.CreateThings(context.Background(), token, things.Thing{ID: id, Name: name, Metadata: map[string]interface{}{"test": name}})
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 		th := ths[0]
@@ -1175,13 +1365,13 @@ func TestListThingsByChannel(t *testing.T) {
 
 	data := []thingRes{}
 	for i := 0; i < 101; i++ {
-		id := fmt.Sprintf("%s%012d", prefix, i + 1)
+		id := fmt.Sprintf("%s%012d", prefix, i+1)
 		thing1 := thing
 		thing1.ID = id
 		ths, err := svc.CreateThings(context.Background(), token, thing1)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 		th := ths[0]
-		err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+		err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID}, things.ConnectionMetadata{})
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 		data = append(data, thingRes{
@@ -1735,7 +1925,7 @@ func TestViewChannel(t *testing.T) {
 	ths, err := svc.CreateThings(context.Background(), token, thing)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	th := ths[0]
-	svc.Connect(context.Background(), token, []string{sch.ID}, []string{th.ID})
+	svc.Connect(context.Background(), token, []string{sch.ID}, []string{th.ID}, things.ConnectionMetadata{})
 
 	data := toJSON(channelRes{
 		ID:       sch.ID,
@@ -1822,7 +2012,7 @@ func TestListChannels(t *testing.T) {
 		ths, err := svc.CreateThings(context.Background(), token, thing)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 		th := ths[0]
-		svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+		svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID}, things.ConnectionMetadata{})
 
 		channels = append(channels, channelRes{
 			ID:       ch.ID,
@@ -2029,13 +2219,13 @@ func TestListChannelsByThing(t *testing.T) {
 
 	channels := []channelRes{}
 	for i := 0; i < 101; i++ {
-		id := fmt.Sprintf("%s%012d", prefix, i + 1)
+		id := fmt.Sprintf("%s%012d", prefix, i+1)
 		channel1 := channel
 		channel1.ID = id
 		chs, err := svc.CreateChannels(context.Background(), token, channel1)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 		ch := chs[0]
-		err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+		err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID}, things.ConnectionMetadata{})
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 		channels = append(channels, channelRes{
@@ -2485,6 +2675,13 @@ func TestCreateConnections(t *testing.T) {
 			status:      http.StatusBadRequest,
 			body:        "{",
 		},
+		{
+			desc:        "connect with invalid connection role",
+			auth:        token,
+			contentType: contentType,
+			status:      http.StatusBadRequest,
+			body:        toJSON(map[string]interface{}{"channel_ids": chIDs1, "thing_ids": thIDs, "role": "invalid"}),
+		},
 		{
 			desc:        "connect valid thing ids with empty channel ids",
 			channelIDs:  []string{},
@@ -2571,7 +2768,7 @@ func TestDisconnectList(t *testing.T) {
 		chIDs2 = append(chIDs2, ch.ID)
 	}
 
-	err = svc.Connect(context.Background(), token, chIDs1, thIDs)
+	err = svc.Connect(context.Background(), token, chIDs1, thIDs, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := []struct {
@@ -2746,7 +2943,7 @@ func TestDisconnnect(t *testing.T) {
 	th1 := ths[0]
 	chs, _ := svc.CreateChannels(context.Background(), token, channel)
 	ch1 := chs[0]
-	svc.Connect(context.Background(), token, []string{ch1.ID}, []string{th1.ID})
+	svc.Connect(context.Background(), token, []string{ch1.ID}, []string{th1.ID}, things.ConnectionMetadata{})
 	chs, _ = svc.CreateChannels(context.Background(), otherToken, channel)
 	ch2 := chs[0]
 
@@ -2835,11 +3032,324 @@ func TestDisconnnect(t *testing.T) {
 	}
 }
 
+func TestCreateWebhook(t *testing.T) {
+	otherToken := "other_token"
+	otherEmail := "other_user@example.com"
+	svc := newService(map[string]string{
+		token:      email,
+		otherToken: otherEmail,
+	})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, _ := svc.CreateChannels(context.Background(), token, channel)
+	ch := chs[0]
+
+	data := toJSON(map[string]interface{}{"url": "http://localhost/hook", "filter": "sensors.*", "batch_size": 10})
+
+	cases := []struct {
+		desc        string
+		chanID      string
+		req         string
+		contentType string
+		auth        string
+		status      int
+	}{
+		{
+			desc:        "create webhook",
+			chanID:      ch.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusCreated,
+		},
+		{
+			desc:        "create webhook with invalid token",
+			chanID:      ch.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        wrongValue,
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "create webhook with empty token",
+			chanID:      ch.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        "",
+			status:      http.StatusUnauthorized,
+		},
+		{
+			desc:        "create webhook on channel of other user",
+			chanID:      ch.ID,
+			req:         data,
+			contentType: contentType,
+			auth:        otherToken,
+			status:      http.StatusForbidden,
+		},
+		{
+			desc:        "create webhook with invalid url",
+			chanID:      ch.ID,
+			req:         toJSON(map[string]interface{}{"url": "localhost"}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create webhook with invalid filter",
+			chanID:      ch.ID,
+			req:         toJSON(map[string]interface{}{"url": "http://localhost", "filter": "["}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create webhook with too big batch size",
+			chanID:      ch.ID,
+			req:         toJSON(map[string]interface{}{"url": "http://localhost", "batch_size": 1001}),
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create webhook with invalid request format",
+			chanID:      ch.ID,
+			req:         "}",
+			contentType: contentType,
+			auth:        token,
+			status:      http.StatusBadRequest,
+		},
+		{
+			desc:        "create webhook without content type",
+			chanID:      ch.ID,
+			req:         data,
+			contentType: "",
+			auth:        token,
+			status:      http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      ts.Client(),
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/channels/%s/webhooks", ts.URL, tc.chanID),
+			contentType: tc.contentType,
+			token:       tc.auth,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestListWebhooks(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, _ := svc.CreateChannels(context.Background(), token, channel)
+	ch := chs[0]
+	w, err := svc.CreateWebhook(context.Background(), token, things.Webhook{ChannelID: ch.ID, URL: "http://localhost"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		chanID string
+		auth   string
+		status int
+		size   int
+	}{
+		{
+			desc:   "list webhooks",
+			chanID: ch.ID,
+			auth:   token,
+			status: http.StatusOK,
+			size:   1,
+		},
+		{
+			desc:   "list webhooks with invalid token",
+			chanID: ch.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+			size:   0,
+		},
+		{
+			desc:   "list webhooks with empty token",
+			chanID: ch.ID,
+			auth:   "",
+			status: http.StatusUnauthorized,
+			size:   0,
+		},
+		{
+			desc:   "list webhooks of non-existing channel",
+			chanID: wrongValue,
+			auth:   token,
+			status: http.StatusOK,
+			size:   0,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/webhooks", ts.URL, tc.chanID),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		var body webhooksRes
+		json.NewDecoder(res.Body).Decode(&body)
+		assert.Equal(t, tc.size, len(body.Webhooks), fmt.Sprintf("%s: expected %d webhooks got %d", tc.desc, tc.size, len(body.Webhooks)))
+		if tc.size > 0 {
+			assert.Equal(t, w.ID, body.Webhooks[0].ID, fmt.Sprintf("%s: expected webhook %s got %s", tc.desc, w.ID, body.Webhooks[0].ID))
+		}
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, _ := svc.CreateChannels(context.Background(), token, channel)
+	ch := chs[0]
+	w, err := svc.CreateWebhook(context.Background(), token, things.Webhook{ChannelID: ch.ID, URL: "http://localhost"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		chanID string
+		id     string
+		auth   string
+		status int
+	}{
+		{
+			desc:   "remove webhook with invalid token",
+			chanID: ch.ID,
+			id:     w.ID,
+			auth:   wrongValue,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "remove webhook of other channel",
+			chanID: wrongValue,
+			id:     w.ID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+		{
+			desc:   "remove webhook",
+			chanID: ch.ID,
+			id:     w.ID,
+			auth:   token,
+			status: http.StatusNoContent,
+		},
+		{
+			desc:   "remove removed webhook",
+			chanID: ch.ID,
+			id:     w.ID,
+			auth:   token,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodDelete,
+			url:    fmt.Sprintf("%s/channels/%s/webhooks/%s", ts.URL, tc.chanID, tc.id),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	svc := newService(map[string]string{token: email})
+	ts := newServer(svc)
+	defer ts.Close()
+
+	chs, _ := svc.CreateChannels(context.Background(), token, channel)
+	ch := chs[0]
+	w, err := svc.CreateWebhook(context.Background(), token, things.Webhook{ChannelID: ch.ID, URL: "http://localhost"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		chanID string
+		id     string
+		auth   string
+		limit  uint64
+		status int
+	}{
+		{
+			desc:   "list deliveries",
+			chanID: ch.ID,
+			id:     w.ID,
+			auth:   token,
+			limit:  5,
+			status: http.StatusOK,
+		},
+		{
+			desc:   "list deliveries with invalid token",
+			chanID: ch.ID,
+			id:     w.ID,
+			auth:   wrongValue,
+			limit:  5,
+			status: http.StatusUnauthorized,
+		},
+		{
+			desc:   "list deliveries with zero limit",
+			chanID: ch.ID,
+			id:     w.ID,
+			auth:   token,
+			limit:  0,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list deliveries with limit greater than max",
+			chanID: ch.ID,
+			id:     w.ID,
+			auth:   token,
+			limit:  110,
+			status: http.StatusBadRequest,
+		},
+		{
+			desc:   "list deliveries of non-existing webhook",
+			chanID: ch.ID,
+			id:     wrongValue,
+			auth:   token,
+			limit:  5,
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: ts.Client(),
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/channels/%s/webhooks/%s/deliveries?limit=%d", ts.URL, tc.chanID, tc.id, tc.limit),
+			token:  tc.auth,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
 type thingRes struct {
 	ID       string                 `json:"id"`
 	Name     string                 `json:"name,omitempty"`
 	Key      string                 `json:"key"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   map[string]string      `json:"labels,omitempty"`
 }
 
 type channelRes struct {
@@ -2862,6 +3372,22 @@ type channelsPageRes struct {
 	Limit    uint64       `json:"limit"`
 }
 
+type webhookRes struct {
+	ID string `json:"id"`
+}
+
+type webhooksRes struct {
+	Webhooks []webhookRes `json:"webhooks"`
+}
+
+type jobRes struct {
+	ID     string       `json:"id"`
+	Status string       `json:"status"`
+	Done   uint64       `json:"done"`
+	Failed uint64       `json:"failed"`
+	Errors []jobs.Error `json:"errors"`
+}
+
 type errorRes struct {
 	Err string `json:"error"`
 }
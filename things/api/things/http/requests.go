@@ -4,14 +4,22 @@
 package http
 
 import (
+	"net/url"
+	"path"
+
 	"github.com/gofrs/uuid"
 	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/things"
 )
 
 const (
 	maxLimitSize = 100
 	maxNameSize  = 1024
+	maxLabelSize = 254
+	maxURLSize   = 1024
+	maxBatchSize = 1000
 	nameOrder    = "name"
 	idOrder      = "id"
 	ascDir       = "asc"
@@ -27,6 +35,7 @@ type createThingReq struct {
 	Key      string                 `json:"key,omitempty"`
 	ID       string                 `json:"id,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   labels.Labels          `json:"labels,omitempty"`
 }
 
 func validateUUID(extID string) (err error) {
@@ -51,11 +60,16 @@ func (req createThingReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
+	if err := req.Labels.Validate(); err != nil {
+		return errors.Wrap(things.ErrMalformedEntity, err)
+	}
+
 	return nil
 }
 
 type createThingsReq struct {
 	token  string
+	async  bool
 	Things []createThingReq
 }
 
@@ -76,6 +90,10 @@ func (req createThingsReq) validate() error {
 		if len(thing.Name) > maxNameSize {
 			return things.ErrMalformedEntity
 		}
+
+		if err := thing.Labels.Validate(); err != nil {
+			return errors.Wrap(things.ErrMalformedEntity, err)
+		}
 	}
 
 	return nil
@@ -109,6 +127,7 @@ type updateThingReq struct {
 	id       string
 	Name     string                 `json:"name,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   labels.Labels          `json:"labels,omitempty"`
 }
 
 func (req updateThingReq) validate() error {
@@ -124,6 +143,10 @@ func (req updateThingReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
+	if err := req.Labels.Validate(); err != nil {
+		return errors.Wrap(things.ErrMalformedEntity, err)
+	}
+
 	return nil
 }
 
@@ -150,6 +173,7 @@ type createChannelReq struct {
 	Name     string                 `json:"name,omitempty"`
 	ID       string                 `json:"id,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   labels.Labels          `json:"labels,omitempty"`
 }
 
 func (req createChannelReq) validate() error {
@@ -165,6 +189,10 @@ func (req createChannelReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
+	if err := req.Labels.Validate(); err != nil {
+		return errors.Wrap(things.ErrMalformedEntity, err)
+	}
+
 	return nil
 }
 
@@ -190,6 +218,10 @@ func (req createChannelsReq) validate() error {
 		if len(channel.Name) > maxNameSize {
 			return things.ErrMalformedEntity
 		}
+
+		if err := channel.Labels.Validate(); err != nil {
+			return errors.Wrap(things.ErrMalformedEntity, err)
+		}
 	}
 
 	return nil
@@ -200,6 +232,7 @@ type updateChannelReq struct {
 	id       string
 	Name     string                 `json:"name,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   labels.Labels          `json:"labels,omitempty"`
 }
 
 func (req updateChannelReq) validate() error {
@@ -215,6 +248,10 @@ func (req updateChannelReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
+	if err := req.Labels.Validate(); err != nil {
+		return errors.Wrap(things.ErrMalformedEntity, err)
+	}
+
 	return nil
 }
 
@@ -306,6 +343,8 @@ type connectThingReq struct {
 	token   string
 	chanID  string
 	thingID string
+	role    string
+	label   string
 }
 
 func (req connectThingReq) validate() error {
@@ -317,13 +356,15 @@ func (req connectThingReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
-	return nil
+	return validateConnection(req.role, req.label)
 }
 
 type connectReq struct {
 	token      string
 	ChannelIDs []string `json:"channel_ids,omitempty"`
 	ThingIDs   []string `json:"thing_ids,omitempty"`
+	Role       string   `json:"role,omitempty"`
+	Label      string   `json:"label,omitempty"`
 }
 
 func (req connectReq) validate() error {
@@ -335,6 +376,10 @@ func (req connectReq) validate() error {
 		return things.ErrMalformedEntity
 	}
 
+	if err := validateConnection(req.Role, req.Label); err != nil {
+		return err
+	}
+
 	for _, chID := range req.ChannelIDs {
 		if chID == "" {
 			return things.ErrMalformedEntity
@@ -385,3 +430,90 @@ func (req listThingsGroupReq) validate() error {
 	return nil
 
 }
+
+func validateConnection(role, label string) error {
+	if len(label) > maxLabelSize {
+		return things.ErrMalformedEntity
+	}
+
+	return things.ConnectionMetadata{Role: role}.Validate()
+}
+
+type createWebhookReq struct {
+	token     string
+	chanID    string
+	URL       string `json:"url"`
+	Secret    string `json:"secret,omitempty"`
+	Filter    string `json:"filter,omitempty"`
+	BatchSize uint64 `json:"batch_size,omitempty"`
+}
+
+func (req createWebhookReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" {
+		return things.ErrMalformedEntity
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return things.ErrMalformedEntity
+	}
+
+	if len(req.URL) > maxURLSize || len(req.Secret) > maxLabelSize || len(req.Filter) > maxLabelSize {
+		return things.ErrMalformedEntity
+	}
+
+	if _, err := path.Match(req.Filter, ""); err != nil {
+		return things.ErrMalformedEntity
+	}
+
+	if req.BatchSize > maxBatchSize {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type webhookReq struct {
+	token  string
+	chanID string
+	id     string
+}
+
+func (req webhookReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" || req.id == "" {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
+
+type listDeliveriesReq struct {
+	token        string
+	chanID       string
+	id           string
+	pageMetadata things.PageMetadata
+}
+
+func (req listDeliveriesReq) validate() error {
+	if req.token == "" {
+		return things.ErrUnauthorizedAccess
+	}
+
+	if req.chanID == "" || req.id == "" {
+		return things.ErrMalformedEntity
+	}
+
+	if req.pageMetadata.Limit == 0 || req.pageMetadata.Limit > maxLimitSize {
+		return things.ErrMalformedEntity
+	}
+
+	return nil
+}
@@ -4,10 +4,14 @@
 package http
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/jobs"
+	"github.com/mainflux/mainflux/pkg/labels"
 )
 
 var (
@@ -23,6 +27,11 @@ var (
 	_ mainflux.Response = (*disconnectThingRes)(nil)
 	_ mainflux.Response = (*disconnectRes)(nil)
 	_ mainflux.Response = (*shareThingRes)(nil)
+	_ mainflux.Response = (*webhookRes)(nil)
+	_ mainflux.Response = (*webhooksRes)(nil)
+	_ mainflux.Response = (*deliveriesPageRes)(nil)
+	_ mainflux.Response = (*jobRes)(nil)
+	_ mainflux.Response = (*jobResultRes)(nil)
 )
 
 type removeRes struct{}
@@ -44,6 +53,7 @@ type thingRes struct {
 	Name     string                 `json:"name,omitempty"`
 	Key      string                 `json:"key"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   labels.Labels          `json:"labels,omitempty"`
 	created  bool
 }
 
@@ -105,12 +115,20 @@ func (res thingsRes) Empty() bool {
 	return false
 }
 
+type connectionRes struct {
+	Role      string `json:"role,omitempty"`
+	Label     string `json:"label,omitempty"`
+	CreatedBy string `json:"created_by,omitempty"`
+}
+
 type viewThingRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Key      string                 `json:"key"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID         string                 `json:"id"`
+	Owner      string                 `json:"-"`
+	Name       string                 `json:"name,omitempty"`
+	Key        string                 `json:"key"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Labels     labels.Labels          `json:"labels,omitempty"`
+	Connection *connectionRes         `json:"connection,omitempty"`
 }
 
 func (res viewThingRes) Code() int {
@@ -146,6 +164,7 @@ type channelRes struct {
 	ID       string                 `json:"id"`
 	Name     string                 `json:"name,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   labels.Labels          `json:"labels,omitempty"`
 	created  bool
 }
 
@@ -194,11 +213,13 @@ func (res channelsRes) Empty() bool {
 }
 
 type viewChannelRes struct {
-	ID       string                 `json:"id"`
-	Owner    string                 `json:"-"`
-	Name     string                 `json:"name,omitempty"`
-	Things   []viewThingRes         `json:"connected,omitempty"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID         string                 `json:"id"`
+	Owner      string                 `json:"-"`
+	Name       string                 `json:"name,omitempty"`
+	Things     []viewThingRes         `json:"connected,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Labels     labels.Labels          `json:"labels,omitempty"`
+	Connection *connectionRes         `json:"connection,omitempty"`
 }
 
 func (res viewChannelRes) Code() int {
@@ -288,6 +309,139 @@ func (res disconnectThingRes) Empty() bool {
 	return true
 }
 
+type jobRes struct {
+	ID        string       `json:"id"`
+	Kind      string       `json:"kind"`
+	Status    string       `json:"status"`
+	Total     uint64       `json:"total"`
+	Done      uint64       `json:"done"`
+	Failed    uint64       `json:"failed"`
+	Errors    []jobs.Error `json:"errors,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	created   bool
+}
+
+func (res jobRes) Code() int {
+	if res.created {
+		return http.StatusAccepted
+	}
+
+	return http.StatusOK
+}
+
+func (res jobRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/jobs/%s", res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res jobRes) Empty() bool {
+	return false
+}
+
+// jobResultRes is the JSON encoded result of the completed job, which is
+// returned as is.
+type jobResultRes struct {
+	result json.RawMessage
+}
+
+func (res jobResultRes) Code() int {
+	return http.StatusOK
+}
+
+func (res jobResultRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res jobResultRes) Empty() bool {
+	return false
+}
+
+func (res jobResultRes) MarshalJSON() ([]byte, error) {
+	if len(res.result) == 0 {
+		return []byte("null"), nil
+	}
+	return res.result, nil
+}
+
+type webhookRes struct {
+	ID        string    `json:"id"`
+	ChannelID string    `json:"channel_id"`
+	URL       string    `json:"url"`
+	Filter    string    `json:"filter,omitempty"`
+	BatchSize uint64    `json:"batch_size"`
+	CreatedAt time.Time `json:"created_at"`
+	created   bool
+}
+
+func (res webhookRes) Code() int {
+	if res.created {
+		return http.StatusCreated
+	}
+
+	return http.StatusOK
+}
+
+func (res webhookRes) Headers() map[string]string {
+	if res.created {
+		return map[string]string{
+			"Location": fmt.Sprintf("/channels/%s/webhooks/%s", res.ChannelID, res.ID),
+		}
+	}
+
+	return map[string]string{}
+}
+
+func (res webhookRes) Empty() bool {
+	return false
+}
+
+type webhooksRes struct {
+	Webhooks []webhookRes `json:"webhooks"`
+}
+
+func (res webhooksRes) Code() int {
+	return http.StatusOK
+}
+
+func (res webhooksRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res webhooksRes) Empty() bool {
+	return false
+}
+
+type deliveryRes struct {
+	Messages    uint64    `json:"messages"`
+	Status      int       `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
+
+type deliveriesPageRes struct {
+	pageRes
+	Deliveries []deliveryRes `json:"deliveries"`
+}
+
+func (res deliveriesPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res deliveriesPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res deliveriesPageRes) Empty() bool {
+	return false
+}
+
 type pageRes struct {
 	Total  uint64 `json:"total"`
 	Offset uint64 `json:"offset"`
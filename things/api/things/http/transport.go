@@ -6,6 +6,7 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -16,6 +17,7 @@ import (
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/jobs"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/things"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -30,16 +32,23 @@ const (
 	orderKey    = "order"
 	dirKey      = "dir"
 	metadataKey = "metadata"
+	selectorKey = "selector"
 	disconnKey  = "disconnected"
 	sharedKey   = "shared"
+	roleKey     = "role"
+	labelKey    = "label"
+	asyncKey    = "async"
 	defOffset   = 0
 	defLimit    = 10
 )
 
-// MakeHandler returns a HTTP handler for API endpoints.
-func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
+// MakeHandler returns a HTTP handler for API endpoints. The heavy operations,
+// such as the bulk creation, are run as the jobs of the job service on
+// request.
+func MakeHandler(tracer opentracing.Tracer, svc things.Service, jsvc things.JobService) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
 	}
 
 	r := bone.New()
@@ -52,12 +61,26 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 	))
 
 	r.Post("/things/bulk", kithttp.NewServer(
-		kitot.TraceServer(tracer, "create_things")(createThingsEndpoint(svc)),
+		kitot.TraceServer(tracer, "create_things")(createThingsEndpoint(svc, jsvc)),
 		decodeThingsCreation,
 		encodeResponse,
 		opts...,
 	))
 
+	r.Get("/jobs/:id", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_job")(viewJobEndpoint(jsvc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/jobs/:id/result", kithttp.NewServer(
+		kitot.TraceServer(tracer, "view_job_result")(viewJobResultEndpoint(jsvc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Post("/things/:id/share", kithttp.NewServer(
 		kitot.TraceServer(tracer, "share_thing")(shareThingEndpoint(svc)),
 		decodeShareThing,
@@ -191,6 +214,34 @@ func MakeHandler(tracer opentracing.Tracer, svc things.Service) http.Handler {
 		opts...,
 	))
 
+	r.Post("/channels/:id/webhooks", kithttp.NewServer(
+		kitot.TraceServer(tracer, "create_webhook")(createWebhookEndpoint(svc)),
+		decodeWebhookCreation,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/webhooks", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_webhooks")(listWebhooksEndpoint(svc)),
+		decodeView,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Delete("/channels/:id/webhooks/:webhookId", kithttp.NewServer(
+		kitot.TraceServer(tracer, "remove_webhook")(removeWebhookEndpoint(svc)),
+		decodeWebhook,
+		encodeResponse,
+		opts...,
+	))
+
+	r.Get("/channels/:id/webhooks/:webhookId/deliveries", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_deliveries")(listDeliveriesEndpoint(svc)),
+		decodeListDeliveries,
+		encodeResponse,
+		opts...,
+	))
+
 	r.Get("/groups/:groupId", kithttp.NewServer(
 		kitot.TraceServer(tracer, "list_members")(listMembersEndpoint(svc)),
 		decodeListMembersRequest,
@@ -222,7 +273,12 @@ func decodeThingsCreation(_ context.Context, r *http.Request) (interface{}, erro
 		return nil, errors.ErrUnsupportedContentType
 	}
 
-	req := createThingsReq{token: r.Header.Get("Authorization")}
+	async, err := httputil.ReadBoolQuery(r, asyncKey, false)
+	if err != nil {
+		return nil, err
+	}
+
+	req := createThingsReq{token: r.Header.Get("Authorization"), async: async}
 	if err := json.NewDecoder(r.Body).Decode(&req.Things); err != nil {
 		return nil, errors.Wrap(things.ErrMalformedEntity, err)
 	}
@@ -357,6 +413,12 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+
+	sel, err := httputil.ReadSelectorQuery(r, selectorKey)
+	if err != nil {
+		return nil, err
+	}
+
 	shared, err := httputil.ReadBoolQuery(r, sharedKey, false)
 	if err != nil {
 		return nil, err
@@ -371,6 +433,7 @@ func decodeList(_ context.Context, r *http.Request) (interface{}, error) {
 			Order:             or,
 			Dir:               d,
 			Metadata:          m,
+			Selector:          sel,
 			FetchSharedThings: shared,
 		},
 	}
@@ -429,10 +492,22 @@ func decodeListByConnection(_ context.Context, r *http.Request) (interface{}, er
 }
 
 func decodeConnectThing(_ context.Context, r *http.Request) (interface{}, error) {
+	role, err := httputil.ReadStringQuery(r, roleKey, "")
+	if err != nil {
+		return nil, err
+	}
+
+	label, err := httputil.ReadStringQuery(r, labelKey, "")
+	if err != nil {
+		return nil, err
+	}
+
 	req := connectThingReq{
 		token:   r.Header.Get("Authorization"),
 		chanID:  bone.GetValue(r, "chanId"),
 		thingID: bone.GetValue(r, "thingId"),
+		role:    role,
+		label:   label,
 	}
 
 	return req, nil
@@ -451,6 +526,56 @@ func decodeConnectList(_ context.Context, r *http.Request) (interface{}, error)
 	return req, nil
 }
 
+func decodeWebhookCreation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := createWebhookReq{
+		token:  r.Header.Get("Authorization"),
+		chanID: bone.GetValue(r, "id"),
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(things.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeWebhook(_ context.Context, r *http.Request) (interface{}, error) {
+	req := webhookReq{
+		token:  r.Header.Get("Authorization"),
+		chanID: bone.GetValue(r, "id"),
+		id:     bone.GetValue(r, "webhookId"),
+	}
+
+	return req, nil
+}
+
+func decodeListDeliveries(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listDeliveriesReq{
+		token:  r.Header.Get("Authorization"),
+		chanID: bone.GetValue(r, "id"),
+		id:     bone.GetValue(r, "webhookId"),
+		pageMetadata: things.PageMetadata{
+			Offset: o,
+			Limit:  l,
+		},
+	}
+
+	return req, nil
+}
+
 func decodeListMembersRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
 	if err != nil {
@@ -467,6 +592,11 @@ func decodeListMembersRequest(_ context.Context, r *http.Request) (interface{},
 		return nil, err
 	}
 
+	sel, err := httputil.ReadSelectorQuery(r, selectorKey)
+	if err != nil {
+		return nil, err
+	}
+
 	req := listThingsGroupReq{
 		token:   r.Header.Get("Authorization"),
 		groupID: bone.GetValue(r, "groupId"),
@@ -474,6 +604,7 @@ func decodeListMembersRequest(_ context.Context, r *http.Request) (interface{},
 			Offset:   o,
 			Limit:    l,
 			Metadata: m,
+			Selector: sel,
 		},
 	}
 	return req, nil
@@ -515,13 +646,18 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 
 		case errors.Contains(errorVal, things.ErrMalformedEntity):
 			w.WriteHeader(http.StatusBadRequest)
-		case errors.Contains(errorVal, things.ErrNotFound):
+		case errors.Contains(errorVal, things.ErrNotFound),
+			errors.Contains(errorVal, jobs.ErrNotFound):
 			w.WriteHeader(http.StatusNotFound)
-		case errors.Contains(errorVal, things.ErrConflict):
+		case errors.Contains(errorVal, things.ErrConflict),
+			errors.Contains(errorVal, jobs.ErrNotCompleted):
 			w.WriteHeader(http.StatusConflict)
+		case errors.Contains(errorVal, things.ErrQuotaExceeded):
+			w.WriteHeader(http.StatusTooManyRequests)
 
 		case errors.Contains(errorVal, things.ErrScanMetadata),
-			errors.Contains(errorVal, things.ErrSelectEntity):
+			errors.Contains(errorVal, things.ErrSelectEntity),
+			errors.Contains(errorVal, things.ErrValidation):
 			w.WriteHeader(http.StatusUnprocessableEntity)
 
 		case errors.Contains(errorVal, things.ErrCreateEntity),
@@ -544,7 +680,12 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 			w.WriteHeader(http.StatusInternalServerError)
 		}
 		if errorVal.Msg() != "" {
-			if err := json.NewEncoder(w).Encode(errorRes{Err: errorVal.Msg()}); err != nil {
+			msg := errorVal.Msg()
+			// Quota errors describe the exceeded limit.
+			if errors.Contains(errorVal, things.ErrQuotaExceeded) && errorVal.Err() != nil {
+				msg = fmt.Sprintf("%s: %s", msg, errorVal.Err())
+			}
+			if err := json.NewEncoder(w).Encode(errorRes{Err: msg}); err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
 			}
 		}
@@ -5,6 +5,8 @@ package things
 
 import (
 	"context"
+
+	"github.com/mainflux/mainflux/pkg/labels"
 )
 
 // Channel represents a Mainflux "communication group". This group contains the
@@ -14,6 +16,54 @@ type Channel struct {
 	Owner    string
 	Name     string
 	Metadata map[string]interface{}
+	Labels   labels.Labels
+
+	// Connection is set when the channel is listed by a connected thing.
+	Connection *ConnectionMetadata
+}
+
+// Connection roles restrict the direction in which a connected thing is allowed
+// to exchange messages over the channel. A connection without a role allows both
+// publishing and subscribing.
+const (
+	PublisherRole  = "publisher"
+	SubscriberRole = "subscriber"
+)
+
+// Operations a thing performs over the channel, used to enforce connection roles.
+// An empty operation skips the role check.
+const (
+	PublishOp   = "publish"
+	SubscribeOp = "subscribe"
+)
+
+// ConnectionMetadata contains data attached to a thing-channel connection.
+type ConnectionMetadata struct {
+	Role      string
+	Label     string
+	CreatedBy string
+}
+
+// Validate returns an error if the connection metadata is invalid.
+func (cm ConnectionMetadata) Validate() error {
+	switch cm.Role {
+	case "", PublisherRole, SubscriberRole:
+		return nil
+	default:
+		return ErrMalformedEntity
+	}
+}
+
+// Allows returns true if the connection role permits the given operation.
+func (cm ConnectionMetadata) Allows(op string) bool {
+	switch op {
+	case PublishOp:
+		return cm.Role != SubscriberRole
+	case SubscribeOp:
+		return cm.Role != PublisherRole
+	default:
+		return true
+	}
 }
 
 // ChannelsPage contains page related metadata as well as list of channels that
@@ -49,8 +99,9 @@ type ChannelRepository interface {
 	// by the specified user.
 	Remove(ctx context.Context, owner, id string) error
 
-	// Connect adds things to the channels list of connected things.
-	Connect(ctx context.Context, owner string, chIDs, thIDs []string) error
+	// Connect adds things to the channels list of connected things. The
+	// provided metadata is attached to every created connection.
+	Connect(ctx context.Context, owner string, chIDs, thIDs []string, meta ConnectionMetadata) error
 
 	// Disconnect removes things from the channels list of connected
 	// things.
@@ -65,16 +116,23 @@ type ChannelRepository interface {
 	// "connected" to the specified channel. If that's the case, then
 	// returned error will be nil.
 	HasThingByID(ctx context.Context, chanID, thingID string) error
+
+	// RetrieveConnection retrieves metadata of the connection between the
+	// specified channel and thing.
+	RetrieveConnection(ctx context.Context, chanID, thingID string) (ConnectionMetadata, error)
 }
 
 // ChannelCache contains channel-thing connection caching interface.
 type ChannelCache interface {
-	// Connect channel thing connection.
-	Connect(context.Context, string, string) error
+	// Connect channel thing connection with the given connection role.
+	Connect(context.Context, string, string, string) error
 
 	// HasThing checks if thing is connected to channel.
 	HasThing(context.Context, string, string) bool
 
+	// Role returns the role of the cached channel thing connection.
+	Role(context.Context, string, string) string
+
 	// Disconnects thing from channel.
 	Disconnect(context.Context, string, string) error
 
@@ -0,0 +1,102 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/jobs"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
+)
+
+// BulkCreateJob is the kind of the job creating the things in bulk.
+const BulkCreateJob = "create_things"
+
+// JobService runs the heavy operations of the service as the background
+// jobs, tracking their progress and partial errors.
+type JobService interface {
+	// CreateThingsAsync starts the job creating the things one by one on
+	// behalf of the user identified by the token. The things which failed
+	// to be created are reported as the partial errors, while the job
+	// fails as a whole once the user isn't authorized anymore.
+	CreateThingsAsync(ctx context.Context, token string, things ...Thing) (jobs.Job, error)
+
+	// ViewJob retrieves the job started by the user identified by the
+	// token, along with the result of the completed job.
+	ViewJob(ctx context.Context, token, id string) (jobs.Job, error)
+}
+
+// jobThing is the thing created by the bulk creation job, as reported by the
+// result of the job, which is the same as the synchronous bulk creation.
+type jobThing struct {
+	ID       string                 `json:"id"`
+	Name     string                 `json:"name,omitempty"`
+	Key      string                 `json:"key"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   labels.Labels          `json:"labels,omitempty"`
+}
+
+type jobResult struct {
+	Things []jobThing `json:"things"`
+}
+
+var _ JobService = (*jobService)(nil)
+
+type jobService struct {
+	svc     Service
+	auth    mainflux.AuthServiceClient
+	manager *jobs.Manager
+}
+
+// NewJobService returns the job service running the operations using the
+// given service. The service is expected to be fully decorated, so that the
+// operations of the jobs are cached, published and logged like the rest.
+func NewJobService(svc Service, auth mainflux.AuthServiceClient, manager *jobs.Manager) JobService {
+	return &jobService{
+		svc:     svc,
+		auth:    auth,
+		manager: manager,
+	}
+}
+
+func (js *jobService) CreateThingsAsync(ctx context.Context, token string, things ...Thing) (jobs.Job, error) {
+	res, err := js.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return jobs.Job{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	create := func(ctx context.Context, p *jobs.Progress) (interface{}, error) {
+		r := jobResult{Things: []jobThing{}}
+		for _, th := range things {
+			ths, err := js.svc.CreateThings(ctx, token, th)
+			if errors.Contains(err, ErrUnauthorizedAccess) || errors.Contains(err, ErrAuthorization) {
+				return nil, err
+			}
+			p.Step(ctx, err)
+			for _, th := range ths {
+				r.Things = append(r.Things, jobThing{
+					ID:       th.ID,
+					Name:     th.Name,
+					Key:      th.Key,
+					Metadata: th.Metadata,
+					Labels:   th.Labels,
+				})
+			}
+		}
+		return r, nil
+	}
+
+	return js.manager.Start(ctx, res.GetEmail(), BulkCreateJob, uint64(len(things)), create)
+}
+
+func (js *jobService) ViewJob(ctx context.Context, token, id string) (jobs.Job, error) {
+	res, err := js.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return jobs.Job{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return js.manager.Retrieve(ctx, res.GetEmail(), id)
+}
@@ -86,3 +86,35 @@ func (svc authServiceMock) Members(ctx context.Context, req *mainflux.MembersReq
 func (svc authServiceMock) Assign(ctx context.Context, req *mainflux.Assignment, _ ...grpc.CallOption) (r *empty.Empty, err error) {
 	panic("not implemented")
 }
+
+func (svc authServiceMock) ReserveQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	return &empty.Empty{}, nil
+}
+
+func (svc authServiceMock) ReleaseQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	return &empty.Empty{}, nil
+}
+
+func (svc authServiceMock) RevokeKeys(ctx context.Context, req *mainflux.RevokeKeysReq, _ ...grpc.CallOption) (*mainflux.RevokeKeysRes, error) {
+	return &mainflux.RevokeKeysRes{}, nil
+}
+
+func (svc authServiceMock) ApplyPolicyTemplate(ctx context.Context, req *mainflux.PolicyTemplateReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	if req.GetEntityID() == "" || req.GetOwnerID() == "" {
+		return &empty.Empty{}, things.ErrMalformedEntity
+	}
+
+	// The default thing and channel template gives the owner and the admins
+	// the read, write and delete relations on the entity.
+	admins := "members:authorities#member"
+	for _, sub := range []string{req.GetOwnerID(), admins} {
+		for _, rel := range []string{"read", "write", "delete"} {
+			svc.policies[sub] = append(svc.policies[sub], MockSubjectSet{Object: req.GetEntityID(), Relation: rel})
+		}
+	}
+	return &empty.Empty{}, nil
+}
+
+func (svc authServiceMock) RemoveLinks(ctx context.Context, req *mainflux.RemoveLinksReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	panic("not implemented")
+}
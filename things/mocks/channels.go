@@ -95,7 +95,7 @@ func (crm *channelRepositoryMock) RetrieveAll(_ context.Context, owner string, p
 	// itself (see mocks/commons.go).
 	prefix := fmt.Sprintf("%s-", owner)
 	for k, v := range crm.channels {
-		if strings.HasPrefix(k, prefix) {
+		if strings.HasPrefix(k, prefix) && pm.Selector.Matches(v.Labels) {
 			chs = append(chs, v)
 		}
 	}
@@ -189,7 +189,7 @@ func (crm *channelRepositoryMock) Remove(_ context.Context, owner, id string) er
 	return nil
 }
 
-func (crm *channelRepositoryMock) Connect(_ context.Context, owner string, chIDs, thIDs []string) error {
+func (crm *channelRepositoryMock) Connect(_ context.Context, owner string, chIDs, thIDs []string, meta things.ConnectionMetadata) error {
 	for _, chID := range chIDs {
 		ch, err := crm.RetrieveByID(context.Background(), owner, chID)
 		if err != nil {
@@ -210,6 +210,8 @@ func (crm *channelRepositoryMock) Connect(_ context.Context, owner string, chIDs
 			if _, ok := crm.cconns[thID]; !ok {
 				crm.cconns[thID] = make(map[string]things.Channel)
 			}
+			conn := meta
+			ch.Connection = &conn
 			crm.cconns[thID][chID] = ch
 		}
 	}
@@ -271,23 +273,40 @@ func (crm *channelRepositoryMock) HasThingByID(_ context.Context, chanID, thingI
 	return nil
 }
 
+func (crm *channelRepositoryMock) RetrieveConnection(_ context.Context, chanID, thingID string) (things.ConnectionMetadata, error) {
+	chans, ok := crm.cconns[thingID]
+	if !ok {
+		return things.ConnectionMetadata{}, things.ErrEntityConnected
+	}
+
+	ch, ok := chans[chanID]
+	if !ok {
+		return things.ConnectionMetadata{}, things.ErrEntityConnected
+	}
+
+	return *ch.Connection, nil
+}
+
 type channelCacheMock struct {
 	mu       sync.Mutex
 	channels map[string]string
+	roles    map[string]string
 }
 
 // NewChannelCache returns mock cache instance.
 func NewChannelCache() things.ChannelCache {
 	return &channelCacheMock{
 		channels: make(map[string]string),
+		roles:    make(map[string]string),
 	}
 }
 
-func (ccm *channelCacheMock) Connect(_ context.Context, chanID, thingID string) error {
+func (ccm *channelCacheMock) Connect(_ context.Context, chanID, thingID, role string) error {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 
 	ccm.channels[chanID] = thingID
+	ccm.roles[chanID] = role
 	return nil
 }
 
@@ -298,11 +317,22 @@ func (ccm *channelCacheMock) HasThing(_ context.Context, chanID, thingID string)
 	return ccm.channels[chanID] == thingID
 }
 
+func (ccm *channelCacheMock) Role(_ context.Context, chanID, thingID string) string {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	if ccm.channels[chanID] != thingID {
+		return ""
+	}
+	return ccm.roles[chanID]
+}
+
 func (ccm *channelCacheMock) Disconnect(_ context.Context, chanID, thingID string) error {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 
 	delete(ccm.channels, chanID)
+	delete(ccm.roles, chanID)
 	return nil
 }
 
@@ -311,5 +341,6 @@ func (ccm *channelCacheMock) Remove(_ context.Context, chanID string) error {
 	defer ccm.mu.Unlock()
 
 	delete(ccm.channels, chanID)
+	delete(ccm.roles, chanID)
 	return nil
 }
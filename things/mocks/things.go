@@ -130,7 +130,7 @@ func (trm *thingRepositoryMock) RetrieveAll(_ context.Context, owner string, pm
 	prefix := fmt.Sprintf("%s-", owner)
 	for k, v := range trm.things {
 		id := parseID(v.ID)
-		if strings.HasPrefix(k, prefix) && id >= first && id < last {
+		if strings.HasPrefix(k, prefix) && id >= first && id < last && pm.Selector.Matches(v.Labels) {
 			ths = append(ths, v)
 		}
 	}
@@ -169,7 +169,7 @@ func (trm *thingRepositoryMock) RetrieveByIDs(_ context.Context, thingIDs []stri
 		suffix := fmt.Sprintf("-%s", id)
 		for k, v := range trm.things {
 			id := parseID(v.ID)
-			if strings.HasSuffix(k, suffix) && id >= first && id < last {
+			if strings.HasSuffix(k, suffix) && id >= first && id < last && pm.Selector.Matches(v.Labels) {
 				items = append(items, v)
 			}
 		}
@@ -0,0 +1,144 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.WebhookRepository = (*webhookRepositoryMock)(nil)
+
+type webhookRepositoryMock struct {
+	mu         sync.Mutex
+	webhooks   map[string]things.Webhook
+	deliveries map[string][]things.Delivery
+}
+
+// NewWebhookRepository creates in-memory webhook repository.
+func NewWebhookRepository() things.WebhookRepository {
+	return &webhookRepositoryMock{
+		webhooks:   make(map[string]things.Webhook),
+		deliveries: make(map[string][]things.Delivery),
+	}
+}
+
+func (wrm *webhookRepositoryMock) Save(_ context.Context, w things.Webhook) error {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	if _, ok := wrm.webhooks[w.ID]; ok {
+		return things.ErrConflict
+	}
+	wrm.webhooks[w.ID] = w
+
+	return nil
+}
+
+func (wrm *webhookRepositoryMock) RetrieveByID(_ context.Context, owner, id string) (things.Webhook, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	w, ok := wrm.webhooks[id]
+	if !ok || w.Owner != owner {
+		return things.Webhook{}, things.ErrNotFound
+	}
+
+	return w, nil
+}
+
+func (wrm *webhookRepositoryMock) RetrieveByChannel(_ context.Context, owner, chID string) ([]things.Webhook, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	webhooks := []things.Webhook{}
+	for _, w := range wrm.webhooks {
+		if w.ChannelID == chID && w.Owner == owner {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return sortWebhooks(webhooks), nil
+}
+
+func (wrm *webhookRepositoryMock) RetrieveAllByChannel(_ context.Context, chID string) ([]things.Webhook, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	webhooks := []things.Webhook{}
+	for _, w := range wrm.webhooks {
+		if w.ChannelID == chID {
+			webhooks = append(webhooks, w)
+		}
+	}
+
+	return sortWebhooks(webhooks), nil
+}
+
+func (wrm *webhookRepositoryMock) Count(_ context.Context, owner string) (uint64, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	var count uint64
+	for _, w := range wrm.webhooks {
+		if w.Owner == owner {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (wrm *webhookRepositoryMock) Remove(_ context.Context, owner, id string) error {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	if w, ok := wrm.webhooks[id]; ok && w.Owner == owner {
+		delete(wrm.webhooks, id)
+		delete(wrm.deliveries, id)
+	}
+
+	return nil
+}
+
+func (wrm *webhookRepositoryMock) SaveDelivery(_ context.Context, d things.Delivery) error {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	wrm.deliveries[d.WebhookID] = append(wrm.deliveries[d.WebhookID], d)
+
+	return nil
+}
+
+func (wrm *webhookRepositoryMock) RetrieveDeliveries(_ context.Context, webhookID string, pm things.PageMetadata) (things.DeliveriesPage, error) {
+	wrm.mu.Lock()
+	defer wrm.mu.Unlock()
+
+	all := wrm.deliveries[webhookID]
+	page := things.DeliveriesPage{
+		PageMetadata: things.PageMetadata{
+			Total:  uint64(len(all)),
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+		Deliveries: []things.Delivery{},
+	}
+
+	// Deliveries are appended in order, so the newest ones are at the end.
+	for i := len(all) - 1 - int(pm.Offset); i >= 0 && uint64(len(page.Deliveries)) < pm.Limit; i-- {
+		page.Deliveries = append(page.Deliveries, all[i])
+	}
+
+	return page, nil
+}
+
+func sortWebhooks(webhooks []things.Webhook) []things.Webhook {
+	sort.SliceStable(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+	return webhooks
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/things"
 )
 
@@ -24,9 +25,12 @@ type channelRepository struct {
 }
 
 type dbConnection struct {
-	Channel string `db:"channel"`
-	Thing   string `db:"thing"`
-	Owner   string `db:"owner"`
+	Channel   string `db:"channel"`
+	Thing     string `db:"thing"`
+	Owner     string `db:"owner"`
+	Role      string `db:"role"`
+	Label     string `db:"label"`
+	CreatedBy string `db:"created_by"`
 }
 
 // NewChannelRepository instantiates a PostgreSQL implementation of channel
@@ -43,8 +47,8 @@ func (cr channelRepository) Save(ctx context.Context, channels ...things.Channel
 		return nil, errors.Wrap(things.ErrCreateEntity, err)
 	}
 
-	q := `INSERT INTO channels (id, owner, name, metadata)
-		  VALUES (:id, :owner, :name, :metadata);`
+	q := `INSERT INTO channels (id, owner, name, metadata, labels)
+		  VALUES (:id, :owner, :name, :metadata, :labels);`
 
 	for _, channel := range channels {
 		dbch := toDBChannel(channel)
@@ -73,7 +77,7 @@ func (cr channelRepository) Save(ctx context.Context, channels ...things.Channel
 }
 
 func (cr channelRepository) Update(ctx context.Context, channel things.Channel) error {
-	q := `UPDATE channels SET name = :name, metadata = :metadata WHERE owner = :owner AND id = :id;`
+	q := `UPDATE channels SET name = :name, metadata = :metadata, labels = :labels WHERE owner = :owner AND id = :id;`
 
 	dbch := toDBChannel(channel)
 
@@ -103,7 +107,7 @@ func (cr channelRepository) Update(ctx context.Context, channel things.Channel)
 }
 
 func (cr channelRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Channel, error) {
-	q := `SELECT name, metadata, owner FROM channels WHERE id = $1;`
+	q := `SELECT name, metadata, labels, owner FROM channels WHERE id = $1;`
 
 	dbch := dbChannel{
 		ID: id,
@@ -128,12 +132,19 @@ func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, pm th
 	if err != nil {
 		return things.ChannelsPage{}, errors.Wrap(things.ErrSelectEntity, err)
 	}
+	sq, sp, err := getSelectorQuery(pm.Selector)
+	if err != nil {
+		return things.ChannelsPage{}, errors.Wrap(things.ErrSelectEntity, err)
+	}
 
 	var whereClause string
 	var query []string
 	if mq != "" {
 		query = append(query, mq)
 	}
+	if sq != "" {
+		query = append(query, sq)
+	}
 	if nq != "" {
 		query = append(query, nq)
 	}
@@ -145,7 +156,7 @@ func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, pm th
 		whereClause = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
 	}
 
-	q := fmt.Sprintf(`SELECT id, name, metadata FROM channels
+	q := fmt.Sprintf(`SELECT id, name, metadata, labels FROM channels
 		%s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, whereClause, oq, dq)
 
 	params := map[string]interface{}{
@@ -155,6 +166,9 @@ func (cr channelRepository) RetrieveAll(ctx context.Context, owner string, pm th
 		"name":     name,
 		"metadata": meta,
 	}
+	for k, v := range sp {
+		params[k] = v
+	}
 	rows, err := cr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
 		return things.ChannelsPage{}, errors.Wrap(things.ErrSelectEntity, err)
@@ -205,7 +219,7 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thID str
 	var q, qc string
 	switch pm.Disconnected {
 	case true:
-		q = fmt.Sprintf(`SELECT id, name, metadata
+		q = fmt.Sprintf(`SELECT id, name, metadata, labels
 		        FROM channels ch
 		        WHERE ch.owner = :owner AND ch.id NOT IN
 		        (SELECT id FROM channels ch
@@ -224,7 +238,8 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thID str
 		          ON ch.id = conn.channel_id
 		          WHERE ch.owner = $1 AND conn.thing_id = $2);`
 	default:
-		q = fmt.Sprintf(`SELECT id, name, metadata FROM channels ch
+		q = fmt.Sprintf(`SELECT id, name, metadata, labels, conn.role, conn.label, conn.created_by
+		        FROM channels ch
 		        INNER JOIN connections conn
 		        ON ch.id = conn.channel_id
 		        WHERE ch.owner = :owner AND conn.thing_id = :thing
@@ -254,12 +269,16 @@ func (cr channelRepository) RetrieveByThing(ctx context.Context, owner, thID str
 
 	items := []things.Channel{}
 	for rows.Next() {
-		dbch := dbChannel{Owner: owner}
+		dbch := dbConnectedChannel{dbChannel: dbChannel{Owner: owner}}
 		if err := rows.StructScan(&dbch); err != nil {
 			return things.ChannelsPage{}, errors.Wrap(things.ErrSelectEntity, err)
 		}
 
-		ch := toChannel(dbch)
+		ch := toChannel(dbch.dbChannel)
+		if !pm.Disconnected {
+			conn := toConnMetadata(dbch.dbConnMetadata)
+			ch.Connection = &conn
+		}
 		items = append(items, ch)
 	}
 
@@ -288,21 +307,24 @@ func (cr channelRepository) Remove(ctx context.Context, owner, id string) error
 	return nil
 }
 
-func (cr channelRepository) Connect(ctx context.Context, owner string, chIDs, thIDs []string) error {
+func (cr channelRepository) Connect(ctx context.Context, owner string, chIDs, thIDs []string, meta things.ConnectionMetadata) error {
 	tx, err := cr.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return errors.Wrap(things.ErrConnect, err)
 	}
 
-	q := `INSERT INTO connections (channel_id, channel_owner, thing_id, thing_owner)
-	      VALUES (:channel, :owner, :thing, :owner);`
+	q := `INSERT INTO connections (channel_id, channel_owner, thing_id, thing_owner, role, label, created_by)
+	      VALUES (:channel, :owner, :thing, :owner, :role, :label, :created_by);`
 
 	for _, chID := range chIDs {
 		for _, thID := range thIDs {
 			dbco := dbConnection{
-				Channel: chID,
-				Thing:   thID,
-				Owner:   owner,
+				Channel:   chID,
+				Thing:     thID,
+				Owner:     owner,
+				Role:      meta.Role,
+				Label:     meta.Label,
+				CreatedBy: meta.CreatedBy,
 			}
 
 			_, err := tx.NamedExecContext(ctx, q, dbco)
@@ -399,6 +421,20 @@ func (cr channelRepository) HasThingByID(ctx context.Context, chanID, thingID st
 	return cr.hasThing(ctx, chanID, thingID)
 }
 
+func (cr channelRepository) RetrieveConnection(ctx context.Context, chanID, thingID string) (things.ConnectionMetadata, error) {
+	q := `SELECT role, label, created_by FROM connections WHERE channel_id = $1 AND thing_id = $2;`
+
+	var dbcm dbConnMetadata
+	if err := cr.db.QueryRowxContext(ctx, q, chanID, thingID).StructScan(&dbcm); err != nil {
+		if err == sql.ErrNoRows {
+			return things.ConnectionMetadata{}, things.ErrNotFound
+		}
+		return things.ConnectionMetadata{}, errors.Wrap(things.ErrEntityConnected, err)
+	}
+
+	return toConnMetadata(dbcm), nil
+}
+
 func (cr channelRepository) hasThing(ctx context.Context, chanID, thingID string) error {
 	q := `SELECT EXISTS (SELECT 1 FROM connections WHERE channel_id = $1 AND thing_id = $2);`
 	exists := false
@@ -451,11 +487,35 @@ func (m dbMetadata) Value() (driver.Value, error) {
 	return b, err
 }
 
+// dbLabels type for handling labels properly in database/sql.
+type dbLabels labels.Labels
+
+// Scan implements the database/sql scanner interface.
+func (l *dbLabels) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return things.ErrScanMetadata
+	}
+
+	return json.Unmarshal(b, l)
+}
+
+// Value implements database/sql valuer interface. The entities without
+// labels are stored with the empty labels, so the selectors apply to them.
+func (l dbLabels) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(l)
+}
+
 type dbChannel struct {
 	ID       string     `db:"id"`
 	Owner    string     `db:"owner"`
 	Name     string     `db:"name"`
 	Metadata dbMetadata `db:"metadata"`
+	Labels   dbLabels   `db:"labels"`
 }
 
 func toDBChannel(ch things.Channel) dbChannel {
@@ -464,6 +524,7 @@ func toDBChannel(ch things.Channel) dbChannel {
 		Owner:    ch.Owner,
 		Name:     ch.Name,
 		Metadata: ch.Metadata,
+		Labels:   dbLabels(ch.Labels),
 	}
 }
 
@@ -473,6 +534,26 @@ func toChannel(ch dbChannel) things.Channel {
 		Owner:    ch.Owner,
 		Name:     ch.Name,
 		Metadata: ch.Metadata,
+		Labels:   labels.Labels(ch.Labels),
+	}
+}
+
+type dbConnMetadata struct {
+	Role      string `db:"role"`
+	Label     string `db:"label"`
+	CreatedBy string `db:"created_by"`
+}
+
+type dbConnectedChannel struct {
+	dbChannel
+	dbConnMetadata
+}
+
+func toConnMetadata(cm dbConnMetadata) things.ConnectionMetadata {
+	return things.ConnectionMetadata{
+		Role:      cm.Role,
+		Label:     cm.Label,
+		CreatedBy: cm.CreatedBy,
 	}
 }
 
@@ -527,6 +608,37 @@ func getMetadataQuery(m things.Metadata) ([]byte, string, error) {
 	return mb, mq, nil
 }
 
+// getSelectorQuery returns the condition selecting the entities whose labels
+// satisfy all the selector requirements, along with its named parameters.
+func getSelectorQuery(sel labels.Selector) (string, map[string]interface{}, error) {
+	var conds []string
+	params := map[string]interface{}{}
+	for i, r := range sel {
+		p := fmt.Sprintf("selector_%d", i)
+		switch r.Operator {
+		case labels.Equals, labels.NotEquals:
+			b, err := json.Marshal(labels.Labels{r.Key: r.Value})
+			if err != nil {
+				return "", nil, err
+			}
+			params[p] = b
+			cond := fmt.Sprintf("labels @> :%s", p)
+			if r.Operator == labels.NotEquals {
+				cond = fmt.Sprintf("NOT (%s)", cond)
+			}
+			conds = append(conds, cond)
+		case labels.Exists:
+			params[p] = r.Key
+			conds = append(conds, fmt.Sprintf("labels ? :%s", p))
+		case labels.NotExists:
+			params[p] = r.Key
+			conds = append(conds, fmt.Sprintf("NOT (labels ? :%s)", p))
+		}
+	}
+
+	return strings.Join(conds, " AND "), params, nil
+}
+
 func total(ctx context.Context, db Database, query string, params interface{}) (uint64, error) {
 	rows, err := db.NamedQueryContext(ctx, query, params)
 	if err != nil {
@@ -166,7 +166,7 @@ func TestSingleChannelRetrieval(t *testing.T) {
 	}
 	chs, _ := chanRepo.Save(context.Background(), ch)
 	ch.ID = chs[0].ID
-	chanRepo.Connect(context.Background(), email, []string{ch.ID}, []string{th.ID})
+	chanRepo.Connect(context.Background(), email, []string{ch.ID}, []string{th.ID}, things.ConnectionMetadata{})
 
 	nonexistentChanID, err := idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -396,7 +396,7 @@ func TestRetrieveByThing(t *testing.T) {
 			break
 		}
 
-		err = chanRepo.Connect(context.Background(), email, []string{cid}, []string{thID})
+		err = chanRepo.Connect(context.Background(), email, []string{cid}, []string{thID}, things.ConnectionMetadata{})
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	}
 
@@ -632,7 +632,7 @@ func TestConnect(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := chanRepo.Connect(context.Background(), tc.owner, []string{tc.chID}, []string{tc.thID})
+		err := chanRepo.Connect(context.Background(), tc.owner, []string{tc.chID}, []string{tc.thID}, things.ConnectionMetadata{})
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
@@ -665,7 +665,7 @@ func TestDisconnect(t *testing.T) {
 	})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	chID = chs[0].ID
-	chanRepo.Connect(context.Background(), email, []string{chID}, []string{thID})
+	chanRepo.Connect(context.Background(), email, []string{chID}, []string{thID}, things.ConnectionMetadata{})
 
 	nonexistentThingID, err := idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -751,7 +751,7 @@ func TestHasThing(t *testing.T) {
 	})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	chID = chs[0].ID
-	chanRepo.Connect(context.Background(), email, []string{chID}, []string{thID})
+	chanRepo.Connect(context.Background(), email, []string{chID}, []string{thID}, things.ConnectionMetadata{})
 
 	nonexistentChanID, err := idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -826,7 +826,7 @@ func TestHasThingByID(t *testing.T) {
 	})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	chID = chs[0].ID
-	chanRepo.Connect(context.Background(), email, []string{chID}, []string{thID})
+	chanRepo.Connect(context.Background(), email, []string{chID}, []string{thID}, things.ConnectionMetadata{})
 
 	nonexistentChanID, err := idProvider.ID()
 	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
@@ -882,3 +882,61 @@ func testSortChannels(t *testing.T, pm things.PageMetadata, chs []things.Channel
 		break
 	}
 }
+
+func TestRetrieveConnection(t *testing.T) {
+	email := "channel-connection@example.com"
+	dbMiddleware := postgres.NewDatabase(db)
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+	chanRepo := postgres.NewChannelRepository(dbMiddleware)
+
+	thID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	thKey, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	ths, err := thingRepo.Save(context.Background(), things.Thing{ID: thID, Owner: email, Key: thKey})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	thID = ths[0].ID
+
+	chID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chs, err := chanRepo.Save(context.Background(), things.Channel{ID: chID, Owner: email})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	chID = chs[0].ID
+
+	meta := things.ConnectionMetadata{
+		Role:      things.PublisherRole,
+		Label:     "sensor",
+		CreatedBy: email,
+	}
+	err = chanRepo.Connect(context.Background(), email, []string{chID}, []string{thID}, meta)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	nonexistentChanID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	cases := map[string]struct {
+		chID string
+		thID string
+		meta things.ConnectionMetadata
+		err  error
+	}{
+		"retrieve existing connection": {
+			chID: chID,
+			thID: thID,
+			meta: meta,
+			err:  nil,
+		},
+		"retrieve connection for non-existing channel": {
+			chID: nonexistentChanID,
+			thID: thID,
+			meta: things.ConnectionMetadata{},
+			err:  things.ErrNotFound,
+		},
+	}
+
+	for desc, tc := range cases {
+		conn, err := chanRepo.RetrieveConnection(context.Background(), tc.chID, tc.thID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.Equal(t, tc.meta, conn, fmt.Sprintf("%s: expected %v got %v\n", desc, tc.meta, conn))
+	}
+}
@@ -99,6 +99,62 @@ func migrateDB(db *sqlx.DB) error {
 					`ALTER TABLE IF EXISTS things ADD CONSTRAINT things_id_key UNIQUE (id)`,
 				},
 			},
+			{
+				Id: "things_5",
+				Up: []string{
+					`ALTER TABLE IF EXISTS connections
+					 ADD COLUMN IF NOT EXISTS role       VARCHAR(16)  NOT NULL DEFAULT '',
+					 ADD COLUMN IF NOT EXISTS label      VARCHAR(254) NOT NULL DEFAULT '',
+					 ADD COLUMN IF NOT EXISTS created_by VARCHAR(254) NOT NULL DEFAULT ''`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS connections
+					 DROP COLUMN IF EXISTS role,
+					 DROP COLUMN IF EXISTS label,
+					 DROP COLUMN IF EXISTS created_by`,
+				},
+			},
+			{
+				Id: "things_6",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS webhooks (
+						id         UUID PRIMARY KEY,
+						owner      VARCHAR(254) NOT NULL,
+						channel_id UUID NOT NULL,
+						url        VARCHAR(1024) NOT NULL,
+						secret     VARCHAR(254) NOT NULL DEFAULT '',
+						filter     VARCHAR(254) NOT NULL DEFAULT '',
+						batch_size BIGINT NOT NULL,
+						created_at TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS webhooks_channel_id ON webhooks (channel_id)`,
+					`CREATE TABLE IF NOT EXISTS webhook_deliveries (
+						webhook_id   UUID NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+						messages     BIGINT NOT NULL,
+						status       INTEGER NOT NULL,
+						error        TEXT NOT NULL DEFAULT '',
+						delivered_at TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id ON webhook_deliveries (webhook_id, delivered_at)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS webhook_deliveries`,
+					`DROP TABLE IF EXISTS webhooks`,
+				},
+			},
+			{
+				Id: "things_7",
+				Up: []string{
+					`ALTER TABLE IF EXISTS things ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}'`,
+					`ALTER TABLE IF EXISTS channels ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}'`,
+					`CREATE INDEX IF NOT EXISTS things_labels ON things USING GIN (labels)`,
+					`CREATE INDEX IF NOT EXISTS channels_labels ON channels USING GIN (labels)`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS things DROP COLUMN IF EXISTS labels`,
+					`ALTER TABLE IF EXISTS channels DROP COLUMN IF EXISTS labels`,
+				},
+			},
 		},
 	}
 
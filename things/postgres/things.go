@@ -13,6 +13,7 @@ import (
 	"github.com/gofrs/uuid"
 	"github.com/lib/pq" // required for DB access
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/things"
 )
 
@@ -43,8 +44,8 @@ func (tr thingRepository) Save(ctx context.Context, ths ...things.Thing) ([]thin
 		return []things.Thing{}, errors.Wrap(things.ErrCreateEntity, err)
 	}
 
-	q := `INSERT INTO things (id, owner, name, key, metadata)
-		  VALUES (:id, :owner, :name, :key, :metadata);`
+	q := `INSERT INTO things (id, owner, name, key, metadata, labels)
+		  VALUES (:id, :owner, :name, :key, :metadata, :labels);`
 
 	for _, thing := range ths {
 		dbth, err := toDBThing(thing)
@@ -76,7 +77,7 @@ func (tr thingRepository) Save(ctx context.Context, ths ...things.Thing) ([]thin
 }
 
 func (tr thingRepository) Update(ctx context.Context, t things.Thing) error {
-	q := `UPDATE things SET name = :name, metadata = :metadata WHERE id = :id;`
+	q := `UPDATE things SET name = :name, metadata = :metadata, labels = :labels WHERE id = :id;`
 
 	dbth, err := toDBThing(t)
 	if err != nil {
@@ -145,7 +146,7 @@ func (tr thingRepository) UpdateKey(ctx context.Context, owner, id, key string)
 }
 
 func (tr thingRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Thing, error) {
-	q := `SELECT name, key, metadata FROM things WHERE id = $1;`
+	q := `SELECT name, key, metadata, labels FROM things WHERE id = $1;`
 
 	dbth := dbThing{ID: id}
 
@@ -182,15 +183,31 @@ func (tr thingRepository) RetrieveByIDs(ctx context.Context, thingIDs []string,
 	nq, name := getNameQuery(pm.Name)
 	oq := getOrderQuery(pm.Order)
 	dq := getDirQuery(pm.Dir)
-	idq := fmt.Sprintf("WHERE id IN ('%s') ", strings.Join(thingIDs, "','"))
+	idq := fmt.Sprintf("id IN ('%s')", strings.Join(thingIDs, "','"))
 
 	m, mq, err := getMetadataQuery(pm.Metadata)
 	if err != nil {
 		return things.Page{}, errors.Wrap(things.ErrSelectEntity, err)
 	}
+	sq, sp, err := getSelectorQuery(pm.Selector)
+	if err != nil {
+		return things.Page{}, errors.Wrap(things.ErrSelectEntity, err)
+	}
+
+	query := []string{idq}
+	if mq != "" {
+		query = append(query, mq)
+	}
+	if nq != "" {
+		query = append(query, nq)
+	}
+	if sq != "" {
+		query = append(query, sq)
+	}
+	whereClause := fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
 
-	q := fmt.Sprintf(`SELECT id, owner, name, key, metadata FROM things
-					   %s%s%s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, idq, mq, nq, oq, dq)
+	q := fmt.Sprintf(`SELECT id, owner, name, key, metadata, labels FROM things
+					   %s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, whereClause, oq, dq)
 
 	params := map[string]interface{}{
 		"limit":    pm.Limit,
@@ -198,6 +215,9 @@ func (tr thingRepository) RetrieveByIDs(ctx context.Context, thingIDs []string,
 		"name":     name,
 		"metadata": m,
 	}
+	for k, v := range sp {
+		params[k] = v
+	}
 
 	rows, err := tr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
@@ -220,7 +240,7 @@ func (tr thingRepository) RetrieveByIDs(ctx context.Context, thingIDs []string,
 		items = append(items, th)
 	}
 
-	cq := fmt.Sprintf(`SELECT COUNT(*) FROM things %s%s%s;`, idq, mq, nq)
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM things %s;`, whereClause)
 
 	total, err := total(ctx, tr.db, cq, params)
 	if err != nil {
@@ -257,11 +277,18 @@ func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, pm thin
 	if err != nil {
 		return things.Page{}, errors.Wrap(things.ErrSelectEntity, err)
 	}
+	sq, sp, err := getSelectorQuery(pm.Selector)
+	if err != nil {
+		return things.Page{}, errors.Wrap(things.ErrSelectEntity, err)
+	}
 
 	var query []string
 	if mq != "" {
 		query = append(query, mq)
 	}
+	if sq != "" {
+		query = append(query, sq)
+	}
 	if nq != "" {
 		query = append(query, nq)
 	}
@@ -274,7 +301,7 @@ func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, pm thin
 		whereClause = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
 	}
 
-	q := fmt.Sprintf(`SELECT id, name, key, metadata FROM things
+	q := fmt.Sprintf(`SELECT id, name, key, metadata, labels FROM things
 	      %s ORDER BY %s %s LIMIT :limit OFFSET :offset;`, whereClause, oq, dq)
 	params := map[string]interface{}{
 		"owner":    owner,
@@ -283,6 +310,9 @@ func (tr thingRepository) RetrieveAll(ctx context.Context, owner string, pm thin
 		"name":     name,
 		"metadata": m,
 	}
+	for k, v := range sp {
+		params[k] = v
+	}
 
 	rows, err := tr.db.NamedQueryContext(ctx, q, params)
 	if err != nil {
@@ -338,7 +368,7 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, chID str
 	var q, qc string
 	switch pm.Disconnected {
 	case true:
-		q = fmt.Sprintf(`SELECT id, name, key, metadata
+		q = fmt.Sprintf(`SELECT id, name, key, metadata, labels
 		        FROM things th
 		        WHERE th.owner = :owner AND th.id NOT IN
 		        (SELECT id FROM things th
@@ -357,7 +387,7 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, chID str
 		          ON th.id = conn.thing_id
 		          WHERE th.owner = $1 AND conn.channel_id = $2);`
 	default:
-		q = fmt.Sprintf(`SELECT id, name, key, metadata
+		q = fmt.Sprintf(`SELECT id, name, key, metadata, labels, conn.role, conn.label, conn.created_by
 		        FROM things th
 		        INNER JOIN connections conn
 		        ON th.id = conn.thing_id
@@ -388,15 +418,19 @@ func (tr thingRepository) RetrieveByChannel(ctx context.Context, owner, chID str
 
 	var items []things.Thing
 	for rows.Next() {
-		dbth := dbThing{Owner: owner}
+		dbth := dbConnectedThing{dbThing: dbThing{Owner: owner}}
 		if err := rows.StructScan(&dbth); err != nil {
 			return things.Page{}, errors.Wrap(things.ErrSelectEntity, err)
 		}
 
-		th, err := toThing(dbth)
+		th, err := toThing(dbth.dbThing)
 		if err != nil {
 			return things.Page{}, errors.Wrap(things.ErrViewEntity, err)
 		}
+		if !pm.Disconnected {
+			conn := toConnMetadata(dbth.dbConnMetadata)
+			th.Connection = &conn
+		}
 
 		items = append(items, th)
 	}
@@ -429,11 +463,17 @@ func (tr thingRepository) Remove(ctx context.Context, owner, id string) error {
 }
 
 type dbThing struct {
-	ID       string `db:"id"`
-	Owner    string `db:"owner"`
-	Name     string `db:"name"`
-	Key      string `db:"key"`
-	Metadata []byte `db:"metadata"`
+	ID       string   `db:"id"`
+	Owner    string   `db:"owner"`
+	Name     string   `db:"name"`
+	Key      string   `db:"key"`
+	Metadata []byte   `db:"metadata"`
+	Labels   dbLabels `db:"labels"`
+}
+
+type dbConnectedThing struct {
+	dbThing
+	dbConnMetadata
 }
 
 func toDBThing(th things.Thing) (dbThing, error) {
@@ -452,6 +492,7 @@ func toDBThing(th things.Thing) (dbThing, error) {
 		Name:     th.Name,
 		Key:      th.Key,
 		Metadata: data,
+		Labels:   dbLabels(th.Labels),
 	}, nil
 }
 
@@ -467,5 +508,6 @@ func toThing(dbth dbThing) (things.Thing, error) {
 		Name:     dbth.Name,
 		Key:      dbth.Key,
 		Metadata: metadata,
+		Labels:   labels.Labels(dbth.Labels),
 	}, nil
 }
@@ -11,6 +11,7 @@ import (
 	"testing"
 
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/postgres"
@@ -543,6 +544,82 @@ func TestMultiThingRetrieval(t *testing.T) {
 	}
 }
 
+func TestMultiThingRetrievalBySelector(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	err := cleanTestTable(context.Background(), "things", dbMiddleware)
+	assert.Nil(t, err, fmt.Sprintf("cleaning table 'things' expected to success %v", err))
+	thingRepo := postgres.NewThingRepository(dbMiddleware)
+
+	email := "thing-selector-retrieval@example.com"
+	n := uint64(10)
+	prodNum := uint64(4)
+	for i := uint64(0); i < n; i++ {
+		id, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		key, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		th := things.Thing{
+			Owner:  email,
+			ID:     id,
+			Key:    key,
+			Labels: labels.Labels{"env": "dev"},
+		}
+		if i < prodNum {
+			th.Labels = labels.Labels{"env": "prod", "region": "eu"}
+		}
+		// The last thing is not labeled at all.
+		if i == n-1 {
+			th.Labels = nil
+		}
+
+		_, err = thingRepo.Save(context.Background(), th)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc     string
+		selector string
+		size     uint64
+	}{
+		{
+			desc:     "retrieve things with equal label",
+			selector: "env=prod",
+			size:     prodNum,
+		},
+		{
+			desc:     "retrieve things with not equal label",
+			selector: "env!=prod",
+			size:     n - prodNum,
+		},
+		{
+			desc:     "retrieve things with existing label",
+			selector: "env",
+			size:     n - 1,
+		},
+		{
+			desc:     "retrieve things with missing label",
+			selector: "!region",
+			size:     n - prodNum,
+		},
+		{
+			desc:     "retrieve things with multiple requirements",
+			selector: "env=prod,region!=eu",
+			size:     0,
+		},
+	}
+
+	for _, tc := range cases {
+		sel, err := labels.Parse(tc.selector)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		pm := things.PageMetadata{Offset: 0, Limit: n, Selector: sel}
+		page, err := thingRepo.RetrieveAll(context.Background(), email, pm)
+		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %s\n", tc.desc, err))
+		size := uint64(len(page.Things))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected size %d got %d\n", tc.desc, tc.size, size))
+		assert.Equal(t, tc.size, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, tc.size, page.Total))
+	}
+}
+
 func TestMultiThingRetrievalByChannel(t *testing.T) {
 	email := "thing-multi-retrieval-by-channel@example.com"
 
@@ -582,7 +659,7 @@ func TestMultiThingRetrievalByChannel(t *testing.T) {
 			break
 		}
 
-		err = channelRepo.Connect(context.Background(), email, []string{chID}, []string{thID})
+		err = channelRepo.Connect(context.Background(), email, []string{chID}, []string{thID}, things.ConnectionMetadata{})
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 	}
 
@@ -0,0 +1,232 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.WebhookRepository = (*webhookRepository)(nil)
+
+type webhookRepository struct {
+	db Database
+}
+
+// NewWebhookRepository instantiates a PostgreSQL implementation of webhook
+// repository.
+func NewWebhookRepository(db Database) things.WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+func (wr webhookRepository) Save(ctx context.Context, w things.Webhook) error {
+	q := `INSERT INTO webhooks (id, owner, channel_id, url, secret, filter, batch_size, created_at)
+		  VALUES (:id, :owner, :channel_id, :url, :secret, :filter, :batch_size, :created_at);`
+
+	if _, err := wr.db.NamedExecContext(ctx, q, toDBWebhook(w)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(things.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(things.ErrConflict, err)
+			}
+		}
+		return errors.Wrap(things.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) RetrieveByID(ctx context.Context, owner, id string) (things.Webhook, error) {
+	q := `SELECT id, owner, channel_id, url, secret, filter, batch_size, created_at
+		  FROM webhooks WHERE id = $1 AND owner = $2;`
+
+	var dbw dbWebhook
+	if err := wr.db.QueryRowxContext(ctx, q, id, owner).StructScan(&dbw); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && errInvalid == pqErr.Code.Name() {
+			return things.Webhook{}, things.ErrNotFound
+		}
+		return things.Webhook{}, errors.Wrap(things.ErrSelectEntity, err)
+	}
+
+	return toWebhook(dbw), nil
+}
+
+func (wr webhookRepository) RetrieveByChannel(ctx context.Context, owner, chID string) ([]things.Webhook, error) {
+	q := `SELECT id, owner, channel_id, url, secret, filter, batch_size, created_at
+		  FROM webhooks WHERE channel_id = :channel_id AND owner = :owner ORDER BY created_at;`
+
+	return wr.retrieve(ctx, q, map[string]interface{}{"channel_id": chID, "owner": owner})
+}
+
+func (wr webhookRepository) RetrieveAllByChannel(ctx context.Context, chID string) ([]things.Webhook, error) {
+	q := `SELECT id, owner, channel_id, url, secret, filter, batch_size, created_at
+		  FROM webhooks WHERE channel_id = :channel_id ORDER BY created_at;`
+
+	return wr.retrieve(ctx, q, map[string]interface{}{"channel_id": chID})
+}
+
+func (wr webhookRepository) retrieve(ctx context.Context, q string, params map[string]interface{}) ([]things.Webhook, error) {
+	rows, err := wr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && errInvalid == pqErr.Code.Name() {
+			return []things.Webhook{}, nil
+		}
+		return nil, errors.Wrap(things.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	webhooks := []things.Webhook{}
+	for rows.Next() {
+		var dbw dbWebhook
+		if err := rows.StructScan(&dbw); err != nil {
+			return nil, errors.Wrap(things.ErrSelectEntity, err)
+		}
+		webhooks = append(webhooks, toWebhook(dbw))
+	}
+
+	return webhooks, nil
+}
+
+func (wr webhookRepository) Count(ctx context.Context, owner string) (uint64, error) {
+	q := `SELECT COUNT(*) FROM webhooks WHERE owner = :owner;`
+
+	count, err := total(ctx, wr.db, q, map[string]interface{}{"owner": owner})
+	if err != nil {
+		return 0, errors.Wrap(things.ErrSelectEntity, err)
+	}
+
+	return count, nil
+}
+
+func (wr webhookRepository) Remove(ctx context.Context, owner, id string) error {
+	q := `DELETE FROM webhooks WHERE id = :id AND owner = :owner;`
+
+	params := map[string]interface{}{"id": id, "owner": owner}
+	if _, err := wr.db.NamedExecContext(ctx, q, params); err != nil {
+		return errors.Wrap(things.ErrRemoveEntity, err)
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) SaveDelivery(ctx context.Context, d things.Delivery) error {
+	q := `INSERT INTO webhook_deliveries (webhook_id, messages, status, error, delivered_at)
+		  VALUES (:webhook_id, :messages, :status, :error, :delivered_at);`
+
+	dbd := dbDelivery{
+		WebhookID:   d.WebhookID,
+		Messages:    d.Messages,
+		Status:      d.Status,
+		Error:       d.Error,
+		DeliveredAt: d.DeliveredAt,
+	}
+	if _, err := wr.db.NamedExecContext(ctx, q, dbd); err != nil {
+		return errors.Wrap(things.ErrCreateEntity, err)
+	}
+
+	return nil
+}
+
+func (wr webhookRepository) RetrieveDeliveries(ctx context.Context, webhookID string, pm things.PageMetadata) (things.DeliveriesPage, error) {
+	q := `SELECT webhook_id, messages, status, error, delivered_at FROM webhook_deliveries
+		  WHERE webhook_id = :webhook_id ORDER BY delivered_at DESC LIMIT :limit OFFSET :offset;`
+
+	params := map[string]interface{}{
+		"webhook_id": webhookID,
+		"limit":      pm.Limit,
+		"offset":     pm.Offset,
+	}
+	rows, err := wr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return things.DeliveriesPage{}, errors.Wrap(things.ErrSelectEntity, err)
+	}
+	defer rows.Close()
+
+	deliveries := []things.Delivery{}
+	for rows.Next() {
+		var dbd dbDelivery
+		if err := rows.StructScan(&dbd); err != nil {
+			return things.DeliveriesPage{}, errors.Wrap(things.ErrSelectEntity, err)
+		}
+		deliveries = append(deliveries, things.Delivery{
+			WebhookID:   dbd.WebhookID,
+			Messages:    dbd.Messages,
+			Status:      dbd.Status,
+			Error:       dbd.Error,
+			DeliveredAt: dbd.DeliveredAt,
+		})
+	}
+
+	cq := `SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = :webhook_id;`
+	total, err := total(ctx, wr.db, cq, params)
+	if err != nil {
+		return things.DeliveriesPage{}, errors.Wrap(things.ErrSelectEntity, err)
+	}
+
+	return things.DeliveriesPage{
+		PageMetadata: things.PageMetadata{
+			Total:  total,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
+		},
+		Deliveries: deliveries,
+	}, nil
+}
+
+type dbWebhook struct {
+	ID        string    `db:"id"`
+	Owner     string    `db:"owner"`
+	ChannelID string    `db:"channel_id"`
+	URL       string    `db:"url"`
+	Secret    string    `db:"secret"`
+	Filter    string    `db:"filter"`
+	BatchSize uint64    `db:"batch_size"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBWebhook(w things.Webhook) dbWebhook {
+	return dbWebhook{
+		ID:        w.ID,
+		Owner:     w.Owner,
+		ChannelID: w.ChannelID,
+		URL:       w.URL,
+		Secret:    w.Secret,
+		Filter:    w.Filter,
+		BatchSize: w.BatchSize,
+		CreatedAt: w.CreatedAt,
+	}
+}
+
+func toWebhook(dbw dbWebhook) things.Webhook {
+	return things.Webhook{
+		ID:        dbw.ID,
+		Owner:     dbw.Owner,
+		ChannelID: dbw.ChannelID,
+		URL:       dbw.URL,
+		Secret:    dbw.Secret,
+		Filter:    dbw.Filter,
+		BatchSize: dbw.BatchSize,
+		CreatedAt: dbw.CreatedAt,
+	}
+}
+
+type dbDelivery struct {
+	WebhookID   string    `db:"webhook_id"`
+	Messages    uint64    `db:"messages"`
+	Status      int       `db:"status"`
+	Error       string    `db:"error"`
+	DeliveredAt time.Time `db:"delivered_at"`
+}
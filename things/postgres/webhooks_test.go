@@ -0,0 +1,213 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSave(t *testing.T) {
+	email := "webhook-save@example.com"
+	webhookRepo := postgres.NewWebhookRepository(postgres.NewDatabase(db))
+
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	w := things.Webhook{
+		ID:        id,
+		Owner:     email,
+		ChannelID: chID,
+		URL:       "http://localhost",
+		BatchSize: 1,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	cases := []struct {
+		desc    string
+		webhook things.Webhook
+		err     error
+	}{
+		{
+			desc:    "save new webhook",
+			webhook: w,
+			err:     nil,
+		},
+		{
+			desc:    "save existing webhook",
+			webhook: w,
+			err:     things.ErrConflict,
+		},
+		{
+			desc: "save webhook with invalid channel id",
+			webhook: things.Webhook{
+				ID:        chID,
+				Owner:     email,
+				ChannelID: "invalid",
+				URL:       "http://localhost",
+				BatchSize: 1,
+				CreatedAt: time.Now().UTC(),
+			},
+			err: things.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		err := webhookRepo.Save(context.Background(), tc.webhook)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestWebhookRetrieval(t *testing.T) {
+	email := "webhook-retrieval@example.com"
+	webhookRepo := postgres.NewWebhookRepository(postgres.NewDatabase(db))
+
+	chID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	n := uint64(3)
+	for i := uint64(0); i < n; i++ {
+		id, err := idProvider.ID()
+		require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+		w := things.Webhook{
+			ID:        id,
+			Owner:     email,
+			ChannelID: chID,
+			URL:       "http://localhost",
+			BatchSize: 1,
+			CreatedAt: time.Now().UTC(),
+		}
+		err = webhookRepo.Save(context.Background(), w)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	whs, err := webhookRepo.RetrieveByChannel(context.Background(), email, chID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, n, uint64(len(whs)), fmt.Sprintf("retrieve webhooks by channel: expected %d got %d\n", n, len(whs)))
+
+	whs, err = webhookRepo.RetrieveByChannel(context.Background(), wrongValue, chID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, 0, len(whs), fmt.Sprintf("retrieve webhooks of other user: expected 0 got %d\n", len(whs)))
+
+	whs, err = webhookRepo.RetrieveAllByChannel(context.Background(), chID)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, n, uint64(len(whs)), fmt.Sprintf("retrieve all webhooks by channel: expected %d got %d\n", n, len(whs)))
+
+	count, err := webhookRepo.Count(context.Background(), email)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, n, count, fmt.Sprintf("count webhooks: expected %d got %d\n", n, count))
+
+	_, err = webhookRepo.RetrieveByID(context.Background(), wrongValue, whs[0].ID)
+	assert.True(t, errors.Contains(err, things.ErrNotFound), fmt.Sprintf("retrieve webhook of other user: expected %s got %s\n", things.ErrNotFound, err))
+}
+
+func TestWebhookRemoval(t *testing.T) {
+	email := "webhook-removal@example.com"
+	webhookRepo := postgres.NewWebhookRepository(postgres.NewDatabase(db))
+
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	w := things.Webhook{
+		ID:        id,
+		Owner:     email,
+		ChannelID: chID,
+		URL:       "http://localhost",
+		BatchSize: 1,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = webhookRepo.Save(context.Background(), w)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	err = webhookRepo.SaveDelivery(context.Background(), things.Delivery{WebhookID: id, Messages: 1, Status: 200, DeliveredAt: time.Now().UTC()})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	// show that the removal works the same for both existing and non-existing
+	// (removed) webhook
+	for i := 0; i < 2; i++ {
+		err := webhookRepo.Remove(context.Background(), email, id)
+		require.Nil(t, err, fmt.Sprintf("#%d: failed to remove webhook due to: %s", i, err))
+
+		_, err = webhookRepo.RetrieveByID(context.Background(), email, id)
+		assert.True(t, errors.Contains(err, things.ErrNotFound), fmt.Sprintf("#%d: expected %s got %s", i, things.ErrNotFound, err))
+	}
+
+	page, err := webhookRepo.RetrieveDeliveries(context.Background(), id, things.PageMetadata{Limit: 10})
+	assert.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, uint64(0), page.Total, fmt.Sprintf("expected removed delivery log got %d deliveries\n", page.Total))
+}
+
+func TestRetrieveDeliveries(t *testing.T) {
+	email := "webhook-deliveries@example.com"
+	webhookRepo := postgres.NewWebhookRepository(postgres.NewDatabase(db))
+
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+	chID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("got unexpected error: %s", err))
+
+	err = webhookRepo.Save(context.Background(), things.Webhook{
+		ID:        id,
+		Owner:     email,
+		ChannelID: chID,
+		URL:       "http://localhost",
+		BatchSize: 1,
+		CreatedAt: time.Now().UTC(),
+	})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	n := uint64(10)
+	now := time.Now().UTC()
+	for i := uint64(0); i < n; i++ {
+		d := things.Delivery{
+			WebhookID:   id,
+			Messages:    i + 1,
+			Status:      200,
+			DeliveredAt: now.Add(time.Duration(i) * time.Second),
+		}
+		err := webhookRepo.SaveDelivery(context.Background(), d)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+
+	cases := []struct {
+		desc     string
+		pm       things.PageMetadata
+		size     uint64
+		messages uint64
+	}{
+		{
+			desc:     "retrieve all deliveries",
+			pm:       things.PageMetadata{Offset: 0, Limit: n},
+			size:     n,
+			messages: n,
+		},
+		{
+			desc:     "retrieve subset of deliveries",
+			pm:       things.PageMetadata{Offset: 5, Limit: n},
+			size:     5,
+			messages: 5,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := webhookRepo.RetrieveDeliveries(context.Background(), id, tc.pm)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s\n", tc.desc, err))
+		assert.Equal(t, n, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, n, page.Total))
+		assert.Equal(t, tc.size, uint64(len(page.Deliveries)), fmt.Sprintf("%s: expected size %d got %d\n", tc.desc, tc.size, len(page.Deliveries)))
+		// The newest delivery comes first.
+		assert.Equal(t, tc.messages, page.Deliveries[0].Messages, fmt.Sprintf("%s: expected %d messages got %d\n", tc.desc, tc.messages, page.Deliveries[0].Messages))
+	}
+}
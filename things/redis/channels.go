@@ -12,7 +12,10 @@ import (
 	"github.com/mainflux/mainflux/things"
 )
 
-const chanPrefix = "channel"
+const (
+	chanPrefix  = "channel"
+	rolesSuffix = "roles"
+)
 
 var _ things.ChannelCache = (*channelCache)(nil)
 
@@ -25,11 +28,17 @@ func NewChannelCache(client *redis.Client) things.ChannelCache {
 	return channelCache{client: client}
 }
 
-func (cc channelCache) Connect(ctx context.Context, chanID, thingID string) error {
+func (cc channelCache) Connect(ctx context.Context, chanID, thingID, role string) error {
 	cid, tid := kv(chanID, thingID)
 	if err := cc.client.SAdd(ctx, cid, tid).Err(); err != nil {
 		return errors.Wrap(things.ErrConnect, err)
 	}
+	if role == "" {
+		return nil
+	}
+	if err := cc.client.HSet(ctx, rolesKey(cid), tid, role).Err(); err != nil {
+		return errors.Wrap(things.ErrConnect, err)
+	}
 	return nil
 }
 
@@ -38,17 +47,25 @@ func (cc channelCache) HasThing(ctx context.Context, chanID, thingID string) boo
 	return cc.client.SIsMember(ctx, cid, tid).Val()
 }
 
+func (cc channelCache) Role(ctx context.Context, chanID, thingID string) string {
+	cid, tid := kv(chanID, thingID)
+	return cc.client.HGet(ctx, rolesKey(cid), tid).Val()
+}
+
 func (cc channelCache) Disconnect(ctx context.Context, chanID, thingID string) error {
 	cid, tid := kv(chanID, thingID)
 	if err := cc.client.SRem(ctx, cid, tid).Err(); err != nil {
 		return errors.Wrap(things.ErrDisconnect, err)
 	}
+	if err := cc.client.HDel(ctx, rolesKey(cid), tid).Err(); err != nil {
+		return errors.Wrap(things.ErrDisconnect, err)
+	}
 	return nil
 }
 
 func (cc channelCache) Remove(ctx context.Context, chanID string) error {
 	cid, _ := kv(chanID, "0")
-	if err := cc.client.Del(ctx, cid).Err(); err != nil {
+	if err := cc.client.Del(ctx, cid, rolesKey(cid)).Err(); err != nil {
 		return errors.Wrap(things.ErrRemoveEntity, err)
 	}
 	return nil
@@ -59,3 +76,8 @@ func kv(chanID, thingID string) (string, string) {
 	cid := fmt.Sprintf("%s:%s", chanPrefix, chanID)
 	return cid, thingID
 }
+
+// Generates key of the hash holding connection roles
+func rolesKey(cid string) string {
+	return fmt.Sprintf("%s:%s", cid, rolesSuffix)
+}
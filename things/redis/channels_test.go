@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/mainflux/mainflux/things"
 	"github.com/mainflux/mainflux/things/redis"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -36,7 +37,7 @@ func TestConnect(t *testing.T) {
 		},
 	}
 	for _, tc := range cases {
-		err := channelCache.Connect(context.Background(), cid, tid)
+		err := channelCache.Connect(context.Background(), cid, tid, "")
 		assert.Nil(t, err, fmt.Sprintf("%s: fail to connect due to: %s\n", tc.desc, err))
 	}
 }
@@ -47,7 +48,7 @@ func TestHasThing(t *testing.T) {
 	cid := "123"
 	tid := "321"
 
-	err := channelCache.Connect(context.Background(), cid, tid)
+	err := channelCache.Connect(context.Background(), cid, tid, "")
 	require.Nil(t, err, fmt.Sprintf("connect thing to channel: fail to connect due to: %s\n", err))
 
 	cases := map[string]struct {
@@ -77,6 +78,46 @@ func TestHasThing(t *testing.T) {
 		assert.Equal(t, tc.hasAccess, hasAccess, fmt.Sprintf("%s: expected %t got %t\n", desc, tc.hasAccess, hasAccess))
 	}
 }
+
+func TestRole(t *testing.T) {
+	channelCache := redis.NewChannelCache(redisClient)
+
+	cid := "456"
+	pubID := "654"
+	anyID := "655"
+
+	err := channelCache.Connect(context.Background(), cid, pubID, things.PublisherRole)
+	require.Nil(t, err, fmt.Sprintf("connect publisher to channel: fail to connect due to: %s\n", err))
+	err = channelCache.Connect(context.Background(), cid, anyID, "")
+	require.Nil(t, err, fmt.Sprintf("connect thing to channel: fail to connect due to: %s\n", err))
+
+	cases := map[string]struct {
+		cid  string
+		tid  string
+		role string
+	}{
+		"role of connection with role": {
+			cid:  cid,
+			tid:  pubID,
+			role: things.PublisherRole,
+		},
+		"role of connection without role": {
+			cid:  cid,
+			tid:  anyID,
+			role: "",
+		},
+		"role of non-existing connection": {
+			cid:  pubID,
+			tid:  cid,
+			role: "",
+		},
+	}
+
+	for desc, tc := range cases {
+		role := channelCache.Role(context.Background(), tc.cid, tc.tid)
+		assert.Equal(t, tc.role, role, fmt.Sprintf("%s: expected %s got %s\n", desc, tc.role, role))
+	}
+}
 func TestDisconnect(t *testing.T) {
 	channelCache := redis.NewChannelCache(redisClient)
 
@@ -84,7 +125,7 @@ func TestDisconnect(t *testing.T) {
 	tid := "321"
 	tid2 := "322"
 
-	err := channelCache.Connect(context.Background(), cid, tid)
+	err := channelCache.Connect(context.Background(), cid, tid, "")
 	require.Nil(t, err, fmt.Sprintf("connect thing to channel: fail to connect due to: %s\n", err))
 
 	cases := []struct {
@@ -122,7 +163,7 @@ func TestRemove(t *testing.T) {
 	cid2 := "124"
 	tid := "321"
 
-	err := channelCache.Connect(context.Background(), cid, tid)
+	err := channelCache.Connect(context.Background(), cid, tid, "")
 	require.Nil(t, err, fmt.Sprintf("connect thing to channel: fail to connect due to: %s\n", err))
 
 	cases := []struct {
@@ -0,0 +1,161 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+)
+
+var _ things.Service = (*consistency)(nil)
+
+type consistency struct {
+	svc          things.Service
+	thingCache   things.ThingCache
+	channelCache things.ChannelCache
+}
+
+// NewConsistencyMiddleware returns wrapper around things service providing
+// read-your-writes consistency of the things cache. Thing and channel
+// mutations update the cache before returning, so the changes are visible
+// to the adapters authorizing the messages through the cache as soon as the
+// request completes.
+func NewConsistencyMiddleware(svc things.Service, tcache things.ThingCache, ccache things.ChannelCache) things.Service {
+	return consistency{
+		svc:          svc,
+		thingCache:   tcache,
+		channelCache: ccache,
+	}
+}
+
+func (c consistency) CreateThings(ctx context.Context, token string, ths ...things.Thing) ([]things.Thing, error) {
+	sths, err := c.svc.CreateThings(ctx, token, ths...)
+	if err != nil {
+		return sths, err
+	}
+
+	for _, th := range sths {
+		if err := c.thingCache.Save(ctx, th.Key, th.ID); err != nil {
+			return sths, err
+		}
+	}
+
+	return sths, nil
+}
+
+func (c consistency) UpdateKey(ctx context.Context, token, id, key string) error {
+	if err := c.svc.UpdateKey(ctx, token, id, key); err != nil {
+		return err
+	}
+
+	if err := c.thingCache.Remove(ctx, id); err != nil {
+		return err
+	}
+	return c.thingCache.Save(ctx, key, id)
+}
+
+func (c consistency) RemoveThing(ctx context.Context, token, id string) error {
+	return c.svc.RemoveThing(ctx, token, id)
+}
+
+func (c consistency) RemoveChannel(ctx context.Context, token, id string) error {
+	return c.svc.RemoveChannel(ctx, token, id)
+}
+
+func (c consistency) Connect(ctx context.Context, token string, chIDs, thIDs []string, meta things.ConnectionMetadata) error {
+	if err := c.svc.Connect(ctx, token, chIDs, thIDs, meta); err != nil {
+		return err
+	}
+
+	for _, chID := range chIDs {
+		for _, thID := range thIDs {
+			if err := c.channelCache.Connect(ctx, chID, thID, meta.Role); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c consistency) Disconnect(ctx context.Context, token string, chIDs, thIDs []string) error {
+	return c.svc.Disconnect(ctx, token, chIDs, thIDs)
+}
+
+func (c consistency) UpdateThing(ctx context.Context, token string, thing things.Thing) error {
+	return c.svc.UpdateThing(ctx, token, thing)
+}
+
+func (c consistency) ShareThing(ctx context.Context, token, thingID string, actions, userIDs []string) error {
+	return c.svc.ShareThing(ctx, token, thingID, actions, userIDs)
+}
+
+func (c consistency) ViewThing(ctx context.Context, token, id string) (things.Thing, error) {
+	return c.svc.ViewThing(ctx, token, id)
+}
+
+func (c consistency) ListThings(ctx context.Context, token string, pm things.PageMetadata) (things.Page, error) {
+	return c.svc.ListThings(ctx, token, pm)
+}
+
+func (c consistency) ListThingsByChannel(ctx context.Context, token, chID string, pm things.PageMetadata) (things.Page, error) {
+	return c.svc.ListThingsByChannel(ctx, token, chID, pm)
+}
+
+func (c consistency) CreateChannels(ctx context.Context, token string, channels ...things.Channel) ([]things.Channel, error) {
+	return c.svc.CreateChannels(ctx, token, channels...)
+}
+
+func (c consistency) UpdateChannel(ctx context.Context, token string, channel things.Channel) error {
+	return c.svc.UpdateChannel(ctx, token, channel)
+}
+
+func (c consistency) ViewChannel(ctx context.Context, token, id string) (things.Channel, error) {
+	return c.svc.ViewChannel(ctx, token, id)
+}
+
+func (c consistency) ListChannels(ctx context.Context, token string, pm things.PageMetadata) (things.ChannelsPage, error) {
+	return c.svc.ListChannels(ctx, token, pm)
+}
+
+func (c consistency) ListChannelsByThing(ctx context.Context, token, thID string, pm things.PageMetadata) (things.ChannelsPage, error) {
+	return c.svc.ListChannelsByThing(ctx, token, thID, pm)
+}
+
+func (c consistency) CanAccessByKey(ctx context.Context, chanID, key, op string) (string, error) {
+	return c.svc.CanAccessByKey(ctx, chanID, key, op)
+}
+
+func (c consistency) CanAccessByID(ctx context.Context, chanID, thingID, op string) error {
+	return c.svc.CanAccessByID(ctx, chanID, thingID, op)
+}
+
+func (c consistency) IsChannelOwner(ctx context.Context, owner, chanID string) error {
+	return c.svc.IsChannelOwner(ctx, owner, chanID)
+}
+
+func (c consistency) Identify(ctx context.Context, key string) (string, error) {
+	return c.svc.Identify(ctx, key)
+}
+
+func (c consistency) ListMembers(ctx context.Context, token, groupID string, pm things.PageMetadata) (things.Page, error) {
+	return c.svc.ListMembers(ctx, token, groupID, pm)
+}
+
+func (c consistency) CreateWebhook(ctx context.Context, token string, w things.Webhook) (things.Webhook, error) {
+	return c.svc.CreateWebhook(ctx, token, w)
+}
+
+func (c consistency) ListWebhooks(ctx context.Context, token, chID string) ([]things.Webhook, error) {
+	return c.svc.ListWebhooks(ctx, token, chID)
+}
+
+func (c consistency) RemoveWebhook(ctx context.Context, token, chID, id string) error {
+	return c.svc.RemoveWebhook(ctx, token, chID, id)
+}
+
+func (c consistency) ListDeliveries(ctx context.Context, token, chID, id string, pm things.PageMetadata) (things.DeliveriesPage, error) {
+	return c.svc.ListDeliveries(ctx, token, chID, id, pm)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package redis_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/uuid"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/mocks"
+	"github.com/mainflux/mainflux/things/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConsistentService(tokens map[string]string) (things.Service, things.ThingCache, things.ChannelCache) {
+	userPolicy := mocks.MockSubjectSet{Object: "users", Relation: "member"}
+	adminPolicy := mocks.MockSubjectSet{Object: "authorities", Relation: "member"}
+	auth := mocks.NewAuthService(tokens, map[string][]mocks.MockSubjectSet{
+		adminEmail: {userPolicy, adminPolicy}, email: {userPolicy}})
+	conns := make(chan mocks.Connection)
+	thingsRepo := mocks.NewThingRepository(conns)
+	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	webhooksRepo := mocks.NewWebhookRepository()
+	chanCache := redis.NewChannelCache(redisClient)
+	thingCache := redis.NewThingCache(redisClient)
+
+	svc := things.New(auth, thingsRepo, channelsRepo, webhooksRepo, webhookLimit, chanCache, thingCache, uuid.NewMock(), nil)
+	return redis.NewConsistencyMiddleware(svc, thingCache, chanCache), thingCache, chanCache
+}
+
+func TestConsistencyCreateThings(t *testing.T) {
+	_ = redisClient.FlushAll(context.Background()).Err()
+
+	svc, thingCache, _ := newConsistentService(map[string]string{token: email})
+
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	id, err := thingCache.ID(context.Background(), ths[0].Key)
+	assert.Nil(t, err, fmt.Sprintf("create things: expected thing to be cached got %s\n", err))
+	assert.Equal(t, ths[0].ID, id, fmt.Sprintf("create things: expected %s got %s\n", ths[0].ID, id))
+}
+
+func TestConsistencyUpdateKey(t *testing.T) {
+	_ = redisClient.FlushAll(context.Background()).Err()
+
+	svc, thingCache, _ := newConsistentService(map[string]string{token: email})
+
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th := ths[0]
+
+	key := "new-key"
+	err = svc.UpdateKey(context.Background(), token, th.ID, key)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = thingCache.ID(context.Background(), th.Key)
+	assert.NotNil(t, err, "update key: expected old key to be removed from cache")
+	id, err := thingCache.ID(context.Background(), key)
+	assert.Nil(t, err, fmt.Sprintf("update key: expected new key to be cached got %s\n", err))
+	assert.Equal(t, th.ID, id, fmt.Sprintf("update key: expected %s got %s\n", th.ID, id))
+}
+
+func TestConsistencyConnect(t *testing.T) {
+	_ = redisClient.FlushAll(context.Background()).Err()
+
+	svc, _, chanCache := newConsistentService(map[string]string{token: email})
+
+	ths, err := svc.CreateThings(context.Background(), token, things.Thing{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	th := ths[0]
+	chs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "a"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	ch := chs[0]
+
+	cases := []struct {
+		desc      string
+		op        func(ctx context.Context, token string, chIDs, thIDs []string) error
+		connected bool
+	}{
+		{
+			desc: "connect thing",
+			op: func(ctx context.Context, token string, chIDs, thIDs []string) error {
+				return svc.Connect(ctx, token, chIDs, thIDs, things.ConnectionMetadata{})
+			},
+			connected: true,
+		},
+		{
+			desc:      "disconnect thing",
+			op:        svc.Disconnect,
+			connected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := tc.op(context.Background(), token, []string{ch.ID}, []string{th.ID})
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+
+		connected := chanCache.HasThing(context.Background(), ch.ID, th.ID)
+		assert.Equal(t, tc.connected, connected, fmt.Sprintf("%s: expected connected %t got %t\n", tc.desc, tc.connected, connected))
+	}
+}
@@ -172,14 +172,21 @@ func (rce removeChannelEvent) Encode() map[string]interface{} {
 type connectThingEvent struct {
 	chanID  string
 	thingID string
+	role    string
 }
 
 func (cte connectThingEvent) Encode() map[string]interface{} {
-	return map[string]interface{}{
+	val := map[string]interface{}{
 		"chan_id":   cte.chanID,
 		"thing_id":  cte.thingID,
 		"operation": thingConnect,
 	}
+
+	if cte.role != "" {
+		val["role"] = cte.role
+	}
+
+	return val
 }
 
 type disconnectThingEvent struct {
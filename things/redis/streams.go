@@ -190,8 +190,8 @@ func (es eventStore) RemoveChannel(ctx context.Context, token, id string) error
 	return nil
 }
 
-func (es eventStore) Connect(ctx context.Context, token string, chIDs, thIDs []string) error {
-	if err := es.svc.Connect(ctx, token, chIDs, thIDs); err != nil {
+func (es eventStore) Connect(ctx context.Context, token string, chIDs, thIDs []string, meta things.ConnectionMetadata) error {
+	if err := es.svc.Connect(ctx, token, chIDs, thIDs, meta); err != nil {
 		return err
 	}
 
@@ -200,6 +200,7 @@ func (es eventStore) Connect(ctx context.Context, token string, chIDs, thIDs []s
 			event := connectThingEvent{
 				chanID:  chID,
 				thingID: thID,
+				role:    meta.Role,
 			}
 			record := &redis.XAddArgs{
 				Stream:       streamID,
@@ -236,12 +237,12 @@ func (es eventStore) Disconnect(ctx context.Context, token string, chIDs, thIDs
 	return nil
 }
 
-func (es eventStore) CanAccessByKey(ctx context.Context, chanID string, key string) (string, error) {
-	return es.svc.CanAccessByKey(ctx, chanID, key)
+func (es eventStore) CanAccessByKey(ctx context.Context, chanID string, key, op string) (string, error) {
+	return es.svc.CanAccessByKey(ctx, chanID, key, op)
 }
 
-func (es eventStore) CanAccessByID(ctx context.Context, chanID string, thingID string) error {
-	return es.svc.CanAccessByID(ctx, chanID, thingID)
+func (es eventStore) CanAccessByID(ctx context.Context, chanID string, thingID, op string) error {
+	return es.svc.CanAccessByID(ctx, chanID, thingID, op)
 }
 
 func (es eventStore) IsChannelOwner(ctx context.Context, owner, chanID string) error {
@@ -255,3 +256,19 @@ func (es eventStore) Identify(ctx context.Context, key string) (string, error) {
 func (es eventStore) ListMembers(ctx context.Context, token, groupID string, pm things.PageMetadata) (things.Page, error) {
 	return es.svc.ListMembers(ctx, token, groupID, pm)
 }
+
+func (es eventStore) CreateWebhook(ctx context.Context, token string, w things.Webhook) (things.Webhook, error) {
+	return es.svc.CreateWebhook(ctx, token, w)
+}
+
+func (es eventStore) ListWebhooks(ctx context.Context, token, chID string) ([]things.Webhook, error) {
+	return es.svc.ListWebhooks(ctx, token, chID)
+}
+
+func (es eventStore) RemoveWebhook(ctx context.Context, token, chID, id string) error {
+	return es.svc.RemoveWebhook(ctx, token, chID, id)
+}
+
+func (es eventStore) ListDeliveries(ctx context.Context, token, chID, id string, pm things.PageMetadata) (things.DeliveriesPage, error) {
+	return es.svc.ListDeliveries(ctx, token, chID, id, pm)
+}
@@ -26,6 +26,7 @@ const (
 	email           = "user@example.com"
 	adminEmail      = "admin@example.com"
 	token           = "token"
+	webhookLimit    = 10
 	thingPrefix     = "thing."
 	thingCreate     = thingPrefix + "create"
 	thingUpdate     = thingPrefix + "update"
@@ -47,11 +48,12 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	webhooksRepo := mocks.NewWebhookRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, webhooksRepo, webhookLimit, chanCache, thingCache, idProvider, nil)
 }
 
 func TestCreateThings(t *testing.T) {
@@ -214,7 +216,7 @@ func TestListThingsByChannel(t *testing.T) {
 	schs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 	sch := schs[0]
-	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID})
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
@@ -450,7 +452,7 @@ func TestListChannelsByThing(t *testing.T) {
 	schs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 	sch := schs[0]
-	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID})
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	essvc := redis.NewEventStoreMiddleware(svc, redisClient)
@@ -565,7 +567,7 @@ func TestConnectEvent(t *testing.T) {
 
 	lastID := "0"
 	for _, tc := range cases {
-		err := svc.Connect(context.Background(), tc.key, []string{tc.chanID}, []string{tc.thingID})
+		err := svc.Connect(context.Background(), tc.key, []string{tc.chanID}, []string{tc.thingID}, things.ConnectionMetadata{})
 		assert.Equal(t, tc.err, err, fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 
 		streams := redisClient.XRead(context.Background(), &r.XReadArgs{
@@ -596,7 +598,7 @@ func TestDisconnectEvent(t *testing.T) {
 	schs, err := svc.CreateChannels(context.Background(), token, things.Channel{Name: "a"})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 	sch := schs[0]
-	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID})
+	err = svc.Connect(context.Background(), token, []string{sch.ID}, []string{sth.ID}, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
 
 	svc = redis.NewEventStoreMiddleware(svc, redisClient)
@@ -6,10 +6,16 @@ package things
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mainflux/mainflux/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/pkg/ulid"
 )
 
@@ -44,6 +50,12 @@ var (
 
 	// ErrFailedToRetrieveThings failed to retrieve things.
 	ErrFailedToRetrieveThings = errors.New("failed to retrieve group members")
+
+	// ErrQuotaExceeded indicates that the organization quota does not allow
+	// the operation.
+	ErrQuotaExceeded = errors.New("quota exceeded")
+
+	errWebhooksLimit = errors.New("webhooks limit reached")
 )
 
 const (
@@ -53,6 +65,13 @@ const (
 	readRelationKey   = "read"
 	writeRelationKey  = "write"
 	deleteRelationKey = "delete"
+
+	thingsQuota   = "things"
+	channelsQuota = "channels"
+	messagesQuota = "messages"
+
+	thingEntityType   = "thing"
+	channelEntityType = "channel"
 )
 
 // Service specifies an API that must be fullfiled by the domain service
@@ -115,20 +134,22 @@ type Service interface {
 	// belongs to the user identified by the provided key.
 	RemoveChannel(ctx context.Context, token, id string) error
 
-	// Connect adds things to the channels list of connected things.
-	Connect(ctx context.Context, token string, chIDs, thIDs []string) error
+	// Connect adds things to the channels list of connected things. The
+	// provided metadata is attached to every created connection.
+	Connect(ctx context.Context, token string, chIDs, thIDs []string, meta ConnectionMetadata) error
 
 	// Disconnect removes things from the channels list of connected
 	// things.
 	Disconnect(ctx context.Context, token string, chIDs, thIDs []string) error
 
 	// CanAccessByKey determines whether the channel can be accessed using the
-	// provided key and returns thing's id if access is allowed.
-	CanAccessByKey(ctx context.Context, chanID, key string) (string, error)
+	// provided key for the given operation and returns thing's id if access
+	// is allowed.
+	CanAccessByKey(ctx context.Context, chanID, key, op string) (string, error)
 
 	// CanAccessByID determines whether the channel can be accessed by
-	// the given thing and returns error if it cannot.
-	CanAccessByID(ctx context.Context, chanID, thingID string) error
+	// the given thing for the given operation and returns error if it cannot.
+	CanAccessByID(ctx context.Context, chanID, thingID, op string) error
 
 	// IsChannelOwner determines whether the channel can be accessed by
 	// the given user and returns error if it cannot.
@@ -139,6 +160,23 @@ type Service interface {
 
 	// ListMembers retrieves everything that is assigned to a group identified by groupID.
 	ListMembers(ctx context.Context, token, groupID string, pm PageMetadata) (Page, error)
+
+	// CreateWebhook adds the webhook to the channel that can be written by
+	// the user identified by the provided key. The number of the webhooks
+	// the user can own is limited.
+	CreateWebhook(ctx context.Context, token string, w Webhook) (Webhook, error)
+
+	// ListWebhooks retrieves the webhooks of the channel, that belong to
+	// the user identified by the provided key.
+	ListWebhooks(ctx context.Context, token, chID string) ([]Webhook, error)
+
+	// RemoveWebhook removes the webhook of the channel, that belongs to
+	// the user identified by the provided key.
+	RemoveWebhook(ctx context.Context, token, chID, id string) error
+
+	// ListDeliveries retrieves the subset of the delivery log of the
+	// webhook, that belongs to the user identified by the provided key.
+	ListDeliveries(ctx context.Context, token, chID, id string, pm PageMetadata) (DeliveriesPage, error)
 }
 
 // PageMetadata contains page metadata that helps navigation.
@@ -150,6 +188,7 @@ type PageMetadata struct {
 	Order             string                 `json:"order,omitempty"`
 	Dir               string                 `json:"dir,omitempty"`
 	Metadata          map[string]interface{} `json:"metadata,omitempty"`
+	Selector          labels.Selector        `json:"selector,omitempty"`
 	Disconnected      bool                   // Used for connected or disconnected lists
 	FetchSharedThings bool                   // Used for identifying fetching either all or shared things.
 }
@@ -160,38 +199,58 @@ type thingsService struct {
 	auth         mainflux.AuthServiceClient
 	things       ThingRepository
 	channels     ChannelRepository
+	webhooks     WebhookRepository
+	webhookLimit uint64
 	channelCache ChannelCache
 	thingCache   ThingCache
 	idProvider   mainflux.IDProvider
 	ulidProvider mainflux.IDProvider
+	validator    Validator
 }
 
-// New instantiates the things service implementation.
-func New(auth mainflux.AuthServiceClient, things ThingRepository, channels ChannelRepository, ccache ChannelCache, tcache ThingCache, idp mainflux.IDProvider) Service {
+// New instantiates the things service implementation. The webhookLimit is
+// the number of the webhooks the user can own. The nil validator accepts all
+// the things.
+func New(auth mainflux.AuthServiceClient, things ThingRepository, channels ChannelRepository, webhooks WebhookRepository, webhookLimit uint64, ccache ChannelCache, tcache ThingCache, idp mainflux.IDProvider, validator Validator) Service {
+	if validator == nil {
+		validator = NewNopValidator()
+	}
 	return &thingsService{
 		auth:         auth,
 		things:       things,
 		channels:     channels,
+		webhooks:     webhooks,
+		webhookLimit: webhookLimit,
 		channelCache: ccache,
 		thingCache:   tcache,
 		idProvider:   idp,
 		ulidProvider: ulid.New(),
+		validator:    validator,
 	}
 }
 
 func (ts *thingsService) CreateThings(ctx context.Context, token string, things ...Thing) ([]Thing, error) {
+	ctx = auth.WithScope(ctx, "things:write")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return []Thing{}, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), usersObjectKey, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "things:write", res.GetId(), usersObjectKey, memberRelationKey); err != nil {
 		return []Thing{}, err
 	}
 
+	// All the things are validated up front, so that none of them is
+	// created unless all of them are valid.
+	for _, thing := range things {
+		if err := ts.validator.ValidateThing(ctx, thing); err != nil {
+			return []Thing{}, err
+		}
+	}
+
 	ths := []Thing{}
 	for _, thing := range things {
-		th, err := ts.createThing(ctx, &thing, res)
+		th, err := ts.createThing(ctx, token, &thing, res)
 
 		if err != nil {
 			return []Thing{}, err
@@ -203,7 +262,7 @@ func (ts *thingsService) CreateThings(ctx context.Context, token string, things
 }
 
 // createThing saves the Thing and adds identity as an owner(Read, Write, Delete policies) of the Thing.
-func (ts *thingsService) createThing(ctx context.Context, thing *Thing, identity *mainflux.UserIdentity) (Thing, error) {
+func (ts *thingsService) createThing(ctx context.Context, token string, thing *Thing, identity *mainflux.UserIdentity) (Thing, error) {
 
 	thing.Owner = identity.GetEmail()
 
@@ -224,16 +283,20 @@ func (ts *thingsService) createThing(ctx context.Context, thing *Thing, identity
 		thing.Key = key
 	}
 
+	if err := ts.reserveQuota(ctx, token, thingsQuota, thing.ID); err != nil {
+		return Thing{}, err
+	}
+
 	ths, err := ts.things.Save(ctx, *thing)
 	if err != nil {
+		ts.releaseQuota(ctx, thingsQuota, thing.ID)
 		return Thing{}, err
 	}
 	if len(ths) == 0 {
 		return Thing{}, ErrCreateEntity
 	}
 
-	ss := fmt.Sprintf("%s:%s#%s", "members", authoritiesObject, memberRelationKey)
-	if err := ts.claimOwnership(ctx, ths[0].ID, []string{readRelationKey, writeRelationKey, deleteRelationKey}, []string{identity.GetId(), ss}); err != nil {
+	if err := ts.applyPolicyTemplate(ctx, thingEntityType, ths[0].ID, identity.GetId()); err != nil {
 		return Thing{}, err
 	}
 
@@ -241,13 +304,14 @@ func (ts *thingsService) createThing(ctx context.Context, thing *Thing, identity
 }
 
 func (ts *thingsService) UpdateThing(ctx context.Context, token string, thing Thing) error {
+	ctx = auth.WithScope(ctx, "things:write")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), thing.ID, writeRelationKey); err != nil {
-		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "things:write", res.GetId(), thing.ID, writeRelationKey); err != nil {
+		if err := ts.authorize(ctx, token, "things:write", res.GetId(), authoritiesObject, memberRelationKey); err != nil {
 			return err
 		}
 	}
@@ -258,13 +322,14 @@ func (ts *thingsService) UpdateThing(ctx context.Context, token string, thing Th
 }
 
 func (ts *thingsService) ShareThing(ctx context.Context, token, thingID string, actions, userIDs []string) error {
+	ctx = auth.WithScope(ctx, "things:write")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), thingID, writeRelationKey); err != nil {
-		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "things:write", res.GetId(), thingID, writeRelationKey); err != nil {
+		if err := ts.authorize(ctx, token, "things:write", res.GetId(), authoritiesObject, memberRelationKey); err != nil {
 			return err
 		}
 	}
@@ -288,14 +353,29 @@ func (ts *thingsService) claimOwnership(ctx context.Context, objectID string, ac
 	return errs
 }
 
+// applyPolicyTemplate adds the default policies of the new entity, as
+// configured in the auth service.
+func (ts *thingsService) applyPolicyTemplate(ctx context.Context, entityType, entityID, ownerID string) error {
+	req := &mainflux.PolicyTemplateReq{
+		EntityType: entityType,
+		EntityID:   entityID,
+		OwnerID:    ownerID,
+	}
+	if _, err := ts.auth.ApplyPolicyTemplate(ctx, req); err != nil {
+		return errors.Wrap(fmt.Errorf("cannot claim ownership on object '%s' by user '%s'", entityID, ownerID), err)
+	}
+	return nil
+}
+
 func (ts *thingsService) UpdateKey(ctx context.Context, token, id, key string) error {
+	ctx = auth.WithScope(ctx, "things:write")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), id, writeRelationKey); err != nil {
-		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "things:write", res.GetId(), id, writeRelationKey); err != nil {
+		if err := ts.authorize(ctx, token, "things:write", res.GetId(), authoritiesObject, memberRelationKey); err != nil {
 			return err
 		}
 	}
@@ -306,13 +386,14 @@ func (ts *thingsService) UpdateKey(ctx context.Context, token, id, key string) e
 }
 
 func (ts *thingsService) ViewThing(ctx context.Context, token, id string) (Thing, error) {
+	ctx = auth.WithScope(ctx, "things:read")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return Thing{}, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), id, readRelationKey); err != nil {
-		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "things:read", res.GetId(), id, readRelationKey); err != nil {
+		if err := ts.authorize(ctx, token, "things:read", res.GetId(), authoritiesObject, memberRelationKey); err != nil {
 			return Thing{}, err
 		}
 	}
@@ -321,6 +402,7 @@ func (ts *thingsService) ViewThing(ctx context.Context, token, id string) (Thing
 }
 
 func (ts *thingsService) ListThings(ctx context.Context, token string, pm PageMetadata) (Page, error) {
+	ctx = auth.WithScope(ctx, "things:read")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return Page{}, errors.Wrap(ErrUnauthorizedAccess, err)
@@ -328,7 +410,7 @@ func (ts *thingsService) ListThings(ctx context.Context, token string, pm PageMe
 
 	subject := res.GetId()
 	// If the user is admin, fetch all things from database.
-	if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err == nil {
+	if err := ts.authorize(ctx, token, "things:read", res.GetId(), authoritiesObject, memberRelationKey); err == nil {
 		pm.FetchSharedThings = true
 		page, err := ts.things.RetrieveAll(ctx, res.GetEmail(), pm)
 		if err != nil {
@@ -338,8 +420,9 @@ func (ts *thingsService) ListThings(ctx context.Context, token string, pm PageMe
 	}
 
 	// If the user is not admin, check 'shared' parameter from page metadata.
-	// If user provides 'shared' key, fetch things from policies. Otherwise,
-	// fetch things from the database based on thing's 'owner' field.
+	// If user provides 'shared' key, fetch things from policies, which cover
+	// the things of the group subtrees the user is the admin of as well.
+	// Otherwise, fetch things from the database based on thing's 'owner' field.
 	if pm.FetchSharedThings {
 		req := &mainflux.ListPoliciesReq{Act: "read", Sub: subject}
 		lpr, err := ts.auth.ListPolicies(ctx, req)
@@ -347,11 +430,9 @@ func (ts *thingsService) ListThings(ctx context.Context, token string, pm PageMe
 			return Page{}, err
 		}
 
-		var page Page
-		for _, thingID := range lpr.Policies {
-			page.Things = append(page.Things, Thing{ID: thingID})
-		}
-		return page, nil
+		// The policies cover the other entities as well, which aren't
+		// found among the things.
+		return ts.things.RetrieveByIDs(ctx, lpr.Policies, pm)
 	}
 
 	// By default, fetch things from Things service.
@@ -364,6 +445,7 @@ func (ts *thingsService) ListThings(ctx context.Context, token string, pm PageMe
 }
 
 func (ts *thingsService) ListThingsByChannel(ctx context.Context, token, chID string, pm PageMetadata) (Page, error) {
+	ctx = auth.WithScope(ctx, "things:read")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return Page{}, errors.Wrap(ErrUnauthorizedAccess, err)
@@ -373,13 +455,14 @@ func (ts *thingsService) ListThingsByChannel(ctx context.Context, token, chID st
 }
 
 func (ts *thingsService) RemoveThing(ctx context.Context, token, id string) error {
+	ctx = auth.WithScope(ctx, "things:delete")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), id, deleteRelationKey); err != nil {
-		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "things:delete", res.GetId(), id, deleteRelationKey); err != nil {
+		if err := ts.authorize(ctx, token, "things:delete", res.GetId(), authoritiesObject, memberRelationKey); err != nil {
 			return err
 		}
 	}
@@ -387,10 +470,15 @@ func (ts *thingsService) RemoveThing(ctx context.Context, token, id string) erro
 	if err := ts.thingCache.Remove(ctx, id); err != nil {
 		return err
 	}
-	return ts.things.Remove(ctx, res.GetEmail(), id)
+	if err := ts.things.Remove(ctx, res.GetEmail(), id); err != nil {
+		return err
+	}
+	ts.releaseQuota(ctx, thingsQuota, id)
+	return nil
 }
 
 func (ts *thingsService) CreateChannels(ctx context.Context, token string, channels ...Channel) ([]Channel, error) {
+	ctx = auth.WithScope(ctx, "channels:write")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return []Channel{}, errors.Wrap(ErrUnauthorizedAccess, err)
@@ -398,7 +486,7 @@ func (ts *thingsService) CreateChannels(ctx context.Context, token string, chann
 
 	chs := []Channel{}
 	for _, channel := range channels {
-		ch, err := ts.createChannel(ctx, &channel, res)
+		ch, err := ts.createChannel(ctx, token, &channel, res)
 		if err != nil {
 			return []Channel{}, err
 		}
@@ -407,7 +495,7 @@ func (ts *thingsService) CreateChannels(ctx context.Context, token string, chann
 	return chs, nil
 }
 
-func (ts *thingsService) createChannel(ctx context.Context, channel *Channel, identity *mainflux.UserIdentity) (Channel, error) {
+func (ts *thingsService) createChannel(ctx context.Context, token string, channel *Channel, identity *mainflux.UserIdentity) (Channel, error) {
 	if channel.ID == "" {
 		chID, err := ts.idProvider.ID()
 		if err != nil {
@@ -417,29 +505,34 @@ func (ts *thingsService) createChannel(ctx context.Context, channel *Channel, id
 	}
 	channel.Owner = identity.GetEmail()
 
+	if err := ts.reserveQuota(ctx, token, channelsQuota, channel.ID); err != nil {
+		return Channel{}, err
+	}
+
 	chs, err := ts.channels.Save(ctx, *channel)
 	if err != nil {
+		ts.releaseQuota(ctx, channelsQuota, channel.ID)
 		return Channel{}, err
 	}
 	if len(chs) == 0 {
 		return Channel{}, ErrCreateEntity
 	}
 
-	ss := fmt.Sprintf("%s:%s#%s", "members", authoritiesObject, memberRelationKey)
-	if err := ts.claimOwnership(ctx, chs[0].ID, []string{readRelationKey, writeRelationKey, deleteRelationKey}, []string{identity.GetId(), ss}); err != nil {
+	if err := ts.applyPolicyTemplate(ctx, channelEntityType, chs[0].ID, identity.GetId()); err != nil {
 		return Channel{}, err
 	}
 	return chs[0], nil
 }
 
 func (ts *thingsService) UpdateChannel(ctx context.Context, token string, channel Channel) error {
+	ctx = auth.WithScope(ctx, "channels:write")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), channel.ID, writeRelationKey); err != nil {
-		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "channels:write", res.GetId(), channel.ID, writeRelationKey); err != nil {
+		if err := ts.authorize(ctx, token, "channels:write", res.GetId(), authoritiesObject, memberRelationKey); err != nil {
 			return err
 		}
 	}
@@ -449,13 +542,14 @@ func (ts *thingsService) UpdateChannel(ctx context.Context, token string, channe
 }
 
 func (ts *thingsService) ViewChannel(ctx context.Context, token, id string) (Channel, error) {
+	ctx = auth.WithScope(ctx, "channels:read")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return Channel{}, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), id, readRelationKey); err != nil {
-		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "channels:read", res.GetId(), id, readRelationKey); err != nil {
+		if err := ts.authorize(ctx, token, "channels:read", res.GetId(), authoritiesObject, memberRelationKey); err != nil {
 			return Channel{}, err
 		}
 	}
@@ -464,13 +558,14 @@ func (ts *thingsService) ViewChannel(ctx context.Context, token, id string) (Cha
 }
 
 func (ts *thingsService) ListChannels(ctx context.Context, token string, pm PageMetadata) (ChannelsPage, error) {
+	ctx = auth.WithScope(ctx, "channels:read")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return ChannelsPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
 	// If the user is admin, fetch all channels from the database.
-	if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err == nil {
+	if err := ts.authorize(ctx, token, "channels:read", res.GetId(), authoritiesObject, memberRelationKey); err == nil {
 		pm.FetchSharedThings = true
 		page, err := ts.channels.RetrieveAll(ctx, res.GetEmail(), pm)
 		if err != nil {
@@ -484,6 +579,7 @@ func (ts *thingsService) ListChannels(ctx context.Context, token string, pm Page
 }
 
 func (ts *thingsService) ListChannelsByThing(ctx context.Context, token, thID string, pm PageMetadata) (ChannelsPage, error) {
+	ctx = auth.WithScope(ctx, "channels:read")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return ChannelsPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
@@ -493,13 +589,14 @@ func (ts *thingsService) ListChannelsByThing(ctx context.Context, token, thID st
 }
 
 func (ts *thingsService) RemoveChannel(ctx context.Context, token, id string) error {
+	ctx = auth.WithScope(ctx, "channels:delete")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	if err := ts.authorize(ctx, res.GetId(), id, deleteRelationKey); err != nil {
-		if err := ts.authorize(ctx, res.GetId(), authoritiesObject, memberRelationKey); err != nil {
+	if err := ts.authorize(ctx, token, "channels:delete", res.GetId(), id, deleteRelationKey); err != nil {
+		if err := ts.authorize(ctx, token, "channels:delete", res.GetId(), authoritiesObject, memberRelationKey); err != nil {
 			return err
 		}
 	}
@@ -508,19 +605,30 @@ func (ts *thingsService) RemoveChannel(ctx context.Context, token, id string) er
 		return err
 	}
 
-	return ts.channels.Remove(ctx, res.GetEmail(), id)
+	if err := ts.channels.Remove(ctx, res.GetEmail(), id); err != nil {
+		return err
+	}
+	ts.releaseQuota(ctx, channelsQuota, id)
+	return nil
 }
 
-func (ts *thingsService) Connect(ctx context.Context, token string, chIDs, thIDs []string) error {
+func (ts *thingsService) Connect(ctx context.Context, token string, chIDs, thIDs []string, meta ConnectionMetadata) error {
+	ctx = auth.WithScope(ctx, "channels:write")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	return ts.channels.Connect(ctx, res.GetEmail(), chIDs, thIDs)
+	if err := meta.Validate(); err != nil {
+		return err
+	}
+	meta.CreatedBy = res.GetEmail()
+
+	return ts.channels.Connect(ctx, res.GetEmail(), chIDs, thIDs, meta)
 }
 
 func (ts *thingsService) Disconnect(ctx context.Context, token string, chIDs, thIDs []string) error {
+	ctx = auth.WithScope(ctx, "channels:write")
 	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
 	if err != nil {
 		return errors.Wrap(ErrUnauthorizedAccess, err)
@@ -537,10 +645,13 @@ func (ts *thingsService) Disconnect(ctx context.Context, token string, chIDs, th
 	return ts.channels.Disconnect(ctx, res.GetEmail(), chIDs, thIDs)
 }
 
-func (ts *thingsService) CanAccessByKey(ctx context.Context, chanID, thingKey string) (string, error) {
+func (ts *thingsService) CanAccessByKey(ctx context.Context, chanID, thingKey, op string) (string, error) {
 	thingID, err := ts.hasThing(ctx, chanID, thingKey)
 	if err == nil {
-		return thingID, nil
+		if err := ts.allows(ctx, chanID, thingID, op); err != nil {
+			return "", err
+		}
+		return thingID, ts.reserveMessage(ctx, thingID, op)
 	}
 
 	thingID, err = ts.channels.HasThing(ctx, chanID, thingKey)
@@ -548,27 +659,39 @@ func (ts *thingsService) CanAccessByKey(ctx context.Context, chanID, thingKey st
 		return "", err
 	}
 
+	conn, err := ts.channels.RetrieveConnection(ctx, chanID, thingID)
+	if err != nil {
+		return "", err
+	}
+
 	if err := ts.thingCache.Save(ctx, thingKey, thingID); err != nil {
 		return "", err
 	}
-	if err := ts.channelCache.Connect(ctx, chanID, thingID); err != nil {
+	if err := ts.channelCache.Connect(ctx, chanID, thingID, conn.Role); err != nil {
 		return "", err
 	}
-	return thingID, nil
+	if !conn.Allows(op) {
+		return "", ErrOperationNotAllowed
+	}
+	return thingID, ts.reserveMessage(ctx, thingID, op)
 }
 
-func (ts *thingsService) CanAccessByID(ctx context.Context, chanID, thingID string) error {
+func (ts *thingsService) CanAccessByID(ctx context.Context, chanID, thingID, op string) error {
 	if connected := ts.channelCache.HasThing(ctx, chanID, thingID); connected {
-		return nil
+		return ts.allows(ctx, chanID, thingID, op)
 	}
 
-	if err := ts.channels.HasThingByID(ctx, chanID, thingID); err != nil {
+	conn, err := ts.channels.RetrieveConnection(ctx, chanID, thingID)
+	if err != nil {
 		return err
 	}
 
-	if err := ts.channelCache.Connect(ctx, chanID, thingID); err != nil {
+	if err := ts.channelCache.Connect(ctx, chanID, thingID, conn.Role); err != nil {
 		return err
 	}
+	if !conn.Allows(op) {
+		return ErrOperationNotAllowed
+	}
 	return nil
 }
 
@@ -608,12 +731,22 @@ func (ts *thingsService) hasThing(ctx context.Context, chanID, thingKey string)
 	return thingID, nil
 }
 
+// allows checks the cached connection role against the requested operation.
+func (ts *thingsService) allows(ctx context.Context, chanID, thingID, op string) error {
+	conn := ConnectionMetadata{Role: ts.channelCache.Role(ctx, chanID, thingID)}
+	if !conn.Allows(op) {
+		return ErrOperationNotAllowed
+	}
+	return nil
+}
+
 func (ts *thingsService) ListMembers(ctx context.Context, token, groupID string, pm PageMetadata) (Page, error) {
+	ctx = auth.WithScope(ctx, "things:read")
 	if _, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token}); err != nil {
 		return Page{}, errors.Wrap(ErrUnauthorizedAccess, err)
 	}
 
-	res, err := ts.members(ctx, token, groupID, "things", pm.Offset, pm.Limit)
+	res, err := ts.members(ctx, token, groupID, "things")
 	if err != nil {
 		return Page{}, nil
 	}
@@ -621,12 +754,95 @@ func (ts *thingsService) ListMembers(ctx context.Context, token, groupID string,
 	return ts.things.RetrieveByIDs(ctx, res, pm)
 }
 
-func (ts *thingsService) members(ctx context.Context, token, groupID, groupType string, limit, offset uint64) ([]string, error) {
+func (ts *thingsService) CreateWebhook(ctx context.Context, token string, w Webhook) (Webhook, error) {
+	ctx = auth.WithScope(ctx, "channels:write")
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return Webhook{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	if err := ts.authorize(ctx, token, "channels:write", res.GetId(), w.ChannelID, writeRelationKey); err != nil {
+		return Webhook{}, err
+	}
+
+	count, err := ts.webhooks.Count(ctx, res.GetEmail())
+	if err != nil {
+		return Webhook{}, err
+	}
+	if count >= ts.webhookLimit {
+		return Webhook{}, errors.Wrap(ErrQuotaExceeded, errWebhooksLimit)
+	}
+
+	id, err := ts.idProvider.ID()
+	if err != nil {
+		return Webhook{}, errors.Wrap(ErrCreateUUID, err)
+	}
+	w.ID = id
+	w.Owner = res.GetEmail()
+	w.CreatedAt = time.Now().UTC()
+	if w.BatchSize == 0 {
+		w.BatchSize = 1
+	}
+
+	if err := ts.webhooks.Save(ctx, w); err != nil {
+		return Webhook{}, err
+	}
+
+	return w, nil
+}
+
+func (ts *thingsService) ListWebhooks(ctx context.Context, token, chID string) ([]Webhook, error) {
+	ctx = auth.WithScope(ctx, "channels:read")
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return nil, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return ts.webhooks.RetrieveByChannel(ctx, res.GetEmail(), chID)
+}
+
+func (ts *thingsService) RemoveWebhook(ctx context.Context, token, chID, id string) error {
+	ctx = auth.WithScope(ctx, "channels:write")
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	w, err := ts.webhooks.RetrieveByID(ctx, res.GetEmail(), id)
+	if err != nil {
+		return err
+	}
+	if w.ChannelID != chID {
+		return ErrNotFound
+	}
+
+	return ts.webhooks.Remove(ctx, res.GetEmail(), id)
+}
+
+func (ts *thingsService) ListDeliveries(ctx context.Context, token, chID, id string, pm PageMetadata) (DeliveriesPage, error) {
+	ctx = auth.WithScope(ctx, "channels:read")
+	res, err := ts.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return DeliveriesPage{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	w, err := ts.webhooks.RetrieveByID(ctx, res.GetEmail(), id)
+	if err != nil {
+		return DeliveriesPage{}, err
+	}
+	if w.ChannelID != chID {
+		return DeliveriesPage{}, ErrNotFound
+	}
+
+	return ts.webhooks.RetrieveDeliveries(ctx, id, pm)
+}
+
+// members lists all the members of the group, which are paged along with
+// the things.
+func (ts *thingsService) members(ctx context.Context, token, groupID, groupType string) ([]string, error) {
 	req := mainflux.MembersReq{
 		Token:   token,
 		GroupID: groupID,
-		Offset:  offset,
-		Limit:   limit,
 		Type:    groupType,
 	}
 
@@ -637,11 +853,15 @@ func (ts *thingsService) members(ctx context.Context, token, groupID, groupType
 	return res.Members, nil
 }
 
-func (ts *thingsService) authorize(ctx context.Context, subject, object string, relation string) error {
+// authorize checks the subject's relation on the object. The scoped API key
+// the subject is identified by is required to allow the operation scope.
+func (ts *thingsService) authorize(ctx context.Context, token, scope, subject, object, relation string) error {
 	req := &mainflux.AuthorizeReq{
-		Sub: subject,
-		Obj: object,
-		Act: relation,
+		Sub:   subject,
+		Obj:   object,
+		Act:   relation,
+		Token: token,
+		Scope: scope,
 	}
 	res, err := ts.auth.Authorize(ctx, req)
 	if err != nil {
@@ -652,3 +872,33 @@ func (ts *thingsService) authorize(ctx context.Context, subject, object string,
 	}
 	return nil
 }
+
+// reserveQuota charges the resources to the organizations quotas of the
+// user identified by the token.
+func (ts *thingsService) reserveQuota(ctx context.Context, token, resource string, ids ...string) error {
+	_, err := ts.auth.ReserveQuota(ctx, &mainflux.QuotaReq{Token: token, Resource: resource, Ids: ids})
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.ResourceExhausted {
+			// Keep the exceeded limit description sent by the auth service.
+			msg := strings.TrimPrefix(st.Message(), ErrQuotaExceeded.Error()+" : ")
+			return errors.Wrap(ErrQuotaExceeded, errors.New(msg))
+		}
+		return err
+	}
+	return nil
+}
+
+// releaseQuota releases the removed resources from the organizations quotas.
+// Failing to release is not fatal for the removal, since the released
+// resources only leave the quota usage stale.
+func (ts *thingsService) releaseQuota(ctx context.Context, resource string, ids ...string) {
+	ts.auth.ReleaseQuota(ctx, &mainflux.QuotaReq{Resource: resource, Ids: ids})
+}
+
+// reserveMessage checks the message rate of the publishing thing organizations.
+func (ts *thingsService) reserveMessage(ctx context.Context, thingID, op string) error {
+	if op != PublishOp {
+		return nil
+	}
+	return ts.reserveQuota(ctx, "", messagesQuota, thingID)
+}
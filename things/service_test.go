@@ -6,6 +6,7 @@ package things_test
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"testing"
 	"time"
 
@@ -18,15 +19,16 @@ import (
 )
 
 const (
-	wrongID    = ""
-	wrongValue = "wrong-value"
-	adminEmail = "admin@example.com"
-	email      = "user@example.com"
-	email2     = "user2@example.com"
-	token      = "token"
-	token2     = "token2"
-	n          = uint64(10)
-	prefix     = "fe6b4e92-cc98-425e-b0aa-"
+	wrongID      = ""
+	wrongValue   = "wrong-value"
+	adminEmail   = "admin@example.com"
+	email        = "user@example.com"
+	email2       = "user2@example.com"
+	token        = "token"
+	webhookLimit = 10
+	token2       = "token2"
+	n            = uint64(10)
+	prefix       = "fe6b4e92-cc98-425e-b0aa-"
 )
 
 var (
@@ -38,6 +40,10 @@ var (
 )
 
 func newService(tokens map[string]string) things.Service {
+	return newValidatedService(tokens, nil)
+}
+
+func newValidatedService(tokens map[string]string, validator things.Validator) things.Service {
 	userPolicy := mocks.MockSubjectSet{Object: "users", Relation: "member"}
 	adminPolicy := mocks.MockSubjectSet{Object: "authorities", Relation: "member"}
 	auth := mocks.NewAuthService(tokens, map[string][]mocks.MockSubjectSet{
@@ -45,11 +51,12 @@ func newService(tokens map[string]string) things.Service {
 	conns := make(chan mocks.Connection)
 	thingsRepo := mocks.NewThingRepository(conns)
 	channelsRepo := mocks.NewChannelRepository(thingsRepo, conns)
+	webhooksRepo := mocks.NewWebhookRepository()
 	chanCache := mocks.NewChannelCache()
 	thingCache := mocks.NewThingCache()
 	idProvider := uuid.NewMock()
 
-	return things.New(auth, thingsRepo, channelsRepo, chanCache, thingCache, idProvider)
+	return things.New(auth, thingsRepo, channelsRepo, webhooksRepo, webhookLimit, chanCache, thingCache, idProvider, validator)
 }
 
 func TestInit(t *testing.T) {
@@ -101,6 +108,42 @@ func TestCreateThings(t *testing.T) {
 	}
 }
 
+func TestCreateThingsValidator(t *testing.T) {
+	validator := things.NewMetadataValidator("serial", regexp.MustCompile("^SN-[0-9]{4}$"))
+	svc := newValidatedService(map[string]string{token: email}, validator)
+
+	cases := []struct {
+		desc   string
+		things []things.Thing
+		err    error
+	}{
+		{
+			desc:   "create things of valid serial numbers",
+			things: []things.Thing{{Name: "a", Metadata: things.Metadata{"serial": "SN-0001"}}},
+			err:    nil,
+		},
+		{
+			desc:   "create things including invalid serial number",
+			things: []things.Thing{{Name: "b", Metadata: things.Metadata{"serial": "SN-0002"}}, {Name: "c", Metadata: things.Metadata{"serial": "0003"}}},
+			err:    things.ErrValidation,
+		},
+		{
+			desc:   "create thing without serial number",
+			things: []things.Thing{{Name: "d"}},
+			err:    things.ErrValidation,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.CreateThings(context.Background(), token, tc.things...)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	page, err := svc.ListThings(context.Background(), token, things.PageMetadata{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	assert.Equal(t, uint64(1), page.Total, fmt.Sprintf("expected only the valid things to be created, got %d things\n", page.Total))
+}
+
 func TestUpdateThing(t *testing.T) {
 	svc := newService(map[string]string{token: email})
 	ths, err := svc.CreateThings(context.Background(), token, thingList[0])
@@ -418,7 +461,7 @@ func TestListThingsByChannel(t *testing.T) {
 	}
 	chIDs := []string{chs[0].ID}
 
-	err = svc.Connect(context.Background(), token, chIDs, thIDs[0:n-thsDisconNum])
+	err = svc.Connect(context.Background(), token, chIDs, thIDs[0:n-thsDisconNum], things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	// Wait for things and channels to connect
@@ -905,7 +948,7 @@ func TestListChannelsByThing(t *testing.T) {
 	}
 	thIDs := []string{ths[0].ID}
 
-	err = svc.Connect(context.Background(), token, chIDs[0:n-chsDisconNum], thIDs)
+	err = svc.Connect(context.Background(), token, chIDs[0:n-chsDisconNum], thIDs, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	// Wait for things and channels to connect.
@@ -1121,6 +1164,7 @@ func TestConnect(t *testing.T) {
 		token   string
 		chanID  string
 		thingID string
+		meta    things.ConnectionMetadata
 		err     error
 	}{
 		{
@@ -1130,6 +1174,14 @@ func TestConnect(t *testing.T) {
 			thingID: th.ID,
 			err:     nil,
 		},
+		{
+			desc:    "connect thing with invalid role",
+			token:   token,
+			chanID:  ch.ID,
+			thingID: th.ID,
+			meta:    things.ConnectionMetadata{Role: wrongValue},
+			err:     things.ErrMalformedEntity,
+		},
 		{
 			desc:    "connect thing with wrong credentials",
 			token:   wrongValue,
@@ -1154,7 +1206,7 @@ func TestConnect(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		err := svc.Connect(context.Background(), tc.token, []string{tc.chanID}, []string{tc.thingID})
+		err := svc.Connect(context.Background(), tc.token, []string{tc.chanID}, []string{tc.thingID}, tc.meta)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
 	}
 }
@@ -1168,7 +1220,7 @@ func TestDisconnect(t *testing.T) {
 	chs, err := svc.CreateChannels(context.Background(), token, channel)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	ch := chs[0]
-	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID}, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := []struct {
@@ -1227,14 +1279,17 @@ func TestCanAccessByKey(t *testing.T) {
 
 	ths, err := svc.CreateThings(context.Background(), token, thingList[0])
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
-	chs, err := svc.CreateChannels(context.Background(), token, channel, channel)
+	chs, err := svc.CreateChannels(context.Background(), token, channel, channel, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{chs[0].ID}, []string{ths[0].ID}, things.ConnectionMetadata{})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
-	err = svc.Connect(context.Background(), token, []string{chs[0].ID}, []string{ths[0].ID})
+	err = svc.Connect(context.Background(), token, []string{chs[2].ID}, []string{ths[0].ID}, things.ConnectionMetadata{Role: things.PublisherRole})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := map[string]struct {
 		token   string
 		channel string
+		op      string
 		err     error
 	}{
 		"allowed access": {
@@ -1242,6 +1297,24 @@ func TestCanAccessByKey(t *testing.T) {
 			channel: chs[0].ID,
 			err:     nil,
 		},
+		"allowed subscribe without connection role": {
+			token:   ths[0].Key,
+			channel: chs[0].ID,
+			op:      things.SubscribeOp,
+			err:     nil,
+		},
+		"allowed publish by publisher": {
+			token:   ths[0].Key,
+			channel: chs[2].ID,
+			op:      things.PublishOp,
+			err:     nil,
+		},
+		"denied subscribe by publisher": {
+			token:   ths[0].Key,
+			channel: chs[2].ID,
+			op:      things.SubscribeOp,
+			err:     things.ErrOperationNotAllowed,
+		},
 		"non-existing thing": {
 			token:   wrongValue,
 			channel: chs[0].ID,
@@ -1260,7 +1333,7 @@ func TestCanAccessByKey(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := svc.CanAccessByKey(context.Background(), tc.channel, tc.token)
+		_, err := svc.CanAccessByKey(context.Background(), tc.channel, tc.token, tc.op)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected '%s' got '%s'\n", desc, tc.err, err))
 	}
 }
@@ -1271,15 +1344,18 @@ func TestCanAccessByID(t *testing.T) {
 	ths, err := svc.CreateThings(context.Background(), token, thingList[0], thingList[1])
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	th := ths[0]
-	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	chs, err := svc.CreateChannels(context.Background(), token, channel, channel)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 	ch := chs[0]
-	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID})
+	err = svc.Connect(context.Background(), token, []string{ch.ID}, []string{th.ID}, things.ConnectionMetadata{})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	err = svc.Connect(context.Background(), token, []string{chs[1].ID}, []string{th.ID}, things.ConnectionMetadata{Role: things.SubscriberRole})
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
 
 	cases := map[string]struct {
 		thingID string
 		channel string
+		op      string
 		err     error
 	}{
 		"allowed access": {
@@ -1287,6 +1363,18 @@ func TestCanAccessByID(t *testing.T) {
 			channel: ch.ID,
 			err:     nil,
 		},
+		"allowed subscribe by subscriber": {
+			thingID: th.ID,
+			channel: chs[1].ID,
+			op:      things.SubscribeOp,
+			err:     nil,
+		},
+		"denied publish by subscriber": {
+			thingID: th.ID,
+			channel: chs[1].ID,
+			op:      things.PublishOp,
+			err:     things.ErrOperationNotAllowed,
+		},
 		"access to non-existing thing": {
 			thingID: wrongValue,
 			channel: ch.ID,
@@ -1305,7 +1393,7 @@ func TestCanAccessByID(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		err := svc.CanAccessByID(context.Background(), tc.channel, tc.thingID)
+		err := svc.CanAccessByID(context.Background(), tc.channel, tc.thingID, tc.op)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
@@ -1375,6 +1463,223 @@ func TestIdentify(t *testing.T) {
 	}
 }
 
+func TestCreateWebhook(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: email2})
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+
+	cases := []struct {
+		desc    string
+		webhook things.Webhook
+		token   string
+		err     error
+	}{
+		{
+			desc:    "create webhook",
+			webhook: things.Webhook{ChannelID: ch.ID, URL: "http://localhost"},
+			token:   token,
+			err:     nil,
+		},
+		{
+			desc:    "create webhook with wrong credentials",
+			webhook: things.Webhook{ChannelID: ch.ID, URL: "http://localhost"},
+			token:   wrongValue,
+			err:     things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "create webhook on channel of other user",
+			webhook: things.Webhook{ChannelID: ch.ID, URL: "http://localhost"},
+			token:   token2,
+			err:     things.ErrAuthorization,
+		},
+		{
+			desc:    "create webhook on non-existing channel",
+			webhook: things.Webhook{ChannelID: wrongValue, URL: "http://localhost"},
+			token:   token,
+			err:     things.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		w, err := svc.CreateWebhook(context.Background(), tc.token, tc.webhook)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			assert.NotEmpty(t, w.ID, fmt.Sprintf("%s: expected non-empty webhook id\n", tc.desc))
+			assert.Equal(t, email, w.Owner, fmt.Sprintf("%s: expected owner %s got %s\n", tc.desc, email, w.Owner))
+			assert.Equal(t, uint64(1), w.BatchSize, fmt.Sprintf("%s: expected default batch size got %d\n", tc.desc, w.BatchSize))
+		}
+	}
+
+	for i := 1; i < webhookLimit; i++ {
+		_, err := svc.CreateWebhook(context.Background(), token, things.Webhook{ChannelID: ch.ID, URL: "http://localhost"})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	}
+	_, err = svc.CreateWebhook(context.Background(), token, things.Webhook{ChannelID: ch.ID, URL: "http://localhost"})
+	assert.True(t, errors.Contains(err, things.ErrQuotaExceeded), fmt.Sprintf("create webhook over the limit: expected %s got %s\n", things.ErrQuotaExceeded, err))
+}
+
+func TestListWebhooks(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: email2})
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+	w, err := svc.CreateWebhook(context.Background(), token, things.Webhook{ChannelID: ch.ID, URL: "http://localhost"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		chanID string
+		size   int
+		err    error
+	}{
+		{
+			desc:   "list webhooks",
+			token:  token,
+			chanID: w.ChannelID,
+			size:   1,
+			err:    nil,
+		},
+		{
+			desc:   "list webhooks with wrong credentials",
+			token:  wrongValue,
+			chanID: w.ChannelID,
+			size:   0,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "list webhooks of other user",
+			token:  token2,
+			chanID: w.ChannelID,
+			size:   0,
+			err:    nil,
+		},
+		{
+			desc:   "list webhooks of non-existing channel",
+			token:  token,
+			chanID: wrongValue,
+			size:   0,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		whs, err := svc.ListWebhooks(context.Background(), tc.token, tc.chanID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.size, len(whs), fmt.Sprintf("%s: expected %d got %d\n", tc.desc, tc.size, len(whs)))
+	}
+}
+
+func TestRemoveWebhook(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: email2})
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+	w, err := svc.CreateWebhook(context.Background(), token, things.Webhook{ChannelID: ch.ID, URL: "http://localhost"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		chanID string
+		id     string
+		err    error
+	}{
+		{
+			desc:   "remove webhook with wrong credentials",
+			token:  wrongValue,
+			chanID: ch.ID,
+			id:     w.ID,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "remove webhook of other user",
+			token:  token2,
+			chanID: ch.ID,
+			id:     w.ID,
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "remove webhook of other channel",
+			token:  token,
+			chanID: wrongValue,
+			id:     w.ID,
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "remove webhook",
+			token:  token,
+			chanID: ch.ID,
+			id:     w.ID,
+			err:    nil,
+		},
+		{
+			desc:   "remove removed webhook",
+			token:  token,
+			chanID: ch.ID,
+			id:     w.ID,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RemoveWebhook(context.Background(), tc.token, tc.chanID, tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	svc := newService(map[string]string{token: email, token2: email2})
+	chs, err := svc.CreateChannels(context.Background(), token, channel)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+	ch := chs[0]
+	w, err := svc.CreateWebhook(context.Background(), token, things.Webhook{ChannelID: ch.ID, URL: "http://localhost"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s\n", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		chanID string
+		id     string
+		err    error
+	}{
+		{
+			desc:   "list deliveries",
+			token:  token,
+			chanID: ch.ID,
+			id:     w.ID,
+			err:    nil,
+		},
+		{
+			desc:   "list deliveries with wrong credentials",
+			token:  wrongValue,
+			chanID: ch.ID,
+			id:     w.ID,
+			err:    things.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "list deliveries of webhook of other user",
+			token:  token2,
+			chanID: ch.ID,
+			id:     w.ID,
+			err:    things.ErrNotFound,
+		},
+		{
+			desc:   "list deliveries of webhook of other channel",
+			token:  token,
+			chanID: wrongValue,
+			id:     w.ID,
+			err:    things.ErrNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.ListDeliveries(context.Background(), tc.token, tc.chanID, tc.id, things.PageMetadata{Limit: n})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
 func testSortThings(t *testing.T, pm things.PageMetadata, ths []things.Thing) {
 	switch pm.Order {
 	case "name":
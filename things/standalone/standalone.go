@@ -78,3 +78,26 @@ func (repo singleUserRepo) Members(ctx context.Context, req *mainflux.MembersReq
 func (repo singleUserRepo) Assign(ctx context.Context, req *mainflux.Assignment, _ ...grpc.CallOption) (r *empty.Empty, err error) {
 	return &empty.Empty{}, errUnsupported
 }
+
+func (repo singleUserRepo) ReserveQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	// Single user has no organization quotas.
+	return &empty.Empty{}, nil
+}
+
+func (repo singleUserRepo) ReleaseQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	return &empty.Empty{}, nil
+}
+
+func (repo singleUserRepo) RevokeKeys(ctx context.Context, req *mainflux.RevokeKeysReq, _ ...grpc.CallOption) (*mainflux.RevokeKeysRes, error) {
+	return &mainflux.RevokeKeysRes{}, nil
+}
+
+func (repo singleUserRepo) ApplyPolicyTemplate(ctx context.Context, req *mainflux.PolicyTemplateReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	// The single user owns all the entities, so there are no policies to add.
+	return &empty.Empty{}, nil
+}
+
+func (repo singleUserRepo) RemoveLinks(ctx context.Context, req *mainflux.RemoveLinksReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	// The single user has no linked identities.
+	return &empty.Empty{}, nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 )
 
 var (
@@ -28,6 +29,10 @@ var (
 
 	// ErrEntityConnected indicates error while checking connection in database
 	ErrEntityConnected = errors.New("check thing-channel connection in database error")
+
+	// ErrOperationNotAllowed indicates that the connection role does not permit
+	// the requested operation.
+	ErrOperationNotAllowed = errors.New("operation not allowed by connection role")
 )
 
 // Metadata to be used for Mainflux thing or channel for customized
@@ -42,6 +47,10 @@ type Thing struct {
 	Name     string
 	Key      string
 	Metadata Metadata
+	Labels   labels.Labels
+
+	// Connection is set when the thing is listed by a connected channel.
+	Connection *ConnectionMetadata
 }
 
 // Page contains page related metadata as well as list of things that
@@ -21,6 +21,8 @@ const (
 	disconnectOp              = "disconnect"
 	hasThingOp                = "has_thing"
 	hasThingByIDOp            = "has_thing_by_id"
+	retrieveConnectionOp      = "retrieve_connection"
+	connectionRoleOp          = "connection_role"
 )
 
 var (
@@ -90,12 +92,12 @@ func (crm channelRepositoryMiddleware) Remove(ctx context.Context, owner, id str
 	return crm.repo.Remove(ctx, owner, id)
 }
 
-func (crm channelRepositoryMiddleware) Connect(ctx context.Context, owner string, chIDs, thIDs []string) error {
+func (crm channelRepositoryMiddleware) Connect(ctx context.Context, owner string, chIDs, thIDs []string, meta things.ConnectionMetadata) error {
 	span := createSpan(ctx, crm.tracer, connectOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return crm.repo.Connect(ctx, owner, chIDs, thIDs)
+	return crm.repo.Connect(ctx, owner, chIDs, thIDs, meta)
 }
 
 func (crm channelRepositoryMiddleware) Disconnect(ctx context.Context, owner string, chIDs, thIDs []string) error {
@@ -122,6 +124,14 @@ func (crm channelRepositoryMiddleware) HasThingByID(ctx context.Context, chanID,
 	return crm.repo.HasThingByID(ctx, chanID, thingID)
 }
 
+func (crm channelRepositoryMiddleware) RetrieveConnection(ctx context.Context, chanID, thingID string) (things.ConnectionMetadata, error) {
+	span := createSpan(ctx, crm.tracer, retrieveConnectionOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return crm.repo.RetrieveConnection(ctx, chanID, thingID)
+}
+
 type channelCacheMiddleware struct {
 	tracer opentracing.Tracer
 	cache  things.ChannelCache
@@ -136,12 +146,12 @@ func ChannelCacheMiddleware(tracer opentracing.Tracer, cache things.ChannelCache
 	}
 }
 
-func (ccm channelCacheMiddleware) Connect(ctx context.Context, chanID, thingID string) error {
+func (ccm channelCacheMiddleware) Connect(ctx context.Context, chanID, thingID, role string) error {
 	span := createSpan(ctx, ccm.tracer, connectOp)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return ccm.cache.Connect(ctx, chanID, thingID)
+	return ccm.cache.Connect(ctx, chanID, thingID, role)
 }
 
 func (ccm channelCacheMiddleware) HasThing(ctx context.Context, chanID, thingID string) bool {
@@ -152,6 +162,14 @@ func (ccm channelCacheMiddleware) HasThing(ctx context.Context, chanID, thingID
 	return ccm.cache.HasThing(ctx, chanID, thingID)
 }
 
+func (ccm channelCacheMiddleware) Role(ctx context.Context, chanID, thingID string) string {
+	span := createSpan(ctx, ccm.tracer, connectionRoleOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return ccm.cache.Role(ctx, chanID, thingID)
+}
+
 func (ccm channelCacheMiddleware) Disconnect(ctx context.Context, chanID, thingID string) error {
 	span := createSpan(ctx, ccm.tracer, disconnectOp)
 	defer span.Finish()
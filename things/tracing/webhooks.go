@@ -0,0 +1,102 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/things"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveWebhookOp             = "save_webhook"
+	retrieveWebhookByIDOp     = "retrieve_webhook_by_id"
+	retrieveWebhooksOp        = "retrieve_webhooks_by_channel"
+	retrieveAllWebhooksOp     = "retrieve_all_webhooks_by_channel"
+	countWebhooksOp           = "count_webhooks"
+	removeWebhookOp           = "remove_webhook"
+	saveDeliveryOp            = "save_delivery"
+	retrieveWebhookDeliveryOp = "retrieve_deliveries"
+)
+
+var _ things.WebhookRepository = (*webhookRepositoryMiddleware)(nil)
+
+type webhookRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   things.WebhookRepository
+}
+
+// WebhookRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func WebhookRepositoryMiddleware(tracer opentracing.Tracer, repo things.WebhookRepository) things.WebhookRepository {
+	return webhookRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (wrm webhookRepositoryMiddleware) Save(ctx context.Context, w things.Webhook) error {
+	span := createSpan(ctx, wrm.tracer, saveWebhookOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.Save(ctx, w)
+}
+
+func (wrm webhookRepositoryMiddleware) RetrieveByID(ctx context.Context, owner, id string) (things.Webhook, error) {
+	span := createSpan(ctx, wrm.tracer, retrieveWebhookByIDOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.RetrieveByID(ctx, owner, id)
+}
+
+func (wrm webhookRepositoryMiddleware) RetrieveByChannel(ctx context.Context, owner, chID string) ([]things.Webhook, error) {
+	span := createSpan(ctx, wrm.tracer, retrieveWebhooksOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.RetrieveByChannel(ctx, owner, chID)
+}
+
+func (wrm webhookRepositoryMiddleware) RetrieveAllByChannel(ctx context.Context, chID string) ([]things.Webhook, error) {
+	span := createSpan(ctx, wrm.tracer, retrieveAllWebhooksOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.RetrieveAllByChannel(ctx, chID)
+}
+
+func (wrm webhookRepositoryMiddleware) Count(ctx context.Context, owner string) (uint64, error) {
+	span := createSpan(ctx, wrm.tracer, countWebhooksOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.Count(ctx, owner)
+}
+
+func (wrm webhookRepositoryMiddleware) Remove(ctx context.Context, owner, id string) error {
+	span := createSpan(ctx, wrm.tracer, removeWebhookOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.Remove(ctx, owner, id)
+}
+
+func (wrm webhookRepositoryMiddleware) SaveDelivery(ctx context.Context, d things.Delivery) error {
+	span := createSpan(ctx, wrm.tracer, saveDeliveryOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.SaveDelivery(ctx, d)
+}
+
+func (wrm webhookRepositoryMiddleware) RetrieveDeliveries(ctx context.Context, webhookID string, pm things.PageMetadata) (things.DeliveriesPage, error) {
+	span := createSpan(ctx, wrm.tracer, retrieveWebhookDeliveryOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return wrm.repo.RetrieveDeliveries(ctx, webhookID, pm)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrValidation indicates the thing rejected by the custom validation rules
+// of the deployment.
+var ErrValidation = errors.New("thing rejected by validation rules")
+
+// Validator enforces the custom rules of the deployment on the thing
+// creation, such as the format of the device serial numbers.
+type Validator interface {
+	// ValidateThing returns the error wrapping ErrValidation if the thing
+	// violates the rules.
+	ValidateThing(ctx context.Context, thing Thing) error
+}
+
+var _ Validator = (Validators)(nil)
+
+// Validators combine the validators, running them in turn until the first
+// one rejects the thing.
+type Validators []Validator
+
+// ValidateThing validates the thing using each of the validators.
+func (vs Validators) ValidateThing(ctx context.Context, thing Thing) error {
+	for _, v := range vs {
+		if err := v.ValidateThing(ctx, thing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type nopValidator struct{}
+
+// NewNopValidator returns the validator accepting all the things.
+func NewNopValidator() Validator {
+	return nopValidator{}
+}
+
+func (nopValidator) ValidateThing(context.Context, Thing) error {
+	return nil
+}
+
+type metadataValidator struct {
+	key    string
+	format *regexp.Regexp
+}
+
+// NewMetadataValidator returns the validator accepting only the things with
+// the metadata value under the key, e.g. the device serial number, matching
+// the format. The things without the value are rejected.
+func NewMetadataValidator(key string, format *regexp.Regexp) Validator {
+	return metadataValidator{key: key, format: format}
+}
+
+func (v metadataValidator) ValidateThing(_ context.Context, thing Thing) error {
+	val, ok := thing.Metadata[v.key].(string)
+	if !ok {
+		return errors.Wrap(ErrValidation, fmt.Errorf("missing metadata %s", v.key))
+	}
+	if !v.format.MatchString(val) {
+		return errors.Wrap(ErrValidation, fmt.Errorf("metadata %s does not match format %s", v.key, v.format))
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/things"
+)
+
+const (
+	contentType = "application/json"
+
+	// SignatureHeader is the header carrying the HMAC-SHA256 signature of
+	// the request body, computed using the webhook secret.
+	SignatureHeader = "X-Mainflux-Signature"
+)
+
+// Dispatcher posts the messages published to the channels to their webhooks.
+type Dispatcher interface {
+	// Dispatch adds the message to the batches of the channel webhooks
+	// whose filter it matches. The batches that reached the webhook batch
+	// size are posted immediately.
+	Dispatch(msg messaging.Message) error
+
+	// Flush posts all the pending batches, regardless of their size.
+	Flush()
+}
+
+type message struct {
+	Channel   string      `json:"channel"`
+	Subtopic  string      `json:"subtopic,omitempty"`
+	Publisher string      `json:"publisher"`
+	Protocol  string      `json:"protocol"`
+	Created   int64       `json:"created"`
+	Payload   interface{} `json:"payload"`
+}
+
+type batch struct {
+	webhook  things.Webhook
+	messages []message
+}
+
+type request struct {
+	Webhook  string    `json:"webhook"`
+	Messages []message `json:"messages"`
+}
+
+var _ Dispatcher = (*dispatcher)(nil)
+
+type dispatcher struct {
+	mu      sync.Mutex
+	repo    things.WebhookRepository
+	client  *http.Client
+	logger  logger.Logger
+	batches map[string]*batch
+}
+
+// New instantiates the webhook dispatcher, recording each delivery attempt
+// in the webhook delivery log.
+func New(repo things.WebhookRepository, client *http.Client, logger logger.Logger) Dispatcher {
+	return &dispatcher{
+		repo:    repo,
+		client:  client,
+		logger:  logger,
+		batches: make(map[string]*batch),
+	}
+}
+
+func (d *dispatcher) Dispatch(msg messaging.Message) error {
+	whs, err := d.repo.RetrieveAllByChannel(context.Background(), msg.Channel)
+	if err != nil {
+		return err
+	}
+
+	m := message{
+		Channel:   msg.Channel,
+		Subtopic:  msg.Subtopic,
+		Publisher: msg.Publisher,
+		Protocol:  msg.Protocol,
+		Created:   msg.Created,
+		Payload:   string(msg.Payload),
+	}
+	if json.Valid(msg.Payload) {
+		m.Payload = json.RawMessage(msg.Payload)
+	}
+
+	var ready []*batch
+	d.mu.Lock()
+	for _, w := range whs {
+		if !w.Matches(msg.Subtopic) {
+			continue
+		}
+		b, ok := d.batches[w.ID]
+		if !ok {
+			b = &batch{webhook: w}
+			d.batches[w.ID] = b
+		}
+		b.messages = append(b.messages, m)
+		if uint64(len(b.messages)) >= w.BatchSize {
+			ready = append(ready, b)
+			delete(d.batches, w.ID)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, b := range ready {
+		d.deliver(b)
+	}
+
+	return nil
+}
+
+func (d *dispatcher) Flush() {
+	d.mu.Lock()
+	batches := d.batches
+	d.batches = make(map[string]*batch)
+	d.mu.Unlock()
+
+	for _, b := range batches {
+		d.deliver(b)
+	}
+}
+
+func (d *dispatcher) deliver(b *batch) {
+	dl := things.Delivery{
+		WebhookID: b.webhook.ID,
+		Messages:  uint64(len(b.messages)),
+	}
+
+	status, err := d.post(b)
+	dl.Status = status
+	dl.DeliveredAt = time.Now().UTC()
+	if err != nil {
+		dl.Error = err.Error()
+		d.logger.Warn(fmt.Sprintf("Failed to deliver %d messages to webhook %s: %s", dl.Messages, b.webhook.ID, err))
+	}
+
+	if err := d.repo.SaveDelivery(context.Background(), dl); err != nil {
+		d.logger.Error(fmt.Sprintf("Failed to save delivery of webhook %s: %s", b.webhook.ID, err))
+	}
+}
+
+func (d *dispatcher) post(b *batch) (int, error) {
+	body, err := json.Marshal(request{Webhook: b.webhook.ID, Messages: b.messages})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, b.webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if b.webhook.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(b.webhook.Secret, body))
+	}
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, fmt.Errorf("unexpected status %s", res.Status)
+	}
+
+	return res.StatusCode, nil
+}
+
+// Sign returns the signature of the request body, in the form of
+// "sha256=<hex encoded HMAC>", so the receiver can verify its origin.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
@@ -0,0 +1,143 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/pkg/messaging"
+	"github.com/mainflux/mainflux/things"
+	"github.com/mainflux/mainflux/things/mocks"
+	"github.com/mainflux/mainflux/things/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	chanID = "chan"
+	secret = "secret"
+)
+
+type receiver struct {
+	mu       sync.Mutex
+	requests []received
+	status   int
+}
+
+type received struct {
+	signature string
+	messages  int
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	var req struct {
+		Messages []json.RawMessage `json:"messages"`
+	}
+	json.Unmarshal(body, &req)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	sig := r.Header.Get(webhook.SignatureHeader)
+	if sig != "" && sig != webhook.Sign(secret, body) {
+		sig = "invalid"
+	}
+	rc.requests = append(rc.requests, received{signature: sig, messages: len(req.Messages)})
+	w.WriteHeader(rc.status)
+}
+
+func TestDispatch(t *testing.T) {
+	rc := &receiver{status: http.StatusOK}
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	repo := mocks.NewWebhookRepository()
+	whs := []things.Webhook{
+		{ID: "batched", Owner: "owner", ChannelID: chanID, URL: ts.URL, Secret: secret, BatchSize: 2, CreatedAt: time.Now()},
+		{ID: "filtered", Owner: "owner", ChannelID: chanID, URL: ts.URL, Filter: "sensors.*", BatchSize: 1, CreatedAt: time.Now()},
+	}
+	for _, w := range whs {
+		err := repo.Save(context.Background(), w)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	}
+
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	d := webhook.New(repo, ts.Client(), logger)
+
+	cases := []struct {
+		desc     string
+		msg      messaging.Message
+		requests int
+	}{
+		{
+			desc:     "dispatch message to other channel",
+			msg:      messaging.Message{Channel: "other", Payload: []byte(`{"v":1}`)},
+			requests: 0,
+		},
+		{
+			desc:     "dispatch message not matching filter",
+			msg:      messaging.Message{Channel: chanID, Subtopic: "actuators", Payload: []byte(`{"v":1}`)},
+			requests: 0,
+		},
+		{
+			desc:     "dispatch message filling batch and matching filter",
+			msg:      messaging.Message{Channel: chanID, Subtopic: "sensors.temp", Payload: []byte(`{"v":2}`)},
+			requests: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		rc.requests = nil
+		err := d.Dispatch(tc.msg)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.requests, len(rc.requests), fmt.Sprintf("%s: expected %d requests got %d", tc.desc, tc.requests, len(rc.requests)))
+		for _, r := range rc.requests {
+			assert.NotEqual(t, "invalid", r.signature, fmt.Sprintf("%s: got invalid signature", tc.desc))
+		}
+	}
+
+	page, err := repo.RetrieveDeliveries(context.Background(), "batched", things.PageMetadata{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, uint64(1), page.Total, fmt.Sprintf("expected 1 delivery got %d", page.Total))
+	assert.Equal(t, uint64(2), page.Deliveries[0].Messages, fmt.Sprintf("expected 2 messages got %d", page.Deliveries[0].Messages))
+	assert.Equal(t, http.StatusOK, page.Deliveries[0].Status, fmt.Sprintf("expected status %d got %d", http.StatusOK, page.Deliveries[0].Status))
+}
+
+func TestFlush(t *testing.T) {
+	rc := &receiver{status: http.StatusInternalServerError}
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+
+	repo := mocks.NewWebhookRepository()
+	w := things.Webhook{ID: "webhook", Owner: "owner", ChannelID: chanID, URL: ts.URL, BatchSize: 10, CreatedAt: time.Now()}
+	err := repo.Save(context.Background(), w)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	d := webhook.New(repo, ts.Client(), logger)
+	err = d.Dispatch(messaging.Message{Channel: chanID, Payload: []byte("raw")})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Empty(t, rc.requests, "expected pending batch not to be posted")
+
+	d.Flush()
+	require.Len(t, rc.requests, 1, fmt.Sprintf("expected 1 request got %d", len(rc.requests)))
+	assert.Equal(t, 1, rc.requests[0].messages, fmt.Sprintf("expected 1 message got %d", rc.requests[0].messages))
+
+	page, err := repo.RetrieveDeliveries(context.Background(), w.ID, things.PageMetadata{Limit: 10})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, uint64(1), page.Total, fmt.Sprintf("expected 1 delivery got %d", page.Total))
+	assert.Equal(t, http.StatusInternalServerError, page.Deliveries[0].Status, fmt.Sprintf("expected status %d got %d", http.StatusInternalServerError, page.Deliveries[0].Status))
+	assert.NotEmpty(t, page.Deliveries[0].Error, "expected delivery error to be recorded")
+}
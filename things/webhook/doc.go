@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhook contains the dispatcher posting the messages published
+// to the channels to the webhooks configured by the channel owners.
+package webhook
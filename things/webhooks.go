@@ -0,0 +1,92 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package things
+
+import (
+	"context"
+	"path"
+	"time"
+)
+
+// Webhook represents the HTTP endpoint the messages published to the
+// channel are posted to.
+type Webhook struct {
+	ID        string
+	Owner     string
+	ChannelID string
+	URL       string
+
+	// Secret is used to sign the posted messages, so the receiver can
+	// verify their origin. The messages are not signed if it's empty.
+	Secret string
+
+	// Filter is the pattern the message subtopic has to match, e.g.
+	// "sensors.*". All the messages are posted if it's empty.
+	Filter string
+
+	// BatchSize is the number of the messages posted at once.
+	BatchSize uint64
+	CreatedAt time.Time
+}
+
+// Matches checks if the message published to the subtopic passes the
+// webhook filter.
+func (w Webhook) Matches(subtopic string) bool {
+	if w.Filter == "" {
+		return true
+	}
+	ok, _ := path.Match(w.Filter, subtopic)
+	return ok
+}
+
+// Delivery represents the attempt to post the batch of messages to the
+// webhook.
+type Delivery struct {
+	WebhookID string
+	Messages  uint64
+
+	// Status is the HTTP status code of the response, or zero if the
+	// request failed.
+	Status      int
+	Error       string
+	DeliveredAt time.Time
+}
+
+// DeliveriesPage contains the page of the webhook delivery log.
+type DeliveriesPage struct {
+	PageMetadata
+	Deliveries []Delivery
+}
+
+// WebhookRepository specifies a webhook persistence API.
+type WebhookRepository interface {
+	// Save persists the webhook.
+	Save(ctx context.Context, w Webhook) error
+
+	// RetrieveByID retrieves the webhook having the provided identifier,
+	// that is owned by the specified user.
+	RetrieveByID(ctx context.Context, owner, id string) (Webhook, error)
+
+	// RetrieveByChannel retrieves the webhooks of the channel, that are
+	// owned by the specified user.
+	RetrieveByChannel(ctx context.Context, owner, chID string) ([]Webhook, error)
+
+	// RetrieveAllByChannel retrieves all the webhooks of the channel the
+	// messages are dispatched to.
+	RetrieveAllByChannel(ctx context.Context, chID string) ([]Webhook, error)
+
+	// Count returns the number of the webhooks owned by the user.
+	Count(ctx context.Context, owner string) (uint64, error)
+
+	// Remove removes the webhook having the provided identifier, that is
+	// owned by the specified user, alongside its delivery log.
+	Remove(ctx context.Context, owner, id string) error
+
+	// SaveDelivery appends the delivery to the webhook delivery log.
+	SaveDelivery(ctx context.Context, d Delivery) error
+
+	// RetrieveDeliveries retrieves the subset of the webhook delivery log,
+	// newest first.
+	RetrieveDeliveries(ctx context.Context, webhookID string, pm PageMetadata) (DeliveriesPage, error)
+}
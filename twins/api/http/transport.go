@@ -35,6 +35,7 @@ const (
 func MakeHandler(tracer opentracing.Tracer, svc twins.Service) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
 	}
 
 	r := bone.New()
@@ -199,6 +200,11 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 	w.Header().Set("Content-Type", contentType)
 
+	if errors.Contains(err, errors.ErrInvalidQueryParams) {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
 	switch err {
 	case twins.ErrMalformedEntity:
 		w.WriteHeader(http.StatusBadRequest)
@@ -210,8 +216,6 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 		w.WriteHeader(http.StatusUnprocessableEntity)
 	case errors.ErrUnsupportedContentType:
 		w.WriteHeader(http.StatusUnsupportedMediaType)
-	case errors.ErrInvalidQueryParams:
-		w.WriteHeader(http.StatusBadRequest)
 	case io.ErrUnexpectedEOF:
 		w.WriteHeader(http.StatusBadRequest)
 	case io.EOF:
@@ -0,0 +1,8 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package postgres contains repository implementations using PostgreSQL as
+// the underlying database. Twin definitions, metadata and state payloads
+// are stored as JSONB, which makes it an alternative to the MongoDB
+// repositories for the deployments that already operate PostgreSQL.
+package postgres
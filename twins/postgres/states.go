@@ -0,0 +1,171 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/twins"
+)
+
+type stateRepository struct {
+	db *sqlx.DB
+}
+
+var _ twins.StateRepository = (*stateRepository)(nil)
+
+// NewStateRepository instantiates a PostgreSQL implementation of state
+// repository.
+func NewStateRepository(db *sqlx.DB) twins.StateRepository {
+	return &stateRepository{
+		db: db,
+	}
+}
+
+// Save persists the state
+func (sr *stateRepository) Save(ctx context.Context, st twins.State) error {
+	dbst, err := toDBState(st)
+	if err != nil {
+		return err
+	}
+
+	q := `INSERT INTO states (twin_id, id, definition, created, payload)
+		  VALUES (:twin_id, :id, :definition, :created, :payload);`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbst); err != nil {
+		return handleError(err)
+	}
+
+	return nil
+}
+
+// Update persists the state
+func (sr *stateRepository) Update(ctx context.Context, st twins.State) error {
+	dbst, err := toDBState(st)
+	if err != nil {
+		return err
+	}
+
+	q := `UPDATE states SET definition = :definition, created = :created, payload = :payload
+		  WHERE twin_id = :twin_id AND id = :id;`
+
+	if _, err := sr.db.NamedExecContext(ctx, q, dbst); err != nil {
+		return handleError(err)
+	}
+
+	return nil
+}
+
+// Count returns the number of states related to twin
+func (sr *stateRepository) Count(ctx context.Context, tw twins.Twin) (int64, error) {
+	var total int64
+	if err := sr.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM states WHERE twin_id = $1;`, tw.ID); err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// RetrieveAll retrieves the subset of states related to twin specified by id
+func (sr *stateRepository) RetrieveAll(ctx context.Context, offset uint64, limit uint64, twinID string) (twins.StatesPage, error) {
+	q := `SELECT twin_id, id, definition, created, payload FROM states
+		  WHERE twin_id = $1 ORDER BY id LIMIT $2 OFFSET $3;`
+
+	rows, err := sr.db.QueryxContext(ctx, q, twinID, limit, offset)
+	if err != nil {
+		return twins.StatesPage{}, err
+	}
+	defer rows.Close()
+
+	var items []twins.State
+	for rows.Next() {
+		var dbst dbState
+		if err := rows.StructScan(&dbst); err != nil {
+			return twins.StatesPage{}, err
+		}
+
+		st, err := toState(dbst)
+		if err != nil {
+			return twins.StatesPage{}, err
+		}
+		items = append(items, st)
+	}
+
+	var total uint64
+	if err := sr.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM states WHERE twin_id = $1;`, twinID); err != nil {
+		return twins.StatesPage{}, err
+	}
+
+	return twins.StatesPage{
+		States: items,
+		PageMetadata: twins.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+// RetrieveLast returns the last state related to twin spec by id
+func (sr *stateRepository) RetrieveLast(ctx context.Context, twinID string) (twins.State, error) {
+	q := `SELECT twin_id, id, definition, created, payload FROM states
+		  WHERE twin_id = $1 ORDER BY id DESC LIMIT 1;`
+
+	var dbst dbState
+	if err := sr.db.QueryRowxContext(ctx, q, twinID).StructScan(&dbst); err != nil {
+		if err == sql.ErrNoRows {
+			return twins.State{}, nil
+		}
+		return twins.State{}, err
+	}
+
+	return toState(dbst)
+}
+
+type dbState struct {
+	TwinID     string    `db:"twin_id"`
+	ID         int64     `db:"id"`
+	Definition int       `db:"definition"`
+	Created    time.Time `db:"created"`
+	Payload    []byte    `db:"payload"`
+}
+
+func toDBState(st twins.State) (dbState, error) {
+	payload := []byte("{}")
+	if len(st.Payload) > 0 {
+		b, err := json.Marshal(st.Payload)
+		if err != nil {
+			return dbState{}, errors.Wrap(twins.ErrMalformedEntity, err)
+		}
+		payload = b
+	}
+
+	return dbState{
+		TwinID:     st.TwinID,
+		ID:         st.ID,
+		Definition: st.Definition,
+		Created:    st.Created,
+		Payload:    payload,
+	}, nil
+}
+
+func toState(dbst dbState) (twins.State, error) {
+	var payload map[string]interface{}
+	if err := json.Unmarshal(dbst.Payload, &payload); err != nil {
+		return twins.State{}, errors.Wrap(twins.ErrMalformedEntity, err)
+	}
+
+	return twins.State{
+		TwinID:     dbst.TwinID,
+		ID:         dbst.ID,
+		Definition: dbst.Definition,
+		Created:    dbst.Created,
+		Payload:    payload,
+	}, nil
+}
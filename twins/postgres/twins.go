@@ -0,0 +1,313 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq" // required for DB access
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/twins"
+)
+
+const (
+	maxNameSize = 1024
+
+	errDuplicate  = "unique_violation"
+	errInvalid    = "invalid_text_representation"
+	errTruncation = "string_data_right_truncation"
+)
+
+type twinRepository struct {
+	db *sqlx.DB
+}
+
+var _ twins.TwinRepository = (*twinRepository)(nil)
+
+// NewTwinRepository instantiates a PostgreSQL implementation of twin
+// repository.
+func NewTwinRepository(db *sqlx.DB) twins.TwinRepository {
+	return &twinRepository{
+		db: db,
+	}
+}
+
+func (tr *twinRepository) Save(ctx context.Context, tw twins.Twin) (string, error) {
+	if len(tw.Name) > maxNameSize {
+		return "", twins.ErrMalformedEntity
+	}
+
+	dbtw, err := toDBTwin(tw)
+	if err != nil {
+		return "", err
+	}
+
+	q := `INSERT INTO twins (id, owner, name, created, updated, revision, definitions, metadata)
+		  VALUES (:id, :owner, :name, :created, :updated, :revision, :definitions, :metadata);`
+
+	if _, err := tr.db.NamedExecContext(ctx, q, dbtw); err != nil {
+		return "", handleError(err)
+	}
+
+	return tw.ID, nil
+}
+
+func (tr *twinRepository) Update(ctx context.Context, tw twins.Twin) error {
+	if len(tw.Name) > maxNameSize {
+		return twins.ErrMalformedEntity
+	}
+
+	dbtw, err := toDBTwin(tw)
+	if err != nil {
+		return err
+	}
+
+	q := `UPDATE twins SET owner = :owner, name = :name, created = :created, updated = :updated,
+		  revision = :revision, definitions = :definitions, metadata = :metadata
+		  WHERE id = :id;`
+
+	res, err := tr.db.NamedExecContext(ctx, q, dbtw)
+	if err != nil {
+		return handleError(err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt < 1 {
+		return twins.ErrNotFound
+	}
+
+	return nil
+}
+
+func (tr *twinRepository) RetrieveByID(ctx context.Context, twinID string) (twins.Twin, error) {
+	q := `SELECT id, owner, name, created, updated, revision, definitions, metadata FROM twins WHERE id = $1;`
+
+	var dbtw dbTwin
+	if err := tr.db.QueryRowxContext(ctx, q, twinID).StructScan(&dbtw); err != nil {
+		if err == sql.ErrNoRows {
+			return twins.Twin{}, twins.ErrNotFound
+		}
+		return twins.Twin{}, err
+	}
+
+	return toTwin(dbtw)
+}
+
+func (tr *twinRepository) RetrieveByAttribute(ctx context.Context, channel, subtopic string) ([]string, error) {
+	// Only the attributes of the last, i.e. current, definition are matched.
+	q := `SELECT id FROM twins
+		  WHERE EXISTS (
+		    SELECT 1 FROM jsonb_array_elements(definitions -> -1 -> 'attributes') AS attr
+		    WHERE attr ->> 'channel' = $1 AND attr ->> 'subtopic' IN ($2, $3)
+		  );`
+
+	rows, err := tr.db.QueryxContext(ctx, q, channel, subtopic, twins.SubtopicWildcard)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (tr *twinRepository) RetrieveAll(ctx context.Context, owner string, offset uint64, limit uint64, name string, metadata twins.Metadata) (twins.Page, error) {
+	params := map[string]interface{}{
+		"owner":  owner,
+		"name":   name,
+		"limit":  limit,
+		"offset": offset,
+	}
+
+	var query []string
+	if owner != "" {
+		query = append(query, "owner = :owner")
+	}
+	if name != "" {
+		query = append(query, "name = :name")
+	}
+	if len(metadata) > 0 {
+		m, err := json.Marshal(metadata)
+		if err != nil {
+			return twins.Page{}, errors.Wrap(twins.ErrMalformedEntity, err)
+		}
+		query = append(query, "metadata @> :metadata")
+		params["metadata"] = m
+	}
+
+	var whereClause string
+	if len(query) > 0 {
+		whereClause = fmt.Sprintf("WHERE %s", strings.Join(query, " AND "))
+	}
+
+	q := fmt.Sprintf(`SELECT id, owner, name, created, updated, revision, definitions, metadata FROM twins
+		  %s ORDER BY created, id LIMIT :limit OFFSET :offset;`, whereClause)
+
+	rows, err := tr.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return twins.Page{}, err
+	}
+	defer rows.Close()
+
+	var items []twins.Twin
+	for rows.Next() {
+		var dbtw dbTwin
+		if err := rows.StructScan(&dbtw); err != nil {
+			return twins.Page{}, err
+		}
+
+		tw, err := toTwin(dbtw)
+		if err != nil {
+			return twins.Page{}, err
+		}
+		items = append(items, tw)
+	}
+
+	cq := fmt.Sprintf(`SELECT COUNT(*) FROM twins %s;`, whereClause)
+	total, err := total(ctx, tr.db, cq, params)
+	if err != nil {
+		return twins.Page{}, err
+	}
+
+	return twins.Page{
+		Twins: items,
+		PageMetadata: twins.PageMetadata{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+	}, nil
+}
+
+func (tr *twinRepository) Remove(ctx context.Context, twinID string) error {
+	res, err := tr.db.ExecContext(ctx, `DELETE FROM twins WHERE id = $1;`, twinID)
+	if err != nil {
+		return err
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if cnt < 1 {
+		return twins.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbTwin struct {
+	ID          string    `db:"id"`
+	Owner       string    `db:"owner"`
+	Name        string    `db:"name"`
+	Created     time.Time `db:"created"`
+	Updated     time.Time `db:"updated"`
+	Revision    int       `db:"revision"`
+	Definitions []byte    `db:"definitions"`
+	Metadata    []byte    `db:"metadata"`
+}
+
+func toDBTwin(tw twins.Twin) (dbTwin, error) {
+	defs := []byte("[]")
+	if len(tw.Definitions) > 0 {
+		b, err := json.Marshal(tw.Definitions)
+		if err != nil {
+			return dbTwin{}, errors.Wrap(twins.ErrMalformedEntity, err)
+		}
+		defs = b
+	}
+
+	metadata := []byte("{}")
+	if len(tw.Metadata) > 0 {
+		b, err := json.Marshal(tw.Metadata)
+		if err != nil {
+			return dbTwin{}, errors.Wrap(twins.ErrMalformedEntity, err)
+		}
+		metadata = b
+	}
+
+	return dbTwin{
+		ID:          tw.ID,
+		Owner:       tw.Owner,
+		Name:        tw.Name,
+		Created:     tw.Created,
+		Updated:     tw.Updated,
+		Revision:    tw.Revision,
+		Definitions: defs,
+		Metadata:    metadata,
+	}, nil
+}
+
+func toTwin(dbtw dbTwin) (twins.Twin, error) {
+	var defs []twins.Definition
+	if err := json.Unmarshal(dbtw.Definitions, &defs); err != nil {
+		return twins.Twin{}, errors.Wrap(twins.ErrMalformedEntity, err)
+	}
+
+	var metadata twins.Metadata
+	if err := json.Unmarshal(dbtw.Metadata, &metadata); err != nil {
+		return twins.Twin{}, errors.Wrap(twins.ErrMalformedEntity, err)
+	}
+
+	return twins.Twin{
+		ID:          dbtw.ID,
+		Owner:       dbtw.Owner,
+		Name:        dbtw.Name,
+		Created:     dbtw.Created,
+		Updated:     dbtw.Updated,
+		Revision:    dbtw.Revision,
+		Definitions: defs,
+		Metadata:    metadata,
+	}, nil
+}
+
+func handleError(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if ok {
+		switch pqErr.Code.Name() {
+		case errInvalid, errTruncation:
+			return errors.Wrap(twins.ErrMalformedEntity, err)
+		case errDuplicate:
+			return errors.Wrap(twins.ErrConflict, err)
+		}
+	}
+
+	return err
+}
+
+func total(ctx context.Context, db *sqlx.DB, query string, params interface{}) (uint64, error) {
+	rows, err := db.NamedQueryContext(ctx, query, params)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	total := uint64(0)
+	if rows.Next() {
+		if err := rows.Scan(&total); err != nil {
+			return 0, err
+		}
+	}
+
+	return total, nil
+}
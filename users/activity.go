@@ -0,0 +1,65 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"time"
+)
+
+// Reasons of the failed login attempts.
+const (
+	ReasonInvalidCredentials = "invalid_credentials"
+	ReasonInvalidMFACode     = "invalid_mfa_code"
+	ReasonLocked             = "locked"
+	ReasonDisabled           = "disabled"
+	ReasonUnverified         = "unverified"
+	ReasonPasswordExpired    = "password_expired"
+)
+
+// maxUserAgentLength is the maximal length of the recorded user agent,
+// longer user agents are truncated.
+const maxUserAgentLength = 254
+
+// LoginAttempt represents the single attempt to log in as the user.
+type LoginAttempt struct {
+	ID     string
+	UserID string
+
+	// Success tells whether the user was issued the tokens. The password
+	// verified for the user enrolled in the MFA isn't an attempt by itself,
+	// the attempt is recorded once the MFA code is checked.
+	Success bool
+
+	// Reason is the reason of the failed attempt, empty on success.
+	Reason string
+
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+}
+
+// ActivityPage contains the page of the login attempts.
+type ActivityPage struct {
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Attempts []LoginAttempt
+}
+
+// ActivityRepository specifies the login activity persistence API. The
+// attempts are kept for the limited time, so the old attempts may be
+// removed.
+type ActivityRepository interface {
+	// Save appends the login attempt to the activity of the user.
+	Save(ctx context.Context, a LoginAttempt) error
+
+	// RetrieveByUser retrieves the login attempts of the user, the newest
+	// first.
+	RetrieveByUser(ctx context.Context, userID string, offset, limit uint64) (ActivityPage, error)
+
+	// CountSuccessful counts the successful login attempts of the user from
+	// the IP address, or from any address if the IP address is empty.
+	CountSuccessful(ctx context.Context, userID, ip string) (uint64, error)
+}
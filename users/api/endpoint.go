@@ -5,6 +5,8 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"net/url"
 
 	"github.com/go-kit/kit/endpoint"
 	"github.com/mainflux/mainflux/auth"
@@ -75,6 +77,64 @@ func passwordResetEndpoint(svc users.Service) endpoint.Endpoint {
 	}
 }
 
+// Email verification endpoint. The self registered user lands on it
+// after the click on the verification link from the email, given by
+// MF_USERS_VERIFY_URL, e.g. http://mainflux.com/users/verify?token=xxxxxxxxxxx.
+func verifyEmailEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(verifyEmailReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.VerifyEmail(ctx, req.token); err != nil {
+			return nil, err
+		}
+		return verifyEmailRes{}, nil
+	}
+}
+
+func inviteEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(inviteReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		inv, err := svc.Invite(ctx, req.token, users.Invitation{Email: req.Email, GroupID: req.GroupID})
+		if err != nil {
+			return nil, err
+		}
+
+		res := inviteRes{
+			ID:        inv.ID,
+			Email:     inv.Email,
+			GroupID:   inv.GroupID,
+			CreatedAt: inv.CreatedAt,
+			ExpiresAt: inv.ExpiresAt,
+		}
+		return res, nil
+	}
+}
+
+// Invitation acceptance endpoint. The invited user lands on the UI from the
+// link sent in the invitation, built from MF_USERS_INVITE_URL, e.g.
+// http://mainflux.com/invitations/accept?token=xxxxxxxxxxx, and the UI
+// submits the token along with the password of the new account.
+func acceptInvitationEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(acceptInvitationReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		uid, err := svc.AcceptInvitation(ctx, req.Token, req.Password)
+		if err != nil {
+			return nil, err
+		}
+		return createUserRes{ID: uid, created: true}, nil
+	}
+}
+
 func viewUserEndpoint(svc users.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(viewUserReq)
@@ -86,11 +146,199 @@ func viewUserEndpoint(svc users.Service) endpoint.Endpoint {
 		if err != nil {
 			return nil, err
 		}
-		return viewUserRes{
-			ID:       u.ID,
-			Email:    u.Email,
-			Metadata: u.Metadata,
-		}, nil
+		return buildUserResponse(u), nil
+	}
+}
+
+func disableUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.DisableUser(ctx, req.token, req.userID); err != nil {
+			return nil, err
+		}
+		return changeStatusRes{}, nil
+	}
+}
+
+func enableUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.EnableUser(ctx, req.token, req.userID); err != nil {
+			return nil, err
+		}
+		return changeStatusRes{}, nil
+	}
+}
+
+func unlockUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.UnlockUser(ctx, req.token, req.userID); err != nil {
+			return nil, err
+		}
+		return changeStatusRes{}, nil
+	}
+}
+
+func assignRoleEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(roleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.AssignRole(ctx, req.token, req.userID, req.role); err != nil {
+			return nil, err
+		}
+		return changeStatusRes{}, nil
+	}
+}
+
+func revokeRoleEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(roleReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.RevokeRole(ctx, req.token, req.userID, req.role); err != nil {
+			return nil, err
+		}
+		return changeStatusRes{}, nil
+	}
+}
+
+func listRoleMembersEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listRoleMembersReq)
+		if err := req.validate(); err != nil {
+			return userPageRes{}, err
+		}
+
+		page, err := svc.ListRoleMembers(ctx, req.token, req.role, req.offset, req.limit)
+		if err != nil {
+			return userPageRes{}, err
+		}
+
+		return buildUsersResponse(page), nil
+	}
+}
+
+func listActivityEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(listActivityReq)
+		if err := req.validate(); err != nil {
+			return activityPageRes{}, err
+		}
+
+		page, err := svc.ListActivity(ctx, req.token, req.offset, req.limit)
+		if err != nil {
+			return activityPageRes{}, err
+		}
+
+		res := activityPageRes{
+			pageRes: pageRes{
+				Total:  page.Total,
+				Offset: page.Offset,
+				Limit:  page.Limit,
+			},
+			Attempts: []loginAttemptRes{},
+		}
+		for _, a := range page.Attempts {
+			res.Attempts = append(res.Attempts, loginAttemptRes{
+				ID:        a.ID,
+				Success:   a.Success,
+				Reason:    a.Reason,
+				IP:        a.IP,
+				UserAgent: a.UserAgent,
+				CreatedAt: a.CreatedAt,
+			})
+		}
+		return res, nil
+	}
+}
+
+func importUsersEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(importUsersReq)
+		if err := req.validate(); err != nil {
+			return importUsersRes{}, err
+		}
+
+		records := []users.UserRecord{}
+		for _, u := range req.Users {
+			records = append(records, users.UserRecord{
+				Email:        u.Email,
+				Password:     u.Password,
+				PasswordHash: u.PasswordHash,
+				Metadata:     u.Metadata,
+				Groups:       u.Groups,
+			})
+		}
+		results, err := svc.ImportUsers(ctx, req.token, records)
+		if err != nil {
+			return importUsersRes{}, err
+		}
+
+		res := importUsersRes{Users: []importResultRes{}}
+		for _, r := range results {
+			ir := importResultRes{
+				Email:    r.Email,
+				ID:       r.ID,
+				Password: r.Password,
+			}
+			if r.Err != nil {
+				ir.Err = errorMessage(ctx, r.Err)
+			}
+			res.Users = append(res.Users, ir)
+		}
+		return res, nil
+	}
+}
+
+func exportUsersEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserReq)
+		if err := req.validate(); err != nil {
+			return exportUsersRes{}, err
+		}
+
+		records, err := svc.ExportUsers(ctx, req.token)
+		if err != nil {
+			return exportUsersRes{}, err
+		}
+
+		res := exportUsersRes{Users: []userRecordRes{}}
+		for _, r := range records {
+			res.Users = append(res.Users, userRecordRes{
+				ID:           r.ID,
+				Email:        r.Email,
+				PasswordHash: r.PasswordHash,
+				Metadata:     r.Metadata,
+				Groups:       r.Groups,
+			})
+		}
+		return res, nil
+	}
+}
+
+func deleteUserEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(userIDReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.DeleteUser(ctx, req.token, req.userID); err != nil {
+			return nil, err
+		}
+		return deleteRes{}, nil
 	}
 }
 
@@ -105,11 +353,7 @@ func viewProfileEndpoint(svc users.Service) endpoint.Endpoint {
 		if err != nil {
 			return nil, err
 		}
-		return viewUserRes{
-			ID:       u.ID,
-			Email:    u.Email,
-			Metadata: u.Metadata,
-		}, nil
+		return buildUserResponse(u), nil
 	}
 }
 
@@ -119,7 +363,7 @@ func listUsersEndpoint(svc users.Service) endpoint.Endpoint {
 		if err := req.validate(); err != nil {
 			return users.UserPage{}, err
 		}
-		up, err := svc.ListUsers(ctx, req.token, req.offset, req.limit, req.email, req.metadata)
+		up, err := svc.ListUsers(ctx, req.token, req.pageMetadata)
 		if err != nil {
 			return users.UserPage{}, err
 		}
@@ -135,6 +379,7 @@ func updateUserEndpoint(svc users.Service) endpoint.Endpoint {
 		}
 		user := users.User{
 			Metadata: req.Metadata,
+			Labels:   req.Labels,
 		}
 		err := svc.UpdateUser(ctx, req.token, user)
 		if err != nil {
@@ -144,6 +389,19 @@ func updateUserEndpoint(svc users.Service) endpoint.Endpoint {
 	}
 }
 
+func updateProfileEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(updateProfileReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.UpdateProfile(ctx, req.token, req.profile()); err != nil {
+			return nil, err
+		}
+		return updateUserRes{}, nil
+	}
+}
+
 func passwordChangeEndpoint(svc users.Service) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req := request.(passwChangeReq)
@@ -164,12 +422,94 @@ func loginEndpoint(svc users.Service) endpoint.Endpoint {
 		if err := req.validate(); err != nil {
 			return nil, err
 		}
-		token, err := svc.Login(ctx, req.user)
+		token, refresh, err := svc.Login(ctx, req.user)
+		return loginRes(token, refresh, err)
+	}
+}
+
+// loginRes returns the response of the login, which carries the challenge
+// token of the user enrolled in the MFA, or the reset token of the user whose
+// password expired, instead of the access token.
+func loginRes(token, refresh string, err error) (interface{}, error) {
+	switch {
+	case errors.Contains(err, users.ErrMFARequired):
+		return mfaChallengeRes{MFAToken: token}, nil
+	case errors.Contains(err, users.ErrPasswordExpired):
+		return passwordExpiredRes{ResetToken: token, PasswordExpired: true}, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return tokenRes{Token: token, RefreshToken: refresh}, nil
+}
+
+func loginMFAEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(loginMFAReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		token, refresh, err := svc.LoginMFA(ctx, req.MFAToken, req.Code)
+		return loginRes(token, refresh, err)
+	}
+}
+
+func oauthEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(oauthReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		url, state, err := svc.OAuthURL(ctx, req.provider)
+		if err != nil {
+			return nil, err
+		}
+
+		return oauthRedirectRes{url: url, state: state}, nil
+	}
+}
+
+func oauthCallbackEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(oauthCallbackReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		token, refresh, err := svc.OAuthLogin(ctx, req.provider, req.code)
+		return loginRes(token, refresh, err)
+	}
+}
+
+func enrollMFAEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		e, err := svc.EnrollMFA(ctx, req.token)
 		if err != nil {
 			return nil, err
 		}
 
-		return tokenRes{token}, nil
+		return enrollMFARes{
+			URI:           e.URI,
+			Secret:        e.Secret,
+			RecoveryCodes: e.RecoveryCodes,
+		}, nil
+	}
+}
+
+func confirmMFAEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(confirmMFAReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+		if err := svc.ConfirmMFA(ctx, req.token, req.Code); err != nil {
+			return nil, err
+		}
+
+		return changeStatusRes{}, nil
 	}
 }
 
@@ -180,7 +520,7 @@ func listMembersEndpoint(svc users.Service) endpoint.Endpoint {
 			return userPageRes{}, errors.Wrap(auth.ErrMalformedEntity, err)
 		}
 
-		page, err := svc.ListMembers(ctx, req.token, req.groupID, req.offset, req.limit, req.metadata)
+		page, err := svc.ListMembers(ctx, req.token, req.groupID, req.pageMetadata)
 		if err != nil {
 			return userPageRes{}, err
 		}
@@ -199,12 +539,67 @@ func buildUsersResponse(up users.UserPage) userPageRes {
 		Users: []viewUserRes{},
 	}
 	for _, user := range up.Users {
-		view := viewUserRes{
-			ID:       user.ID,
-			Email:    user.Email,
-			Metadata: user.Metadata,
-		}
-		res.Users = append(res.Users, view)
+		res.Users = append(res.Users, buildUserResponse(user))
 	}
 	return res
 }
+
+func buildUserResponse(u users.User) viewUserRes {
+	return viewUserRes{
+		ID:          u.ID,
+		Email:       u.Email,
+		FirstName:   u.Profile.FirstName,
+		LastName:    u.Profile.LastName,
+		DisplayName: u.Profile.DisplayName,
+		Phone:       u.Profile.Phone,
+		Locale:      u.Profile.Locale,
+		Metadata:    u.Metadata,
+		Labels:      u.Labels,
+		Status:      u.Status(),
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+func exportDataEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(viewUserReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		e, err := svc.ExportData(ctx, req.token)
+		if err != nil {
+			return nil, err
+		}
+
+		res := exportRes{
+			ID:        e.ID,
+			Status:    e.Status,
+			Error:     e.Error,
+			CreatedAt: e.CreatedAt,
+			ExpiresAt: e.ExpiresAt,
+		}
+		if !e.CompletedAt.IsZero() {
+			res.CompletedAt = &e.CompletedAt
+		}
+		if e.Status == users.ExportCompleted {
+			res.DownloadURL = fmt.Sprintf("/users/exports/%s?%s=%s", e.ID, signatureKey, url.QueryEscape(e.Signature))
+		}
+		return res, nil
+	}
+}
+
+func downloadExportEndpoint(svc users.Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(downloadExportReq)
+		if err := req.validate(); err != nil {
+			return nil, err
+		}
+
+		archive, err := svc.DownloadExport(ctx, req.id, req.signature)
+		if err != nil {
+			return nil, err
+		}
+		return archiveRes{id: req.id, archive: archive}, nil
+	}
+}
@@ -11,16 +11,19 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
-	"regexp"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/internal/ratelimit"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/mainflux/mainflux/users"
 	"github.com/mainflux/mainflux/users/api"
 	"github.com/mainflux/mainflux/users/bcrypt"
+	"github.com/mainflux/mainflux/users/jsonschema"
 	"github.com/mainflux/mainflux/users/mocks"
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/stretchr/testify/assert"
@@ -42,10 +45,15 @@ var (
 	notFoundRes    = toJSON(errorRes{users.ErrUserNotFound.Error()})
 	unauthRes      = toJSON(errorRes{users.ErrUnauthorizedAccess.Error()})
 	malformedRes   = toJSON(errorRes{users.ErrMalformedEntity.Error()})
-	weakPassword   = toJSON(errorRes{users.ErrPasswordFormat.Error()})
+	weakPassword   = toJSON(passwordErrorRes{users.ErrPasswordFormat.Error(), []string{users.RuleMinLength}})
 	unsupportedRes = toJSON(errorRes{errors.ErrUnsupportedContentType.Error()})
 	failDecodeRes  = toJSON(errorRes{errors.ErrMalformedEntity.Error()})
-	passRegex      = regexp.MustCompile("^.{8,}$")
+	passPolicy     = users.PasswordPolicy{MinLength: 8}
+	providers      = map[string]users.IdentityProvider{
+		"idp": mocks.NewIdentityProvider(map[string]users.Identity{
+			"code": {Subject: "subject", Email: validEmail, EmailVerified: true},
+		}),
+	}
 )
 
 type testRequest struct {
@@ -74,6 +82,24 @@ func (tr testRequest) make() (*http.Response, error) {
 }
 
 func newService() users.Service {
+	return newPolicyService(users.Lockout{}, passPolicy)
+}
+
+func newPolicyService(lockout users.Lockout, passPolicy users.PasswordPolicy) users.Service {
+	svc, _ := newServiceWithEmailer(lockout, passPolicy)
+	return svc
+}
+
+func newServiceWithEmailer(lockout users.Lockout, passPolicy users.PasswordPolicy) (users.Service, *mocks.Emailer) {
+	return newServiceWith(nil, lockout, passPolicy)
+}
+
+func newValidatedService(validator users.Validator) users.Service {
+	svc, _ := newServiceWith(validator, users.Lockout{}, passPolicy)
+	return svc
+}
+
+func newServiceWith(validator users.Validator, lockout users.Lockout, passPolicy users.PasswordPolicy) (users.Service, *mocks.Emailer) {
 	usersRepo := mocks.NewUserRepository()
 	hasher := bcrypt.New()
 
@@ -84,7 +110,10 @@ func newService() users.Service {
 	email := mocks.NewEmailer()
 	idProvider := uuid.New()
 
-	return users.New(usersRepo, hasher, auth, email, idProvider, passRegex)
+	exports := mocks.NewExportRepository()
+	source := mocks.NewExportSource(map[string]interface{}{}, nil)
+
+	return users.New(usersRepo, hasher, auth, email, idProvider, exports, mocks.NewInvitationRepository(), mocks.NewActivityRepository(), source, users.Config{ExportKey: []byte("export-key"), Validator: validator, Providers: providers, Lockout: lockout, PasswordPolicy: passPolicy}), email
 }
 
 func newServer(svc users.Service) *httptest.Server {
@@ -157,7 +186,7 @@ func TestLogin(t *testing.T) {
 	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email}, mockAuthzDB)
 	tkn, _ := auth.Issue(context.Background(), &mainflux.IssueReq{Id: user.ID, Email: user.Email, Type: 0})
 	token := tkn.GetValue()
-	tokenData := toJSON(map[string]string{"token": token})
+	tokenData := fmt.Sprintf(`{"token":"%s","refresh_token":"%s"}`, token, token)
 	data := toJSON(user)
 	invalidEmailData := toJSON(users.User{
 		Email:    invalidEmail,
@@ -210,6 +239,442 @@ func TestLogin(t *testing.T) {
 	}
 }
 
+func TestLoginMFA(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	req := testRequest{
+		client: client,
+		method: http.MethodPost,
+		url:    fmt.Sprintf("%s/mfa/enroll", ts.URL),
+		token:  user.Email,
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Equal(t, http.StatusCreated, res.StatusCode, fmt.Sprintf("enroll: expected status code %d got %d", http.StatusCreated, res.StatusCode))
+	var enrollment struct {
+		Secret string `json:"secret"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&enrollment)
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	code, err := users.TOTP(enrollment.Secret, time.Now())
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+
+	req = testRequest{
+		client:      client,
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/mfa/confirm", ts.URL),
+		contentType: contentType,
+		token:       user.Email,
+		body:        strings.NewReader(fmt.Sprintf(`{"code":"%s"}`, code)),
+	}
+	res, err = req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Equal(t, http.StatusNoContent, res.StatusCode, fmt.Sprintf("confirm: expected status code %d got %d", http.StatusNoContent, res.StatusCode))
+
+	login := func() string {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/tokens", ts.URL),
+			contentType: contentType,
+			body:        strings.NewReader(toJSON(user)),
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		require.Equal(t, http.StatusAccepted, res.StatusCode, fmt.Sprintf("login enrolled user: expected status code %d got %d", http.StatusAccepted, res.StatusCode))
+		var body struct {
+			Challenge string `json:"mfa_token"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		require.NotEmpty(t, body.Challenge, "login enrolled user: expected the challenge token")
+		return body.Challenge
+	}
+
+	// The challenge can't be used as the access token.
+	challenge := login()
+	req = testRequest{
+		client: client,
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/users/profile", ts.URL),
+		token:  challenge,
+	}
+	res, err = req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, http.StatusForbidden, res.StatusCode, fmt.Sprintf("view profile with challenge token: expected status code %d got %d", http.StatusForbidden, res.StatusCode))
+
+	cases := []struct {
+		desc   string
+		req    string
+		status int
+		res    string
+	}{
+		{"login with invalid code", fmt.Sprintf(`{"mfa_token":"%s","code":"invalid"}`, challenge), http.StatusForbidden, toJSON(errorRes{users.ErrInvalidMFACode.Error()})},
+		{"login with used challenge", fmt.Sprintf(`{"mfa_token":"%s","code":"%s"}`, challenge, code), http.StatusForbidden, unauthRes},
+		{"login without code", fmt.Sprintf(`{"mfa_token":"%s"}`, login()), http.StatusBadRequest, malformedRes},
+		{"login with invalid challenge", fmt.Sprintf(`{"mfa_token":"invalid","code":"%s"}`, code), http.StatusForbidden, unauthRes},
+		{"login with valid code", fmt.Sprintf(`{"mfa_token":"%s","code":"%s"}`, login(), code), http.StatusCreated, fmt.Sprintf(`{"token":"%s","refresh_token":"%s"}`, user.Email, user.Email)},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/tokens/mfa", ts.URL),
+			contentType: contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
+func TestOAuth(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	// The redirects to the identity provider aren't followed.
+	client := ts.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	res, err := client.Get(fmt.Sprintf("%s/login/idp", ts.URL))
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Equal(t, http.StatusFound, res.StatusCode, fmt.Sprintf("redirect: expected status code %d got %d", http.StatusFound, res.StatusCode))
+	location, err := url.Parse(res.Header.Get("Location"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	state := location.Query().Get("state")
+	var cookie *http.Cookie
+	for _, c := range res.Cookies() {
+		if c.Name == "oauth_state" {
+			cookie = c
+		}
+	}
+	require.NotNil(t, cookie, "redirect: expected the state cookie")
+	assert.Equal(t, state, cookie.Value, "redirect: expected the state cookie matching the state")
+
+	res, err = client.Get(fmt.Sprintf("%s/login/unknown", ts.URL))
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, http.StatusNotFound, res.StatusCode, fmt.Sprintf("redirect to unknown provider: expected status code %d got %d", http.StatusNotFound, res.StatusCode))
+
+	cases := []struct {
+		desc     string
+		provider string
+		code     string
+		state    string
+		cookie   string
+		status   int
+	}{
+		{
+			desc:     "callback without state cookie",
+			provider: "idp",
+			code:     "code",
+			state:    state,
+			cookie:   "",
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:     "callback with state not matching cookie",
+			provider: "idp",
+			code:     "code",
+			state:    "wrong",
+			cookie:   state,
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:     "callback without code",
+			provider: "idp",
+			code:     "",
+			state:    state,
+			cookie:   state,
+			status:   http.StatusBadRequest,
+		},
+		{
+			desc:     "callback with invalid code",
+			provider: "idp",
+			code:     "wrong",
+			state:    state,
+			cookie:   state,
+			status:   http.StatusForbidden,
+		},
+		{
+			desc:     "callback of unknown provider",
+			provider: "unknown",
+			code:     "code",
+			state:    state,
+			cookie:   state,
+			status:   http.StatusNotFound,
+		},
+		{
+			desc:     "callback with valid code",
+			provider: "idp",
+			code:     "code",
+			state:    state,
+			cookie:   state,
+			status:   http.StatusCreated,
+		},
+	}
+
+	for _, tc := range cases {
+		q := url.Values{"code": {tc.code}, "state": {tc.state}}
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/login/%s/callback?%s", ts.URL, tc.provider, q.Encode()), nil)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		if tc.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "oauth_state", Value: tc.cookie})
+		}
+		res, err := client.Do(req)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	svc := newService()
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), "login", ratelimit.PerMinute(10), ratelimit.PerMinute(2))
+	reset := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), "reset", ratelimit.Limit{}, ratelimit.Limit{})
+	ts := newServer(api.RateLimitMiddleware(svc, limiter, reset))
+	defer ts.Close()
+	client := ts.Client()
+
+	mockAuthzDB := map[string][]mocks.SubjectSet{}
+	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: authoritiesObjKey, Relation: memberRelationKey})
+
+	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email}, mockAuthzDB)
+	tkn, _ := auth.Issue(context.Background(), &mainflux.IssueReq{Id: user.ID, Email: user.Email, Type: 0})
+	token := tkn.GetValue()
+	_, err := svc.Register(context.Background(), token, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	invalidData := toJSON(users.User{
+		Email:    user.Email,
+		Password: "invalid_password",
+	})
+	otherData := toJSON(users.User{
+		Email:    "other@example.com",
+		Password: validPass,
+	})
+
+	cases := []struct {
+		desc   string
+		req    string
+		status int
+	}{
+		{"login with invalid credentials", invalidData, http.StatusForbidden},
+		{"login with valid credentials", toJSON(user), http.StatusCreated},
+		{"login exceeding account limit", toJSON(user), http.StatusTooManyRequests},
+		{"login with other account", otherData, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/tokens", ts.URL),
+			contentType: contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestLoginRateLimitForgedIP(t *testing.T) {
+	svc := newService()
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), "login", ratelimit.PerMinute(2), ratelimit.PerMinute(10))
+	reset := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), "reset", ratelimit.Limit{}, ratelimit.Limit{})
+	ts := newServer(api.RateLimitMiddleware(svc, limiter, reset))
+	defer ts.Close()
+	client := ts.Client()
+
+	cases := []struct {
+		desc   string
+		email  string
+		ip     string
+		status int
+	}{
+		{"login with forged address", "first@example.com", "203.0.113.1", http.StatusForbidden},
+		{"login with other forged address", "second@example.com", "203.0.113.2", http.StatusForbidden},
+		{"login exceeding address limit with forged address", "third@example.com", "203.0.113.3", http.StatusTooManyRequests},
+	}
+
+	for _, tc := range cases {
+		data := toJSON(users.User{Email: tc.email, Password: validPass})
+		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/tokens", ts.URL), strings.NewReader(data))
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("X-Real-IP", tc.ip)
+		res, err := client.Do(req)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestPasswordResetRateLimit(t *testing.T) {
+	svc := newService()
+	login := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), "login", ratelimit.Limit{}, ratelimit.Limit{})
+	reset := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), "reset", ratelimit.Limit{}, ratelimit.PerHour(2))
+	ts := newServer(api.RateLimitMiddleware(svc, login, reset))
+	defer ts.Close()
+	client := ts.Client()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	data := toJSON(user)
+	otherData := toJSON(users.User{
+		Email:    "other@example.com",
+		Password: validPass,
+	})
+
+	cases := []struct {
+		desc   string
+		req    string
+		status int
+	}{
+		{"password reset request", data, http.StatusCreated},
+		{"password reset request within account limit", data, http.StatusCreated},
+		{"password reset request exceeding account limit", data, http.StatusTooManyRequests},
+		{"password reset request with other account", otherData, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/password/reset-request", ts.URL),
+			contentType: contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestLoginLockout(t *testing.T) {
+	svc := newPolicyService(users.Lockout{Threshold: 1, Cooldown: time.Hour}, users.PasswordPolicy{})
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	userID, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	invalidData := toJSON(users.User{
+		Email:    user.Email,
+		Password: "invalid_password",
+	})
+
+	cases := []struct {
+		desc   string
+		url    string
+		token  string
+		req    string
+		status int
+	}{
+		{"login with invalid credentials", fmt.Sprintf("%s/tokens", ts.URL), "", invalidData, http.StatusLocked},
+		{"login locked user", fmt.Sprintf("%s/tokens", ts.URL), "", toJSON(user), http.StatusLocked},
+		{"unlock user without token", fmt.Sprintf("%s/users/%s/unlock", ts.URL, userID), "", "", http.StatusForbidden},
+		{"unlock non-existing user", fmt.Sprintf("%s/users/%s/unlock", ts.URL, "non-existing"), user.Email, "", http.StatusBadRequest},
+		{"unlock user", fmt.Sprintf("%s/users/%s/unlock", ts.URL, userID), user.Email, "", http.StatusNoContent},
+		{"login unlocked user", fmt.Sprintf("%s/tokens", ts.URL), "", toJSON(user), http.StatusCreated},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         tc.url,
+			token:       tc.token,
+			contentType: contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	svc := newPolicyService(users.Lockout{}, users.PasswordPolicy{History: 2, MaxAge: time.Nanosecond})
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	login := testRequest{
+		client:      client,
+		method:      http.MethodPost,
+		url:         fmt.Sprintf("%s/tokens", ts.URL),
+		contentType: contentType,
+		body:        strings.NewReader(toJSON(user)),
+	}
+	res, err := login.make()
+	require.Nil(t, err, fmt.Sprintf("login with expired password: unexpected error %s", err))
+	var expired struct {
+		ResetToken      string `json:"reset_token"`
+		PasswordExpired bool   `json:"password_expired"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&expired)
+	require.Nil(t, err, fmt.Sprintf("login with expired password: unexpected error %s", err))
+	assert.Equal(t, http.StatusAccepted, res.StatusCode, fmt.Sprintf("login with expired password: expected status code %d got %d", http.StatusAccepted, res.StatusCode))
+	assert.True(t, expired.PasswordExpired, "login with expired password: expected password expired")
+
+	resetReq := func(password string) string {
+		return fmt.Sprintf(`{"token":"%s","password":"%s","confirm_password":"%s"}`, expired.ResetToken, password, password)
+	}
+
+	cases := []struct {
+		desc   string
+		method string
+		url    string
+		req    string
+		status int
+		res    string
+	}{
+		{"reset password to current password", http.MethodPut, "/password/reset", resetReq(user.Password), http.StatusBadRequest, toJSON(errorRes{users.ErrPasswordReused.Error()})},
+		{"reset password to new password", http.MethodPut, "/password/reset", resetReq("newpassword"), http.StatusCreated, "{}"},
+		{"reset password with used reset token", http.MethodPut, "/password/reset", resetReq("otherpassword"), http.StatusForbidden, unauthRes},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      tc.method,
+			url:         ts.URL + tc.url,
+			contentType: contentType,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		body, err := ioutil.ReadAll(res.Body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		data := strings.Trim(string(body), "\n")
+
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		assert.Equal(t, tc.res, data, fmt.Sprintf("%s: expected body %s got %s", tc.desc, tc.res, data))
+	}
+}
+
 func TestUser(t *testing.T) {
 	svc := newService()
 	ts := newServer(svc)
@@ -254,6 +719,368 @@ func TestUser(t *testing.T) {
 	}
 }
 
+func TestUpdateProfile(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	valid := toJSON(map[string]string{
+		"first_name":   "Petar",
+		"last_name":    "Petrović",
+		"display_name": "pera",
+		"phone":        "+381641234567",
+		"locale":       "sr-RS",
+	})
+
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{"update profile", valid, contentType, user.Email, http.StatusOK},
+		{"update profile without token", valid, contentType, "", http.StatusForbidden},
+		{"update profile with invalid phone", toJSON(map[string]string{"phone": "064"}), contentType, user.Email, http.StatusBadRequest},
+		{"update profile with invalid locale", toJSON(map[string]string{"locale": "sr_RS"}), contentType, user.Email, http.StatusBadRequest},
+		{"update profile with malformed body", "{", contentType, user.Email, http.StatusBadRequest},
+		{"update profile without content type", valid, "", user.Email, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/users/profile", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+
+	req := testRequest{
+		client: client,
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/users/profile", ts.URL),
+		token:  user.Email,
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("view profile: unexpected error %s", err))
+	var body map[string]interface{}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	require.Nil(t, err, fmt.Sprintf("view profile: unexpected error %s", err))
+	assert.Equal(t, "sr-RS", body["locale"], fmt.Sprintf("view profile: expected locale sr-RS got %v", body["locale"]))
+}
+
+func TestVerifyEmail(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		status int
+	}{
+		{"verify email with valid token", user.Email, http.StatusNoContent},
+		{"verify email with invalid token", "invalid", http.StatusForbidden},
+		{"verify email without token", "", http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    fmt.Sprintf("%s/users/verify?token=%s", ts.URL, tc.token),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestInvitation(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	invalidTokenRes := toJSON(errorRes{users.ErrInvalidInvitation.Error()})
+	cases := []struct {
+		desc        string
+		url         string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{"invite user", "/invitations", `{"email": "invited@example.com"}`, contentType, user.Email, http.StatusCreated},
+		{"invite user with invalid token", "/invitations", `{"email": "invited@example.com"}`, contentType, "wrong", http.StatusForbidden},
+		{"invite user with empty token", "/invitations", `{"email": "invited@example.com"}`, contentType, "", http.StatusForbidden},
+		{"invite user without email", "/invitations", "{}", contentType, user.Email, http.StatusBadRequest},
+		{"invite registered user", "/invitations", toJSON(map[string]string{"email": user.Email}), contentType, user.Email, http.StatusConflict},
+		{"invite user with invalid request format", "/invitations", "{", contentType, user.Email, http.StatusBadRequest},
+		{"invite user with missing content type", "/invitations", `{"email": "invited@example.com"}`, "", user.Email, http.StatusUnsupportedMediaType},
+		{"accept invitation with invalid token", "/invitations/accept", `{"token": "wrong", "password": "password", "confirm_password": "password"}`, contentType, "", http.StatusNotFound},
+		{"accept invitation with confirm password not matching", "/invitations/accept", `{"token": "wrong", "password": "password", "confirm_password": "other"}`, contentType, "", http.StatusBadRequest},
+		{"accept invitation without token", "/invitations/accept", `{"password": "password", "confirm_password": "password"}`, contentType, "", http.StatusBadRequest},
+		{"accept invitation with missing content type", "/invitations/accept", `{"token": "wrong", "password": "password", "confirm_password": "password"}`, "", "", http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         ts.URL + tc.url,
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+
+		switch tc.status {
+		case http.StatusCreated:
+			var body struct {
+				ID    string `json:"id"`
+				Email string `json:"email"`
+			}
+			err := json.NewDecoder(res.Body).Decode(&body)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.NotEmpty(t, body.ID, fmt.Sprintf("%s: expected invitation ID", tc.desc))
+			assert.Equal(t, "invited@example.com", body.Email, fmt.Sprintf("%s: expected invited email", tc.desc))
+		case http.StatusNotFound:
+			body, err := ioutil.ReadAll(res.Body)
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+			assert.Equal(t, invalidTokenRes, strings.Trim(string(body), "\n"), fmt.Sprintf("%s: expected invalid invitation", tc.desc))
+		}
+	}
+}
+
+func TestBulkUsers(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	imported := "imported@example.com"
+	data := toJSON(map[string]interface{}{
+		"users": []map[string]string{
+			{"email": imported, "password": validPass},
+			{"email": "invalid-email"},
+		},
+	})
+	cases := []struct {
+		desc        string
+		req         string
+		contentType string
+		token       string
+		status      int
+	}{
+		{"import users", data, contentType, user.Email, http.StatusOK},
+		{"import users with invalid token", data, contentType, "wrong", http.StatusForbidden},
+		{"import users with empty token", data, contentType, "", http.StatusForbidden},
+		{"import no users", `{"users": []}`, contentType, user.Email, http.StatusBadRequest},
+		{"import users with invalid request format", "{", contentType, user.Email, http.StatusBadRequest},
+		{"import users with missing content type", data, "", user.Email, http.StatusUnsupportedMediaType},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPost,
+			url:         fmt.Sprintf("%s/users/import", ts.URL),
+			contentType: tc.contentType,
+			token:       tc.token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusOK {
+			continue
+		}
+
+		var body struct {
+			Users []struct {
+				Email string `json:"email"`
+				ID    string `json:"id"`
+				Err   string `json:"error"`
+			} `json:"users"`
+		}
+		err = json.NewDecoder(res.Body).Decode(&body)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		require.Len(t, body.Users, 2, fmt.Sprintf("%s: expected 2 results", tc.desc))
+		assert.NotEmpty(t, body.Users[0].ID, fmt.Sprintf("%s: expected imported user ID", tc.desc))
+		assert.Empty(t, body.Users[0].Err, fmt.Sprintf("%s: unexpected import error %s", tc.desc, body.Users[0].Err))
+		assert.Empty(t, body.Users[1].ID, fmt.Sprintf("%s: unexpected user ID %s", tc.desc, body.Users[1].ID))
+		assert.Equal(t, users.ErrMalformedEntity.Error(), body.Users[1].Err, fmt.Sprintf("%s: expected import error", tc.desc))
+	}
+
+	req := testRequest{
+		client: client,
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/users/export", ts.URL),
+	}
+	res, err := req.make()
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, http.StatusForbidden, res.StatusCode, fmt.Sprintf("export users without token: expected status code %d got %d", http.StatusForbidden, res.StatusCode))
+
+	req.token = user.Email
+	res, err = req.make()
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, http.StatusOK, res.StatusCode, fmt.Sprintf("export users: expected status code %d got %d", http.StatusOK, res.StatusCode))
+
+	var body struct {
+		Users []struct {
+			Email        string   `json:"email"`
+			PasswordHash string   `json:"password_hash"`
+			Groups       []string `json:"groups"`
+		} `json:"users"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&body)
+	assert.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	require.Len(t, body.Users, 2, "export users: expected 2 users")
+	assert.Equal(t, imported, body.Users[0].Email, fmt.Sprintf("export users: expected %s got %s", imported, body.Users[0].Email))
+	assert.NotEmpty(t, body.Users[0].PasswordHash, "export users: expected password hash")
+	assert.NotNil(t, body.Users[0].Groups, "export users: expected groups")
+}
+
+func TestUserStatus(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	other := users.User{Email: "other@example.com", Password: validPass}
+	userID, err := svc.Register(context.Background(), user.Email, other)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		method string
+		url    string
+		token  string
+		status int
+	}{
+		{"disable user without token", http.MethodPost, fmt.Sprintf("%s/users/%s/disable", ts.URL, userID), "", http.StatusForbidden},
+		{"disable user", http.MethodPost, fmt.Sprintf("%s/users/%s/disable", ts.URL, userID), user.Email, http.StatusNoContent},
+		{"enable non-existing user", http.MethodPost, fmt.Sprintf("%s/users/%s/enable", ts.URL, "non-existing"), user.Email, http.StatusBadRequest},
+		{"enable user", http.MethodPost, fmt.Sprintf("%s/users/%s/enable", ts.URL, userID), user.Email, http.StatusNoContent},
+		{"delete user with invalid token", http.MethodDelete, fmt.Sprintf("%s/users/%s", ts.URL, userID), "invalid", http.StatusForbidden},
+		{"delete user", http.MethodDelete, fmt.Sprintf("%s/users/%s", ts.URL, userID), user.Email, http.StatusNoContent},
+		{"delete deleted user", http.MethodDelete, fmt.Sprintf("%s/users/%s", ts.URL, userID), user.Email, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: tc.method,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	cases := []struct {
+		desc   string
+		url    string
+		token  string
+		status int
+	}{
+		{"list users without token", fmt.Sprintf("%s/users", ts.URL), "", http.StatusForbidden},
+		{"list users", fmt.Sprintf("%s/users", ts.URL), user.Email, http.StatusOK},
+		{"list users by status", fmt.Sprintf("%s/users?status=%s", ts.URL, users.StatusDisabled), user.Email, http.StatusOK},
+		{"list users by invalid status", fmt.Sprintf("%s/users?status=invalid", ts.URL), user.Email, http.StatusBadRequest},
+		{"list users by creation time", fmt.Sprintf("%s/users?created_from=2021-01-01T00:00:00Z&created_to=2022-01-01T00:00:00Z", ts.URL), user.Email, http.StatusOK},
+		{"list users by invalid creation time", fmt.Sprintf("%s/users?created_from=invalid", ts.URL), user.Email, http.StatusBadRequest},
+		{"list users by inverted creation time", fmt.Sprintf("%s/users?created_from=2022-01-01T00:00:00Z&created_to=2021-01-01T00:00:00Z", ts.URL), user.Email, http.StatusBadRequest},
+		{"list users sorted by creation time", fmt.Sprintf("%s/users?order=created_at&dir=desc", ts.URL), user.Email, http.StatusOK},
+		{"list users with invalid order", fmt.Sprintf("%s/users?order=password", ts.URL), user.Email, http.StatusBadRequest},
+		{"list users with invalid direction", fmt.Sprintf("%s/users?dir=up", ts.URL), user.Email, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
+func TestRoles(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	other := users.User{Email: "other@example.com", Password: validPass}
+	userID, err := svc.Register(context.Background(), user.Email, other)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		method string
+		url    string
+		token  string
+		status int
+	}{
+		{"assign role without token", http.MethodPut, fmt.Sprintf("%s/users/%s/roles/%s", ts.URL, userID, users.RoleAdmin), "", http.StatusForbidden},
+		{"assign invalid role", http.MethodPut, fmt.Sprintf("%s/users/%s/roles/%s", ts.URL, userID, "invalid"), user.Email, http.StatusNotFound},
+		{"assign role to non-existing user", http.MethodPut, fmt.Sprintf("%s/users/%s/roles/%s", ts.URL, "non-existing", users.RoleAdmin), user.Email, http.StatusBadRequest},
+		{"assign admin role", http.MethodPut, fmt.Sprintf("%s/users/%s/roles/%s", ts.URL, userID, users.RoleAdmin), user.Email, http.StatusNoContent},
+		{"list admins without token", http.MethodGet, fmt.Sprintf("%s/roles/%s/users", ts.URL, users.RoleAdmin), "", http.StatusForbidden},
+		{"list admins", http.MethodGet, fmt.Sprintf("%s/roles/%s/users", ts.URL, users.RoleAdmin), user.Email, http.StatusOK},
+		{"list members of invalid role", http.MethodGet, fmt.Sprintf("%s/roles/%s/users", ts.URL, "invalid"), user.Email, http.StatusNotFound},
+		{"revoke admin role", http.MethodDelete, fmt.Sprintf("%s/users/%s/roles/%s", ts.URL, userID, users.RoleAdmin), user.Email, http.StatusNoContent},
+		{"revoke role of the last admin", http.MethodDelete, fmt.Sprintf("%s/users/%s/roles/%s", ts.URL, user.Email, users.RoleAdmin), user.Email, http.StatusConflict},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: tc.method,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+	}
+}
+
 func TestPasswordResetRequest(t *testing.T) {
 	svc := newService()
 	ts := newServer(svc)
@@ -317,7 +1144,7 @@ func TestPasswordResetRequest(t *testing.T) {
 }
 
 func TestPasswordReset(t *testing.T) {
-	svc := newService()
+	svc, e := newServiceWithEmailer(users.Lockout{}, passPolicy)
 	ts := newServer(svc)
 	defer ts.Close()
 	client := ts.Client()
@@ -340,16 +1167,23 @@ func TestPasswordReset(t *testing.T) {
 	_, err = svc.Register(context.Background(), token, user)
 	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
 
-	reqData.Password = user.Password
-	reqData.ConfPass = user.Password
-	reqData.Token = token
+	err = svc.GenerateResetToken(context.Background(), user.Email, "example.com")
+	require.Nil(t, err, fmt.Sprintf("generate reset token got unexpected error: %s", err))
+	resetToken := e.PasswordReset(user.Email)
+
+	reqData.Password = "newpassword"
+	reqData.ConfPass = "newpassword"
+	reqData.Token = resetToken
 	reqExisting := toJSON(reqData)
 
+	reqData.Token = token
+	reqLoginToken := toJSON(reqData)
+
 	reqData.Token = "wrong"
 
 	reqNoExist := toJSON(reqData)
 
-	reqData.Token = token
+	reqData.Token = resetToken
 
 	reqData.ConfPass = invalidPass
 	reqPassNoMatch := toJSON(reqData)
@@ -365,14 +1199,16 @@ func TestPasswordReset(t *testing.T) {
 		res         string
 		tok         string
 	}{
-		{"password reset with valid token", reqExisting, contentType, http.StatusCreated, "{}", token},
 		{"password reset with invalid token", reqNoExist, contentType, http.StatusForbidden, unauthRes, token},
+		{"password reset with login token", reqLoginToken, contentType, http.StatusForbidden, unauthRes, token},
 		{"password reset with confirm password not matching", reqPassNoMatch, contentType, http.StatusBadRequest, malformedRes, token},
 		{"password reset request with invalid request format", "{", contentType, http.StatusBadRequest, malformedRes, token},
 		{"password reset request with empty JSON request", "{}", contentType, http.StatusBadRequest, malformedRes, token},
 		{"password reset request with empty request", "", contentType, http.StatusBadRequest, malformedRes, token},
 		{"password reset request with missing content type", reqExisting, "", http.StatusUnsupportedMediaType, unsupportedRes, token},
 		{"password reset with weak password", reqPassWeak, contentType, http.StatusBadRequest, weakPassword, token},
+		{"password reset with valid token", reqExisting, contentType, http.StatusCreated, "{}", token},
+		{"password reset with used token", reqExisting, contentType, http.StatusForbidden, unauthRes, token},
 	}
 
 	for _, tc := range cases {
@@ -472,3 +1308,172 @@ func TestPasswordChange(t *testing.T) {
 type errorRes struct {
 	Err string `json:"error"`
 }
+
+type passwordErrorRes struct {
+	Err   string   `json:"error"`
+	Rules []string `json:"rules"`
+}
+
+type violationRes struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type metadataErrorRes struct {
+	Err        string         `json:"error"`
+	Violations []violationRes `json:"violations"`
+}
+
+func TestUpdateUserMetadataSchema(t *testing.T) {
+	validator, err := jsonschema.New([]byte(`{"type": "object", "properties": {"floor": {"type": "integer"}}, "required": ["floor"]}`))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	svc := newValidatedService(validator)
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	u := user
+	u.Metadata = users.Metadata{"floor": 1}
+	_, err = svc.Register(context.Background(), user.Email, u)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+	token, _, err := svc.Login(context.Background(), u)
+	require.Nil(t, err, fmt.Sprintf("login user got unexpected error: %s", err))
+
+	cases := []struct {
+		desc       string
+		req        string
+		status     int
+		violations []string
+	}{
+		{
+			desc:   "update user with conforming metadata",
+			req:    `{"metadata": {"floor": 2}}`,
+			status: http.StatusOK,
+		},
+		{
+			desc:       "update user with metadata of wrong type",
+			req:        `{"metadata": {"floor": "second"}}`,
+			status:     http.StatusUnprocessableEntity,
+			violations: []string{"floor"},
+		},
+		{
+			desc:       "update user with metadata without required field",
+			req:        `{"metadata": {"room": 2}}`,
+			status:     http.StatusUnprocessableEntity,
+			violations: []string{"(root)"},
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client:      client,
+			method:      http.MethodPut,
+			url:         fmt.Sprintf("%s/users", ts.URL),
+			contentType: contentType,
+			token:       token,
+			body:        strings.NewReader(tc.req),
+		}
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.status != http.StatusUnprocessableEntity {
+			continue
+		}
+		var body metadataErrorRes
+		err = json.NewDecoder(res.Body).Decode(&body)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		var fields []string
+		for _, v := range body.Violations {
+			fields = append(fields, v.Field)
+		}
+		assert.Equal(t, users.ErrValidation.Error(), body.Err, fmt.Sprintf("%s: expected error %s got %s", tc.desc, users.ErrValidation, body.Err))
+		assert.Equal(t, tc.violations, fields, fmt.Sprintf("%s: expected violated fields %v got %v", tc.desc, tc.violations, fields))
+	}
+}
+
+func TestExportData(t *testing.T) {
+	svc := newService()
+	ts := newServer(svc)
+	defer ts.Close()
+	client := ts.Client()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user got unexpected error: %s", err))
+	token, _, err := svc.Login(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("login user got unexpected error: %s", err))
+
+	req := testRequest{
+		client: client,
+		method: http.MethodGet,
+		url:    fmt.Sprintf("%s/users/me/export", ts.URL),
+		token:  token,
+	}
+	res, err := req.make()
+	require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+	assert.Equal(t, http.StatusAccepted, res.StatusCode, fmt.Sprintf("start export: expected status code %d got %d", http.StatusAccepted, res.StatusCode))
+
+	var export exportRes
+	for i := 0; i < 100 && export.Status != users.ExportCompleted; i++ {
+		time.Sleep(10 * time.Millisecond)
+		res, err := req.make()
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		err = json.NewDecoder(res.Body).Decode(&export)
+		require.Nil(t, err, fmt.Sprintf("unexpected error %s", err))
+		res.Body.Close()
+	}
+	require.Equal(t, users.ExportCompleted, export.Status, "export expected to complete")
+	require.NotEmpty(t, export.DownloadURL, "expected the completed export to have the download link")
+
+	cases := []struct {
+		desc        string
+		url         string
+		token       string
+		status      int
+		contentType string
+	}{
+		{
+			desc:   "export data with invalid token",
+			url:    fmt.Sprintf("%s/users/me/export", ts.URL),
+			token:  "",
+			status: http.StatusForbidden,
+		},
+		{
+			desc:        "download export",
+			url:         ts.URL + export.DownloadURL,
+			status:      http.StatusOK,
+			contentType: "application/zip",
+		},
+		{
+			desc:   "download export with invalid signature",
+			url:    fmt.Sprintf("%s/users/exports/%s?signature=invalid", ts.URL, export.ID),
+			status: http.StatusForbidden,
+		},
+		{
+			desc:   "download export without signature",
+			url:    fmt.Sprintf("%s/users/exports/%s", ts.URL, export.ID),
+			status: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		req := testRequest{
+			client: client,
+			method: http.MethodGet,
+			url:    tc.url,
+			token:  tc.token,
+		}
+		res, err := req.make()
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error %s", tc.desc, err))
+		assert.Equal(t, tc.status, res.StatusCode, fmt.Sprintf("%s: expected status code %d got %d", tc.desc, tc.status, res.StatusCode))
+		if tc.contentType != "" {
+			ct := res.Header.Get("Content-Type")
+			assert.Equal(t, tc.contentType, ct, fmt.Sprintf("%s: expected content type %s got %s", tc.desc, tc.contentType, ct))
+		}
+	}
+}
+
+type exportRes struct {
+	ID          string `json:"id"`
+	Status      string `json:"status"`
+	DownloadURL string `json:"download_url"`
+}
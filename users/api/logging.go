@@ -38,7 +38,7 @@ func (lm *loggingMiddleware) Register(ctx context.Context, token string, user us
 	return lm.svc.Register(ctx, token, user)
 }
 
-func (lm *loggingMiddleware) Login(ctx context.Context, user users.User) (token string, err error) {
+func (lm *loggingMiddleware) Login(ctx context.Context, user users.User) (token, refresh string, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method login for user %s took %s to complete", user.Email, time.Since(begin))
 		if err != nil {
@@ -51,6 +51,45 @@ func (lm *loggingMiddleware) Login(ctx context.Context, user users.User) (token
 	return lm.svc.Login(ctx, user)
 }
 
+func (lm *loggingMiddleware) VerifyEmail(ctx context.Context, token string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method verify_email took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.VerifyEmail(ctx, token)
+}
+
+func (lm *loggingMiddleware) Invite(ctx context.Context, token string, inv users.Invitation) (i users.Invitation, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method invite for email %s took %s to complete", inv.Email, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.Invite(ctx, token, inv)
+}
+
+func (lm *loggingMiddleware) AcceptInvitation(ctx context.Context, token, password string) (id string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method accept_invitation for user %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AcceptInvitation(ctx, token, password)
+}
+
 func (lm *loggingMiddleware) ViewUser(ctx context.Context, token, id string) (u users.User, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method view_user for user %s took %s to complete", u.Email, time.Since(begin))
@@ -77,7 +116,7 @@ func (lm *loggingMiddleware) ViewProfile(ctx context.Context, token string) (u u
 	return lm.svc.ViewProfile(ctx, token)
 }
 
-func (lm *loggingMiddleware) ListUsers(ctx context.Context, token string, offset, limit uint64, email string, um users.Metadata) (e users.UserPage, err error) {
+func (lm *loggingMiddleware) ListUsers(ctx context.Context, token string, pm users.PageMetadata) (e users.UserPage, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method list_users for token %s took %s to complete", token, time.Since(begin))
 		if err != nil {
@@ -87,7 +126,7 @@ func (lm *loggingMiddleware) ListUsers(ctx context.Context, token string, offset
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListUsers(ctx, token, offset, limit, email, um)
+	return lm.svc.ListUsers(ctx, token, pm)
 }
 
 func (lm *loggingMiddleware) UpdateUser(ctx context.Context, token string, u users.User) (err error) {
@@ -103,6 +142,19 @@ func (lm *loggingMiddleware) UpdateUser(ctx context.Context, token string, u use
 	return lm.svc.UpdateUser(ctx, token, u)
 }
 
+func (lm *loggingMiddleware) UpdateProfile(ctx context.Context, token string, p users.Profile) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method update_profile took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UpdateProfile(ctx, token, p)
+}
+
 func (lm *loggingMiddleware) GenerateResetToken(ctx context.Context, email, host string) (err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method generate_reset_token for user %s took %s to complete", email, time.Since(begin))
@@ -155,7 +207,7 @@ func (lm *loggingMiddleware) SendPasswordReset(ctx context.Context, host, email,
 	return lm.svc.SendPasswordReset(ctx, host, email, token)
 }
 
-func (lm *loggingMiddleware) ListMembers(ctx context.Context, token, groupID string, offset, limit uint64, m users.Metadata) (mp users.UserPage, err error) {
+func (lm *loggingMiddleware) ListMembers(ctx context.Context, token, groupID string, pm users.PageMetadata) (mp users.UserPage, err error) {
 	defer func(begin time.Time) {
 		message := fmt.Sprintf("Method list_members for group %s took %s to complete", groupID, time.Since(begin))
 		if err != nil {
@@ -165,5 +217,226 @@ func (lm *loggingMiddleware) ListMembers(ctx context.Context, token, groupID str
 		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
 	}(time.Now())
 
-	return lm.svc.ListMembers(ctx, token, groupID, offset, limit, m)
+	return lm.svc.ListMembers(ctx, token, groupID, pm)
+}
+
+func (lm *loggingMiddleware) ExportData(ctx context.Context, token string) (e users.Export, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_data for export %s took %s to complete", e.ID, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportData(ctx, token)
+}
+
+func (lm *loggingMiddleware) DownloadExport(ctx context.Context, id, signature string) (archive []byte, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method download_export for export %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DownloadExport(ctx, id, signature)
+}
+
+func (lm *loggingMiddleware) DisableUser(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method disable_user for user %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DisableUser(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) EnableUser(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method enable_user for user %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EnableUser(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) UnlockUser(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method unlock_user for user %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.UnlockUser(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) AssignRole(ctx context.Context, token, id, role string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method assign_role %s for user %s took %s to complete", role, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.AssignRole(ctx, token, id, role)
+}
+
+func (lm *loggingMiddleware) RevokeRole(ctx context.Context, token, id, role string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method revoke_role %s for user %s took %s to complete", role, id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.RevokeRole(ctx, token, id, role)
+}
+
+func (lm *loggingMiddleware) ListRoleMembers(ctx context.Context, token, role string, offset, limit uint64) (up users.UserPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_role_members for role %s took %s to complete", role, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListRoleMembers(ctx, token, role, offset, limit)
+}
+
+func (lm *loggingMiddleware) DeleteUser(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method delete_user for user %s took %s to complete", id, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.DeleteUser(ctx, token, id)
+}
+
+func (lm *loggingMiddleware) EnrollMFA(ctx context.Context, token string) (e users.Enrollment, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method enroll_mfa took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.EnrollMFA(ctx, token)
+}
+
+func (lm *loggingMiddleware) ConfirmMFA(ctx context.Context, token, code string) (err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method confirm_mfa took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ConfirmMFA(ctx, token, code)
+}
+
+func (lm *loggingMiddleware) LoginMFA(ctx context.Context, challenge, code string) (token, refresh string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method login_mfa took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.LoginMFA(ctx, challenge, code)
+}
+
+func (lm *loggingMiddleware) OAuthURL(ctx context.Context, provider string) (url, state string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method oauth_url for provider %s took %s to complete", provider, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.OAuthURL(ctx, provider)
+}
+
+func (lm *loggingMiddleware) OAuthLogin(ctx context.Context, provider, code string) (token, refresh string, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method oauth_login for provider %s took %s to complete", provider, time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.OAuthLogin(ctx, provider, code)
+}
+
+func (lm *loggingMiddleware) ListActivity(ctx context.Context, token string, offset, limit uint64) (ap users.ActivityPage, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method list_activity took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ListActivity(ctx, token, offset, limit)
+}
+
+func (lm *loggingMiddleware) ImportUsers(ctx context.Context, token string, records []users.UserRecord) (res []users.ImportResult, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method import_users for %d users took %s to complete", len(records), time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ImportUsers(ctx, token, records)
+}
+
+func (lm *loggingMiddleware) ExportUsers(ctx context.Context, token string) (res []users.UserRecord, err error) {
+	defer func(begin time.Time) {
+		message := fmt.Sprintf("Method export_users took %s to complete", time.Since(begin))
+		if err != nil {
+			lm.logger.Warn(fmt.Sprintf("%s with error: %s.", message, err))
+			return
+		}
+		lm.logger.Info(fmt.Sprintf("%s without errors.", message))
+	}(time.Now())
+
+	return lm.svc.ExportUsers(ctx, token)
 }
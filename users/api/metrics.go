@@ -5,9 +5,11 @@ package api
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"github.com/go-kit/kit/metrics"
+	"github.com/mainflux/mainflux/internal/cardinality"
 	"github.com/mainflux/mainflux/users"
 )
 
@@ -16,6 +18,7 @@ var _ users.Service = (*metricsMiddleware)(nil)
 type metricsMiddleware struct {
 	counter metrics.Counter
 	latency metrics.Histogram
+	tenants *cardinality.Limiter
 	svc     users.Service
 }
 
@@ -28,101 +31,353 @@ func MetricsMiddleware(svc users.Service, counter metrics.Counter, latency metri
 	}
 }
 
-func (ms *metricsMiddleware) Register(ctx context.Context, token string, user users.User) (string, error) {
+// TenantMetricsMiddleware instruments core service by tracking request count
+// and latency labeled by tenant. Tenant is the email domain of the user the
+// request is made for, if it's known. Counter and latency have to declare the
+// "method" and "tenant" labels. Tenants exceeding the limiter are reported as
+// cardinality.Other.
+func TenantMetricsMiddleware(svc users.Service, counter metrics.Counter, latency metrics.Histogram, tenants *cardinality.Limiter) users.Service {
+	return &metricsMiddleware{
+		counter: counter,
+		latency: latency,
+		tenants: tenants,
+		svc:     svc,
+	}
+}
+
+func (ms *metricsMiddleware) Register(ctx context.Context, token string, user users.User) (id string, err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "register").Add(1)
-		ms.latency.With("method", "register").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("register", user.Email)
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.Register(ctx, token, user)
 }
 
-func (ms *metricsMiddleware) Login(ctx context.Context, user users.User) (string, error) {
+func (ms *metricsMiddleware) Login(ctx context.Context, user users.User) (t, r string, err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "login").Add(1)
-		ms.latency.With("method", "login").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("login", user.Email)
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.Login(ctx, user)
 }
 
-func (ms *metricsMiddleware) ViewUser(ctx context.Context, token, id string) (users.User, error) {
+func (ms *metricsMiddleware) VerifyEmail(ctx context.Context, token string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("verify_email", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.VerifyEmail(ctx, token)
+}
+
+func (ms *metricsMiddleware) Invite(ctx context.Context, token string, inv users.Invitation) (i users.Invitation, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("invite", inv.Email)
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.Invite(ctx, token, inv)
+}
+
+func (ms *metricsMiddleware) AcceptInvitation(ctx context.Context, token, password string) (id string, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("accept_invitation", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AcceptInvitation(ctx, token, password)
+}
+
+func (ms *metricsMiddleware) ViewUser(ctx context.Context, token, id string) (u users.User, err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "view_user").Add(1)
-		ms.latency.With("method", "view_user").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("view_user", u.Email)
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.ViewUser(ctx, token, id)
 }
 
-func (ms *metricsMiddleware) ViewProfile(ctx context.Context, token string) (users.User, error) {
+func (ms *metricsMiddleware) ViewProfile(ctx context.Context, token string) (u users.User, err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "view_profile").Add(1)
-		ms.latency.With("method", "view_profile").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("view_profile", u.Email)
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.ViewProfile(ctx, token)
 }
 
-func (ms *metricsMiddleware) ListUsers(ctx context.Context, token string, offset, limit uint64, email string, um users.Metadata) (users.UserPage, error) {
+func (ms *metricsMiddleware) ListUsers(ctx context.Context, token string, pm users.PageMetadata) (up users.UserPage, err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "list_users").Add(1)
-		ms.latency.With("method", "list_users").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("list_users", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListUsers(ctx, token, offset, limit, email, um)
+	return ms.svc.ListUsers(ctx, token, pm)
 }
 
 func (ms *metricsMiddleware) UpdateUser(ctx context.Context, token string, u users.User) (err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "update_user").Add(1)
-		ms.latency.With("method", "update_user").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("update_user", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.UpdateUser(ctx, token, u)
 }
 
-func (ms *metricsMiddleware) GenerateResetToken(ctx context.Context, email, host string) error {
+func (ms *metricsMiddleware) UpdateProfile(ctx context.Context, token string, p users.Profile) (err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "generate_reset_token").Add(1)
-		ms.latency.With("method", "generate_reset_token").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("update_profile", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UpdateProfile(ctx, token, p)
+}
+
+func (ms *metricsMiddleware) GenerateResetToken(ctx context.Context, email, host string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("generate_reset_token", email)
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.GenerateResetToken(ctx, email, host)
 }
 
-func (ms *metricsMiddleware) ChangePassword(ctx context.Context, email, password, oldPassword string) error {
+func (ms *metricsMiddleware) ChangePassword(ctx context.Context, email, password, oldPassword string) (err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "change_password").Add(1)
-		ms.latency.With("method", "change_password").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("change_password", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.ChangePassword(ctx, email, password, oldPassword)
 }
 
-func (ms *metricsMiddleware) ResetPassword(ctx context.Context, email, password string) error {
+func (ms *metricsMiddleware) ResetPassword(ctx context.Context, email, password string) (err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "reset_password").Add(1)
-		ms.latency.With("method", "reset_password").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("reset_password", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.ResetPassword(ctx, email, password)
 }
 
-func (ms *metricsMiddleware) SendPasswordReset(ctx context.Context, host, email, token string) error {
+func (ms *metricsMiddleware) SendPasswordReset(ctx context.Context, host, email, token string) (err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "send_password_reset").Add(1)
-		ms.latency.With("method", "send_password_reset").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("send_password_reset", email)
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
 	return ms.svc.SendPasswordReset(ctx, host, email, token)
 }
 
-func (ms *metricsMiddleware) ListMembers(ctx context.Context, token, groupID string, offset, limit uint64, gm users.Metadata) (users.UserPage, error) {
+func (ms *metricsMiddleware) ListMembers(ctx context.Context, token, groupID string, pm users.PageMetadata) (up users.UserPage, err error) {
 	defer func(begin time.Time) {
-		ms.counter.With("method", "list_members").Add(1)
-		ms.latency.With("method", "list_members").Observe(time.Since(begin).Seconds())
+		lvs := ms.labels("list_members", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
 	}(time.Now())
 
-	return ms.svc.ListMembers(ctx, token, groupID, offset, limit, gm)
+	return ms.svc.ListMembers(ctx, token, groupID, pm)
+}
+
+func (ms *metricsMiddleware) ExportData(ctx context.Context, token string) (e users.Export, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("export_data", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExportData(ctx, token)
+}
+
+func (ms *metricsMiddleware) DownloadExport(ctx context.Context, id, signature string) (archive []byte, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("download_export", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DownloadExport(ctx, id, signature)
+}
+
+func (ms *metricsMiddleware) DisableUser(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("disable_user", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DisableUser(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) EnableUser(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("enable_user", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnableUser(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) UnlockUser(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("unlock_user", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.UnlockUser(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) AssignRole(ctx context.Context, token, id, role string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("assign_role", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.AssignRole(ctx, token, id, role)
+}
+
+func (ms *metricsMiddleware) RevokeRole(ctx context.Context, token, id, role string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("revoke_role", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.RevokeRole(ctx, token, id, role)
+}
+
+func (ms *metricsMiddleware) ListRoleMembers(ctx context.Context, token, role string, offset, limit uint64) (up users.UserPage, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("list_role_members", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListRoleMembers(ctx, token, role, offset, limit)
+}
+
+func (ms *metricsMiddleware) DeleteUser(ctx context.Context, token, id string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("delete_user", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.DeleteUser(ctx, token, id)
+}
+
+func (ms *metricsMiddleware) EnrollMFA(ctx context.Context, token string) (e users.Enrollment, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("enroll_mfa", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.EnrollMFA(ctx, token)
+}
+
+func (ms *metricsMiddleware) ConfirmMFA(ctx context.Context, token, code string) (err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("confirm_mfa", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ConfirmMFA(ctx, token, code)
+}
+
+func (ms *metricsMiddleware) LoginMFA(ctx context.Context, challenge, code string) (token, refresh string, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("login_mfa", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.LoginMFA(ctx, challenge, code)
+}
+
+func (ms *metricsMiddleware) OAuthURL(ctx context.Context, provider string) (url, state string, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("oauth_url", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OAuthURL(ctx, provider)
+}
+
+func (ms *metricsMiddleware) OAuthLogin(ctx context.Context, provider, code string) (token, refresh string, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("oauth_login", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.OAuthLogin(ctx, provider, code)
+}
+
+func (ms *metricsMiddleware) ListActivity(ctx context.Context, token string, offset, limit uint64) (ap users.ActivityPage, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("list_activity", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ListActivity(ctx, token, offset, limit)
+}
+
+func (ms *metricsMiddleware) ImportUsers(ctx context.Context, token string, records []users.UserRecord) (res []users.ImportResult, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("import_users", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ImportUsers(ctx, token, records)
+}
+
+func (ms *metricsMiddleware) ExportUsers(ctx context.Context, token string) (res []users.UserRecord, err error) {
+	defer func(begin time.Time) {
+		lvs := ms.labels("export_users", "")
+		ms.counter.With(lvs...).Add(1)
+		ms.latency.With(lvs...).Observe(time.Since(begin).Seconds())
+	}(time.Now())
+
+	return ms.svc.ExportUsers(ctx, token)
+}
+
+// labels returns the label values of the request. Tenant is reported for the
+// failed requests as well; the tenants made up by failed logins or
+// registrations are reported as other unless observed more than the tracked
+// ones.
+func (ms *metricsMiddleware) labels(method, email string) []string {
+	lvs := []string{"method", method}
+	if ms.tenants == nil {
+		return lvs
+	}
+
+	var tenant string
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		tenant = strings.ToLower(email[i+1:])
+	}
+	return append(lvs, "tenant", ms.tenants.Label(tenant))
 }
@@ -0,0 +1,61 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package api
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/internal/ratelimit"
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.Service = (*rateLimitMiddleware)(nil)
+
+// rateLimitMiddleware limits the rate of the login attempts, including the
+// attempts to complete the login with the MFA code, and of the password
+// reset requests, passing the other calls through to the embedded service.
+type rateLimitMiddleware struct {
+	users.Service
+	login *ratelimit.Limiter
+	reset *ratelimit.Limiter
+}
+
+// RateLimitMiddleware limits the rate of the login attempts per client IP
+// address and per email, so that the failed attempts are limited as well,
+// and the rate of the password reset requests. The client IP address is the
+// one the transport trusts, so it can't be forged to evade the limit.
+func RateLimitMiddleware(svc users.Service, login, reset *ratelimit.Limiter) users.Service {
+	return &rateLimitMiddleware{svc, login, reset}
+}
+
+func (rm *rateLimitMiddleware) Login(ctx context.Context, user users.User) (string, string, error) {
+	if err := rm.login.Allow(ctx, auth.ClientIP(ctx), user.Email); err != nil {
+		return "", "", err
+	}
+
+	return rm.Service.Login(ctx, user)
+}
+
+// LoginMFA limits the attempts per client IP address and per challenge, so
+// the one time codes can't be guessed.
+func (rm *rateLimitMiddleware) LoginMFA(ctx context.Context, challenge, code string) (string, string, error) {
+	if err := rm.login.Allow(ctx, auth.ClientIP(ctx), challenge); err != nil {
+		return "", "", err
+	}
+
+	return rm.Service.LoginMFA(ctx, challenge, code)
+}
+
+// GenerateResetToken limits the password reset requests per email, so the
+// reset emails can't be used to spam the user. The requests aren't limited
+// per client IP address, so the single client can't block the reset of
+// every other user.
+func (rm *rateLimitMiddleware) GenerateResetToken(ctx context.Context, email, host string) error {
+	if err := rm.reset.Allow(ctx, "", email); err != nil {
+		return err
+	}
+
+	return rm.Service.GenerateResetToken(ctx, email, host)
+}
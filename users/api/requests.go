@@ -4,10 +4,21 @@
 package api
 
 import (
+	"crypto/subtle"
+
 	groups "github.com/mainflux/mainflux/auth"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/users"
 )
 
+const (
+	emailOrder   = "email"
+	createdOrder = "created_at"
+	ascDir       = "asc"
+	descDir      = "desc"
+)
+
 type userReq struct {
 	user users.User
 }
@@ -37,33 +48,153 @@ func (req viewUserReq) validate() error {
 	return nil
 }
 
+type userIDReq struct {
+	token  string
+	userID string
+}
+
+func (req userIDReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.userID == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type roleReq struct {
+	token  string
+	userID string
+	role   string
+}
+
+func (req roleReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.userID == "" || req.role == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type listRoleMembersReq struct {
+	token  string
+	role   string
+	offset uint64
+	limit  uint64
+}
+
+func (req listRoleMembersReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.role == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type listActivityReq struct {
+	token  string
+	offset uint64
+	limit  uint64
+}
+
+func (req listActivityReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+type userRecordReq struct {
+	Email        string                 `json:"email"`
+	Password     string                 `json:"password,omitempty"`
+	PasswordHash string                 `json:"password_hash,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Groups       []string               `json:"groups,omitempty"`
+}
+
+type importUsersReq struct {
+	token string
+	Users []userRecordReq `json:"users"`
+}
+
+func (req importUsersReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if len(req.Users) == 0 || len(req.Users) > users.MaxImportUsers {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
 type listUsersReq struct {
-	token    string
-	offset   uint64
-	limit    uint64
-	email    string
-	metadata users.Metadata
+	token        string
+	pageMetadata users.PageMetadata
 }
 
 func (req listUsersReq) validate() error {
 	if req.token == "" {
 		return users.ErrUnauthorizedAccess
 	}
+	return validateCreated(req.pageMetadata)
+}
+
+// validateCreated rejects the registration time range ending before it
+// starts.
+func validateCreated(pm users.PageMetadata) error {
+	if !pm.CreatedFrom.IsZero() && !pm.CreatedTo.IsZero() && pm.CreatedFrom.After(pm.CreatedTo) {
+		return users.ErrMalformedEntity
+	}
 	return nil
 }
 
 type updateUserReq struct {
 	token    string
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Labels   labels.Labels          `json:"labels,omitempty"`
 }
 
 func (req updateUserReq) validate() error {
 	if req.token == "" {
 		return users.ErrUnauthorizedAccess
 	}
+	if err := req.Labels.Validate(); err != nil {
+		return errors.Wrap(users.ErrMalformedEntity, err)
+	}
 	return nil
 }
 
+type updateProfileReq struct {
+	token       string
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	DisplayName string `json:"display_name"`
+	Phone       string `json:"phone"`
+	Locale      string `json:"locale"`
+}
+
+func (req updateProfileReq) profile() users.Profile {
+	return users.Profile{
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		DisplayName: req.DisplayName,
+		Phone:       req.Phone,
+		Locale:      req.Locale,
+	}
+}
+
+func (req updateProfileReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	return req.profile().Validate()
+}
+
 type passwResetReq struct {
 	Email string `json:"email"`
 	Host  string `json:"host"`
@@ -112,11 +243,9 @@ func (req passwChangeReq) validate() error {
 }
 
 type listMemberGroupReq struct {
-	token    string
-	offset   uint64
-	limit    uint64
-	metadata users.Metadata
-	groupID  string
+	token        string
+	groupID      string
+	pageMetadata users.PageMetadata
 }
 
 func (req listMemberGroupReq) validate() error {
@@ -128,5 +257,118 @@ func (req listMemberGroupReq) validate() error {
 		return groups.ErrMalformedEntity
 	}
 
+	return validateCreated(req.pageMetadata)
+}
+
+type verifyEmailReq struct {
+	token string
+}
+
+func (req verifyEmailReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	return nil
+}
+
+type inviteReq struct {
+	token   string
+	Email   string `json:"email"`
+	GroupID string `json:"group_id,omitempty"`
+}
+
+func (req inviteReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.Email == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type acceptInvitationReq struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+	ConfPass string `json:"confirm_password"`
+}
+
+func (req acceptInvitationReq) validate() error {
+	if req.Token == "" || req.Password == "" || req.Password != req.ConfPass {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type oauthReq struct {
+	provider string
+}
+
+func (req oauthReq) validate() error {
+	if req.provider == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+// oauthCallbackReq is the callback of the identity provider. The state has
+// to match the state cookie, set by the redirect to the provider, so the
+// callback isn't forged to log in someone else's browser.
+type oauthCallbackReq struct {
+	provider string
+	code     string
+	state    string
+	cookie   string
+}
+
+func (req oauthCallbackReq) validate() error {
+	if req.state == "" || subtle.ConstantTimeCompare([]byte(req.state), []byte(req.cookie)) != 1 {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.provider == "" || req.code == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type confirmMFAReq struct {
+	token string
+	Code  string `json:"code"`
+}
+
+func (req confirmMFAReq) validate() error {
+	if req.token == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.Code == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type loginMFAReq struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+func (req loginMFAReq) validate() error {
+	if req.MFAToken == "" {
+		return users.ErrUnauthorizedAccess
+	}
+	if req.Code == "" {
+		return users.ErrMalformedEntity
+	}
+	return nil
+}
+
+type downloadExportReq struct {
+	id        string
+	signature string
+}
+
+func (req downloadExportReq) validate() error {
+	if req.id == "" || req.signature == "" {
+		return users.ErrInvalidSignature
+	}
 	return nil
 }
@@ -6,8 +6,10 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/users"
 )
 
 var (
@@ -21,6 +23,16 @@ var (
 	_ mainflux.Response = (*deleteRes)(nil)
 	_ mainflux.Response = (*assignUserToGroupRes)(nil)
 	_ mainflux.Response = (*removeUserFromGroupRes)(nil)
+	_ mainflux.Response = (*exportRes)(nil)
+	_ mainflux.Response = (*verifyEmailRes)(nil)
+	_ mainflux.Response = (*activityPageRes)(nil)
+	_ mainflux.Response = (*importUsersRes)(nil)
+	_ mainflux.Response = (*exportUsersRes)(nil)
+	_ mainflux.Response = (*oauthRedirectRes)(nil)
+	_ mainflux.Response = (*changeStatusRes)(nil)
+	_ mainflux.Response = (*enrollMFARes)(nil)
+	_ mainflux.Response = (*mfaChallengeRes)(nil)
+	_ mainflux.Response = (*inviteRes)(nil)
 )
 
 // MailSent message response when link is sent
@@ -60,7 +72,8 @@ func (res createUserRes) Empty() bool {
 }
 
 type tokenRes struct {
-	Token string `json:"token,omitempty"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
 func (res tokenRes) Code() int {
@@ -75,6 +88,91 @@ func (res tokenRes) Empty() bool {
 	return res.Token == ""
 }
 
+// mfaChallengeRes is returned by the login of the user enrolled in the MFA,
+// who completes the login with the challenge token and the one time code.
+type mfaChallengeRes struct {
+	MFAToken string `json:"mfa_token"`
+}
+
+func (res mfaChallengeRes) Code() int {
+	return http.StatusAccepted
+}
+
+func (res mfaChallengeRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res mfaChallengeRes) Empty() bool {
+	return false
+}
+
+// passwordExpiredRes is returned by the login of the user whose password
+// expired, who changes the password with the reset token before logging in.
+type passwordExpiredRes struct {
+	ResetToken      string `json:"reset_token"`
+	PasswordExpired bool   `json:"password_expired"`
+}
+
+func (res passwordExpiredRes) Code() int {
+	return http.StatusAccepted
+}
+
+func (res passwordExpiredRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res passwordExpiredRes) Empty() bool {
+	return false
+}
+
+// oauthRedirectRes redirects the user to the identity provider, setting the
+// state cookie the callback is checked against.
+type oauthRedirectRes struct {
+	url   string
+	state string
+}
+
+func (res oauthRedirectRes) Code() int {
+	return http.StatusFound
+}
+
+func (res oauthRedirectRes) Headers() map[string]string {
+	cookie := http.Cookie{
+		Name:     stateCookie,
+		Value:    res.state,
+		Path:     loginPath,
+		MaxAge:   int(stateDuration.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	return map[string]string{
+		"Location":   res.url,
+		"Set-Cookie": cookie.String(),
+	}
+}
+
+func (res oauthRedirectRes) Empty() bool {
+	return true
+}
+
+type enrollMFARes struct {
+	URI           string   `json:"uri"`
+	Secret        string   `json:"secret"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (res enrollMFARes) Code() int {
+	return http.StatusCreated
+}
+
+func (res enrollMFARes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res enrollMFARes) Empty() bool {
+	return false
+}
+
 type updateUserRes struct{}
 
 func (res updateUserRes) Code() int {
@@ -90,9 +188,17 @@ func (res updateUserRes) Empty() bool {
 }
 
 type viewUserRes struct {
-	ID       string                 `json:"id"`
-	Email    string                 `json:"email"`
-	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	ID          string                 `json:"id"`
+	Email       string                 `json:"email"`
+	FirstName   string                 `json:"first_name,omitempty"`
+	LastName    string                 `json:"last_name,omitempty"`
+	DisplayName string                 `json:"display_name,omitempty"`
+	Phone       string                 `json:"phone,omitempty"`
+	Locale      string                 `json:"locale,omitempty"`
+	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	Labels      map[string]string      `json:"labels,omitempty"`
+	Status      string                 `json:"status"`
+	CreatedAt   time.Time              `json:"created_at"`
 }
 
 func (res viewUserRes) Code() int {
@@ -124,6 +230,79 @@ func (res userPageRes) Empty() bool {
 	return false
 }
 
+type loginAttemptRes struct {
+	ID        string    `json:"id"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type activityPageRes struct {
+	pageRes
+	Attempts []loginAttemptRes `json:"attempts"`
+}
+
+func (res activityPageRes) Code() int {
+	return http.StatusOK
+}
+
+func (res activityPageRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res activityPageRes) Empty() bool {
+	return false
+}
+
+type importResultRes struct {
+	Email    string `json:"email"`
+	ID       string `json:"id,omitempty"`
+	Password string `json:"password,omitempty"`
+	Err      string `json:"error,omitempty"`
+}
+
+type importUsersRes struct {
+	Users []importResultRes `json:"users"`
+}
+
+func (res importUsersRes) Code() int {
+	return http.StatusOK
+}
+
+func (res importUsersRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res importUsersRes) Empty() bool {
+	return false
+}
+
+type userRecordRes struct {
+	ID           string                 `json:"id"`
+	Email        string                 `json:"email"`
+	PasswordHash string                 `json:"password_hash"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Groups       []string               `json:"groups"`
+}
+
+type exportUsersRes struct {
+	Users []userRecordRes `json:"users"`
+}
+
+func (res exportUsersRes) Code() int {
+	return http.StatusOK
+}
+
+func (res exportUsersRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res exportUsersRes) Empty() bool {
+	return false
+}
+
 type createGroupRes struct {
 	ID          string                 `json:"id"`
 	Name        string                 `json:"name,omitempty"`
@@ -193,6 +372,29 @@ type errorRes struct {
 	Err string `json:"error"`
 }
 
+type passwordErrorRes struct {
+	Err   string   `json:"error"`
+	Rules []string `json:"rules"`
+}
+
+type violationRes struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type metadataErrorRes struct {
+	Err        string         `json:"error"`
+	Violations []violationRes `json:"violations"`
+}
+
+func newMetadataErrorRes(msg string, me *users.MetadataError) metadataErrorRes {
+	res := metadataErrorRes{Err: msg}
+	for _, v := range me.Violations {
+		res.Violations = append(res.Violations, violationRes{Field: v.Field, Description: v.Description})
+	}
+	return res
+}
+
 type passwResetReqRes struct {
 	Msg string `json:"msg"`
 }
@@ -224,6 +426,34 @@ func (res passwChangeRes) Empty() bool {
 	return false
 }
 
+type verifyEmailRes struct{}
+
+func (res verifyEmailRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res verifyEmailRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res verifyEmailRes) Empty() bool {
+	return true
+}
+
+type changeStatusRes struct{}
+
+func (res changeStatusRes) Code() int {
+	return http.StatusNoContent
+}
+
+func (res changeStatusRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res changeStatusRes) Empty() bool {
+	return true
+}
+
 type deleteRes struct{}
 
 func (res deleteRes) Code() int {
@@ -265,3 +495,53 @@ func (res removeUserFromGroupRes) Headers() map[string]string {
 func (res removeUserFromGroupRes) Empty() bool {
 	return true
 }
+
+type exportRes struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	DownloadURL string     `json:"download_url,omitempty"`
+}
+
+func (res exportRes) Code() int {
+	if res.Status == users.ExportPending {
+		return http.StatusAccepted
+	}
+	return http.StatusOK
+}
+
+func (res exportRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res exportRes) Empty() bool {
+	return false
+}
+
+type inviteRes struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	GroupID   string    `json:"group_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (res inviteRes) Code() int {
+	return http.StatusCreated
+}
+
+func (res inviteRes) Headers() map[string]string {
+	return map[string]string{}
+}
+
+func (res inviteRes) Empty() bool {
+	return false
+}
+
+type archiveRes struct {
+	id      string
+	archive []byte
+}
@@ -6,15 +6,19 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	kitot "github.com/go-kit/kit/tracing/opentracing"
 	kithttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-zoo/bone"
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/internal/httputil"
+	"github.com/mainflux/mainflux/internal/i18n"
+	"github.com/mainflux/mainflux/internal/ratelimit"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/users"
 	opentracing "github.com/opentracing/opentracing-go"
@@ -22,19 +26,38 @@ import (
 )
 
 const (
-	contentType = "application/json"
-	offsetKey   = "offset"
-	limitKey    = "limit"
-	emailKey    = "email"
-	metadataKey = "metadata"
-	defOffset   = 0
-	defLimit    = 10
+	contentType  = "application/json"
+	offsetKey    = "offset"
+	limitKey     = "limit"
+	emailKey     = "email"
+	metadataKey  = "metadata"
+	selectorKey  = "selector"
+	signatureKey = "signature"
+	tokenKey     = "token"
+	codeKey      = "code"
+	stateKey     = "state"
+	statusKey    = "status"
+	fromKey      = "created_from"
+	toKey        = "created_to"
+	orderKey     = "order"
+	dirKey       = "dir"
+	stateCookie  = "oauth_state"
+	loginPath    = "/login"
+	defOffset    = 0
+	defLimit     = 10
+
+	// stateDuration is the time the user has to log in at the identity
+	// provider.
+	stateDuration = 10 * time.Minute
 )
 
 // MakeHandler returns a HTTP handler for API endpoints.
 func MakeHandler(svc users.Service, tracer opentracing.Tracer) http.Handler {
 	opts := []kithttp.ServerOption{
 		kithttp.ServerErrorEncoder(encodeError),
+		kithttp.ServerBefore(i18n.PopulateRequestContext),
+		kithttp.ServerBefore(httputil.PopulateClientIP),
+		kithttp.ServerBefore(httputil.PopulateUserAgent),
 	}
 
 	mux := bone.New()
@@ -53,6 +76,55 @@ func MakeHandler(svc users.Service, tracer opentracing.Tracer) http.Handler {
 		opts...,
 	))
 
+	mux.Put("/users/profile", kithttp.NewServer(
+		kitot.TraceServer(tracer, "update_profile")(updateProfileEndpoint(svc)),
+		decodeUpdateProfile,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/users/activity", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_activity")(listActivityEndpoint(svc)),
+		decodeListActivity,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/users/import", kithttp.NewServer(
+		kitot.TraceServer(tracer, "import_users")(importUsersEndpoint(svc)),
+		decodeImportUsers,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/users/export", kithttp.NewServer(
+		kitot.TraceServer(tracer, "export_users")(exportUsersEndpoint(svc)),
+		decodeViewProfile,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/users/verify", kithttp.NewServer(
+		kitot.TraceServer(tracer, "verify_email")(verifyEmailEndpoint(svc)),
+		decodeVerifyEmail,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/invitations", kithttp.NewServer(
+		kitot.TraceServer(tracer, "invite")(inviteEndpoint(svc)),
+		decodeInvite,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/invitations/accept", kithttp.NewServer(
+		kitot.TraceServer(tracer, "accept_invitation")(acceptInvitationEndpoint(svc)),
+		decodeAcceptInvitation,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Get("/users/:userID", kithttp.NewServer(
 		kitot.TraceServer(tracer, "view_user")(viewUserEndpoint(svc)),
 		decodeViewUser,
@@ -60,6 +132,55 @@ func MakeHandler(svc users.Service, tracer opentracing.Tracer) http.Handler {
 		opts...,
 	))
 
+	mux.Post("/users/:userID/disable", kithttp.NewServer(
+		kitot.TraceServer(tracer, "disable_user")(disableUserEndpoint(svc)),
+		decodeUserID,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/users/:userID/enable", kithttp.NewServer(
+		kitot.TraceServer(tracer, "enable_user")(enableUserEndpoint(svc)),
+		decodeUserID,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/users/:userID/unlock", kithttp.NewServer(
+		kitot.TraceServer(tracer, "unlock_user")(unlockUserEndpoint(svc)),
+		decodeUserID,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Put("/users/:userID/roles/:role", kithttp.NewServer(
+		kitot.TraceServer(tracer, "assign_role")(assignRoleEndpoint(svc)),
+		decodeRole,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/users/:userID/roles/:role", kithttp.NewServer(
+		kitot.TraceServer(tracer, "revoke_role")(revokeRoleEndpoint(svc)),
+		decodeRole,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/roles/:role/users", kithttp.NewServer(
+		kitot.TraceServer(tracer, "list_role_members")(listRoleMembersEndpoint(svc)),
+		decodeListRoleMembers,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Delete("/users/:userID", kithttp.NewServer(
+		kitot.TraceServer(tracer, "delete_user")(deleteUserEndpoint(svc)),
+		decodeUserID,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.Get("/users", kithttp.NewServer(
 		kitot.TraceServer(tracer, "list_users")(listUsersEndpoint(svc)),
 		decodeListUsers,
@@ -102,6 +223,20 @@ func MakeHandler(svc users.Service, tracer opentracing.Tracer) http.Handler {
 		opts...,
 	))
 
+	mux.Get("/users/me/export", kithttp.NewServer(
+		kitot.TraceServer(tracer, "export_data")(exportDataEndpoint(svc)),
+		decodeViewProfile,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/users/exports/:exportID", kithttp.NewServer(
+		kitot.TraceServer(tracer, "download_export")(downloadExportEndpoint(svc)),
+		decodeDownloadExport,
+		encodeArchive,
+		opts...,
+	))
+
 	mux.Post("/tokens", kithttp.NewServer(
 		kitot.TraceServer(tracer, "login")(loginEndpoint(svc)),
 		decodeCredentials,
@@ -109,6 +244,41 @@ func MakeHandler(svc users.Service, tracer opentracing.Tracer) http.Handler {
 		opts...,
 	))
 
+	mux.Post("/tokens/mfa", kithttp.NewServer(
+		kitot.TraceServer(tracer, "login_mfa")(loginMFAEndpoint(svc)),
+		decodeLoginMFA,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/mfa/enroll", kithttp.NewServer(
+		kitot.TraceServer(tracer, "enroll_mfa")(enrollMFAEndpoint(svc)),
+		decodeViewProfile,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Post("/mfa/confirm", kithttp.NewServer(
+		kitot.TraceServer(tracer, "confirm_mfa")(confirmMFAEndpoint(svc)),
+		decodeConfirmMFA,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/login/:provider", kithttp.NewServer(
+		kitot.TraceServer(tracer, "oauth")(oauthEndpoint(svc)),
+		decodeOAuth,
+		encodeResponse,
+		opts...,
+	))
+
+	mux.Get("/login/:provider/callback", kithttp.NewServer(
+		kitot.TraceServer(tracer, "oauth_callback")(oauthCallbackEndpoint(svc)),
+		decodeOAuthCallback,
+		encodeResponse,
+		opts...,
+	))
+
 	mux.GetFunc("/version", mainflux.Version("users"))
 	mux.Handle("/metrics", promhttp.Handler())
 
@@ -123,14 +293,24 @@ func decodeViewUser(_ context.Context, r *http.Request) (interface{}, error) {
 	return req, nil
 }
 
-func decodeViewProfile(_ context.Context, r *http.Request) (interface{}, error) {
-	req := viewUserReq{
-		token: r.Header.Get("Authorization"),
+func decodeUserID(_ context.Context, r *http.Request) (interface{}, error) {
+	req := userIDReq{
+		token:  r.Header.Get("Authorization"),
+		userID: bone.GetValue(r, "userID"),
 	}
 	return req, nil
 }
 
-func decodeListUsers(_ context.Context, r *http.Request) (interface{}, error) {
+func decodeRole(_ context.Context, r *http.Request) (interface{}, error) {
+	req := roleReq{
+		token:  r.Header.Get("Authorization"),
+		userID: bone.GetValue(r, "userID"),
+		role:   bone.GetValue(r, "role"),
+	}
+	return req, nil
+}
+
+func decodeListRoleMembers(_ context.Context, r *http.Request) (interface{}, error) {
 	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
 	if err != nil {
 		return nil, err
@@ -141,26 +321,121 @@ func decodeListUsers(_ context.Context, r *http.Request) (interface{}, error) {
 		return nil, err
 	}
 
-	e, err := httputil.ReadStringQuery(r, emailKey, "")
+	req := listRoleMembersReq{
+		token:  r.Header.Get("Authorization"),
+		role:   bone.GetValue(r, "role"),
+		offset: o,
+		limit:  l,
+	}
+	return req, nil
+}
+
+func decodeListActivity(_ context.Context, r *http.Request) (interface{}, error) {
+	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
 	if err != nil {
 		return nil, err
 	}
 
-	m, err := httputil.ReadMetadataQuery(r, metadataKey, nil)
+	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	req := listActivityReq{
+		token:  r.Header.Get("Authorization"),
+		offset: o,
+		limit:  l,
+	}
+	return req, nil
+}
+
+func decodeViewProfile(_ context.Context, r *http.Request) (interface{}, error) {
+	req := viewUserReq{
+		token: r.Header.Get("Authorization"),
+	}
+	return req, nil
+}
+
+func decodeListUsers(_ context.Context, r *http.Request) (interface{}, error) {
+	pm, err := readPageMetadata(r)
 	if err != nil {
 		return nil, err
 	}
 
 	req := listUsersReq{
-		token:    r.Header.Get("Authorization"),
-		offset:   o,
-		limit:    l,
-		email:    e,
-		metadata: m,
+		token:        r.Header.Get("Authorization"),
+		pageMetadata: pm,
 	}
 	return req, nil
 }
 
+// readPageMetadata reads the page, the filters and the order of the listed
+// users from the query.
+func readPageMetadata(r *http.Request) (users.PageMetadata, error) {
+	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	e, err := httputil.ReadStringQuery(r, emailKey, "")
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	m, err := httputil.ReadMetadataQuery(r, metadataKey, nil)
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	s, err := httputil.ReadSelectorQuery(r, selectorKey)
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	st, err := httputil.ReadEnumQuery(r, statusKey, "", users.StatusPending, users.StatusActive, users.StatusDisabled, users.StatusLocked)
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	from, err := httputil.ReadTimeQuery(r, fromKey, time.Time{})
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	to, err := httputil.ReadTimeQuery(r, toKey, time.Time{})
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	or, err := httputil.ReadEnumQuery(r, orderKey, "", emailOrder, createdOrder)
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	d, err := httputil.ReadEnumQuery(r, dirKey, "", ascDir, descDir)
+	if err != nil {
+		return users.PageMetadata{}, err
+	}
+
+	return users.PageMetadata{
+		Offset:      o,
+		Limit:       l,
+		Email:       e,
+		Metadata:    m,
+		Selector:    s,
+		Status:      st,
+		CreatedFrom: from,
+		CreatedTo:   to,
+		Order:       or,
+		Dir:         d,
+	}, nil
+}
+
 func decodeUpdateUser(_ context.Context, r *http.Request) (interface{}, error) {
 	var req updateUserReq
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -197,6 +472,20 @@ func decodeCreateUserReq(_ context.Context, r *http.Request) (interface{}, error
 	return createUserReq{user, r.Header.Get("Authorization")}, nil
 }
 
+func decodeUpdateProfile(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	var req updateProfileReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(users.ErrMalformedEntity, err)
+	}
+
+	req.token = r.Header.Get("Authorization")
+	return req, nil
+}
+
 func decodePasswordResetRequest(_ context.Context, r *http.Request) (interface{}, error) {
 	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
 		return nil, errors.ErrUnsupportedContentType
@@ -241,31 +530,128 @@ func decodePasswordChange(_ context.Context, r *http.Request) (interface{}, erro
 }
 
 func decodeListMembersRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	o, err := httputil.ReadUintQuery(r, offsetKey, defOffset)
+	pm, err := readPageMetadata(r)
 	if err != nil {
 		return nil, err
 	}
 
-	l, err := httputil.ReadUintQuery(r, limitKey, defLimit)
-	if err != nil {
-		return nil, err
+	req := listMemberGroupReq{
+		token:        r.Header.Get("Authorization"),
+		groupID:      bone.GetValue(r, "groupId"),
+		pageMetadata: pm,
 	}
+	return req, nil
+}
 
-	m, err := httputil.ReadMetadataQuery(r, metadataKey, nil)
-	if err != nil {
-		return nil, err
+func decodeLoginMFA(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
 	}
 
-	req := listMemberGroupReq{
-		token:    r.Header.Get("Authorization"),
-		groupID:  bone.GetValue(r, "groupId"),
-		offset:   o,
-		limit:    l,
-		metadata: m,
+	var req loginMFAReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeConfirmMFA(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	var req confirmMFAReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	req.token = r.Header.Get("Authorization")
+	return req, nil
+}
+
+func decodeOAuth(_ context.Context, r *http.Request) (interface{}, error) {
+	req := oauthReq{
+		provider: bone.GetValue(r, "provider"),
 	}
 	return req, nil
 }
 
+func decodeOAuthCallback(_ context.Context, r *http.Request) (interface{}, error) {
+	req := oauthCallbackReq{
+		provider: bone.GetValue(r, "provider"),
+		code:     r.URL.Query().Get(codeKey),
+		state:    r.URL.Query().Get(stateKey),
+	}
+	if c, err := r.Cookie(stateCookie); err == nil {
+		req.cookie = c.Value
+	}
+	return req, nil
+}
+
+func decodeVerifyEmail(_ context.Context, r *http.Request) (interface{}, error) {
+	req := verifyEmailReq{
+		token: r.URL.Query().Get(tokenKey),
+	}
+	return req, nil
+}
+
+func decodeImportUsers(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := importUsersReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeInvite(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	req := inviteReq{token: r.Header.Get("Authorization")}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeAcceptInvitation(_ context.Context, r *http.Request) (interface{}, error) {
+	if !strings.Contains(r.Header.Get("Content-Type"), contentType) {
+		return nil, errors.ErrUnsupportedContentType
+	}
+
+	var req acceptInvitationReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, errors.Wrap(errors.ErrMalformedEntity, err)
+	}
+
+	return req, nil
+}
+
+func decodeDownloadExport(_ context.Context, r *http.Request) (interface{}, error) {
+	req := downloadExportReq{
+		id:        bone.GetValue(r, "exportID"),
+		signature: r.URL.Query().Get(signatureKey),
+	}
+	return req, nil
+}
+
+func encodeArchive(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	res := response.(archiveRes)
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="mainflux-export-%s.zip"`, res.id))
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(res.archive)
+	return err
+}
+
 func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
 	if ar, ok := response.(mainflux.Response); ok {
 		for k, v := range ar.Headers() {
@@ -282,7 +668,16 @@ func encodeResponse(_ context.Context, w http.ResponseWriter, response interface
 	return json.NewEncoder(w).Encode(response)
 }
 
-func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+// errorMessage returns the localized message of the error reported in the
+// response body rather than the status.
+func errorMessage(ctx context.Context, err error) string {
+	if e, ok := err.(errors.Error); ok && e.Msg() != "" {
+		return i18n.Localize(ctx, e.Msg())
+	}
+	return i18n.Localize(ctx, err.Error())
+}
+
+func encodeError(ctx context.Context, err error, w http.ResponseWriter) {
 	switch errorVal := err.(type) {
 	case errors.Error:
 		w.Header().Set("Content-Type", contentType)
@@ -295,6 +690,26 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 			w.WriteHeader(http.StatusForbidden)
 		case errors.Contains(errorVal, users.ErrAuthorization):
 			w.WriteHeader(http.StatusForbidden)
+		case errors.Contains(errorVal, users.ErrInvalidSignature):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Contains(errorVal, users.ErrEmailNotVerified):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Contains(errorVal, users.ErrUserDisabled):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Contains(errorVal, users.ErrUserLocked):
+			w.WriteHeader(http.StatusLocked)
+		case errors.Contains(errorVal, users.ErrInvalidMFACode):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Contains(errorVal, users.ErrMFANotEnrolled):
+			w.WriteHeader(http.StatusForbidden)
+		case errors.Contains(errorVal, users.ErrUnknownProvider):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Contains(errorVal, users.ErrInvalidRole):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Contains(errorVal, users.ErrLastAdmin):
+			w.WriteHeader(http.StatusConflict)
+		case errors.Contains(errorVal, users.ErrMFAEnrolled):
+			w.WriteHeader(http.StatusConflict)
 		case errors.Contains(errorVal, users.ErrConflict):
 			w.WriteHeader(http.StatusConflict)
 		case errors.Contains(errorVal, users.ErrGroupConflict):
@@ -311,13 +726,33 @@ func encodeError(_ context.Context, err error, w http.ResponseWriter) {
 			w.WriteHeader(http.StatusBadRequest)
 		case errors.Contains(errorVal, users.ErrRecoveryToken):
 			w.WriteHeader(http.StatusNotFound)
+		case errors.Contains(errorVal, users.ErrInvalidInvitation):
+			w.WriteHeader(http.StatusNotFound)
 		case errors.Contains(errorVal, users.ErrPasswordFormat):
 			w.WriteHeader(http.StatusBadRequest)
+		case errors.Contains(errorVal, users.ErrPasswordReused):
+			w.WriteHeader(http.StatusBadRequest)
+		case errors.Contains(errorVal, users.ErrValidation):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+		case errors.Contains(errorVal, ratelimit.ErrLimitExceeded):
+			w.WriteHeader(http.StatusTooManyRequests)
 		default:
 			w.WriteHeader(http.StatusInternalServerError)
 		}
+		if pe, ok := errorVal.(*users.PasswordError); ok {
+			if err := json.NewEncoder(w).Encode(passwordErrorRes{Err: i18n.Localize(ctx, pe.Msg()), Rules: pe.Rules}); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+		if me, ok := errorVal.(*users.MetadataError); ok {
+			if err := json.NewEncoder(w).Encode(newMetadataErrorRes(i18n.Localize(ctx, me.Msg()), me)); err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
 		if errorVal.Msg() != "" {
-			if err := json.NewEncoder(w).Encode(errorRes{Err: errorVal.Msg()}); err != nil {
+			if err := json.NewEncoder(w).Encode(errorRes{Err: i18n.Localize(ctx, errorVal.Msg())}); err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
 			}
 		}
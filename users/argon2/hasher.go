@@ -0,0 +1,126 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package argon2 provides a hasher implementation utilizing Argon2id.
+package argon2
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	prefix  = "$argon2id$"
+	saltLen = 16
+	keyLen  = 32
+)
+
+var (
+	errHashPassword    = errors.New("Generate hash from password failed")
+	errComparePassword = errors.New("Compare hash and password failed")
+	errMalformedHash   = errors.New("Malformed Argon2id hash")
+)
+
+// Params are the Argon2id parameters the new hashes are generated with.
+type Params struct {
+	// Time is the number of passes over the memory.
+	Time uint32
+
+	// Memory is the size of the memory in KiB.
+	Memory uint32
+
+	// Threads is the number of threads the hash is computed with.
+	Threads uint8
+}
+
+var _ users.Hasher = (*argon2Hasher)(nil)
+
+type argon2Hasher struct {
+	params Params
+	legacy users.Hasher
+}
+
+// New instantiates an Argon2id-based hasher implementation, generating the
+// hashes with the given parameters. The hashes made with the other algorithm
+// are compared with the legacy hasher, if set, and are reported to be
+// rehashed.
+func New(params Params, legacy users.Hasher) users.Hasher {
+	return &argon2Hasher{
+		params: params,
+		legacy: legacy,
+	}
+}
+
+func (ah *argon2Hasher) Hash(pwd string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrap(errHashPassword, err)
+	}
+	key := argon2.IDKey([]byte(pwd), salt, ah.params.Time, ah.params.Memory, ah.params.Threads, keyLen)
+
+	return encode(ah.params, salt, key), nil
+}
+
+func (ah *argon2Hasher) Compare(plain, hashed string) error {
+	if !strings.HasPrefix(hashed, prefix) {
+		if ah.legacy == nil {
+			return errors.Wrap(errComparePassword, errMalformedHash)
+		}
+		return ah.legacy.Compare(plain, hashed)
+	}
+
+	p, salt, key, err := decode(hashed)
+	if err != nil {
+		return errors.Wrap(errComparePassword, err)
+	}
+	other := argon2.IDKey([]byte(plain), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return errComparePassword
+	}
+	return nil
+}
+
+func (ah *argon2Hasher) NeedsRehash(hashed string) bool {
+	p, salt, key, err := decode(hashed)
+	return err != nil || p != ah.params || len(salt) != saltLen || len(key) != keyLen
+}
+
+// encode encodes the hash in the PHC string format, e.g.
+// $argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>.
+func encode(p Params, salt, key []byte) string {
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", prefix, argon2.Version, p.Memory, p.Time, p.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key))
+}
+
+func decode(hashed string) (Params, []byte, []byte, error) {
+	parts := strings.Split(strings.TrimPrefix(hashed, prefix), "$")
+	if !strings.HasPrefix(hashed, prefix) || len(parts) != 4 {
+		return Params{}, nil, nil, errMalformedHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return Params{}, nil, nil, errMalformedHash
+	}
+	var p Params
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil || p.Time == 0 || p.Threads == 0 {
+		return Params{}, nil, nil, errMalformedHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return Params{}, nil, nil, errMalformedHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(key) == 0 {
+		return Params{}, nil, nil, errMalformedHash
+	}
+
+	return p, salt, key, nil
+}
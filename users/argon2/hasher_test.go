@@ -0,0 +1,83 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package argon2_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/argon2"
+	"github.com/mainflux/mainflux/users/bcrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const password = "password"
+
+var params = argon2.Params{Time: 1, Memory: 1024, Threads: 1}
+
+func TestCompare(t *testing.T) {
+	legacy := bcrypt.New()
+	hasher := argon2.New(params, legacy)
+
+	hash, err := hasher.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	other, err := hasher.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotEqual(t, hash, other, "hashes of the same password expected to be salted")
+
+	legacyHash, err := legacy.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		hasher users.Hasher
+		plain  string
+		hash   string
+		valid  bool
+	}{
+		{"compare password", hasher, password, hash, true},
+		{"compare wrong password", hasher, "wrong", hash, false},
+		{"compare password with legacy hash", hasher, password, legacyHash, true},
+		{"compare wrong password with legacy hash", hasher, "wrong", legacyHash, false},
+		{"compare password with legacy hash and no legacy hasher", argon2.New(params, nil), password, legacyHash, false},
+		{"compare password with malformed hash", hasher, password, "$argon2id$v=19$m=1024,t=1,p=1$salt", false},
+		{"compare password with hash of other parameters", argon2.New(argon2.Params{Time: 2, Memory: 2048, Threads: 2}, nil), password, hash, true},
+	}
+
+	for _, tc := range cases {
+		err := tc.hasher.Compare(tc.plain, tc.hash)
+		assert.Equal(t, tc.valid, err == nil, fmt.Sprintf("%s: expected valid %t got error %s", tc.desc, tc.valid, err))
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	hasher := argon2.New(params, bcrypt.New())
+
+	hash, err := hasher.Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"), fmt.Sprintf("unexpected hash format %s", hash))
+
+	legacyHash, err := bcrypt.New().Hash(password)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		hasher users.Hasher
+		hash   string
+		rehash bool
+	}{
+		{"hash with current parameters", hasher, hash, false},
+		{"hash with other parameters", argon2.New(argon2.Params{Time: 2, Memory: 1024, Threads: 1}, nil), hash, true},
+		{"legacy hash", hasher, legacyHash, true},
+		{"malformed hash", hasher, "$argon2id$", true},
+	}
+
+	for _, tc := range cases {
+		rehash := tc.hasher.NeedsRehash(tc.hash)
+		assert.Equal(t, tc.rehash, rehash, fmt.Sprintf("%s: expected rehash %t got %t", tc.desc, tc.rehash, rehash))
+	}
+}
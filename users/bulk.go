@@ -0,0 +1,42 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+// MaxImportUsers is the maximal number of the users imported at once.
+const MaxImportUsers = 1000
+
+// UserRecord is the user account as imported and exported in bulk, used to
+// migrate the users between the deployments.
+type UserRecord struct {
+	ID    string
+	Email string
+
+	// Password is the plain text password of the imported user. Unless the
+	// password or its hash is given, the password is generated.
+	Password string
+
+	// PasswordHash is the hash of the password made by the hasher of the
+	// users service, so it's imported as is. The exported users carry the
+	// password hash only.
+	PasswordHash string
+
+	Metadata Metadata
+
+	// Groups are the IDs of the user groups the user is a member of.
+	Groups []string
+}
+
+// ImportResult is the outcome of the import of the single user.
+type ImportResult struct {
+	Email string
+	ID    string
+
+	// Password is the generated password of the user, empty unless the
+	// password was generated.
+	Password string
+
+	// Err is the reason the user wasn't imported, or wasn't assigned to
+	// all of its groups.
+	Err error
+}
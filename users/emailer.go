@@ -3,7 +3,21 @@
 
 package users
 
+import "context"
+
 // Emailer wrapper around the email
 type Emailer interface {
-	SendPasswordReset(To []string, host, token string) error
+	SendPasswordReset(ctx context.Context, To []string, host, token string) error
+
+	// SendVerification sends the email verification link carrying the token
+	// to the self registered user.
+	SendVerification(ctx context.Context, To []string, token string) error
+
+	// SendInvitation sends the invitation link carrying the token to the
+	// invited user, naming the inviter.
+	SendInvitation(ctx context.Context, To []string, inviter, token string) error
+
+	// SendLoginAlert notifies the user of the login from the new IP
+	// address, using the client with the user agent.
+	SendLoginAlert(ctx context.Context, To []string, ip, userAgent string) error
 }
@@ -3,26 +3,50 @@
 package emailer
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/mainflux/mainflux/internal/email"
+	"github.com/mainflux/mainflux/internal/i18n"
 	"github.com/mainflux/mainflux/users"
 )
 
 var _ users.Emailer = (*emailer)(nil)
 
 type emailer struct {
-	resetURL string
-	agent    *email.Agent
+	resetURL  string
+	verifyURL string
+	inviteURL string
+	agent     *email.Agent
 }
 
-// New creates new emailer utility
-func New(url string, c *email.Config) (users.Emailer, error) {
+// New creates new emailer utility. The reset URL is the path of the password
+// reset link on the host of the request, while the verify and the invite
+// URLs are the full URLs of the email verification and the invitation links.
+func New(resetURL, verifyURL, inviteURL string, c *email.Config) (users.Emailer, error) {
 	e, err := email.New(c)
-	return &emailer{resetURL: url, agent: e}, err
+	return &emailer{resetURL: resetURL, verifyURL: verifyURL, inviteURL: inviteURL, agent: e}, err
 }
 
-func (e *emailer) SendPasswordReset(To []string, host string, token string) error {
+func (e *emailer) SendPasswordReset(ctx context.Context, To []string, host string, token string) error {
 	url := fmt.Sprintf("%s%s?token=%s", host, e.resetURL, token)
-	return e.agent.Send(To, "", "Password reset", "", url, "")
+	return e.agent.SendLocalized(i18n.Language(ctx), To, "", i18n.Localize(ctx, "Password reset"), "", url, "")
+}
+
+func (e *emailer) SendVerification(ctx context.Context, To []string, token string) error {
+	url := fmt.Sprintf("%s?token=%s", e.verifyURL, token)
+	return e.agent.SendLocalized(i18n.Language(ctx), To, "", i18n.Localize(ctx, "Email verification"), "", url, "")
+}
+
+func (e *emailer) SendInvitation(ctx context.Context, To []string, inviter, token string) error {
+	url := fmt.Sprintf("%s?token=%s", e.inviteURL, token)
+	header := fmt.Sprintf(i18n.Localize(ctx, "Invitation from %s"), inviter)
+	return e.agent.SendLocalized(i18n.Language(ctx), To, "", i18n.Localize(ctx, "Invitation"), header, url, "")
+}
+
+func (e *emailer) SendLoginAlert(ctx context.Context, To []string, ip, userAgent string) error {
+	header := fmt.Sprintf(i18n.Localize(ctx, "New login from %s"), ip)
+	content := fmt.Sprintf(i18n.Localize(ctx, "Device: %s"), userAgent)
+	footer := i18n.Localize(ctx, "If this wasn't you, change your password and log out other devices.")
+	return e.agent.SendLocalized(i18n.Language(ctx), To, "", i18n.Localize(ctx, "New login"), header, content, footer)
 }
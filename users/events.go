@@ -0,0 +1,49 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"time"
+)
+
+// The types of the user lifecycle events.
+const (
+	EventUserCreated  = "user.created"
+	EventUserUpdated  = "user.updated"
+	EventUserDisabled = "user.disabled"
+	EventUserEnabled  = "user.enabled"
+	EventUserDeleted  = "user.deleted"
+)
+
+// Event is the change of the user account, notified to the external
+// systems, such as the CRMs, so they stay in sync with the users.
+type Event struct {
+	// ID uniquely identifies the event, so the receivers can recognize the
+	// event delivered more than once.
+	ID   string
+	Type string
+	// UserID, Email and Metadata describe the user the event is about. The
+	// email and the metadata are empty if the user couldn't be retrieved.
+	UserID     string
+	Email      string
+	Metadata   Metadata
+	OccurredAt time.Time
+}
+
+// EventNotifier notifies the user lifecycle events. The events are notified
+// once the change is made, and the notification is best effort: it doesn't
+// affect the change, so the implementations handle the failures themselves.
+type EventNotifier interface {
+	Notify(ctx context.Context, event Event)
+}
+
+type nopNotifier struct{}
+
+// NewNopEventNotifier returns the event notifier discarding all the events.
+func NewNopEventNotifier() EventNotifier {
+	return nopNotifier{}
+}
+
+func (nopNotifier) Notify(context.Context, Event) {}
@@ -0,0 +1,118 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
+)
+
+// profileSection is the archive section holding the user profile.
+const profileSection = "profile"
+
+// The statuses of the user data export.
+const (
+	ExportPending   = "pending"
+	ExportCompleted = "completed"
+	ExportFailed    = "failed"
+)
+
+// ErrInvalidSignature indicates the download link of the export which isn't
+// signed by the service, or which has expired.
+var ErrInvalidSignature = errors.New("invalid or expired download link")
+
+// Export represents the export of the user data, such as the profile, the
+// group memberships, the owned things and channels, and the issued keys,
+// archived by the background job.
+type Export struct {
+	ID          string
+	UserID      string
+	Status      string
+	Error       string
+	Archive     []byte
+	CreatedAt   time.Time
+	CompletedAt time.Time
+
+	// ExpiresAt is the time the export expires at. Once the export expires,
+	// the new export is started in its place.
+	ExpiresAt time.Time
+
+	// Signature signs the download link of the completed export. It isn't
+	// persisted, since it's derived from the export.
+	Signature string
+}
+
+// ExportRepository specifies the user data export persistence API.
+type ExportRepository interface {
+	// Save persists the export, pruning the expired exports.
+	Save(ctx context.Context, e Export) error
+
+	// Update updates the status, the error, the archive and the expiration
+	// of the export.
+	Update(ctx context.Context, e Export) error
+
+	// RetrieveByID retrieves the export along with its archive.
+	RetrieveByID(ctx context.Context, id string) (Export, error)
+
+	// RetrieveLatest retrieves the latest export of the user, without its
+	// archive.
+	RetrieveLatest(ctx context.Context, userID string) (Export, error)
+}
+
+// ExportSource collects the user data held by the other services.
+type ExportSource interface {
+	// Collect returns the named sections of the data of the user identified
+	// by the token, e.g. the groups or the things. Each section is archived
+	// as its own JSON file.
+	Collect(ctx context.Context, token, userID string) (map[string]interface{}, error)
+
+	// Memberships returns the IDs of the groups the user is a member of,
+	// retrieved with the token.
+	Memberships(ctx context.Context, token, userID string) ([]string, error)
+}
+
+// exportProfile is the user profile as archived, without the password hash.
+type exportProfile struct {
+	ID       string        `json:"id"`
+	Email    string        `json:"email"`
+	Metadata Metadata      `json:"metadata,omitempty"`
+	Labels   labels.Labels `json:"labels,omitempty"`
+	Profile  Profile       `json:"profile"`
+}
+
+// archive creates the ZIP archive holding each section as the JSON file named
+// after the section.
+func archive(sections map[string]interface{}) ([]byte, error) {
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range names {
+		w, err := zw.Create(name + ".json")
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(sections[name]); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
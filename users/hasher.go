@@ -12,4 +12,9 @@ type Hasher interface {
 	// Compare compares plain-text version to the hashed one. An error should
 	// indicate failed comparison.
 	Compare(string, string) error
+
+	// NeedsRehash returns whether the hash was generated with the other
+	// algorithm or parameters than the new hashes are, so it should be
+	// replaced once the plain-text version is known.
+	NeedsRehash(string) bool
 }
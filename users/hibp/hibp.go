@@ -0,0 +1,82 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hibp contains the breach checker looking the passwords up in the
+// Have I Been Pwned Pwned Passwords API.
+package hibp
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+// URL is the URL of the Pwned Passwords API.
+const URL = "https://api.pwnedpasswords.com"
+
+const prefixLen = 5
+
+var _ users.BreachChecker = (*checker)(nil)
+
+type checker struct {
+	client *http.Client
+	url    string
+}
+
+// New returns the breach checker using the Pwned Passwords API at the given
+// URL. The checker relies on the k-anonymity of the API: only the first five
+// characters of the SHA-1 hash of the password are sent, and the remaining
+// ones are matched locally against the hashes sharing the prefix.
+func New(client *http.Client, url string) users.BreachChecker {
+	return &checker{
+		client: client,
+		url:    strings.TrimSuffix(url, "/"),
+	}
+}
+
+func (c *checker) Breached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLen], hash[prefixLen:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/range/%s", c.url, prefix), nil)
+	if err != nil {
+		return false, err
+	}
+	// The padding hides the number of the hashes sharing the prefix from
+	// the observers of the response size.
+	req.Header.Set("Add-Padding", "true")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	// Each line of the response is the hash suffix and the number of its
+	// occurrences, separated by the colon. The padding lines occur zero
+	// times.
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		parts := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], suffix) {
+			continue
+		}
+		count, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return false, err
+		}
+		return count > 0, nil
+	}
+	return false, scanner.Err()
+}
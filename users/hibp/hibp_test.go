@@ -0,0 +1,73 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package hibp_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mainflux/mainflux/users/hibp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBreached(t *testing.T) {
+	// The ranges of the SHA-1 hashes of "password", which is breached, and
+	// of "padded", which only appears in the padding.
+	ranges := map[string]string{
+		"5BAA6": "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\r\n",
+		"35B1A": "C6F9CC1A7D2B46D057C6858B3AF47086AE9:0\r\n",
+	}
+	var padding string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		padding = r.Header.Get("Add-Padding")
+		prefix := r.URL.Path[len("/range/"):]
+		if prefix == "1D5EE" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, ranges[prefix])
+	}))
+	defer ts.Close()
+
+	c := hibp.New(ts.Client(), ts.URL)
+
+	cases := []struct {
+		desc     string
+		password string
+		breached bool
+		err      bool
+	}{
+		{
+			desc:     "check breached password",
+			password: "password",
+			breached: true,
+		},
+		{
+			desc:     "check password not breached",
+			password: "Xk2#pLw9!qRt",
+			breached: false,
+		},
+		{
+			desc:     "check password in padding",
+			password: "padded",
+			breached: false,
+		},
+		{
+			desc:     "check password with API unavailable",
+			password: "unavailable",
+			breached: false,
+			err:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		breached, err := c.Breached(context.Background(), tc.password)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.breached, breached, fmt.Sprintf("%s: expected %t got %t", tc.desc, tc.breached, breached))
+		assert.Equal(t, "true", padding, fmt.Sprintf("%s: expected padding to be requested", tc.desc))
+	}
+}
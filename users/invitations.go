@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	// invitationTokenSize is the number of the random bytes of the
+	// invitation token.
+	invitationTokenSize = 32
+
+	// invitationDuration is the time the invitation link is valid for.
+	invitationDuration = 7 * 24 * time.Hour
+)
+
+// ErrInvalidInvitation indicates the invitation token which is unknown, was
+// already used or has expired.
+var ErrInvalidInvitation = errors.New("invalid or expired invitation")
+
+// Invitation represents the invitation of the user to register, sent by the
+// admin or by the user managing the members of the group. The invited user
+// joins the group, if set, once the invitation is accepted.
+type Invitation struct {
+	ID           string
+	Email        string
+	GroupID      string
+	InviterID    string
+	InviterEmail string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+
+	// TokenHash is the hash of the token carried by the invitation link.
+	// The token itself is only sent to the invited user.
+	TokenHash string
+}
+
+// InvitationRepository specifies the invitation persistence API.
+type InvitationRepository interface {
+	// Save persists the invitation, pruning the expired invitations.
+	Save(ctx context.Context, inv Invitation) error
+
+	// RetrieveByToken retrieves the invitation given the hash of its token.
+	RetrieveByToken(ctx context.Context, tokenHash string) (Invitation, error)
+
+	// Remove removes the invitation with given ID.
+	Remove(ctx context.Context, id string) error
+}
+
+// hashToken returns the hash of the random token, such as the invitation or
+// the MFA challenge token. The tokens are random, so the plain hash is enough
+// to look them up safely.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
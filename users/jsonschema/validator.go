@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jsonschema contains the validator checking the user metadata
+// against the JSON Schema.
+package jsonschema
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var errInvalidSchema = errors.New("invalid metadata schema")
+
+var _ users.Validator = (*validator)(nil)
+
+type validator struct {
+	schema *gojsonschema.Schema
+}
+
+// New returns the validator accepting only the users whose metadata conforms
+// to the given JSON Schema. The user without the metadata is validated as
+// the empty object, so the schema decides whether the metadata is required.
+func New(schema []byte) (users.Validator, error) {
+	s, err := gojsonschema.NewSchema(gojsonschema.NewBytesLoader(schema))
+	if err != nil {
+		return nil, errors.Wrap(errInvalidSchema, err)
+	}
+	return &validator{schema: s}, nil
+}
+
+func (v *validator) ValidateUser(_ context.Context, user users.User) error {
+	metadata := user.Metadata
+	if metadata == nil {
+		metadata = users.Metadata{}
+	}
+
+	res, err := v.schema.Validate(gojsonschema.NewGoLoader(metadata))
+	if err != nil {
+		return errors.Wrap(users.ErrValidation, err)
+	}
+	if res.Valid() {
+		return nil
+	}
+
+	me := &users.MetadataError{}
+	for _, re := range res.Errors() {
+		me.Violations = append(me.Violations, users.MetadataViolation{
+			Field:       re.Field(),
+			Description: re.Description(),
+		})
+	}
+	return me
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package jsonschema_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const schema = `{
+	"type": "object",
+	"properties": {
+		"department": {"type": "string"},
+		"floor": {"type": "integer", "minimum": 0}
+	},
+	"required": ["department"]
+}`
+
+func TestNew(t *testing.T) {
+	cases := []struct {
+		desc   string
+		schema string
+		err    bool
+	}{
+		{"create validator with valid schema", schema, false},
+		{"create validator with malformed schema", `{"type": `, true},
+		{"create validator with invalid schema", `{"type": "unknown"}`, true},
+	}
+
+	for _, tc := range cases {
+		_, err := jsonschema.New([]byte(tc.schema))
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: expected error %t got %s", tc.desc, tc.err, err))
+	}
+}
+
+func TestValidateUser(t *testing.T) {
+	v, err := jsonschema.New([]byte(schema))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		meta   users.Metadata
+		fields []string
+	}{
+		{
+			desc: "validate conforming metadata",
+			meta: users.Metadata{"department": "sales", "floor": 2},
+		},
+		{
+			desc:   "validate metadata without required field",
+			meta:   users.Metadata{"floor": 2},
+			fields: []string{"(root)"},
+		},
+		{
+			desc:   "validate metadata with fields of wrong type",
+			meta:   users.Metadata{"department": 1, "floor": -1},
+			fields: []string{"department", "floor"},
+		},
+		{
+			desc:   "validate user without metadata",
+			fields: []string{"(root)"},
+		},
+	}
+
+	for _, tc := range cases {
+		err := v.ValidateUser(context.Background(), users.User{Email: "user@example.com", Metadata: tc.meta})
+		if len(tc.fields) == 0 {
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			continue
+		}
+		assert.True(t, errors.Contains(err, users.ErrValidation), fmt.Sprintf("%s: expected %s got %s", tc.desc, users.ErrValidation, err))
+		me, ok := err.(*users.MetadataError)
+		require.True(t, ok, fmt.Sprintf("%s: expected metadata error got %s", tc.desc, err))
+		var fields []string
+		for _, v := range me.Violations {
+			fields = append(fields, v.Field)
+		}
+		assert.ElementsMatch(t, tc.fields, fields, fmt.Sprintf("%s: expected violated fields %v got %v", tc.desc, tc.fields, fields))
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const (
+	// mfaIssuer is the issuer of the TOTP secrets, shown by the
+	// authenticator apps.
+	mfaIssuer = "Mainflux"
+
+	// The TOTP parameters, as used by the common authenticator apps.
+	totpDigits    = 6
+	totpPeriod    = 30 * time.Second
+	totpSkew      = 1
+	totpSecretLen = 20
+
+	recoveryCodesNum = 10
+	recoveryCodeSize = 6
+
+	// mfaChallengeSize is the number of the random bytes of the MFA
+	// challenge token.
+	mfaChallengeSize = 32
+	// mfaChallengeDuration is the time the user has to complete the login
+	// with the one time code.
+	mfaChallengeDuration = 5 * time.Minute
+)
+
+var (
+	// ErrMFARequired indicates the login of the user enrolled in the MFA,
+	// who has to complete the login with the one time code.
+	ErrMFARequired = errors.New("multi-factor authentication required")
+
+	// ErrInvalidMFACode indicates the invalid or expired one time code, or
+	// the invalid recovery code.
+	ErrInvalidMFACode = errors.New("invalid multi-factor authentication code")
+
+	// ErrMFAEnrolled indicates the enrollment of the user who already
+	// enabled the MFA.
+	ErrMFAEnrolled = errors.New("multi-factor authentication already enabled")
+
+	// ErrMFANotEnrolled indicates the MFA verification of the user who
+	// didn't enroll in the MFA.
+	ErrMFANotEnrolled = errors.New("multi-factor authentication not enrolled")
+
+	codeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// MFA represents the TOTP based multi-factor authentication of the user. The
+// MFA is enabled once the user confirms the enrollment with the one time
+// code, and until then the login doesn't require it.
+type MFA struct {
+	UserID  string
+	Secret  string
+	Enabled bool
+
+	// RecoveryCodes are the hashes of the single use codes the user logs in
+	// with instead of the one time code, e.g. once the device is lost.
+	RecoveryCodes []string
+}
+
+// MFAChallenge is the pending login of the user enrolled in the MFA, which
+// is completed with the challenge token and the one time code. The challenge
+// token isn't the auth key, so it can't be used as the access token, and it
+// completes only one login.
+type MFAChallenge struct {
+	// TokenHash is the hash of the challenge token, which is stored only
+	// hashed.
+	TokenHash string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+// Enrollment represents the MFA enrollment of the user. The recovery codes
+// are returned only on enrollment, since they're stored hashed.
+type Enrollment struct {
+	URI           string
+	Secret        string
+	RecoveryCodes []string
+}
+
+// TOTP returns the time-based one time code of the base32 encoded secret at
+// the given time, as specified by RFC 6238.
+func TOTP(secret string, t time.Time) (string, error) {
+	key, err := codeEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", errors.Wrap(ErrMalformedEntity, err)
+	}
+	return totp(key, uint64(t.Unix())/uint64(totpPeriod.Seconds())), nil
+}
+
+func totp(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, code%1000000)
+}
+
+// validTOTP checks the one time code, accepting the codes of the adjacent
+// periods to tolerate the clock skew of the device.
+func validTOTP(secret, code string, t time.Time) bool {
+	key, err := codeEncoding.DecodeString(strings.ToUpper(secret))
+	if err != nil || len(code) != totpDigits {
+		return false
+	}
+	counter := int64(t.Unix()) / int64(totpPeriod.Seconds())
+	for i := -totpSkew; i <= totpSkew; i++ {
+		if hmac.Equal([]byte(totp(key, uint64(counter+int64(i)))), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// provisioningURI returns the URI the authenticator apps enroll the secret
+// with, usually scanned as the QR code.
+func provisioningURI(email, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", mfaIssuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(totpDigits))
+	v.Set("period", fmt.Sprint(int(totpPeriod.Seconds())))
+	label := url.PathEscape(fmt.Sprintf("%s:%s", mfaIssuer, email))
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, v.Encode())
+}
+
+// randomCode returns the random base32 encoded code of the given number of
+// random bytes.
+func randomCode(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return codeEncoding.EncodeToString(b), nil
+}
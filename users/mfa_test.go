@@ -0,0 +1,58 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTP(t *testing.T) {
+	// The test vectors of RFC 6238, truncated to 6 digits.
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	cases := []struct {
+		desc   string
+		secret string
+		time   int64
+		code   string
+		err    error
+	}{
+		{
+			desc:   "generate code at the start of the epoch",
+			secret: secret,
+			time:   59,
+			code:   "287082",
+		},
+		{
+			desc:   "generate code with leading zeros",
+			secret: secret,
+			time:   1234567890,
+			code:   "005924",
+		},
+		{
+			desc:   "generate code of lower case secret",
+			secret: "gezdgnbvgy3tqojqgezdgnbvgy3tqojq",
+			time:   2000000000,
+			code:   "279037",
+		},
+		{
+			desc:   "generate code of invalid secret",
+			secret: "invalid-secret",
+			time:   59,
+			err:    users.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range cases {
+		code, err := users.TOTP(tc.secret, time.Unix(tc.time, 0))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.code, code, fmt.Sprintf("%s: expected code %s got %s\n", tc.desc, tc.code, code))
+	}
+}
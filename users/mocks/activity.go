@@ -0,0 +1,73 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.ActivityRepository = (*activityRepositoryMock)(nil)
+
+type activityRepositoryMock struct {
+	mu       sync.Mutex
+	attempts map[string][]users.LoginAttempt
+}
+
+// NewActivityRepository creates in-memory login activity repository.
+func NewActivityRepository() users.ActivityRepository {
+	return &activityRepositoryMock{
+		attempts: make(map[string][]users.LoginAttempt),
+	}
+}
+
+func (arm *activityRepositoryMock) Save(ctx context.Context, a users.LoginAttempt) error {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	arm.attempts[a.UserID] = append(arm.attempts[a.UserID], a)
+	return nil
+}
+
+func (arm *activityRepositoryMock) RetrieveByUser(ctx context.Context, userID string, offset, limit uint64) (users.ActivityPage, error) {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	attempts := append([]users.LoginAttempt{}, arm.attempts[userID]...)
+	sort.SliceStable(attempts, func(i, j int) bool {
+		return attempts[i].CreatedAt.After(attempts[j].CreatedAt)
+	})
+
+	page := users.ActivityPage{
+		Total:    uint64(len(attempts)),
+		Offset:   offset,
+		Limit:    limit,
+		Attempts: []users.LoginAttempt{},
+	}
+	if offset >= uint64(len(attempts)) {
+		return page, nil
+	}
+	end := offset + limit
+	if end > uint64(len(attempts)) {
+		end = uint64(len(attempts))
+	}
+	page.Attempts = attempts[offset:end]
+	return page, nil
+}
+
+func (arm *activityRepositoryMock) CountSuccessful(ctx context.Context, userID, ip string) (uint64, error) {
+	arm.mu.Lock()
+	defer arm.mu.Unlock()
+
+	var n uint64
+	for _, a := range arm.attempts[userID] {
+		if a.Success && (ip == "" || a.IP == ip) {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -5,9 +5,11 @@ package mocks
 
 import (
 	"context"
+	"net/url"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/mainflux/mainflux"
+	"github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/users"
 	"google.golang.org/grpc"
 )
@@ -20,29 +22,58 @@ type SubjectSet struct {
 }
 
 type authServiceMock struct {
-	users map[string]string
-	authz map[string][]SubjectSet
+	users  map[string]string
+	authz  map[string][]SubjectSet
+	claims map[string]map[string]string
 }
 
 func (svc authServiceMock) ListPolicies(ctx context.Context, in *mainflux.ListPoliciesReq, opts ...grpc.CallOption) (*mainflux.ListPoliciesRes, error) {
-	panic("not implemented")
+	res := &mainflux.ListPoliciesRes{}
+	if in.GetSub() == "" {
+		// Given the object alone, the subjects are listed.
+		for sub, ss := range svc.authz {
+			for _, v := range ss {
+				if v.Relation == in.GetAct() && v.Object == in.GetObj() {
+					res.Policies = append(res.Policies, sub)
+				}
+			}
+		}
+		return res, nil
+	}
+	for _, v := range svc.authz[in.GetSub()] {
+		if v.Relation == in.GetAct() && (in.GetObj() == "" || v.Object == in.GetObj()) {
+			res.Policies = append(res.Policies, v.Object)
+		}
+	}
+	return res, nil
 }
 
 // NewAuthService creates mock of users service.
 func NewAuthService(users map[string]string, authzDB map[string][]SubjectSet) mainflux.AuthServiceClient {
-	return &authServiceMock{users, authzDB}
+	return &authServiceMock{users, authzDB, map[string]map[string]string{}}
 }
 
 func (svc authServiceMock) Identify(ctx context.Context, in *mainflux.Token, opts ...grpc.CallOption) (*mainflux.UserIdentity, error) {
 	if id, ok := svc.users[in.Value]; ok {
-		return &mainflux.UserIdentity{Id: id, Email: id}, nil
+		return &mainflux.UserIdentity{Id: id, Email: id, Claims: svc.claims[in.Value]}, nil
 	}
 	return nil, users.ErrUnauthorizedAccess
 }
 
 func (svc authServiceMock) Issue(ctx context.Context, in *mainflux.IssueReq, opts ...grpc.CallOption) (*mainflux.Token, error) {
 	if id, ok := svc.users[in.GetEmail()]; ok {
-		switch in.Type {
+		switch {
+		case in.Type == auth.RecoveryKey && len(in.GetClaims()) > 0:
+			// The recovery keys carrying the claims are told apart, so
+			// the claims are returned on identification.
+			claims := url.Values{}
+			for k, v := range in.GetClaims() {
+				claims.Set(k, v)
+			}
+			token := id + "?" + claims.Encode()
+			svc.users[token] = id
+			svc.claims[token] = in.GetClaims()
+			return &mainflux.Token{Value: token}, nil
 		default:
 			return &mainflux.Token{Value: id}, nil
 		}
@@ -67,14 +98,77 @@ func (svc authServiceMock) AddPolicy(ctx context.Context, in *mainflux.AddPolicy
 }
 
 func (svc authServiceMock) DeletePolicy(ctx context.Context, in *mainflux.DeletePolicyReq, opts ...grpc.CallOption) (*mainflux.DeletePolicyRes, error) {
-	// Not implemented
+	var sub []SubjectSet
+	for _, v := range svc.authz[in.GetSub()] {
+		if v.Relation != in.GetAct() || v.Object != in.GetObj() {
+			sub = append(sub, v)
+		}
+	}
+	svc.authz[in.GetSub()] = sub
 	return &mainflux.DeletePolicyRes{Deleted: true}, nil
 }
 
 func (svc authServiceMock) Members(ctx context.Context, req *mainflux.MembersReq, _ ...grpc.CallOption) (r *mainflux.MembersRes, err error) {
-	panic("not implemented")
+	if _, ok := svc.users[req.GetToken()]; !ok {
+		return nil, users.ErrUnauthorizedAccess
+	}
+
+	// The members of the group are the subjects holding the member relation
+	// on it.
+	res := &mainflux.MembersRes{Type: req.GetType()}
+	for sub, ss := range svc.authz {
+		for _, v := range ss {
+			if v.Relation == "member" && v.Object == req.GetGroupID() {
+				res.Members = append(res.Members, sub)
+			}
+		}
+	}
+	res.Total = uint64(len(res.Members))
+	return res, nil
 }
 
 func (svc authServiceMock) Assign(ctx context.Context, req *mainflux.Assignment, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	id, ok := svc.users[req.GetToken()]
+	if !ok {
+		return nil, users.ErrUnauthorizedAccess
+	}
+	for _, v := range svc.authz[id] {
+		if v.Relation == "invite" && v.Object == req.GetGroupID() {
+			svc.authz[req.GetMemberID()] = append(svc.authz[req.GetMemberID()], SubjectSet{Object: req.GetGroupID(), Relation: "member"})
+			return &empty.Empty{}, nil
+		}
+	}
+	return nil, users.ErrAuthorization
+}
+
+func (svc authServiceMock) ReserveQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
 	panic("not implemented")
 }
+
+func (svc authServiceMock) ReleaseQuota(ctx context.Context, req *mainflux.QuotaReq, _ ...grpc.CallOption) (r *empty.Empty, err error) {
+	panic("not implemented")
+}
+
+func (svc authServiceMock) RevokeKeys(ctx context.Context, req *mainflux.RevokeKeysReq, _ ...grpc.CallOption) (*mainflux.RevokeKeysRes, error) {
+	if _, ok := svc.users[req.GetToken()]; !ok {
+		return nil, users.ErrUnauthorizedAccess
+	}
+	return &mainflux.RevokeKeysRes{}, nil
+}
+
+func (svc authServiceMock) RemoveLinks(ctx context.Context, req *mainflux.RemoveLinksReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	if req.GetUserID() == "" {
+		return &empty.Empty{}, users.ErrMalformedEntity
+	}
+	return &empty.Empty{}, nil
+}
+
+func (svc authServiceMock) ApplyPolicyTemplate(ctx context.Context, req *mainflux.PolicyTemplateReq, _ ...grpc.CallOption) (*empty.Empty, error) {
+	// Only the default user template, making the user the member of the
+	// users object, is supported.
+	if req.GetEntityType() != "user" || req.GetEntityID() == "" {
+		return &empty.Empty{}, users.ErrMalformedEntity
+	}
+	svc.authz[req.GetEntityID()] = append(svc.authz[req.GetEntityID()], SubjectSet{Object: "users", Relation: "member"})
+	return &empty.Empty{}, nil
+}
@@ -0,0 +1,30 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.BreachChecker = (*breachCheckerMock)(nil)
+
+type breachCheckerMock struct {
+	breached map[string]bool
+}
+
+// NewBreachChecker creates the breach checker reporting the given passwords
+// as breached.
+func NewBreachChecker(passwords ...string) users.BreachChecker {
+	bc := &breachCheckerMock{breached: map[string]bool{}}
+	for _, p := range passwords {
+		bc.breached[p] = true
+	}
+	return bc
+}
+
+func (bc *breachCheckerMock) Breached(_ context.Context, password string) (bool, error) {
+	return bc.breached[password], nil
+}
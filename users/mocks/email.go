@@ -4,17 +4,82 @@
 package mocks
 
 import (
+	"context"
+	"sync"
+
 	"github.com/mainflux/mainflux/users"
 )
 
-type emailerMock struct {
+var _ users.Emailer = (*Emailer)(nil)
+
+// Emailer is the mock emailer, keeping the latest invitation and password
+// reset token sent to each email, and the IP addresses of the login alerts.
+type Emailer struct {
+	mu          sync.Mutex
+	invitations map[string]string
+	resets      map[string]string
+	alerts      map[string][]string
 }
 
 // NewEmailer provides emailer instance for  the test
-func NewEmailer() users.Emailer {
-	return &emailerMock{}
+func NewEmailer() *Emailer {
+	return &Emailer{invitations: map[string]string{}, resets: map[string]string{}, alerts: map[string][]string{}}
+}
+
+func (e *Emailer) SendPasswordReset(_ context.Context, to []string, _, token string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, email := range to {
+		e.resets[email] = token
+	}
+	return nil
 }
 
-func (e *emailerMock) SendPasswordReset([]string, string, string) error {
+// PasswordReset returns the latest password reset token sent to the email.
+func (e *Emailer) PasswordReset(email string) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.resets[email]
+}
+
+func (e *Emailer) SendVerification(context.Context, []string, string) error {
 	return nil
 }
+
+func (e *Emailer) SendInvitation(_ context.Context, to []string, _, token string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, email := range to {
+		e.invitations[email] = token
+	}
+	return nil
+}
+
+// Invitation returns the latest invitation token sent to the email.
+func (e *Emailer) Invitation(email string) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.invitations[email]
+}
+
+func (e *Emailer) SendLoginAlert(_ context.Context, to []string, ip, _ string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, email := range to {
+		e.alerts[email] = append(e.alerts[email], ip)
+	}
+	return nil
+}
+
+// LoginAlerts returns the IP addresses of the login alerts sent to the email.
+func (e *Emailer) LoginAlerts(email string) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.alerts[email]
+}
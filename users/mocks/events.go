@@ -0,0 +1,39 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.EventNotifier = (*EventNotifier)(nil)
+
+// EventNotifier is the mock event notifier, keeping the notified events.
+type EventNotifier struct {
+	mu     sync.Mutex
+	events []users.Event
+}
+
+// NewEventNotifier creates the event notifier keeping the notified events.
+func NewEventNotifier() *EventNotifier {
+	return &EventNotifier{}
+}
+
+func (en *EventNotifier) Notify(_ context.Context, event users.Event) {
+	en.mu.Lock()
+	defer en.mu.Unlock()
+
+	en.events = append(en.events, event)
+}
+
+// Events returns the events notified so far, in the order of notification.
+func (en *EventNotifier) Events() []users.Event {
+	en.mu.Lock()
+	defer en.mu.Unlock()
+
+	return append([]users.Event{}, en.events...)
+}
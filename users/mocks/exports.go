@@ -0,0 +1,72 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.ExportRepository = (*exportRepositoryMock)(nil)
+
+type exportRepositoryMock struct {
+	mu      sync.Mutex
+	exports map[string]users.Export
+}
+
+// NewExportRepository creates in-memory user data export repository.
+func NewExportRepository() users.ExportRepository {
+	return &exportRepositoryMock{
+		exports: make(map[string]users.Export),
+	}
+}
+
+func (erm *exportRepositoryMock) Save(ctx context.Context, e users.Export) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	erm.exports[e.ID] = e
+	return nil
+}
+
+func (erm *exportRepositoryMock) Update(ctx context.Context, e users.Export) error {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	if _, ok := erm.exports[e.ID]; !ok {
+		return users.ErrNotFound
+	}
+	erm.exports[e.ID] = e
+	return nil
+}
+
+func (erm *exportRepositoryMock) RetrieveByID(ctx context.Context, id string) (users.Export, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	e, ok := erm.exports[id]
+	if !ok {
+		return users.Export{}, users.ErrNotFound
+	}
+	return e, nil
+}
+
+func (erm *exportRepositoryMock) RetrieveLatest(ctx context.Context, userID string) (users.Export, error) {
+	erm.mu.Lock()
+	defer erm.mu.Unlock()
+
+	var latest users.Export
+	for _, e := range erm.exports {
+		if e.UserID == userID && !e.CreatedAt.Before(latest.CreatedAt) {
+			latest = e
+		}
+	}
+	if latest.ID == "" {
+		return users.Export{}, users.ErrNotFound
+	}
+	latest.Archive = nil
+	return latest, nil
+}
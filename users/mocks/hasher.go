@@ -4,15 +4,22 @@
 package mocks
 
 import (
+	"strings"
+
 	"github.com/mainflux/mainflux/users"
 )
 
+// LegacyHashPrefix marks the hashes the mock hasher accepts, but reports as
+// to be rehashed, standing for the hashes made with the older algorithm.
+const LegacyHashPrefix = "legacy:"
+
 var _ users.Hasher = (*hasherMock)(nil)
 
 type hasherMock struct{}
 
 // NewHasher creates "no-op" hasher for test purposes. This implementation will
-// return secrets without changing them.
+// return secrets without changing them, and accepts the secrets prefixed with
+// LegacyHashPrefix as well.
 func NewHasher() users.Hasher {
 	return &hasherMock{}
 }
@@ -25,9 +32,13 @@ func (hm *hasherMock) Hash(pwd string) (string, error) {
 }
 
 func (hm *hasherMock) Compare(plain, hashed string) error {
-	if plain != hashed {
+	if plain != strings.TrimPrefix(hashed, LegacyHashPrefix) {
 		return users.ErrUnauthorizedAccess
 	}
 
 	return nil
 }
+
+func (hm *hasherMock) NeedsRehash(hashed string) bool {
+	return strings.HasPrefix(hashed, LegacyHashPrefix)
+}
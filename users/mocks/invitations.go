@@ -0,0 +1,63 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.InvitationRepository = (*invitationRepositoryMock)(nil)
+
+type invitationRepositoryMock struct {
+	mu          sync.Mutex
+	invitations map[string]users.Invitation
+}
+
+// NewInvitationRepository creates in-memory invitation repository.
+func NewInvitationRepository() users.InvitationRepository {
+	return &invitationRepositoryMock{
+		invitations: make(map[string]users.Invitation),
+	}
+}
+
+func (irm *invitationRepositoryMock) Save(ctx context.Context, inv users.Invitation) error {
+	irm.mu.Lock()
+	defer irm.mu.Unlock()
+
+	now := time.Now()
+	for id, i := range irm.invitations {
+		if i.ExpiresAt.Before(now) {
+			delete(irm.invitations, id)
+		}
+	}
+	irm.invitations[inv.ID] = inv
+	return nil
+}
+
+func (irm *invitationRepositoryMock) RetrieveByToken(ctx context.Context, tokenHash string) (users.Invitation, error) {
+	irm.mu.Lock()
+	defer irm.mu.Unlock()
+
+	for _, i := range irm.invitations {
+		if i.TokenHash == tokenHash {
+			return i, nil
+		}
+	}
+	return users.Invitation{}, users.ErrNotFound
+}
+
+func (irm *invitationRepositoryMock) Remove(ctx context.Context, id string) error {
+	irm.mu.Lock()
+	defer irm.mu.Unlock()
+
+	if _, ok := irm.invitations[id]; !ok {
+		return users.ErrNotFound
+	}
+	delete(irm.invitations, id)
+	return nil
+}
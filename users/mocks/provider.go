@@ -0,0 +1,39 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+// ProviderURL is the authorization URL of the mock identity provider.
+const ProviderURL = "https://idp.example.com/authorize"
+
+var _ users.IdentityProvider = (*identityProviderMock)(nil)
+
+type identityProviderMock struct {
+	identities map[string]users.Identity
+}
+
+// NewIdentityProvider creates the identity provider which exchanges the
+// authorization codes for the given identities.
+func NewIdentityProvider(identities map[string]users.Identity) users.IdentityProvider {
+	return &identityProviderMock{identities: identities}
+}
+
+func (p *identityProviderMock) AuthURL(state string) string {
+	return fmt.Sprintf("%s?state=%s", ProviderURL, url.QueryEscape(state))
+}
+
+func (p *identityProviderMock) Exchange(_ context.Context, code string) (users.Identity, error) {
+	id, ok := p.identities[code]
+	if !ok {
+		return users.Identity{}, users.ErrUnauthorizedAccess
+	}
+	return id, nil
+}
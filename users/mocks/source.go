@@ -0,0 +1,42 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package mocks
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+)
+
+var _ users.ExportSource = (*exportSourceMock)(nil)
+
+type exportSourceMock struct {
+	sections map[string]interface{}
+	err      error
+}
+
+// NewExportSource creates the export source returning the given sections, or
+// the error if it isn't nil.
+func NewExportSource(sections map[string]interface{}, err error) users.ExportSource {
+	return exportSourceMock{sections: sections, err: err}
+}
+
+func (esm exportSourceMock) Collect(ctx context.Context, token, userID string) (map[string]interface{}, error) {
+	if esm.err != nil {
+		return nil, esm.err
+	}
+
+	sections := map[string]interface{}{}
+	for k, v := range esm.sections {
+		sections[k] = v
+	}
+	return sections, nil
+}
+
+func (esm exportSourceMock) Memberships(ctx context.Context, token, userID string) ([]string, error) {
+	if esm.err != nil {
+		return nil, esm.err
+	}
+	return []string{}, nil
+}
@@ -5,7 +5,10 @@ package mocks
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/mainflux/mainflux/users"
 )
@@ -17,6 +20,12 @@ type userRepositoryMock struct {
 	users          map[string]users.User
 	usersByID      map[string]users.User
 	usersByGroupID map[string]users.User
+	mfa            map[string]users.MFA
+	identities     map[string]users.Identity
+	history        map[string][]string
+	resets         map[string]string
+	challenges     map[string]users.MFAChallenge
+	resetChalls    map[string]users.ResetChallenge
 }
 
 // NewUserRepository creates in-memory user repository
@@ -25,6 +34,12 @@ func NewUserRepository() users.UserRepository {
 		users:          make(map[string]users.User),
 		usersByID:      make(map[string]users.User),
 		usersByGroupID: make(map[string]users.User),
+		mfa:            make(map[string]users.MFA),
+		identities:     make(map[string]users.Identity),
+		history:        make(map[string][]string),
+		resets:         make(map[string]string),
+		challenges:     make(map[string]users.MFAChallenge),
+		resetChalls:    make(map[string]users.ResetChallenge),
 	}
 }
 
@@ -36,6 +51,12 @@ func (urm *userRepositoryMock) Save(ctx context.Context, user users.User) (strin
 		return "", users.ErrConflict
 	}
 
+	if user.PasswordChangedAt.IsZero() {
+		user.PasswordChangedAt = time.Now()
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
 	urm.users[user.Email] = user
 	urm.usersByID[user.ID] = user
 	return user.ID, nil
@@ -57,11 +78,29 @@ func (urm *userRepositoryMock) UpdateUser(ctx context.Context, user users.User)
 	urm.mu.Lock()
 	defer urm.mu.Unlock()
 
-	if _, ok := urm.users[user.Email]; !ok {
+	u, ok := urm.users[user.Email]
+	if !ok {
 		return users.ErrUserNotFound
 	}
 
-	urm.users[user.Email] = user
+	u.Metadata = user.Metadata
+	u.Labels = user.Labels
+	urm.users[u.Email] = u
+	urm.usersByID[u.ID] = u
+	return nil
+}
+
+func (urm *userRepositoryMock) UpdateProfile(ctx context.Context, id string, p users.Profile) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	u, ok := urm.usersByID[id]
+	if !ok {
+		return users.ErrNotFound
+	}
+	u.Profile = p
+	urm.users[u.Email] = u
+	urm.usersByID[id] = u
 	return nil
 }
 
@@ -89,33 +128,327 @@ func (urm *userRepositoryMock) RetrieveByID(ctx context.Context, id string) (use
 	return val, nil
 }
 
-func (urm *userRepositoryMock) RetrieveAll(ctx context.Context, offset, limit uint64, ids []string, email string, um users.Metadata) (users.UserPage, error) {
+func (urm *userRepositoryMock) RetrieveAll(ctx context.Context, ids []string, pm users.PageMetadata) (users.UserPage, error) {
 	urm.mu.Lock()
 	defer urm.mu.Unlock()
 
-	up := users.UserPage{}
-	i := uint64(0)
-
+	var all []users.User
 	for _, u := range urm.users {
-		if i >= offset && i < (limit+offset) {
+		if !pm.Selector.Matches(u.Labels) {
+			continue
+		}
+		if len(ids) > 0 && !contains(ids, u.ID) {
+			continue
+		}
+		if pm.Email != "" && !strings.Contains(u.Email, pm.Email) {
+			continue
+		}
+		if pm.Status != "" && u.Status() != pm.Status {
+			continue
+		}
+		if !pm.CreatedFrom.IsZero() && u.CreatedAt.Before(pm.CreatedFrom) {
+			continue
+		}
+		if !pm.CreatedTo.IsZero() && u.CreatedAt.After(pm.CreatedTo) {
+			continue
+		}
+		all = append(all, u)
+	}
+
+	sort.SliceStable(all, func(i, j int) bool {
+		if pm.Dir == "desc" {
+			i, j = j, i
+		}
+		if pm.Order == "created_at" {
+			return all[i].CreatedAt.Before(all[j].CreatedAt)
+		}
+		return all[i].Email < all[j].Email
+	})
+
+	up := users.UserPage{}
+	for i, u := range all {
+		if uint64(i) >= pm.Offset && uint64(i) < pm.Limit+pm.Offset {
 			up.Users = append(up.Users, u)
 		}
-		i++
 	}
 
-	up.Offset = offset
-	up.Limit = limit
-	up.Total = uint64(i)
+	up.Offset = pm.Offset
+	up.Limit = pm.Limit
+	up.Total = uint64(len(all))
 
 	return up, nil
 }
 
-func (urm *userRepositoryMock) UpdatePassword(_ context.Context, token, password string) error {
+func (urm *userRepositoryMock) UpdatePassword(_ context.Context, email, password string) error {
 	urm.mu.Lock()
 	defer urm.mu.Unlock()
 
-	if _, ok := urm.users[token]; !ok {
+	u, ok := urm.users[email]
+	if !ok {
 		return users.ErrUserNotFound
 	}
+	u.Password = password
+	u.PasswordChangedAt = time.Now()
+	urm.users[email] = u
+	urm.usersByID[u.ID] = u
+	return nil
+}
+
+func (urm *userRepositoryMock) UpdatePasswordHash(_ context.Context, id, hash string) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	u, ok := urm.usersByID[id]
+	if !ok {
+		return users.ErrNotFound
+	}
+	u.Password = hash
+	urm.users[u.Email] = u
+	urm.usersByID[id] = u
+	return nil
+}
+
+func (urm *userRepositoryMock) SavePasswordHistory(_ context.Context, userID, password string, limit uint32) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.usersByID[userID]; !ok {
+		return users.ErrMalformedEntity
+	}
+	h := append([]string{password}, urm.history[userID]...)
+	if uint32(len(h)) > limit {
+		h = h[:limit]
+	}
+	urm.history[userID] = h
+	return nil
+}
+
+func (urm *userRepositoryMock) RetrievePasswordHistory(_ context.Context, userID string, limit uint32) ([]string, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	h := urm.history[userID]
+	if uint32(len(h)) > limit {
+		h = h[:limit]
+	}
+	return append([]string{}, h...), nil
+}
+
+func (urm *userRepositoryMock) Verify(_ context.Context, email string) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	u, ok := urm.users[email]
+	if !ok {
+		return users.ErrNotFound
+	}
+	u.Verified = true
+	urm.users[email] = u
+	urm.usersByID[u.ID] = u
+	return nil
+}
+
+func (urm *userRepositoryMock) Disable(_ context.Context, id string) error {
+	return urm.setDisabled(id, true)
+}
+
+func (urm *userRepositoryMock) Enable(_ context.Context, id string) error {
+	return urm.setDisabled(id, false)
+}
+
+func (urm *userRepositoryMock) setDisabled(id string, disabled bool) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	u, ok := urm.usersByID[id]
+	if !ok {
+		return users.ErrNotFound
+	}
+	u.Disabled = disabled
+	urm.users[u.Email] = u
+	urm.usersByID[id] = u
+	return nil
+}
+
+func (urm *userRepositoryMock) IncrementFailedLogins(_ context.Context, id string) (uint32, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	u, ok := urm.usersByID[id]
+	if !ok {
+		return 0, users.ErrNotFound
+	}
+	u.FailedLogins++
+	urm.users[u.Email] = u
+	urm.usersByID[id] = u
+	return u.FailedLogins, nil
+}
+
+func (urm *userRepositoryMock) Lock(_ context.Context, id string, until time.Time) error {
+	return urm.setLockout(id, until)
+}
+
+func (urm *userRepositoryMock) Unlock(_ context.Context, id string) error {
+	return urm.setLockout(id, time.Time{})
+}
+
+func (urm *userRepositoryMock) setLockout(id string, until time.Time) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	u, ok := urm.usersByID[id]
+	if !ok {
+		return users.ErrNotFound
+	}
+	u.FailedLogins = 0
+	u.LockedUntil = until
+	urm.users[u.Email] = u
+	urm.usersByID[id] = u
 	return nil
 }
+
+func (urm *userRepositoryMock) Delete(_ context.Context, id string) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	u, ok := urm.usersByID[id]
+	if !ok {
+		return users.ErrNotFound
+	}
+	delete(urm.users, u.Email)
+	delete(urm.usersByID, id)
+	delete(urm.mfa, id)
+	delete(urm.history, id)
+	for key, i := range urm.identities {
+		if i.UserID == id {
+			delete(urm.identities, key)
+		}
+	}
+	return nil
+}
+
+func (urm *userRepositoryMock) SaveMFA(_ context.Context, mfa users.MFA) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.usersByID[mfa.UserID]; !ok {
+		return users.ErrMalformedEntity
+	}
+	urm.mfa[mfa.UserID] = mfa
+	return nil
+}
+
+func (urm *userRepositoryMock) RetrieveMFA(_ context.Context, userID string) (users.MFA, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	mfa, ok := urm.mfa[userID]
+	if !ok {
+		return users.MFA{}, users.ErrNotFound
+	}
+	return mfa, nil
+}
+
+func (urm *userRepositoryMock) SaveMFAChallenge(_ context.Context, c users.MFAChallenge) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.usersByID[c.UserID]; !ok {
+		return users.ErrNotFound
+	}
+	urm.challenges[c.TokenHash] = c
+	return nil
+}
+
+func (urm *userRepositoryMock) ConsumeMFAChallenge(_ context.Context, tokenHash string) (users.MFAChallenge, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	c, ok := urm.challenges[tokenHash]
+	if !ok {
+		return users.MFAChallenge{}, users.ErrNotFound
+	}
+	delete(urm.challenges, tokenHash)
+	return c, nil
+}
+
+func (urm *userRepositoryMock) SaveIdentity(_ context.Context, id users.Identity) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.usersByID[id.UserID]; !ok {
+		return users.ErrMalformedEntity
+	}
+	key := id.Provider + ":" + id.Subject
+	if _, ok := urm.identities[key]; ok {
+		return users.ErrConflict
+	}
+	urm.identities[key] = id
+	return nil
+}
+
+func (urm *userRepositoryMock) RetrieveByIdentity(_ context.Context, provider, subject string) (users.User, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	id, ok := urm.identities[provider+":"+subject]
+	if !ok {
+		return users.User{}, users.ErrNotFound
+	}
+	u, ok := urm.usersByID[id.UserID]
+	if !ok {
+		return users.User{}, users.ErrNotFound
+	}
+	return u, nil
+}
+
+func (urm *userRepositoryMock) SaveResetNonce(_ context.Context, userID, nonce string) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	urm.resets[userID] = nonce
+	return nil
+}
+
+func (urm *userRepositoryMock) ConsumeResetNonce(_ context.Context, userID, nonce string) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if n, ok := urm.resets[userID]; !ok || n != nonce {
+		return users.ErrNotFound
+	}
+	delete(urm.resets, userID)
+	return nil
+}
+
+func (urm *userRepositoryMock) SaveResetChallenge(_ context.Context, c users.ResetChallenge) error {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	if _, ok := urm.usersByID[c.UserID]; !ok {
+		return users.ErrNotFound
+	}
+	urm.resetChalls[c.TokenHash] = c
+	return nil
+}
+
+func (urm *userRepositoryMock) ConsumeResetChallenge(_ context.Context, tokenHash string) (users.ResetChallenge, error) {
+	urm.mu.Lock()
+	defer urm.mu.Unlock()
+
+	c, ok := urm.resetChalls[tokenHash]
+	if !ok {
+		return users.ResetChallenge{}, users.ErrNotFound
+	}
+	delete(urm.resetChalls, tokenHash)
+	return c, nil
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
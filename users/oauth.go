@@ -0,0 +1,44 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// oauthStateSize is the number of the random bytes of the state, which binds
+// the callback of the identity provider to the browser of the user.
+const oauthStateSize = 16
+
+// ErrUnknownProvider indicates the login with the identity provider which
+// isn't configured.
+var ErrUnknownProvider = errors.New("unknown identity provider")
+
+// Identity represents the user of the external identity provider, such as
+// Google or GitHub, linked to the Mainflux user.
+type Identity struct {
+	Provider string
+	Subject  string
+	UserID   string
+
+	// Email is the email of the user at the identity provider. The
+	// identity is linked to the user with the same email only if the
+	// provider verified it.
+	Email         string
+	EmailVerified bool
+}
+
+// IdentityProvider is the external identity provider the users log in with,
+// using the OAuth 2.0 authorization code flow.
+type IdentityProvider interface {
+	// AuthURL returns the URL the user is redirected to in order to log in
+	// at the provider, which passes the state back to the callback.
+	AuthURL(state string) string
+
+	// Exchange exchanges the authorization code, issued by the provider to
+	// the callback, for the identity of the user.
+	Exchange(ctx context.Context, code string) (Identity, error)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oauth
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	githubAuthURL  = "https://github.com/login/oauth/authorize"
+	githubTokenURL = "https://github.com/login/oauth/access_token"
+	githubAPIURL   = "https://api.github.com"
+	githubScope    = "read:user user:email"
+)
+
+var errMissingSubject = errors.New("missing user ID")
+
+var _ users.IdentityProvider = (*githubProvider)(nil)
+
+type githubProvider struct {
+	client *http.Client
+	cfg    Config
+}
+
+// NewGitHub returns the GitHub identity provider. GitHub doesn't support
+// OpenID Connect, so the user is identified by the numeric user ID, and the
+// email is the primary email of the user.
+func NewGitHub(client *http.Client, cfg Config) users.IdentityProvider {
+	return &githubProvider{
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+func (p *githubProvider) AuthURL(state string) string {
+	return authURL(githubAuthURL, p.cfg, githubScope, state)
+}
+
+func (p *githubProvider) Exchange(ctx context.Context, code string) (users.Identity, error) {
+	tr, err := exchange(ctx, p.client, githubTokenURL, p.cfg, code)
+	if err != nil {
+		return users.Identity{}, err
+	}
+
+	var user struct {
+		ID int64 `json:"id"`
+	}
+	if err := fetch(ctx, p.client, githubAPIURL+"/user", tr.AccessToken, &user); err != nil {
+		return users.Identity{}, err
+	}
+	if user.ID == 0 {
+		return users.Identity{}, errMissingSubject
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := fetch(ctx, p.client, githubAPIURL+"/user/emails", tr.AccessToken, &emails); err != nil {
+		return users.Identity{}, err
+	}
+
+	id := users.Identity{Subject: strconv.FormatInt(user.ID, 10)}
+	for _, e := range emails {
+		if e.Primary {
+			id.Email = e.Email
+			id.EmailVerified = e.Verified
+		}
+	}
+	return id, nil
+}
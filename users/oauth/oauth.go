@@ -0,0 +1,122 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oauth provides the external identity providers the users log in
+// with, using the OAuth 2.0 authorization code flow: Google, GitHub and any
+// OpenID Connect issuer.
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// maxDocumentSize limits the size of the responses of the identity
+// providers.
+const maxDocumentSize = 1 << 20
+
+var (
+	errExchange = errors.New("failed to exchange authorization code")
+	errFetch    = errors.New("failed to fetch identity provider document")
+)
+
+// Config represents the client of Mainflux registered at the identity
+// provider.
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL is the callback of the users service, the provider
+	// redirects the user to once logged in.
+	RedirectURL string
+}
+
+type tokenRes struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// authURL returns the authorization request URL of the given endpoint.
+func authURL(endpoint string, cfg Config, scope, state string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", cfg.ClientID)
+	v.Set("redirect_uri", cfg.RedirectURL)
+	v.Set("scope", scope)
+	v.Set("state", state)
+
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	return endpoint + sep + v.Encode()
+}
+
+// exchange exchanges the authorization code at the token endpoint, using the
+// client secret to authenticate the client.
+func exchange(ctx context.Context, client *http.Client, endpoint string, cfg Config, code string) (tokenRes, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", cfg.RedirectURL)
+	v.Set("client_id", cfg.ClientID)
+	v.Set("client_secret", cfg.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return tokenRes{}, errors.Wrap(errExchange, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tr tokenRes
+	if err := do(client, req, &tr); err != nil {
+		return tokenRes{}, errors.Wrap(errExchange, err)
+	}
+	// Some providers, e.g. GitHub, report the errors with the 200 status.
+	if tr.Error != "" {
+		return tokenRes{}, errors.Wrap(errExchange, fmt.Errorf("%s: %s", tr.Error, tr.ErrorDescription))
+	}
+	if tr.AccessToken == "" {
+		return tokenRes{}, errors.Wrap(errExchange, fmt.Errorf("missing access token"))
+	}
+	return tr, nil
+}
+
+// fetch fetches the document on behalf of the user, if the access token is
+// given.
+func fetch(ctx context.Context, client *http.Client, url, accessToken string, doc interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(errFetch, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	if err := do(client, req, doc); err != nil {
+		return errors.Wrap(errFetch, err)
+	}
+	return nil
+}
+
+func do(client *http.Client, req *http.Request, doc interface{}) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d of '%s'", resp.StatusCode, req.URL.Redacted())
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxDocumentSize)).Decode(doc)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/mainflux/mainflux/auth/jwt"
+	"github.com/mainflux/mainflux/auth/oidc"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	// GoogleIssuer is the OpenID Connect issuer of Google.
+	GoogleIssuer = "https://accounts.google.com"
+
+	discoveryPath = "/.well-known/openid-configuration"
+	oidcScope     = oidc.OpenIDScope + " " + oidc.EmailScope
+)
+
+var (
+	errDiscovery      = errors.New("failed to discover OpenID Connect issuer")
+	errMissingIDToken = errors.New("missing ID token")
+)
+
+var _ users.IdentityProvider = (*oidcProvider)(nil)
+
+type oidcProvider struct {
+	client   *http.Client
+	cfg      Config
+	authURL  string
+	tokenURL string
+	verifier oidc.TokenVerifier
+}
+
+// NewOIDC returns the OpenID Connect provider of the given issuer, whose
+// endpoints are found using the discovery. The ID tokens are verified
+// using the keys the issuer publishes, and have to be issued to the client.
+func NewOIDC(ctx context.Context, client *http.Client, issuer string, cfg Config) (users.IdentityProvider, error) {
+	var meta struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+	}
+	if err := fetch(ctx, client, strings.TrimSuffix(issuer, "/")+discoveryPath, "", &meta); err != nil {
+		return nil, errors.Wrap(errDiscovery, err)
+	}
+	if meta.Issuer != issuer || meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" {
+		return nil, errors.Wrap(errDiscovery, fmt.Errorf("invalid metadata of issuer '%s'", issuer))
+	}
+
+	return &oidcProvider{
+		client:   client,
+		cfg:      cfg,
+		authURL:  meta.AuthorizationEndpoint,
+		tokenURL: meta.TokenEndpoint,
+		verifier: jwt.NewExternalVerifier(client, []oidc.TrustedIssuer{{URL: issuer, Audience: cfg.ClientID}}),
+	}, nil
+}
+
+// NewGoogle returns the Google identity provider.
+func NewGoogle(ctx context.Context, client *http.Client, cfg Config) (users.IdentityProvider, error) {
+	return NewOIDC(ctx, client, GoogleIssuer, cfg)
+}
+
+func (p *oidcProvider) AuthURL(state string) string {
+	return authURL(p.authURL, p.cfg, oidcScope, state)
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code string) (users.Identity, error) {
+	tr, err := exchange(ctx, p.client, p.tokenURL, p.cfg, code)
+	if err != nil {
+		return users.Identity{}, err
+	}
+	if tr.IDToken == "" {
+		return users.Identity{}, errMissingIDToken
+	}
+
+	id, err := p.verifier.Verify(ctx, tr.IDToken)
+	if err != nil {
+		return users.Identity{}, err
+	}
+
+	return users.Identity{
+		Subject:       id.Subject,
+		Email:         id.Email,
+		EmailVerified: id.EmailVerified,
+	}, nil
+}
@@ -0,0 +1,128 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package oauth_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/oauth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	clientID     = "mainflux"
+	clientSecret = "secret"
+	redirectURL  = "http://localhost/oauth/oidc/callback"
+	email        = "user@example.com"
+)
+
+func TestOIDC(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err, fmt.Sprintf("generating RSA key expected to succeed: %s", err))
+
+	// The issuer publishes the discovery document and the JWKS, and issues
+	// the ID tokens in exchange for the authorization codes.
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 ts.URL,
+			"authorization_endpoint": ts.URL + "/authorize",
+			"token_endpoint":         ts.URL + "/token",
+			"jwks_uri":               ts.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		jwk := map[string]string{
+			"kty": "RSA",
+			"kid": "idp",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(priv.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(priv.E)).Bytes()),
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []interface{}{jwk}})
+	})
+	audiences := map[string]string{"code": clientID, "other-code": "other"}
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		aud, ok := audiences[r.PostFormValue("code")]
+		if !ok || r.PostFormValue("client_secret") != clientSecret || r.PostFormValue("redirect_uri") != redirectURL {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		claims := gojwt.MapClaims{
+			"iss":            ts.URL,
+			"sub":            "external-user",
+			"aud":            aud,
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"email":          email,
+			"email_verified": true,
+		}
+		token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "idp"
+		idToken, err := token.SignedString(priv)
+		require.Nil(t, err, fmt.Sprintf("signing token expected to succeed: %s", err))
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "access", "id_token": idToken})
+	})
+
+	cfg := oauth.Config{ClientID: clientID, ClientSecret: clientSecret, RedirectURL: redirectURL}
+	_, err = oauth.NewOIDC(context.Background(), ts.Client(), ts.URL+"/unknown", cfg)
+	assert.NotNil(t, err, "creating provider of unknown issuer expected to fail")
+
+	p, err := oauth.NewOIDC(context.Background(), ts.Client(), ts.URL, cfg)
+	require.Nil(t, err, fmt.Sprintf("creating provider expected to succeed: %s", err))
+
+	u, err := url.Parse(p.AuthURL("state"))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, ts.URL+"/authorize", fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path), "expected the authorization endpoint")
+	assert.Equal(t, clientID, u.Query().Get("client_id"), "expected the client ID")
+	assert.Equal(t, redirectURL, u.Query().Get("redirect_uri"), "expected the redirect URL")
+	assert.Equal(t, "state", u.Query().Get("state"), "expected the state")
+	assert.Equal(t, "openid email", u.Query().Get("scope"), "expected the openid scope")
+
+	cases := []struct {
+		desc string
+		code string
+		id   users.Identity
+		err  bool
+	}{
+		{
+			desc: "exchange valid code",
+			code: "code",
+			id:   users.Identity{Subject: "external-user", Email: email, EmailVerified: true},
+			err:  false,
+		},
+		{
+			desc: "exchange code for token issued to another client",
+			code: "other-code",
+			err:  true,
+		},
+		{
+			desc: "exchange invalid code",
+			code: "invalid",
+			err:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		id, err := p.Exchange(context.Background(), tc.code)
+		assert.Equal(t, tc.err, err != nil, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.id, id, fmt.Sprintf("%s: expected identity %v got %v", tc.desc, tc.id, id))
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// The rules of the password policy, reported by the PasswordError.
+const (
+	RuleMinLength = "min_length"
+	RuleUpper     = "upper"
+	RuleLower     = "lower"
+	RuleDigit     = "digit"
+	RuleSpecial   = "special"
+	RulePattern   = "pattern"
+	RuleBreached  = "breached"
+)
+
+const (
+	// resetChallengeSize is the number of the random bytes of the password
+	// reset challenge token.
+	resetChallengeSize = 32
+	// resetChallengeDuration is the time the user whose password expired
+	// has to change it after the login.
+	resetChallengeDuration = 15 * time.Minute
+)
+
+var (
+	// ErrPasswordReused indicates the new password reusing one of the latest
+	// passwords of the user.
+	ErrPasswordReused = errors.New("password used recently")
+
+	// ErrPasswordExpired indicates the login of the user whose password is
+	// older than the max age, who has to change the password with the reset
+	// token before logging in.
+	ErrPasswordExpired = errors.New("password expired")
+
+	// ErrBreachCheck indicates the failure to look the password up in the
+	// breached passwords.
+	ErrBreachCheck = errors.New("failed to check password breaches")
+)
+
+// ResetChallenge is the pending password change of the user who logged in
+// with the expired password, which is completed with the challenge token and
+// the new password. The challenge token isn't the auth key, so it can't be
+// used as the access token, and it changes the password only once.
+type ResetChallenge struct {
+	// TokenHash is the hash of the challenge token, which is stored only
+	// hashed.
+	TokenHash string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+// BreachChecker looks the passwords up in the known data breaches.
+type BreachChecker interface {
+	// Breached tells whether the password appeared in a data breach.
+	Breached(ctx context.Context, password string) (bool, error)
+}
+
+var _ errors.Error = (*PasswordError)(nil)
+
+// PasswordError is the ErrPasswordFormat listing the rules of the password
+// policy the password violates.
+type PasswordError struct {
+	Rules []string
+}
+
+func (e *PasswordError) Error() string {
+	return e.Msg() + " : " + e.Err().Error()
+}
+
+// Msg returns the message of ErrPasswordFormat, so the error is matched
+// against it.
+func (e *PasswordError) Msg() string {
+	return ErrPasswordFormat.Msg()
+}
+
+// Err returns the error listing the violated rules.
+func (e *PasswordError) Err() errors.Error {
+	return errors.New("violated rules: " + strings.Join(e.Rules, ", "))
+}
+
+// PasswordPolicy is the policy of the password strength and rotation.
+type PasswordPolicy struct {
+	// MinLength is the minimal number of characters of the password.
+	MinLength int
+	// RequireUpper, RequireLower, RequireDigit and RequireSpecial require
+	// the password to contain at least one character of the class.
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSpecial bool
+	// Pattern is the optional regular expression the password has to match.
+	Pattern *regexp.Regexp
+	// Breaches is the optional checker rejecting the breached passwords.
+	Breaches BreachChecker
+	// History is the number of the latest passwords of the user, including
+	// the current one, the new password can't reuse. The passwords are
+	// reused freely if the history is zero.
+	History uint32
+	// MaxAge is the age of the password after which the user is forced to
+	// change it. The passwords never expire if the max age is zero.
+	MaxAge time.Duration
+}
+
+// validate returns the PasswordError listing the violated rules if the
+// password doesn't comply with the policy. The password is checked against
+// the breaches only if it complies with all the other rules.
+func (p PasswordPolicy) validate(ctx context.Context, password string) error {
+	var upper, lower, digit, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			special = true
+		}
+	}
+
+	var rules []string
+	if utf8.RuneCountInString(password) < p.MinLength {
+		rules = append(rules, RuleMinLength)
+	}
+	if p.RequireUpper && !upper {
+		rules = append(rules, RuleUpper)
+	}
+	if p.RequireLower && !lower {
+		rules = append(rules, RuleLower)
+	}
+	if p.RequireDigit && !digit {
+		rules = append(rules, RuleDigit)
+	}
+	if p.RequireSpecial && !special {
+		rules = append(rules, RuleSpecial)
+	}
+	if p.Pattern != nil && !p.Pattern.MatchString(password) {
+		rules = append(rules, RulePattern)
+	}
+	if len(rules) > 0 {
+		return &PasswordError{Rules: rules}
+	}
+
+	if p.Breaches == nil {
+		return nil
+	}
+	breached, err := p.Breaches.Breached(ctx, password)
+	if err != nil {
+		return errors.Wrap(ErrBreachCheck, err)
+	}
+	if breached {
+		return &PasswordError{Rules: []string{RuleBreached}}
+	}
+	return nil
+}
+
+// expired tells whether the password changed at the given time expired.
+func (p PasswordPolicy) expired(changedAt, now time.Time) bool {
+	return p.MaxAge > 0 && now.Sub(changedAt) > p.MaxAge
+}
@@ -0,0 +1,143 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+)
+
+// activityRetention is the time the login attempts are kept for.
+const activityRetention = 90 * 24 * time.Hour
+
+var (
+	errSaveActivity     = errors.New("failed to save login attempt in database")
+	errRetrieveActivity = errors.New("failed to retrieve login attempts from database")
+)
+
+var _ users.ActivityRepository = (*activityRepository)(nil)
+
+type activityRepository struct {
+	db Database
+}
+
+// NewActivityRepo instantiates a PostgreSQL implementation of the login
+// activity repository.
+func NewActivityRepo(db Database) users.ActivityRepository {
+	return &activityRepository{
+		db: db,
+	}
+}
+
+func (ar activityRepository) Save(ctx context.Context, a users.LoginAttempt) error {
+	qDel := `DELETE FROM login_attempts WHERE user_id = :user_id AND created_at < :created_at`
+	old := dbLoginAttempt{UserID: a.UserID, CreatedAt: a.CreatedAt.Add(-activityRetention)}
+	if _, err := ar.db.NamedExecContext(ctx, qDel, old); err != nil {
+		return errors.Wrap(errSaveActivity, err)
+	}
+
+	q := `INSERT INTO login_attempts (id, user_id, success, reason, ip, user_agent, created_at)
+	      VALUES (:id, :user_id, :success, :reason, :ip, :user_agent, :created_at)`
+	if _, err := ar.db.NamedExecContext(ctx, q, toDBLoginAttempt(a)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && (pqErr.Code.Name() == errInvalid || pqErr.Code.Name() == errFK || pqErr.Code.Name() == errTruncation) {
+			return errors.Wrap(users.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errSaveActivity, err)
+	}
+
+	return nil
+}
+
+func (ar activityRepository) RetrieveByUser(ctx context.Context, userID string, offset, limit uint64) (users.ActivityPage, error) {
+	q := `SELECT id, user_id, success, reason, ip, user_agent, created_at FROM login_attempts
+	      WHERE user_id = :user_id ORDER BY created_at DESC LIMIT :limit OFFSET :offset`
+	params := map[string]interface{}{
+		"user_id": userID,
+		"limit":   limit,
+		"offset":  offset,
+	}
+
+	rows, err := ar.db.NamedQueryContext(ctx, q, params)
+	if err != nil {
+		return users.ActivityPage{}, errors.Wrap(errRetrieveActivity, err)
+	}
+	defer rows.Close()
+
+	attempts := []users.LoginAttempt{}
+	for rows.Next() {
+		dba := dbLoginAttempt{}
+		if err := rows.StructScan(&dba); err != nil {
+			return users.ActivityPage{}, errors.Wrap(errRetrieveActivity, err)
+		}
+		attempts = append(attempts, toLoginAttempt(dba))
+	}
+
+	cq := `SELECT COUNT(*) FROM login_attempts WHERE user_id = :user_id`
+	total, err := total(ctx, ar.db, cq, params)
+	if err != nil {
+		return users.ActivityPage{}, errors.Wrap(errRetrieveActivity, err)
+	}
+
+	page := users.ActivityPage{
+		Total:    total,
+		Offset:   offset,
+		Limit:    limit,
+		Attempts: attempts,
+	}
+
+	return page, nil
+}
+
+func (ar activityRepository) CountSuccessful(ctx context.Context, userID, ip string) (uint64, error) {
+	q := `SELECT COUNT(*) FROM login_attempts WHERE user_id = :user_id AND success`
+	if ip != "" {
+		q += ` AND ip = :ip`
+	}
+
+	n, err := total(ctx, ar.db, q, dbLoginAttempt{UserID: userID, IP: ip})
+	if err != nil {
+		return 0, errors.Wrap(errRetrieveActivity, err)
+	}
+
+	return n, nil
+}
+
+type dbLoginAttempt struct {
+	ID        string    `db:"id"`
+	UserID    string    `db:"user_id"`
+	Success   bool      `db:"success"`
+	Reason    string    `db:"reason"`
+	IP        string    `db:"ip"`
+	UserAgent string    `db:"user_agent"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func toDBLoginAttempt(a users.LoginAttempt) dbLoginAttempt {
+	return dbLoginAttempt{
+		ID:        a.ID,
+		UserID:    a.UserID,
+		Success:   a.Success,
+		Reason:    a.Reason,
+		IP:        a.IP,
+		UserAgent: a.UserAgent,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func toLoginAttempt(dba dbLoginAttempt) users.LoginAttempt {
+	return users.LoginAttempt{
+		ID:        dba.ID,
+		UserID:    dba.UserID,
+		Success:   dba.Success,
+		Reason:    dba.Reason,
+		IP:        dba.IP,
+		UserAgent: dba.UserAgent,
+		CreatedAt: dba.CreatedAt,
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+)
+
+const errFK = "foreign_key_violation"
+
+var (
+	errSaveExport     = errors.New("failed to save export in database")
+	errUpdateExport   = errors.New("failed to update export in database")
+	errRetrieveExport = errors.New("failed to retrieve export from database")
+)
+
+var _ users.ExportRepository = (*exportRepository)(nil)
+
+type exportRepository struct {
+	db Database
+}
+
+// NewExportRepo instantiates a PostgreSQL implementation of the user data
+// export repository.
+func NewExportRepo(db Database) users.ExportRepository {
+	return &exportRepository{
+		db: db,
+	}
+}
+
+func (er exportRepository) Save(ctx context.Context, e users.Export) error {
+	// The archives of the expired exports aren't kept, apart from the latest
+	// export of each user.
+	qDel := `DELETE FROM exports e WHERE e.expires_at < :expires_at
+	         AND e.created_at < (SELECT MAX(l.created_at) FROM exports l WHERE l.user_id = e.user_id)`
+	if _, err := er.db.NamedExecContext(ctx, qDel, dbExport{ExpiresAt: time.Now().UTC()}); err != nil {
+		return errors.Wrap(errSaveExport, err)
+	}
+
+	q := `INSERT INTO exports (id, user_id, status, error, created_at, expires_at)
+	      VALUES (:id, :user_id, :status, :error, :created_at, :expires_at)`
+	if _, err := er.db.NamedExecContext(ctx, q, toDBExport(e)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && (pqErr.Code.Name() == errInvalid || pqErr.Code.Name() == errFK) {
+			return errors.Wrap(users.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errSaveExport, err)
+	}
+
+	return nil
+}
+
+func (er exportRepository) Update(ctx context.Context, e users.Export) error {
+	q := `UPDATE exports SET status = :status, error = :error, archive = :archive, completed_at = :completed_at, expires_at = :expires_at
+	      WHERE id = :id`
+
+	res, err := er.db.NamedExecContext(ctx, q, toDBExport(e))
+	if err != nil {
+		return errors.Wrap(errUpdateExport, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateExport, err)
+	}
+	if cnt != 1 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (er exportRepository) RetrieveByID(ctx context.Context, id string) (users.Export, error) {
+	q := `SELECT id, user_id, status, error, archive, created_at, completed_at, expires_at FROM exports WHERE id = $1`
+
+	dbe := dbExport{}
+	if err := er.db.QueryRowxContext(ctx, q, id).StructScan(&dbe); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return users.Export{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.Export{}, errors.Wrap(errRetrieveExport, err)
+	}
+
+	return toExport(dbe), nil
+}
+
+func (er exportRepository) RetrieveLatest(ctx context.Context, userID string) (users.Export, error) {
+	q := `SELECT id, user_id, status, error, created_at, completed_at, expires_at FROM exports
+	      WHERE user_id = $1 ORDER BY created_at DESC LIMIT 1`
+
+	dbe := dbExport{}
+	if err := er.db.QueryRowxContext(ctx, q, userID).StructScan(&dbe); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if err == sql.ErrNoRows || ok && pqErr.Code.Name() == errInvalid {
+			return users.Export{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.Export{}, errors.Wrap(errRetrieveExport, err)
+	}
+
+	return toExport(dbe), nil
+}
+
+type dbExport struct {
+	ID          string       `db:"id"`
+	UserID      string       `db:"user_id"`
+	Status      string       `db:"status"`
+	Error       string       `db:"error"`
+	Archive     []byte       `db:"archive"`
+	CreatedAt   time.Time    `db:"created_at"`
+	CompletedAt sql.NullTime `db:"completed_at"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+}
+
+func toDBExport(e users.Export) dbExport {
+	return dbExport{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		Status:      e.Status,
+		Error:       e.Error,
+		Archive:     e.Archive,
+		CreatedAt:   e.CreatedAt,
+		CompletedAt: sql.NullTime{Time: e.CompletedAt, Valid: !e.CompletedAt.IsZero()},
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
+
+func toExport(dbe dbExport) users.Export {
+	return users.Export{
+		ID:          dbe.ID,
+		UserID:      dbe.UserID,
+		Status:      dbe.Status,
+		Error:       dbe.Error,
+		Archive:     dbe.Archive,
+		CreatedAt:   dbe.CreatedAt,
+		CompletedAt: dbe.CompletedAt.Time,
+		ExpiresAt:   dbe.ExpiresAt,
+	}
+}
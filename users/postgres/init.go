@@ -78,6 +78,213 @@ func migrateDB(db *sqlx.DB) error {
 					`ALTER TABLE IF EXISTS users ADD PRIMARY KEY (id)`,
 				},
 			},
+			{
+				Id: "users_5",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS labels JSONB NOT NULL DEFAULT '{}'`,
+					`CREATE INDEX IF NOT EXISTS users_labels ON users USING GIN (labels)`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS labels`,
+				},
+			},
+			{
+				Id: "users_6",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS exports (
+						id           UUID PRIMARY KEY,
+						user_id      UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+						status       VARCHAR(32) NOT NULL,
+						error        TEXT NOT NULL DEFAULT '',
+						archive      BYTEA,
+						created_at   TIMESTAMPTZ NOT NULL,
+						completed_at TIMESTAMPTZ,
+						expires_at   TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS exports_user_idx ON exports (user_id, created_at)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS exports`,
+				},
+			},
+			{
+				Id: "users_7",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT TRUE`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS verified`,
+				},
+			},
+			{
+				Id: "users_8",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS disabled`,
+				},
+			},
+			{
+				Id: "users_9",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS mfa (
+						user_id        UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+						secret         VARCHAR(64) NOT NULL,
+						enabled        BOOLEAN NOT NULL DEFAULT FALSE,
+						recovery_codes TEXT[] NOT NULL DEFAULT '{}'
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS mfa`,
+				},
+			},
+			{
+				Id: "users_10",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS identities (
+						provider VARCHAR(64) NOT NULL,
+						subject  VARCHAR(254) NOT NULL,
+						user_id  UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+						PRIMARY KEY (provider, subject)
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS identities`,
+				},
+			},
+			{
+				Id: "users_11",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS failed_logins INTEGER NOT NULL DEFAULT 0`,
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS failed_logins`,
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS locked_until`,
+				},
+			},
+			{
+				Id: "users_12",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()`,
+					`CREATE TABLE IF NOT EXISTS password_history (
+						id         BIGSERIAL PRIMARY KEY,
+						user_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+						password   VARCHAR(60) NOT NULL,
+						created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+					)`,
+					`CREATE INDEX IF NOT EXISTS password_history_user_id ON password_history (user_id)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS password_history`,
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS password_changed_at`,
+				},
+			},
+			{
+				Id: "users_13",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS invitations (
+						id            UUID PRIMARY KEY,
+						email         VARCHAR(254) NOT NULL,
+						group_id      VARCHAR(254) NOT NULL DEFAULT '',
+						inviter_id    UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+						inviter_email VARCHAR(254) NOT NULL,
+						token_hash    CHAR(64) UNIQUE NOT NULL,
+						created_at    TIMESTAMPTZ NOT NULL,
+						expires_at    TIMESTAMPTZ NOT NULL
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS invitations`,
+				},
+			},
+			{
+				Id: "users_14",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()`,
+					`CREATE INDEX IF NOT EXISTS users_created_at ON users (created_at)`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS created_at`,
+				},
+			},
+			{
+				Id: "users_15",
+				Up: []string{
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS first_name VARCHAR(256) NOT NULL DEFAULT ''`,
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS last_name VARCHAR(256) NOT NULL DEFAULT ''`,
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS display_name VARCHAR(256) NOT NULL DEFAULT ''`,
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS phone VARCHAR(16) NOT NULL DEFAULT ''`,
+					`ALTER TABLE IF EXISTS users ADD COLUMN IF NOT EXISTS locale VARCHAR(16) NOT NULL DEFAULT ''`,
+				},
+				Down: []string{
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS first_name`,
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS last_name`,
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS display_name`,
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS phone`,
+					`ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS locale`,
+				},
+			},
+			{
+				Id: "users_16",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS login_attempts (
+						id          UUID PRIMARY KEY,
+						user_id     UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+						success     BOOLEAN NOT NULL,
+						reason      VARCHAR(32) NOT NULL DEFAULT '',
+						ip          VARCHAR(64) NOT NULL DEFAULT '',
+						user_agent  VARCHAR(254) NOT NULL DEFAULT '',
+						created_at  TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS login_attempts_user_id_created_at ON login_attempts (user_id, created_at)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS login_attempts`,
+				},
+			},
+			{
+				Id: "users_17",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS password_resets (
+						user_id     UUID PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+						nonce       VARCHAR(64) NOT NULL,
+						created_at  TIMESTAMPTZ NOT NULL
+					)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS password_resets`,
+				},
+			},
+			{
+				Id: "users_18",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS mfa_challenges (
+						token_hash  VARCHAR(64) PRIMARY KEY,
+						user_id     UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+						expires_at  TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS mfa_challenges_user_id ON mfa_challenges (user_id)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS mfa_challenges`,
+				},
+			},
+			{
+				Id: "users_19",
+				Up: []string{
+					`CREATE TABLE IF NOT EXISTS reset_challenges (
+						token_hash  VARCHAR(64) PRIMARY KEY,
+						user_id     UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+						expires_at  TIMESTAMPTZ NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS reset_challenges_user_id ON reset_challenges (user_id)`,
+				},
+				Down: []string{
+					`DROP TABLE IF EXISTS reset_challenges`,
+				},
+			},
 		},
 	}
 
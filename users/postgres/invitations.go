@@ -0,0 +1,123 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+)
+
+var (
+	errSaveInvitation     = errors.New("failed to save invitation in database")
+	errRetrieveInvitation = errors.New("failed to retrieve invitation from database")
+	errRemoveInvitation   = errors.New("failed to remove invitation from database")
+)
+
+var _ users.InvitationRepository = (*invitationRepository)(nil)
+
+type invitationRepository struct {
+	db Database
+}
+
+// NewInvitationRepo instantiates a PostgreSQL implementation of the
+// invitation repository.
+func NewInvitationRepo(db Database) users.InvitationRepository {
+	return &invitationRepository{
+		db: db,
+	}
+}
+
+func (ir invitationRepository) Save(ctx context.Context, inv users.Invitation) error {
+	qDel := `DELETE FROM invitations WHERE expires_at < :expires_at`
+	if _, err := ir.db.NamedExecContext(ctx, qDel, dbInvitation{ExpiresAt: time.Now().UTC()}); err != nil {
+		return errors.Wrap(errSaveInvitation, err)
+	}
+
+	q := `INSERT INTO invitations (id, email, group_id, inviter_id, inviter_email, token_hash, created_at, expires_at)
+	      VALUES (:id, :email, :group_id, :inviter_id, :inviter_email, :token_hash, :created_at, :expires_at)`
+	if _, err := ir.db.NamedExecContext(ctx, q, toDBInvitation(inv)); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && (pqErr.Code.Name() == errInvalid || pqErr.Code.Name() == errFK) {
+			return errors.Wrap(users.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errSaveInvitation, err)
+	}
+
+	return nil
+}
+
+func (ir invitationRepository) RetrieveByToken(ctx context.Context, tokenHash string) (users.Invitation, error) {
+	q := `SELECT id, email, group_id, inviter_id, inviter_email, token_hash, created_at, expires_at FROM invitations
+	      WHERE token_hash = $1`
+
+	dbi := dbInvitation{}
+	if err := ir.db.QueryRowxContext(ctx, q, tokenHash).StructScan(&dbi); err != nil {
+		if err == sql.ErrNoRows {
+			return users.Invitation{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.Invitation{}, errors.Wrap(errRetrieveInvitation, err)
+	}
+
+	return toInvitation(dbi), nil
+}
+
+func (ir invitationRepository) Remove(ctx context.Context, id string) error {
+	q := `DELETE FROM invitations WHERE id = :id`
+
+	res, err := ir.db.NamedExecContext(ctx, q, dbInvitation{ID: id})
+	if err != nil {
+		return errors.Wrap(errRemoveInvitation, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errRemoveInvitation, err)
+	}
+	if cnt != 1 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+type dbInvitation struct {
+	ID           string    `db:"id"`
+	Email        string    `db:"email"`
+	GroupID      string    `db:"group_id"`
+	InviterID    string    `db:"inviter_id"`
+	InviterEmail string    `db:"inviter_email"`
+	TokenHash    string    `db:"token_hash"`
+	CreatedAt    time.Time `db:"created_at"`
+	ExpiresAt    time.Time `db:"expires_at"`
+}
+
+func toDBInvitation(inv users.Invitation) dbInvitation {
+	return dbInvitation{
+		ID:           inv.ID,
+		Email:        inv.Email,
+		GroupID:      inv.GroupID,
+		InviterID:    inv.InviterID,
+		InviterEmail: inv.InviterEmail,
+		TokenHash:    inv.TokenHash,
+		CreatedAt:    inv.CreatedAt,
+		ExpiresAt:    inv.ExpiresAt,
+	}
+}
+
+func toInvitation(dbi dbInvitation) users.Invitation {
+	return users.Invitation{
+		ID:           dbi.ID,
+		Email:        dbi.Email,
+		GroupID:      dbi.GroupID,
+		InviterID:    dbi.InviterID,
+		InviterEmail: dbi.InviterEmail,
+		TokenHash:    dbi.TokenHash,
+		CreatedAt:    dbi.CreatedAt,
+		ExpiresAt:    dbi.ExpiresAt,
+	}
+}
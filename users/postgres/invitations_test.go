@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvitations(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	userRepo := postgres.NewUserRepo(dbMiddleware)
+	repo := postgres.NewInvitationRepo(dbMiddleware)
+
+	inviterID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, err = userRepo.Save(context.Background(), users.User{ID: inviterID, Email: "inviter@example.com", Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	id, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	now := time.Now().UTC().Round(time.Millisecond)
+	inv := users.Invitation{
+		ID:           id,
+		Email:        "invited@example.com",
+		GroupID:      "group",
+		InviterID:    inviterID,
+		InviterEmail: "inviter@example.com",
+		TokenHash:    strings.Repeat("a", 64),
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(time.Hour),
+	}
+
+	err = repo.Save(context.Background(), users.Invitation{ID: unknownID, Email: inv.Email, InviterID: unknownID, TokenHash: strings.Repeat("b", 64), CreatedAt: now, ExpiresAt: now})
+	assert.True(t, errors.Contains(err, users.ErrMalformedEntity), fmt.Sprintf("save invitation of unknown inviter: expected %s got %s\n", users.ErrMalformedEntity, err))
+
+	err = repo.Save(context.Background(), inv)
+	require.Nil(t, err, fmt.Sprintf("save invitation: unexpected error: %s", err))
+
+	saved, err := repo.RetrieveByToken(context.Background(), inv.TokenHash)
+	assert.Nil(t, err, fmt.Sprintf("retrieve invitation: unexpected error: %s", err))
+	assert.True(t, saved.ExpiresAt.Equal(inv.ExpiresAt), fmt.Sprintf("retrieve invitation: expected expiration %s got %s\n", inv.ExpiresAt, saved.ExpiresAt))
+	saved.CreatedAt, saved.ExpiresAt = inv.CreatedAt, inv.ExpiresAt
+	assert.Equal(t, inv, saved, fmt.Sprintf("retrieve invitation: expected %v got %v\n", inv, saved))
+
+	_, err = repo.RetrieveByToken(context.Background(), strings.Repeat("c", 64))
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("retrieve unknown invitation: expected %s got %s\n", users.ErrNotFound, err))
+
+	err = repo.Remove(context.Background(), inv.ID)
+	assert.Nil(t, err, fmt.Sprintf("remove invitation: unexpected error: %s", err))
+	err = repo.Remove(context.Background(), inv.ID)
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("remove removed invitation: expected %s got %s\n", users.ErrNotFound, err))
+	_, err = repo.RetrieveByToken(context.Background(), inv.TokenHash)
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("retrieve removed invitation: expected %s got %s\n", users.ErrNotFound, err))
+}
@@ -10,10 +10,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/users"
 )
 
@@ -27,8 +29,22 @@ var (
 	errUpdateDB         = errors.New("Update user email to DB failed")
 	errSelectDb         = errors.New("Select from DB failed")
 	errUpdateUserDB     = errors.New("Update user metadata to DB failed")
+	errUpdateProfileDB  = errors.New("Update user profile to DB failed")
 	errRetrieveDB       = errors.New("Retreiving from DB failed")
 	errUpdatePasswordDB = errors.New("Update password to DB failed")
+	errSaveHistoryDB    = errors.New("Save password history to DB failed")
+	errVerifyDB         = errors.New("Verify user email in DB failed")
+	errUpdateStatusDB   = errors.New("Update user status in DB failed")
+	errUpdateLockoutDB  = errors.New("Update user lockout in DB failed")
+	errDeleteDB         = errors.New("Delete user from DB failed")
+	errSaveMFADB        = errors.New("Save user MFA to DB failed")
+	errSaveChallengeDB  = errors.New("Save MFA challenge to DB failed")
+	errConsumeChallDB   = errors.New("Consume MFA challenge from DB failed")
+	errSaveIdentityDB   = errors.New("Save user identity to DB failed")
+	errSaveResetDB      = errors.New("Save password reset nonce to DB failed")
+	errConsumeResetDB   = errors.New("Consume password reset nonce from DB failed")
+	errSaveResetChallDB = errors.New("Save password reset challenge to DB failed")
+	errConsumeRCDB      = errors.New("Consume password reset challenge from DB failed")
 	errMarshal          = errors.New("Failed to marshal metadata")
 	errUnmarshal        = errors.New("Failed to unmarshal metadata")
 )
@@ -50,7 +66,8 @@ func NewUserRepo(db Database) users.UserRepository {
 }
 
 func (ur userRepository) Save(ctx context.Context, user users.User) (string, error) {
-	q := `INSERT INTO users (email, password, id, metadata) VALUES (:email, :password, :id, :metadata) RETURNING id`
+	q := `INSERT INTO users (email, password, id, metadata, labels, verified, first_name, last_name, display_name, phone, locale)
+	      VALUES (:email, :password, :id, :metadata, :labels, :verified, :first_name, :last_name, :display_name, :phone, :locale) RETURNING id`
 	if user.ID == "" || user.Email == "" {
 		return "", users.ErrMalformedEntity
 	}
@@ -99,7 +116,7 @@ func (ur userRepository) Update(ctx context.Context, user users.User) error {
 }
 
 func (ur userRepository) UpdateUser(ctx context.Context, user users.User) error {
-	q := `UPDATE users SET metadata = :metadata WHERE email = :email`
+	q := `UPDATE users SET metadata = :metadata, labels = :labels WHERE email = :email`
 
 	dbu, err := toDBUser(user)
 	if err != nil {
@@ -113,8 +130,34 @@ func (ur userRepository) UpdateUser(ctx context.Context, user users.User) error
 	return nil
 }
 
+func (ur userRepository) UpdateProfile(ctx context.Context, id string, p users.Profile) error {
+	q := `UPDATE users SET first_name = :first_name, last_name = :last_name, display_name = :display_name,
+	      phone = :phone, locale = :locale WHERE id = :id`
+
+	dbu := dbUser{ID: id}
+	setDBProfile(&dbu, p)
+	res, err := ur.db.NamedExecContext(ctx, q, dbu)
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && (pqErr.Code.Name() == errInvalid || pqErr.Code.Name() == errTruncation) {
+			return errors.Wrap(users.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errUpdateProfileDB, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateProfileDB, err)
+	}
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
 func (ur userRepository) RetrieveByEmail(ctx context.Context, email string) (users.User, error) {
-	q := `SELECT id, password, metadata FROM users WHERE email = $1`
+	q := `SELECT id, password, metadata, labels, verified, disabled, failed_logins, locked_until, password_changed_at, created_at,
+	      first_name, last_name, display_name, phone, locale FROM users WHERE email = $1`
 
 	dbu := dbUser{
 		Email: email,
@@ -132,7 +175,8 @@ func (ur userRepository) RetrieveByEmail(ctx context.Context, email string) (use
 }
 
 func (ur userRepository) RetrieveByID(ctx context.Context, id string) (users.User, error) {
-	q := `SELECT email, password, metadata FROM users WHERE id = $1`
+	q := `SELECT email, password, metadata, labels, verified, disabled, failed_logins, locked_until, password_changed_at, created_at,
+	      first_name, last_name, display_name, phone, locale FROM users WHERE id = $1`
 
 	dbu := dbUser{
 		ID: id,
@@ -149,13 +193,18 @@ func (ur userRepository) RetrieveByID(ctx context.Context, id string) (users.Use
 	return toUser(dbu)
 }
 
-func (ur userRepository) RetrieveAll(ctx context.Context, offset, limit uint64, userIDs []string, email string, um users.Metadata) (users.UserPage, error) {
-	eq, ep, err := createEmailQuery("", email)
+func (ur userRepository) RetrieveAll(ctx context.Context, userIDs []string, pm users.PageMetadata) (users.UserPage, error) {
+	eq, ep, err := createEmailQuery("", pm.Email)
+	if err != nil {
+		return users.UserPage{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	mq, mp, err := createMetadataQuery("", pm.Metadata)
 	if err != nil {
 		return users.UserPage{}, errors.Wrap(errRetrieveDB, err)
 	}
 
-	mq, mp, err := createMetadataQuery("", um)
+	sq, sp, err := createSelectorQuery("", pm.Selector)
 	if err != nil {
 		return users.UserPage{}, errors.Wrap(errRetrieveDB, err)
 	}
@@ -168,6 +217,18 @@ func (ur userRepository) RetrieveAll(ctx context.Context, offset, limit uint64,
 	if mq != "" {
 		query = append(query, mq)
 	}
+	if sq != "" {
+		query = append(query, sq)
+	}
+	if stq := createStatusQuery(pm.Status); stq != "" {
+		query = append(query, stq)
+	}
+	if !pm.CreatedFrom.IsZero() {
+		query = append(query, "created_at >= :created_from")
+	}
+	if !pm.CreatedTo.IsZero() {
+		query = append(query, "created_at <= :created_to")
+	}
 
 	if len(userIDs) > 0 {
 		query = append(query, fmt.Sprintf("id IN ('%s')", strings.Join(userIDs, "','")))
@@ -176,12 +237,19 @@ func (ur userRepository) RetrieveAll(ctx context.Context, offset, limit uint64,
 		emq = fmt.Sprintf(" WHERE %s", strings.Join(query, " AND "))
 	}
 
-	q := fmt.Sprintf(`SELECT id, email, metadata FROM users %s ORDER BY email LIMIT :limit OFFSET :offset;`, emq)
+	q := fmt.Sprintf(`SELECT id, email, metadata, labels, verified, disabled, failed_logins, locked_until, created_at,
+	                  first_name, last_name, display_name, phone, locale FROM users %s
+	                  ORDER BY %s %s, id LIMIT :limit OFFSET :offset;`, emq, getOrderQuery(pm.Order), getDirQuery(pm.Dir))
 	params := map[string]interface{}{
-		"limit":    limit,
-		"offset":   offset,
-		"email":    ep,
-		"metadata": mp,
+		"limit":        pm.Limit,
+		"offset":       pm.Offset,
+		"email":        ep,
+		"metadata":     mp,
+		"created_from": pm.CreatedFrom,
+		"created_to":   pm.CreatedTo,
+	}
+	for k, v := range sp {
+		params[k] = v
 	}
 
 	rows, err := ur.db.NamedQueryContext(ctx, q, params)
@@ -216,8 +284,8 @@ func (ur userRepository) RetrieveAll(ctx context.Context, offset, limit uint64,
 		Users: items,
 		PageMetadata: users.PageMetadata{
 			Total:  total,
-			Offset: offset,
-			Limit:  limit,
+			Offset: pm.Offset,
+			Limit:  pm.Limit,
 		},
 	}
 
@@ -225,7 +293,7 @@ func (ur userRepository) RetrieveAll(ctx context.Context, offset, limit uint64,
 }
 
 func (ur userRepository) UpdatePassword(ctx context.Context, email, password string) error {
-	q := `UPDATE users SET password = :password WHERE email = :email`
+	q := `UPDATE users SET password = :password, password_changed_at = NOW() WHERE email = :email`
 
 	db := dbUser{
 		Email:    email,
@@ -239,6 +307,410 @@ func (ur userRepository) UpdatePassword(ctx context.Context, email, password str
 	return nil
 }
 
+func (ur userRepository) UpdatePasswordHash(ctx context.Context, id, hash string) error {
+	q := `UPDATE users SET password = :password WHERE id = :id`
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbUser{ID: id, Password: hash})
+	if err != nil {
+		return errors.Wrap(errUpdatePasswordDB, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdatePasswordDB, err)
+	}
+	if cnt != 1 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ur userRepository) SavePasswordHistory(ctx context.Context, userID, password string, limit uint32) error {
+	q := `INSERT INTO password_history (user_id, password) VALUES (:user_id, :password)`
+	qDel := `DELETE FROM password_history WHERE user_id = :user_id AND id NOT IN
+	         (SELECT id FROM password_history WHERE user_id = :user_id ORDER BY id DESC LIMIT :limit)`
+
+	dbh := dbPasswordHistory{
+		UserID:   userID,
+		Password: password,
+		Limit:    limit,
+	}
+	if _, err := ur.db.NamedExecContext(ctx, q, dbh); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && (pqErr.Code.Name() == errInvalid || pqErr.Code.Name() == errFK) {
+			return errors.Wrap(users.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errSaveHistoryDB, err)
+	}
+	if _, err := ur.db.NamedExecContext(ctx, qDel, dbh); err != nil {
+		return errors.Wrap(errSaveHistoryDB, err)
+	}
+
+	return nil
+}
+
+func (ur userRepository) RetrievePasswordHistory(ctx context.Context, userID string, limit uint32) ([]string, error) {
+	q := `SELECT password FROM password_history WHERE user_id = :user_id ORDER BY id DESC LIMIT :limit`
+
+	rows, err := ur.db.NamedQueryContext(ctx, q, dbPasswordHistory{UserID: userID, Limit: limit})
+	if err != nil {
+		return nil, errors.Wrap(errRetrieveDB, err)
+	}
+	defer rows.Close()
+
+	var passwords []string
+	for rows.Next() {
+		var password string
+		if err := rows.Scan(&password); err != nil {
+			return nil, errors.Wrap(errRetrieveDB, err)
+		}
+		passwords = append(passwords, password)
+	}
+
+	return passwords, nil
+}
+
+func (ur userRepository) Verify(ctx context.Context, email string) error {
+	q := `UPDATE users SET verified = TRUE WHERE email = :email`
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbUser{Email: email})
+	if err != nil {
+		return errors.Wrap(errVerifyDB, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errVerifyDB, err)
+	}
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ur userRepository) Disable(ctx context.Context, id string) error {
+	return ur.setDisabled(ctx, id, true)
+}
+
+func (ur userRepository) Enable(ctx context.Context, id string) error {
+	return ur.setDisabled(ctx, id, false)
+}
+
+func (ur userRepository) setDisabled(ctx context.Context, id string, disabled bool) error {
+	q := `UPDATE users SET disabled = :disabled WHERE id = :id`
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbUser{ID: id, Disabled: disabled})
+	if err != nil {
+		return errors.Wrap(errUpdateStatusDB, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateStatusDB, err)
+	}
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ur userRepository) IncrementFailedLogins(ctx context.Context, id string) (uint32, error) {
+	q := `UPDATE users SET failed_logins = failed_logins + 1 WHERE id = $1 RETURNING failed_logins`
+
+	var cnt uint32
+	if err := ur.db.QueryRowxContext(ctx, q, id).Scan(&cnt); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errors.Wrap(users.ErrNotFound, err)
+		}
+		return 0, errors.Wrap(errUpdateLockoutDB, err)
+	}
+
+	return cnt, nil
+}
+
+func (ur userRepository) Lock(ctx context.Context, id string, until time.Time) error {
+	return ur.setLockout(ctx, id, sql.NullTime{Time: until, Valid: true})
+}
+
+func (ur userRepository) Unlock(ctx context.Context, id string) error {
+	return ur.setLockout(ctx, id, sql.NullTime{})
+}
+
+func (ur userRepository) setLockout(ctx context.Context, id string, until sql.NullTime) error {
+	q := `UPDATE users SET locked_until = :locked_until, failed_logins = 0 WHERE id = :id`
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbUser{ID: id, Locked: until})
+	if err != nil {
+		return errors.Wrap(errUpdateLockoutDB, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errUpdateLockoutDB, err)
+	}
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+// Delete removes the user, while the exports of the user are removed by the
+// database along with it.
+func (ur userRepository) Delete(ctx context.Context, id string) error {
+	q := `DELETE FROM users WHERE id = :id`
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbUser{ID: id})
+	if err != nil {
+		return errors.Wrap(errDeleteDB, err)
+	}
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errDeleteDB, err)
+	}
+	if cnt == 0 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ur userRepository) SaveMFA(ctx context.Context, mfa users.MFA) error {
+	q := `INSERT INTO mfa (user_id, secret, enabled, recovery_codes) VALUES (:user_id, :secret, :enabled, :recovery_codes)
+	      ON CONFLICT (user_id) DO UPDATE SET secret = :secret, enabled = :enabled, recovery_codes = :recovery_codes`
+
+	dbm := dbMFA{
+		UserID:        mfa.UserID,
+		Secret:        mfa.Secret,
+		Enabled:       mfa.Enabled,
+		RecoveryCodes: pq.StringArray(mfa.RecoveryCodes),
+	}
+	if dbm.RecoveryCodes == nil {
+		dbm.RecoveryCodes = pq.StringArray{}
+	}
+	if _, err := ur.db.NamedExecContext(ctx, q, dbm); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && (pqErr.Code.Name() == errInvalid || pqErr.Code.Name() == errFK) {
+			return errors.Wrap(users.ErrMalformedEntity, err)
+		}
+		return errors.Wrap(errSaveMFADB, err)
+	}
+
+	return nil
+}
+
+func (ur userRepository) RetrieveMFA(ctx context.Context, userID string) (users.MFA, error) {
+	q := `SELECT user_id, secret, enabled, recovery_codes FROM mfa WHERE user_id = $1`
+
+	var dbm dbMFA
+	if err := ur.db.QueryRowxContext(ctx, q, userID).StructScan(&dbm); err != nil {
+		if err == sql.ErrNoRows {
+			return users.MFA{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.MFA{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return users.MFA{
+		UserID:        dbm.UserID,
+		Secret:        dbm.Secret,
+		Enabled:       dbm.Enabled,
+		RecoveryCodes: []string(dbm.RecoveryCodes),
+	}, nil
+}
+
+func (ur userRepository) SaveIdentity(ctx context.Context, id users.Identity) error {
+	q := `INSERT INTO identities (provider, subject, user_id) VALUES (:provider, :subject, :user_id)`
+
+	dbi := dbIdentity{
+		Provider: id.Provider,
+		Subject:  id.Subject,
+		UserID:   id.UserID,
+	}
+	if _, err := ur.db.NamedExecContext(ctx, q, dbi); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation, errFK:
+				return errors.Wrap(users.ErrMalformedEntity, err)
+			case errDuplicate:
+				return errors.Wrap(users.ErrConflict, err)
+			}
+		}
+		return errors.Wrap(errSaveIdentityDB, err)
+	}
+
+	return nil
+}
+
+func (ur userRepository) RetrieveByIdentity(ctx context.Context, provider, subject string) (users.User, error) {
+	q := `SELECT u.id, u.email, u.password, u.metadata, u.labels, u.verified, u.disabled, u.failed_logins, u.locked_until, u.password_changed_at, u.created_at,
+	      u.first_name, u.last_name, u.display_name, u.phone, u.locale FROM users u
+	      JOIN identities i ON i.user_id = u.id WHERE i.provider = $1 AND i.subject = $2`
+
+	var dbu dbUser
+	if err := ur.db.QueryRowxContext(ctx, q, provider, subject).StructScan(&dbu); err != nil {
+		if err == sql.ErrNoRows {
+			return users.User{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.User{}, errors.Wrap(errRetrieveDB, err)
+	}
+
+	return toUser(dbu)
+}
+
+func (ur userRepository) SaveMFAChallenge(ctx context.Context, c users.MFAChallenge) error {
+	// The expired challenges of the user are removed along the way.
+	q := `DELETE FROM mfa_challenges WHERE user_id = :user_id AND expires_at < now()`
+
+	dbc := dbChallenge{TokenHash: c.TokenHash, UserID: c.UserID, ExpiresAt: c.ExpiresAt}
+	if _, err := ur.db.NamedExecContext(ctx, q, dbc); err != nil {
+		return errors.Wrap(errSaveChallengeDB, err)
+	}
+
+	q = `INSERT INTO mfa_challenges (token_hash, user_id, expires_at) VALUES (:token_hash, :user_id, :expires_at)`
+	if _, err := ur.db.NamedExecContext(ctx, q, dbc); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(users.ErrMalformedEntity, err)
+			case errFK:
+				return errors.Wrap(users.ErrNotFound, err)
+			}
+		}
+		return errors.Wrap(errSaveChallengeDB, err)
+	}
+
+	return nil
+}
+
+func (ur userRepository) ConsumeMFAChallenge(ctx context.Context, tokenHash string) (users.MFAChallenge, error) {
+	q := `DELETE FROM mfa_challenges WHERE token_hash = $1 RETURNING token_hash, user_id, expires_at`
+
+	var dbc dbChallenge
+	if err := ur.db.QueryRowxContext(ctx, q, tokenHash).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return users.MFAChallenge{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.MFAChallenge{}, errors.Wrap(errConsumeChallDB, err)
+	}
+
+	return users.MFAChallenge{TokenHash: dbc.TokenHash, UserID: dbc.UserID, ExpiresAt: dbc.ExpiresAt}, nil
+}
+
+func (ur userRepository) SaveResetNonce(ctx context.Context, userID, nonce string) error {
+	q := `INSERT INTO password_resets (user_id, nonce, created_at) VALUES (:user_id, :nonce, now())
+	      ON CONFLICT (user_id) DO UPDATE SET nonce = EXCLUDED.nonce, created_at = EXCLUDED.created_at`
+
+	dbr := dbPasswordReset{UserID: userID, Nonce: nonce}
+	if _, err := ur.db.NamedExecContext(ctx, q, dbr); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(users.ErrMalformedEntity, err)
+			case errFK:
+				return errors.Wrap(users.ErrNotFound, err)
+			}
+		}
+		return errors.Wrap(errSaveResetDB, err)
+	}
+
+	return nil
+}
+
+func (ur userRepository) ConsumeResetNonce(ctx context.Context, userID, nonce string) error {
+	q := `DELETE FROM password_resets WHERE user_id = :user_id AND nonce = :nonce`
+
+	res, err := ur.db.NamedExecContext(ctx, q, dbPasswordReset{UserID: userID, Nonce: nonce})
+	if err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok && pqErr.Code.Name() == errInvalid {
+			return errors.Wrap(users.ErrNotFound, err)
+		}
+		return errors.Wrap(errConsumeResetDB, err)
+	}
+
+	cnt, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(errConsumeResetDB, err)
+	}
+	if cnt != 1 {
+		return users.ErrNotFound
+	}
+
+	return nil
+}
+
+func (ur userRepository) SaveResetChallenge(ctx context.Context, c users.ResetChallenge) error {
+	// The expired challenges of the user are removed along the way.
+	q := `DELETE FROM reset_challenges WHERE user_id = :user_id AND expires_at < now()`
+
+	dbc := dbChallenge{TokenHash: c.TokenHash, UserID: c.UserID, ExpiresAt: c.ExpiresAt}
+	if _, err := ur.db.NamedExecContext(ctx, q, dbc); err != nil {
+		return errors.Wrap(errSaveResetChallDB, err)
+	}
+
+	q = `INSERT INTO reset_challenges (token_hash, user_id, expires_at) VALUES (:token_hash, :user_id, :expires_at)`
+	if _, err := ur.db.NamedExecContext(ctx, q, dbc); err != nil {
+		pqErr, ok := err.(*pq.Error)
+		if ok {
+			switch pqErr.Code.Name() {
+			case errInvalid, errTruncation:
+				return errors.Wrap(users.ErrMalformedEntity, err)
+			case errFK:
+				return errors.Wrap(users.ErrNotFound, err)
+			}
+		}
+		return errors.Wrap(errSaveResetChallDB, err)
+	}
+
+	return nil
+}
+
+func (ur userRepository) ConsumeResetChallenge(ctx context.Context, tokenHash string) (users.ResetChallenge, error) {
+	q := `DELETE FROM reset_challenges WHERE token_hash = $1 RETURNING token_hash, user_id, expires_at`
+
+	var dbc dbChallenge
+	if err := ur.db.QueryRowxContext(ctx, q, tokenHash).StructScan(&dbc); err != nil {
+		if err == sql.ErrNoRows {
+			return users.ResetChallenge{}, errors.Wrap(users.ErrNotFound, err)
+		}
+		return users.ResetChallenge{}, errors.Wrap(errConsumeRCDB, err)
+	}
+
+	return users.ResetChallenge{TokenHash: dbc.TokenHash, UserID: dbc.UserID, ExpiresAt: dbc.ExpiresAt}, nil
+}
+
+type dbIdentity struct {
+	Provider string `db:"provider"`
+	Subject  string `db:"subject"`
+	UserID   string `db:"user_id"`
+}
+
+type dbChallenge struct {
+	TokenHash string    `db:"token_hash"`
+	UserID    string    `db:"user_id"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+type dbPasswordReset struct {
+	UserID string `db:"user_id"`
+	Nonce  string `db:"nonce"`
+}
+
+type dbPasswordHistory struct {
+	UserID   string `db:"user_id"`
+	Password string `db:"password"`
+	Limit    uint32 `db:"limit"`
+}
+
+type dbMFA struct {
+	UserID        string         `db:"user_id"`
+	Secret        string         `db:"secret"`
+	Enabled       bool           `db:"enabled"`
+	RecoveryCodes pq.StringArray `db:"recovery_codes"`
+}
+
 // dbMetadata type for handling metadata properly in database/sql
 type dbMetadata map[string]interface{}
 
@@ -273,12 +745,47 @@ func (m dbMetadata) Value() (driver.Value, error) {
 	return b, err
 }
 
+// dbLabels type for handling labels properly in database/sql
+type dbLabels labels.Labels
+
+// Scan - Implement the database/sql scanner interface
+func (l *dbLabels) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return users.ErrScanMetadata
+	}
+
+	return json.Unmarshal(b, l)
+}
+
+// Value Implements valuer, storing the missing labels as the empty ones
+func (l dbLabels) Value() (driver.Value, error) {
+	if len(l) == 0 {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(l)
+}
+
 type dbUser struct {
 	ID       string       `db:"id"`
 	Email    string       `db:"email"`
 	Password string       `db:"password"`
 	Metadata []byte       `db:"metadata"`
+	Labels   dbLabels     `db:"labels"`
 	Groups   []auth.Group `db:"groups"`
+	Verified bool         `db:"verified"`
+	Disabled bool         `db:"disabled"`
+	Failed   uint32       `db:"failed_logins"`
+	Locked   sql.NullTime `db:"locked_until"`
+	Changed  time.Time    `db:"password_changed_at"`
+	Created  time.Time    `db:"created_at"`
+
+	FirstName   string `db:"first_name"`
+	LastName    string `db:"last_name"`
+	DisplayName string `db:"display_name"`
+	Phone       string `db:"phone"`
+	Locale      string `db:"locale"`
 }
 
 func toDBUser(u users.User) (dbUser, error) {
@@ -291,12 +798,25 @@ func toDBUser(u users.User) (dbUser, error) {
 		data = b
 	}
 
-	return dbUser{
+	dbu := dbUser{
 		ID:       u.ID,
 		Email:    u.Email,
 		Password: u.Password,
 		Metadata: data,
-	}, nil
+		Labels:   dbLabels(u.Labels),
+		Verified: u.Verified,
+		Disabled: u.Disabled,
+	}
+	setDBProfile(&dbu, u.Profile)
+	return dbu, nil
+}
+
+func setDBProfile(dbu *dbUser, p users.Profile) {
+	dbu.FirstName = p.FirstName
+	dbu.LastName = p.LastName
+	dbu.DisplayName = p.DisplayName
+	dbu.Phone = p.Phone
+	dbu.Locale = p.Locale
 }
 
 func total(ctx context.Context, db Database, query string, params interface{}) (uint64, error) {
@@ -323,10 +843,24 @@ func toUser(dbu dbUser) (users.User, error) {
 	}
 
 	return users.User{
-		ID:       dbu.ID,
-		Email:    dbu.Email,
-		Password: dbu.Password,
-		Metadata: metadata,
+		ID:                dbu.ID,
+		Email:             dbu.Email,
+		Password:          dbu.Password,
+		Metadata:          metadata,
+		Labels:            labels.Labels(dbu.Labels),
+		Verified:          dbu.Verified,
+		Disabled:          dbu.Disabled,
+		FailedLogins:      dbu.Failed,
+		LockedUntil:       dbu.Locked.Time,
+		PasswordChangedAt: dbu.Changed,
+		CreatedAt:         dbu.Created,
+		Profile: users.Profile{
+			FirstName:   dbu.FirstName,
+			LastName:    dbu.LastName,
+			DisplayName: dbu.DisplayName,
+			Phone:       dbu.Phone,
+			Locale:      dbu.Locale,
+		},
 	}, nil
 }
 
@@ -355,3 +889,71 @@ func createMetadataQuery(entity string, um users.Metadata) (string, []byte, erro
 
 	return query, param, nil
 }
+
+// createStatusQuery creates the condition selecting the users with the
+// status, matching users.User.Status.
+func createStatusQuery(status string) string {
+	switch status {
+	case users.StatusDisabled:
+		return "disabled"
+	case users.StatusLocked:
+		return "NOT disabled AND locked_until > NOW()"
+	case users.StatusActive:
+		return "NOT disabled AND (locked_until IS NULL OR locked_until <= NOW()) AND verified"
+	case users.StatusPending:
+		return "NOT disabled AND (locked_until IS NULL OR locked_until <= NOW()) AND NOT verified"
+	default:
+		return ""
+	}
+}
+
+// getOrderQuery returns the column the users are sorted by.
+func getOrderQuery(order string) string {
+	switch order {
+	case "created_at":
+		return "created_at"
+	default:
+		return "email"
+	}
+}
+
+// getDirQuery returns the direction of the order, ascending by default.
+func getDirQuery(dir string) string {
+	switch dir {
+	case "desc":
+		return "DESC"
+	default:
+		return "ASC"
+	}
+}
+
+// createSelectorQuery creates the condition selecting the users whose labels
+// satisfy all the selector requirements, along with its named parameters.
+func createSelectorQuery(entity string, sel labels.Selector) (string, map[string]interface{}, error) {
+	var conds []string
+	params := map[string]interface{}{}
+	for i, r := range sel {
+		p := fmt.Sprintf("selector_%d", i)
+		switch r.Operator {
+		case labels.Equals, labels.NotEquals:
+			b, err := json.Marshal(labels.Labels{r.Key: r.Value})
+			if err != nil {
+				return "", nil, err
+			}
+			params[p] = b
+			cond := fmt.Sprintf("%slabels @> :%s", entity, p)
+			if r.Operator == labels.NotEquals {
+				cond = fmt.Sprintf("NOT (%s)", cond)
+			}
+			conds = append(conds, cond)
+		case labels.Exists:
+			params[p] = r.Key
+			conds = append(conds, fmt.Sprintf("%slabels ? :%s", entity, p))
+		case labels.NotExists:
+			params[p] = r.Key
+			conds = append(conds, fmt.Sprintf("NOT (%slabels ? :%s)", entity, p))
+		}
+	}
+
+	return strings.Join(conds, " AND "), params, nil
+}
@@ -6,9 +6,12 @@ package postgres_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/mainflux/mainflux/users"
 	"github.com/mainflux/mainflux/users/postgres"
@@ -90,6 +93,419 @@ func TestSingleUserRetrieval(t *testing.T) {
 	}
 }
 
+func TestUserVerify(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	email := "user-verify@example.com"
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	user := users.User{
+		ID:       uid,
+		Email:    email,
+		Password: "pass",
+	}
+
+	_, err = repo.Save(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		email string
+		err   error
+	}{
+		"verify existing user":     {email, nil},
+		"verify non-existing user": {"unknown@example.com", users.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		err := repo.Verify(context.Background(), tc.email)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+
+	u, err := repo.RetrieveByEmail(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, u.Verified, "expected verified user")
+}
+
+func TestUserUpdateProfile(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	email := "user-profile@example.com"
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), users.User{ID: uid, Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	profile := users.Profile{
+		FirstName:   "Petar",
+		LastName:    "Petrović",
+		DisplayName: "pera",
+		Phone:       "+381641234567",
+		Locale:      "sr-RS",
+	}
+
+	wrongID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := map[string]struct {
+		id      string
+		profile users.Profile
+		err     error
+	}{
+		"update profile of existing user":     {uid, profile, nil},
+		"update profile of non-existing user": {wrongID, profile, users.ErrNotFound},
+		"update profile with too long phone":  {uid, users.Profile{Phone: "+" + strings.Repeat("1", 20)}, users.ErrMalformedEntity},
+	}
+
+	for desc, tc := range cases {
+		err := repo.UpdateProfile(context.Background(), tc.id, tc.profile)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+
+	u, err := repo.RetrieveByID(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, profile, u.Profile, fmt.Sprintf("expected profile %v got %v\n", profile, u.Profile))
+}
+
+func TestUserDisableDelete(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	user := users.User{
+		ID:       uid,
+		Email:    "user-disable@example.com",
+		Password: "pass",
+	}
+
+	_, err = repo.Save(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = repo.Disable(context.Background(), uid)
+	assert.Nil(t, err, fmt.Sprintf("disable existing user: unexpected error: %s", err))
+	u, err := repo.RetrieveByID(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, u.Disabled, "expected disabled user")
+
+	err = repo.Enable(context.Background(), uid)
+	assert.Nil(t, err, fmt.Sprintf("enable existing user: unexpected error: %s", err))
+	u, err = repo.RetrieveByID(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.False(t, u.Disabled, "expected enabled user")
+
+	err = repo.Disable(context.Background(), unknownID)
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("disable non-existing user: expected %s got %s\n", users.ErrNotFound, err))
+
+	cases := map[string]struct {
+		id  string
+		err error
+	}{
+		"delete existing user":     {uid, nil},
+		"delete non-existing user": {unknownID, users.ErrNotFound},
+	}
+
+	for desc, tc := range cases {
+		err := repo.Delete(context.Background(), tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+
+	_, err = repo.RetrieveByID(context.Background(), uid)
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("retrieve deleted user: expected %s got %s\n", users.ErrNotFound, err))
+}
+
+func TestUserLockout(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	email := "user-lockout@example.com"
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), users.User{ID: uid, Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	for i := uint32(1); i <= 2; i++ {
+		cnt, err := repo.IncrementFailedLogins(context.Background(), uid)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, i, cnt, fmt.Sprintf("increment failed logins: expected %d got %d\n", i, cnt))
+	}
+	_, err = repo.IncrementFailedLogins(context.Background(), unknownID)
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("increment failed logins of non-existing user: expected %s got %s\n", users.ErrNotFound, err))
+
+	until := time.Now().Add(time.Hour).Round(time.Millisecond)
+	err = repo.Lock(context.Background(), uid, until)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	u, err := repo.RetrieveByEmail(context.Background(), email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, until.Equal(u.LockedUntil), fmt.Sprintf("lock user: expected lockout until %s got %s\n", until, u.LockedUntil))
+	assert.Equal(t, uint32(0), u.FailedLogins, fmt.Sprintf("lock user: expected no failed logins got %d\n", u.FailedLogins))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{"unlock user", uid, nil},
+		{"unlock non-existing user", unknownID, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := repo.Unlock(context.Background(), tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	u, err = repo.RetrieveByID(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, u.LockedUntil.IsZero(), fmt.Sprintf("unlock user: expected no lockout got %s\n", u.LockedUntil))
+}
+
+func TestPasswordHistory(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	email := "user-password-history@example.com"
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), users.User{ID: uid, Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	u, err := repo.RetrieveByID(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	savedAt := u.PasswordChangedAt
+
+	err = repo.UpdatePassword(context.Background(), email, "pass-1")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	u, err = repo.RetrieveByID(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.True(t, u.PasswordChangedAt.After(savedAt), fmt.Sprintf("update password: expected change time after %s got %s\n", savedAt, u.PasswordChangedAt))
+
+	cases := []struct {
+		desc     string
+		userID   string
+		password string
+		err      error
+	}{
+		{"save password", uid, "pass", nil},
+		{"save another password", uid, "pass-1", nil},
+		{"save password exceeding limit", uid, "pass-2", nil},
+		{"save password of non-existing user", unknownID, "pass", users.ErrMalformedEntity},
+	}
+
+	for _, tc := range cases {
+		err := repo.SavePasswordHistory(context.Background(), tc.userID, tc.password, 2)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	h, err := repo.RetrievePasswordHistory(context.Background(), uid, 10)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []string{"pass-2", "pass-1"}, h, fmt.Sprintf("retrieve password history: expected %v got %v\n", []string{"pass-2", "pass-1"}, h))
+
+	h, err = repo.RetrievePasswordHistory(context.Background(), uid, 1)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, []string{"pass-2"}, h, fmt.Sprintf("retrieve limited password history: expected %v got %v\n", []string{"pass-2"}, h))
+}
+
+func TestUpdatePasswordHash(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	email := "user-password-hash@example.com"
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), users.User{ID: uid, Email: email, Password: "hash"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	saved, err := repo.RetrieveByID(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		id   string
+		err  error
+	}{
+		{"update password hash", uid, nil},
+		{"update password hash of non-existing user", unknownID, users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := repo.UpdatePasswordHash(context.Background(), tc.id, "new-hash")
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	u, err := repo.RetrieveByID(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, "new-hash", u.Password, fmt.Sprintf("update password hash: expected %s got %s\n", "new-hash", u.Password))
+	assert.True(t, u.PasswordChangedAt.Equal(saved.PasswordChangedAt), fmt.Sprintf("update password hash: expected change time %s got %s\n", saved.PasswordChangedAt, u.PasswordChangedAt))
+}
+
+func TestUserMFA(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), users.User{ID: uid, Email: "user-mfa@example.com", Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.RetrieveMFA(context.Background(), uid)
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("retrieve missing MFA: expected %s got %s\n", users.ErrNotFound, err))
+
+	mfa := users.MFA{UserID: uid, Secret: "secret", RecoveryCodes: []string{"hash1", "hash2"}}
+	enabled := mfa
+	enabled.Enabled = true
+	enabled.RecoveryCodes = []string{"hash2"}
+
+	cases := []struct {
+		desc string
+		mfa  users.MFA
+		err  error
+	}{
+		{"save MFA", mfa, nil},
+		{"save existing MFA", enabled, nil},
+		{"save MFA of non-existing user", users.MFA{UserID: unknownID, Secret: "secret"}, users.ErrMalformedEntity},
+	}
+
+	for _, tc := range cases {
+		err := repo.SaveMFA(context.Background(), tc.mfa)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	m, err := repo.RetrieveMFA(context.Background(), uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, enabled, m, fmt.Sprintf("retrieve MFA: expected %v got %v\n", enabled, m))
+}
+
+func TestUserIdentity(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	email := "user-identity@example.com"
+	_, err = repo.Save(context.Background(), users.User{ID: uid, Email: email, Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.RetrieveByIdentity(context.Background(), "idp", "subject")
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("retrieve unlinked identity: expected %s got %s\n", users.ErrNotFound, err))
+
+	cases := []struct {
+		desc string
+		id   users.Identity
+		err  error
+	}{
+		{"save identity", users.Identity{Provider: "idp", Subject: "subject", UserID: uid}, nil},
+		{"save existing identity", users.Identity{Provider: "idp", Subject: "subject", UserID: uid}, users.ErrConflict},
+		{"save identity of non-existing user", users.Identity{Provider: "idp", Subject: "other", UserID: unknownID}, users.ErrMalformedEntity},
+	}
+
+	for _, tc := range cases {
+		err := repo.SaveIdentity(context.Background(), tc.id)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	u, err := repo.RetrieveByIdentity(context.Background(), "idp", "subject")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, uid, u.ID, fmt.Sprintf("retrieve by identity: expected %s got %s\n", uid, u.ID))
+	assert.Equal(t, email, u.Email, fmt.Sprintf("retrieve by identity: expected %s got %s\n", email, u.Email))
+}
+
+func TestResetNonce(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), users.User{ID: uid, Email: "user-reset@example.com", Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	err = repo.SaveResetNonce(context.Background(), unknownID, "nonce")
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("save nonce of non-existing user: expected %s got %s\n", users.ErrNotFound, err))
+
+	err = repo.SaveResetNonce(context.Background(), uid, "first")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = repo.SaveResetNonce(context.Background(), uid, "second")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		userID string
+		nonce  string
+		err    error
+	}{
+		{"consume replaced nonce", uid, "first", users.ErrNotFound},
+		{"consume nonce of other user", unknownID, "second", users.ErrNotFound},
+		{"consume latest nonce", uid, "second", nil},
+		{"consume consumed nonce", uid, "second", users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		err := repo.ConsumeResetNonce(context.Background(), tc.userID, tc.nonce)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestMFAChallenge(t *testing.T) {
+	dbMiddleware := postgres.NewDatabase(db)
+	repo := postgres.NewUserRepo(dbMiddleware)
+
+	uid, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	unknownID, err := idProvider.ID()
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = repo.Save(context.Background(), users.User{ID: uid, Email: "user-mfa-challenge@example.com", Password: "pass"})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	expiresAt := time.Now().Add(time.Minute).UTC().Round(time.Millisecond)
+	err = repo.SaveMFAChallenge(context.Background(), users.MFAChallenge{TokenHash: "unknown", UserID: unknownID, ExpiresAt: expiresAt})
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("save challenge of non-existing user: expected %s got %s\n", users.ErrNotFound, err))
+	err = repo.SaveMFAChallenge(context.Background(), users.MFAChallenge{TokenHash: "hash", UserID: uid, ExpiresAt: expiresAt})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		hash string
+		err  error
+	}{
+		{"consume non-existing challenge", "unknown", users.ErrNotFound},
+		{"consume challenge", "hash", nil},
+		{"consume consumed challenge", "hash", users.ErrNotFound},
+	}
+
+	for _, tc := range cases {
+		c, err := repo.ConsumeMFAChallenge(context.Background(), tc.hash)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.Equal(t, uid, c.UserID, fmt.Sprintf("%s: expected user ID %s got %s\n", tc.desc, uid, c.UserID))
+			assert.True(t, expiresAt.Equal(c.ExpiresAt), fmt.Sprintf("%s: expected expiration %s got %s\n", tc.desc, expiresAt, c.ExpiresAt))
+		}
+	}
+}
+
 func TestRetrieveAll(t *testing.T) {
 	dbMiddleware := postgres.NewDatabase(db)
 	userRepo := postgres.NewUserRepo(dbMiddleware)
@@ -116,6 +532,8 @@ func TestRetrieveAll(t *testing.T) {
 		}
 		if i < metaNum {
 			user.Metadata = meta
+			user.Labels = labels.Labels{"role": "admin"}
+			user.Verified = true
 		}
 		ids = append(ids, uid)
 		_, err = userRepo.Save(context.Background(), user)
@@ -130,6 +548,11 @@ func TestRetrieveAll(t *testing.T) {
 		total    uint64
 		ids      []string
 		metadata users.Metadata
+		selector labels.Selector
+		status   string
+		from     time.Time
+		order    string
+		dir      string
 	}{
 		"retrieve all users filtered by email": {
 			email:  "All",
@@ -216,9 +639,66 @@ func TestRetrieveAll(t *testing.T) {
 			total:  nUsers,
 			ids:    []string{},
 		},
+		"retrieve all users by selector": {
+			email:    "All",
+			offset:   0,
+			limit:    nUsers,
+			size:     metaNum,
+			total:    nUsers,
+			ids:      ids,
+			selector: labels.Selector{{Key: "role", Operator: labels.Equals, Value: "admin"}},
+		},
+		"retrieve all users by negated selector": {
+			email:    "All",
+			offset:   0,
+			limit:    nUsers,
+			size:     nUsers - metaNum,
+			total:    nUsers,
+			ids:      ids,
+			selector: labels.Selector{{Key: "role", Operator: labels.NotExists}},
+		},
+		"retrieve all users by status": {
+			email:  "All",
+			offset: 0,
+			limit:  nUsers,
+			size:   nUsers - metaNum,
+			total:  nUsers,
+			ids:    ids,
+			status: users.StatusPending,
+		},
+		"retrieve all users by creation time": {
+			email:  "All",
+			offset: 0,
+			limit:  nUsers,
+			size:   0,
+			total:  nUsers,
+			ids:    ids,
+			from:   time.Now().Add(time.Hour),
+		},
+		"retrieve all users sorted by creation time": {
+			email:  "All",
+			offset: 0,
+			limit:  nUsers,
+			size:   nUsers,
+			total:  nUsers,
+			ids:    ids,
+			order:  "created_at",
+			dir:    "desc",
+		},
 	}
 	for desc, tc := range cases {
-		page, err := userRepo.RetrieveAll(context.Background(), tc.offset, tc.limit, tc.ids, tc.email, tc.metadata)
+		pm := users.PageMetadata{
+			Offset:      tc.offset,
+			Limit:       tc.limit,
+			Email:       tc.email,
+			Metadata:    tc.metadata,
+			Selector:    tc.selector,
+			Status:      tc.status,
+			CreatedFrom: tc.from,
+			Order:       tc.order,
+			Dir:         tc.dir,
+		}
+		page, err := userRepo.RetrieveAll(context.Background(), tc.ids, pm)
 		size := uint64(len(page.Users))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected size %d got %d\n", desc, tc.size, size))
 		assert.Nil(t, err, fmt.Sprintf("%s: expected no error got %d\n", desc, err))
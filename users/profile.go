@@ -0,0 +1,68 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+const maxNameLen = 64
+
+var (
+	// phoneRegexp matches the phone numbers in the E.164 format, e.g.
+	// +381641234567.
+	phoneRegexp = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+	// localeRegexp matches the BCP 47 language tags made of the language
+	// and the optional region, e.g. en or sr-RS.
+	localeRegexp = regexp.MustCompile(`^[a-z]{2,3}(-([A-Z]{2}|[0-9]{3}))?$`)
+
+	errInvalidName   = errors.New("name is too long or contains control characters")
+	errInvalidPhone  = errors.New("phone number is not in the E.164 format")
+	errInvalidLocale = errors.New("locale is not a valid language tag")
+)
+
+// Profile represents the personal details of the user. All the fields are
+// optional.
+type Profile struct {
+	FirstName   string `json:"first_name,omitempty"`
+	LastName    string `json:"last_name,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	// Phone is the phone number in the E.164 format.
+	Phone string `json:"phone,omitempty"`
+	// Locale is the language tag, e.g. en or sr-RS.
+	Locale string `json:"locale,omitempty"`
+}
+
+// Validate returns an error if the profile representation is invalid.
+func (p Profile) Validate() error {
+	for _, name := range []string{p.FirstName, p.LastName, p.DisplayName} {
+		if !isName(name) {
+			return errors.Wrap(ErrMalformedEntity, errInvalidName)
+		}
+	}
+	if p.Phone != "" && !phoneRegexp.MatchString(p.Phone) {
+		return errors.Wrap(ErrMalformedEntity, errInvalidPhone)
+	}
+	if p.Locale != "" && !localeRegexp.MatchString(p.Locale) {
+		return errors.Wrap(ErrMalformedEntity, errInvalidLocale)
+	}
+	return nil
+}
+
+func isName(name string) bool {
+	if !utf8.ValidString(name) || utf8.RuneCountInString(name) > maxNameLen {
+		return false
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import "github.com/mainflux/mainflux/pkg/errors"
+
+const (
+	// RoleAdmin is the platform admin, i.e. the member of the authorities.
+	RoleAdmin = "admin"
+
+	// RoleUserManager disables, enables, unlocks and invites the users.
+	RoleUserManager = "user_manager"
+
+	// RoleInviter invites the users to register.
+	RoleInviter = "inviter"
+)
+
+var (
+	// ErrInvalidRole indicates the role which doesn't exist.
+	ErrInvalidRole = errors.New("invalid role")
+
+	// ErrLastAdmin indicates the attempt to revoke the role of the only
+	// admin left, or to delete that admin.
+	ErrLastAdmin = errors.New("cannot remove the last admin")
+)
+
+// roleRelations maps the platform roles to the relations the users holding
+// them have on the authorities object.
+var roleRelations = map[string]string{
+	RoleAdmin:       memberRelationKey,
+	RoleUserManager: "manage_users",
+	RoleInviter:     "invite_users",
+}
+
+// roleRanks lists the platform roles from the highest to the lowest rank.
+var roleRanks = []string{RoleAdmin, RoleUserManager, RoleInviter}
+
+// roleRelation returns the relation of the role on the authorities object.
+func roleRelation(role string) (string, error) {
+	relation, ok := roleRelations[role]
+	if !ok {
+		return "", ErrInvalidRole
+	}
+	return relation, nil
+}
@@ -5,17 +5,47 @@ package users
 
 import (
 	"context"
-	"regexp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
 
 	"github.com/mainflux/mainflux"
 	"github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	memberRelationKey = "member"
 	authoritiesObjKey = "authorities"
-	usersObjKey       = "users"
+	userEntityType    = "user"
+
+	// exportTimeout limits the export job. The pending export expires
+	// once the job times out, so the export interrupted by the restart
+	// is started again.
+	exportTimeout = 5 * time.Minute
+
+	// exportDuration is the time the completed export is downloadable for.
+	exportDuration = 24 * time.Hour
+
+	// exportRetryInterval is the time the failed export is reported for,
+	// before it's started again.
+	exportRetryInterval = time.Minute
+
+	// bulkPageLimit is the size of the pages the users exported in bulk
+	// are retrieved in.
+	bulkPageLimit = 100
+
+	// resetClaim is the claim of the password reset token carrying the
+	// nonce the token is consumed by.
+	resetClaim = "reset"
+
+	// resetNonceLen is the length in bytes of the password reset nonce.
+	resetNonceLen = 16
 )
 
 var (
@@ -62,22 +92,119 @@ var (
 	// ErrCreateUser indicates error in creating user.
 	ErrCreateUser = errors.New("failed to create user")
 
-	// ErrPasswordFormat indicates weak password.
+	// ErrPasswordFormat indicates the password violating the password
+	// policy.
 	ErrPasswordFormat = errors.New("password does not meet the requirements")
+
+	// ErrEmailNotVerified indicates the login of the self registered user
+	// who didn't verify the email yet.
+	ErrEmailNotVerified = errors.New("email not verified")
+
+	// ErrVerificationToken indicates error in sending the email
+	// verification link.
+	ErrVerificationToken = errors.New("failed to send email verification link")
+
+	// ErrUserDisabled indicates the login of the user disabled by the admin.
+	ErrUserDisabled = errors.New("user is disabled")
+
+	// ErrUserLocked indicates the login of the user locked out after too
+	// many failed logins.
+	ErrUserLocked = errors.New("user is locked out")
+
+	errRevokeKeys = errors.New("failed to revoke user keys")
+
+	errRemovePolicies = errors.New("failed to remove user policies")
+
+	errRemoveLinks = errors.New("failed to remove user identity links")
+
+	errSendInvitation = errors.New("failed to send invitation")
+
+	errJoinGroup = errors.New("failed to add user to invitation group")
+
+	errImportGroups = errors.New("failed to assign imported user to groups")
 )
 
+// policyRelations are the relations of the policies removed along with the
+// deleted user.
+var policyRelations = []string{
+	"read",
+	"write",
+	"delete",
+	memberRelationKey,
+	auth.OwnerRelation,
+	auth.EditorRelation,
+	auth.ViewerRelation,
+	auth.InviteRelation,
+	auth.CreateRelation,
+	auth.AdminRelation,
+	roleRelations[RoleUserManager],
+	roleRelations[RoleInviter],
+}
+
+// SelfRegister is the mode of the registration made without the admin token.
+type SelfRegister uint8
+
+const (
+	// SelfRegisterDisabled allows only the admin to register the users.
+	SelfRegisterDisabled SelfRegister = iota
+	// SelfRegisterEnabled allows everybody to register.
+	SelfRegisterEnabled
+	// SelfRegisterVerified allows everybody to register, while the self
+	// registered users can't log in until they verify the email.
+	SelfRegisterVerified
+)
+
+// Lockout is the policy of locking the users out after too many failed logins.
+type Lockout struct {
+	// Threshold is the number of the consecutive failed logins locking the
+	// user out. The zero threshold disables the lockout.
+	Threshold uint32
+	// Cooldown is the duration of the lockout.
+	Cooldown time.Duration
+}
+
 // Service specifies an API that must be fullfiled by the domain service
 // implementation, and all of its decorators (e.g. logging & metrics).
 type Service interface {
 	// Register creates new user account. In case of the failed registration, a
-	// non-nil error value is returned. The user registration is only allowed
-	// for admin.
+	// non-nil error value is returned. Unless the self registration is
+	// enabled, the user registration is only allowed for admin. The accounts
+	// required to verify the email start pending, and the users are sent the
+	// email verification link.
 	Register(ctx context.Context, token string, user User) (string, error)
 
 	// Login authenticates the user given its credentials. Successful
-	// authentication generates new access token. Failed invocations are
-	// identified by the non-nil error values in the response.
-	Login(ctx context.Context, user User) (string, error)
+	// authentication generates new access token, and the refresh token
+	// used to obtain the new access token once it expires. Failed
+	// invocations are identified by the non-nil error values in the
+	// response. The user who didn't verify the email is sent the new
+	// verification link instead. The user enrolled in the MFA is returned
+	// the short-lived challenge token along with ErrMFARequired, and
+	// completes the login using LoginMFA. The user is locked out once the
+	// failed logins reach the lockout threshold, and the login of the
+	// locked user fails with ErrUserLocked until the lockout expires. The
+	// user whose password is older than the max age is returned the
+	// short-lived password reset challenge token, which isn't the access
+	// token, along with ErrPasswordExpired, and logs in once the password is
+	// changed using ResetPassword.
+	Login(ctx context.Context, user User) (string, string, error)
+
+	// VerifyEmail verifies the email of the user, given the token of the
+	// verification link.
+	VerifyEmail(ctx context.Context, token string) error
+
+	// Invite sends the email invitation to register, and to join the group
+	// if set, returning the invitation. The admin invites to any group,
+	// while the other users invite only to the groups they're allowed to
+	// invite the members of. Without the group, the user managers and the
+	// inviters invite as well.
+	Invite(ctx context.Context, token string, inv Invitation) (Invitation, error)
+
+	// AcceptInvitation registers the invited user with the password, given
+	// the token of the invitation link, and returns the ID of the user. The
+	// invitation is single use, and the user joins the group of the
+	// invitation on behalf of the inviter.
+	AcceptInvitation(ctx context.Context, token, password string) (string, error)
 
 	// ViewUser retrieves user info for a given user ID and an authorized token.
 	ViewUser(ctx context.Context, token, id string) (User, error)
@@ -85,36 +212,147 @@ type Service interface {
 	// ViewProfile retrieves user info for a given token.
 	ViewProfile(ctx context.Context, token string) (User, error)
 
-	// ListUsers retrieves users list for a valid admin token.
-	ListUsers(ctx context.Context, token string, offset, limit uint64, email string, meta Metadata) (UserPage, error)
+	// ListUsers retrieves the page of the users matching the filters of the
+	// page metadata, sorted in its order.
+	ListUsers(ctx context.Context, token string, pm PageMetadata) (UserPage, error)
 
-	// UpdateUser updates the user metadata.
+	// UpdateUser updates the user metadata and labels.
 	UpdateUser(ctx context.Context, token string, user User) error
 
+	// UpdateProfile replaces the profile of the user identified by the
+	// token.
+	UpdateProfile(ctx context.Context, token string, p Profile) error
+
 	// GenerateResetToken email where mail will be sent.
-	// host is used for generating reset link.
+	// host is used for generating reset link. The new reset token
+	// replaces the reset tokens issued to the user before.
 	GenerateResetToken(ctx context.Context, email, host string) error
 
 	// ChangePassword change users password for authenticated user.
+	// All the keys of the user are revoked, so the user must log in again.
+	// The new password can't reuse the latest passwords of the user.
 	ChangePassword(ctx context.Context, authToken, password, oldPassword string) error
 
 	// ResetPassword change users password in reset flow.
-	// token can be authentication token or password reset token.
+	// token is the latest password reset token of the user, or the reset
+	// challenge token of the login with the expired password, and can be used
+	// only once. All the keys of the user, including the reset token, are revoked.
+	// The new password can't reuse the latest passwords of the user.
 	ResetPassword(ctx context.Context, resetToken, password string) error
 
 	// SendPasswordReset sends reset password link to email.
 	SendPasswordReset(ctx context.Context, host, email, token string) error
 
-	// ListMembers retrieves everything that is assigned to a group identified by groupID.
-	ListMembers(ctx context.Context, token, groupID string, offset, limit uint64, meta Metadata) (UserPage, error)
+	// ListMembers retrieves the page of the members of the group identified
+	// by groupID, matching the filters of the page metadata and sorted in
+	// its order. The total counts all the matching members.
+	ListMembers(ctx context.Context, token, groupID string, pm PageMetadata) (UserPage, error)
+
+	// ExportData starts the background export of the data of the user
+	// identified by the token, unless the latest export hasn't expired,
+	// and returns the export. The completed export carries the signature
+	// of its download link.
+	ExportData(ctx context.Context, token string) (Export, error)
+
+	// DownloadExport retrieves the archive of the completed export, given
+	// the signature of its download link.
+	DownloadExport(ctx context.Context, id, signature string) ([]byte, error)
+
+	// DisableUser disables the user identified by the provided ID, so the
+	// user can't log in anymore. All the keys of the user are revoked. The
+	// users are disabled only by the admin or the user manager, who can't
+	// disable the users holding the higher role, e.g. the admin.
+	DisableUser(ctx context.Context, token, id string) error
+
+	// EnableUser enables the user identified by the provided ID. The users
+	// are enabled only by the admin or the user manager.
+	EnableUser(ctx context.Context, token, id string) error
+
+	// UnlockUser lifts the lockout of the user identified by the provided
+	// ID. The users are unlocked only by the admin or the user manager.
+	UnlockUser(ctx context.Context, token, id string) error
+
+	// AssignRole grants the platform role to the user identified by the
+	// provided ID. The roles are granted only by the admin.
+	AssignRole(ctx context.Context, token, id, role string) error
+
+	// RevokeRole revokes the platform role of the user identified by the
+	// provided ID. The roles are revoked only by the admin, and the admin
+	// role of the last admin can't be revoked.
+	RevokeRole(ctx context.Context, token, id, role string) error
+
+	// ListRoleMembers retrieves the users holding the platform role. The
+	// roles are listed only by the admin.
+	ListRoleMembers(ctx context.Context, token, role string, offset, limit uint64) (UserPage, error)
+
+	// DeleteUser removes the user identified by the provided ID, along with
+	// the keys, the policies and the exports of the user. The users are
+	// deleted by themselves or by the admin, and the last admin can't be
+	// deleted.
+	DeleteUser(ctx context.Context, token, id string) error
+
+	// EnrollMFA enrolls the user identified by the token in the TOTP based
+	// multi-factor authentication, returning the provisioning URI of the
+	// secret and the recovery codes. The MFA is enabled once the user
+	// confirms the enrollment.
+	EnrollMFA(ctx context.Context, token string) (Enrollment, error)
+
+	// ConfirmMFA enables the MFA of the user identified by the token, given
+	// the one time code generated from the enrolled secret.
+	ConfirmMFA(ctx context.Context, token, code string) error
+
+	// LoginMFA completes the login of the user enrolled in the MFA, given
+	// the challenge token returned by the login and either the one time
+	// code or the single use recovery code.
+	LoginMFA(ctx context.Context, challenge, code string) (string, string, error)
+
+	// OAuthURL returns the URL the user logging in with the identity
+	// provider is redirected to, along with the random state the provider
+	// passes back to the callback.
+	OAuthURL(ctx context.Context, provider string) (string, string, error)
+
+	// OAuthLogin logs in the user with the authorization code issued by the
+	// identity provider. On the first login, the external identity is
+	// linked to the user with the same verified email, or the new user is
+	// provisioned, unless the self registration is disabled. As on Login,
+	// the user enrolled in the MFA gets the challenge token instead.
+	OAuthLogin(ctx context.Context, provider, code string) (string, string, error)
+
+	// ListActivity retrieves the page of the login attempts of the user
+	// identified by the token, the newest first.
+	ListActivity(ctx context.Context, token string, offset, limit uint64) (ActivityPage, error)
+
+	// ImportUsers creates the accounts of the users in bulk, as the admin
+	// identified by the token, and assigns them to their groups. The users
+	// failing the import are reported in the results along with the
+	// imported ones.
+	ImportUsers(ctx context.Context, token string, records []UserRecord) ([]ImportResult, error)
+
+	// ExportUsers retrieves all the users, along with their password hashes
+	// and group memberships, as the admin identified by the token.
+	ExportUsers(ctx context.Context, token string) ([]UserRecord, error)
 }
 
-// PageMetadata contains page metadata that helps navigation.
+// PageMetadata contains page metadata that helps navigation, along with the
+// filters and the order of the listed users.
 type PageMetadata struct {
-	Total  uint64
-	Offset uint64
-	Limit  uint64
-	Name   string
+	Total    uint64
+	Offset   uint64
+	Limit    uint64
+	Name     string
+	Email    string
+	Metadata Metadata
+	Selector labels.Selector
+	// Status lists only the users with the status, unless empty.
+	Status string
+	// CreatedFrom and CreatedTo bound the registration time of the users,
+	// unless zero.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Order is the field the users are sorted by, either email or
+	// created_at, and Dir is the direction of the order, asc or desc.
+	Order string
+	Dir   string
 }
 
 // GroupPage contains a page of groups.
@@ -132,36 +370,109 @@ type UserPage struct {
 var _ Service = (*usersService)(nil)
 
 type usersService struct {
-	users      UserRepository
-	hasher     Hasher
-	email      Emailer
-	auth       mainflux.AuthServiceClient
-	idProvider mainflux.IDProvider
-	passRegex  *regexp.Regexp
+	users        UserRepository
+	hasher       Hasher
+	email        Emailer
+	auth         mainflux.AuthServiceClient
+	idProvider   mainflux.IDProvider
+	exports      ExportRepository
+	invitations  InvitationRepository
+	activity     ActivityRepository
+	source       ExportSource
+	exportKey    []byte
+	validator    Validator
+	selfRegister SelfRegister
+	verifyEmail  bool
+	providers    map[string]IdentityProvider
+	lockout      Lockout
+	passPolicy   PasswordPolicy
+	loginAlerts  bool
+	events       EventNotifier
+}
+
+// Config defines the users service parameters.
+type Config struct {
+	// ExportKey signs the download links of the exports.
+	ExportKey []byte
+
+	// Validator validates the users; the nil validator accepts all the
+	// users.
+	Validator Validator
+
+	// SelfRegister defines who is allowed to register.
+	SelfRegister SelfRegister
+
+	// VerifyEmail requires all the new accounts, including the ones
+	// registered by the admin, to verify the email.
+	VerifyEmail bool
+
+	// Providers are the identity providers the users log in with, by
+	// their names.
+	Providers map[string]IdentityProvider
+
+	// Lockout defines when the users are locked out after too many failed
+	// logins.
+	Lockout Lockout
+
+	// PasswordPolicy defines the passwords the users are allowed to use.
+	PasswordPolicy PasswordPolicy
+
+	// LoginAlerts notifies the users by email on the login from the new IP
+	// address.
+	LoginAlerts bool
+
+	// Events notifies the user lifecycle events; the nil notifier discards
+	// them.
+	Events EventNotifier
 }
 
-// New instantiates the users service implementation
-func New(users UserRepository, hasher Hasher, auth mainflux.AuthServiceClient, e Emailer, idp mainflux.IDProvider, passRegex *regexp.Regexp) Service {
+// New instantiates the users service implementation configured by the
+// config. The login attempts are recorded in the user activity.
+func New(users UserRepository, hasher Hasher, auth mainflux.AuthServiceClient, e Emailer, idp mainflux.IDProvider, exports ExportRepository, invitations InvitationRepository, activity ActivityRepository, source ExportSource, cfg Config) Service {
+	validator := cfg.Validator
+	if validator == nil {
+		validator = NewNopValidator()
+	}
+	events := cfg.Events
+	if events == nil {
+		events = NewNopEventNotifier()
+	}
 	return &usersService{
-		users:      users,
-		hasher:     hasher,
-		auth:       auth,
-		email:      e,
-		idProvider: idp,
-		passRegex:  passRegex,
+		users:        users,
+		hasher:       hasher,
+		auth:         auth,
+		email:        e,
+		idProvider:   idp,
+		exports:      exports,
+		invitations:  invitations,
+		activity:     activity,
+		source:       source,
+		exportKey:    cfg.ExportKey,
+		validator:    validator,
+		selfRegister: cfg.SelfRegister,
+		verifyEmail:  cfg.VerifyEmail,
+		providers:    cfg.Providers,
+		lockout:      cfg.Lockout,
+		passPolicy:   cfg.PasswordPolicy,
+		loginAlerts:  cfg.LoginAlerts,
+		events:       events,
 	}
 }
 
 func (svc usersService) Register(ctx context.Context, token string, user User) (string, error) {
-	if err := svc.checkAuthz(ctx, token); err != nil {
+	admin, err := svc.checkAuthz(ctx, token)
+	if err != nil {
 		return "", err
 	}
 
 	if err := user.Validate(); err != nil {
 		return "", err
 	}
-	if !svc.passRegex.MatchString(user.Password) {
-		return "", ErrPasswordFormat
+	if err := svc.passPolicy.validate(ctx, user.Password); err != nil {
+		return "", err
+	}
+	if err := svc.validator.ValidateUser(ctx, user); err != nil {
+		return "", err
 	}
 
 	uid, err := svc.idProvider.ID()
@@ -170,258 +481,1613 @@ func (svc usersService) Register(ctx context.Context, token string, user User) (
 	}
 	user.ID = uid
 
-	if err := svc.claimOwnership(ctx, user.ID, usersObjKey, memberRelationKey); err != nil {
-		return "", err
-	}
-
 	hash, err := svc.hasher.Hash(user.Password)
 	if err != nil {
 		return "", errors.Wrap(ErrMalformedEntity, err)
 	}
 	user.Password = hash
-	uid, err = svc.users.Save(ctx, user)
+	user.Verified = !svc.verifyEmail && (admin || svc.selfRegister != SelfRegisterVerified)
+	uid, err = svc.save(ctx, user)
 	if err != nil {
 		return "", err
 	}
+	svc.notify(ctx, EventUserCreated, user)
+	if !user.Verified {
+		return uid, svc.sendVerification(ctx, user)
+	}
 	return uid, nil
 }
 
-func (svc usersService) checkAuthz(ctx context.Context, token string) error {
-	if err := svc.authorize(ctx, "*", "user", "create"); err == nil {
-		return nil
+// checkAuthz authorizes the registration, reporting whether it's made by
+// the admin. Unless the self registration is disabled, the registration
+// without the admin token is allowed.
+func (svc usersService) checkAuthz(ctx context.Context, token string) (bool, error) {
+	var err error = ErrUnauthorizedAccess
+	if token != "" {
+		var ir userIdentity
+		if ir, err = svc.identify(ctx, token); err == nil {
+			err = svc.authorize(ctx, ir.id, authoritiesObjKey, memberRelationKey)
+		}
 	}
-	if token == "" {
-		return ErrUnauthorizedAccess
+	switch {
+	case err == nil:
+		return true, nil
+	case svc.selfRegister != SelfRegisterDisabled:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// sendVerification sends the email verification link to the user. The link
+// expires along with the recovery key it carries.
+func (svc usersService) sendVerification(ctx context.Context, user User) error {
+	t, err := svc.issue(ctx, user.ID, user.Email, auth.RecoveryKey)
+	if err != nil {
+		return errors.Wrap(ErrVerificationToken, err)
 	}
+	if err := svc.email.SendVerification(ctx, []string{user.Email}, t); err != nil {
+		return errors.Wrap(ErrVerificationToken, err)
+	}
+	return nil
+}
 
-	ir, err := svc.identify(ctx, token)
+func (svc usersService) Login(ctx context.Context, user User) (string, string, error) {
+	dbUser, err := svc.authenticate(ctx, user)
 	if err != nil {
-		return err
+		svc.recordFailedLogin(ctx, user.Email, err)
+		return "", "", err
+	}
+	return svc.login(ctx, dbUser)
+}
+
+// login issues the tokens of the authenticated user, or the MFA challenge
+// token if the user is enrolled in the MFA.
+func (svc usersService) login(ctx context.Context, user User) (string, string, error) {
+	mfa, err := svc.users.RetrieveMFA(ctx, user.ID)
+	switch {
+	case err == nil && mfa.Enabled:
+		challenge, err := svc.issueMFAChallenge(ctx, user)
+		if err != nil {
+			return "", "", err
+		}
+		return challenge, "", ErrMFARequired
+	case err != nil && !errors.Contains(err, ErrNotFound):
+		return "", "", err
 	}
 
-	return svc.authorize(ctx, ir.id, authoritiesObjKey, memberRelationKey)
+	return svc.issueTokens(ctx, user)
 }
 
-func (svc usersService) Login(ctx context.Context, user User) (string, error) {
+// authenticate checks the credentials of the user, who has to be enabled,
+// verified and not locked out. The user who didn't verify the email is sent
+// the new verification link.
+func (svc usersService) authenticate(ctx context.Context, user User) (User, error) {
 	dbUser, err := svc.users.RetrieveByEmail(ctx, user.Email)
 	if err != nil {
-		return "", errors.Wrap(ErrUnauthorizedAccess, err)
+		return User{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	// The password isn't checked during the lockout, so it can't be guessed.
+	if dbUser.Locked(time.Now()) {
+		return User{}, ErrUserLocked
 	}
 	if err := svc.hasher.Compare(user.Password, dbUser.Password); err != nil {
-		return "", errors.Wrap(ErrUnauthorizedAccess, err)
+		if err := svc.failLogin(ctx, dbUser); err != nil {
+			return User{}, err
+		}
+		return User{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if svc.hasher.NeedsRehash(dbUser.Password) {
+		dbUser.Password = svc.rehash(ctx, dbUser, user.Password)
 	}
-	return svc.issue(ctx, dbUser.ID, dbUser.Email, auth.UserKey)
+	if dbUser.FailedLogins > 0 || !dbUser.LockedUntil.IsZero() {
+		if err := svc.users.Unlock(ctx, dbUser.ID); err != nil {
+			return User{}, err
+		}
+	}
+	if dbUser.Disabled {
+		return User{}, ErrUserDisabled
+	}
+	if !dbUser.Verified {
+		if err := svc.sendVerification(ctx, dbUser); err != nil {
+			return User{}, err
+		}
+		return User{}, ErrEmailNotVerified
+	}
+	return dbUser, nil
 }
 
-func (svc usersService) ViewUser(ctx context.Context, token, id string) (User, error) {
-	_, err := svc.identify(ctx, token)
+// rehash replaces the hash of the password made with the older algorithm or
+// parameters, returning the hash the user is left with. The password stays
+// usable with the old hash, so the failed rehash doesn't fail the login, and
+// is retried on the next one.
+func (svc usersService) rehash(ctx context.Context, user User, password string) string {
+	hash, err := svc.hasher.Hash(password)
 	if err != nil {
-		return User{}, err
+		return user.Password
 	}
+	if err := svc.users.UpdatePasswordHash(ctx, user.ID, hash); err != nil {
+		return user.Password
+	}
+	return hash
+}
 
-	dbUser, err := svc.users.RetrieveByID(ctx, id)
+// failLogin records the failed login of the user, and locks the user out once
+// the failed logins reach the threshold.
+func (svc usersService) failLogin(ctx context.Context, user User) error {
+	if svc.lockout.Threshold == 0 {
+		return nil
+	}
+	cnt, err := svc.users.IncrementFailedLogins(ctx, user.ID)
 	if err != nil {
-		return User{}, errors.Wrap(ErrUnauthorizedAccess, err)
+		return err
 	}
-
-	return User{
-		ID:       id,
-		Email:    dbUser.Email,
-		Password: "",
-		Metadata: dbUser.Metadata,
-	}, nil
+	if cnt < svc.lockout.Threshold {
+		return nil
+	}
+	if err := svc.users.Lock(ctx, user.ID, time.Now().Add(svc.lockout.Cooldown)); err != nil {
+		return err
+	}
+	return ErrUserLocked
 }
 
-func (svc usersService) ViewProfile(ctx context.Context, token string) (User, error) {
-	ir, err := svc.identify(ctx, token)
+// issueTokens issues the access and the refresh token of the logged in user,
+// both belonging to the new session. The user whose password expired is
+// issued the password reset challenge instead.
+func (svc usersService) issueTokens(ctx context.Context, user User) (string, string, error) {
+	if svc.passPolicy.expired(user.PasswordChangedAt, time.Now()) {
+		token, err := svc.issueResetChallenge(ctx, user)
+		if err != nil {
+			return "", "", err
+		}
+		svc.recordLogin(ctx, user, ReasonPasswordExpired)
+		return token, "", ErrPasswordExpired
+	}
+	sid, err := svc.idProvider.ID()
 	if err != nil {
-		return User{}, err
+		return "", "", err
 	}
-
-	dbUser, err := svc.users.RetrieveByEmail(ctx, ir.email)
+	claims := map[string]string{auth.SessionClaim: sid}
+	token, err := svc.issueClaims(ctx, user.ID, user.Email, auth.UserKey, claims)
 	if err != nil {
-		return User{}, errors.Wrap(ErrUnauthorizedAccess, err)
+		return "", "", err
 	}
-
-	return User{
-		ID:       dbUser.ID,
-		Email:    ir.email,
-		Metadata: dbUser.Metadata,
-	}, nil
+	refresh, err := svc.issueClaims(ctx, user.ID, user.Email, auth.RefreshKey, claims)
+	if err != nil {
+		return "", "", err
+	}
+	svc.recordLogin(ctx, user, "")
+	return token, refresh, nil
 }
 
-func (svc usersService) ListUsers(ctx context.Context, token string, offset, limit uint64, email string, m Metadata) (UserPage, error) {
-	_, err := svc.identify(ctx, token)
+// recordFailedLogin records the failed login attempt of the existing user.
+// The attempts failed for the other reasons than the user's credentials or
+// state, e.g. the database failures, are not recorded.
+func (svc usersService) recordFailedLogin(ctx context.Context, email string, err error) {
+	var reason string
+	switch {
+	case errors.Contains(err, ErrUserLocked):
+		reason = ReasonLocked
+	case errors.Contains(err, ErrUserDisabled):
+		reason = ReasonDisabled
+	case errors.Contains(err, ErrEmailNotVerified):
+		reason = ReasonUnverified
+	case errors.Contains(err, ErrUnauthorizedAccess):
+		reason = ReasonInvalidCredentials
+	default:
+		return
+	}
+	user, err := svc.users.RetrieveByEmail(ctx, email)
 	if err != nil {
-		return UserPage{}, err
+		return
 	}
-
-	return svc.users.RetrieveAll(ctx, offset, limit, nil, email, m)
+	svc.recordLogin(ctx, user, reason)
 }
 
-func (svc usersService) UpdateUser(ctx context.Context, token string, u User) error {
-	ir, err := svc.identify(ctx, token)
+// recordLogin records the login attempt of the user, failed for the reason
+// unless it's empty, and notifies the user on the successful login from the
+// new IP address. The activity is recorded on the best effort basis, so the
+// failure to record it or to notify the user doesn't fail the login.
+func (svc usersService) recordLogin(ctx context.Context, user User, reason string) {
+	id, err := svc.idProvider.ID()
 	if err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+		return
 	}
-	user := User{
-		Email:    ir.email,
-		Metadata: u.Metadata,
+	ua := auth.UserAgent(ctx)
+	if len(ua) > maxUserAgentLength {
+		ua = ua[:maxUserAgentLength]
+	}
+	a := LoginAttempt{
+		ID:        id,
+		UserID:    user.ID,
+		Success:   reason == "",
+		Reason:    reason,
+		IP:        auth.ClientIP(ctx),
+		UserAgent: ua,
+		CreatedAt: time.Now().UTC(),
+	}
+	// The address is checked before the attempt is saved, so the attempt
+	// itself doesn't make the address known.
+	notify := a.Success && svc.loginAlerts && svc.newLoginIP(ctx, user.ID, a.IP)
+	if err := svc.activity.Save(ctx, a); err != nil {
+		return
+	}
+	if notify {
+		svc.email.SendLoginAlert(ctx, []string{user.Email}, a.IP, a.UserAgent)
 	}
-	return svc.users.UpdateUser(ctx, user)
 }
 
-func (svc usersService) GenerateResetToken(ctx context.Context, email, host string) error {
-	user, err := svc.users.RetrieveByEmail(ctx, email)
-	if err != nil || user.Email == "" {
-		return ErrUserNotFound
+// newLoginIP tells whether the user who logged in before never logged in
+// from the IP address. The first login of the user isn't from the new
+// address, since there are no known addresses yet.
+func (svc usersService) newLoginIP(ctx context.Context, userID, ip string) bool {
+	if ip == "" {
+		return false
 	}
-	t, err := svc.issue(ctx, user.ID, user.Email, auth.RecoveryKey)
+	total, err := svc.activity.CountSuccessful(ctx, userID, "")
+	if err != nil || total == 0 {
+		return false
+	}
+	n, err := svc.activity.CountSuccessful(ctx, userID, ip)
+	return err == nil && n == 0
+}
+
+func (svc usersService) EnrollMFA(ctx context.Context, token string) (Enrollment, error) {
+	u, err := svc.identifyUser(ctx, token)
 	if err != nil {
-		return errors.Wrap(ErrRecoveryToken, err)
+		return Enrollment{}, err
 	}
-	return svc.SendPasswordReset(ctx, host, email, t)
+	mfa, err := svc.users.RetrieveMFA(ctx, u.ID)
+	switch {
+	case err == nil && mfa.Enabled:
+		return Enrollment{}, ErrMFAEnrolled
+	case err != nil && !errors.Contains(err, ErrNotFound):
+		return Enrollment{}, err
+	}
+
+	secret, err := randomCode(totpSecretLen)
+	if err != nil {
+		return Enrollment{}, err
+	}
+	mfa = MFA{
+		UserID: u.ID,
+		Secret: secret,
+	}
+	e := Enrollment{
+		URI:    provisioningURI(u.Email, secret),
+		Secret: secret,
+	}
+	for i := 0; i < recoveryCodesNum; i++ {
+		code, err := randomCode(recoveryCodeSize)
+		if err != nil {
+			return Enrollment{}, err
+		}
+		hash, err := svc.hasher.Hash(code)
+		if err != nil {
+			return Enrollment{}, err
+		}
+		e.RecoveryCodes = append(e.RecoveryCodes, code)
+		mfa.RecoveryCodes = append(mfa.RecoveryCodes, hash)
+	}
+	if err := svc.users.SaveMFA(ctx, mfa); err != nil {
+		return Enrollment{}, err
+	}
+	return e, nil
 }
 
-func (svc usersService) ResetPassword(ctx context.Context, resetToken, password string) error {
-	ir, err := svc.identify(ctx, resetToken)
+func (svc usersService) ConfirmMFA(ctx context.Context, token, code string) error {
+	u, err := svc.identifyUser(ctx, token)
 	if err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+		return err
 	}
-	u, err := svc.users.RetrieveByEmail(ctx, ir.email)
+	mfa, err := svc.retrieveMFA(ctx, u.ID)
 	if err != nil {
 		return err
 	}
-	if u.Email == "" {
-		return ErrUserNotFound
+	if mfa.Enabled {
+		return ErrMFAEnrolled
 	}
-	if !svc.passRegex.MatchString(password) {
-		return ErrPasswordFormat
+	if !validTOTP(mfa.Secret, code, time.Now()) {
+		return ErrInvalidMFACode
 	}
-	password, err = svc.hasher.Hash(password)
+	mfa.Enabled = true
+	return svc.users.SaveMFA(ctx, mfa)
+}
+
+// issueMFAChallenge issues the token of the MFA challenge of the login. The
+// token is the random code rather than the auth key, so it's accepted only
+// to complete the login with the one time code.
+func (svc usersService) issueMFAChallenge(ctx context.Context, user User) (string, error) {
+	token, err := randomCode(mfaChallengeSize)
 	if err != nil {
-		return err
+		return "", err
+	}
+	c := MFAChallenge{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(mfaChallengeDuration),
 	}
-	return svc.users.UpdatePassword(ctx, ir.email, password)
+	if err := svc.users.SaveMFAChallenge(ctx, c); err != nil {
+		return "", err
+	}
+	return token, nil
 }
 
-func (svc usersService) ChangePassword(ctx context.Context, authToken, password, oldPassword string) error {
-	ir, err := svc.identify(ctx, authToken)
+func (svc usersService) LoginMFA(ctx context.Context, challenge, code string) (string, string, error) {
+	// The challenge is consumed even if the code is invalid, so each
+	// challenge allows only one guess of the code.
+	c, err := svc.users.ConsumeMFAChallenge(ctx, hashToken(challenge))
 	if err != nil {
-		return errors.Wrap(ErrUnauthorizedAccess, err)
+		if errors.Contains(err, ErrNotFound) {
+			return "", "", ErrUnauthorizedAccess
+		}
+		return "", "", err
 	}
-	if !svc.passRegex.MatchString(password) {
-		return ErrPasswordFormat
+	if time.Now().After(c.ExpiresAt) {
+		return "", "", ErrUnauthorizedAccess
 	}
-	u := User{
-		Email:    ir.email,
-		Password: oldPassword,
+	u, err := svc.users.RetrieveByID(ctx, c.UserID)
+	if err != nil {
+		return "", "", errors.Wrap(ErrUnauthorizedAccess, err)
 	}
-	if _, err := svc.Login(ctx, u); err != nil {
-		return ErrUnauthorizedAccess
+	if u.Disabled {
+		return "", "", ErrUserDisabled
 	}
-	u, err = svc.users.RetrieveByEmail(ctx, ir.email)
-	if err != nil || u.Email == "" {
-		return ErrUserNotFound
+	mfa, err := svc.retrieveMFA(ctx, u.ID)
+	if err != nil {
+		return "", "", err
 	}
+	if !mfa.Enabled {
+		return "", "", ErrMFANotEnrolled
+	}
+	if err := svc.checkMFACode(ctx, mfa, code); err != nil {
+		if err == ErrInvalidMFACode {
+			svc.recordLogin(ctx, u, ReasonInvalidMFACode)
+		}
+		return "", "", err
+	}
+	return svc.issueTokens(ctx, u)
+}
 
-	password, err = svc.hasher.Hash(password)
-	if err != nil {
-		return err
+// checkMFACode checks the one time code, or the recovery code, which is
+// removed once used.
+func (svc usersService) checkMFACode(ctx context.Context, mfa MFA, code string) error {
+	if validTOTP(mfa.Secret, code, time.Now()) {
+		return nil
 	}
-	return svc.users.UpdatePassword(ctx, ir.email, password)
+	for i, hash := range mfa.RecoveryCodes {
+		if err := svc.hasher.Compare(code, hash); err != nil {
+			continue
+		}
+		codes := append([]string{}, mfa.RecoveryCodes[:i]...)
+		mfa.RecoveryCodes = append(codes, mfa.RecoveryCodes[i+1:]...)
+		return svc.users.SaveMFA(ctx, mfa)
+	}
+	return ErrInvalidMFACode
 }
 
-func (svc usersService) SendPasswordReset(_ context.Context, host, email, token string) error {
-	to := []string{email}
-	return svc.email.SendPasswordReset(to, host, token)
+func (svc usersService) retrieveMFA(ctx context.Context, userID string) (MFA, error) {
+	mfa, err := svc.users.RetrieveMFA(ctx, userID)
+	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return MFA{}, ErrMFANotEnrolled
+		}
+		return MFA{}, err
+	}
+	return mfa, nil
 }
 
-func (svc usersService) ListMembers(ctx context.Context, token, groupID string, offset, limit uint64, m Metadata) (UserPage, error) {
-	if _, err := svc.identify(ctx, token); err != nil {
-		return UserPage{}, err
+func (svc usersService) OAuthURL(ctx context.Context, provider string) (string, string, error) {
+	p, ok := svc.providers[provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
 	}
+	state, err := randomCode(oauthStateSize)
+	if err != nil {
+		return "", "", err
+	}
+	return p.AuthURL(state), state, nil
+}
 
-	userIDs, err := svc.members(ctx, token, groupID, offset, limit)
+func (svc usersService) OAuthLogin(ctx context.Context, provider, code string) (string, string, error) {
+	p, ok := svc.providers[provider]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+	id, err := p.Exchange(ctx, code)
 	if err != nil {
-		return UserPage{}, err
+		return "", "", errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	id.Provider = provider
+
+	user, err := svc.users.RetrieveByIdentity(ctx, provider, id.Subject)
+	switch {
+	case errors.Contains(err, ErrNotFound):
+		if user, err = svc.linkIdentity(ctx, id); err != nil {
+			return "", "", err
+		}
+	case err != nil:
+		return "", "", err
 	}
+	if user.Disabled {
+		return "", "", ErrUserDisabled
+	}
+	return svc.login(ctx, user)
+}
 
-	if len(userIDs) == 0 {
-		return UserPage{
-			Users: []User{},
-			PageMetadata: PageMetadata{
-				Total:  0,
-				Offset: offset,
-				Limit:  limit,
-			},
-		}, nil
+// linkIdentity links the external identity to the user with the same email,
+// who is provisioned if there's none. The email has to be verified by the
+// provider, so that nobody takes over the account using the email of
+// someone else.
+func (svc usersService) linkIdentity(ctx context.Context, id Identity) (User, error) {
+	if id.Email == "" || !id.EmailVerified {
+		return User{}, ErrEmailNotVerified
+	}
+
+	user, err := svc.users.RetrieveByEmail(ctx, id.Email)
+	switch {
+	case errors.Contains(err, ErrNotFound):
+		if user, err = svc.provision(ctx, id.Email); err != nil {
+			return User{}, err
+		}
+	case err != nil:
+		return User{}, err
+	case !user.Verified:
+		// The provider verified the email on behalf of the user.
+		if err := svc.users.Verify(ctx, user.Email); err != nil {
+			return User{}, err
+		}
+		user.Verified = true
 	}
 
-	return svc.users.RetrieveAll(ctx, offset, limit, userIDs, "", m)
+	id.UserID = user.ID
+	if err := svc.users.SaveIdentity(ctx, id); err != nil {
+		return User{}, err
+	}
+	return user, nil
 }
 
-// Auth helpers
-func (svc usersService) issue(ctx context.Context, id, email string, keyType uint32) (string, error) {
-	key, err := svc.auth.Issue(ctx, &mainflux.IssueReq{Id: id, Email: email, Type: keyType})
+// provision registers the user logging in with the identity provider for
+// the first time. The user gets the random password, which is set using the
+// password reset, if the user ever logs in without the provider.
+func (svc usersService) provision(ctx context.Context, email string) (User, error) {
+	if svc.selfRegister == SelfRegisterDisabled {
+		return User{}, ErrUnauthorizedAccess
+	}
+
+	user := User{Email: email, Verified: true, PasswordChangedAt: time.Now()}
+	if err := user.Validate(); err != nil {
+		return User{}, err
+	}
+	if err := svc.validator.ValidateUser(ctx, user); err != nil {
+		return User{}, err
+	}
+
+	uid, err := svc.idProvider.ID()
 	if err != nil {
-		return "", errors.Wrap(ErrUserNotFound, err)
+		return User{}, errors.Wrap(ErrCreateUser, err)
 	}
-	return key.GetValue(), nil
+	user.ID = uid
+
+	password, err := randomCode(totpSecretLen)
+	if err != nil {
+		return User{}, errors.Wrap(ErrCreateUser, err)
+	}
+	if user.Password, err = svc.hasher.Hash(password); err != nil {
+		return User{}, errors.Wrap(ErrCreateUser, err)
+	}
+	if _, err := svc.save(ctx, user); err != nil {
+		return User{}, err
+	}
+	svc.notify(ctx, EventUserCreated, user)
+	return user, nil
 }
 
-type userIdentity struct {
-	id    string
-	email string
+func (svc usersService) VerifyEmail(ctx context.Context, token string) error {
+	ir, err := svc.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+	if err := svc.users.Verify(ctx, ir.email); err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
 }
 
-func (svc usersService) identify(ctx context.Context, token string) (userIdentity, error) {
-	identity, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
+func (svc usersService) Invite(ctx context.Context, token string, inv Invitation) (Invitation, error) {
+	ir, err := svc.identify(ctx, token)
 	if err != nil {
-		return userIdentity{}, errors.Wrap(ErrUnauthorizedAccess, err)
+		return Invitation{}, err
+	}
+	if err := svc.authorizeInvite(ctx, ir.id, inv.GroupID); err != nil {
+		return Invitation{}, err
+	}
+	if !isEmail(inv.Email) {
+		return Invitation{}, ErrMalformedEntity
+	}
+	_, err = svc.users.RetrieveByEmail(ctx, inv.Email)
+	switch {
+	case err == nil:
+		return Invitation{}, ErrConflict
+	case !errors.Contains(err, ErrNotFound):
+		return Invitation{}, err
+	}
+
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		return Invitation{}, err
+	}
+	t, err := randomCode(invitationTokenSize)
+	if err != nil {
+		return Invitation{}, err
+	}
+	now := time.Now().UTC()
+	inv.ID = id
+	inv.InviterID = ir.id
+	inv.InviterEmail = ir.email
+	inv.CreatedAt = now
+	inv.ExpiresAt = now.Add(invitationDuration)
+	inv.TokenHash = hashToken(t)
+	if err := svc.invitations.Save(ctx, inv); err != nil {
+		return Invitation{}, err
 	}
 
-	return userIdentity{identity.Id, identity.Email}, nil
+	if err := svc.email.SendInvitation(ctx, []string{inv.Email}, inv.InviterEmail, t); err != nil {
+		return Invitation{}, errors.Wrap(errSendInvitation, err)
+	}
+	return inv, nil
 }
 
-func (svc usersService) authorize(ctx context.Context, subject, object, relation string) error {
-	req := &mainflux.AuthorizeReq{
-		Sub: subject,
-		Obj: object,
-		Act: relation,
+// authorizeInvite checks whether the user is allowed to send the invitation
+// to the group, holding the invite relation on it, or is the admin. The
+// invitations without the group are sent by the user managers and the
+// inviters as well.
+func (svc usersService) authorizeInvite(ctx context.Context, userID, groupID string) error {
+	if groupID == "" {
+		return svc.hasRole(ctx, userID, RoleAdmin, RoleUserManager, RoleInviter)
 	}
-	res, err := svc.auth.Authorize(ctx, req)
+	if err := svc.authorize(ctx, userID, groupID, auth.InviteRelation); err == nil {
+		return nil
+	}
+	return svc.hasRole(ctx, userID, RoleAdmin)
+}
+
+func (svc usersService) AcceptInvitation(ctx context.Context, token, password string) (string, error) {
+	inv, err := svc.invitations.RetrieveByToken(ctx, hashToken(token))
 	if err != nil {
-		return errors.Wrap(ErrAuthorization, err)
+		if errors.Contains(err, ErrNotFound) {
+			return "", ErrInvalidInvitation
+		}
+		return "", err
 	}
-	if !res.GetAuthorized() {
-		return ErrAuthorization
+	if time.Now().After(inv.ExpiresAt) {
+		return "", ErrInvalidInvitation
 	}
-	return nil
-}
 
-func (svc usersService) claimOwnership(ctx context.Context, subject, object, relation string) error {
-	req := &mainflux.AddPolicyReq{
-		Sub: subject,
-		Obj: object,
-		Act: relation,
+	user := User{Email: inv.Email, Password: password}
+	if err := svc.passPolicy.validate(ctx, user.Password); err != nil {
+		return "", err
 	}
-	res, err := svc.auth.AddPolicy(ctx, req)
+	if err := svc.validator.ValidateUser(ctx, user); err != nil {
+		return "", err
+	}
+
+	uid, err := svc.idProvider.ID()
 	if err != nil {
-		return errors.Wrap(ErrAuthorization, err)
+		return "", errors.Wrap(ErrCreateUser, err)
 	}
-	if !res.GetAuthorized() {
-		return ErrAuthorization
+	user.ID = uid
+	hash, err := svc.hasher.Hash(user.Password)
+	if err != nil {
+		return "", errors.Wrap(ErrMalformedEntity, err)
+	}
+	user.Password = hash
+	// The invitation link proves the ownership of the email.
+	user.Verified = true
+	if _, err := svc.save(ctx, user); err != nil {
+		return "", err
+	}
+	svc.notify(ctx, EventUserCreated, user)
+	if err := svc.invitations.Remove(ctx, inv.ID); err != nil {
+		return "", err
+	}
+
+	if inv.GroupID == "" {
+		return uid, nil
+	}
+	return uid, svc.joinGroup(ctx, inv, uid)
+}
+
+// joinGroup assigns the invited user to the group of the invitation on
+// behalf of the inviter, so the inviter still has to be allowed to invite
+// the members of the group once the invitation is accepted.
+func (svc usersService) joinGroup(ctx context.Context, inv Invitation, userID string) error {
+	t, err := svc.issue(ctx, inv.InviterID, inv.InviterEmail, auth.RecoveryKey)
+	if err != nil {
+		return errors.Wrap(errJoinGroup, err)
+	}
+	req := &mainflux.Assignment{
+		Token:    t,
+		GroupID:  inv.GroupID,
+		MemberID: userID,
+	}
+	if _, err := svc.auth.Assign(ctx, req); err != nil {
+		return errors.Wrap(errJoinGroup, err)
+	}
+	return nil
+}
+
+func (svc usersService) ViewUser(ctx context.Context, token, id string) (User, error) {
+	_, err := svc.identify(ctx, token)
+	if err != nil {
+		return User{}, err
+	}
+
+	dbUser, err := svc.users.RetrieveByID(ctx, id)
+	if err != nil {
+		return User{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return User{
+		ID:          id,
+		Email:       dbUser.Email,
+		Password:    "",
+		Metadata:    dbUser.Metadata,
+		Labels:      dbUser.Labels,
+		Verified:    dbUser.Verified,
+		Disabled:    dbUser.Disabled,
+		LockedUntil: dbUser.LockedUntil,
+		CreatedAt:   dbUser.CreatedAt,
+		Profile:     dbUser.Profile,
+	}, nil
+}
+
+func (svc usersService) ViewProfile(ctx context.Context, token string) (User, error) {
+	ir, err := svc.identify(ctx, token)
+	if err != nil {
+		return User{}, err
+	}
+
+	dbUser, err := svc.users.RetrieveByEmail(ctx, ir.email)
+	if err != nil {
+		return User{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return User{
+		ID:          dbUser.ID,
+		Email:       ir.email,
+		Metadata:    dbUser.Metadata,
+		Verified:    dbUser.Verified,
+		Disabled:    dbUser.Disabled,
+		LockedUntil: dbUser.LockedUntil,
+		CreatedAt:   dbUser.CreatedAt,
+		Profile:     dbUser.Profile,
+	}, nil
+}
+
+func (svc usersService) ListUsers(ctx context.Context, token string, pm PageMetadata) (UserPage, error) {
+	_, err := svc.identify(ctx, token)
+	if err != nil {
+		return UserPage{}, err
+	}
+
+	return svc.users.RetrieveAll(ctx, nil, pm)
+}
+
+func (svc usersService) UpdateUser(ctx context.Context, token string, u User) error {
+	ir, err := svc.identify(ctx, token)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	user, err := svc.users.RetrieveByEmail(ctx, ir.email)
+	if err != nil {
+		return err
+	}
+	user.Metadata = u.Metadata
+	user.Labels = u.Labels
+	if err := svc.validator.ValidateUser(ctx, user); err != nil {
+		return err
+	}
+	if err := svc.users.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+	svc.notify(ctx, EventUserUpdated, user)
+	return nil
+}
+
+func (svc usersService) UpdateProfile(ctx context.Context, token string, p Profile) error {
+	u, err := svc.identifyUser(ctx, token)
+	if err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if err := svc.users.UpdateProfile(ctx, u.ID, p); err != nil {
+		return err
+	}
+	svc.notify(ctx, EventUserUpdated, u)
+	return nil
+}
+
+func (svc usersService) ListActivity(ctx context.Context, token string, offset, limit uint64) (ActivityPage, error) {
+	u, err := svc.identifyUser(ctx, token)
+	if err != nil {
+		return ActivityPage{}, err
+	}
+	return svc.activity.RetrieveByUser(ctx, u.ID, offset, limit)
+}
+
+func (svc usersService) GenerateResetToken(ctx context.Context, email, host string) error {
+	user, err := svc.users.RetrieveByEmail(ctx, email)
+	if err != nil || user.Email == "" {
+		return ErrUserNotFound
+	}
+	if user.Disabled {
+		return ErrUserDisabled
+	}
+	t, err := svc.issueResetToken(ctx, user)
+	if err != nil {
+		return errors.Wrap(ErrRecoveryToken, err)
+	}
+	return svc.SendPasswordReset(ctx, host, email, t)
+}
+
+// issueResetToken issues the password reset token carrying the new nonce of
+// the user. Saving the nonce replaces the nonce of the previous token, so
+// only the latest token resets the password.
+func (svc usersService) issueResetToken(ctx context.Context, user User) (string, error) {
+	nonce, err := randomCode(resetNonceLen)
+	if err != nil {
+		return "", err
+	}
+	if err := svc.users.SaveResetNonce(ctx, user.ID, nonce); err != nil {
+		return "", err
+	}
+	claims := map[string]string{resetClaim: nonce}
+	return svc.issueClaims(ctx, user.ID, user.Email, auth.RecoveryKey, claims)
+}
+
+// issueResetChallenge issues the token of the password reset challenge of the
+// login with the expired password. The token is the random code rather than
+// the auth key, so it's accepted only to reset the password.
+func (svc usersService) issueResetChallenge(ctx context.Context, user User) (string, error) {
+	token, err := randomCode(resetChallengeSize)
+	if err != nil {
+		return "", err
+	}
+	c := ResetChallenge{
+		TokenHash: hashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(resetChallengeDuration),
+	}
+	if err := svc.users.SaveResetChallenge(ctx, c); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (svc usersService) ResetPassword(ctx context.Context, resetToken, password string) error {
+	c, err := svc.users.ConsumeResetChallenge(ctx, hashToken(resetToken))
+	switch {
+	case err == nil:
+		return svc.resetExpiredPassword(ctx, c, password)
+	case !errors.Contains(err, ErrNotFound):
+		return err
+	}
+
+	ir, err := svc.identify(ctx, resetToken)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	nonce := ir.claims[resetClaim]
+	if nonce == "" {
+		return ErrUnauthorizedAccess
+	}
+	u, err := svc.users.RetrieveByEmail(ctx, ir.email)
+	if err != nil {
+		return err
+	}
+	if u.Email == "" {
+		return ErrUserNotFound
+	}
+	if err := svc.passPolicy.validate(ctx, password); err != nil {
+		return err
+	}
+	if err := svc.checkReuse(ctx, u, password); err != nil {
+		return err
+	}
+	// The nonce is consumed only once the password is known to be valid, so
+	// the rejected password doesn't burn the token.
+	if err := svc.users.ConsumeResetNonce(ctx, u.ID, nonce); err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return ErrUnauthorizedAccess
+		}
+		return err
+	}
+	if err := svc.setPassword(ctx, u, password); err != nil {
+		return err
+	}
+	return svc.revokeKeys(ctx, resetToken, ir.id)
+}
+
+// resetExpiredPassword changes the expired password using the consumed reset
+// challenge. The challenge is restored if the password is rejected, so the
+// rejected password doesn't burn it.
+func (svc usersService) resetExpiredPassword(ctx context.Context, c ResetChallenge, password string) error {
+	if time.Now().After(c.ExpiresAt) {
+		return ErrUnauthorizedAccess
+	}
+	u, err := svc.users.RetrieveByID(ctx, c.UserID)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if u.Disabled {
+		return ErrUserDisabled
+	}
+	if err := svc.passPolicy.validate(ctx, password); err != nil {
+		return svc.restoreResetChallenge(ctx, c, err)
+	}
+	if err := svc.checkReuse(ctx, u, password); err != nil {
+		return svc.restoreResetChallenge(ctx, c, err)
+	}
+	if err := svc.setPassword(ctx, u, password); err != nil {
+		return err
+	}
+	// The challenge isn't the auth key, so the keys of the user are revoked
+	// using the recovery key issued for the purpose.
+	token, err := svc.issueClaims(ctx, u.ID, u.Email, auth.RecoveryKey, nil)
+	if err != nil {
+		return err
+	}
+	return svc.revokeKeys(ctx, token, u.ID)
+}
+
+// restoreResetChallenge saves back the reset challenge consumed by the reset
+// which failed with the given error, and returns the error.
+func (svc usersService) restoreResetChallenge(ctx context.Context, c ResetChallenge, err error) error {
+	if serr := svc.users.SaveResetChallenge(ctx, c); serr != nil {
+		return errors.Wrap(err, serr)
+	}
+	return err
+}
+
+func (svc usersService) ChangePassword(ctx context.Context, authToken, password, oldPassword string) error {
+	ir, err := svc.identify(ctx, authToken)
+	if err != nil {
+		return errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	if err := svc.passPolicy.validate(ctx, password); err != nil {
+		return err
+	}
+	u := User{
+		Email:    ir.email,
+		Password: oldPassword,
+	}
+	dbUser, err := svc.authenticate(ctx, u)
+	if err != nil {
+		return ErrUnauthorizedAccess
+	}
+	if err := svc.updatePassword(ctx, dbUser, password); err != nil {
+		return err
+	}
+	return svc.revokeKeys(ctx, authToken, ir.id)
+}
+
+// updatePassword sets the new password of the user, unless it reuses one of
+// the latest passwords, and keeps the replaced password in the history.
+func (svc usersService) updatePassword(ctx context.Context, user User, password string) error {
+	if err := svc.checkReuse(ctx, user, password); err != nil {
+		return err
+	}
+	return svc.setPassword(ctx, user, password)
+}
+
+// checkReuse returns ErrPasswordReused if the password is one of the latest
+// passwords of the user.
+func (svc usersService) checkReuse(ctx context.Context, user User, password string) error {
+	if svc.passPolicy.History > 0 {
+		hashes := []string{user.Password}
+		if svc.passPolicy.History > 1 {
+			h, err := svc.users.RetrievePasswordHistory(ctx, user.ID, svc.passPolicy.History-1)
+			if err != nil {
+				return err
+			}
+			hashes = append(hashes, h...)
+		}
+		for _, hash := range hashes {
+			if err := svc.hasher.Compare(password, hash); err == nil {
+				return ErrPasswordReused
+			}
+		}
+	}
+	return nil
+}
+
+// setPassword sets the new password of the user and keeps the replaced
+// password in the history.
+func (svc usersService) setPassword(ctx context.Context, user User, password string) error {
+	hash, err := svc.hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	if err := svc.users.UpdatePassword(ctx, user.Email, hash); err != nil {
+		return err
+	}
+	if svc.passPolicy.History > 1 {
+		return svc.users.SavePasswordHistory(ctx, user.ID, user.Password, svc.passPolicy.History-1)
+	}
+	return nil
+}
+
+// revokeKeys revokes all the keys of the user, so the sessions opened with
+// the old password can't be used anymore.
+func (svc usersService) revokeKeys(ctx context.Context, token, userID string) error {
+	if _, err := svc.auth.RevokeKeys(ctx, &mainflux.RevokeKeysReq{Token: token, Subject: userID}); err != nil {
+		return errors.Wrap(errRevokeKeys, err)
+	}
+	return nil
+}
+
+// notify notifies the lifecycle event of the user.
+func (svc usersService) notify(ctx context.Context, typ string, user User) {
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		return
+	}
+	svc.events.Notify(ctx, Event{
+		ID:         id,
+		Type:       typ,
+		UserID:     user.ID,
+		Email:      user.Email,
+		Metadata:   user.Metadata,
+		OccurredAt: time.Now().UTC(),
+	})
+}
+
+// notifyByID notifies the lifecycle event of the user with the given ID,
+// which is notified without the details if the user can't be retrieved.
+func (svc usersService) notifyByID(ctx context.Context, typ, id string) {
+	user, err := svc.users.RetrieveByID(ctx, id)
+	if err != nil {
+		user = User{ID: id}
+	}
+	svc.notify(ctx, typ, user)
+}
+
+func (svc usersService) DisableUser(ctx context.Context, token, id string) error {
+	ir, err := svc.authorizeRole(ctx, token, RoleAdmin, RoleUserManager)
+	if err != nil {
+		return err
+	}
+	// The users can't disable the users holding the higher role.
+	rank, err := svc.rank(ctx, ir.id)
+	if err != nil {
+		return err
+	}
+	targetRank, err := svc.rank(ctx, id)
+	if err != nil {
+		return err
+	}
+	if targetRank > rank {
+		return ErrAuthorization
+	}
+	if err := svc.users.Disable(ctx, id); err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	svc.notifyByID(ctx, EventUserDisabled, id)
+	return svc.revokeKeys(ctx, token, id)
+}
+
+func (svc usersService) EnableUser(ctx context.Context, token, id string) error {
+	if _, err := svc.authorizeRole(ctx, token, RoleAdmin, RoleUserManager); err != nil {
+		return err
+	}
+	if err := svc.users.Enable(ctx, id); err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	svc.notifyByID(ctx, EventUserEnabled, id)
+	return nil
+}
+
+func (svc usersService) UnlockUser(ctx context.Context, token, id string) error {
+	if _, err := svc.authorizeRole(ctx, token, RoleAdmin, RoleUserManager); err != nil {
+		return err
+	}
+	if err := svc.users.Unlock(ctx, id); err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (svc usersService) DeleteUser(ctx context.Context, token, id string) error {
+	ir, err := svc.identify(ctx, token)
+	if err != nil {
+		return err
+	}
+	if ir.id != id {
+		if err := svc.authorize(ctx, ir.id, authoritiesObjKey, memberRelationKey); err != nil {
+			return err
+		}
+	}
+	if err := svc.checkLastAdmin(ctx, id); err != nil {
+		return err
+	}
+
+	user, err := svc.users.RetrieveByID(ctx, id)
+	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if err := svc.revokeKeys(ctx, token, id); err != nil {
+		return err
+	}
+	if err := svc.removePolicies(ctx, id); err != nil {
+		return err
+	}
+	// The external identities linked to the user would otherwise be
+	// exchanged for the tokens of the removed user.
+	if _, err := svc.auth.RemoveLinks(ctx, &mainflux.RemoveLinksReq{UserID: id}); err != nil {
+		return errors.Wrap(errRemoveLinks, err)
+	}
+	if err := svc.users.Delete(ctx, id); err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	svc.notify(ctx, EventUserDeleted, user)
+	return nil
+}
+
+func (svc usersService) AssignRole(ctx context.Context, token, id, role string) error {
+	if _, err := svc.authorizeRole(ctx, token, RoleAdmin); err != nil {
+		return err
+	}
+	relation, err := roleRelation(role)
+	if err != nil {
+		return err
+	}
+	if _, err := svc.users.RetrieveByID(ctx, id); err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	req := &mainflux.AddPolicyReq{Sub: id, Obj: authoritiesObjKey, Act: relation}
+	if _, err := svc.auth.AddPolicy(ctx, req); err != nil {
+		return errors.Wrap(ErrAuthorization, err)
+	}
+	return nil
+}
+
+func (svc usersService) RevokeRole(ctx context.Context, token, id, role string) error {
+	if _, err := svc.authorizeRole(ctx, token, RoleAdmin); err != nil {
+		return err
+	}
+	relation, err := roleRelation(role)
+	if err != nil {
+		return err
+	}
+	if role == RoleAdmin {
+		if err := svc.checkLastAdmin(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	req := &mainflux.DeletePolicyReq{Sub: id, Obj: authoritiesObjKey, Act: relation}
+	if _, err := svc.auth.DeletePolicy(ctx, req); err != nil {
+		return errors.Wrap(ErrAuthorization, err)
+	}
+	return nil
+}
+
+func (svc usersService) ListRoleMembers(ctx context.Context, token, role string, offset, limit uint64) (UserPage, error) {
+	if _, err := svc.authorizeRole(ctx, token, RoleAdmin); err != nil {
+		return UserPage{}, err
+	}
+	relation, err := roleRelation(role)
+	if err != nil {
+		return UserPage{}, err
+	}
+	userIDs, err := svc.roleMembers(ctx, relation)
+	if err != nil {
+		return UserPage{}, err
+	}
+
+	if len(userIDs) == 0 {
+		return UserPage{
+			Users: []User{},
+			PageMetadata: PageMetadata{
+				Total:  0,
+				Offset: offset,
+				Limit:  limit,
+			},
+		}, nil
+	}
+
+	return svc.users.RetrieveAll(ctx, userIDs, PageMetadata{Offset: offset, Limit: limit})
+}
+
+// roleMembers lists the IDs of the users holding the relation on the
+// authorities object.
+// checkLastAdmin returns ErrLastAdmin if the user is the only admin left.
+func (svc usersService) checkLastAdmin(ctx context.Context, id string) error {
+	admins, err := svc.roleMembers(ctx, roleRelations[RoleAdmin])
+	if err != nil {
+		return err
+	}
+	if len(admins) == 1 && admins[0] == id {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+func (svc usersService) roleMembers(ctx context.Context, relation string) ([]string, error) {
+	res, err := svc.auth.ListPolicies(ctx, &mainflux.ListPoliciesReq{Obj: authoritiesObjKey, Act: relation})
+	if err != nil {
+		return nil, errors.Wrap(ErrAuthorization, err)
+	}
+	return res.GetPolicies(), nil
+}
+
+// removePolicies removes all the policies the user is the subject of, so
+// no access is left to the deleted user.
+func (svc usersService) removePolicies(ctx context.Context, userID string) error {
+	for _, relation := range policyRelations {
+		res, err := svc.auth.ListPolicies(ctx, &mainflux.ListPoliciesReq{Sub: userID, Act: relation})
+		if err != nil {
+			return errors.Wrap(errRemovePolicies, err)
+		}
+		for _, obj := range res.GetPolicies() {
+			req := &mainflux.DeletePolicyReq{Sub: userID, Obj: obj, Act: relation}
+			if _, err := svc.auth.DeletePolicy(ctx, req); err != nil {
+				return errors.Wrap(errRemovePolicies, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (svc usersService) SendPasswordReset(ctx context.Context, host, email, token string) error {
+	to := []string{email}
+	return svc.email.SendPasswordReset(ctx, to, host, token)
+}
+
+func (svc usersService) ListMembers(ctx context.Context, token, groupID string, pm PageMetadata) (UserPage, error) {
+	if _, err := svc.identify(ctx, token); err != nil {
+		return UserPage{}, err
+	}
+
+	userIDs, err := svc.members(ctx, token, groupID)
+	if err != nil {
+		return UserPage{}, err
+	}
+
+	if len(userIDs) == 0 {
+		return UserPage{
+			Users: []User{},
+			PageMetadata: PageMetadata{
+				Total:  0,
+				Offset: pm.Offset,
+				Limit:  pm.Limit,
+			},
+		}, nil
+	}
+
+	return svc.users.RetrieveAll(ctx, userIDs, pm)
+}
+
+func (svc usersService) ImportUsers(ctx context.Context, token string, records []UserRecord) ([]ImportResult, error) {
+	if _, err := svc.authorizeRole(ctx, token, RoleAdmin); err != nil {
+		return nil, err
+	}
+	if len(records) == 0 || len(records) > MaxImportUsers {
+		return nil, ErrMalformedEntity
+	}
+
+	results := make([]ImportResult, len(records))
+	for i, rec := range records {
+		results[i] = svc.importUser(ctx, token, rec)
+	}
+	return results, nil
+}
+
+// importUser creates the verified account of the imported user, as the
+// ownership of the email was checked by the deployment the user comes from,
+// and assigns the user to its groups.
+func (svc usersService) importUser(ctx context.Context, token string, rec UserRecord) ImportResult {
+	res := ImportResult{Email: rec.Email}
+	if rec.Password != "" && rec.PasswordHash != "" {
+		res.Err = ErrMalformedEntity
+		return res
+	}
+
+	user := User{Email: rec.Email, Metadata: rec.Metadata, Verified: true}
+	if err := user.Validate(); err != nil {
+		res.Err = err
+		return res
+	}
+	if err := svc.validator.ValidateUser(ctx, user); err != nil {
+		res.Err = err
+		return res
+	}
+	_, err := svc.users.RetrieveByEmail(ctx, user.Email)
+	switch {
+	case err == nil:
+		res.Err = ErrConflict
+		return res
+	case !errors.Contains(err, ErrNotFound):
+		res.Err = err
+		return res
+	}
+
+	if user.Password, res.Password, res.Err = svc.importPassword(ctx, rec); res.Err != nil {
+		return res
+	}
+
+	uid, err := svc.idProvider.ID()
+	if err != nil {
+		res.Err = errors.Wrap(ErrCreateUser, err)
+		return res
+	}
+	user.ID = uid
+	if _, res.Err = svc.save(ctx, user); res.Err != nil {
+		return res
+	}
+	res.ID = user.ID
+	svc.notify(ctx, EventUserCreated, user)
+
+	for _, groupID := range rec.Groups {
+		req := &mainflux.Assignment{
+			Token:    token,
+			GroupID:  groupID,
+			MemberID: user.ID,
+		}
+		if _, err := svc.auth.Assign(ctx, req); err != nil {
+			res.Err = errors.Wrap(errImportGroups, err)
+		}
+	}
+	return res
+}
+
+// importPassword returns the password hash of the imported user, along with
+// the generated password, if any.
+func (svc usersService) importPassword(ctx context.Context, rec UserRecord) (string, string, error) {
+	if rec.PasswordHash != "" {
+		return rec.PasswordHash, "", nil
+	}
+
+	password := rec.Password
+	if password == "" {
+		p, err := randomCode(totpSecretLen)
+		if err != nil {
+			return "", "", errors.Wrap(ErrCreateUser, err)
+		}
+		password = p
+	} else if err := svc.passPolicy.validate(ctx, password); err != nil {
+		return "", "", err
+	}
+
+	hash, err := svc.hasher.Hash(password)
+	if err != nil {
+		return "", "", errors.Wrap(ErrMalformedEntity, err)
+	}
+	if rec.Password != "" {
+		return hash, "", nil
+	}
+	return hash, password, nil
+}
+
+func (svc usersService) ExportUsers(ctx context.Context, token string) ([]UserRecord, error) {
+	if _, err := svc.authorizeRole(ctx, token, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	records := []UserRecord{}
+	for offset := uint64(0); ; offset += bulkPageLimit {
+		up, err := svc.users.RetrieveAll(ctx, nil, PageMetadata{Offset: offset, Limit: bulkPageLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range up.Users {
+			rec, err := svc.exportUser(ctx, token, u.ID)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, rec)
+		}
+		if len(up.Users) == 0 || offset+bulkPageLimit >= up.Total {
+			break
+		}
+	}
+	return records, nil
+}
+
+// exportUser returns the record of the user, including the password hash
+// which isn't listed along with the users.
+func (svc usersService) exportUser(ctx context.Context, token, id string) (UserRecord, error) {
+	u, err := svc.users.RetrieveByID(ctx, id)
+	if err != nil {
+		return UserRecord{}, err
+	}
+	groups, err := svc.source.Memberships(ctx, token, id)
+	if err != nil {
+		return UserRecord{}, err
+	}
+	return UserRecord{
+		ID:           id,
+		Email:        u.Email,
+		PasswordHash: u.Password,
+		Metadata:     u.Metadata,
+		Groups:       groups,
+	}, nil
+}
+
+func (svc usersService) ExportData(ctx context.Context, token string) (Export, error) {
+	ir, err := svc.identify(ctx, token)
+	if err != nil {
+		return Export{}, err
+	}
+
+	now := time.Now().UTC()
+	latest, err := svc.exports.RetrieveLatest(ctx, ir.id)
+	switch {
+	case err == nil && now.Before(latest.ExpiresAt):
+		if latest.Status == ExportCompleted {
+			latest.Signature = svc.sign(latest)
+		}
+		return latest, nil
+	case err != nil && !errors.Contains(err, ErrNotFound):
+		return Export{}, err
+	}
+
+	id, err := svc.idProvider.ID()
+	if err != nil {
+		return Export{}, err
+	}
+	e := Export{
+		ID:        id,
+		UserID:    ir.id,
+		Status:    ExportPending,
+		CreatedAt: now,
+		ExpiresAt: now.Add(exportTimeout),
+	}
+	if err := svc.exports.Save(ctx, e); err != nil {
+		return Export{}, err
+	}
+
+	// The job outlives the request, so it doesn't use the request context.
+	go svc.export(token, ir.email, e)
+
+	return e, nil
+}
+
+func (svc usersService) DownloadExport(ctx context.Context, id, signature string) ([]byte, error) {
+	e, err := svc.exports.RetrieveByID(ctx, id)
+	if err != nil {
+		if errors.Contains(err, ErrNotFound) {
+			return nil, ErrInvalidSignature
+		}
+		return nil, err
+	}
+	if e.Status != ExportCompleted || time.Now().After(e.ExpiresAt) || !hmac.Equal([]byte(signature), []byte(svc.sign(e))) {
+		return nil, ErrInvalidSignature
+	}
+
+	return e.Archive, nil
+}
+
+// export archives the user data and completes the export. The export which
+// fails to be updated is left pending until it expires.
+func (svc usersService) export(token, email string, e Export) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+
+	archive, err := svc.archive(ctx, token, email, e.UserID)
+	e.CompletedAt = time.Now().UTC()
+	switch err {
+	case nil:
+		e.Status = ExportCompleted
+		e.Archive = archive
+		e.ExpiresAt = e.CompletedAt.Add(exportDuration)
+	default:
+		e.Status = ExportFailed
+		e.Error = err.Error()
+		e.ExpiresAt = e.CompletedAt.Add(exportRetryInterval)
+	}
+
+	svc.exports.Update(ctx, e)
+}
+
+func (svc usersService) archive(ctx context.Context, token, email, userID string) ([]byte, error) {
+	u, err := svc.users.RetrieveByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	collected, err := svc.source.Collect(ctx, token, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := map[string]interface{}{}
+	for name, section := range collected {
+		sections[name] = section
+	}
+	sections[profileSection] = exportProfile{
+		ID:       u.ID,
+		Email:    u.Email,
+		Metadata: u.Metadata,
+		Labels:   u.Labels,
+		Profile:  u.Profile,
+	}
+
+	return archive(sections)
+}
+
+// sign signs the download link of the export, which expires along with the
+// export.
+func (svc usersService) sign(e Export) string {
+	mac := hmac.New(sha256.New, svc.exportKey)
+	fmt.Fprintf(mac, "%s:%d", e.ID, e.ExpiresAt.Unix())
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Auth helpers
+func (svc usersService) issue(ctx context.Context, id, email string, keyType uint32) (string, error) {
+	return svc.issueClaims(ctx, id, email, keyType, nil)
+}
+
+func (svc usersService) issueClaims(ctx context.Context, id, email string, keyType uint32, claims map[string]string) (string, error) {
+	key, err := svc.auth.Issue(ctx, &mainflux.IssueReq{Id: id, Email: email, Type: keyType, Claims: claims})
+	if err != nil {
+		return "", errors.Wrap(ErrUserNotFound, err)
+	}
+	return key.GetValue(), nil
+}
+
+type userIdentity struct {
+	id     string
+	email  string
+	claims map[string]string
+}
+
+func (svc usersService) identify(ctx context.Context, token string) (userIdentity, error) {
+	identity, err := svc.auth.Identify(ctx, &mainflux.Token{Value: token})
+	if err != nil {
+		return userIdentity{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+
+	return userIdentity{identity.Id, identity.Email, identity.Claims}, nil
+}
+
+// authorizeRole identifies the user by the token and checks whether the
+// user holds any of the roles.
+func (svc usersService) authorizeRole(ctx context.Context, token string, roles ...string) (userIdentity, error) {
+	ir, err := svc.identify(ctx, token)
+	if err != nil {
+		return userIdentity{}, err
+	}
+	if err := svc.hasRole(ctx, ir.id, roles...); err != nil {
+		return userIdentity{}, err
+	}
+	return ir, nil
+}
+
+// rank returns the rank of the highest platform role the user holds, the
+// higher the more privileged, and zero if the user holds no role.
+func (svc usersService) rank(ctx context.Context, userID string) (int, error) {
+	for i, role := range roleRanks {
+		req := &mainflux.AuthorizeReq{Sub: userID, Obj: authoritiesObjKey, Act: roleRelations[role]}
+		res, err := svc.auth.Authorize(ctx, req)
+		// The auth service reports the missing relation as unauthenticated.
+		if st, ok := status.FromError(err); ok && st.Code() == codes.Unauthenticated {
+			continue
+		}
+		if err != nil {
+			return 0, errors.Wrap(ErrAuthorization, err)
+		}
+		if res.GetAuthorized() {
+			return len(roleRanks) - i, nil
+		}
+	}
+	return 0, nil
+}
+
+// hasRole checks whether the user holds any of the roles.
+func (svc usersService) hasRole(ctx context.Context, userID string, roles ...string) error {
+	var err error = ErrAuthorization
+	for _, role := range roles {
+		if err = svc.authorize(ctx, userID, authoritiesObjKey, roleRelations[role]); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// identifyUser retrieves the user identified by the token.
+func (svc usersService) identifyUser(ctx context.Context, token string) (User, error) {
+	ir, err := svc.identify(ctx, token)
+	if err != nil {
+		return User{}, err
+	}
+	u, err := svc.users.RetrieveByEmail(ctx, ir.email)
+	if err != nil {
+		return User{}, errors.Wrap(ErrUnauthorizedAccess, err)
+	}
+	return u, nil
+}
+
+func (svc usersService) authorize(ctx context.Context, subject, object, relation string) error {
+	req := &mainflux.AuthorizeReq{
+		Sub: subject,
+		Obj: object,
+		Act: relation,
+	}
+	res, err := svc.auth.Authorize(ctx, req)
+	if err != nil {
+		return errors.Wrap(ErrAuthorization, err)
+	}
+	if !res.GetAuthorized() {
+		return ErrAuthorization
+	}
+	return nil
+}
+
+// save saves the new user and applies the policy template to it. The user
+// is removed along with the policies applied so far if the template fails,
+// so no user is left without the default policies.
+func (svc usersService) save(ctx context.Context, user User) (string, error) {
+	uid, err := svc.users.Save(ctx, user)
+	if err != nil {
+		return "", err
+	}
+	if err := svc.applyPolicyTemplate(ctx, uid); err != nil {
+		if rerr := svc.removePolicies(ctx, uid); rerr != nil {
+			return "", errors.Wrap(err, rerr)
+		}
+		if rerr := svc.users.Delete(ctx, uid); rerr != nil {
+			return "", errors.Wrap(err, rerr)
+		}
+		return "", err
+	}
+	return uid, nil
+}
+
+// applyPolicyTemplate adds the default policies of the new user, as
+// configured in the auth service.
+func (svc usersService) applyPolicyTemplate(ctx context.Context, userID string) error {
+	req := &mainflux.PolicyTemplateReq{
+		EntityType: userEntityType,
+		EntityID:   userID,
+	}
+	if _, err := svc.auth.ApplyPolicyTemplate(ctx, req); err != nil {
+		return errors.Wrap(ErrAuthorization, err)
 	}
 	return nil
 }
 
-func (svc usersService) members(ctx context.Context, token, groupID string, limit, offset uint64) ([]string, error) {
+// members lists all the members of the group, which are filtered and paged
+// along with the users, so that the total counts the matching members.
+func (svc usersService) members(ctx context.Context, token, groupID string) ([]string, error) {
 	req := mainflux.MembersReq{
 		Token:   token,
 		GroupID: groupID,
-		Offset:  offset,
-		Limit:   limit,
 		Type:    "users",
 	}
 
@@ -4,16 +4,22 @@
 package users_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/mainflux/mainflux"
+	mfauth "github.com/mainflux/mainflux/auth"
 	"github.com/mainflux/mainflux/pkg/errors"
 	"github.com/mainflux/mainflux/pkg/uuid"
 	"github.com/mainflux/mainflux/users"
 
+	"github.com/mainflux/mainflux/users/jsonschema"
 	"github.com/mainflux/mainflux/users/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,27 +30,92 @@ const wrong string = "wrong-value"
 var (
 	user            = users.User{Email: "user@example.com", Password: "password", Metadata: map[string]interface{}{"role": "user"}}
 	nonExistingUser = users.User{Email: "non-ex-user@example.com", Password: "password", Metadata: map[string]interface{}{"role": "user"}}
+	selfUser        = users.User{Email: "self@example.com", Password: "password"}
 	host            = "example.com"
 
 	idProvider = uuid.New()
-	passRegex  = regexp.MustCompile("^.{8,}$")
+	passPolicy = users.PasswordPolicy{MinLength: 8}
+
+	exportKey = []byte("export-key")
+	sections  = map[string]interface{}{"things": []map[string]string{{"id": "thing"}}}
 
 	unauthzToken = "unauthorizedtoken"
+
+	identities = map[string]users.Identity{
+		"user-code":       {Subject: "user-subject", Email: user.Email, EmailVerified: true},
+		"self-code":       {Subject: "self-subject", Email: selfUser.Email, EmailVerified: true},
+		"unverified-code": {Subject: "unverified-subject", Email: selfUser.Email},
+	}
+	providers = map[string]users.IdentityProvider{"idp": mocks.NewIdentityProvider(identities)}
 )
 
 func newService() users.Service {
+	return newConfiguredService(nil, users.SelfRegisterDisabled, false)
+}
+
+func newValidatedService(validator users.Validator) users.Service {
+	return newConfiguredService(validator, users.SelfRegisterDisabled, false)
+}
+
+func newConfiguredService(validator users.Validator, selfRegister users.SelfRegister, verifyEmail bool) users.Service {
+	return newServiceWith(validator, selfRegister, verifyEmail, users.Lockout{}, passPolicy)
+}
+
+func newPolicyService(lockout users.Lockout, passPolicy users.PasswordPolicy) users.Service {
+	return newServiceWith(nil, users.SelfRegisterDisabled, false, lockout, passPolicy)
+}
+
+func newServiceWith(validator users.Validator, selfRegister users.SelfRegister, verifyEmail bool, lockout users.Lockout, passPolicy users.PasswordPolicy) users.Service {
+	svc, _ := newServiceWithEmailer(validator, selfRegister, verifyEmail, lockout, passPolicy)
+	return svc
+}
+
+func newResetService(passPolicy users.PasswordPolicy) (users.Service, *mocks.Emailer) {
+	return newServiceWithEmailer(nil, users.SelfRegisterDisabled, false, users.Lockout{}, passPolicy)
+}
+
+func newServiceWithEmailer(validator users.Validator, selfRegister users.SelfRegister, verifyEmail bool, lockout users.Lockout, passPolicy users.PasswordPolicy) (users.Service, *mocks.Emailer) {
 	userRepo := mocks.NewUserRepository()
 	hasher := mocks.NewHasher()
 
 	mockAuthzDB := map[string][]mocks.SubjectSet{}
 	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: "authorities", Relation: "member"})
 	mockAuthzDB[unauthzToken] = append(mockAuthzDB[unauthzToken], mocks.SubjectSet{Object: "nothing", Relation: "do"})
-	mockUsers := map[string]string{user.Email: user.Email, unauthzToken: unauthzToken}
+	mockUsers := map[string]string{user.Email: user.Email, unauthzToken: unauthzToken, selfUser.Email: selfUser.Email}
 
 	auth := mocks.NewAuthService(mockUsers, mockAuthzDB)
 	e := mocks.NewEmailer()
 
-	return users.New(userRepo, hasher, auth, e, idProvider, passRegex)
+	exports := mocks.NewExportRepository()
+	source := mocks.NewExportSource(sections, nil)
+
+	return users.New(userRepo, hasher, auth, e, idProvider, exports, mocks.NewInvitationRepository(), mocks.NewActivityRepository(), source, users.Config{ExportKey: exportKey, Validator: validator, SelfRegister: selfRegister, VerifyEmail: verifyEmail, Providers: providers, Lockout: lockout, PasswordPolicy: passPolicy}), e
+}
+
+func TestRegisterValidator(t *testing.T) {
+	svc := newValidatedService(users.NewEmailDomainValidator("Example.com"))
+
+	cases := []struct {
+		desc string
+		user users.User
+		err  error
+	}{
+		{
+			desc: "register user of allowed email domain",
+			user: user,
+			err:  nil,
+		},
+		{
+			desc: "register user of other email domain",
+			user: users.User{Email: "user@other.com", Password: "password"},
+			err:  users.ErrValidation,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.Register(context.Background(), user.Email, tc.user)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
 }
 
 func TestRegister(t *testing.T) {
@@ -91,6 +162,139 @@ func TestRegister(t *testing.T) {
 	}
 }
 
+func TestRegisterPolicyTemplate(t *testing.T) {
+	mockAuthzDB := map[string][]mocks.SubjectSet{}
+	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: "authorities", Relation: "member"})
+	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email}, mockAuthzDB)
+	svc := users.New(mocks.NewUserRepository(), mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{PasswordPolicy: passPolicy})
+
+	newUser := users.User{Email: "new@example.com", Password: "password"}
+	uid, err := svc.Register(context.Background(), user.Email, newUser)
+	require.Nil(t, err, fmt.Sprintf("registering user expected to succeed: %s", err))
+	_, err = svc.Register(context.Background(), user.Email, newUser)
+	assert.True(t, errors.Contains(err, users.ErrConflict), fmt.Sprintf("registering existing user: expected %s got %s\n", users.ErrConflict, err))
+
+	// The template is applied only to the saved user.
+	res, err := auth.ListPolicies(context.Background(), &mainflux.ListPoliciesReq{Obj: "users", Act: "member"})
+	require.Nil(t, err, fmt.Sprintf("listing policies expected to succeed: %s", err))
+	assert.Equal(t, []string{uid}, res.GetPolicies(), fmt.Sprintf("expected template applied to %s got %v\n", uid, res.GetPolicies()))
+}
+
+func TestSelfRegister(t *testing.T) {
+	cases := []struct {
+		desc  string
+		mode  users.SelfRegister
+		token string
+		err   error
+		login error
+	}{
+		{
+			desc:  "self register with self registration disabled",
+			mode:  users.SelfRegisterDisabled,
+			token: "",
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "self register with self registration enabled",
+			mode:  users.SelfRegisterEnabled,
+			token: "",
+			err:   nil,
+			login: nil,
+		},
+		{
+			desc:  "self register with non-admin token with self registration enabled",
+			mode:  users.SelfRegisterEnabled,
+			token: unauthzToken,
+			err:   nil,
+			login: nil,
+		},
+		{
+			desc:  "self register with email verification",
+			mode:  users.SelfRegisterVerified,
+			token: "",
+			err:   nil,
+			login: users.ErrEmailNotVerified,
+		},
+		{
+			desc:  "register by admin with email verification",
+			mode:  users.SelfRegisterVerified,
+			token: user.Email,
+			err:   nil,
+			login: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		svc := newConfiguredService(nil, tc.mode, false)
+		_, err := svc.Register(context.Background(), tc.token, selfUser)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		_, _, err = svc.Login(context.Background(), selfUser)
+		assert.True(t, errors.Contains(err, tc.login), fmt.Sprintf("%s: expected login %s got %s\n", tc.desc, tc.login, err))
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	svc := newConfiguredService(nil, users.SelfRegisterVerified, false)
+	_, err := svc.Register(context.Background(), "", selfUser)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		err   error
+	}{
+		{
+			desc:  "verify email with invalid token",
+			token: wrong,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "verify email of non-existing user",
+			token: unauthzToken,
+			err:   users.ErrUserNotFound,
+		},
+		{
+			desc:  "verify email",
+			token: selfUser.Email,
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.VerifyEmail(context.Background(), tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, _, err = svc.Login(context.Background(), selfUser)
+	assert.Nil(t, err, fmt.Sprintf("login after verification: unexpected error: %s", err))
+}
+
+func TestRegisterVerifyEmail(t *testing.T) {
+	svc := newConfiguredService(nil, users.SelfRegisterDisabled, true)
+	uid, err := svc.Register(context.Background(), user.Email, selfUser)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	u, err := svc.ViewUser(context.Background(), user.Email, uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, users.StatusPending, u.Status(), fmt.Sprintf("expected status %s got %s\n", users.StatusPending, u.Status()))
+
+	_, _, err = svc.Login(context.Background(), selfUser)
+	assert.True(t, errors.Contains(err, users.ErrEmailNotVerified), fmt.Sprintf("login of pending user: expected %s got %s\n", users.ErrEmailNotVerified, err))
+
+	err = svc.VerifyEmail(context.Background(), selfUser.Email)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	u, err = svc.ViewUser(context.Background(), user.Email, uid)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, users.StatusActive, u.Status(), fmt.Sprintf("expected status %s got %s\n", users.StatusActive, u.Status()))
+
+	_, _, err = svc.Login(context.Background(), selfUser)
+	assert.Nil(t, err, fmt.Sprintf("login of active user: unexpected error: %s", err))
+}
+
 func TestLogin(t *testing.T) {
 	svc := newService()
 	_, err := svc.Register(context.Background(), user.Email, user)
@@ -130,17 +334,283 @@ func TestLogin(t *testing.T) {
 	}
 
 	for desc, tc := range cases {
-		_, err := svc.Login(context.Background(), tc.user)
+		_, _, err := svc.Login(context.Background(), tc.user)
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
 	}
 }
 
+func TestListActivity(t *testing.T) {
+	svc := newService()
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	ctx := mfauth.WithUserAgent(mfauth.WithClientIP(context.Background(), "10.0.0.1"), "Firefox")
+	_, _, err = svc.Login(ctx, user)
+	require.Nil(t, err, fmt.Sprintf("login error: %s", err))
+	_, _, err = svc.Login(ctx, users.User{Email: user.Email, Password: wrong})
+	require.True(t, errors.Contains(err, users.ErrUnauthorizedAccess), fmt.Sprintf("login with wrong password: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	_, _, err = svc.Login(ctx, users.User{Email: wrong, Password: wrong})
+	require.True(t, errors.Contains(err, users.ErrUnauthorizedAccess), fmt.Sprintf("login with wrong e-mail: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	cases := []struct {
+		desc    string
+		token   string
+		offset  uint64
+		limit   uint64
+		size    int
+		reasons []string
+		err     error
+	}{
+		{
+			desc:    "list activity",
+			token:   user.Email,
+			limit:   10,
+			size:    2,
+			reasons: []string{users.ReasonInvalidCredentials, ""},
+			err:     nil,
+		},
+		{
+			desc:    "list activity with offset",
+			token:   user.Email,
+			offset:  1,
+			limit:   10,
+			size:    1,
+			reasons: []string{""},
+			err:     nil,
+		},
+		{
+			desc:  "list activity with invalid token",
+			token: wrong,
+			limit: 10,
+			err:   users.ErrUnauthorizedAccess,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListActivity(context.Background(), tc.token, tc.offset, tc.limit)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		assert.Equal(t, uint64(2), page.Total, fmt.Sprintf("%s: expected total %d got %d\n", tc.desc, 2, page.Total))
+		require.Equal(t, tc.size, len(page.Attempts), fmt.Sprintf("%s: expected %d attempts got %d\n", tc.desc, tc.size, len(page.Attempts)))
+		for i, a := range page.Attempts {
+			assert.Equal(t, tc.reasons[i], a.Reason, fmt.Sprintf("%s: expected reason %s got %s\n", tc.desc, tc.reasons[i], a.Reason))
+			assert.Equal(t, tc.reasons[i] == "", a.Success, fmt.Sprintf("%s: unexpected success of attempt %d\n", tc.desc, i))
+			assert.Equal(t, "10.0.0.1", a.IP, fmt.Sprintf("%s: expected IP %s got %s\n", tc.desc, "10.0.0.1", a.IP))
+			assert.Equal(t, "Firefox", a.UserAgent, fmt.Sprintf("%s: expected user agent %s got %s\n", tc.desc, "Firefox", a.UserAgent))
+		}
+	}
+}
+
+func TestLoginAlerts(t *testing.T) {
+	mockAuthzDB := map[string][]mocks.SubjectSet{
+		user.Email: {{Object: "authorities", Relation: "member"}},
+	}
+	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email}, mockAuthzDB)
+	e := mocks.NewEmailer()
+	svc := users.New(mocks.NewUserRepository(), mocks.NewHasher(), auth, e, idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy, LoginAlerts: true})
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		ip   string
+		user users.User
+	}{
+		{"first login", "10.0.0.1", user},
+		{"login from known IP", "10.0.0.1", user},
+		{"login from new IP", "10.0.0.2", user},
+		{"failed login from new IP", "10.0.0.3", users.User{Email: user.Email, Password: wrong}},
+		{"login from unknown IP", "", user},
+	}
+	for _, tc := range cases {
+		svc.Login(mfauth.WithClientIP(context.Background(), tc.ip), tc.user)
+	}
+
+	alerts := e.LoginAlerts(user.Email)
+	assert.Equal(t, []string{"10.0.0.2"}, alerts, fmt.Sprintf("expected login alerts %v got %v\n", []string{"10.0.0.2"}, alerts))
+}
+
+func TestLoginLockout(t *testing.T) {
+	svc := newPolicyService(users.Lockout{Threshold: 3, Cooldown: time.Minute}, users.PasswordPolicy{})
+	id, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	wrongUser := users.User{Email: user.Email, Password: wrong}
+
+	cases := []struct {
+		desc string
+		user users.User
+		err  error
+	}{
+		{"login with wrong password", wrongUser, users.ErrUnauthorizedAccess},
+		{"login with wrong password again", wrongUser, users.ErrUnauthorizedAccess},
+		{"login with good credentials resetting failed logins", user, nil},
+		{"login with wrong password after reset", wrongUser, users.ErrUnauthorizedAccess},
+		{"login with wrong password after reset again", wrongUser, users.ErrUnauthorizedAccess},
+		{"login with wrong password reaching threshold", wrongUser, users.ErrUserLocked},
+		{"login locked user with good credentials", user, users.ErrUserLocked},
+		{"login locked user with wrong password", wrongUser, users.ErrUserLocked},
+	}
+
+	for _, tc := range cases {
+		_, _, err := svc.Login(context.Background(), tc.user)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	u, err := svc.ViewUser(context.Background(), user.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, users.StatusLocked, u.Status(), fmt.Sprintf("expected status %s got %s", users.StatusLocked, u.Status()))
+}
+
+func TestInvite(t *testing.T) {
+	inviter := "inviter@example.com"
+	invited := "invited@example.com"
+	mockAuthzDB := map[string][]mocks.SubjectSet{
+		user.Email: {{Object: "authorities", Relation: "member"}},
+		inviter:    {{Object: "group", Relation: "invite"}},
+	}
+	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email, inviter: inviter}, mockAuthzDB)
+	svc := users.New(mocks.NewUserRepository(), mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy})
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		inv   users.Invitation
+		err   error
+	}{
+		{
+			desc:  "invite user by admin",
+			token: user.Email,
+			inv:   users.Invitation{Email: invited},
+			err:   nil,
+		},
+		{
+			desc:  "invite user to group by admin",
+			token: user.Email,
+			inv:   users.Invitation{Email: invited, GroupID: "other-group"},
+			err:   nil,
+		},
+		{
+			desc:  "invite user to group by user allowed to invite",
+			token: inviter,
+			inv:   users.Invitation{Email: invited, GroupID: "group"},
+			err:   nil,
+		},
+		{
+			desc:  "invite user to other group by user allowed to invite",
+			token: inviter,
+			inv:   users.Invitation{Email: invited, GroupID: "other-group"},
+			err:   users.ErrAuthorization,
+		},
+		{
+			desc:  "invite user without group by user allowed to invite",
+			token: inviter,
+			inv:   users.Invitation{Email: invited},
+			err:   users.ErrAuthorization,
+		},
+		{
+			desc:  "invite user with invalid token",
+			token: wrong,
+			inv:   users.Invitation{Email: invited},
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "invite user with invalid email",
+			token: user.Email,
+			inv:   users.Invitation{Email: "invalid"},
+			err:   users.ErrMalformedEntity,
+		},
+		{
+			desc:  "invite registered user",
+			token: user.Email,
+			inv:   users.Invitation{Email: user.Email},
+			err:   users.ErrConflict,
+		},
+	}
+
+	for _, tc := range cases {
+		inv, err := svc.Invite(context.Background(), tc.token, tc.inv)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.NotEmpty(t, inv.ID, fmt.Sprintf("%s: expected invitation ID", tc.desc))
+			assert.True(t, inv.ExpiresAt.After(inv.CreatedAt), fmt.Sprintf("%s: expected invitation to expire after creation", tc.desc))
+		}
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	inviter := "inviter@example.com"
+	invited := users.User{Email: "invited@example.com", Password: "password"}
+	mockAuthzDB := map[string][]mocks.SubjectSet{
+		inviter: {{Object: "group", Relation: "invite"}},
+	}
+	auth := mocks.NewAuthService(map[string]string{inviter: inviter, invited.Email: invited.Email}, mockAuthzDB)
+	e := mocks.NewEmailer()
+	svc := users.New(mocks.NewUserRepository(), mocks.NewHasher(), auth, e, idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy})
+
+	_, err := svc.Invite(context.Background(), inviter, users.Invitation{Email: invited.Email, GroupID: "group"})
+	require.Nil(t, err, fmt.Sprintf("invite user error: %s", err))
+	token := e.Invitation(invited.Email)
+	require.NotEmpty(t, token, "expected the invitation token to be sent")
+
+	cases := []struct {
+		desc     string
+		token    string
+		password string
+		err      error
+	}{
+		{
+			desc:     "accept invitation with invalid token",
+			token:    wrong,
+			password: invited.Password,
+			err:      users.ErrInvalidInvitation,
+		},
+		{
+			desc:     "accept invitation with weak password",
+			token:    token,
+			password: "weak",
+			err:      users.ErrPasswordFormat,
+		},
+		{
+			desc:     "accept invitation",
+			token:    token,
+			password: invited.Password,
+			err:      nil,
+		},
+		{
+			desc:     "accept accepted invitation",
+			token:    token,
+			password: invited.Password,
+			err:      users.ErrInvalidInvitation,
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.AcceptInvitation(context.Background(), tc.token, tc.password)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, _, err = svc.Login(context.Background(), invited)
+	assert.Nil(t, err, fmt.Sprintf("login invited user: unexpected error: %s", err))
+
+	u, err := svc.ViewProfile(context.Background(), invited.Email)
+	require.Nil(t, err, fmt.Sprintf("view invited user error: %s", err))
+	res, err := auth.Authorize(context.Background(), &mainflux.AuthorizeReq{Sub: u.ID, Obj: "group", Act: "member"})
+	require.Nil(t, err, fmt.Sprintf("authorize invited user error: %s", err))
+	assert.True(t, res.GetAuthorized(), "expected the invited user to join the group")
+}
+
 func TestViewUser(t *testing.T) {
 	svc := newService()
 	id, err := svc.Register(context.Background(), user.Email, user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	token, err := svc.Login(context.Background(), user)
+	token, _, err := svc.Login(context.Background(), user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	u := user
@@ -183,7 +653,7 @@ func TestViewProfile(t *testing.T) {
 	_, err := svc.Register(context.Background(), user.Email, user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	token, err := svc.Login(context.Background(), user)
+	token, _, err := svc.Login(context.Background(), user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	u := user
@@ -218,28 +688,31 @@ func TestListUsers(t *testing.T) {
 	_, err := svc.Register(context.Background(), user.Email, user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	token, err := svc.Login(context.Background(), user)
+	token, _, err := svc.Login(context.Background(), user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	var nUsers = uint64(10)
 
+	var ids []string
 	for i := uint64(1); i < nUsers; i++ {
 		email := fmt.Sprintf("TestListUsers%d@example.com", i)
 		user := users.User{
 			Email:    email,
 			Password: "passpass",
 		}
-		_, err := svc.Register(context.Background(), token, user)
+		id, err := svc.Register(context.Background(), token, user)
 		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		ids = append(ids, id)
 	}
+	err = svc.DisableUser(context.Background(), token, ids[0])
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	cases := map[string]struct {
-		token  string
-		offset uint64
-		limit  uint64
-		email  string
-		size   uint64
-		err    error
+		token string
+		pm    users.PageMetadata
+		size  uint64
+		first string
+		err   error
 	}{
 		"list users with authorized token": {
 			token: token,
@@ -252,28 +725,122 @@ func TestListUsers(t *testing.T) {
 			err:   users.ErrUnauthorizedAccess,
 		},
 		"list users with offset and limit": {
-			token:  token,
-			offset: 6,
-			limit:  nUsers,
-			size:   nUsers - 6,
+			token: token,
+			pm:    users.PageMetadata{Offset: 6, Limit: nUsers},
+			size:  nUsers - 6,
+		},
+		"list disabled users": {
+			token: token,
+			pm:    users.PageMetadata{Limit: nUsers, Status: users.StatusDisabled},
+			size:  1,
+			first: "TestListUsers1@example.com",
+		},
+		"list active users": {
+			token: token,
+			pm:    users.PageMetadata{Limit: nUsers, Status: users.StatusActive},
+			size:  nUsers - 1,
+		},
+		"list users created in the future": {
+			token: token,
+			pm:    users.PageMetadata{Limit: nUsers, CreatedFrom: time.Now().Add(time.Hour)},
+			size:  0,
+		},
+		"list users sorted by email": {
+			token: token,
+			pm:    users.PageMetadata{Limit: nUsers, Email: "TestListUsers", Order: "email", Dir: "desc"},
+			size:  nUsers - 1,
+			first: "TestListUsers9@example.com",
 		},
 	}
 
 	for desc, tc := range cases {
-		page, err := svc.ListUsers(context.Background(), tc.token, tc.offset, tc.limit, tc.email, nil)
+		page, err := svc.ListUsers(context.Background(), tc.token, tc.pm)
 		size := uint64(len(page.Users))
 		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected size %d got %d\n", desc, tc.size, size))
 		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		if tc.first != "" && size > 0 {
+			assert.Equal(t, tc.first, page.Users[0].Email, fmt.Sprintf("%s: expected first user %s got %s\n", desc, tc.first, page.Users[0].Email))
+		}
 	}
 }
 
-func TestUpdateUser(t *testing.T) {
-	svc := newService()
-
-	_, err := svc.Register(context.Background(), user.Email, user)
+func TestListMembers(t *testing.T) {
+	groupID := "group-id"
+	userRepo := mocks.NewUserRepository()
+	mockAuthzDB := map[string][]mocks.SubjectSet{}
+	nMembers := uint64(5)
+	for i := uint64(0); i < nMembers; i++ {
+		id := fmt.Sprintf("member-%d", i)
+		_, err := userRepo.Save(context.Background(), users.User{ID: id, Email: fmt.Sprintf("member%d@example.com", i), Password: user.Password})
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		mockAuthzDB[id] = append(mockAuthzDB[id], mocks.SubjectSet{Object: groupID, Relation: "member"})
+	}
+	_, err := userRepo.Save(context.Background(), users.User{ID: user.Email, Email: user.Email, Password: user.Password})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email}, mockAuthzDB)
+	svc := users.New(userRepo, mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy})
+
+	cases := map[string]struct {
+		token   string
+		groupID string
+		pm      users.PageMetadata
+		size    uint64
+		total   uint64
+		err     error
+	}{
+		"list members with unauthorized token": {
+			token:   wrong,
+			groupID: groupID,
+			pm:      users.PageMetadata{Limit: nMembers},
+			err:     users.ErrUnauthorizedAccess,
+		},
+		"list all members": {
+			token:   user.Email,
+			groupID: groupID,
+			pm:      users.PageMetadata{Limit: nMembers},
+			size:    nMembers,
+			total:   nMembers,
+		},
+		"list members with offset and limit": {
+			token:   user.Email,
+			groupID: groupID,
+			pm:      users.PageMetadata{Offset: 1, Limit: 2},
+			size:    2,
+			total:   nMembers,
+		},
+		"list members filtered by email": {
+			token:   user.Email,
+			groupID: groupID,
+			pm:      users.PageMetadata{Limit: nMembers, Email: "member3"},
+			size:    1,
+			total:   1,
+		},
+		"list members of the group without members": {
+			token:   user.Email,
+			groupID: wrong,
+			pm:      users.PageMetadata{Limit: nMembers},
+			size:    0,
+			total:   0,
+		},
+	}
+
+	for desc, tc := range cases {
+		page, err := svc.ListMembers(context.Background(), tc.token, tc.groupID, tc.pm)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		size := uint64(len(page.Users))
+		assert.Equal(t, tc.size, size, fmt.Sprintf("%s: expected size %d got %d\n", desc, tc.size, size))
+		assert.Equal(t, tc.total, page.Total, fmt.Sprintf("%s: expected total %d got %d\n", desc, tc.total, page.Total))
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	svc := newService()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	token, err := svc.Login(context.Background(), user)
+	token, _, err := svc.Login(context.Background(), user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
 	user.Metadata = map[string]interface{}{"role": "test"}
@@ -301,6 +868,98 @@ func TestUpdateUser(t *testing.T) {
 	}
 }
 
+func TestUpdateUserValidator(t *testing.T) {
+	validator, err := jsonschema.New([]byte(`{"type": "object", "properties": {"role": {"enum": ["test", "admin"]}}}`))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	svc := newValidatedService(validator)
+
+	_, err = svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	token, _, err := svc.Login(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc string
+		meta users.Metadata
+		err  error
+	}{
+		{
+			desc: "update user with conforming metadata",
+			meta: users.Metadata{"role": "admin"},
+			err:  nil,
+		},
+		{
+			desc: "update user with metadata violating schema",
+			meta: users.Metadata{"role": "other"},
+			err:  users.ErrValidation,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UpdateUser(context.Background(), token, users.User{Metadata: tc.meta})
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	u, err := svc.ViewProfile(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, users.Metadata{"role": "admin"}, u.Metadata, "rejected metadata expected not to be saved")
+}
+
+func TestUpdateProfile(t *testing.T) {
+	svc := newService()
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	token, _, err := svc.Login(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	profile := users.Profile{
+		FirstName:   "Petar",
+		LastName:    "Petrović",
+		DisplayName: "pera",
+		Phone:       "+381641234567",
+		Locale:      "sr-RS",
+	}
+
+	cases := map[string]struct {
+		profile users.Profile
+		token   string
+		err     error
+	}{
+		"update profile with invalid token": {
+			profile: profile,
+			token:   "non-existent",
+			err:     users.ErrUnauthorizedAccess,
+		},
+		"update profile with invalid phone": {
+			profile: users.Profile{Phone: "0641234567"},
+			token:   token,
+			err:     users.ErrMalformedEntity,
+		},
+		"update profile with invalid locale": {
+			profile: users.Profile{Locale: "serbian"},
+			token:   token,
+			err:     users.ErrMalformedEntity,
+		},
+		"update profile with valid token": {
+			profile: profile,
+			token:   token,
+			err:     nil,
+		},
+	}
+
+	for desc, tc := range cases {
+		err := svc.UpdateProfile(context.Background(), tc.token, tc.profile)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+
+	u, err := svc.ViewProfile(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, profile, u.Profile, fmt.Sprintf("view profile: expected %v got %v\n", profile, u.Profile))
+}
+
 func TestGenerateResetToken(t *testing.T) {
 	svc := newService()
 	_, err := svc.Register(context.Background(), user.Email, user)
@@ -324,7 +983,7 @@ func TestChangePassword(t *testing.T) {
 	svc := newService()
 	_, err := svc.Register(context.Background(), user.Email, user)
 	require.Nil(t, err, fmt.Sprintf("register user error: %s", err))
-	token, _ := svc.Login(context.Background(), user)
+	token, _, _ := svc.Login(context.Background(), user)
 
 	cases := map[string]struct {
 		token       string
@@ -345,36 +1004,192 @@ func TestChangePassword(t *testing.T) {
 }
 
 func TestResetPassword(t *testing.T) {
-	svc := newService()
+	svc, e := newResetService(passPolicy)
 	_, err := svc.Register(context.Background(), user.Email, user)
 	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
 
-	mockAuthzDB := map[string][]mocks.SubjectSet{}
-	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: "authorities", Relation: "member"})
-	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email}, mockAuthzDB)
+	err = svc.GenerateResetToken(context.Background(), user.Email, host)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	replaced := e.PasswordReset(user.Email)
+	err = svc.GenerateResetToken(context.Background(), user.Email, host)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	resetToken := e.PasswordReset(user.Email)
 
-	resetToken, err := auth.Issue(context.Background(), &mainflux.IssueReq{Id: user.ID, Email: user.Email, Type: 2})
-	assert.Nil(t, err, fmt.Sprintf("Generating reset token expected to succeed: %s", err))
-	cases := map[string]struct {
+	cases := []struct {
+		desc     string
 		token    string
 		password string
 		err      error
 	}{
-		"valid user reset password ":   {resetToken.GetValue(), user.Email, nil},
-		"invalid user reset password ": {"", "newpassword", users.ErrUnauthorizedAccess},
+		{"reset password with empty token", "", "newpassword", users.ErrUnauthorizedAccess},
+		{"reset password with login token", user.Email, "newpassword", users.ErrUnauthorizedAccess},
+		{"reset password with replaced reset token", replaced, "newpassword", users.ErrUnauthorizedAccess},
+		{"reset password", resetToken, "newpassword", nil},
+		{"reset password with used reset token", resetToken, "otherpassword", users.ErrUnauthorizedAccess},
 	}
 
-	for desc, tc := range cases {
+	for _, tc := range cases {
 		err := svc.ResetPassword(context.Background(), tc.token, tc.password)
-		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestPasswordHistory(t *testing.T) {
+	svc, e := newResetService(users.PasswordPolicy{History: 3})
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user error: %s", err))
+
+	cases := []struct {
+		desc     string
+		password string
+		err      error
+	}{
+		{"change password to current password", user.Password, users.ErrPasswordReused},
+		{"change password to new password", "password-2", nil},
+		{"change password to previous password", user.Password, users.ErrPasswordReused},
+		{"change password to another new password", "password-3", nil},
+		{"change password to second previous password", user.Password, users.ErrPasswordReused},
+		{"change password to third new password", "password-4", nil},
+		{"change password to password out of history", user.Password, nil},
+	}
+
+	current := user.Password
+	for _, tc := range cases {
+		err := svc.ChangePassword(context.Background(), user.Email, tc.password, current)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if err == nil {
+			current = tc.password
+		}
+	}
+
+	err = svc.GenerateResetToken(context.Background(), user.Email, host)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	resetToken := e.PasswordReset(user.Email)
+
+	err = svc.ResetPassword(context.Background(), resetToken, "password-4")
+	assert.True(t, errors.Contains(err, users.ErrPasswordReused), fmt.Sprintf("reset password to previous password: expected %s got %s\n", users.ErrPasswordReused, err))
+
+	// The reset token isn't consumed by the rejected password.
+	err = svc.ResetPassword(context.Background(), resetToken, "password-5")
+	assert.Nil(t, err, fmt.Sprintf("reset password to new password: unexpected error: %s", err))
+}
+
+func TestPasswordStrength(t *testing.T) {
+	svc := newPolicyService(users.Lockout{}, users.PasswordPolicy{
+		MinLength:      10,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSpecial: true,
+		Pattern:        regexp.MustCompile("^[^ ]+$"),
+		Breaches:       mocks.NewBreachChecker("Password123!"),
+	})
+
+	cases := []struct {
+		desc     string
+		password string
+		rules    []string
+	}{
+		{
+			desc:     "register user with strong password",
+			password: "Str0ng-Passw0rd",
+			rules:    nil,
+		},
+		{
+			desc:     "register user with short password",
+			password: "Sh0rt!",
+			rules:    []string{users.RuleMinLength},
+		},
+		{
+			desc:     "register user with lowercase password",
+			password: "lowercase-only",
+			rules:    []string{users.RuleUpper, users.RuleDigit},
+		},
+		{
+			desc:     "register user with password of single class",
+			password: "1234567890",
+			rules:    []string{users.RuleUpper, users.RuleLower, users.RuleSpecial},
+		},
+		{
+			desc:     "register user with password not matching pattern",
+			password: "Str0ng Passw0rd",
+			rules:    []string{users.RulePattern},
+		},
+		{
+			desc:     "register user with breached password",
+			password: "Password123!",
+			rules:    []string{users.RuleBreached},
+		},
+	}
+
+	for _, tc := range cases {
+		_, err := svc.Register(context.Background(), user.Email, users.User{Email: "strength@example.com", Password: tc.password})
+		if tc.rules == nil {
+			assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			continue
+		}
+		assert.True(t, errors.Contains(err, users.ErrPasswordFormat), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, users.ErrPasswordFormat, err))
+		var rules []string
+		if pe, ok := err.(*users.PasswordError); ok {
+			rules = pe.Rules
+		}
+		assert.Equal(t, tc.rules, rules, fmt.Sprintf("%s: expected rules %v got %v", tc.desc, tc.rules, rules))
+	}
+}
+
+func TestRehashPassword(t *testing.T) {
+	userRepo := mocks.NewUserRepository()
+	_, err := userRepo.Save(context.Background(), users.User{ID: user.Email, Email: user.Email, Password: mocks.LegacyHashPrefix + user.Password, Verified: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email}, map[string][]mocks.SubjectSet{})
+	svc := users.New(userRepo, mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy})
+
+	cases := []struct {
+		desc string
+		user users.User
+		hash string
+		err  error
+	}{
+		{"login with wrong password", users.User{Email: user.Email, Password: wrong}, mocks.LegacyHashPrefix + user.Password, users.ErrUnauthorizedAccess},
+		{"login with legacy hash", user, user.Password, nil},
+		{"login with rehashed password", user, user.Password, nil},
+	}
+
+	for _, tc := range cases {
+		_, _, err := svc.Login(context.Background(), tc.user)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		u, err := userRepo.RetrieveByEmail(context.Background(), user.Email)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		assert.Equal(t, tc.hash, u.Password, fmt.Sprintf("%s: expected hash %s got %s\n", tc.desc, tc.hash, u.Password))
 	}
 }
 
+func TestPasswordExpiry(t *testing.T) {
+	svc := newPolicyService(users.Lockout{}, users.PasswordPolicy{MaxAge: time.Nanosecond})
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("register user error: %s", err))
+
+	token, refresh, err := svc.Login(context.Background(), user)
+	assert.True(t, errors.Contains(err, users.ErrPasswordExpired), fmt.Sprintf("login with expired password: expected %s got %s\n", users.ErrPasswordExpired, err))
+	assert.NotEmpty(t, token, "expected the reset token")
+	assert.Empty(t, refresh, "expected no refresh token")
+
+	_, err = svc.ViewProfile(context.Background(), token)
+	assert.True(t, errors.Contains(err, users.ErrUnauthorizedAccess), fmt.Sprintf("view profile with reset token: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	err = svc.ResetPassword(context.Background(), token, "newpassword")
+	assert.Nil(t, err, fmt.Sprintf("reset expired password: unexpected error: %s", err))
+
+	err = svc.ResetPassword(context.Background(), token, "otherpassword")
+	assert.True(t, errors.Contains(err, users.ErrUnauthorizedAccess), fmt.Sprintf("reset expired password again: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+}
+
 func TestSendPasswordReset(t *testing.T) {
 	svc := newService()
 	_, err := svc.Register(context.Background(), user.Email, user)
 	require.Nil(t, err, fmt.Sprintf("register user error: %s", err))
-	token, _ := svc.Login(context.Background(), user)
+	token, _, _ := svc.Login(context.Background(), user)
 
 	cases := map[string]struct {
 		token string
@@ -390,3 +1205,1010 @@ func TestSendPasswordReset(t *testing.T) {
 
 	}
 }
+
+func TestExportData(t *testing.T) {
+	svc := newService()
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	token, _, err := svc.Login(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.ExportData(context.Background(), "")
+	assert.True(t, errors.Contains(err, users.ErrUnauthorizedAccess), fmt.Sprintf("export with invalid token: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	e, err := svc.ExportData(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, users.ExportPending, e.Status, fmt.Sprintf("expected status %s got %s\n", users.ExportPending, e.Status))
+
+	completed := waitExport(t, svc, token)
+	assert.Equal(t, e.ID, completed.ID, fmt.Sprintf("expected the started export %s got %s\n", e.ID, completed.ID))
+	assert.NotEmpty(t, completed.Signature, "expected the completed export to be signed")
+
+	archive, err := svc.DownloadExport(context.Background(), completed.ID, completed.Signature)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	files := readArchive(t, archive)
+	assert.ElementsMatch(t, []string{"profile.json", "things.json"}, keys(files), "expected the profile and the collected sections")
+	assert.Contains(t, files["profile.json"], user.Email, "expected the profile to contain the user email")
+	assert.NotContains(t, files["profile.json"], "password", "expected the profile not to contain the password")
+}
+
+func TestDownloadExport(t *testing.T) {
+	svc := newService()
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	token, _, err := svc.Login(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.ExportData(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	e := waitExport(t, svc, token)
+
+	cases := map[string]struct {
+		id        string
+		signature string
+		err       error
+	}{
+		"download export":                      {e.ID, e.Signature, nil},
+		"download export with wrong signature": {e.ID, wrong, users.ErrInvalidSignature},
+		"download export with empty signature": {e.ID, "", users.ErrInvalidSignature},
+		"download non-existing export":         {wrong, e.Signature, users.ErrInvalidSignature},
+	}
+
+	for desc, tc := range cases {
+		_, err := svc.DownloadExport(context.Background(), tc.id, tc.signature)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", desc, tc.err, err))
+	}
+}
+
+func TestExportDataFailed(t *testing.T) {
+	userRepo := mocks.NewUserRepository()
+	mockAuthzDB := map[string][]mocks.SubjectSet{}
+	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: "authorities", Relation: "member"})
+	auth := mocks.NewAuthService(map[string]string{user.Email: user.Email}, mockAuthzDB)
+	source := mocks.NewExportSource(nil, errors.New("source unavailable"))
+	svc := users.New(userRepo, mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), source, users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy})
+
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	token, _, err := svc.Login(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.ExportData(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	e := waitExport(t, svc, token)
+	assert.Equal(t, users.ExportFailed, e.Status, fmt.Sprintf("expected status %s got %s\n", users.ExportFailed, e.Status))
+	assert.Empty(t, e.Signature, "expected the failed export not to be signed")
+
+	_, err = svc.DownloadExport(context.Background(), e.ID, e.Signature)
+	assert.True(t, errors.Contains(err, users.ErrInvalidSignature), fmt.Sprintf("download failed export: expected %s got %s\n", users.ErrInvalidSignature, err))
+}
+
+func TestDisableUser(t *testing.T) {
+	svc := newService()
+	id, err := svc.Register(context.Background(), user.Email, selfUser)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		userID string
+		err    error
+	}{
+		{
+			desc:   "disable user with unauthorized token",
+			token:  unauthzToken,
+			userID: id,
+			err:    users.ErrAuthorization,
+		},
+		{
+			desc:   "disable user with invalid token",
+			token:  wrong,
+			userID: id,
+			err:    users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "disable non-existing user",
+			token:  user.Email,
+			userID: wrong,
+			err:    users.ErrUserNotFound,
+		},
+		{
+			desc:   "disable user",
+			token:  user.Email,
+			userID: id,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.DisableUser(context.Background(), tc.token, tc.userID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, _, err = svc.Login(context.Background(), selfUser)
+	assert.True(t, errors.Contains(err, users.ErrUserDisabled), fmt.Sprintf("login disabled user: expected %s got %s\n", users.ErrUserDisabled, err))
+
+	err = svc.GenerateResetToken(context.Background(), selfUser.Email, host)
+	assert.True(t, errors.Contains(err, users.ErrUserDisabled), fmt.Sprintf("reset disabled user password: expected %s got %s\n", users.ErrUserDisabled, err))
+
+	u, err := svc.ViewUser(context.Background(), user.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Equal(t, users.StatusDisabled, u.Status(), fmt.Sprintf("expected status %s got %s\n", users.StatusDisabled, u.Status()))
+}
+
+func TestDisableUserRole(t *testing.T) {
+	userRepo := mocks.NewUserRepository()
+	otherID := "other-id"
+	_, err := userRepo.Save(context.Background(), users.User{ID: otherID, Email: nonExistingUser.Email, Password: nonExistingUser.Password, Verified: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	managerID := "manager-id"
+	mockAuthzDB := map[string][]mocks.SubjectSet{}
+	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: "authorities", Relation: "member"})
+	mockAuthzDB[managerID] = append(mockAuthzDB[managerID], mocks.SubjectSet{Object: "authorities", Relation: "manage_users"})
+	mockUsers := map[string]string{user.Email: user.Email, selfUser.Email: managerID}
+	auth := mocks.NewAuthService(mockUsers, mockAuthzDB)
+	svc := users.New(userRepo, mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{PasswordPolicy: passPolicy})
+
+	cases := []struct {
+		desc   string
+		token  string
+		userID string
+		err    error
+	}{
+		{
+			desc:   "disable admin as user manager",
+			token:  selfUser.Email,
+			userID: user.Email,
+			err:    users.ErrAuthorization,
+		},
+		{
+			desc:   "disable user as user manager",
+			token:  selfUser.Email,
+			userID: otherID,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.DisableUser(context.Background(), tc.token, tc.userID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestEnableUser(t *testing.T) {
+	svc := newService()
+	id, err := svc.Register(context.Background(), user.Email, selfUser)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.DisableUser(context.Background(), user.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		userID string
+		err    error
+	}{
+		{
+			desc:   "enable user with unauthorized token",
+			token:  unauthzToken,
+			userID: id,
+			err:    users.ErrAuthorization,
+		},
+		{
+			desc:   "enable non-existing user",
+			token:  user.Email,
+			userID: wrong,
+			err:    users.ErrUserNotFound,
+		},
+		{
+			desc:   "enable user",
+			token:  user.Email,
+			userID: id,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.EnableUser(context.Background(), tc.token, tc.userID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, _, err = svc.Login(context.Background(), selfUser)
+	assert.Nil(t, err, fmt.Sprintf("login enabled user: unexpected error: %s", err))
+}
+
+func TestUnlockUser(t *testing.T) {
+	svc := newPolicyService(users.Lockout{Threshold: 1, Cooldown: time.Hour}, users.PasswordPolicy{})
+	id, err := svc.Register(context.Background(), user.Email, selfUser)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	_, _, err = svc.Login(context.Background(), users.User{Email: selfUser.Email, Password: wrong})
+	require.True(t, errors.Contains(err, users.ErrUserLocked), fmt.Sprintf("expected %s got %s", users.ErrUserLocked, err))
+
+	cases := []struct {
+		desc   string
+		token  string
+		userID string
+		err    error
+	}{
+		{
+			desc:   "unlock user with unauthorized token",
+			token:  unauthzToken,
+			userID: id,
+			err:    users.ErrAuthorization,
+		},
+		{
+			desc:   "unlock non-existing user",
+			token:  user.Email,
+			userID: wrong,
+			err:    users.ErrUserNotFound,
+		},
+		{
+			desc:   "unlock user",
+			token:  user.Email,
+			userID: id,
+			err:    nil,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.UnlockUser(context.Background(), tc.token, tc.userID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, _, err = svc.Login(context.Background(), selfUser)
+	assert.Nil(t, err, fmt.Sprintf("login unlocked user: unexpected error: %s", err))
+}
+
+func newRolesService(t *testing.T) (users.Service, string) {
+	userRepo := mocks.NewUserRepository()
+	_, err := userRepo.Save(context.Background(), users.User{ID: user.Email, Email: user.Email, Password: user.Password, Verified: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	selfID := "self-id"
+	_, err = userRepo.Save(context.Background(), users.User{ID: selfID, Email: selfUser.Email, Password: selfUser.Password, Verified: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	mockAuthzDB := map[string][]mocks.SubjectSet{}
+	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: "authorities", Relation: "member"})
+	mockUsers := map[string]string{user.Email: user.Email, selfUser.Email: selfID, unauthzToken: unauthzToken}
+	auth := mocks.NewAuthService(mockUsers, mockAuthzDB)
+	svc := users.New(userRepo, mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy})
+	return svc, selfID
+}
+
+func TestImportUsers(t *testing.T) {
+	userRepo := mocks.NewUserRepository()
+	_, err := userRepo.Save(context.Background(), users.User{ID: user.Email, Email: user.Email, Password: user.Password, Verified: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	mockAuthzDB := map[string][]mocks.SubjectSet{
+		user.Email: {{Object: "authorities", Relation: "member"}, {Object: "group", Relation: "invite"}},
+	}
+	mockUsers := map[string]string{user.Email: user.Email, selfUser.Email: selfUser.Email}
+	auth := mocks.NewAuthService(mockUsers, mockAuthzDB)
+	svc := users.New(userRepo, mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy})
+
+	records := []users.UserRecord{
+		{Email: "plain@example.com", Password: "password", Groups: []string{"group"}},
+		{Email: "hashed@example.com", PasswordHash: "password-hash"},
+		{Email: "generated@example.com"},
+		{Email: "both@example.com", Password: "password", PasswordHash: "password-hash"},
+		{Email: "invalid-email", Password: "password"},
+		{Email: user.Email, Password: "password"},
+		{Email: "short@example.com", Password: "short"},
+		{Email: "ungrouped@example.com", Password: "password", Groups: []string{wrong}},
+	}
+
+	cases := []struct {
+		desc      string
+		hash      string
+		generated bool
+		groups    []string
+		err       error
+	}{
+		{
+			desc:   "import user with password",
+			hash:   "password",
+			groups: []string{"group"},
+			err:    nil,
+		},
+		{
+			desc: "import user with password hash",
+			hash: "password-hash",
+			err:  nil,
+		},
+		{
+			desc:      "import user with generated password",
+			generated: true,
+			err:       nil,
+		},
+		{
+			desc: "import user with both password and password hash",
+			err:  users.ErrMalformedEntity,
+		},
+		{
+			desc: "import user with invalid email",
+			err:  users.ErrMalformedEntity,
+		},
+		{
+			desc: "import existing user",
+			err:  users.ErrConflict,
+		},
+		{
+			desc: "import user with password violating the policy",
+			err:  users.ErrPasswordFormat,
+		},
+		{
+			desc: "import user to the group without invite rights",
+			hash: "password",
+			err:  users.ErrAuthorization,
+		},
+	}
+
+	results, err := svc.ImportUsers(context.Background(), user.Email, records)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	require.Equal(t, len(cases), len(results), fmt.Sprintf("expected %d results got %d\n", len(cases), len(results)))
+
+	for i, tc := range cases {
+		res := results[i]
+		assert.Equal(t, records[i].Email, res.Email, fmt.Sprintf("%s: expected email %s got %s\n", tc.desc, records[i].Email, res.Email))
+		assert.True(t, errors.Contains(res.Err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, res.Err))
+		if res.ID == "" {
+			continue
+		}
+		assert.Equal(t, tc.generated, res.Password != "", fmt.Sprintf("%s: unexpected generated password %s\n", tc.desc, res.Password))
+		hash := tc.hash
+		if tc.generated {
+			hash = res.Password
+		}
+		u, err := userRepo.RetrieveByEmail(context.Background(), res.Email)
+		require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, hash, u.Password, fmt.Sprintf("%s: expected password hash %s got %s\n", tc.desc, hash, u.Password))
+		assert.True(t, u.Verified, fmt.Sprintf("%s: expected imported user to be verified\n", tc.desc))
+		for _, g := range tc.groups {
+			res, err := auth.Authorize(context.Background(), &mainflux.AuthorizeReq{Sub: u.ID, Obj: g, Act: "member"})
+			require.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+			assert.True(t, res.GetAuthorized(), fmt.Sprintf("%s: expected user to be member of %s\n", tc.desc, g))
+		}
+	}
+
+	failures := []struct {
+		desc    string
+		token   string
+		records []users.UserRecord
+		err     error
+	}{
+		{
+			desc:    "import users with invalid token",
+			token:   wrong,
+			records: records,
+			err:     users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:    "import users as non-admin",
+			token:   selfUser.Email,
+			records: records,
+			err:     users.ErrAuthorization,
+		},
+		{
+			desc:    "import no users",
+			token:   user.Email,
+			records: []users.UserRecord{},
+			err:     users.ErrMalformedEntity,
+		},
+	}
+
+	for _, tc := range failures {
+		_, err := svc.ImportUsers(context.Background(), tc.token, tc.records)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+}
+
+func TestExportUsers(t *testing.T) {
+	svc, selfID := newRolesService(t)
+
+	cases := []struct {
+		desc    string
+		token   string
+		records []users.UserRecord
+		err     error
+	}{
+		{
+			desc:  "export users with invalid token",
+			token: wrong,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "export users as non-admin",
+			token: selfUser.Email,
+			err:   users.ErrAuthorization,
+		},
+		{
+			desc:  "export users",
+			token: user.Email,
+			records: []users.UserRecord{
+				{ID: selfID, Email: selfUser.Email, PasswordHash: selfUser.Password, Groups: []string{}},
+				{ID: user.Email, Email: user.Email, PasswordHash: user.Password, Groups: []string{}},
+			},
+			err: nil,
+		},
+	}
+
+	for _, tc := range cases {
+		records, err := svc.ExportUsers(context.Background(), tc.token)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		assert.Equal(t, tc.records, records, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.records, records))
+	}
+}
+
+func TestAssignRole(t *testing.T) {
+	svc, selfID := newRolesService(t)
+
+	cases := []struct {
+		desc   string
+		token  string
+		userID string
+		role   string
+		err    error
+	}{
+		{
+			desc:   "assign role with unauthorized token",
+			token:  unauthzToken,
+			userID: selfID,
+			role:   users.RoleUserManager,
+			err:    users.ErrAuthorization,
+		},
+		{
+			desc:   "assign invalid role",
+			token:  user.Email,
+			userID: selfID,
+			role:   wrong,
+			err:    users.ErrInvalidRole,
+		},
+		{
+			desc:   "assign role to non-existing user",
+			token:  user.Email,
+			userID: wrong,
+			role:   users.RoleUserManager,
+			err:    users.ErrUserNotFound,
+		},
+		{
+			desc:   "assign user manager role",
+			token:  user.Email,
+			userID: selfID,
+			role:   users.RoleUserManager,
+			err:    nil,
+		},
+		{
+			desc:   "assign role as user manager",
+			token:  selfUser.Email,
+			userID: selfID,
+			role:   users.RoleAdmin,
+			err:    users.ErrAuthorization,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.AssignRole(context.Background(), tc.token, tc.userID, tc.role)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	err := svc.DisableUser(context.Background(), selfUser.Email, user.Email)
+	assert.True(t, errors.Contains(err, users.ErrAuthorization), fmt.Sprintf("disable admin as user manager: expected %s got %s\n", users.ErrAuthorization, err))
+}
+
+func TestRevokeRole(t *testing.T) {
+	svc, selfID := newRolesService(t)
+
+	cases := []struct {
+		desc   string
+		token  string
+		userID string
+		role   string
+		err    error
+	}{
+		{
+			desc:   "revoke role with unauthorized token",
+			token:  unauthzToken,
+			userID: user.Email,
+			role:   users.RoleAdmin,
+			err:    users.ErrAuthorization,
+		},
+		{
+			desc:   "revoke invalid role",
+			token:  user.Email,
+			userID: user.Email,
+			role:   wrong,
+			err:    users.ErrInvalidRole,
+		},
+		{
+			desc:   "revoke role of the last admin",
+			token:  user.Email,
+			userID: user.Email,
+			role:   users.RoleAdmin,
+			err:    users.ErrLastAdmin,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.RevokeRole(context.Background(), tc.token, tc.userID, tc.role)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	err := svc.AssignRole(context.Background(), user.Email, selfID, users.RoleAdmin)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.RevokeRole(context.Background(), selfUser.Email, user.Email, users.RoleAdmin)
+	assert.Nil(t, err, fmt.Sprintf("revoke admin role: unexpected error: %s", err))
+	_, err = svc.ListRoleMembers(context.Background(), user.Email, users.RoleAdmin, 0, 10)
+	assert.True(t, errors.Contains(err, users.ErrAuthorization), fmt.Sprintf("list roles as revoked admin: expected %s got %s\n", users.ErrAuthorization, err))
+}
+
+func TestListRoleMembers(t *testing.T) {
+	svc, selfID := newRolesService(t)
+	err := svc.AssignRole(context.Background(), user.Email, selfID, users.RoleInviter)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		role  string
+		ids   []string
+		err   error
+	}{
+		{
+			desc:  "list role members with unauthorized token",
+			token: unauthzToken,
+			role:  users.RoleAdmin,
+			err:   users.ErrAuthorization,
+		},
+		{
+			desc:  "list members of invalid role",
+			token: user.Email,
+			role:  wrong,
+			err:   users.ErrInvalidRole,
+		},
+		{
+			desc:  "list admins",
+			token: user.Email,
+			role:  users.RoleAdmin,
+			ids:   []string{user.Email},
+			err:   nil,
+		},
+		{
+			desc:  "list inviters",
+			token: user.Email,
+			role:  users.RoleInviter,
+			ids:   []string{selfID},
+			err:   nil,
+		},
+		{
+			desc:  "list user managers",
+			token: user.Email,
+			role:  users.RoleUserManager,
+			ids:   []string{},
+			err:   nil,
+		},
+	}
+
+	for _, tc := range cases {
+		page, err := svc.ListRoleMembers(context.Background(), tc.token, tc.role, 0, 10)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		ids := []string{}
+		for _, u := range page.Users {
+			ids = append(ids, u.ID)
+		}
+		assert.ElementsMatch(t, tc.ids, ids, fmt.Sprintf("%s: expected %v got %v\n", tc.desc, tc.ids, ids))
+	}
+}
+
+func TestLifecycleEvents(t *testing.T) {
+	mockAuthzDB := map[string][]mocks.SubjectSet{}
+	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: "authorities", Relation: "member"})
+	mockUsers := map[string]string{user.Email: user.Email, selfUser.Email: selfUser.Email}
+	auth := mocks.NewAuthService(mockUsers, mockAuthzDB)
+	notifier := mocks.NewEventNotifier()
+	svc := users.New(mocks.NewUserRepository(), mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy, Events: notifier})
+
+	id, err := svc.Register(context.Background(), user.Email, selfUser)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	token, _, err := svc.Login(context.Background(), selfUser)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	metadata := users.Metadata{"plan": "premium"}
+	err = svc.UpdateUser(context.Background(), token, users.User{Metadata: metadata})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.DisableUser(context.Background(), user.Email, wrong)
+	assert.True(t, errors.Contains(err, users.ErrUserNotFound), fmt.Sprintf("disable non-existing user: expected %s got %s\n", users.ErrUserNotFound, err))
+	err = svc.DisableUser(context.Background(), user.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.EnableUser(context.Background(), user.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.DeleteUser(context.Background(), user.Email, id)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		typ      string
+		metadata users.Metadata
+	}{
+		{typ: users.EventUserCreated, metadata: selfUser.Metadata},
+		{typ: users.EventUserUpdated, metadata: metadata},
+		{typ: users.EventUserDisabled, metadata: metadata},
+		{typ: users.EventUserEnabled, metadata: metadata},
+		{typ: users.EventUserDeleted, metadata: metadata},
+	}
+
+	events := notifier.Events()
+	require.Equal(t, len(cases), len(events), fmt.Sprintf("expected %d events got %d", len(cases), len(events)))
+	ids := map[string]bool{}
+	for i, tc := range cases {
+		e := events[i]
+		assert.Equal(t, tc.typ, e.Type, fmt.Sprintf("event %d: expected type %s got %s", i, tc.typ, e.Type))
+		assert.Equal(t, id, e.UserID, fmt.Sprintf("%s: expected user ID %s got %s", tc.typ, id, e.UserID))
+		assert.Equal(t, selfUser.Email, e.Email, fmt.Sprintf("%s: expected email %s got %s", tc.typ, selfUser.Email, e.Email))
+		assert.Equal(t, tc.metadata, e.Metadata, fmt.Sprintf("%s: expected metadata %v got %v", tc.typ, tc.metadata, e.Metadata))
+		assert.False(t, ids[e.ID], fmt.Sprintf("%s: expected unique event ID got %s", tc.typ, e.ID))
+		ids[e.ID] = true
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	userRepo := mocks.NewUserRepository()
+	selfID := "self-id"
+	_, err := userRepo.Save(context.Background(), users.User{ID: selfID, Email: selfUser.Email, Password: selfUser.Password, Verified: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	otherID := "other-id"
+	_, err = userRepo.Save(context.Background(), users.User{ID: otherID, Email: nonExistingUser.Email, Password: nonExistingUser.Password, Verified: true})
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	mockAuthzDB := map[string][]mocks.SubjectSet{}
+	mockAuthzDB[user.Email] = append(mockAuthzDB[user.Email], mocks.SubjectSet{Object: "authorities", Relation: "member"})
+	mockAuthzDB[selfID] = []mocks.SubjectSet{{Object: "thing", Relation: "read"}, {Object: "thing", Relation: "write"}, {Object: "group", Relation: "member"}}
+	mockUsers := map[string]string{user.Email: user.Email, selfUser.Email: selfID, unauthzToken: unauthzToken}
+	auth := mocks.NewAuthService(mockUsers, mockAuthzDB)
+	svc := users.New(userRepo, mocks.NewHasher(), auth, mocks.NewEmailer(), idProvider, mocks.NewExportRepository(), mocks.NewInvitationRepository(), mocks.NewActivityRepository(), mocks.NewExportSource(sections, nil), users.Config{ExportKey: exportKey, Providers: providers, PasswordPolicy: passPolicy})
+
+	cases := []struct {
+		desc   string
+		token  string
+		userID string
+		err    error
+	}{
+		{
+			desc:   "delete other user with unauthorized token",
+			token:  unauthzToken,
+			userID: otherID,
+			err:    users.ErrAuthorization,
+		},
+		{
+			desc:   "delete user with invalid token",
+			token:  wrong,
+			userID: selfID,
+			err:    users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:   "delete non-existing user",
+			token:  user.Email,
+			userID: wrong,
+			err:    users.ErrUserNotFound,
+		},
+		{
+			desc:   "delete self",
+			token:  selfUser.Email,
+			userID: selfID,
+			err:    nil,
+		},
+		{
+			desc:   "delete other user as admin",
+			token:  user.Email,
+			userID: otherID,
+			err:    nil,
+		},
+		{
+			desc:   "delete deleted user",
+			token:  user.Email,
+			userID: otherID,
+			err:    users.ErrUserNotFound,
+		},
+		{
+			desc:   "delete last admin",
+			token:  user.Email,
+			userID: user.Email,
+			err:    users.ErrLastAdmin,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.DeleteUser(context.Background(), tc.token, tc.userID)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = userRepo.RetrieveByID(context.Background(), selfID)
+	assert.True(t, errors.Contains(err, users.ErrNotFound), fmt.Sprintf("retrieve deleted user: expected %s got %s\n", users.ErrNotFound, err))
+	assert.Empty(t, mockAuthzDB[selfID], fmt.Sprintf("expected the policies of the deleted user to be removed, got %v\n", mockAuthzDB[selfID]))
+}
+
+func TestEnrollMFA(t *testing.T) {
+	svc := newService()
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	token, _, err := svc.Login(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	_, err = svc.EnrollMFA(context.Background(), wrong)
+	assert.True(t, errors.Contains(err, users.ErrUnauthorizedAccess), fmt.Sprintf("enroll with invalid token: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+	err = svc.ConfirmMFA(context.Background(), token, "123456")
+	assert.True(t, errors.Contains(err, users.ErrMFANotEnrolled), fmt.Sprintf("confirm without enrollment: expected %s got %s\n", users.ErrMFANotEnrolled, err))
+
+	e, err := svc.EnrollMFA(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.Contains(t, e.URI, "otpauth://totp/", fmt.Sprintf("expected provisioning URI got %s\n", e.URI))
+	assert.Contains(t, e.URI, e.Secret, fmt.Sprintf("expected provisioning URI to carry the secret got %s\n", e.URI))
+	assert.Len(t, e.RecoveryCodes, 10, fmt.Sprintf("expected 10 recovery codes got %d\n", len(e.RecoveryCodes)))
+
+	// The login doesn't require the MFA until the enrollment is confirmed.
+	_, _, err = svc.Login(context.Background(), user)
+	assert.Nil(t, err, fmt.Sprintf("login before confirmation: unexpected error: %s", err))
+
+	code, err := users.TOTP(e.Secret, time.Now())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc  string
+		token string
+		code  string
+		err   error
+	}{
+		{
+			desc:  "confirm with invalid token",
+			token: wrong,
+			code:  code,
+			err:   users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:  "confirm with invalid code",
+			token: token,
+			code:  wrong,
+			err:   users.ErrInvalidMFACode,
+		},
+		{
+			desc:  "confirm with valid code",
+			token: token,
+			code:  code,
+			err:   nil,
+		},
+		{
+			desc:  "confirm confirmed enrollment",
+			token: token,
+			code:  code,
+			err:   users.ErrMFAEnrolled,
+		},
+	}
+
+	for _, tc := range cases {
+		err := svc.ConfirmMFA(context.Background(), tc.token, tc.code)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+	}
+
+	_, err = svc.EnrollMFA(context.Background(), token)
+	assert.True(t, errors.Contains(err, users.ErrMFAEnrolled), fmt.Sprintf("enroll again: expected %s got %s\n", users.ErrMFAEnrolled, err))
+}
+
+func TestLoginMFA(t *testing.T) {
+	svc := newService()
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	token, _, err := svc.Login(context.Background(), user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	e, err := svc.EnrollMFA(context.Background(), token)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	code, err := users.TOTP(e.Secret, time.Now())
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	err = svc.ConfirmMFA(context.Background(), token, code)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	challenge, refresh, err := svc.Login(context.Background(), user)
+	assert.True(t, errors.Contains(err, users.ErrMFARequired), fmt.Sprintf("login enrolled user: expected %s got %s\n", users.ErrMFARequired, err))
+	assert.NotEmpty(t, challenge, "expected the challenge token")
+	assert.Empty(t, refresh, "expected no refresh token")
+
+	_, err = svc.ViewProfile(context.Background(), challenge)
+	assert.True(t, errors.Contains(err, users.ErrUnauthorizedAccess), fmt.Sprintf("use challenge as access token: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+
+	// The challenge is issued by the new login, unless it's set.
+	cases := []struct {
+		desc      string
+		challenge string
+		code      string
+		err       error
+	}{
+		{
+			desc:      "login with invalid challenge",
+			challenge: wrong,
+			code:      code,
+			err:       users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:      "login with invalid code",
+			challenge: challenge,
+			code:      wrong,
+			err:       users.ErrInvalidMFACode,
+		},
+		{
+			desc:      "login with used challenge",
+			challenge: challenge,
+			code:      code,
+			err:       users.ErrUnauthorizedAccess,
+		},
+		{
+			desc: "login with one time code",
+			code: code,
+			err:  nil,
+		},
+		{
+			desc: "login with recovery code",
+			code: e.RecoveryCodes[0],
+			err:  nil,
+		},
+		{
+			desc: "login with used recovery code",
+			code: e.RecoveryCodes[0],
+			err:  users.ErrInvalidMFACode,
+		},
+		{
+			desc: "login with another recovery code",
+			code: e.RecoveryCodes[1],
+			err:  nil,
+		},
+	}
+
+	for _, tc := range cases {
+		if tc.challenge == "" {
+			tc.challenge, _, err = svc.Login(context.Background(), user)
+			require.True(t, errors.Contains(err, users.ErrMFARequired), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, users.ErrMFARequired, err))
+		}
+		token, _, err := svc.LoginMFA(context.Background(), tc.challenge, tc.code)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err == nil {
+			assert.NotEmpty(t, token, fmt.Sprintf("%s: expected the access token\n", tc.desc))
+		}
+	}
+
+	err = svc.ChangePassword(context.Background(), token, "new-password", user.Password)
+	assert.Nil(t, err, fmt.Sprintf("change password of enrolled user: unexpected error: %s", err))
+}
+
+func TestOAuthURL(t *testing.T) {
+	svc := newService()
+
+	url, state, err := svc.OAuthURL(context.Background(), "idp")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	assert.NotEmpty(t, state, "expected the state")
+	assert.Contains(t, url, mocks.ProviderURL, fmt.Sprintf("expected the provider URL got %s", url))
+	assert.Contains(t, url, state, fmt.Sprintf("expected the URL carrying the state got %s", url))
+
+	_, _, err = svc.OAuthURL(context.Background(), wrong)
+	assert.True(t, errors.Contains(err, users.ErrUnknownProvider), fmt.Sprintf("unknown provider: expected %s got %s\n", users.ErrUnknownProvider, err))
+}
+
+func TestOAuthLogin(t *testing.T) {
+	svc := newConfiguredService(nil, users.SelfRegisterEnabled, false)
+	_, err := svc.Register(context.Background(), user.Email, user)
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	cases := []struct {
+		desc     string
+		provider string
+		code     string
+		email    string
+		err      error
+	}{
+		{
+			desc:     "login with unknown provider",
+			provider: wrong,
+			code:     "user-code",
+			err:      users.ErrUnknownProvider,
+		},
+		{
+			desc:     "login with invalid code",
+			provider: "idp",
+			code:     wrong,
+			err:      users.ErrUnauthorizedAccess,
+		},
+		{
+			desc:     "login with unverified email",
+			provider: "idp",
+			code:     "unverified-code",
+			err:      users.ErrEmailNotVerified,
+		},
+		{
+			desc:     "login linking existing user",
+			provider: "idp",
+			code:     "user-code",
+			email:    user.Email,
+			err:      nil,
+		},
+		{
+			desc:     "login with linked identity",
+			provider: "idp",
+			code:     "user-code",
+			email:    user.Email,
+			err:      nil,
+		},
+		{
+			desc:     "login provisioning new user",
+			provider: "idp",
+			code:     "self-code",
+			email:    selfUser.Email,
+			err:      nil,
+		},
+		{
+			desc:     "login with identity of provisioned user",
+			provider: "idp",
+			code:     "self-code",
+			email:    selfUser.Email,
+			err:      nil,
+		},
+	}
+
+	for _, tc := range cases {
+		token, _, err := svc.OAuthLogin(context.Background(), tc.provider, tc.code)
+		assert.True(t, errors.Contains(err, tc.err), fmt.Sprintf("%s: expected %s got %s\n", tc.desc, tc.err, err))
+		if tc.err != nil {
+			continue
+		}
+		u, err := svc.ViewProfile(context.Background(), token)
+		assert.Nil(t, err, fmt.Sprintf("%s: unexpected error: %s", tc.desc, err))
+		assert.Equal(t, tc.email, u.Email, fmt.Sprintf("%s: expected user %s got %s\n", tc.desc, tc.email, u.Email))
+		assert.Equal(t, users.StatusActive, u.Status(), fmt.Sprintf("%s: expected status %s got %s\n", tc.desc, users.StatusActive, u.Status()))
+	}
+
+	// The self registration disabled, only the existing users log in.
+	svc = newService()
+	_, _, err = svc.OAuthLogin(context.Background(), "idp", "self-code")
+	assert.True(t, errors.Contains(err, users.ErrUnauthorizedAccess), fmt.Sprintf("provisioning with self registration disabled: expected %s got %s\n", users.ErrUnauthorizedAccess, err))
+}
+
+// waitExport polls the export until the background job finishes it.
+func waitExport(t *testing.T, svc users.Service, token string) users.Export {
+	for i := 0; i < 100; i++ {
+		e, err := svc.ExportData(context.Background(), token)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		if e.Status != users.ExportPending {
+			return e
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.FailNow(t, "export expected to finish")
+	return users.Export{}
+}
+
+func readArchive(t *testing.T, archive []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		data, err := ioutil.ReadAll(r)
+		require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+		r.Close()
+		files[f.Name] = string(data)
+	}
+	return files
+}
+
+func keys(files map[string]string) []string {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	return names
+}
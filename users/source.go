@@ -0,0 +1,93 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+	mfsdk "github.com/mainflux/mainflux/pkg/sdk/go"
+)
+
+const sourcePageLimit = 100
+
+var (
+	errCollectGroups   = errors.New("failed to collect group memberships")
+	errCollectThings   = errors.New("failed to collect things")
+	errCollectChannels = errors.New("failed to collect channels")
+	errCollectKeys     = errors.New("failed to collect keys")
+)
+
+var _ ExportSource = (*sdkSource)(nil)
+
+type sdkSource struct {
+	sdk mfsdk.SDK
+}
+
+// NewExportSource returns ExportSource backed by the auth and the things
+// services. The data is retrieved on behalf of the user, with the token of
+// the user who requested the export.
+func NewExportSource(sdk mfsdk.SDK) ExportSource {
+	return sdkSource{sdk: sdk}
+}
+
+func (s sdkSource) Collect(ctx context.Context, token, userID string) (map[string]interface{}, error) {
+	groups := []mfsdk.Group{}
+	for offset := uint64(0); ; offset += sourcePageLimit {
+		gp, err := s.sdk.Memberships(userID, token, offset, sourcePageLimit)
+		if err != nil {
+			return nil, errors.Wrap(errCollectGroups, err)
+		}
+		groups = append(groups, gp.Groups...)
+		if len(gp.Groups) == 0 || offset+sourcePageLimit >= gp.Total {
+			break
+		}
+	}
+
+	things, err := s.sdk.ThingsPages(token, "", sourcePageLimit).All()
+	if err != nil {
+		return nil, errors.Wrap(errCollectThings, err)
+	}
+
+	channels, err := s.sdk.ChannelsPages(token, "", sourcePageLimit).All()
+	if err != nil {
+		return nil, errors.Wrap(errCollectChannels, err)
+	}
+
+	keys := []mfsdk.Key{}
+	for offset := uint64(0); ; offset += sourcePageLimit {
+		kp, err := s.sdk.Keys(token, offset, sourcePageLimit)
+		if err != nil {
+			return nil, errors.Wrap(errCollectKeys, err)
+		}
+		keys = append(keys, kp.Keys...)
+		if len(kp.Keys) == 0 || offset+sourcePageLimit >= kp.Total {
+			break
+		}
+	}
+
+	return map[string]interface{}{
+		"groups":   groups,
+		"things":   things,
+		"channels": channels,
+		"keys":     keys,
+	}, nil
+}
+
+func (s sdkSource) Memberships(ctx context.Context, token, userID string) ([]string, error) {
+	ids := []string{}
+	for offset := uint64(0); ; offset += sourcePageLimit {
+		gp, err := s.sdk.Memberships(userID, token, offset, sourcePageLimit)
+		if err != nil {
+			return nil, errors.Wrap(errCollectGroups, err)
+		}
+		for _, g := range gp.Groups {
+			ids = append(ids, g.ID)
+		}
+		if len(gp.Groups) == 0 || offset+sourcePageLimit >= gp.Total {
+			break
+		}
+	}
+	return ids, nil
+}
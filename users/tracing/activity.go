@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveLoginAttempt      = "save_login_attempt"
+	retrieveUserActivity  = "retrieve_user_activity"
+	countSuccessfulLogins = "count_successful_logins"
+)
+
+var _ users.ActivityRepository = (*activityRepositoryMiddleware)(nil)
+
+type activityRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   users.ActivityRepository
+}
+
+// ActivityRepositoryMiddleware tracks request and their latency, and adds
+// spans to context.
+func ActivityRepositoryMiddleware(repo users.ActivityRepository, tracer opentracing.Tracer) users.ActivityRepository {
+	return activityRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (arm activityRepositoryMiddleware) Save(ctx context.Context, a users.LoginAttempt) error {
+	span := createSpan(ctx, arm.tracer, saveLoginAttempt)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.Save(ctx, a)
+}
+
+func (arm activityRepositoryMiddleware) RetrieveByUser(ctx context.Context, userID string, offset, limit uint64) (users.ActivityPage, error) {
+	span := createSpan(ctx, arm.tracer, retrieveUserActivity)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.RetrieveByUser(ctx, userID, offset, limit)
+}
+
+func (arm activityRepositoryMiddleware) CountSuccessful(ctx context.Context, userID, ip string) (uint64, error) {
+	span := createSpan(ctx, arm.tracer, countSuccessfulLogins)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return arm.repo.CountSuccessful(ctx, userID, ip)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveExport           = "save_export"
+	updateExport         = "update_export"
+	retrieveExport       = "retrieve_export"
+	retrieveLatestExport = "retrieve_latest_export"
+)
+
+var _ users.ExportRepository = (*exportRepositoryMiddleware)(nil)
+
+type exportRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   users.ExportRepository
+}
+
+// ExportRepositoryMiddleware tracks request and their latency, and adds spans
+// to context.
+func ExportRepositoryMiddleware(repo users.ExportRepository, tracer opentracing.Tracer) users.ExportRepository {
+	return exportRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (erm exportRepositoryMiddleware) Save(ctx context.Context, e users.Export) error {
+	span := createSpan(ctx, erm.tracer, saveExport)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.Save(ctx, e)
+}
+
+func (erm exportRepositoryMiddleware) Update(ctx context.Context, e users.Export) error {
+	span := createSpan(ctx, erm.tracer, updateExport)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.Update(ctx, e)
+}
+
+func (erm exportRepositoryMiddleware) RetrieveByID(ctx context.Context, id string) (users.Export, error) {
+	span := createSpan(ctx, erm.tracer, retrieveExport)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveByID(ctx, id)
+}
+
+func (erm exportRepositoryMiddleware) RetrieveLatest(ctx context.Context, userID string) (users.Export, error) {
+	span := createSpan(ctx, erm.tracer, retrieveLatestExport)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return erm.repo.RetrieveLatest(ctx, userID)
+}
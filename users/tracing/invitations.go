@@ -0,0 +1,57 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"context"
+
+	"github.com/mainflux/mainflux/users"
+	opentracing "github.com/opentracing/opentracing-go"
+)
+
+const (
+	saveInvitation            = "save_invitation"
+	retrieveInvitationByToken = "retrieve_invitation_by_token"
+	removeInvitation          = "remove_invitation"
+)
+
+var _ users.InvitationRepository = (*invitationRepositoryMiddleware)(nil)
+
+type invitationRepositoryMiddleware struct {
+	tracer opentracing.Tracer
+	repo   users.InvitationRepository
+}
+
+// InvitationRepositoryMiddleware tracks request and their latency, and adds
+// spans to context.
+func InvitationRepositoryMiddleware(repo users.InvitationRepository, tracer opentracing.Tracer) users.InvitationRepository {
+	return invitationRepositoryMiddleware{
+		tracer: tracer,
+		repo:   repo,
+	}
+}
+
+func (irm invitationRepositoryMiddleware) Save(ctx context.Context, inv users.Invitation) error {
+	span := createSpan(ctx, irm.tracer, saveInvitation)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return irm.repo.Save(ctx, inv)
+}
+
+func (irm invitationRepositoryMiddleware) RetrieveByToken(ctx context.Context, tokenHash string) (users.Invitation, error) {
+	span := createSpan(ctx, irm.tracer, retrieveInvitationByToken)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return irm.repo.RetrieveByToken(ctx, tokenHash)
+}
+
+func (irm invitationRepositoryMiddleware) Remove(ctx context.Context, id string) error {
+	span := createSpan(ctx, irm.tracer, removeInvitation)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return irm.repo.Remove(ctx, id)
+}
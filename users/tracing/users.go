@@ -7,16 +7,38 @@ package tracing
 
 import (
 	"context"
+	"time"
 
 	"github.com/mainflux/mainflux/users"
 	opentracing "github.com/opentracing/opentracing-go"
 )
 
 const (
-	saveOp            = "save_op"
-	retrieveByEmailOp = "retrieve_by_email"
-	updatePassword    = "update_password"
-	members           = "members"
+	saveOp              = "save_op"
+	updateProfileOp     = "update_profile"
+	retrieveByEmailOp   = "retrieve_by_email"
+	updatePassword      = "update_password"
+	updateHashOp        = "update_password_hash"
+	saveHistoryOp       = "save_password_history"
+	retrieveHistoryOp   = "retrieve_password_history"
+	verifyOp            = "verify"
+	disableOp           = "disable"
+	enableOp            = "enable"
+	failedLoginOp       = "increment_failed_logins"
+	lockOp              = "lock"
+	unlockOp            = "unlock"
+	deleteOp            = "delete"
+	saveMFAOp           = "save_mfa"
+	retrieveMFAOp       = "retrieve_mfa"
+	saveChallengeOp     = "save_mfa_challenge"
+	consumeChallengeOp  = "consume_mfa_challenge"
+	saveIdentityOp      = "save_identity"
+	retrieveByIdentOp   = "retrieve_by_identity"
+	saveResetNonceOp    = "save_reset_nonce"
+	consumeResetNonceOp = "consume_reset_nonce"
+	saveResetChallOp    = "save_reset_challenge"
+	consumeResetChallOp = "consume_reset_challenge"
+	members             = "members"
 )
 
 var _ users.UserRepository = (*userRepositoryMiddleware)(nil)
@@ -51,6 +73,14 @@ func (urm userRepositoryMiddleware) UpdateUser(ctx context.Context, user users.U
 	return urm.repo.UpdateUser(ctx, user)
 }
 
+func (urm userRepositoryMiddleware) UpdateProfile(ctx context.Context, id string, p users.Profile) error {
+	span := createSpan(ctx, urm.tracer, updateProfileOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.UpdateProfile(ctx, id, p)
+}
+
 func (urm userRepositoryMiddleware) RetrieveByEmail(ctx context.Context, email string) (users.User, error) {
 	span := createSpan(ctx, urm.tracer, retrieveByEmailOp)
 	defer span.Finish()
@@ -75,12 +105,172 @@ func (urm userRepositoryMiddleware) UpdatePassword(ctx context.Context, email, p
 	return urm.repo.UpdatePassword(ctx, email, password)
 }
 
-func (urm userRepositoryMiddleware) RetrieveAll(ctx context.Context, offset, limit uint64, ids []string, email string, um users.Metadata) (users.UserPage, error) {
+func (urm userRepositoryMiddleware) UpdatePasswordHash(ctx context.Context, id, hash string) error {
+	span := createSpan(ctx, urm.tracer, updateHashOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.UpdatePasswordHash(ctx, id, hash)
+}
+
+func (urm userRepositoryMiddleware) SavePasswordHistory(ctx context.Context, userID, password string, limit uint32) error {
+	span := createSpan(ctx, urm.tracer, saveHistoryOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.SavePasswordHistory(ctx, userID, password, limit)
+}
+
+func (urm userRepositoryMiddleware) RetrievePasswordHistory(ctx context.Context, userID string, limit uint32) ([]string, error) {
+	span := createSpan(ctx, urm.tracer, retrieveHistoryOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.RetrievePasswordHistory(ctx, userID, limit)
+}
+
+func (urm userRepositoryMiddleware) Verify(ctx context.Context, email string) error {
+	span := createSpan(ctx, urm.tracer, verifyOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.Verify(ctx, email)
+}
+
+func (urm userRepositoryMiddleware) Disable(ctx context.Context, id string) error {
+	span := createSpan(ctx, urm.tracer, disableOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.Disable(ctx, id)
+}
+
+func (urm userRepositoryMiddleware) Enable(ctx context.Context, id string) error {
+	span := createSpan(ctx, urm.tracer, enableOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.Enable(ctx, id)
+}
+
+func (urm userRepositoryMiddleware) IncrementFailedLogins(ctx context.Context, id string) (uint32, error) {
+	span := createSpan(ctx, urm.tracer, failedLoginOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.IncrementFailedLogins(ctx, id)
+}
+
+func (urm userRepositoryMiddleware) Lock(ctx context.Context, id string, until time.Time) error {
+	span := createSpan(ctx, urm.tracer, lockOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.Lock(ctx, id, until)
+}
+
+func (urm userRepositoryMiddleware) Unlock(ctx context.Context, id string) error {
+	span := createSpan(ctx, urm.tracer, unlockOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.Unlock(ctx, id)
+}
+
+func (urm userRepositoryMiddleware) Delete(ctx context.Context, id string) error {
+	span := createSpan(ctx, urm.tracer, deleteOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.Delete(ctx, id)
+}
+
+func (urm userRepositoryMiddleware) SaveMFA(ctx context.Context, mfa users.MFA) error {
+	span := createSpan(ctx, urm.tracer, saveMFAOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.SaveMFA(ctx, mfa)
+}
+
+func (urm userRepositoryMiddleware) RetrieveMFA(ctx context.Context, userID string) (users.MFA, error) {
+	span := createSpan(ctx, urm.tracer, retrieveMFAOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.RetrieveMFA(ctx, userID)
+}
+
+func (urm userRepositoryMiddleware) SaveIdentity(ctx context.Context, id users.Identity) error {
+	span := createSpan(ctx, urm.tracer, saveIdentityOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.SaveIdentity(ctx, id)
+}
+
+func (urm userRepositoryMiddleware) RetrieveByIdentity(ctx context.Context, provider, subject string) (users.User, error) {
+	span := createSpan(ctx, urm.tracer, retrieveByIdentOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.RetrieveByIdentity(ctx, provider, subject)
+}
+
+func (urm userRepositoryMiddleware) SaveMFAChallenge(ctx context.Context, c users.MFAChallenge) error {
+	span := createSpan(ctx, urm.tracer, saveChallengeOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.SaveMFAChallenge(ctx, c)
+}
+
+func (urm userRepositoryMiddleware) ConsumeMFAChallenge(ctx context.Context, tokenHash string) (users.MFAChallenge, error) {
+	span := createSpan(ctx, urm.tracer, consumeChallengeOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.ConsumeMFAChallenge(ctx, tokenHash)
+}
+
+func (urm userRepositoryMiddleware) SaveResetNonce(ctx context.Context, userID, nonce string) error {
+	span := createSpan(ctx, urm.tracer, saveResetNonceOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.SaveResetNonce(ctx, userID, nonce)
+}
+
+func (urm userRepositoryMiddleware) ConsumeResetNonce(ctx context.Context, userID, nonce string) error {
+	span := createSpan(ctx, urm.tracer, consumeResetNonceOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.ConsumeResetNonce(ctx, userID, nonce)
+}
+
+func (urm userRepositoryMiddleware) SaveResetChallenge(ctx context.Context, c users.ResetChallenge) error {
+	span := createSpan(ctx, urm.tracer, saveResetChallOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.SaveResetChallenge(ctx, c)
+}
+
+func (urm userRepositoryMiddleware) ConsumeResetChallenge(ctx context.Context, tokenHash string) (users.ResetChallenge, error) {
+	span := createSpan(ctx, urm.tracer, consumeResetChallOp)
+	defer span.Finish()
+	ctx = opentracing.ContextWithSpan(ctx, span)
+
+	return urm.repo.ConsumeResetChallenge(ctx, tokenHash)
+}
+
+func (urm userRepositoryMiddleware) RetrieveAll(ctx context.Context, ids []string, pm users.PageMetadata) (users.UserPage, error) {
 	span := createSpan(ctx, urm.tracer, members)
 	defer span.Finish()
 	ctx = opentracing.ContextWithSpan(ctx, span)
 
-	return urm.repo.RetrieveAll(ctx, offset, limit, ids, email, um)
+	return urm.repo.RetrieveAll(ctx, ids, pm)
 }
 
 func createSpan(ctx context.Context, tracer opentracing.Tracer, opName string) opentracing.Span {
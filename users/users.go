@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/mainflux/mainflux/pkg/errors"
+	"github.com/mainflux/mainflux/pkg/labels"
 	"golang.org/x/net/idna"
 )
 
@@ -19,6 +22,16 @@ const (
 
 	atSeparator  = "@"
 	dotSeparator = "."
+
+	// StatusPending is the status of the user who didn't verify the email.
+	StatusPending = "pending"
+	// StatusActive is the status of the user allowed to log in.
+	StatusActive = "active"
+	// StatusDisabled is the status of the user disabled by the admin.
+	StatusDisabled = "disabled"
+	// StatusLocked is the status of the user locked out after too many
+	// failed logins.
+	StatusLocked = "locked"
 )
 
 var (
@@ -38,6 +51,45 @@ type User struct {
 	Email    string
 	Password string
 	Metadata Metadata
+	Labels   labels.Labels
+	// Verified tells whether the user confirmed the ownership of the email.
+	// The unverified users can't log in.
+	Verified bool
+	// Disabled tells whether the admin disabled the user. The disabled
+	// users can't log in.
+	Disabled bool
+	// FailedLogins is the number of the failed logins since the last
+	// successful login or lockout.
+	FailedLogins uint32
+	// LockedUntil is the end of the lockout after too many failed logins.
+	// The locked users can't log in with the password until then.
+	LockedUntil time.Time
+	// PasswordChangedAt is the time the password was last set.
+	PasswordChangedAt time.Time
+	// CreatedAt is the time the user was registered.
+	CreatedAt time.Time
+	// Profile holds the personal details of the user.
+	Profile Profile
+}
+
+// Locked tells whether the user is locked out at the given time.
+func (u User) Locked(now time.Time) bool {
+	return now.Before(u.LockedUntil)
+}
+
+// Status returns the account status of the user, which is pending until the
+// user verifies the email, unless the user is disabled or locked out.
+func (u User) Status() string {
+	if u.Disabled {
+		return StatusDisabled
+	}
+	if u.Locked(time.Now()) {
+		return StatusLocked
+	}
+	if u.Verified {
+		return StatusActive
+	}
+	return StatusPending
 }
 
 // Validate returns an error if user representation is invalid.
@@ -45,7 +97,10 @@ func (u User) Validate() error {
 	if !isEmail(u.Email) {
 		return ErrMalformedEntity
 	}
-	return nil
+	if err := u.Labels.Validate(); err != nil {
+		return errors.Wrap(ErrMalformedEntity, err)
+	}
+	return u.Profile.Validate()
 }
 
 // UserRepository specifies an account persistence API.
@@ -57,17 +112,100 @@ type UserRepository interface {
 	// Update updates the user metadata.
 	UpdateUser(ctx context.Context, u User) error
 
+	// UpdateProfile updates the profile of the user with given ID.
+	UpdateProfile(ctx context.Context, id string, p Profile) error
+
 	// RetrieveByEmail retrieves user by its unique identifier (i.e. email).
 	RetrieveByEmail(ctx context.Context, email string) (User, error)
 
 	// RetrieveByID retrieves user by its unique identifier ID.
 	RetrieveByID(ctx context.Context, id string) (User, error)
 
-	// RetrieveAll retrieves all users for given array of userIDs.
-	RetrieveAll(ctx context.Context, offset, limit uint64, userIDs []string, email string, m Metadata) (UserPage, error)
+	// RetrieveAll retrieves the page of the users for given array of
+	// userIDs, matching the filters of the page metadata and sorted in its
+	// order.
+	RetrieveAll(ctx context.Context, userIDs []string, pm PageMetadata) (UserPage, error)
 
-	// UpdatePassword updates password for user with given email
+	// UpdatePassword updates password for user with given email, and marks
+	// the time of the change.
 	UpdatePassword(ctx context.Context, email, password string) error
+
+	// UpdatePasswordHash replaces the password hash of the user with given
+	// ID by the new hash of the same password, keeping the time of the last
+	// password change.
+	UpdatePasswordHash(ctx context.Context, id, hash string) error
+
+	// SavePasswordHistory adds the hash of the replaced password to the
+	// password history of the user with given ID, keeping only the latest
+	// limit hashes.
+	SavePasswordHistory(ctx context.Context, userID, password string, limit uint32) error
+
+	// RetrievePasswordHistory retrieves the latest limit hashes of the
+	// password history of the user with given ID, newest first.
+	RetrievePasswordHistory(ctx context.Context, userID string, limit uint32) ([]string, error)
+
+	// Verify marks the email of the user as verified.
+	Verify(ctx context.Context, email string) error
+
+	// Disable marks the user with given ID as disabled.
+	Disable(ctx context.Context, id string) error
+
+	// Enable marks the user with given ID as enabled.
+	Enable(ctx context.Context, id string) error
+
+	// IncrementFailedLogins records the failed login of the user with given
+	// ID, returning the number of the failed logins since the last
+	// successful login or lockout.
+	IncrementFailedLogins(ctx context.Context, id string) (uint32, error)
+
+	// Lock locks out the user with given ID until the given time, and
+	// resets the failed logins.
+	Lock(ctx context.Context, id string, until time.Time) error
+
+	// Unlock lifts the lockout of the user with given ID, and resets the
+	// failed logins.
+	Unlock(ctx context.Context, id string) error
+
+	// Delete removes the user with given ID, along with the user exports.
+	Delete(ctx context.Context, id string) error
+
+	// SaveMFA persists the MFA of the user, replacing the existing one.
+	SaveMFA(ctx context.Context, mfa MFA) error
+
+	// RetrieveMFA retrieves the MFA of the user with given ID.
+	RetrieveMFA(ctx context.Context, userID string) (MFA, error)
+
+	// SaveMFAChallenge persists the MFA challenge of the login.
+	SaveMFAChallenge(ctx context.Context, c MFAChallenge) error
+
+	// ConsumeMFAChallenge removes and returns the MFA challenge with the
+	// given token hash, so the challenge is used only once.
+	ConsumeMFAChallenge(ctx context.Context, tokenHash string) (MFAChallenge, error)
+
+	// SaveIdentity links the external identity to the user. Each external
+	// identity is linked to at most one user.
+	SaveIdentity(ctx context.Context, id Identity) error
+
+	// RetrieveByIdentity retrieves the user linked to the external identity
+	// of the given provider.
+	RetrieveByIdentity(ctx context.Context, provider, subject string) (User, error)
+
+	// SaveResetNonce saves the nonce of the latest password reset token of
+	// the user, replacing the previous one.
+	SaveResetNonce(ctx context.Context, userID, nonce string) error
+
+	// ConsumeResetNonce removes the password reset nonce of the user, so the
+	// reset token carrying it can't be used again. ErrNotFound is returned
+	// unless the nonce is the latest one issued to the user.
+	ConsumeResetNonce(ctx context.Context, userID, nonce string) error
+
+	// SaveResetChallenge persists the password reset challenge of the login
+	// with the expired password.
+	SaveResetChallenge(ctx context.Context, c ResetChallenge) error
+
+	// ConsumeResetChallenge removes and returns the password reset challenge
+	// with the given token hash, so the challenge is used only once.
+	ConsumeResetChallenge(ctx context.Context, tokenHash string) (ResetChallenge, error)
 }
 
 func isEmail(email string) bool {
@@ -20,6 +20,7 @@ const (
 	maxLocalLen  = 64
 	maxDomainLen = 255
 	maxTLDLen    = 24
+	maxNameLen   = 64
 )
 
 var letters = "abcdefghijklmnopqrstuvwxyz"
@@ -135,6 +136,52 @@ func TestValidate(t *testing.T) {
 			},
 			err: users.ErrMalformedEntity,
 		},
+		"validate user with valid profile": {
+			user: users.User{
+				Email:    email,
+				Password: password,
+				Profile: users.Profile{
+					FirstName:   "Петар",
+					LastName:    "Petrović",
+					DisplayName: "pera",
+					Phone:       "+381641234567",
+					Locale:      "sr-RS",
+				},
+			},
+			err: nil,
+		},
+		"validate user with too long first name": {
+			user: users.User{
+				Email:    email,
+				Password: password,
+				Profile:  users.Profile{FirstName: randomString(maxNameLen + 1)},
+			},
+			err: users.ErrMalformedEntity,
+		},
+		"validate user with control characters in display name": {
+			user: users.User{
+				Email:    email,
+				Password: password,
+				Profile:  users.Profile{DisplayName: "pera\n"},
+			},
+			err: users.ErrMalformedEntity,
+		},
+		"validate user with invalid phone": {
+			user: users.User{
+				Email:    email,
+				Password: password,
+				Profile:  users.Profile{Phone: "064 123 4567"},
+			},
+			err: users.ErrMalformedEntity,
+		},
+		"validate user with invalid locale": {
+			user: users.User{
+				Email:    email,
+				Password: password,
+				Profile:  users.Profile{Locale: "sr_RS"},
+			},
+			err: users.ErrMalformedEntity,
+		},
 	}
 
 	for desc, tc := range cases {
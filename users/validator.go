@@ -0,0 +1,113 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package users
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mainflux/mainflux/pkg/errors"
+)
+
+// ErrValidation indicates the user rejected by the custom validation rules
+// of the deployment.
+var ErrValidation = errors.New("user rejected by validation rules")
+
+// Validator enforces the custom rules of the deployment on the user
+// registration, such as the corporate email domains. It's invoked once the
+// user passes the built-in validation, and again once the user updates the
+// metadata.
+type Validator interface {
+	// ValidateUser returns the error wrapping ErrValidation if the user
+	// violates the rules.
+	ValidateUser(ctx context.Context, user User) error
+}
+
+var _ errors.Error = (*MetadataError)(nil)
+
+// MetadataViolation describes the field of the metadata violating the
+// metadata schema.
+type MetadataViolation struct {
+	// Field is the path of the field, e.g. "address.city", or "(root)" for
+	// the metadata itself.
+	Field string
+	// Description tells how the field violates the schema.
+	Description string
+}
+
+// MetadataError is the ErrValidation listing the violations of the metadata
+// schema.
+type MetadataError struct {
+	Violations []MetadataViolation
+}
+
+func (e *MetadataError) Error() string {
+	return e.Msg() + " : " + e.Err().Error()
+}
+
+// Msg returns the message of ErrValidation, so the error is matched against
+// it.
+func (e *MetadataError) Msg() string {
+	return ErrValidation.Msg()
+}
+
+// Err returns the error listing the violations.
+func (e *MetadataError) Err() errors.Error {
+	vs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		vs[i] = fmt.Sprintf("%s: %s", v.Field, v.Description)
+	}
+	return errors.New("metadata violates schema: " + strings.Join(vs, ", "))
+}
+
+var _ Validator = (Validators)(nil)
+
+// Validators combine the validators, running them in turn until the first
+// one rejects the user.
+type Validators []Validator
+
+// ValidateUser validates the user using each of the validators.
+func (vs Validators) ValidateUser(ctx context.Context, user User) error {
+	for _, v := range vs {
+		if err := v.ValidateUser(ctx, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type nopValidator struct{}
+
+// NewNopValidator returns the validator accepting all the users.
+func NewNopValidator() Validator {
+	return nopValidator{}
+}
+
+func (nopValidator) ValidateUser(context.Context, User) error {
+	return nil
+}
+
+type emailDomainValidator struct {
+	domains map[string]bool
+}
+
+// NewEmailDomainValidator returns the validator accepting only the users
+// with the emails of the listed domains, e.g. "example.com". The domains are
+// matched case insensitively.
+func NewEmailDomainValidator(domains ...string) Validator {
+	v := emailDomainValidator{domains: map[string]bool{}}
+	for _, d := range domains {
+		v.domains[strings.ToLower(strings.TrimSpace(d))] = true
+	}
+	return v
+}
+
+func (v emailDomainValidator) ValidateUser(_ context.Context, user User) error {
+	domain := strings.ToLower(user.Email[strings.LastIndex(user.Email, "@")+1:])
+	if !v.domains[domain] {
+		return errors.Wrap(ErrValidation, fmt.Errorf("email domain %s is not allowed", domain))
+	}
+	return nil
+}
@@ -0,0 +1,6 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhook contains the event notifier posting the user lifecycle
+// events to the webhooks configured by the operator.
+package webhook
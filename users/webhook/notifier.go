@@ -0,0 +1,148 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/users"
+)
+
+const (
+	contentType = "application/json"
+
+	// SignatureHeader is the header carrying the HMAC-SHA256 signature of
+	// the request body, computed using the webhook secret.
+	SignatureHeader = "X-Mainflux-Signature"
+
+	// EventHeader is the header carrying the type of the event, so the
+	// receivers can route the events without parsing the body.
+	EventHeader = "X-Mainflux-Event"
+
+	queueSize  = 1024
+	attempts   = 3
+	retryDelay = 500 * time.Millisecond
+)
+
+type user struct {
+	ID       string         `json:"id"`
+	Email    string         `json:"email,omitempty"`
+	Metadata users.Metadata `json:"metadata,omitempty"`
+}
+
+type event struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	User       user      `json:"user"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+var _ users.EventNotifier = (*notifier)(nil)
+
+type notifier struct {
+	urls   []string
+	secret string
+	client *http.Client
+	logger logger.Logger
+	events chan users.Event
+}
+
+// New instantiates the event notifier posting the events to each of the
+// webhook URLs, signed with the secret, if set. The events are queued and
+// posted in the background, in the order of notification. The failed post
+// is retried a few times before the event is dropped for the webhook, and
+// the events notified while the queue is full are dropped too.
+func New(urls []string, secret string, client *http.Client, logger logger.Logger) users.EventNotifier {
+	n := &notifier{
+		urls:   urls,
+		secret: secret,
+		client: client,
+		logger: logger,
+		events: make(chan users.Event, queueSize),
+	}
+	go n.run()
+	return n
+}
+
+func (n *notifier) Notify(_ context.Context, e users.Event) {
+	select {
+	case n.events <- e:
+	default:
+		n.logger.Warn(fmt.Sprintf("Dropped event %s of user %s: webhook queue is full", e.Type, e.UserID))
+	}
+}
+
+func (n *notifier) run() {
+	for e := range n.events {
+		body, err := json.Marshal(event{
+			ID:         e.ID,
+			Type:       e.Type,
+			User:       user{ID: e.UserID, Email: e.Email, Metadata: e.Metadata},
+			OccurredAt: e.OccurredAt,
+		})
+		if err != nil {
+			n.logger.Error(fmt.Sprintf("Failed to encode event %s of user %s: %s", e.Type, e.UserID, err))
+			continue
+		}
+		for _, url := range n.urls {
+			n.deliver(url, e.Type, body)
+		}
+	}
+}
+
+func (n *notifier) deliver(url, typ string, body []byte) {
+	delay := retryDelay
+	for i := 1; ; i++ {
+		err := n.post(url, typ, body)
+		if err == nil {
+			return
+		}
+		if i == attempts {
+			n.logger.Warn(fmt.Sprintf("Failed to deliver event %s to webhook %s after %d attempts: %s", typ, url, attempts, err))
+			return
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+func (n *notifier) post(url, typ string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set(EventHeader, typ)
+	if n.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(n.secret, body))
+	}
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status %s", res.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature of the request body, in the form of
+// "sha256=<hex encoded HMAC>", so the receiver can verify its origin.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return fmt.Sprintf("sha256=%s", hex.EncodeToString(mac.Sum(nil)))
+}
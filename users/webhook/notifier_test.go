@@ -0,0 +1,110 @@
+// Copyright (c) Mainflux
+// SPDX-License-Identifier: Apache-2.0
+
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mainflux/mainflux/logger"
+	"github.com/mainflux/mainflux/users"
+	"github.com/mainflux/mainflux/users/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "secret"
+
+type receiver struct {
+	mu       sync.Mutex
+	failures int
+	events   []received
+}
+
+type received struct {
+	typ       string
+	signature string
+	body      map[string]interface{}
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.failures > 0 {
+		rc.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	sig := r.Header.Get(webhook.SignatureHeader)
+	if sig != "" && sig != webhook.Sign(secret, body) {
+		sig = "invalid"
+	}
+	var e map[string]interface{}
+	json.Unmarshal(body, &e)
+	rc.events = append(rc.events, received{typ: r.Header.Get(webhook.EventHeader), signature: sig, body: e})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rc *receiver) received() []received {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]received{}, rc.events...)
+}
+
+func TestNotify(t *testing.T) {
+	rc := &receiver{}
+	ts := httptest.NewServer(rc)
+	defer ts.Close()
+	flaky := &receiver{failures: 1}
+	fs := httptest.NewServer(flaky)
+	defer fs.Close()
+
+	logger, err := logger.New(ioutil.Discard, "error")
+	require.Nil(t, err, fmt.Sprintf("unexpected error: %s", err))
+	n := webhook.New([]string{ts.URL, fs.URL}, secret, ts.Client(), logger)
+
+	events := []users.Event{
+		{ID: "1", Type: users.EventUserCreated, UserID: "user", Email: "user@example.com", Metadata: users.Metadata{"plan": "free"}, OccurredAt: time.Now()},
+		{ID: "2", Type: users.EventUserDeleted, UserID: "user", OccurredAt: time.Now()},
+	}
+	for _, e := range events {
+		n.Notify(context.Background(), e)
+	}
+
+	for i := 0; i < 300 && len(flaky.received()) < len(events); i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cases := []struct {
+		desc string
+		rc   *receiver
+	}{
+		{desc: "notify webhook", rc: rc},
+		{desc: "notify webhook failing first", rc: flaky},
+	}
+
+	for _, tc := range cases {
+		got := tc.rc.received()
+		require.Equal(t, len(events), len(got), fmt.Sprintf("%s: expected %d events got %d", tc.desc, len(events), len(got)))
+		for i, e := range events {
+			assert.Equal(t, e.Type, got[i].typ, fmt.Sprintf("%s: expected event header %s got %s", tc.desc, e.Type, got[i].typ))
+			assert.NotEmpty(t, got[i].signature, fmt.Sprintf("%s: expected signed request", tc.desc))
+			assert.NotEqual(t, "invalid", got[i].signature, fmt.Sprintf("%s: expected valid signature", tc.desc))
+			assert.Equal(t, e.ID, got[i].body["id"], fmt.Sprintf("%s: expected event ID %s got %v", tc.desc, e.ID, got[i].body["id"]))
+			assert.Equal(t, e.Type, got[i].body["type"], fmt.Sprintf("%s: expected event type %s got %v", tc.desc, e.Type, got[i].body["type"]))
+			u, _ := got[i].body["user"].(map[string]interface{})
+			assert.Equal(t, e.UserID, u["id"], fmt.Sprintf("%s: expected user ID %s got %v", tc.desc, e.UserID, u["id"]))
+		}
+	}
+}